@@ -0,0 +1,55 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"aws-mcp-server/internal/logging"
+	"aws-mcp-server/pkg/bench"
+)
+
+func main() {
+	concurrency := flag.Int("concurrency", 8, "Number of clients sending requests at once")
+	requests := flag.Int("requests", 2000, "Number of requests to send; 0 runs for -duration")
+	duration := flag.Duration("duration", 0, "How long to send requests when -requests is 0, e.g. 30s")
+	instances := flag.Int("instances", 500, "Number of instances in the fake fleet")
+	latency := flag.Duration("latency", 0, "Latency added to every fake AWS call, e.g. 20ms")
+	asJSON := flag.Bool("json", false, "Print the report as JSON")
+	logLevel := flag.String("log-level", "error", "Server log level; info logs every request")
+	flag.Parse()
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	backend := bench.NewBackend(*instances)
+	backend.Latency = *latency
+	server := bench.NewServer(bench.Config(), backend, logging.NewLogger(*logLevel, "text"))
+
+	report, err := bench.Run(ctx, server.HandleMessage, bench.Options{
+		Concurrency: *concurrency,
+		Requests:    *requests,
+		Duration:    *duration,
+		Mix:         bench.DefaultMix(backend.InstanceIDs()),
+	})
+	if err != nil {
+		log.Fatalf("Benchmark failed: %v", err)
+	}
+
+	if *asJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(report); err != nil {
+			log.Fatalf("Failed to encode report: %v", err)
+		}
+		return
+	}
+	if err := report.Write(os.Stdout); err != nil {
+		log.Fatalf("Failed to print report: %v", err)
+	}
+	log.Printf("%d AWS calls answered by the fake backend, at most %d at once", backend.Calls(), backend.MaxInFlight())
+}
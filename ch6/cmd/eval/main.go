@@ -0,0 +1,49 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"log"
+	"os"
+
+	"aws-mcp-server/pkg/eval"
+	"aws-mcp-server/pkg/mcp"
+	"aws-mcp-server/pkg/session"
+)
+
+func main() {
+	sessionPath := flag.String("session", "", "Session file the server recorded while the agent worked the scenario")
+	scenarioPath := flag.String("scenario", "", "Scenario file with the expected detection and remediation")
+	asJSON := flag.Bool("json", false, "Print the report as JSON")
+	minScore := flag.Int("min-score", 0, "Exit with an error when the score is below this")
+	flag.Parse()
+
+	if *sessionPath == "" || *scenarioPath == "" {
+		log.Fatal("-session and -scenario are required")
+	}
+
+	scenario, err := eval.LoadScenario(*scenarioPath)
+	if err != nil {
+		log.Fatalf("Failed to load scenario: %v", err)
+	}
+	entries, err := session.Load(*sessionPath)
+	if err != nil {
+		log.Fatalf("Failed to load session: %v", err)
+	}
+
+	report := eval.Evaluate(scenario, entries, mcp.IsMutating)
+
+	if *asJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(report); err != nil {
+			log.Fatalf("Failed to encode report: %v", err)
+		}
+	} else if err := report.Write(os.Stdout); err != nil {
+		log.Fatalf("Failed to print report: %v", err)
+	}
+
+	if report.Score < *minScore {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/exec"
+	"strings"
+
+	"aws-mcp-server/pkg/session"
+)
+
+// processTransport talks JSON-RPC to a server binary over its stdin and stdout
+type processTransport struct {
+	stdin   io.WriteCloser
+	scanner *bufio.Scanner
+}
+
+func (t *processTransport) Send(request []byte) error {
+	_, err := fmt.Fprintf(t.stdin, "%s\n", request)
+	return err
+}
+
+func (t *processTransport) Receive() ([]byte, error) {
+	if !t.scanner.Scan() {
+		if err := t.scanner.Err(); err != nil {
+			return nil, err
+		}
+		return nil, io.EOF
+	}
+
+	// Copy the line since the scanner reuses its buffer
+	return append([]byte(nil), t.scanner.Bytes()...), nil
+}
+
+func main() {
+	sessionPath := flag.String("session", "", "Recorded session file to replay")
+	serverPath := flag.String("server", "./bin/aws-mcp-server", "Server binary to replay the session against")
+	ignore := flag.String("ignore", strings.Join(session.DefaultIgnoreFields, ","), "Comma-separated response fields to ignore when comparing")
+	verbose := flag.Bool("verbose", false, "Print expected and actual responses for mismatches")
+	flag.Parse()
+
+	if *sessionPath == "" {
+		log.Fatal("-session is required")
+	}
+
+	entries, err := session.Load(*sessionPath)
+	if err != nil {
+		log.Fatalf("Failed to load session: %v", err)
+	}
+
+	// Start the server build under test; its logs stay on stderr
+	cmd := exec.Command(*serverPath)
+	cmd.Stderr = os.Stderr
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		log.Fatalf("Failed to open server stdin: %v", err)
+	}
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		log.Fatalf("Failed to open server stdout: %v", err)
+	}
+
+	if err := cmd.Start(); err != nil {
+		log.Fatalf("Failed to start server: %v", err)
+	}
+
+	scanner := bufio.NewScanner(stdout)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+
+	var ignoreFields []string
+	for _, field := range strings.Split(*ignore, ",") {
+		if field = strings.TrimSpace(field); field != "" {
+			ignoreFields = append(ignoreFields, field)
+		}
+	}
+
+	report, replayErr := session.Replay(entries, &processTransport{stdin: stdin, scanner: scanner}, ignoreFields)
+
+	stdin.Close()
+	cmd.Wait()
+
+	if report != nil {
+		for _, result := range report.Results {
+			status := "OK  "
+			if !result.Match {
+				status = "DIFF"
+			}
+			fmt.Printf("%s #%d %s (%dms, recorded %dms)\n", status, result.Seq, result.Method, result.DurationMs, result.RecordedMs)
+
+			if !result.Match && *verbose {
+				expected, _ := json.MarshalIndent(result.Expected, "     ", "  ")
+				actual, _ := json.MarshalIndent(result.Actual, "     ", "  ")
+				fmt.Printf("     expected: %s\n     actual:   %s\n", expected, actual)
+			}
+		}
+		fmt.Printf("\n%d responses compared: %d matched, %d mismatched\n", report.Total, report.Matched, report.Mismatched)
+	}
+
+	if replayErr != nil {
+		log.Fatalf("Replay failed: %v", replayErr)
+	}
+
+	if report.Mismatched > 0 {
+		os.Exit(1)
+	}
+}
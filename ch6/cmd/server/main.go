@@ -9,8 +9,14 @@ import (
 
 	"aws-mcp-server/internal/config"
 	"aws-mcp-server/internal/logging"
+	"aws-mcp-server/pkg/alertgroup"
+	"aws-mcp-server/pkg/approval"
 	"aws-mcp-server/pkg/aws"
+	"aws-mcp-server/pkg/change"
+	"aws-mcp-server/pkg/fake"
+	"aws-mcp-server/pkg/management"
 	"aws-mcp-server/pkg/mcp"
+	"aws-mcp-server/pkg/ownership"
 )
 
 func main() {
@@ -24,14 +30,58 @@ func main() {
 		log.Fatalf("Failed to load configuration: %v", err)
 	}
 
+	// Reject invalid change freeze windows instead of silently not enforcing them
+	if _, err := approval.ParseFreezeWindows(cfg.Approvals.FreezeWindows); err != nil {
+		log.Fatalf("Invalid approvals configuration: %v", err)
+	}
+	if cfg.Alerts.Grouping.Enabled {
+		if _, err := alertgroup.NewGrouper(cfg.Alerts.Grouping.Rules); err != nil {
+			log.Fatalf("Invalid alerts configuration: %v", err)
+		}
+	}
+	if _, err := ownership.New(cfg.Ownership, cfg.Tagging.OwnerTags, nil); err != nil {
+		log.Fatalf("Invalid ownership configuration: %v", err)
+	}
+	if _, err := change.New(cfg.Change, nil); err != nil {
+		log.Fatalf("Invalid change configuration: %v", err)
+	}
+	if provider := cfg.AWS.Provider; provider != config.AWSProviderAWS && provider != config.AWSProviderFake {
+		log.Fatalf("Invalid aws configuration: unknown provider %q (use aws or fake)", provider)
+	}
+	if transport := cfg.MCP.Transport; transport != config.MCPTransportStdio && transport != config.MCPTransportHTTP {
+		log.Fatalf("Invalid mcp configuration: unknown transport %q (use stdio or http)", transport)
+	}
+	if cfg.MCP.Transport == config.MCPTransportHTTP {
+		if err := mcp.ValidateHTTP(cfg); err != nil {
+			log.Fatalf("Invalid mcp configuration: %v", err)
+		}
+	}
+	if cfg.Export.Enabled && cfg.Export.Bucket == "" {
+		log.Fatalf("Invalid export configuration: export.bucket is required")
+	}
+	if cfg.Management.Enabled {
+		if err := management.Validate(cfg.Management); err != nil {
+			log.Fatalf("Invalid management configuration: %v", err)
+		}
+	}
+
 	// Initialize logger
 	logger := logging.NewLogger("info", "text")
 	logger.Info("Starting AWS MCP Server...")
 
-	// Initialize AWS client
-	awsClient, err := aws.NewClient(cfg.AWS.Region, "", logger)
-	if err != nil {
-		logger.WithError(err).Fatal("Failed to initialize AWS client")
+	// Initialize AWS client, or the generated fleet that stands in for AWS
+	var awsClient *aws.Client
+	if cfg.AWS.Provider == config.AWSProviderFake {
+		fleet := fake.New(cfg.AWS.Fake, cfg.AWS.Region)
+		awsClient = aws.NewClientFromConfig(fleet.Config(), logger)
+		logger.WithField("instances", fleet.InstanceCount()).
+			WithField("seed", cfg.AWS.Fake.Seed).
+			Warn("Serving a generated fleet instead of AWS; changes last until the server stops")
+	} else {
+		awsClient, err = aws.NewClient(cfg.AWS.Region, "", logger)
+		if err != nil {
+			logger.WithError(err).Fatal("Failed to initialize AWS client")
+		}
 	}
 
 	// Test AWS connectivity
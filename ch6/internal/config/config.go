@@ -3,14 +3,59 @@ package config
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/spf13/viper"
 )
 
 type Config struct {
-	Server ServerConfig `mapstructure:"server"`
-	AWS    AWSConfig    `mapstructure:"aws"`
-	MCP    MCPConfig    `mapstructure:"mcp"`
+	Server       ServerConfig       `mapstructure:"server"`
+	AWS          AWSConfig          `mapstructure:"aws"`
+	Organization OrganizationConfig `mapstructure:"organization"`
+	MCP          MCPConfig          `mapstructure:"mcp"`
+	Tagging      TaggingConfig      `mapstructure:"tagging"`
+	Security     SecurityConfig     `mapstructure:"security"`
+	Response     ResponseConfig     `mapstructure:"response"`
+	Cost         CostConfig         `mapstructure:"cost"`
+	Access       AccessConfig       `mapstructure:"access"`
+	Approvals    ApprovalsConfig    `mapstructure:"approvals"`
+	Change       ChangeConfig       `mapstructure:"change"`
+	Notify       NotifyConfig       `mapstructure:"notify"`
+	Heartbeat    HeartbeatConfig    `mapstructure:"heartbeat"`
+	ChatOps      ChatOpsConfig      `mapstructure:"chatops"`
+	Prometheus   PrometheusConfig   `mapstructure:"prometheus"`
+	Logs         LogsConfig         `mapstructure:"logs"`
+	Metrics      MetricsConfig      `mapstructure:"metrics"`
+	OnCall       OnCallConfig       `mapstructure:"oncall"`
+	Audit        AuditConfig        `mapstructure:"audit"`
+	Idempotency  IdempotencyConfig  `mapstructure:"idempotency"`
+	SLO          SLOConfig          `mapstructure:"slo"`
+	GCP          GCPConfig          `mapstructure:"gcp"`
+	Azure        AzureConfig        `mapstructure:"azure"`
+	Athena       AthenaConfig       `mapstructure:"athena"`
+	Redshift     RedshiftConfig     `mapstructure:"redshift"`
+	Backup       BackupConfig       `mapstructure:"backup"`
+	Suppressions SuppressionsConfig `mapstructure:"suppressions"`
+	Notes        NotesConfig        `mapstructure:"notes"`
+	Ignore       IgnoreConfig       `mapstructure:"ignore"`
+	Baselines    BaselinesConfig    `mapstructure:"baselines"`
+	Inventory    InventoryConfig    `mapstructure:"inventory"`
+	Alerts       AlertsConfig       `mapstructure:"alerts"`
+	Ownership    OwnershipConfig    `mapstructure:"ownership"`
+	Remediation  RemediationConfig  `mapstructure:"remediation"`
+	Docs         DocsConfig         `mapstructure:"docs"`
+	Search       SearchConfig       `mapstructure:"search"`
+	Summaries    SummariesConfig    `mapstructure:"summaries"`
+	Parameters   ParametersConfig   `mapstructure:"parameters"`
+	Capture      CaptureConfig      `mapstructure:"capture"`
+	Windows      WindowsConfig      `mapstructure:"windows"`
+	Demo         DemoConfig         `mapstructure:"demo"`
+	Export       ExportConfig       `mapstructure:"export"`
+	Management   ManagementConfig   `mapstructure:"management"`
+
+	// Overrides are settings changed while the server runs, e.g. through the
+	// management API; they are not loaded from the configuration
+	Overrides *Overrides `mapstructure:"-"`
 }
 
 type ServerConfig struct {
@@ -18,13 +63,745 @@ type ServerConfig struct {
 	Host string `mapstructure:"host"`
 }
 
+// AWSConfig selects the region and caps the AWS API calls in flight.
+// MaxConcurrency applies across all services and ServiceConcurrency to single
+// services, keyed by SDK service ID in any case, e.g. ec2 or cloudwatch logs.
+// Calls over a cap wait for a slot; zero leaves calls unlimited. Provider is
+// aws (default) or fake, which serves the generated fleet of Fake instead of
+// calling AWS, so the server runs without an AWS account.
 type AWSConfig struct {
-	Region string `mapstructure:"region"`
+	Region             string         `mapstructure:"region"`
+	MaxConcurrency     int            `mapstructure:"max_concurrency"`
+	ServiceConcurrency map[string]int `mapstructure:"service_concurrency"`
+	Provider           string         `mapstructure:"provider"`
+	Fake               FakeConfig     `mapstructure:"fake"`
+}
+
+// AWS providers
+const (
+	AWSProviderAWS  = "aws"
+	AWSProviderFake = "fake"
+)
+
+// FakeConfig sizes the fleet of the fake AWS provider: EC2 instances with
+// alarms, metrics with injected anomalies and daily cost. The same Seed
+// generates the same fleet, so exercises can refer to it.
+type FakeConfig struct {
+	Instances int   `mapstructure:"instances"`
+	Seed      int64 `mapstructure:"seed"`
+}
+
+// OrganizationConfig lets resources and tools act in other accounts of an AWS
+// organization, addressed by alias, e.g. aws://prod/ec2/instances. Aliases are
+// the configured Accounts and, when Enabled, the names of the organization's
+// active accounts, lowercased with dashes for spaces; account IDs work too.
+// The server assumes RoleName in each account unless the account sets its own
+// RoleARN, passing ExternalID when the roles require one.
+type OrganizationConfig struct {
+	Enabled    bool                        `mapstructure:"enabled"`
+	RoleName   string                      `mapstructure:"role_name"`
+	ExternalID string                      `mapstructure:"external_id"`
+	Accounts   []OrganizationAccountConfig `mapstructure:"accounts"`
+}
+
+// OrganizationAccountConfig names an account. Region overrides aws.region for
+// calls in the account.
+type OrganizationAccountConfig struct {
+	Alias   string `mapstructure:"alias"`
+	ID      string `mapstructure:"id"`
+	RoleARN string `mapstructure:"role_arn"`
+	Region  string `mapstructure:"region"`
 }
 
 type MCPConfig struct {
 	ServerName string `mapstructure:"server_name"`
 	Version    string `mapstructure:"version"`
+	// RecordDir enables session recording when set; every request and response
+	// is appended to a session file in this directory for later replay
+	RecordDir string `mapstructure:"record_dir"`
+	// ToolTimeout bounds how long a tool call may run, overridden per tool
+	// name by ToolTimeouts; zero leaves calls unbounded. Tools with limits of
+	// their own, such as the timeout of run-athena-query or the waves of
+	// bulk-stop-ec2-instances, get as long as the call's arguments allow
+	// unless ToolTimeouts names them, and approve-action runs the approved
+	// call under its tool's timeout. Calls over the limit are cancelled and
+	// answered with what they got done, or an error. encrypt-volume defaults
+	// to 3h, as snapshotting and copying a large volume takes longer than the
+	// 10m of the other tools and cancelling it mid-swap strands the instance.
+	ToolTimeout  time.Duration            `mapstructure:"tool_timeout"`
+	ToolTimeouts map[string]time.Duration `mapstructure:"tool_timeouts"`
+	// Transport is how clients reach the server: stdio for a client that
+	// runs it as a subprocess, or http to serve it on server.host and
+	// server.port as a long-lived service, with streamable HTTP, SSE and
+	// WebSocket endpoints. Elicitation works over stdio and WebSocket only.
+	Transport string `mapstructure:"transport"`
+	// HTTPToken is the bearer token clients of the HTTP transport must send
+	// on every endpoint. Callers act as access.role, so it is required unless
+	// server.host is a loopback address.
+	HTTPToken string `mapstructure:"http_token"`
+	// WebSocketOrigins are the origins of web pages allowed to call the HTTP
+	// transport, WebSocket included, besides the server's own, e.g.
+	// http://localhost:3000; * allows any page
+	WebSocketOrigins []string `mapstructure:"websocket_origins"`
+}
+
+// MCP transports
+const (
+	MCPTransportStdio = "stdio"
+	MCPTransportHTTP  = "http"
+)
+
+// TaggingConfig describes the required-tag policy used for compliance audits
+type TaggingConfig struct {
+	RequiredTags []string          `mapstructure:"required_tags"`
+	OwnerTags    []string          `mapstructure:"owner_tags"`
+	DefaultTags  map[string]string `mapstructure:"default_tags"`
+	// EnvironmentTags name the tags that hold an instance's environment, in
+	// order of preference, for aws://ec2/instances/by-environment
+	EnvironmentTags []string `mapstructure:"environment_tags"`
+}
+
+// SecurityConfig holds the thresholds used by the credential hygiene report.
+// Secrets without rotation whose value has not changed for SecretMaxAgeDays
+// are reported as stale.
+type SecurityConfig struct {
+	AccessKeyMaxAgeDays  int `mapstructure:"access_key_max_age_days"`
+	UnusedCredentialDays int `mapstructure:"unused_credential_days"`
+	SecretMaxAgeDays     int `mapstructure:"secret_max_age_days"`
+}
+
+// ResponseConfig controls how tool and resource responses are presented
+type ResponseConfig struct {
+	// Summaries adds a one-line human-readable summary next to the JSON payload
+	Summaries bool `mapstructure:"summaries"`
+	// Templates overrides the built-in summary templates, keyed by tool name or
+	// resource URI. An empty template disables the summary for that key.
+	Templates map[string]string `mapstructure:"templates"`
+	// Timezone is the IANA timezone used for RFC3339 timestamps in responses
+	Timezone string `mapstructure:"timezone"`
+	// RelativeTimes adds phrases like "launched 3 days ago" to AI-formatted views
+	RelativeTimes bool `mapstructure:"relative_times"`
+	// PageSize is how many instances a read of an instance list returns; the
+	// list's next_cursor reads the next page. Zero returns whole lists.
+	PageSize int `mapstructure:"page_size"`
+}
+
+// CostConfig sets the spending guardrails applied to create actions. Limits are
+// in USD and a zero limit is disabled.
+type CostConfig struct {
+	MaxHourlyPerAction  float64 `mapstructure:"max_hourly_per_action"`
+	MaxMonthlyPerAction float64 `mapstructure:"max_monthly_per_action"`
+	// MaxMonthlyPerDay caps the combined monthly cost of resources created in
+	// one day, from midnight to midnight in response.timezone
+	MaxMonthlyPerDay float64 `mapstructure:"max_monthly_per_day"`
+	// AllowOverride lets callers bypass a violated guardrail with overrideCostGuardrail=true
+	AllowOverride bool `mapstructure:"allow_override"`
+	// InstancePrices adds or corrects hourly on-demand prices by instance type
+	InstancePrices map[string]float64 `mapstructure:"instance_prices"`
+	// CUR enables the per-resource cost resources backed by the Cost and Usage Report
+	CUR CURConfig `mapstructure:"cur"`
+	// Explorer sets the defaults of the Cost Explorer resources
+	Explorer CostExplorerConfig `mapstructure:"explorer"`
+}
+
+// CURConfig points the cost resources at the Cost and Usage Report table in
+// Athena. They are enabled when Table is set; Database defaults to
+// athena.database and queries run with the athena workgroup and limits.
+// Format is legacy (year and month partitions) or cur2 (billing_period
+// partitions, as written by Data Exports). Days is the window of daily costs.
+type CURConfig struct {
+	Database string `mapstructure:"database"`
+	Table    string `mapstructure:"table"`
+	Format   string `mapstructure:"format"`
+	Days     int    `mapstructure:"days"`
+}
+
+// CostExplorerConfig sets the defaults of aws://cost/daily and
+// aws://cost/by-service, which callers can override per read. Days is the
+// window, Granularity is DAILY or MONTHLY and Metric is the cost metric, e.g.
+// UnblendedCost or AmortizedCost. GroupBy breaks down aws://cost/daily by a
+// dimension such as SERVICE, LINKED_ACCOUNT or REGION, or by tag:<key>.
+type CostExplorerConfig struct {
+	Days        int    `mapstructure:"days"`
+	Granularity string `mapstructure:"granularity"`
+	Metric      string `mapstructure:"metric"`
+	GroupBy     string `mapstructure:"group_by"`
+}
+
+// AccessConfig defines the caller's role and which roles may approve queued actions
+type AccessConfig struct {
+	// Role is assumed for callers whose transport does not identify them (e.g. stdio)
+	Role       string   `mapstructure:"role"`
+	AdminRoles []string `mapstructure:"admin_roles"`
+	// ReadOnlyRoles may not run write tools unless an admin grants them
+	// write access with request-elevation, for at most MaxElevation at a time
+	ReadOnlyRoles []string      `mapstructure:"read_only_roles"`
+	MaxElevation  time.Duration `mapstructure:"max_elevation"`
+}
+
+// ApprovalsConfig controls the human-in-the-loop queue for blocked actions
+type ApprovalsConfig struct {
+	// QueueBlocked queues actions blocked by cost guardrails or freeze windows
+	// for admin approval instead of rejecting them outright
+	QueueBlocked  bool                 `mapstructure:"queue_blocked"`
+	FreezeWindows []FreezeWindowConfig `mapstructure:"freeze_windows"`
+	// Elicit asks the user at the MCP client to approve or deny blocked
+	// actions through a form when the client supports elicitation and the
+	// caller is an admin; without an answer within ElicitTimeout, and for
+	// other callers and elevations, the action is queued as usual
+	Elicit        bool          `mapstructure:"elicit"`
+	ElicitTimeout time.Duration `mapstructure:"elicit_timeout"`
+}
+
+// FreezeWindowConfig describes a change freeze. With days set, start and end are
+// "HH:MM" clock times in the response timezone recurring on those weekdays;
+// otherwise they are RFC3339 timestamps of a one-off window.
+type FreezeWindowConfig struct {
+	Name  string   `mapstructure:"name"`
+	Days  []string `mapstructure:"days"`
+	Start string   `mapstructure:"start"`
+	End   string   `mapstructure:"end"`
+}
+
+// ChangeConfig links write tool calls to change tickets passed as
+// changeTicket. Tickets must match Pattern when set and, with a Provider
+// (jira or servicenow), exist there in one of AllowedStatuses, or in any
+// status when none are listed. Required rejects changes without a ticket.
+type ChangeConfig struct {
+	Required        bool             `mapstructure:"required"`
+	Pattern         string           `mapstructure:"pattern"`
+	Provider        string           `mapstructure:"provider"`
+	AllowedStatuses []string         `mapstructure:"allowed_statuses"`
+	Jira            JiraConfig       `mapstructure:"jira"`
+	ServiceNow      ServiceNowConfig `mapstructure:"servicenow"`
+}
+
+// JiraConfig points change ticket lookups at a Jira site. Username is the
+// account email for Jira Cloud; leave it empty to send APIToken as a Data
+// Center personal access token.
+type JiraConfig struct {
+	URL      string `mapstructure:"url"`
+	Username string `mapstructure:"username"`
+	APIToken string `mapstructure:"api_token"`
+}
+
+// ServiceNowConfig points change ticket lookups at a ServiceNow instance,
+// e.g. https://example.service-now.com
+type ServiceNowConfig struct {
+	URL      string `mapstructure:"url"`
+	Username string `mapstructure:"username"`
+	Password string `mapstructure:"password"`
+}
+
+// NotifyConfig lists notification sinks and the routes deciding which events reach them
+type NotifyConfig struct {
+	Sinks  []NotifySinkConfig  `mapstructure:"sinks"`
+	Routes []NotifyRouteConfig `mapstructure:"routes"`
+}
+
+// NotifySinkConfig configures one destination. Type is slack, webhook, sns or email;
+// the remaining fields apply depending on the type. Webhook sinks with a secret
+// sign their requests; email sinks with a digest interval batch non-critical
+// events into one email per interval.
+type NotifySinkConfig struct {
+	Name           string            `mapstructure:"name"`
+	Type           string            `mapstructure:"type"`
+	URL            string            `mapstructure:"url"`
+	Headers        map[string]string `mapstructure:"headers"`
+	Secret         string            `mapstructure:"secret"`
+	TopicARN       string            `mapstructure:"topic_arn"`
+	From           string            `mapstructure:"from"`
+	To             []string          `mapstructure:"to"`
+	DigestInterval time.Duration     `mapstructure:"digest_interval"`
+}
+
+// HeartbeatConfig publishes a heartbeat to every target each Interval while
+// the server runs, a dead man's switch: operators alert on heartbeats that
+// stop arriving rather than on an error the server could no longer send.
+type HeartbeatConfig struct {
+	Interval time.Duration           `mapstructure:"interval"`
+	Targets  []HeartbeatTargetConfig `mapstructure:"targets"`
+}
+
+// HeartbeatTargetConfig configures one heartbeat destination. Type is sns,
+// webhook or cloudwatch. Webhooks, e.g. the ping URL of a cron monitoring
+// service, get a POST signed like webhook sinks when a secret is set;
+// cloudwatch puts the value 1 into Namespace/MetricName with Dimensions, to
+// be watched by an alarm that treats missing data as breaching.
+type HeartbeatTargetConfig struct {
+	Name       string            `mapstructure:"name"`
+	Type       string            `mapstructure:"type"`
+	URL        string            `mapstructure:"url"`
+	Headers    map[string]string `mapstructure:"headers"`
+	Secret     string            `mapstructure:"secret"`
+	TopicARN   string            `mapstructure:"topic_arn"`
+	Namespace  string            `mapstructure:"namespace"`
+	MetricName string            `mapstructure:"metric_name"`
+	Dimensions map[string]string `mapstructure:"dimensions"`
+}
+
+// NotifyRouteConfig sends events whose type matches one of the patterns (e.g.
+// "approval.*") and whose severity is at least MinSeverity to a sink. With
+// Teams, only events about resources owned by one of the teams are sent.
+type NotifyRouteConfig struct {
+	Sink        string   `mapstructure:"sink"`
+	Events      []string `mapstructure:"events"`
+	MinSeverity string   `mapstructure:"min_severity"`
+	Teams       []string `mapstructure:"teams"`
+}
+
+// ChatOpsConfig enables the Slack gateway, which lets humans run the same tools
+// as the AI through slash commands and buttons
+type ChatOpsConfig struct {
+	Enabled       bool   `mapstructure:"enabled"`
+	Listen        string `mapstructure:"listen"`
+	SigningSecret string `mapstructure:"signing_secret"`
+	// Users maps Slack user IDs to roles; DefaultRole applies to everyone else
+	// and leaving it empty refuses unlisted users
+	Users       map[string]string `mapstructure:"users"`
+	DefaultRole string            `mapstructure:"default_role"`
+	// Tools limits which tools can be run from chat; empty allows the
+	// read-only tools. Tools returning secrets are never run from chat.
+	Tools []string `mapstructure:"tools"`
+}
+
+// PrometheusConfig points the prom:// resources at a Prometheus-compatible
+// query endpoint (Prometheus, Thanos, Cortex, Mimir). Leaving URL empty
+// disables them.
+type PrometheusConfig struct {
+	URL         string            `mapstructure:"url"`
+	BearerToken string            `mapstructure:"bearer_token"`
+	Username    string            `mapstructure:"username"`
+	Password    string            `mapstructure:"password"`
+	Headers     map[string]string `mapstructure:"headers"`
+	Timeout     time.Duration     `mapstructure:"timeout"`
+	// MaxSeries caps how many series a query returns to keep responses small
+	MaxSeries int `mapstructure:"max_series"`
+}
+
+// LogsConfig selects where query-logs and summarize-logs read from. Backend is
+// cloudwatch (default), loki or elasticsearch; DefaultSource is the log group,
+// stream selector or index pattern used when a call does not name one.
+// InsightsTimeout bounds how long query-cloudwatch-logs waits for a Logs
+// Insights query, which always runs against CloudWatch Logs.
+type LogsConfig struct {
+	Backend         string              `mapstructure:"backend"`
+	DefaultSource   string              `mapstructure:"default_source"`
+	MaxEntries      int                 `mapstructure:"max_entries"`
+	Timeout         time.Duration       `mapstructure:"timeout"`
+	InsightsTimeout time.Duration       `mapstructure:"insights_timeout"`
+	Loki            LokiConfig          `mapstructure:"loki"`
+	Elasticsearch   ElasticsearchConfig `mapstructure:"elasticsearch"`
+}
+
+// LokiConfig points the log tools at Grafana Loki
+type LokiConfig struct {
+	URL         string            `mapstructure:"url"`
+	TenantID    string            `mapstructure:"tenant_id"`
+	Username    string            `mapstructure:"username"`
+	Password    string            `mapstructure:"password"`
+	BearerToken string            `mapstructure:"bearer_token"`
+	Headers     map[string]string `mapstructure:"headers"`
+}
+
+// ElasticsearchConfig points the log tools at Elasticsearch or OpenSearch. The
+// field names default to the Elastic Common Schema.
+type ElasticsearchConfig struct {
+	URL            string            `mapstructure:"url"`
+	Username       string            `mapstructure:"username"`
+	Password       string            `mapstructure:"password"`
+	APIKey         string            `mapstructure:"api_key"`
+	Headers        map[string]string `mapstructure:"headers"`
+	TimestampField string            `mapstructure:"timestamp_field"`
+	MessageField   string            `mapstructure:"message_field"`
+	LevelField     string            `mapstructure:"level_field"`
+}
+
+// MetricsConfig selects a SaaS monitoring platform for the metrics:// resources.
+// Provider is datadog or newrelic; leaving it empty disables them.
+type MetricsConfig struct {
+	Provider string        `mapstructure:"provider"`
+	Timeout  time.Duration `mapstructure:"timeout"`
+	// MaxSeries caps how many series a query returns to keep responses small
+	MaxSeries int            `mapstructure:"max_series"`
+	Datadog   DatadogConfig  `mapstructure:"datadog"`
+	NewRelic  NewRelicConfig `mapstructure:"newrelic"`
+}
+
+// DatadogConfig holds the keys for the Datadog metrics API. Site is the
+// Datadog site such as datadoghq.com or datadoghq.eu; URL overrides the
+// endpoint derived from it, e.g. for a proxy.
+type DatadogConfig struct {
+	Site   string `mapstructure:"site"`
+	APIKey string `mapstructure:"api_key"`
+	AppKey string `mapstructure:"app_key"`
+	URL    string `mapstructure:"url"`
+}
+
+// NewRelicConfig holds the user API key and account queried through NerdGraph.
+// Region is us (default) or eu; URL overrides the NerdGraph endpoint.
+type NewRelicConfig struct {
+	APIKey    string `mapstructure:"api_key"`
+	AccountID int    `mapstructure:"account_id"`
+	Region    string `mapstructure:"region"`
+	URL       string `mapstructure:"url"`
+}
+
+// OnCallConfig connects the server to PagerDuty or Opsgenie so it knows who is
+// on call. Provider is pagerduty or opsgenie; leaving it empty disables
+// aws://oncall/current and on-call mentions. SlackUsers maps responder email
+// addresses to Slack user IDs for mentions, and events of at least
+// NotifySeverity carry the current responders.
+type OnCallConfig struct {
+	Provider       string            `mapstructure:"provider"`
+	CacheTTL       time.Duration     `mapstructure:"cache_ttl"`
+	NotifySeverity string            `mapstructure:"notify_severity"`
+	SlackUsers     map[string]string `mapstructure:"slack_users"`
+	PagerDuty      PagerDutyConfig   `mapstructure:"pagerduty"`
+	Opsgenie       OpsgenieConfig    `mapstructure:"opsgenie"`
+}
+
+// PagerDutyConfig limits on-call lookups to schedules and escalation policies;
+// with neither, every on-call entry in the account is used
+type PagerDutyConfig struct {
+	APIToken            string   `mapstructure:"api_token"`
+	ScheduleIDs         []string `mapstructure:"schedule_ids"`
+	EscalationPolicyIDs []string `mapstructure:"escalation_policy_ids"`
+	URL                 string   `mapstructure:"url"`
+}
+
+// OpsgenieConfig names the schedules whose on-call users are looked up.
+// Region is us (default) or eu; URL overrides the API endpoint.
+type OpsgenieConfig struct {
+	APIKey    string   `mapstructure:"api_key"`
+	Schedules []string `mapstructure:"schedules"`
+	Region    string   `mapstructure:"region"`
+	URL       string   `mapstructure:"url"`
+}
+
+// ExportConfig writes the audit log, the inventory snapshots and the
+// anomalies the detector flags to S3 as Parquet files every Interval, under
+// Prefix/<table>/dt=YYYY-MM-DD/ for Athena tables partitioned by date. The
+// tables are audit, inventory and anomalies; every row names the server
+// (mcp.server_name), so a fleet of servers can share a bucket.
+type ExportConfig struct {
+	Enabled  bool          `mapstructure:"enabled"`
+	Bucket   string        `mapstructure:"bucket"`
+	Prefix   string        `mapstructure:"prefix"`
+	Interval time.Duration `mapstructure:"interval"`
+}
+
+// ManagementConfig serves the gRPC management API on Address, for control
+// planes running fleets of servers. Calls must carry Token as a bearer token;
+// with TLSCert and TLSKey the API is served over TLS, which it must be unless
+// Address is a loopback address.
+type ManagementConfig struct {
+	Enabled bool   `mapstructure:"enabled"`
+	Address string `mapstructure:"address"`
+	Token   string `mapstructure:"token"`
+	TLSCert string `mapstructure:"tls_cert"`
+	TLSKey  string `mapstructure:"tls_key"`
+}
+
+// AuditConfig controls the audit log of changes made through the server. With
+// a path, entries are appended to a JSON Lines file and survive restarts;
+// MaxEntries bounds how many are kept in memory for postmortems.
+type AuditConfig struct {
+	Path       string `mapstructure:"path"`
+	MaxEntries int    `mapstructure:"max_entries"`
+}
+
+// IdempotencyConfig protects write tools from replayed requests. A request
+// with the same JSON-RPC ID and payload as one answered within Window in the
+// same session gets the stored response instead of running again; zero
+// disables the check. With a path the responses are saved as JSON; sessions
+// end with the server, so responses are not replayed after a restart.
+type IdempotencyConfig struct {
+	Path   string        `mapstructure:"path"`
+	Window time.Duration `mapstructure:"window"`
+}
+
+// SuppressionsConfig controls where acknowledged, snoozed and suppressed
+// alerts are kept. With a path they are saved as JSON and survive restarts;
+// without one they are lost when the server stops.
+type SuppressionsConfig struct {
+	Path string `mapstructure:"path"`
+}
+
+// NotesConfig controls where the notes attached to resources with add-note
+// are kept. With a path they are saved as JSON and carry over from one
+// session to the next; without one they are lost when the server stops.
+type NotesConfig struct {
+	Path string `mapstructure:"path"`
+}
+
+// IgnoreConfig controls where the resources left out of anomaly detection,
+// orphan reports and the daily digest with ignore-resource are kept. With a
+// path they are saved as JSON and survive restarts.
+type IgnoreConfig struct {
+	Path string `mapstructure:"path"`
+}
+
+// AlertsConfig controls how alarms are presented before they are listed
+type AlertsConfig struct {
+	Grouping AlertGroupingConfig `mapstructure:"grouping"`
+}
+
+// AlertGroupingConfig deduplicates and groups related alarms in
+// aws://cloudwatch/alarms so one problem is listed once. Alarms watching the
+// same metric with the same condition are collapsed, and alarms a composite
+// alarm refers to are grouped under it. Rules then group the remaining alarms
+// in the same state by a shared dimension, in order; without rules alarms are
+// grouped by Auto Scaling group and then by instance.
+type AlertGroupingConfig struct {
+	Enabled bool                   `mapstructure:"enabled"`
+	Rules   []AlertGroupRuleConfig `mapstructure:"rules"`
+}
+
+// AlertGroupRuleConfig groups alarms sharing the value of Dimension, e.g.
+// DBInstanceIdentifier. Alarms limits the rule to alarm names matching one of
+// the patterns (path.Match syntax, e.g. "rds-*").
+type AlertGroupRuleConfig struct {
+	Name      string   `mapstructure:"name"`
+	Dimension string   `mapstructure:"dimension"`
+	Alarms    []string `mapstructure:"alarms"`
+}
+
+// BaselinesConfig controls the metric baselines the anomaly detector learns
+// from get-cloudwatch-metrics. With a path they are saved as JSON and survive
+// restarts. Datapoints Threshold or more standard deviations from the baseline
+// for their hour of the week are flagged as anomalies.
+type BaselinesConfig struct {
+	Path      string  `mapstructure:"path"`
+	Threshold float64 `mapstructure:"threshold"`
+}
+
+// InventoryConfig controls the instance list snapshot used for warm
+// startups. With a path, every instance list read from a provider is saved as
+// JSON; on startup the snapshot answers instance list resources, marked as
+// possibly stale, while the providers are listed again in the background.
+// Snapshots older than MaxAge are not served.
+//
+// With a RefreshInterval, a background refresher updates the snapshots that
+// often and instance lists are read from them. Providers that can list their
+// changes, such as EC2 through CloudTrail, are only listed in full every
+// FullRefreshInterval; zero lists them in full on every refresh.
+type InventoryConfig struct {
+	Path                string        `mapstructure:"path"`
+	MaxAge              time.Duration `mapstructure:"max_age"`
+	RefreshInterval     time.Duration `mapstructure:"refresh_interval"`
+	FullRefreshInterval time.Duration `mapstructure:"full_refresh_interval"`
+}
+
+// SLOConfig gates disruptive tools on service error budgets. Resources belong
+// to the service named by their ServiceTag tag. When a service has
+// BudgetThreshold or less of its error budget left (0.1 = 10%), disruptive
+// tools on its resources need overrideErrorBudget=true, and blocks and
+// overrides are published as notifications. Budgets are cached for CacheTTL.
+type SLOConfig struct {
+	ServiceTag      string             `mapstructure:"service_tag"`
+	BudgetThreshold float64            `mapstructure:"budget_threshold"`
+	CacheTTL        time.Duration      `mapstructure:"cache_ttl"`
+	Services        []SLOServiceConfig `mapstructure:"services"`
+}
+
+// SLOServiceConfig is the availability objective of a service in percent
+// (e.g. 99.9) and a query returning its error ratio (0-1) over the SLO window.
+// Source is prometheus (default, PromQL) or metrics (the configured provider).
+type SLOServiceConfig struct {
+	Name            string  `mapstructure:"name"`
+	Objective       float64 `mapstructure:"objective"`
+	Source          string  `mapstructure:"source"`
+	ErrorRatioQuery string  `mapstructure:"error_ratio_query"`
+}
+
+// GCPConfig enables the Compute Engine provider, serving gcp://compute/instances
+// next to the AWS resources. It is enabled when Project is set. Zones limits
+// it to some zones (default: all). CredentialsFile is a service account key;
+// without one, Application Default Credentials are used.
+type GCPConfig struct {
+	Project         string        `mapstructure:"project"`
+	Zones           []string      `mapstructure:"zones"`
+	CredentialsFile string        `mapstructure:"credentials_file"`
+	Timeout         time.Duration `mapstructure:"timeout"`
+}
+
+// AzureConfig enables the Azure VM provider, serving azure://vm/instances and
+// azure://vm/resource-groups. It is enabled when SubscriptionID is set.
+// ResourceGroups limits it to some resource groups (default: all). With a
+// ClientSecret the server signs in as the TenantID/ClientID service principal;
+// otherwise it uses the host's managed identity, the user-assigned one when
+// ClientID is set.
+type AzureConfig struct {
+	SubscriptionID string        `mapstructure:"subscription_id"`
+	ResourceGroups []string      `mapstructure:"resource_groups"`
+	TenantID       string        `mapstructure:"tenant_id"`
+	ClientID       string        `mapstructure:"client_id"`
+	ClientSecret   string        `mapstructure:"client_secret"`
+	Timeout        time.Duration `mapstructure:"timeout"`
+}
+
+// AthenaConfig sets the defaults and limits of run-athena-query. OutputLocation
+// is the s3:// prefix query results are written to; it may be left empty when
+// the workgroup sets its own. Queries are stopped once they scan more than
+// MaxScanGB or would cost more than MaxCostUSD at PricePerTB (zero disables a
+// limit); a workgroup data usage control is the hard guarantee. Queries are
+// checked to be a single read-only statement without INTO; the IAM role the
+// server runs as should not be able to write Glue tables either. MaxRows caps
+// the rows returned to the caller.
+type AthenaConfig struct {
+	Workgroup      string        `mapstructure:"workgroup"`
+	Database       string        `mapstructure:"database"`
+	OutputLocation string        `mapstructure:"output_location"`
+	MaxScanGB      float64       `mapstructure:"max_scan_gb"`
+	MaxCostUSD     float64       `mapstructure:"max_cost_usd"`
+	PricePerTB     float64       `mapstructure:"price_per_tb"`
+	MaxRows        int           `mapstructure:"max_rows"`
+	Timeout        time.Duration `mapstructure:"timeout"`
+}
+
+// RedshiftConfig sets the defaults of the Redshift query tools. Queries run
+// with the Data API on ClusterID, or on the Serverless Workgroup when no
+// cluster is set, in Database. They authenticate with the Secrets Manager
+// secret in SecretARN or, on a cluster, as DBUser. Queries are checked to be
+// a single SELECT, WITH, SHOW or EXPLAIN without INTO, but a function such as
+// pg_terminate_backend still acts from a SELECT: use a user with read-only
+// grants and no superuser rights. MaxRows caps the rows returned to the
+// caller.
+type RedshiftConfig struct {
+	ClusterID string `mapstructure:"cluster_id"`
+	Workgroup string `mapstructure:"workgroup"`
+	Database  string `mapstructure:"database"`
+	DBUser    string `mapstructure:"db_user"`
+	SecretARN string `mapstructure:"secret_arn"`
+	MaxRows   int    `mapstructure:"max_rows"`
+}
+
+// BackupConfig sets the defaults of the AWS Backup resources and tools.
+// On-demand backups go to VaultName and run as RoleARN, e.g. the
+// AWSBackupDefaultServiceRole; JobLookback is how far back backup jobs are
+// listed.
+type BackupConfig struct {
+	VaultName   string        `mapstructure:"vault_name"`
+	RoleARN     string        `mapstructure:"role_arn"`
+	JobLookback time.Duration `mapstructure:"job_lookback"`
+}
+
+// OwnershipConfig resolves which team owns a resource. Sources are tried in
+// order until one names an owner: "tags" reads tagging.owner_tags, "file" a
+// CODEOWNERS-style file of resource patterns and teams, and "api" asks an
+// external service. Answers are cached for CacheTTL.
+type OwnershipConfig struct {
+	Sources  []string              `mapstructure:"sources"`
+	File     string                `mapstructure:"file"`
+	API      OwnershipAPIConfig    `mapstructure:"api"`
+	CacheTTL time.Duration         `mapstructure:"cache_ttl"`
+	Teams    []OwnershipTeamConfig `mapstructure:"teams"`
+}
+
+// OwnershipAPIConfig is an HTTP endpoint answering who owns a resource. The
+// {resource} placeholder in URL is replaced with the escaped resource ID and
+// the response is JSON such as {"team": "payments"} or {"teams": [...]}.
+type OwnershipAPIConfig struct {
+	URL     string            `mapstructure:"url"`
+	Headers map[string]string `mapstructure:"headers"`
+}
+
+// OwnershipTeamConfig is how to reach a team: where its tickets go and which
+// channel and address to use when the agent writes to it
+type OwnershipTeamConfig struct {
+	Name         string `mapstructure:"name"`
+	Email        string `mapstructure:"email"`
+	SlackChannel string `mapstructure:"slack_channel"`
+	TicketQueue  string `mapstructure:"ticket_queue"`
+}
+
+// RemediationConfig controls verify-remediation: how long the signal that
+// prompted an action is watched afterwards and how often it is checked. Every
+// verified outcome is kept for aws://knowledge/remediations; with an
+// OutcomesPath they are appended to a JSON Lines file and survive restarts,
+// and MaxOutcomes bounds how many are kept in memory.
+type RemediationConfig struct {
+	VerifyDuration time.Duration `mapstructure:"verify_duration"`
+	VerifyInterval time.Duration `mapstructure:"verify_interval"`
+	OutcomesPath   string        `mapstructure:"outcomes_path"`
+	MaxOutcomes    int           `mapstructure:"max_outcomes"`
+}
+
+// DocsConfig indexes markdown runbooks and architecture docs for kb://docs.
+// Sources are local directories or S3 locations such as
+// s3://ops-docs/runbooks/; the index is rebuilt on the first read after
+// RefreshInterval, and documents larger than MaxDocumentSize are skipped.
+type DocsConfig struct {
+	Sources         []string      `mapstructure:"sources"`
+	RefreshInterval time.Duration `mapstructure:"refresh_interval"`
+	MaxDocumentSize int64         `mapstructure:"max_document_size"`
+}
+
+// SearchConfig enables the search tool, which finds resources by their names
+// and tags and knowledge base docs by meaning rather than exact identifiers.
+// Embedder is "local", a hashing embedder that matches words and their
+// spellings without a model, or "bedrock" to embed with BedrockModel.
+// Embeddings of unchanged items are reused when the index is rebuilt on the
+// first search after RefreshInterval.
+type SearchConfig struct {
+	Enabled         bool          `mapstructure:"enabled"`
+	Embedder        string        `mapstructure:"embedder"`
+	BedrockModel    string        `mapstructure:"bedrock_model"`
+	Dimensions      int           `mapstructure:"dimensions"`
+	RefreshInterval time.Duration `mapstructure:"refresh_interval"`
+}
+
+// SummariesConfig enables narratives written server-side by a Bedrock model,
+// such as the story behind the patterns of summarize-logs, so the raw data
+// does not have to be shipped to the client's model on every request.
+// Narratives are cached for CacheTTL by their input, and inputs longer than
+// MaxInputChars are truncated before they are sent.
+type SummariesConfig struct {
+	Enabled       bool          `mapstructure:"enabled"`
+	Model         string        `mapstructure:"model"`
+	MaxTokens     int           `mapstructure:"max_tokens"`
+	MaxInputChars int           `mapstructure:"max_input_chars"`
+	CacheTTL      time.Duration `mapstructure:"cache_ttl"`
+}
+
+// ParametersConfig exposes the SSM Parameter Store parameters under
+// PathPrefix as aws://ssm/parameters, and put-parameter only writes under it.
+// SecureString values are redacted unless RevealSecureValues is set, and new
+// SecureString parameters are encrypted with KMSKeyID or the AWS managed key.
+type ParametersConfig struct {
+	PathPrefix         string `mapstructure:"path_prefix"`
+	RevealSecureValues bool   `mapstructure:"reveal_secure_values"`
+	KMSKeyID           string `mapstructure:"kms_key_id"`
+}
+
+// CaptureConfig enables capture-traffic, which records packet headers on an
+// instance with tcpdump through SSM and uploads the pcap to Bucket under
+// Prefix. MaxDuration and MaxSizeMB can only lower the limits built into the
+// server, and download links expire after LinkExpiry.
+type CaptureConfig struct {
+	Bucket      string        `mapstructure:"bucket"`
+	Prefix      string        `mapstructure:"prefix"`
+	MaxDuration time.Duration `mapstructure:"max_duration"`
+	MaxSizeMB   int           `mapstructure:"max_size_mb"`
+	LinkExpiry  time.Duration `mapstructure:"link_expiry"`
+}
+
+// WindowsConfig enables get-windows-password. KeyDirectory holds the private
+// keys of the EC2 key pairs as <key pair name>.pem files; the password of an
+// instance is decrypted with the key of the pair it was launched with.
+type WindowsConfig struct {
+	KeyDirectory string `mapstructure:"key_directory"`
+}
+
+// DemoConfig pseudonymizes account IDs, IP addresses, resource IDs, emails
+// and resource names in every tool and resource output, so transcripts and
+// screenshots from real accounts can be shared. The same Seed gives the same
+// pseudonyms in every session, so keep it secret; without one they change
+// from run to run.
+type DemoConfig struct {
+	Enabled bool   `mapstructure:"enabled"`
+	Seed    string `mapstructure:"seed"`
 }
 
 func Load() (*Config, error) {
@@ -43,8 +820,93 @@ func Load() (*Config, error) {
 	viper.SetDefault("server.port", 8080)
 	viper.SetDefault("server.host", "localhost")
 	viper.SetDefault("aws.region", "us-west-2")
+	viper.SetDefault("aws.max_concurrency", 32)
+	viper.SetDefault("aws.service_concurrency", map[string]int{"ec2": 16, "cloudtrail": 2})
+	viper.SetDefault("aws.provider", AWSProviderAWS)
+	viper.SetDefault("aws.fake.instances", 12)
+	viper.SetDefault("aws.fake.seed", 1)
+	viper.SetDefault("organization.role_name", "OrganizationAccountAccessRole")
 	viper.SetDefault("mcp.server_name", "aws-mcp-server")
 	viper.SetDefault("mcp.version", "1.0.0")
+	viper.SetDefault("mcp.tool_timeout", "10m")
+	viper.SetDefault("mcp.tool_timeouts.encrypt-volume", "3h")
+	viper.SetDefault("mcp.transport", MCPTransportStdio)
+	viper.SetDefault("export.prefix", "aiops")
+	viper.SetDefault("export.interval", "1h")
+	viper.SetDefault("management.address", "127.0.0.1:9090")
+	viper.SetDefault("tagging.required_tags", []string{"Name", "Environment", "Owner"})
+	viper.SetDefault("tagging.owner_tags", []string{"Owner", "Team"})
+	viper.SetDefault("tagging.environment_tags", []string{"Environment", "Env", "Stage"})
+	viper.SetDefault("security.access_key_max_age_days", 90)
+	viper.SetDefault("security.unused_credential_days", 90)
+	viper.SetDefault("security.secret_max_age_days", 90)
+	viper.SetDefault("response.summaries", true)
+	viper.SetDefault("response.timezone", "UTC")
+	viper.SetDefault("response.relative_times", true)
+	viper.SetDefault("response.page_size", 500)
+	viper.SetDefault("cost.allow_override", true)
+	viper.SetDefault("cost.cur.format", "legacy")
+	viper.SetDefault("cost.cur.days", 14)
+	viper.SetDefault("cost.explorer.days", 30)
+	viper.SetDefault("cost.explorer.granularity", "DAILY")
+	viper.SetDefault("cost.explorer.metric", "UnblendedCost")
+	viper.SetDefault("access.role", "operator")
+	viper.SetDefault("access.admin_roles", []string{"admin"})
+	viper.SetDefault("access.max_elevation", "1h")
+	viper.SetDefault("approvals.queue_blocked", true)
+	viper.SetDefault("approvals.elicit_timeout", "5m")
+	viper.SetDefault("heartbeat.interval", "1m")
+	viper.SetDefault("chatops.listen", ":8090")
+	viper.SetDefault("prometheus.timeout", "30s")
+	viper.SetDefault("prometheus.max_series", 50)
+	viper.SetDefault("logs.backend", "cloudwatch")
+	viper.SetDefault("logs.max_entries", 5000)
+	viper.SetDefault("logs.timeout", "30s")
+	viper.SetDefault("logs.insights_timeout", "60s")
+	viper.SetDefault("metrics.timeout", "30s")
+	viper.SetDefault("metrics.max_series", 50)
+	viper.SetDefault("oncall.cache_ttl", "1m")
+	viper.SetDefault("oncall.notify_severity", "critical")
+	viper.SetDefault("audit.max_entries", 10000)
+	viper.SetDefault("idempotency.window", "10m")
+	viper.SetDefault("slo.service_tag", "Service")
+	viper.SetDefault("slo.budget_threshold", 0.1)
+	viper.SetDefault("slo.cache_ttl", "1m")
+	viper.SetDefault("gcp.timeout", "30s")
+	viper.SetDefault("azure.timeout", "30s")
+	viper.SetDefault("athena.workgroup", "primary")
+	viper.SetDefault("athena.max_scan_gb", 10)
+	viper.SetDefault("athena.price_per_tb", 5)
+	viper.SetDefault("athena.max_rows", 1000)
+	viper.SetDefault("athena.timeout", "5m")
+	viper.SetDefault("redshift.database", "dev")
+	viper.SetDefault("redshift.max_rows", 1000)
+	viper.SetDefault("backup.vault_name", "Default")
+	viper.SetDefault("backup.job_lookback", "168h")
+	viper.SetDefault("baselines.threshold", 3)
+	viper.SetDefault("inventory.max_age", "24h")
+	viper.SetDefault("inventory.full_refresh_interval", "1h")
+	viper.SetDefault("alerts.grouping.enabled", true)
+	viper.SetDefault("ownership.sources", []string{"tags", "file", "api"})
+	viper.SetDefault("ownership.cache_ttl", "5m")
+	viper.SetDefault("remediation.verify_duration", "5m")
+	viper.SetDefault("remediation.verify_interval", "30s")
+	viper.SetDefault("remediation.max_outcomes", 5000)
+	viper.SetDefault("docs.refresh_interval", "15m")
+	viper.SetDefault("docs.max_document_size", 1<<20)
+	viper.SetDefault("search.embedder", "local")
+	viper.SetDefault("search.bedrock_model", "amazon.titan-embed-text-v2:0")
+	viper.SetDefault("search.dimensions", 512)
+	viper.SetDefault("search.refresh_interval", "15m")
+	viper.SetDefault("summaries.model", "anthropic.claude-3-haiku-20240307-v1:0")
+	viper.SetDefault("summaries.max_tokens", 1024)
+	viper.SetDefault("summaries.max_input_chars", 50000)
+	viper.SetDefault("summaries.cache_ttl", "1h")
+	viper.SetDefault("parameters.path_prefix", "/")
+	viper.SetDefault("capture.prefix", "captures/")
+	viper.SetDefault("capture.max_duration", "60s")
+	viper.SetDefault("capture.max_size_mb", 20)
+	viper.SetDefault("capture.link_expiry", "1h")
 
 	// Try to read config file (optional)
 	if err := viper.ReadInConfig(); err != nil {
@@ -59,5 +921,9 @@ func Load() (*Config, error) {
 		return nil, fmt.Errorf("unable to decode config: %w", err)
 	}
 
+	if _, err := time.LoadLocation(config.Response.Timezone); err != nil {
+		return nil, fmt.Errorf("invalid response timezone %q: %w", config.Response.Timezone, err)
+	}
+
 	return &config, nil
 }
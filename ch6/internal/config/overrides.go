@@ -0,0 +1,179 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// overrideKind is the type of value a setting takes
+type overrideKind int
+
+const (
+	overrideInt overrideKind = iota
+	overrideFloat
+	overrideBool
+	overrideDuration
+)
+
+// overridable are the settings that can be changed while the server runs.
+// They are read on every request, so an override applies from the next one;
+// other settings are only read on startup.
+var overridable = map[string]overrideKind{
+	"response.page_size":      overrideInt,
+	"baselines.threshold":     overrideFloat,
+	"approvals.queue_blocked": overrideBool,
+	"approvals.elicit":        overrideBool,
+	"access.max_elevation":    overrideDuration,
+}
+
+// OverridableKeys returns the keys of the settings Overrides can change, sorted
+func OverridableKeys() []string {
+	keys := make([]string, 0, len(overridable))
+	for key := range overridable {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Overrides hold settings changed at runtime, keyed like the configuration,
+// e.g. response.page_size. They last until cleared or the server restarts.
+// A nil Overrides has none, so the configured values apply.
+type Overrides struct {
+	mu     sync.RWMutex
+	raw    map[string]string
+	values map[string]interface{}
+}
+
+// NewOverrides creates an empty set of overrides
+func NewOverrides() *Overrides {
+	return &Overrides{raw: make(map[string]string), values: make(map[string]interface{})}
+}
+
+// Apply sets and clears overrides. All of them are checked first, so either
+// every change applies or none does.
+func (o *Overrides) Apply(set map[string]string, clear []string) error {
+	parsed := make(map[string]interface{}, len(set))
+	var errs []error
+	for key, raw := range set {
+		value, err := parseOverride(key, strings.TrimSpace(raw))
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		parsed[key] = value
+	}
+	for _, key := range clear {
+		if _, ok := overridable[key]; !ok {
+			errs = append(errs, fmt.Errorf("%s cannot be overridden", key))
+		}
+	}
+	if err := errors.Join(errs...); err != nil {
+		return err
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	for _, key := range clear {
+		delete(o.raw, key)
+		delete(o.values, key)
+	}
+	for key, value := range parsed {
+		o.raw[key] = strings.TrimSpace(set[key])
+		o.values[key] = value
+	}
+	return nil
+}
+
+// All returns the overridden settings and their values as they were set
+func (o *Overrides) All() map[string]string {
+	all := make(map[string]string)
+	if o == nil {
+		return all
+	}
+
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	for key, value := range o.raw {
+		all[key] = value
+	}
+	return all
+}
+
+// Int returns the override of an integer setting, or its configured value
+func (o *Overrides) Int(key string, configured int) int {
+	if value, ok := o.get(key).(int); ok {
+		return value
+	}
+	return configured
+}
+
+// Float returns the override of a number setting, or its configured value
+func (o *Overrides) Float(key string, configured float64) float64 {
+	if value, ok := o.get(key).(float64); ok {
+		return value
+	}
+	return configured
+}
+
+// Bool returns the override of a boolean setting, or its configured value
+func (o *Overrides) Bool(key string, configured bool) bool {
+	if value, ok := o.get(key).(bool); ok {
+		return value
+	}
+	return configured
+}
+
+// Duration returns the override of a duration setting, or its configured value
+func (o *Overrides) Duration(key string, configured time.Duration) time.Duration {
+	if value, ok := o.get(key).(time.Duration); ok {
+		return value
+	}
+	return configured
+}
+
+// get returns the parsed override of a setting, nil when it is not overridden
+func (o *Overrides) get(key string) interface{} {
+	if o == nil {
+		return nil
+	}
+
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	return o.values[key]
+}
+
+// parseOverride checks that a setting can be overridden and parses its value
+func parseOverride(key, raw string) (interface{}, error) {
+	kind, ok := overridable[key]
+	if !ok {
+		return nil, fmt.Errorf("%s cannot be overridden", key)
+	}
+
+	var value interface{}
+	var err error
+	switch kind {
+	case overrideInt:
+		var n int
+		if n, err = strconv.Atoi(raw); err == nil && n < 0 {
+			err = errors.New("must not be negative")
+		}
+		value = n
+	case overrideFloat:
+		value, err = strconv.ParseFloat(raw, 64)
+	case overrideBool:
+		value, err = strconv.ParseBool(raw)
+	case overrideDuration:
+		value, err = time.ParseDuration(raw)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid value %q for %s: %w", raw, key, err)
+	}
+	return value, nil
+}
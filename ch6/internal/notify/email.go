@@ -0,0 +1,238 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"html/template"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sesv2"
+	sestypes "github.com/aws/aws-sdk-go-v2/service/sesv2/types"
+)
+
+// emailSender delivers a rendered email; it is SES in production
+type emailSender func(ctx context.Context, subject, htmlBody, textBody string) error
+
+// EmailSink emails events through SES as HTML. With a digest interval, events
+// below critical severity are collected and sent as one digest per interval,
+// while critical events are still emailed immediately.
+type EmailSink struct {
+	name           string
+	digestInterval time.Duration
+	send           emailSender
+
+	mu      sync.Mutex
+	pending []Event
+	since   time.Time
+}
+
+// NewEmailSink creates a sink that emails events from a verified SES identity.
+// A zero digestInterval sends every event immediately.
+func NewEmailSink(name string, client *sesv2.Client, from string, to []string, digestInterval time.Duration) *EmailSink {
+	return newEmailSink(name, digestInterval, func(ctx context.Context, subject, htmlBody, textBody string) error {
+		_, err := client.SendEmail(ctx, &sesv2.SendEmailInput{
+			FromEmailAddress: aws.String(from),
+			Destination:      &sestypes.Destination{ToAddresses: to},
+			Content: &sestypes.EmailContent{
+				Simple: &sestypes.Message{
+					Subject: &sestypes.Content{Data: aws.String(subject), Charset: aws.String("UTF-8")},
+					Body: &sestypes.Body{
+						Html: &sestypes.Content{Data: aws.String(htmlBody), Charset: aws.String("UTF-8")},
+						Text: &sestypes.Content{Data: aws.String(textBody), Charset: aws.String("UTF-8")},
+					},
+				},
+			},
+		})
+		if err != nil {
+			return fmt.Errorf("failed to send email: %w", err)
+		}
+		return nil
+	})
+}
+
+// newEmailSink creates an email sink with a custom sender
+func newEmailSink(name string, digestInterval time.Duration, send emailSender) *EmailSink {
+	return &EmailSink{
+		name:           name,
+		digestInterval: digestInterval,
+		send:           send,
+		since:          time.Now(),
+	}
+}
+
+// Name returns the configured sink name
+func (s *EmailSink) Name() string {
+	return s.name
+}
+
+// Send emails critical events right away and queues the rest for the digest
+func (s *EmailSink) Send(ctx context.Context, event Event) error {
+	if s.digestInterval > 0 && event.Severity < SeverityCritical {
+		s.mu.Lock()
+		s.pending = append(s.pending, event)
+		s.mu.Unlock()
+		return nil
+	}
+
+	htmlBody, err := render(alertTemplate, event)
+	if err != nil {
+		return err
+	}
+
+	subject := fmt.Sprintf("[%s] %s", event.Severity, event.Title)
+	return s.send(ctx, subject, htmlBody, event.Text())
+}
+
+// DigestInterval returns how often the digest is sent; zero disables digests
+func (s *EmailSink) DigestInterval() time.Duration {
+	return s.digestInterval
+}
+
+// Flush sends the pending events as one digest email. Nothing is sent when no
+// events were collected. Events are kept for the next digest if sending fails.
+func (s *EmailSink) Flush(ctx context.Context) error {
+	s.mu.Lock()
+	events := s.pending
+	since := s.since
+	s.pending = nil
+	s.since = time.Now()
+	s.mu.Unlock()
+
+	if len(events) == 0 {
+		return nil
+	}
+
+	digest := newDigest(events, since, time.Now())
+	htmlBody, err := render(digestTemplate, digest)
+	if err != nil {
+		return err
+	}
+
+	var text bytes.Buffer
+	for _, event := range events {
+		fmt.Fprintf(&text, "%s\n\n", event.Text())
+	}
+
+	subject := fmt.Sprintf("AIOps digest: %d events since %s", len(events), since.Format(time.RFC3339))
+	if err := s.send(ctx, subject, htmlBody, text.String()); err != nil {
+		s.mu.Lock()
+		s.pending = append(events, s.pending...)
+		s.since = since
+		s.mu.Unlock()
+		return err
+	}
+
+	return nil
+}
+
+// digest is the data rendered by the digest template
+type digest struct {
+	From   time.Time
+	To     time.Time
+	Counts map[string]int
+	Groups []digestGroup
+}
+
+// digestGroup lists the events of one type
+type digestGroup struct {
+	Type   string
+	Events []Event
+}
+
+// newDigest groups events by type, keeping the order in which types first appeared
+func newDigest(events []Event, from, to time.Time) digest {
+	d := digest{From: from, To: to, Counts: make(map[string]int)}
+
+	index := make(map[string]int)
+	for _, event := range events {
+		d.Counts[event.Severity.String()]++
+
+		i, exists := index[event.Type]
+		if !exists {
+			i = len(d.Groups)
+			index[event.Type] = i
+			d.Groups = append(d.Groups, digestGroup{Type: event.Type})
+		}
+		d.Groups[i].Events = append(d.Groups[i].Events, event)
+	}
+
+	return d
+}
+
+// render executes an HTML template into a string
+func render(tmpl *template.Template, data interface{}) (string, error) {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("failed to render email: %w", err)
+	}
+	return buf.String(), nil
+}
+
+// templateFuncs are shared by the email templates
+var templateFuncs = template.FuncMap{
+	"sortedKeys": sortedKeys,
+	"severityColor": func(severity Severity) string {
+		switch severity {
+		case SeverityCritical:
+			return "#c0392b"
+		case SeverityWarning:
+			return "#d68910"
+		default:
+			return "#2874a6"
+		}
+	},
+	"formatTime": func(t time.Time) string {
+		return t.Format(time.RFC3339)
+	},
+}
+
+// alertTemplate renders a single event
+var alertTemplate = template.Must(template.New("alert").Funcs(templateFuncs).Parse(`<!DOCTYPE html>
+<html>
+<body style="font-family: Arial, sans-serif; color: #222;">
+  <h2 style="color: {{severityColor .Severity}};">{{.Title}}</h2>
+  <p><strong>{{.Severity}}</strong> &middot; {{.Type}} &middot; {{formatTime .Time}}</p>
+  {{with .Message}}<p>{{.}}</p>{{end}}
+  {{if .Fields}}
+  <table style="border-collapse: collapse;">
+    {{range $key := sortedKeys .Fields}}
+    <tr>
+      <td style="padding: 4px 12px 4px 0; color: #555;">{{$key}}</td>
+      <td style="padding: 4px 0;">{{index $.Fields $key}}</td>
+    </tr>
+    {{end}}
+  </table>
+  {{end}}
+  {{if .OnCall}}
+  <p><strong>On call:</strong> {{range $i, $r := .OnCall}}{{if $i}}, {{end}}{{$r}}{{end}}</p>
+  {{end}}
+</body>
+</html>`))
+
+// digestTemplate renders the periodic digest
+var digestTemplate = template.Must(template.New("digest").Funcs(templateFuncs).Parse(`<!DOCTYPE html>
+<html>
+<body style="font-family: Arial, sans-serif; color: #222;">
+  <h2>AIOps digest</h2>
+  <p>{{formatTime .From}} &ndash; {{formatTime .To}}</p>
+  <p>
+    {{range $severity, $count := .Counts}}
+    <span style="margin-right: 16px;"><strong>{{$count}}</strong> {{$severity}}</span>
+    {{end}}
+  </p>
+  {{range .Groups}}
+  <h3>{{.Type}} ({{len .Events}})</h3>
+  <ul>
+    {{range .Events}}
+    <li>
+      <span style="color: {{severityColor .Severity}};">{{.Severity}}</span>
+      {{formatTime .Time}} &middot; {{.Title}}{{with .Message}} &mdash; {{.}}{{end}}
+    </li>
+    {{end}}
+  </ul>
+  {{end}}
+</body>
+</html>`))
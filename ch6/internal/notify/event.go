@@ -0,0 +1,131 @@
+package notify
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Severity orders events so routes can ignore low-priority noise
+type Severity int
+
+const (
+	SeverityInfo Severity = iota
+	SeverityWarning
+	SeverityCritical
+)
+
+// String returns the lowercase severity name
+func (s Severity) String() string {
+	switch s {
+	case SeverityWarning:
+		return "warning"
+	case SeverityCritical:
+		return "critical"
+	default:
+		return "info"
+	}
+}
+
+// MarshalText encodes the severity by name in JSON payloads
+func (s Severity) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// UnmarshalText decodes a severity name, so webhook consumers can reuse Event
+func (s *Severity) UnmarshalText(text []byte) error {
+	parsed, err := ParseSeverity(string(text))
+	if err != nil {
+		return err
+	}
+	*s = parsed
+	return nil
+}
+
+// ParseSeverity converts a configured severity name. An empty name is info.
+func ParseSeverity(name string) (Severity, error) {
+	switch strings.ToLower(name) {
+	case "", "info":
+		return SeverityInfo, nil
+	case "warning", "warn":
+		return SeverityWarning, nil
+	case "critical":
+		return SeverityCritical, nil
+	default:
+		return SeverityInfo, fmt.Errorf("unknown severity %q", name)
+	}
+}
+
+// Well-known event types. Approval decisions are published as "approval.<status>".
+const (
+	EventToolExecuted      = "tool.executed"
+	EventApprovalRequested = "approval.requested"
+	EventAnomalyDetected   = "anomaly.detected"
+	EventScheduleFired     = "schedule.fired"
+	EventBudgetBlocked     = "error_budget.blocked"
+	EventBudgetOverridden  = "error_budget.overridden"
+	EventRemediationOK     = "remediation.verified"
+	EventRemediationFailed = "remediation.failed"
+	EventElevationGranted  = "elevation.granted"
+	EventElevationEnded    = "elevation.ended"
+)
+
+// Responder is a person on call when an event was published
+type Responder struct {
+	Name     string `json:"name"`
+	Email    string `json:"email,omitempty"`
+	SlackID  string `json:"slack_id,omitempty"`
+	Schedule string `json:"schedule,omitempty"`
+}
+
+// String renders the responder for plain-text sinks
+func (r Responder) String() string {
+	if r.Email != "" && r.Email != r.Name {
+		return fmt.Sprintf("%s <%s>", r.Name, r.Email)
+	}
+	return r.Name
+}
+
+// Event is something worth telling humans or other systems about. Types are
+// dot-separated, e.g. "approval.requested" or "tool.executed". Resource is the
+// resource the event is about, if any; Teams and OnCall are filled in by the
+// notifier when ownership and on-call lookups are configured.
+type Event struct {
+	Type     string                 `json:"type"`
+	Severity Severity               `json:"severity"`
+	Title    string                 `json:"title"`
+	Message  string                 `json:"message"`
+	Fields   map[string]interface{} `json:"fields,omitempty"`
+	Resource string                 `json:"resource,omitempty"`
+	Teams    []string               `json:"teams,omitempty"`
+	OnCall   []Responder            `json:"on_call,omitempty"`
+	Time     time.Time              `json:"time"`
+}
+
+// Text renders the event as plain text for chat and email sinks
+func (e Event) Text() string {
+	return e.text(Responder.String)
+}
+
+// text renders the event, formatting on-call responders with mention
+func (e Event) text(mention func(Responder) string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "[%s] %s", strings.ToUpper(e.Severity.String()), e.Title)
+	if e.Message != "" {
+		fmt.Fprintf(&b, "\n%s", e.Message)
+	}
+	for _, key := range sortedKeys(e.Fields) {
+		fmt.Fprintf(&b, "\n• %s: %v", key, e.Fields[key])
+	}
+	if len(e.Teams) > 0 {
+		fmt.Fprintf(&b, "\nOwner: %s", strings.Join(e.Teams, ", "))
+	}
+	if len(e.OnCall) > 0 {
+		names := make([]string, 0, len(e.OnCall))
+		for _, responder := range e.OnCall {
+			names = append(names, mention(responder))
+		}
+		fmt.Fprintf(&b, "\nOn call: %s", strings.Join(names, ", "))
+	}
+	return b.String()
+}
@@ -0,0 +1,308 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+	cwtypes "github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+	"github.com/aws/aws-sdk-go-v2/service/sns"
+
+	"aws-mcp-server/internal/config"
+	"aws-mcp-server/internal/logging"
+)
+
+// EventHeartbeat is the event type sent with heartbeats
+const EventHeartbeat = "heartbeat"
+
+// Heartbeat defaults: the interval and the CloudWatch metric beats are put into
+const (
+	defaultHeartbeatInterval   = time.Minute
+	defaultHeartbeatNamespace  = "AIOps/MCPServer"
+	defaultHeartbeatMetricName = "Heartbeat"
+)
+
+// Pulse is one heartbeat
+type Pulse struct {
+	Type          string    `json:"type"`
+	Server        string    `json:"server"`
+	Host          string    `json:"host,omitempty"`
+	StartedAt     time.Time `json:"started_at"`
+	SentAt        time.Time `json:"sent_at"`
+	UptimeSeconds int64     `json:"uptime_seconds"`
+}
+
+// HeartbeatTarget receives heartbeats
+type HeartbeatTarget interface {
+	Name() string
+	Beat(ctx context.Context, pulse Pulse) error
+}
+
+// Heartbeat publishes a pulse to every target on an interval, so monitoring
+// notices when the server stops running
+type Heartbeat struct {
+	targets   []HeartbeatTarget
+	interval  time.Duration
+	server    string
+	host      string
+	startedAt time.Time
+	logger    *logging.Logger
+}
+
+// NewHeartbeat builds the configured targets. Invalid targets are skipped and
+// reported in the returned error. Without valid targets it returns nil, which
+// Run treats as disabled.
+func NewHeartbeat(cfg config.HeartbeatConfig, awsCfg aws.Config, server string, logger *logging.Logger) (*Heartbeat, error) {
+	var errs []error
+
+	var targets []HeartbeatTarget
+	for _, targetCfg := range cfg.Targets {
+		target, err := newHeartbeatTarget(targetCfg, awsCfg)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("heartbeat target %q: %w", targetCfg.Name, err))
+			continue
+		}
+		targets = append(targets, target)
+	}
+
+	if cfg.Interval < 0 {
+		errs = append(errs, errors.New("heartbeat interval must not be negative"))
+		targets = nil
+	}
+	if len(targets) == 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	interval := cfg.Interval
+	if interval == 0 {
+		interval = defaultHeartbeatInterval
+	}
+	host, _ := os.Hostname()
+
+	return &Heartbeat{
+		targets:   targets,
+		interval:  interval,
+		server:    server,
+		host:      host,
+		startedAt: time.Now(),
+		logger:    logger,
+	}, errors.Join(errs...)
+}
+
+// newHeartbeatTarget creates a target from its configuration
+func newHeartbeatTarget(cfg config.HeartbeatTargetConfig, awsCfg aws.Config) (HeartbeatTarget, error) {
+	if cfg.Name == "" {
+		return nil, errors.New("name is required")
+	}
+
+	switch cfg.Type {
+	case "webhook":
+		if cfg.URL == "" {
+			return nil, errors.New("url is required for webhook targets")
+		}
+		return NewWebhookHeartbeat(cfg.Name, cfg.URL, cfg.Headers, cfg.Secret), nil
+	case "sns":
+		if cfg.TopicARN == "" {
+			return nil, errors.New("topic_arn is required for sns targets")
+		}
+		return NewSNSHeartbeat(cfg.Name, sns.NewFromConfig(awsCfg), cfg.TopicARN), nil
+	case "cloudwatch":
+		namespace := cfg.Namespace
+		if namespace == "" {
+			namespace = defaultHeartbeatNamespace
+		}
+		metricName := cfg.MetricName
+		if metricName == "" {
+			metricName = defaultHeartbeatMetricName
+		}
+		return NewMetricHeartbeat(cfg.Name, cloudwatch.NewFromConfig(awsCfg), namespace, metricName, cfg.Dimensions), nil
+	default:
+		return nil, fmt.Errorf("unknown heartbeat target type %q", cfg.Type)
+	}
+}
+
+// Run sends a heartbeat right away and then every interval until ctx is
+// cancelled. Failed beats are logged; the next one is tried regardless.
+func (h *Heartbeat) Run(ctx context.Context) {
+	if h == nil {
+		return
+	}
+
+	h.beat(ctx)
+
+	ticker := time.NewTicker(h.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			h.beat(ctx)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// beat sends one pulse to every target
+func (h *Heartbeat) beat(ctx context.Context) {
+	now := time.Now()
+	pulse := Pulse{
+		Type:          EventHeartbeat,
+		Server:        h.server,
+		Host:          h.host,
+		StartedAt:     h.startedAt,
+		SentAt:        now,
+		UptimeSeconds: int64(now.Sub(h.startedAt).Seconds()),
+	}
+
+	for _, target := range h.targets {
+		beatCtx, cancel := context.WithTimeout(ctx, sendTimeout)
+		if err := target.Beat(beatCtx, pulse); err != nil && ctx.Err() == nil {
+			h.logger.WithError(err).WithField("target", target.Name()).Warn("Failed to send heartbeat")
+		}
+		cancel()
+	}
+}
+
+// WebhookHeartbeat posts heartbeats as JSON, e.g. to the ping URL of a cron
+// monitoring service, signed like webhook sinks when a secret is set
+type WebhookHeartbeat struct {
+	sink *WebhookSink
+}
+
+// NewWebhookHeartbeat creates a target that POSTs heartbeats to url
+func NewWebhookHeartbeat(name, url string, headers map[string]string, secret string) *WebhookHeartbeat {
+	return &WebhookHeartbeat{sink: NewWebhookSink(name, url, headers, secret)}
+}
+
+// Name returns the configured target name
+func (t *WebhookHeartbeat) Name() string {
+	return t.sink.name
+}
+
+// Beat posts the pulse
+func (t *WebhookHeartbeat) Beat(ctx context.Context, pulse Pulse) error {
+	body, err := json.Marshal(pulse)
+	if err != nil {
+		return fmt.Errorf("failed to marshal heartbeat: %w", err)
+	}
+
+	headers := make(map[string]string, len(t.sink.headers)+3)
+	for key, value := range t.sink.headers {
+		headers[key] = value
+	}
+	headers[EventHeader] = EventHeartbeat
+
+	if t.sink.secret != "" {
+		timestamp := strconv.FormatInt(pulse.SentAt.Unix(), 10)
+		headers[TimestampHeader] = timestamp
+		headers[SignatureHeader] = Sign(t.sink.secret, timestamp, body)
+	}
+
+	return post(ctx, t.sink.client, t.sink.url, headers, body)
+}
+
+// SNSHeartbeat publishes heartbeats to an SNS topic
+type SNSHeartbeat struct {
+	name     string
+	client   *sns.Client
+	topicARN string
+}
+
+// NewSNSHeartbeat creates a target that publishes to topicARN
+func NewSNSHeartbeat(name string, client *sns.Client, topicARN string) *SNSHeartbeat {
+	return &SNSHeartbeat{
+		name:     name,
+		client:   client,
+		topicARN: topicARN,
+	}
+}
+
+// Name returns the configured target name
+func (t *SNSHeartbeat) Name() string {
+	return t.name
+}
+
+// Beat publishes the pulse as JSON
+func (t *SNSHeartbeat) Beat(ctx context.Context, pulse Pulse) error {
+	message, err := json.Marshal(pulse)
+	if err != nil {
+		return fmt.Errorf("failed to marshal heartbeat: %w", err)
+	}
+
+	_, err = t.client.Publish(ctx, &sns.PublishInput{
+		TopicArn: aws.String(t.topicARN),
+		Subject:  snsSubject("Heartbeat from " + pulse.Server),
+		Message:  aws.String(string(message)),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to publish to %s: %w", t.topicARN, err)
+	}
+	return nil
+}
+
+// MetricHeartbeat puts the value 1 into a CloudWatch metric on every beat, for
+// an alarm that treats missing data as breaching
+type MetricHeartbeat struct {
+	name       string
+	client     *cloudwatch.Client
+	namespace  string
+	metricName string
+	dimensions []cwtypes.Dimension
+}
+
+// NewMetricHeartbeat creates a target that puts namespace/metricName with the
+// dimensions
+func NewMetricHeartbeat(name string, client *cloudwatch.Client, namespace, metricName string, dimensions map[string]string) *MetricHeartbeat {
+	keys := make([]string, 0, len(dimensions))
+	for key := range dimensions {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	metricDimensions := make([]cwtypes.Dimension, 0, len(keys))
+	for _, key := range keys {
+		metricDimensions = append(metricDimensions, cwtypes.Dimension{
+			Name:  aws.String(key),
+			Value: aws.String(dimensions[key]),
+		})
+	}
+
+	return &MetricHeartbeat{
+		name:       name,
+		client:     client,
+		namespace:  namespace,
+		metricName: metricName,
+		dimensions: metricDimensions,
+	}
+}
+
+// Name returns the configured target name
+func (t *MetricHeartbeat) Name() string {
+	return t.name
+}
+
+// Beat puts one data point
+func (t *MetricHeartbeat) Beat(ctx context.Context, pulse Pulse) error {
+	_, err := t.client.PutMetricData(ctx, &cloudwatch.PutMetricDataInput{
+		Namespace: aws.String(t.namespace),
+		MetricData: []cwtypes.MetricDatum{{
+			MetricName: aws.String(t.metricName),
+			Dimensions: t.dimensions,
+			Timestamp:  aws.Time(pulse.SentAt),
+			Value:      aws.Float64(1),
+			Unit:       cwtypes.StandardUnitCount,
+		}},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to put %s/%s: %w", t.namespace, t.metricName, err)
+	}
+	return nil
+}
@@ -0,0 +1,357 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sesv2"
+	"github.com/aws/aws-sdk-go-v2/service/sns"
+
+	"aws-mcp-server/internal/config"
+	"aws-mcp-server/internal/logging"
+
+	"github.com/sirupsen/logrus"
+)
+
+// sendTimeout bounds how long a single sink may take to deliver an event
+const sendTimeout = 10 * time.Second
+
+// Sink delivers events to one destination
+type Sink interface {
+	Name() string
+	Send(ctx context.Context, event Event) error
+}
+
+// DigestSink is a sink that batches events and sends them periodically
+type DigestSink interface {
+	Sink
+	DigestInterval() time.Duration
+	Flush(ctx context.Context) error
+}
+
+// Route sends events matching any of the type patterns and at least the minimum
+// severity to a sink. Patterns use path.Match syntax, e.g. "approval.*". Routes
+// with teams only take events owned by one of them.
+type Route struct {
+	Sink        string
+	Events      []string
+	MinSeverity Severity
+	Teams       []string
+}
+
+// matches reports whether the route applies to the event
+func (r Route) matches(event Event) bool {
+	if event.Severity < r.MinSeverity {
+		return false
+	}
+	if len(r.Teams) > 0 && !matchesAny(r.Teams, event.Teams...) {
+		return false
+	}
+	if len(r.Events) == 0 {
+		return true
+	}
+	return matchesAny(r.Events, event.Type)
+}
+
+// matchesAny reports whether any value matches any of the patterns
+func matchesAny(patterns []string, values ...string) bool {
+	for _, pattern := range patterns {
+		for _, value := range values {
+			if matched, _ := path.Match(pattern, value); matched {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// OnCallLookup returns the people currently on call
+type OnCallLookup func(ctx context.Context) ([]Responder, error)
+
+// OwnerLookup returns the teams owning a resource, primary team first
+type OwnerLookup func(ctx context.Context, resource string) ([]string, error)
+
+// Notifier routes events to sinks. Delivery is asynchronous so callers never
+// wait on a slow sink; failures are logged.
+type Notifier struct {
+	sinks  map[string]Sink
+	routes []Route
+	logger *logging.Logger
+	wg     sync.WaitGroup
+
+	onCall         OnCallLookup
+	onCallSeverity Severity
+	owners         OwnerLookup
+}
+
+// NewNotifier creates a notifier from ready-made sinks and routes
+func NewNotifier(sinks []Sink, routes []Route, logger *logging.Logger) *Notifier {
+	n := &Notifier{
+		sinks:  make(map[string]Sink, len(sinks)),
+		routes: routes,
+		logger: logger,
+	}
+	for _, sink := range sinks {
+		n.sinks[sink.Name()] = sink
+	}
+	return n
+}
+
+// New builds the configured sinks and routes. Invalid sinks and routes are
+// skipped and reported in the returned error; the notifier stays usable.
+func New(cfg config.NotifyConfig, awsCfg aws.Config, logger *logging.Logger) (*Notifier, error) {
+	var errs []error
+
+	var sinks []Sink
+	for _, sinkCfg := range cfg.Sinks {
+		sink, err := newSink(sinkCfg, awsCfg)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("notification sink %q: %w", sinkCfg.Name, err))
+			continue
+		}
+		sinks = append(sinks, sink)
+	}
+
+	var routes []Route
+	for i, routeCfg := range cfg.Routes {
+		severity, err := ParseSeverity(routeCfg.MinSeverity)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("notification route %d: %w", i+1, err))
+			continue
+		}
+		routes = append(routes, Route{
+			Sink:        routeCfg.Sink,
+			Events:      routeCfg.Events,
+			MinSeverity: severity,
+			Teams:       routeCfg.Teams,
+		})
+	}
+
+	n := NewNotifier(sinks, routes, logger)
+	for _, route := range routes {
+		if _, exists := n.sinks[route.Sink]; !exists {
+			errs = append(errs, fmt.Errorf("notification route references unknown sink %q", route.Sink))
+		}
+	}
+
+	return n, errors.Join(errs...)
+}
+
+// newSink creates a sink from its configuration
+func newSink(cfg config.NotifySinkConfig, awsCfg aws.Config) (Sink, error) {
+	if cfg.Name == "" {
+		return nil, errors.New("name is required")
+	}
+
+	switch cfg.Type {
+	case "slack":
+		if cfg.URL == "" {
+			return nil, errors.New("url is required for slack sinks")
+		}
+		return NewSlackSink(cfg.Name, cfg.URL), nil
+	case "webhook":
+		if cfg.URL == "" {
+			return nil, errors.New("url is required for webhook sinks")
+		}
+		return NewWebhookSink(cfg.Name, cfg.URL, cfg.Headers, cfg.Secret), nil
+	case "sns":
+		if cfg.TopicARN == "" {
+			return nil, errors.New("topic_arn is required for sns sinks")
+		}
+		return NewSNSSink(cfg.Name, sns.NewFromConfig(awsCfg), cfg.TopicARN), nil
+	case "email":
+		if cfg.From == "" || len(cfg.To) == 0 {
+			return nil, errors.New("from and to are required for email sinks")
+		}
+		if cfg.DigestInterval < 0 {
+			return nil, errors.New("digest_interval must not be negative")
+		}
+		return NewEmailSink(cfg.Name, sesv2.NewFromConfig(awsCfg), cfg.From, cfg.To, cfg.DigestInterval), nil
+	default:
+		return nil, fmt.Errorf("unknown sink type %q", cfg.Type)
+	}
+}
+
+// SetOnCall makes the notifier attach the current on-call responders to events
+// of at least minSeverity, so sinks can mention them
+func (n *Notifier) SetOnCall(lookup OnCallLookup, minSeverity Severity) {
+	if n == nil {
+		return
+	}
+	n.onCall = lookup
+	n.onCallSeverity = minSeverity
+}
+
+// SetOwnership makes the notifier look up which teams own an event's resource,
+// so routes can send it to the owning team
+func (n *Notifier) SetOwnership(lookup OwnerLookup) {
+	if n == nil {
+		return
+	}
+	n.owners = lookup
+}
+
+// Notify delivers the event to every sink with a matching route. A nil notifier
+// discards events, so callers need not check whether notifications are configured.
+func (n *Notifier) Notify(event Event) {
+	if n == nil {
+		return
+	}
+
+	if event.Time.IsZero() {
+		event.Time = time.Now()
+	}
+
+	if n.owners == nil || event.Resource == "" || len(event.Teams) > 0 {
+		n.route(event)
+		return
+	}
+
+	// Routes may depend on the owning team, which is looked up off the
+	// caller's goroutine like on-call responders
+	n.wg.Add(1)
+	go func() {
+		defer n.wg.Done()
+
+		ctx, cancel := context.WithTimeout(context.Background(), sendTimeout)
+		defer cancel()
+
+		teams, err := n.owners(ctx, event.Resource)
+		if err != nil {
+			n.logger.WithError(err).WithField("resource", event.Resource).Warn("Failed to look up resource owner")
+		}
+		event.Teams = teams
+		n.route(event)
+	}()
+}
+
+// route delivers the event to the sinks of matching routes, attaching the
+// on-call responders first when the event is important enough
+func (n *Notifier) route(event Event) {
+	var targets []Sink
+	delivered := make(map[string]bool)
+	for _, route := range n.routes {
+		sink, exists := n.sinks[route.Sink]
+		if !exists || delivered[route.Sink] || !route.matches(event) {
+			continue
+		}
+		delivered[route.Sink] = true
+		targets = append(targets, sink)
+	}
+	if len(targets) == 0 {
+		return
+	}
+
+	if n.onCall == nil || event.Severity < n.onCallSeverity || len(event.OnCall) > 0 {
+		n.deliver(targets, event)
+		return
+	}
+
+	// Looking up who is on call may take a network round trip, so it happens
+	// off the caller's goroutine, once for all sinks
+	n.wg.Add(1)
+	go func() {
+		defer n.wg.Done()
+
+		ctx, cancel := context.WithTimeout(context.Background(), sendTimeout)
+		defer cancel()
+
+		responders, err := n.onCall(ctx)
+		if err != nil {
+			n.logger.WithError(err).WithField("event", event.Type).Warn("Failed to look up on-call responders")
+		}
+		event.OnCall = responders
+		n.deliver(targets, event)
+	}()
+}
+
+// deliver sends the event to each sink on its own goroutine
+func (n *Notifier) deliver(targets []Sink, event Event) {
+	for _, sink := range targets {
+		n.wg.Add(1)
+		go func(sink Sink) {
+			defer n.wg.Done()
+
+			ctx, cancel := context.WithTimeout(context.Background(), sendTimeout)
+			defer cancel()
+
+			if err := sink.Send(ctx, event); err != nil {
+				n.logger.WithError(err).WithFields(logrus.Fields{
+					"sink":  sink.Name(),
+					"event": event.Type,
+				}).Warn("Failed to deliver notification")
+			}
+		}(sink)
+	}
+}
+
+// Start flushes digest sinks on their interval until ctx is cancelled, then
+// flushes them one last time. Wait covers the final flush.
+func (n *Notifier) Start(ctx context.Context) {
+	if n == nil {
+		return
+	}
+
+	for _, sink := range n.sinks {
+		digestSink, ok := sink.(DigestSink)
+		if !ok || digestSink.DigestInterval() <= 0 {
+			continue
+		}
+
+		n.wg.Add(1)
+		go func() {
+			defer n.wg.Done()
+			n.runDigest(ctx, digestSink)
+		}()
+	}
+}
+
+// runDigest flushes one digest sink on its interval
+func (n *Notifier) runDigest(ctx context.Context, sink DigestSink) {
+	ticker := time.NewTicker(sink.DigestInterval())
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			n.flush(ctx, sink)
+		case <-ctx.Done():
+			// The run context is gone, so the final digest gets its own deadline
+			flushCtx, cancel := context.WithTimeout(context.Background(), sendTimeout)
+			n.flush(flushCtx, sink)
+			cancel()
+			return
+		}
+	}
+}
+
+// flush sends a digest and logs failures; unsent events stay queued in the sink
+func (n *Notifier) flush(ctx context.Context, sink DigestSink) {
+	if err := sink.Flush(ctx); err != nil {
+		n.logger.WithError(err).WithField("sink", sink.Name()).Warn("Failed to deliver notification digest")
+	}
+}
+
+// Wait blocks until all in-flight deliveries finish, e.g. before shutdown
+func (n *Notifier) Wait() {
+	if n == nil {
+		return
+	}
+	n.wg.Wait()
+}
+
+// sortedKeys returns map keys in a stable order for rendering
+func sortedKeys(fields map[string]interface{}) []string {
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,359 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"aws-mcp-server/internal/config"
+	"aws-mcp-server/internal/logging"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingSink keeps delivered events in memory
+type recordingSink struct {
+	name   string
+	mu     sync.Mutex
+	events []Event
+}
+
+func (s *recordingSink) Name() string { return s.name }
+
+func (s *recordingSink) Send(ctx context.Context, event Event) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.events = append(s.events, event)
+	return nil
+}
+
+func TestNotifierRouting(t *testing.T) {
+	chat := &recordingSink{name: "chat"}
+	pager := &recordingSink{name: "pager"}
+
+	n := NewNotifier([]Sink{chat, pager}, []Route{
+		{Sink: "chat", Events: []string{"approval.*", "tool.executed"}},
+		{Sink: "pager", MinSeverity: SeverityCritical},
+		// A second matching route must not deliver twice
+		{Sink: "chat", Events: []string{"approval.requested"}},
+	}, logging.NewLogger("error", "text"))
+
+	n.Notify(Event{Type: "approval.requested", Severity: SeverityWarning, Title: "needs approval"})
+	n.Notify(Event{Type: "tool.executed", Severity: SeverityInfo, Title: "stopped"})
+	n.Notify(Event{Type: "anomaly.detected", Severity: SeverityCritical, Title: "cost spike"})
+	n.Wait()
+
+	require.Len(t, chat.events, 2)
+	require.Len(t, pager.events, 1)
+	assert.Equal(t, "anomaly.detected", pager.events[0].Type)
+	assert.False(t, pager.events[0].Time.IsZero())
+}
+
+func TestNotifierRoutesByOwner(t *testing.T) {
+	payments := &recordingSink{name: "payments"}
+	platform := &recordingSink{name: "platform"}
+	n := NewNotifier([]Sink{payments, platform}, []Route{
+		{Sink: "payments", Teams: []string{"payments"}},
+		{Sink: "platform", Teams: []string{"platform", "sre-*"}},
+	}, logging.NewLogger("error", "text"))
+
+	n.SetOwnership(func(ctx context.Context, resource string) ([]string, error) {
+		if resource == "i-0payments" {
+			return []string{"payments"}, nil
+		}
+		return nil, nil
+	})
+
+	n.Notify(Event{Type: "tool.executed", Title: "stopped", Resource: "i-0payments"})
+	n.Notify(Event{Type: "tool.executed", Title: "stopped", Resource: "i-0unowned"})
+	n.Notify(Event{Type: "tool.executed", Title: "stopped", Teams: []string{"sre-core"}})
+	n.Wait()
+
+	require.Len(t, payments.events, 1)
+	assert.Equal(t, []string{"payments"}, payments.events[0].Teams)
+	assert.Contains(t, payments.events[0].Text(), "Owner: payments")
+	require.Len(t, platform.events, 1, "unowned events skip team routes")
+	assert.Equal(t, []string{"sre-core"}, platform.events[0].Teams)
+}
+
+func TestNotifierAttachesOnCall(t *testing.T) {
+	chat := &recordingSink{name: "chat"}
+	n := NewNotifier([]Sink{chat}, []Route{{Sink: "chat"}}, logging.NewLogger("error", "text"))
+
+	lookups := 0
+	n.SetOnCall(func(ctx context.Context) ([]Responder, error) {
+		lookups++
+		return []Responder{{Name: "Dana Lee", Email: "dana@example.com", SlackID: "U123"}}, nil
+	}, SeverityWarning)
+
+	n.Notify(Event{Type: "tool.executed", Severity: SeverityInfo, Title: "stopped"})
+	n.Wait()
+	n.Notify(Event{Type: "anomaly.detected", Severity: SeverityCritical, Title: "cost spike"})
+	n.Wait()
+
+	require.Len(t, chat.events, 2)
+	assert.Equal(t, 1, lookups, "info events are not worth a lookup")
+	assert.Empty(t, chat.events[0].OnCall)
+	require.Len(t, chat.events[1].OnCall, 1)
+
+	event := chat.events[1]
+	assert.Contains(t, event.Text(), "On call: Dana Lee <dana@example.com>")
+	assert.Contains(t, event.text(slackMention), "On call: <@U123>")
+}
+
+func TestWebhookSink(t *testing.T) {
+	var received Event
+	var token string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token = r.Header.Get("X-Token")
+		json.NewDecoder(r.Body).Decode(&received)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	sink := NewWebhookSink("automation", server.URL, map[string]string{"X-Token": "secret"}, "")
+	require.NoError(t, sink.Send(context.Background(), Event{Type: "tool.executed", Title: "stopped i-abc"}))
+
+	assert.Equal(t, "secret", token)
+	assert.Equal(t, "tool.executed", received.Type)
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "nope", http.StatusInternalServerError)
+	}))
+	defer failing.Close()
+
+	err := NewWebhookSink("broken", failing.URL, nil, "").Send(context.Background(), Event{Type: "tool.executed"})
+	assert.ErrorContains(t, err, "500")
+}
+
+func TestWebhookSinkSignsRequests(t *testing.T) {
+	var body []byte
+	var header http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header.Clone()
+		body, _ = io.ReadAll(r.Body)
+	}))
+	defer server.Close()
+
+	sink := NewWebhookSink("automation", server.URL, nil, "s3cret")
+	require.NoError(t, sink.Send(context.Background(), Event{Type: EventAnomalyDetected, Title: "cost spike"}))
+
+	assert.Equal(t, EventAnomalyDetected, header.Get(EventHeader))
+	timestamp := header.Get(TimestampHeader)
+	signature := header.Get(SignatureHeader)
+	require.NotEmpty(t, timestamp)
+	assert.Regexp(t, "^sha256=[0-9a-f]{64}$", signature)
+
+	now := time.Now()
+	assert.NoError(t, VerifySignature("s3cret", timestamp, body, signature, 5*time.Minute, now))
+	assert.ErrorContains(t, VerifySignature("wrong", timestamp, body, signature, 5*time.Minute, now), "mismatch")
+	assert.ErrorContains(t, VerifySignature("s3cret", timestamp, append(body, ' '), signature, 5*time.Minute, now), "mismatch")
+	assert.ErrorContains(t, VerifySignature("s3cret", timestamp, body, signature, 5*time.Minute, now.Add(time.Hour)), "old")
+}
+
+func TestSignMatchesPlainHMAC(t *testing.T) {
+	// Receivers in other languages compute HMAC-SHA256 over "<timestamp>.<body>",
+	// e.g. printf '1700000000.{"type":"tool.executed"}' | openssl dgst -sha256 -hmac key
+	assert.Equal(t,
+		"sha256=dcd7b6adad0c191e078a79fceab6cd86cc91e931ef523d19b17a1889dfd477c7",
+		Sign("key", "1700000000", []byte(`{"type":"tool.executed"}`)))
+}
+
+func TestSNSSubjectIsPrintableASCII(t *testing.T) {
+	assert.Equal(t, "stop-ec2-instance blocked", aws.ToString(snsSubject("stop-ec2-instance blocked")))
+
+	// SNS rejects subjects with non-ASCII characters or line breaks
+	assert.Equal(t, "Caf? closed:  disk full", aws.ToString(snsSubject("Café closed:\r\ndisk full")))
+	assert.Equal(t, "?? alarm", aws.ToString(snsSubject("🔥🔥 alarm")), "characters count once, whatever their UTF-8 length")
+
+	subject := aws.ToString(snsSubject("!" + strings.Repeat("é", 150)))
+	assert.Len(t, subject, snsSubjectLimit)
+	assert.Equal(t, "!"+strings.Repeat("?", snsSubjectLimit-1), subject)
+
+	assert.Nil(t, snsSubject(" \n\t"), "blank titles send no subject")
+}
+
+func TestNewReportsInvalidConfig(t *testing.T) {
+	n, err := New(config.NotifyConfig{
+		Sinks: []config.NotifySinkConfig{
+			{Name: "chat", Type: "slack", URL: "https://hooks.slack.com/services/x"},
+			{Name: "pigeon", Type: "carrier-pigeon"},
+		},
+		Routes: []config.NotifyRouteConfig{
+			{Sink: "chat", MinSeverity: "warning"},
+			{Sink: "pigeon"},
+			{Sink: "chat", MinSeverity: "loud"},
+		},
+	}, aws.Config{}, logging.NewLogger("error", "text"))
+
+	require.NotNil(t, n)
+	assert.ErrorContains(t, err, "unknown sink type")
+	assert.ErrorContains(t, err, "unknown sink \"pigeon\"")
+	assert.ErrorContains(t, err, "unknown severity")
+	assert.Len(t, n.sinks, 1)
+	assert.Len(t, n.routes, 2)
+}
+
+func TestHeartbeatSendsSignedWebhook(t *testing.T) {
+	var mu sync.Mutex
+	var body []byte
+	var header http.Header
+	received := make(chan struct{}, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		header = r.Header.Clone()
+		body, _ = io.ReadAll(r.Body)
+		mu.Unlock()
+		select {
+		case received <- struct{}{}:
+		default:
+		}
+	}))
+	defer server.Close()
+
+	heartbeat, err := NewHeartbeat(config.HeartbeatConfig{
+		Interval: time.Hour,
+		Targets: []config.HeartbeatTargetConfig{
+			{Name: "cron-monitor", Type: "webhook", URL: server.URL, Secret: "s3cret"},
+		},
+	}, aws.Config{}, "aws-mcp-server", logging.NewLogger("error", "text"))
+	require.NoError(t, err)
+	require.NotNil(t, heartbeat)
+	assert.Equal(t, time.Hour, heartbeat.interval)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go heartbeat.Run(ctx)
+
+	// The first beat is sent without waiting for the interval
+	select {
+	case <-received:
+	case <-time.After(5 * time.Second):
+		t.Fatal("no heartbeat received")
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, EventHeartbeat, header.Get(EventHeader))
+	assert.NoError(t, VerifySignature("s3cret", header.Get(TimestampHeader), body, header.Get(SignatureHeader), 5*time.Minute, time.Now()))
+
+	var pulse Pulse
+	require.NoError(t, json.Unmarshal(body, &pulse))
+	assert.Equal(t, EventHeartbeat, pulse.Type)
+	assert.Equal(t, "aws-mcp-server", pulse.Server)
+	assert.False(t, pulse.StartedAt.IsZero())
+}
+
+func TestNewHeartbeatReportsInvalidConfig(t *testing.T) {
+	heartbeat, err := NewHeartbeat(config.HeartbeatConfig{}, aws.Config{}, "aws-mcp-server", logging.NewLogger("error", "text"))
+	assert.NoError(t, err)
+	assert.Nil(t, heartbeat, "no targets disables heartbeats")
+
+	heartbeat, err = NewHeartbeat(config.HeartbeatConfig{
+		Targets: []config.HeartbeatTargetConfig{
+			{Name: "alarm", Type: "cloudwatch", Dimensions: map[string]string{"Environment": "prod"}},
+			{Name: "pager", Type: "sns"},
+			{Name: "pigeon", Type: "carrier-pigeon"},
+		},
+	}, aws.Config{}, "aws-mcp-server", logging.NewLogger("error", "text"))
+	require.NotNil(t, heartbeat)
+	assert.ErrorContains(t, err, "topic_arn is required")
+	assert.ErrorContains(t, err, "unknown heartbeat target type")
+	require.Len(t, heartbeat.targets, 1)
+	assert.Equal(t, defaultHeartbeatInterval, heartbeat.interval)
+
+	metric := heartbeat.targets[0].(*MetricHeartbeat)
+	assert.Equal(t, defaultHeartbeatNamespace, metric.namespace)
+	assert.Equal(t, defaultHeartbeatMetricName, metric.metricName)
+	require.Len(t, metric.dimensions, 1)
+	assert.Equal(t, "prod", aws.ToString(metric.dimensions[0].Value))
+}
+
+// sentEmail is an email captured by a test sender
+type sentEmail struct {
+	subject, html, text string
+}
+
+func captureEmails(emails *[]sentEmail) emailSender {
+	return func(ctx context.Context, subject, htmlBody, textBody string) error {
+		*emails = append(*emails, sentEmail{subject, htmlBody, textBody})
+		return nil
+	}
+}
+
+func TestEmailSinkSendsImmediatelyWithoutDigest(t *testing.T) {
+	var emails []sentEmail
+	sink := newEmailSink("ops-email", 0, captureEmails(&emails))
+
+	err := sink.Send(context.Background(), Event{
+		Type:     "tool.executed",
+		Severity: SeverityInfo,
+		Title:    "Stopped <web-1>",
+		Fields:   map[string]interface{}{"instanceId": "i-123"},
+	})
+	require.NoError(t, err)
+
+	require.Len(t, emails, 1)
+	assert.Equal(t, "[info] Stopped <web-1>", emails[0].subject)
+	assert.Contains(t, emails[0].html, "Stopped &lt;web-1&gt;")
+	assert.Contains(t, emails[0].html, "i-123")
+	assert.Contains(t, emails[0].text, "instanceId: i-123")
+}
+
+func TestEmailSinkDigest(t *testing.T) {
+	var emails []sentEmail
+	sink := newEmailSink("ops-email", time.Hour, captureEmails(&emails))
+	ctx := context.Background()
+
+	require.NoError(t, sink.Send(ctx, Event{Type: "tool.executed", Severity: SeverityInfo, Title: "stopped web-1"}))
+	require.NoError(t, sink.Send(ctx, Event{Type: "approval.requested", Severity: SeverityWarning, Title: "needs approval"}))
+	require.NoError(t, sink.Send(ctx, Event{Type: "tool.executed", Severity: SeverityInfo, Title: "started web-2"}))
+	assert.Empty(t, emails, "non-critical events should wait for the digest")
+
+	// Critical events bypass the digest
+	require.NoError(t, sink.Send(ctx, Event{Type: "anomaly.detected", Severity: SeverityCritical, Title: "cost spike"}))
+	require.Len(t, emails, 1)
+	assert.Equal(t, "[critical] cost spike", emails[0].subject)
+
+	require.NoError(t, sink.Flush(ctx))
+	require.Len(t, emails, 2)
+	digest := emails[1]
+	assert.Contains(t, digest.subject, "AIOps digest: 3 events since")
+	assert.Contains(t, digest.html, "tool.executed (2)")
+	assert.Contains(t, digest.html, "approval.requested (1)")
+	assert.Contains(t, digest.text, "started web-2")
+
+	// Nothing pending, nothing sent
+	require.NoError(t, sink.Flush(ctx))
+	assert.Len(t, emails, 2)
+}
+
+func TestEmailSinkKeepsEventsWhenDigestFails(t *testing.T) {
+	fail := true
+	var emails []sentEmail
+	sink := newEmailSink("ops-email", time.Hour, func(ctx context.Context, subject, htmlBody, textBody string) error {
+		if fail {
+			return assert.AnError
+		}
+		emails = append(emails, sentEmail{subject, htmlBody, textBody})
+		return nil
+	})
+	ctx := context.Background()
+
+	require.NoError(t, sink.Send(ctx, Event{Type: "tool.executed", Title: "stopped web-1"}))
+	require.Error(t, sink.Flush(ctx))
+
+	fail = false
+	require.NoError(t, sink.Send(ctx, Event{Type: "tool.executed", Title: "started web-2"}))
+	require.NoError(t, sink.Flush(ctx))
+	require.Len(t, emails, 1)
+	assert.Contains(t, emails[0].subject, "2 events")
+}
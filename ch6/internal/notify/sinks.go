@@ -0,0 +1,247 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sns"
+)
+
+// snsSubjectLimit is the maximum length of an SNS message subject
+const snsSubjectLimit = 100
+
+// snsSubjectUnknown replaces the characters SNS does not accept in subjects
+const snsSubjectUnknown = '?'
+
+// Headers set on signed webhook deliveries
+const (
+	SignatureHeader = "X-AIOps-Signature"
+	TimestampHeader = "X-AIOps-Timestamp"
+	EventHeader     = "X-AIOps-Event"
+)
+
+// SlackSink posts events to a Slack incoming webhook
+type SlackSink struct {
+	name       string
+	webhookURL string
+	client     *http.Client
+}
+
+// NewSlackSink creates a sink for a Slack incoming webhook URL
+func NewSlackSink(name, webhookURL string) *SlackSink {
+	return &SlackSink{
+		name:       name,
+		webhookURL: webhookURL,
+		client:     &http.Client{Timeout: sendTimeout},
+	}
+}
+
+// Name returns the configured sink name
+func (s *SlackSink) Name() string {
+	return s.name
+}
+
+// Send posts the event as a Slack message, mentioning on-call responders
+// whose Slack user is known
+func (s *SlackSink) Send(ctx context.Context, event Event) error {
+	return postJSON(ctx, s.client, s.webhookURL, nil, map[string]string{
+		"text": event.text(slackMention),
+	})
+}
+
+// slackMention renders a responder as a Slack mention when possible
+func slackMention(r Responder) string {
+	if r.SlackID != "" {
+		return "<@" + r.SlackID + ">"
+	}
+	return r.String()
+}
+
+// WebhookSink posts events as JSON to an arbitrary HTTP endpoint. With a secret,
+// each request carries an HMAC-SHA256 signature so receivers can verify that it
+// came from this server and was not replayed.
+type WebhookSink struct {
+	name    string
+	url     string
+	headers map[string]string
+	secret  string
+	client  *http.Client
+}
+
+// NewWebhookSink creates a sink that POSTs events to url with the extra headers.
+// An empty secret sends unsigned requests.
+func NewWebhookSink(name, url string, headers map[string]string, secret string) *WebhookSink {
+	return &WebhookSink{
+		name:    name,
+		url:     url,
+		headers: headers,
+		secret:  secret,
+		client:  &http.Client{Timeout: sendTimeout},
+	}
+}
+
+// Name returns the configured sink name
+func (s *WebhookSink) Name() string {
+	return s.name
+}
+
+// Send posts the event as JSON, signed when a secret is configured
+func (s *WebhookSink) Send(ctx context.Context, event Event) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+
+	headers := make(map[string]string, len(s.headers)+3)
+	for key, value := range s.headers {
+		headers[key] = value
+	}
+	headers[EventHeader] = event.Type
+
+	if s.secret != "" {
+		timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+		headers[TimestampHeader] = timestamp
+		headers[SignatureHeader] = Sign(s.secret, timestamp, body)
+	}
+
+	return post(ctx, s.client, s.url, headers, body)
+}
+
+// Sign returns the signature header value for a webhook body: "sha256=" followed
+// by the hex HMAC-SHA256 of "<timestamp>.<body>" keyed with the shared secret.
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifySignature checks a received webhook signature and rejects timestamps
+// further than maxAge from now, for receivers written in Go
+func VerifySignature(secret, timestamp string, body []byte, signature string, maxAge time.Duration, now time.Time) error {
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid timestamp %q: %w", timestamp, err)
+	}
+
+	age := now.Sub(time.Unix(seconds, 0))
+	if age > maxAge || age < -maxAge {
+		return fmt.Errorf("timestamp is %s old, more than the allowed %s", age.Round(time.Second), maxAge)
+	}
+
+	if !hmac.Equal([]byte(signature), []byte(Sign(secret, timestamp, body))) {
+		return fmt.Errorf("signature mismatch")
+	}
+	return nil
+}
+
+// SNSSink publishes events to an SNS topic
+type SNSSink struct {
+	name     string
+	client   *sns.Client
+	topicARN string
+}
+
+// NewSNSSink creates a sink that publishes to topicARN
+func NewSNSSink(name string, client *sns.Client, topicARN string) *SNSSink {
+	return &SNSSink{
+		name:     name,
+		client:   client,
+		topicARN: topicARN,
+	}
+}
+
+// Name returns the configured sink name
+func (s *SNSSink) Name() string {
+	return s.name
+}
+
+// Send publishes the event as JSON, with the title as subject for email subscribers
+func (s *SNSSink) Send(ctx context.Context, event Event) error {
+	message, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+
+	_, err = s.client.Publish(ctx, &sns.PublishInput{
+		TopicArn: aws.String(s.topicARN),
+		Subject:  snsSubject(event.Title),
+		Message:  aws.String(string(message)),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to publish to %s: %w", s.topicARN, err)
+	}
+	return nil
+}
+
+// snsSubject turns a title into an SNS subject, which must be printable
+// ASCII without line breaks and at most snsSubjectLimit characters. Control
+// characters become spaces and other characters question marks. A title
+// with nothing printable gives no subject.
+func snsSubject(title string) *string {
+	var subject strings.Builder
+	for _, r := range title {
+		switch {
+		case r >= ' ' && r <= '~':
+			subject.WriteRune(r)
+		case unicode.IsControl(r) || unicode.IsSpace(r):
+			subject.WriteByte(' ')
+		default:
+			subject.WriteRune(snsSubjectUnknown)
+		}
+		if subject.Len() >= snsSubjectLimit {
+			break
+		}
+	}
+	trimmed := strings.TrimSpace(subject.String())
+	if trimmed == "" {
+		return nil
+	}
+	return aws.String(trimmed)
+}
+
+// postJSON marshals payload and sends it as a JSON POST request
+func postJSON(ctx context.Context, client *http.Client, url string, headers map[string]string, payload interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal payload: %w", err)
+	}
+	return post(ctx, client, url, headers, body)
+}
+
+// post sends a JSON body and treats non-2xx responses as errors
+func post(ctx context.Context, client *http.Client, url string, headers map[string]string, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to post notification: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		snippet, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("notification endpoint returned %s: %s", resp.Status, bytes.TrimSpace(snippet))
+	}
+	return nil
+}
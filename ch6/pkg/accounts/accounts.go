@@ -0,0 +1,320 @@
+// Package accounts resolves the accounts of an AWS organization by alias to
+// clients that act in them through an assumed role, so resources and tools
+// can reach other accounts than the server's own.
+package accounts
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"aws-mcp-server/internal/config"
+	"aws-mcp-server/pkg/aws"
+	"aws-mcp-server/pkg/types"
+)
+
+// listTTL is how long the organization's account list is reused
+const listTTL = 15 * time.Minute
+
+// accountID matches a 12-digit AWS account ID
+var accountID = regexp.MustCompile(`^\d{12}$`)
+
+// ErrNotConfigured is returned for accounts when neither the organization nor
+// any accounts are configured
+var ErrNotConfigured = errors.New("no organization accounts are configured, see organization in the configuration")
+
+// Account is an account that can be addressed by its alias
+type Account struct {
+	Alias   string `json:"alias"`
+	ID      string `json:"id"`
+	Name    string `json:"name,omitempty"`
+	State   string `json:"state,omitempty"`
+	RoleARN string `json:"roleArn"`
+	Region  string `json:"region,omitempty"`
+}
+
+// Lister lists the accounts of the organization
+type Lister func(ctx context.Context) ([]types.Account, error)
+
+// Assumer returns a client acting through a role in another account
+type Assumer func(roleARN, externalID, region string) *aws.Client
+
+// Identifier returns the ID of the account the server's credentials belong to
+type Identifier func(ctx context.Context) (string, error)
+
+// Directory resolves account aliases and keeps one client per account. A nil
+// Directory knows no accounts.
+type Directory struct {
+	roleName   string
+	externalID string
+	configured []Account
+	list       Lister
+	assume     Assumer
+	// base acts in the server's own account, which identify names; the
+	// organization's role usually does not exist there, e.g. in the
+	// management account
+	base     *aws.Client
+	identify Identifier
+
+	mu       sync.Mutex
+	self     string
+	listed   []Account
+	listedAt time.Time
+	clients  map[string]*aws.Client
+	now      func() time.Time
+}
+
+// New creates a directory of the configured accounts and, when the
+// organization is enabled, its member accounts. Without either it returns
+// nil. Invalid accounts are skipped and reported in the returned error.
+func New(cfg config.OrganizationConfig, base *aws.Client) (*Directory, error) {
+	if !cfg.Enabled && len(cfg.Accounts) == 0 {
+		return nil, nil
+	}
+
+	var list Lister
+	if cfg.Enabled {
+		list = base.ListAccounts
+	}
+	d, err := newDirectory(cfg, list, base.AssumeRole)
+	d.base = base
+	d.identify = base.CallerAccount
+	return d, err
+}
+
+// newDirectory creates a directory that lists and assumes with the given
+// functions; a nil list only knows the configured accounts
+func newDirectory(cfg config.OrganizationConfig, list Lister, assume Assumer) (*Directory, error) {
+	d := &Directory{
+		roleName:   cfg.RoleName,
+		externalID: cfg.ExternalID,
+		list:       list,
+		assume:     assume,
+		clients:    make(map[string]*aws.Client),
+		now:        time.Now,
+	}
+
+	var errs []error
+	seen := make(map[string]bool)
+	for _, accountCfg := range cfg.Accounts {
+		account, err := d.configuredAccount(accountCfg)
+		if err == nil && seen[account.Alias] {
+			err = errors.New("alias is used more than once")
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("organization account %q: %w", accountCfg.Alias, err))
+			continue
+		}
+		seen[account.Alias] = true
+		d.configured = append(d.configured, account)
+	}
+	return d, errors.Join(errs...)
+}
+
+// configuredAccount validates a configured account. The ID may be left out
+// when the role ARN names the account.
+func (d *Directory) configuredAccount(cfg config.OrganizationAccountConfig) (Account, error) {
+	account := Account{
+		Alias:   strings.ToLower(cfg.Alias),
+		ID:      cfg.ID,
+		RoleARN: cfg.RoleARN,
+		Region:  cfg.Region,
+	}
+	if account.Alias == "" {
+		return Account{}, errors.New("alias is required")
+	}
+	if account.ID == "" {
+		account.ID = roleAccount(cfg.RoleARN)
+	}
+	if !accountID.MatchString(account.ID) {
+		return Account{}, errors.New("id must be a 12-digit account ID, or role_arn must name the account")
+	}
+	if account.RoleARN == "" {
+		if d.roleName == "" {
+			return Account{}, errors.New("role_arn is required when organization.role_name is not set")
+		}
+		account.RoleARN = d.roleARN(account.ID)
+	}
+	return account, nil
+}
+
+// Accounts returns the configured accounts followed by the active accounts
+// of the organization that are not configured, sorted by alias
+func (d *Directory) Accounts(ctx context.Context) ([]Account, error) {
+	if d == nil {
+		return nil, nil
+	}
+
+	accounts := append([]Account{}, d.configured...)
+	listed, err := d.organizationAccounts(ctx)
+	if err != nil {
+		return accounts, err
+	}
+	return append(accounts, listed...), nil
+}
+
+// Resolve returns the account with the alias or ID. Configured accounts are
+// resolved without listing the organization.
+func (d *Directory) Resolve(ctx context.Context, ref string) (Account, error) {
+	if d == nil {
+		return Account{}, ErrNotConfigured
+	}
+
+	ref = strings.ToLower(ref)
+	for _, account := range d.configured {
+		if account.Alias == ref || account.ID == ref {
+			return account, nil
+		}
+	}
+
+	listed, err := d.organizationAccounts(ctx)
+	if err != nil {
+		return Account{}, err
+	}
+	for _, account := range listed {
+		if account.Alias == ref || account.ID == ref {
+			return account, nil
+		}
+	}
+	return Account{}, fmt.Errorf("unknown account %q", ref)
+}
+
+// Client returns a client acting in the account with the alias or ID. The
+// client is reused for later calls in the same account.
+func (d *Directory) Client(ctx context.Context, ref string) (*aws.Client, Account, error) {
+	account, err := d.Resolve(ctx, ref)
+	if err != nil {
+		return nil, Account{}, err
+	}
+	return d.ClientFor(ctx, account), account, nil
+}
+
+// ClientFor returns a client acting in an account listed by Accounts. The
+// server's own account is served by the server's client without assuming a
+// role, unless the account is configured with another region.
+func (d *Directory) ClientFor(ctx context.Context, account Account) *aws.Client {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.self == "" && d.identify != nil {
+		// Failing to identify only means assuming the role; it is retried on
+		// the next call
+		d.self, _ = d.identify(ctx)
+	}
+	if account.ID == d.self && account.Region == "" {
+		return d.base
+	}
+
+	client, ok := d.clients[account.ID]
+	if !ok {
+		client = d.assume(account.RoleARN, d.externalID, account.Region)
+		d.clients[account.ID] = client
+	}
+	return client
+}
+
+// organizationAccounts returns the organization's active accounts that are
+// not configured, listing them again once the last list is older than listTTL
+func (d *Directory) organizationAccounts(ctx context.Context) ([]Account, error) {
+	if d.list == nil {
+		return nil, nil
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.listed != nil && d.now().Sub(d.listedAt) < listTTL {
+		return d.listed, nil
+	}
+
+	members, err := d.list(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	configured := make(map[string]bool, len(d.configured))
+	for _, account := range d.configured {
+		configured[account.ID] = true
+		configured[account.Alias] = true
+	}
+
+	listed := []Account{}
+	for _, member := range members {
+		alias := Alias(member.Name)
+		if member.State != "ACTIVE" || configured[member.ID] || configured[alias] {
+			continue
+		}
+		listed = append(listed, Account{
+			Alias:   alias,
+			ID:      member.ID,
+			Name:    member.Name,
+			State:   member.State,
+			RoleARN: d.roleARN(member.ID),
+		})
+	}
+	sort.Slice(listed, func(i, j int) bool { return listed[i].Alias < listed[j].Alias })
+
+	d.listed = listed
+	d.listedAt = d.now()
+	return listed, nil
+}
+
+// AliasOf returns the alias of an organization account: the configured
+// alias for its ID, or else the alias of its name. An account whose name
+// alias is taken by another configured account is addressed by its ID.
+func (d *Directory) AliasOf(id, name string) string {
+	alias := Alias(name)
+	if d == nil {
+		return alias
+	}
+	for _, account := range d.configured {
+		if account.ID == id {
+			return account.Alias
+		}
+	}
+	for _, account := range d.configured {
+		if account.Alias == alias {
+			return id
+		}
+	}
+	return alias
+}
+
+// roleARN is the ARN of the configured role in an account
+func (d *Directory) roleARN(id string) string {
+	return fmt.Sprintf("arn:aws:iam::%s:role/%s", id, d.roleName)
+}
+
+// Alias turns an account name into its alias: lowercased, with dashes for
+// runs of other characters than letters and digits, e.g. "Prod (EU)" becomes
+// "prod-eu"
+func Alias(name string) string {
+	var alias strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(name) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			if dash && alias.Len() > 0 {
+				alias.WriteByte('-')
+			}
+			alias.WriteRune(r)
+			dash = false
+			continue
+		}
+		dash = true
+	}
+	return alias.String()
+}
+
+// roleAccount returns the account ID in an IAM role ARN
+func roleAccount(roleARN string) string {
+	parts := strings.Split(roleARN, ":")
+	if len(parts) < 6 || parts[0] != "arn" {
+		return ""
+	}
+	return parts[4]
+}
@@ -0,0 +1,154 @@
+package accounts
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"aws-mcp-server/internal/config"
+	"aws-mcp-server/pkg/aws"
+	"aws-mcp-server/pkg/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAlias(t *testing.T) {
+	assert.Equal(t, "prod-eu", Alias("Prod (EU)"))
+	assert.Equal(t, "shared-services", Alias("  Shared_Services "))
+	assert.Equal(t, "log-archive2", Alias("log-archive2"))
+}
+
+func TestNewValidatesConfiguredAccounts(t *testing.T) {
+	d, err := New(config.OrganizationConfig{}, nil)
+	require.NoError(t, err)
+	assert.Nil(t, d, "without accounts there is no directory")
+
+	d, err = newDirectory(config.OrganizationConfig{
+		RoleName: "OrganizationAccountAccessRole",
+		Accounts: []config.OrganizationAccountConfig{
+			{Alias: "Prod", ID: "111111111111"},
+			{Alias: "staging", RoleARN: "arn:aws:iam::222222222222:role/ReadOnly"},
+			{Alias: "", ID: "333333333333"},
+			{Alias: "dev", ID: "12345"},
+			{Alias: "prod", ID: "444444444444"},
+		},
+	}, nil, nil)
+	require.Error(t, err)
+	assert.ErrorContains(t, err, "alias is required")
+	assert.ErrorContains(t, err, `"dev": id must be a 12-digit account ID`)
+	assert.ErrorContains(t, err, `"prod": alias is used more than once`)
+
+	accounts, err := d.Accounts(context.Background())
+	require.NoError(t, err)
+	require.Len(t, accounts, 2, "invalid accounts are skipped")
+	assert.Equal(t, Account{Alias: "prod", ID: "111111111111", RoleARN: "arn:aws:iam::111111111111:role/OrganizationAccountAccessRole"}, accounts[0])
+	assert.Equal(t, "222222222222", accounts[1].ID, "the ID is taken from the role ARN")
+}
+
+func TestResolveListsOrganizationOnce(t *testing.T) {
+	lists := 0
+	list := func(ctx context.Context) ([]types.Account, error) {
+		lists++
+		return []types.Account{
+			{ID: "111111111111", Name: "Prod", State: "ACTIVE"},
+			{ID: "555555555555", Name: "Data Lake", State: "ACTIVE"},
+			{ID: "666666666666", Name: "Closed", State: "SUSPENDED"},
+		}, nil
+	}
+	var assumed []string
+	assume := func(roleARN, externalID, region string) *aws.Client {
+		assumed = append(assumed, roleARN+" "+externalID+" "+region)
+		return &aws.Client{}
+	}
+
+	d, err := newDirectory(config.OrganizationConfig{
+		Enabled:    true,
+		RoleName:   "Auditor",
+		ExternalID: "ext",
+		Accounts:   []config.OrganizationAccountConfig{{Alias: "production", ID: "111111111111", Region: "eu-west-1"}},
+	}, list, assume)
+	require.NoError(t, err)
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	d.now = func() time.Time { return now }
+
+	account, err := d.Resolve(context.Background(), "Production")
+	require.NoError(t, err)
+	assert.Equal(t, "111111111111", account.ID)
+	assert.Equal(t, 0, lists, "configured accounts resolve without listing the organization")
+
+	client, account, err := d.Client(context.Background(), "data-lake")
+	require.NoError(t, err)
+	assert.Equal(t, "arn:aws:iam::555555555555:role/Auditor", account.RoleARN)
+	again, _, err := d.Client(context.Background(), "555555555555")
+	require.NoError(t, err)
+	assert.Same(t, client, again, "clients are reused per account")
+	assert.Equal(t, []string{"arn:aws:iam::555555555555:role/Auditor ext "}, assumed)
+
+	_, err = d.Resolve(context.Background(), "closed")
+	assert.ErrorContains(t, err, `unknown account "closed"`, "suspended accounts are not addressable")
+
+	accounts, err := d.Accounts(context.Background())
+	require.NoError(t, err)
+	var aliases []string
+	for _, account := range accounts {
+		aliases = append(aliases, account.Alias)
+	}
+	assert.Equal(t, []string{"production", "data-lake"}, aliases, "a configured account is not listed twice")
+	assert.Equal(t, 1, lists)
+
+	now = now.Add(listTTL)
+	_, err = d.Accounts(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 2, lists, "the list is refreshed after its TTL")
+}
+
+func TestClientForOwnAccount(t *testing.T) {
+	assumed := 0
+	d, err := newDirectory(config.OrganizationConfig{
+		RoleName: "OrganizationAccountAccessRole",
+		Accounts: []config.OrganizationAccountConfig{{Alias: "management", ID: "111111111111"}, {Alias: "prod", ID: "222222222222"}},
+	}, nil, func(roleARN, externalID, region string) *aws.Client {
+		assumed++
+		return &aws.Client{}
+	})
+	require.NoError(t, err)
+	d.base = &aws.Client{}
+	d.identify = func(ctx context.Context) (string, error) { return "111111111111", nil }
+
+	client, _, err := d.Client(context.Background(), "management")
+	require.NoError(t, err)
+	assert.Same(t, d.base, client, "the server's own account needs no assumed role")
+
+	client, _, err = d.Client(context.Background(), "prod")
+	require.NoError(t, err)
+	assert.NotSame(t, d.base, client)
+	assert.Equal(t, 1, assumed)
+}
+
+func TestAliasOf(t *testing.T) {
+	var none *Directory
+	assert.Equal(t, "data-lake", none.AliasOf("555555555555", "Data Lake"))
+
+	d, err := newDirectory(config.OrganizationConfig{
+		RoleName: "Auditor",
+		Accounts: []config.OrganizationAccountConfig{{Alias: "prod", ID: "111111111111"}},
+	}, nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "prod", d.AliasOf("111111111111", "Production"), "the configured alias wins")
+	assert.Equal(t, "222222222222", d.AliasOf("222222222222", "Prod"), "a taken alias falls back to the ID")
+}
+
+func TestResolveErrors(t *testing.T) {
+	var d *Directory
+	_, err := d.Resolve(context.Background(), "prod")
+	assert.ErrorContains(t, err, "no organization accounts are configured")
+
+	d, err = newDirectory(config.OrganizationConfig{Enabled: true, RoleName: "Auditor"}, func(ctx context.Context) ([]types.Account, error) {
+		return nil, errors.New("access denied")
+	}, nil)
+	require.NoError(t, err)
+	_, err = d.Resolve(context.Background(), "prod")
+	assert.ErrorContains(t, err, "access denied")
+}
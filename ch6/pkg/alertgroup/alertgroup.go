@@ -0,0 +1,256 @@
+package alertgroup
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"aws-mcp-server/internal/config"
+	"aws-mcp-server/pkg/types"
+)
+
+const (
+	// RuleComposite names groups of alarms referenced by a composite alarm
+	RuleComposite = "composite"
+	// autoScalingDimension is the dimension of Auto Scaling group metrics;
+	// instance alarms are matched to their group through its tag
+	autoScalingDimension = "AutoScalingGroupName"
+	// AutoScalingTag is the tag EC2 sets on instances launched by an Auto Scaling group
+	AutoScalingTag = "aws:autoscaling:groupName"
+)
+
+// DefaultRules group alarms by Auto Scaling group and then by instance when no
+// rules are configured
+var DefaultRules = []config.AlertGroupRuleConfig{
+	{Name: "autoscaling-group", Dimension: autoScalingDimension},
+	{Name: "instance", Dimension: "InstanceId"},
+}
+
+// compositeChild matches the alarms a composite alarm rule refers to, e.g.
+// ALARM("cpu-high") or OK(disk-full)
+var compositeChild = regexp.MustCompile(`(?:ALARM|OK|INSUFFICIENT_DATA)\s*\(\s*("[^"]+"|[^)\s]+)\s*\)`)
+
+// Group is alarms in the same state that most likely report one problem. The
+// lead alarm is listed for the whole group: the composite alarm for composite
+// groups, otherwise the alarm that changed state most recently.
+type Group struct {
+	Rule    string
+	Key     string
+	Lead    types.Alarm
+	Members []types.Alarm
+}
+
+// Names returns the names of the alarms grouped under the lead
+func (g *Group) Names() []string {
+	names := make([]string, 0, len(g.Members))
+	for _, alarm := range g.Members {
+		names = append(names, alarm.Name)
+	}
+	return names
+}
+
+// Result is the outcome of grouping a list of alarms
+type Result struct {
+	// Alarms are the ungrouped alarms and the lead of each group, in input order
+	Alarms []types.Alarm
+	// Groups maps lead alarm names to their groups
+	Groups map[string]*Group
+	// Duplicates maps each collapsed alarm to the alarm kept in its place
+	Duplicates map[string]string
+}
+
+// Grouper deduplicates and groups related alarms so that one problem is
+// listed once
+type Grouper struct {
+	rules []config.AlertGroupRuleConfig
+}
+
+// NewGrouper validates the grouping rules; without rules DefaultRules are used
+func NewGrouper(rules []config.AlertGroupRuleConfig) (*Grouper, error) {
+	if len(rules) == 0 {
+		rules = DefaultRules
+	}
+	rules = append([]config.AlertGroupRuleConfig(nil), rules...)
+
+	for i, rule := range rules {
+		if rule.Dimension == "" {
+			return nil, fmt.Errorf("alert grouping rule %d: dimension is required, e.g. InstanceId", i+1)
+		}
+		if rule.Name == "" {
+			rules[i].Name = rule.Dimension
+		}
+		for _, pattern := range rule.Alarms {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return nil, fmt.Errorf("alert grouping rule %s: invalid alarm pattern %q: %w", rules[i].Name, pattern, err)
+			}
+		}
+	}
+
+	return &Grouper{rules: rules}, nil
+}
+
+// UsesAutoScalingGroups reports whether a rule groups by Auto Scaling group,
+// which needs the groups of instances passed to Group
+func (g *Grouper) UsesAutoScalingGroups() bool {
+	for _, rule := range g.rules {
+		if rule.Dimension == autoScalingDimension {
+			return true
+		}
+	}
+	return false
+}
+
+// Group collapses duplicate alarms, groups alarms under the composite alarms
+// that refer to them and then applies the rules in order to the alarms left.
+// Only alarms in the same state are grouped. instanceGroups maps instance IDs
+// to their Auto Scaling group so instance alarms join their group's alarms.
+func (g *Grouper) Group(alarms []types.Alarm, instanceGroups map[string]string) Result {
+	result := Result{
+		Groups:     make(map[string]*Group),
+		Duplicates: make(map[string]string),
+	}
+	grouped := make(map[string]bool)
+
+	// Alarms watching the same metric with the same condition are duplicates
+	kept := make(map[string]string)
+	for _, alarm := range alarms {
+		signature := alarmSignature(alarm)
+		if signature == "" {
+			continue
+		}
+		if original, ok := kept[signature]; ok {
+			result.Duplicates[alarm.Name] = original
+			grouped[alarm.Name] = true
+			continue
+		}
+		kept[signature] = alarm.Name
+	}
+
+	byName := make(map[string]types.Alarm, len(alarms))
+	for _, alarm := range alarms {
+		byName[alarm.Name] = alarm
+	}
+
+	for _, alarm := range alarms {
+		if alarm.Rule == "" || grouped[alarm.Name] {
+			continue
+		}
+		group := &Group{Rule: RuleComposite, Key: alarm.Name, Lead: alarm}
+		for _, name := range compositeChildren(alarm.Rule) {
+			child, ok := byName[name]
+			if !ok || grouped[name] || result.Groups[name] != nil || child.State != alarm.State {
+				continue
+			}
+			group.Members = append(group.Members, child)
+			grouped[name] = true
+		}
+		if len(group.Members) > 0 {
+			result.Groups[alarm.Name] = group
+		}
+	}
+
+	for _, rule := range g.rules {
+		buckets := make(map[string][]types.Alarm)
+		var keys []string
+		for _, alarm := range alarms {
+			if grouped[alarm.Name] || result.Groups[alarm.Name] != nil || !ruleApplies(rule, alarm) {
+				continue
+			}
+			value := dimensionValue(rule.Dimension, alarm, instanceGroups)
+			if value == "" {
+				continue
+			}
+			key := alarm.State + "\x00" + value
+			if _, ok := buckets[key]; !ok {
+				keys = append(keys, key)
+			}
+			buckets[key] = append(buckets[key], alarm)
+		}
+
+		for _, key := range keys {
+			members := buckets[key]
+			if len(members) < 2 {
+				continue
+			}
+			_, value, _ := strings.Cut(key, "\x00")
+			group := &Group{Rule: rule.Name, Key: value, Lead: members[0], Members: members[1:]}
+			result.Groups[group.Lead.Name] = group
+			for _, member := range group.Members {
+				grouped[member.Name] = true
+			}
+		}
+	}
+
+	for _, alarm := range alarms {
+		if !grouped[alarm.Name] {
+			result.Alarms = append(result.Alarms, alarm)
+		}
+	}
+	return result
+}
+
+// ruleApplies reports whether an alarm name matches the rule's patterns
+func ruleApplies(rule config.AlertGroupRuleConfig, alarm types.Alarm) bool {
+	if len(rule.Alarms) == 0 {
+		return true
+	}
+	for _, pattern := range rule.Alarms {
+		if matched, _ := path.Match(pattern, alarm.Name); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// dimensionValue returns the alarm's value of a dimension. Alarms on an
+// instance of an Auto Scaling group count as alarms on the group.
+func dimensionValue(dimension string, alarm types.Alarm, instanceGroups map[string]string) string {
+	if value := alarm.Dimensions[dimension]; value != "" {
+		return value
+	}
+	if dimension == autoScalingDimension {
+		return instanceGroups[alarm.Dimensions["InstanceId"]]
+	}
+	return ""
+}
+
+// alarmSignature identifies what a metric alarm watches and when it fires;
+// composite alarms have no signature
+func alarmSignature(alarm types.Alarm) string {
+	if alarm.MetricName == "" || alarm.Threshold == nil {
+		return ""
+	}
+
+	dimensions := make([]string, 0, len(alarm.Dimensions))
+	for name, value := range alarm.Dimensions {
+		dimensions = append(dimensions, name+"="+value)
+	}
+	sort.Strings(dimensions)
+
+	return strings.Join([]string{
+		alarm.State,
+		alarm.Namespace,
+		alarm.MetricName,
+		strings.Join(dimensions, ","),
+		alarm.Statistic,
+		alarm.ComparisonOperator,
+		strconv.FormatFloat(*alarm.Threshold, 'g', -1, 64),
+	}, "|")
+}
+
+// compositeChildren returns the alarm names a composite alarm rule refers to.
+// Names may be ARNs; the alarm name is the part after "alarm:".
+func compositeChildren(rule string) []string {
+	var names []string
+	for _, match := range compositeChild.FindAllStringSubmatch(rule, -1) {
+		name := strings.Trim(match[1], `"`)
+		if i := strings.LastIndex(name, ":alarm:"); i >= 0 {
+			name = name[i+len(":alarm:"):]
+		}
+		names = append(names, name)
+	}
+	return names
+}
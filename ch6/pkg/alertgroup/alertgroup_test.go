@@ -0,0 +1,99 @@
+package alertgroup
+
+import (
+	"testing"
+	"time"
+
+	"aws-mcp-server/internal/config"
+	"aws-mcp-server/pkg/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func metricAlarm(name, state, metric string, dimensions map[string]string, threshold float64) types.Alarm {
+	return types.Alarm{
+		Name:               name,
+		Type:               "metric",
+		State:              state,
+		StateUpdated:       time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC),
+		Namespace:          "AWS/EC2",
+		MetricName:         metric,
+		Dimensions:         dimensions,
+		Statistic:          "Average",
+		ComparisonOperator: "GreaterThanThreshold",
+		Threshold:          &threshold,
+	}
+}
+
+func TestGroupDuplicatesAndDimensions(t *testing.T) {
+	g, err := NewGrouper(nil)
+	require.NoError(t, err)
+
+	alarms := []types.Alarm{
+		metricAlarm("web-1-cpu", "ALARM", "CPUUtilization", map[string]string{"InstanceId": "i-1"}, 80),
+		// Created twice, e.g. by two deployment tools
+		metricAlarm("web-1-cpu-copy", "ALARM", "CPUUtilization", map[string]string{"InstanceId": "i-1"}, 80),
+		metricAlarm("web-1-status", "ALARM", "StatusCheckFailed", map[string]string{"InstanceId": "i-1"}, 0),
+		metricAlarm("web-2-cpu", "ALARM", "CPUUtilization", map[string]string{"InstanceId": "i-2"}, 80),
+		metricAlarm("db-1-cpu", "ALARM", "CPUUtilization", map[string]string{"InstanceId": "i-3"}, 80),
+		metricAlarm("db-1-status", "OK", "StatusCheckFailed", map[string]string{"InstanceId": "i-3"}, 0),
+	}
+
+	result := g.Group(alarms, map[string]string{"i-1": "web", "i-2": "web"})
+	assert.Equal(t, map[string]string{"web-1-cpu-copy": "web-1-cpu"}, result.Duplicates)
+
+	var listed []string
+	for _, alarm := range result.Alarms {
+		listed = append(listed, alarm.Name)
+	}
+	assert.Equal(t, []string{"web-1-cpu", "db-1-cpu", "db-1-status"}, listed)
+
+	require.Contains(t, result.Groups, "web-1-cpu")
+	group := result.Groups["web-1-cpu"]
+	assert.Equal(t, "autoscaling-group", group.Rule)
+	assert.Equal(t, "web", group.Key)
+	assert.Equal(t, []string{"web-1-status", "web-2-cpu"}, group.Names())
+	assert.NotContains(t, result.Groups, "db-1-cpu", "alarms in different states are not grouped")
+}
+
+func TestGroupComposite(t *testing.T) {
+	g, err := NewGrouper([]config.AlertGroupRuleConfig{{Dimension: "InstanceId"}})
+	require.NoError(t, err)
+	assert.False(t, g.UsesAutoScalingGroups())
+
+	alarms := []types.Alarm{
+		{Name: "checkout-degraded", Type: "composite", State: "ALARM",
+			Rule: `ALARM("api-5xx") OR ALARM(arn:aws:cloudwatch:us-east-1:123456789012:alarm:api-latency) OR OK("api-healthy")`},
+		metricAlarm("api-5xx", "ALARM", "HTTPCode_Target_5XX_Count", map[string]string{"LoadBalancer": "app/api"}, 10),
+		metricAlarm("api-latency", "ALARM", "TargetResponseTime", map[string]string{"LoadBalancer": "app/api"}, 1),
+		metricAlarm("api-healthy", "OK", "HealthyHostCount", map[string]string{"LoadBalancer": "app/api"}, 2),
+	}
+
+	result := g.Group(alarms, nil)
+	require.Len(t, result.Alarms, 2)
+	assert.Equal(t, "checkout-degraded", result.Alarms[0].Name)
+	assert.Equal(t, "api-healthy", result.Alarms[1].Name)
+	group := result.Groups["checkout-degraded"]
+	assert.Equal(t, RuleComposite, group.Rule)
+	assert.Equal(t, []string{"api-5xx", "api-latency"}, group.Names())
+}
+
+func TestNewGrouperValidatesRules(t *testing.T) {
+	_, err := NewGrouper([]config.AlertGroupRuleConfig{{Name: "database"}})
+	assert.ErrorContains(t, err, "dimension is required")
+
+	_, err = NewGrouper([]config.AlertGroupRuleConfig{{Name: "database", Dimension: "DBInstanceIdentifier", Alarms: []string{"rds-["}}})
+	assert.ErrorContains(t, err, `invalid alarm pattern "rds-["`)
+
+	g, err := NewGrouper([]config.AlertGroupRuleConfig{{Dimension: "DBInstanceIdentifier", Alarms: []string{"rds-*"}}})
+	require.NoError(t, err)
+	alarms := []types.Alarm{
+		metricAlarm("rds-cpu", "ALARM", "CPUUtilization", map[string]string{"DBInstanceIdentifier": "orders"}, 80),
+		metricAlarm("rds-connections", "ALARM", "DatabaseConnections", map[string]string{"DBInstanceIdentifier": "orders"}, 500),
+		metricAlarm("orders-storage", "ALARM", "FreeStorageSpace", map[string]string{"DBInstanceIdentifier": "orders"}, 1e9),
+	}
+	result := g.Group(alarms, nil)
+	assert.Len(t, result.Alarms, 2)
+	assert.Equal(t, "DBInstanceIdentifier", result.Groups["rds-cpu"].Rule)
+}
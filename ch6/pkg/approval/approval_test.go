@@ -0,0 +1,84 @@
+package approval
+
+import (
+	"testing"
+	"time"
+
+	"aws-mcp-server/internal/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueueDecisions(t *testing.T) {
+	q := NewQueue()
+
+	request, err := q.Enqueue("stop-ec2-instance", map[string]interface{}{"instanceId": "i-abc"}, []string{"change freeze"}, "operator")
+	require.NoError(t, err)
+	assert.Equal(t, StatusPending, request.Status)
+	assert.Len(t, q.Pending(), 1)
+
+	approved, err := q.Approve(request.ID, "admin", "ok")
+	require.NoError(t, err)
+	assert.Equal(t, StatusApproved, approved.Status)
+	assert.Empty(t, q.Pending())
+
+	_, err = q.Reject(request.ID, "admin", "")
+	assert.ErrorContains(t, err, "already approved")
+
+	_, err = q.Approve("apr-missing", "admin", "")
+	assert.ErrorContains(t, err, "not found")
+}
+
+func TestQueueRetriesFailedRequests(t *testing.T) {
+	q := NewQueue()
+	request, err := q.Enqueue("stop-ec2-instance", map[string]interface{}{"instanceId": "i-abc"}, []string{"change freeze"}, "operator")
+	require.NoError(t, err)
+
+	_, err = q.Fail(request.ID, "throttled")
+	assert.ErrorContains(t, err, "not approved", "only approved requests can fail")
+
+	_, err = q.Approve(request.ID, "admin", "ok")
+	require.NoError(t, err)
+	failed, err := q.Fail(request.ID, "throttled")
+	require.NoError(t, err)
+	assert.Equal(t, StatusFailed, failed.Status)
+	assert.Equal(t, "throttled", failed.Error)
+	require.Len(t, q.Pending(), 1, "failed requests wait for a decision again")
+
+	retried, err := q.Approve(request.ID, "admin", "retry")
+	require.NoError(t, err)
+	assert.Equal(t, StatusApproved, retried.Status)
+	assert.Empty(t, retried.Error)
+	assert.Empty(t, q.Pending())
+}
+
+func TestFreezeWindows(t *testing.T) {
+	windows, err := ParseFreezeWindows([]config.FreezeWindowConfig{
+		{Name: "friday", Days: []string{"Fri"}, Start: "15:00", End: "23:59"},
+		{Name: "overnight", Days: []string{"monday"}, Start: "22:00", End: "06:00"},
+		{Name: "holidays", Start: "2025-12-24T00:00:00Z", End: "2025-12-27T00:00:00Z"},
+	})
+	require.NoError(t, err)
+
+	friday := time.Date(2025, 6, 6, 16, 0, 0, 0, time.UTC)
+	require.NotNil(t, ActiveFreeze(windows, friday))
+	assert.Equal(t, "friday", ActiveFreeze(windows, friday).Name)
+	assert.Nil(t, ActiveFreeze(windows, friday.Add(-2*time.Hour)))
+
+	tuesdayMorning := time.Date(2025, 6, 3, 5, 0, 0, 0, time.UTC)
+	require.NotNil(t, ActiveFreeze(windows, tuesdayMorning))
+	assert.Equal(t, "overnight", ActiveFreeze(windows, tuesdayMorning).Name)
+
+	christmas := time.Date(2025, 12, 25, 12, 0, 0, 0, time.UTC)
+	require.NotNil(t, ActiveFreeze(windows, christmas))
+	assert.Equal(t, "holidays", ActiveFreeze(windows, christmas).Name)
+}
+
+func TestParseFreezeWindowsErrors(t *testing.T) {
+	_, err := ParseFreezeWindows([]config.FreezeWindowConfig{{Days: []string{"someday"}, Start: "10:00", End: "11:00"}})
+	assert.ErrorContains(t, err, "unknown day")
+
+	_, err = ParseFreezeWindows([]config.FreezeWindowConfig{{Start: "2025-12-27T00:00:00Z", End: "2025-12-24T00:00:00Z"}})
+	assert.ErrorContains(t, err, "end must be after start")
+}
@@ -0,0 +1,120 @@
+package approval
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"aws-mcp-server/internal/config"
+)
+
+// weekdays maps lowercase day names and abbreviations to time.Weekday
+var weekdays = map[string]time.Weekday{
+	"sunday": time.Sunday, "sun": time.Sunday,
+	"monday": time.Monday, "mon": time.Monday,
+	"tuesday": time.Tuesday, "tue": time.Tuesday,
+	"wednesday": time.Wednesday, "wed": time.Wednesday,
+	"thursday": time.Thursday, "thu": time.Thursday,
+	"friday": time.Friday, "fri": time.Friday,
+	"saturday": time.Saturday, "sat": time.Saturday,
+}
+
+// FreezeWindow is a period during which mutating actions need approval. It is
+// either a one-off range of absolute times or a weekly recurring range of clock
+// times on the listed days.
+type FreezeWindow struct {
+	Name string
+
+	// One-off window
+	start, end time.Time
+
+	// Recurring window, in minutes after midnight
+	days                   map[time.Weekday]bool
+	startMinute, endMinute int
+}
+
+// ParseFreezeWindows validates the configured freeze windows. Recurring windows
+// use "HH:MM" start and end times; one-off windows use RFC3339 timestamps.
+func ParseFreezeWindows(configs []config.FreezeWindowConfig) ([]FreezeWindow, error) {
+	windows := make([]FreezeWindow, 0, len(configs))
+	for _, cfg := range configs {
+		window := FreezeWindow{Name: cfg.Name}
+		if window.Name == "" {
+			window.Name = fmt.Sprintf("%s - %s", cfg.Start, cfg.End)
+		}
+
+		if len(cfg.Days) == 0 {
+			start, err := time.Parse(time.RFC3339, cfg.Start)
+			if err != nil {
+				return nil, fmt.Errorf("freeze window %q: invalid start: %w", window.Name, err)
+			}
+			end, err := time.Parse(time.RFC3339, cfg.End)
+			if err != nil {
+				return nil, fmt.Errorf("freeze window %q: invalid end: %w", window.Name, err)
+			}
+			if !end.After(start) {
+				return nil, fmt.Errorf("freeze window %q: end must be after start", window.Name)
+			}
+			window.start, window.end = start, end
+			windows = append(windows, window)
+			continue
+		}
+
+		window.days = make(map[time.Weekday]bool, len(cfg.Days))
+		for _, day := range cfg.Days {
+			weekday, exists := weekdays[strings.ToLower(strings.TrimSpace(day))]
+			if !exists {
+				return nil, fmt.Errorf("freeze window %q: unknown day %q", window.Name, day)
+			}
+			window.days[weekday] = true
+		}
+
+		var err error
+		if window.startMinute, err = parseClock(cfg.Start); err != nil {
+			return nil, fmt.Errorf("freeze window %q: invalid start: %w", window.Name, err)
+		}
+		if window.endMinute, err = parseClock(cfg.End); err != nil {
+			return nil, fmt.Errorf("freeze window %q: invalid end: %w", window.Name, err)
+		}
+		windows = append(windows, window)
+	}
+
+	return windows, nil
+}
+
+// Active reports whether the window covers now. Recurring windows are evaluated
+// in now's location and may span midnight (e.g. 22:00 - 06:00).
+func (w FreezeWindow) Active(now time.Time) bool {
+	if w.days == nil {
+		return !now.Before(w.start) && now.Before(w.end)
+	}
+
+	minute := now.Hour()*60 + now.Minute()
+	if w.startMinute < w.endMinute {
+		return w.days[now.Weekday()] && minute >= w.startMinute && minute < w.endMinute
+	}
+
+	// Overnight window: the evening part belongs to the listed day, the early
+	// morning part to the day after it
+	previous := (now.Weekday() + 6) % 7
+	return (w.days[now.Weekday()] && minute >= w.startMinute) || (w.days[previous] && minute < w.endMinute)
+}
+
+// ActiveFreeze returns the first window covering now, or nil
+func ActiveFreeze(windows []FreezeWindow, now time.Time) *FreezeWindow {
+	for i := range windows {
+		if windows[i].Active(now) {
+			return &windows[i]
+		}
+	}
+	return nil
+}
+
+// parseClock converts "HH:MM" to minutes after midnight
+func parseClock(value string) (int, error) {
+	clock, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, err
+	}
+	return clock.Hour()*60 + clock.Minute(), nil
+}
@@ -0,0 +1,168 @@
+package approval
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Status is the lifecycle state of a queued request
+type Status string
+
+const (
+	StatusPending  Status = "pending"
+	StatusApproved Status = "approved"
+	StatusRejected Status = "rejected"
+	// StatusFailed is an approved request whose call failed. It waits for a
+	// decision again, so it can be retried or rejected.
+	StatusFailed Status = "failed"
+)
+
+// Request is a blocked tool call waiting for a human decision
+type Request struct {
+	ID          string                 `json:"id"`
+	Tool        string                 `json:"tool"`
+	Arguments   map[string]interface{} `json:"arguments"`
+	Reasons     []string               `json:"reasons"`
+	RequestedBy string                 `json:"requestedBy"`
+	RequestedAt time.Time              `json:"requestedAt"`
+	Status      Status                 `json:"status"`
+	DecidedBy   string                 `json:"decidedBy,omitempty"`
+	DecidedAt   *time.Time             `json:"decidedAt,omitempty"`
+	Note        string                 `json:"note,omitempty"`
+	Error       string                 `json:"error,omitempty"`
+}
+
+// Queue holds blocked actions until an admin approves or rejects them
+type Queue struct {
+	mu       sync.Mutex
+	requests map[string]*Request
+}
+
+// NewQueue creates an empty in-memory approval queue
+func NewQueue() *Queue {
+	return &Queue{
+		requests: make(map[string]*Request),
+	}
+}
+
+// Enqueue adds a blocked tool call and returns the pending request
+func (q *Queue) Enqueue(tool string, arguments map[string]interface{}, reasons []string, requestedBy string) (Request, error) {
+	id, err := newRequestID()
+	if err != nil {
+		return Request{}, err
+	}
+
+	request := &Request{
+		ID:          id,
+		Tool:        tool,
+		Arguments:   arguments,
+		Reasons:     reasons,
+		RequestedBy: requestedBy,
+		RequestedAt: time.Now(),
+		Status:      StatusPending,
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.requests[id] = request
+
+	return *request, nil
+}
+
+// Pending returns the requests still waiting for a decision, oldest first.
+// Failed requests are waiting again.
+func (q *Queue) Pending() []Request {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	pending := make([]Request, 0)
+	for _, request := range q.requests {
+		if request.Status == StatusPending || request.Status == StatusFailed {
+			pending = append(pending, *request)
+		}
+	}
+
+	sort.Slice(pending, func(i, j int) bool {
+		return pending[i].RequestedAt.Before(pending[j].RequestedAt)
+	})
+	return pending
+}
+
+// Get returns the request with the ID, whatever its status
+func (q *Queue) Get(id string) (Request, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	request, exists := q.requests[id]
+	if !exists {
+		return Request{}, false
+	}
+	return *request, true
+}
+
+// Approve marks a pending or failed request as approved and returns it for
+// execution
+func (q *Queue) Approve(id, decidedBy, note string) (Request, error) {
+	return q.decide(id, StatusApproved, decidedBy, note)
+}
+
+// Reject marks a pending request as rejected
+func (q *Queue) Reject(id, decidedBy, note string) (Request, error) {
+	return q.decide(id, StatusRejected, decidedBy, note)
+}
+
+// Fail marks an approved request whose call failed, so that it can be
+// approved again once the cause is fixed, or rejected
+func (q *Queue) Fail(id, reason string) (Request, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	request, exists := q.requests[id]
+	if !exists {
+		return Request{}, fmt.Errorf("approval request %s not found", id)
+	}
+	if request.Status != StatusApproved {
+		return Request{}, fmt.Errorf("approval request %s is %s, not approved", id, request.Status)
+	}
+
+	request.Status = StatusFailed
+	request.Error = reason
+	return *request, nil
+}
+
+// decide records a decision on a pending or failed request
+func (q *Queue) decide(id string, status Status, decidedBy, note string) (Request, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	request, exists := q.requests[id]
+	if !exists {
+		return Request{}, fmt.Errorf("approval request %s not found", id)
+	}
+
+	if request.Status != StatusPending && request.Status != StatusFailed {
+		return Request{}, fmt.Errorf("approval request %s is already %s", id, request.Status)
+	}
+
+	now := time.Now()
+	request.Status = status
+	request.Error = ""
+	request.DecidedBy = decidedBy
+	request.DecidedAt = &now
+	request.Note = note
+
+	return *request, nil
+}
+
+// newRequestID generates a short random request identifier
+func newRequestID() (string, error) {
+	buf := make([]byte, 4)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate request ID: %w", err)
+	}
+	return "apr-" + hex.EncodeToString(buf), nil
+}
@@ -0,0 +1,143 @@
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// defaultMaxEntries applies when audit.max_entries is not configured
+const defaultMaxEntries = 10000
+
+// Entry records one change made or attempted through the server
+type Entry struct {
+	Time         time.Time              `json:"time"`
+	Tool         string                 `json:"tool"`
+	Arguments    map[string]interface{} `json:"arguments,omitempty"`
+	Role         string                 `json:"role"`
+	Success      bool                   `json:"success"`
+	Message      string                 `json:"message,omitempty"`
+	ChangeTicket string                 `json:"changeTicket,omitempty"`
+	Elevation    string                 `json:"elevation,omitempty"`
+}
+
+// Log keeps recent audit entries in memory and, with a path, appends every
+// entry to a JSON Lines file that is reloaded on startup. A nil Log discards
+// entries, so callers need not check whether auditing is configured.
+type Log struct {
+	mu         sync.Mutex
+	path       string
+	maxEntries int
+	entries    []Entry
+}
+
+// Open creates a log. With an empty path entries are only kept in memory.
+func Open(path string, maxEntries int) (*Log, error) {
+	if maxEntries <= 0 {
+		maxEntries = defaultMaxEntries
+	}
+
+	l := &Log{path: path, maxEntries: maxEntries}
+	if path == "" {
+		return l, nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return l, fmt.Errorf("failed to create audit directory: %w", err)
+	}
+
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return l, nil
+	}
+	if err != nil {
+		return l, fmt.Errorf("failed to open audit log: %w", err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+	for scanner.Scan() {
+		var entry Entry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			// A torn last line from a crash should not lose the rest of the history
+			continue
+		}
+		l.append(entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return l, fmt.Errorf("failed to read audit log: %w", err)
+	}
+
+	return l, nil
+}
+
+// Record adds an entry and appends it to the log file
+func (l *Log) Record(entry Entry) error {
+	if l == nil {
+		return nil
+	}
+	if entry.Time.IsZero() {
+		entry.Time = time.Now()
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.append(entry)
+	if l.path == "" {
+		return nil
+	}
+
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to marshal audit entry: %w", err)
+	}
+
+	file, err := os.OpenFile(l.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open audit log: %w", err)
+	}
+	defer file.Close()
+
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to write audit entry: %w", err)
+	}
+	return nil
+}
+
+// Between returns entries recorded in [start, end], oldest first
+func (l *Log) Between(start, end time.Time) []Entry {
+	if l == nil {
+		return nil
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var entries []Entry
+	for _, entry := range l.entries {
+		if !entry.Time.Before(start) && !entry.Time.After(end) {
+			entries = append(entries, entry)
+		}
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Time.Before(entries[j].Time)
+	})
+	return entries
+}
+
+// append keeps at least the newest maxEntries entries in memory. Old entries
+// are dropped in batches so loading a long history stays linear.
+func (l *Log) append(entry Entry) {
+	l.entries = append(l.entries, entry)
+	if len(l.entries) > l.maxEntries+l.maxEntries/4 {
+		l.entries = append([]Entry(nil), l.entries[len(l.entries)-l.maxEntries:]...)
+	}
+}
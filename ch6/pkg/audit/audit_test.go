@@ -0,0 +1,60 @@
+package audit
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogPersistsAndReloads(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit", "audit.jsonl")
+	base := time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC)
+
+	log, err := Open(path, 0)
+	require.NoError(t, err)
+	require.NoError(t, log.Record(Entry{Time: base, Tool: "stop-ec2-instance", Role: "operator", Success: true}))
+	require.NoError(t, log.Record(Entry{Time: base.Add(time.Hour), Tool: "terminate-ec2-instance", Role: "operator", Message: "access denied"}))
+
+	// A torn line from a crash is skipped
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o600)
+	require.NoError(t, err)
+	_, err = file.WriteString(`{"time":"2025-06-01T1`)
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+
+	reloaded, err := Open(path, 0)
+	require.NoError(t, err)
+
+	entries := reloaded.Between(base, base.Add(2*time.Hour))
+	require.Len(t, entries, 2)
+	assert.Equal(t, "stop-ec2-instance", entries[0].Tool)
+	assert.False(t, entries[1].Success)
+	assert.Equal(t, "access denied", entries[1].Message)
+
+	assert.Len(t, reloaded.Between(base.Add(30*time.Minute), base.Add(2*time.Hour)), 1)
+}
+
+func TestLogKeepsNewestEntries(t *testing.T) {
+	log, err := Open("", 4)
+	require.NoError(t, err)
+
+	base := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 20; i++ {
+		require.NoError(t, log.Record(Entry{Time: base.Add(time.Duration(i) * time.Minute), Tool: "stop-ec2-instance"}))
+	}
+
+	entries := log.Between(base, base.Add(time.Hour))
+	assert.GreaterOrEqual(t, len(entries), 4)
+	assert.Equal(t, base.Add(19*time.Minute), entries[len(entries)-1].Time)
+	assert.True(t, entries[0].Time.After(base.Add(10*time.Minute)))
+}
+
+func TestNilLog(t *testing.T) {
+	var log *Log
+	assert.NoError(t, log.Record(Entry{Tool: "stop-ec2-instance"}))
+	assert.Nil(t, log.Between(time.Time{}, time.Now()))
+}
@@ -0,0 +1,296 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/apigateway"
+	apigwtypes "github.com/aws/aws-sdk-go-v2/service/apigateway/types"
+	"github.com/aws/aws-sdk-go-v2/service/apigatewayv2"
+	apigwv2types "github.com/aws/aws-sdk-go-v2/service/apigatewayv2/types"
+
+	"aws-mcp-server/pkg/types"
+
+	"github.com/sirupsen/logrus"
+)
+
+// restStageDefaults is the method settings key of the settings that apply to
+// every method of a REST API stage
+const restStageDefaults = "*/*"
+
+// ListAPIs retrieves the REST, HTTP and WebSocket APIs with their stages
+func (c *Client) ListAPIs(ctx context.Context) ([]types.API, error) {
+	start := time.Now()
+
+	apis, err := c.listRestAPIs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	v2APIs, err := c.listV2APIs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	apis = append(apis, v2APIs...)
+
+	c.logger.WithFields(logrus.Fields{
+		"count":    len(apis),
+		"duration": time.Since(start),
+	}).Info("Retrieved API Gateway APIs")
+
+	return apis, nil
+}
+
+// listRestAPIs retrieves the REST APIs with their stages
+func (c *Client) listRestAPIs(ctx context.Context) ([]types.API, error) {
+	var apis []types.API
+	paginator := apigateway.NewGetRestApisPaginator(c.apigateway, &apigateway.GetRestApisInput{Limit: aws.Int32(500)})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			c.logger.WithError(err).Error("Failed to list REST APIs")
+			return nil, fmt.Errorf("failed to list REST APIs: %w", err)
+		}
+		for _, item := range page.Items {
+			api := types.API{
+				ID:        aws.ToString(item.Id),
+				Name:      aws.ToString(item.Name),
+				Protocol:  "REST",
+				Endpoint:  fmt.Sprintf("https://%s.execute-api.%s.amazonaws.com", aws.ToString(item.Id), c.cfg.Region),
+				CreatedAt: aws.ToTime(item.CreatedDate),
+			}
+			if item.EndpointConfiguration != nil && len(item.EndpointConfiguration.Types) > 0 {
+				api.EndpointType = string(item.EndpointConfiguration.Types[0])
+			}
+
+			stages, err := c.apigateway.GetStages(ctx, &apigateway.GetStagesInput{RestApiId: item.Id})
+			if err != nil {
+				c.logger.WithError(err).WithField("api_id", api.ID).Error("Failed to get REST API stages")
+				return nil, fmt.Errorf("failed to get stages of API %s: %w", api.ID, err)
+			}
+			for _, stage := range stages.Item {
+				api.Stages = append(api.Stages, convertRestStage(stage))
+			}
+			apis = append(apis, api)
+		}
+	}
+	return apis, nil
+}
+
+// convertRestStage converts a REST API stage. Throttling values below zero
+// mean the setting is not configured.
+func convertRestStage(stage apigwtypes.Stage) types.APIStage {
+	converted := types.APIStage{
+		Name:           aws.ToString(stage.StageName),
+		DeploymentID:   aws.ToString(stage.DeploymentId),
+		TracingEnabled: stage.TracingEnabled,
+		CacheEnabled:   stage.CacheClusterEnabled,
+		LastUpdatedAt:  stage.LastUpdatedDate,
+	}
+	for key, setting := range stage.MethodSettings {
+		if setting.ThrottlingRateLimit < 0 && setting.ThrottlingBurstLimit < 0 {
+			continue
+		}
+		throttle := types.APIThrottle{RateLimit: setting.ThrottlingRateLimit, BurstLimit: setting.ThrottlingBurstLimit}
+		if key == restStageDefaults {
+			converted.Throttle = &throttle
+			continue
+		}
+		if converted.RouteThrottles == nil {
+			converted.RouteThrottles = make(map[string]types.APIThrottle)
+		}
+		converted.RouteThrottles[key] = throttle
+	}
+	return converted
+}
+
+// listV2APIs retrieves the HTTP and WebSocket APIs with their stages
+func (c *Client) listV2APIs(ctx context.Context) ([]types.API, error) {
+	var apis []types.API
+	input := &apigatewayv2.GetApisInput{MaxResults: aws.String("500")}
+	for {
+		page, err := c.apigatewayv2.GetApis(ctx, input)
+		if err != nil {
+			c.logger.WithError(err).Error("Failed to list HTTP and WebSocket APIs")
+			return nil, fmt.Errorf("failed to list HTTP and WebSocket APIs: %w", err)
+		}
+		for _, item := range page.Items {
+			api := types.API{
+				ID:        aws.ToString(item.ApiId),
+				Name:      aws.ToString(item.Name),
+				Protocol:  string(item.ProtocolType),
+				Endpoint:  aws.ToString(item.ApiEndpoint),
+				CreatedAt: aws.ToTime(item.CreatedDate),
+			}
+			stages, err := c.listV2Stages(ctx, api.ID)
+			if err != nil {
+				return nil, err
+			}
+			for _, stage := range stages {
+				api.Stages = append(api.Stages, convertV2Stage(stage))
+			}
+			apis = append(apis, api)
+		}
+		if page.NextToken == nil {
+			return apis, nil
+		}
+		input.NextToken = page.NextToken
+	}
+}
+
+// listV2Stages retrieves the stages of an HTTP or WebSocket API
+func (c *Client) listV2Stages(ctx context.Context, apiID string) ([]apigwv2types.Stage, error) {
+	var stages []apigwv2types.Stage
+	input := &apigatewayv2.GetStagesInput{ApiId: aws.String(apiID)}
+	for {
+		page, err := c.apigatewayv2.GetStages(ctx, input)
+		if err != nil {
+			c.logger.WithError(err).WithField("api_id", apiID).Error("Failed to get API stages")
+			return nil, fmt.Errorf("failed to get stages of API %s: %w", apiID, err)
+		}
+		stages = append(stages, page.Items...)
+		if page.NextToken == nil {
+			return stages, nil
+		}
+		input.NextToken = page.NextToken
+	}
+}
+
+// convertV2Stage converts a stage of an HTTP or WebSocket API
+func convertV2Stage(stage apigwv2types.Stage) types.APIStage {
+	converted := types.APIStage{
+		Name:             aws.ToString(stage.StageName),
+		DeploymentID:     aws.ToString(stage.DeploymentId),
+		AutoDeploy:       aws.ToBool(stage.AutoDeploy),
+		LastUpdatedAt:    stage.LastUpdatedDate,
+		DeploymentStatus: aws.ToString(stage.LastDeploymentStatusMessage),
+	}
+	converted.Throttle = convertRouteThrottle(stage.DefaultRouteSettings)
+	for route, settings := range stage.RouteSettings {
+		if throttle := convertRouteThrottle(&settings); throttle != nil {
+			if converted.RouteThrottles == nil {
+				converted.RouteThrottles = make(map[string]types.APIThrottle)
+			}
+			converted.RouteThrottles[route] = *throttle
+		}
+	}
+	return converted
+}
+
+// convertRouteThrottle returns the throttling of v2 route settings, nil when
+// neither limit is set
+func convertRouteThrottle(settings *apigwv2types.RouteSettings) *types.APIThrottle {
+	if settings == nil || (settings.ThrottlingRateLimit == nil && settings.ThrottlingBurstLimit == nil) {
+		return nil
+	}
+	return &types.APIThrottle{
+		RateLimit:  aws.ToFloat64(settings.ThrottlingRateLimit),
+		BurstLimit: aws.ToInt32(settings.ThrottlingBurstLimit),
+	}
+}
+
+// GetAPIAccountThrottle retrieves the account-level throttle that applies to
+// REST API stages without their own limit
+func (c *Client) GetAPIAccountThrottle(ctx context.Context) (*types.APIThrottle, error) {
+	result, err := c.apigateway.GetAccount(ctx, &apigateway.GetAccountInput{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get API Gateway account settings: %w", err)
+	}
+	if result.ThrottleSettings == nil {
+		return nil, nil
+	}
+	return &types.APIThrottle{
+		RateLimit:  result.ThrottleSettings.RateLimit,
+		BurstLimit: result.ThrottleSettings.BurstLimit,
+	}, nil
+}
+
+// DeployAPIStage creates a deployment of the API's current configuration and
+// points the stage at it, returning the deployment ID
+func (c *Client) DeployAPIStage(ctx context.Context, api types.API, stage, description string) (string, error) {
+	var deploymentID string
+	if api.Protocol == "REST" {
+		result, err := c.apigateway.CreateDeployment(ctx, &apigateway.CreateDeploymentInput{
+			RestApiId:   aws.String(api.ID),
+			StageName:   aws.String(stage),
+			Description: aws.String(description),
+		})
+		if err != nil {
+			c.logger.WithError(err).WithField("api_id", api.ID).Error("Failed to deploy REST API stage")
+			return "", fmt.Errorf("failed to deploy stage %s of %s: %w", stage, api.Name, err)
+		}
+		deploymentID = aws.ToString(result.Id)
+	} else {
+		result, err := c.apigatewayv2.CreateDeployment(ctx, &apigatewayv2.CreateDeploymentInput{
+			ApiId:       aws.String(api.ID),
+			StageName:   aws.String(stage),
+			Description: aws.String(description),
+		})
+		if err != nil {
+			c.logger.WithError(err).WithField("api_id", api.ID).Error("Failed to deploy API stage")
+			return "", fmt.Errorf("failed to deploy stage %s of %s: %w", stage, api.Name, err)
+		}
+		deploymentID = aws.ToString(result.DeploymentId)
+	}
+
+	c.logger.WithFields(logrus.Fields{
+		"api_id":        api.ID,
+		"stage":         stage,
+		"deployment_id": deploymentID,
+	}).Info("Deployed API stage")
+
+	return deploymentID, nil
+}
+
+// UpdateAPIStageThrottle sets the default throttle of a stage. Limits set on
+// single methods or routes are left unchanged.
+func (c *Client) UpdateAPIStageThrottle(ctx context.Context, api types.API, stage string, throttle types.APIThrottle) error {
+	if api.Protocol == "REST" {
+		_, err := c.apigateway.UpdateStage(ctx, &apigateway.UpdateStageInput{
+			RestApiId: aws.String(api.ID),
+			StageName: aws.String(stage),
+			PatchOperations: []apigwtypes.PatchOperation{
+				{Op: apigwtypes.OpReplace, Path: aws.String("/*/*/throttling/rateLimit"), Value: aws.String(strconv.FormatFloat(throttle.RateLimit, 'f', -1, 64))},
+				{Op: apigwtypes.OpReplace, Path: aws.String("/*/*/throttling/burstLimit"), Value: aws.String(strconv.Itoa(int(throttle.BurstLimit)))},
+			},
+		})
+		if err != nil {
+			c.logger.WithError(err).WithField("api_id", api.ID).Error("Failed to update REST API stage throttling")
+			return fmt.Errorf("failed to update throttling of stage %s of %s: %w", stage, api.Name, err)
+		}
+	} else {
+		// DefaultRouteSettings is replaced as a whole, so keep the logging and
+		// metrics settings of the stage
+		current, err := c.apigatewayv2.GetStage(ctx, &apigatewayv2.GetStageInput{ApiId: aws.String(api.ID), StageName: aws.String(stage)})
+		if err != nil {
+			return fmt.Errorf("failed to get stage %s of %s: %w", stage, api.Name, err)
+		}
+		settings := apigwv2types.RouteSettings{}
+		if current.DefaultRouteSettings != nil {
+			settings = *current.DefaultRouteSettings
+		}
+		settings.ThrottlingRateLimit = aws.Float64(throttle.RateLimit)
+		settings.ThrottlingBurstLimit = aws.Int32(throttle.BurstLimit)
+
+		_, err = c.apigatewayv2.UpdateStage(ctx, &apigatewayv2.UpdateStageInput{
+			ApiId:                aws.String(api.ID),
+			StageName:            aws.String(stage),
+			DefaultRouteSettings: &settings,
+		})
+		if err != nil {
+			c.logger.WithError(err).WithField("api_id", api.ID).Error("Failed to update API stage throttling")
+			return fmt.Errorf("failed to update throttling of stage %s of %s: %w", stage, api.Name, err)
+		}
+	}
+
+	c.logger.WithFields(logrus.Fields{
+		"api_id":      api.ID,
+		"stage":       stage,
+		"rate_limit":  throttle.RateLimit,
+		"burst_limit": throttle.BurstLimit,
+	}).Info("Updated API stage throttling")
+
+	return nil
+}
@@ -0,0 +1,228 @@
+package aws
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/athena"
+	athenatypes "github.com/aws/aws-sdk-go-v2/service/athena/types"
+
+	"aws-mcp-server/pkg/types"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// athenaPollInterval is how often a running Athena query is checked
+	athenaPollInterval = time.Second
+	// athenaPageSize is the most rows GetQueryResults returns per call
+	athenaPageSize = 1000
+)
+
+var (
+	// ErrAthenaTimeout is returned when an Athena query does not finish in time
+	ErrAthenaTimeout = errors.New("athena query did not complete in time")
+	// ErrAthenaScanLimit is returned when an Athena query scans more data than allowed
+	ErrAthenaScanLimit = errors.New("athena query exceeded the scan limit")
+)
+
+// AthenaQueryParams describes an Athena query. OutputLocation may be empty
+// when the workgroup sets one; MaxBytesScanned of zero disables the limit.
+type AthenaQueryParams struct {
+	SQL             string
+	Database        string
+	Workgroup       string
+	OutputLocation  string
+	MaxRows         int
+	MaxBytesScanned int64
+	Timeout         time.Duration
+}
+
+// RunAthenaQuery starts an Athena query, polls until it completes and returns
+// up to MaxRows rows. The query is stopped when Timeout passes (ErrAthenaTimeout)
+// or it scans more than MaxBytesScanned (ErrAthenaScanLimit); both errors come
+// with the partial result so the caller can report the execution ID and scan size.
+func (c *Client) RunAthenaQuery(ctx context.Context, params AthenaQueryParams) (*types.AthenaResult, error) {
+	began := time.Now()
+
+	executionID, err := c.StartAthenaQuery(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	deadline := time.NewTimer(params.Timeout)
+	defer deadline.Stop()
+	ticker := time.NewTicker(athenaPollInterval)
+	defer ticker.Stop()
+
+	for {
+		result, err := c.GetAthenaQuery(ctx, executionID, params.MaxBytesScanned)
+		if err != nil {
+			return result, err
+		}
+
+		switch athenatypes.QueryExecutionState(result.State) {
+		case athenatypes.QueryExecutionStateSucceeded:
+			if err := c.readAthenaResults(ctx, executionID, result, params.MaxRows); err != nil {
+				return nil, err
+			}
+			c.logger.WithFields(logrus.Fields{
+				"queryExecutionId": executionID,
+				"count":            len(result.Rows),
+				"dataScanned":      result.DataScannedBytes,
+				"duration":         time.Since(began),
+			}).Info("Completed Athena query")
+			return result, nil
+		case athenatypes.QueryExecutionStateFailed, athenatypes.QueryExecutionStateCancelled:
+			return nil, fmt.Errorf("athena query %s ended with status %s: %s", executionID, result.State, result.StateReason)
+		}
+
+		select {
+		case <-ctx.Done():
+			c.stopAthenaQuery(executionID)
+			return nil, ctx.Err()
+		case <-deadline.C:
+			c.stopAthenaQuery(executionID)
+			return result, ErrAthenaTimeout
+		case <-ticker.C:
+		}
+	}
+}
+
+// StartAthenaQuery starts an Athena query and returns its execution ID
+// without waiting for it
+func (c *Client) StartAthenaQuery(ctx context.Context, params AthenaQueryParams) (string, error) {
+	input := &athena.StartQueryExecutionInput{
+		QueryString: aws.String(params.SQL),
+		WorkGroup:   aws.String(params.Workgroup),
+	}
+	if params.Database != "" {
+		input.QueryExecutionContext = &athenatypes.QueryExecutionContext{Database: aws.String(params.Database)}
+	}
+	if params.OutputLocation != "" {
+		input.ResultConfiguration = &athenatypes.ResultConfiguration{OutputLocation: aws.String(params.OutputLocation)}
+	}
+
+	started, err := c.athena.StartQueryExecution(ctx, input)
+	if err != nil {
+		c.logger.WithError(err).WithField("workgroup", params.Workgroup).Error("Failed to start Athena query")
+		return "", fmt.Errorf("failed to start athena query: %w", err)
+	}
+	return aws.ToString(started.QueryExecutionId), nil
+}
+
+// GetAthenaQuery returns the state and statistics of a query. A query that
+// scanned more than maxBytesScanned is returned with ErrAthenaScanLimit and,
+// if still running, stopped; zero disables the limit.
+func (c *Client) GetAthenaQuery(ctx context.Context, executionID string, maxBytesScanned int64) (*types.AthenaResult, error) {
+	output, err := c.athena.GetQueryExecution(ctx, &athena.GetQueryExecutionInput{QueryExecutionId: aws.String(executionID)})
+	if err != nil {
+		c.logger.WithError(err).WithField("queryExecutionId", executionID).Error("Failed to get Athena query status")
+		return nil, fmt.Errorf("failed to get status of athena query %s: %w", executionID, err)
+	}
+
+	result := convertAthenaExecution(executionID, output.QueryExecution)
+	if maxBytesScanned > 0 && result.DataScannedBytes > maxBytesScanned {
+		if !result.Done() {
+			c.stopAthenaQuery(executionID)
+		}
+		return result, ErrAthenaScanLimit
+	}
+	return result, nil
+}
+
+// GetAthenaQueryResults returns up to maxRows rows of a query that succeeded
+func (c *Client) GetAthenaQueryResults(ctx context.Context, executionID string, maxRows int) (*types.AthenaResult, error) {
+	result, err := c.GetAthenaQuery(ctx, executionID, 0)
+	if err != nil {
+		return nil, err
+	}
+	if result.State != string(athenatypes.QueryExecutionStateSucceeded) {
+		return result, fmt.Errorf("athena query %s is %s, results are only available once it SUCCEEDED", executionID, result.State)
+	}
+	if err := c.readAthenaResults(ctx, executionID, result, maxRows); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// readAthenaResults reads up to maxRows rows of a finished query into result.
+// The first row of a SELECT holds the column names and is skipped.
+func (c *Client) readAthenaResults(ctx context.Context, executionID string, result *types.AthenaResult, maxRows int) error {
+	headerRow := result.StatementType == string(athenatypes.StatementTypeDml)
+
+	paginator := athena.NewGetQueryResultsPaginator(c.athena, &athena.GetQueryResultsInput{
+		QueryExecutionId: aws.String(executionID),
+		MaxResults:       aws.Int32(athenaPageSize),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			c.logger.WithError(err).WithField("queryExecutionId", executionID).Error("Failed to get Athena query results")
+			return fmt.Errorf("failed to get results of athena query %s: %w", executionID, err)
+		}
+
+		if result.Columns == nil && page.ResultSet.ResultSetMetadata != nil {
+			result.Columns = make([]string, 0, len(page.ResultSet.ResultSetMetadata.ColumnInfo))
+			for _, column := range page.ResultSet.ResultSetMetadata.ColumnInfo {
+				result.Columns = append(result.Columns, aws.ToString(column.Name))
+			}
+		}
+
+		for _, row := range page.ResultSet.Rows {
+			if headerRow {
+				headerRow = false
+				continue
+			}
+			if len(result.Rows) == maxRows {
+				result.Truncated = true
+				return nil
+			}
+
+			values := make(map[string]string, len(row.Data))
+			for i, datum := range row.Data {
+				if i < len(result.Columns) {
+					values[result.Columns[i]] = aws.ToString(datum.VarCharValue)
+				}
+			}
+			result.Rows = append(result.Rows, values)
+		}
+	}
+
+	return nil
+}
+
+// stopAthenaQuery cancels a running query so it stops scanning (and billing).
+// It uses its own context because the caller's may already be done.
+func (c *Client) stopAthenaQuery(executionID string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if _, err := c.athena.StopQueryExecution(ctx, &athena.StopQueryExecutionInput{QueryExecutionId: aws.String(executionID)}); err != nil {
+		c.logger.WithError(err).WithField("queryExecutionId", executionID).Warn("Failed to stop Athena query")
+	}
+}
+
+// convertAthenaExecution converts the state and statistics of a query execution
+func convertAthenaExecution(executionID string, execution *athenatypes.QueryExecution) *types.AthenaResult {
+	result := &types.AthenaResult{
+		QueryExecutionID: executionID,
+		StatementType:    string(execution.StatementType),
+		Rows:             []map[string]string{},
+	}
+	if execution.Status != nil {
+		result.State = string(execution.Status.State)
+		result.StateReason = aws.ToString(execution.Status.StateChangeReason)
+	}
+	if execution.Statistics != nil {
+		result.DataScannedBytes = aws.ToInt64(execution.Statistics.DataScannedInBytes)
+		result.ExecutionTimeMs = aws.ToInt64(execution.Statistics.EngineExecutionTimeInMillis)
+	}
+	if execution.ResultConfiguration != nil {
+		result.OutputLocation = aws.ToString(execution.ResultConfiguration.OutputLocation)
+	}
+	return result
+}
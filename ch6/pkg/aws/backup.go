@@ -0,0 +1,142 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/backup"
+	backuptypes "github.com/aws/aws-sdk-go-v2/service/backup/types"
+
+	"aws-mcp-server/pkg/types"
+
+	"github.com/sirupsen/logrus"
+)
+
+// BackupJobParams describes an on-demand backup. DeleteAfterDays of zero keeps
+// the recovery point until it is deleted.
+type BackupJobParams struct {
+	ResourceARN     string
+	VaultName       string
+	RoleARN         string
+	DeleteAfterDays int64
+}
+
+// ListBackupJobs retrieves the backup jobs created since the given time, of
+// one resource when resourceARN is set
+func (c *Client) ListBackupJobs(ctx context.Context, since time.Time, resourceARN string) ([]types.BackupJob, error) {
+	start := time.Now()
+
+	input := &backup.ListBackupJobsInput{
+		ByCreatedAfter: aws.Time(since),
+	}
+	if resourceARN != "" {
+		input.ByResourceArn = aws.String(resourceARN)
+	}
+
+	var jobs []types.BackupJob
+	paginator := backup.NewListBackupJobsPaginator(c.backup, input)
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			c.logger.WithError(err).Error("Failed to list backup jobs")
+			return nil, fmt.Errorf("failed to list backup jobs: %w", err)
+		}
+		for _, job := range page.BackupJobs {
+			jobs = append(jobs, convertBackupJob(job))
+		}
+	}
+
+	c.logger.WithFields(logrus.Fields{
+		"count":    len(jobs),
+		"duration": time.Since(start),
+	}).Info("Retrieved backup jobs")
+
+	return jobs, nil
+}
+
+// ListRecoveryPoints retrieves the recovery points of a resource across
+// backup vaults
+func (c *Client) ListRecoveryPoints(ctx context.Context, resourceARN string) ([]types.RecoveryPoint, error) {
+	var points []types.RecoveryPoint
+	paginator := backup.NewListRecoveryPointsByResourcePaginator(c.backup, &backup.ListRecoveryPointsByResourceInput{
+		ResourceArn: aws.String(resourceARN),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			c.logger.WithError(err).WithField("resourceArn", resourceARN).Error("Failed to list recovery points")
+			return nil, fmt.Errorf("failed to list recovery points of %s: %w", resourceARN, err)
+		}
+		for _, point := range page.RecoveryPoints {
+			points = append(points, types.RecoveryPoint{
+				ARN:       aws.ToString(point.RecoveryPointArn),
+				VaultName: aws.ToString(point.BackupVaultName),
+				Status:    string(point.Status),
+				SizeBytes: aws.ToInt64(point.BackupSizeBytes),
+				Encrypted: aws.ToString(point.EncryptionKeyArn) != "",
+				Created:   aws.ToTime(point.CreationDate),
+			})
+		}
+	}
+	return points, nil
+}
+
+// StartBackupJob starts an on-demand backup of a resource into a vault
+func (c *Client) StartBackupJob(ctx context.Context, params BackupJobParams) (*types.BackupJob, error) {
+	logger := c.logger.WithFields(logrus.Fields{
+		"resourceArn": params.ResourceARN,
+		"vault":       params.VaultName,
+	})
+	logger.Info("Starting on-demand backup")
+
+	input := &backup.StartBackupJobInput{
+		ResourceArn:     aws.String(params.ResourceARN),
+		BackupVaultName: aws.String(params.VaultName),
+		IamRoleArn:      aws.String(params.RoleARN),
+	}
+	if params.DeleteAfterDays > 0 {
+		input.Lifecycle = &backuptypes.Lifecycle{DeleteAfterDays: aws.Int64(params.DeleteAfterDays)}
+	}
+
+	result, err := c.backup.StartBackupJob(ctx, input)
+	if err != nil {
+		logger.WithError(err).Error("Failed to start backup job")
+		return nil, fmt.Errorf("failed to start backup of %s: %w", params.ResourceARN, err)
+	}
+
+	return &types.BackupJob{
+		ID:               aws.ToString(result.BackupJobId),
+		ResourceARN:      params.ResourceARN,
+		VaultName:        params.VaultName,
+		State:            string(backuptypes.BackupJobStateCreated),
+		RecoveryPointARN: aws.ToString(result.RecoveryPointArn),
+		Created:          aws.ToTime(result.CreationDate),
+	}, nil
+}
+
+// convertBackupJob converts a backup job
+func convertBackupJob(job backuptypes.BackupJob) types.BackupJob {
+	converted := types.BackupJob{
+		ID:               aws.ToString(job.BackupJobId),
+		ResourceARN:      aws.ToString(job.ResourceArn),
+		ResourceType:     aws.ToString(job.ResourceType),
+		ResourceName:     aws.ToString(job.ResourceName),
+		VaultName:        aws.ToString(job.BackupVaultName),
+		State:            string(job.State),
+		StatusMessage:    aws.ToString(job.StatusMessage),
+		PercentDone:      aws.ToString(job.PercentDone),
+		SizeBytes:        aws.ToInt64(job.BackupSizeInBytes),
+		RecoveryPointARN: aws.ToString(job.RecoveryPointArn),
+		Created:          aws.ToTime(job.CreationDate),
+		Completed:        job.CompletionDate,
+	}
+	if job.CreatedBy != nil {
+		converted.BackupPlan = aws.ToString(job.CreatedBy.BackupPlanName)
+		if converted.BackupPlan == "" {
+			converted.BackupPlan = aws.ToString(job.CreatedBy.BackupPlanId)
+		}
+	}
+	return converted
+}
@@ -0,0 +1,116 @@
+package aws
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockruntime"
+	brtypes "github.com/aws/aws-sdk-go-v2/service/bedrockruntime/types"
+
+	"github.com/sirupsen/logrus"
+)
+
+// titanEmbeddingRequest is the request body of Amazon Titan text embedding models
+type titanEmbeddingRequest struct {
+	InputText  string `json:"inputText"`
+	Dimensions int    `json:"dimensions,omitempty"`
+	Normalize  bool   `json:"normalize"`
+}
+
+// titanEmbeddingResponse is the response body of Amazon Titan text embedding models
+type titanEmbeddingResponse struct {
+	Embedding []float32 `json:"embedding"`
+}
+
+// EmbedText embeds a text with a Titan text embedding model on Bedrock. With
+// zero dimensions the model's default size is used.
+func (c *Client) EmbedText(ctx context.Context, modelID, text string, dimensions int) ([]float32, error) {
+	body, err := json.Marshal(titanEmbeddingRequest{
+		InputText:  text,
+		Dimensions: dimensions,
+		Normalize:  true,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal embedding request: %w", err)
+	}
+
+	result, err := c.bedrock.InvokeModel(ctx, &bedrockruntime.InvokeModelInput{
+		ModelId:     aws.String(modelID),
+		Body:        body,
+		ContentType: aws.String("application/json"),
+		Accept:      aws.String("application/json"),
+	})
+	if err != nil {
+		c.logger.WithError(err).WithFields(logrus.Fields{
+			"model": modelID,
+		}).Error("Failed to invoke Bedrock embedding model")
+		return nil, fmt.Errorf("failed to embed text with %s: %w", modelID, err)
+	}
+
+	var response titanEmbeddingResponse
+	if err := json.Unmarshal(result.Body, &response); err != nil {
+		return nil, fmt.Errorf("failed to parse embedding of %s: %w", modelID, err)
+	}
+	if len(response.Embedding) == 0 {
+		return nil, fmt.Errorf("model %s returned no embedding", modelID)
+	}
+	return response.Embedding, nil
+}
+
+// GenerateText asks a Bedrock model to answer a prompt under a system prompt
+// through the Converse API, which works the same for every text model
+func (c *Client) GenerateText(ctx context.Context, modelID, system, prompt string, maxTokens int) (string, error) {
+	start := time.Now()
+
+	input := &bedrockruntime.ConverseInput{
+		ModelId: aws.String(modelID),
+		Messages: []brtypes.Message{{
+			Role:    brtypes.ConversationRoleUser,
+			Content: []brtypes.ContentBlock{&brtypes.ContentBlockMemberText{Value: prompt}},
+		}},
+	}
+	if system != "" {
+		input.System = []brtypes.SystemContentBlock{&brtypes.SystemContentBlockMemberText{Value: system}}
+	}
+	if maxTokens > 0 {
+		input.InferenceConfig = &brtypes.InferenceConfiguration{MaxTokens: aws.Int32(int32(maxTokens))}
+	}
+
+	result, err := c.bedrock.Converse(ctx, input)
+	if err != nil {
+		c.logger.WithError(err).WithFields(logrus.Fields{
+			"model": modelID,
+		}).Error("Failed to invoke Bedrock model")
+		return "", fmt.Errorf("failed to generate text with %s: %w", modelID, err)
+	}
+
+	message, ok := result.Output.(*brtypes.ConverseOutputMemberMessage)
+	if !ok {
+		return "", fmt.Errorf("model %s returned no message", modelID)
+	}
+	var text strings.Builder
+	for _, block := range message.Value.Content {
+		if part, ok := block.(*brtypes.ContentBlockMemberText); ok {
+			text.WriteString(part.Value)
+		}
+	}
+
+	fields := logrus.Fields{
+		"model":    modelID,
+		"duration": time.Since(start),
+	}
+	if usage := result.Usage; usage != nil {
+		fields["inputTokens"] = aws.ToInt32(usage.InputTokens)
+		fields["outputTokens"] = aws.ToInt32(usage.OutputTokens)
+	}
+	c.logger.WithFields(fields).Info("Generated text with Bedrock")
+
+	if strings.TrimSpace(text.String()) == "" {
+		return "", fmt.Errorf("model %s returned no text", modelID)
+	}
+	return strings.TrimSpace(text.String()), nil
+}
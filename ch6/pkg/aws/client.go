@@ -3,12 +3,41 @@ package aws
 import (
 	"context"
 	"fmt"
+	"regexp"
+	"strings"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/apigateway"
+	"github.com/aws/aws-sdk-go-v2/service/apigatewayv2"
+	"github.com/aws/aws-sdk-go-v2/service/athena"
+	"github.com/aws/aws-sdk-go-v2/service/backup"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockruntime"
+	"github.com/aws/aws-sdk-go-v2/service/cloudtrail"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
+	"github.com/aws/aws-sdk-go-v2/service/configservice"
+	"github.com/aws/aws-sdk-go-v2/service/costexplorer"
 	"github.com/aws/aws-sdk-go-v2/service/ec2"
 	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/aws-sdk-go-v2/service/ecr"
+	"github.com/aws/aws-sdk-go-v2/service/ecs"
+	"github.com/aws/aws-sdk-go-v2/service/elasticache"
+	elbv2 "github.com/aws/aws-sdk-go-v2/service/elasticloadbalancingv2"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	"github.com/aws/aws-sdk-go-v2/service/kinesis"
+	"github.com/aws/aws-sdk-go-v2/service/organizations"
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+	"github.com/aws/aws-sdk-go-v2/service/redshiftdata"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	"github.com/aws/aws-sdk-go-v2/service/servicequotas"
+	"github.com/aws/aws-sdk-go-v2/service/sfn"
+	"github.com/aws/aws-sdk-go-v2/service/sns"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
 
 	"aws-mcp-server/internal/logging"
 	"aws-mcp-server/pkg/types"
@@ -16,10 +45,41 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
+// ProviderName identifies AWS resources among those of other cloud providers
+const ProviderName = "aws"
+
 type Client struct {
-	cfg    aws.Config
-	ec2    *ec2.Client
-	logger *logging.Logger
+	cfg            aws.Config
+	ec2            *ec2.Client
+	elbv2          *elbv2.Client
+	rds            *rds.Client
+	iam            *iam.Client
+	logs           *cloudwatchlogs.Client
+	cloudwatch     *cloudwatch.Client
+	cloudtrail     *cloudtrail.Client
+	costexplorer   *costexplorer.Client
+	ecs            *ecs.Client
+	elasticache    *elasticache.Client
+	athena         *athena.Client
+	redshiftdata   *redshiftdata.Client
+	bedrock        *bedrockruntime.Client
+	sqs            *sqs.Client
+	sns            *sns.Client
+	s3             *s3.Client
+	ssm            *ssm.Client
+	secretsmanager *secretsmanager.Client
+	ecr            *ecr.Client
+	sfn            *sfn.Client
+	kinesis        *kinesis.Client
+	apigateway     *apigateway.Client
+	apigatewayv2   *apigatewayv2.Client
+	servicequotas  *servicequotas.Client
+	configservice  *configservice.Client
+	backup         *backup.Client
+	organizations  *organizations.Client
+	sts            *sts.Client
+	hooks          *hookChain
+	logger         *logging.Logger
 }
 
 type CreateInstanceParams struct {
@@ -29,6 +89,8 @@ type CreateInstanceParams struct {
 	SecurityGroupID string
 	SubnetID        string
 	Name            string
+	// Spot launches the instance on the Spot market when set
+	Spot *SpotOptions
 }
 
 func NewClient(region, profile string, logger *logging.Logger) (*Client, error) {
@@ -39,11 +101,61 @@ func NewClient(region, profile string, logger *logging.Logger) (*Client, error)
 		return nil, fmt.Errorf("failed to load AWS config: %w", err)
 	}
 
+	return NewClientFromConfig(cfg, logger), nil
+}
+
+// NewClientFromConfig creates a client from an AWS configuration, e.g. one
+// whose service clients are answered by a fake fleet
+func NewClientFromConfig(cfg aws.Config, logger *logging.Logger) *Client {
+	// Every service client gets the hook middleware, so hooks added with
+	// AddHook observe all of them
+	hooks := &hookChain{hooks: []Hook{logHook{logger: logger}}}
+	cfg.APIOptions = append(cfg.APIOptions, hooks.register)
+
+	return newClient(cfg, hooks, logger)
+}
+
+// newClient creates the service clients for a configuration whose API
+// options already register hooks
+func newClient(cfg aws.Config, hooks *hookChain, logger *logging.Logger) *Client {
 	return &Client{
-		cfg:    cfg,
-		ec2:    ec2.NewFromConfig(cfg),
-		logger: logger,
-	}, nil
+		cfg:            cfg,
+		ec2:            ec2.NewFromConfig(cfg),
+		elbv2:          elbv2.NewFromConfig(cfg),
+		rds:            rds.NewFromConfig(cfg),
+		iam:            iam.NewFromConfig(cfg),
+		logs:           cloudwatchlogs.NewFromConfig(cfg),
+		cloudwatch:     cloudwatch.NewFromConfig(cfg),
+		cloudtrail:     cloudtrail.NewFromConfig(cfg),
+		costexplorer:   costexplorer.NewFromConfig(cfg),
+		ecs:            ecs.NewFromConfig(cfg),
+		elasticache:    elasticache.NewFromConfig(cfg),
+		athena:         athena.NewFromConfig(cfg),
+		redshiftdata:   redshiftdata.NewFromConfig(cfg),
+		bedrock:        bedrockruntime.NewFromConfig(cfg),
+		sqs:            sqs.NewFromConfig(cfg),
+		sns:            sns.NewFromConfig(cfg),
+		s3:             s3.NewFromConfig(cfg),
+		ssm:            ssm.NewFromConfig(cfg),
+		secretsmanager: secretsmanager.NewFromConfig(cfg),
+		ecr:            ecr.NewFromConfig(cfg),
+		sfn:            sfn.NewFromConfig(cfg),
+		kinesis:        kinesis.NewFromConfig(cfg),
+		apigateway:     apigateway.NewFromConfig(cfg),
+		apigatewayv2:   apigatewayv2.NewFromConfig(cfg),
+		servicequotas:  servicequotas.NewFromConfig(cfg),
+		configservice:  configservice.NewFromConfig(cfg),
+		backup:         backup.NewFromConfig(cfg),
+		organizations:  organizations.NewFromConfig(cfg),
+		sts:            sts.NewFromConfig(cfg),
+		hooks:          hooks,
+		logger:         logger,
+	}
+}
+
+// Config returns the AWS configuration shared by all service clients
+func (c *Client) Config() aws.Config {
+	return c.cfg
 }
 
 // HealthCheck verifies AWS connectivity
@@ -55,34 +167,99 @@ func (c *Client) HealthCheck(ctx context.Context) error {
 	return nil
 }
 
-// ListEC2Instances retrieves all EC2 instances in the region
-func (c *Client) ListEC2Instances(ctx context.Context) ([]types.AWSResource, error) {
+// ListEC2Instances retrieves all EC2 instances in the region, following
+// every page of the listing
+func (c *Client) ListEC2Instances(ctx context.Context) ([]types.CloudResource, error) {
 	start := time.Now()
 
-	result, err := c.ec2.DescribeInstances(ctx, &ec2.DescribeInstancesInput{})
+	var resources []types.CloudResource
+	paginator := ec2.NewDescribeInstancesPaginator(c.ec2, &ec2.DescribeInstancesInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			c.logger.WithError(err).Error("Failed to describe EC2 instances")
+			return nil, fmt.Errorf("failed to describe instances: %w", err)
+		}
+		for _, reservation := range page.Reservations {
+			for _, instance := range reservation.Instances {
+				resources = append(resources, c.convertEC2Instance(instance))
+			}
+		}
+	}
+
+	c.logger.WithFields(logrus.Fields{
+		"count":    len(resources),
+		"duration": time.Since(start),
+	}).Info("Retrieved EC2 instances")
+
+	return resources, nil
+}
+
+// DescribeInstances page sizes EC2 accepts
+const (
+	minDescribeInstancesPage = 5
+	maxDescribeInstancesPage = 1000
+)
+
+// ListEC2InstancesPage retrieves one page of the EC2 instances in the region.
+// nextToken continues where the previous page ended, empty for the first
+// page; the returned token is empty after the last page. EC2 takes page
+// sizes of 5 to 1000, so others are brought into that range.
+func (c *Client) ListEC2InstancesPage(ctx context.Context, nextToken string, maxResults int) ([]types.CloudResource, string, error) {
+	input := &ec2.DescribeInstancesInput{
+		MaxResults: aws.Int32(int32(min(max(maxResults, minDescribeInstancesPage), maxDescribeInstancesPage))),
+	}
+	if nextToken != "" {
+		input.NextToken = aws.String(nextToken)
+	}
+
+	result, err := c.ec2.DescribeInstances(ctx, input)
 	if err != nil {
 		c.logger.WithError(err).Error("Failed to describe EC2 instances")
-		return nil, fmt.Errorf("failed to describe instances: %w", err)
+		return nil, "", fmt.Errorf("failed to describe instances: %w", err)
 	}
 
-	var resources []types.AWSResource
+	var resources []types.CloudResource
 	for _, reservation := range result.Reservations {
 		for _, instance := range reservation.Instances {
-			resource := c.convertEC2Instance(instance)
-			resources = append(resources, resource)
+			resources = append(resources, c.convertEC2Instance(instance))
 		}
 	}
+	return resources, aws.ToString(result.NextToken), nil
+}
 
-	c.logger.WithFields(logrus.Fields{
-		"count":    len(resources),
-		"duration": time.Since(start),
-	}).Info("Retrieved EC2 instances")
-
+// describeInstancesBatch is how many instance IDs one filtered
+// DescribeInstances call asks for
+const describeInstancesBatch = 200
+
+// DescribeEC2Instances retrieves the EC2 instances with the given IDs. IDs are
+// matched by filter, so instances that no longer exist are left out rather
+// than failing the call.
+func (c *Client) DescribeEC2Instances(ctx context.Context, instanceIDs []string) ([]types.CloudResource, error) {
+	var resources []types.CloudResource
+	for start := 0; start < len(instanceIDs); start += describeInstancesBatch {
+		batch := instanceIDs[start:min(start+describeInstancesBatch, len(instanceIDs))]
+		paginator := ec2.NewDescribeInstancesPaginator(c.ec2, &ec2.DescribeInstancesInput{
+			Filters: []ec2types.Filter{{Name: aws.String("instance-id"), Values: batch}},
+		})
+		for paginator.HasMorePages() {
+			page, err := paginator.NextPage(ctx)
+			if err != nil {
+				c.logger.WithError(err).Error("Failed to describe changed EC2 instances")
+				return nil, fmt.Errorf("failed to describe instances: %w", err)
+			}
+			for _, reservation := range page.Reservations {
+				for _, instance := range reservation.Instances {
+					resources = append(resources, c.convertEC2Instance(instance))
+				}
+			}
+		}
+	}
 	return resources, nil
 }
 
 // GetEC2Instance retrieves a specific EC2 instance
-func (c *Client) GetEC2Instance(ctx context.Context, instanceID string) (*types.AWSResource, error) {
+func (c *Client) GetEC2Instance(ctx context.Context, instanceID string) (*types.CloudResource, error) {
 	result, err := c.ec2.DescribeInstances(ctx, &ec2.DescribeInstancesInput{
 		InstanceIds: []string{instanceID},
 	})
@@ -100,27 +277,63 @@ func (c *Client) GetEC2Instance(ctx context.Context, instanceID string) (*types.
 	return &resource, nil
 }
 
-// convertEC2Instance converts AWS EC2 instance to our standard format
-func (c *Client) convertEC2Instance(instance ec2types.Instance) types.AWSResource {
-	tags := make(map[string]string)
-	for _, tag := range instance.Tags {
-		if tag.Key != nil && tag.Value != nil {
-			tags[*tag.Key] = *tag.Value
-		}
+// GetEC2InstanceStatus retrieves the state and status checks of an instance,
+// including stopped instances, which have no status checks
+func (c *Client) GetEC2InstanceStatus(ctx context.Context, instanceID string) (*types.InstanceStatus, error) {
+	result, err := c.ec2.DescribeInstanceStatus(ctx, &ec2.DescribeInstanceStatusInput{
+		InstanceIds:         []string{instanceID},
+		IncludeAllInstances: aws.Bool(true),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe status of instance %s: %w", instanceID, err)
 	}
-
-	details := map[string]interface{}{
-		"instanceType": string(instance.InstanceType),
-		"placement":    instance.Placement,
-		"launchTime":   instance.LaunchTime,
+	if len(result.InstanceStatuses) == 0 {
+		return nil, fmt.Errorf("instance %s not found", instanceID)
 	}
 
-	if instance.PublicIpAddress != nil {
-		details["publicIpAddress"] = *instance.PublicIpAddress
+	status := result.InstanceStatuses[0]
+	converted := &types.InstanceStatus{InstanceID: aws.ToString(status.InstanceId)}
+	if status.InstanceState != nil {
+		converted.State = string(status.InstanceState.Name)
+	}
+	if status.SystemStatus != nil {
+		converted.SystemStatus = string(status.SystemStatus.Status)
+	}
+	if status.InstanceStatus != nil {
+		converted.InstanceStatus = string(status.InstanceStatus.Status)
 	}
+	return converted, nil
+}
 
-	if instance.PrivateIpAddress != nil {
-		details["privateIpAddress"] = *instance.PrivateIpAddress
+// convertEC2Instance converts AWS EC2 instance to our standard format
+func (c *Client) convertEC2Instance(instance ec2types.Instance) types.CloudResource {
+	tags := convertTags(instance.Tags)
+
+	details := &types.EC2InstanceDetails{
+		InstanceType:     string(instance.InstanceType),
+		Placement:        instance.Placement,
+		LaunchTime:       instance.LaunchTime,
+		ImageID:          aws.ToString(instance.ImageId),
+		PlatformDetails:  aws.ToString(instance.PlatformDetails),
+		KeyName:          aws.ToString(instance.KeyName),
+		PublicIPAddress:  aws.ToString(instance.PublicIpAddress),
+		PrivateIPAddress: aws.ToString(instance.PrivateIpAddress),
+		VpcID:            aws.ToString(instance.VpcId),
+		SubnetID:         aws.ToString(instance.SubnetId),
+		Lifecycle:        string(instance.InstanceLifecycle),
+		SpotRequestID:    aws.ToString(instance.SpotInstanceRequestId),
+	}
+
+	// Platform is only set for Windows instances
+	details.Platform = "linux"
+	if instance.Platform == ec2types.PlatformValuesWindows {
+		details.Platform = "windows"
+	}
+
+	for _, group := range instance.SecurityGroups {
+		if group.GroupId != nil {
+			details.SecurityGroups = append(details.SecurityGroups, *group.GroupId)
+		}
 	}
 
 	var instanceID string
@@ -128,8 +341,9 @@ func (c *Client) convertEC2Instance(instance ec2types.Instance) types.AWSResourc
 		instanceID = *instance.InstanceId
 	}
 
-	return types.AWSResource{
+	return types.CloudResource{
 		ID:       instanceID,
+		Provider: ProviderName,
 		Type:     "ec2-instance",
 		Region:   c.cfg.Region,
 		State:    string(instance.State.Name),
@@ -139,12 +353,24 @@ func (c *Client) convertEC2Instance(instance ec2types.Instance) types.AWSResourc
 	}
 }
 
+// convertTags converts EC2 tags to a simple key/value map
+func convertTags(ec2Tags []ec2types.Tag) map[string]string {
+	tags := make(map[string]string)
+	for _, tag := range ec2Tags {
+		if tag.Key != nil && tag.Value != nil {
+			tags[*tag.Key] = *tag.Value
+		}
+	}
+	return tags
+}
+
 // CreateEC2Instance creates a new EC2 instance
-func (c *Client) CreateEC2Instance(ctx context.Context, params CreateInstanceParams) (*types.AWSResource, error) {
+func (c *Client) CreateEC2Instance(ctx context.Context, params CreateInstanceParams) (*types.CloudResource, error) {
 	c.logger.WithFields(logrus.Fields{
 		"imageId":      params.ImageID,
 		"instanceType": params.InstanceType,
 		"keyName":      params.KeyName,
+		"spot":         params.Spot != nil,
 	}).Info("Creating EC2 instance")
 
 	input := &ec2.RunInstancesInput{
@@ -158,6 +384,10 @@ func (c *Client) CreateEC2Instance(ctx context.Context, params CreateInstancePar
 		input.KeyName = &params.KeyName
 	}
 
+	if params.Spot != nil {
+		input.InstanceMarketOptions = params.Spot.marketOptions()
+	}
+
 	if params.SecurityGroupID != "" {
 		input.SecurityGroupIds = []string{params.SecurityGroupID}
 	}
@@ -256,6 +486,21 @@ func (c *Client) TerminateEC2Instance(ctx context.Context, instanceID string) er
 	return nil
 }
 
+// TagEC2Instance applies the given tags to an EC2 instance, overwriting existing values
+func (c *Client) TagEC2Instance(ctx context.Context, instanceID string, tags map[string]string) error {
+	c.logger.WithFields(logrus.Fields{
+		"instanceId": instanceID,
+		"tags":       tags,
+	}).Info("Tagging EC2 instance")
+
+	if err := c.tagInstance(ctx, instanceID, tags); err != nil {
+		c.logger.WithError(err).WithField("instanceId", instanceID).Error("Failed to tag EC2 instance")
+		return fmt.Errorf("failed to tag instance %s: %w", instanceID, err)
+	}
+
+	return nil
+}
+
 // tagInstance adds tags to an EC2 instance
 func (c *Client) tagInstance(ctx context.Context, instanceID string, tags map[string]string) error {
 	var ec2Tags []ec2types.Tag
@@ -275,6 +520,120 @@ func (c *Client) tagInstance(ctx context.Context, instanceID string, tags map[st
 	return err
 }
 
+// ec2ResourceID matches EC2 resource IDs such as i-0abc123 or subnet-0abc123
+var ec2ResourceID = regexp.MustCompile(`^[a-z]+(-[a-z]+)*-[0-9a-f]{8,17}$`)
+
+// IsEC2ResourceID reports whether id looks like the ID of an EC2 resource that
+// can be tagged, such as an instance, volume, snapshot or security group
+func IsEC2ResourceID(id string) bool {
+	return ec2ResourceID.MatchString(id)
+}
+
+// TagEC2Resources applies the given tags to EC2 resources of any type,
+// overwriting existing values
+func (c *Client) TagEC2Resources(ctx context.Context, resourceIDs []string, tags map[string]string) error {
+	c.logger.WithFields(logrus.Fields{
+		"resourceIds": resourceIDs,
+		"tags":        tags,
+	}).Info("Tagging EC2 resources")
+
+	ec2Tags := make([]ec2types.Tag, 0, len(tags))
+	for key, value := range tags {
+		ec2Tags = append(ec2Tags, ec2types.Tag{
+			Key:   aws.String(key),
+			Value: aws.String(value),
+		})
+	}
+
+	_, err := c.ec2.CreateTags(ctx, &ec2.CreateTagsInput{
+		Resources: resourceIDs,
+		Tags:      ec2Tags,
+	})
+	if err != nil {
+		c.logger.WithError(err).WithField("resourceIds", resourceIDs).Error("Failed to tag EC2 resources")
+		return fmt.Errorf("failed to tag %s: %w", strings.Join(resourceIDs, ", "), err)
+	}
+
+	return nil
+}
+
+// UntagEC2Resources removes the tags with the given keys from EC2 resources of
+// any type, whatever their values
+func (c *Client) UntagEC2Resources(ctx context.Context, resourceIDs []string, keys []string) error {
+	c.logger.WithFields(logrus.Fields{
+		"resourceIds": resourceIDs,
+		"keys":        keys,
+	}).Info("Removing tags from EC2 resources")
+
+	ec2Tags := make([]ec2types.Tag, 0, len(keys))
+	for _, key := range keys {
+		ec2Tags = append(ec2Tags, ec2types.Tag{Key: aws.String(key)})
+	}
+
+	_, err := c.ec2.DeleteTags(ctx, &ec2.DeleteTagsInput{
+		Resources: resourceIDs,
+		Tags:      ec2Tags,
+	})
+	if err != nil {
+		c.logger.WithError(err).WithField("resourceIds", resourceIDs).Error("Failed to remove tags from EC2 resources")
+		return fmt.Errorf("failed to remove tags from %s: %w", strings.Join(resourceIDs, ", "), err)
+	}
+
+	return nil
+}
+
+// GetResourceTags returns the tags of an EC2 resource by ID or of an RDS or
+// ElastiCache resource by ARN. Other resources return nil, since their tags
+// cannot be read without knowing the service.
+func (c *Client) GetResourceTags(ctx context.Context, resourceID string) (map[string]string, error) {
+	switch {
+	case strings.HasPrefix(resourceID, "arn:aws:rds:"):
+		result, err := c.rds.ListTagsForResource(ctx, &rds.ListTagsForResourceInput{
+			ResourceName: aws.String(resourceID),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list tags of %s: %w", resourceID, err)
+		}
+		tags := make(map[string]string, len(result.TagList))
+		for _, tag := range result.TagList {
+			tags[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+		}
+		return tags, nil
+
+	case strings.HasPrefix(resourceID, "arn:aws:elasticache:"):
+		result, err := c.elasticache.ListTagsForResource(ctx, &elasticache.ListTagsForResourceInput{
+			ResourceName: aws.String(resourceID),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list tags of %s: %w", resourceID, err)
+		}
+		tags := make(map[string]string, len(result.TagList))
+		for _, tag := range result.TagList {
+			tags[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+		}
+		return tags, nil
+
+	case ec2ResourceID.MatchString(resourceID):
+		tags := make(map[string]string)
+		paginator := ec2.NewDescribeTagsPaginator(c.ec2, &ec2.DescribeTagsInput{
+			Filters: []ec2types.Filter{{Name: aws.String("resource-id"), Values: []string{resourceID}}},
+		})
+		for paginator.HasMorePages() {
+			page, err := paginator.NextPage(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to describe tags of %s: %w", resourceID, err)
+			}
+			for _, tag := range page.Tags {
+				tags[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+			}
+		}
+		return tags, nil
+
+	default:
+		return nil, nil
+	}
+}
+
 // findDefaultSubnet finds a default subnet in the default VPC or any available subnet
 func (c *Client) findDefaultSubnet(ctx context.Context) (string, error) {
 	// First, try to find the default VPC
@@ -0,0 +1,121 @@
+package aws
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudtrail"
+	cttypes "github.com/aws/aws-sdk-go-v2/service/cloudtrail/types"
+
+	"aws-mcp-server/pkg/types"
+
+	"github.com/sirupsen/logrus"
+)
+
+// LookupChangeEvents retrieves write API calls recorded by CloudTrail between
+// start and end, newest first, stopping after limit events
+func (c *Client) LookupChangeEvents(ctx context.Context, start, end time.Time, limit int) ([]types.ChangeEvent, error) {
+	began := time.Now()
+
+	input := &cloudtrail.LookupEventsInput{
+		StartTime: aws.Time(start),
+		EndTime:   aws.Time(end),
+		LookupAttributes: []cttypes.LookupAttribute{
+			{
+				AttributeKey:   cttypes.LookupAttributeKeyReadOnly,
+				AttributeValue: aws.String("false"),
+			},
+		},
+	}
+
+	var events []types.ChangeEvent
+	paginator := cloudtrail.NewLookupEventsPaginator(c.cloudtrail, input)
+	for paginator.HasMorePages() && len(events) < limit {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			c.logger.WithError(err).Error("Failed to look up CloudTrail events")
+			return nil, fmt.Errorf("failed to look up CloudTrail events: %w", err)
+		}
+
+		for _, event := range page.Events {
+			if len(events) == limit {
+				break
+			}
+
+			change := types.ChangeEvent{
+				Time:        aws.ToTime(event.EventTime),
+				EventName:   aws.ToString(event.EventName),
+				EventSource: aws.ToString(event.EventSource),
+				Username:    aws.ToString(event.Username),
+			}
+			for _, resource := range event.Resources {
+				if name := aws.ToString(resource.ResourceName); name != "" {
+					change.Resources = append(change.Resources, name)
+				}
+			}
+
+			// Failed calls carry an error code in the raw event only
+			var raw struct {
+				ErrorCode string `json:"errorCode"`
+			}
+			if err := json.Unmarshal([]byte(aws.ToString(event.CloudTrailEvent)), &raw); err == nil {
+				change.ErrorCode = raw.ErrorCode
+			}
+
+			events = append(events, change)
+		}
+	}
+
+	c.logger.WithFields(logrus.Fields{
+		"count":    len(events),
+		"duration": time.Since(began),
+	}).Info("Retrieved CloudTrail change events")
+
+	return events, nil
+}
+
+// cloudTrailDelay is how long CloudTrail can take to make an event available
+// to LookupEvents; lookups for recent changes reach back this much further
+const cloudTrailDelay = 15 * time.Minute
+
+// ChangedEC2InstanceIDs returns the IDs of the EC2 instances that API calls
+// recorded by CloudTrail since the given time touched, e.g. launches, stops,
+// terminations and tag changes, including those made by Auto Scaling
+func (c *Client) ChangedEC2InstanceIDs(ctx context.Context, since time.Time) ([]string, error) {
+	input := &cloudtrail.LookupEventsInput{
+		StartTime: aws.Time(since.Add(-cloudTrailDelay)),
+		EndTime:   aws.Time(time.Now()),
+		LookupAttributes: []cttypes.LookupAttribute{
+			{
+				AttributeKey:   cttypes.LookupAttributeKeyResourceType,
+				AttributeValue: aws.String("AWS::EC2::Instance"),
+			},
+		},
+	}
+
+	seen := make(map[string]bool)
+	var instanceIDs []string
+	paginator := cloudtrail.NewLookupEventsPaginator(c.cloudtrail, input)
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			c.logger.WithError(err).Error("Failed to look up EC2 instance events")
+			return nil, fmt.Errorf("failed to look up CloudTrail events: %w", err)
+		}
+
+		for _, event := range page.Events {
+			for _, resource := range event.Resources {
+				id := aws.ToString(resource.ResourceName)
+				if aws.ToString(resource.ResourceType) == "AWS::EC2::Instance" && strings.HasPrefix(id, "i-") && !seen[id] {
+					seen[id] = true
+					instanceIDs = append(instanceIDs, id)
+				}
+			}
+		}
+	}
+	return instanceIDs, nil
+}
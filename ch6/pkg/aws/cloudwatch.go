@@ -0,0 +1,291 @@
+package aws
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+	cwtypes "github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+
+	"aws-mcp-server/pkg/types"
+
+	"github.com/sirupsen/logrus"
+)
+
+// MetricDataParams selects one statistic of a CloudWatch metric over a time window
+type MetricDataParams struct {
+	Namespace  string
+	MetricName string
+	Dimensions map[string]string
+	Statistic  string
+	Period     int32
+	Start      time.Time
+	End        time.Time
+}
+
+// alarmHistoryData is the part of an alarm state update's HistoryData used here
+type alarmHistoryData struct {
+	OldState struct {
+		StateValue  string `json:"stateValue"`
+		StateReason string `json:"stateReason"`
+	} `json:"oldState"`
+	NewState struct {
+		StateValue  string `json:"stateValue"`
+		StateReason string `json:"stateReason"`
+	} `json:"newState"`
+}
+
+// DescribeAlarmStateChanges retrieves alarm state transitions between start and
+// end, oldest first, stopping after limit entries
+func (c *Client) DescribeAlarmStateChanges(ctx context.Context, start, end time.Time, limit int) ([]types.AlarmStateChange, error) {
+	began := time.Now()
+
+	input := &cloudwatch.DescribeAlarmHistoryInput{
+		StartDate:       aws.Time(start),
+		EndDate:         aws.Time(end),
+		HistoryItemType: cwtypes.HistoryItemTypeStateUpdate,
+		ScanBy:          cwtypes.ScanByTimestampAscending,
+	}
+
+	var changes []types.AlarmStateChange
+	paginator := cloudwatch.NewDescribeAlarmHistoryPaginator(c.cloudwatch, input)
+	for paginator.HasMorePages() && len(changes) < limit {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			c.logger.WithError(err).Error("Failed to describe alarm history")
+			return nil, fmt.Errorf("failed to describe alarm history: %w", err)
+		}
+
+		for _, item := range page.AlarmHistoryItems {
+			if len(changes) == limit {
+				break
+			}
+
+			changes = append(changes, convertAlarmHistoryItem(item))
+		}
+	}
+
+	c.logger.WithFields(logrus.Fields{
+		"count":    len(changes),
+		"duration": time.Since(began),
+	}).Info("Retrieved alarm history")
+
+	return changes, nil
+}
+
+// DescribeAlarmHistory retrieves the state transitions of one alarm, newest
+// first, stopping after limit entries. CloudWatch keeps 30 days of history.
+func (c *Client) DescribeAlarmHistory(ctx context.Context, alarmName string, limit int) ([]types.AlarmStateChange, error) {
+	began := time.Now()
+
+	input := &cloudwatch.DescribeAlarmHistoryInput{
+		AlarmName:       aws.String(alarmName),
+		AlarmTypes:      []cwtypes.AlarmType{cwtypes.AlarmTypeMetricAlarm, cwtypes.AlarmTypeCompositeAlarm},
+		HistoryItemType: cwtypes.HistoryItemTypeStateUpdate,
+		ScanBy:          cwtypes.ScanByTimestampDescending,
+	}
+
+	var changes []types.AlarmStateChange
+	paginator := cloudwatch.NewDescribeAlarmHistoryPaginator(c.cloudwatch, input)
+	for paginator.HasMorePages() && len(changes) < limit {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			c.logger.WithError(err).WithField("alarm", alarmName).Error("Failed to describe alarm history")
+			return nil, fmt.Errorf("failed to describe history of alarm %s: %w", alarmName, err)
+		}
+
+		for _, item := range page.AlarmHistoryItems {
+			if len(changes) == limit {
+				break
+			}
+			changes = append(changes, convertAlarmHistoryItem(item))
+		}
+	}
+
+	c.logger.WithFields(logrus.Fields{
+		"alarm":    alarmName,
+		"count":    len(changes),
+		"duration": time.Since(began),
+	}).Info("Retrieved alarm history")
+
+	return changes, nil
+}
+
+// convertAlarmHistoryItem converts a state update history item, reading the
+// old and new states from its JSON HistoryData
+func convertAlarmHistoryItem(item cwtypes.AlarmHistoryItem) types.AlarmStateChange {
+	change := types.AlarmStateChange{
+		Time:      aws.ToTime(item.Timestamp),
+		AlarmName: aws.ToString(item.AlarmName),
+		Summary:   aws.ToString(item.HistorySummary),
+	}
+
+	var data alarmHistoryData
+	if err := json.Unmarshal([]byte(aws.ToString(item.HistoryData)), &data); err == nil {
+		change.OldState = data.OldState.StateValue
+		change.NewState = data.NewState.StateValue
+		change.Reason = data.NewState.StateReason
+	}
+	return change
+}
+
+// ListAlarms retrieves metric and composite alarms, all of them or only the
+// named ones
+func (c *Client) ListAlarms(ctx context.Context, alarmNames ...string) ([]types.Alarm, error) {
+	began := time.Now()
+
+	input := &cloudwatch.DescribeAlarmsInput{
+		AlarmNames: alarmNames,
+		AlarmTypes: []cwtypes.AlarmType{cwtypes.AlarmTypeMetricAlarm, cwtypes.AlarmTypeCompositeAlarm},
+	}
+
+	var alarms []types.Alarm
+	paginator := cloudwatch.NewDescribeAlarmsPaginator(c.cloudwatch, input)
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			c.logger.WithError(err).Error("Failed to describe alarms")
+			return nil, fmt.Errorf("failed to describe alarms: %w", err)
+		}
+
+		for _, alarm := range page.MetricAlarms {
+			alarms = append(alarms, convertMetricAlarm(alarm))
+		}
+		for _, alarm := range page.CompositeAlarms {
+			alarms = append(alarms, types.Alarm{
+				Name:           aws.ToString(alarm.AlarmName),
+				ARN:            aws.ToString(alarm.AlarmArn),
+				Type:           "composite",
+				Description:    aws.ToString(alarm.AlarmDescription),
+				State:          string(alarm.StateValue),
+				StateReason:    aws.ToString(alarm.StateReason),
+				StateUpdated:   aws.ToTime(alarm.StateUpdatedTimestamp),
+				Rule:           aws.ToString(alarm.AlarmRule),
+				ActionsEnabled: aws.ToBool(alarm.ActionsEnabled),
+			})
+		}
+	}
+
+	c.logger.WithFields(logrus.Fields{
+		"count":    len(alarms),
+		"duration": time.Since(began),
+	}).Info("Retrieved CloudWatch alarms")
+
+	return alarms, nil
+}
+
+// convertMetricAlarm converts a metric alarm to our standard format. Metric
+// math alarms have no single metric and keep only their threshold.
+func convertMetricAlarm(alarm cwtypes.MetricAlarm) types.Alarm {
+	converted := types.Alarm{
+		Name:               aws.ToString(alarm.AlarmName),
+		ARN:                aws.ToString(alarm.AlarmArn),
+		Type:               "metric",
+		Description:        aws.ToString(alarm.AlarmDescription),
+		State:              string(alarm.StateValue),
+		StateReason:        aws.ToString(alarm.StateReason),
+		StateUpdated:       aws.ToTime(alarm.StateUpdatedTimestamp),
+		Namespace:          aws.ToString(alarm.Namespace),
+		MetricName:         aws.ToString(alarm.MetricName),
+		Statistic:          string(alarm.Statistic),
+		ComparisonOperator: string(alarm.ComparisonOperator),
+		Threshold:          alarm.Threshold,
+		Period:             aws.ToInt32(alarm.Period),
+		EvaluationPeriods:  aws.ToInt32(alarm.EvaluationPeriods),
+		ActionsEnabled:     aws.ToBool(alarm.ActionsEnabled),
+	}
+	if alarm.ExtendedStatistic != nil {
+		converted.Statistic = *alarm.ExtendedStatistic
+	}
+
+	if len(alarm.Dimensions) > 0 {
+		converted.Dimensions = make(map[string]string, len(alarm.Dimensions))
+		for _, dimension := range alarm.Dimensions {
+			converted.Dimensions[aws.ToString(dimension.Name)] = aws.ToString(dimension.Value)
+		}
+	}
+	return converted
+}
+
+// GetMetricData retrieves one metric statistic with GetMetricData, oldest datapoint first
+func (c *Client) GetMetricData(ctx context.Context, params MetricDataParams) (*types.MetricData, error) {
+	began := time.Now()
+
+	names := make([]string, 0, len(params.Dimensions))
+	for name := range params.Dimensions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	dimensions := make([]cwtypes.Dimension, 0, len(names))
+	for _, name := range names {
+		dimensions = append(dimensions, cwtypes.Dimension{
+			Name:  aws.String(name),
+			Value: aws.String(params.Dimensions[name]),
+		})
+	}
+
+	input := &cloudwatch.GetMetricDataInput{
+		StartTime: aws.Time(params.Start),
+		EndTime:   aws.Time(params.End),
+		ScanBy:    cwtypes.ScanByTimestampAscending,
+		MetricDataQueries: []cwtypes.MetricDataQuery{
+			{
+				Id: aws.String("m1"),
+				MetricStat: &cwtypes.MetricStat{
+					Metric: &cwtypes.Metric{
+						Namespace:  aws.String(params.Namespace),
+						MetricName: aws.String(params.MetricName),
+						Dimensions: dimensions,
+					},
+					Period: aws.Int32(params.Period),
+					Stat:   aws.String(params.Statistic),
+				},
+			},
+		},
+	}
+
+	data := &types.MetricData{}
+	paginator := cloudwatch.NewGetMetricDataPaginator(c.cloudwatch, input)
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			c.logger.WithError(err).WithField("metric", params.Namespace+"/"+params.MetricName).Error("Failed to get metric data")
+			return nil, fmt.Errorf("failed to get metric data for %s/%s: %w", params.Namespace, params.MetricName, err)
+		}
+
+		for _, result := range page.MetricDataResults {
+			if data.Label == "" {
+				data.Label = aws.ToString(result.Label)
+			}
+			data.StatusCode = string(result.StatusCode)
+			for i, timestamp := range result.Timestamps {
+				if i >= len(result.Values) {
+					break
+				}
+				data.Datapoints = append(data.Datapoints, types.MetricDatapoint{
+					Timestamp: timestamp,
+					Value:     result.Values[i],
+				})
+			}
+			for _, message := range result.Messages {
+				data.Messages = append(data.Messages, aws.ToString(message.Value))
+			}
+		}
+		for _, message := range page.Messages {
+			data.Messages = append(data.Messages, aws.ToString(message.Value))
+		}
+	}
+
+	c.logger.WithFields(logrus.Fields{
+		"metric":   params.Namespace + "/" + params.MetricName,
+		"count":    len(data.Datapoints),
+		"duration": time.Since(began),
+	}).Info("Retrieved metric data")
+
+	return data, nil
+}
@@ -0,0 +1,113 @@
+package aws
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	ssmtypes "github.com/aws/aws-sdk-go-v2/service/ssm/types"
+
+	"aws-mcp-server/pkg/types"
+
+	"github.com/sirupsen/logrus"
+)
+
+// commandPollInterval is how often a running shell command is checked
+const commandPollInterval = 3 * time.Second
+
+// RunShellCommand runs a shell script on a Linux instance with
+// AWS-RunShellScript and waits up to timeout for it to finish. The script is
+// stopped by the agent once timeout has passed.
+func (c *Client) RunShellCommand(ctx context.Context, instanceID string, commands []string, comment string, timeout time.Duration) (*types.CommandResult, error) {
+	return c.runScript(ctx, "AWS-RunShellScript", instanceID, commands, comment, timeout)
+}
+
+// RunPowerShellCommand runs a PowerShell script on a Windows instance with
+// AWS-RunPowerShellScript, like RunShellCommand
+func (c *Client) RunPowerShellCommand(ctx context.Context, instanceID string, commands []string, comment string, timeout time.Duration) (*types.CommandResult, error) {
+	return c.runScript(ctx, "AWS-RunPowerShellScript", instanceID, commands, comment, timeout)
+}
+
+// runScript sends a script document, whose commands parameter holds the
+// script lines, and polls the invocation until it finishes
+func (c *Client) runScript(ctx context.Context, document, instanceID string, commands []string, comment string, timeout time.Duration) (*types.CommandResult, error) {
+	start := time.Now()
+
+	input := &ssm.SendCommandInput{
+		DocumentName: aws.String(document),
+		InstanceIds:  []string{instanceID},
+		Parameters: map[string][]string{
+			"commands":         commands,
+			"executionTimeout": {strconv.Itoa(int(timeout.Seconds()))},
+		},
+		TimeoutSeconds: aws.Int32(int32(max(timeout, 30*time.Second).Seconds())),
+	}
+	if comment != "" {
+		input.Comment = aws.String(comment)
+	}
+
+	sent, err := c.ssm.SendCommand(ctx, input)
+	if err != nil {
+		c.logger.WithError(err).WithFields(logrus.Fields{
+			"instance_id": instanceID,
+			"document":    document,
+		}).Error("Failed to send shell command")
+		return nil, fmt.Errorf("failed to send command to %s: %w", instanceID, err)
+	}
+	commandID := aws.ToString(sent.Command.CommandId)
+
+	// The agent reports a little after the execution timeout, so allow a margin
+	ctx, cancel := context.WithTimeout(ctx, timeout+time.Minute)
+	defer cancel()
+
+	ticker := time.NewTicker(commandPollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("command %s on %s did not finish: %w", commandID, instanceID, ctx.Err())
+		case <-ticker.C:
+		}
+
+		invocation, err := c.ssm.GetCommandInvocation(ctx, &ssm.GetCommandInvocationInput{
+			CommandId:  aws.String(commandID),
+			InstanceId: aws.String(instanceID),
+		})
+		if err != nil {
+			// The invocation is not visible right after the command is sent
+			var notYet *ssmtypes.InvocationDoesNotExist
+			if errors.As(err, &notYet) {
+				continue
+			}
+			c.logger.WithError(err).WithField("command_id", commandID).Error("Failed to get command invocation")
+			return nil, fmt.Errorf("failed to get command %s on %s: %w", commandID, instanceID, err)
+		}
+
+		switch invocation.Status {
+		case ssmtypes.CommandInvocationStatusPending, ssmtypes.CommandInvocationStatusInProgress,
+			ssmtypes.CommandInvocationStatusDelayed, ssmtypes.CommandInvocationStatusCancelling:
+			continue
+		}
+
+		c.logger.WithFields(logrus.Fields{
+			"command_id":  commandID,
+			"instance_id": instanceID,
+			"document":    document,
+			"status":      invocation.Status,
+			"duration":    time.Since(start),
+		}).Info("Shell command finished")
+
+		return &types.CommandResult{
+			CommandID:    commandID,
+			InstanceID:   instanceID,
+			Status:       string(invocation.Status),
+			ResponseCode: invocation.ResponseCode,
+			Output:       aws.ToString(invocation.StandardOutputContent),
+			Error:        aws.ToString(invocation.StandardErrorContent),
+		}, nil
+	}
+}
@@ -0,0 +1,204 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/configservice"
+	cfgtypes "github.com/aws/aws-sdk-go-v2/service/configservice/types"
+
+	"aws-mcp-server/pkg/types"
+
+	"github.com/sirupsen/logrus"
+)
+
+// configRuleBatch is the most rule names DescribeComplianceByConfigRule and
+// DescribeRemediationConfigurations accept per call
+const configRuleBatch = 25
+
+// ListConfigRules retrieves the AWS Config rules of the region with their
+// compliance and remediation actions
+func (c *Client) ListConfigRules(ctx context.Context) ([]types.ConfigRule, error) {
+	start := time.Now()
+
+	rules, err := c.describeConfigRules(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.addConfigCompliance(ctx, rules); err != nil {
+		return nil, err
+	}
+	c.addConfigRemediations(ctx, rules)
+
+	c.logger.WithFields(logrus.Fields{
+		"count":    len(rules),
+		"duration": time.Since(start),
+	}).Info("Retrieved Config rules")
+
+	return rules, nil
+}
+
+// GetConfigRule retrieves one rule with its compliance and remediation action
+func (c *Client) GetConfigRule(ctx context.Context, name string) (*types.ConfigRule, error) {
+	rules, err := c.describeConfigRules(ctx, []string{name})
+	if err != nil {
+		return nil, err
+	}
+	if len(rules) == 0 {
+		return nil, fmt.Errorf("config rule %s not found", name)
+	}
+	if err := c.addConfigCompliance(ctx, rules); err != nil {
+		return nil, err
+	}
+	c.addConfigRemediations(ctx, rules)
+	return &rules[0], nil
+}
+
+// ListNonCompliantResources retrieves up to limit resources a rule found non
+// compliant, with the rule's annotation
+func (c *Client) ListNonCompliantResources(ctx context.Context, ruleName string, limit int) ([]types.ConfigEvaluation, error) {
+	var evaluations []types.ConfigEvaluation
+	paginator := configservice.NewGetComplianceDetailsByConfigRulePaginator(c.configservice, &configservice.GetComplianceDetailsByConfigRuleInput{
+		ConfigRuleName:  aws.String(ruleName),
+		ComplianceTypes: []cfgtypes.ComplianceType{cfgtypes.ComplianceTypeNonCompliant},
+		Limit:           100,
+	})
+	for paginator.HasMorePages() && len(evaluations) < limit {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			c.logger.WithError(err).WithField("rule", ruleName).Error("Failed to get Config rule compliance details")
+			return nil, fmt.Errorf("failed to get non-compliant resources of %s: %w", ruleName, err)
+		}
+		for _, result := range page.EvaluationResults {
+			if len(evaluations) == limit {
+				break
+			}
+			evaluations = append(evaluations, convertConfigEvaluation(result))
+		}
+	}
+	return evaluations, nil
+}
+
+// describeConfigRules lists the rules, or only those named
+func (c *Client) describeConfigRules(ctx context.Context, names []string) ([]types.ConfigRule, error) {
+	var rules []types.ConfigRule
+	paginator := configservice.NewDescribeConfigRulesPaginator(c.configservice, &configservice.DescribeConfigRulesInput{
+		ConfigRuleNames: names,
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			c.logger.WithError(err).Error("Failed to describe Config rules")
+			return nil, fmt.Errorf("failed to describe config rules: %w", err)
+		}
+		for _, rule := range page.ConfigRules {
+			rules = append(rules, convertConfigRule(rule))
+		}
+	}
+	return rules, nil
+}
+
+// addConfigCompliance adds the compliance of each rule. Rules without
+// evaluations are left INSUFFICIENT_DATA.
+func (c *Client) addConfigCompliance(ctx context.Context, rules []types.ConfigRule) error {
+	names := make([]string, 0, len(rules))
+	for _, rule := range rules {
+		names = append(names, rule.Name)
+	}
+
+	compliance := make(map[string]*cfgtypes.Compliance, len(rules))
+	for batch := range slices.Chunk(names, configRuleBatch) {
+		paginator := configservice.NewDescribeComplianceByConfigRulePaginator(c.configservice, &configservice.DescribeComplianceByConfigRuleInput{
+			ConfigRuleNames: batch,
+		})
+		for paginator.HasMorePages() {
+			page, err := paginator.NextPage(ctx)
+			if err != nil {
+				c.logger.WithError(err).Error("Failed to describe Config rule compliance")
+				return fmt.Errorf("failed to describe config rule compliance: %w", err)
+			}
+			for _, rule := range page.ComplianceByConfigRules {
+				compliance[aws.ToString(rule.ConfigRuleName)] = rule.Compliance
+			}
+		}
+	}
+
+	for i := range rules {
+		rules[i].Compliance = string(cfgtypes.ComplianceTypeInsufficientData)
+		result := compliance[rules[i].Name]
+		if result == nil {
+			continue
+		}
+		rules[i].Compliance = string(result.ComplianceType)
+		if count := result.ComplianceContributorCount; count != nil {
+			rules[i].NonCompliantCount = int(count.CappedCount)
+			rules[i].CountCapped = count.CapExceeded
+		}
+	}
+	return nil
+}
+
+// addConfigRemediations adds the remediation action of each rule that has
+// one. Remediations are best effort: rules keep none when they cannot be read.
+func (c *Client) addConfigRemediations(ctx context.Context, rules []types.ConfigRule) {
+	index := make(map[string]int, len(rules))
+	names := make([]string, 0, len(rules))
+	for i, rule := range rules {
+		index[rule.Name] = i
+		names = append(names, rule.Name)
+	}
+
+	for batch := range slices.Chunk(names, configRuleBatch) {
+		output, err := c.configservice.DescribeRemediationConfigurations(ctx, &configservice.DescribeRemediationConfigurationsInput{
+			ConfigRuleNames: batch,
+		})
+		if err != nil {
+			c.logger.WithError(err).Warn("Failed to describe Config remediation configurations")
+			return
+		}
+		for _, remediation := range output.RemediationConfigurations {
+			if i, ok := index[aws.ToString(remediation.ConfigRuleName)]; ok {
+				rules[i].Remediation = &types.ConfigRemediation{
+					TargetType: string(remediation.TargetType),
+					TargetID:   aws.ToString(remediation.TargetId),
+					Automatic:  remediation.Automatic,
+				}
+			}
+		}
+	}
+}
+
+// convertConfigRule converts a rule without its compliance
+func convertConfigRule(rule cfgtypes.ConfigRule) types.ConfigRule {
+	converted := types.ConfigRule{
+		Name:        aws.ToString(rule.ConfigRuleName),
+		Description: aws.ToString(rule.Description),
+		State:       string(rule.ConfigRuleState),
+	}
+	if rule.Source != nil {
+		converted.Owner = string(rule.Source.Owner)
+		converted.Source = aws.ToString(rule.Source.SourceIdentifier)
+	}
+	if rule.Scope != nil {
+		converted.ResourceTypes = rule.Scope.ComplianceResourceTypes
+	}
+	return converted
+}
+
+// convertConfigEvaluation converts the evaluation of a resource
+func convertConfigEvaluation(result cfgtypes.EvaluationResult) types.ConfigEvaluation {
+	evaluation := types.ConfigEvaluation{
+		Compliance: string(result.ComplianceType),
+		Annotation: aws.ToString(result.Annotation),
+		RecordedAt: aws.ToTime(result.ResultRecordedTime),
+	}
+	if id := result.EvaluationResultIdentifier; id != nil && id.EvaluationResultQualifier != nil {
+		evaluation.RuleName = aws.ToString(id.EvaluationResultQualifier.ConfigRuleName)
+		evaluation.ResourceType = aws.ToString(id.EvaluationResultQualifier.ResourceType)
+		evaluation.ResourceID = aws.ToString(id.EvaluationResultQualifier.ResourceId)
+	}
+	return evaluation
+}
@@ -0,0 +1,48 @@
+package aws
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+)
+
+// GetConsoleOutput retrieves the serial console output of an instance with the
+// time it was captured. EC2 keeps the last 64 KB; latest asks Nitro instances
+// for the output as of now instead of the snapshot taken at the last boot,
+// stop or termination.
+func (c *Client) GetConsoleOutput(ctx context.Context, instanceID string, latest bool) (string, *time.Time, error) {
+	input := &ec2.GetConsoleOutputInput{InstanceId: aws.String(instanceID)}
+	if latest {
+		input.Latest = aws.Bool(true)
+	}
+
+	result, err := c.ec2.GetConsoleOutput(ctx, input)
+	if err != nil {
+		c.logger.WithError(err).WithField("instance_id", instanceID).Error("Failed to get console output")
+		return "", nil, fmt.Errorf("failed to get console output of %s: %w", instanceID, err)
+	}
+
+	output, err := base64.StdEncoding.DecodeString(aws.ToString(result.Output))
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to decode console output of %s: %w", instanceID, err)
+	}
+	return string(output), result.Timestamp, nil
+}
+
+// GetConsoleScreenshot captures a screenshot of the console of a running
+// instance, returned as base64-encoded JPEG data
+func (c *Client) GetConsoleScreenshot(ctx context.Context, instanceID string) (string, error) {
+	result, err := c.ec2.GetConsoleScreenshot(ctx, &ec2.GetConsoleScreenshotInput{
+		InstanceId: aws.String(instanceID),
+		WakeUp:     aws.Bool(true),
+	})
+	if err != nil {
+		c.logger.WithError(err).WithField("instance_id", instanceID).Error("Failed to get console screenshot")
+		return "", fmt.Errorf("failed to get console screenshot of %s: %w", instanceID, err)
+	}
+	return aws.ToString(result.ImageData), nil
+}
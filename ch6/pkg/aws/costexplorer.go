@@ -0,0 +1,124 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/costexplorer"
+	cetypes "github.com/aws/aws-sdk-go-v2/service/costexplorer/types"
+
+	"aws-mcp-server/pkg/types"
+
+	"github.com/sirupsen/logrus"
+)
+
+// costTagPrefix selects a cost allocation tag in CostQuery.GroupBy
+const costTagPrefix = "tag:"
+
+// CostQuery selects the cost to retrieve from Cost Explorer. End is
+// exclusive. GroupBy is a dimension such as SERVICE, LINKED_ACCOUNT or
+// REGION, or tag:<key> for a cost allocation tag; empty returns totals only.
+type CostQuery struct {
+	Start       time.Time
+	End         time.Time
+	Granularity string
+	Metric      string
+	GroupBy     string
+}
+
+// GetCostAndUsage retrieves the cost per period from Cost Explorer
+func (c *Client) GetCostAndUsage(ctx context.Context, query CostQuery) ([]types.CostPeriod, error) {
+	start := time.Now()
+
+	input := &costexplorer.GetCostAndUsageInput{
+		TimePeriod: &cetypes.DateInterval{
+			Start: aws.String(query.Start.Format(time.DateOnly)),
+			End:   aws.String(query.End.Format(time.DateOnly)),
+		},
+		Granularity: cetypes.Granularity(query.Granularity),
+		Metrics:     []string{query.Metric},
+	}
+	tagKey := ""
+	if query.GroupBy != "" {
+		group := cetypes.GroupDefinition{Type: cetypes.GroupDefinitionTypeDimension, Key: aws.String(query.GroupBy)}
+		if key, ok := strings.CutPrefix(query.GroupBy, costTagPrefix); ok {
+			tagKey = key
+			group = cetypes.GroupDefinition{Type: cetypes.GroupDefinitionTypeTag, Key: aws.String(key)}
+		}
+		input.GroupBy = []cetypes.GroupDefinition{group}
+	}
+
+	var periods []types.CostPeriod
+	for {
+		result, err := c.costexplorer.GetCostAndUsage(ctx, input)
+		if err != nil {
+			c.logger.WithError(err).WithFields(logrus.Fields{
+				"granularity": query.Granularity,
+				"groupBy":     query.GroupBy,
+			}).Error("Failed to get cost and usage")
+			return nil, fmt.Errorf("failed to get cost and usage: %w", err)
+		}
+		for _, result := range result.ResultsByTime {
+			periods = appendCostPeriod(periods, result, query.Metric, tagKey)
+		}
+		if result.NextPageToken == nil {
+			break
+		}
+		input.NextPageToken = result.NextPageToken
+	}
+
+	c.logger.WithFields(logrus.Fields{
+		"count":    len(periods),
+		"groupBy":  query.GroupBy,
+		"duration": time.Since(start),
+	}).Info("Retrieved cost and usage")
+
+	return periods, nil
+}
+
+// appendCostPeriod converts a Cost Explorer result. Later pages of a grouped
+// query continue the groups of the period they start in.
+func appendCostPeriod(periods []types.CostPeriod, result cetypes.ResultByTime, metric, tagKey string) []types.CostPeriod {
+	var period types.CostPeriod
+	if result.TimePeriod != nil {
+		period.Start, _ = time.Parse(time.DateOnly, aws.ToString(result.TimePeriod.Start))
+		period.End, _ = time.Parse(time.DateOnly, aws.ToString(result.TimePeriod.End))
+	}
+	period.Estimated = result.Estimated
+	if total, ok := result.Total[metric]; ok {
+		period.Total, _ = strconv.ParseFloat(aws.ToString(total.Amount), 64)
+		period.Unit = aws.ToString(total.Unit)
+	}
+
+	grouped := len(result.Groups) > 0
+	for _, group := range result.Groups {
+		value := group.Metrics[metric]
+		amount, _ := strconv.ParseFloat(aws.ToString(value.Amount), 64)
+		key := strings.Join(group.Keys, ",")
+		if tagKey != "" {
+			// Tag groups are returned as key$value, with an empty value for
+			// untagged cost
+			key = strings.TrimPrefix(key, tagKey+"$")
+			if key == "" {
+				key = "(untagged)"
+			}
+		}
+		period.Groups = append(period.Groups, types.CostGroup{Key: key, Amount: amount})
+		period.Total += amount
+		if period.Unit == "" {
+			period.Unit = aws.ToString(value.Unit)
+		}
+	}
+
+	if grouped && len(periods) > 0 && periods[len(periods)-1].Start.Equal(period.Start) {
+		last := &periods[len(periods)-1]
+		last.Groups = append(last.Groups, period.Groups...)
+		last.Total += period.Total
+		return periods
+	}
+	return append(periods, period)
+}
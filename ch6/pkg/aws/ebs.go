@@ -0,0 +1,546 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+
+	"aws-mcp-server/pkg/types"
+
+	"github.com/sirupsen/logrus"
+)
+
+// encryptionWaitTimeout bounds each wait step of the volume encryption workflow
+const encryptionWaitTimeout = 30 * time.Minute
+
+// EncryptVolumeParams describes an unencrypted volume to replace with an encrypted copy
+type EncryptVolumeParams struct {
+	VolumeID string
+	KMSKeyID string
+}
+
+// EncryptVolumeResult reports the artifacts created while encrypting a volume
+type EncryptVolumeResult struct {
+	OriginalVolumeID    string `json:"originalVolumeId"`
+	NewVolumeID         string `json:"newVolumeId"`
+	SourceSnapshotID    string `json:"sourceSnapshotId"`
+	EncryptedSnapshotID string `json:"encryptedSnapshotId"`
+	InstanceID          string `json:"instanceId,omitempty"`
+	Device              string `json:"device,omitempty"`
+}
+
+// ModifyVolumeParams describes the new size and performance of a volume. Zero
+// values keep the current setting.
+type ModifyVolumeParams struct {
+	VolumeID   string
+	SizeGiB    int32
+	VolumeType string
+	IOPS       int32
+	Throughput int32
+}
+
+// VolumeModificationResult reports a volume modification in progress
+type VolumeModificationResult struct {
+	VolumeID           string `json:"volumeId"`
+	State              string `json:"state"`
+	OriginalSizeGiB    int32  `json:"originalSizeGiB"`
+	TargetSizeGiB      int32  `json:"targetSizeGiB"`
+	OriginalVolumeType string `json:"originalVolumeType"`
+	TargetVolumeType   string `json:"targetVolumeType"`
+	TargetIOPS         int32  `json:"targetIops,omitempty"`
+	TargetThroughput   int32  `json:"targetThroughput,omitempty"`
+}
+
+// ListEBSVolumes retrieves all EBS volumes in the region
+func (c *Client) ListEBSVolumes(ctx context.Context) ([]types.CloudResource, error) {
+	start := time.Now()
+
+	var resources []types.CloudResource
+	paginator := ec2.NewDescribeVolumesPaginator(c.ec2, &ec2.DescribeVolumesInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			c.logger.WithError(err).Error("Failed to describe EBS volumes")
+			return nil, fmt.Errorf("failed to describe volumes: %w", err)
+		}
+
+		for _, volume := range page.Volumes {
+			resources = append(resources, c.convertEBSVolume(volume))
+		}
+	}
+
+	c.logger.WithFields(logrus.Fields{
+		"count":    len(resources),
+		"duration": time.Since(start),
+	}).Info("Retrieved EBS volumes")
+
+	return resources, nil
+}
+
+// GetEBSVolume retrieves a specific EBS volume
+func (c *Client) GetEBSVolume(ctx context.Context, volumeID string) (*types.CloudResource, error) {
+	result, err := c.ec2.DescribeVolumes(ctx, &ec2.DescribeVolumesInput{
+		VolumeIds: []string{volumeID},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe volume %s: %w", volumeID, err)
+	}
+
+	if len(result.Volumes) == 0 {
+		return nil, fmt.Errorf("volume %s not found", volumeID)
+	}
+
+	resource := c.convertEBSVolume(result.Volumes[0])
+	return &resource, nil
+}
+
+// ListEBSSnapshots retrieves all EBS snapshots owned by the current account
+func (c *Client) ListEBSSnapshots(ctx context.Context) ([]types.CloudResource, error) {
+	start := time.Now()
+
+	var resources []types.CloudResource
+	paginator := ec2.NewDescribeSnapshotsPaginator(c.ec2, &ec2.DescribeSnapshotsInput{
+		OwnerIds: []string{"self"},
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			c.logger.WithError(err).Error("Failed to describe EBS snapshots")
+			return nil, fmt.Errorf("failed to describe snapshots: %w", err)
+		}
+
+		for _, snapshot := range page.Snapshots {
+			resources = append(resources, c.convertEBSSnapshot(snapshot))
+		}
+	}
+
+	c.logger.WithFields(logrus.Fields{
+		"count":    len(resources),
+		"duration": time.Since(start),
+	}).Info("Retrieved EBS snapshots")
+
+	return resources, nil
+}
+
+// GetEBSSnapshot retrieves a specific EBS snapshot
+func (c *Client) GetEBSSnapshot(ctx context.Context, snapshotID string) (*types.CloudResource, error) {
+	result, err := c.ec2.DescribeSnapshots(ctx, &ec2.DescribeSnapshotsInput{
+		SnapshotIds: []string{snapshotID},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe snapshot %s: %w", snapshotID, err)
+	}
+
+	if len(result.Snapshots) == 0 {
+		return nil, fmt.Errorf("snapshot %s not found", snapshotID)
+	}
+
+	resource := c.convertEBSSnapshot(result.Snapshots[0])
+	return &resource, nil
+}
+
+// CreateEBSSnapshot starts a snapshot of a volume and returns it in the pending state.
+// The volume's tags are copied to the snapshot, except reserved aws: tags.
+func (c *Client) CreateEBSSnapshot(ctx context.Context, volumeID, description string) (*types.CloudResource, error) {
+	logger := c.logger.WithField("volumeId", volumeID)
+	logger.Info("Creating EBS snapshot")
+
+	input := &ec2.CreateSnapshotInput{
+		VolumeId:    aws.String(volumeID),
+		Description: aws.String(description),
+	}
+
+	volume, err := c.GetEBSVolume(ctx, volumeID)
+	if err != nil {
+		return nil, err
+	}
+	tags := make([]ec2types.Tag, 0, len(volume.Tags))
+	for key, value := range volume.Tags {
+		if strings.HasPrefix(key, "aws:") {
+			continue
+		}
+		tags = append(tags, ec2types.Tag{Key: aws.String(key), Value: aws.String(value)})
+	}
+	if len(tags) > 0 {
+		input.TagSpecifications = []ec2types.TagSpecification{{
+			ResourceType: ec2types.ResourceTypeSnapshot,
+			Tags:         tags,
+		}}
+	}
+
+	result, err := c.ec2.CreateSnapshot(ctx, input)
+	if err != nil {
+		logger.WithError(err).Error("Failed to create EBS snapshot")
+		return nil, fmt.Errorf("failed to snapshot volume %s: %w", volumeID, err)
+	}
+
+	snapshot := c.convertEBSSnapshot(ec2types.Snapshot{
+		SnapshotId:  result.SnapshotId,
+		VolumeId:    result.VolumeId,
+		VolumeSize:  result.VolumeSize,
+		Encrypted:   result.Encrypted,
+		StartTime:   result.StartTime,
+		Progress:    result.Progress,
+		StorageTier: result.StorageTier,
+		Description: result.Description,
+		State:       result.State,
+		Tags:        result.Tags,
+	})
+
+	logger.WithField("snapshotId", snapshot.ID).Info("EBS snapshot creation initiated")
+	return &snapshot, nil
+}
+
+// DeleteEBSSnapshot deletes an EBS snapshot. Snapshots used by an AMI cannot be deleted.
+func (c *Client) DeleteEBSSnapshot(ctx context.Context, snapshotID string) error {
+	logger := c.logger.WithField("snapshotId", snapshotID)
+	logger.Info("Deleting EBS snapshot")
+
+	_, err := c.ec2.DeleteSnapshot(ctx, &ec2.DeleteSnapshotInput{
+		SnapshotId: aws.String(snapshotID),
+	})
+	if err != nil {
+		logger.WithError(err).Error("Failed to delete EBS snapshot")
+		return fmt.Errorf("failed to delete snapshot %s: %w", snapshotID, err)
+	}
+
+	logger.Info("EBS snapshot deleted")
+	return nil
+}
+
+// ModifyEBSVolume changes the size, type or performance of a volume while it
+// stays in use. AWS allows one modification per volume every six hours, and a
+// grown volume still needs its file system extended on the instance.
+func (c *Client) ModifyEBSVolume(ctx context.Context, params ModifyVolumeParams) (*VolumeModificationResult, error) {
+	logger := c.logger.WithFields(logrus.Fields{
+		"volumeId":   params.VolumeID,
+		"sizeGiB":    params.SizeGiB,
+		"volumeType": params.VolumeType,
+		"iops":       params.IOPS,
+		"throughput": params.Throughput,
+	})
+	logger.Info("Modifying EBS volume")
+
+	input := &ec2.ModifyVolumeInput{
+		VolumeId: aws.String(params.VolumeID),
+	}
+	if params.SizeGiB > 0 {
+		input.Size = aws.Int32(params.SizeGiB)
+	}
+	if params.VolumeType != "" {
+		input.VolumeType = ec2types.VolumeType(params.VolumeType)
+	}
+	if params.IOPS > 0 {
+		input.Iops = aws.Int32(params.IOPS)
+	}
+	if params.Throughput > 0 {
+		input.Throughput = aws.Int32(params.Throughput)
+	}
+
+	result, err := c.ec2.ModifyVolume(ctx, input)
+	if err != nil {
+		logger.WithError(err).Error("Failed to modify EBS volume")
+		return nil, fmt.Errorf("failed to modify volume %s: %w", params.VolumeID, err)
+	}
+
+	modification := &VolumeModificationResult{VolumeID: params.VolumeID}
+	if result.VolumeModification != nil {
+		m := result.VolumeModification
+		modification.State = string(m.ModificationState)
+		modification.OriginalSizeGiB = aws.ToInt32(m.OriginalSize)
+		modification.TargetSizeGiB = aws.ToInt32(m.TargetSize)
+		modification.OriginalVolumeType = string(m.OriginalVolumeType)
+		modification.TargetVolumeType = string(m.TargetVolumeType)
+		modification.TargetIOPS = aws.ToInt32(m.TargetIops)
+		modification.TargetThroughput = aws.ToInt32(m.TargetThroughput)
+	}
+
+	logger.Info("EBS volume modification initiated")
+	return modification, nil
+}
+
+// EncryptEBSVolume replaces an unencrypted volume with an encrypted copy.
+// The volume is snapshotted, the snapshot is copied with encryption enabled and a new
+// volume is created from it. If the original volume is attached, the owning instance must
+// be stopped; the original is detached and the new volume attached on the same device.
+// If the new volume fails to attach, the original is attached again and
+// the error reports both. The original volume and snapshots are kept so the
+// change can be rolled back.
+func (c *Client) EncryptEBSVolume(ctx context.Context, params EncryptVolumeParams) (*EncryptVolumeResult, error) {
+	logger := c.logger.WithField("volumeId", params.VolumeID)
+	logger.Info("Encrypting EBS volume")
+
+	volumeResult, err := c.ec2.DescribeVolumes(ctx, &ec2.DescribeVolumesInput{
+		VolumeIds: []string{params.VolumeID},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe volume %s: %w", params.VolumeID, err)
+	}
+	if len(volumeResult.Volumes) == 0 {
+		return nil, fmt.Errorf("volume %s not found", params.VolumeID)
+	}
+
+	volume := volumeResult.Volumes[0]
+	if aws.ToBool(volume.Encrypted) {
+		return nil, fmt.Errorf("volume %s is already encrypted", params.VolumeID)
+	}
+
+	result := &EncryptVolumeResult{OriginalVolumeID: params.VolumeID}
+
+	if len(volume.Attachments) > 0 {
+		attachment := volume.Attachments[0]
+		result.InstanceID = aws.ToString(attachment.InstanceId)
+		result.Device = aws.ToString(attachment.Device)
+
+		instance, err := c.GetEC2Instance(ctx, result.InstanceID)
+		if err != nil {
+			return nil, err
+		}
+		if instance.State != string(ec2types.InstanceStateNameStopped) {
+			return nil, fmt.Errorf("instance %s must be stopped before volume %s can be swapped (current state: %s)",
+				result.InstanceID, params.VolumeID, instance.State)
+		}
+	}
+
+	// Snapshot the original volume
+	snapshot, err := c.ec2.CreateSnapshot(ctx, &ec2.CreateSnapshotInput{
+		VolumeId:    aws.String(params.VolumeID),
+		Description: aws.String(fmt.Sprintf("Pre-encryption snapshot of %s", params.VolumeID)),
+	})
+	if err != nil {
+		logger.WithError(err).Error("Failed to snapshot volume")
+		return nil, fmt.Errorf("failed to snapshot volume %s: %w", params.VolumeID, err)
+	}
+	result.SourceSnapshotID = aws.ToString(snapshot.SnapshotId)
+
+	if err := c.waitForSnapshot(ctx, result.SourceSnapshotID); err != nil {
+		return result, err
+	}
+
+	// Copy the snapshot with encryption enabled
+	copyInput := &ec2.CopySnapshotInput{
+		SourceSnapshotId: aws.String(result.SourceSnapshotID),
+		SourceRegion:     aws.String(c.cfg.Region),
+		Encrypted:        aws.Bool(true),
+		Description:      aws.String(fmt.Sprintf("Encrypted copy of %s", result.SourceSnapshotID)),
+	}
+	if params.KMSKeyID != "" {
+		copyInput.KmsKeyId = aws.String(params.KMSKeyID)
+	}
+
+	encryptedSnapshot, err := c.ec2.CopySnapshot(ctx, copyInput)
+	if err != nil {
+		logger.WithError(err).Error("Failed to copy snapshot with encryption")
+		return result, fmt.Errorf("failed to copy snapshot %s with encryption: %w", result.SourceSnapshotID, err)
+	}
+	result.EncryptedSnapshotID = aws.ToString(encryptedSnapshot.SnapshotId)
+
+	if err := c.waitForSnapshot(ctx, result.EncryptedSnapshotID); err != nil {
+		return result, err
+	}
+
+	// Create the encrypted replacement volume with the same shape and tags
+	tags := append([]ec2types.Tag{{Key: aws.String("EncryptedFrom"), Value: aws.String(params.VolumeID)}}, volume.Tags...)
+
+	createInput := &ec2.CreateVolumeInput{
+		AvailabilityZone: volume.AvailabilityZone,
+		SnapshotId:       aws.String(result.EncryptedSnapshotID),
+		VolumeType:       volume.VolumeType,
+		TagSpecifications: []ec2types.TagSpecification{{
+			ResourceType: ec2types.ResourceTypeVolume,
+			Tags:         tags,
+		}},
+	}
+	switch volume.VolumeType {
+	case ec2types.VolumeTypeIo1, ec2types.VolumeTypeIo2:
+		createInput.Iops = volume.Iops
+	case ec2types.VolumeTypeGp3:
+		createInput.Iops = volume.Iops
+		createInput.Throughput = volume.Throughput
+	}
+
+	newVolume, err := c.ec2.CreateVolume(ctx, createInput)
+	if err != nil {
+		logger.WithError(err).Error("Failed to create encrypted volume")
+		return result, fmt.Errorf("failed to create encrypted volume: %w", err)
+	}
+	result.NewVolumeID = aws.ToString(newVolume.VolumeId)
+
+	if err := c.waitForVolumeAvailable(ctx, result.NewVolumeID); err != nil {
+		return result, err
+	}
+
+	// Swap the volumes on the stopped instance
+	if result.InstanceID != "" {
+		if _, err := c.ec2.DetachVolume(ctx, &ec2.DetachVolumeInput{
+			VolumeId:   aws.String(params.VolumeID),
+			InstanceId: aws.String(result.InstanceID),
+		}); err != nil {
+			logger.WithError(err).Error("Failed to detach original volume")
+			return result, fmt.Errorf("failed to detach volume %s: %w", params.VolumeID, err)
+		}
+
+		if err := c.attachEncryptedVolume(ctx, params.VolumeID, result); err != nil {
+			logger.WithError(err).Error("Failed to attach encrypted volume, re-attaching the original")
+			// The tool's context may be what ran out, so the rollback gets its own
+			if rollbackErr := c.reattachVolume(context.WithoutCancel(ctx), params.VolumeID, result); rollbackErr != nil {
+				logger.WithError(rollbackErr).Error("Failed to re-attach original volume")
+				return result, fmt.Errorf("%w; re-attaching volume %s to instance %s also failed, the instance has no volume on %s: %w",
+					err, params.VolumeID, result.InstanceID, result.Device, rollbackErr)
+			}
+			return result, fmt.Errorf("%w; volume %s was re-attached to instance %s", err, params.VolumeID, result.InstanceID)
+		}
+	}
+
+	logger.WithField("newVolumeId", result.NewVolumeID).Info("EBS volume encrypted successfully")
+	return result, nil
+}
+
+// attachEncryptedVolume attaches the new volume of result in place of the
+// detached original
+func (c *Client) attachEncryptedVolume(ctx context.Context, originalID string, result *EncryptVolumeResult) error {
+	if err := c.waitForVolumeAvailable(ctx, originalID); err != nil {
+		return err
+	}
+
+	if _, err := c.ec2.AttachVolume(ctx, &ec2.AttachVolumeInput{
+		VolumeId:   aws.String(result.NewVolumeID),
+		InstanceId: aws.String(result.InstanceID),
+		Device:     aws.String(result.Device),
+	}); err != nil {
+		return fmt.Errorf("failed to attach volume %s: %w", result.NewVolumeID, err)
+	}
+
+	waiter := ec2.NewVolumeInUseWaiter(c.ec2)
+	if err := waiter.Wait(ctx, &ec2.DescribeVolumesInput{VolumeIds: []string{result.NewVolumeID}}, encryptionWaitTimeout); err != nil {
+		return fmt.Errorf("volume %s did not attach: %w", result.NewVolumeID, err)
+	}
+	return nil
+}
+
+// reattachVolume puts the original volume back on the instance after the
+// encrypted one failed to attach, detaching the encrypted volume first if
+// it got attached after all
+func (c *Client) reattachVolume(ctx context.Context, originalID string, result *EncryptVolumeResult) error {
+	ctx, cancel := context.WithTimeout(ctx, encryptionWaitTimeout)
+	defer cancel()
+
+	newVolume, err := c.ec2.DescribeVolumes(ctx, &ec2.DescribeVolumesInput{VolumeIds: []string{result.NewVolumeID}})
+	if err != nil {
+		return fmt.Errorf("failed to describe volume %s: %w", result.NewVolumeID, err)
+	}
+	if len(newVolume.Volumes) > 0 && len(newVolume.Volumes[0].Attachments) > 0 {
+		if _, err := c.ec2.DetachVolume(ctx, &ec2.DetachVolumeInput{
+			VolumeId:   aws.String(result.NewVolumeID),
+			InstanceId: aws.String(result.InstanceID),
+		}); err != nil {
+			return fmt.Errorf("failed to detach volume %s: %w", result.NewVolumeID, err)
+		}
+		if err := c.waitForVolumeAvailable(ctx, result.NewVolumeID); err != nil {
+			return err
+		}
+	}
+
+	if err := c.waitForVolumeAvailable(ctx, originalID); err != nil {
+		return err
+	}
+	if _, err := c.ec2.AttachVolume(ctx, &ec2.AttachVolumeInput{
+		VolumeId:   aws.String(originalID),
+		InstanceId: aws.String(result.InstanceID),
+		Device:     aws.String(result.Device),
+	}); err != nil {
+		return fmt.Errorf("failed to attach volume %s: %w", originalID, err)
+	}
+
+	waiter := ec2.NewVolumeInUseWaiter(c.ec2)
+	if err := waiter.Wait(ctx, &ec2.DescribeVolumesInput{VolumeIds: []string{originalID}}, encryptionWaitTimeout); err != nil {
+		return fmt.Errorf("volume %s did not attach: %w", originalID, err)
+	}
+	return nil
+}
+
+// waitForSnapshot blocks until a snapshot has completed
+func (c *Client) waitForSnapshot(ctx context.Context, snapshotID string) error {
+	waiter := ec2.NewSnapshotCompletedWaiter(c.ec2)
+	if err := waiter.Wait(ctx, &ec2.DescribeSnapshotsInput{SnapshotIds: []string{snapshotID}}, encryptionWaitTimeout); err != nil {
+		return fmt.Errorf("snapshot %s did not complete: %w", snapshotID, err)
+	}
+	return nil
+}
+
+// waitForVolumeAvailable blocks until a volume is in the available state
+func (c *Client) waitForVolumeAvailable(ctx context.Context, volumeID string) error {
+	waiter := ec2.NewVolumeAvailableWaiter(c.ec2)
+	if err := waiter.Wait(ctx, &ec2.DescribeVolumesInput{VolumeIds: []string{volumeID}}, encryptionWaitTimeout); err != nil {
+		return fmt.Errorf("volume %s did not become available: %w", volumeID, err)
+	}
+	return nil
+}
+
+// convertEBSVolume converts an AWS EBS volume to our standard format
+func (c *Client) convertEBSVolume(volume ec2types.Volume) types.CloudResource {
+	details := &types.EBSVolumeDetails{
+		VolumeType:       string(volume.VolumeType),
+		SizeGiB:          aws.ToInt32(volume.Size),
+		Encrypted:        aws.ToBool(volume.Encrypted),
+		AvailabilityZone: aws.ToString(volume.AvailabilityZone),
+		CreateTime:       volume.CreateTime,
+		MultiAttach:      aws.ToBool(volume.MultiAttachEnabled),
+		IOPS:             aws.ToInt32(volume.Iops),
+		Throughput:       aws.ToInt32(volume.Throughput),
+		KmsKeyID:         aws.ToString(volume.KmsKeyId),
+		SnapshotID:       aws.ToString(volume.SnapshotId),
+		Attachments:      make([]types.EBSAttachment, 0, len(volume.Attachments)),
+	}
+
+	for _, attachment := range volume.Attachments {
+		details.Attachments = append(details.Attachments, types.EBSAttachment{
+			InstanceID:          aws.ToString(attachment.InstanceId),
+			Device:              aws.ToString(attachment.Device),
+			State:               string(attachment.State),
+			DeleteOnTermination: aws.ToBool(attachment.DeleteOnTermination),
+			AttachTime:          attachment.AttachTime,
+		})
+	}
+
+	return types.CloudResource{
+		ID:       aws.ToString(volume.VolumeId),
+		Provider: ProviderName,
+		Type:     "ebs-volume",
+		Region:   c.cfg.Region,
+		State:    string(volume.State),
+		Tags:     convertTags(volume.Tags),
+		Details:  details,
+		LastSeen: time.Now(),
+	}
+}
+
+// convertEBSSnapshot converts an AWS EBS snapshot to our standard format
+func (c *Client) convertEBSSnapshot(snapshot ec2types.Snapshot) types.CloudResource {
+	details := &types.EBSSnapshotDetails{
+		VolumeID:       aws.ToString(snapshot.VolumeId),
+		SizeGiB:        aws.ToInt32(snapshot.VolumeSize),
+		Encrypted:      aws.ToBool(snapshot.Encrypted),
+		StartTime:      snapshot.StartTime,
+		Progress:       aws.ToString(snapshot.Progress),
+		StorageTier:    string(snapshot.StorageTier),
+		Description:    aws.ToString(snapshot.Description),
+		CompletionTime: snapshot.CompletionTime,
+	}
+
+	return types.CloudResource{
+		ID:       aws.ToString(snapshot.SnapshotId),
+		Provider: ProviderName,
+		Type:     "ebs-snapshot",
+		Region:   c.cfg.Region,
+		State:    string(snapshot.State),
+		Tags:     convertTags(snapshot.Tags),
+		Details:  details,
+		LastSeen: time.Now(),
+	}
+}
@@ -0,0 +1,159 @@
+package aws
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"aws-mcp-server/internal/config"
+	"aws-mcp-server/internal/logging"
+	"aws-mcp-server/pkg/fake"
+	"aws-mcp-server/pkg/types"
+)
+
+// failingHook aborts the calls fail returns an error for
+type failingHook struct {
+	fail func(call Call) error
+}
+
+func (h failingHook) OnRequest(ctx context.Context, call Call) (context.Context, error) {
+	return ctx, h.fail(call)
+}
+
+func (h failingHook) OnResponse(ctx context.Context, call Call, output interface{}, duration time.Duration) {
+}
+
+func (h failingHook) OnError(ctx context.Context, call Call, err error, duration time.Duration) {}
+
+// newFleetClient returns a client of a fake fleet and the unencrypted root
+// volume of one of its stopped instances
+func newFleetClient(t *testing.T) (*Client, types.CloudResource) {
+	t.Helper()
+	fleet := fake.New(config.FakeConfig{Seed: 7}, "us-east-1")
+	c := NewClientFromConfig(fleet.Config(), logging.NewLogger("error", "text"))
+
+	volumes, err := c.ListEBSVolumes(context.Background())
+	require.NoError(t, err)
+	for _, volume := range volumes {
+		details := volume.EBSVolume()
+		if details.Encrypted || len(details.Attachments) == 0 {
+			continue
+		}
+		instance, err := c.GetEC2Instance(context.Background(), details.Attachments[0].InstanceID)
+		require.NoError(t, err)
+		if instance.State == "stopped" {
+			return c, volume
+		}
+	}
+	t.Fatal("the fleet has no unencrypted volume on a stopped instance")
+	return nil, types.CloudResource{}
+}
+
+// attachmentOf returns the instance and device a volume is attached on
+func attachmentOf(t *testing.T, c *Client, volumeID string) (string, string) {
+	t.Helper()
+	volume, err := c.GetEBSVolume(context.Background(), volumeID)
+	require.NoError(t, err)
+	details := volume.EBSVolume()
+	if len(details.Attachments) == 0 {
+		return "", ""
+	}
+	return details.Attachments[0].InstanceID, details.Attachments[0].Device
+}
+
+func TestEncryptEBSVolumeSwapsVolumes(t *testing.T) {
+	c, original := newFleetClient(t)
+	instanceID, device := attachmentOf(t, c, original.ID)
+
+	result, err := c.EncryptEBSVolume(context.Background(), EncryptVolumeParams{VolumeID: original.ID})
+	require.NoError(t, err)
+	assert.Equal(t, instanceID, result.InstanceID)
+	assert.Equal(t, device, result.Device)
+	assert.NotEmpty(t, result.SourceSnapshotID)
+	assert.NotEmpty(t, result.EncryptedSnapshotID)
+
+	replacement, err := c.GetEBSVolume(context.Background(), result.NewVolumeID)
+	require.NoError(t, err)
+	assert.True(t, replacement.EBSVolume().Encrypted)
+	assert.Equal(t, original.ID, replacement.Tags["EncryptedFrom"])
+	gotInstance, gotDevice := attachmentOf(t, c, result.NewVolumeID)
+	assert.Equal(t, instanceID, gotInstance)
+	assert.Equal(t, device, gotDevice)
+
+	gotInstance, _ = attachmentOf(t, c, original.ID)
+	assert.Empty(t, gotInstance, "the original is kept detached for rollback")
+
+	_, err = c.EncryptEBSVolume(context.Background(), EncryptVolumeParams{VolumeID: result.NewVolumeID})
+	assert.ErrorContains(t, err, "already encrypted")
+}
+
+func TestEncryptEBSVolumeReattachesOriginalWhenAttachFails(t *testing.T) {
+	c, original := newFleetClient(t)
+	instanceID, device := attachmentOf(t, c, original.ID)
+	c.AddHook(failingHook{fail: func(call Call) error {
+		if input, ok := call.Input.(*ec2.AttachVolumeInput); ok && aws.ToString(input.VolumeId) != original.ID {
+			return errors.New("attachment limit exceeded")
+		}
+		return nil
+	}})
+
+	result, err := c.EncryptEBSVolume(context.Background(), EncryptVolumeParams{VolumeID: original.ID})
+	require.Error(t, err)
+	assert.ErrorContains(t, err, "attachment limit exceeded")
+	assert.ErrorContains(t, err, "volume "+original.ID+" was re-attached")
+	assert.NotEmpty(t, result.NewVolumeID, "the artifacts created so far are reported")
+
+	gotInstance, gotDevice := attachmentOf(t, c, original.ID)
+	assert.Equal(t, instanceID, gotInstance)
+	assert.Equal(t, device, gotDevice)
+	gotInstance, _ = attachmentOf(t, c, result.NewVolumeID)
+	assert.Empty(t, gotInstance)
+}
+
+func TestEncryptEBSVolumeReportsFailedRollback(t *testing.T) {
+	c, original := newFleetClient(t)
+	c.AddHook(failingHook{fail: func(call Call) error {
+		if input, ok := call.Input.(*ec2.AttachVolumeInput); ok {
+			return errors.New("attach refused for " + aws.ToString(input.VolumeId))
+		}
+		return nil
+	}})
+
+	result, err := c.EncryptEBSVolume(context.Background(), EncryptVolumeParams{VolumeID: original.ID})
+	require.Error(t, err)
+	assert.ErrorContains(t, err, "attach refused for "+result.NewVolumeID)
+	assert.ErrorContains(t, err, "re-attaching volume "+original.ID)
+	assert.ErrorContains(t, err, "attach refused for "+original.ID, "both errors are reported")
+}
+
+func TestEncryptEBSVolumeRequiresStoppedInstance(t *testing.T) {
+	fleet := fake.New(config.FakeConfig{Seed: 7}, "us-east-1")
+	c := NewClientFromConfig(fleet.Config(), logging.NewLogger("error", "text"))
+
+	volumes, err := c.ListEBSVolumes(context.Background())
+	require.NoError(t, err)
+	for _, volume := range volumes {
+		details := volume.EBSVolume()
+		if details.Encrypted {
+			continue
+		}
+		instance, err := c.GetEC2Instance(context.Background(), details.Attachments[0].InstanceID)
+		require.NoError(t, err)
+		if instance.State != "running" {
+			continue
+		}
+
+		_, err = c.EncryptEBSVolume(context.Background(), EncryptVolumeParams{VolumeID: volume.ID})
+		assert.ErrorContains(t, err, "must be stopped")
+		gotInstance, _ := attachmentOf(t, c, volume.ID)
+		assert.Equal(t, instance.ID, gotInstance, "nothing is detached")
+		return
+	}
+	t.Fatal("the fleet has no unencrypted volume on a running instance")
+}
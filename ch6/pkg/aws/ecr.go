@@ -0,0 +1,174 @@
+package aws
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ecr"
+	ecrtypes "github.com/aws/aws-sdk-go-v2/service/ecr/types"
+
+	"aws-mcp-server/pkg/types"
+
+	"github.com/sirupsen/logrus"
+)
+
+// ListECRRepositories retrieves all ECR repositories of the account's registry
+func (c *Client) ListECRRepositories(ctx context.Context) ([]types.ECRRepository, error) {
+	start := time.Now()
+
+	var repositories []types.ECRRepository
+	paginator := ecr.NewDescribeRepositoriesPaginator(c.ecr, &ecr.DescribeRepositoriesInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			c.logger.WithError(err).Error("Failed to describe ECR repositories")
+			return nil, fmt.Errorf("failed to describe repositories: %w", err)
+		}
+
+		for _, repository := range page.Repositories {
+			converted := types.ECRRepository{
+				Name:          aws.ToString(repository.RepositoryName),
+				ARN:           aws.ToString(repository.RepositoryArn),
+				URI:           aws.ToString(repository.RepositoryUri),
+				CreatedAt:     aws.ToTime(repository.CreatedAt),
+				TagMutability: string(repository.ImageTagMutability),
+			}
+			if repository.ImageScanningConfiguration != nil {
+				converted.ScanOnPush = repository.ImageScanningConfiguration.ScanOnPush
+			}
+			if repository.EncryptionConfiguration != nil {
+				converted.EncryptionType = string(repository.EncryptionConfiguration.EncryptionType)
+			}
+			repositories = append(repositories, converted)
+		}
+	}
+
+	c.logger.WithFields(logrus.Fields{
+		"count":    len(repositories),
+		"duration": time.Since(start),
+	}).Info("Retrieved ECR repositories")
+
+	return repositories, nil
+}
+
+// ListECRImages retrieves the images of a repository with their scan summaries
+func (c *Client) ListECRImages(ctx context.Context, repository string) ([]types.ECRImage, error) {
+	start := time.Now()
+
+	var images []types.ECRImage
+	paginator := ecr.NewDescribeImagesPaginator(c.ecr, &ecr.DescribeImagesInput{
+		RepositoryName: aws.String(repository),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			c.logger.WithError(err).WithField("repository", repository).Error("Failed to describe ECR images")
+			return nil, fmt.Errorf("failed to describe images of %s: %w", repository, err)
+		}
+
+		for _, image := range page.ImageDetails {
+			converted := types.ECRImage{
+				Digest:       aws.ToString(image.ImageDigest),
+				Tags:         image.ImageTags,
+				PushedAt:     aws.ToTime(image.ImagePushedAt),
+				LastPulledAt: image.LastRecordedPullTime,
+				SizeBytes:    aws.ToInt64(image.ImageSizeInBytes),
+			}
+			if image.ImageScanStatus != nil {
+				converted.ScanStatus = string(image.ImageScanStatus.Status)
+			}
+			if summary := image.ImageScanFindingsSummary; summary != nil {
+				converted.ScannedAt = summary.ImageScanCompletedAt
+				converted.FindingCounts = summary.FindingSeverityCounts
+			}
+			images = append(images, converted)
+		}
+	}
+
+	c.logger.WithFields(logrus.Fields{
+		"repository": repository,
+		"count":      len(images),
+		"duration":   time.Since(start),
+	}).Info("Retrieved ECR images")
+
+	return images, nil
+}
+
+// GetECRScanFindings retrieves the findings of the latest scan of an image,
+// referenced by tag or by sha256: digest. Both basic and enhanced (Amazon
+// Inspector) scanning findings are returned.
+func (c *Client) GetECRScanFindings(ctx context.Context, repository, reference string) (*types.ECRScanFindings, error) {
+	imageID := &ecrtypes.ImageIdentifier{ImageTag: aws.String(reference)}
+	if strings.HasPrefix(reference, "sha256:") {
+		imageID = &ecrtypes.ImageIdentifier{ImageDigest: aws.String(reference)}
+	}
+
+	result := &types.ECRScanFindings{Findings: []types.ECRScanFinding{}}
+	paginator := ecr.NewDescribeImageScanFindingsPaginator(c.ecr, &ecr.DescribeImageScanFindingsInput{
+		RepositoryName: aws.String(repository),
+		ImageId:        imageID,
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			c.logger.WithError(err).WithField("repository", repository).Error("Failed to describe ECR image scan findings")
+			return nil, fmt.Errorf("failed to describe scan findings of %s:%s: %w", repository, reference, err)
+		}
+
+		if page.ImageId != nil {
+			result.Digest = aws.ToString(page.ImageId.ImageDigest)
+		}
+		if page.ImageScanStatus != nil {
+			result.ScanStatus = string(page.ImageScanStatus.Status)
+		}
+		findings := page.ImageScanFindings
+		if findings == nil {
+			continue
+		}
+		result.ScannedAt = findings.ImageScanCompletedAt
+		result.FindingCounts = findings.FindingSeverityCounts
+
+		for _, finding := range findings.Findings {
+			converted := types.ECRScanFinding{
+				Name:        aws.ToString(finding.Name),
+				Severity:    string(finding.Severity),
+				Description: aws.ToString(finding.Description),
+				URI:         aws.ToString(finding.Uri),
+			}
+			for _, attribute := range finding.Attributes {
+				switch aws.ToString(attribute.Key) {
+				case "package_name":
+					converted.Package = aws.ToString(attribute.Value)
+				case "package_version":
+					converted.PackageVersion = aws.ToString(attribute.Value)
+				}
+			}
+			result.Findings = append(result.Findings, converted)
+		}
+
+		for _, finding := range findings.EnhancedFindings {
+			converted := types.ECRScanFinding{
+				Name:        aws.ToString(finding.Title),
+				Severity:    aws.ToString(finding.Severity),
+				Description: aws.ToString(finding.Description),
+			}
+			if details := finding.PackageVulnerabilityDetails; details != nil {
+				converted.Name = cmp.Or(aws.ToString(details.VulnerabilityId), converted.Name)
+				converted.URI = aws.ToString(details.SourceUrl)
+				if len(details.VulnerablePackages) > 0 {
+					pkg := details.VulnerablePackages[0]
+					converted.Package = aws.ToString(pkg.Name)
+					converted.PackageVersion = aws.ToString(pkg.Version)
+					converted.FixedIn = aws.ToString(pkg.FixedInVersion)
+				}
+			}
+			result.Findings = append(result.Findings, converted)
+		}
+	}
+
+	return result, nil
+}
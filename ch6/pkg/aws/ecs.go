@@ -0,0 +1,312 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ecs"
+	ecstypes "github.com/aws/aws-sdk-go-v2/service/ecs/types"
+
+	"aws-mcp-server/pkg/types"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// ecsDescribeClustersBatch is the most clusters DescribeClusters accepts
+	ecsDescribeClustersBatch = 100
+	// ecsDescribeServicesBatch is the most services DescribeServices accepts
+	ecsDescribeServicesBatch = 10
+	// ecsDescribeTasksBatch is the most tasks DescribeTasks accepts
+	ecsDescribeTasksBatch = 100
+	// ecsServiceEvents is how many of the latest scheduler events are kept per service
+	ecsServiceEvents = 5
+)
+
+// ListECSClusters retrieves all ECS clusters with their service and task counts
+func (c *Client) ListECSClusters(ctx context.Context) ([]types.ECSCluster, error) {
+	start := time.Now()
+
+	var arns []string
+	paginator := ecs.NewListClustersPaginator(c.ecs, &ecs.ListClustersInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			c.logger.WithError(err).Error("Failed to list ECS clusters")
+			return nil, fmt.Errorf("failed to list ECS clusters: %w", err)
+		}
+		arns = append(arns, page.ClusterArns...)
+	}
+
+	var clusters []types.ECSCluster
+	for batch := range slices.Chunk(arns, ecsDescribeClustersBatch) {
+		result, err := c.ecs.DescribeClusters(ctx, &ecs.DescribeClustersInput{Clusters: batch})
+		if err != nil {
+			c.logger.WithError(err).Error("Failed to describe ECS clusters")
+			return nil, fmt.Errorf("failed to describe ECS clusters: %w", err)
+		}
+		for _, cluster := range result.Clusters {
+			clusters = append(clusters, types.ECSCluster{
+				Name:               aws.ToString(cluster.ClusterName),
+				ARN:                aws.ToString(cluster.ClusterArn),
+				Status:             aws.ToString(cluster.Status),
+				ActiveServices:     cluster.ActiveServicesCount,
+				RunningTasks:       cluster.RunningTasksCount,
+				PendingTasks:       cluster.PendingTasksCount,
+				ContainerInstances: cluster.RegisteredContainerInstancesCount,
+				CapacityProviders:  cluster.CapacityProviders,
+			})
+		}
+	}
+
+	c.logger.WithFields(logrus.Fields{
+		"count":    len(clusters),
+		"duration": time.Since(start),
+	}).Info("Retrieved ECS clusters")
+
+	return clusters, nil
+}
+
+// ListECSServices retrieves the services of a cluster with their deployments
+func (c *Client) ListECSServices(ctx context.Context, cluster string) ([]types.ECSService, error) {
+	start := time.Now()
+
+	var arns []string
+	paginator := ecs.NewListServicesPaginator(c.ecs, &ecs.ListServicesInput{Cluster: aws.String(cluster)})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			c.logger.WithError(err).WithField("cluster", cluster).Error("Failed to list ECS services")
+			return nil, fmt.Errorf("failed to list services of cluster %s: %w", cluster, err)
+		}
+		arns = append(arns, page.ServiceArns...)
+	}
+
+	var services []types.ECSService
+	for batch := range slices.Chunk(arns, ecsDescribeServicesBatch) {
+		described, err := c.describeECSServices(ctx, cluster, batch)
+		if err != nil {
+			return nil, err
+		}
+		services = append(services, described...)
+	}
+
+	c.logger.WithFields(logrus.Fields{
+		"cluster":  cluster,
+		"count":    len(services),
+		"duration": time.Since(start),
+	}).Info("Retrieved ECS services")
+
+	return services, nil
+}
+
+// GetECSService retrieves one service of a cluster
+func (c *Client) GetECSService(ctx context.Context, cluster, service string) (*types.ECSService, error) {
+	services, err := c.describeECSServices(ctx, cluster, []string{service})
+	if err != nil {
+		return nil, err
+	}
+	if len(services) == 0 {
+		return nil, fmt.Errorf("service %s not found in cluster %s", service, cluster)
+	}
+	return &services[0], nil
+}
+
+// describeECSServices describes up to ecsDescribeServicesBatch services
+func (c *Client) describeECSServices(ctx context.Context, cluster string, services []string) ([]types.ECSService, error) {
+	result, err := c.ecs.DescribeServices(ctx, &ecs.DescribeServicesInput{
+		Cluster:  aws.String(cluster),
+		Services: services,
+	})
+	if err != nil {
+		c.logger.WithError(err).WithField("cluster", cluster).Error("Failed to describe ECS services")
+		return nil, fmt.Errorf("failed to describe services of cluster %s: %w", cluster, err)
+	}
+
+	converted := make([]types.ECSService, 0, len(result.Services))
+	for _, service := range result.Services {
+		converted = append(converted, convertECSService(service))
+	}
+	return converted, nil
+}
+
+// ListECSTasks retrieves the running tasks of a service and the tasks that
+// stopped recently, which ECS keeps for about an hour
+func (c *Client) ListECSTasks(ctx context.Context, cluster, service string) ([]types.ECSTask, error) {
+	start := time.Now()
+
+	var arns []string
+	for _, status := range []ecstypes.DesiredStatus{ecstypes.DesiredStatusRunning, ecstypes.DesiredStatusStopped} {
+		paginator := ecs.NewListTasksPaginator(c.ecs, &ecs.ListTasksInput{
+			Cluster:       aws.String(cluster),
+			ServiceName:   aws.String(service),
+			DesiredStatus: status,
+		})
+		for paginator.HasMorePages() {
+			page, err := paginator.NextPage(ctx)
+			if err != nil {
+				c.logger.WithError(err).WithField("service", service).Error("Failed to list ECS tasks")
+				return nil, fmt.Errorf("failed to list tasks of service %s: %w", service, err)
+			}
+			arns = append(arns, page.TaskArns...)
+		}
+	}
+
+	var tasks []types.ECSTask
+	for batch := range slices.Chunk(arns, ecsDescribeTasksBatch) {
+		result, err := c.ecs.DescribeTasks(ctx, &ecs.DescribeTasksInput{
+			Cluster: aws.String(cluster),
+			Tasks:   batch,
+		})
+		if err != nil {
+			c.logger.WithError(err).WithField("service", service).Error("Failed to describe ECS tasks")
+			return nil, fmt.Errorf("failed to describe tasks of service %s: %w", service, err)
+		}
+		for _, task := range result.Tasks {
+			tasks = append(tasks, convertECSTask(task))
+		}
+	}
+
+	c.logger.WithFields(logrus.Fields{
+		"cluster":  cluster,
+		"service":  service,
+		"count":    len(tasks),
+		"duration": time.Since(start),
+	}).Info("Retrieved ECS tasks")
+
+	return tasks, nil
+}
+
+// UpdateECSServiceDesiredCount sets the number of tasks a service runs
+func (c *Client) UpdateECSServiceDesiredCount(ctx context.Context, cluster, service string, desiredCount int32) (*types.ECSService, error) {
+	c.logger.WithFields(logrus.Fields{
+		"cluster":      cluster,
+		"service":      service,
+		"desiredCount": desiredCount,
+	}).Info("Updating ECS service desired count")
+
+	result, err := c.ecs.UpdateService(ctx, &ecs.UpdateServiceInput{
+		Cluster:      aws.String(cluster),
+		Service:      aws.String(service),
+		DesiredCount: aws.Int32(desiredCount),
+	})
+	if err != nil {
+		c.logger.WithError(err).WithField("service", service).Error("Failed to update ECS service")
+		return nil, fmt.Errorf("failed to update desired count of service %s: %w", service, err)
+	}
+
+	updated := convertECSService(*result.Service)
+	c.logger.WithField("service", service).Info("ECS service desired count updated")
+	return &updated, nil
+}
+
+// ForceNewECSDeployment starts a deployment that replaces every task of a
+// service with the same task definition, e.g. to pick up a new image pushed
+// under the same tag or to recover from stuck tasks
+func (c *Client) ForceNewECSDeployment(ctx context.Context, cluster, service string) (*types.ECSService, error) {
+	c.logger.WithFields(logrus.Fields{
+		"cluster": cluster,
+		"service": service,
+	}).Info("Forcing new ECS deployment")
+
+	result, err := c.ecs.UpdateService(ctx, &ecs.UpdateServiceInput{
+		Cluster:            aws.String(cluster),
+		Service:            aws.String(service),
+		ForceNewDeployment: true,
+	})
+	if err != nil {
+		c.logger.WithError(err).WithField("service", service).Error("Failed to force new ECS deployment")
+		return nil, fmt.Errorf("failed to force new deployment of service %s: %w", service, err)
+	}
+
+	updated := convertECSService(*result.Service)
+	c.logger.WithField("service", service).Info("ECS deployment started")
+	return &updated, nil
+}
+
+// convertECSService converts an ECS service to our standard format
+func convertECSService(service ecstypes.Service) types.ECSService {
+	converted := types.ECSService{
+		Name:           aws.ToString(service.ServiceName),
+		ARN:            aws.ToString(service.ServiceArn),
+		Cluster:        lastARNSegment(aws.ToString(service.ClusterArn)),
+		Status:         aws.ToString(service.Status),
+		LaunchType:     string(service.LaunchType),
+		TaskDefinition: lastARNSegment(aws.ToString(service.TaskDefinition)),
+		DesiredCount:   service.DesiredCount,
+		RunningCount:   service.RunningCount,
+		PendingCount:   service.PendingCount,
+		CreatedAt:      aws.ToTime(service.CreatedAt),
+	}
+
+	for _, deployment := range service.Deployments {
+		converted.Deployments = append(converted.Deployments, types.ECSDeployment{
+			ID:                 aws.ToString(deployment.Id),
+			Status:             aws.ToString(deployment.Status),
+			RolloutState:       string(deployment.RolloutState),
+			RolloutStateReason: aws.ToString(deployment.RolloutStateReason),
+			TaskDefinition:     lastARNSegment(aws.ToString(deployment.TaskDefinition)),
+			DesiredCount:       deployment.DesiredCount,
+			RunningCount:       deployment.RunningCount,
+			PendingCount:       deployment.PendingCount,
+			FailedTasks:        deployment.FailedTasks,
+			CreatedAt:          aws.ToTime(deployment.CreatedAt),
+			UpdatedAt:          aws.ToTime(deployment.UpdatedAt),
+		})
+	}
+
+	// Events are newest first
+	for i, event := range service.Events {
+		if i == ecsServiceEvents {
+			break
+		}
+		converted.Events = append(converted.Events, types.ECSServiceEvent{
+			Time:    aws.ToTime(event.CreatedAt),
+			Message: aws.ToString(event.Message),
+		})
+	}
+
+	return converted
+}
+
+// convertECSTask converts an ECS task to our standard format
+func convertECSTask(task ecstypes.Task) types.ECSTask {
+	converted := types.ECSTask{
+		ID:             lastARNSegment(aws.ToString(task.TaskArn)),
+		ARN:            aws.ToString(task.TaskArn),
+		Group:          aws.ToString(task.Group),
+		TaskDefinition: lastARNSegment(aws.ToString(task.TaskDefinitionArn)),
+		LastStatus:     aws.ToString(task.LastStatus),
+		DesiredStatus:  aws.ToString(task.DesiredStatus),
+		HealthStatus:   string(task.HealthStatus),
+		LaunchType:     string(task.LaunchType),
+		StartedAt:      task.StartedAt,
+		StoppedAt:      task.StoppedAt,
+		StopCode:       string(task.StopCode),
+		StoppedReason:  aws.ToString(task.StoppedReason),
+	}
+
+	for _, container := range task.Containers {
+		converted.Containers = append(converted.Containers, types.ECSContainer{
+			Name:         aws.ToString(container.Name),
+			Image:        aws.ToString(container.Image),
+			ImageDigest:  aws.ToString(container.ImageDigest),
+			LastStatus:   aws.ToString(container.LastStatus),
+			HealthStatus: string(container.HealthStatus),
+			ExitCode:     container.ExitCode,
+			Reason:       aws.ToString(container.Reason),
+		})
+	}
+
+	return converted
+}
+
+// lastARNSegment returns the resource name at the end of an ARN, such as
+// web:42 for arn:aws:ecs:us-east-1:123456789012:task-definition/web:42
+func lastARNSegment(arn string) string {
+	return arn[strings.LastIndex(arn, "/")+1:]
+}
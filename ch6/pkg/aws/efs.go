@@ -0,0 +1,349 @@
+package aws
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+	cwtypes "github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+
+	"aws-mcp-server/pkg/types"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// efsAPIPath is the path prefix of the EFS REST API version
+	efsAPIPath = "/2015-02-01"
+	// efsMetricsWindow is how far back the file system metrics are read
+	efsMetricsWindow = 15 * time.Minute
+)
+
+// efsMetrics are the CloudWatch metrics read for each file system, with the
+// statistic used for each one-minute datapoint
+var efsMetrics = []struct {
+	name      string
+	statistic string
+}{
+	{"BurstCreditBalance", "Minimum"},
+	{"PermittedThroughput", "Minimum"},
+	{"PercentIOLimit", "Maximum"},
+}
+
+// efsFileSystem is a file system as the EFS API describes it
+type efsFileSystem struct {
+	FileSystemID                 string  `json:"FileSystemId"`
+	Name                         string  `json:"Name"`
+	LifeCycleState               string  `json:"LifeCycleState"`
+	PerformanceMode              string  `json:"PerformanceMode"`
+	ThroughputMode               string  `json:"ThroughputMode"`
+	ProvisionedThroughputInMibps float64 `json:"ProvisionedThroughputInMibps"`
+	Encrypted                    bool    `json:"Encrypted"`
+	CreationTime                 float64 `json:"CreationTime"`
+	SizeInBytes                  struct {
+		Value int64 `json:"Value"`
+	} `json:"SizeInBytes"`
+}
+
+// ListFileSystems retrieves the EFS file systems of the region with their
+// mount targets and latest metrics
+func (c *Client) ListFileSystems(ctx context.Context) ([]types.FileSystem, error) {
+	start := time.Now()
+
+	fileSystems, err := c.describeFileSystems(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+	for i := range fileSystems {
+		if err := c.addMountTargets(ctx, &fileSystems[i]); err != nil {
+			return nil, err
+		}
+	}
+	c.addEFSMetrics(ctx, fileSystems)
+
+	c.logger.WithFields(logrus.Fields{
+		"count":    len(fileSystems),
+		"duration": time.Since(start),
+	}).Info("Retrieved EFS file systems")
+
+	return fileSystems, nil
+}
+
+// GetFileSystem retrieves one file system with its mount targets and metrics
+func (c *Client) GetFileSystem(ctx context.Context, fileSystemID string) (*types.FileSystem, error) {
+	fileSystems, err := c.describeFileSystems(ctx, fileSystemID)
+	if err != nil {
+		return nil, err
+	}
+	if len(fileSystems) == 0 {
+		return nil, fmt.Errorf("file system %s not found", fileSystemID)
+	}
+	if err := c.addMountTargets(ctx, &fileSystems[0]); err != nil {
+		return nil, err
+	}
+	c.addEFSMetrics(ctx, fileSystems[:1])
+	return &fileSystems[0], nil
+}
+
+// UpdateThroughputMode switches a file system to a throughput mode. The
+// provisioned throughput in MiB/s is only sent for provisioned mode.
+func (c *Client) UpdateThroughputMode(ctx context.Context, fileSystemID, mode string, provisionedMiBps float64) (*types.FileSystem, error) {
+	input := map[string]interface{}{"ThroughputMode": mode}
+	if mode == "provisioned" {
+		input["ProvisionedThroughputInMibps"] = provisionedMiBps
+	}
+
+	var updated efsFileSystem
+	if err := c.callEFS(ctx, "UpdateFileSystem", http.MethodPut, "/file-systems/"+url.PathEscape(fileSystemID), nil, input, &updated); err != nil {
+		c.logger.WithError(err).WithField("fileSystemId", fileSystemID).Error("Failed to update EFS throughput mode")
+		return nil, fmt.Errorf("failed to update throughput mode of %s: %w", fileSystemID, err)
+	}
+
+	c.logger.WithFields(logrus.Fields{
+		"fileSystemId": fileSystemID,
+		"mode":         mode,
+	}).Info("Updated EFS throughput mode")
+
+	fileSystem := convertFileSystem(updated)
+	return &fileSystem, nil
+}
+
+// describeFileSystems lists the file systems, or only the one with the given ID
+func (c *Client) describeFileSystems(ctx context.Context, fileSystemID string) ([]types.FileSystem, error) {
+	var fileSystems []types.FileSystem
+	marker := ""
+	for {
+		query := url.Values{}
+		if fileSystemID != "" {
+			query.Set("FileSystemId", fileSystemID)
+		}
+		if marker != "" {
+			query.Set("Marker", marker)
+		}
+
+		var page struct {
+			FileSystems []efsFileSystem `json:"FileSystems"`
+			NextMarker  string          `json:"NextMarker"`
+		}
+		if err := c.callEFS(ctx, "DescribeFileSystems", http.MethodGet, "/file-systems", query, nil, &page); err != nil {
+			c.logger.WithError(err).Error("Failed to describe EFS file systems")
+			return nil, fmt.Errorf("failed to describe file systems: %w", err)
+		}
+		for _, fileSystem := range page.FileSystems {
+			fileSystems = append(fileSystems, convertFileSystem(fileSystem))
+		}
+
+		if page.NextMarker == "" {
+			return fileSystems, nil
+		}
+		marker = page.NextMarker
+	}
+}
+
+// addMountTargets reads the mount targets of a file system
+func (c *Client) addMountTargets(ctx context.Context, fileSystem *types.FileSystem) error {
+	var result struct {
+		MountTargets []struct {
+			MountTargetID        string `json:"MountTargetId"`
+			SubnetID             string `json:"SubnetId"`
+			AvailabilityZoneName string `json:"AvailabilityZoneName"`
+			IPAddress            string `json:"IpAddress"`
+			LifeCycleState       string `json:"LifeCycleState"`
+		} `json:"MountTargets"`
+	}
+	query := url.Values{"FileSystemId": []string{fileSystem.ID}}
+	if err := c.callEFS(ctx, "DescribeMountTargets", http.MethodGet, "/mount-targets", query, nil, &result); err != nil {
+		c.logger.WithError(err).WithField("fileSystemId", fileSystem.ID).Error("Failed to describe EFS mount targets")
+		return fmt.Errorf("failed to describe mount targets of %s: %w", fileSystem.ID, err)
+	}
+
+	fileSystem.MountTargets = make([]types.MountTarget, 0, len(result.MountTargets))
+	for _, target := range result.MountTargets {
+		fileSystem.MountTargets = append(fileSystem.MountTargets, types.MountTarget{
+			ID:               target.MountTargetID,
+			SubnetID:         target.SubnetID,
+			AvailabilityZone: target.AvailabilityZoneName,
+			IPAddress:        target.IPAddress,
+			State:            target.LifeCycleState,
+		})
+	}
+	return nil
+}
+
+// convertFileSystem converts a file system of the EFS API
+func convertFileSystem(fileSystem efsFileSystem) types.FileSystem {
+	converted := types.FileSystem{
+		ID:              fileSystem.FileSystemID,
+		Name:            fileSystem.Name,
+		State:           fileSystem.LifeCycleState,
+		PerformanceMode: fileSystem.PerformanceMode,
+		ThroughputMode:  fileSystem.ThroughputMode,
+		SizeBytes:       fileSystem.SizeInBytes.Value,
+		Encrypted:       fileSystem.Encrypted,
+		Created:         time.Unix(0, int64(fileSystem.CreationTime*float64(time.Second))).UTC(),
+	}
+	if fileSystem.ThroughputMode == "provisioned" {
+		converted.ProvisionedMiBps = fileSystem.ProvisionedThroughputInMibps
+	}
+	return converted
+}
+
+// addEFSMetrics fills in the latest metrics of each file system. The metrics
+// are best effort: file systems keep nil metrics when CloudWatch cannot be
+// read.
+func (c *Client) addEFSMetrics(ctx context.Context, fileSystems []types.FileSystem) {
+	end := time.Now()
+	perBatch := sqsMetricQueriesBatch / len(efsMetrics)
+	for batch := range slices.Chunk(fileSystems, perBatch) {
+		queries := make([]cwtypes.MetricDataQuery, 0, len(batch)*len(efsMetrics))
+		for i, fileSystem := range batch {
+			for m, metric := range efsMetrics {
+				queries = append(queries, cwtypes.MetricDataQuery{
+					Id: aws.String(fmt.Sprintf("f%d_m%d", i, m)),
+					MetricStat: &cwtypes.MetricStat{
+						Metric: &cwtypes.Metric{
+							Namespace:  aws.String("AWS/EFS"),
+							MetricName: aws.String(metric.name),
+							Dimensions: []cwtypes.Dimension{{Name: aws.String("FileSystemId"), Value: aws.String(fileSystem.ID)}},
+						},
+						Period: aws.Int32(60),
+						Stat:   aws.String(metric.statistic),
+					},
+				})
+			}
+		}
+
+		// Results are newest first, so the first value is the latest
+		latest := make(map[string]float64, len(queries))
+		paginator := cloudwatch.NewGetMetricDataPaginator(c.cloudwatch, &cloudwatch.GetMetricDataInput{
+			StartTime:         aws.Time(end.Add(-efsMetricsWindow)),
+			EndTime:           aws.Time(end),
+			MetricDataQueries: queries,
+			ScanBy:            cwtypes.ScanByTimestampDescending,
+		})
+		for paginator.HasMorePages() {
+			page, err := paginator.NextPage(ctx)
+			if err != nil {
+				c.logger.WithError(err).Warn("Failed to get EFS file system metrics")
+				return
+			}
+			for _, result := range page.MetricDataResults {
+				id := aws.ToString(result.Id)
+				if _, seen := latest[id]; !seen && len(result.Values) > 0 {
+					latest[id] = result.Values[0]
+				}
+			}
+		}
+
+		for i := range batch {
+			value := func(m int) float64 { return latest[fmt.Sprintf("f%d_m%d", i, m)] }
+			batch[i].Metrics = &types.FileSystemMetrics{
+				BurstCreditBytes:        value(0),
+				PermittedBytesPerSecond: value(1),
+				PercentIOLimit:          value(2),
+			}
+		}
+	}
+}
+
+// callEFS sends one call to the EFS REST API and decodes its JSON output.
+// EFS is called without an SDK service client, so the request is signed here
+// and sent through the configuration's HTTP client and the client's hooks,
+// like the calls of the SDK clients.
+func (c *Client) callEFS(ctx context.Context, operation, method, path string, query url.Values, input, output interface{}) error {
+	call := Call{Service: "EFS", Operation: operation, Region: c.cfg.Region, Input: input}
+	_, err := c.hooks.observe(ctx, call, func(ctx context.Context) (interface{}, error) {
+		var body []byte
+		if input != nil {
+			var err error
+			if body, err = json.Marshal(input); err != nil {
+				return nil, err
+			}
+		}
+
+		endpoint := fmt.Sprintf("https://elasticfilesystem.%s.amazonaws.com", c.cfg.Region)
+		if c.cfg.BaseEndpoint != nil {
+			endpoint = strings.TrimSuffix(*c.cfg.BaseEndpoint, "/")
+		}
+		target := endpoint + efsAPIPath + path
+		if len(query) > 0 {
+			target += "?" + query.Encode()
+		}
+
+		req, err := http.NewRequestWithContext(ctx, method, target, bytes.NewReader(body))
+		if err != nil {
+			return nil, err
+		}
+		if input != nil {
+			req.Header.Set("Content-Type", "application/json")
+		}
+
+		credentials, err := c.cfg.Credentials.Retrieve(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to retrieve credentials: %w", err)
+		}
+		hash := sha256.Sum256(body)
+		if err := v4.NewSigner().SignHTTP(ctx, credentials, req, hex.EncodeToString(hash[:]), "elasticfilesystem", c.cfg.Region, time.Now()); err != nil {
+			return nil, fmt.Errorf("failed to sign request: %w", err)
+		}
+
+		var httpClient aws.HTTPClient = http.DefaultClient
+		if c.cfg.HTTPClient != nil {
+			httpClient = c.cfg.HTTPClient
+		}
+		resp, err := httpClient.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+
+		data, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode >= 300 {
+			return nil, efsError(resp, data)
+		}
+		if output != nil && len(data) > 0 {
+			if err := json.Unmarshal(data, output); err != nil {
+				return nil, fmt.Errorf("failed to decode %s response: %w", operation, err)
+			}
+		}
+		return output, nil
+	})
+	return err
+}
+
+// efsError returns the error of a failed EFS call, e.g.
+// "FileSystemNotFound: File system 'fs-1' does not exist."
+func efsError(resp *http.Response, data []byte) error {
+	var apiErr struct {
+		ErrorCode string `json:"ErrorCode"`
+		Message   string `json:"Message"`
+	}
+	_ = json.Unmarshal(data, &apiErr)
+
+	code := apiErr.ErrorCode
+	if code == "" {
+		code, _, _ = strings.Cut(resp.Header.Get("X-Amzn-ErrorType"), ":")
+	}
+	if code == "" {
+		code = resp.Status
+	}
+	if apiErr.Message == "" {
+		return fmt.Errorf("%s", code)
+	}
+	return fmt.Errorf("%s: %s", code, apiErr.Message)
+}
@@ -0,0 +1,260 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/elasticache"
+	ectypes "github.com/aws/aws-sdk-go-v2/service/elasticache/types"
+
+	"aws-mcp-server/pkg/types"
+
+	"github.com/sirupsen/logrus"
+)
+
+// pendingUpdateStatuses are the states of service updates not yet applied
+var pendingUpdateStatuses = []ectypes.UpdateActionStatus{
+	ectypes.UpdateActionStatusNotApplied,
+	ectypes.UpdateActionStatusWaitingToStart,
+	ectypes.UpdateActionStatusScheduling,
+	ectypes.UpdateActionStatusScheduled,
+	ectypes.UpdateActionStatusInProgress,
+	ectypes.UpdateActionStatusStopped,
+}
+
+// ListCacheClusters retrieves all ElastiCache clusters with their nodes and
+// pending service updates
+func (c *Client) ListCacheClusters(ctx context.Context) ([]types.CacheCluster, error) {
+	start := time.Now()
+
+	clusters, err := c.describeCacheClusters(ctx, "")
+	if err != nil {
+		c.logger.WithError(err).Error("Failed to describe ElastiCache clusters")
+		return nil, err
+	}
+	c.addCacheClusterModes(ctx, clusters, "")
+	c.addCacheServiceUpdates(ctx, clusters)
+
+	c.logger.WithFields(logrus.Fields{
+		"count":    len(clusters),
+		"duration": time.Since(start),
+	}).Info("Retrieved ElastiCache clusters")
+
+	return clusters, nil
+}
+
+// GetCacheCluster retrieves one ElastiCache cluster by ID
+func (c *Client) GetCacheCluster(ctx context.Context, clusterID string) (*types.CacheCluster, error) {
+	clusters, err := c.describeCacheClusters(ctx, clusterID)
+	if err != nil {
+		c.logger.WithError(err).WithField("clusterId", clusterID).Error("Failed to describe ElastiCache cluster")
+		return nil, err
+	}
+	if len(clusters) == 0 {
+		return nil, fmt.Errorf("ElastiCache cluster %s not found", clusterID)
+	}
+	c.addCacheClusterModes(ctx, clusters, clusters[0].ReplicationGroupID)
+	c.addCacheServiceUpdates(ctx, clusters)
+	return &clusters[0], nil
+}
+
+// describeCacheClusters describes one cluster, or all with an empty ID
+func (c *Client) describeCacheClusters(ctx context.Context, clusterID string) ([]types.CacheCluster, error) {
+	input := &elasticache.DescribeCacheClustersInput{ShowCacheNodeInfo: aws.Bool(true)}
+	if clusterID != "" {
+		input.CacheClusterId = aws.String(clusterID)
+	}
+
+	var clusters []types.CacheCluster
+	paginator := elasticache.NewDescribeCacheClustersPaginator(c.elasticache, input)
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe ElastiCache clusters: %w", err)
+		}
+		for _, cluster := range page.CacheClusters {
+			clusters = append(clusters, convertCacheCluster(cluster))
+		}
+	}
+	return clusters, nil
+}
+
+// convertCacheCluster converts an ElastiCache cluster description
+func convertCacheCluster(cluster ectypes.CacheCluster) types.CacheCluster {
+	converted := types.CacheCluster{
+		ID:                      aws.ToString(cluster.CacheClusterId),
+		ARN:                     aws.ToString(cluster.ARN),
+		Engine:                  aws.ToString(cluster.Engine),
+		EngineVersion:           aws.ToString(cluster.EngineVersion),
+		NodeType:                aws.ToString(cluster.CacheNodeType),
+		Status:                  aws.ToString(cluster.CacheClusterStatus),
+		ReplicationGroupID:      aws.ToString(cluster.ReplicationGroupId),
+		AvailabilityZone:        aws.ToString(cluster.PreferredAvailabilityZone),
+		MaintenanceWindow:       aws.ToString(cluster.PreferredMaintenanceWindow),
+		SnapshotWindow:          aws.ToString(cluster.SnapshotWindow),
+		SnapshotRetention:       aws.ToInt32(cluster.SnapshotRetentionLimit),
+		AutoMinorVersionUpgrade: aws.ToBool(cluster.AutoMinorVersionUpgrade),
+		TransitEncryption:       aws.ToBool(cluster.TransitEncryptionEnabled),
+		AtRestEncryption:        aws.ToBool(cluster.AtRestEncryptionEnabled),
+		CreatedAt:               aws.ToTime(cluster.CacheClusterCreateTime),
+	}
+
+	for _, node := range cluster.CacheNodes {
+		converted.Nodes = append(converted.Nodes, types.CacheNode{
+			ID:                   aws.ToString(node.CacheNodeId),
+			Status:               aws.ToString(node.CacheNodeStatus),
+			AvailabilityZone:     aws.ToString(node.CustomerAvailabilityZone),
+			Endpoint:             cacheEndpoint(node.Endpoint),
+			ParameterGroupStatus: aws.ToString(node.ParameterGroupStatus),
+			CreatedAt:            aws.ToTime(node.CacheNodeCreateTime),
+		})
+	}
+
+	if pending := cluster.PendingModifiedValues; pending != nil {
+		changes := make(map[string]string)
+		if pending.CacheNodeType != nil {
+			changes["nodeType"] = aws.ToString(pending.CacheNodeType)
+		}
+		if pending.EngineVersion != nil {
+			changes["engineVersion"] = aws.ToString(pending.EngineVersion)
+		}
+		if pending.NumCacheNodes != nil {
+			changes["numNodes"] = strconv.Itoa(int(aws.ToInt32(pending.NumCacheNodes)))
+		}
+		if len(pending.CacheNodeIdsToRemove) > 0 {
+			changes["nodesToRemove"] = fmt.Sprint(pending.CacheNodeIdsToRemove)
+		}
+		if len(changes) > 0 {
+			converted.PendingChanges = changes
+		}
+	}
+	return converted
+}
+
+// cacheEndpoint formats a node endpoint as host:port
+func cacheEndpoint(endpoint *ectypes.Endpoint) string {
+	if endpoint == nil || endpoint.Address == nil {
+		return ""
+	}
+	return fmt.Sprintf("%s:%d", aws.ToString(endpoint.Address), aws.ToInt32(endpoint.Port))
+}
+
+// addCacheClusterModes marks the clusters of replication groups with cluster
+// mode enabled, which cannot be rebooted node by node. With a group ID only
+// that group is described. It is best-effort.
+func (c *Client) addCacheClusterModes(ctx context.Context, clusters []types.CacheCluster, groupID string) {
+	input := &elasticache.DescribeReplicationGroupsInput{}
+	if groupID != "" {
+		input.ReplicationGroupId = aws.String(groupID)
+	}
+
+	enabled := make(map[string]bool)
+	paginator := elasticache.NewDescribeReplicationGroupsPaginator(c.elasticache, input)
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			c.logger.WithError(err).Warn("Failed to describe ElastiCache replication groups")
+			return
+		}
+		for _, group := range page.ReplicationGroups {
+			enabled[aws.ToString(group.ReplicationGroupId)] = aws.ToBool(group.ClusterEnabled)
+		}
+	}
+
+	for i := range clusters {
+		clusters[i].ClusterModeEnabled = enabled[clusters[i].ReplicationGroupID]
+	}
+}
+
+// addCacheServiceUpdates attaches the service updates not yet applied to each
+// cluster; updates of a replication group apply to all of its clusters. It is
+// best-effort.
+func (c *Client) addCacheServiceUpdates(ctx context.Context, clusters []types.CacheCluster) {
+	if len(clusters) == 0 {
+		return
+	}
+
+	byCluster := make(map[string][]types.CacheServiceUpdate)
+	byGroup := make(map[string][]types.CacheServiceUpdate)
+	paginator := elasticache.NewDescribeUpdateActionsPaginator(c.elasticache, &elasticache.DescribeUpdateActionsInput{
+		UpdateActionStatus: pendingUpdateStatuses,
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			c.logger.WithError(err).Warn("Failed to describe ElastiCache service updates")
+			return
+		}
+		for _, action := range page.UpdateActions {
+			update := types.CacheServiceUpdate{
+				Name:     aws.ToString(action.ServiceUpdateName),
+				Type:     string(action.ServiceUpdateType),
+				Severity: string(action.ServiceUpdateSeverity),
+				Status:   string(action.UpdateActionStatus),
+				ApplyBy:  aws.ToTime(action.ServiceUpdateRecommendedApplyByDate),
+			}
+			if id := aws.ToString(action.CacheClusterId); id != "" {
+				byCluster[id] = append(byCluster[id], update)
+			} else if group := aws.ToString(action.ReplicationGroupId); group != "" {
+				byGroup[group] = append(byGroup[group], update)
+			}
+		}
+	}
+
+	for i := range clusters {
+		updates := byCluster[clusters[i].ID]
+		if clusters[i].ReplicationGroupID != "" {
+			updates = append(updates, byGroup[clusters[i].ReplicationGroupID]...)
+		}
+		clusters[i].PendingUpdates = updates
+	}
+}
+
+// ListCacheEvents retrieves the events of a cluster since a time, newest first
+func (c *Client) ListCacheEvents(ctx context.Context, clusterID string, since time.Time) ([]types.CacheEvent, error) {
+	var events []types.CacheEvent
+	paginator := elasticache.NewDescribeEventsPaginator(c.elasticache, &elasticache.DescribeEventsInput{
+		SourceIdentifier: aws.String(clusterID),
+		SourceType:       ectypes.SourceTypeCacheCluster,
+		StartTime:        aws.Time(since),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			c.logger.WithError(err).WithField("clusterId", clusterID).Error("Failed to describe ElastiCache events")
+			return nil, fmt.Errorf("failed to describe events of cluster %s: %w", clusterID, err)
+		}
+		for _, event := range page.Events {
+			events = append(events, types.CacheEvent{
+				Time:     aws.ToTime(event.Date),
+				SourceID: aws.ToString(event.SourceIdentifier),
+				Message:  aws.ToString(event.Message),
+			})
+		}
+	}
+	return events, nil
+}
+
+// RebootCacheNodes reboots nodes of an ElastiCache cluster
+func (c *Client) RebootCacheNodes(ctx context.Context, clusterID string, nodeIDs []string) error {
+	_, err := c.elasticache.RebootCacheCluster(ctx, &elasticache.RebootCacheClusterInput{
+		CacheClusterId:       aws.String(clusterID),
+		CacheNodeIdsToReboot: nodeIDs,
+	})
+	if err != nil {
+		c.logger.WithError(err).WithFields(logrus.Fields{
+			"clusterId": clusterID,
+			"nodes":     nodeIDs,
+		}).Error("Failed to reboot ElastiCache nodes")
+		return fmt.Errorf("failed to reboot nodes of cluster %s: %w", clusterID, err)
+	}
+
+	c.logger.WithFields(logrus.Fields{
+		"clusterId": clusterID,
+		"nodes":     nodeIDs,
+	}).Info("Rebooted ElastiCache nodes")
+	return nil
+}
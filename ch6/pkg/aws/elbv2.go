@@ -0,0 +1,158 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	elbv2 "github.com/aws/aws-sdk-go-v2/service/elasticloadbalancingv2"
+
+	"aws-mcp-server/pkg/types"
+
+	"github.com/sirupsen/logrus"
+)
+
+// ListLoadBalancers retrieves all ELBv2 load balancers in the region together with their listeners
+func (c *Client) ListLoadBalancers(ctx context.Context) ([]types.LoadBalancer, error) {
+	start := time.Now()
+
+	var loadBalancers []types.LoadBalancer
+	paginator := elbv2.NewDescribeLoadBalancersPaginator(c.elbv2, &elbv2.DescribeLoadBalancersInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			c.logger.WithError(err).Error("Failed to describe load balancers")
+			return nil, fmt.Errorf("failed to describe load balancers: %w", err)
+		}
+
+		for _, lb := range page.LoadBalancers {
+			converted := types.LoadBalancer{
+				ARN:            aws.ToString(lb.LoadBalancerArn),
+				Name:           aws.ToString(lb.LoadBalancerName),
+				DNSName:        aws.ToString(lb.DNSName),
+				Type:           string(lb.Type),
+				Scheme:         string(lb.Scheme),
+				VpcID:          aws.ToString(lb.VpcId),
+				SecurityGroups: lb.SecurityGroups,
+			}
+			if lb.State != nil {
+				converted.State = string(lb.State.Code)
+			}
+
+			listeners, err := c.listListeners(ctx, converted.ARN)
+			if err != nil {
+				return nil, err
+			}
+			converted.Listeners = listeners
+
+			loadBalancers = append(loadBalancers, converted)
+		}
+	}
+
+	c.logger.WithFields(logrus.Fields{
+		"count":    len(loadBalancers),
+		"duration": time.Since(start),
+	}).Info("Retrieved load balancers")
+
+	return loadBalancers, nil
+}
+
+// listListeners retrieves the listeners configured on a load balancer
+func (c *Client) listListeners(ctx context.Context, loadBalancerARN string) ([]types.LoadBalancerListener, error) {
+	var listeners []types.LoadBalancerListener
+
+	paginator := elbv2.NewDescribeListenersPaginator(c.elbv2, &elbv2.DescribeListenersInput{
+		LoadBalancerArn: aws.String(loadBalancerARN),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			c.logger.WithError(err).WithField("loadBalancerArn", loadBalancerARN).Error("Failed to describe listeners")
+			return nil, fmt.Errorf("failed to describe listeners for %s: %w", loadBalancerARN, err)
+		}
+
+		for _, listener := range page.Listeners {
+			listeners = append(listeners, types.LoadBalancerListener{
+				Port:     aws.ToInt32(listener.Port),
+				Protocol: string(listener.Protocol),
+			})
+		}
+	}
+
+	return listeners, nil
+}
+
+// ListTargetGroups retrieves all ELBv2 target groups in the region together with the health of their targets
+func (c *Client) ListTargetGroups(ctx context.Context) ([]types.TargetGroup, error) {
+	start := time.Now()
+
+	var groups []types.TargetGroup
+	paginator := elbv2.NewDescribeTargetGroupsPaginator(c.elbv2, &elbv2.DescribeTargetGroupsInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			c.logger.WithError(err).Error("Failed to describe target groups")
+			return nil, fmt.Errorf("failed to describe target groups: %w", err)
+		}
+
+		for _, group := range page.TargetGroups {
+			converted := types.TargetGroup{
+				ARN:              aws.ToString(group.TargetGroupArn),
+				Name:             aws.ToString(group.TargetGroupName),
+				Protocol:         string(group.Protocol),
+				Port:             aws.ToInt32(group.Port),
+				TargetType:       string(group.TargetType),
+				LoadBalancerARNs: group.LoadBalancerArns,
+			}
+
+			targets, err := c.describeTargetHealth(ctx, converted.ARN)
+			if err != nil {
+				return nil, err
+			}
+			converted.Targets = targets
+
+			groups = append(groups, converted)
+		}
+	}
+
+	c.logger.WithFields(logrus.Fields{
+		"count":    len(groups),
+		"duration": time.Since(start),
+	}).Info("Retrieved target groups")
+
+	return groups, nil
+}
+
+// GetTargetHealth retrieves the current health of the targets of one target group
+func (c *Client) GetTargetHealth(ctx context.Context, targetGroupARN string) ([]types.Target, error) {
+	return c.describeTargetHealth(ctx, targetGroupARN)
+}
+
+// describeTargetHealth retrieves the targets registered with a target group and their health
+func (c *Client) describeTargetHealth(ctx context.Context, targetGroupARN string) ([]types.Target, error) {
+	result, err := c.elbv2.DescribeTargetHealth(ctx, &elbv2.DescribeTargetHealthInput{
+		TargetGroupArn: aws.String(targetGroupARN),
+	})
+	if err != nil {
+		c.logger.WithError(err).WithField("targetGroupArn", targetGroupARN).Error("Failed to describe target health")
+		return nil, fmt.Errorf("failed to describe target health for %s: %w", targetGroupARN, err)
+	}
+
+	targets := make([]types.Target, 0, len(result.TargetHealthDescriptions))
+	for _, description := range result.TargetHealthDescriptions {
+		target := types.Target{}
+		if description.Target != nil {
+			target.ID = aws.ToString(description.Target.Id)
+			target.Port = aws.ToInt32(description.Target.Port)
+		}
+		if description.TargetHealth != nil {
+			target.State = string(description.TargetHealth.State)
+			target.Reason = string(description.TargetHealth.Reason)
+			target.Description = aws.ToString(description.TargetHealth.Description)
+		}
+		targets = append(targets, target)
+	}
+
+	return targets, nil
+}
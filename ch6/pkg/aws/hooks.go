@@ -0,0 +1,152 @@
+package aws
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	awsmiddleware "github.com/aws/aws-sdk-go-v2/aws/middleware"
+	"github.com/aws/smithy-go/middleware"
+
+	"aws-mcp-server/internal/logging"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Call identifies one AWS API call, e.g. service "EC2" and operation
+// "DescribeInstances". Input is the operation's input struct.
+type Call struct {
+	Service   string
+	Operation string
+	Region    string
+	Input     interface{}
+}
+
+// Hook observes the AWS API calls of every service client, so metrics,
+// auditing, caching or circuit breaking can be added without changing each
+// client method. Hooks see one call per operation, however often the SDK
+// retries it.
+type Hook interface {
+	// OnRequest runs before the call is sent. The returned context is used
+	// for the call; a non-nil error aborts it, e.g. while a circuit is open,
+	// and is returned to the caller instead.
+	OnRequest(ctx context.Context, call Call) (context.Context, error)
+	// OnResponse runs after the call succeeded with the operation's output
+	OnResponse(ctx context.Context, call Call, output interface{}, duration time.Duration)
+	// OnError runs after the call failed, including calls aborted by a hook
+	OnError(ctx context.Context, call Call, err error, duration time.Duration)
+}
+
+// hookChain holds the hooks of a client. It is shared by the middleware of
+// all service clients, so hooks added later apply to every service.
+type hookChain struct {
+	mu    sync.RWMutex
+	hooks []Hook
+}
+
+// add appends a hook; hooks run in the order they were added
+func (c *hookChain) add(hook Hook) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.hooks = append(c.hooks, hook)
+}
+
+// snapshot returns the current hooks
+func (c *hookChain) snapshot() []Hook {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.hooks
+}
+
+// ID names the middleware in the SDK's middleware stacks
+func (c *hookChain) ID() string {
+	return "AIOpsHooks"
+}
+
+// HandleInitialize runs the hooks around a call. The initialize step comes
+// before retries, so hooks see each operation once.
+func (c *hookChain) HandleInitialize(ctx context.Context, in middleware.InitializeInput, next middleware.InitializeHandler) (middleware.InitializeOutput, middleware.Metadata, error) {
+	if len(c.snapshot()) == 0 {
+		return next.HandleInitialize(ctx, in)
+	}
+
+	call := Call{
+		Service:   awsmiddleware.GetServiceID(ctx),
+		Operation: awsmiddleware.GetOperationName(ctx),
+		Region:    awsmiddleware.GetRegion(ctx),
+		Input:     in.Parameters,
+	}
+
+	var metadata middleware.Metadata
+	result, err := c.observe(ctx, call, func(ctx context.Context) (interface{}, error) {
+		out, md, err := next.HandleInitialize(ctx, in)
+		metadata = md
+		return out.Result, err
+	})
+	return middleware.InitializeOutput{Result: result}, metadata, err
+}
+
+// observe runs the hooks around send, which makes the call and returns its
+// output. Calls made without an SDK client use it to pass the same hooks.
+func (c *hookChain) observe(ctx context.Context, call Call, send func(ctx context.Context) (interface{}, error)) (interface{}, error) {
+	hooks := c.snapshot()
+	start := time.Now()
+
+	for _, hook := range hooks {
+		hookCtx, err := hook.OnRequest(ctx, call)
+		if err != nil {
+			for _, h := range hooks {
+				h.OnError(ctx, call, err, time.Since(start))
+			}
+			return nil, err
+		}
+		ctx = hookCtx
+	}
+
+	output, err := send(ctx)
+	duration := time.Since(start)
+	for _, hook := range hooks {
+		if err != nil {
+			hook.OnError(ctx, call, err, duration)
+		} else {
+			hook.OnResponse(ctx, call, output, duration)
+		}
+	}
+	return output, err
+}
+
+// register adds the hook middleware to a service client's stack
+func (c *hookChain) register(stack *middleware.Stack) error {
+	return stack.Initialize.Add(c, middleware.After)
+}
+
+// AddHook adds a hook that observes every AWS API call of the client
+func (c *Client) AddHook(hook Hook) {
+	c.hooks.add(hook)
+}
+
+// logHook logs every call at debug level, so the AWS traffic behind a tool
+// call or resource read can be followed
+type logHook struct {
+	logger *logging.Logger
+}
+
+func (h logHook) OnRequest(ctx context.Context, call Call) (context.Context, error) {
+	return ctx, nil
+}
+
+func (h logHook) OnResponse(ctx context.Context, call Call, output interface{}, duration time.Duration) {
+	h.logger.WithFields(logrus.Fields{
+		"service":   call.Service,
+		"operation": call.Operation,
+		"duration":  duration,
+	}).Debug("AWS API call succeeded")
+}
+
+func (h logHook) OnError(ctx context.Context, call Call, err error, duration time.Duration) {
+	h.logger.WithError(err).WithFields(logrus.Fields{
+		"service":   call.Service,
+		"operation": call.Operation,
+		"duration":  duration,
+	}).Debug("AWS API call failed")
+}
@@ -0,0 +1,168 @@
+package aws
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	awsmiddleware "github.com/aws/aws-sdk-go-v2/aws/middleware"
+	"github.com/aws/smithy-go/middleware"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// hookContextKey carries the names of the hooks whose OnRequest ran
+type hookContextKey struct{}
+
+// recordingHook writes what it sees to a shared journal and can abort calls
+type recordingHook struct {
+	name    string
+	journal *journal
+	abort   error
+}
+
+// journal is the order in which hooks ran, shared by the hooks of a chain
+type journal struct {
+	mu      sync.Mutex
+	entries []string
+}
+
+func (j *journal) add(format string, args ...interface{}) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.entries = append(j.entries, fmt.Sprintf(format, args...))
+}
+
+func (h *recordingHook) OnRequest(ctx context.Context, call Call) (context.Context, error) {
+	h.journal.add("%s.request %s/%s", h.name, call.Service, call.Operation)
+	if h.abort != nil {
+		return ctx, h.abort
+	}
+	seen, _ := ctx.Value(hookContextKey{}).([]string)
+	return context.WithValue(ctx, hookContextKey{}, append(seen, h.name)), nil
+}
+
+func (h *recordingHook) OnResponse(ctx context.Context, call Call, output interface{}, duration time.Duration) {
+	h.journal.add("%s.response %v", h.name, output)
+}
+
+func (h *recordingHook) OnError(ctx context.Context, call Call, err error, duration time.Duration) {
+	h.journal.add("%s.error %v", h.name, err)
+}
+
+func newRecordingChain(hooks ...*recordingHook) (*hookChain, *journal) {
+	j := &journal{}
+	chain := &hookChain{}
+	for _, hook := range hooks {
+		hook.journal = j
+		chain.add(hook)
+	}
+	return chain, j
+}
+
+func TestHookChainRunsHooksInOrder(t *testing.T) {
+	chain, j := newRecordingChain(&recordingHook{name: "first"}, &recordingHook{name: "second"})
+	call := Call{Service: "EC2", Operation: "DescribeInstances"}
+
+	var seen []string
+	output, err := chain.observe(context.Background(), call, func(ctx context.Context) (interface{}, error) {
+		seen, _ = ctx.Value(hookContextKey{}).([]string)
+		return "instances", nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "instances", output)
+	assert.Equal(t, []string{"first", "second"}, seen, "the call runs with the context of every hook")
+	assert.Equal(t, []string{
+		"first.request EC2/DescribeInstances",
+		"second.request EC2/DescribeInstances",
+		"first.response instances",
+		"second.response instances",
+	}, j.entries)
+}
+
+func TestHookChainShortCircuitsOnRequestErrors(t *testing.T) {
+	open := errors.New("circuit open")
+	chain, j := newRecordingChain(&recordingHook{name: "first"}, &recordingHook{name: "breaker", abort: open}, &recordingHook{name: "last"})
+
+	sent := false
+	_, err := chain.observe(context.Background(), Call{Service: "RDS", Operation: "DescribeDBInstances"}, func(ctx context.Context) (interface{}, error) {
+		sent = true
+		return nil, nil
+	})
+	assert.ErrorIs(t, err, open)
+	assert.False(t, sent, "an aborted call is not sent")
+	assert.Equal(t, []string{
+		"first.request RDS/DescribeDBInstances",
+		"breaker.request RDS/DescribeDBInstances",
+		"first.error circuit open",
+		"breaker.error circuit open",
+		"last.error circuit open",
+	}, j.entries, "later hooks do not see the request but every hook sees the error")
+}
+
+func TestHookChainPropagatesCallErrors(t *testing.T) {
+	throttled := errors.New("throttled")
+	chain, j := newRecordingChain(&recordingHook{name: "first"}, &recordingHook{name: "second"})
+
+	_, err := chain.observe(context.Background(), Call{Service: "EC2", Operation: "StopInstances"}, func(ctx context.Context) (interface{}, error) {
+		return nil, throttled
+	})
+	assert.ErrorIs(t, err, throttled)
+	assert.Equal(t, []string{
+		"first.request EC2/StopInstances",
+		"second.request EC2/StopInstances",
+		"first.error throttled",
+		"second.error throttled",
+	}, j.entries)
+}
+
+func TestHookChainHandleInitialize(t *testing.T) {
+	chain, j := newRecordingChain(&recordingHook{name: "first"}, &recordingHook{name: "second"})
+	stack := middleware.NewStack("DescribeInstances", func() interface{} { return nil })
+	require.NoError(t, chain.register(stack))
+	// the responses of the handler are raw until a deserializer decodes them
+	require.NoError(t, stack.Deserialize.Add(middleware.DeserializeMiddlewareFunc("decode",
+		func(ctx context.Context, in middleware.DeserializeInput, next middleware.DeserializeHandler) (middleware.DeserializeOutput, middleware.Metadata, error) {
+			out, metadata, err := next.HandleDeserialize(ctx, in)
+			out.Result = out.RawResponse
+			return out, metadata, err
+		}), middleware.After))
+
+	ctx := awsmiddleware.SetServiceID(context.Background(), "EC2")
+	ctx = awsmiddleware.SetOperationName(ctx, "DescribeInstances")
+	ctx = awsmiddleware.SetRegion(ctx, "us-east-1")
+
+	var seen []string
+	handler := middleware.DecorateHandler(middleware.HandlerFunc(func(ctx context.Context, input interface{}) (interface{}, middleware.Metadata, error) {
+		seen, _ = ctx.Value(hookContextKey{}).([]string)
+		return "reservations", middleware.Metadata{}, nil
+	}), stack)
+
+	output, _, err := handler.Handle(ctx, "input")
+	require.NoError(t, err)
+	assert.Equal(t, "reservations", output)
+	assert.Equal(t, []string{"first", "second"}, seen)
+	assert.Equal(t, []string{
+		"first.request EC2/DescribeInstances",
+		"second.request EC2/DescribeInstances",
+		"first.response reservations",
+		"second.response reservations",
+	}, j.entries)
+
+	chain.add(&recordingHook{name: "breaker", abort: errors.New("circuit open"), journal: j})
+	_, _, err = handler.Handle(ctx, "input")
+	assert.EqualError(t, err, "circuit open", "hook errors are returned to the caller")
+}
+
+func TestHookChainWithoutHooksPassesThrough(t *testing.T) {
+	chain := &hookChain{}
+	out, _, err := chain.HandleInitialize(context.Background(), middleware.InitializeInput{Parameters: "input"},
+		middleware.InitializeHandlerFunc(func(ctx context.Context, in middleware.InitializeInput) (middleware.InitializeOutput, middleware.Metadata, error) {
+			return middleware.InitializeOutput{Result: in.Parameters}, middleware.Metadata{}, nil
+		}))
+	require.NoError(t, err)
+	assert.Equal(t, "input", out.Result)
+}
@@ -0,0 +1,365 @@
+package aws
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	iamtypes "github.com/aws/aws-sdk-go-v2/service/iam/types"
+
+	"aws-mcp-server/pkg/types"
+
+	"github.com/sirupsen/logrus"
+)
+
+// ListIAMUsers retrieves all IAM users together with their access keys, console
+// access and MFA status
+func (c *Client) ListIAMUsers(ctx context.Context) ([]types.IAMUser, error) {
+	start := time.Now()
+
+	var users []types.IAMUser
+	paginator := iam.NewListUsersPaginator(c.iam, &iam.ListUsersInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			c.logger.WithError(err).Error("Failed to list IAM users")
+			return nil, fmt.Errorf("failed to list IAM users: %w", err)
+		}
+
+		for _, user := range page.Users {
+			converted, err := c.describeIAMUser(ctx, user)
+			if err != nil {
+				return nil, err
+			}
+			users = append(users, converted)
+		}
+	}
+
+	c.logger.WithFields(logrus.Fields{
+		"count":    len(users),
+		"duration": time.Since(start),
+	}).Info("Retrieved IAM users")
+
+	return users, nil
+}
+
+// ListAccessKeys retrieves the access keys of an IAM user, including when each key was last used
+func (c *Client) ListAccessKeys(ctx context.Context, userName string) ([]types.AccessKey, error) {
+	var keys []types.AccessKey
+	paginator := iam.NewListAccessKeysPaginator(c.iam, &iam.ListAccessKeysInput{
+		UserName: aws.String(userName),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list access keys for user %s: %w", userName, err)
+		}
+
+		for _, metadata := range page.AccessKeyMetadata {
+			key := types.AccessKey{
+				ID:         aws.ToString(metadata.AccessKeyId),
+				Status:     string(metadata.Status),
+				CreateDate: aws.ToTime(metadata.CreateDate),
+			}
+
+			lastUsed, err := c.iam.GetAccessKeyLastUsed(ctx, &iam.GetAccessKeyLastUsedInput{
+				AccessKeyId: metadata.AccessKeyId,
+			})
+			if err != nil {
+				return nil, fmt.Errorf("failed to get last use of access key %s: %w", key.ID, err)
+			}
+
+			if lastUsed.AccessKeyLastUsed != nil && lastUsed.AccessKeyLastUsed.LastUsedDate != nil {
+				key.LastUsed = lastUsed.AccessKeyLastUsed.LastUsedDate
+				key.LastUsedService = aws.ToString(lastUsed.AccessKeyLastUsed.ServiceName)
+			}
+
+			keys = append(keys, key)
+		}
+	}
+
+	return keys, nil
+}
+
+// DeactivateAccessKey marks an IAM access key as inactive. The key is kept so it
+// can be re-activated if something still depends on it.
+func (c *Client) DeactivateAccessKey(ctx context.Context, userName, accessKeyID string) error {
+	c.logger.WithFields(logrus.Fields{
+		"userName":    userName,
+		"accessKeyId": accessKeyID,
+	}).Info("Deactivating IAM access key")
+
+	_, err := c.iam.UpdateAccessKey(ctx, &iam.UpdateAccessKeyInput{
+		UserName:    aws.String(userName),
+		AccessKeyId: aws.String(accessKeyID),
+		Status:      iamtypes.StatusTypeInactive,
+	})
+	if err != nil {
+		c.logger.WithError(err).WithField("accessKeyId", accessKeyID).Error("Failed to deactivate IAM access key")
+		return fmt.Errorf("failed to deactivate access key %s: %w", accessKeyID, err)
+	}
+
+	c.logger.WithField("accessKeyId", accessKeyID).Info("IAM access key deactivated")
+	return nil
+}
+
+// describeIAMUser collects the credential details of a single IAM user
+func (c *Client) describeIAMUser(ctx context.Context, user iamtypes.User) (types.IAMUser, error) {
+	userName := aws.ToString(user.UserName)
+
+	result := types.IAMUser{
+		UserName:         userName,
+		ARN:              aws.ToString(user.Arn),
+		CreateDate:       aws.ToTime(user.CreateDate),
+		PasswordLastUsed: user.PasswordLastUsed,
+	}
+
+	keys, err := c.ListAccessKeys(ctx, userName)
+	if err != nil {
+		return result, err
+	}
+	result.AccessKeys = keys
+
+	// A user without a login profile has no console password
+	_, err = c.iam.GetLoginProfile(ctx, &iam.GetLoginProfileInput{
+		UserName: aws.String(userName),
+	})
+	var noSuchEntity *iamtypes.NoSuchEntityException
+	switch {
+	case err == nil:
+		result.ConsoleAccess = true
+	case !errors.As(err, &noSuchEntity):
+		return result, fmt.Errorf("failed to get login profile for user %s: %w", userName, err)
+	}
+
+	mfaDevices, err := c.iam.ListMFADevices(ctx, &iam.ListMFADevicesInput{
+		UserName: aws.String(userName),
+	})
+	if err != nil {
+		return result, fmt.Errorf("failed to list MFA devices for user %s: %w", userName, err)
+	}
+	result.MFAEnabled = len(mfaDevices.MFADevices) > 0
+
+	return result, nil
+}
+
+// ListIAMRoles retrieves all IAM roles with their trust policy and their
+// attached and inline policies
+func (c *Client) ListIAMRoles(ctx context.Context) ([]types.IAMRole, error) {
+	start := time.Now()
+
+	var roles []types.IAMRole
+	paginator := iam.NewGetAccountAuthorizationDetailsPaginator(c.iam, &iam.GetAccountAuthorizationDetailsInput{
+		Filter: []iamtypes.EntityType{iamtypes.EntityTypeRole},
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			c.logger.WithError(err).Error("Failed to get IAM role details")
+			return nil, fmt.Errorf("failed to get IAM role details: %w", err)
+		}
+
+		for _, role := range page.RoleDetailList {
+			converted := types.IAMRole{
+				RoleName:         aws.ToString(role.RoleName),
+				ARN:              aws.ToString(role.Arn),
+				Path:             aws.ToString(role.Path),
+				CreateDate:       aws.ToTime(role.CreateDate),
+				TrustPolicy:      decodePolicyDocument(role.AssumeRolePolicyDocument),
+				AttachedPolicies: convertAttachedPolicies(role.AttachedManagedPolicies),
+				InlinePolicies:   convertInlinePolicies(role.RolePolicyList),
+				Tags:             convertIAMTags(role.Tags),
+			}
+			if role.RoleLastUsed != nil {
+				converted.LastUsed = role.RoleLastUsed.LastUsedDate
+				converted.LastUsedRegion = aws.ToString(role.RoleLastUsed.Region)
+			}
+			if role.PermissionsBoundary != nil {
+				converted.PermissionsBoundary = aws.ToString(role.PermissionsBoundary.PermissionsBoundaryArn)
+			}
+			for _, profile := range role.InstanceProfileList {
+				converted.InstanceProfiles = append(converted.InstanceProfiles, aws.ToString(profile.InstanceProfileName))
+			}
+			roles = append(roles, converted)
+		}
+	}
+
+	c.logger.WithFields(logrus.Fields{
+		"count":    len(roles),
+		"duration": time.Since(start),
+	}).Info("Retrieved IAM roles")
+
+	return roles, nil
+}
+
+// ListIAMUserPermissions retrieves all IAM users with the policies attached to
+// them directly and through their groups
+func (c *Client) ListIAMUserPermissions(ctx context.Context) ([]types.IAMUserPermissions, error) {
+	start := time.Now()
+
+	var users []iamtypes.UserDetail
+	groups := make(map[string]types.IAMGroup)
+	paginator := iam.NewGetAccountAuthorizationDetailsPaginator(c.iam, &iam.GetAccountAuthorizationDetailsInput{
+		Filter: []iamtypes.EntityType{iamtypes.EntityTypeUser, iamtypes.EntityTypeGroup},
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			c.logger.WithError(err).Error("Failed to get IAM user details")
+			return nil, fmt.Errorf("failed to get IAM user details: %w", err)
+		}
+
+		users = append(users, page.UserDetailList...)
+		for _, group := range page.GroupDetailList {
+			groups[aws.ToString(group.GroupName)] = types.IAMGroup{
+				GroupName:        aws.ToString(group.GroupName),
+				ARN:              aws.ToString(group.Arn),
+				AttachedPolicies: convertAttachedPolicies(group.AttachedManagedPolicies),
+				InlinePolicies:   convertInlinePolicies(group.GroupPolicyList),
+			}
+		}
+	}
+
+	// Groups may be listed on a later page than their members
+	permissions := make([]types.IAMUserPermissions, 0, len(users))
+	for _, user := range users {
+		converted := types.IAMUserPermissions{
+			UserName:         aws.ToString(user.UserName),
+			ARN:              aws.ToString(user.Arn),
+			Path:             aws.ToString(user.Path),
+			CreateDate:       aws.ToTime(user.CreateDate),
+			AttachedPolicies: convertAttachedPolicies(user.AttachedManagedPolicies),
+			InlinePolicies:   convertInlinePolicies(user.UserPolicyList),
+			Tags:             convertIAMTags(user.Tags),
+		}
+		if user.PermissionsBoundary != nil {
+			converted.PermissionsBoundary = aws.ToString(user.PermissionsBoundary.PermissionsBoundaryArn)
+		}
+		for _, name := range user.GroupList {
+			group, ok := groups[name]
+			if !ok {
+				group = types.IAMGroup{GroupName: name}
+			}
+			converted.Groups = append(converted.Groups, group)
+		}
+		permissions = append(permissions, converted)
+	}
+
+	c.logger.WithFields(logrus.Fields{
+		"count":    len(permissions),
+		"duration": time.Since(start),
+	}).Info("Retrieved IAM user permissions")
+
+	return permissions, nil
+}
+
+// GetIAMPolicy retrieves a managed policy with the document of its default
+// version and the users, groups and roles it is attached to
+func (c *Client) GetIAMPolicy(ctx context.Context, policyARN string) (*types.IAMPolicy, error) {
+	start := time.Now()
+
+	result, err := c.iam.GetPolicy(ctx, &iam.GetPolicyInput{PolicyArn: aws.String(policyARN)})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get policy %s: %w", policyARN, err)
+	}
+	policy := result.Policy
+
+	converted := &types.IAMPolicy{
+		ARN:             aws.ToString(policy.Arn),
+		Name:            aws.ToString(policy.PolicyName),
+		Path:            aws.ToString(policy.Path),
+		Description:     aws.ToString(policy.Description),
+		AWSManaged:      strings.HasPrefix(policyARN, "arn:aws:iam::aws:policy/"),
+		DefaultVersion:  aws.ToString(policy.DefaultVersionId),
+		AttachmentCount: aws.ToInt32(policy.AttachmentCount),
+		CreateDate:      aws.ToTime(policy.CreateDate),
+		UpdateDate:      aws.ToTime(policy.UpdateDate),
+	}
+
+	version, err := c.iam.GetPolicyVersion(ctx, &iam.GetPolicyVersionInput{
+		PolicyArn: aws.String(policyARN),
+		VersionId: policy.DefaultVersionId,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get version %s of policy %s: %w", converted.DefaultVersion, policyARN, err)
+	}
+	if version.PolicyVersion != nil {
+		converted.Document = decodePolicyDocument(version.PolicyVersion.Document)
+	}
+
+	paginator := iam.NewListEntitiesForPolicyPaginator(c.iam, &iam.ListEntitiesForPolicyInput{PolicyArn: aws.String(policyARN)})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list entities of policy %s: %w", policyARN, err)
+		}
+		for _, user := range page.PolicyUsers {
+			converted.Users = append(converted.Users, aws.ToString(user.UserName))
+		}
+		for _, group := range page.PolicyGroups {
+			converted.Groups = append(converted.Groups, aws.ToString(group.GroupName))
+		}
+		for _, role := range page.PolicyRoles {
+			converted.Roles = append(converted.Roles, aws.ToString(role.RoleName))
+		}
+	}
+
+	c.logger.WithFields(logrus.Fields{
+		"policyArn": policyARN,
+		"duration":  time.Since(start),
+	}).Info("Retrieved IAM policy")
+
+	return converted, nil
+}
+
+// convertAttachedPolicies converts managed policy attachments to our standard format
+func convertAttachedPolicies(attached []iamtypes.AttachedPolicy) []types.IAMPolicyRef {
+	policies := make([]types.IAMPolicyRef, 0, len(attached))
+	for _, policy := range attached {
+		policies = append(policies, types.IAMPolicyRef{
+			Name: aws.ToString(policy.PolicyName),
+			ARN:  aws.ToString(policy.PolicyArn),
+		})
+	}
+	return policies
+}
+
+// convertInlinePolicies converts inline policies to our standard format
+func convertInlinePolicies(details []iamtypes.PolicyDetail) []types.IAMInlinePolicy {
+	policies := make([]types.IAMInlinePolicy, 0, len(details))
+	for _, detail := range details {
+		policies = append(policies, types.IAMInlinePolicy{
+			Name:     aws.ToString(detail.PolicyName),
+			Document: decodePolicyDocument(detail.PolicyDocument),
+		})
+	}
+	return policies
+}
+
+// convertIAMTags converts IAM tags to a simple key/value map
+func convertIAMTags(iamTags []iamtypes.Tag) map[string]string {
+	if len(iamTags) == 0 {
+		return nil
+	}
+	tags := make(map[string]string, len(iamTags))
+	for _, tag := range iamTags {
+		tags[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+	}
+	return tags
+}
+
+// decodePolicyDocument returns the JSON of a policy document; IAM returns
+// documents URL-encoded (RFC 3986)
+func decodePolicyDocument(document *string) string {
+	encoded := aws.ToString(document)
+	decoded, err := url.PathUnescape(encoded)
+	if err != nil {
+		return encoded
+	}
+	return decoded
+}
@@ -0,0 +1,61 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+
+	"aws-mcp-server/pkg/types"
+
+	"github.com/sirupsen/logrus"
+)
+
+// ListMachineImages retrieves the AMIs owned by the current account
+func (c *Client) ListMachineImages(ctx context.Context) ([]types.MachineImage, error) {
+	start := time.Now()
+
+	var images []types.MachineImage
+	paginator := ec2.NewDescribeImagesPaginator(c.ec2, &ec2.DescribeImagesInput{
+		Owners: []string{"self"},
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			c.logger.WithError(err).Error("Failed to describe AMIs")
+			return nil, fmt.Errorf("failed to describe images: %w", err)
+		}
+
+		for _, image := range page.Images {
+			converted := types.MachineImage{
+				ID:     aws.ToString(image.ImageId),
+				Name:   aws.ToString(image.Name),
+				State:  string(image.State),
+				Public: aws.ToBool(image.Public),
+				Tags:   convertTags(image.Tags),
+			}
+			if created, err := time.Parse(time.RFC3339, aws.ToString(image.CreationDate)); err == nil {
+				converted.CreatedAt = created
+			}
+			for _, mapping := range image.BlockDeviceMappings {
+				if mapping.Ebs == nil {
+					continue
+				}
+				if snapshotID := aws.ToString(mapping.Ebs.SnapshotId); snapshotID != "" {
+					converted.SnapshotIDs = append(converted.SnapshotIDs, snapshotID)
+				}
+				converted.SizeGiB += aws.ToInt32(mapping.Ebs.VolumeSize)
+			}
+			images = append(images, converted)
+		}
+	}
+
+	c.logger.WithFields(logrus.Fields{
+		"count":    len(images),
+		"duration": time.Since(start),
+	}).Info("Retrieved AMIs")
+
+	return images, nil
+}
@@ -0,0 +1,210 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+	cwtypes "github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+	"github.com/aws/aws-sdk-go-v2/service/kinesis"
+	kinesistypes "github.com/aws/aws-sdk-go-v2/service/kinesis/types"
+
+	"aws-mcp-server/pkg/types"
+
+	"github.com/sirupsen/logrus"
+)
+
+// KinesisMetricsWindow is how far back the stream metrics are read
+const KinesisMetricsWindow = 15 * time.Minute
+
+// kinesisStreamMetrics are the CloudWatch metrics read for each stream, with
+// the statistic used for each one-minute datapoint
+var kinesisStreamMetrics = []struct {
+	name      string
+	statistic string
+}{
+	{"GetRecords.IteratorAgeMilliseconds", "Maximum"},
+	{"IncomingBytes", "Sum"},
+	{"IncomingRecords", "Sum"},
+	{"WriteProvisionedThroughputExceeded", "Sum"},
+	{"ReadProvisionedThroughputExceeded", "Sum"},
+}
+
+// ListKinesisStreams retrieves all Kinesis data streams with their shard
+// counts and recent throughput metrics
+func (c *Client) ListKinesisStreams(ctx context.Context) ([]types.KinesisStream, error) {
+	start := time.Now()
+
+	var names []string
+	paginator := kinesis.NewListStreamsPaginator(c.kinesis, &kinesis.ListStreamsInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			c.logger.WithError(err).Error("Failed to list Kinesis streams")
+			return nil, fmt.Errorf("failed to list Kinesis streams: %w", err)
+		}
+		for _, summary := range page.StreamSummaries {
+			names = append(names, aws.ToString(summary.StreamName))
+		}
+	}
+
+	streams := make([]types.KinesisStream, 0, len(names))
+	for _, name := range names {
+		stream, err := c.describeKinesisStream(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		streams = append(streams, *stream)
+	}
+	c.addKinesisMetrics(ctx, streams)
+
+	c.logger.WithFields(logrus.Fields{
+		"count":    len(streams),
+		"duration": time.Since(start),
+	}).Info("Retrieved Kinesis streams")
+
+	return streams, nil
+}
+
+// GetKinesisStream retrieves one stream by name with its recent metrics
+func (c *Client) GetKinesisStream(ctx context.Context, name string) (*types.KinesisStream, error) {
+	stream, err := c.describeKinesisStream(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	streams := []types.KinesisStream{*stream}
+	c.addKinesisMetrics(ctx, streams)
+	return &streams[0], nil
+}
+
+// describeKinesisStream reads the summary of a stream
+func (c *Client) describeKinesisStream(ctx context.Context, name string) (*types.KinesisStream, error) {
+	result, err := c.kinesis.DescribeStreamSummary(ctx, &kinesis.DescribeStreamSummaryInput{StreamName: aws.String(name)})
+	if err != nil {
+		c.logger.WithError(err).WithField("stream", name).Error("Failed to describe Kinesis stream")
+		return nil, fmt.Errorf("failed to describe Kinesis stream %s: %w", name, err)
+	}
+	return convertKinesisStream(result.StreamDescriptionSummary), nil
+}
+
+// convertKinesisStream converts a stream summary; streams created before
+// on-demand mode existed have no mode details and are provisioned
+func convertKinesisStream(summary *kinesistypes.StreamDescriptionSummary) *types.KinesisStream {
+	stream := &types.KinesisStream{
+		Name:           aws.ToString(summary.StreamName),
+		ARN:            aws.ToString(summary.StreamARN),
+		Status:         string(summary.StreamStatus),
+		Mode:           string(kinesistypes.StreamModeProvisioned),
+		OpenShards:     aws.ToInt32(summary.OpenShardCount),
+		Consumers:      aws.ToInt32(summary.ConsumerCount),
+		RetentionHours: aws.ToInt32(summary.RetentionPeriodHours),
+		Encryption:     string(summary.EncryptionType),
+		CreatedAt:      aws.ToTime(summary.StreamCreationTimestamp),
+	}
+	if summary.StreamModeDetails != nil {
+		stream.Mode = string(summary.StreamModeDetails.StreamMode)
+	}
+	return stream
+}
+
+// addKinesisMetrics fills in the metrics of each stream over the metrics
+// window. The metrics are best effort: streams keep nil metrics when
+// CloudWatch cannot be read.
+func (c *Client) addKinesisMetrics(ctx context.Context, streams []types.KinesisStream) {
+	end := time.Now()
+	perBatch := sqsMetricQueriesBatch / len(kinesisStreamMetrics)
+	for batch := range slices.Chunk(streams, perBatch) {
+		queries := make([]cwtypes.MetricDataQuery, 0, len(batch)*len(kinesisStreamMetrics))
+		for i, stream := range batch {
+			for m, metric := range kinesisStreamMetrics {
+				queries = append(queries, cwtypes.MetricDataQuery{
+					Id: aws.String(fmt.Sprintf("s%d_m%d", i, m)),
+					MetricStat: &cwtypes.MetricStat{
+						Metric: &cwtypes.Metric{
+							Namespace:  aws.String("AWS/Kinesis"),
+							MetricName: aws.String(metric.name),
+							Dimensions: []cwtypes.Dimension{{Name: aws.String("StreamName"), Value: aws.String(stream.Name)}},
+						},
+						Period: aws.Int32(60),
+						Stat:   aws.String(metric.statistic),
+					},
+				})
+			}
+		}
+
+		values := make(map[string][]float64, len(queries))
+		paginator := cloudwatch.NewGetMetricDataPaginator(c.cloudwatch, &cloudwatch.GetMetricDataInput{
+			StartTime:         aws.Time(end.Add(-KinesisMetricsWindow)),
+			EndTime:           aws.Time(end),
+			MetricDataQueries: queries,
+		})
+		for paginator.HasMorePages() {
+			page, err := paginator.NextPage(ctx)
+			if err != nil {
+				c.logger.WithError(err).Warn("Failed to get Kinesis stream metrics")
+				return
+			}
+			for _, result := range page.MetricDataResults {
+				id := aws.ToString(result.Id)
+				values[id] = append(values[id], result.Values...)
+			}
+		}
+
+		for i := range batch {
+			series := func(m int) []float64 { return values[fmt.Sprintf("s%d_m%d", i, m)] }
+			batch[i].Metrics = &types.KinesisStreamMetrics{
+				WindowMinutes:        int(KinesisMetricsWindow.Minutes()),
+				IteratorAgeMs:        maxValue(series(0)),
+				IncomingBytes:        sumValues(series(1)),
+				IncomingRecords:      sumValues(series(2)),
+				WriteThrottled:       sumValues(series(3)),
+				ReadThrottled:        sumValues(series(4)),
+				PeakBytesPerSecond:   maxValue(series(1)) / 60,
+				PeakRecordsPerSecond: maxValue(series(2)) / 60,
+			}
+		}
+	}
+}
+
+// UpdateShardCount scales a provisioned stream to the target number of open
+// shards, splitting or merging shards uniformly. It returns the shard count
+// the stream had when scaling started.
+func (c *Client) UpdateShardCount(ctx context.Context, name string, target int32) (int32, error) {
+	result, err := c.kinesis.UpdateShardCount(ctx, &kinesis.UpdateShardCountInput{
+		StreamName:       aws.String(name),
+		TargetShardCount: aws.Int32(target),
+		ScalingType:      kinesistypes.ScalingTypeUniformScaling,
+	})
+	if err != nil {
+		c.logger.WithError(err).WithField("stream", name).Error("Failed to update Kinesis shard count")
+		return 0, fmt.Errorf("failed to update shard count of %s: %w", name, err)
+	}
+
+	c.logger.WithFields(logrus.Fields{
+		"stream": name,
+		"from":   aws.ToInt32(result.CurrentShardCount),
+		"to":     target,
+	}).Info("Updated Kinesis shard count")
+
+	return aws.ToInt32(result.CurrentShardCount), nil
+}
+
+// maxValue returns the largest value, or 0 for none
+func maxValue(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	return slices.Max(values)
+}
+
+// sumValues returns the sum of the values
+func sumValues(values []float64) float64 {
+	var sum float64
+	for _, value := range values {
+		sum += value
+	}
+	return sum
+}
@@ -0,0 +1,98 @@
+package aws
+
+import (
+	"context"
+	"strings"
+	"time"
+)
+
+// ConcurrencyLimiter is a Hook that caps the AWS API calls in flight, across
+// all services and per service, so fan-out over many resources or regions
+// queues behind semaphores instead of tripping account-wide API throttling.
+// A call holds its slots through the SDK's retries and waits for them until
+// its context is done.
+type ConcurrencyLimiter struct {
+	global chan struct{}
+	// services holds the semaphores of the limited services by lowercase
+	// service ID, e.g. ec2 or cloudwatch logs
+	services map[string]chan struct{}
+}
+
+// limiterSlots marks a call's context with the slots it holds
+type limiterSlots struct {
+	limiter *ConcurrencyLimiter
+}
+
+// NewConcurrencyLimiter creates a limiter allowing limit calls at once and,
+// per service, the calls in perService, keyed by SDK service ID in any case.
+// Limits of zero or less leave calls unlimited. It returns nil when nothing
+// is limited.
+func NewConcurrencyLimiter(limit int, perService map[string]int) *ConcurrencyLimiter {
+	l := &ConcurrencyLimiter{services: make(map[string]chan struct{})}
+	if limit > 0 {
+		l.global = make(chan struct{}, limit)
+	}
+	for service, serviceLimit := range perService {
+		if serviceLimit > 0 {
+			l.services[strings.ToLower(service)] = make(chan struct{}, serviceLimit)
+		}
+	}
+
+	if l.global == nil && len(l.services) == 0 {
+		return nil
+	}
+	return l
+}
+
+// OnRequest waits for a slot of the call's service, then for a global one.
+// Slots are always taken in that order, so calls cannot deadlock.
+func (l *ConcurrencyLimiter) OnRequest(ctx context.Context, call Call) (context.Context, error) {
+	service := l.services[strings.ToLower(call.Service)]
+	if err := acquire(ctx, service); err != nil {
+		return ctx, err
+	}
+	if err := acquire(ctx, l.global); err != nil {
+		release(service)
+		return ctx, err
+	}
+	return context.WithValue(ctx, limiterSlots{l}, true), nil
+}
+
+// OnResponse frees the call's slots
+func (l *ConcurrencyLimiter) OnResponse(ctx context.Context, call Call, output interface{}, duration time.Duration) {
+	l.done(ctx, call)
+}
+
+// OnError frees the call's slots, unless it failed waiting for them
+func (l *ConcurrencyLimiter) OnError(ctx context.Context, call Call, err error, duration time.Duration) {
+	l.done(ctx, call)
+}
+
+// done frees the slots a call holds
+func (l *ConcurrencyLimiter) done(ctx context.Context, call Call) {
+	if ctx.Value(limiterSlots{l}) == nil {
+		return
+	}
+	release(l.global)
+	release(l.services[strings.ToLower(call.Service)])
+}
+
+// acquire takes a slot of a semaphore; a nil semaphore is unlimited
+func acquire(ctx context.Context, semaphore chan struct{}) error {
+	if semaphore == nil {
+		return nil
+	}
+	select {
+	case semaphore <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// release frees a slot of a semaphore
+func release(semaphore chan struct{}) {
+	if semaphore != nil {
+		<-semaphore
+	}
+}
@@ -0,0 +1,182 @@
+package aws
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
+	logstypes "github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs/types"
+
+	"aws-mcp-server/pkg/types"
+
+	"github.com/sirupsen/logrus"
+)
+
+// insightsPollInterval is how often a running Logs Insights query is checked
+const insightsPollInterval = time.Second
+
+// ErrInsightsTimeout is returned when a Logs Insights query does not finish in time
+var ErrInsightsTimeout = errors.New("logs insights query did not complete in time")
+
+// InsightsQueryParams describes a Logs Insights query over one or more log groups
+type InsightsQueryParams struct {
+	LogGroups []string
+	Query     string
+	Start     time.Time
+	End       time.Time
+	Limit     int32
+	Timeout   time.Duration
+}
+
+// FilterLogEvents retrieves up to limit events of a log group between start and
+// end that match a CloudWatch Logs filter pattern. An empty pattern matches all events.
+func (c *Client) FilterLogEvents(ctx context.Context, logGroup, pattern string, start, end time.Time, limit int) ([]types.LogEvent, error) {
+	began := time.Now()
+
+	input := &cloudwatchlogs.FilterLogEventsInput{
+		LogGroupName: aws.String(logGroup),
+		StartTime:    aws.Int64(start.UnixMilli()),
+		EndTime:      aws.Int64(end.UnixMilli()),
+	}
+	if pattern != "" {
+		input.FilterPattern = aws.String(pattern)
+	}
+
+	var events []types.LogEvent
+	paginator := cloudwatchlogs.NewFilterLogEventsPaginator(c.logs, input)
+	for paginator.HasMorePages() && len(events) < limit {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			c.logger.WithError(err).WithField("logGroup", logGroup).Error("Failed to filter log events")
+			return nil, fmt.Errorf("failed to filter log events in %s: %w", logGroup, err)
+		}
+
+		for _, event := range page.Events {
+			if len(events) == limit {
+				break
+			}
+			events = append(events, types.LogEvent{
+				Timestamp: time.UnixMilli(aws.ToInt64(event.Timestamp)),
+				LogGroup:  logGroup,
+				LogStream: aws.ToString(event.LogStreamName),
+				Message:   aws.ToString(event.Message),
+			})
+		}
+	}
+
+	c.logger.WithFields(logrus.Fields{
+		"logGroup": logGroup,
+		"count":    len(events),
+		"duration": time.Since(began),
+	}).Info("Retrieved log events")
+
+	return events, nil
+}
+
+// RunInsightsQuery starts a Logs Insights query and polls until it completes.
+// When Timeout passes first, the query is stopped and ErrInsightsTimeout is
+// returned together with the query ID.
+func (c *Client) RunInsightsQuery(ctx context.Context, params InsightsQueryParams) (*types.InsightsResult, error) {
+	began := time.Now()
+
+	input := &cloudwatchlogs.StartQueryInput{
+		LogGroupNames: params.LogGroups,
+		QueryString:   aws.String(params.Query),
+		StartTime:     aws.Int64(params.Start.Unix()),
+		EndTime:       aws.Int64(params.End.Unix()),
+	}
+	if params.Limit > 0 {
+		input.Limit = aws.Int32(params.Limit)
+	}
+
+	started, err := c.logs.StartQuery(ctx, input)
+	if err != nil {
+		c.logger.WithError(err).WithField("logGroups", params.LogGroups).Error("Failed to start Logs Insights query")
+		return nil, fmt.Errorf("failed to start logs insights query: %w", err)
+	}
+	queryID := aws.ToString(started.QueryId)
+
+	deadline := time.NewTimer(params.Timeout)
+	defer deadline.Stop()
+	ticker := time.NewTicker(insightsPollInterval)
+	defer ticker.Stop()
+
+	for {
+		output, err := c.logs.GetQueryResults(ctx, &cloudwatchlogs.GetQueryResultsInput{QueryId: aws.String(queryID)})
+		if err != nil {
+			c.logger.WithError(err).WithField("queryId", queryID).Error("Failed to get Logs Insights results")
+			return nil, fmt.Errorf("failed to get results of logs insights query %s: %w", queryID, err)
+		}
+
+		switch output.Status {
+		case logstypes.QueryStatusComplete:
+			result := convertInsightsResult(queryID, output)
+			c.logger.WithFields(logrus.Fields{
+				"queryId":  queryID,
+				"count":    len(result.Rows),
+				"duration": time.Since(began),
+			}).Info("Completed Logs Insights query")
+			return result, nil
+		case logstypes.QueryStatusFailed, logstypes.QueryStatusCancelled, logstypes.QueryStatusTimeout:
+			return nil, fmt.Errorf("logs insights query %s ended with status %s", queryID, output.Status)
+		}
+
+		select {
+		case <-ctx.Done():
+			c.stopInsightsQuery(queryID)
+			return nil, ctx.Err()
+		case <-deadline.C:
+			c.stopInsightsQuery(queryID)
+			return &types.InsightsResult{QueryID: queryID, Status: string(output.Status)}, ErrInsightsTimeout
+		case <-ticker.C:
+		}
+	}
+}
+
+// stopInsightsQuery cancels a running query so it stops scanning (and billing).
+// It uses its own context because the caller's may already be done.
+func (c *Client) stopInsightsQuery(queryID string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if _, err := c.logs.StopQuery(ctx, &cloudwatchlogs.StopQueryInput{QueryId: aws.String(queryID)}); err != nil {
+		c.logger.WithError(err).WithField("queryId", queryID).Warn("Failed to stop Logs Insights query")
+	}
+}
+
+// convertInsightsResult flattens result rows into field maps. The @ptr field
+// is an internal record pointer and is dropped.
+func convertInsightsResult(queryID string, output *cloudwatchlogs.GetQueryResultsOutput) *types.InsightsResult {
+	result := &types.InsightsResult{
+		QueryID: queryID,
+		Status:  string(output.Status),
+		Rows:    make([]map[string]string, 0, len(output.Results)),
+	}
+	if output.Statistics != nil {
+		result.RecordsMatched = output.Statistics.RecordsMatched
+		result.RecordsScanned = output.Statistics.RecordsScanned
+		result.BytesScanned = output.Statistics.BytesScanned
+	}
+
+	seen := make(map[string]bool)
+	for _, fields := range output.Results {
+		row := make(map[string]string, len(fields))
+		for _, field := range fields {
+			name := aws.ToString(field.Field)
+			if name == "@ptr" {
+				continue
+			}
+			row[name] = aws.ToString(field.Value)
+			if !seen[name] {
+				seen[name] = true
+				result.Fields = append(result.Fields, name)
+			}
+		}
+		result.Rows = append(result.Rows, row)
+	}
+
+	return result
+}
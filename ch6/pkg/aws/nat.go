@@ -0,0 +1,187 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+	cwtypes "github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+
+	"aws-mcp-server/pkg/types"
+
+	"github.com/sirupsen/logrus"
+)
+
+// NATMetricsWindow is how far back the NAT gateway metrics are summed
+const NATMetricsWindow = time.Hour
+
+// natMetrics are the CloudWatch metrics read for each NAT gateway, with the
+// statistic over the whole window
+var natMetrics = []struct {
+	name      string
+	statistic string
+}{
+	{"BytesInFromSource", "Sum"},
+	{"BytesInFromDestination", "Sum"},
+	{"ErrorPortAllocation", "Sum"},
+	{"PacketsDropCount", "Sum"},
+	{"ActiveConnectionCount", "Maximum"},
+}
+
+// ListNATGateways retrieves the NAT gateways of the region with their metrics
+// over the last NATMetricsWindow. Deleted gateways are left out.
+func (c *Client) ListNATGateways(ctx context.Context) ([]types.NATGateway, error) {
+	start := time.Now()
+
+	var gateways []types.NATGateway
+	paginator := ec2.NewDescribeNatGatewaysPaginator(c.ec2, &ec2.DescribeNatGatewaysInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			c.logger.WithError(err).Error("Failed to describe NAT gateways")
+			return nil, fmt.Errorf("failed to describe NAT gateways: %w", err)
+		}
+		for _, gateway := range page.NatGateways {
+			if gateway.State == ec2types.NatGatewayStateDeleted {
+				continue
+			}
+			gateways = append(gateways, convertNATGateway(gateway))
+		}
+	}
+
+	c.addNATMetrics(ctx, gateways)
+
+	c.logger.WithFields(logrus.Fields{
+		"count":    len(gateways),
+		"duration": time.Since(start),
+	}).Info("Retrieved NAT gateways")
+
+	return gateways, nil
+}
+
+// ListVPCEndpoints retrieves the VPC endpoints of the region
+func (c *Client) ListVPCEndpoints(ctx context.Context) ([]types.VPCEndpoint, error) {
+	start := time.Now()
+
+	var endpoints []types.VPCEndpoint
+	paginator := ec2.NewDescribeVpcEndpointsPaginator(c.ec2, &ec2.DescribeVpcEndpointsInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			c.logger.WithError(err).Error("Failed to describe VPC endpoints")
+			return nil, fmt.Errorf("failed to describe VPC endpoints: %w", err)
+		}
+		for _, endpoint := range page.VpcEndpoints {
+			endpoints = append(endpoints, types.VPCEndpoint{
+				ID:                aws.ToString(endpoint.VpcEndpointId),
+				Name:              nameTag(endpoint.Tags),
+				VpcID:             aws.ToString(endpoint.VpcId),
+				ServiceName:       aws.ToString(endpoint.ServiceName),
+				Type:              string(endpoint.VpcEndpointType),
+				State:             string(endpoint.State),
+				PrivateDNSEnabled: aws.ToBool(endpoint.PrivateDnsEnabled),
+				SubnetIDs:         endpoint.SubnetIds,
+				RouteTableIDs:     endpoint.RouteTableIds,
+				Created:           aws.ToTime(endpoint.CreationTimestamp),
+			})
+		}
+	}
+
+	c.logger.WithFields(logrus.Fields{
+		"count":    len(endpoints),
+		"duration": time.Since(start),
+	}).Info("Retrieved VPC endpoints")
+
+	return endpoints, nil
+}
+
+// convertNATGateway converts a NAT gateway to our standard format
+func convertNATGateway(gateway ec2types.NatGateway) types.NATGateway {
+	converted := types.NATGateway{
+		ID:               aws.ToString(gateway.NatGatewayId),
+		Name:             nameTag(gateway.Tags),
+		VpcID:            aws.ToString(gateway.VpcId),
+		SubnetID:         aws.ToString(gateway.SubnetId),
+		State:            string(gateway.State),
+		ConnectivityType: string(gateway.ConnectivityType),
+		Addresses:        len(gateway.NatGatewayAddresses),
+		FailureMessage:   aws.ToString(gateway.FailureMessage),
+	}
+	for _, address := range gateway.NatGatewayAddresses {
+		if ip := aws.ToString(address.PublicIp); ip != "" {
+			converted.PublicIP = ip
+			break
+		}
+	}
+	return converted
+}
+
+// addNATMetrics fills in the metrics of each NAT gateway over the window. The
+// metrics are best effort: gateways keep nil metrics when CloudWatch cannot be
+// read.
+func (c *Client) addNATMetrics(ctx context.Context, gateways []types.NATGateway) {
+	end := time.Now()
+	perBatch := sqsMetricQueriesBatch / len(natMetrics)
+	for batch := range slices.Chunk(gateways, perBatch) {
+		queries := make([]cwtypes.MetricDataQuery, 0, len(batch)*len(natMetrics))
+		statistics := make(map[string]string, cap(queries))
+		for i, gateway := range batch {
+			for m, metric := range natMetrics {
+				id := fmt.Sprintf("n%d_m%d", i, m)
+				statistics[id] = metric.statistic
+				queries = append(queries, cwtypes.MetricDataQuery{
+					Id: aws.String(id),
+					MetricStat: &cwtypes.MetricStat{
+						Metric: &cwtypes.Metric{
+							Namespace:  aws.String("AWS/NATGateway"),
+							MetricName: aws.String(metric.name),
+							Dimensions: []cwtypes.Dimension{{Name: aws.String("NatGatewayId"), Value: aws.String(gateway.ID)}},
+						},
+						Period: aws.Int32(int32(NATMetricsWindow / time.Second)),
+						Stat:   aws.String(metric.statistic),
+					},
+				})
+			}
+		}
+
+		// One period covers the window; a window spanning two periods adds up
+		values := make(map[string]float64, len(queries))
+		paginator := cloudwatch.NewGetMetricDataPaginator(c.cloudwatch, &cloudwatch.GetMetricDataInput{
+			StartTime:         aws.Time(end.Add(-NATMetricsWindow)),
+			EndTime:           aws.Time(end),
+			MetricDataQueries: queries,
+		})
+		for paginator.HasMorePages() {
+			page, err := paginator.NextPage(ctx)
+			if err != nil {
+				c.logger.WithError(err).Warn("Failed to get NAT gateway metrics")
+				return
+			}
+			for _, result := range page.MetricDataResults {
+				id := aws.ToString(result.Id)
+				for _, value := range result.Values {
+					if statistics[id] == "Maximum" {
+						values[id] = max(values[id], value)
+					} else {
+						values[id] += value
+					}
+				}
+			}
+		}
+
+		for i := range batch {
+			value := func(m int) float64 { return values[fmt.Sprintf("n%d_m%d", i, m)] }
+			batch[i].Metrics = &types.NATGatewayMetrics{
+				BytesProcessed:      value(0) + value(1),
+				ErrorPortAllocation: value(2),
+				PacketsDropped:      value(3),
+				ActiveConnections:   value(4),
+			}
+		}
+	}
+}
@@ -0,0 +1,657 @@
+package aws
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+
+	"aws-mcp-server/pkg/types"
+
+	"github.com/sirupsen/logrus"
+)
+
+// ListSecurityGroups retrieves all security groups in the region with their ingress rules
+func (c *Client) ListSecurityGroups(ctx context.Context) ([]types.SecurityGroup, error) {
+	start := time.Now()
+
+	var groups []types.SecurityGroup
+	paginator := ec2.NewDescribeSecurityGroupsPaginator(c.ec2, &ec2.DescribeSecurityGroupsInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			c.logger.WithError(err).Error("Failed to describe security groups")
+			return nil, fmt.Errorf("failed to describe security groups: %w", err)
+		}
+
+		for _, group := range page.SecurityGroups {
+			groups = append(groups, convertSecurityGroup(group))
+		}
+	}
+
+	c.logger.WithFields(logrus.Fields{
+		"count":    len(groups),
+		"duration": time.Since(start),
+	}).Info("Retrieved security groups")
+
+	return groups, nil
+}
+
+// convertSecurityGroup converts an AWS security group to our standard format
+func convertSecurityGroup(group ec2types.SecurityGroup) types.SecurityGroup {
+	converted := types.SecurityGroup{
+		ID:          aws.ToString(group.GroupId),
+		Name:        aws.ToString(group.GroupName),
+		Description: aws.ToString(group.Description),
+		VpcID:       aws.ToString(group.VpcId),
+	}
+
+	for _, permission := range group.IpPermissions {
+		converted.Ingress = append(converted.Ingress, convertPermission(permission))
+	}
+	for _, permission := range group.IpPermissionsEgress {
+		converted.Egress = append(converted.Egress, convertPermission(permission))
+	}
+
+	return converted
+}
+
+// convertPermission converts one permission of a security group
+func convertPermission(permission ec2types.IpPermission) types.SecurityGroupRule {
+	rule := types.SecurityGroupRule{
+		Protocol: aws.ToString(permission.IpProtocol),
+		FromPort: aws.ToInt32(permission.FromPort),
+		ToPort:   aws.ToInt32(permission.ToPort),
+	}
+
+	for _, ipRange := range permission.IpRanges {
+		rule.CIDRs = append(rule.CIDRs, aws.ToString(ipRange.CidrIp))
+	}
+	for _, ipRange := range permission.Ipv6Ranges {
+		rule.CIDRs = append(rule.CIDRs, aws.ToString(ipRange.CidrIpv6))
+	}
+	for _, pair := range permission.UserIdGroupPairs {
+		rule.SourceGroups = append(rule.SourceGroups, aws.ToString(pair.GroupId))
+	}
+
+	return rule
+}
+
+// IngressPermission is one inbound rule to add to or remove from a security
+// group. The source is either CIDR or SourceGroupID. Ports are -1 for the
+// icmp and all (-1) protocols.
+type IngressPermission struct {
+	Protocol      string
+	FromPort      int32
+	ToPort        int32
+	CIDR          string
+	SourceGroupID string
+	Description   string
+}
+
+// ipPermission converts the rule to the EC2 API format
+func (p IngressPermission) ipPermission() ec2types.IpPermission {
+	permission := ec2types.IpPermission{
+		IpProtocol: aws.String(p.Protocol),
+		FromPort:   aws.Int32(p.FromPort),
+		ToPort:     aws.Int32(p.ToPort),
+	}
+
+	var description *string
+	if p.Description != "" {
+		description = aws.String(p.Description)
+	}
+
+	switch {
+	case p.SourceGroupID != "":
+		permission.UserIdGroupPairs = []ec2types.UserIdGroupPair{{GroupId: aws.String(p.SourceGroupID), Description: description}}
+	case strings.Contains(p.CIDR, ":"):
+		permission.Ipv6Ranges = []ec2types.Ipv6Range{{CidrIpv6: aws.String(p.CIDR), Description: description}}
+	default:
+		permission.IpRanges = []ec2types.IpRange{{CidrIp: aws.String(p.CIDR), Description: description}}
+	}
+	return permission
+}
+
+// ErrRuleNotFound is returned when a security group has no rule matching the one to revoke
+var ErrRuleNotFound = errors.New("no matching rule")
+
+// GetSecurityGroup retrieves a specific security group with its rules
+func (c *Client) GetSecurityGroup(ctx context.Context, groupID string) (*types.SecurityGroup, error) {
+	result, err := c.ec2.DescribeSecurityGroups(ctx, &ec2.DescribeSecurityGroupsInput{
+		GroupIds: []string{groupID},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe security group %s: %w", groupID, err)
+	}
+
+	if len(result.SecurityGroups) == 0 {
+		return nil, fmt.Errorf("security group %s not found", groupID)
+	}
+
+	group := convertSecurityGroup(result.SecurityGroups[0])
+	return &group, nil
+}
+
+// AuthorizeSecurityGroupIngress adds an inbound rule to a security group and
+// returns the IDs of the created rules
+func (c *Client) AuthorizeSecurityGroupIngress(ctx context.Context, groupID string, permission IngressPermission) ([]string, error) {
+	logger := c.logger.WithFields(logrus.Fields{
+		"groupId":  groupID,
+		"protocol": permission.Protocol,
+		"fromPort": permission.FromPort,
+		"toPort":   permission.ToPort,
+		"cidr":     permission.CIDR,
+		"source":   permission.SourceGroupID,
+	})
+	logger.Info("Authorizing security group ingress")
+
+	result, err := c.ec2.AuthorizeSecurityGroupIngress(ctx, &ec2.AuthorizeSecurityGroupIngressInput{
+		GroupId:       aws.String(groupID),
+		IpPermissions: []ec2types.IpPermission{permission.ipPermission()},
+	})
+	if err != nil {
+		logger.WithError(err).Error("Failed to authorize security group ingress")
+		return nil, fmt.Errorf("failed to authorize ingress on security group %s: %w", groupID, err)
+	}
+
+	ruleIDs := make([]string, 0, len(result.SecurityGroupRules))
+	for _, rule := range result.SecurityGroupRules {
+		ruleIDs = append(ruleIDs, aws.ToString(rule.SecurityGroupRuleId))
+	}
+
+	logger.Info("Security group ingress authorized")
+	return ruleIDs, nil
+}
+
+// RevokeSecurityGroupIngress removes an inbound rule from a security group and
+// returns the IDs of the removed rules. The rule must match exactly, otherwise
+// ErrRuleNotFound is returned.
+func (c *Client) RevokeSecurityGroupIngress(ctx context.Context, groupID string, permission IngressPermission) ([]string, error) {
+	logger := c.logger.WithFields(logrus.Fields{
+		"groupId":  groupID,
+		"protocol": permission.Protocol,
+		"fromPort": permission.FromPort,
+		"toPort":   permission.ToPort,
+		"cidr":     permission.CIDR,
+		"source":   permission.SourceGroupID,
+	})
+	logger.Info("Revoking security group ingress")
+
+	// Rules are matched without their descriptions
+	permission.Description = ""
+	result, err := c.ec2.RevokeSecurityGroupIngress(ctx, &ec2.RevokeSecurityGroupIngressInput{
+		GroupId:       aws.String(groupID),
+		IpPermissions: []ec2types.IpPermission{permission.ipPermission()},
+	})
+	if err != nil {
+		logger.WithError(err).Error("Failed to revoke security group ingress")
+		return nil, fmt.Errorf("failed to revoke ingress on security group %s: %w", groupID, err)
+	}
+	if len(result.UnknownIpPermissions) > 0 {
+		return nil, fmt.Errorf("security group %s: %w", groupID, ErrRuleNotFound)
+	}
+
+	ruleIDs := make([]string, 0, len(result.RevokedSecurityGroupRules))
+	for _, rule := range result.RevokedSecurityGroupRules {
+		ruleIDs = append(ruleIDs, aws.ToString(rule.SecurityGroupRuleId))
+	}
+
+	logger.Info("Security group ingress revoked")
+	return ruleIDs, nil
+}
+
+const (
+	// reachabilityPollInterval is how often a Reachability Analyzer run is checked
+	reachabilityPollInterval = 2 * time.Second
+	// reachabilityTimeout bounds how long a Reachability Analyzer run may take
+	reachabilityTimeout = 2 * time.Minute
+)
+
+// ErrReachabilityTimeout is returned when a Reachability Analyzer run does not finish in time
+var ErrReachabilityTimeout = errors.New("reachability analysis did not complete in time")
+
+// GetInstanceNetwork retrieves the security groups of an instance and the
+// network ACL, route table and internet gateway that apply to its subnet
+func (c *Client) GetInstanceNetwork(ctx context.Context, instanceID string) (*types.InstanceNetwork, error) {
+	start := time.Now()
+
+	result, err := c.ec2.DescribeInstances(ctx, &ec2.DescribeInstancesInput{InstanceIds: []string{instanceID}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe instance %s: %w", instanceID, err)
+	}
+	if len(result.Reservations) == 0 || len(result.Reservations[0].Instances) == 0 {
+		return nil, fmt.Errorf("instance %s not found", instanceID)
+	}
+	instance := result.Reservations[0].Instances[0]
+	if instance.SubnetId == nil {
+		return nil, fmt.Errorf("instance %s is not in a VPC subnet", instanceID)
+	}
+
+	network := &types.InstanceNetwork{
+		InstanceID: instanceID,
+		PrivateIP:  aws.ToString(instance.PrivateIpAddress),
+		PublicIP:   aws.ToString(instance.PublicIpAddress),
+		VpcID:      aws.ToString(instance.VpcId),
+		SubnetID:   aws.ToString(instance.SubnetId),
+	}
+	if instance.State != nil {
+		network.State = string(instance.State.Name)
+	}
+
+	var groupIDs []string
+	for _, group := range instance.SecurityGroups {
+		groupIDs = append(groupIDs, aws.ToString(group.GroupId))
+	}
+	if len(groupIDs) > 0 {
+		groups, err := c.ec2.DescribeSecurityGroups(ctx, &ec2.DescribeSecurityGroupsInput{GroupIds: groupIDs})
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe security groups of %s: %w", instanceID, err)
+		}
+		for _, group := range groups.SecurityGroups {
+			network.SecurityGroups = append(network.SecurityGroups, convertSecurityGroup(group))
+		}
+	}
+
+	acls, err := c.ec2.DescribeNetworkAcls(ctx, &ec2.DescribeNetworkAclsInput{
+		Filters: []ec2types.Filter{{Name: aws.String("association.subnet-id"), Values: []string{network.SubnetID}}},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe network ACL of subnet %s: %w", network.SubnetID, err)
+	}
+	if len(acls.NetworkAcls) > 0 {
+		network.NetworkACL = convertNetworkACL(acls.NetworkAcls[0])
+	}
+
+	// Subnets without an explicit association use the main route table of the VPC
+	routeTables, err := c.ec2.DescribeRouteTables(ctx, &ec2.DescribeRouteTablesInput{
+		Filters: []ec2types.Filter{{Name: aws.String("association.subnet-id"), Values: []string{network.SubnetID}}},
+	})
+	if err == nil && len(routeTables.RouteTables) == 0 {
+		routeTables, err = c.ec2.DescribeRouteTables(ctx, &ec2.DescribeRouteTablesInput{
+			Filters: []ec2types.Filter{
+				{Name: aws.String("vpc-id"), Values: []string{network.VpcID}},
+				{Name: aws.String("association.main"), Values: []string{"true"}},
+			},
+		})
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe route table of subnet %s: %w", network.SubnetID, err)
+	}
+	if len(routeTables.RouteTables) > 0 {
+		network.RouteTable = convertRouteTable(routeTables.RouteTables[0])
+	}
+
+	gateways, err := c.ec2.DescribeInternetGateways(ctx, &ec2.DescribeInternetGatewaysInput{
+		Filters: []ec2types.Filter{{Name: aws.String("attachment.vpc-id"), Values: []string{network.VpcID}}},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe internet gateway of %s: %w", network.VpcID, err)
+	}
+	if len(gateways.InternetGateways) > 0 {
+		network.InternetGateway = aws.ToString(gateways.InternetGateways[0].InternetGatewayId)
+	}
+
+	c.logger.WithFields(logrus.Fields{
+		"instanceId": instanceID,
+		"duration":   time.Since(start),
+	}).Info("Retrieved instance network path")
+
+	return network, nil
+}
+
+// GetVPCTopology retrieves every VPC in the region with its subnets, route
+// tables, NAT gateways and internet gateways
+func (c *Client) GetVPCTopology(ctx context.Context) (*types.VPCTopology, error) {
+	start := time.Now()
+	topology := &types.VPCTopology{}
+
+	vpcs := ec2.NewDescribeVpcsPaginator(c.ec2, &ec2.DescribeVpcsInput{})
+	for vpcs.HasMorePages() {
+		page, err := vpcs.NextPage(ctx)
+		if err != nil {
+			c.logger.WithError(err).Error("Failed to describe VPCs")
+			return nil, fmt.Errorf("failed to describe VPCs: %w", err)
+		}
+		for _, vpc := range page.Vpcs {
+			topology.VPCs = append(topology.VPCs, convertVPC(vpc))
+		}
+	}
+
+	subnets := ec2.NewDescribeSubnetsPaginator(c.ec2, &ec2.DescribeSubnetsInput{})
+	for subnets.HasMorePages() {
+		page, err := subnets.NextPage(ctx)
+		if err != nil {
+			c.logger.WithError(err).Error("Failed to describe subnets")
+			return nil, fmt.Errorf("failed to describe subnets: %w", err)
+		}
+		for _, subnet := range page.Subnets {
+			topology.Subnets = append(topology.Subnets, types.Subnet{
+				ID:               aws.ToString(subnet.SubnetId),
+				Name:             nameTag(subnet.Tags),
+				VpcID:            aws.ToString(subnet.VpcId),
+				CIDR:             aws.ToString(subnet.CidrBlock),
+				AvailabilityZone: aws.ToString(subnet.AvailabilityZone),
+				AvailableIPs:     aws.ToInt32(subnet.AvailableIpAddressCount),
+				MapPublicIP:      aws.ToBool(subnet.MapPublicIpOnLaunch),
+			})
+		}
+	}
+
+	routeTables := ec2.NewDescribeRouteTablesPaginator(c.ec2, &ec2.DescribeRouteTablesInput{})
+	for routeTables.HasMorePages() {
+		page, err := routeTables.NextPage(ctx)
+		if err != nil {
+			c.logger.WithError(err).Error("Failed to describe route tables")
+			return nil, fmt.Errorf("failed to describe route tables: %w", err)
+		}
+		for _, table := range page.RouteTables {
+			topology.RouteTables = append(topology.RouteTables, convertRouteTable(table))
+		}
+	}
+
+	// Deleted NAT gateways stay listed for about an hour and are left out
+	natGateways := ec2.NewDescribeNatGatewaysPaginator(c.ec2, &ec2.DescribeNatGatewaysInput{})
+	for natGateways.HasMorePages() {
+		page, err := natGateways.NextPage(ctx)
+		if err != nil {
+			c.logger.WithError(err).Error("Failed to describe NAT gateways")
+			return nil, fmt.Errorf("failed to describe NAT gateways: %w", err)
+		}
+		for _, gateway := range page.NatGateways {
+			if gateway.State == ec2types.NatGatewayStateDeleted {
+				continue
+			}
+			topology.NATGateways = append(topology.NATGateways, convertNATGateway(gateway))
+		}
+	}
+
+	internetGateways := ec2.NewDescribeInternetGatewaysPaginator(c.ec2, &ec2.DescribeInternetGatewaysInput{})
+	for internetGateways.HasMorePages() {
+		page, err := internetGateways.NextPage(ctx)
+		if err != nil {
+			c.logger.WithError(err).Error("Failed to describe internet gateways")
+			return nil, fmt.Errorf("failed to describe internet gateways: %w", err)
+		}
+		for _, gateway := range page.InternetGateways {
+			converted := types.InternetGateway{ID: aws.ToString(gateway.InternetGatewayId)}
+			for _, attachment := range gateway.Attachments {
+				converted.VpcIDs = append(converted.VpcIDs, aws.ToString(attachment.VpcId))
+			}
+			topology.InternetGateways = append(topology.InternetGateways, converted)
+		}
+	}
+
+	c.logger.WithFields(logrus.Fields{
+		"vpcs":     len(topology.VPCs),
+		"subnets":  len(topology.Subnets),
+		"duration": time.Since(start),
+	}).Info("Retrieved VPC topology")
+
+	return topology, nil
+}
+
+// convertVPC converts a VPC to our standard format
+func convertVPC(vpc ec2types.Vpc) types.VPC {
+	converted := types.VPC{
+		ID:      aws.ToString(vpc.VpcId),
+		Name:    nameTag(vpc.Tags),
+		Default: aws.ToBool(vpc.IsDefault),
+		State:   string(vpc.State),
+	}
+
+	for _, association := range vpc.CidrBlockAssociationSet {
+		if association.CidrBlockState == nil || association.CidrBlockState.State == ec2types.VpcCidrBlockStateCodeAssociated {
+			converted.CIDRs = append(converted.CIDRs, aws.ToString(association.CidrBlock))
+		}
+	}
+	if len(converted.CIDRs) == 0 {
+		converted.CIDRs = []string{aws.ToString(vpc.CidrBlock)}
+	}
+	for _, association := range vpc.Ipv6CidrBlockAssociationSet {
+		if association.Ipv6CidrBlockState == nil || association.Ipv6CidrBlockState.State == ec2types.VpcCidrBlockStateCodeAssociated {
+			converted.CIDRs = append(converted.CIDRs, aws.ToString(association.Ipv6CidrBlock))
+		}
+	}
+
+	return converted
+}
+
+// nameTag returns the value of the Name tag, which the console shows as the name
+func nameTag(tags []ec2types.Tag) string {
+	for _, tag := range tags {
+		if aws.ToString(tag.Key) == "Name" {
+			return aws.ToString(tag.Value)
+		}
+	}
+	return ""
+}
+
+// RunReachabilityAnalysis runs VPC Reachability Analyzer from a source such as
+// an internet gateway to an instance port and removes the path afterwards.
+// sourceIP narrows the source to one address and may be empty.
+func (c *Client) RunReachabilityAnalysis(ctx context.Context, source, sourceIP, instanceID, protocol string, port int32) (*types.ReachabilityAnalysis, error) {
+	c.logger.WithFields(logrus.Fields{
+		"source":      source,
+		"destination": instanceID,
+		"port":        port,
+	}).Info("Starting reachability analysis")
+
+	input := &ec2.CreateNetworkInsightsPathInput{
+		Source:          aws.String(source),
+		Destination:     aws.String(instanceID),
+		Protocol:        ec2types.Protocol(protocol),
+		DestinationPort: aws.Int32(port),
+	}
+	if sourceIP != "" {
+		input.SourceIp = aws.String(sourceIP)
+	}
+
+	path, err := c.ec2.CreateNetworkInsightsPath(ctx, input)
+	if err != nil {
+		c.logger.WithError(err).Error("Failed to create network insights path")
+		return nil, fmt.Errorf("failed to create network insights path: %w", err)
+	}
+	pathID := path.NetworkInsightsPath.NetworkInsightsPathId
+	defer func() {
+		// Analyses must be deleted before their path; use a fresh context so
+		// cleanup runs even when the caller gave up
+		cleanup, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		analyses, err := c.ec2.DescribeNetworkInsightsAnalyses(cleanup, &ec2.DescribeNetworkInsightsAnalysesInput{NetworkInsightsPathId: pathID})
+		if err == nil {
+			for _, analysis := range analyses.NetworkInsightsAnalyses {
+				if _, err := c.ec2.DeleteNetworkInsightsAnalysis(cleanup, &ec2.DeleteNetworkInsightsAnalysisInput{
+					NetworkInsightsAnalysisId: analysis.NetworkInsightsAnalysisId,
+				}); err != nil {
+					c.logger.WithError(err).Warn("Failed to delete network insights analysis")
+				}
+			}
+		}
+		if _, err := c.ec2.DeleteNetworkInsightsPath(cleanup, &ec2.DeleteNetworkInsightsPathInput{NetworkInsightsPathId: pathID}); err != nil {
+			c.logger.WithError(err).WithField("pathId", aws.ToString(pathID)).Warn("Failed to delete network insights path")
+		}
+	}()
+
+	started, err := c.ec2.StartNetworkInsightsAnalysis(ctx, &ec2.StartNetworkInsightsAnalysisInput{NetworkInsightsPathId: pathID})
+	if err != nil {
+		c.logger.WithError(err).Error("Failed to start network insights analysis")
+		return nil, fmt.Errorf("failed to start reachability analysis: %w", err)
+	}
+	analysisID := started.NetworkInsightsAnalysis.NetworkInsightsAnalysisId
+
+	ctx, cancel := context.WithTimeout(ctx, reachabilityTimeout)
+	defer cancel()
+	ticker := time.NewTicker(reachabilityPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return &types.ReachabilityAnalysis{AnalysisID: aws.ToString(analysisID), Status: "running"}, ErrReachabilityTimeout
+		case <-ticker.C:
+		}
+
+		result, err := c.ec2.DescribeNetworkInsightsAnalyses(ctx, &ec2.DescribeNetworkInsightsAnalysesInput{
+			NetworkInsightsAnalysisIds: []string{aws.ToString(analysisID)},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get reachability analysis: %w", err)
+		}
+		if len(result.NetworkInsightsAnalyses) == 0 {
+			continue
+		}
+
+		analysis := result.NetworkInsightsAnalyses[0]
+		if analysis.Status == ec2types.AnalysisStatusRunning {
+			continue
+		}
+
+		converted := &types.ReachabilityAnalysis{
+			AnalysisID: aws.ToString(analysisID),
+			Status:     string(analysis.Status),
+			Message:    aws.ToString(analysis.StatusMessage),
+			PathFound:  aws.ToBool(analysis.NetworkPathFound),
+		}
+		for _, explanation := range analysis.Explanations {
+			converted.Explanations = append(converted.Explanations, formatExplanation(explanation))
+		}
+
+		c.logger.WithField("pathFound", converted.PathFound).Info("Reachability analysis completed")
+		return converted, nil
+	}
+}
+
+// formatExplanation renders why Reachability Analyzer found no path, e.g.
+// "ENI_SG_RULES_MISMATCH at sg-0123 (ingress)"
+func formatExplanation(explanation ec2types.Explanation) string {
+	text := aws.ToString(explanation.ExplanationCode)
+	if explanation.Component != nil {
+		text += " at " + aws.ToString(explanation.Component.Id)
+	}
+	if explanation.Direction != nil {
+		text += " (" + aws.ToString(explanation.Direction) + ")"
+	}
+	return text
+}
+
+// convertNetworkACL converts a network ACL to our standard format
+func convertNetworkACL(acl ec2types.NetworkAcl) types.NetworkACL {
+	converted := types.NetworkACL{
+		ID:      aws.ToString(acl.NetworkAclId),
+		Default: aws.ToBool(acl.IsDefault),
+	}
+
+	for _, entry := range acl.Entries {
+		cidr := aws.ToString(entry.CidrBlock)
+		if cidr == "" {
+			cidr = aws.ToString(entry.Ipv6CidrBlock)
+		}
+		rule := types.NetworkACLEntry{
+			RuleNumber: aws.ToInt32(entry.RuleNumber),
+			Egress:     aws.ToBool(entry.Egress),
+			Protocol:   aws.ToString(entry.Protocol),
+			CIDR:       cidr,
+			Allow:      entry.RuleAction == ec2types.RuleActionAllow,
+		}
+		if entry.PortRange != nil {
+			rule.FromPort = aws.ToInt32(entry.PortRange.From)
+			rule.ToPort = aws.ToInt32(entry.PortRange.To)
+		}
+		converted.Entries = append(converted.Entries, rule)
+	}
+
+	return converted
+}
+
+// convertRouteTable converts a route table to our standard format
+func convertRouteTable(table ec2types.RouteTable) types.RouteTable {
+	converted := types.RouteTable{
+		ID:    aws.ToString(table.RouteTableId),
+		VpcID: aws.ToString(table.VpcId),
+	}
+	for _, association := range table.Associations {
+		if aws.ToBool(association.Main) {
+			converted.Main = true
+		}
+		if subnetID := aws.ToString(association.SubnetId); subnetID != "" {
+			converted.SubnetIDs = append(converted.SubnetIDs, subnetID)
+		}
+	}
+
+	for _, route := range table.Routes {
+		destination := aws.ToString(route.DestinationCidrBlock)
+		if destination == "" {
+			destination = aws.ToString(route.DestinationIpv6CidrBlock)
+		}
+		if destination == "" {
+			destination = aws.ToString(route.DestinationPrefixListId)
+		}
+
+		converted.Routes = append(converted.Routes, types.Route{
+			Destination: destination,
+			Target:      routeTarget(route),
+			State:       string(route.State),
+		})
+	}
+
+	return converted
+}
+
+// routeTarget returns the resource a route sends traffic to
+func routeTarget(route ec2types.Route) string {
+	for _, target := range []*string{
+		route.GatewayId,
+		route.NatGatewayId,
+		route.TransitGatewayId,
+		route.VpcPeeringConnectionId,
+		route.NetworkInterfaceId,
+		route.InstanceId,
+		route.EgressOnlyInternetGatewayId,
+		route.CarrierGatewayId,
+		route.LocalGatewayId,
+		route.CoreNetworkArn,
+	} {
+		if id := aws.ToString(target); id != "" {
+			return id
+		}
+	}
+	return ""
+}
+
+// ListElasticIPs retrieves the Elastic IP addresses allocated in the region
+func (c *Client) ListElasticIPs(ctx context.Context) ([]types.ElasticIP, error) {
+	start := time.Now()
+
+	// DescribeAddresses is not paginated, it returns every address at once
+	result, err := c.ec2.DescribeAddresses(ctx, &ec2.DescribeAddressesInput{})
+	if err != nil {
+		c.logger.WithError(err).Error("Failed to describe Elastic IPs")
+		return nil, fmt.Errorf("failed to describe addresses: %w", err)
+	}
+
+	addresses := make([]types.ElasticIP, 0, len(result.Addresses))
+	for _, address := range result.Addresses {
+		addresses = append(addresses, types.ElasticIP{
+			AllocationID:       aws.ToString(address.AllocationId),
+			PublicIP:           aws.ToString(address.PublicIp),
+			Domain:             string(address.Domain),
+			AssociationID:      aws.ToString(address.AssociationId),
+			InstanceID:         aws.ToString(address.InstanceId),
+			NetworkInterfaceID: aws.ToString(address.NetworkInterfaceId),
+			Tags:               convertTags(address.Tags),
+		})
+	}
+
+	c.logger.WithFields(logrus.Fields{
+		"count":    len(addresses),
+		"duration": time.Since(start),
+	}).Info("Retrieved Elastic IPs")
+
+	return addresses, nil
+}
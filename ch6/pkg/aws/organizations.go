@@ -0,0 +1,115 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
+	"github.com/aws/aws-sdk-go-v2/service/organizations"
+	orgtypes "github.com/aws/aws-sdk-go-v2/service/organizations/types"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+
+	"aws-mcp-server/pkg/types"
+
+	"github.com/sirupsen/logrus"
+)
+
+// assumeRoleSessionName names the sessions of assumed roles in CloudTrail
+const assumeRoleSessionName = "aws-mcp-server"
+
+// ListAccounts retrieves the member accounts of the organization. It must be
+// called with credentials of the management account or a delegated
+// administrator.
+func (c *Client) ListAccounts(ctx context.Context) ([]types.Account, error) {
+	start := time.Now()
+
+	var accounts []types.Account
+	paginator := organizations.NewListAccountsPaginator(c.organizations, &organizations.ListAccountsInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			c.logger.WithError(err).Error("Failed to list organization accounts")
+			return nil, fmt.Errorf("failed to list organization accounts: %w", err)
+		}
+		for _, account := range page.Accounts {
+			accounts = append(accounts, convertAccount(account))
+		}
+	}
+
+	c.logger.WithFields(logrus.Fields{
+		"count":    len(accounts),
+		"duration": time.Since(start),
+	}).Info("Retrieved organization accounts")
+
+	return accounts, nil
+}
+
+// ListAccountTags retrieves the tags of a member account of the organization
+func (c *Client) ListAccountTags(ctx context.Context, accountID string) (map[string]string, error) {
+	tags := make(map[string]string)
+	paginator := organizations.NewListTagsForResourcePaginator(c.organizations, &organizations.ListTagsForResourceInput{
+		ResourceId: aws.String(accountID),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			c.logger.WithError(err).WithField("account", accountID).Error("Failed to list account tags")
+			return nil, fmt.Errorf("failed to list tags of account %s: %w", accountID, err)
+		}
+		for _, tag := range page.Tags {
+			tags[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+		}
+	}
+	return tags, nil
+}
+
+// CallerAccount returns the ID of the account the client's credentials
+// belong to
+func (c *Client) CallerAccount(ctx context.Context) (string, error) {
+	identity, err := c.sts.GetCallerIdentity(ctx, &sts.GetCallerIdentityInput{})
+	if err != nil {
+		c.logger.WithError(err).Error("Failed to get caller identity")
+		return "", fmt.Errorf("failed to get caller identity: %w", err)
+	}
+	return aws.ToString(identity.Account), nil
+}
+
+// AssumeRole returns a client acting in another account through roleARN, in
+// region when it is set. Credentials are refreshed before they expire. The
+// client shares this client's hooks, so call logging and concurrency limits
+// cover every account.
+func (c *Client) AssumeRole(roleARN, externalID, region string) *Client {
+	cfg := c.cfg.Copy()
+	if region != "" {
+		cfg.Region = region
+	}
+	cfg.Credentials = aws.NewCredentialsCache(stscreds.NewAssumeRoleProvider(c.sts, roleARN,
+		func(options *stscreds.AssumeRoleOptions) {
+			options.RoleSessionName = assumeRoleSessionName
+			if externalID != "" {
+				options.ExternalID = aws.String(externalID)
+			}
+		}))
+
+	return newClient(cfg, c.hooks, c.logger)
+}
+
+// convertAccount converts an organization account. Older responses only
+// carry the deprecated status, which uses the same values.
+func convertAccount(account orgtypes.Account) types.Account {
+	state := string(account.State)
+	if state == "" {
+		state = string(account.Status)
+	}
+	return types.Account{
+		ID:           aws.ToString(account.Id),
+		ARN:          aws.ToString(account.Arn),
+		Name:         aws.ToString(account.Name),
+		Email:        aws.ToString(account.Email),
+		State:        state,
+		JoinedMethod: string(account.JoinedMethod),
+		JoinedAt:     aws.ToTime(account.JoinedTimestamp),
+	}
+}
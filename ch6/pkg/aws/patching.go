@@ -0,0 +1,320 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	ssmtypes "github.com/aws/aws-sdk-go-v2/service/ssm/types"
+
+	"aws-mcp-server/pkg/types"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// runPatchBaselineDocument is the SSM document Patch Manager uses to scan
+	// for and install patches
+	runPatchBaselineDocument = "AWS-RunPatchBaseline"
+	// maxPatchStateInstances is how many instances DescribeInstancePatchStates
+	// accepts per call
+	maxPatchStateInstances = 50
+)
+
+// maintenanceWindowTimeLayouts are the formats of a window's next execution time
+var maintenanceWindowTimeLayouts = []string{"2006-01-02T15:04Z07:00", time.RFC3339}
+
+// RunPatchBaselineParams describes a run of AWS-RunPatchBaseline. Install
+// operations run on a quarter of the instances at a time and stop after the
+// first failure.
+type RunPatchBaselineParams struct {
+	InstanceIDs []string
+	Operation   string
+	Reboot      bool
+	Comment     string
+}
+
+// ListManagedInstances retrieves the instances registered with Systems Manager
+func (c *Client) ListManagedInstances(ctx context.Context) ([]types.ManagedInstance, error) {
+	start := time.Now()
+
+	var instances []types.ManagedInstance
+	paginator := ssm.NewDescribeInstanceInformationPaginator(c.ssm, &ssm.DescribeInstanceInformationInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			c.logger.WithError(err).Error("Failed to describe SSM managed instances")
+			return nil, fmt.Errorf("failed to describe instance information: %w", err)
+		}
+
+		for _, info := range page.InstanceInformationList {
+			instances = append(instances, types.ManagedInstance{
+				InstanceID:      aws.ToString(info.InstanceId),
+				ComputerName:    aws.ToString(info.ComputerName),
+				PingStatus:      string(info.PingStatus),
+				LastPingAt:      info.LastPingDateTime,
+				AgentVersion:    aws.ToString(info.AgentVersion),
+				LatestAgent:     aws.ToBool(info.IsLatestVersion),
+				PlatformType:    string(info.PlatformType),
+				PlatformName:    aws.ToString(info.PlatformName),
+				PlatformVersion: aws.ToString(info.PlatformVersion),
+				IPAddress:       aws.ToString(info.IPAddress),
+			})
+		}
+	}
+
+	c.logger.WithFields(logrus.Fields{
+		"count":    len(instances),
+		"duration": time.Since(start),
+	}).Info("Retrieved SSM managed instances")
+
+	return instances, nil
+}
+
+// ListInventoryApplications retrieves the installed applications SSM
+// Inventory last collected from an instance
+func (c *Client) ListInventoryApplications(ctx context.Context, instanceID string) ([]types.InstalledApplication, error) {
+	start := time.Now()
+
+	var applications []types.InstalledApplication
+	input := &ssm.ListInventoryEntriesInput{
+		InstanceId: aws.String(instanceID),
+		TypeName:   aws.String("AWS:Application"),
+	}
+	for {
+		result, err := c.ssm.ListInventoryEntries(ctx, input)
+		if err != nil {
+			c.logger.WithError(err).WithField("instance_id", instanceID).Error("Failed to list SSM inventory entries")
+			return nil, fmt.Errorf("failed to list inventory of %s: %w", instanceID, err)
+		}
+
+		for _, entry := range result.Entries {
+			applications = append(applications, types.InstalledApplication{
+				Name:          entry["Name"],
+				Version:       entry["Version"],
+				Publisher:     entry["Publisher"],
+				Architecture:  entry["Architecture"],
+				InstalledTime: entry["InstalledTime"],
+			})
+		}
+
+		if aws.ToString(result.NextToken) == "" {
+			break
+		}
+		input.NextToken = result.NextToken
+	}
+
+	c.logger.WithFields(logrus.Fields{
+		"instance_id": instanceID,
+		"count":       len(applications),
+		"duration":    time.Since(start),
+	}).Info("Retrieved SSM inventory applications")
+
+	return applications, nil
+}
+
+// ListInstancePatchStates retrieves the patch compliance summary of the given
+// instances. Instances that were never scanned are missing from the result.
+func (c *Client) ListInstancePatchStates(ctx context.Context, instanceIDs []string) ([]types.InstancePatchState, error) {
+	var states []types.InstancePatchState
+	for batch := range slices.Chunk(instanceIDs, maxPatchStateInstances) {
+		paginator := ssm.NewDescribeInstancePatchStatesPaginator(c.ssm, &ssm.DescribeInstancePatchStatesInput{
+			InstanceIds: batch,
+		})
+		for paginator.HasMorePages() {
+			page, err := paginator.NextPage(ctx)
+			if err != nil {
+				c.logger.WithError(err).Error("Failed to describe instance patch states")
+				return nil, fmt.Errorf("failed to describe instance patch states: %w", err)
+			}
+
+			for _, state := range page.InstancePatchStates {
+				states = append(states, types.InstancePatchState{
+					InstanceID:            aws.ToString(state.InstanceId),
+					PatchGroup:            aws.ToString(state.PatchGroup),
+					BaselineID:            aws.ToString(state.BaselineId),
+					Operation:             string(state.Operation),
+					OperationEndTime:      aws.ToTime(state.OperationEndTime),
+					InstalledCount:        state.InstalledCount,
+					MissingCount:          state.MissingCount,
+					FailedCount:           state.FailedCount,
+					PendingRebootCount:    aws.ToInt32(state.InstalledPendingRebootCount),
+					CriticalNonCompliant:  aws.ToInt32(state.CriticalNonCompliantCount),
+					SecurityNonCompliant:  aws.ToInt32(state.SecurityNonCompliantCount),
+					RebootOption:          string(state.RebootOption),
+					LastNoRebootInstallAt: state.LastNoRebootInstallOperationTime,
+				})
+			}
+		}
+	}
+	return states, nil
+}
+
+// ListInstancePatches retrieves the patches of an instance that are missing,
+// failed to install or wait for a reboot
+func (c *Client) ListInstancePatches(ctx context.Context, instanceID string) ([]types.PatchInfo, error) {
+	var patches []types.PatchInfo
+	paginator := ssm.NewDescribeInstancePatchesPaginator(c.ssm, &ssm.DescribeInstancePatchesInput{
+		InstanceId: aws.String(instanceID),
+		Filters: []ssmtypes.PatchOrchestratorFilter{
+			{Key: aws.String("State"), Values: []string{"Missing", "Failed", "InstalledPendingReboot"}},
+		},
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			c.logger.WithError(err).WithField("instance_id", instanceID).Error("Failed to describe instance patches")
+			return nil, fmt.Errorf("failed to describe patches of %s: %w", instanceID, err)
+		}
+
+		for _, patch := range page.Patches {
+			converted := types.PatchInfo{
+				Title:          aws.ToString(patch.Title),
+				KBID:           aws.ToString(patch.KBId),
+				Classification: aws.ToString(patch.Classification),
+				Severity:       aws.ToString(patch.Severity),
+				State:          string(patch.State),
+				InstalledTime:  patch.InstalledTime,
+			}
+			if cves := aws.ToString(patch.CVEIds); cves != "" {
+				converted.CVEIDs = strings.Split(cves, ",")
+			}
+			patches = append(patches, converted)
+		}
+	}
+	return patches, nil
+}
+
+// ListMaintenanceWindows retrieves the maintenance windows with their targets
+// and whether they run a patch baseline
+func (c *Client) ListMaintenanceWindows(ctx context.Context) ([]types.MaintenanceWindow, error) {
+	start := time.Now()
+
+	var windows []types.MaintenanceWindow
+	paginator := ssm.NewDescribeMaintenanceWindowsPaginator(c.ssm, &ssm.DescribeMaintenanceWindowsInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			c.logger.WithError(err).Error("Failed to describe maintenance windows")
+			return nil, fmt.Errorf("failed to describe maintenance windows: %w", err)
+		}
+
+		for _, identity := range page.WindowIdentities {
+			window := types.MaintenanceWindow{
+				ID:            aws.ToString(identity.WindowId),
+				Name:          aws.ToString(identity.Name),
+				Enabled:       identity.Enabled,
+				Schedule:      aws.ToString(identity.Schedule),
+				Timezone:      aws.ToString(identity.ScheduleTimezone),
+				DurationHours: aws.ToInt32(identity.Duration),
+				CutoffHours:   identity.Cutoff,
+				NextExecution: parseMaintenanceWindowTime(aws.ToString(identity.NextExecutionTime)),
+			}
+			if err := c.describeMaintenanceWindow(ctx, &window); err != nil {
+				return nil, err
+			}
+			windows = append(windows, window)
+		}
+	}
+
+	c.logger.WithFields(logrus.Fields{
+		"count":    len(windows),
+		"duration": time.Since(start),
+	}).Info("Retrieved maintenance windows")
+
+	return windows, nil
+}
+
+// describeMaintenanceWindow fills in the targets and patch tasks of a window
+func (c *Client) describeMaintenanceWindow(ctx context.Context, window *types.MaintenanceWindow) error {
+	targets := ssm.NewDescribeMaintenanceWindowTargetsPaginator(c.ssm, &ssm.DescribeMaintenanceWindowTargetsInput{
+		WindowId: aws.String(window.ID),
+	})
+	for targets.HasMorePages() {
+		page, err := targets.NextPage(ctx)
+		if err != nil {
+			c.logger.WithError(err).WithField("window_id", window.ID).Error("Failed to describe maintenance window targets")
+			return fmt.Errorf("failed to describe targets of maintenance window %s: %w", window.ID, err)
+		}
+		for _, target := range page.Targets {
+			for _, selector := range target.Targets {
+				window.Targets = append(window.Targets, types.MaintenanceWindowTarget{
+					Key:    aws.ToString(selector.Key),
+					Values: selector.Values,
+				})
+			}
+		}
+	}
+
+	tasks := ssm.NewDescribeMaintenanceWindowTasksPaginator(c.ssm, &ssm.DescribeMaintenanceWindowTasksInput{
+		WindowId: aws.String(window.ID),
+	})
+	for tasks.HasMorePages() {
+		page, err := tasks.NextPage(ctx)
+		if err != nil {
+			c.logger.WithError(err).WithField("window_id", window.ID).Error("Failed to describe maintenance window tasks")
+			return fmt.Errorf("failed to describe tasks of maintenance window %s: %w", window.ID, err)
+		}
+		for _, task := range page.Tasks {
+			if strings.Contains(aws.ToString(task.TaskArn), "PatchBaseline") {
+				window.PatchTasks = true
+			}
+		}
+	}
+	return nil
+}
+
+// RunPatchBaseline sends AWS-RunPatchBaseline to the instances and returns
+// the command ID
+func (c *Client) RunPatchBaseline(ctx context.Context, params RunPatchBaselineParams) (string, error) {
+	rebootOption := "RebootIfNeeded"
+	if !params.Reboot {
+		rebootOption = "NoReboot"
+	}
+
+	input := &ssm.SendCommandInput{
+		DocumentName: aws.String(runPatchBaselineDocument),
+		InstanceIds:  params.InstanceIDs,
+		Parameters: map[string][]string{
+			"Operation":    {params.Operation},
+			"RebootOption": {rebootOption},
+		},
+	}
+	if params.Comment != "" {
+		input.Comment = aws.String(params.Comment)
+	}
+	if params.Operation == "Install" {
+		input.MaxConcurrency = aws.String("25%")
+		input.MaxErrors = aws.String("1")
+	}
+
+	result, err := c.ssm.SendCommand(ctx, input)
+	if err != nil {
+		c.logger.WithError(err).WithField("operation", params.Operation).Error("Failed to send AWS-RunPatchBaseline")
+		return "", fmt.Errorf("failed to run patch baseline: %w", err)
+	}
+
+	commandID := aws.ToString(result.Command.CommandId)
+	c.logger.WithFields(logrus.Fields{
+		"command_id": commandID,
+		"operation":  params.Operation,
+		"instances":  len(params.InstanceIDs),
+	}).Info("Sent AWS-RunPatchBaseline")
+
+	return commandID, nil
+}
+
+// parseMaintenanceWindowTime parses the next execution time of a window,
+// which is empty for windows that are disabled or have no future run
+func parseMaintenanceWindowTime(value string) *time.Time {
+	for _, layout := range maintenanceWindowTimeLayouts {
+		if parsed, err := time.Parse(layout, value); err == nil {
+			return &parsed
+		}
+	}
+	return nil
+}
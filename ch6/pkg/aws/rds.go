@@ -0,0 +1,187 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+	rdstypes "github.com/aws/aws-sdk-go-v2/service/rds/types"
+
+	"aws-mcp-server/pkg/types"
+
+	"github.com/sirupsen/logrus"
+)
+
+// ListDBInstances retrieves all RDS database instances in the region
+func (c *Client) ListDBInstances(ctx context.Context) ([]types.CloudResource, error) {
+	start := time.Now()
+
+	var resources []types.CloudResource
+	paginator := rds.NewDescribeDBInstancesPaginator(c.rds, &rds.DescribeDBInstancesInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			c.logger.WithError(err).Error("Failed to describe RDS instances")
+			return nil, fmt.Errorf("failed to describe DB instances: %w", err)
+		}
+
+		for _, instance := range page.DBInstances {
+			resources = append(resources, c.convertDBInstance(instance))
+		}
+	}
+
+	c.logger.WithFields(logrus.Fields{
+		"count":    len(resources),
+		"duration": time.Since(start),
+	}).Info("Retrieved RDS instances")
+
+	return resources, nil
+}
+
+// convertDBInstance converts an AWS RDS instance to our standard format
+func (c *Client) convertDBInstance(instance rdstypes.DBInstance) types.CloudResource {
+	tags := make(map[string]string)
+	for _, tag := range instance.TagList {
+		if tag.Key != nil && tag.Value != nil {
+			tags[*tag.Key] = *tag.Value
+		}
+	}
+
+	details := &types.RDSInstanceDetails{
+		Engine:                    aws.ToString(instance.Engine),
+		EngineVersion:             aws.ToString(instance.EngineVersion),
+		InstanceClass:             aws.ToString(instance.DBInstanceClass),
+		AllocatedStorage:          aws.ToInt32(instance.AllocatedStorage),
+		StorageEncrypted:          aws.ToBool(instance.StorageEncrypted),
+		MultiAZ:                   aws.ToBool(instance.MultiAZ),
+		PubliclyAccessible:        aws.ToBool(instance.PubliclyAccessible),
+		ARN:                       aws.ToString(instance.DBInstanceArn),
+		DeletionProtection:        aws.ToBool(instance.DeletionProtection),
+		SecondaryAvailabilityZone: aws.ToString(instance.SecondaryAvailabilityZone),
+		AvailabilityZone:          aws.ToString(instance.AvailabilityZone),
+		KmsKeyID:                  aws.ToString(instance.KmsKeyId),
+	}
+
+	if instance.Endpoint != nil {
+		details.Endpoint = fmt.Sprintf("%s:%d", aws.ToString(instance.Endpoint.Address), aws.ToInt32(instance.Endpoint.Port))
+	}
+
+	return types.CloudResource{
+		ID:       aws.ToString(instance.DBInstanceIdentifier),
+		Provider: ProviderName,
+		Type:     "rds-instance",
+		Region:   c.cfg.Region,
+		State:    aws.ToString(instance.DBInstanceStatus),
+		Tags:     tags,
+		Details:  details,
+		LastSeen: time.Now(),
+	}
+}
+
+// StartDBInstance starts a stopped RDS instance
+func (c *Client) StartDBInstance(ctx context.Context, dbInstanceID string) error {
+	c.logger.WithField("dbInstanceId", dbInstanceID).Info("Starting RDS instance")
+
+	_, err := c.rds.StartDBInstance(ctx, &rds.StartDBInstanceInput{
+		DBInstanceIdentifier: aws.String(dbInstanceID),
+	})
+	if err != nil {
+		c.logger.WithError(err).WithField("dbInstanceId", dbInstanceID).Error("Failed to start RDS instance")
+		return fmt.Errorf("failed to start DB instance %s: %w", dbInstanceID, err)
+	}
+
+	c.logger.WithField("dbInstanceId", dbInstanceID).Info("RDS instance start initiated")
+	return nil
+}
+
+// StopDBInstance stops a running RDS instance. When snapshotID is set, RDS takes a
+// snapshot of the instance before stopping it.
+func (c *Client) StopDBInstance(ctx context.Context, dbInstanceID, snapshotID string) error {
+	c.logger.WithFields(logrus.Fields{
+		"dbInstanceId": dbInstanceID,
+		"snapshotId":   snapshotID,
+	}).Info("Stopping RDS instance")
+
+	input := &rds.StopDBInstanceInput{
+		DBInstanceIdentifier: aws.String(dbInstanceID),
+	}
+	if snapshotID != "" {
+		input.DBSnapshotIdentifier = aws.String(snapshotID)
+	}
+
+	_, err := c.rds.StopDBInstance(ctx, input)
+	if err != nil {
+		c.logger.WithError(err).WithField("dbInstanceId", dbInstanceID).Error("Failed to stop RDS instance")
+		return fmt.Errorf("failed to stop DB instance %s: %w", dbInstanceID, err)
+	}
+
+	c.logger.WithField("dbInstanceId", dbInstanceID).Info("RDS instance stop initiated")
+	return nil
+}
+
+// RebootDBInstance reboots an RDS instance. forceFailover reboots a Multi-AZ
+// instance by failing over to its standby.
+func (c *Client) RebootDBInstance(ctx context.Context, dbInstanceID string, forceFailover bool) error {
+	c.logger.WithFields(logrus.Fields{
+		"dbInstanceId":  dbInstanceID,
+		"forceFailover": forceFailover,
+	}).Info("Rebooting RDS instance")
+
+	input := &rds.RebootDBInstanceInput{
+		DBInstanceIdentifier: aws.String(dbInstanceID),
+	}
+	if forceFailover {
+		input.ForceFailover = aws.Bool(true)
+	}
+
+	_, err := c.rds.RebootDBInstance(ctx, input)
+	if err != nil {
+		c.logger.WithError(err).WithField("dbInstanceId", dbInstanceID).Error("Failed to reboot RDS instance")
+		return fmt.Errorf("failed to reboot DB instance %s: %w", dbInstanceID, err)
+	}
+
+	c.logger.WithField("dbInstanceId", dbInstanceID).Info("RDS instance reboot initiated")
+	return nil
+}
+
+// CreateDBSnapshot starts a manual snapshot of an RDS instance and returns it in the creating state
+func (c *Client) CreateDBSnapshot(ctx context.Context, dbInstanceID, snapshotID string) (*types.CloudResource, error) {
+	c.logger.WithFields(logrus.Fields{
+		"dbInstanceId": dbInstanceID,
+		"snapshotId":   snapshotID,
+	}).Info("Creating RDS snapshot")
+
+	result, err := c.rds.CreateDBSnapshot(ctx, &rds.CreateDBSnapshotInput{
+		DBInstanceIdentifier: aws.String(dbInstanceID),
+		DBSnapshotIdentifier: aws.String(snapshotID),
+	})
+	if err != nil {
+		c.logger.WithError(err).WithField("dbInstanceId", dbInstanceID).Error("Failed to create RDS snapshot")
+		return nil, fmt.Errorf("failed to create snapshot of DB instance %s: %w", dbInstanceID, err)
+	}
+
+	snapshot := result.DBSnapshot
+	resource := &types.CloudResource{
+		ID:       snapshotID,
+		Provider: ProviderName,
+		Type:     "rds-snapshot",
+		Region:   c.cfg.Region,
+		State:    "creating",
+		Tags:     make(map[string]string),
+		LastSeen: time.Now(),
+	}
+	details := &types.RDSSnapshotDetails{DBInstanceID: dbInstanceID}
+	if snapshot != nil {
+		resource.State = aws.ToString(snapshot.Status)
+		details.ARN = aws.ToString(snapshot.DBSnapshotArn)
+		details.Engine = aws.ToString(snapshot.Engine)
+		details.AllocatedStorage = aws.ToInt32(snapshot.AllocatedStorage)
+		details.Encrypted = aws.ToBool(snapshot.Encrypted)
+	}
+	resource.Details = details
+
+	c.logger.WithField("snapshotId", snapshotID).Info("RDS snapshot creation initiated")
+	return resource, nil
+}
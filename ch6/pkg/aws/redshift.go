@@ -0,0 +1,147 @@
+package aws
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/redshiftdata"
+	redshifttypes "github.com/aws/aws-sdk-go-v2/service/redshiftdata/types"
+
+	"aws-mcp-server/pkg/types"
+
+	"github.com/sirupsen/logrus"
+)
+
+// RedshiftQueryParams describes a Redshift Data API statement. It runs on a
+// provisioned cluster by ClusterID or on a Serverless workgroup, and
+// authenticates with the secret in SecretARN or, on a cluster, temporary
+// credentials of DBUser.
+type RedshiftQueryParams struct {
+	SQL       string
+	ClusterID string
+	Workgroup string
+	Database  string
+	DBUser    string
+	SecretARN string
+}
+
+// StartRedshiftQuery submits a statement and returns its ID without waiting
+// for it. The Data API runs a single statement per call.
+func (c *Client) StartRedshiftQuery(ctx context.Context, params RedshiftQueryParams) (string, error) {
+	input := &redshiftdata.ExecuteStatementInput{
+		Sql:      aws.String(params.SQL),
+		Database: aws.String(params.Database),
+	}
+	if params.ClusterID != "" {
+		input.ClusterIdentifier = aws.String(params.ClusterID)
+	}
+	if params.Workgroup != "" {
+		input.WorkgroupName = aws.String(params.Workgroup)
+	}
+	if params.SecretARN != "" {
+		input.SecretArn = aws.String(params.SecretARN)
+	} else if params.DBUser != "" {
+		input.DbUser = aws.String(params.DBUser)
+	}
+
+	output, err := c.redshiftdata.ExecuteStatement(ctx, input)
+	if err != nil {
+		c.logger.WithError(err).WithFields(logrus.Fields{
+			"cluster":   params.ClusterID,
+			"workgroup": params.Workgroup,
+		}).Error("Failed to start Redshift query")
+		return "", fmt.Errorf("failed to start redshift query: %w", err)
+	}
+	return aws.ToString(output.Id), nil
+}
+
+// GetRedshiftQuery returns the state of a statement
+func (c *Client) GetRedshiftQuery(ctx context.Context, statementID string) (*types.RedshiftResult, error) {
+	output, err := c.redshiftdata.DescribeStatement(ctx, &redshiftdata.DescribeStatementInput{Id: aws.String(statementID)})
+	if err != nil {
+		c.logger.WithError(err).WithField("statementId", statementID).Error("Failed to get Redshift query status")
+		return nil, fmt.Errorf("failed to get status of redshift query %s: %w", statementID, err)
+	}
+
+	return &types.RedshiftResult{
+		StatementID: statementID,
+		State:       string(output.Status),
+		Error:       aws.ToString(output.Error),
+		Rows:        []map[string]string{},
+		TotalRows:   output.ResultRows,
+		DurationMs:  time.Duration(output.Duration).Milliseconds(),
+	}, nil
+}
+
+// GetRedshiftQueryResults returns up to maxRows rows of a finished statement
+func (c *Client) GetRedshiftQueryResults(ctx context.Context, statementID string, maxRows int) (*types.RedshiftResult, error) {
+	result, err := c.GetRedshiftQuery(ctx, statementID)
+	if err != nil {
+		return nil, err
+	}
+	if result.State != string(redshifttypes.StatusStringFinished) {
+		return result, fmt.Errorf("redshift query %s is %s, results are only available once it is FINISHED", statementID, result.State)
+	}
+
+	paginator := redshiftdata.NewGetStatementResultPaginator(c.redshiftdata, &redshiftdata.GetStatementResultInput{Id: aws.String(statementID)})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			c.logger.WithError(err).WithField("statementId", statementID).Error("Failed to get Redshift query results")
+			return nil, fmt.Errorf("failed to get results of redshift query %s: %w", statementID, err)
+		}
+
+		if result.Columns == nil {
+			result.Columns = make([]string, 0, len(page.ColumnMetadata))
+			for _, column := range page.ColumnMetadata {
+				result.Columns = append(result.Columns, aws.ToString(column.Name))
+			}
+		}
+		result.TotalRows = page.TotalNumRows
+
+		for _, record := range page.Records {
+			if len(result.Rows) == maxRows {
+				result.Truncated = true
+				return result, nil
+			}
+
+			values := make(map[string]string, len(record))
+			for i, field := range record {
+				if i < len(result.Columns) {
+					values[result.Columns[i]] = redshiftFieldValue(field)
+				}
+			}
+			result.Rows = append(result.Rows, values)
+		}
+	}
+
+	c.logger.WithFields(logrus.Fields{
+		"statementId": statementID,
+		"count":       len(result.Rows),
+	}).Info("Retrieved Redshift query results")
+
+	return result, nil
+}
+
+// redshiftFieldValue renders a field of a result as text, like Athena does;
+// NULL is empty and binary values are base64
+func redshiftFieldValue(field redshifttypes.Field) string {
+	switch value := field.(type) {
+	case *redshifttypes.FieldMemberStringValue:
+		return value.Value
+	case *redshifttypes.FieldMemberLongValue:
+		return strconv.FormatInt(value.Value, 10)
+	case *redshifttypes.FieldMemberDoubleValue:
+		return strconv.FormatFloat(value.Value, 'g', -1, 64)
+	case *redshifttypes.FieldMemberBooleanValue:
+		return strconv.FormatBool(value.Value)
+	case *redshifttypes.FieldMemberBlobValue:
+		return base64.StdEncoding.EncodeToString(value.Value)
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,112 @@
+package aws
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+
+	"aws-mcp-server/pkg/types"
+
+	"github.com/sirupsen/logrus"
+)
+
+// ListS3Objects retrieves the objects under a prefix of a bucket
+func (c *Client) ListS3Objects(ctx context.Context, bucket, prefix string) ([]types.S3Object, error) {
+	start := time.Now()
+
+	var objects []types.S3Object
+	paginator := s3.NewListObjectsV2Paginator(c.s3, &s3.ListObjectsV2Input{
+		Bucket: aws.String(bucket),
+		Prefix: aws.String(prefix),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			c.logger.WithError(err).WithField("bucket", bucket).Error("Failed to list S3 objects")
+			return nil, fmt.Errorf("failed to list objects in s3://%s/%s: %w", bucket, prefix, err)
+		}
+		for _, object := range page.Contents {
+			objects = append(objects, types.S3Object{
+				Bucket:       bucket,
+				Key:          aws.ToString(object.Key),
+				Size:         aws.ToInt64(object.Size),
+				LastModified: aws.ToTime(object.LastModified),
+			})
+		}
+	}
+
+	c.logger.WithFields(logrus.Fields{
+		"bucket":   bucket,
+		"prefix":   prefix,
+		"count":    len(objects),
+		"duration": time.Since(start),
+	}).Info("Retrieved S3 objects")
+
+	return objects, nil
+}
+
+// GetS3Object reads an object, failing when it is larger than maxSize bytes
+func (c *Client) GetS3Object(ctx context.Context, bucket, key string, maxSize int64) ([]byte, error) {
+	result, err := c.s3.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		c.logger.WithError(err).WithField("object", "s3://"+bucket+"/"+key).Error("Failed to get S3 object")
+		return nil, fmt.Errorf("failed to get s3://%s/%s: %w", bucket, key, err)
+	}
+	defer result.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(result.Body, maxSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read s3://%s/%s: %w", bucket, key, err)
+	}
+	if int64(len(data)) > maxSize {
+		return nil, fmt.Errorf("s3://%s/%s is larger than %d bytes", bucket, key, maxSize)
+	}
+	return data, nil
+}
+
+// PresignS3Upload returns a URL that uploads an object with a plain HTTP PUT
+// until it expires, so instances can upload without S3 permissions
+func (c *Client) PresignS3Upload(ctx context.Context, bucket, key string, expires time.Duration) (string, error) {
+	request, err := s3.NewPresignClient(c.s3).PresignPutObject(ctx, &s3.PutObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+	}, s3.WithPresignExpires(expires))
+	if err != nil {
+		return "", fmt.Errorf("failed to presign upload to s3://%s/%s: %w", bucket, key, err)
+	}
+	return request.URL, nil
+}
+
+// PresignS3Download returns a URL that downloads an object until it expires
+func (c *Client) PresignS3Download(ctx context.Context, bucket, key string, expires time.Duration) (string, error) {
+	request, err := s3.NewPresignClient(c.s3).PresignGetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+	}, s3.WithPresignExpires(expires))
+	if err != nil {
+		return "", fmt.Errorf("failed to presign download of s3://%s/%s: %w", bucket, key, err)
+	}
+	return request.URL, nil
+}
+
+// PutS3Object stores an object, replacing any object under the same key
+func (c *Client) PutS3Object(ctx context.Context, bucket, key string, body []byte) error {
+	_, err := c.s3.PutObject(ctx, &s3.PutObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+		Body:   bytes.NewReader(body),
+	})
+	if err != nil {
+		c.logger.WithError(err).WithField("object", "s3://"+bucket+"/"+key).Error("Failed to put S3 object")
+		return fmt.Errorf("failed to put s3://%s/%s: %w", bucket, key, err)
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	smtypes "github.com/aws/aws-sdk-go-v2/service/secretsmanager/types"
+
+	"aws-mcp-server/pkg/types"
+
+	"github.com/sirupsen/logrus"
+)
+
+// ListSecrets retrieves the metadata of all Secrets Manager secrets in the
+// region. Secret values are never retrieved.
+func (c *Client) ListSecrets(ctx context.Context) ([]types.Secret, error) {
+	start := time.Now()
+
+	var secrets []types.Secret
+	paginator := secretsmanager.NewListSecretsPaginator(c.secretsmanager, &secretsmanager.ListSecretsInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			c.logger.WithError(err).Error("Failed to list secrets")
+			return nil, fmt.Errorf("failed to list secrets: %w", err)
+		}
+
+		for _, entry := range page.SecretList {
+			secrets = append(secrets, convertSecret(entry))
+		}
+	}
+
+	c.logger.WithFields(logrus.Fields{
+		"count":    len(secrets),
+		"duration": time.Since(start),
+	}).Info("Retrieved secrets")
+
+	return secrets, nil
+}
+
+// GetSecret retrieves the metadata of one secret by name or ARN
+func (c *Client) GetSecret(ctx context.Context, secretID string) (*types.Secret, error) {
+	result, err := c.secretsmanager.DescribeSecret(ctx, &secretsmanager.DescribeSecretInput{
+		SecretId: aws.String(secretID),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe secret %s: %w", secretID, err)
+	}
+
+	// DescribeSecret returns the fields of a list entry, with the version
+	// stages under a different name
+	secret := convertSecret(smtypes.SecretListEntry{
+		Name:                   result.Name,
+		ARN:                    result.ARN,
+		Description:            result.Description,
+		KmsKeyId:               result.KmsKeyId,
+		OwningService:          result.OwningService,
+		PrimaryRegion:          result.PrimaryRegion,
+		RotationEnabled:        result.RotationEnabled,
+		RotationLambdaARN:      result.RotationLambdaARN,
+		RotationRules:          result.RotationRules,
+		CreatedDate:            result.CreatedDate,
+		LastRotatedDate:        result.LastRotatedDate,
+		LastChangedDate:        result.LastChangedDate,
+		LastAccessedDate:       result.LastAccessedDate,
+		NextRotationDate:       result.NextRotationDate,
+		DeletedDate:            result.DeletedDate,
+		SecretVersionsToStages: result.VersionIdsToStages,
+		Tags:                   result.Tags,
+	})
+	return &secret, nil
+}
+
+// RotateSecret starts an immediate rotation of a secret with its configured
+// rotation function and returns the ID of the new version
+func (c *Client) RotateSecret(ctx context.Context, secretID string) (string, error) {
+	result, err := c.secretsmanager.RotateSecret(ctx, &secretsmanager.RotateSecretInput{
+		SecretId:          aws.String(secretID),
+		RotateImmediately: aws.Bool(true),
+	})
+	if err != nil {
+		c.logger.WithError(err).WithField("secretId", secretID).Error("Failed to rotate secret")
+		return "", fmt.Errorf("failed to rotate secret %s: %w", secretID, err)
+	}
+
+	c.logger.WithFields(logrus.Fields{
+		"secretId":  secretID,
+		"versionId": aws.ToString(result.VersionId),
+	}).Info("Started secret rotation")
+
+	return aws.ToString(result.VersionId), nil
+}
+
+// convertSecret converts the metadata of a secret
+func convertSecret(entry smtypes.SecretListEntry) types.Secret {
+	secret := types.Secret{
+		Name:              aws.ToString(entry.Name),
+		ARN:               aws.ToString(entry.ARN),
+		Description:       aws.ToString(entry.Description),
+		KMSKeyID:          aws.ToString(entry.KmsKeyId),
+		OwningService:     aws.ToString(entry.OwningService),
+		PrimaryRegion:     aws.ToString(entry.PrimaryRegion),
+		RotationEnabled:   aws.ToBool(entry.RotationEnabled),
+		RotationLambdaARN: aws.ToString(entry.RotationLambdaARN),
+		CreatedAt:         aws.ToTime(entry.CreatedDate),
+		LastRotated:       entry.LastRotatedDate,
+		LastChanged:       entry.LastChangedDate,
+		LastAccessed:      entry.LastAccessedDate,
+		NextRotation:      entry.NextRotationDate,
+		DeletedAt:         entry.DeletedDate,
+		VersionStages:     entry.SecretVersionsToStages,
+		Tags:              make(map[string]string, len(entry.Tags)),
+	}
+	if entry.RotationRules != nil {
+		secret.RotationDays = aws.ToInt64(entry.RotationRules.AutomaticallyAfterDays)
+		secret.RotationSchedule = aws.ToString(entry.RotationRules.ScheduleExpression)
+	}
+	for _, tag := range entry.Tags {
+		secret.Tags[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+	}
+	return secret
+}
@@ -0,0 +1,221 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+	cwtypes "github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+	"github.com/aws/aws-sdk-go-v2/service/servicequotas"
+	sqtypes "github.com/aws/aws-sdk-go-v2/service/servicequotas/types"
+
+	"aws-mcp-server/pkg/types"
+
+	"github.com/sirupsen/logrus"
+)
+
+// QuotaUsageWindow is how far back the usage of a quota is read; usage is the
+// highest value within it
+const QuotaUsageWindow = time.Hour
+
+// ListServiceQuotas retrieves the applied quotas of a service, e.g. ec2, with
+// the usage of those that have a usage metric
+func (c *Client) ListServiceQuotas(ctx context.Context, serviceCode string) ([]types.ServiceQuota, error) {
+	start := time.Now()
+
+	var quotas []types.ServiceQuota
+	var metrics []*sqtypes.MetricInfo
+	paginator := servicequotas.NewListServiceQuotasPaginator(c.servicequotas, &servicequotas.ListServiceQuotasInput{
+		ServiceCode: aws.String(serviceCode),
+		MaxResults:  aws.Int32(100),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			c.logger.WithError(err).WithField("service", serviceCode).Error("Failed to list service quotas")
+			return nil, fmt.Errorf("failed to list quotas of %s: %w", serviceCode, err)
+		}
+		for _, quota := range page.Quotas {
+			quotas = append(quotas, convertServiceQuota(quota))
+			metrics = append(metrics, quota.UsageMetric)
+		}
+	}
+
+	c.addQuotaUsage(ctx, quotas, metrics)
+
+	c.logger.WithFields(logrus.Fields{
+		"service":  serviceCode,
+		"count":    len(quotas),
+		"duration": time.Since(start),
+	}).Info("Retrieved service quotas")
+
+	return quotas, nil
+}
+
+// GetServiceQuota retrieves one applied quota with its usage
+func (c *Client) GetServiceQuota(ctx context.Context, serviceCode, quotaCode string) (*types.ServiceQuota, error) {
+	result, err := c.servicequotas.GetServiceQuota(ctx, &servicequotas.GetServiceQuotaInput{
+		ServiceCode: aws.String(serviceCode),
+		QuotaCode:   aws.String(quotaCode),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get quota %s of %s: %w", quotaCode, serviceCode, err)
+	}
+	if result.Quota == nil {
+		return nil, fmt.Errorf("quota %s of %s not found", quotaCode, serviceCode)
+	}
+
+	quotas := []types.ServiceQuota{convertServiceQuota(*result.Quota)}
+	c.addQuotaUsage(ctx, quotas, []*sqtypes.MetricInfo{result.Quota.UsageMetric})
+	return &quotas[0], nil
+}
+
+// convertServiceQuota converts an applied quota without its usage
+func convertServiceQuota(quota sqtypes.ServiceQuota) types.ServiceQuota {
+	return types.ServiceQuota{
+		ServiceCode: aws.ToString(quota.ServiceCode),
+		ServiceName: aws.ToString(quota.ServiceName),
+		QuotaCode:   aws.ToString(quota.QuotaCode),
+		QuotaName:   aws.ToString(quota.QuotaName),
+		Value:       aws.ToFloat64(quota.Value),
+		Unit:        aws.ToString(quota.Unit),
+		Adjustable:  quota.Adjustable,
+		GlobalQuota: quota.GlobalQuota,
+	}
+}
+
+// addQuotaUsage reads the usage metric of each quota that has one. metrics
+// holds the usage metric of the quota at the same index. Quotas stay without
+// usage when CloudWatch cannot be read, since the limits are still useful.
+func (c *Client) addQuotaUsage(ctx context.Context, quotas []types.ServiceQuota, metrics []*sqtypes.MetricInfo) {
+	var queries []cwtypes.MetricDataQuery
+	indexes := make(map[string]int)
+	for i, metric := range metrics {
+		if metric == nil || metric.MetricName == nil || metric.MetricNamespace == nil {
+			continue
+		}
+		dimensions := make([]cwtypes.Dimension, 0, len(metric.MetricDimensions))
+		for name, value := range metric.MetricDimensions {
+			dimensions = append(dimensions, cwtypes.Dimension{Name: aws.String(name), Value: aws.String(value)})
+		}
+		statistic := aws.ToString(metric.MetricStatisticRecommendation)
+		if statistic == "" {
+			statistic = "Maximum"
+		}
+		id := fmt.Sprintf("q%d", i)
+		indexes[id] = i
+		queries = append(queries, cwtypes.MetricDataQuery{
+			Id: aws.String(id),
+			MetricStat: &cwtypes.MetricStat{
+				Metric: &cwtypes.Metric{
+					Namespace:  metric.MetricNamespace,
+					MetricName: metric.MetricName,
+					Dimensions: dimensions,
+				},
+				Period: aws.Int32(300),
+				Stat:   aws.String(statistic),
+			},
+		})
+	}
+
+	end := time.Now()
+	for batch := range slices.Chunk(queries, sqsMetricQueriesBatch) {
+		values := make(map[string][]float64, len(batch))
+		paginator := cloudwatch.NewGetMetricDataPaginator(c.cloudwatch, &cloudwatch.GetMetricDataInput{
+			StartTime:         aws.Time(end.Add(-QuotaUsageWindow)),
+			EndTime:           aws.Time(end),
+			MetricDataQueries: batch,
+		})
+		for paginator.HasMorePages() {
+			page, err := paginator.NextPage(ctx)
+			if err != nil {
+				c.logger.WithError(err).Warn("Failed to get service quota usage")
+				return
+			}
+			for _, result := range page.MetricDataResults {
+				id := aws.ToString(result.Id)
+				values[id] = append(values[id], result.Values...)
+			}
+		}
+
+		for _, query := range batch {
+			id := aws.ToString(query.Id)
+			// Quotas that were not used in the window have no data points and
+			// a usage of zero
+			usage := maxValue(values[id])
+			quotas[indexes[id]].Usage = &usage
+		}
+	}
+}
+
+// ListQuotaIncreaseRequests retrieves the quota increase requests of a service
+// from the last 90 days, newest first
+func (c *Client) ListQuotaIncreaseRequests(ctx context.Context, serviceCode string) ([]types.QuotaIncreaseRequest, error) {
+	var requests []types.QuotaIncreaseRequest
+	paginator := servicequotas.NewListRequestedServiceQuotaChangeHistoryPaginator(c.servicequotas, &servicequotas.ListRequestedServiceQuotaChangeHistoryInput{
+		ServiceCode: aws.String(serviceCode),
+		MaxResults:  aws.Int32(100),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			c.logger.WithError(err).WithField("service", serviceCode).Error("Failed to list quota increase requests")
+			return nil, fmt.Errorf("failed to list quota increase requests of %s: %w", serviceCode, err)
+		}
+		for _, request := range page.RequestedQuotas {
+			requests = append(requests, convertQuotaIncreaseRequest(request))
+		}
+	}
+
+	slices.SortFunc(requests, func(a, b types.QuotaIncreaseRequest) int {
+		return b.Created.Compare(a.Created)
+	})
+	return requests, nil
+}
+
+// RequestQuotaIncrease asks AWS to raise a quota to the desired value. Large
+// increases are reviewed in a support case.
+func (c *Client) RequestQuotaIncrease(ctx context.Context, serviceCode, quotaCode string, desiredValue float64) (*types.QuotaIncreaseRequest, error) {
+	result, err := c.servicequotas.RequestServiceQuotaIncrease(ctx, &servicequotas.RequestServiceQuotaIncreaseInput{
+		ServiceCode:  aws.String(serviceCode),
+		QuotaCode:    aws.String(quotaCode),
+		DesiredValue: aws.Float64(desiredValue),
+	})
+	if err != nil {
+		c.logger.WithError(err).WithFields(logrus.Fields{
+			"service": serviceCode,
+			"quota":   quotaCode,
+		}).Error("Failed to request quota increase")
+		return nil, fmt.Errorf("failed to request increase of quota %s of %s: %w", quotaCode, serviceCode, err)
+	}
+	if result.RequestedQuota == nil {
+		return nil, fmt.Errorf("quota increase of %s was not recorded", quotaCode)
+	}
+
+	request := convertQuotaIncreaseRequest(*result.RequestedQuota)
+	c.logger.WithFields(logrus.Fields{
+		"service":       serviceCode,
+		"quota":         quotaCode,
+		"desired_value": desiredValue,
+		"request_id":    request.ID,
+	}).Info("Requested quota increase")
+
+	return &request, nil
+}
+
+// convertQuotaIncreaseRequest converts a requested quota change
+func convertQuotaIncreaseRequest(request sqtypes.RequestedServiceQuotaChange) types.QuotaIncreaseRequest {
+	return types.QuotaIncreaseRequest{
+		ID:           aws.ToString(request.Id),
+		ServiceCode:  aws.ToString(request.ServiceCode),
+		QuotaCode:    aws.ToString(request.QuotaCode),
+		QuotaName:    aws.ToString(request.QuotaName),
+		DesiredValue: aws.ToFloat64(request.DesiredValue),
+		Status:       string(request.Status),
+		CaseID:       aws.ToString(request.CaseId),
+		Created:      aws.ToTime(request.Created),
+	}
+}
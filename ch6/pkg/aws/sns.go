@@ -0,0 +1,175 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sns"
+	snstypes "github.com/aws/aws-sdk-go-v2/service/sns/types"
+
+	"aws-mcp-server/pkg/types"
+
+	"github.com/sirupsen/logrus"
+)
+
+// snsPendingConfirmation is the subscription ARN SNS reports until the
+// endpoint confirms the subscription
+const snsPendingConfirmation = "PendingConfirmation"
+
+// SNSPublishParams describes a message to publish. GroupID is required for
+// FIFO topics; DeduplicationID is needed there unless the topic deduplicates
+// by content.
+type SNSPublishParams struct {
+	TopicARN        string
+	Subject         string
+	Message         string
+	Attributes      map[string]string
+	GroupID         string
+	DeduplicationID string
+}
+
+// ListSNSTopics retrieves all SNS topics with their subscription counts
+func (c *Client) ListSNSTopics(ctx context.Context) ([]types.SNSTopic, error) {
+	start := time.Now()
+
+	var arns []string
+	paginator := sns.NewListTopicsPaginator(c.sns, &sns.ListTopicsInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			c.logger.WithError(err).Error("Failed to list SNS topics")
+			return nil, fmt.Errorf("failed to list SNS topics: %w", err)
+		}
+		for _, topic := range page.Topics {
+			arns = append(arns, aws.ToString(topic.TopicArn))
+		}
+	}
+
+	topics := make([]types.SNSTopic, 0, len(arns))
+	for _, arn := range arns {
+		topic, err := c.GetSNSTopic(ctx, arn)
+		if err != nil {
+			return nil, err
+		}
+		topics = append(topics, *topic)
+	}
+
+	c.logger.WithFields(logrus.Fields{
+		"count":    len(topics),
+		"duration": time.Since(start),
+	}).Info("Retrieved SNS topics")
+
+	return topics, nil
+}
+
+// FindSNSTopicARN returns the ARN of the topic with the given name
+func (c *Client) FindSNSTopicARN(ctx context.Context, name string) (string, error) {
+	paginator := sns.NewListTopicsPaginator(c.sns, &sns.ListTopicsInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			c.logger.WithError(err).Error("Failed to list SNS topics")
+			return "", fmt.Errorf("failed to list SNS topics: %w", err)
+		}
+		for _, topic := range page.Topics {
+			arn := aws.ToString(topic.TopicArn)
+			if strings.HasSuffix(arn, ":"+name) {
+				return arn, nil
+			}
+		}
+	}
+	return "", fmt.Errorf("topic %s not found", name)
+}
+
+// GetSNSTopic retrieves one topic by ARN
+func (c *Client) GetSNSTopic(ctx context.Context, topicARN string) (*types.SNSTopic, error) {
+	result, err := c.sns.GetTopicAttributes(ctx, &sns.GetTopicAttributesInput{TopicArn: aws.String(topicARN)})
+	if err != nil {
+		c.logger.WithError(err).WithField("topic", topicARN).Error("Failed to get SNS topic attributes")
+		return nil, fmt.Errorf("failed to get attributes of topic %s: %w", topicARN, err)
+	}
+
+	count := func(name string) int {
+		value, _ := strconv.Atoi(result.Attributes[name])
+		return value
+	}
+	return &types.SNSTopic{
+		Name:        topicARN[strings.LastIndex(topicARN, ":")+1:],
+		ARN:         topicARN,
+		DisplayName: result.Attributes["DisplayName"],
+		FIFO:        result.Attributes["FifoTopic"] == "true",
+		Encrypted:   result.Attributes["KmsMasterKeyId"] != "",
+		Confirmed:   count("SubscriptionsConfirmed"),
+		Pending:     count("SubscriptionsPending"),
+		Deleted:     count("SubscriptionsDeleted"),
+	}, nil
+}
+
+// ListSNSSubscriptions retrieves the subscriptions of a topic
+func (c *Client) ListSNSSubscriptions(ctx context.Context, topicARN string) ([]types.SNSSubscription, error) {
+	var subscriptions []types.SNSSubscription
+	paginator := sns.NewListSubscriptionsByTopicPaginator(c.sns, &sns.ListSubscriptionsByTopicInput{TopicArn: aws.String(topicARN)})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			c.logger.WithError(err).WithField("topic", topicARN).Error("Failed to list SNS subscriptions")
+			return nil, fmt.Errorf("failed to list subscriptions of topic %s: %w", topicARN, err)
+		}
+		for _, subscription := range page.Subscriptions {
+			arn := aws.ToString(subscription.SubscriptionArn)
+			converted := types.SNSSubscription{
+				Protocol: aws.ToString(subscription.Protocol),
+				Endpoint: aws.ToString(subscription.Endpoint),
+				Pending:  arn == snsPendingConfirmation,
+			}
+			if !converted.Pending {
+				converted.ARN = arn
+			}
+			subscriptions = append(subscriptions, converted)
+		}
+	}
+	return subscriptions, nil
+}
+
+// PublishSNSMessage publishes a message to a topic and returns its message ID
+func (c *Client) PublishSNSMessage(ctx context.Context, params SNSPublishParams) (string, error) {
+	input := &sns.PublishInput{
+		TopicArn: aws.String(params.TopicARN),
+		Message:  aws.String(params.Message),
+	}
+	if params.Subject != "" {
+		input.Subject = aws.String(params.Subject)
+	}
+	if params.GroupID != "" {
+		input.MessageGroupId = aws.String(params.GroupID)
+	}
+	if params.DeduplicationID != "" {
+		input.MessageDeduplicationId = aws.String(params.DeduplicationID)
+	}
+	if len(params.Attributes) > 0 {
+		input.MessageAttributes = make(map[string]snstypes.MessageAttributeValue, len(params.Attributes))
+		for name, value := range params.Attributes {
+			input.MessageAttributes[name] = snstypes.MessageAttributeValue{
+				DataType:    aws.String("String"),
+				StringValue: aws.String(value),
+			}
+		}
+	}
+
+	result, err := c.sns.Publish(ctx, input)
+	if err != nil {
+		c.logger.WithError(err).WithField("topic", params.TopicARN).Error("Failed to publish SNS message")
+		return "", fmt.Errorf("failed to publish to topic %s: %w", params.TopicARN, err)
+	}
+
+	c.logger.WithFields(logrus.Fields{
+		"topic":     params.TopicARN,
+		"messageId": aws.ToString(result.MessageId),
+	}).Info("Published SNS message")
+
+	return aws.ToString(result.MessageId), nil
+}
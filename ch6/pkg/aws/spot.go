@@ -0,0 +1,130 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudtrail"
+	cttypes "github.com/aws/aws-sdk-go-v2/service/cloudtrail/types"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+
+	"aws-mcp-server/pkg/types"
+
+	"github.com/sirupsen/logrus"
+)
+
+// SpotOptions launch an instance on the Spot market. MaxPrice is in USD per
+// hour; empty caps it at the On-Demand price. InterruptionBehavior is
+// terminate, stop or hibernate; instances that stop or hibernate need a
+// persistent request, which AWS fulfills again once capacity returns.
+type SpotOptions struct {
+	MaxPrice             string
+	InterruptionBehavior string
+}
+
+// marketOptions returns the RunInstances market options of a Spot launch
+func (o SpotOptions) marketOptions() *ec2types.InstanceMarketOptionsRequest {
+	spot := &ec2types.SpotMarketOptions{
+		SpotInstanceType: ec2types.SpotInstanceTypeOneTime,
+	}
+	if o.MaxPrice != "" {
+		spot.MaxPrice = aws.String(o.MaxPrice)
+	}
+	if o.InterruptionBehavior != "" {
+		spot.InstanceInterruptionBehavior = ec2types.InstanceInterruptionBehavior(o.InterruptionBehavior)
+		if spot.InstanceInterruptionBehavior != ec2types.InstanceInterruptionBehaviorTerminate {
+			spot.SpotInstanceType = ec2types.SpotInstanceTypePersistent
+		}
+	}
+
+	return &ec2types.InstanceMarketOptionsRequest{
+		MarketType:  ec2types.MarketTypeSpot,
+		SpotOptions: spot,
+	}
+}
+
+// ListSpotRequests retrieves the Spot Instance requests of the region. AWS
+// keeps closed and cancelled requests for about four hours.
+func (c *Client) ListSpotRequests(ctx context.Context) ([]types.SpotRequest, error) {
+	start := time.Now()
+
+	var requests []types.SpotRequest
+	paginator := ec2.NewDescribeSpotInstanceRequestsPaginator(c.ec2, &ec2.DescribeSpotInstanceRequestsInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			c.logger.WithError(err).Error("Failed to describe Spot Instance requests")
+			return nil, fmt.Errorf("failed to describe Spot Instance requests: %w", err)
+		}
+
+		for _, request := range page.SpotInstanceRequests {
+			converted := types.SpotRequest{
+				ID:                   aws.ToString(request.SpotInstanceRequestId),
+				State:                string(request.State),
+				Type:                 string(request.Type),
+				InstanceID:           aws.ToString(request.InstanceId),
+				AvailabilityZone:     aws.ToString(request.LaunchedAvailabilityZone),
+				MaxPrice:             aws.ToString(request.SpotPrice),
+				InterruptionBehavior: string(request.InstanceInterruptionBehavior),
+				Created:              aws.ToTime(request.CreateTime),
+			}
+			if request.Status != nil {
+				converted.StatusCode = aws.ToString(request.Status.Code)
+				converted.StatusMessage = aws.ToString(request.Status.Message)
+				converted.StatusUpdated = request.Status.UpdateTime
+			}
+			if request.LaunchSpecification != nil {
+				converted.InstanceType = string(request.LaunchSpecification.InstanceType)
+			}
+			requests = append(requests, converted)
+		}
+	}
+
+	c.logger.WithFields(logrus.Fields{
+		"count":    len(requests),
+		"duration": time.Since(start),
+	}).Info("Retrieved Spot Instance requests")
+
+	return requests, nil
+}
+
+// ListSpotInterruptions retrieves the Spot Instance interruptions CloudTrail
+// recorded since the given time, newest first
+func (c *Client) ListSpotInterruptions(ctx context.Context, since time.Time) ([]types.SpotInterruption, error) {
+	input := &cloudtrail.LookupEventsInput{
+		StartTime: aws.Time(since),
+		EndTime:   aws.Time(time.Now()),
+		LookupAttributes: []cttypes.LookupAttribute{
+			{
+				AttributeKey:   cttypes.LookupAttributeKeyEventName,
+				AttributeValue: aws.String("BidEvictedEvent"),
+			},
+		},
+	}
+
+	var interruptions []types.SpotInterruption
+	paginator := cloudtrail.NewLookupEventsPaginator(c.cloudtrail, input)
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			c.logger.WithError(err).Error("Failed to look up Spot interruption events")
+			return nil, fmt.Errorf("failed to look up CloudTrail events: %w", err)
+		}
+
+		for _, event := range page.Events {
+			for _, resource := range event.Resources {
+				if id := aws.ToString(resource.ResourceName); strings.HasPrefix(id, "i-") {
+					interruptions = append(interruptions, types.SpotInterruption{
+						Time:       aws.ToTime(event.EventTime),
+						InstanceID: id,
+					})
+				}
+			}
+		}
+	}
+	return interruptions, nil
+}
@@ -0,0 +1,272 @@
+package aws
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+	cwtypes "github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	sqstypes "github.com/aws/aws-sdk-go-v2/service/sqs/types"
+
+	"aws-mcp-server/pkg/types"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// sqsMetricQueriesBatch is the most queries GetMetricData accepts
+	sqsMetricQueriesBatch = 500
+	// sqsAgeWindow is how far back the age of the oldest message is looked up;
+	// SQS publishes it every minute, but only while a queue is active
+	sqsAgeWindow = 15 * time.Minute
+)
+
+// ListSQSQueues retrieves all SQS queues with their depth and the age of their
+// oldest message
+func (c *Client) ListSQSQueues(ctx context.Context) ([]types.SQSQueue, error) {
+	start := time.Now()
+
+	var urls []string
+	paginator := sqs.NewListQueuesPaginator(c.sqs, &sqs.ListQueuesInput{MaxResults: aws.Int32(1000)})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			c.logger.WithError(err).Error("Failed to list SQS queues")
+			return nil, fmt.Errorf("failed to list SQS queues: %w", err)
+		}
+		urls = append(urls, page.QueueUrls...)
+	}
+
+	queues := make([]types.SQSQueue, 0, len(urls))
+	for _, url := range urls {
+		queue, err := c.describeSQSQueue(ctx, url)
+		if err != nil {
+			return nil, err
+		}
+		queues = append(queues, *queue)
+	}
+	c.addOldestMessageAges(ctx, queues)
+
+	c.logger.WithFields(logrus.Fields{
+		"count":    len(queues),
+		"duration": time.Since(start),
+	}).Info("Retrieved SQS queues")
+
+	return queues, nil
+}
+
+// GetSQSQueue retrieves one queue by name
+func (c *Client) GetSQSQueue(ctx context.Context, name string) (*types.SQSQueue, error) {
+	result, err := c.sqs.GetQueueUrl(ctx, &sqs.GetQueueUrlInput{QueueName: aws.String(name)})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get URL of queue %s: %w", name, err)
+	}
+
+	queue, err := c.describeSQSQueue(ctx, aws.ToString(result.QueueUrl))
+	if err != nil {
+		return nil, err
+	}
+	queues := []types.SQSQueue{*queue}
+	c.addOldestMessageAges(ctx, queues)
+	return &queues[0], nil
+}
+
+// describeSQSQueue reads the attributes of a queue
+func (c *Client) describeSQSQueue(ctx context.Context, url string) (*types.SQSQueue, error) {
+	result, err := c.sqs.GetQueueAttributes(ctx, &sqs.GetQueueAttributesInput{
+		QueueUrl:       aws.String(url),
+		AttributeNames: []sqstypes.QueueAttributeName{sqstypes.QueueAttributeNameAll},
+	})
+	if err != nil {
+		c.logger.WithError(err).WithField("queue", url).Error("Failed to get SQS queue attributes")
+		return nil, fmt.Errorf("failed to get attributes of queue %s: %w", url, err)
+	}
+	return convertSQSQueue(url, result.Attributes), nil
+}
+
+// convertSQSQueue converts queue attributes, which SQS returns as strings
+func convertSQSQueue(url string, attributes map[string]string) *types.SQSQueue {
+	number := func(name sqstypes.QueueAttributeName) int64 {
+		value, _ := strconv.ParseInt(attributes[string(name)], 10, 64)
+		return value
+	}
+
+	queue := &types.SQSQueue{
+		URL:               url,
+		ARN:               attributes[string(sqstypes.QueueAttributeNameQueueArn)],
+		FIFO:              attributes[string(sqstypes.QueueAttributeNameFifoQueue)] == "true",
+		Visible:           number(sqstypes.QueueAttributeNameApproximateNumberOfMessages),
+		InFlight:          number(sqstypes.QueueAttributeNameApproximateNumberOfMessagesNotVisible),
+		Delayed:           number(sqstypes.QueueAttributeNameApproximateNumberOfMessagesDelayed),
+		VisibilityTimeout: int32(number(sqstypes.QueueAttributeNameVisibilityTimeout)),
+		RetentionPeriod:   int32(number(sqstypes.QueueAttributeNameMessageRetentionPeriod)),
+	}
+	if created := number(sqstypes.QueueAttributeNameCreatedTimestamp); created > 0 {
+		queue.CreatedAt = time.Unix(created, 0)
+	}
+	queue.Name = queueNameFromARN(queue.ARN)
+
+	// maxReceiveCount is a string in policies set through the console and a
+	// number in those set through the API
+	var policy struct {
+		DeadLetterTargetARN string          `json:"deadLetterTargetArn"`
+		MaxReceiveCount     json.RawMessage `json:"maxReceiveCount"`
+	}
+	if raw := attributes[string(sqstypes.QueueAttributeNameRedrivePolicy)]; raw != "" && json.Unmarshal([]byte(raw), &policy) == nil {
+		queue.DeadLetterQueueARN = policy.DeadLetterTargetARN
+		queue.MaxReceiveCount, _ = strconv.Atoi(strings.Trim(string(policy.MaxReceiveCount), `"`))
+	}
+	return queue
+}
+
+// queueNameFromARN returns the queue name, the last part of its ARN
+func queueNameFromARN(arn string) string {
+	return arn[strings.LastIndex(arn, ":")+1:]
+}
+
+// addOldestMessageAges fills in the latest ApproximateAgeOfOldestMessage of
+// each queue. The metric is best effort: queues keep a nil age when CloudWatch
+// cannot be read or has no recent datapoint.
+func (c *Client) addOldestMessageAges(ctx context.Context, queues []types.SQSQueue) {
+	end := time.Now()
+	for batch := range slices.Chunk(queues, sqsMetricQueriesBatch) {
+		queries := make([]cwtypes.MetricDataQuery, 0, len(batch))
+		for i, queue := range batch {
+			queries = append(queries, cwtypes.MetricDataQuery{
+				Id: aws.String(fmt.Sprintf("q%d", i)),
+				MetricStat: &cwtypes.MetricStat{
+					Metric: &cwtypes.Metric{
+						Namespace:  aws.String("AWS/SQS"),
+						MetricName: aws.String("ApproximateAgeOfOldestMessage"),
+						Dimensions: []cwtypes.Dimension{{Name: aws.String("QueueName"), Value: aws.String(queue.Name)}},
+					},
+					Period: aws.Int32(60),
+					Stat:   aws.String("Maximum"),
+				},
+			})
+		}
+
+		paginator := cloudwatch.NewGetMetricDataPaginator(c.cloudwatch, &cloudwatch.GetMetricDataInput{
+			StartTime:         aws.Time(end.Add(-sqsAgeWindow)),
+			EndTime:           aws.Time(end),
+			ScanBy:            cwtypes.ScanByTimestampDescending,
+			MetricDataQueries: queries,
+		})
+		for paginator.HasMorePages() {
+			page, err := paginator.NextPage(ctx)
+			if err != nil {
+				c.logger.WithError(err).Warn("Failed to get the age of the oldest SQS messages")
+				return
+			}
+			for _, result := range page.MetricDataResults {
+				var index int
+				if _, err := fmt.Sscanf(aws.ToString(result.Id), "q%d", &index); err != nil || index >= len(batch) {
+					continue
+				}
+				if len(result.Values) > 0 && batch[index].OldestMessageAge == nil {
+					age := result.Values[0]
+					batch[index].OldestMessageAge = &age
+				}
+			}
+		}
+	}
+}
+
+// ListSQSDeadLetterSources returns the names of the queues that use a queue as
+// their dead letter queue
+func (c *Client) ListSQSDeadLetterSources(ctx context.Context, queueURL string) ([]string, error) {
+	var names []string
+	paginator := sqs.NewListDeadLetterSourceQueuesPaginator(c.sqs, &sqs.ListDeadLetterSourceQueuesInput{
+		QueueUrl:   aws.String(queueURL),
+		MaxResults: aws.Int32(1000),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list source queues of %s: %w", queueURL, err)
+		}
+		for _, url := range page.QueueUrls {
+			names = append(names, queueNameFromURL(url))
+		}
+	}
+	return names, nil
+}
+
+// queueNameFromURL returns the queue name, the last path segment of its URL
+func queueNameFromURL(url string) string {
+	return url[strings.LastIndex(url, "/")+1:]
+}
+
+// PurgeSQSQueue deletes every message in a queue. SQS allows one purge per
+// queue every 60 seconds.
+func (c *Client) PurgeSQSQueue(ctx context.Context, queueURL string) error {
+	_, err := c.sqs.PurgeQueue(ctx, &sqs.PurgeQueueInput{QueueUrl: aws.String(queueURL)})
+	if err != nil {
+		c.logger.WithError(err).WithField("queue", queueURL).Error("Failed to purge SQS queue")
+		return fmt.Errorf("failed to purge queue %s: %w", queueURL, err)
+	}
+
+	c.logger.WithField("queue", queueURL).Info("Purged SQS queue")
+	return nil
+}
+
+// StartSQSRedrive starts moving the messages of a dead letter queue back to
+// the queues they came from, or to destinationARN when it is set. A
+// maxPerSecond of zero lets SQS choose the rate.
+func (c *Client) StartSQSRedrive(ctx context.Context, sourceARN, destinationARN string, maxPerSecond int32) (string, error) {
+	input := &sqs.StartMessageMoveTaskInput{SourceArn: aws.String(sourceARN)}
+	if destinationARN != "" {
+		input.DestinationArn = aws.String(destinationARN)
+	}
+	if maxPerSecond > 0 {
+		input.MaxNumberOfMessagesPerSecond = aws.Int32(maxPerSecond)
+	}
+
+	result, err := c.sqs.StartMessageMoveTask(ctx, input)
+	if err != nil {
+		c.logger.WithError(err).WithField("source", sourceARN).Error("Failed to start SQS redrive")
+		return "", fmt.Errorf("failed to start redrive from %s: %w", sourceARN, err)
+	}
+
+	c.logger.WithFields(logrus.Fields{
+		"source":      sourceARN,
+		"destination": destinationARN,
+	}).Info("Started SQS redrive")
+
+	return aws.ToString(result.TaskHandle), nil
+}
+
+// ListSQSMoveTasks returns the latest message move tasks of a dead letter
+// queue, newest first
+func (c *Client) ListSQSMoveTasks(ctx context.Context, sourceARN string) ([]types.SQSMoveTask, error) {
+	result, err := c.sqs.ListMessageMoveTasks(ctx, &sqs.ListMessageMoveTasksInput{
+		SourceArn:  aws.String(sourceARN),
+		MaxResults: aws.Int32(10),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list redrive tasks of %s: %w", sourceARN, err)
+	}
+
+	tasks := make([]types.SQSMoveTask, 0, len(result.Results))
+	for _, entry := range result.Results {
+		tasks = append(tasks, types.SQSMoveTask{
+			Handle:         aws.ToString(entry.TaskHandle),
+			Status:         aws.ToString(entry.Status),
+			SourceARN:      aws.ToString(entry.SourceArn),
+			DestinationARN: aws.ToString(entry.DestinationArn),
+			Moved:          entry.ApproximateNumberOfMessagesMoved,
+			ToMove:         entry.ApproximateNumberOfMessagesToMove,
+			MaxPerSecond:   entry.MaxNumberOfMessagesPerSecond,
+			FailureReason:  aws.ToString(entry.FailureReason),
+			StartedAt:      time.UnixMilli(entry.StartedTimestamp),
+		})
+	}
+	return tasks, nil
+}
@@ -0,0 +1,132 @@
+package aws
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	ssmtypes "github.com/aws/aws-sdk-go-v2/service/ssm/types"
+
+	"aws-mcp-server/pkg/types"
+
+	"github.com/sirupsen/logrus"
+)
+
+// ErrParameterNotFound is returned when a parameter does not exist
+var ErrParameterNotFound = errors.New("parameter not found")
+
+// PutParameterParams describes a parameter to create or overwrite. KMSKeyID
+// only applies to SecureString parameters; empty uses the AWS managed key.
+type PutParameterParams struct {
+	Name        string
+	Value       string
+	Type        string
+	Description string
+	KMSKeyID    string
+	Overwrite   bool
+}
+
+// ListParameters retrieves the parameters under a path and its sub-paths.
+// SecureString values are only decrypted when decrypt is set and are left
+// empty otherwise.
+func (c *Client) ListParameters(ctx context.Context, path string, decrypt bool) ([]types.Parameter, error) {
+	start := time.Now()
+
+	var parameters []types.Parameter
+	paginator := ssm.NewGetParametersByPathPaginator(c.ssm, &ssm.GetParametersByPathInput{
+		Path:           aws.String(path),
+		Recursive:      aws.Bool(true),
+		WithDecryption: aws.Bool(decrypt),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			c.logger.WithError(err).WithField("path", path).Error("Failed to get SSM parameters")
+			return nil, fmt.Errorf("failed to get parameters under %s: %w", path, err)
+		}
+
+		for _, parameter := range page.Parameters {
+			parameters = append(parameters, convertParameter(parameter, decrypt))
+		}
+	}
+
+	c.logger.WithFields(logrus.Fields{
+		"path":     path,
+		"count":    len(parameters),
+		"duration": time.Since(start),
+	}).Info("Retrieved SSM parameters")
+
+	return parameters, nil
+}
+
+// GetParameter retrieves one parameter by name, decrypting SecureString
+// values only when decrypt is set
+func (c *Client) GetParameter(ctx context.Context, name string, decrypt bool) (*types.Parameter, error) {
+	result, err := c.ssm.GetParameter(ctx, &ssm.GetParameterInput{
+		Name:           aws.String(name),
+		WithDecryption: aws.Bool(decrypt),
+	})
+	if err != nil {
+		var notFound *ssmtypes.ParameterNotFound
+		if errors.As(err, &notFound) {
+			return nil, fmt.Errorf("%w: %s", ErrParameterNotFound, name)
+		}
+		return nil, fmt.Errorf("failed to get parameter %s: %w", name, err)
+	}
+
+	parameter := convertParameter(*result.Parameter, decrypt)
+	return &parameter, nil
+}
+
+// PutParameter creates or overwrites a parameter and returns its new version
+func (c *Client) PutParameter(ctx context.Context, params PutParameterParams) (int64, error) {
+	input := &ssm.PutParameterInput{
+		Name:      aws.String(params.Name),
+		Value:     aws.String(params.Value),
+		Type:      ssmtypes.ParameterType(params.Type),
+		Overwrite: aws.Bool(params.Overwrite),
+	}
+	if params.Description != "" {
+		input.Description = aws.String(params.Description)
+	}
+	if params.KMSKeyID != "" && params.Type == string(ssmtypes.ParameterTypeSecureString) {
+		input.KeyId = aws.String(params.KMSKeyID)
+	}
+
+	result, err := c.ssm.PutParameter(ctx, input)
+	if err != nil {
+		c.logger.WithError(err).WithField("name", params.Name).Error("Failed to put SSM parameter")
+		return 0, fmt.Errorf("failed to put parameter %s: %w", params.Name, err)
+	}
+
+	c.logger.WithFields(logrus.Fields{
+		"name":    params.Name,
+		"type":    params.Type,
+		"version": result.Version,
+	}).Info("Put SSM parameter")
+
+	return result.Version, nil
+}
+
+// convertParameter converts an SSM parameter, dropping the ciphertext of
+// SecureString values that were not decrypted
+func convertParameter(parameter ssmtypes.Parameter, decrypted bool) types.Parameter {
+	converted := types.Parameter{
+		Name:     aws.ToString(parameter.Name),
+		Type:     string(parameter.Type),
+		Value:    aws.ToString(parameter.Value),
+		Version:  parameter.Version,
+		DataType: aws.ToString(parameter.DataType),
+		ARN:      aws.ToString(parameter.ARN),
+	}
+	if parameter.LastModifiedDate != nil {
+		converted.LastModified = *parameter.LastModifiedDate
+	}
+	if parameter.Type == ssmtypes.ParameterTypeSecureString && !decrypted {
+		converted.Value = ""
+	}
+	return converted
+}
@@ -0,0 +1,196 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sfn"
+	sfntypes "github.com/aws/aws-sdk-go-v2/service/sfn/types"
+
+	"aws-mcp-server/pkg/types"
+
+	"github.com/sirupsen/logrus"
+)
+
+// failureHistoryEvents is how many of the last history events are searched
+// for the error and the state an execution failed in
+const failureHistoryEvents = 50
+
+// ListStateMachines retrieves all Step Functions state machines
+func (c *Client) ListStateMachines(ctx context.Context) ([]types.StateMachine, error) {
+	start := time.Now()
+
+	var machines []types.StateMachine
+	paginator := sfn.NewListStateMachinesPaginator(c.sfn, &sfn.ListStateMachinesInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			c.logger.WithError(err).Error("Failed to list state machines")
+			return nil, fmt.Errorf("failed to list state machines: %w", err)
+		}
+
+		for _, machine := range page.StateMachines {
+			machines = append(machines, types.StateMachine{
+				ARN:       aws.ToString(machine.StateMachineArn),
+				Name:      aws.ToString(machine.Name),
+				Type:      string(machine.Type),
+				CreatedAt: aws.ToTime(machine.CreationDate),
+			})
+		}
+	}
+
+	c.logger.WithFields(logrus.Fields{
+		"count":    len(machines),
+		"duration": time.Since(start),
+	}).Info("Retrieved state machines")
+
+	return machines, nil
+}
+
+// ListExecutions retrieves up to limit of the most recent executions of a
+// state machine, newest first
+func (c *Client) ListExecutions(ctx context.Context, stateMachineARN string, limit int) ([]types.Execution, error) {
+	var executions []types.Execution
+	paginator := sfn.NewListExecutionsPaginator(c.sfn, &sfn.ListExecutionsInput{
+		StateMachineArn: aws.String(stateMachineARN),
+		MaxResults:      int32(min(limit, 1000)),
+	})
+	for paginator.HasMorePages() && len(executions) < limit {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			c.logger.WithError(err).WithField("state_machine", stateMachineARN).Error("Failed to list executions")
+			return nil, fmt.Errorf("failed to list executions of %s: %w", stateMachineARN, err)
+		}
+
+		for _, execution := range page.Executions {
+			executions = append(executions, types.Execution{
+				ARN:             aws.ToString(execution.ExecutionArn),
+				Name:            aws.ToString(execution.Name),
+				StateMachineARN: aws.ToString(execution.StateMachineArn),
+				Status:          string(execution.Status),
+				StartedAt:       aws.ToTime(execution.StartDate),
+				StoppedAt:       execution.StopDate,
+				RedriveCount:    aws.ToInt32(execution.RedriveCount),
+			})
+		}
+	}
+
+	return executions[:min(len(executions), limit)], nil
+}
+
+// DescribeExecution retrieves an execution with whether it can be redriven
+// and, when it did not succeed, the error, cause and state it failed in
+func (c *Client) DescribeExecution(ctx context.Context, executionARN string) (*types.Execution, error) {
+	result, err := c.sfn.DescribeExecution(ctx, &sfn.DescribeExecutionInput{
+		ExecutionArn: aws.String(executionARN),
+	})
+	if err != nil {
+		c.logger.WithError(err).WithField("execution", executionARN).Error("Failed to describe execution")
+		return nil, fmt.Errorf("failed to describe execution %s: %w", executionARN, err)
+	}
+
+	execution := &types.Execution{
+		ARN:                 aws.ToString(result.ExecutionArn),
+		Name:                aws.ToString(result.Name),
+		StateMachineARN:     aws.ToString(result.StateMachineArn),
+		Status:              string(result.Status),
+		StartedAt:           aws.ToTime(result.StartDate),
+		StoppedAt:           result.StopDate,
+		RedriveCount:        aws.ToInt32(result.RedriveCount),
+		RedriveStatus:       string(result.RedriveStatus),
+		RedriveStatusReason: aws.ToString(result.RedriveStatusReason),
+		Error:               aws.ToString(result.Error),
+		Cause:               aws.ToString(result.Cause),
+	}
+
+	switch result.Status {
+	case sfntypes.ExecutionStatusFailed, sfntypes.ExecutionStatusTimedOut, sfntypes.ExecutionStatusAborted:
+		history, err := c.sfn.GetExecutionHistory(ctx, &sfn.GetExecutionHistoryInput{
+			ExecutionArn:         aws.String(executionARN),
+			ReverseOrder:         true,
+			MaxResults:           failureHistoryEvents,
+			IncludeExecutionData: aws.Bool(false),
+		})
+		if err != nil {
+			c.logger.WithError(err).WithField("execution", executionARN).Warn("Failed to get execution history")
+			break
+		}
+		state, errorName, cause := historyFailure(history.Events)
+		execution.FailedState = state
+		if execution.Error == "" {
+			execution.Error = errorName
+		}
+		if execution.Cause == "" {
+			execution.Cause = cause
+		}
+	}
+
+	return execution, nil
+}
+
+// historyFailure finds the last state entered and the first error in history
+// events listed newest first
+func historyFailure(events []sfntypes.HistoryEvent) (state, errorName, cause string) {
+	for _, event := range events {
+		if state == "" && event.StateEnteredEventDetails != nil {
+			state = aws.ToString(event.StateEnteredEventDetails.Name)
+		}
+		if errorName != "" {
+			continue
+		}
+		switch {
+		case event.TaskFailedEventDetails != nil:
+			errorName, cause = aws.ToString(event.TaskFailedEventDetails.Error), aws.ToString(event.TaskFailedEventDetails.Cause)
+		case event.TaskTimedOutEventDetails != nil:
+			errorName, cause = aws.ToString(event.TaskTimedOutEventDetails.Error), aws.ToString(event.TaskTimedOutEventDetails.Cause)
+		case event.LambdaFunctionFailedEventDetails != nil:
+			errorName, cause = aws.ToString(event.LambdaFunctionFailedEventDetails.Error), aws.ToString(event.LambdaFunctionFailedEventDetails.Cause)
+		case event.ActivityFailedEventDetails != nil:
+			errorName, cause = aws.ToString(event.ActivityFailedEventDetails.Error), aws.ToString(event.ActivityFailedEventDetails.Cause)
+		case event.ExecutionFailedEventDetails != nil:
+			errorName, cause = aws.ToString(event.ExecutionFailedEventDetails.Error), aws.ToString(event.ExecutionFailedEventDetails.Cause)
+		case event.ExecutionTimedOutEventDetails != nil:
+			errorName, cause = aws.ToString(event.ExecutionTimedOutEventDetails.Error), aws.ToString(event.ExecutionTimedOutEventDetails.Cause)
+		}
+	}
+	return state, errorName, cause
+}
+
+// StartExecution starts a state machine with a JSON input and returns the
+// ARN of the new execution. An empty name lets Step Functions generate one.
+func (c *Client) StartExecution(ctx context.Context, stateMachineARN, name, input string) (string, error) {
+	params := &sfn.StartExecutionInput{
+		StateMachineArn: aws.String(stateMachineARN),
+		Input:           aws.String(input),
+	}
+	if name != "" {
+		params.Name = aws.String(name)
+	}
+
+	result, err := c.sfn.StartExecution(ctx, params)
+	if err != nil {
+		c.logger.WithError(err).WithField("state_machine", stateMachineARN).Error("Failed to start execution")
+		return "", fmt.Errorf("failed to start execution of %s: %w", stateMachineARN, err)
+	}
+
+	executionARN := aws.ToString(result.ExecutionArn)
+	c.logger.WithField("execution", executionARN).Info("Started execution")
+	return executionARN, nil
+}
+
+// RedriveExecution restarts a failed, timed out or aborted execution from
+// the state that did not succeed
+func (c *Client) RedriveExecution(ctx context.Context, executionARN string) error {
+	_, err := c.sfn.RedriveExecution(ctx, &sfn.RedriveExecutionInput{
+		ExecutionArn: aws.String(executionARN),
+	})
+	if err != nil {
+		c.logger.WithError(err).WithField("execution", executionARN).Error("Failed to redrive execution")
+		return fmt.Errorf("failed to redrive execution %s: %w", executionARN, err)
+	}
+
+	c.logger.WithField("execution", executionARN).Info("Redrove execution")
+	return nil
+}
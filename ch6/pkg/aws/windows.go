@@ -0,0 +1,23 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+)
+
+// GetWindowsPasswordData retrieves the encrypted Administrator password EC2
+// generated when a Windows instance launched, with the time it was generated.
+// The password is empty until the instance has finished its first boot, which
+// takes a few minutes.
+func (c *Client) GetWindowsPasswordData(ctx context.Context, instanceID string) (string, *time.Time, error) {
+	result, err := c.ec2.GetPasswordData(ctx, &ec2.GetPasswordDataInput{InstanceId: aws.String(instanceID)})
+	if err != nil {
+		c.logger.WithError(err).WithField("instance_id", instanceID).Error("Failed to get Windows password data")
+		return "", nil, fmt.Errorf("failed to get password data of %s: %w", instanceID, err)
+	}
+	return aws.ToString(result.PasswordData), result.Timestamp, nil
+}
@@ -0,0 +1,267 @@
+package azure
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"aws-mcp-server/internal/config"
+	"aws-mcp-server/internal/logging"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
+)
+
+// ProviderName identifies Azure resources among those of other cloud providers
+const ProviderName = "azure"
+
+const (
+	// managementEndpoint is the Azure Resource Manager API
+	managementEndpoint = "https://management.azure.com/"
+	// managementResource is the token audience for Resource Manager
+	managementResource = "https://management.azure.com/"
+	// imdsTokenURL is the managed identity endpoint of the instance metadata service
+	imdsTokenURL = "http://169.254.169.254/metadata/identity/oauth2/token"
+	// defaultTimeout applies when azure.timeout is not configured
+	defaultTimeout = 30 * time.Second
+	// maxResponseSize bounds how much of an API response is read
+	maxResponseSize = 32 << 20
+)
+
+// errNotFound is returned for API calls answered with 404
+var errNotFound = errors.New("not found")
+
+// Client calls the Resource Manager API of one subscription
+type Client struct {
+	subscription   string
+	resourceGroups map[string]bool
+	endpoint       *url.URL
+	client         *http.Client
+	logger         *logging.Logger
+}
+
+// NewClient creates a client for the configured subscription. With a client
+// secret it signs in as that service principal; otherwise it uses the managed
+// identity of the host, the user-assigned one when a client ID is set.
+func NewClient(cfg config.AzureConfig, logger *logging.Logger) (*Client, error) {
+	if cfg.SubscriptionID == "" {
+		return nil, errors.New("azure.subscription_id is required")
+	}
+
+	timeout := cfg.Timeout
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+
+	ctx := context.Background()
+	var tokens oauth2.TokenSource
+	if cfg.ClientSecret != "" {
+		if cfg.TenantID == "" || cfg.ClientID == "" {
+			return nil, errors.New("azure.tenant_id and azure.client_id are required with a client secret")
+		}
+		tokens = (&clientcredentials.Config{
+			ClientID:     cfg.ClientID,
+			ClientSecret: cfg.ClientSecret,
+			TokenURL:     "https://login.microsoftonline.com/" + url.PathEscape(cfg.TenantID) + "/oauth2/v2.0/token",
+			Scopes:       []string{managementResource + ".default"},
+		}).TokenSource(ctx)
+	} else {
+		tokens = oauth2.ReuseTokenSource(nil, &managedIdentity{
+			clientID: cfg.ClientID,
+			client:   &http.Client{Timeout: timeout},
+		})
+	}
+
+	httpClient := oauth2.NewClient(ctx, tokens)
+	httpClient.Timeout = timeout
+
+	return newClient(cfg.SubscriptionID, cfg.ResourceGroups, managementEndpoint, httpClient, logger)
+}
+
+// newClient creates a client against an endpoint with an authorized HTTP client
+func newClient(subscription string, resourceGroups []string, endpoint string, httpClient *http.Client, logger *logging.Logger) (*Client, error) {
+	base, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid management endpoint %q: %w", endpoint, err)
+	}
+
+	c := &Client{
+		subscription: subscription,
+		endpoint:     base,
+		client:       httpClient,
+		logger:       logger,
+	}
+	if len(resourceGroups) > 0 {
+		c.resourceGroups = make(map[string]bool, len(resourceGroups))
+		for _, group := range resourceGroups {
+			// Resource group names are case-insensitive
+			c.resourceGroups[strings.ToLower(group)] = true
+		}
+	}
+	return c, nil
+}
+
+// HealthCheck verifies access to the subscription
+func (c *Client) HealthCheck(ctx context.Context) error {
+	var subscription struct {
+		State string `json:"state"`
+	}
+	if err := c.get(ctx, c.subscriptionPath(""), "2022-12-01", &subscription); err != nil {
+		return fmt.Errorf("Azure health check failed: %w", err)
+	}
+	return nil
+}
+
+// subscriptionPath returns a path below the subscription
+func (c *Client) subscriptionPath(path string) string {
+	return "subscriptions/" + url.PathEscape(c.subscription) + path
+}
+
+// inScope reports whether a resource group is among the configured ones
+func (c *Client) inScope(resourceGroup string) bool {
+	return c.resourceGroups == nil || c.resourceGroups[strings.ToLower(resourceGroup)]
+}
+
+// get reads a resource or one page of a list
+func (c *Client) get(ctx context.Context, path, apiVersion string, out interface{}) error {
+	return c.do(ctx, http.MethodGet, c.apiURL(path, apiVersion), out)
+}
+
+// apiURL returns the URL of a path with its API version
+func (c *Client) apiURL(path, apiVersion string) string {
+	endpoint := c.endpoint.JoinPath(path)
+	endpoint.RawQuery = url.Values{"api-version": {apiVersion}}.Encode()
+	return endpoint.String()
+}
+
+// do sends a request and decodes the JSON response into out. Accepted
+// long-running operations have no body and leave out unchanged.
+func (c *Client) do(ctx context.Context, method, rawURL string, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, method, rawURL, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("request to Azure Resource Manager failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode == http.StatusNotFound {
+		return errNotFound
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("Azure Resource Manager returned %s: %s", resp.Status, apiErrorMessage(data))
+	}
+
+	if out == nil || len(bytes.TrimSpace(data)) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
+
+// apiErrorMessage extracts the message of a Resource Manager error response,
+// or a snippet of the body when it is not one
+func apiErrorMessage(data []byte) string {
+	var apiError struct {
+		Error struct {
+			Code    string `json:"code"`
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(data, &apiError); err == nil && apiError.Error.Message != "" {
+		return apiError.Error.Code + ": " + apiError.Error.Message
+	}
+
+	snippet := bytes.TrimSpace(data)
+	if len(snippet) > 512 {
+		snippet = snippet[:512]
+	}
+	return string(snippet)
+}
+
+// resourceGroupOf returns the resource group in a resource ID such as
+// /subscriptions/s/resourceGroups/web-rg/providers/Microsoft.Compute/virtualMachines/web-1
+func resourceGroupOf(resourceID string) string {
+	parts := strings.Split(resourceID, "/")
+	for i := 0; i+1 < len(parts); i++ {
+		if strings.EqualFold(parts[i], "resourceGroups") {
+			return parts[i+1]
+		}
+	}
+	return ""
+}
+
+// managedIdentity gets Resource Manager tokens from the instance metadata
+// service of an Azure VM, scale set or container with a managed identity
+type managedIdentity struct {
+	clientID string
+	client   *http.Client
+}
+
+// Token requests a new access token
+func (m *managedIdentity) Token() (*oauth2.Token, error) {
+	params := url.Values{
+		"api-version": {"2018-02-01"},
+		"resource":    {managementResource},
+	}
+	if m.clientID != "" {
+		params.Set("client_id", m.clientID)
+	}
+
+	req, err := http.NewRequest(http.MethodGet, imdsTokenURL+"?"+params.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create managed identity request: %w", err)
+	}
+	req.Header.Set("Metadata", "true")
+
+	resp, err := m.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("managed identity is not available: %w", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read managed identity token: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("managed identity returned %s: %s", resp.Status, bytes.TrimSpace(data))
+	}
+
+	var token struct {
+		AccessToken string      `json:"access_token"`
+		TokenType   string      `json:"token_type"`
+		ExpiresOn   json.Number `json:"expires_on"`
+	}
+	if err := json.Unmarshal(data, &token); err != nil {
+		return nil, fmt.Errorf("failed to decode managed identity token: %w", err)
+	}
+
+	expiresOn, err := token.ExpiresOn.Int64()
+	if err != nil {
+		return nil, fmt.Errorf("invalid managed identity token expiry %q", token.ExpiresOn)
+	}
+	return &oauth2.Token{
+		AccessToken: token.AccessToken,
+		TokenType:   token.TokenType,
+		Expiry:      time.Unix(expiresOn, 0),
+	}, nil
+}
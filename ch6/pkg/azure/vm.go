@@ -0,0 +1,291 @@
+package azure
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"aws-mcp-server/pkg/types"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// computeAPIVersion is the Microsoft.Compute API version used for VMs
+	computeAPIVersion = "2024-07-01"
+	// resourcesAPIVersion is the Microsoft.Resources API version used for resource groups
+	resourcesAPIVersion = "2021-04-01"
+)
+
+// virtualMachine is the part of a VM resource used here
+type virtualMachine struct {
+	ID         string            `json:"id"`
+	Name       string            `json:"name"`
+	Location   string            `json:"location"`
+	Tags       map[string]string `json:"tags"`
+	Zones      []string          `json:"zones"`
+	Properties struct {
+		VMID              string `json:"vmId"`
+		ProvisioningState string `json:"provisioningState"`
+		TimeCreated       string `json:"timeCreated"`
+		Priority          string `json:"priority"`
+		HardwareProfile   struct {
+			VMSize string `json:"vmSize"`
+		} `json:"hardwareProfile"`
+		StorageProfile struct {
+			OSDisk struct {
+				OSType string `json:"osType"`
+			} `json:"osDisk"`
+		} `json:"storageProfile"`
+		InstanceView *instanceView `json:"instanceView"`
+	} `json:"properties"`
+}
+
+// instanceView carries the power state of a VM
+type instanceView struct {
+	Statuses []struct {
+		Code          string `json:"code"`
+		DisplayStatus string `json:"displayStatus"`
+		Time          string `json:"time"`
+	} `json:"statuses"`
+}
+
+// vmList is one page of VMs
+type vmList struct {
+	Value    []virtualMachine `json:"value"`
+	NextLink string           `json:"nextLink"`
+}
+
+// ResourceGroup is an Azure resource group
+type ResourceGroup struct {
+	Name              string            `json:"name"`
+	Location          string            `json:"location"`
+	ProvisioningState string            `json:"provisioningState"`
+	Tags              map[string]string `json:"tags,omitempty"`
+}
+
+// ListInstances retrieves all VMs of the subscription with their power state,
+// limited to the configured resource groups if any
+func (c *Client) ListInstances(ctx context.Context) ([]types.CloudResource, error) {
+	start := time.Now()
+
+	vms, err := c.listVMs(ctx)
+	if err != nil {
+		c.logger.WithError(err).Error("Failed to list Azure VMs")
+		return nil, fmt.Errorf("failed to list virtual machines: %w", err)
+	}
+
+	resources := make([]types.CloudResource, 0, len(vms))
+	for _, vm := range vms {
+		resources = append(resources, convertVM(vm))
+	}
+
+	c.logger.WithFields(logrus.Fields{
+		"count":    len(resources),
+		"duration": time.Since(start),
+	}).Info("Retrieved Azure VMs")
+
+	return resources, nil
+}
+
+// GetInstance retrieves a VM by name, looking up its resource group
+func (c *Client) GetInstance(ctx context.Context, name string) (*types.CloudResource, error) {
+	vm, err := c.findVM(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	resource := convertVM(*vm)
+	return &resource, nil
+}
+
+// StartInstance starts a stopped or deallocated VM
+func (c *Client) StartInstance(ctx context.Context, name string) error {
+	return c.vmAction(ctx, name, "start")
+}
+
+// StopInstance deallocates a VM. A VM that is only powered off keeps its
+// compute reservation and is still billed, so stopping always deallocates.
+func (c *Client) StopInstance(ctx context.Context, name string) error {
+	return c.vmAction(ctx, name, "deallocate")
+}
+
+// ListResourceGroups retrieves the resource groups of the subscription,
+// limited to the configured ones if any
+func (c *Client) ListResourceGroups(ctx context.Context) ([]ResourceGroup, error) {
+	start := time.Now()
+
+	var page struct {
+		Value []struct {
+			Name       string            `json:"name"`
+			Location   string            `json:"location"`
+			Tags       map[string]string `json:"tags"`
+			Properties struct {
+				ProvisioningState string `json:"provisioningState"`
+			} `json:"properties"`
+		} `json:"value"`
+		NextLink string `json:"nextLink"`
+	}
+
+	var groups []ResourceGroup
+	next := c.apiURL(c.subscriptionPath("/resourcegroups"), resourcesAPIVersion)
+	for next != "" {
+		page.Value, page.NextLink = nil, ""
+		if err := c.do(ctx, http.MethodGet, next, &page); err != nil {
+			c.logger.WithError(err).Error("Failed to list Azure resource groups")
+			return nil, fmt.Errorf("failed to list resource groups: %w", err)
+		}
+		for _, group := range page.Value {
+			if c.inScope(group.Name) {
+				groups = append(groups, ResourceGroup{
+					Name:              group.Name,
+					Location:          group.Location,
+					ProvisioningState: group.Properties.ProvisioningState,
+					Tags:              group.Tags,
+				})
+			}
+		}
+		next = page.NextLink
+	}
+
+	sort.Slice(groups, func(i, j int) bool {
+		return strings.ToLower(groups[i].Name) < strings.ToLower(groups[j].Name)
+	})
+
+	c.logger.WithFields(logrus.Fields{
+		"count":    len(groups),
+		"duration": time.Since(start),
+	}).Info("Retrieved Azure resource groups")
+
+	return groups, nil
+}
+
+// vmAction runs a start or deallocate operation on a VM. Resource Manager
+// accepts the operation and runs it asynchronously; the power state shows its
+// progress.
+func (c *Client) vmAction(ctx context.Context, name, action string) error {
+	c.logger.WithField("vm", name).Infof("Requesting Azure VM %s", action)
+
+	vm, err := c.findVM(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	if err := c.do(ctx, http.MethodPost, c.apiURL(strings.TrimPrefix(vm.ID, "/")+"/"+action, computeAPIVersion), nil); err != nil {
+		c.logger.WithError(err).WithField("vm", name).Errorf("Failed to %s Azure VM", action)
+		return fmt.Errorf("failed to %s VM %s: %w", action, name, err)
+	}
+
+	c.logger.WithField("vm", name).Infof("Azure VM %s initiated", action)
+	return nil
+}
+
+// findVM returns the VM with a name. Names are unique per resource group, so
+// a name used in several groups is an error.
+func (c *Client) findVM(ctx context.Context, name string) (*virtualMachine, error) {
+	vms, err := c.listVMs(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up VM %s: %w", name, err)
+	}
+
+	var matches []virtualMachine
+	for _, vm := range vms {
+		if strings.EqualFold(vm.Name, name) {
+			matches = append(matches, vm)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("VM %s not found", name)
+	case 1:
+		return &matches[0], nil
+	default:
+		groups := make([]string, 0, len(matches))
+		for _, vm := range matches {
+			groups = append(groups, resourceGroupOf(vm.ID))
+		}
+		return nil, fmt.Errorf("VM name %s is used in resource groups %s; limit azure.resource_groups to one of them", name, strings.Join(groups, ", "))
+	}
+}
+
+// listVMs lists the VMs of the subscription in scope, ordered by resource
+// group and name. statusOnly includes the instance view with the power state.
+func (c *Client) listVMs(ctx context.Context) ([]virtualMachine, error) {
+	endpoint := c.endpoint.JoinPath(c.subscriptionPath("/providers/Microsoft.Compute/virtualMachines"))
+	endpoint.RawQuery = "api-version=" + computeAPIVersion + "&statusOnly=true"
+
+	var vms []virtualMachine
+	next := endpoint.String()
+	for next != "" {
+		var page vmList
+		if err := c.do(ctx, http.MethodGet, next, &page); err != nil {
+			if err == errNotFound {
+				return nil, fmt.Errorf("subscription %s not found", c.subscription)
+			}
+			return nil, err
+		}
+		for _, vm := range page.Value {
+			if c.inScope(resourceGroupOf(vm.ID)) {
+				vms = append(vms, vm)
+			}
+		}
+		next = page.NextLink
+	}
+
+	sort.Slice(vms, func(i, j int) bool {
+		gi, gj := strings.ToLower(resourceGroupOf(vms[i].ID)), strings.ToLower(resourceGroupOf(vms[j].ID))
+		if gi != gj {
+			return gi < gj
+		}
+		return vms[i].Name < vms[j].Name
+	})
+	return vms, nil
+}
+
+// convertVM converts a VM to our standard format. VMs are identified by name
+// and their state is the power state, e.g. running or deallocated.
+func convertVM(vm virtualMachine) types.CloudResource {
+	details := &types.AzureVMDetails{
+		InstanceType:      vm.Properties.HardwareProfile.VMSize,
+		ResourceGroup:     resourceGroupOf(vm.ID),
+		Location:          vm.Location,
+		ResourceID:        vm.ID,
+		VMID:              vm.Properties.VMID,
+		ProvisioningState: vm.Properties.ProvisioningState,
+		OSType:            vm.Properties.StorageProfile.OSDisk.OSType,
+		Zones:             vm.Zones,
+		Spot:              vm.Properties.Priority == "Spot",
+	}
+	if created, err := time.Parse(time.RFC3339, vm.Properties.TimeCreated); err == nil {
+		details.CreationTime = &created
+	}
+
+	state := "unknown"
+	if vm.Properties.InstanceView != nil {
+		for _, status := range vm.Properties.InstanceView.Statuses {
+			if power, ok := strings.CutPrefix(status.Code, "PowerState/"); ok {
+				state = power
+			}
+		}
+	}
+
+	tags := vm.Tags
+	if tags == nil {
+		tags = make(map[string]string)
+	}
+
+	return types.CloudResource{
+		ID:       vm.Name,
+		Provider: ProviderName,
+		Type:     "virtual-machine",
+		Region:   vm.Location,
+		State:    state,
+		Tags:     tags,
+		Details:  details,
+		LastSeen: time.Now(),
+	}
+}
@@ -0,0 +1,113 @@
+package azure
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"aws-mcp-server/internal/logging"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListInstances(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/subscriptions/sub-1/providers/Microsoft.Compute/virtualMachines", r.URL.Path)
+		assert.Equal(t, "true", r.URL.Query().Get("statusOnly"))
+
+		if r.URL.Query().Get("page") == "" {
+			w.Write([]byte(`{"value":[
+				{"id":"/subscriptions/sub-1/resourceGroups/batch-rg/providers/Microsoft.Compute/virtualMachines/batch-1","name":"batch-1",
+				 "location":"westeurope","properties":{"priority":"Spot","hardwareProfile":{"vmSize":"Standard_F8s_v2"},
+				 "instanceView":{"statuses":[{"code":"PowerState/deallocated"}]}}}],
+				"nextLink":"` + server.URL + `/subscriptions/sub-1/providers/Microsoft.Compute/virtualMachines?api-version=2024-07-01&statusOnly=true&page=2"}`))
+			return
+		}
+		w.Write([]byte(`{"value":[
+			{"id":"/subscriptions/sub-1/resourceGroups/WEB-RG/providers/Microsoft.Compute/virtualMachines/web-1","name":"web-1",
+			 "location":"westeurope","tags":{"Environment":"prod"},"zones":["1"],
+			 "properties":{"vmId":"abc","provisioningState":"Succeeded","timeCreated":"2025-06-01T10:00:00Z",
+			  "hardwareProfile":{"vmSize":"Standard_D2s_v5"},"storageProfile":{"osDisk":{"osType":"Linux"}},
+			  "instanceView":{"statuses":[{"code":"ProvisioningState/succeeded"},{"code":"PowerState/running"}]}}}]}`))
+	}))
+	defer server.Close()
+
+	client, err := newClient("sub-1", nil, server.URL+"/", server.Client(), logging.NewLogger("error", "text"))
+	require.NoError(t, err)
+
+	vms, err := client.ListInstances(context.Background())
+	require.NoError(t, err)
+	require.Len(t, vms, 2)
+
+	assert.Equal(t, "batch-1", vms[0].ID, "ordered by resource group")
+	assert.Equal(t, "deallocated", vms[0].State)
+	assert.True(t, vms[0].AzureVM().Spot)
+
+	web := vms[1]
+	assert.Equal(t, "azure", web.Provider)
+	assert.Equal(t, "virtual-machine", web.Type)
+	assert.Equal(t, "westeurope", web.Region)
+	assert.Equal(t, "running", web.State)
+	assert.Equal(t, "prod", web.Tags["Environment"])
+	assert.Equal(t, "Standard_D2s_v5", web.InstanceType())
+	assert.Equal(t, "WEB-RG", web.AzureVM().ResourceGroup)
+	assert.Equal(t, "Linux", web.AzureVM().OSType)
+
+	// Configured resource groups limit what is listed, ignoring case
+	scoped, err := newClient("sub-1", []string{"web-rg"}, server.URL+"/", server.Client(), logging.NewLogger("error", "text"))
+	require.NoError(t, err)
+	vms, err = scoped.ListInstances(context.Background())
+	require.NoError(t, err)
+	require.Len(t, vms, 1)
+	assert.Equal(t, "web-1", vms[0].ID)
+}
+
+func TestVMActions(t *testing.T) {
+	var actions []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			actions = append(actions, r.URL.Path)
+			w.WriteHeader(http.StatusAccepted)
+			return
+		}
+		w.Write([]byte(`{"value":[
+			{"id":"/subscriptions/sub-1/resourceGroups/web-rg/providers/Microsoft.Compute/virtualMachines/web-1","name":"web-1"},
+			{"id":"/subscriptions/sub-1/resourceGroups/a-rg/providers/Microsoft.Compute/virtualMachines/api","name":"api"},
+			{"id":"/subscriptions/sub-1/resourceGroups/b-rg/providers/Microsoft.Compute/virtualMachines/api","name":"api"}]}`))
+	}))
+	defer server.Close()
+
+	client, err := newClient("sub-1", nil, server.URL+"/", server.Client(), logging.NewLogger("error", "text"))
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	require.NoError(t, client.StopInstance(ctx, "web-1"))
+	require.NoError(t, client.StartInstance(ctx, "web-1"))
+	assert.Equal(t, []string{
+		"/subscriptions/sub-1/resourceGroups/web-rg/providers/Microsoft.Compute/virtualMachines/web-1/deallocate",
+		"/subscriptions/sub-1/resourceGroups/web-rg/providers/Microsoft.Compute/virtualMachines/web-1/start",
+	}, actions)
+
+	_, err = client.GetInstance(ctx, "missing")
+	assert.ErrorContains(t, err, "VM missing not found")
+
+	err = client.StopInstance(ctx, "api")
+	assert.ErrorContains(t, err, "a-rg, b-rg")
+}
+
+func TestAPIErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte(`{"error":{"code":"AuthorizationFailed","message":"The client does not have authorization to perform action"}}`))
+	}))
+	defer server.Close()
+
+	client, err := newClient("sub-1", nil, server.URL+"/", server.Client(), logging.NewLogger("error", "text"))
+	require.NoError(t, err)
+
+	_, err = client.ListResourceGroups(context.Background())
+	assert.ErrorContains(t, err, "403 Forbidden: AuthorizationFailed: The client does not have authorization")
+}
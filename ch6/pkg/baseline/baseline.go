@@ -0,0 +1,289 @@
+package baseline
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"aws-mcp-server/pkg/jsonfile"
+)
+
+const (
+	// HoursPerWeek is the number of seasonal buckets in a baseline, one per
+	// hour of the week starting Monday 00:00 UTC
+	HoursPerWeek = 7 * 24
+	// MinSamples is how many samples a baseline needs before points are scored
+	MinSamples = 30
+	// MinHourSamples is how many samples an hour of the week needs before
+	// points in that hour are scored against it instead of the overall baseline
+	MinHourSamples = 4
+)
+
+// Point is one metric value at a time
+type Point struct {
+	Time  time.Time
+	Value float64
+}
+
+// Bucket keeps a running mean and variance of the samples it has seen
+type Bucket struct {
+	Count int     `json:"count"`
+	Mean  float64 `json:"mean"`
+	M2    float64 `json:"m2"`
+}
+
+// add folds a sample into the bucket using Welford's algorithm
+func (b *Bucket) add(value float64) {
+	b.Count++
+	delta := value - b.Mean
+	b.Mean += delta / float64(b.Count)
+	b.M2 += delta * (value - b.Mean)
+}
+
+// Stddev returns the sample standard deviation of the bucket
+func (b Bucket) Stddev() float64 {
+	if b.Count < 2 {
+		return 0
+	}
+	return math.Sqrt(b.M2 / float64(b.Count-1))
+}
+
+// Baseline is the learned behavior of one metric of one resource: overall and
+// for each hour of the week, so daily and weekly cycles are not anomalies
+type Baseline struct {
+	Resource  string    `json:"resource"`
+	Metric    string    `json:"metric"`
+	Overall   Bucket    `json:"overall"`
+	Hours     []Bucket  `json:"hours"`
+	FirstSeen time.Time `json:"firstSeen"`
+	LastSeen  time.Time `json:"lastSeen"`
+}
+
+// Trained reports whether the baseline has enough samples to score points
+func (b Baseline) Trained() bool {
+	return b.Overall.Count >= MinSamples
+}
+
+// Expected returns the bucket a point at t is compared with: its hour of the
+// week once that hour has enough samples, otherwise the overall baseline
+func (b Baseline) Expected(t time.Time) (Bucket, bool) {
+	if len(b.Hours) == HoursPerWeek {
+		if hour := b.Hours[HourOfWeek(t)]; hour.Count >= MinHourSamples {
+			return hour, true
+		}
+	}
+	return b.Overall, false
+}
+
+// Score is how far a point is from its baseline
+type Score struct {
+	Expected float64 `json:"expected"`
+	Stddev   float64 `json:"stddev"`
+	// Z is the number of standard deviations the value is from Expected
+	Z float64 `json:"z"`
+	// Seasonal is set when the point was compared with its hour of the week
+	Seasonal bool `json:"seasonal"`
+}
+
+// Score compares a point with the baseline. The standard deviation is floored
+// at 1% of the expected value so flat metrics do not turn noise into anomalies.
+func (b Baseline) Score(p Point) Score {
+	bucket, seasonal := b.Expected(p.Time)
+	stddev := math.Max(bucket.Stddev(), math.Max(math.Abs(bucket.Mean)*0.01, 1e-9))
+	return Score{
+		Expected: bucket.Mean,
+		Stddev:   bucket.Stddev(),
+		Z:        (p.Value - bucket.Mean) / stddev,
+		Seasonal: seasonal,
+	}
+}
+
+// Anomaly is a point that is further from its baseline than the threshold
+type Anomaly struct {
+	Point
+	Score
+}
+
+// HourOfWeek returns the seasonal bucket of t
+func HourOfWeek(t time.Time) int {
+	t = t.UTC()
+	return (int(t.Weekday())+6)%7*24 + t.Hour()
+}
+
+// Store holds the baselines of every resource and metric. With a path, the
+// store is saved as JSON after every change and reloaded on startup, so
+// detection does not start from scratch after a restart.
+type Store struct {
+	mu        sync.Mutex
+	path      string
+	baselines map[string]*Baseline
+}
+
+// NewStore creates an empty in-memory store
+func NewStore() *Store {
+	return &Store{baselines: make(map[string]*Baseline)}
+}
+
+// Open creates a store saved to path. With an empty path baselines are only
+// kept in memory.
+func Open(path string) (*Store, error) {
+	s := NewStore()
+	s.path = path
+	if path == "" {
+		return s, nil
+	}
+
+	var baselines []*Baseline
+	if err := jsonfile.Load(path, "baselines", &baselines); err != nil {
+		return s, err
+	}
+	for _, b := range baselines {
+		if len(b.Hours) != HoursPerWeek {
+			b.Hours = make([]Bucket, HoursPerWeek)
+		}
+		s.baselines[key(b.Resource, b.Metric)] = b
+	}
+	return s, nil
+}
+
+// Detect scores points against the baseline learned so far and returns those
+// at least threshold standard deviations away. It returns the baseline as it
+// was before this call and whether it was trained; untrained baselines flag nothing.
+func (s *Store) Detect(resource, metric string, points []Point, threshold float64) ([]Anomaly, Baseline, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	b, exists := s.baselines[key(resource, metric)]
+	if !exists {
+		return nil, Baseline{Resource: resource, Metric: metric}, false
+	}
+	snapshot := b.copy()
+	if !b.Trained() {
+		return nil, snapshot, false
+	}
+
+	var anomalies []Anomaly
+	for _, p := range points {
+		if score := b.Score(p); math.Abs(score.Z) >= threshold {
+			anomalies = append(anomalies, Anomaly{Point: p, Score: score})
+		}
+	}
+	return anomalies, snapshot, true
+}
+
+// Learn adds points newer than the last learned one to the baseline, so
+// overlapping windows are not counted twice, and returns how many were added
+func (s *Store) Learn(resource, metric string, points []Point) (int, error) {
+	points = append([]Point(nil), points...)
+	sort.Slice(points, func(i, j int) bool {
+		return points[i].Time.Before(points[j].Time)
+	})
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	b, exists := s.baselines[key(resource, metric)]
+	if !exists {
+		b = &Baseline{Resource: resource, Metric: metric, Hours: make([]Bucket, HoursPerWeek)}
+	}
+
+	learned := 0
+	for _, p := range points {
+		if !p.Time.After(b.LastSeen) || math.IsNaN(p.Value) || math.IsInf(p.Value, 0) {
+			continue
+		}
+		b.Overall.add(p.Value)
+		b.Hours[HourOfWeek(p.Time)].add(p.Value)
+		if b.FirstSeen.IsZero() {
+			b.FirstSeen = p.Time
+		}
+		b.LastSeen = p.Time
+		learned++
+	}
+	if learned == 0 {
+		return 0, nil
+	}
+
+	s.baselines[key(resource, metric)] = b
+	return learned, s.save()
+}
+
+// Find returns the baselines of a resource ordered by metric. The resource is
+// either the exact resource name or one of its dimension values, e.g. i-0abc123
+// for InstanceId=i-0abc123.
+func (s *Store) Find(resource, metric string) []Baseline {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var found []Baseline
+	for _, b := range s.baselines {
+		if matches(b, resource, metric) {
+			found = append(found, b.copy())
+		}
+	}
+	sort.Slice(found, func(i, j int) bool {
+		if found[i].Resource != found[j].Resource {
+			return found[i].Resource < found[j].Resource
+		}
+		return found[i].Metric < found[j].Metric
+	})
+	return found
+}
+
+// Reset discards the baselines Find would return, for example after a bad
+// training period, and returns them
+func (s *Store) Reset(resource, metric string) ([]Baseline, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var removed []Baseline
+	for k, b := range s.baselines {
+		if matches(b, resource, metric) {
+			removed = append(removed, b.copy())
+			delete(s.baselines, k)
+		}
+	}
+	if len(removed) == 0 {
+		return nil, nil
+	}
+	return removed, s.save()
+}
+
+// matches reports whether a baseline belongs to resource and, when set, metric
+func matches(b *Baseline, resource, metric string) bool {
+	if metric != "" && b.Metric != metric {
+		return false
+	}
+	return b.Resource == resource || strings.Contains(","+b.Resource+",", "="+resource+",")
+}
+
+// copy returns a copy of the baseline that does not share its hour buckets
+func (b *Baseline) copy() Baseline {
+	c := *b
+	c.Hours = append([]Bucket(nil), b.Hours...)
+	return c
+}
+
+// key identifies the baseline of one metric of one resource
+func key(resource, metric string) string {
+	return resource + "\x00" + metric
+}
+
+// save writes all baselines to the store file, replacing it atomically
+func (s *Store) save() error {
+	if s.path == "" {
+		return nil
+	}
+
+	baselines := make([]*Baseline, 0, len(s.baselines))
+	for _, b := range s.baselines {
+		baselines = append(baselines, b)
+	}
+	sort.Slice(baselines, func(i, j int) bool {
+		return key(baselines[i].Resource, baselines[i].Metric) < key(baselines[j].Resource, baselines[j].Metric)
+	})
+
+	return jsonfile.Save(s.path, "baselines", baselines)
+}
@@ -0,0 +1,104 @@
+package baseline
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// weeklyPoints returns four weeks of hourly points that are busy during
+// weekday office hours and quiet otherwise
+func weeklyPoints(start time.Time) []Point {
+	var points []Point
+	for t := start; t.Before(start.Add(28 * 24 * time.Hour)); t = t.Add(time.Hour) {
+		value := 10.0 + float64(t.Hour()%3)
+		if t.Weekday() != time.Saturday && t.Weekday() != time.Sunday && t.Hour() >= 9 && t.Hour() < 17 {
+			value = 70.0 + float64(t.Hour()%3)
+		}
+		points = append(points, Point{Time: t, Value: value})
+	}
+	return points
+}
+
+func TestHourOfWeek(t *testing.T) {
+	assert.Equal(t, 0, HourOfWeek(time.Date(2025, 6, 2, 0, 30, 0, 0, time.UTC)))
+	assert.Equal(t, 167, HourOfWeek(time.Date(2025, 6, 8, 23, 0, 0, 0, time.UTC)))
+	assert.Equal(t, 24+1, HourOfWeek(time.Date(2025, 6, 3, 3, 0, 0, 0, time.FixedZone("UTC+2", 2*3600))))
+}
+
+func TestStoreDetectsSeasonalAnomalies(t *testing.T) {
+	s := NewStore()
+	start := time.Date(2025, 6, 2, 0, 0, 0, 0, time.UTC)
+	monday := start.Add(28 * 24 * time.Hour)
+
+	_, _, trained := s.Detect("InstanceId=i-1", "cpu", []Point{{Time: monday, Value: 99}}, 3)
+	assert.False(t, trained)
+
+	learned, err := s.Learn("InstanceId=i-1", "cpu", weeklyPoints(start))
+	require.NoError(t, err)
+	assert.Equal(t, 28*24, learned)
+
+	learned, err = s.Learn("InstanceId=i-1", "cpu", weeklyPoints(start)[:48])
+	require.NoError(t, err)
+	assert.Zero(t, learned, "points already learned are skipped")
+
+	points := []Point{
+		// Busy office hours are normal on a Monday
+		{Time: monday.Add(10 * time.Hour), Value: 71},
+		// but not at 3am
+		{Time: monday.Add(3 * time.Hour), Value: 71},
+	}
+	anomalies, b, trained := s.Detect("InstanceId=i-1", "cpu", points, 3)
+	require.True(t, trained)
+	assert.Equal(t, 28*24, b.Overall.Count)
+	require.Len(t, anomalies, 1)
+	assert.Equal(t, monday.Add(3*time.Hour), anomalies[0].Time)
+	assert.True(t, anomalies[0].Seasonal)
+	assert.InDelta(t, 10, anomalies[0].Expected, 0.01)
+}
+
+func TestStoreFindAndReset(t *testing.T) {
+	s := NewStore()
+	now := time.Date(2025, 6, 2, 0, 0, 0, 0, time.UTC)
+	for _, resource := range []string{"InstanceId=i-1", "AutoScalingGroupName=web,InstanceId=i-2"} {
+		for _, metric := range []string{"cpu", "network"} {
+			_, err := s.Learn(resource, metric, []Point{{Time: now, Value: 1}})
+			require.NoError(t, err)
+		}
+	}
+
+	assert.Len(t, s.Find("i-2", ""), 2)
+	assert.Len(t, s.Find("InstanceId=i-1", "cpu"), 1)
+	assert.Empty(t, s.Find("i-", ""))
+
+	removed, err := s.Reset("i-2", "network")
+	require.NoError(t, err)
+	require.Len(t, removed, 1)
+	assert.Equal(t, "network", removed[0].Metric)
+	assert.Len(t, s.Find("i-2", ""), 1)
+}
+
+func TestStorePersists(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state", "baselines.json")
+	start := time.Date(2025, 6, 2, 0, 0, 0, 0, time.UTC)
+
+	s, err := Open(path)
+	require.NoError(t, err)
+	_, err = s.Learn("InstanceId=i-1", "cpu", weeklyPoints(start))
+	require.NoError(t, err)
+	_, err = s.Learn("InstanceId=i-2", "cpu", weeklyPoints(start))
+	require.NoError(t, err)
+	_, err = s.Reset("i-2", "")
+	require.NoError(t, err)
+
+	reopened, err := Open(path)
+	require.NoError(t, err)
+	found := reopened.Find("i-1", "cpu")
+	require.Len(t, found, 1)
+	assert.True(t, found[0].Trained())
+	assert.Equal(t, start.Add(28*24*time.Hour-time.Hour), found[0].LastSeen.UTC())
+	assert.Empty(t, reopened.Find("i-2", ""))
+}
@@ -0,0 +1,88 @@
+package bench
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/smithy-go/middleware"
+
+	"aws-mcp-server/internal/config"
+	"aws-mcp-server/pkg/fake"
+)
+
+// Backend answers the AWS API calls of a benchmark server with a fake fleet,
+// so the server runs its full request path, including input validation and
+// hooks, without credentials or network. It counts the calls it answers.
+type Backend struct {
+	// Latency is added to every call to stand in for the round trip to AWS
+	Latency time.Duration
+
+	fleet       *fake.Fleet
+	calls       atomic.Int64
+	inFlight    atomic.Int64
+	maxInFlight atomic.Int64
+}
+
+// NewBackend creates a backend whose fleet has n instances in Region. The
+// fleet has a fixed seed, so every run reads the same inventory.
+func NewBackend(n int) *Backend {
+	return &Backend{fleet: fake.New(config.FakeConfig{Instances: n, Seed: 1}, Region)}
+}
+
+// InstanceIDs returns the IDs of the fleet's instances
+func (b *Backend) InstanceIDs() []string {
+	return b.fleet.InstanceIDs()
+}
+
+// Calls returns how many AWS API calls the backend answered
+func (b *Backend) Calls() int64 {
+	return b.calls.Load()
+}
+
+// MaxInFlight returns the most calls the backend was answering at once
+func (b *Backend) MaxInFlight() int64 {
+	return b.maxInFlight.Load()
+}
+
+// Config returns an AWS configuration whose service clients call the backend.
+// Retries are disabled so failing operations cost one call.
+func (b *Backend) Config() aws.Config {
+	cfg := b.fleet.Config()
+	cfg.APIOptions = append(cfg.APIOptions, func(stack *middleware.Stack) error {
+		return stack.Serialize.Add(b, middleware.Before)
+	})
+	cfg.Retryer = func() aws.Retryer {
+		return aws.NopRetryer{}
+	}
+	return cfg
+}
+
+// ID names the middleware in the SDK's middleware stacks
+func (b *Backend) ID() string {
+	return "BenchBackend"
+}
+
+// HandleSerialize counts a call and holds it for Latency before the fleet
+// answers it
+func (b *Backend) HandleSerialize(ctx context.Context, in middleware.SerializeInput, next middleware.SerializeHandler) (middleware.SerializeOutput, middleware.Metadata, error) {
+	b.calls.Add(1)
+	inFlight := b.inFlight.Add(1)
+	defer b.inFlight.Add(-1)
+	for {
+		peak := b.maxInFlight.Load()
+		if inFlight <= peak || b.maxInFlight.CompareAndSwap(peak, inFlight) {
+			break
+		}
+	}
+
+	if b.Latency > 0 {
+		select {
+		case <-time.After(b.Latency):
+		case <-ctx.Done():
+			return middleware.SerializeOutput{}, middleware.Metadata{}, ctx.Err()
+		}
+	}
+	return next.HandleSerialize(ctx, in)
+}
@@ -0,0 +1,208 @@
+// Package bench drives an MCP server with synthetic tool and resource traffic
+// at a configurable concurrency and reports throughput and latency
+// percentiles, so regressions in the message loop and the handlers show up
+// as numbers. The server runs against the generated fleet of package fake,
+// so runs are repeatable and need no AWS account.
+package bench
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Handler handles one JSON-RPC message and returns the encoded response, like
+// the Server.HandleMessage of the MCP server
+type Handler func(ctx context.Context, message []byte) ([]byte, error)
+
+// Request is one kind of synthetic traffic
+type Request struct {
+	// Name labels the request in the report, e.g. read instances
+	Name string
+	// Method is the JSON-RPC method, e.g. resources/read
+	Method string
+	// Params returns the parameters of the seq-th request of this kind, so
+	// requests can vary, e.g. the instance they read
+	Params func(seq int) interface{}
+	// Weight is the share of the traffic relative to the other requests; a
+	// weight below one counts as one
+	Weight int
+}
+
+// Options configure a run. It ends after Requests requests or, when that is
+// zero, after Duration.
+type Options struct {
+	Concurrency int
+	Requests    int
+	Duration    time.Duration
+	Mix         []Request
+}
+
+// DefaultMix is the traffic of an assistant working an incident: listing and
+// reading instances, auditing tags and listing the server's tools and
+// resources, which exercise the message loop without AWS calls
+func DefaultMix(instanceIDs []string) []Request {
+	return []Request{
+		{Name: "list tools", Method: "tools/list", Params: noParams, Weight: 1},
+		{Name: "list resources", Method: "resources/list", Params: noParams, Weight: 1},
+		{Name: "read instances", Method: "resources/read", Weight: 3, Params: func(int) interface{} {
+			return map[string]interface{}{"uri": "aws://ec2/instances"}
+		}},
+		{Name: "read instance", Method: "resources/read", Weight: 4, Params: func(seq int) interface{} {
+			id := "i-00000000000000000"
+			if len(instanceIDs) > 0 {
+				id = instanceIDs[seq%len(instanceIDs)]
+			}
+			return map[string]interface{}{"uri": "aws://ec2/instances/" + id}
+		}},
+		{Name: "audit tags", Method: "tools/call", Weight: 1, Params: func(int) interface{} {
+			return map[string]interface{}{"name": "audit-tags", "arguments": map[string]interface{}{}}
+		}},
+	}
+}
+
+// noParams is the parameters of requests without any
+func noParams(int) interface{} {
+	return map[string]interface{}{}
+}
+
+// Run initializes a session on the handler and then sends the traffic mix
+// from Concurrency workers. Requests the server answers with an error count
+// as failed but do not stop the run; a handler error does.
+func Run(ctx context.Context, handler Handler, opts Options) (*Report, error) {
+	if opts.Concurrency < 1 {
+		opts.Concurrency = 1
+	}
+	if opts.Requests <= 0 && opts.Duration <= 0 {
+		return nil, errors.New("either a number of requests or a duration is required")
+	}
+	if len(opts.Mix) == 0 {
+		return nil, errors.New("the traffic mix is empty")
+	}
+
+	initialize, err := encodeRequest(0, "initialize", map[string]interface{}{
+		"protocolVersion": "2025-03-26",
+		"clientInfo":      map[string]interface{}{"name": "aiops-bench", "version": "1.0.0"},
+		"capabilities":    map[string]interface{}{},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if _, err := handler(ctx, initialize); err != nil {
+		return nil, fmt.Errorf("failed to initialize: %w", err)
+	}
+
+	// Requests are picked by weight in a fixed rotation, so the mix of two
+	// runs with the same options is the same
+	var schedule []int
+	for i, request := range opts.Mix {
+		for range max(request.Weight, 1) {
+			schedule = append(schedule, i)
+		}
+	}
+
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	if opts.Duration > 0 {
+		runCtx, cancel = context.WithTimeout(runCtx, opts.Duration)
+		defer cancel()
+	}
+
+	samples := make([][]sample, opts.Concurrency)
+	var next atomic.Int64
+	var failure error
+	var failureOnce sync.Once
+	var wg sync.WaitGroup
+
+	started := time.Now()
+	for worker := range opts.Concurrency {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for runCtx.Err() == nil {
+				seq := int(next.Add(1))
+				if opts.Requests > 0 && seq > opts.Requests {
+					return
+				}
+
+				kind := schedule[(seq-1)%len(schedule)]
+				request := opts.Mix[kind]
+				message, err := encodeRequest(seq, request.Method, request.Params(seq))
+				if err != nil {
+					failureOnce.Do(func() { failure = err })
+					cancel()
+					return
+				}
+
+				sent := time.Now()
+				response, err := handler(runCtx, message)
+				latency := time.Since(sent)
+				if err != nil {
+					// Requests cut off by the end of a timed run are not samples
+					if runCtx.Err() == nil {
+						failureOnce.Do(func() { failure = fmt.Errorf("%s failed: %w", request.Name, err) })
+						cancel()
+					}
+					return
+				}
+				samples[worker] = append(samples[worker], sample{kind: kind, latency: latency, failed: failed(response)})
+			}
+		}()
+	}
+	wg.Wait()
+	elapsed := time.Since(started)
+
+	if failure != nil {
+		return nil, failure
+	}
+	return newReport(opts, samples, elapsed), nil
+}
+
+// encodeRequest encodes a JSON-RPC request
+func encodeRequest(id int, method string, params interface{}) ([]byte, error) {
+	message, err := json.Marshal(map[string]interface{}{
+		"jsonrpc": "2.0",
+		"id":      id,
+		"method":  method,
+		"params":  params,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode %s request: %w", method, err)
+	}
+	return message, nil
+}
+
+// failed reports whether a response is a JSON-RPC error or a tool result the
+// server marked as failed
+func failed(response []byte) bool {
+	var decoded struct {
+		Error  json.RawMessage `json:"error"`
+		Result struct {
+			IsError bool `json:"isError"`
+			Content []struct {
+				Text string `json:"text"`
+			} `json:"content"`
+		} `json:"result"`
+	}
+	if err := json.Unmarshal(response, &decoded); err != nil {
+		return true
+	}
+	if len(decoded.Error) > 0 || decoded.Result.IsError {
+		return true
+	}
+
+	// Tool handlers report failures in their JSON payload
+	for _, content := range decoded.Result.Content {
+		var payload struct {
+			Success *bool `json:"success"`
+		}
+		if json.Unmarshal([]byte(content.Text), &payload) == nil && payload.Success != nil && !*payload.Success {
+			return true
+		}
+	}
+	return false
+}
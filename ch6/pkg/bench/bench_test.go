@@ -0,0 +1,164 @@
+package bench
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"aws-mcp-server/internal/logging"
+	"aws-mcp-server/pkg/aws"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPercentile(t *testing.T) {
+	latencies := make([]time.Duration, 100)
+	for i := range latencies {
+		latencies[i] = time.Duration(i+1) * time.Millisecond
+	}
+
+	assert.Equal(t, 50*time.Millisecond, percentile(latencies, 50))
+	assert.Equal(t, 99*time.Millisecond, percentile(latencies, 99))
+	assert.Equal(t, time.Millisecond, percentile(latencies[:1], 99))
+
+	stats := newStats("read", []time.Duration{3 * time.Millisecond, time.Millisecond, 2 * time.Millisecond}, 1)
+	assert.Equal(t, 2*time.Millisecond, stats.P50)
+	assert.Equal(t, 2*time.Millisecond, stats.Mean)
+	assert.Equal(t, 3*time.Millisecond, stats.Max)
+	assert.Equal(t, 1, stats.Failed)
+}
+
+func TestFailed(t *testing.T) {
+	assert.False(t, failed([]byte(`{"jsonrpc":"2.0","id":1,"result":{"tools":[]}}`)))
+	assert.True(t, failed([]byte(`{"jsonrpc":"2.0","id":1,"error":{"code":-32601,"message":"not found"}}`)))
+	assert.True(t, failed([]byte(`{"jsonrpc":"2.0","id":1,"result":{"isError":true,"content":[]}}`)))
+	assert.True(t, failed([]byte(`{"jsonrpc":"2.0","id":1,"result":{"content":[{"type":"text","text":"{\"success\": false}"}]}}`)))
+	assert.False(t, failed([]byte(`{"jsonrpc":"2.0","id":1,"result":{"content":[{"type":"text","text":"{\"success\": true}"}]}}`)))
+	assert.True(t, failed([]byte(`not json`)))
+}
+
+func TestRunSendsTheMix(t *testing.T) {
+	var calls atomic.Int64
+	handler := func(ctx context.Context, message []byte) ([]byte, error) {
+		calls.Add(1)
+		if bytes.Contains(message, []byte("broken")) {
+			return []byte(`{"jsonrpc":"2.0","id":1,"error":{"code":-32603,"message":"broken"}}`), nil
+		}
+		return []byte(`{"jsonrpc":"2.0","id":1,"result":{}}`), nil
+	}
+
+	report, err := Run(context.Background(), handler, Options{
+		Concurrency: 4,
+		Requests:    40,
+		Mix: []Request{
+			{Name: "ok", Method: "tools/list", Params: noParams, Weight: 3},
+			{Name: "broken", Method: "broken", Params: noParams},
+		},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, int64(41), calls.Load(), "the session is initialized first")
+	assert.Equal(t, 40, report.Total.Requests)
+	assert.Equal(t, 10, report.Total.Failed)
+	require.Len(t, report.ByRequest, 2)
+	assert.Equal(t, 30, report.ByRequest[0].Requests)
+	assert.Equal(t, 10, report.ByRequest[1].Failed)
+	assert.Positive(t, report.Throughput)
+
+	var out bytes.Buffer
+	require.NoError(t, report.Write(&out))
+	assert.Contains(t, out.String(), "40 requests from 4 workers")
+}
+
+func TestRunStopsOnHandlerError(t *testing.T) {
+	handler := func(ctx context.Context, message []byte) ([]byte, error) {
+		if bytes.Contains(message, []byte("initialize")) {
+			return nil, nil
+		}
+		return nil, errors.New("encoding failed")
+	}
+
+	_, err := Run(context.Background(), handler, Options{Concurrency: 2, Requests: 10,
+		Mix: []Request{{Name: "list", Method: "tools/list", Params: noParams}}})
+	assert.ErrorContains(t, err, "list failed: encoding failed")
+
+	_, err = Run(context.Background(), handler, Options{Concurrency: 2})
+	assert.Error(t, err)
+}
+
+func TestBackendCountsCalls(t *testing.T) {
+	backend := NewBackend(12)
+	client := aws.NewClientFromConfig(backend.Config(), logging.NewLogger("error", "text"))
+
+	instances, err := client.ListEC2Instances(context.Background())
+	require.NoError(t, err)
+	require.Len(t, instances, 12)
+	require.Len(t, backend.InstanceIDs(), 12)
+
+	instance, err := client.GetEC2Instance(context.Background(), backend.InstanceIDs()[5])
+	require.NoError(t, err)
+	assert.Equal(t, backend.InstanceIDs()[5], instance.ID)
+
+	_, err = client.ListECRRepositories(context.Background())
+	assert.ErrorContains(t, err, "UnsupportedOperation")
+	assert.Equal(t, int64(3), backend.Calls(), "failed calls are not retried")
+	assert.Equal(t, int64(1), backend.MaxInFlight())
+}
+
+func TestConcurrencyLimiterCapsCalls(t *testing.T) {
+	backend := NewBackend(4)
+	backend.Latency = 20 * time.Millisecond
+	client := aws.NewClientFromConfig(backend.Config(), logging.NewLogger("error", "text"))
+	client.AddHook(aws.NewConcurrencyLimiter(8, map[string]int{"ec2": 2}))
+
+	var wg sync.WaitGroup
+	for i := range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := client.GetEC2Instance(context.Background(), backend.InstanceIDs()[i%4])
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int64(8), backend.Calls())
+	assert.Equal(t, int64(2), backend.MaxInFlight(), "EC2 calls wait for one of two slots")
+
+	// Calls give up waiting when their context is done, without leaking slots
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Millisecond)
+	defer cancel()
+	for range 3 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			client.GetEC2Instance(ctx, backend.InstanceIDs()[0])
+		}()
+	}
+	wg.Wait()
+	_, err := client.GetEC2Instance(context.Background(), backend.InstanceIDs()[0])
+	assert.NoError(t, err)
+
+	assert.Nil(t, aws.NewConcurrencyLimiter(0, map[string]int{"ec2": 0}), "nothing to limit")
+}
+
+func TestRunAgainstServer(t *testing.T) {
+	backend := NewBackend(20)
+	server := NewServer(Config(), backend, logging.NewLogger("error", "text"))
+
+	report, err := Run(context.Background(), server.HandleMessage, Options{
+		Concurrency: 4,
+		Requests:    50,
+		Mix:         DefaultMix(backend.InstanceIDs()),
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, 50, report.Total.Requests)
+	assert.Zero(t, report.Total.Failed, "the default mix only uses calls the fake backend serves")
+	assert.Len(t, report.ByRequest, 5)
+	assert.Positive(t, backend.Calls())
+}
@@ -0,0 +1,122 @@
+package bench
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"text/tabwriter"
+	"time"
+)
+
+// sample is the outcome of one request
+type sample struct {
+	kind    int
+	latency time.Duration
+	failed  bool
+}
+
+// Stats are the latency percentiles of a set of requests
+type Stats struct {
+	Name     string        `json:"name"`
+	Requests int           `json:"requests"`
+	Failed   int           `json:"failed"`
+	Mean     time.Duration `json:"mean"`
+	P50      time.Duration `json:"p50"`
+	P90      time.Duration `json:"p90"`
+	P99      time.Duration `json:"p99"`
+	Max      time.Duration `json:"max"`
+}
+
+// Report is the outcome of a run. Throughput is in requests per second.
+type Report struct {
+	Concurrency int           `json:"concurrency"`
+	Elapsed     time.Duration `json:"elapsed"`
+	Throughput  float64       `json:"throughput"`
+	Total       Stats         `json:"total"`
+	ByRequest   []Stats       `json:"byRequest"`
+}
+
+// newReport aggregates the samples of all workers
+func newReport(opts Options, samples [][]sample, elapsed time.Duration) *Report {
+	all := make([]time.Duration, 0)
+	byKind := make([][]time.Duration, len(opts.Mix))
+	failedByKind := make([]int, len(opts.Mix))
+	failedTotal := 0
+	for _, worker := range samples {
+		for _, s := range worker {
+			all = append(all, s.latency)
+			byKind[s.kind] = append(byKind[s.kind], s.latency)
+			if s.failed {
+				failedByKind[s.kind]++
+				failedTotal++
+			}
+		}
+	}
+
+	report := &Report{
+		Concurrency: opts.Concurrency,
+		Elapsed:     elapsed,
+		Total:       newStats("total", all, failedTotal),
+	}
+	if elapsed > 0 {
+		report.Throughput = float64(len(all)) / elapsed.Seconds()
+	}
+	for i, request := range opts.Mix {
+		if len(byKind[i]) > 0 {
+			report.ByRequest = append(report.ByRequest, newStats(request.Name, byKind[i], failedByKind[i]))
+		}
+	}
+	return report
+}
+
+// newStats computes the percentiles of latencies, which it sorts
+func newStats(name string, latencies []time.Duration, failed int) Stats {
+	stats := Stats{Name: name, Requests: len(latencies), Failed: failed}
+	if len(latencies) == 0 {
+		return stats
+	}
+
+	slices.Sort(latencies)
+	var sum time.Duration
+	for _, latency := range latencies {
+		sum += latency
+	}
+	stats.Mean = sum / time.Duration(len(latencies))
+	stats.P50 = percentile(latencies, 50)
+	stats.P90 = percentile(latencies, 90)
+	stats.P99 = percentile(latencies, 99)
+	stats.Max = latencies[len(latencies)-1]
+	return stats
+}
+
+// percentile returns the nearest-rank percentile of sorted latencies
+func percentile(sorted []time.Duration, p int) time.Duration {
+	rank := (p*len(sorted) + 99) / 100
+	return sorted[max(rank, 1)-1]
+}
+
+// Write prints the report as a table with one row per request kind
+func (r *Report) Write(w io.Writer) error {
+	fmt.Fprintf(w, "%d requests from %d workers in %s: %.1f requests/s, %d failed\n\n",
+		r.Total.Requests, r.Concurrency, r.Elapsed.Round(time.Millisecond), r.Throughput, r.Total.Failed)
+
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(table, "request\trequests\tfailed\tmean\tp50\tp90\tp99\tmax\t")
+	for _, stats := range append(slices.Clone(r.ByRequest), r.Total) {
+		fmt.Fprintf(table, "%s\t%d\t%d\t%s\t%s\t%s\t%s\t%s\t\n", stats.Name, stats.Requests, stats.Failed,
+			roundLatency(stats.Mean), roundLatency(stats.P50), roundLatency(stats.P90), roundLatency(stats.P99), roundLatency(stats.Max))
+	}
+	return table.Flush()
+}
+
+// roundLatency keeps three significant digits of a latency
+func roundLatency(d time.Duration) time.Duration {
+	switch {
+	case d >= time.Second:
+		return d.Round(time.Millisecond)
+	case d >= time.Millisecond:
+		return d.Round(time.Microsecond)
+	default:
+		return d.Round(time.Microsecond / 10)
+	}
+}
@@ -0,0 +1,36 @@
+package bench
+
+import (
+	"aws-mcp-server/internal/config"
+	"aws-mcp-server/internal/logging"
+	"aws-mcp-server/pkg/aws"
+	"aws-mcp-server/pkg/mcp"
+)
+
+// Region is the region the benchmark server reports
+const Region = "us-east-1"
+
+// Config returns the configuration of a benchmark server: the defaults of
+// config.Load without the optional integrations, so only the server and
+// the Backend take part in a run
+func Config() *config.Config {
+	cfg := &config.Config{}
+	cfg.AWS.Region = Region
+	cfg.MCP.ServerName = "aws-mcp-server"
+	cfg.MCP.Version = "bench"
+	cfg.Tagging.RequiredTags = []string{"Name", "Environment", "Owner"}
+	cfg.Tagging.OwnerTags = []string{"Owner", "Team"}
+	cfg.Tagging.EnvironmentTags = []string{"Environment", "Env", "Stage"}
+	cfg.Response.Summaries = true
+	cfg.Response.Timezone = "UTC"
+	cfg.Response.RelativeTimes = true
+	cfg.Access.Role = "operator"
+	cfg.Access.AdminRoles = []string{"admin"}
+	return cfg
+}
+
+// NewServer creates an MCP server whose AWS calls are answered by backend
+func NewServer(cfg *config.Config, backend *Backend, logger *logging.Logger) *mcp.Server {
+	client := aws.NewClientFromConfig(backend.Config(), logger)
+	return mcp.NewServer(cfg, client, logger)
+}
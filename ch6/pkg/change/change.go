@@ -0,0 +1,192 @@
+package change
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"aws-mcp-server/internal/config"
+	"aws-mcp-server/internal/logging"
+)
+
+// Supported ticket systems
+const (
+	ProviderJira       = "jira"
+	ProviderServiceNow = "servicenow"
+)
+
+const (
+	// requestTimeout bounds a single ticket lookup
+	requestTimeout = 10 * time.Second
+	// maxResponseSize bounds how much of a lookup response is read
+	maxResponseSize = 1 << 20
+)
+
+// ErrNotFound is returned by a Lookup when the ticket system has no such ticket
+var ErrNotFound = errors.New("ticket not found")
+
+// Ticket is a change ticket as reported by the ticket system. Without a
+// lookup only Key is set.
+type Ticket struct {
+	Key     string `json:"key"`
+	Status  string `json:"status,omitempty"`
+	Summary string `json:"summary,omitempty"`
+	URL     string `json:"url,omitempty"`
+}
+
+// Lookup finds change tickets in a ticket system
+type Lookup interface {
+	Name() string
+	Find(ctx context.Context, key string) (*Ticket, error)
+}
+
+// Validator checks the change tickets passed to write tools against a format
+// and, with a lookup, against the ticket system. A nil Validator accepts any
+// ticket and requires none.
+type Validator struct {
+	required bool
+	pattern  *regexp.Regexp
+	statuses []string
+	lookup   Lookup
+}
+
+// New creates a validator for the configured rules and ticket system
+func New(cfg config.ChangeConfig, logger *logging.Logger) (*Validator, error) {
+	v := &Validator{required: cfg.Required, statuses: cfg.AllowedStatuses}
+
+	if cfg.Pattern != "" {
+		pattern, err := regexp.Compile(cfg.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid change.pattern: %w", err)
+		}
+		v.pattern = pattern
+	}
+
+	var err error
+	switch strings.ToLower(cfg.Provider) {
+	case "":
+	case ProviderJira:
+		v.lookup, err = NewJiraLookup(cfg.Jira, logger)
+	case ProviderServiceNow:
+		v.lookup, err = NewServiceNowLookup(cfg.ServiceNow, logger)
+	default:
+		return nil, fmt.Errorf("unknown change ticket provider %q (use jira or servicenow)", cfg.Provider)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// Required reports whether write tools need a change ticket
+func (v *Validator) Required() bool {
+	return v != nil && v.required
+}
+
+// Describe explains the accepted tickets for tool descriptions
+func (v *Validator) Describe() string {
+	description := "Change ticket authorizing this change, e.g. CHG-1234; recorded in the audit log and as a note on the resource"
+	if v == nil {
+		return description
+	}
+	if v.pattern != nil {
+		description += fmt.Sprintf(". Must match %s", v.pattern)
+	}
+	if v.lookup != nil {
+		description += fmt.Sprintf(" and exist in %s", v.lookup.Name())
+		if len(v.statuses) > 0 {
+			description += " with status " + strings.Join(v.statuses, ", ")
+		}
+	}
+	if v.required {
+		description += ". Required for every change"
+	}
+	return description
+}
+
+// Validate checks a ticket's format and, with a lookup, that it exists and is
+// in an allowed status. It returns the ticket as the ticket system reports it.
+func (v *Validator) Validate(ctx context.Context, key string) (*Ticket, error) {
+	key = strings.TrimSpace(key)
+	if key == "" {
+		return nil, errors.New("change ticket is empty")
+	}
+	if v == nil {
+		return &Ticket{Key: key}, nil
+	}
+	if v.pattern != nil && !v.pattern.MatchString(key) {
+		return nil, fmt.Errorf("change ticket %q does not match the required format %s", key, v.pattern)
+	}
+	if v.lookup == nil {
+		return &Ticket{Key: key}, nil
+	}
+
+	ticket, err := v.lookup.Find(ctx, key)
+	if errors.Is(err, ErrNotFound) {
+		return nil, fmt.Errorf("change ticket %s was not found in %s", key, v.lookup.Name())
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up change ticket %s in %s: %w", key, v.lookup.Name(), err)
+	}
+	if len(v.statuses) > 0 && !containsFold(v.statuses, ticket.Status) {
+		return nil, fmt.Errorf("change ticket %s is %s in %s, allowed statuses are %s",
+			key, ticket.Status, v.lookup.Name(), strings.Join(v.statuses, ", "))
+	}
+	return ticket, nil
+}
+
+// getJSON requests url with the given headers and decodes the response into
+// target. A 404 is reported as ErrNotFound.
+func getJSON(ctx context.Context, client *http.Client, url string, headers map[string]string, target interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		return ErrNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	if err := json.Unmarshal(body, target); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+	return nil
+}
+
+// basicAuth returns the Authorization header value for HTTP basic auth
+func basicAuth(username, password string) string {
+	return "Basic " + base64.StdEncoding.EncodeToString([]byte(username+":"+password))
+}
+
+// containsFold reports whether values contains value, ignoring case
+func containsFold(values []string, value string) bool {
+	for _, candidate := range values {
+		if strings.EqualFold(candidate, value) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,107 @@
+package change
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"aws-mcp-server/internal/config"
+	"aws-mcp-server/internal/logging"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidatorPattern(t *testing.T) {
+	v, err := New(config.ChangeConfig{Required: true, Pattern: `^CHG-\d+$`}, logging.NewLogger("error", "text"))
+	require.NoError(t, err)
+	assert.True(t, v.Required())
+
+	ticket, err := v.Validate(context.Background(), " CHG-42 ")
+	require.NoError(t, err)
+	assert.Equal(t, "CHG-42", ticket.Key)
+
+	_, err = v.Validate(context.Background(), "fixing stuff")
+	assert.ErrorContains(t, err, "does not match the required format")
+
+	_, err = New(config.ChangeConfig{Pattern: "("}, logging.NewLogger("error", "text"))
+	assert.ErrorContains(t, err, "invalid change.pattern")
+	_, err = New(config.ChangeConfig{Provider: "remedy"}, logging.NewLogger("error", "text"))
+	assert.ErrorContains(t, err, "unknown change ticket provider")
+
+	var none *Validator
+	assert.False(t, none.Required())
+	_, err = none.Validate(context.Background(), "anything")
+	assert.NoError(t, err)
+}
+
+func TestJiraLookup(t *testing.T) {
+	var requests []*http.Request
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r)
+		switch r.URL.Path {
+		case "/rest/api/2/issue/CHG-1":
+			w.Write([]byte(`{"key":"CHG-1","fields":{"summary":"Resize web tier","status":{"name":"Approved"}}}`))
+		case "/rest/api/2/issue/CHG-2":
+			w.Write([]byte(`{"key":"CHG-2","fields":{"summary":"Patch bastion","status":{"name":"Closed"}}}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	v, err := New(config.ChangeConfig{
+		Provider:        ProviderJira,
+		AllowedStatuses: []string{"approved", "In Progress"},
+		Jira:            config.JiraConfig{URL: server.URL + "/", Username: "sre@example.com", APIToken: "token"},
+	}, logging.NewLogger("error", "text"))
+	require.NoError(t, err)
+
+	ticket, err := v.Validate(context.Background(), "CHG-1")
+	require.NoError(t, err)
+	assert.Equal(t, &Ticket{Key: "CHG-1", Status: "Approved", Summary: "Resize web tier", URL: server.URL + "/browse/CHG-1"}, ticket)
+	username, password, ok := requests[0].BasicAuth()
+	require.True(t, ok)
+	assert.Equal(t, "sre@example.com", username)
+	assert.Equal(t, "token", password)
+
+	_, err = v.Validate(context.Background(), "CHG-2")
+	assert.EqualError(t, err, "change ticket CHG-2 is Closed in Jira, allowed statuses are approved, In Progress")
+
+	_, err = v.Validate(context.Background(), "CHG-3")
+	assert.EqualError(t, err, "change ticket CHG-3 was not found in Jira")
+}
+
+func TestServiceNowLookup(t *testing.T) {
+	var query string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/now/table/change_request", r.URL.Path)
+		query = r.URL.Query().Get("sysparm_query")
+		if query == "number=CHG0030001" {
+			w.Write([]byte(`{"result":[{"sys_id":"abc","number":"CHG0030001","state":"Implement","short_description":"Rotate keys"}]}`))
+			return
+		}
+		w.Write([]byte(`{"result":[]}`))
+	}))
+	defer server.Close()
+
+	v, err := New(config.ChangeConfig{
+		Provider:   ProviderServiceNow,
+		ServiceNow: config.ServiceNowConfig{URL: server.URL, Username: "mcp", Password: "secret"},
+	}, logging.NewLogger("error", "text"))
+	require.NoError(t, err)
+
+	ticket, err := v.Validate(context.Background(), "CHG0030001")
+	require.NoError(t, err)
+	assert.Equal(t, "Implement", ticket.Status)
+	assert.Equal(t, "Rotate keys", ticket.Summary)
+	assert.Contains(t, ticket.URL, "sys_id%3Dabc")
+
+	_, err = v.Validate(context.Background(), "CHG0039999")
+	assert.EqualError(t, err, "change ticket CHG0039999 was not found in ServiceNow")
+	assert.Equal(t, "number=CHG0039999", query)
+
+	_, err = New(config.ChangeConfig{Provider: ProviderServiceNow, ServiceNow: config.ServiceNowConfig{URL: server.URL}}, logging.NewLogger("error", "text"))
+	assert.ErrorContains(t, err, "username and change.servicenow.password are required")
+}
@@ -0,0 +1,88 @@
+package change
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"aws-mcp-server/internal/config"
+	"aws-mcp-server/internal/logging"
+
+	"github.com/sirupsen/logrus"
+)
+
+// JiraLookup finds change tickets as Jira issues
+type JiraLookup struct {
+	baseURL string
+	headers map[string]string
+	client  *http.Client
+	logger  *logging.Logger
+}
+
+// NewJiraLookup creates a lookup for a Jira site. With a username the API
+// token is sent with basic auth as Jira Cloud expects; without one it is sent
+// as a personal access token for Jira Data Center.
+func NewJiraLookup(cfg config.JiraConfig, logger *logging.Logger) (*JiraLookup, error) {
+	if cfg.URL == "" {
+		return nil, errors.New("change.jira.url is required")
+	}
+	if cfg.APIToken == "" {
+		return nil, errors.New("change.jira.api_token is required")
+	}
+
+	authorization := "Bearer " + cfg.APIToken
+	if cfg.Username != "" {
+		authorization = basicAuth(cfg.Username, cfg.APIToken)
+	}
+
+	return &JiraLookup{
+		baseURL: strings.TrimSuffix(cfg.URL, "/"),
+		headers: map[string]string{"Authorization": authorization},
+		client:  &http.Client{Timeout: requestTimeout},
+		logger:  logger,
+	}, nil
+}
+
+// Name returns the ticket system name
+func (j *JiraLookup) Name() string {
+	return "Jira"
+}
+
+// jiraIssue is the response of GET /rest/api/2/issue/{key}
+type jiraIssue struct {
+	Key    string `json:"key"`
+	Fields struct {
+		Summary string `json:"summary"`
+		Status  struct {
+			Name string `json:"name"`
+		} `json:"status"`
+	} `json:"fields"`
+}
+
+// Find returns the issue with the given key
+func (j *JiraLookup) Find(ctx context.Context, key string) (*Ticket, error) {
+	start := time.Now()
+
+	endpoint := fmt.Sprintf("%s/rest/api/2/issue/%s?fields=status,summary", j.baseURL, url.PathEscape(key))
+	var issue jiraIssue
+	if err := getJSON(ctx, j.client, endpoint, j.headers, &issue); err != nil {
+		return nil, err
+	}
+
+	j.logger.WithFields(logrus.Fields{
+		"key":      issue.Key,
+		"status":   issue.Fields.Status.Name,
+		"duration": time.Since(start),
+	}).Debug("Looked up Jira change ticket")
+
+	return &Ticket{
+		Key:     issue.Key,
+		Status:  issue.Fields.Status.Name,
+		Summary: issue.Fields.Summary,
+		URL:     j.baseURL + "/browse/" + url.PathEscape(issue.Key),
+	}, nil
+}
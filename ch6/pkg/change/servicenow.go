@@ -0,0 +1,90 @@
+package change
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"aws-mcp-server/internal/config"
+	"aws-mcp-server/internal/logging"
+
+	"github.com/sirupsen/logrus"
+)
+
+// ServiceNowLookup finds change tickets as ServiceNow change requests
+type ServiceNowLookup struct {
+	baseURL string
+	headers map[string]string
+	client  *http.Client
+	logger  *logging.Logger
+}
+
+// NewServiceNowLookup creates a lookup for a ServiceNow instance using a user
+// that may read the change_request table
+func NewServiceNowLookup(cfg config.ServiceNowConfig, logger *logging.Logger) (*ServiceNowLookup, error) {
+	if cfg.URL == "" {
+		return nil, errors.New("change.servicenow.url is required")
+	}
+	if cfg.Username == "" || cfg.Password == "" {
+		return nil, errors.New("change.servicenow.username and change.servicenow.password are required")
+	}
+
+	return &ServiceNowLookup{
+		baseURL: strings.TrimSuffix(cfg.URL, "/"),
+		headers: map[string]string{"Authorization": basicAuth(cfg.Username, cfg.Password)},
+		client:  &http.Client{Timeout: requestTimeout},
+		logger:  logger,
+	}, nil
+}
+
+// Name returns the ticket system name
+func (s *ServiceNowLookup) Name() string {
+	return "ServiceNow"
+}
+
+// serviceNowChanges is the response of GET /api/now/table/change_request with
+// display values, so state is the label shown in ServiceNow, e.g. Implement
+type serviceNowChanges struct {
+	Result []struct {
+		SysID            string `json:"sys_id"`
+		Number           string `json:"number"`
+		State            string `json:"state"`
+		ShortDescription string `json:"short_description"`
+	} `json:"result"`
+}
+
+// Find returns the change request with the given number
+func (s *ServiceNowLookup) Find(ctx context.Context, key string) (*Ticket, error) {
+	start := time.Now()
+
+	params := url.Values{
+		"sysparm_query":         {"number=" + key},
+		"sysparm_fields":        {"sys_id,number,state,short_description"},
+		"sysparm_display_value": {"true"},
+		"sysparm_limit":         {"1"},
+	}
+	var resp serviceNowChanges
+	if err := getJSON(ctx, s.client, s.baseURL+"/api/now/table/change_request?"+params.Encode(), s.headers, &resp); err != nil {
+		return nil, err
+	}
+	if len(resp.Result) == 0 {
+		return nil, ErrNotFound
+	}
+	found := resp.Result[0]
+
+	s.logger.WithFields(logrus.Fields{
+		"number":   found.Number,
+		"state":    found.State,
+		"duration": time.Since(start),
+	}).Debug("Looked up ServiceNow change request")
+
+	return &Ticket{
+		Key:     found.Number,
+		Status:  found.State,
+		Summary: found.ShortDescription,
+		URL:     s.baseURL + "/nav_to.do?uri=" + url.QueryEscape("change_request.do?sys_id="+found.SysID),
+	}, nil
+}
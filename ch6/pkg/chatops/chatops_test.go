@@ -0,0 +1,267 @@
+package chatops
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"aws-mcp-server/internal/config"
+	"aws-mcp-server/internal/logging"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testSecret = "8f742231b10e8888abcd99yyyzzz85a5"
+
+func TestParseCommand(t *testing.T) {
+	cmd, err := ParseCommand(`create-ec2-instance imageId=ami-123 count=2 dryRun=true tags={"Team":"web"} name="web 1" id="42"`)
+	require.NoError(t, err)
+
+	assert.Equal(t, "create-ec2-instance", cmd.Tool)
+	assert.Equal(t, "ami-123", cmd.Arguments["imageId"])
+	assert.Equal(t, float64(2), cmd.Arguments["count"])
+	assert.Equal(t, true, cmd.Arguments["dryRun"])
+	assert.Equal(t, map[string]interface{}{"Team": "web"}, cmd.Arguments["tags"])
+	assert.Equal(t, "web 1", cmd.Arguments["name"])
+	assert.Equal(t, "42", cmd.Arguments["id"], "quoted values stay strings")
+
+	_, err = ParseCommand(`stop-ec2-instance i-123`)
+	assert.ErrorContains(t, err, "key=value")
+	_, err = ParseCommand(`stop-ec2-instance reason="oops`)
+	assert.ErrorContains(t, err, "unterminated")
+	_, err = ParseCommand(`stop-ec2-instance "instanceId"=i-123`)
+	assert.ErrorContains(t, err, "must not be quoted")
+}
+
+func TestVerifySlackSignature(t *testing.T) {
+	now := time.Unix(1531420618, 0)
+	body := []byte("token=xyz&command=/aiops&text=help")
+	signature := sign(testSecret, "1531420618", body)
+
+	assert.NoError(t, VerifySlackSignature(testSecret, "1531420618", body, signature, now))
+	assert.ErrorContains(t, VerifySlackSignature("other", "1531420618", body, signature, now), "mismatch")
+	assert.ErrorContains(t, VerifySlackSignature(testSecret, "1531420618", body, signature, now.Add(time.Hour)), "away from now")
+	assert.Error(t, VerifySlackSignature("", "1531420618", body, signature, now))
+}
+
+func TestSlashCommandRunsToolWithRole(t *testing.T) {
+	slack := newResponseRecorder(t)
+	defer slack.Close()
+
+	type call struct {
+		role, tool  string
+		arguments   map[string]interface{}
+		hasDeadline bool
+	}
+	calls := make(chan call, 1)
+	gateway := newTestGateway(func(ctx context.Context, user, role, tool string, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+		_, hasDeadline := ctx.Deadline()
+		calls <- call{role, tool, arguments, hasDeadline}
+		return textResult(`{"success": true, "message": "Instance stopped"}`, "Stopped web-1"), nil
+	})
+
+	resp := post(t, gateway, "/slack/commands", url.Values{
+		"user_id":      {"U123ADMIN"},
+		"command":      {"/aiops"},
+		"text":         {"stop-ec2-instance instanceId=i-0abc"},
+		"response_url": {slack.URL},
+	})
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Contains(t, resp.Body.String(), "Running `stop-ec2-instance`")
+
+	got := <-calls
+	assert.Equal(t, "admin", got.role)
+	assert.Equal(t, "stop-ec2-instance", got.tool)
+	assert.Equal(t, "i-0abc", got.arguments["instanceId"])
+	assert.False(t, got.hasDeadline, "the tool's own timeout applies, not one of the gateway")
+
+	msg := slack.next(t)
+	assert.Equal(t, "in_channel", msg.ResponseType)
+	assert.Contains(t, msg.Text, "Stopped web-1")
+}
+
+func TestConfirmationButtonsRoundTrip(t *testing.T) {
+	slack := newResponseRecorder(t)
+	defer slack.Close()
+
+	calls := make(chan map[string]interface{}, 2)
+	gateway := newTestGateway(func(ctx context.Context, user, role, tool string, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+		calls <- arguments
+		if arguments["confirm"] == true {
+			return textResult(`{"success": true, "message": "Volume encrypted"}`, ""), nil
+		}
+		return textResult(`{"success": false, "confirmation_required": true, "warnings": ["The instance will be stopped"]}`, ""), nil
+	})
+
+	post(t, gateway, "/slack/commands", url.Values{
+		"user_id":      {"U999"},
+		"text":         {"encrypt-volume volumeId=vol-1"},
+		"response_url": {slack.URL},
+	})
+	<-calls
+
+	prompt := slack.next(t)
+	var confirm *Element
+	for _, block := range prompt.Blocks {
+		for i, element := range block.Elements {
+			if element.Text.Text == "Confirm" {
+				confirm = &block.Elements[i]
+			}
+		}
+	}
+	require.NotNil(t, confirm, "confirmation prompt should have a Confirm button")
+	assert.Contains(t, prompt.Blocks[1].Text.Text, "The instance will be stopped")
+	assert.NotContains(t, confirm.Value, "vol-1", "buttons do not carry the arguments, which may hold secrets")
+
+	payload, _ := json.Marshal(map[string]interface{}{
+		"type":         "block_actions",
+		"user":         map[string]string{"id": "U999"},
+		"response_url": slack.URL,
+		"actions":      []map[string]string{{"action_id": confirm.ActionID, "value": confirm.Value}},
+	})
+	resp := post(t, gateway, "/slack/interactions", url.Values{"payload": {string(payload)}})
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	arguments := <-calls
+	assert.Equal(t, true, arguments["confirm"])
+	assert.Equal(t, "vol-1", arguments["volumeId"])
+
+	result := slack.next(t)
+	assert.True(t, result.ReplaceOriginal)
+	assert.Contains(t, result.Text, "Volume encrypted")
+
+	post(t, gateway, "/slack/interactions", url.Values{"payload": {string(payload)}})
+	assert.Contains(t, slack.next(t).Text, "expired or was already used", "each Confirm button runs once")
+	assert.Empty(t, calls)
+}
+
+func TestConfirmationsExpire(t *testing.T) {
+	var pending confirmations
+	now := time.Now()
+	id, err := pending.add(Command{Tool: "put-parameter", Arguments: map[string]interface{}{"value": "s3cret"}}, now)
+	require.NoError(t, err)
+
+	_, ok := pending.take(id, now.Add(confirmationTTL))
+	assert.False(t, ok)
+	_, ok = pending.take("unknown", now)
+	assert.False(t, ok)
+}
+
+func TestGatewayRejectsUnknownUsersAndBadSignatures(t *testing.T) {
+	gateway := newTestGateway(func(ctx context.Context, user, role, tool string, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+		t.Fatal("tool should not be called")
+		return nil, nil
+	})
+	gateway.config.DefaultRole = ""
+
+	resp := post(t, gateway, "/slack/commands", url.Values{"user_id": {"UNKNOWN"}, "text": {"audit-tags"}})
+	assert.Contains(t, resp.Body.String(), "not allowed")
+
+	resp = post(t, gateway, "/slack/commands", url.Values{"user_id": {"U123ADMIN"}, "text": {"terminate-ec2-instance instanceId=i-1"}})
+	assert.Contains(t, resp.Body.String(), "not available from chat")
+
+	resp = post(t, gateway, "/slack/commands", url.Values{"user_id": {"U123ADMIN"}, "text": {"get-windows-password instanceId=i-1"}})
+	assert.Contains(t, resp.Body.String(), "returns secrets and is not available from chat", "secret tools are refused even when listed")
+	assert.Contains(t, resp.Body.String(), `"response_type":"ephemeral"`)
+
+	req := httptest.NewRequest(http.MethodPost, "/slack/commands", strings.NewReader("user_id=U123ADMIN&text=audit-tags"))
+	req.Header.Set("X-Slack-Request-Timestamp", strconv.FormatInt(time.Now().Unix(), 10))
+	req.Header.Set("X-Slack-Signature", "v0=bogus")
+	rec := httptest.NewRecorder()
+	gateway.Handler().ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+}
+
+func TestGatewayDefaultsToReadOnlyTools(t *testing.T) {
+	gateway := newTestGateway(nil)
+	gateway.config.Tools = nil
+
+	assert.True(t, gateway.toolAllowed("search"))
+	assert.False(t, gateway.toolAllowed("stop-ec2-instance"), "write tools must be listed in chatops.tools")
+	assert.Contains(t, gateway.help("").Text, "Available: `find-orphans`, `search`")
+}
+
+func newTestGateway(call CallFunc) *SlackGateway {
+	return NewSlackGateway(config.ChatOpsConfig{
+		SigningSecret: testSecret,
+		// Viper lowercases map keys
+		Users:       map[string]string{"u123admin": "admin"},
+		DefaultRole: "operator",
+		Tools:       []string{"stop-ec2-instance", "encrypt-volume", "audit-tags", "get-windows-password"},
+	}, ToolPolicy{
+		DefaultTools: []string{"find-orphans", "search"},
+		SecretTools:  []string{"get-windows-password"},
+	}, call, logging.NewLogger("error", "text"))
+}
+
+func textResult(payload, summary string) *mcp.CallToolResult {
+	result := &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Type: "text", Text: payload}}}
+	if summary != "" {
+		result.Content = append(result.Content, &mcp.TextContent{Type: "text", Text: summary})
+	}
+	return result
+}
+
+func sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte("v0:" + timestamp + ":"))
+	mac.Write(body)
+	return "v0=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// post sends a signed form request to the gateway
+func post(t *testing.T, gateway *SlackGateway, path string, form url.Values) *httptest.ResponseRecorder {
+	t.Helper()
+
+	body := form.Encode()
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("X-Slack-Request-Timestamp", timestamp)
+	req.Header.Set("X-Slack-Signature", sign(testSecret, timestamp, []byte(body)))
+
+	rec := httptest.NewRecorder()
+	gateway.Handler().ServeHTTP(rec, req)
+	return rec
+}
+
+// responseRecorder stands in for Slack's response_url
+type responseRecorder struct {
+	*httptest.Server
+	messages chan Message
+}
+
+func newResponseRecorder(t *testing.T) *responseRecorder {
+	r := &responseRecorder{messages: make(chan Message, 4)}
+	r.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		var msg Message
+		if err := json.NewDecoder(req.Body).Decode(&msg); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		r.messages <- msg
+	}))
+	return r
+}
+
+func (r *responseRecorder) next(t *testing.T) Message {
+	t.Helper()
+	select {
+	case msg := <-r.messages:
+		return msg
+	case <-time.After(5 * time.Second):
+		t.Fatal("no message posted to the response_url")
+		return Message{}
+	}
+}
@@ -0,0 +1,123 @@
+package chatops
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Command is a tool call typed by a human, e.g.
+//
+//	stop-ec2-instance instanceId=i-0abc123 reason="cost cleanup"
+type Command struct {
+	Tool      string
+	Arguments map[string]interface{}
+}
+
+// ParseCommand parses "<tool> key=value ..." into a tool call. Unquoted values
+// that look like JSON (true, 3, {"Team":"web"}) are decoded so they match what
+// an MCP client would send; quoted values are always strings.
+func ParseCommand(text string) (Command, error) {
+	tokens, err := splitTokens(text)
+	if err != nil {
+		return Command{}, err
+	}
+	if len(tokens) == 0 {
+		return Command{}, fmt.Errorf("no tool given")
+	}
+
+	cmd := Command{
+		Tool:      tokens[0].text,
+		Arguments: make(map[string]interface{}),
+	}
+
+	for _, token := range tokens[1:] {
+		key, value, found := strings.Cut(token.text, "=")
+		if !found || key == "" {
+			return Command{}, fmt.Errorf("argument %q is not in key=value form", token.text)
+		}
+		if token.quotedFrom >= 0 && token.quotedFrom <= len(key) {
+			return Command{}, fmt.Errorf("argument name %q must not be quoted", key)
+		}
+
+		if token.quotedFrom >= 0 {
+			cmd.Arguments[key] = value
+		} else {
+			cmd.Arguments[key] = parseValue(value)
+		}
+	}
+
+	return cmd, nil
+}
+
+// parseValue decodes JSON scalars, objects and arrays and falls back to a string
+func parseValue(value string) interface{} {
+	var decoded interface{}
+	if err := json.Unmarshal([]byte(value), &decoded); err == nil && decoded != nil {
+		return decoded
+	}
+	return value
+}
+
+// token is one whitespace-separated word. quotedFrom is the offset of the first
+// quoted character in text, or -1 when the word contains no quotes.
+type token struct {
+	text       string
+	quotedFrom int
+}
+
+// splitTokens splits on whitespace outside double quotes and strips the quotes.
+// Slack sends "smart" quotes when users type them on mobile, so those count too.
+// Inside JSON objects and arrays, quotes and whitespace are kept as typed.
+func splitTokens(text string) ([]token, error) {
+	var tokens []token
+	var current strings.Builder
+	inWord, inQuotes := false, false
+	quotedFrom := -1
+	depth := 0
+
+	flush := func() {
+		if inWord {
+			tokens = append(tokens, token{text: current.String(), quotedFrom: quotedFrom})
+		}
+		current.Reset()
+		inWord = false
+		quotedFrom = -1
+	}
+
+	for _, r := range text {
+		switch {
+		case !inQuotes && (r == '{' || r == '['):
+			depth++
+			current.WriteRune(r)
+			inWord = true
+		case !inQuotes && depth > 0 && (r == '}' || r == ']'):
+			depth--
+			current.WriteRune(r)
+		case depth > 0:
+			// Normalise smart quotes so the JSON still decodes
+			if r == '“' || r == '”' {
+				r = '"'
+			}
+			current.WriteRune(r)
+		case r == '"' || r == '“' || r == '”':
+			if !inQuotes && quotedFrom < 0 {
+				quotedFrom = current.Len()
+			}
+			inQuotes = !inQuotes
+			inWord = true
+		case !inQuotes && (r == ' ' || r == '\t' || r == '\n'):
+			flush()
+		default:
+			current.WriteRune(r)
+			inWord = true
+		}
+	}
+
+	if inQuotes {
+		return nil, fmt.Errorf("unterminated quote")
+	}
+	flush()
+
+	return tokens, nil
+}
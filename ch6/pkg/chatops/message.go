@@ -0,0 +1,256 @@
+package chatops
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const (
+	// maxCodeBlock keeps the JSON payload inside Slack's 3000 character limit for a section
+	maxCodeBlock = 2800
+	// maxButtonValue is Slack's limit for the value carried by a button
+	maxButtonValue = 2000
+	// confirmationTTL is how long a Confirm button can be clicked
+	confirmationTTL = 15 * time.Minute
+
+	// Action IDs must be unique within a message, so run buttons are "run-<label>"
+	actionRun    = "run"
+	actionCancel = "cancel"
+)
+
+// Message is a Slack message posted to a response_url
+type Message struct {
+	ResponseType    string  `json:"response_type,omitempty"`
+	ReplaceOriginal bool    `json:"replace_original,omitempty"`
+	Text            string  `json:"text"`
+	Blocks          []Block `json:"blocks,omitempty"`
+}
+
+// Block is a Slack Block Kit block; only the fields used here are modelled
+type Block struct {
+	Type     string    `json:"type"`
+	Text     *Text     `json:"text,omitempty"`
+	Elements []Element `json:"elements,omitempty"`
+}
+
+// Text is a Block Kit text object
+type Text struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+// Element is a Block Kit button
+type Element struct {
+	Type     string `json:"type"`
+	Text     Text   `json:"text"`
+	ActionID string `json:"action_id"`
+	Value    string `json:"value,omitempty"`
+	Style    string `json:"style,omitempty"`
+}
+
+// buttonValue is the tool call carried by a button and run when it is
+// clicked. Confirm buttons only carry the ID of a confirmation kept by the
+// gateway, as the arguments may hold secrets.
+type buttonValue struct {
+	Tool         string                 `json:"tool,omitempty"`
+	Arguments    map[string]interface{} `json:"arguments,omitempty"`
+	Confirmation string                 `json:"confirmation,omitempty"`
+}
+
+// confirmations keeps the confirmed calls behind Confirm buttons until they
+// are clicked or expire, so that arguments such as the value of a
+// put-parameter SecureString are not posted to the channel
+type confirmations struct {
+	mu    sync.Mutex
+	calls map[string]confirmation
+}
+
+// confirmation is a confirmed call waiting for its Confirm button
+type confirmation struct {
+	cmd       Command
+	expiresAt time.Time
+}
+
+// add keeps a call for confirmationTTL and returns its ID, dropping the
+// calls whose buttons were never clicked
+func (c *confirmations) add(cmd Command, now time.Time) (string, error) {
+	random := make([]byte, 16)
+	if _, err := rand.Read(random); err != nil {
+		return "", err
+	}
+	id := hex.EncodeToString(random)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.calls == nil {
+		c.calls = make(map[string]confirmation)
+	}
+	for key, call := range c.calls {
+		if !now.Before(call.expiresAt) {
+			delete(c.calls, key)
+		}
+	}
+	c.calls[id] = confirmation{cmd: cmd, expiresAt: now.Add(confirmationTTL)}
+	return id, nil
+}
+
+// take returns the call of a confirmation and forgets it, so each Confirm
+// button runs once
+func (c *confirmations) take(id string, now time.Time) (Command, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	call, ok := c.calls[id]
+	delete(c.calls, id)
+	if !ok || !now.Before(call.expiresAt) {
+		return Command{}, false
+	}
+	return call.cmd, true
+}
+
+// ephemeral returns a message only the invoking user sees
+func ephemeral(format string, args ...interface{}) Message {
+	return Message{ResponseType: "ephemeral", Text: fmt.Sprintf(format, args...)}
+}
+
+// resultMessage turns a tool result into a channel message. Confirmation
+// prompts get Confirm/Cancel buttons, with the confirmed call kept in
+// pending, and queued actions get Approve/Reject buttons, so the follow-up
+// calls are one click away.
+func resultMessage(user string, cmd Command, result *mcp.CallToolResult, pending *confirmations) Message {
+	payload, summary := splitResult(result)
+
+	var response struct {
+		Error                string   `json:"error"`
+		Message              string   `json:"message"`
+		ConfirmationRequired bool     `json:"confirmation_required"`
+		Warnings             []string `json:"warnings"`
+		ApprovalRequestID    string   `json:"approval_request_id"`
+	}
+	_ = json.Unmarshal([]byte(payload), &response)
+
+	header := fmt.Sprintf("<@%s> ran `%s`", user, cmd.Tool)
+	status := summary
+	if status == "" {
+		status = response.Message
+	}
+	if response.Error != "" {
+		status = ":x: " + response.Error
+	}
+
+	msg := Message{
+		ResponseType: "in_channel",
+		Text:         strings.TrimSpace(header + ": " + status),
+	}
+	msg.Blocks = append(msg.Blocks, section(fmt.Sprintf("%s\n%s", header, status)))
+
+	switch {
+	case response.ConfirmationRequired:
+		if len(response.Warnings) > 0 {
+			msg.Blocks = append(msg.Blocks, section(":warning: "+strings.Join(response.Warnings, "\n:warning: ")))
+		}
+		msg.Blocks = append(msg.Blocks, section(codeBlock(payload)))
+
+		confirmed := make(map[string]interface{}, len(cmd.Arguments)+1)
+		for key, value := range cmd.Arguments {
+			confirmed[key] = value
+		}
+		confirmed["confirm"] = true
+		var confirm Element
+		if id, err := pending.add(Command{Tool: cmd.Tool, Arguments: confirmed}, time.Now()); err == nil {
+			confirm = button("Confirm", buttonValue{Confirmation: id}, "danger")
+		}
+		msg.Blocks = append(msg.Blocks, actions(confirm, cancelButton()))
+	case response.ApprovalRequestID != "":
+		id := response.ApprovalRequestID
+		msg.Blocks = append(msg.Blocks, actions(
+			button("Approve", buttonValue{Tool: "approve-action", Arguments: map[string]interface{}{"requestId": id}}, "primary"),
+			button("Reject", buttonValue{Tool: "reject-action", Arguments: map[string]interface{}{"requestId": id}}, "danger"),
+		))
+	default:
+		msg.Blocks = append(msg.Blocks, section(codeBlock(payload)))
+	}
+
+	return msg
+}
+
+// splitResult returns the JSON payload and the optional one-line summary of a result
+func splitResult(result *mcp.CallToolResult) (payload, summary string) {
+	if result == nil {
+		return "", ""
+	}
+	for _, content := range result.Content {
+		var text string
+		switch c := content.(type) {
+		case *mcp.TextContent:
+			text = c.Text
+		case mcp.TextContent:
+			text = c.Text
+		default:
+			continue
+		}
+
+		if payload == "" && json.Valid([]byte(text)) {
+			payload = text
+		} else if summary == "" {
+			summary = text
+		}
+	}
+	return payload, summary
+}
+
+// section returns a mrkdwn section block
+func section(text string) Block {
+	return Block{Type: "section", Text: &Text{Type: "mrkdwn", Text: text}}
+}
+
+// codeBlock wraps text in a code block, truncated to fit a section
+func codeBlock(text string) string {
+	if len(text) > maxCodeBlock {
+		text = text[:maxCodeBlock] + "\n…"
+	}
+	return "```" + text + "```"
+}
+
+// actions returns an actions block holding the buttons that fit
+func actions(elements ...Element) Block {
+	block := Block{Type: "actions"}
+	for _, element := range elements {
+		if element.ActionID != "" {
+			block.Elements = append(block.Elements, element)
+		}
+	}
+	return block
+}
+
+// button returns a button that runs a tool call when clicked. Calls that are too
+// large for a button value produce no button; the user can type the command instead.
+func button(label string, value buttonValue, style string) Element {
+	encoded, err := json.Marshal(value)
+	if err != nil || len(encoded) > maxButtonValue {
+		return Element{}
+	}
+	return Element{
+		Type:     "button",
+		Text:     Text{Type: "plain_text", Text: label},
+		ActionID: actionRun + "-" + strings.ToLower(label),
+		Value:    string(encoded),
+		Style:    style,
+	}
+}
+
+// cancelButton dismisses a confirmation prompt
+func cancelButton() Element {
+	return Element{
+		Type:     "button",
+		Text:     Text{Type: "plain_text", Text: "Cancel"},
+		ActionID: actionCancel,
+	}
+}
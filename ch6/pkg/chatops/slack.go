@@ -0,0 +1,398 @@
+package chatops
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"aws-mcp-server/internal/config"
+	"aws-mcp-server/internal/logging"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// maxRequestAge rejects replayed Slack requests, as Slack recommends
+	maxRequestAge = 5 * time.Minute
+	// maxBodySize bounds the request bodies read from Slack
+	maxBodySize = 1 << 20
+)
+
+// CallFunc runs a tool on behalf of a Slack user with the given role. The MCP server
+// passes its tool handler here, so chat calls get the same access checks,
+// guardrails, freeze windows and approvals as calls from the AI. It also
+// bounds the call with the tool's timeout, so the gateway adds none.
+type CallFunc func(ctx context.Context, user, role, tool string, arguments map[string]interface{}) (*mcp.CallToolResult, error)
+
+// ToolPolicy tells the gateway which tools chat may run. DefaultTools are
+// allowed when chatops.tools is empty; SecretTools return secrets, which
+// would be posted to the channel, so they are never run from chat.
+type ToolPolicy struct {
+	DefaultTools []string
+	SecretTools  []string
+}
+
+// SlackGateway serves Slack slash commands and interactive buttons and maps
+// them to tool calls. Results are posted back asynchronously to the
+// response_url because Slack expects an answer within three seconds.
+type SlackGateway struct {
+	config config.ChatOpsConfig
+	policy ToolPolicy
+	call   CallFunc
+	logger *logging.Logger
+	client *http.Client
+	wg     sync.WaitGroup
+	// pending holds the calls behind Confirm buttons
+	pending confirmations
+}
+
+// NewSlackGateway creates a gateway that runs the tools policy allows through call
+func NewSlackGateway(cfg config.ChatOpsConfig, policy ToolPolicy, call CallFunc, logger *logging.Logger) *SlackGateway {
+	return &SlackGateway{
+		config: cfg,
+		policy: policy,
+		call:   call,
+		logger: logger,
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Handler returns the HTTP handler for the Slack endpoints
+func (g *SlackGateway) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /slack/commands", g.handleCommand)
+	mux.HandleFunc("POST /slack/interactions", g.handleInteraction)
+	return mux
+}
+
+// ListenAndServe serves the gateway until ctx is cancelled, then waits for
+// running tool calls to post their results
+func (g *SlackGateway) ListenAndServe(ctx context.Context) error {
+	if g.config.SigningSecret == "" {
+		return errors.New("chatops.signing_secret is required to verify Slack requests")
+	}
+
+	server := &http.Server{
+		Addr:              g.config.Listen,
+		Handler:           g.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- server.ListenAndServe()
+	}()
+
+	g.logger.WithField("address", g.config.Listen).Info("Slack chat gateway listening")
+
+	select {
+	case err := <-errCh:
+		return fmt.Errorf("chat gateway stopped: %w", err)
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	err := server.Shutdown(shutdownCtx)
+	g.wg.Wait()
+	return err
+}
+
+// handleCommand handles a slash command such as "/aiops stop-ec2-instance instanceId=i-123"
+func (g *SlackGateway) handleCommand(w http.ResponseWriter, r *http.Request) {
+	form, ok := g.verifiedForm(w, r)
+	if !ok {
+		return
+	}
+
+	user := form.Get("user_id")
+	role, allowed := g.role(user)
+	if !allowed {
+		writeJSON(w, ephemeral("You are not allowed to run tools from chat. Ask an admin to add your Slack user ID to chatops.users."))
+		return
+	}
+
+	text := strings.TrimSpace(form.Get("text"))
+	if text == "" || text == "help" {
+		writeJSON(w, g.help(form.Get("command")))
+		return
+	}
+
+	cmd, err := ParseCommand(text)
+	if err != nil {
+		writeJSON(w, ephemeral("Could not parse the command: %v", err))
+		return
+	}
+	if g.secretTool(cmd.Tool) {
+		writeJSON(w, ephemeral("`%s` returns secrets and is not available from chat, where results are posted to the channel", cmd.Tool))
+		return
+	}
+	if !g.toolAllowed(cmd.Tool) {
+		writeJSON(w, ephemeral("`%s` is not available from chat", cmd.Tool))
+		return
+	}
+
+	g.run(user, role, cmd, form.Get("response_url"), false)
+	writeJSON(w, ephemeral("Running `%s`…", cmd.Tool))
+}
+
+// interaction is the part of a Slack block_actions payload used by the gateway
+type interaction struct {
+	Type string `json:"type"`
+	User struct {
+		ID string `json:"id"`
+	} `json:"user"`
+	ResponseURL string `json:"response_url"`
+	Actions     []struct {
+		ActionID string `json:"action_id"`
+		Value    string `json:"value"`
+	} `json:"actions"`
+}
+
+// handleInteraction handles clicks on the buttons attached to results
+func (g *SlackGateway) handleInteraction(w http.ResponseWriter, r *http.Request) {
+	form, ok := g.verifiedForm(w, r)
+	if !ok {
+		return
+	}
+
+	var payload interaction
+	if err := json.Unmarshal([]byte(form.Get("payload")), &payload); err != nil {
+		http.Error(w, "invalid payload", http.StatusBadRequest)
+		return
+	}
+
+	// Slack only needs an acknowledgement; replies go to the response_url
+	w.WriteHeader(http.StatusOK)
+
+	if payload.Type != "block_actions" || len(payload.Actions) == 0 {
+		return
+	}
+	action := payload.Actions[0]
+	user := payload.User.ID
+
+	if action.ActionID == actionCancel {
+		g.respond(payload.ResponseURL, Message{ReplaceOriginal: true, Text: fmt.Sprintf("Cancelled by <@%s>", user)})
+		return
+	}
+	if !strings.HasPrefix(action.ActionID, actionRun) {
+		return
+	}
+
+	role, allowed := g.role(user)
+	if !allowed {
+		g.respond(payload.ResponseURL, ephemeral("You are not allowed to run tools from chat."))
+		return
+	}
+
+	var value buttonValue
+	if err := json.Unmarshal([]byte(action.Value), &value); err != nil {
+		g.respond(payload.ResponseURL, ephemeral("This button is no longer valid."))
+		return
+	}
+	cmd := Command{Tool: value.Tool, Arguments: value.Arguments}
+	if value.Confirmation != "" {
+		cmd, ok = g.pending.take(value.Confirmation, time.Now())
+		if !ok {
+			g.respond(payload.ResponseURL, ephemeral("This confirmation has expired or was already used; run the command again."))
+			return
+		}
+	}
+	if cmd.Tool == "" {
+		g.respond(payload.ResponseURL, ephemeral("This button is no longer valid."))
+		return
+	}
+	if !g.toolAllowed(cmd.Tool) {
+		g.respond(payload.ResponseURL, ephemeral("`%s` is not available from chat", cmd.Tool))
+		return
+	}
+
+	g.run(user, role, cmd, payload.ResponseURL, true)
+}
+
+// run calls the tool in the background and posts the result to responseURL
+func (g *SlackGateway) run(user, role string, cmd Command, responseURL string, replace bool) {
+	g.logger.WithFields(logrus.Fields{
+		"slack_user": user,
+		"role":       role,
+		"tool":       cmd.Tool,
+	}).Info("Running tool from chat")
+
+	g.wg.Add(1)
+	go func() {
+		defer g.wg.Done()
+
+		var msg Message
+		result, err := g.call(context.Background(), user, role, cmd.Tool, cmd.Arguments)
+		if err != nil {
+			msg = Message{ResponseType: "in_channel", Text: fmt.Sprintf("<@%s> ran `%s`: :x: %v", user, cmd.Tool, err)}
+		} else {
+			msg = resultMessage(user, cmd, result, &g.pending)
+		}
+		msg.ReplaceOriginal = replace
+
+		g.respond(responseURL, msg)
+	}()
+}
+
+// respond posts a message to a Slack response_url
+func (g *SlackGateway) respond(responseURL string, msg Message) {
+	if responseURL == "" {
+		return
+	}
+
+	body, err := json.Marshal(msg)
+	if err != nil {
+		g.logger.WithError(err).Error("Failed to marshal Slack message")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, responseURL, bytes.NewReader(body))
+	if err != nil {
+		g.logger.WithError(err).Error("Failed to create Slack response")
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := g.client.Do(req)
+	if err != nil {
+		g.logger.WithError(err).Warn("Failed to post result to Slack")
+		return
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		g.logger.WithField("status", resp.Status).Warn("Slack rejected the result message")
+	}
+}
+
+// verifiedForm reads and authenticates a Slack request. Slack signs
+// "v0:<timestamp>:<body>" with the app's signing secret.
+func (g *SlackGateway) verifiedForm(w http.ResponseWriter, r *http.Request) (url.Values, bool) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxBodySize))
+	if err != nil {
+		http.Error(w, "failed to read request", http.StatusBadRequest)
+		return nil, false
+	}
+
+	timestamp := r.Header.Get("X-Slack-Request-Timestamp")
+	signature := r.Header.Get("X-Slack-Signature")
+	if err := VerifySlackSignature(g.config.SigningSecret, timestamp, body, signature, time.Now()); err != nil {
+		g.logger.WithError(err).Warn("Rejected unsigned or stale Slack request")
+		http.Error(w, "invalid signature", http.StatusUnauthorized)
+		return nil, false
+	}
+
+	form, err := url.ParseQuery(string(body))
+	if err != nil {
+		http.Error(w, "invalid form", http.StatusBadRequest)
+		return nil, false
+	}
+	return form, true
+}
+
+// VerifySlackSignature checks the X-Slack-Signature of a request body
+func VerifySlackSignature(secret, timestamp string, body []byte, signature string, now time.Time) error {
+	if secret == "" {
+		return errors.New("no signing secret configured")
+	}
+
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid timestamp %q", timestamp)
+	}
+	age := now.Sub(time.Unix(seconds, 0))
+	if age > maxRequestAge || age < -maxRequestAge {
+		return fmt.Errorf("request timestamp is %s away from now", age.Round(time.Second))
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "v0:%s:", timestamp)
+	mac.Write(body)
+	expected := "v0=" + hex.EncodeToString(mac.Sum(nil))
+
+	if !hmac.Equal([]byte(signature), []byte(expected)) {
+		return errors.New("signature mismatch")
+	}
+	return nil
+}
+
+// role maps a Slack user to a role. Users not listed get the default role, and
+// are refused when no default role is configured. Viper lowercases map keys,
+// so user IDs are compared case-insensitively.
+func (g *SlackGateway) role(user string) (string, bool) {
+	if user == "" {
+		return "", false
+	}
+	for id, role := range g.config.Users {
+		if strings.EqualFold(id, user) {
+			return role, role != ""
+		}
+	}
+	return g.config.DefaultRole, g.config.DefaultRole != ""
+}
+
+// toolAllowed reports whether a tool may be run from chat
+func (g *SlackGateway) toolAllowed(tool string) bool {
+	return !g.secretTool(tool) && slices.Contains(g.allowedTools(), tool)
+}
+
+// allowedTools returns chatops.tools, or the policy's default tools when it
+// is empty
+func (g *SlackGateway) allowedTools() []string {
+	if len(g.config.Tools) == 0 {
+		return g.policy.DefaultTools
+	}
+	return g.config.Tools
+}
+
+// secretTool reports whether a tool returns secrets
+func (g *SlackGateway) secretTool(tool string) bool {
+	return slices.Contains(g.policy.SecretTools, tool)
+}
+
+// help describes the command syntax and the tools available from chat
+func (g *SlackGateway) help(command string) Message {
+	if command == "" {
+		command = "/aiops"
+	}
+
+	tools := "no tools"
+	var allowed []string
+	for _, tool := range g.allowedTools() {
+		if !g.secretTool(tool) {
+			allowed = append(allowed, tool)
+		}
+	}
+	if len(allowed) > 0 {
+		sort.Strings(allowed)
+		tools = "`" + strings.Join(allowed, "`, `") + "`"
+	}
+
+	return ephemeral("Usage: `%s <tool> key=value ...`, e.g. `%s stop-ec2-instance instanceId=i-0abc123`\nAvailable: %s",
+		command, command, tools)
+}
+
+// writeJSON writes an immediate reply to Slack
+func writeJSON(w http.ResponseWriter, msg Message) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(msg)
+}
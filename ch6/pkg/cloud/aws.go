@@ -0,0 +1,107 @@
+package cloud
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"aws-mcp-server/pkg/aws"
+	"aws-mcp-server/pkg/types"
+)
+
+// AWSProvider serves EC2 instances through the server's AWS client
+type AWSProvider struct {
+	client *aws.Client
+}
+
+// NewAWSProvider creates a provider on top of the server's AWS client
+func NewAWSProvider(client *aws.Client) *AWSProvider {
+	return &AWSProvider{client: client}
+}
+
+// Name returns the provider name
+func (p *AWSProvider) Name() string {
+	return aws.ProviderName
+}
+
+// Service returns the compute service name used in URIs
+func (p *AWSProvider) Service() string {
+	return "ec2"
+}
+
+// Label returns the instance label used in messages
+func (p *AWSProvider) Label() string {
+	return "EC2"
+}
+
+// ListInstances returns all EC2 instances in the region
+func (p *AWSProvider) ListInstances(ctx context.Context) ([]types.CloudResource, error) {
+	return p.client.ListEC2Instances(ctx)
+}
+
+// ListInstancesPage returns one page of the EC2 instances in the region,
+// continuing at the EC2 NextToken of the previous page
+func (p *AWSProvider) ListInstancesPage(ctx context.Context, token string, size int) ([]types.CloudResource, string, error) {
+	return p.client.ListEC2InstancesPage(ctx, token, size)
+}
+
+// GetInstance returns one EC2 instance
+func (p *AWSProvider) GetInstance(ctx context.Context, instanceID string) (*types.CloudResource, error) {
+	return p.client.GetEC2Instance(ctx, instanceID)
+}
+
+// StartInstance starts a stopped EC2 instance
+func (p *AWSProvider) StartInstance(ctx context.Context, instanceID string) error {
+	return p.client.StartEC2Instance(ctx, instanceID)
+}
+
+// StopInstance stops a running EC2 instance
+func (p *AWSProvider) StopInstance(ctx context.Context, instanceID string) error {
+	return p.client.StopEC2Instance(ctx, instanceID)
+}
+
+// ListChangedInstances returns the EC2 instances CloudTrail recorded changes
+// to since the snapshot was taken, along with the snapshot's instances that
+// were still changing state then, e.g. pending or stopping. Instances that
+// were asked for but are no longer described are gone.
+func (p *AWSProvider) ListChangedInstances(ctx context.Context, snapshot []types.CloudResource, since time.Time) ([]types.CloudResource, []string, error) {
+	instanceIDs, err := p.client.ChangedEC2InstanceIDs(ctx, since)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, instance := range snapshot {
+		if transitioning(instance.State) && !slices.Contains(instanceIDs, instance.ID) {
+			instanceIDs = append(instanceIDs, instance.ID)
+		}
+	}
+	if len(instanceIDs) == 0 {
+		return nil, nil, nil
+	}
+
+	changed, err := p.client.DescribeEC2Instances(ctx, instanceIDs)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	described := make(map[string]bool, len(changed))
+	for _, instance := range changed {
+		described[instance.ID] = true
+	}
+	var gone []string
+	for _, id := range instanceIDs {
+		if !described[id] {
+			gone = append(gone, id)
+		}
+	}
+	return changed, gone, nil
+}
+
+// transitioning reports whether an EC2 instance state is on its way to
+// another one
+func transitioning(state string) bool {
+	switch state {
+	case "pending", "stopping", "shutting-down":
+		return true
+	}
+	return false
+}
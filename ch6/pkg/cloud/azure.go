@@ -0,0 +1,53 @@
+package cloud
+
+import (
+	"context"
+
+	"aws-mcp-server/pkg/azure"
+	"aws-mcp-server/pkg/types"
+)
+
+// AzureProvider serves the virtual machines of one subscription
+type AzureProvider struct {
+	client *azure.Client
+}
+
+// NewAzureProvider creates a provider on top of a Resource Manager client
+func NewAzureProvider(client *azure.Client) *AzureProvider {
+	return &AzureProvider{client: client}
+}
+
+// Name returns the provider name
+func (p *AzureProvider) Name() string {
+	return azure.ProviderName
+}
+
+// Service returns the compute service name used in URIs
+func (p *AzureProvider) Service() string {
+	return "vm"
+}
+
+// Label returns the instance label used in messages
+func (p *AzureProvider) Label() string {
+	return "Azure VM"
+}
+
+// ListInstances returns all VMs of the subscription
+func (p *AzureProvider) ListInstances(ctx context.Context) ([]types.CloudResource, error) {
+	return p.client.ListInstances(ctx)
+}
+
+// GetInstance returns one VM by name
+func (p *AzureProvider) GetInstance(ctx context.Context, instanceID string) (*types.CloudResource, error) {
+	return p.client.GetInstance(ctx, instanceID)
+}
+
+// StartInstance starts a stopped or deallocated VM
+func (p *AzureProvider) StartInstance(ctx context.Context, instanceID string) error {
+	return p.client.StartInstance(ctx, instanceID)
+}
+
+// StopInstance deallocates a running VM
+func (p *AzureProvider) StopInstance(ctx context.Context, instanceID string) error {
+	return p.client.StopInstance(ctx, instanceID)
+}
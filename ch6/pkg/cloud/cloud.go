@@ -0,0 +1,98 @@
+package cloud
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"aws-mcp-server/pkg/types"
+)
+
+// Provider exposes the compute instances of one cloud to the resource and
+// tool handlers, which stay the same whichever cloud serves them
+type Provider interface {
+	// Name is the provider's URI scheme, e.g. aws
+	Name() string
+	// Service names the compute service in resource URIs, e.g. ec2
+	Service() string
+	// Label names the instances in messages, e.g. EC2
+	Label() string
+
+	ListInstances(ctx context.Context) ([]types.CloudResource, error)
+	GetInstance(ctx context.Context, instanceID string) (*types.CloudResource, error)
+	StartInstance(ctx context.Context, instanceID string) error
+	StopInstance(ctx context.Context, instanceID string) error
+}
+
+// DeltaProvider is a Provider that can list just the instances that changed
+// since a snapshot of its instances was taken, so refreshing the snapshot of
+// a large fleet does not list all of it. Changed holds new and updated
+// instances and gone the IDs of snapshot instances that no longer exist.
+type DeltaProvider interface {
+	ListChangedInstances(ctx context.Context, snapshot []types.CloudResource, since time.Time) (changed []types.CloudResource, gone []string, err error)
+}
+
+// PagedProvider is a Provider that can list its instances a page at a time,
+// so reading a large fleet does not list all of it at once. The token
+// continues where the previous page ended and is empty for the first page;
+// next is empty after the last page. Pages may hold a few more or fewer
+// instances than size.
+type PagedProvider interface {
+	ListInstancesPage(ctx context.Context, token string, size int) (instances []types.CloudResource, next string, err error)
+}
+
+// InstancesURI returns the resource URI listing a provider's instances, such
+// as aws://ec2/instances; single instances live below it
+func InstancesURI(p Provider) string {
+	return p.Name() + "://" + p.Service() + "/instances"
+}
+
+// Registry holds the configured providers in registration order. A nil
+// Registry has no providers.
+type Registry struct {
+	providers []Provider
+}
+
+// NewRegistry creates a registry of the given providers, skipping nil ones
+func NewRegistry(providers ...Provider) *Registry {
+	r := &Registry{}
+	for _, p := range providers {
+		if p != nil {
+			r.providers = append(r.providers, p)
+		}
+	}
+	return r
+}
+
+// Providers returns the registered providers
+func (r *Registry) Providers() []Provider {
+	if r == nil {
+		return nil
+	}
+	return r.providers
+}
+
+// Get returns the provider with the given name, or nil when it is not registered
+func (r *Registry) Get(name string) Provider {
+	for _, p := range r.Providers() {
+		if p.Name() == name {
+			return p
+		}
+	}
+	return nil
+}
+
+// Resolve finds the provider serving an instances URI. The instance ID is
+// empty for the list URI itself.
+func (r *Registry) Resolve(uri string) (Provider, string, bool) {
+	for _, p := range r.Providers() {
+		base := InstancesURI(p)
+		if uri == base {
+			return p, "", true
+		}
+		if instanceID, ok := strings.CutPrefix(uri, base+"/"); ok && instanceID != "" && !strings.Contains(instanceID, "/") {
+			return p, instanceID, true
+		}
+	}
+	return nil, "", false
+}
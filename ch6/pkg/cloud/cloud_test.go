@@ -0,0 +1,57 @@
+package cloud
+
+import (
+	"context"
+	"testing"
+
+	"aws-mcp-server/pkg/types"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type stubProvider struct {
+	name    string
+	service string
+}
+
+func (p stubProvider) Name() string    { return p.name }
+func (p stubProvider) Service() string { return p.service }
+func (p stubProvider) Label() string   { return p.name }
+
+func (p stubProvider) ListInstances(context.Context) ([]types.CloudResource, error) { return nil, nil }
+func (p stubProvider) GetInstance(context.Context, string) (*types.CloudResource, error) {
+	return nil, nil
+}
+func (p stubProvider) StartInstance(context.Context, string) error { return nil }
+func (p stubProvider) StopInstance(context.Context, string) error  { return nil }
+
+func TestRegistryResolve(t *testing.T) {
+	aws := stubProvider{name: "aws", service: "ec2"}
+	gcp := stubProvider{name: "gcp", service: "compute"}
+	registry := NewRegistry(aws, nil, gcp)
+
+	assert.Len(t, registry.Providers(), 2)
+	assert.Equal(t, "aws://ec2/instances", InstancesURI(aws))
+	assert.Equal(t, gcp, registry.Get("gcp"))
+	assert.Nil(t, registry.Get("azure"))
+
+	provider, instanceID, ok := registry.Resolve("aws://ec2/instances")
+	assert.True(t, ok)
+	assert.Equal(t, aws, provider)
+	assert.Empty(t, instanceID)
+
+	provider, instanceID, ok = registry.Resolve("gcp://compute/instances/web-1")
+	assert.True(t, ok)
+	assert.Equal(t, gcp, provider)
+	assert.Equal(t, "web-1", instanceID)
+
+	for _, uri := range []string{"aws://ec2/instances/", "aws://ec2/instances/i-1/tags", "aws://rds/instances", "gcp://compute"} {
+		_, _, ok = registry.Resolve(uri)
+		assert.False(t, ok, uri)
+	}
+
+	var empty *Registry
+	assert.Nil(t, empty.Get("aws"))
+	_, _, ok = empty.Resolve("aws://ec2/instances")
+	assert.False(t, ok)
+}
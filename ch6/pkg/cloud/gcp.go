@@ -0,0 +1,53 @@
+package cloud
+
+import (
+	"context"
+
+	"aws-mcp-server/pkg/gcp"
+	"aws-mcp-server/pkg/types"
+)
+
+// GCPProvider serves Compute Engine instances of one project
+type GCPProvider struct {
+	client *gcp.Client
+}
+
+// NewGCPProvider creates a provider on top of a Compute Engine client
+func NewGCPProvider(client *gcp.Client) *GCPProvider {
+	return &GCPProvider{client: client}
+}
+
+// Name returns the provider name
+func (p *GCPProvider) Name() string {
+	return gcp.ProviderName
+}
+
+// Service returns the compute service name used in URIs
+func (p *GCPProvider) Service() string {
+	return "compute"
+}
+
+// Label returns the instance label used in messages
+func (p *GCPProvider) Label() string {
+	return "Compute Engine"
+}
+
+// ListInstances returns all instances of the project
+func (p *GCPProvider) ListInstances(ctx context.Context) ([]types.CloudResource, error) {
+	return p.client.ListInstances(ctx)
+}
+
+// GetInstance returns one instance by name
+func (p *GCPProvider) GetInstance(ctx context.Context, instanceID string) (*types.CloudResource, error) {
+	return p.client.GetInstance(ctx, instanceID)
+}
+
+// StartInstance starts a stopped instance
+func (p *GCPProvider) StartInstance(ctx context.Context, instanceID string) error {
+	return p.client.StartInstance(ctx, instanceID)
+}
+
+// StopInstance stops a running instance
+func (p *GCPProvider) StopInstance(ctx context.Context, instanceID string) error {
+	return p.client.StopInstance(ctx, instanceID)
+}
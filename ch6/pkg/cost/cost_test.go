@@ -0,0 +1,100 @@
+package cost
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEstimateEC2(t *testing.T) {
+	estimate := EstimateEC2("t3.micro", nil)
+	assert.True(t, estimate.Known)
+	assert.InDelta(t, 7.592, estimate.MonthlyUSD(), 0.001)
+
+	estimate = EstimateEC2("t3.micro", map[string]float64{"t3.micro": 0.02})
+	assert.Equal(t, 0.02, estimate.HourlyUSD)
+
+	assert.False(t, EstimateEC2("x99.mega", nil).Known)
+}
+
+func TestEBSVolumeMonthlyUSD(t *testing.T) {
+	monthly, known := EBSVolumeMonthlyUSD("gp3", 100, 3000, 125)
+	assert.True(t, known)
+	assert.InDelta(t, 8.0, monthly, 0.001)
+
+	monthly, _ = EBSVolumeMonthlyUSD("gp3", 100, 4000, 250)
+	assert.InDelta(t, 8.0+5.0+5.0, monthly, 0.001)
+
+	monthly, _ = EBSVolumeMonthlyUSD("io2", 10, 1000, 0)
+	assert.InDelta(t, 1.25+65.0, monthly, 0.001)
+
+	_, known = EBSVolumeMonthlyUSD("gp9", 10, 0, 0)
+	assert.False(t, known)
+}
+
+func TestLoadBalancerMonthlyUSD(t *testing.T) {
+	monthly, known := LoadBalancerMonthlyUSD("application")
+	assert.True(t, known)
+	assert.InDelta(t, 16.425, monthly, 0.001)
+
+	_, known = LoadBalancerMonthlyUSD("classic")
+	assert.False(t, known)
+}
+
+func TestGuardrailPerAction(t *testing.T) {
+	g := NewGuardrail(Limits{MaxHourlyPerAction: 0.1, MaxMonthlyPerAction: 50}, nil)
+
+	assert.Nil(t, g.Check(EstimateEC2("t3.small", nil)))
+
+	violation := g.Check(EstimateEC2("m5.xlarge", nil))
+	require.NotNil(t, violation)
+	assert.Len(t, violation.Reasons, 2)
+
+	violation = g.Check(EstimateEC2("x99.mega", nil))
+	require.NotNil(t, violation)
+	assert.Contains(t, violation.Reasons[0], "no price is known")
+}
+
+func TestGuardrailDailyLimit(t *testing.T) {
+	now := time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC)
+	g := NewGuardrail(Limits{MaxMonthlyPerDay: 100}, nil)
+	g.now = func() time.Time { return now }
+
+	medium := EstimateEC2("t3.large", nil) // about $60.74/month
+	require.Nil(t, g.Check(medium))
+	g.Record(medium)
+
+	violation := g.Check(medium)
+	require.NotNil(t, violation)
+	assert.Equal(t, 60.74, violation.CreatedTodayUSD)
+
+	// The daily total resets on the next day
+	now = now.Add(24 * time.Hour)
+	assert.Nil(t, g.Check(medium))
+}
+
+func TestGuardrailDayFollowsLocation(t *testing.T) {
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	require.NoError(t, err)
+	// 23:30 in Tokyo, 14:30 UTC
+	now := time.Date(2025, 6, 1, 23, 30, 0, 0, tokyo)
+	g := NewGuardrail(Limits{MaxMonthlyPerDay: 100}, tokyo)
+	g.now = func() time.Time { return now.UTC() }
+
+	medium := EstimateEC2("t3.large", nil) // about $60.74/month
+	g.Record(medium)
+	require.NotNil(t, g.Check(medium))
+
+	// Midnight in Tokyo starts a new day, though it is still June 1 in UTC
+	now = now.Add(time.Hour)
+	require.Equal(t, 1, now.UTC().Day())
+	assert.Nil(t, g.Check(medium))
+}
+
+func TestGuardrailDisabled(t *testing.T) {
+	g := NewGuardrail(Limits{}, nil)
+	assert.False(t, g.Enabled())
+	assert.Nil(t, g.Check(EstimateEC2("x99.mega", nil)))
+}
@@ -0,0 +1,136 @@
+package cost
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Limits are the configured spending limits. A zero limit is disabled.
+type Limits struct {
+	MaxHourlyPerAction  float64
+	MaxMonthlyPerAction float64
+	// MaxMonthlyPerDay caps the combined monthly cost of everything created in one day
+	MaxMonthlyPerDay float64
+}
+
+// Violation explains why an estimate was blocked by the guardrail
+type Violation struct {
+	Estimate        Estimate `json:"estimate"`
+	MonthlyUSD      float64  `json:"monthlyUsd"`
+	Reasons         []string `json:"reasons"`
+	CreatedTodayUSD float64  `json:"createdTodayMonthlyUsd"`
+	DailyLimitUSD   float64  `json:"dailyLimitMonthlyUsd,omitempty"`
+	HourlyLimitUSD  float64  `json:"hourlyLimitUsd,omitempty"`
+	MonthlyLimitUSD float64  `json:"monthlyLimitUsd,omitempty"`
+}
+
+// Error implements the error interface
+func (v *Violation) Error() string {
+	return fmt.Sprintf("cost guardrail exceeded for %s: %s", v.Estimate.Resource, strings.Join(v.Reasons, "; "))
+}
+
+// Guardrail checks estimated costs of create actions against the configured limits
+// and tracks the monthly cost of resources created during the current day.
+// Days start at midnight in the guardrail's location.
+type Guardrail struct {
+	limits   Limits
+	location *time.Location
+
+	mu           sync.Mutex
+	day          string
+	createdToday float64
+	now          func() time.Time
+}
+
+// NewGuardrail creates a guardrail with the given limits whose days follow
+// location, e.g. the timezone of response.timezone. A nil location is UTC.
+func NewGuardrail(limits Limits, location *time.Location) *Guardrail {
+	if location == nil {
+		location = time.UTC
+	}
+	return &Guardrail{
+		limits:   limits,
+		location: location,
+		now:      time.Now,
+	}
+}
+
+// Enabled reports whether any limit is configured
+func (g *Guardrail) Enabled() bool {
+	return g.limits.MaxHourlyPerAction > 0 || g.limits.MaxMonthlyPerAction > 0 || g.limits.MaxMonthlyPerDay > 0
+}
+
+// Check returns a violation when the estimate exceeds any limit, or nil when the
+// action may proceed. Unknown prices are blocked while a limit is configured,
+// because the guardrail cannot vouch for them.
+func (g *Guardrail) Check(estimate Estimate) *Violation {
+	if !g.Enabled() {
+		return nil
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.rollover()
+
+	violation := &Violation{
+		Estimate:        estimate,
+		MonthlyUSD:      roundUSD(estimate.MonthlyUSD()),
+		CreatedTodayUSD: roundUSD(g.createdToday),
+		HourlyLimitUSD:  g.limits.MaxHourlyPerAction,
+		MonthlyLimitUSD: g.limits.MaxMonthlyPerAction,
+		DailyLimitUSD:   g.limits.MaxMonthlyPerDay,
+	}
+
+	if !estimate.Known {
+		violation.Reasons = append(violation.Reasons, fmt.Sprintf("no price is known for %s", estimate.Resource))
+		return violation
+	}
+
+	if limit := g.limits.MaxHourlyPerAction; limit > 0 && estimate.HourlyUSD > limit {
+		violation.Reasons = append(violation.Reasons,
+			fmt.Sprintf("estimated $%.4f/hour exceeds the per-action limit of $%.4f/hour", estimate.HourlyUSD, limit))
+	}
+
+	if limit := g.limits.MaxMonthlyPerAction; limit > 0 && estimate.MonthlyUSD() > limit {
+		violation.Reasons = append(violation.Reasons,
+			fmt.Sprintf("estimated $%.2f/month exceeds the per-action limit of $%.2f/month", estimate.MonthlyUSD(), limit))
+	}
+
+	if limit := g.limits.MaxMonthlyPerDay; limit > 0 && g.createdToday+estimate.MonthlyUSD() > limit {
+		violation.Reasons = append(violation.Reasons,
+			fmt.Sprintf("resources created today would total $%.2f/month, above the daily limit of $%.2f/month",
+				g.createdToday+estimate.MonthlyUSD(), limit))
+	}
+
+	if len(violation.Reasons) == 0 {
+		return nil
+	}
+	return violation
+}
+
+// Record adds a created resource to today's total
+func (g *Guardrail) Record(estimate Estimate) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.rollover()
+
+	g.createdToday += estimate.MonthlyUSD()
+}
+
+// rollover resets the daily total when the day changes in the guardrail's
+// location. Callers hold g.mu.
+func (g *Guardrail) rollover() {
+	today := g.now().In(g.location).Format("2006-01-02")
+	if g.day != today {
+		g.day = today
+		g.createdToday = 0
+	}
+}
+
+// roundUSD rounds to cents for reporting
+func roundUSD(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
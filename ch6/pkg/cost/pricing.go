@@ -0,0 +1,144 @@
+package cost
+
+// HoursPerMonth is the average number of hours in a month used by AWS pricing
+const HoursPerMonth = 730
+
+// ec2HourlyPrices are on-demand Linux prices in USD per hour (us-east-1). They are
+// estimates for guardrails, not billing figures; other regions differ slightly.
+var ec2HourlyPrices = map[string]float64{
+	"t2.nano":     0.0058,
+	"t2.micro":    0.0116,
+	"t2.small":    0.023,
+	"t2.medium":   0.0464,
+	"t2.large":    0.0928,
+	"t3.nano":     0.0052,
+	"t3.micro":    0.0104,
+	"t3.small":    0.0208,
+	"t3.medium":   0.0416,
+	"t3.large":    0.0832,
+	"t3.xlarge":   0.1664,
+	"t3.2xlarge":  0.3328,
+	"t3a.micro":   0.0094,
+	"t3a.small":   0.0188,
+	"t3a.medium":  0.0376,
+	"t3a.large":   0.0752,
+	"t4g.micro":   0.0084,
+	"t4g.small":   0.0168,
+	"t4g.medium":  0.0336,
+	"t4g.large":   0.0672,
+	"m5.large":    0.096,
+	"m5.xlarge":   0.192,
+	"m5.2xlarge":  0.384,
+	"m5.4xlarge":  0.768,
+	"m6i.large":   0.096,
+	"m6i.xlarge":  0.192,
+	"m6g.large":   0.077,
+	"m7g.large":   0.0816,
+	"c5.large":    0.085,
+	"c5.xlarge":   0.17,
+	"c5.2xlarge":  0.34,
+	"c6i.large":   0.085,
+	"c6g.large":   0.068,
+	"r5.large":    0.126,
+	"r5.xlarge":   0.252,
+	"r6i.large":   0.126,
+	"r6g.large":   0.1008,
+	"g4dn.xlarge": 0.526,
+	"g5.xlarge":   1.006,
+	"p3.2xlarge":  3.06,
+}
+
+// Estimate is the expected on-demand cost of a resource a tool is about to create
+type Estimate struct {
+	Resource  string  `json:"resource"`
+	HourlyUSD float64 `json:"hourlyUsd"`
+	Known     bool    `json:"known"`
+}
+
+// MonthlyUSD returns the estimated cost of running the resource for a full month
+func (e Estimate) MonthlyUSD() float64 {
+	return e.HourlyUSD * HoursPerMonth
+}
+
+// EstimateEC2 estimates the hourly cost of an instance type. overrides take
+// precedence over the built-in table so operators can add or correct prices.
+func EstimateEC2(instanceType string, overrides map[string]float64) Estimate {
+	estimate := Estimate{Resource: "ec2:" + instanceType}
+
+	if price, exists := overrides[instanceType]; exists {
+		estimate.HourlyUSD, estimate.Known = price, true
+		return estimate
+	}
+
+	if price, exists := ec2HourlyPrices[instanceType]; exists {
+		estimate.HourlyUSD, estimate.Known = price, true
+	}
+
+	return estimate
+}
+
+// ebsMonthlyPricesPerGiB are EBS storage prices in USD per GiB-month (us-east-1)
+var ebsMonthlyPricesPerGiB = map[string]float64{
+	"gp3":      0.08,
+	"gp2":      0.10,
+	"io1":      0.125,
+	"io2":      0.125,
+	"st1":      0.045,
+	"sc1":      0.015,
+	"standard": 0.05,
+}
+
+// Prices of provisioned EBS performance and the other resources that keep
+// billing when nothing uses them, in USD (us-east-1)
+const (
+	// gp3IncludedIOPS and gp3IncludedThroughput come with every gp3 volume
+	gp3IncludedIOPS       = 3000
+	gp3IncludedThroughput = 125
+	gp3IOPSMonthly        = 0.005
+	gp3ThroughputMonthly  = 0.04
+	// provisionedIOPSMonthly is the io1/io2 price per provisioned IOPS-month
+	provisionedIOPSMonthly = 0.065
+	// SnapshotMonthlyPerGiB is the standard tier EBS snapshot price
+	SnapshotMonthlyPerGiB = 0.05
+	// ElasticIPHourly is charged for every public IPv4 address, in use or not
+	ElasticIPHourly = 0.005
+)
+
+// loadBalancerHourlyPrices are ELBv2 prices per hour before capacity units
+var loadBalancerHourlyPrices = map[string]float64{
+	"application": 0.0225,
+	"network":     0.0225,
+	"gateway":     0.0125,
+}
+
+// EBSVolumeMonthlyUSD estimates the monthly cost of a volume from its type,
+// size and provisioned performance. Unknown types return false.
+func EBSVolumeMonthlyUSD(volumeType string, sizeGiB, iops, throughput int32) (float64, bool) {
+	perGiB, exists := ebsMonthlyPricesPerGiB[volumeType]
+	if !exists {
+		return 0, false
+	}
+
+	monthly := perGiB * float64(sizeGiB)
+	switch volumeType {
+	case "gp3":
+		monthly += float64(max(iops-gp3IncludedIOPS, 0)) * gp3IOPSMonthly
+		monthly += float64(max(throughput-gp3IncludedThroughput, 0)) * gp3ThroughputMonthly
+	case "io1", "io2":
+		monthly += float64(iops) * provisionedIOPSMonthly
+	}
+	return monthly, true
+}
+
+// SnapshotMonthlyUSD estimates the monthly cost of snapshots holding sizeGiB.
+// Snapshots are incremental, so for a chain of snapshots this is an upper bound.
+func SnapshotMonthlyUSD(sizeGiB int32) float64 {
+	return SnapshotMonthlyPerGiB * float64(sizeGiB)
+}
+
+// LoadBalancerMonthlyUSD estimates the fixed monthly cost of an ELBv2 load
+// balancer of a type, excluding capacity units. Unknown types return false.
+func LoadBalancerMonthlyUSD(lbType string) (float64, bool) {
+	hourly, exists := loadBalancerHourlyPrices[lbType]
+	return hourly * HoursPerMonth, exists
+}
@@ -0,0 +1,140 @@
+package elevation
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Grant gives a read-only caller write access until ExpiresAt, like a
+// break-glass account that is handed out for a fixed time. Holder is the
+// session or person it was granted to; other callers with the same role
+// stay read-only.
+type Grant struct {
+	ID         string    `json:"id"`
+	Holder     string    `json:"holder"`
+	Role       string    `json:"role"`
+	Reason     string    `json:"reason"`
+	ApprovedBy string    `json:"approvedBy"`
+	GrantedAt  time.Time `json:"grantedAt"`
+	ExpiresAt  time.Time `json:"expiresAt"`
+}
+
+// Expired reports whether the grant no longer applies at now
+func (g Grant) Expired(now time.Time) bool {
+	return !now.Before(g.ExpiresAt)
+}
+
+// Registry holds the grants in effect, one per holder. Grants are kept in
+// memory only, so a restart reverts every caller to read-only.
+type Registry struct {
+	mu     sync.Mutex
+	nextID int
+	grants map[string]Grant
+}
+
+// NewRegistry creates a registry without grants
+func NewRegistry() *Registry {
+	return &Registry{grants: make(map[string]Grant)}
+}
+
+// Grant elevates a holder with the given role from now for the given
+// duration, replacing any earlier grant for the holder
+func (r *Registry) Grant(holder, role, reason, approvedBy string, now time.Time, duration time.Duration) Grant {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.nextID++
+	grant := Grant{
+		ID:         fmt.Sprintf("elev-%d", r.nextID),
+		Holder:     holder,
+		Role:       role,
+		Reason:     reason,
+		ApprovedBy: approvedBy,
+		GrantedAt:  now,
+		ExpiresAt:  now.Add(duration),
+	}
+	r.grants[holder] = grant
+	return grant
+}
+
+// Active returns the grant of a holder if it applies at now
+func (r *Registry) Active(holder string, now time.Time) (Grant, bool) {
+	if r == nil {
+		return Grant{}, false
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	grant, exists := r.grants[holder]
+	if !exists || grant.Expired(now) {
+		return Grant{}, false
+	}
+	return grant, true
+}
+
+// End removes the grant of a holder before it expires and returns it.
+// Grants of other holders with the same role are left in effect.
+func (r *Registry) End(holder string) (Grant, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	grant, exists := r.grants[holder]
+	if !exists {
+		return Grant{}, fmt.Errorf("%s is not elevated", holder)
+	}
+	delete(r.grants, holder)
+	return grant, nil
+}
+
+// Revoke removes the grant with the given ID before it expires and returns it
+func (r *Registry) Revoke(id string) (Grant, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for holder, grant := range r.grants {
+		if grant.ID == id {
+			delete(r.grants, holder)
+			return grant, nil
+		}
+	}
+	return Grant{}, fmt.Errorf("elevation %s is not in effect", id)
+}
+
+// Expire removes the grant with the given ID once it has expired at now and
+// reports whether it did. A grant that was ended or replaced is left alone.
+func (r *Registry) Expire(id string, now time.Time) (Grant, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for holder, grant := range r.grants {
+		if grant.ID == id && grant.Expired(now) {
+			delete(r.grants, holder)
+			return grant, true
+		}
+	}
+	return Grant{}, false
+}
+
+// All returns the grants that apply at now, soonest to expire first
+func (r *Registry) All(now time.Time) []Grant {
+	if r == nil {
+		return nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	grants := make([]Grant, 0, len(r.grants))
+	for _, grant := range r.grants {
+		if !grant.Expired(now) {
+			grants = append(grants, grant)
+		}
+	}
+	sort.Slice(grants, func(i, j int) bool {
+		return grants[i].ExpiresAt.Before(grants[j].ExpiresAt)
+	})
+	return grants
+}
@@ -0,0 +1,70 @@
+package elevation
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistry(t *testing.T) {
+	r := NewRegistry()
+	now := time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC)
+
+	first := r.Grant("websocket-1", "viewer", "restart stuck workers", "admin", now, 30*time.Minute)
+	assert.Equal(t, "elev-1", first.ID)
+
+	grant, ok := r.Active("websocket-1", now.Add(29*time.Minute))
+	require.True(t, ok)
+	assert.Equal(t, "admin", grant.ApprovedBy)
+	assert.Equal(t, "websocket-1", grant.Holder)
+	_, ok = r.Active("websocket-1", now.Add(30*time.Minute))
+	assert.False(t, ok, "grants end when they expire")
+
+	second := r.Grant("websocket-1", "viewer", "still stuck", "admin", now.Add(20*time.Minute), time.Hour)
+	_, expired := r.Expire(first.ID, now.Add(time.Hour))
+	assert.False(t, expired, "a replaced grant does not expire its successor")
+	require.Len(t, r.All(now.Add(time.Hour)), 1)
+
+	_, expired = r.Expire(second.ID, now.Add(2*time.Hour))
+	assert.True(t, expired)
+	assert.Empty(t, r.All(now))
+
+	r.Grant("websocket-1", "viewer", "one more", "admin", now, time.Hour)
+	ended, err := r.End("websocket-1")
+	require.NoError(t, err)
+	assert.Equal(t, "elev-3", ended.ID)
+	_, err = r.End("websocket-1")
+	assert.EqualError(t, err, "websocket-1 is not elevated")
+
+	fourth := r.Grant("slack:U123", "operator", "rotate keys", "admin", now, time.Hour)
+	revoked, err := r.Revoke(fourth.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "operator", revoked.Role)
+	_, ok = r.Active("slack:U123", now)
+	assert.False(t, ok, "revoked grants end at once")
+	_, err = r.Revoke(fourth.ID)
+	assert.EqualError(t, err, "elevation elev-4 is not in effect")
+
+	var none *Registry
+	_, ok = none.Active("websocket-1", now)
+	assert.False(t, ok)
+}
+
+func TestGrantsBelongToTheirHolder(t *testing.T) {
+	r := NewRegistry()
+	now := time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC)
+
+	alice := r.Grant("websocket-1", "viewer", "restart stuck workers", "admin", now, time.Hour)
+	_, ok := r.Active("websocket-2", now)
+	assert.False(t, ok, "another session with the same role is not elevated")
+
+	bob := r.Grant("websocket-2", "viewer", "rotate keys", "admin", now, time.Hour)
+	ended, err := r.End("websocket-2")
+	require.NoError(t, err)
+	assert.Equal(t, bob.ID, ended.ID)
+	grant, ok := r.Active("websocket-1", now)
+	require.True(t, ok, "ending one grant leaves the others of the role")
+	assert.Equal(t, alice.ID, grant.ID)
+}
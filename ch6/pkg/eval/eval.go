@@ -0,0 +1,264 @@
+// Package eval scores how an AI agent worked an incident. It reads the MCP
+// session the server recorded while the agent ran a scenario and compares
+// the agent's actions with the scenario's expected remediation: how long it
+// took to look at the problem, which expected steps it carried out, how many
+// of its calls failed and which destructive calls it made without need.
+package eval
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"aws-mcp-server/pkg/session"
+)
+
+// Scenario is an incident with the actions that detect and remediate it
+type Scenario struct {
+	Name        string `yaml:"name"`
+	Description string `yaml:"description"`
+	// Detect are the actions that show the agent looked at the problem, e.g.
+	// reading the firing alarm or the instance's metrics
+	Detect []Action `yaml:"detect"`
+	// DetectWithin is the time from the start of the session by which the
+	// agent should have detected the problem; zero accepts any time
+	DetectWithin time.Duration `yaml:"detect_within"`
+	// Steps are the expected remediation, in order
+	Steps []Action `yaml:"steps"`
+	// Allowed are destructive calls outside the remediation that are still
+	// acceptable, such as snapshotting a volume before changing it
+	Allowed []Action `yaml:"allowed"`
+}
+
+// Action is a tool call or a resource read. A tool call matches when the
+// tool is the same and the call has every argument listed; a read matches
+// the URI, or the URI prefix when it ends in *.
+type Action struct {
+	Tool      string                 `yaml:"tool,omitempty" json:"tool,omitempty"`
+	Resource  string                 `yaml:"resource,omitempty" json:"resource,omitempty"`
+	Arguments map[string]interface{} `yaml:"arguments,omitempty" json:"arguments,omitempty"`
+}
+
+// String describes the action for reports
+func (a Action) String() string {
+	if a.Resource != "" {
+		return "read " + a.Resource
+	}
+	if len(a.Arguments) == 0 {
+		return a.Tool
+	}
+	arguments, _ := json.Marshal(a.Arguments)
+	return fmt.Sprintf("%s %s", a.Tool, arguments)
+}
+
+// Destructive reports whether a tool call changes infrastructure
+type Destructive func(tool string, arguments map[string]interface{}) bool
+
+// LoadScenario reads a scenario from a YAML file
+func LoadScenario(path string) (*Scenario, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read scenario: %w", err)
+	}
+
+	var scenario Scenario
+	if err := yaml.Unmarshal(data, &scenario); err != nil {
+		return nil, fmt.Errorf("failed to parse scenario: %w", err)
+	}
+	if len(scenario.Detect) == 0 && len(scenario.Steps) == 0 {
+		return nil, fmt.Errorf("scenario %s has neither detect actions nor steps", path)
+	}
+	for i, action := range append(append(append([]Action(nil), scenario.Detect...), scenario.Steps...), scenario.Allowed...) {
+		if (action.Tool == "") == (action.Resource == "") {
+			return nil, fmt.Errorf("action %d of scenario %s needs either a tool or a resource", i+1, path)
+		}
+	}
+	return &scenario, nil
+}
+
+// call is an action of the agent in the recorded session
+type call struct {
+	seq       int
+	at        time.Time
+	tool      string
+	resource  string
+	arguments map[string]interface{}
+	failed    bool
+}
+
+// String describes the call for reports
+func (c call) String() string {
+	return Action{Tool: c.tool, Resource: c.resource, Arguments: c.arguments}.String()
+}
+
+// Evaluate scores the recorded session against the scenario. The session
+// starts with its first entry, usually the client's initialize request.
+func Evaluate(scenario *Scenario, entries []session.Entry, destructive Destructive) *Report {
+	report := &Report{Scenario: scenario.Name, DetectWithin: scenario.DetectWithin}
+	calls := agentCalls(entries)
+	var started time.Time
+	if len(entries) > 0 {
+		started = entries[0].Timestamp
+		report.Duration = entries[len(entries)-1].Timestamp.Sub(started)
+	}
+
+	for _, c := range calls {
+		if c.tool != "" {
+			report.ToolCalls++
+			if c.failed {
+				report.FailedCalls++
+			}
+		}
+		if !report.Detected && matchesAny(scenario.Detect, c) {
+			report.Detected = true
+			report.TimeToDetect = c.at.Sub(started)
+			report.DetectedBy = c.String()
+		}
+	}
+
+	// Each step is done by the agent's first successful matching call
+	used := make(map[int]bool)
+	last := -1
+	for _, step := range scenario.Steps {
+		result := StepResult{Action: step}
+		for i, c := range calls {
+			if !used[i] && !c.failed && step.matches(c) {
+				used[i] = true
+				result.Done = true
+				result.Seq = c.seq
+				result.OutOfOrder = i < last
+				last = max(last, i)
+				break
+			}
+		}
+		report.Steps = append(report.Steps, result)
+	}
+
+	for i, c := range calls {
+		if c.tool == "" || used[i] || !destructive(c.tool, c.arguments) || matchesAny(scenario.Allowed, c) {
+			continue
+		}
+		report.Unnecessary = append(report.Unnecessary, Finding{Seq: c.seq, Action: c.String(), Failed: c.failed})
+	}
+
+	report.score(scenario)
+	return report
+}
+
+// agentCalls extracts the tool calls and resource reads of a session
+func agentCalls(entries []session.Entry) []call {
+	var calls []call
+	for _, entry := range entries {
+		var request struct {
+			Method string `json:"method"`
+			Params struct {
+				Name      string                 `json:"name"`
+				Arguments map[string]interface{} `json:"arguments"`
+				URI       string                 `json:"uri"`
+			} `json:"params"`
+		}
+		if err := json.Unmarshal(entry.Request, &request); err != nil {
+			continue
+		}
+
+		c := call{seq: entry.Seq, at: entry.Timestamp, failed: failed(entry.Response)}
+		switch request.Method {
+		case "tools/call":
+			c.tool, c.arguments = request.Params.Name, request.Params.Arguments
+		case "resources/read":
+			c.resource = request.Params.URI
+		default:
+			continue
+		}
+		calls = append(calls, c)
+	}
+	return calls
+}
+
+// matches reports whether the agent's call is the action
+func (a Action) matches(c call) bool {
+	if a.Resource != "" {
+		if prefix, ok := strings.CutSuffix(a.Resource, "*"); ok {
+			return c.resource != "" && strings.HasPrefix(c.resource, prefix)
+		}
+		return c.resource == a.Resource
+	}
+	if c.tool != a.Tool {
+		return false
+	}
+	for name, expected := range a.Arguments {
+		actual, ok := c.arguments[name]
+		if !ok || !sameValue(expected, actual) {
+			return false
+		}
+	}
+	return true
+}
+
+// matchesAny reports whether the call is any of the actions
+func matchesAny(actions []Action, c call) bool {
+	for _, action := range actions {
+		if action.matches(c) {
+			return true
+		}
+	}
+	return false
+}
+
+// sameValue compares a scenario argument with a call argument. Objects
+// match when the call has every expected key; other values are compared as
+// text, since YAML and JSON decode numbers differently.
+func sameValue(expected, actual interface{}) bool {
+	if want, ok := expected.(map[string]interface{}); ok {
+		got, ok := actual.(map[string]interface{})
+		if !ok {
+			return false
+		}
+		for key, value := range want {
+			if item, ok := got[key]; !ok || !sameValue(value, item) {
+				return false
+			}
+		}
+		return true
+	}
+	return fmt.Sprint(expected) == fmt.Sprint(actual)
+}
+
+// failed reports whether a response is a JSON-RPC error or a tool result the
+// server marked as failed. Requests without a response did not fail.
+func failed(response []byte) bool {
+	if len(response) == 0 {
+		return false
+	}
+
+	var decoded struct {
+		Error  json.RawMessage `json:"error"`
+		Result struct {
+			IsError bool `json:"isError"`
+			Content []struct {
+				Text string `json:"text"`
+			} `json:"content"`
+		} `json:"result"`
+	}
+	if err := json.Unmarshal(response, &decoded); err != nil {
+		return true
+	}
+	if len(decoded.Error) > 0 || decoded.Result.IsError {
+		return true
+	}
+
+	// Tool handlers report failures in their JSON payload
+	for _, content := range decoded.Result.Content {
+		var payload struct {
+			Success *bool `json:"success"`
+		}
+		if json.Unmarshal([]byte(content.Text), &payload) == nil && payload.Success != nil && !*payload.Success {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,138 @@
+package eval
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"aws-mcp-server/pkg/session"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// destructiveTools stands in for the server's list of mutating tools
+func destructiveTools(tool string, _ map[string]interface{}) bool {
+	return tool == "stop-ec2-instance" || tool == "start-ec2-instance" || tool == "terminate-ec2-instance"
+}
+
+// recording builds a session of requests a minute apart with their responses
+type recording struct {
+	started time.Time
+	entries []session.Entry
+}
+
+func (r *recording) add(t *testing.T, method string, params interface{}, result interface{}) {
+	t.Helper()
+	seq := len(r.entries) + 1
+	request, err := json.Marshal(map[string]interface{}{"jsonrpc": "2.0", "id": seq, "method": method, "params": params})
+	require.NoError(t, err)
+	response, err := json.Marshal(map[string]interface{}{"jsonrpc": "2.0", "id": seq, "result": result})
+	require.NoError(t, err)
+	r.entries = append(r.entries, session.Entry{
+		Seq:       seq,
+		Timestamp: r.started.Add(time.Duration(seq-1) * time.Minute),
+		Request:   request,
+		Response:  response,
+	})
+}
+
+func (r *recording) call(t *testing.T, tool string, arguments map[string]interface{}, success bool) {
+	t.Helper()
+	payload, err := json.Marshal(map[string]interface{}{"success": success})
+	require.NoError(t, err)
+	r.add(t, "tools/call", map[string]interface{}{"name": tool, "arguments": arguments},
+		map[string]interface{}{"content": []map[string]interface{}{{"type": "text", "text": string(payload)}}})
+}
+
+func cpuScenario() *Scenario {
+	return &Scenario{
+		Name: "cpu",
+		Detect: []Action{
+			{Resource: "aws://cloudwatch/alarms*"},
+			{Tool: "get-cloudwatch-metrics", Arguments: map[string]interface{}{"dimensions": map[string]interface{}{"InstanceId": "i-1"}}},
+		},
+		DetectWithin: 5 * time.Minute,
+		Steps: []Action{
+			{Tool: "stop-ec2-instance", Arguments: map[string]interface{}{"instanceId": "i-1"}},
+			{Tool: "start-ec2-instance", Arguments: map[string]interface{}{"instanceId": "i-1"}},
+		},
+	}
+}
+
+func TestEvaluatePerfectRun(t *testing.T) {
+	r := &recording{started: time.Date(2026, 1, 5, 9, 0, 0, 0, time.UTC)}
+	r.add(t, "initialize", map[string]interface{}{}, map[string]interface{}{})
+	r.add(t, "resources/read", map[string]interface{}{"uri": "aws://cloudwatch/alarms?state=ALARM"}, map[string]interface{}{})
+	r.call(t, "get-cloudwatch-metrics", map[string]interface{}{"metricName": "CPUUtilization", "dimensions": map[string]interface{}{"InstanceId": "i-1"}}, true)
+	r.call(t, "stop-ec2-instance", map[string]interface{}{"instanceId": "i-1"}, true)
+	r.call(t, "start-ec2-instance", map[string]interface{}{"instanceId": "i-1"}, true)
+
+	report := Evaluate(cpuScenario(), r.entries, destructiveTools)
+	assert.True(t, report.Detected)
+	assert.Equal(t, time.Minute, report.TimeToDetect)
+	assert.Equal(t, "read aws://cloudwatch/alarms?state=ALARM", report.DetectedBy)
+	assert.Equal(t, 2, report.StepsDone())
+	assert.Equal(t, 4, report.Steps[0].Seq)
+	assert.Equal(t, 3, report.ToolCalls)
+	assert.Empty(t, report.Unnecessary)
+	assert.Equal(t, 100, report.Score)
+	assert.Equal(t, 4*time.Minute, report.Duration)
+}
+
+func TestEvaluateCareless(t *testing.T) {
+	r := &recording{started: time.Date(2026, 1, 5, 9, 0, 0, 0, time.UTC)}
+	r.add(t, "initialize", map[string]interface{}{}, map[string]interface{}{})
+	r.call(t, "terminate-ec2-instance", map[string]interface{}{"instanceId": "i-2"}, true)
+	r.call(t, "start-ec2-instance", map[string]interface{}{"instanceId": "i-1"}, true)
+	r.call(t, "stop-ec2-instance", map[string]interface{}{"instanceId": "i-1"}, false)
+	r.call(t, "stop-ec2-instance", map[string]interface{}{"instanceId": "i-1"}, true)
+	for i := 0; i < 3; i++ {
+		r.call(t, "search", map[string]interface{}{"query": "cpu"}, true)
+	}
+	r.call(t, "get-cloudwatch-metrics", map[string]interface{}{"dimensions": map[string]interface{}{"InstanceId": "i-1"}}, true)
+
+	report := Evaluate(cpuScenario(), r.entries, destructiveTools)
+	assert.True(t, report.Detected)
+	assert.Equal(t, 8*time.Minute, report.TimeToDetect, "detected after the target")
+	require.Len(t, report.Steps, 2)
+	assert.Equal(t, 5, report.Steps[0].Seq, "the failed stop does not count")
+	assert.False(t, report.Steps[0].OutOfOrder)
+	assert.True(t, report.Steps[1].OutOfOrder, "started before it was stopped")
+	assert.Equal(t, 1, report.FailedCalls)
+	require.Len(t, report.Unnecessary, 2)
+	assert.Equal(t, `terminate-ec2-instance {"instanceId":"i-2"}`, report.Unnecessary[0].Action)
+	assert.True(t, report.Unnecessary[1].Failed, "failed attempts still count")
+	// 15 for the late detection, 25+12.5 for the steps, 17.5 for tool usage, -30
+	assert.Equal(t, 40, report.Score)
+
+	var out bytes.Buffer
+	require.NoError(t, report.Write(&out))
+	assert.Contains(t, out.String(), "score 40/100")
+	assert.Contains(t, out.String(), "out of order")
+	assert.Contains(t, out.String(), "after 8m0s (target 5m0s)")
+}
+
+func TestEvaluateNothingDone(t *testing.T) {
+	report := Evaluate(cpuScenario(), nil, destructiveTools)
+	assert.False(t, report.Detected)
+	assert.Equal(t, 0, report.StepsDone())
+	assert.Equal(t, 0, report.Score)
+}
+
+func TestLoadScenario(t *testing.T) {
+	scenario, err := LoadScenario("../../examples/scenarios/web-cpu-spike.yaml")
+	require.NoError(t, err)
+	assert.Equal(t, "web-cpu-spike", scenario.Name)
+	assert.Equal(t, 5*time.Minute, scenario.DetectWithin)
+	require.Len(t, scenario.Steps, 3)
+	assert.Equal(t, "i-018c194fcc7dffab0", scenario.Steps[0].Arguments["instanceId"])
+
+	path := filepath.Join(t.TempDir(), "bad.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("name: bad\nsteps:\n  - arguments: {instanceId: i-1}\n"), 0o644))
+	_, err = LoadScenario(path)
+	assert.ErrorContains(t, err, "needs either a tool or a resource")
+}
@@ -0,0 +1,143 @@
+package eval
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+)
+
+// Points of the score. Detection and remediation make up most of it, the
+// share of calls that worked the rest; every unnecessary destructive call
+// costs unnecessaryPenalty.
+const (
+	detectPoints       = 30
+	stepPoints         = 50
+	toolUsagePoints    = 20
+	unnecessaryPenalty = 15
+)
+
+// StepResult is whether the agent carried out an expected step. Seq is the
+// session entry of the call that did it.
+type StepResult struct {
+	Action     Action `json:"action"`
+	Done       bool   `json:"done"`
+	Seq        int    `json:"seq,omitempty"`
+	OutOfOrder bool   `json:"outOfOrder,omitempty"`
+}
+
+// Finding is a destructive call the scenario did not call for. Calls the
+// server refused or failed still count, since the agent attempted them.
+type Finding struct {
+	Seq    int    `json:"seq"`
+	Action string `json:"action"`
+	Failed bool   `json:"failed,omitempty"`
+}
+
+// Report is the outcome of an evaluation. Score is out of 100.
+type Report struct {
+	Scenario     string        `json:"scenario"`
+	Duration     time.Duration `json:"duration"`
+	Detected     bool          `json:"detected"`
+	DetectedBy   string        `json:"detectedBy,omitempty"`
+	TimeToDetect time.Duration `json:"timeToDetect,omitempty"`
+	DetectWithin time.Duration `json:"detectWithin,omitempty"`
+	Steps        []StepResult  `json:"steps"`
+	ToolCalls    int           `json:"toolCalls"`
+	FailedCalls  int           `json:"failedCalls"`
+	Unnecessary  []Finding     `json:"unnecessaryDestructiveCalls"`
+	Score        int           `json:"score"`
+}
+
+// score computes the score. Detecting late earns half the detection points
+// and a step done out of order half of its share.
+func (r *Report) score(scenario *Scenario) {
+	points := 0.0
+	switch {
+	case len(scenario.Detect) == 0:
+		points += detectPoints
+	case r.Detected && (r.DetectWithin == 0 || r.TimeToDetect <= r.DetectWithin):
+		points += detectPoints
+	case r.Detected:
+		points += detectPoints / 2
+	}
+
+	if len(r.Steps) == 0 {
+		points += stepPoints
+	}
+	for _, step := range r.Steps {
+		share := float64(stepPoints) / float64(len(r.Steps))
+		switch {
+		case step.Done && step.OutOfOrder:
+			points += share / 2
+		case step.Done:
+			points += share
+		}
+	}
+
+	if r.ToolCalls > 0 {
+		points += toolUsagePoints * float64(r.ToolCalls-r.FailedCalls) / float64(r.ToolCalls)
+	}
+	points -= float64(unnecessaryPenalty * len(r.Unnecessary))
+
+	r.Score = int(max(points, 0) + 0.5)
+}
+
+// StepsDone returns how many expected steps the agent carried out
+func (r *Report) StepsDone() int {
+	done := 0
+	for _, step := range r.Steps {
+		if step.Done {
+			done++
+		}
+	}
+	return done
+}
+
+// Write prints the report with one row per expected step
+func (r *Report) Write(w io.Writer) error {
+	fmt.Fprintf(w, "Scenario %s: score %d/100 over a session of %s\n\n", r.Scenario, r.Score, r.Duration.Round(time.Second))
+
+	switch {
+	case !r.Detected:
+		fmt.Fprintln(w, "Detection:   not detected")
+	case r.DetectWithin > 0:
+		fmt.Fprintf(w, "Detection:   after %s (target %s) by %s\n", r.TimeToDetect.Round(time.Second), r.DetectWithin, r.DetectedBy)
+	default:
+		fmt.Fprintf(w, "Detection:   after %s by %s\n", r.TimeToDetect.Round(time.Second), r.DetectedBy)
+	}
+	fmt.Fprintf(w, "Remediation: %d of %d steps\n", r.StepsDone(), len(r.Steps))
+	fmt.Fprintf(w, "Tool calls:  %d, %d failed\n", r.ToolCalls, r.FailedCalls)
+	fmt.Fprintf(w, "Unnecessary destructive calls: %d\n", len(r.Unnecessary))
+
+	if len(r.Steps) > 0 {
+		fmt.Fprintln(w)
+		table := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(table, "step\tstatus\tentry")
+		for _, step := range r.Steps {
+			status, entry := "missing", "-"
+			if step.Done {
+				status, entry = "done", fmt.Sprintf("#%d", step.Seq)
+				if step.OutOfOrder {
+					status = "out of order"
+				}
+			}
+			fmt.Fprintf(table, "%s\t%s\t%s\n", step.Action, status, entry)
+		}
+		if err := table.Flush(); err != nil {
+			return err
+		}
+	}
+
+	if len(r.Unnecessary) > 0 {
+		fmt.Fprintln(w)
+		for _, finding := range r.Unnecessary {
+			note := ""
+			if finding.Failed {
+				note = " (failed)"
+			}
+			fmt.Fprintf(w, "  #%d %s%s\n", finding.Seq, finding.Action, note)
+		}
+	}
+	return nil
+}
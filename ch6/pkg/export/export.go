@@ -0,0 +1,313 @@
+// Package export writes what the server did and saw to S3 as Parquet files,
+// so operations driven through MCP can be analyzed with Athena or
+// QuickSight. Each export writes three tables under the configured prefix,
+// partitioned by date:
+//
+//	<prefix>/audit/dt=YYYY-MM-DD/      changes made or attempted, by entry time
+//	<prefix>/inventory/dt=YYYY-MM-DD/  every snapshot instance, by export time
+//	<prefix>/anomalies/dt=YYYY-MM-DD/  flagged datapoints, by datapoint time
+package export
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"sync"
+	"time"
+
+	"aws-mcp-server/internal/config"
+	"aws-mcp-server/internal/logging"
+	"aws-mcp-server/pkg/audit"
+	"aws-mcp-server/pkg/baseline"
+	"aws-mcp-server/pkg/inventory"
+	"aws-mcp-server/pkg/parquet"
+)
+
+// Export defaults: the interval between exports, and how long the final
+// export on shutdown may take
+const (
+	defaultInterval = time.Hour
+	shutdownTimeout = 30 * time.Second
+)
+
+// seenRetention is how long anomalies are remembered to skip the same
+// datapoint flagged again by a later metric read
+const seenRetention = 7 * 24 * time.Hour
+
+// Uploader stores the exported files
+type Uploader interface {
+	PutS3Object(ctx context.Context, bucket, key string, body []byte) error
+}
+
+// AnomalyEvent is a datapoint the anomaly detector flagged
+type AnomalyEvent struct {
+	Time       time.Time
+	DetectedAt time.Time
+	Resource   string
+	Metric     string
+	Value      float64
+	Expected   float64
+	Z          float64
+	Seasonal   bool
+}
+
+// Exporter periodically uploads the audit log, the inventory snapshots and
+// the recorded anomalies. A nil Exporter is disabled, so callers need not
+// check whether exports are configured.
+type Exporter struct {
+	cfg       config.ExportConfig
+	server    string
+	uploader  Uploader
+	audit     *audit.Log
+	inventory *inventory.Cache
+	logger    *logging.Logger
+	now       func() time.Time
+
+	mu        sync.Mutex
+	anomalies []AnomalyEvent
+	seen      map[string]time.Time
+	// auditedUntil is the time of the last exported audit entry. It starts
+	// at the creation of the exporter, as earlier entries were exported by
+	// the previous run of the server.
+	auditedUntil time.Time
+}
+
+// New creates an exporter for the server, or returns nil when exports are
+// disabled
+func New(cfg config.ExportConfig, server string, uploader Uploader, auditLog *audit.Log, cache *inventory.Cache, logger *logging.Logger) *Exporter {
+	if !cfg.Enabled {
+		return nil
+	}
+	if cfg.Interval <= 0 {
+		cfg.Interval = defaultInterval
+	}
+
+	return &Exporter{
+		cfg:          cfg,
+		server:       server,
+		uploader:     uploader,
+		audit:        auditLog,
+		inventory:    cache,
+		logger:       logger,
+		now:          time.Now,
+		seen:         make(map[string]time.Time),
+		auditedUntil: time.Now(),
+	}
+}
+
+// RecordAnomalies queues anomalies of a resource's metric for the next
+// export. Anomalies already recorded are skipped, since overlapping metric
+// reads flag the same datapoints again.
+func (e *Exporter) RecordAnomalies(resource, metric string, anomalies []baseline.Anomaly, detectedAt time.Time) {
+	if e == nil {
+		return
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	for _, anomaly := range anomalies {
+		key := resource + "|" + metric + "|" + anomaly.Time.UTC().Format(time.RFC3339Nano)
+		if _, ok := e.seen[key]; ok {
+			continue
+		}
+		e.seen[key] = anomaly.Time
+		e.anomalies = append(e.anomalies, AnomalyEvent{
+			Time:       anomaly.Time,
+			DetectedAt: detectedAt,
+			Resource:   resource,
+			Metric:     metric,
+			Value:      anomaly.Value,
+			Expected:   anomaly.Expected,
+			Z:          anomaly.Z,
+			Seasonal:   anomaly.Seasonal,
+		})
+	}
+}
+
+// Run exports every interval until ctx is cancelled, then exports once more
+// so the last changes are not lost. Failed exports are logged; what they
+// missed is retried with the next one.
+func (e *Exporter) Run(ctx context.Context) {
+	if e == nil {
+		return
+	}
+
+	ticker := time.NewTicker(e.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			e.export(ctx)
+		case <-ctx.Done():
+			final, cancel := context.WithTimeout(context.WithoutCancel(ctx), shutdownTimeout)
+			e.export(final)
+			cancel()
+			return
+		}
+	}
+}
+
+// export runs one export and logs its outcome
+func (e *Exporter) export(ctx context.Context) {
+	if err := e.Export(ctx); err != nil {
+		e.logger.WithError(err).Error("Failed to export to S3")
+	}
+}
+
+// Export uploads the audit entries recorded since the last export, the
+// current inventory snapshots and the queued anomalies. Tables that fail are
+// exported again next time.
+func (e *Exporter) Export(ctx context.Context) error {
+	if e == nil {
+		return nil
+	}
+
+	now := e.now().UTC()
+	stamp := now.Format("20060102T150405Z")
+
+	return errors.Join(
+		e.exportAudit(ctx, now, stamp),
+		e.exportInventory(ctx, now, stamp),
+		e.exportAnomalies(ctx, now, stamp),
+	)
+}
+
+// exportAudit uploads the audit entries recorded since the last export
+func (e *Exporter) exportAudit(ctx context.Context, now time.Time, stamp string) error {
+	e.mu.Lock()
+	since := e.auditedUntil
+	e.mu.Unlock()
+
+	var entries []audit.Entry
+	for _, entry := range e.audit.Between(since, now) {
+		if entry.Time.After(since) {
+			entries = append(entries, entry)
+		}
+	}
+	if len(entries) == 0 {
+		return nil
+	}
+
+	byDate := make(map[string]*parquet.Writer)
+	for _, entry := range entries {
+		arguments, err := json.Marshal(entry.Arguments)
+		if err != nil {
+			return fmt.Errorf("failed to encode the arguments of %s: %w", entry.Tool, err)
+		}
+		w := writerFor(byDate, entry.Time, auditColumns)
+		if err := w.Append(e.server, entry.Time, entry.Tool, string(arguments), entry.Role, entry.Success,
+			entry.Message, entry.ChangeTicket, entry.Elevation); err != nil {
+			return err
+		}
+	}
+	if err := e.upload(ctx, "audit", stamp, byDate); err != nil {
+		return err
+	}
+
+	e.mu.Lock()
+	e.auditedUntil = entries[len(entries)-1].Time
+	e.mu.Unlock()
+	e.logger.WithField("entries", len(entries)).Info("Exported audit entries")
+	return nil
+}
+
+// exportInventory uploads every instance of the inventory snapshots
+func (e *Exporter) exportInventory(ctx context.Context, now time.Time, stamp string) error {
+	snapshots := e.inventory.Snapshots()
+	w := parquet.NewWriter(inventoryColumns...)
+	for _, snapshot := range snapshots {
+		for _, instance := range snapshot.Instances {
+			tags, err := json.Marshal(instance.Tags)
+			if err != nil {
+				return fmt.Errorf("failed to encode the tags of %s: %w", instance.ID, err)
+			}
+			details, err := json.Marshal(instance.Details)
+			if err != nil {
+				return fmt.Errorf("failed to encode the details of %s: %w", instance.ID, err)
+			}
+			if err := w.Append(e.server, now, snapshot.Key, snapshot.RefreshedAt, instance.ID, instance.Provider,
+				instance.Type, instance.Region, instance.State, string(tags), string(details), instance.LastSeen); err != nil {
+				return err
+			}
+		}
+	}
+	if w.Rows() == 0 {
+		return nil
+	}
+
+	if err := e.upload(ctx, "inventory", stamp, map[string]*parquet.Writer{now.Format(time.DateOnly): w}); err != nil {
+		return err
+	}
+	e.logger.WithField("instances", w.Rows()).Info("Exported the inventory")
+	return nil
+}
+
+// exportAnomalies uploads the queued anomalies, putting them back on failure
+func (e *Exporter) exportAnomalies(ctx context.Context, now time.Time, stamp string) error {
+	e.mu.Lock()
+	events := e.anomalies
+	e.anomalies = nil
+	for key, at := range e.seen {
+		if now.Sub(at) > seenRetention {
+			delete(e.seen, key)
+		}
+	}
+	e.mu.Unlock()
+	if len(events) == 0 {
+		return nil
+	}
+
+	byDate := make(map[string]*parquet.Writer)
+	for _, event := range events {
+		w := writerFor(byDate, event.Time, anomalyColumns)
+		if err := w.Append(e.server, event.Time, event.DetectedAt, event.Resource, event.Metric,
+			event.Value, event.Expected, event.Z, event.Seasonal); err != nil {
+			return err
+		}
+	}
+	if err := e.upload(ctx, "anomalies", stamp, byDate); err != nil {
+		e.mu.Lock()
+		e.anomalies = append(events, e.anomalies...)
+		e.mu.Unlock()
+		return err
+	}
+
+	e.logger.WithField("anomalies", len(events)).Info("Exported anomalies")
+	return nil
+}
+
+// upload writes one file per date partition of a table
+func (e *Exporter) upload(ctx context.Context, table, stamp string, byDate map[string]*parquet.Writer) error {
+	for date, w := range byDate {
+		var file bytes.Buffer
+		if _, err := w.WriteTo(&file); err != nil {
+			return fmt.Errorf("failed to write the %s table: %w", table, err)
+		}
+		if err := e.uploader.PutS3Object(ctx, e.cfg.Bucket, ObjectKey(e.cfg.Prefix, table, date, e.server, stamp), file.Bytes()); err != nil {
+			return fmt.Errorf("failed to upload the %s table: %w", table, err)
+		}
+	}
+	return nil
+}
+
+// ObjectKey returns where a file of a table is stored. The server name keeps
+// servers sharing a bucket from overwriting each other's files.
+func ObjectKey(prefix, table, date, server, stamp string) string {
+	return path.Join(prefix, table, "dt="+date, fmt.Sprintf("%s-%s.parquet", server, stamp))
+}
+
+// writerFor returns the writer of the date partition t falls in
+func writerFor(byDate map[string]*parquet.Writer, t time.Time, columns []parquet.Column) *parquet.Writer {
+	date := t.UTC().Format(time.DateOnly)
+	w, ok := byDate[date]
+	if !ok {
+		w = parquet.NewWriter(columns...)
+		byDate[date] = w
+	}
+	return w
+}
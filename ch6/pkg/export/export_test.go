@@ -0,0 +1,127 @@
+package export
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"testing"
+	"time"
+
+	"aws-mcp-server/internal/config"
+	"aws-mcp-server/internal/logging"
+	"aws-mcp-server/pkg/audit"
+	"aws-mcp-server/pkg/baseline"
+	"aws-mcp-server/pkg/inventory"
+	"aws-mcp-server/pkg/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeUploader keeps uploaded files by key and fails while err is set
+type fakeUploader struct {
+	objects map[string][]byte
+	err     error
+}
+
+func (u *fakeUploader) PutS3Object(_ context.Context, bucket, key string, body []byte) error {
+	if u.err != nil {
+		return u.err
+	}
+	if u.objects == nil {
+		u.objects = make(map[string][]byte)
+	}
+	u.objects[bucket+"/"+key] = body
+	return nil
+}
+
+func (u *fakeUploader) keys() []string {
+	keys := make([]string, 0, len(u.objects))
+	for key := range u.objects {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func newExporter(t *testing.T, uploader Uploader, auditLog *audit.Log, cache *inventory.Cache, now time.Time) *Exporter {
+	t.Helper()
+	cfg := config.ExportConfig{Enabled: true, Bucket: "analytics", Prefix: "aiops"}
+	e := New(cfg, "mcp-1", uploader, auditLog, cache, logging.NewLogger("error", "text"))
+	require.NotNil(t, e)
+	e.now = func() time.Time { return now }
+	e.auditedUntil = now.Add(-48 * time.Hour)
+	return e
+}
+
+func TestDisabled(t *testing.T) {
+	e := New(config.ExportConfig{}, "mcp-1", &fakeUploader{}, nil, nil, logging.NewLogger("error", "text"))
+	assert.Nil(t, e)
+	e.RecordAnomalies("i-1", "CPUUtilization", []baseline.Anomaly{{}}, time.Now())
+	assert.NoError(t, e.Export(context.Background()))
+	e.Run(context.Background())
+}
+
+func TestExportPartitionsByDate(t *testing.T) {
+	now := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	auditLog, err := audit.Open("", 0)
+	require.NoError(t, err)
+	require.NoError(t, auditLog.Record(audit.Entry{Time: now.Add(-12 * time.Hour), Tool: "stop-ec2-instance", Success: true}))
+	require.NoError(t, auditLog.Record(audit.Entry{Time: now.Add(-time.Hour), Tool: "tag-resource", Success: true}))
+
+	cache := inventory.NewCache()
+	require.NoError(t, cache.Put("aws", []types.CloudResource{{ID: "i-1", Provider: "aws", Tags: map[string]string{"Name": "web"}}}, now))
+
+	uploader := &fakeUploader{}
+	e := newExporter(t, uploader, auditLog, cache, now)
+	e.RecordAnomalies("i-1", "AWS/EC2/CPUUtilization", []baseline.Anomaly{
+		{Point: baseline.Point{Time: now.Add(-30 * time.Minute), Value: 97}, Score: baseline.Score{Expected: 20, Z: 8}},
+	}, now)
+
+	require.NoError(t, e.Export(context.Background()))
+	assert.Equal(t, []string{
+		"analytics/aiops/anomalies/dt=2026-03-02/mcp-1-20260302T100000Z.parquet",
+		"analytics/aiops/audit/dt=2026-03-01/mcp-1-20260302T100000Z.parquet",
+		"analytics/aiops/audit/dt=2026-03-02/mcp-1-20260302T100000Z.parquet",
+		"analytics/aiops/inventory/dt=2026-03-02/mcp-1-20260302T100000Z.parquet",
+	}, uploader.keys())
+	for key, body := range uploader.objects {
+		assert.Equal(t, "PAR1", string(body[:4]), key)
+	}
+
+	// The next export only has the inventory, as nothing else happened since
+	uploader.objects = nil
+	e.now = func() time.Time { return now.Add(time.Hour) }
+	require.NoError(t, e.Export(context.Background()))
+	assert.Equal(t, []string{"analytics/aiops/inventory/dt=2026-03-02/mcp-1-20260302T110000Z.parquet"}, uploader.keys())
+}
+
+func TestFailedExportIsRetried(t *testing.T) {
+	now := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	auditLog, err := audit.Open("", 0)
+	require.NoError(t, err)
+	require.NoError(t, auditLog.Record(audit.Entry{Time: now.Add(-time.Hour), Tool: "reboot-ec2-instance"}))
+
+	uploader := &fakeUploader{err: errors.New("access denied")}
+	e := newExporter(t, uploader, auditLog, nil, now)
+	anomaly := baseline.Anomaly{Point: baseline.Point{Time: now.Add(-time.Minute), Value: 5}}
+	e.RecordAnomalies("i-1", "AWS/EC2/CPUUtilization", []baseline.Anomaly{anomaly}, now)
+	assert.ErrorContains(t, e.Export(context.Background()), "access denied")
+
+	// A later read flagging the same datapoint does not queue it twice
+	e.RecordAnomalies("i-1", "AWS/EC2/CPUUtilization", []baseline.Anomaly{anomaly}, now)
+	assert.Len(t, e.anomalies, 1)
+
+	uploader.err = nil
+	require.NoError(t, e.Export(context.Background()))
+	assert.Equal(t, []string{
+		"analytics/aiops/anomalies/dt=2026-03-02/mcp-1-20260302T100000Z.parquet",
+		"analytics/aiops/audit/dt=2026-03-02/mcp-1-20260302T100000Z.parquet",
+	}, uploader.keys())
+	assert.Empty(t, e.anomalies)
+}
+
+func TestObjectKey(t *testing.T) {
+	assert.Equal(t, "audit/dt=2026-03-02/mcp-1-20260302T100000Z.parquet",
+		ObjectKey("", "audit", "2026-03-02", "mcp-1", "20260302T100000Z"))
+}
@@ -0,0 +1,46 @@
+package export
+
+import "aws-mcp-server/pkg/parquet"
+
+// Columns of the exported tables. Maps are stored as JSON strings, which
+// Athena reads with json_extract.
+var (
+	auditColumns = []parquet.Column{
+		{Name: "server", Type: parquet.String},
+		{Name: "time", Type: parquet.Timestamp},
+		{Name: "tool", Type: parquet.String},
+		{Name: "arguments", Type: parquet.String},
+		{Name: "role", Type: parquet.String},
+		{Name: "success", Type: parquet.Boolean},
+		{Name: "message", Type: parquet.String},
+		{Name: "change_ticket", Type: parquet.String},
+		{Name: "elevation", Type: parquet.String},
+	}
+
+	inventoryColumns = []parquet.Column{
+		{Name: "server", Type: parquet.String},
+		{Name: "exported_at", Type: parquet.Timestamp},
+		{Name: "snapshot", Type: parquet.String},
+		{Name: "refreshed_at", Type: parquet.Timestamp},
+		{Name: "id", Type: parquet.String},
+		{Name: "provider", Type: parquet.String},
+		{Name: "type", Type: parquet.String},
+		{Name: "region", Type: parquet.String},
+		{Name: "state", Type: parquet.String},
+		{Name: "tags", Type: parquet.String},
+		{Name: "details", Type: parquet.String},
+		{Name: "last_seen", Type: parquet.Timestamp},
+	}
+
+	anomalyColumns = []parquet.Column{
+		{Name: "server", Type: parquet.String},
+		{Name: "time", Type: parquet.Timestamp},
+		{Name: "detected_at", Type: parquet.Timestamp},
+		{Name: "resource", Type: parquet.String},
+		{Name: "metric", Type: parquet.String},
+		{Name: "value", Type: parquet.Double},
+		{Name: "expected", Type: parquet.Double},
+		{Name: "z", Type: parquet.Double},
+		{Name: "seasonal", Type: parquet.Boolean},
+	}
+)
@@ -0,0 +1,279 @@
+package fake
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+	cwtypes "github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+)
+
+const (
+	// ec2Namespace is the namespace of the instance metrics the fleet serves
+	ec2Namespace = "AWS/EC2"
+	// alarmPeriod is the period in seconds every alarm of the fleet evaluates
+	alarmPeriod = 300
+	// alarmEvaluationPeriods is how many periods must breach before an alarm fires
+	alarmEvaluationPeriods = 2
+)
+
+// transition is a state change of an alarm
+type transition struct {
+	at       time.Time
+	oldState string
+	newState string
+	reason   string
+}
+
+// handleCloudWatch answers the CloudWatch operations behind the alarm
+// resources and the metric tools
+func (f *Fleet) handleCloudWatch(operation string, params interface{}) (interface{}, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	switch input := params.(type) {
+	case *cloudwatch.DescribeAlarmsInput:
+		return f.describeAlarms(input), nil
+	case *cloudwatch.DescribeAlarmHistoryInput:
+		return f.describeAlarmHistory(input), nil
+	case *cloudwatch.GetMetricDataInput:
+		return f.getMetricData(input), nil
+	default:
+		return nil, unsupported("CloudWatch", operation)
+	}
+}
+
+// describeAlarms lists the metric alarms matching the names, prefix and state
+func (f *Fleet) describeAlarms(input *cloudwatch.DescribeAlarmsInput) *cloudwatch.DescribeAlarmsOutput {
+	output := &cloudwatch.DescribeAlarmsOutput{}
+	if len(input.AlarmTypes) > 0 && !containsAlarmType(input.AlarmTypes, cwtypes.AlarmTypeMetricAlarm) {
+		return output
+	}
+
+	now := f.now()
+	for _, a := range f.alarms {
+		if !contains(input.AlarmNames, a.name) || !strings.HasPrefix(a.name, aws.ToString(input.AlarmNamePrefix)) {
+			continue
+		}
+		transitions := f.transitions(a, now)
+		current := transitions[len(transitions)-1]
+		if input.StateValue != "" && string(input.StateValue) != current.newState {
+			continue
+		}
+
+		output.MetricAlarms = append(output.MetricAlarms, cwtypes.MetricAlarm{
+			AlarmName:             aws.String(a.name),
+			AlarmArn:              aws.String(fmt.Sprintf("arn:aws:cloudwatch:%s:%s:alarm:%s", f.region, AccountID, a.name)),
+			AlarmDescription:      aws.String(a.description),
+			ActionsEnabled:        aws.Bool(true),
+			AlarmActions:          []string{fmt.Sprintf("arn:aws:sns:%s:%s:ops-alerts", f.region, AccountID)},
+			StateValue:            cwtypes.StateValue(current.newState),
+			StateReason:           aws.String(current.reason),
+			StateUpdatedTimestamp: aws.Time(current.at),
+			Namespace:             aws.String(ec2Namespace),
+			MetricName:            aws.String(a.metric),
+			Dimensions:            []cwtypes.Dimension{{Name: aws.String("InstanceId"), Value: aws.String(a.instance.id)}},
+			Statistic:             cwtypes.Statistic(a.statistic),
+			ComparisonOperator:    cwtypes.ComparisonOperator(a.comparison),
+			Threshold:             aws.Float64(a.threshold),
+			Period:                aws.Int32(alarmPeriod),
+			EvaluationPeriods:     aws.Int32(alarmEvaluationPeriods),
+		})
+	}
+	return output
+}
+
+// describeAlarmHistory lists the state changes of the alarms in a time window
+func (f *Fleet) describeAlarmHistory(input *cloudwatch.DescribeAlarmHistoryInput) *cloudwatch.DescribeAlarmHistoryOutput {
+	output := &cloudwatch.DescribeAlarmHistoryOutput{}
+	if input.HistoryItemType != "" && input.HistoryItemType != cwtypes.HistoryItemTypeStateUpdate {
+		return output
+	}
+	if len(input.AlarmTypes) > 0 && !containsAlarmType(input.AlarmTypes, cwtypes.AlarmTypeMetricAlarm) {
+		return output
+	}
+
+	now := f.now()
+	for _, a := range f.alarms {
+		if name := aws.ToString(input.AlarmName); name != "" && name != a.name {
+			continue
+		}
+		for _, t := range f.transitions(a, now) {
+			if input.StartDate != nil && t.at.Before(*input.StartDate) || input.EndDate != nil && t.at.After(*input.EndDate) {
+				continue
+			}
+			output.AlarmHistoryItems = append(output.AlarmHistoryItems, historyItem(a.name, t))
+		}
+	}
+
+	ascending := input.ScanBy == cwtypes.ScanByTimestampAscending
+	sort.SliceStable(output.AlarmHistoryItems, func(i, j int) bool {
+		if ascending {
+			return output.AlarmHistoryItems[i].Timestamp.Before(*output.AlarmHistoryItems[j].Timestamp)
+		}
+		return output.AlarmHistoryItems[i].Timestamp.After(*output.AlarmHistoryItems[j].Timestamp)
+	})
+	return output
+}
+
+// getMetricData returns the datapoints of the instance metrics queried.
+// Metrics the fleet does not have and metric math come back without
+// datapoints, as CloudWatch returns unknown metrics.
+func (f *Fleet) getMetricData(input *cloudwatch.GetMetricDataInput) *cloudwatch.GetMetricDataOutput {
+	output := &cloudwatch.GetMetricDataOutput{}
+	start, end := aws.ToTime(input.StartTime), aws.ToTime(input.EndTime)
+	if now := f.now(); end.After(now) {
+		end = now
+	}
+
+	for _, query := range input.MetricDataQueries {
+		result := cwtypes.MetricDataResult{Id: query.Id, Label: query.Label, StatusCode: cwtypes.StatusCodeComplete}
+		if query.Expression != nil {
+			result.Messages = []cwtypes.MessageData{{Code: aws.String("Unsupported"), Value: aws.String("the fake provider does not evaluate metric math")}}
+		}
+
+		if stat := query.MetricStat; stat != nil && stat.Metric != nil {
+			metric := aws.ToString(stat.Metric.MetricName)
+			if result.Label == nil {
+				result.Label = aws.String(metric)
+			}
+			period := max(aws.ToInt32(stat.Period), 60)
+			if inst := f.metricInstance(stat.Metric); inst != nil {
+				for t := start.Truncate(time.Duration(period) * time.Second); t.Before(end); t = t.Add(time.Duration(period) * time.Second) {
+					if t.Before(start) || !inst.running(t) {
+						continue
+					}
+					value, ok := f.metricValue(inst, metric, t)
+					if !ok {
+						break
+					}
+					result.Timestamps = append(result.Timestamps, t)
+					result.Values = append(result.Values, statistic(metric, aws.ToString(stat.Stat), value, period))
+				}
+			}
+		}
+
+		if input.ScanBy != cwtypes.ScanByTimestampAscending {
+			for i, j := 0, len(result.Timestamps)-1; i < j; i, j = i+1, j-1 {
+				result.Timestamps[i], result.Timestamps[j] = result.Timestamps[j], result.Timestamps[i]
+				result.Values[i], result.Values[j] = result.Values[j], result.Values[i]
+			}
+		}
+		output.MetricDataResults = append(output.MetricDataResults, result)
+	}
+	return output
+}
+
+// metricInstance returns the instance an AWS/EC2 metric with only an
+// InstanceId dimension belongs to
+func (f *Fleet) metricInstance(metric *cwtypes.Metric) *instance {
+	if aws.ToString(metric.Namespace) != ec2Namespace || len(metric.Dimensions) != 1 {
+		return nil
+	}
+	dimension := metric.Dimensions[0]
+	if aws.ToString(dimension.Name) != "InstanceId" {
+		return nil
+	}
+	return f.findInstance(aws.ToString(dimension.Value))
+}
+
+// transitions returns the state changes of an alarm up to now, oldest first.
+// An alarm starts out OK; its anomaly puts it in ALARM once the metric
+// breaches the threshold for the evaluation periods and back to OK when the
+// anomaly ends.
+func (f *Fleet) transitions(a alarm, now time.Time) []transition {
+	transitions := []transition{{
+		at:       a.created,
+		oldState: string(cwtypes.StateValueInsufficientData),
+		newState: string(cwtypes.StateValueOk),
+		reason:   "Threshold Crossed: no datapoints were breaching the threshold",
+	}}
+	if a.anomaly == nil {
+		return transitions
+	}
+
+	period := time.Duration(alarmPeriod) * time.Second
+	for t := a.anomaly.Start.Truncate(period); t.Before(now) && (a.anomaly.End.IsZero() || t.Before(a.anomaly.End)); t = t.Add(period) {
+		value, ok := f.metricValue(a.instance, a.metric, t)
+		if !ok || !a.breaches(statistic(a.metric, a.statistic, value, alarmPeriod)) {
+			continue
+		}
+		fired := t.Add(alarmEvaluationPeriods * period)
+		if fired.After(now) {
+			break
+		}
+		transitions = append(transitions, transition{
+			at:       fired,
+			oldState: string(cwtypes.StateValueOk),
+			newState: string(cwtypes.StateValueAlarm),
+			reason: fmt.Sprintf("Threshold Crossed: %d datapoints were %s the threshold (%s). The most recent datapoint: %.1f.",
+				alarmEvaluationPeriods, comparisonText[a.comparison], formatThreshold(a.threshold), statistic(a.metric, a.statistic, value, alarmPeriod)),
+		})
+		if resolved := a.anomaly.End.Add(period); !a.anomaly.End.IsZero() && !resolved.After(now) {
+			transitions = append(transitions, transition{
+				at:       resolved,
+				oldState: string(cwtypes.StateValueAlarm),
+				newState: string(cwtypes.StateValueOk),
+				reason:   fmt.Sprintf("Threshold Crossed: 1 datapoint was not %s the threshold (%s).", comparisonText[a.comparison], formatThreshold(a.threshold)),
+			})
+		}
+		break
+	}
+	return transitions
+}
+
+// comparisonText words the comparisons of the fleet's alarms as CloudWatch does
+var comparisonText = map[string]string{
+	"GreaterThanThreshold": "greater than",
+	"LessThanThreshold":    "less than",
+}
+
+// breaches reports whether a statistic crosses the alarm's threshold
+func (a alarm) breaches(value float64) bool {
+	if a.comparison == "LessThanThreshold" {
+		return value < a.threshold
+	}
+	return value > a.threshold
+}
+
+// historyItem converts an alarm state change to a history item, with the
+// old and new states as JSON HistoryData like CloudWatch
+func historyItem(name string, t transition) cwtypes.AlarmHistoryItem {
+	type state struct {
+		StateValue  string `json:"stateValue"`
+		StateReason string `json:"stateReason"`
+	}
+	data, _ := json.Marshal(struct {
+		Version  string `json:"version"`
+		OldState state  `json:"oldState"`
+		NewState state  `json:"newState"`
+	}{"1.0", state{StateValue: t.oldState}, state{StateValue: t.newState, StateReason: t.reason}})
+
+	return cwtypes.AlarmHistoryItem{
+		AlarmName:       aws.String(name),
+		AlarmType:       cwtypes.AlarmTypeMetricAlarm,
+		Timestamp:       aws.Time(t.at),
+		HistoryItemType: cwtypes.HistoryItemTypeStateUpdate,
+		HistorySummary:  aws.String(fmt.Sprintf("Alarm updated from %s to %s", t.oldState, t.newState)),
+		HistoryData:     aws.String(string(data)),
+	}
+}
+
+// formatThreshold formats a threshold like the CloudWatch console
+func formatThreshold(threshold float64) string {
+	return fmt.Sprintf("%.1f", threshold)
+}
+
+// containsAlarmType reports whether types holds the alarm type
+func containsAlarmType(types []cwtypes.AlarmType, alarmType cwtypes.AlarmType) bool {
+	for _, candidate := range types {
+		if candidate == alarmType {
+			return true
+		}
+	}
+	return false
+}
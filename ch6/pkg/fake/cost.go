@@ -0,0 +1,245 @@
+package fake
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/costexplorer"
+	cetypes "github.com/aws/aws-sdk-go-v2/service/costexplorer/types"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+)
+
+// ec2ComputeService is the Cost Explorer service name of instance hours
+const ec2ComputeService = "Amazon Elastic Compute Cloud - Compute"
+
+// hourlyPrices are the on-demand Linux prices of the fleet's instance types
+// in USD; Windows adds windowsHourly
+var hourlyPrices = map[string]float64{
+	"t3.micro":   0.0104,
+	"t3.medium":  0.0416,
+	"m5.large":   0.096,
+	"m5.xlarge":  0.192,
+	"c6g.xlarge": 0.136,
+	"r5.xlarge":  0.252,
+}
+
+const (
+	// defaultHourly prices instance types missing from hourlyPrices
+	defaultHourly = 0.1
+	// windowsHourly is the license cost added to Windows instances per hour
+	windowsHourly = 0.184
+)
+
+// dailyServiceCosts are the daily costs in USD of the services around the
+// instances
+var dailyServiceCosts = map[string]float64{
+	"Amazon Relational Database Service": 38,
+	"Amazon Simple Storage Service":      12.5,
+	"EC2 - Other":                        9.2,
+	"Amazon Virtual Private Cloud":       3.6,
+	"Amazon CloudWatch":                  4.1,
+	"AWS Key Management Service":         0.9,
+}
+
+// costMetrics are the Cost Explorer metrics the fleet reports; they are the
+// same without discounts or credits
+var costMetrics = map[string]bool{
+	"UnblendedCost":    true,
+	"BlendedCost":      true,
+	"AmortizedCost":    true,
+	"NetUnblendedCost": true,
+}
+
+// handleCostExplorer answers the Cost Explorer operations behind the cost resources
+func (f *Fleet) handleCostExplorer(operation string, params interface{}) (interface{}, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	switch input := params.(type) {
+	case *costexplorer.GetCostAndUsageInput:
+		return f.getCostAndUsage(input)
+	default:
+		return nil, unsupported("Cost Explorer", operation)
+	}
+}
+
+// getCostAndUsage returns the daily or monthly cost of the fleet, in total
+// or by service, region or account
+func (f *Fleet) getCostAndUsage(input *costexplorer.GetCostAndUsageInput) (*costexplorer.GetCostAndUsageOutput, error) {
+	start, err := time.Parse(time.DateOnly, aws.ToString(input.TimePeriod.Start))
+	if err != nil {
+		return nil, apiError("ValidationException", "Start date is invalid")
+	}
+	end, err := time.Parse(time.DateOnly, aws.ToString(input.TimePeriod.End))
+	if err != nil || !end.After(start) {
+		return nil, apiError("ValidationException", "End date must be after the start date")
+	}
+	for _, metric := range input.Metrics {
+		if !costMetrics[metric] {
+			return nil, apiError("ValidationException", fmt.Sprintf("the fake provider does not report the metric %s", metric))
+		}
+	}
+
+	groupBy := ""
+	if len(input.GroupBy) > 0 {
+		group := input.GroupBy[0]
+		groupBy = aws.ToString(group.Key)
+		if len(input.GroupBy) > 1 || group.Type != cetypes.GroupDefinitionTypeDimension ||
+			groupBy != "SERVICE" && groupBy != "REGION" && groupBy != "LINKED_ACCOUNT" {
+			return nil, apiError("ValidationException", fmt.Sprintf("the fake provider groups cost by SERVICE, REGION or LINKED_ACCOUNT, not %s", groupBy))
+		}
+	}
+
+	var periods [][2]time.Time
+	switch input.Granularity {
+	case cetypes.GranularityDaily:
+		for day := start; day.Before(end); day = day.AddDate(0, 0, 1) {
+			periods = append(periods, [2]time.Time{day, day.AddDate(0, 0, 1)})
+		}
+	case cetypes.GranularityMonthly:
+		for month := start; month.Before(end); {
+			next := time.Date(month.Year(), month.Month()+1, 1, 0, 0, 0, 0, time.UTC)
+			periods = append(periods, [2]time.Time{month, minTime(next, end)})
+			month = next
+		}
+	default:
+		return nil, apiError("ValidationException", fmt.Sprintf("the fake provider does not report %s cost", input.Granularity))
+	}
+
+	today := f.now().UTC().Truncate(24 * time.Hour)
+	output := &costexplorer.GetCostAndUsageOutput{GroupDefinitions: input.GroupBy}
+	for _, period := range periods {
+		costs := make(map[string]float64)
+		for day := period[0]; day.Before(period[1]) && !day.After(today); day = day.AddDate(0, 0, 1) {
+			for service, amount := range f.dailyCost(day, today) {
+				costs[service] += amount
+			}
+		}
+
+		result := cetypes.ResultByTime{
+			TimePeriod: &cetypes.DateInterval{
+				Start: aws.String(period[0].Format(time.DateOnly)),
+				End:   aws.String(period[1].Format(time.DateOnly)),
+			},
+			Estimated: period[1].After(today),
+			Total:     make(map[string]cetypes.MetricValue),
+		}
+		switch groupBy {
+		case "":
+			total := 0.0
+			for _, amount := range costs {
+				total += amount
+			}
+			for _, metric := range input.Metrics {
+				result.Total[metric] = usd(total)
+			}
+		case "SERVICE":
+			for _, service := range sortedCostKeys(costs) {
+				result.Groups = append(result.Groups, costGroup(service, costs[service], input.Metrics))
+			}
+		default:
+			key := f.region
+			if groupBy == "LINKED_ACCOUNT" {
+				key = AccountID
+			}
+			total := 0.0
+			for _, amount := range costs {
+				total += amount
+			}
+			result.Groups = append(result.Groups, costGroup(key, total, input.Metrics))
+		}
+		output.ResultsByTime = append(output.ResultsByTime, result)
+	}
+	return output, nil
+}
+
+// dailyCost returns the cost per service of a day. Instance hours follow the
+// time each instance ran; the other services vary a little from day to day.
+// Today only counts the hours so far.
+func (f *Fleet) dailyCost(day, today time.Time) map[string]float64 {
+	dayEnd := day.Add(24 * time.Hour)
+	if !day.Before(today) {
+		dayEnd = f.now().UTC()
+	}
+	share := dayEnd.Sub(day).Hours() / 24
+
+	costs := make(map[string]float64, len(dailyServiceCosts)+1)
+	for service, amount := range dailyServiceCosts {
+		costs[service] = amount * share * (0.9 + 0.2*f.unit("cost", service, day.Format(time.DateOnly)))
+	}
+	for _, inst := range f.instances {
+		from, to := maxTime(day, inst.up), dayEnd
+		if !inst.down.IsZero() {
+			to = minTime(to, inst.down)
+		}
+		if !to.After(from) {
+			continue
+		}
+		hourly, ok := hourlyPrices[inst.instanceType]
+		if !ok {
+			hourly = defaultHourly
+		}
+		if inst.profile.windows {
+			hourly += windowsHourly
+		}
+		costs[ec2ComputeService] += hourly * to.Sub(from).Hours()
+	}
+	return costs
+}
+
+// costGroup converts the cost of a group for every requested metric
+func costGroup(key string, amount float64, metrics []string) cetypes.Group {
+	group := cetypes.Group{Keys: []string{key}, Metrics: make(map[string]cetypes.MetricValue, len(metrics))}
+	for _, metric := range metrics {
+		group.Metrics[metric] = usd(amount)
+	}
+	return group
+}
+
+// usd formats an amount like Cost Explorer
+func usd(amount float64) cetypes.MetricValue {
+	return cetypes.MetricValue{Amount: aws.String(strconv.FormatFloat(amount, 'f', 8, 64)), Unit: aws.String("USD")}
+}
+
+// sortedCostKeys returns the services of a cost map in order
+func sortedCostKeys(costs map[string]float64) []string {
+	keys := make([]string, 0, len(costs))
+	for key := range costs {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// handleSTS answers the caller identity checks with the fleet's account
+func (f *Fleet) handleSTS(operation string, params interface{}) (interface{}, error) {
+	switch params.(type) {
+	case *sts.GetCallerIdentityInput:
+		return &sts.GetCallerIdentityOutput{
+			Account: aws.String(AccountID),
+			Arn:     aws.String("arn:aws:iam::" + AccountID + ":user/learner"),
+			UserId:  aws.String(fmt.Sprintf("AIDA%016X", f.hash("user", "learner"))),
+		}, nil
+	default:
+		return nil, unsupported("STS", operation)
+	}
+}
+
+// minTime returns the earlier of two times
+func minTime(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+	return b
+}
+
+// maxTime returns the later of two times
+func maxTime(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
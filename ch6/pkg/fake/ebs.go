@@ -0,0 +1,383 @@
+package fake
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+)
+
+// rootDevice is the device the root volume of every instance is attached on
+const rootDevice = "/dev/xvda"
+
+// volume is an EBS volume of the fleet. Volumes change state at once, so
+// waiters see them available or in use on their first check.
+type volume struct {
+	id         string
+	zone       string
+	sizeGiB    int32
+	volumeType string
+	encrypted  bool
+	kmsKeyID   string
+	snapshotID string
+	created    time.Time
+	tags       map[string]string
+	// instance and device name the attachment; a volume without instance
+	// is available
+	instance string
+	device   string
+}
+
+// snapshot is an EBS snapshot of the fleet. Snapshots complete at once.
+type snapshot struct {
+	id          string
+	volumeID    string
+	sizeGiB     int32
+	encrypted   bool
+	kmsKeyID    string
+	description string
+	started     time.Time
+}
+
+// unencryptedRoles are the workloads whose root volumes predate default
+// encryption, for the encryption audit to find
+var unencryptedRoles = map[string]bool{"report": true, "bastion": true}
+
+// generateVolumes gives every instance a root volume
+func (f *Fleet) generateVolumes() {
+	for i, inst := range f.instances {
+		size := int32(20)
+		if inst.profile.role == "batch" || inst.profile.role == "report" {
+			size = 100
+		}
+		f.volumes = append(f.volumes, &volume{
+			id:         f.id("vol", i),
+			zone:       inst.subnet.zone,
+			sizeGiB:    size,
+			volumeType: "gp3",
+			encrypted:  !unencryptedRoles[inst.profile.role],
+			created:    inst.launchTime,
+			tags:       map[string]string{"Name": inst.tags["Name"] + "-root"},
+			instance:   inst.id,
+			device:     rootDevice,
+		})
+	}
+}
+
+// handleRDS answers the RDS listing of the encryption audit. The fleet runs
+// no databases.
+func (f *Fleet) handleRDS(operation string, params interface{}) (interface{}, error) {
+	switch params.(type) {
+	case *rds.DescribeDBInstancesInput:
+		return &rds.DescribeDBInstancesOutput{}, nil
+	default:
+		return nil, unsupported("RDS", operation)
+	}
+}
+
+// describeVolumes lists the volumes with the IDs, failing like EC2 when one
+// does not exist
+func (f *Fleet) describeVolumes(input *ec2.DescribeVolumesInput) (*ec2.DescribeVolumesOutput, error) {
+	candidates := f.volumes
+	if len(input.VolumeIds) > 0 {
+		candidates = nil
+		for _, id := range input.VolumeIds {
+			vol := f.findVolume(id)
+			if vol == nil {
+				return nil, apiError("InvalidVolume.NotFound", fmt.Sprintf("The volume '%s' does not exist.", id))
+			}
+			candidates = append(candidates, vol)
+		}
+	}
+
+	output := &ec2.DescribeVolumesOutput{}
+	for _, vol := range candidates {
+		matched, err := matchFilters(input.Filters, func(name string) ([]string, bool) { return vol.filterValue(name) })
+		if err != nil {
+			return nil, err
+		}
+		if matched {
+			output.Volumes = append(output.Volumes, vol.convert())
+		}
+	}
+	return output, nil
+}
+
+// describeSnapshots lists the snapshots with the IDs, all of which the
+// fleet's account owns
+func (f *Fleet) describeSnapshots(input *ec2.DescribeSnapshotsInput) (*ec2.DescribeSnapshotsOutput, error) {
+	output := &ec2.DescribeSnapshotsOutput{}
+	for _, id := range input.SnapshotIds {
+		if f.findSnapshot(id) == nil {
+			return nil, apiError("InvalidSnapshot.NotFound", fmt.Sprintf("The snapshot '%s' does not exist.", id))
+		}
+	}
+	for _, snap := range f.snapshots {
+		if contains(input.SnapshotIds, snap.id) {
+			output.Snapshots = append(output.Snapshots, snap.convert())
+		}
+	}
+	return output, nil
+}
+
+// createSnapshot snapshots a volume
+func (f *Fleet) createSnapshot(input *ec2.CreateSnapshotInput) (*ec2.CreateSnapshotOutput, error) {
+	vol := f.findVolume(aws.ToString(input.VolumeId))
+	if vol == nil {
+		return nil, apiError("InvalidVolume.NotFound", fmt.Sprintf("The volume '%s' does not exist.", aws.ToString(input.VolumeId)))
+	}
+	snap := f.addSnapshot(snapshot{
+		volumeID:    vol.id,
+		sizeGiB:     vol.sizeGiB,
+		encrypted:   vol.encrypted,
+		kmsKeyID:    vol.kmsKeyID,
+		description: aws.ToString(input.Description),
+	})
+	converted := snap.convert()
+	return &ec2.CreateSnapshotOutput{
+		SnapshotId:  converted.SnapshotId,
+		VolumeId:    converted.VolumeId,
+		VolumeSize:  converted.VolumeSize,
+		Encrypted:   converted.Encrypted,
+		State:       converted.State,
+		StartTime:   converted.StartTime,
+		Description: converted.Description,
+	}, nil
+}
+
+// copySnapshot copies a snapshot, encrypting the copy when asked to
+func (f *Fleet) copySnapshot(input *ec2.CopySnapshotInput) (*ec2.CopySnapshotOutput, error) {
+	source := f.findSnapshot(aws.ToString(input.SourceSnapshotId))
+	if source == nil {
+		return nil, apiError("InvalidSnapshot.NotFound", fmt.Sprintf("The snapshot '%s' does not exist.", aws.ToString(input.SourceSnapshotId)))
+	}
+	copied := *source
+	copied.description = aws.ToString(input.Description)
+	if aws.ToBool(input.Encrypted) {
+		copied.encrypted = true
+		copied.kmsKeyID = aws.ToString(input.KmsKeyId)
+		if copied.kmsKeyID == "" {
+			copied.kmsKeyID = "alias/aws/ebs"
+		}
+	}
+	snap := f.addSnapshot(copied)
+	return &ec2.CopySnapshotOutput{SnapshotId: aws.String(snap.id)}, nil
+}
+
+// deleteSnapshot removes a snapshot
+func (f *Fleet) deleteSnapshot(input *ec2.DeleteSnapshotInput) error {
+	for i, snap := range f.snapshots {
+		if snap.id == aws.ToString(input.SnapshotId) {
+			f.snapshots = append(f.snapshots[:i], f.snapshots[i+1:]...)
+			return nil
+		}
+	}
+	return apiError("InvalidSnapshot.NotFound", fmt.Sprintf("The snapshot '%s' does not exist.", aws.ToString(input.SnapshotId)))
+}
+
+// createVolume creates an available volume, from a snapshot when given one
+func (f *Fleet) createVolume(input *ec2.CreateVolumeInput) (*ec2.CreateVolumeOutput, error) {
+	vol := &volume{
+		zone:       aws.ToString(input.AvailabilityZone),
+		sizeGiB:    aws.ToInt32(input.Size),
+		volumeType: string(input.VolumeType),
+		encrypted:  aws.ToBool(input.Encrypted),
+		kmsKeyID:   aws.ToString(input.KmsKeyId),
+		created:    f.now().UTC(),
+		tags:       make(map[string]string),
+	}
+	if vol.volumeType == "" {
+		vol.volumeType = "gp2"
+	}
+	if id := aws.ToString(input.SnapshotId); id != "" {
+		source := f.findSnapshot(id)
+		if source == nil {
+			return nil, apiError("InvalidSnapshot.NotFound", fmt.Sprintf("The snapshot '%s' does not exist.", id))
+		}
+		vol.snapshotID = id
+		vol.encrypted = vol.encrypted || source.encrypted
+		if vol.kmsKeyID == "" {
+			vol.kmsKeyID = source.kmsKeyID
+		}
+		if vol.sizeGiB == 0 {
+			vol.sizeGiB = source.sizeGiB
+		}
+	}
+	if vol.sizeGiB == 0 {
+		return nil, apiError("MissingParameter", "The request must contain the parameter size or snapshotId")
+	}
+	for _, spec := range input.TagSpecifications {
+		for _, tag := range spec.Tags {
+			vol.tags[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+		}
+	}
+
+	f.launchedVolumes++
+	vol.id = f.id("vol", "created", f.launchedVolumes)
+	f.volumes = append(f.volumes, vol)
+
+	converted := vol.convert()
+	return &ec2.CreateVolumeOutput{
+		VolumeId:         converted.VolumeId,
+		AvailabilityZone: converted.AvailabilityZone,
+		Size:             converted.Size,
+		VolumeType:       converted.VolumeType,
+		Encrypted:        converted.Encrypted,
+		State:            converted.State,
+	}, nil
+}
+
+// attachVolume attaches an available volume to an instance in its zone
+func (f *Fleet) attachVolume(input *ec2.AttachVolumeInput) (*ec2.AttachVolumeOutput, error) {
+	vol := f.findVolume(aws.ToString(input.VolumeId))
+	if vol == nil {
+		return nil, apiError("InvalidVolume.NotFound", fmt.Sprintf("The volume '%s' does not exist.", aws.ToString(input.VolumeId)))
+	}
+	instances, err := f.instancesByID([]string{aws.ToString(input.InstanceId)})
+	if err != nil {
+		return nil, err
+	}
+	inst := instances[0]
+	if vol.instance != "" {
+		return nil, apiError("VolumeInUse", fmt.Sprintf("%s is already attached to an instance", vol.id))
+	}
+	if vol.zone != inst.subnet.zone {
+		return nil, apiError("InvalidVolume.ZoneMismatch", fmt.Sprintf("The volume '%s' is not in the same availability zone as instance '%s'", vol.id, inst.id))
+	}
+	for _, other := range f.volumes {
+		if other.instance == inst.id && other.device == aws.ToString(input.Device) {
+			return nil, apiError("InvalidParameterValue", fmt.Sprintf("Attachment point %s is already in use", other.device))
+		}
+	}
+
+	vol.instance, vol.device = inst.id, aws.ToString(input.Device)
+	return &ec2.AttachVolumeOutput{
+		VolumeId:   aws.String(vol.id),
+		InstanceId: aws.String(inst.id),
+		Device:     aws.String(vol.device),
+		State:      ec2types.VolumeAttachmentStateAttached,
+	}, nil
+}
+
+// detachVolume detaches a volume from its instance
+func (f *Fleet) detachVolume(input *ec2.DetachVolumeInput) (*ec2.DetachVolumeOutput, error) {
+	vol := f.findVolume(aws.ToString(input.VolumeId))
+	if vol == nil {
+		return nil, apiError("InvalidVolume.NotFound", fmt.Sprintf("The volume '%s' does not exist.", aws.ToString(input.VolumeId)))
+	}
+	if vol.instance == "" || input.InstanceId != nil && aws.ToString(input.InstanceId) != vol.instance {
+		return nil, apiError("IncorrectState", fmt.Sprintf("Volume '%s' is in the 'available' state.", vol.id))
+	}
+
+	output := &ec2.DetachVolumeOutput{
+		VolumeId:   aws.String(vol.id),
+		InstanceId: aws.String(vol.instance),
+		Device:     aws.String(vol.device),
+		State:      ec2types.VolumeAttachmentStateDetached,
+	}
+	vol.instance, vol.device = "", ""
+	return output, nil
+}
+
+// addSnapshot stores a completed snapshot under a new ID
+func (f *Fleet) addSnapshot(snap snapshot) *snapshot {
+	f.launchedSnapshots++
+	snap.id = f.id("snap", f.launchedSnapshots)
+	snap.started = f.now().UTC()
+	f.snapshots = append(f.snapshots, &snap)
+	return &snap
+}
+
+// findVolume returns the volume with the ID, nil when there is none
+func (f *Fleet) findVolume(id string) *volume {
+	for _, vol := range f.volumes {
+		if vol.id == id {
+			return vol
+		}
+	}
+	return nil
+}
+
+// findSnapshot returns the snapshot with the ID, nil when there is none
+func (f *Fleet) findSnapshot(id string) *snapshot {
+	for _, snap := range f.snapshots {
+		if snap.id == id {
+			return snap
+		}
+	}
+	return nil
+}
+
+// convert returns the volume in its EC2 form
+func (v *volume) convert() ec2types.Volume {
+	converted := ec2types.Volume{
+		VolumeId:         aws.String(v.id),
+		AvailabilityZone: aws.String(v.zone),
+		Size:             aws.Int32(v.sizeGiB),
+		VolumeType:       ec2types.VolumeType(v.volumeType),
+		Encrypted:        aws.Bool(v.encrypted),
+		CreateTime:       aws.Time(v.created),
+		State:            ec2types.VolumeStateAvailable,
+	}
+	if v.kmsKeyID != "" {
+		converted.KmsKeyId = aws.String(v.kmsKeyID)
+	}
+	if v.snapshotID != "" {
+		converted.SnapshotId = aws.String(v.snapshotID)
+	}
+	if v.volumeType == "gp3" {
+		converted.Iops = aws.Int32(3000)
+		converted.Throughput = aws.Int32(125)
+	}
+	if v.instance != "" {
+		converted.State = ec2types.VolumeStateInUse
+		converted.Attachments = []ec2types.VolumeAttachment{{
+			VolumeId:            aws.String(v.id),
+			InstanceId:          aws.String(v.instance),
+			Device:              aws.String(v.device),
+			State:               ec2types.VolumeAttachmentStateAttached,
+			DeleteOnTermination: aws.Bool(v.device == rootDevice),
+		}}
+	}
+	for _, key := range sortedKeys(v.tags) {
+		converted.Tags = append(converted.Tags, ec2types.Tag{Key: aws.String(key), Value: aws.String(v.tags[key])})
+	}
+	return converted
+}
+
+// filterValue returns the values of a volume for a DescribeVolumes filter
+func (v *volume) filterValue(name string) ([]string, bool) {
+	switch name {
+	case "volume-id":
+		return []string{v.id}, true
+	case "attachment.instance-id":
+		return []string{v.instance}, true
+	case "encrypted":
+		return []string{fmt.Sprint(v.encrypted)}, true
+	case "status":
+		return []string{string(v.convert().State)}, true
+	}
+	return nil, false
+}
+
+// convert returns the snapshot in its EC2 form
+func (s *snapshot) convert() ec2types.Snapshot {
+	converted := ec2types.Snapshot{
+		SnapshotId:  aws.String(s.id),
+		VolumeId:    aws.String(s.volumeID),
+		VolumeSize:  aws.Int32(s.sizeGiB),
+		Encrypted:   aws.Bool(s.encrypted),
+		Description: aws.String(s.description),
+		StartTime:   aws.Time(s.started),
+		State:       ec2types.SnapshotStateCompleted,
+		Progress:    aws.String("100%"),
+		OwnerId:     aws.String(AccountID),
+	}
+	if s.kmsKeyID != "" {
+		converted.KmsKeyId = aws.String(s.kmsKeyID)
+	}
+	return converted
+}
@@ -0,0 +1,638 @@
+package fake
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/smithy-go"
+)
+
+// stateCodes are the codes EC2 reports with each instance state
+var stateCodes = map[string]int32{
+	"pending":       0,
+	"running":       16,
+	"shutting-down": 32,
+	"terminated":    48,
+	"stopping":      64,
+	"stopped":       80,
+}
+
+// stateVerbs describe moving an instance to a state in errors
+var stateVerbs = map[string]string{"running": "started", "stopped": "stopped"}
+
+// handleEC2 answers the EC2 operations behind the instance resources and tools
+func (f *Fleet) handleEC2(operation string, params interface{}) (interface{}, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	switch input := params.(type) {
+	case *ec2.DescribeInstancesInput:
+		return f.describeInstances(input)
+	case *ec2.DescribeInstanceStatusInput:
+		return f.describeInstanceStatus(input)
+	case *ec2.StartInstancesInput:
+		changes, err := f.changeState(input.InstanceIds, "pending", "running")
+		return &ec2.StartInstancesOutput{StartingInstances: changes}, err
+	case *ec2.StopInstancesInput:
+		changes, err := f.changeState(input.InstanceIds, "stopping", "stopped")
+		return &ec2.StopInstancesOutput{StoppingInstances: changes}, err
+	case *ec2.TerminateInstancesInput:
+		changes, err := f.changeState(input.InstanceIds, "shutting-down", "terminated")
+		return &ec2.TerminateInstancesOutput{TerminatingInstances: changes}, err
+	case *ec2.RebootInstancesInput:
+		_, err := f.instancesByID(input.InstanceIds)
+		return &ec2.RebootInstancesOutput{}, err
+	case *ec2.RunInstancesInput:
+		return f.runInstances(input)
+	case *ec2.CreateTagsInput:
+		return &ec2.CreateTagsOutput{}, f.createTags(input)
+	case *ec2.DeleteTagsInput:
+		return &ec2.DeleteTagsOutput{}, f.deleteTags(input)
+	case *ec2.DescribeTagsInput:
+		return f.describeTags(input)
+	case *ec2.DescribeRegionsInput:
+		return &ec2.DescribeRegionsOutput{Regions: []ec2types.Region{{
+			RegionName:  aws.String(f.region),
+			Endpoint:    aws.String("ec2." + f.region + ".amazonaws.com"),
+			OptInStatus: aws.String("opt-in-not-required"),
+		}}}, nil
+	case *ec2.DescribeVpcsInput:
+		return f.describeVpcs(input)
+	case *ec2.DescribeSubnetsInput:
+		return f.describeSubnets(input)
+	case *ec2.DescribeSecurityGroupsInput:
+		return f.describeSecurityGroups(input)
+	case *ec2.DescribeVolumesInput:
+		return f.describeVolumes(input)
+	case *ec2.CreateVolumeInput:
+		return f.createVolume(input)
+	case *ec2.AttachVolumeInput:
+		return f.attachVolume(input)
+	case *ec2.DetachVolumeInput:
+		return f.detachVolume(input)
+	case *ec2.DescribeSnapshotsInput:
+		return f.describeSnapshots(input)
+	case *ec2.CreateSnapshotInput:
+		return f.createSnapshot(input)
+	case *ec2.CopySnapshotInput:
+		return f.copySnapshot(input)
+	case *ec2.DeleteSnapshotInput:
+		return &ec2.DeleteSnapshotOutput{}, f.deleteSnapshot(input)
+	default:
+		return nil, unsupported("EC2", operation)
+	}
+}
+
+// describeInstances lists the instances matching the IDs and filters, a page
+// of MaxResults at a time when it is set. NextToken is the offset of the page.
+func (f *Fleet) describeInstances(input *ec2.DescribeInstancesInput) (*ec2.DescribeInstancesOutput, error) {
+	candidates := f.instances
+	if len(input.InstanceIds) > 0 {
+		var err error
+		if candidates, err = f.instancesByID(input.InstanceIds); err != nil {
+			return nil, err
+		}
+	}
+
+	var instances []ec2types.Instance
+	for _, inst := range candidates {
+		ok, err := matchFilters(input.Filters, func(name string) ([]string, bool) {
+			return f.instanceFilterValue(inst, name)
+		})
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			instances = append(instances, f.convertInstance(inst))
+		}
+	}
+
+	output := &ec2.DescribeInstancesOutput{}
+	if input.MaxResults != nil {
+		offset := 0
+		if input.NextToken != nil {
+			var err error
+			if offset, err = strconv.Atoi(*input.NextToken); err != nil || offset < 0 || offset > len(instances) {
+				return nil, apiError("InvalidParameterValue", "The NextToken is invalid")
+			}
+		}
+		end := min(offset+int(*input.MaxResults), len(instances))
+		if end < len(instances) {
+			output.NextToken = aws.String(strconv.Itoa(end))
+		}
+		instances = instances[offset:end]
+	}
+	if len(instances) > 0 {
+		output.Reservations = []ec2types.Reservation{{
+			ReservationId: aws.String(f.id("r", "fleet")),
+			OwnerId:       aws.String(AccountID),
+			Instances:     instances,
+		}}
+	}
+	return output, nil
+}
+
+// describeInstanceStatus reports status checks, which pass on every running instance
+func (f *Fleet) describeInstanceStatus(input *ec2.DescribeInstanceStatusInput) (*ec2.DescribeInstanceStatusOutput, error) {
+	candidates := f.instances
+	if len(input.InstanceIds) > 0 {
+		var err error
+		if candidates, err = f.instancesByID(input.InstanceIds); err != nil {
+			return nil, err
+		}
+	}
+
+	output := &ec2.DescribeInstanceStatusOutput{}
+	for _, inst := range candidates {
+		if inst.state != "running" && !aws.ToBool(input.IncludeAllInstances) {
+			continue
+		}
+		status := ec2types.SummaryStatusNotApplicable
+		if inst.state == "running" {
+			status = ec2types.SummaryStatusOk
+		}
+		output.InstanceStatuses = append(output.InstanceStatuses, ec2types.InstanceStatus{
+			InstanceId:       aws.String(inst.id),
+			AvailabilityZone: aws.String(inst.subnet.zone),
+			InstanceState:    instanceState(inst.state),
+			InstanceStatus:   &ec2types.InstanceStatusSummary{Status: status},
+			SystemStatus:     &ec2types.InstanceStatusSummary{Status: status},
+		})
+	}
+	return output, nil
+}
+
+// changeState moves instances to a new state. The change takes effect at
+// once, but is reported as the transition EC2 would report.
+func (f *Fleet) changeState(ids []string, transition, target string) ([]ec2types.InstanceStateChange, error) {
+	instances, err := f.instancesByID(ids)
+	if err != nil {
+		return nil, err
+	}
+	for _, inst := range instances {
+		if inst.state == "terminated" && target != "terminated" {
+			return nil, apiError("IncorrectInstanceState", fmt.Sprintf("The instance '%s' is not in a state from which it can be %s.", inst.id, stateVerbs[target]))
+		}
+	}
+
+	now := f.now().UTC()
+	changes := make([]ec2types.InstanceStateChange, 0, len(instances))
+	for _, inst := range instances {
+		previous := inst.state
+		current := transition
+		if previous == target {
+			current = target
+		}
+		switch {
+		case target == "running" && previous != "running":
+			inst.up, inst.down = now, time.Time{}
+		case target != "running" && previous == "running":
+			inst.down = now
+		}
+		inst.state = target
+		changes = append(changes, ec2types.InstanceStateChange{
+			InstanceId:    aws.String(inst.id),
+			PreviousState: instanceState(previous),
+			CurrentState:  instanceState(current),
+		})
+	}
+	return changes, nil
+}
+
+// runInstances launches instances into the fleet's VPC. They are running
+// right away and show little load.
+func (f *Fleet) runInstances(input *ec2.RunInstancesInput) (*ec2.RunInstancesOutput, error) {
+	if aws.ToString(input.ImageId) == "" {
+		return nil, apiError("MissingParameter", "The request must contain the parameter ImageId")
+	}
+	instanceType := string(input.InstanceType)
+	if instanceType == "" {
+		instanceType = "m1.small"
+	}
+
+	sub := f.subnets[0]
+	if id := aws.ToString(input.SubnetId); id != "" {
+		found := false
+		for _, candidate := range f.subnets {
+			if candidate.id == id {
+				sub, found = candidate, true
+			}
+		}
+		if !found {
+			return nil, apiError("InvalidSubnetID.NotFound", fmt.Sprintf("The subnet ID '%s' does not exist", id))
+		}
+	}
+	// Without security groups instances get the internal group, like the
+	// default group of a VPC
+	groups := []securityGroup{f.groups[len(f.groups)-1]}
+	if len(input.SecurityGroupIds) > 0 {
+		groups = nil
+		for _, id := range input.SecurityGroupIds {
+			group, ok := f.findGroup(id)
+			if !ok {
+				return nil, apiError("InvalidGroup.NotFound", fmt.Sprintf("The security group '%s' does not exist", id))
+			}
+			groups = append(groups, group)
+		}
+	}
+
+	tags := make(map[string]string)
+	for _, spec := range input.TagSpecifications {
+		if spec.ResourceType == ec2types.ResourceTypeInstance {
+			for _, tag := range spec.Tags {
+				tags[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+			}
+		}
+	}
+
+	now := f.now().UTC()
+	count := max(1, int(aws.ToInt32(input.MinCount)))
+	output := &ec2.RunInstancesOutput{ReservationId: aws.String(f.id("r", "launch", f.launched)), OwnerId: aws.String(AccountID)}
+	for range count {
+		f.launched++
+		inst := &instance{
+			id:           f.id("i", "launched", f.launched),
+			profile:      launchedProfile,
+			instanceType: instanceType,
+			imageID:      aws.ToString(input.ImageId),
+			subnet:       sub,
+			privateIP:    fmt.Sprintf("10.0.%d.%d", 200+f.launched/250, 1+f.launched%250),
+			groups:       groups,
+			launchTime:   now,
+			state:        "running",
+			tags:         copyTags(tags),
+			up:           now,
+		}
+		f.instances = append(f.instances, inst)
+
+		converted := f.convertInstance(inst)
+		converted.State = instanceState("pending")
+		output.Instances = append(output.Instances, converted)
+	}
+	return output, nil
+}
+
+// createTags sets tags on instances, overwriting existing values
+func (f *Fleet) createTags(input *ec2.CreateTagsInput) error {
+	instances, err := f.instancesByID(input.Resources)
+	if err != nil {
+		return err
+	}
+	for _, inst := range instances {
+		for _, tag := range input.Tags {
+			inst.tags[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+		}
+	}
+	return nil
+}
+
+// deleteTags removes tags from instances; a tag with a value is only
+// removed while it has that value, and no tags removes them all
+func (f *Fleet) deleteTags(input *ec2.DeleteTagsInput) error {
+	instances, err := f.instancesByID(input.Resources)
+	if err != nil {
+		return err
+	}
+	for _, inst := range instances {
+		if input.Tags == nil {
+			inst.tags = make(map[string]string)
+			continue
+		}
+		for _, tag := range input.Tags {
+			key := aws.ToString(tag.Key)
+			if tag.Value == nil || inst.tags[key] == aws.ToString(tag.Value) {
+				delete(inst.tags, key)
+			}
+		}
+	}
+	return nil
+}
+
+// describeTags lists the tags of the instances matching the filters
+func (f *Fleet) describeTags(input *ec2.DescribeTagsInput) (*ec2.DescribeTagsOutput, error) {
+	output := &ec2.DescribeTagsOutput{}
+	for _, inst := range f.instances {
+		for _, key := range sortedKeys(inst.tags) {
+			value := inst.tags[key]
+			ok, err := matchFilters(input.Filters, func(name string) ([]string, bool) {
+				switch name {
+				case "resource-id":
+					return []string{inst.id}, true
+				case "resource-type":
+					return []string{"instance"}, true
+				case "key":
+					return []string{key}, true
+				case "value":
+					return []string{value}, true
+				}
+				return nil, false
+			})
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				output.Tags = append(output.Tags, ec2types.TagDescription{
+					ResourceId:   aws.String(inst.id),
+					ResourceType: ec2types.ResourceTypeInstance,
+					Key:          aws.String(key),
+					Value:        aws.String(value),
+				})
+			}
+		}
+	}
+	return output, nil
+}
+
+// describeVpcs lists the fleet's VPC, which is not the default VPC
+func (f *Fleet) describeVpcs(input *ec2.DescribeVpcsInput) (*ec2.DescribeVpcsOutput, error) {
+	ok, err := matchFilters(input.Filters, func(name string) ([]string, bool) {
+		switch name {
+		case "vpc-id":
+			return []string{f.vpcID}, true
+		case "is-default":
+			return []string{"false"}, true
+		case "state":
+			return []string{"available"}, true
+		}
+		return nil, false
+	})
+	if err != nil || !ok || !contains(input.VpcIds, f.vpcID) {
+		return &ec2.DescribeVpcsOutput{}, err
+	}
+	return &ec2.DescribeVpcsOutput{Vpcs: []ec2types.Vpc{{
+		VpcId:     aws.String(f.vpcID),
+		CidrBlock: aws.String("10.0.0.0/16"),
+		IsDefault: aws.Bool(false),
+		State:     ec2types.VpcStateAvailable,
+		OwnerId:   aws.String(AccountID),
+		Tags:      []ec2types.Tag{{Key: aws.String("Name"), Value: aws.String("main")}},
+	}}}, nil
+}
+
+// describeSubnets lists the subnets of the fleet's VPC
+func (f *Fleet) describeSubnets(input *ec2.DescribeSubnetsInput) (*ec2.DescribeSubnetsOutput, error) {
+	output := &ec2.DescribeSubnetsOutput{}
+	for _, sub := range f.subnets {
+		ok, err := matchFilters(input.Filters, func(name string) ([]string, bool) {
+			switch name {
+			case "subnet-id":
+				return []string{sub.id}, true
+			case "vpc-id":
+				return []string{f.vpcID}, true
+			case "availability-zone":
+				return []string{sub.zone}, true
+			case "state":
+				return []string{"available"}, true
+			case "default-for-az":
+				return []string{"false"}, true
+			}
+			return nil, false
+		})
+		if err != nil {
+			return nil, err
+		}
+		if ok && contains(input.SubnetIds, sub.id) {
+			output.Subnets = append(output.Subnets, ec2types.Subnet{
+				SubnetId:         aws.String(sub.id),
+				VpcId:            aws.String(f.vpcID),
+				AvailabilityZone: aws.String(sub.zone),
+				CidrBlock:        aws.String(sub.cidr),
+				State:            ec2types.SubnetStateAvailable,
+				DefaultForAz:     aws.Bool(false),
+				OwnerId:          aws.String(AccountID),
+			})
+		}
+	}
+	return output, nil
+}
+
+// describeSecurityGroups lists the fleet's security groups with their rules
+func (f *Fleet) describeSecurityGroups(input *ec2.DescribeSecurityGroupsInput) (*ec2.DescribeSecurityGroupsOutput, error) {
+	output := &ec2.DescribeSecurityGroupsOutput{}
+	for _, group := range f.groups {
+		ok, err := matchFilters(input.Filters, func(name string) ([]string, bool) {
+			switch name {
+			case "group-id":
+				return []string{group.id}, true
+			case "group-name":
+				return []string{group.name}, true
+			case "vpc-id":
+				return []string{f.vpcID}, true
+			}
+			return nil, false
+		})
+		if err != nil {
+			return nil, err
+		}
+		if !ok || !contains(input.GroupIds, group.id) || !contains(input.GroupNames, group.name) {
+			continue
+		}
+
+		converted := ec2types.SecurityGroup{
+			GroupId:     aws.String(group.id),
+			GroupName:   aws.String(group.name),
+			Description: aws.String(group.description),
+			VpcId:       aws.String(f.vpcID),
+			OwnerId:     aws.String(AccountID),
+			IpPermissionsEgress: []ec2types.IpPermission{{
+				IpProtocol: aws.String("-1"),
+				IpRanges:   []ec2types.IpRange{{CidrIp: aws.String("0.0.0.0/0")}},
+			}},
+		}
+		for _, rule := range group.rules {
+			converted.IpPermissions = append(converted.IpPermissions, ec2types.IpPermission{
+				IpProtocol: aws.String("tcp"),
+				FromPort:   aws.Int32(rule.port),
+				ToPort:     aws.Int32(rule.port),
+				IpRanges:   []ec2types.IpRange{{CidrIp: aws.String(rule.cidr), Description: aws.String(rule.description)}},
+			})
+		}
+		output.SecurityGroups = append(output.SecurityGroups, converted)
+	}
+	return output, nil
+}
+
+// convertInstance converts an instance of the fleet to its EC2 form
+func (f *Fleet) convertInstance(inst *instance) ec2types.Instance {
+	converted := ec2types.Instance{
+		InstanceId:       aws.String(inst.id),
+		ImageId:          aws.String(inst.imageID),
+		InstanceType:     ec2types.InstanceType(inst.instanceType),
+		LaunchTime:       aws.Time(inst.launchTime),
+		Placement:        &ec2types.Placement{AvailabilityZone: aws.String(inst.subnet.zone)},
+		PrivateIpAddress: aws.String(inst.privateIP),
+		PrivateDnsName:   aws.String("ip-" + strings.ReplaceAll(inst.privateIP, ".", "-") + "." + f.region + ".compute.internal"),
+		VpcId:            aws.String(f.vpcID),
+		SubnetId:         aws.String(inst.subnet.id),
+		State:            instanceState(inst.state),
+		PlatformDetails:  aws.String("Linux/UNIX"),
+		Architecture:     ec2types.ArchitectureValuesX8664,
+		KeyName:          aws.String("ops"),
+	}
+	if strings.HasPrefix(inst.instanceType, "c6g") {
+		converted.Architecture = ec2types.ArchitectureValuesArm64
+	}
+	if inst.profile.windows {
+		converted.Platform = ec2types.PlatformValuesWindows
+		converted.PlatformDetails = aws.String("Windows")
+	}
+	if inst.publicIP != "" && inst.state == "running" {
+		converted.PublicIpAddress = aws.String(inst.publicIP)
+	}
+	for _, group := range inst.groups {
+		converted.SecurityGroups = append(converted.SecurityGroups, ec2types.GroupIdentifier{
+			GroupId:   aws.String(group.id),
+			GroupName: aws.String(group.name),
+		})
+	}
+	for _, key := range sortedKeys(inst.tags) {
+		converted.Tags = append(converted.Tags, ec2types.Tag{Key: aws.String(key), Value: aws.String(inst.tags[key])})
+	}
+	return converted
+}
+
+// instanceFilterValue returns the values of an instance for a
+// DescribeInstances filter
+func (f *Fleet) instanceFilterValue(i *instance, name string) ([]string, bool) {
+	if key, ok := strings.CutPrefix(name, "tag:"); ok {
+		if value, tagged := i.tags[key]; tagged {
+			return []string{value}, true
+		}
+		return nil, true
+	}
+	switch name {
+	case "instance-id":
+		return []string{i.id}, true
+	case "instance-state-name":
+		return []string{i.state}, true
+	case "instance-type":
+		return []string{i.instanceType}, true
+	case "vpc-id":
+		return []string{f.vpcID}, true
+	case "subnet-id":
+		return []string{i.subnet.id}, true
+	case "availability-zone":
+		return []string{i.subnet.zone}, true
+	case "tag-key":
+		return sortedKeys(i.tags), true
+	}
+	return nil, false
+}
+
+// instancesByID returns the instances with the IDs, failing like EC2 when
+// one does not exist
+func (f *Fleet) instancesByID(ids []string) ([]*instance, error) {
+	instances := make([]*instance, 0, len(ids))
+	for _, id := range ids {
+		inst := f.findInstance(id)
+		if inst == nil {
+			return nil, apiError("InvalidInstanceID.NotFound", fmt.Sprintf("The instance ID '%s' does not exist", id))
+		}
+		instances = append(instances, inst)
+	}
+	return instances, nil
+}
+
+// findGroup returns the security group with the ID
+func (f *Fleet) findGroup(id string) (securityGroup, bool) {
+	for _, group := range f.groups {
+		if group.id == id {
+			return group, true
+		}
+	}
+	return securityGroup{}, false
+}
+
+// matchFilters reports whether a resource matches every EC2 filter. A
+// filter matches when one of its values matches one of the resource's
+// values, with * as wildcard. Filters the fleet does not know fail the call
+// rather than being ignored.
+func matchFilters(filters []ec2types.Filter, valueOf func(name string) ([]string, bool)) (bool, error) {
+	for _, filter := range filters {
+		name := aws.ToString(filter.Name)
+		values, known := valueOf(name)
+		if !known {
+			return false, apiError("InvalidParameterValue", fmt.Sprintf("the fake provider does not support the filter %s", name))
+		}
+		matched := false
+		for _, want := range filter.Values {
+			for _, value := range values {
+				if wildcardMatch(want, value) {
+					matched = true
+				}
+			}
+		}
+		if !matched {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// wildcardMatch matches a value against a filter value with * wildcards
+func wildcardMatch(pattern, value string) bool {
+	parts := strings.Split(pattern, "*")
+	if len(parts) == 1 {
+		return pattern == value
+	}
+	if !strings.HasPrefix(value, parts[0]) {
+		return false
+	}
+	value = value[len(parts[0]):]
+	for _, part := range parts[1 : len(parts)-1] {
+		index := strings.Index(value, part)
+		if index < 0 {
+			return false
+		}
+		value = value[index+len(part):]
+	}
+	return strings.HasSuffix(value, parts[len(parts)-1])
+}
+
+// instanceState returns the EC2 form of an instance state
+func instanceState(name string) *ec2types.InstanceState {
+	return &ec2types.InstanceState{Name: ec2types.InstanceStateName(name), Code: aws.Int32(stateCodes[name])}
+}
+
+// apiError fails a call the way the AWS API does
+func apiError(code, message string) error {
+	return &smithy.GenericAPIError{Code: code, Message: message, Fault: smithy.FaultClient}
+}
+
+// contains reports whether ids is empty, meaning no restriction, or holds id
+func contains(ids []string, id string) bool {
+	if len(ids) == 0 {
+		return true
+	}
+	for _, candidate := range ids {
+		if candidate == id {
+			return true
+		}
+	}
+	return false
+}
+
+// copyTags returns a copy of a tag map
+func copyTags(tags map[string]string) map[string]string {
+	copied := make(map[string]string, len(tags))
+	for key, value := range tags {
+		copied[key] = value
+	}
+	return copied
+}
+
+// sortedKeys returns the keys of a tag map in order
+func sortedKeys(tags map[string]string) []string {
+	keys := make([]string, 0, len(tags))
+	for key := range tags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
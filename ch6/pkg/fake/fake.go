@@ -0,0 +1,208 @@
+// Package fake serves a generated AWS fleet to the server's SDK clients, so
+// readers without an AWS account can follow the book's exercises end to end.
+// The fleet has EC2 instances across a few services and teams, their root
+// volumes, CloudWatch alarms on them and metrics with injected anomalies to
+// find, plus daily cost per service. The same seed always generates the same fleet.
+package fake
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsmiddleware "github.com/aws/aws-sdk-go-v2/aws/middleware"
+	"github.com/aws/smithy-go"
+	"github.com/aws/smithy-go/middleware"
+
+	"aws-mcp-server/internal/config"
+)
+
+// AccountID is the account the fleet runs in
+const AccountID = "123456789012"
+
+// defaultInstances sizes the fleet when aws.fake.instances is not set
+const defaultInstances = 12
+
+// Fleet is a generated AWS account. It answers the calls of the SDK clients
+// it is configured into and keeps the changes tools make, e.g. stopping or
+// tagging an instance, for the rest of the run.
+type Fleet struct {
+	seed    int64
+	region  string
+	started time.Time
+	now     func() time.Time
+
+	mu        sync.Mutex
+	vpcID     string
+	subnets   []subnet
+	groups    []securityGroup
+	instances []*instance
+	volumes   []*volume
+	snapshots []*snapshot
+	alarms    []alarm
+	anomalies []Anomaly
+	launched  int
+	// launchedVolumes and launchedSnapshots number the volumes and
+	// snapshots tools create
+	launchedVolumes   int
+	launchedSnapshots int
+}
+
+// subnet is a subnet of the fleet's VPC
+type subnet struct {
+	id   string
+	zone string
+	cidr string
+}
+
+// Anomaly is an injected deviation of an instance metric. An anomaly without
+// End is still ongoing.
+type Anomaly struct {
+	InstanceID  string    `json:"instance_id"`
+	Name        string    `json:"name"`
+	Metric      string    `json:"metric"`
+	Description string    `json:"description"`
+	Start       time.Time `json:"start"`
+	End         time.Time `json:"end,omitempty"`
+}
+
+// active reports whether the anomaly affects the datapoint at t
+func (a Anomaly) active(t time.Time) bool {
+	return !t.Before(a.Start) && (a.End.IsZero() || t.Before(a.End))
+}
+
+// New generates the fleet for the configuration. Anomalies are placed
+// relative to now, so a fresh run always has an incident in progress.
+func New(cfg config.FakeConfig, region string) *Fleet {
+	f := &Fleet{
+		seed:    cfg.Seed,
+		region:  region,
+		started: time.Now().UTC().Truncate(time.Minute),
+		now:     time.Now,
+	}
+	instances := cfg.Instances
+	if instances <= 0 {
+		instances = defaultInstances
+	}
+	f.generate(instances)
+	return f
+}
+
+// Anomalies returns the injected anomalies, oldest first
+func (f *Fleet) Anomalies() []Anomaly {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	anomalies := append([]Anomaly(nil), f.anomalies...)
+	sort.Slice(anomalies, func(i, j int) bool { return anomalies[i].Start.Before(anomalies[j].Start) })
+	return anomalies
+}
+
+// InstanceCount returns how many instances the fleet has
+func (f *Fleet) InstanceCount() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.instances)
+}
+
+// InstanceIDs returns the IDs of the fleet's instances, in launch order
+func (f *Fleet) InstanceIDs() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	ids := make([]string, len(f.instances))
+	for i, instance := range f.instances {
+		ids[i] = instance.id
+	}
+	return ids
+}
+
+// Config returns an AWS configuration whose service clients are answered by
+// the fleet. Calls never leave the process, so no credentials are needed.
+func (f *Fleet) Config() aws.Config {
+	return aws.Config{
+		Region:      f.region,
+		Credentials: aws.AnonymousCredentials{},
+		APIOptions:  []func(*middleware.Stack) error{f.register},
+	}
+}
+
+// register adds the fleet to a service client's stack. Cross-region copies,
+// e.g. CopySnapshot, would presign a URL for the source region through a
+// second call the fleet answers too; nothing leaves the process, so the
+// presigning is dropped.
+func (f *Fleet) register(stack *middleware.Stack) error {
+	if _, ok := stack.Initialize.Get("Presign"); ok {
+		if _, err := stack.Initialize.Remove("Presign"); err != nil {
+			return err
+		}
+	}
+	return stack.Serialize.Add(f, middleware.Before)
+}
+
+// ID names the middleware in the SDK's middleware stacks
+func (f *Fleet) ID() string {
+	return "FakeFleet"
+}
+
+// HandleSerialize answers a call with the fleet's output instead of sending
+// it. It runs after input validation and the server's hooks, so they see the
+// call as if it went to AWS.
+func (f *Fleet) HandleSerialize(ctx context.Context, in middleware.SerializeInput, next middleware.SerializeHandler) (middleware.SerializeOutput, middleware.Metadata, error) {
+	service := awsmiddleware.GetServiceID(ctx)
+	operation := awsmiddleware.GetOperationName(ctx)
+
+	var output interface{}
+	var err error
+	switch service {
+	case "EC2":
+		output, err = f.handleEC2(operation, in.Parameters)
+	case "CloudWatch":
+		output, err = f.handleCloudWatch(operation, in.Parameters)
+	case "RDS":
+		output, err = f.handleRDS(operation, in.Parameters)
+	case "Cost Explorer":
+		output, err = f.handleCostExplorer(operation, in.Parameters)
+	case "STS":
+		output, err = f.handleSTS(operation, in.Parameters)
+	default:
+		err = unsupported(service, operation)
+	}
+	return middleware.SerializeOutput{Result: output}, middleware.Metadata{}, err
+}
+
+// unsupported fails a call the fleet does not serve
+func unsupported(service, operation string) error {
+	return &smithy.GenericAPIError{
+		Code:    "UnsupportedOperation",
+		Message: fmt.Sprintf("the fake provider does not serve %s %s", service, operation),
+		Fault:   smithy.FaultClient,
+	}
+}
+
+// unit returns a number in [0, 1) derived from the seed and the key, so the
+// fleet is the same for the same seed
+func (f *Fleet) unit(key ...interface{}) float64 {
+	return float64(f.hash(key...)>>11) / (1 << 53)
+}
+
+// hash derives a number from the seed and the key
+func (f *Fleet) hash(key ...interface{}) uint64 {
+	h := fnv.New64a()
+	fmt.Fprint(h, f.seed)
+	for _, part := range key {
+		fmt.Fprintf(h, "/%v", part)
+	}
+	// Mix the bits, so similar keys get unrelated numbers
+	x := h.Sum64()
+	x = (x ^ x>>30) * 0xbf58476d1ce4e5b9
+	x = (x ^ x>>27) * 0x94d049bb133111eb
+	return x ^ x>>31
+}
+
+// id returns a resource ID with the prefix, e.g. i-0a1b2c3d4e5f67890
+func (f *Fleet) id(prefix string, key ...interface{}) string {
+	return fmt.Sprintf("%s-%017x", prefix, f.hash(append([]interface{}{prefix}, key...)...))
+}
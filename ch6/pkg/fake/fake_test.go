@@ -0,0 +1,161 @@
+package fake
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"aws-mcp-server/internal/config"
+	"aws-mcp-server/internal/logging"
+	"aws-mcp-server/pkg/aws"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestClient(t *testing.T) (*Fleet, *aws.Client) {
+	t.Helper()
+	fleet := New(config.FakeConfig{Seed: 7}, "us-east-1")
+	return fleet, aws.NewClientFromConfig(fleet.Config(), logging.NewLogger("error", "text"))
+}
+
+func TestFleetIsGeneratedFromSeed(t *testing.T) {
+	first := New(config.FakeConfig{Seed: 7, Instances: 6}, "us-east-1")
+	second := New(config.FakeConfig{Seed: 7, Instances: 6}, "us-east-1")
+	other := New(config.FakeConfig{Seed: 8, Instances: 6}, "us-east-1")
+
+	assert.Equal(t, 6, first.InstanceCount())
+	assert.Equal(t, first.instances[0].id, second.instances[0].id)
+	assert.NotEqual(t, first.instances[0].id, other.instances[0].id)
+	assert.Len(t, first.Anomalies(), 3)
+}
+
+func TestInstances(t *testing.T) {
+	fleet, client := newTestClient(t)
+	ctx := context.Background()
+
+	require.NoError(t, client.HealthCheck(ctx))
+	account, err := client.CallerAccount(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, AccountID, account)
+
+	instances, err := client.ListEC2Instances(ctx)
+	require.NoError(t, err)
+	require.Len(t, instances, defaultInstances)
+	states := make(map[string]int)
+	missingOwner := 0
+	for _, instance := range instances {
+		states[instance.State]++
+		if instance.Tags["Owner"] == "" {
+			missingOwner++
+		}
+	}
+	assert.Equal(t, 10, states["running"])
+	assert.Equal(t, 2, states["stopped"])
+	assert.Equal(t, 2, missingOwner)
+
+	_, err = client.GetEC2Instance(ctx, "i-0000000000000dead")
+	assert.ErrorContains(t, err, "InvalidInstanceID.NotFound")
+
+	id := fleet.Anomalies()[0].InstanceID
+	require.NoError(t, client.StopEC2Instance(ctx, id))
+	instance, err := client.GetEC2Instance(ctx, id)
+	require.NoError(t, err)
+	assert.Equal(t, "stopped", instance.State)
+
+	require.NoError(t, client.TagEC2Resources(ctx, []string{id}, map[string]string{"Ticket": "INC-1"}))
+	instance, err = client.GetEC2Instance(ctx, id)
+	require.NoError(t, err)
+	assert.Equal(t, "INC-1", instance.Tags["Ticket"])
+
+	_, err = client.ListSQSQueues(ctx)
+	assert.ErrorContains(t, err, "the fake provider does not serve SQS ListQueues")
+}
+
+func TestAnomaliesShowInMetricsAndAlarms(t *testing.T) {
+	fleet, client := newTestClient(t)
+	ctx := context.Background()
+
+	var spike Anomaly
+	for _, anomaly := range fleet.Anomalies() {
+		if anomaly.Metric == "CPUUtilization" {
+			spike = anomaly
+		}
+	}
+	require.NotEmpty(t, spike.InstanceID)
+	assert.True(t, spike.End.IsZero(), "the CPU spike is still going on")
+
+	now := time.Now()
+	data, err := client.GetMetricData(ctx, aws.MetricDataParams{
+		Namespace:  "AWS/EC2",
+		MetricName: "CPUUtilization",
+		Dimensions: map[string]string{"InstanceId": spike.InstanceID},
+		Statistic:  "Average",
+		Period:     300,
+		Start:      now.Add(-2 * time.Hour),
+		End:        now,
+	})
+	require.NoError(t, err)
+	require.NotEmpty(t, data.Datapoints)
+	latest := data.Datapoints[len(data.Datapoints)-1]
+	assert.Greater(t, latest.Value, 85.0)
+	assert.Less(t, data.Datapoints[0].Value, 80.0, "CPU was normal before the spike")
+	assert.True(t, data.Datapoints[0].Timestamp.Before(latest.Timestamp), "datapoints are oldest first")
+
+	alarms, err := client.ListAlarms(ctx)
+	require.NoError(t, err)
+	firing := make(map[string]string)
+	for _, alarm := range alarms {
+		if alarm.State == "ALARM" {
+			firing[alarm.Name] = alarm.Dimensions["InstanceId"]
+		}
+	}
+	assert.Len(t, firing, 2, "the CPU spike and the drained credits fire; the network spike is over")
+	assert.Contains(t, firing, spike.Name+"-cpu-high")
+
+	history, err := client.DescribeAlarmHistory(ctx, spike.Name+"-cpu-high", 10)
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+	assert.Equal(t, "ALARM", history[0].NewState, "newest first")
+	assert.Equal(t, "OK", history[0].OldState)
+	assert.False(t, history[0].Time.Before(spike.Start))
+
+	changes, err := client.DescribeAlarmStateChanges(ctx, now.Add(-4*time.Hour), now, 100)
+	require.NoError(t, err)
+	var resolved bool
+	for _, change := range changes {
+		if change.OldState == "ALARM" && change.NewState == "OK" {
+			resolved = true
+		}
+	}
+	assert.True(t, resolved, "the network spike came and went")
+}
+
+func TestCost(t *testing.T) {
+	_, client := newTestClient(t)
+
+	end := time.Now().UTC().Truncate(24 * time.Hour)
+	periods, err := client.GetCostAndUsage(context.Background(), aws.CostQuery{
+		Start:       end.AddDate(0, 0, -7),
+		End:         end,
+		Granularity: "DAILY",
+		Metric:      "UnblendedCost",
+		GroupBy:     "SERVICE",
+	})
+	require.NoError(t, err)
+	require.Len(t, periods, 7)
+	assert.Equal(t, "USD", periods[0].Unit)
+	assert.Greater(t, periods[0].Total, 50.0)
+
+	services := make(map[string]float64)
+	for _, group := range periods[0].Groups {
+		services[group.Key] = group.Amount
+	}
+	assert.Greater(t, services[ec2ComputeService], 0.0)
+	assert.Contains(t, services, "Amazon Relational Database Service")
+
+	_, err = client.GetCostAndUsage(context.Background(), aws.CostQuery{
+		Start: end.AddDate(0, 0, -7), End: end, Granularity: "DAILY", Metric: "UnblendedCost", GroupBy: "tag:Owner",
+	})
+	assert.ErrorContains(t, err, "groups cost by SERVICE")
+}
@@ -0,0 +1,350 @@
+package fake
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// profile is the kind of workload an instance runs, which sets its type,
+// owner and how busy it is over the day
+type profile struct {
+	role         string
+	instanceType string
+	owner        string
+	windows      bool
+	public       bool
+	// cpu is the mean CPU utilization and swing its daily amplitude, peaking
+	// at peakHour UTC
+	cpu      float64
+	swing    float64
+	peakHour float64
+	// network is the mean NetworkIn per minute in bytes; NetworkOut is twice as much
+	network float64
+}
+
+// profiles are the workloads of the fleet, assigned in turn
+var profiles = []profile{
+	{role: "web", instanceType: "m5.large", owner: "storefront", public: true, cpu: 35, swing: 20, peakHour: 15, network: 40e6},
+	{role: "api", instanceType: "c6g.xlarge", owner: "payments", cpu: 45, swing: 15, peakHour: 15, network: 25e6},
+	{role: "worker", instanceType: "t3.medium", owner: "data", cpu: 18, swing: 8, peakHour: 10, network: 5e6},
+	{role: "batch", instanceType: "r5.xlarge", owner: "data", cpu: 15, swing: 35, peakHour: 2, network: 10e6},
+	{role: "report", instanceType: "m5.xlarge", owner: "finance", windows: true, cpu: 12, swing: 10, peakHour: 8, network: 2e6},
+	{role: "bastion", instanceType: "t3.micro", owner: "platform", public: true, cpu: 2, swing: 1, peakHour: 9, network: 0.2e6},
+}
+
+// launchedProfile is the workload of instances launched during the run
+var launchedProfile = profile{role: "new", owner: "", cpu: 5, swing: 3, peakHour: 12, network: 1e6}
+
+// creditLimits are the CPU credit balances burstable instances accrue up to
+var creditLimits = map[string]float64{"t3.micro": 144, "t3.medium": 576}
+
+// instance is an EC2 instance of the fleet
+type instance struct {
+	id           string
+	profile      profile
+	instanceType string
+	imageID      string
+	subnet       subnet
+	privateIP    string
+	publicIP     string
+	groups       []securityGroup
+	launchTime   time.Time
+	state        string
+	tags         map[string]string
+	// up and down bound the time the instance was running; metrics only
+	// have datapoints in between. A running instance has no down.
+	up   time.Time
+	down time.Time
+}
+
+// securityGroup is a security group of the fleet's VPC
+type securityGroup struct {
+	id          string
+	name        string
+	description string
+	rules       []ingressRule
+}
+
+// ingressRule allows inbound TCP traffic to a port
+type ingressRule struct {
+	port        int32
+	cidr        string
+	description string
+}
+
+// running reports whether the instance produced metrics at t
+func (i *instance) running(t time.Time) bool {
+	return !t.Before(i.up) && (i.down.IsZero() || t.Before(i.down))
+}
+
+// alarm is a CloudWatch metric alarm on an instance metric. It is in ALARM
+// while its anomaly is active.
+type alarm struct {
+	name        string
+	description string
+	instance    *instance
+	metric      string
+	statistic   string
+	comparison  string
+	threshold   float64
+	created     time.Time
+	anomaly     *Anomaly
+}
+
+// generate builds n instances with alarms and injects the anomalies
+func (f *Fleet) generate(n int) {
+	f.vpcID = f.id("vpc", "main")
+	for i, zone := range []string{"a", "b", "c"} {
+		f.subnets = append(f.subnets, subnet{
+			id:   f.id("subnet", zone),
+			zone: f.region + zone,
+			cidr: fmt.Sprintf("10.0.%d.0/24", i*16),
+		})
+	}
+	// SSH open to the world on the web group is a finding to discover
+	webGroup := securityGroup{id: f.id("sg", "web"), name: "web", description: "Public web and bastion access", rules: []ingressRule{
+		{port: 443, cidr: "0.0.0.0/0", description: "HTTPS"},
+		{port: 80, cidr: "0.0.0.0/0", description: "HTTP redirect"},
+		{port: 22, cidr: "0.0.0.0/0", description: "temporary debug access"},
+	}}
+	internalGroup := securityGroup{id: f.id("sg", "internal"), name: "internal", description: "Traffic within the VPC", rules: []ingressRule{
+		{port: 443, cidr: "10.0.0.0/16", description: "internal APIs"},
+		{port: 5432, cidr: "10.0.0.0/16", description: "PostgreSQL"},
+	}}
+	f.groups = []securityGroup{webGroup, internalGroup}
+
+	counts := make(map[string]int)
+	for i := range n {
+		p := profiles[i%len(profiles)]
+		counts[p.role]++
+		sub := f.subnets[i%len(f.subnets)]
+
+		environment := "prod"
+		if counts[p.role]%3 == 0 {
+			environment = "staging"
+		}
+		tags := map[string]string{
+			"Name":        fmt.Sprintf("%s-%s-%02d", p.role, environment, counts[p.role]),
+			"Environment": environment,
+			"Service":     p.role,
+			"Owner":       p.owner,
+		}
+		// Some instances miss their owner, for the tag audit to find
+		if i%5 == 4 {
+			delete(tags, "Owner")
+		}
+
+		launched := f.started.Add(-time.Duration(7+f.hash("age", i)%90) * 24 * time.Hour).Truncate(time.Hour)
+		inst := &instance{
+			id:           f.id("i", i),
+			profile:      p,
+			instanceType: p.instanceType,
+			imageID:      f.id("ami", p.role),
+			subnet:       sub,
+			privateIP:    fmt.Sprintf("10.0.%d.%d", i%3*16, 10+i),
+			groups:       []securityGroup{internalGroup},
+			launchTime:   launched,
+			state:        "running",
+			tags:         tags,
+			up:           launched,
+		}
+		if p.public {
+			inst.publicIP = fmt.Sprintf("203.0.113.%d", 10+i)
+			inst.groups = append([]securityGroup{webGroup}, inst.groups...)
+		}
+		// Every sixth instance, staging ones mostly, sits stopped
+		if i%6 == 5 {
+			inst.state = "stopped"
+			inst.down = f.started.Add(-time.Duration(2+f.hash("stopped", i)%10) * 24 * time.Hour)
+		}
+		f.instances = append(f.instances, inst)
+	}
+
+	f.generateVolumes()
+	f.injectAnomalies()
+	f.createAlarms()
+}
+
+// injectAnomalies places the incidents of the run: a CPU spike still going
+// on, a burstable instance running out of CPU credits and a network spike
+// that came and went a few hours ago
+func (f *Fleet) injectAnomalies() {
+	if target := f.firstRunning("web"); target != nil {
+		f.anomalies = append(f.anomalies, Anomaly{
+			InstanceID:  target.id,
+			Name:        target.tags["Name"],
+			Metric:      "CPUUtilization",
+			Description: "CPU pinned above 90% since a runaway process started",
+			Start:       f.started.Add(-40 * time.Minute),
+		})
+	}
+	if target := f.firstRunning("worker"); target != nil {
+		f.anomalies = append(f.anomalies, Anomaly{
+			InstanceID:  target.id,
+			Name:        target.tags["Name"],
+			Metric:      "CPUCreditBalance",
+			Description: "CPU credits draining under sustained load until the instance is throttled",
+			Start:       f.started.Add(-6 * time.Hour),
+		})
+	}
+	if target := f.firstRunning("api"); target != nil {
+		f.anomalies = append(f.anomalies, Anomaly{
+			InstanceID:  target.id,
+			Name:        target.tags["Name"],
+			Metric:      "NetworkIn",
+			Description: "Inbound traffic eight times the usual during a retry storm",
+			Start:       f.started.Add(-3 * time.Hour),
+			End:         f.started.Add(-3*time.Hour + 25*time.Minute),
+		})
+	}
+}
+
+// createAlarms puts a CPU alarm on every running instance, a credit alarm on
+// burstable ones and an alarm on each anomaly's metric
+func (f *Fleet) createAlarms() {
+	for _, inst := range f.instances {
+		if inst.state != "running" {
+			continue
+		}
+		name := inst.tags["Name"]
+		created := inst.launchTime.Add(time.Hour)
+		f.alarms = append(f.alarms, alarm{
+			name:        name + "-cpu-high",
+			description: "CPU utilization of " + name + " above 80% for 10 minutes",
+			instance:    inst,
+			metric:      "CPUUtilization",
+			statistic:   "Average",
+			comparison:  "GreaterThanThreshold",
+			threshold:   80,
+			created:     created,
+			anomaly:     f.anomalyOf(inst.id, "CPUUtilization"),
+		})
+		if _, burstable := creditLimits[inst.instanceType]; burstable {
+			f.alarms = append(f.alarms, alarm{
+				name:        name + "-cpu-credits-low",
+				description: "CPU credit balance of " + name + " below 50, the instance is about to be throttled",
+				instance:    inst,
+				metric:      "CPUCreditBalance",
+				statistic:   "Minimum",
+				comparison:  "LessThanThreshold",
+				threshold:   50,
+				created:     created,
+				anomaly:     f.anomalyOf(inst.id, "CPUCreditBalance"),
+			})
+		}
+		if anomaly := f.anomalyOf(inst.id, "NetworkIn"); anomaly != nil {
+			f.alarms = append(f.alarms, alarm{
+				name:        name + "-network-in-high",
+				description: "Inbound traffic of " + name + " more than three times the usual",
+				instance:    inst,
+				metric:      "NetworkIn",
+				statistic:   "Sum",
+				comparison:  "GreaterThanThreshold",
+				threshold:   3 * inst.profile.network * 5,
+				created:     created,
+				anomaly:     anomaly,
+			})
+		}
+	}
+}
+
+// firstRunning returns the first running instance of a role
+func (f *Fleet) firstRunning(role string) *instance {
+	for _, inst := range f.instances {
+		if inst.profile.role == role && inst.state == "running" {
+			return inst
+		}
+	}
+	return nil
+}
+
+// anomalyOf returns the anomaly injected into an instance metric, if any
+func (f *Fleet) anomalyOf(instanceID, metric string) *Anomaly {
+	for i := range f.anomalies {
+		if f.anomalies[i].InstanceID == instanceID && f.anomalies[i].Metric == metric {
+			return &f.anomalies[i]
+		}
+	}
+	return nil
+}
+
+// findInstance returns the instance with the ID
+func (f *Fleet) findInstance(id string) *instance {
+	for _, inst := range f.instances {
+		if inst.id == id {
+			return inst
+		}
+	}
+	return nil
+}
+
+// metricValue returns the per-minute value of an instance metric at t, or
+// false when the fleet has no such metric. Values follow the instance's
+// daily pattern with some noise, bent by the anomalies active at t.
+func (f *Fleet) metricValue(inst *instance, metric string, t time.Time) (float64, bool) {
+	p := inst.profile
+	hour := float64(t.Hour()) + float64(t.Minute())/60
+	daily := math.Cos(2 * math.Pi * (hour - p.peakHour) / 24)
+	noise := f.unit(inst.id, metric, t.Unix()/60) - 0.5
+	anomaly := f.anomalyOf(inst.id, metric)
+
+	switch metric {
+	case "CPUUtilization":
+		value := p.cpu + p.swing*daily + 6*noise
+		if anomaly != nil && anomaly.active(t) {
+			value = 94 + 8*noise
+		}
+		return math.Max(0.5, math.Min(100, value)), true
+	case "NetworkIn", "NetworkOut":
+		value := p.network * (1 + 0.5*daily + 0.3*noise)
+		if metric == "NetworkOut" {
+			value *= 2
+		}
+		if anomaly != nil && anomaly.active(t) {
+			value *= 8
+		}
+		return value, true
+	case "CPUCreditBalance", "CPUCreditUsage":
+		limit, ok := creditLimits[inst.instanceType]
+		if !ok {
+			return 0, false
+		}
+		balance := limit - 0.05*limit*(1+noise)
+		if anomaly != nil && anomaly.active(t) {
+			// The balance drains to zero over five hours and stays there
+			drained := t.Sub(anomaly.Start).Hours() / 5
+			balance = math.Max(0, limit*(1-drained))
+		}
+		if metric == "CPUCreditUsage" {
+			return math.Max(0.01, (p.cpu+p.swing*daily)/100+0.05*noise), true
+		}
+		return balance, true
+	case "StatusCheckFailed", "StatusCheckFailed_Instance", "StatusCheckFailed_System":
+		return 0, true
+	default:
+		return 0, false
+	}
+}
+
+// statistic turns the per-minute value of a metric into a statistic over a
+// period of the given seconds
+func statistic(metric, stat string, value float64, period int32) float64 {
+	samples := math.Max(1, float64(period)/60)
+	switch stat {
+	case "Sum":
+		return value * samples
+	case "SampleCount":
+		return samples
+	case "Minimum":
+		return value * 0.85
+	case "Maximum":
+		if metric == "CPUUtilization" {
+			return math.Min(100, value*1.15)
+		}
+		return value * 1.15
+	default:
+		// Average and percentiles
+		return value
+	}
+}
@@ -0,0 +1,177 @@
+package gcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"aws-mcp-server/internal/config"
+	"aws-mcp-server/internal/logging"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/google"
+)
+
+// ProviderName identifies GCP resources among those of other cloud providers
+const ProviderName = "gcp"
+
+const (
+	// computeEndpoint is the Compute Engine REST API
+	computeEndpoint = "https://compute.googleapis.com/compute/v1/"
+	// computeScope is the OAuth scope needed to read and start/stop instances
+	computeScope = "https://www.googleapis.com/auth/compute"
+	// defaultTimeout applies when gcp.timeout is not configured
+	defaultTimeout = 30 * time.Second
+	// maxResponseSize bounds how much of an API response is read
+	maxResponseSize = 32 << 20
+)
+
+// errNotFound is returned for API calls answered with 404
+var errNotFound = errors.New("not found")
+
+// Client calls the Compute Engine API of one project
+type Client struct {
+	project  string
+	zones    map[string]bool
+	endpoint *url.URL
+	client   *http.Client
+	logger   *logging.Logger
+}
+
+// NewClient creates a client for the configured project. Credentials come from
+// the service account key in gcp.credentials_file or, without one, from
+// Application Default Credentials (GOOGLE_APPLICATION_CREDENTIALS, gcloud or
+// the metadata server).
+func NewClient(cfg config.GCPConfig, logger *logging.Logger) (*Client, error) {
+	if cfg.Project == "" {
+		return nil, errors.New("gcp.project is required")
+	}
+
+	ctx := context.Background()
+	var credentials *google.Credentials
+	var err error
+	if cfg.CredentialsFile != "" {
+		data, readErr := os.ReadFile(cfg.CredentialsFile)
+		if readErr != nil {
+			return nil, fmt.Errorf("failed to read GCP credentials: %w", readErr)
+		}
+		credentials, err = google.CredentialsFromJSON(ctx, data, computeScope)
+	} else {
+		credentials, err = google.FindDefaultCredentials(ctx, computeScope)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load GCP credentials: %w", err)
+	}
+
+	httpClient := oauth2.NewClient(ctx, credentials.TokenSource)
+	httpClient.Timeout = cfg.Timeout
+	if httpClient.Timeout <= 0 {
+		httpClient.Timeout = defaultTimeout
+	}
+
+	return newClient(cfg.Project, cfg.Zones, computeEndpoint, httpClient, logger)
+}
+
+// newClient creates a client against an endpoint with an authorized HTTP client
+func newClient(project string, zones []string, endpoint string, httpClient *http.Client, logger *logging.Logger) (*Client, error) {
+	base, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid compute endpoint %q: %w", endpoint, err)
+	}
+
+	c := &Client{
+		project:  project,
+		endpoint: base,
+		client:   httpClient,
+		logger:   logger,
+	}
+	if len(zones) > 0 {
+		c.zones = make(map[string]bool, len(zones))
+		for _, zone := range zones {
+			c.zones[zone] = true
+		}
+	}
+	return c, nil
+}
+
+// HealthCheck verifies access to the project
+func (c *Client) HealthCheck(ctx context.Context) error {
+	var project struct {
+		Name string `json:"name"`
+	}
+	if err := c.do(ctx, http.MethodGet, "projects/"+url.PathEscape(c.project), nil, &project); err != nil {
+		return fmt.Errorf("GCP health check failed: %w", err)
+	}
+	return nil
+}
+
+// do sends a request to a path below the project API and decodes the JSON
+// response into out
+func (c *Client) do(ctx context.Context, method, path string, params url.Values, out interface{}) error {
+	endpoint := c.endpoint.JoinPath(path)
+	if params != nil {
+		endpoint.RawQuery = params.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, endpoint.String(), nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("request to compute API failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode == http.StatusNotFound {
+		return errNotFound
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("compute API returned %s: %s", resp.Status, apiErrorMessage(data))
+	}
+
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
+
+// apiErrorMessage extracts the message of a Google API error response, or a
+// snippet of the body when it is not one
+func apiErrorMessage(data []byte) string {
+	var apiError struct {
+		Error struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(data, &apiError); err == nil && apiError.Error.Message != "" {
+		return apiError.Error.Message
+	}
+
+	snippet := bytes.TrimSpace(data)
+	if len(snippet) > 512 {
+		snippet = snippet[:512]
+	}
+	return string(snippet)
+}
+
+// lastSegment returns the name at the end of a resource URL, such as the zone
+// of https://www.googleapis.com/compute/v1/projects/p/zones/us-central1-a
+func lastSegment(resourceURL string) string {
+	return resourceURL[strings.LastIndex(resourceURL, "/")+1:]
+}
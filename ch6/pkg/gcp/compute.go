@@ -0,0 +1,242 @@
+package gcp
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"aws-mcp-server/pkg/types"
+
+	"github.com/sirupsen/logrus"
+)
+
+// instance is the part of a Compute Engine instance used here
+type instance struct {
+	ID                 string            `json:"id"`
+	Name               string            `json:"name"`
+	Zone               string            `json:"zone"`
+	MachineType        string            `json:"machineType"`
+	Status             string            `json:"status"`
+	StatusMessage      string            `json:"statusMessage"`
+	Labels             map[string]string `json:"labels"`
+	CreationTimestamp  string            `json:"creationTimestamp"`
+	LastStartTimestamp string            `json:"lastStartTimestamp"`
+	DeletionProtection bool              `json:"deletionProtection"`
+	NetworkInterfaces  []struct {
+		Network       string `json:"network"`
+		Subnetwork    string `json:"subnetwork"`
+		NetworkIP     string `json:"networkIP"`
+		AccessConfigs []struct {
+			NatIP string `json:"natIP"`
+		} `json:"accessConfigs"`
+	} `json:"networkInterfaces"`
+	Scheduling struct {
+		Preemptible       bool   `json:"preemptible"`
+		ProvisioningModel string `json:"provisioningModel"`
+	} `json:"scheduling"`
+}
+
+// aggregatedInstances is one page of instances across all zones
+type aggregatedInstances struct {
+	Items map[string]struct {
+		Instances []instance `json:"instances"`
+	} `json:"items"`
+	NextPageToken string `json:"nextPageToken"`
+}
+
+// operation is the part of a zonal operation used here
+type operation struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+}
+
+// ListInstances retrieves all instances of the project, limited to the
+// configured zones if any, ordered by zone and name
+func (c *Client) ListInstances(ctx context.Context) ([]types.CloudResource, error) {
+	start := time.Now()
+
+	instances, err := c.aggregatedInstances(ctx, "")
+	if err != nil {
+		c.logger.WithError(err).Error("Failed to list Compute Engine instances")
+		return nil, fmt.Errorf("failed to list instances: %w", err)
+	}
+
+	resources := make([]types.CloudResource, 0, len(instances))
+	for _, inst := range instances {
+		resources = append(resources, c.convertInstance(inst))
+	}
+
+	c.logger.WithFields(logrus.Fields{
+		"count":    len(resources),
+		"duration": time.Since(start),
+	}).Info("Retrieved Compute Engine instances")
+
+	return resources, nil
+}
+
+// GetInstance retrieves an instance by name, looking up its zone
+func (c *Client) GetInstance(ctx context.Context, name string) (*types.CloudResource, error) {
+	inst, err := c.findInstance(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	resource := c.convertInstance(*inst)
+	return &resource, nil
+}
+
+// StartInstance starts a stopped (TERMINATED) instance
+func (c *Client) StartInstance(ctx context.Context, name string) error {
+	return c.instanceAction(ctx, name, "start")
+}
+
+// StopInstance stops a running instance
+func (c *Client) StopInstance(ctx context.Context, name string) error {
+	return c.instanceAction(ctx, name, "stop")
+}
+
+// instanceAction runs a start or stop operation on an instance. The operation
+// is not awaited; the instance status shows its progress.
+func (c *Client) instanceAction(ctx context.Context, name, action string) error {
+	c.logger.WithField("instance", name).Infof("Requesting Compute Engine instance %s", action)
+
+	inst, err := c.findInstance(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	path := fmt.Sprintf("projects/%s/zones/%s/instances/%s/%s",
+		url.PathEscape(c.project), url.PathEscape(lastSegment(inst.Zone)), url.PathEscape(inst.Name), action)
+
+	var op operation
+	if err := c.do(ctx, http.MethodPost, path, nil, &op); err != nil {
+		c.logger.WithError(err).WithField("instance", name).Errorf("Failed to %s Compute Engine instance", action)
+		return fmt.Errorf("failed to %s instance %s: %w", action, name, err)
+	}
+
+	c.logger.WithFields(logrus.Fields{
+		"instance":  name,
+		"operation": op.Name,
+		"status":    op.Status,
+	}).Infof("Compute Engine instance %s initiated", action)
+	return nil
+}
+
+// findInstance returns the instance with a name. Names are unique per zone,
+// so a name used in several zones is an error.
+func (c *Client) findInstance(ctx context.Context, name string) (*instance, error) {
+	instances, err := c.aggregatedInstances(ctx, fmt.Sprintf("name = %q", name))
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up instance %s: %w", name, err)
+	}
+
+	switch len(instances) {
+	case 0:
+		return nil, fmt.Errorf("instance %s not found", name)
+	case 1:
+		return &instances[0], nil
+	default:
+		zones := make([]string, 0, len(instances))
+		for _, inst := range instances {
+			zones = append(zones, lastSegment(inst.Zone))
+		}
+		return nil, fmt.Errorf("instance name %s is used in zones %s; limit gcp.zones to one of them", name, strings.Join(zones, ", "))
+	}
+}
+
+// aggregatedInstances lists instances across zones with an optional API
+// filter, keeping those in the configured zones
+func (c *Client) aggregatedInstances(ctx context.Context, filter string) ([]instance, error) {
+	path := fmt.Sprintf("projects/%s/aggregated/instances", url.PathEscape(c.project))
+	params := url.Values{}
+	if filter != "" {
+		params.Set("filter", filter)
+	}
+
+	var instances []instance
+	for {
+		var page aggregatedInstances
+		if err := c.do(ctx, http.MethodGet, path, params, &page); err != nil {
+			if err == errNotFound {
+				return nil, fmt.Errorf("project %s not found", c.project)
+			}
+			return nil, err
+		}
+
+		for _, scope := range page.Items {
+			for _, inst := range scope.Instances {
+				if c.zones == nil || c.zones[lastSegment(inst.Zone)] {
+					instances = append(instances, inst)
+				}
+			}
+		}
+
+		if page.NextPageToken == "" {
+			break
+		}
+		params.Set("pageToken", page.NextPageToken)
+	}
+
+	sort.Slice(instances, func(i, j int) bool {
+		if instances[i].Zone != instances[j].Zone {
+			return instances[i].Zone < instances[j].Zone
+		}
+		return instances[i].Name < instances[j].Name
+	})
+	return instances, nil
+}
+
+// convertInstance converts a Compute Engine instance to our standard format.
+// Instances are identified by name; labels take the place of tags.
+func (c *Client) convertInstance(inst instance) types.CloudResource {
+	zone := lastSegment(inst.Zone)
+
+	details := &types.ComputeEngineInstanceDetails{
+		InstanceType:       lastSegment(inst.MachineType),
+		Zone:               zone,
+		InstanceID:         inst.ID,
+		StatusMessage:      inst.StatusMessage,
+		DeletionProtection: inst.DeletionProtection,
+		Spot:               inst.Scheduling.Preemptible || inst.Scheduling.ProvisioningModel == "SPOT",
+	}
+
+	if created, err := time.Parse(time.RFC3339, inst.CreationTimestamp); err == nil {
+		details.CreationTime = &created
+	}
+	if started, err := time.Parse(time.RFC3339, inst.LastStartTimestamp); err == nil {
+		details.LaunchTime = &started
+	}
+
+	if len(inst.NetworkInterfaces) > 0 {
+		nic := inst.NetworkInterfaces[0]
+		details.Network = lastSegment(nic.Network)
+		details.Subnetwork = lastSegment(nic.Subnetwork)
+		details.PrivateIPAddress = nic.NetworkIP
+		for _, access := range nic.AccessConfigs {
+			if access.NatIP != "" {
+				details.PublicIPAddress = access.NatIP
+				break
+			}
+		}
+	}
+
+	labels := inst.Labels
+	if labels == nil {
+		labels = make(map[string]string)
+	}
+
+	return types.CloudResource{
+		ID:       inst.Name,
+		Provider: ProviderName,
+		Type:     "compute-instance",
+		Region:   zone,
+		State:    strings.ToLower(inst.Status),
+		Tags:     labels,
+		Details:  details,
+		LastSeen: time.Now(),
+	}
+}
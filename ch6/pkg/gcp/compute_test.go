@@ -0,0 +1,123 @@
+package gcp
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"aws-mcp-server/internal/logging"
+	"aws-mcp-server/pkg/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const zoneURL = "https://www.googleapis.com/compute/v1/projects/shop/zones/"
+
+func TestListInstances(t *testing.T) {
+	var pageTokens []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/compute/v1/projects/shop/aggregated/instances", r.URL.Path)
+		pageTokens = append(pageTokens, r.URL.Query().Get("pageToken"))
+
+		if r.URL.Query().Get("pageToken") == "" {
+			w.Write([]byte(`{"items":{
+				"zones/us-central1-a":{"instances":[{"id":"1","name":"web-1","zone":"` + zoneURL + `us-central1-a",
+					"machineType":"` + zoneURL + `us-central1-a/machineTypes/e2-medium","status":"RUNNING",
+					"labels":{"environment":"prod"},"lastStartTimestamp":"2025-06-01T10:00:00.000-07:00",
+					"networkInterfaces":[{"network":"global/networks/default","networkIP":"10.0.0.2","accessConfigs":[{"natIP":"34.1.2.3"}]}]}]},
+				"zones/europe-west1-b":{"warning":{"code":"NO_RESULTS_ON_PAGE"}}},
+				"nextPageToken":"p2"}`))
+			return
+		}
+		w.Write([]byte(`{"items":{"zones/europe-west1-b":{"instances":[
+			{"id":"2","name":"batch-1","zone":"` + zoneURL + `europe-west1-b","machineType":"` + zoneURL + `europe-west1-b/machineTypes/n2-standard-8",
+			 "status":"TERMINATED","scheduling":{"provisioningModel":"SPOT"}}]}}}`))
+	}))
+	defer server.Close()
+
+	client, err := newClient("shop", nil, server.URL+"/compute/v1/", server.Client(), logging.NewLogger("error", "text"))
+	require.NoError(t, err)
+
+	instances, err := client.ListInstances(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"", "p2"}, pageTokens)
+
+	require.Len(t, instances, 2)
+	assert.Equal(t, "batch-1", instances[0].ID, "ordered by zone")
+	assert.Equal(t, "terminated", instances[0].State)
+	require.IsType(t, &types.ComputeEngineInstanceDetails{}, instances[0].Details)
+	assert.True(t, instances[0].Details.(*types.ComputeEngineInstanceDetails).Spot)
+
+	web := instances[1]
+	assert.Equal(t, "gcp", web.Provider)
+	assert.Equal(t, "compute-instance", web.Type)
+	assert.Equal(t, "us-central1-a", web.Region)
+	assert.Equal(t, "running", web.State)
+	assert.Equal(t, map[string]string{"environment": "prod"}, web.Tags)
+	details := web.Details.(*types.ComputeEngineInstanceDetails)
+	assert.Equal(t, "e2-medium", details.InstanceType)
+	assert.Equal(t, "10.0.0.2", details.PrivateIPAddress)
+	assert.Equal(t, "34.1.2.3", details.PublicIPAddress)
+	assert.NotNil(t, details.LaunchTime)
+
+	// Configured zones limit what is listed
+	zoned, err := newClient("shop", []string{"us-central1-a"}, server.URL+"/compute/v1/", server.Client(), logging.NewLogger("error", "text"))
+	require.NoError(t, err)
+	instances, err = zoned.ListInstances(context.Background())
+	require.NoError(t, err)
+	require.Len(t, instances, 1)
+	assert.Equal(t, "web-1", instances[0].ID)
+}
+
+func TestInstanceActions(t *testing.T) {
+	var actions []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Query().Get("filter") == `name = "web-1"`:
+			w.Write([]byte(`{"items":{"zones/us-central1-a":{"instances":[{"name":"web-1","zone":"` + zoneURL + `us-central1-a","status":"RUNNING"}]}}}`))
+		case r.Method == http.MethodGet && r.URL.Query().Get("filter") == `name = "api"`:
+			w.Write([]byte(`{"items":{
+				"zones/us-central1-a":{"instances":[{"name":"api","zone":"` + zoneURL + `us-central1-a"}]},
+				"zones/us-east1-b":{"instances":[{"name":"api","zone":"` + zoneURL + `us-east1-b"}]}}}`))
+		case r.Method == http.MethodGet:
+			w.Write([]byte(`{"items":{}}`))
+		case r.Method == http.MethodPost:
+			actions = append(actions, r.URL.Path)
+			w.Write([]byte(`{"name":"operation-1","status":"RUNNING"}`))
+		}
+	}))
+	defer server.Close()
+
+	client, err := newClient("shop", nil, server.URL+"/compute/v1/", server.Client(), logging.NewLogger("error", "text"))
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	require.NoError(t, client.StopInstance(ctx, "web-1"))
+	require.NoError(t, client.StartInstance(ctx, "web-1"))
+	assert.Equal(t, []string{
+		"/compute/v1/projects/shop/zones/us-central1-a/instances/web-1/stop",
+		"/compute/v1/projects/shop/zones/us-central1-a/instances/web-1/start",
+	}, actions)
+
+	_, err = client.GetInstance(ctx, "missing")
+	assert.ErrorContains(t, err, "instance missing not found")
+
+	err = client.StopInstance(ctx, "api")
+	assert.ErrorContains(t, err, "us-central1-a, us-east1-b")
+}
+
+func TestAPIErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte(`{"error":{"code":403,"message":"Required 'compute.instances.list' permission for 'projects/shop'"}}`))
+	}))
+	defer server.Close()
+
+	client, err := newClient("shop", nil, server.URL+"/compute/v1/", server.Client(), logging.NewLogger("error", "text"))
+	require.NoError(t, err)
+
+	_, err = client.ListInstances(context.Background())
+	assert.ErrorContains(t, err, "403 Forbidden: Required 'compute.instances.list' permission")
+}
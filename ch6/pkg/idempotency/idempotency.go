@@ -0,0 +1,158 @@
+// Package idempotency remembers the responses to write requests for a
+// window, so a client that sends the same JSON-RPC request again, e.g. when
+// it retries after a transport hiccup, gets the first response back instead
+// of making the change twice.
+package idempotency
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"aws-mcp-server/pkg/jsonfile"
+)
+
+// Entry is the response to one request, stored under the request's key
+type Entry struct {
+	Key      string          `json:"key"`
+	Response json.RawMessage `json:"response"`
+	StoredAt time.Time       `json:"storedAt"`
+}
+
+// Cache holds the responses of the last window. With a path, responses are
+// saved as JSON whenever one is added and reloaded on startup, so retries
+// whose keys outlive a restart are caught too. A nil Cache runs every request, so callers
+// need not check whether replay protection is configured.
+type Cache struct {
+	mu      sync.Mutex
+	path    string
+	window  time.Duration
+	entries map[string]Entry
+	pending map[string]chan struct{}
+	now     func() time.Time
+}
+
+// Open creates a cache for window, saved to path. A window of zero disables
+// replay protection and returns a nil Cache. With an empty path responses
+// are only kept in memory.
+func Open(path string, window time.Duration) (*Cache, error) {
+	if window <= 0 {
+		return nil, nil
+	}
+
+	c := &Cache{
+		path:    path,
+		window:  window,
+		entries: make(map[string]Entry),
+		pending: make(map[string]chan struct{}),
+		now:     time.Now,
+	}
+	if path == "" {
+		return c, nil
+	}
+
+	var entries []Entry
+	if err := jsonfile.Load(path, "idempotency cache", &entries); err != nil {
+		return c, err
+	}
+	for _, entry := range entries {
+		c.entries[entry.Key] = entry
+	}
+	c.prune()
+	return c, nil
+}
+
+// Key identifies a request by the session it was sent in, its JSON-RPC ID
+// and payload. Payloads are compared as JSON values, so key order and
+// whitespace do not matter.
+func Key(session string, id, payload json.RawMessage) (string, error) {
+	var value interface{}
+	if err := json.Unmarshal(payload, &value); err != nil {
+		return "", fmt.Errorf("invalid request payload: %w", err)
+	}
+	canonical, err := json.Marshal(value)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode request payload: %w", err)
+	}
+
+	hash := sha256.New()
+	hash.Write([]byte(session))
+	hash.Write([]byte{0})
+	hash.Write(id)
+	hash.Write([]byte{0})
+	hash.Write(canonical)
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// Do returns the stored response for key when there is one from the last
+// window, reporting it as replayed. Otherwise it runs the request and stores
+// its response. A duplicate that arrives while the first request still runs
+// waits for its response. Failed runs are not stored; a response that could
+// not be saved is returned together with the error.
+func (c *Cache) Do(key string, run func() ([]byte, error)) ([]byte, bool, error) {
+	if c == nil {
+		response, err := run()
+		return response, false, err
+	}
+
+	c.mu.Lock()
+	for {
+		c.prune()
+		if entry, ok := c.entries[key]; ok {
+			c.mu.Unlock()
+			return entry.Response, true, nil
+		}
+		wait, running := c.pending[key]
+		if !running {
+			break
+		}
+		c.mu.Unlock()
+		<-wait
+		c.mu.Lock()
+	}
+	done := make(chan struct{})
+	c.pending[key] = done
+	c.mu.Unlock()
+
+	response, err := run()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.pending, key)
+	close(done)
+
+	if err != nil || response == nil {
+		return response, false, err
+	}
+	c.entries[key] = Entry{Key: key, Response: response, StoredAt: c.now()}
+	return response, false, c.save()
+}
+
+// prune drops the responses older than the window; callers hold the lock
+func (c *Cache) prune() {
+	cutoff := c.now().Add(-c.window)
+	for key, entry := range c.entries {
+		if entry.StoredAt.Before(cutoff) {
+			delete(c.entries, key)
+		}
+	}
+}
+
+// save writes the stored responses to the cache file; callers hold the lock
+func (c *Cache) save() error {
+	if c.path == "" {
+		return nil
+	}
+
+	entries := make([]Entry, 0, len(c.entries))
+	for _, entry := range c.entries {
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].StoredAt.Before(entries[j].StoredAt) })
+
+	return jsonfile.Save(c.path, "idempotency cache", entries)
+}
@@ -0,0 +1,139 @@
+package idempotency
+
+import (
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeyIgnoresPayloadFormatting(t *testing.T) {
+	a, err := Key("stdio-1", json.RawMessage(`7`), json.RawMessage(`{"name":"stop-ec2-instance","arguments":{"instanceId":"i-1"}}`))
+	require.NoError(t, err)
+	b, err := Key("stdio-1", json.RawMessage(`7`), json.RawMessage(`{ "arguments": {"instanceId": "i-1"}, "name": "stop-ec2-instance" }`))
+	require.NoError(t, err)
+	assert.Equal(t, a, b)
+
+	otherID, err := Key("stdio-1", json.RawMessage(`8`), json.RawMessage(`{"name":"stop-ec2-instance","arguments":{"instanceId":"i-1"}}`))
+	require.NoError(t, err)
+	assert.NotEqual(t, a, otherID)
+
+	otherPayload, err := Key("stdio-1", json.RawMessage(`7`), json.RawMessage(`{"name":"stop-ec2-instance","arguments":{"instanceId":"i-2"}}`))
+	require.NoError(t, err)
+	assert.NotEqual(t, a, otherPayload, "a reused ID with another payload is a new request")
+
+	otherSession, err := Key("websocket-2", json.RawMessage(`7`), json.RawMessage(`{"name":"stop-ec2-instance","arguments":{"instanceId":"i-1"}}`))
+	require.NoError(t, err)
+	assert.NotEqual(t, a, otherSession, "the same ID in another session is a new request")
+
+	_, err = Key("stdio-1", json.RawMessage(`7`), json.RawMessage(`{`))
+	assert.Error(t, err)
+}
+
+func TestCacheReplaysWithinWindow(t *testing.T) {
+	c, err := Open("", time.Minute)
+	require.NoError(t, err)
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	c.now = func() time.Time { return now }
+
+	runs := 0
+	run := func() ([]byte, error) {
+		runs++
+		return []byte(`{"result":"stopped"}`), nil
+	}
+
+	response, replayed, err := c.Do("k", run)
+	require.NoError(t, err)
+	assert.False(t, replayed)
+	assert.JSONEq(t, `{"result":"stopped"}`, string(response))
+
+	response, replayed, err = c.Do("k", run)
+	require.NoError(t, err)
+	assert.True(t, replayed)
+	assert.JSONEq(t, `{"result":"stopped"}`, string(response))
+	assert.Equal(t, 1, runs)
+
+	now = now.Add(2 * time.Minute)
+	_, replayed, err = c.Do("k", run)
+	require.NoError(t, err)
+	assert.False(t, replayed, "responses expire after the window")
+	assert.Equal(t, 2, runs)
+}
+
+func TestCacheDoesNotStoreFailures(t *testing.T) {
+	c, err := Open("", time.Minute)
+	require.NoError(t, err)
+
+	_, _, err = c.Do("k", func() ([]byte, error) { return nil, errors.New("marshal failed") })
+	assert.Error(t, err)
+
+	_, replayed, err := c.Do("k", func() ([]byte, error) { return []byte(`{}`), nil })
+	require.NoError(t, err)
+	assert.False(t, replayed)
+}
+
+func TestCacheWaitsForRunningDuplicate(t *testing.T) {
+	c, err := Open("", time.Minute)
+	require.NoError(t, err)
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		c.Do("k", func() ([]byte, error) {
+			close(started)
+			<-release
+			return []byte(`{"id":1}`), nil
+		})
+	}()
+	<-started
+
+	done := make(chan bool)
+	go func() {
+		_, replayed, _ := c.Do("k", func() ([]byte, error) {
+			t.Error("the duplicate must not run")
+			return nil, nil
+		})
+		done <- replayed
+	}()
+
+	close(release)
+	assert.True(t, <-done)
+	wg.Wait()
+}
+
+func TestCacheSurvivesRestart(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "idempotency.json")
+	c, err := Open(path, time.Hour)
+	require.NoError(t, err)
+	_, _, err = c.Do("k", func() ([]byte, error) { return []byte(`{"id":1}`), nil })
+	require.NoError(t, err)
+
+	reopened, err := Open(path, time.Hour)
+	require.NoError(t, err)
+	response, replayed, err := reopened.Do("k", func() ([]byte, error) { return []byte(`{"id":2}`), nil })
+	require.NoError(t, err)
+	assert.True(t, replayed)
+	assert.JSONEq(t, `{"id":1}`, string(response))
+}
+
+func TestNilCacheRunsEveryRequest(t *testing.T) {
+	c, err := Open("", 0)
+	require.NoError(t, err)
+	assert.Nil(t, c)
+
+	runs := 0
+	for i := 0; i < 2; i++ {
+		_, replayed, err := c.Do("k", func() ([]byte, error) { runs++; return []byte(`{}`), nil })
+		require.NoError(t, err)
+		assert.False(t, replayed)
+	}
+	assert.Equal(t, 2, runs)
+}
@@ -0,0 +1,158 @@
+package ignore
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"aws-mcp-server/pkg/jsonfile"
+)
+
+// Entry leaves one resource out of anomaly detection, orphan reports and the
+// daily digest, identified by its ID or ARN
+type Entry struct {
+	Resource  string     `json:"resource"`
+	Reason    string     `json:"reason"`
+	CreatedBy string     `json:"createdBy"`
+	CreatedAt time.Time  `json:"createdAt"`
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+}
+
+// Expired reports whether the entry no longer applies at now
+func (e Entry) Expired(now time.Time) bool {
+	return e.ExpiresAt != nil && !now.Before(*e.ExpiresAt)
+}
+
+// List holds the ignored resources. With a path, the list is saved as JSON
+// after every change and reloaded on startup.
+type List struct {
+	mu      sync.Mutex
+	path    string
+	entries map[string]Entry
+}
+
+// NewList creates an empty in-memory list
+func NewList() *List {
+	return &List{entries: make(map[string]Entry)}
+}
+
+// Open creates a list saved to path. With an empty path entries are only kept in memory.
+func Open(path string) (*List, error) {
+	l := NewList()
+	l.path = path
+	if path == "" {
+		return l, nil
+	}
+
+	var entries []Entry
+	if err := jsonfile.Load(path, "ignored resources", &entries); err != nil {
+		return l, err
+	}
+	for _, entry := range entries {
+		l.entries[entry.Resource] = entry
+	}
+	return l, nil
+}
+
+// Add ignores a resource, replacing any earlier entry for it
+func (l *List) Add(entry Entry) error {
+	if entry.CreatedAt.IsZero() {
+		entry.CreatedAt = time.Now()
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.entries[entry.Resource] = entry
+	return l.save()
+}
+
+// Remove stops ignoring a resource and returns the removed entry
+func (l *List) Remove(resource string) (Entry, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	entry, exists := l.entries[resource]
+	if !exists {
+		return Entry{}, fmt.Errorf("resource %s is not ignored", resource)
+	}
+
+	delete(l.entries, resource)
+	return entry, l.save()
+}
+
+// Get returns the entry for a resource if it is ignored at now
+func (l *List) Get(resource string, now time.Time) (Entry, bool) {
+	if l == nil {
+		return Entry{}, false
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	entry, exists := l.entries[resource]
+	if !exists || entry.Expired(now) {
+		return Entry{}, false
+	}
+	return entry, true
+}
+
+// Any returns the entry of the first of the resources ignored at now, e.g. for
+// a metric whose dimensions name several resources
+func (l *List) Any(resources []string, now time.Time) (Entry, bool) {
+	for _, resource := range resources {
+		if entry, ok := l.Get(resource, now); ok {
+			return entry, true
+		}
+	}
+	return Entry{}, false
+}
+
+// Active returns the entries that apply at now, newest first. Expired entries
+// are dropped.
+func (l *List) Active(now time.Time) []Entry {
+	if l == nil {
+		return nil
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	active := make([]Entry, 0, len(l.entries))
+	expired := false
+	for resource, entry := range l.entries {
+		if entry.Expired(now) {
+			delete(l.entries, resource)
+			expired = true
+			continue
+		}
+		active = append(active, entry)
+	}
+	if expired {
+		// Failing to save only means expired entries are dropped again next time
+		_ = l.save()
+	}
+
+	sort.Slice(active, func(i, j int) bool {
+		return active[i].CreatedAt.After(active[j].CreatedAt)
+	})
+	return active
+}
+
+// save writes all entries to the list file, replacing it atomically
+func (l *List) save() error {
+	if l.path == "" {
+		return nil
+	}
+
+	entries := make([]Entry, 0, len(l.entries))
+	for _, entry := range l.entries {
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Resource < entries[j].Resource
+	})
+
+	return jsonfile.SaveIndent(l.path, "ignored resources", entries)
+}
@@ -0,0 +1,56 @@
+package ignore
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListExpiry(t *testing.T) {
+	l := NewList()
+	now := time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC)
+	until := now.Add(time.Hour)
+	require.NoError(t, l.Add(Entry{Resource: "i-1", Reason: "load test", CreatedAt: now, ExpiresAt: &until}))
+	require.NoError(t, l.Add(Entry{Resource: "vol-1", Reason: "kept for forensics", CreatedAt: now.Add(time.Minute)}))
+
+	_, ok := l.Get("i-1", now.Add(30*time.Minute))
+	assert.True(t, ok)
+	entry, ok := l.Any([]string{"i-2", "vol-1"}, now)
+	require.True(t, ok)
+	assert.Equal(t, "kept for forensics", entry.Reason)
+
+	active := l.Active(now.Add(2 * time.Hour))
+	require.Len(t, active, 1)
+	assert.Equal(t, "vol-1", active[0].Resource)
+
+	_, ok = l.Get("i-1", now)
+	assert.False(t, ok, "expired entries are dropped")
+
+	_, err := l.Remove("i-1")
+	assert.ErrorContains(t, err, "is not ignored")
+
+	var none *List
+	_, ok = none.Any([]string{"i-1"}, now)
+	assert.False(t, ok)
+}
+
+func TestListPersists(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state", "ignored.json")
+
+	l, err := Open(path)
+	require.NoError(t, err)
+	require.NoError(t, l.Add(Entry{Resource: "vol-1", Reason: "kept for forensics", CreatedBy: "sre"}))
+	require.NoError(t, l.Add(Entry{Resource: "i-1", Reason: "load test"}))
+	_, err = l.Remove("i-1")
+	require.NoError(t, err)
+
+	reopened, err := Open(path)
+	require.NoError(t, err)
+	active := reopened.Active(time.Now())
+	require.Len(t, active, 1)
+	assert.Equal(t, "vol-1", active[0].Resource)
+	assert.Equal(t, "sre", active[0].CreatedBy)
+}
@@ -0,0 +1,278 @@
+// Package inventory keeps the last instance list of every cloud provider on
+// disk, so a restarted server answers instance resources from the previous
+// run's snapshot while it lists the instances again in the background. With
+// a refresh interval, the snapshots answer every read and are kept up to
+// date with the instances that changed, which spares large fleets a full
+// listing on every refresh.
+package inventory
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"aws-mcp-server/pkg/cloud"
+	"aws-mcp-server/pkg/jsonfile"
+	"aws-mcp-server/pkg/types"
+)
+
+// Snapshot is the instance list of one provider as of RefreshedAt. ListedAt
+// is when all instances were last listed; the snapshot has been kept up to
+// date with the changes listed since.
+type Snapshot struct {
+	Key         string                `json:"key"`
+	RefreshedAt time.Time             `json:"refreshedAt"`
+	ListedAt    time.Time             `json:"listedAt"`
+	Instances   []types.CloudResource `json:"instances"`
+}
+
+// Cache holds a snapshot per provider, keyed by its instances URI. With a
+// path, snapshots are saved as JSON after every refresh and reloaded on
+// startup. Snapshots loaded from disk are warm: they are served, marked as
+// possibly stale, until the provider is refreshed for the first time.
+// Snapshots this run refreshed are current and are served while the
+// background refresher keeps them so.
+type Cache struct {
+	mu        sync.Mutex
+	path      string
+	snapshots map[string]Snapshot
+	warm      map[string]bool
+}
+
+// NewCache creates an empty in-memory cache
+func NewCache() *Cache {
+	return &Cache{
+		snapshots: make(map[string]Snapshot),
+		warm:      make(map[string]bool),
+	}
+}
+
+// Open creates a cache saved to path. Snapshots older than maxAge are not
+// loaded; a maxAge of zero loads all of them. With an empty path snapshots
+// are only kept in memory and there is nothing to warm up from.
+func Open(path string, maxAge time.Duration) (*Cache, error) {
+	c := NewCache()
+	c.path = path
+	if path == "" {
+		return c, nil
+	}
+
+	var snapshots []Snapshot
+	if err := jsonfile.Load(path, "inventory snapshot", &snapshots); err != nil {
+		return c, err
+	}
+	now := time.Now()
+	for _, snapshot := range snapshots {
+		if maxAge > 0 && now.Sub(snapshot.RefreshedAt) > maxAge {
+			continue
+		}
+		c.snapshots[snapshot.Key] = snapshot
+		c.warm[snapshot.Key] = true
+	}
+	return c, nil
+}
+
+// Warm returns the snapshot of key if it was loaded from disk and the
+// provider has not been refreshed since
+func (c *Cache) Warm(key string) (Snapshot, bool) {
+	if c == nil {
+		return Snapshot{}, false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.warm[key] {
+		return Snapshot{}, false
+	}
+	return c.snapshots[key], true
+}
+
+// Current returns the snapshot of key if it was refreshed by this run within
+// maxAge. A maxAge of zero never returns one.
+func (c *Cache) Current(key string, maxAge time.Duration) (Snapshot, bool) {
+	if c == nil || maxAge <= 0 {
+		return Snapshot{}, false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	snapshot, exists := c.snapshots[key]
+	if !exists || c.warm[key] || time.Since(snapshot.RefreshedAt) > maxAge {
+		return Snapshot{}, false
+	}
+	return snapshot, true
+}
+
+// WarmKeys returns the keys of the warm snapshots, sorted
+func (c *Cache) WarmKeys() []string {
+	if c == nil {
+		return nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	keys := make([]string, 0, len(c.warm))
+	for key := range c.warm {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Snapshots returns the snapshot of every provider, sorted by key
+func (c *Cache) Snapshots() []Snapshot {
+	if c == nil {
+		return nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	snapshots := make([]Snapshot, 0, len(c.snapshots))
+	for _, snapshot := range c.snapshots {
+		snapshots = append(snapshots, snapshot)
+	}
+	sort.Slice(snapshots, func(i, j int) bool { return snapshots[i].Key < snapshots[j].Key })
+	return snapshots
+}
+
+// Flush drops the snapshots from memory, so instance lists are read from the
+// providers until they are listed again
+func (c *Cache) Flush() {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.snapshots = make(map[string]Snapshot)
+	c.warm = make(map[string]bool)
+}
+
+// Put replaces the snapshot of key with all its instances, listed at
+// refreshedAt
+func (c *Cache) Put(key string, instances []types.CloudResource, refreshedAt time.Time) error {
+	if c == nil {
+		return nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.snapshots[key] = Snapshot{Key: key, RefreshedAt: refreshedAt, ListedAt: refreshedAt, Instances: instances}
+	delete(c.warm, key)
+	return c.save()
+}
+
+// Refresh describes one update of a snapshot
+type Refresh struct {
+	// Full is set when all instances were listed rather than the changes
+	Full    bool
+	Changed int
+	Gone    int
+	// DeltaErr is why listing the changes failed when the update fell back
+	// to listing all instances
+	DeltaErr error
+}
+
+// Update brings the snapshot of a provider up to date. Providers that can
+// list their changes only do so while the last full listing is younger than
+// fullEvery; otherwise, and when listing the changes fails, all instances
+// are listed. A fullEvery of zero lists all of them every time. The warm
+// snapshot is retired even when listing fails, so later reads go to the
+// provider and report its error rather than serving an ever older snapshot.
+func (c *Cache) Update(ctx context.Context, provider cloud.Provider, fullEvery time.Duration) (Refresh, error) {
+	var refresh Refresh
+	if c == nil {
+		return refresh, nil
+	}
+
+	key := cloud.InstancesURI(provider)
+	c.mu.Lock()
+	snapshot, exists := c.snapshots[key]
+	c.mu.Unlock()
+
+	delta, ok := provider.(cloud.DeltaProvider)
+	if ok && exists && fullEvery > 0 && time.Since(snapshot.ListedAt) < fullEvery {
+		started := time.Now()
+		changed, gone, err := delta.ListChangedInstances(ctx, snapshot.Instances, snapshot.RefreshedAt)
+		if err == nil {
+			refresh.Changed, refresh.Gone = len(changed), len(gone)
+			return refresh, c.merge(key, changed, gone, started)
+		}
+		refresh.DeltaErr = err
+	}
+
+	refresh.Full = true
+	started := time.Now()
+	instances, err := provider.ListInstances(ctx)
+	if err != nil {
+		c.mu.Lock()
+		delete(c.warm, key)
+		c.mu.Unlock()
+		return refresh, err
+	}
+	refresh.Changed = len(instances)
+	return refresh, c.Put(key, instances, started)
+}
+
+// merge applies the changes listed since the snapshot of key was taken. The
+// snapshot's instances are copied, since reads use them without the lock.
+func (c *Cache) merge(key string, changed []types.CloudResource, gone []string, refreshedAt time.Time) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	snapshot := c.snapshots[key]
+	updates := make(map[string]types.CloudResource, len(changed))
+	for _, instance := range changed {
+		updates[instance.ID] = instance
+	}
+	removed := make(map[string]bool, len(gone))
+	for _, id := range gone {
+		removed[id] = true
+	}
+
+	instances := make([]types.CloudResource, 0, len(snapshot.Instances)+len(changed))
+	for _, instance := range snapshot.Instances {
+		if removed[instance.ID] {
+			continue
+		}
+		if update, ok := updates[instance.ID]; ok {
+			instance = update
+			delete(updates, instance.ID)
+		}
+		instances = append(instances, instance)
+	}
+	// Instances not in the snapshot were launched since, in the order listed
+	for _, instance := range changed {
+		if _, ok := updates[instance.ID]; ok {
+			instances = append(instances, instance)
+		}
+	}
+
+	snapshot.Key = key
+	snapshot.Instances = instances
+	snapshot.RefreshedAt = refreshedAt
+	c.snapshots[key] = snapshot
+	delete(c.warm, key)
+	return c.save()
+}
+
+// save writes the snapshots to the cache's file; the caller holds the lock
+func (c *Cache) save() error {
+	if c.path == "" {
+		return nil
+	}
+
+	snapshots := make([]Snapshot, 0, len(c.snapshots))
+	for _, snapshot := range c.snapshots {
+		snapshots = append(snapshots, snapshot)
+	}
+	sort.Slice(snapshots, func(i, j int) bool { return snapshots[i].Key < snapshots[j].Key })
+
+	return jsonfile.Save(c.path, "inventory snapshot", snapshots)
+}
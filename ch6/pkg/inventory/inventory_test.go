@@ -0,0 +1,202 @@
+package inventory
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"aws-mcp-server/pkg/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func instances() []types.CloudResource {
+	launched := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	return []types.CloudResource{
+		{ID: "i-1", Provider: "aws", Type: "ec2-instance", Region: "us-east-1", State: "running",
+			Tags:    map[string]string{"Name": "web-1"},
+			Details: &types.EC2InstanceDetails{InstanceType: "m5.large", LaunchTime: &launched, Platform: "linux", PrivateIPAddress: "10.0.0.1"}},
+		{ID: "vm-1", Provider: "azure", Type: "virtual-machine", Region: "westeurope", State: "running",
+			Details: &types.AzureVMDetails{InstanceType: "Standard_B2s", ResourceGroup: "web"}},
+		{ID: "unknown", Provider: "aws", Type: "something-else", State: "available"},
+	}
+}
+
+func TestSnapshotSurvivesRestart(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "inventory.json")
+	refreshed := time.Now().Add(-time.Hour).UTC().Truncate(time.Second)
+
+	c, err := Open(path, 24*time.Hour)
+	require.NoError(t, err)
+	assert.Empty(t, c.WarmKeys())
+	require.NoError(t, c.Put("aws://ec2/instances", instances(), refreshed))
+	_, warm := c.Warm("aws://ec2/instances")
+	assert.False(t, warm, "snapshots taken by this run are not served")
+
+	reopened, err := Open(path, 24*time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"aws://ec2/instances"}, reopened.WarmKeys())
+
+	snapshot, warm := reopened.Warm("aws://ec2/instances")
+	require.True(t, warm)
+	assert.True(t, refreshed.Equal(snapshot.RefreshedAt))
+	require.Len(t, snapshot.Instances, 3)
+	assert.Equal(t, "m5.large", snapshot.Instances[0].InstanceType(), "details are decoded by resource type")
+	assert.Equal(t, "10.0.0.1", snapshot.Instances[0].PrivateIPAddress())
+	assert.Equal(t, "web-1", snapshot.Instances[0].Tags["Name"])
+	assert.Equal(t, "Standard_B2s", snapshot.Instances[1].InstanceType())
+	assert.Nil(t, snapshot.Instances[2].Details)
+
+	expired, err := Open(path, time.Minute)
+	require.NoError(t, err)
+	assert.Empty(t, expired.WarmKeys(), "snapshots older than the max age are not served")
+}
+
+// fakeProvider lists a fixed set of instances
+type fakeProvider struct {
+	name      string
+	instances []types.CloudResource
+	listErr   error
+	listed    int
+}
+
+func (p *fakeProvider) Name() string    { return p.name }
+func (p *fakeProvider) Service() string { return "vm" }
+func (p *fakeProvider) Label() string   { return "Fake" }
+
+func (p *fakeProvider) ListInstances(context.Context) ([]types.CloudResource, error) {
+	p.listed++
+	return p.instances, p.listErr
+}
+
+func (p *fakeProvider) GetInstance(context.Context, string) (*types.CloudResource, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (p *fakeProvider) StartInstance(context.Context, string) error { return nil }
+func (p *fakeProvider) StopInstance(context.Context, string) error  { return nil }
+
+// deltaProvider also lists its changes
+type deltaProvider struct {
+	fakeProvider
+	changed  []types.CloudResource
+	gone     []string
+	deltaErr error
+	since    time.Time
+}
+
+func (p *deltaProvider) ListChangedInstances(_ context.Context, _ []types.CloudResource, since time.Time) ([]types.CloudResource, []string, error) {
+	p.since = since
+	return p.changed, p.gone, p.deltaErr
+}
+
+func TestUpdateRetiresTheWarmSnapshot(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "inventory.json")
+	c, err := Open(path, 0)
+	require.NoError(t, err)
+	require.NoError(t, c.Put("aws://vm/instances", instances(), time.Now()))
+	require.NoError(t, c.Put("gcp://vm/instances", instances()[:1], time.Now()))
+
+	c, err = Open(path, 0)
+	require.NoError(t, err)
+	require.Len(t, c.WarmKeys(), 2)
+
+	refresh, err := c.Update(context.Background(), &fakeProvider{name: "aws", instances: instances()[:1]}, time.Hour)
+	require.NoError(t, err)
+	assert.True(t, refresh.Full, "providers that cannot list their changes are listed in full")
+	_, warm := c.Warm("aws://vm/instances")
+	assert.False(t, warm)
+
+	_, err = c.Update(context.Background(), &fakeProvider{name: "gcp", listErr: errors.New("throttled")}, time.Hour)
+	assert.ErrorContains(t, err, "throttled")
+	assert.Empty(t, c.WarmKeys(), "a failed refresh retires the snapshot too")
+
+	reopened, err := Open(path, 0)
+	require.NoError(t, err)
+	snapshot, _ := reopened.Warm("aws://vm/instances")
+	assert.Len(t, snapshot.Instances, 1, "refreshed lists are saved")
+}
+
+func TestUpdateMergesChanges(t *testing.T) {
+	c := NewCache()
+	listed := time.Now().Add(-10 * time.Minute)
+	require.NoError(t, c.Put("aws://vm/instances", instances(), listed))
+
+	stopped := instances()[0]
+	stopped.State = "stopped"
+	launched := types.CloudResource{ID: "i-2", Provider: "aws", Type: "ec2-instance", State: "pending"}
+	provider := &deltaProvider{
+		fakeProvider: fakeProvider{name: "aws"},
+		changed:      []types.CloudResource{launched, stopped},
+		gone:         []string{"vm-1"},
+	}
+
+	refresh, err := c.Update(context.Background(), provider, time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, Refresh{Changed: 2, Gone: 1}, refresh)
+	assert.Zero(t, provider.listed)
+	assert.True(t, listed.Equal(provider.since), "changes are listed since the last refresh")
+
+	snapshot, ok := c.Current("aws://vm/instances", time.Minute)
+	require.True(t, ok)
+	require.Len(t, snapshot.Instances, 3)
+	assert.Equal(t, "i-1", snapshot.Instances[0].ID)
+	assert.Equal(t, "stopped", snapshot.Instances[0].State)
+	assert.Equal(t, "unknown", snapshot.Instances[1].ID)
+	assert.Equal(t, "i-2", snapshot.Instances[2].ID, "new instances are appended")
+	assert.True(t, listed.Equal(snapshot.ListedAt), "merging changes is not a full listing")
+	assert.True(t, snapshot.RefreshedAt.After(listed))
+
+	provider.deltaErr = errors.New("AccessDenied")
+	provider.instances = instances()[:1]
+	refresh, err = c.Update(context.Background(), provider, time.Hour)
+	require.NoError(t, err)
+	assert.True(t, refresh.Full, "a failed delta falls back to a full listing")
+	assert.ErrorContains(t, refresh.DeltaErr, "AccessDenied")
+	snapshot, _ = c.Current("aws://vm/instances", time.Minute)
+	assert.Len(t, snapshot.Instances, 1)
+
+	provider.deltaErr = nil
+	refresh, err = c.Update(context.Background(), provider, 0)
+	require.NoError(t, err)
+	assert.True(t, refresh.Full, "without a full refresh interval every refresh lists all instances")
+	assert.Equal(t, 2, provider.listed)
+}
+
+func TestCurrentSkipsWarmAndOldSnapshots(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "inventory.json")
+	c, err := Open(path, 0)
+	require.NoError(t, err)
+	require.NoError(t, c.Put("aws://vm/instances", instances(), time.Now().Add(-5*time.Minute)))
+
+	_, ok := c.Current("aws://vm/instances", 10*time.Minute)
+	assert.True(t, ok)
+	_, ok = c.Current("aws://vm/instances", time.Minute)
+	assert.False(t, ok, "snapshots the refresher has not kept up are not current")
+	_, ok = c.Current("aws://vm/instances", 0)
+	assert.False(t, ok)
+
+	reopened, err := Open(path, 0)
+	require.NoError(t, err)
+	_, ok = reopened.Current("aws://vm/instances", 10*time.Minute)
+	assert.False(t, ok, "the previous run's snapshots are warm, not current")
+}
+
+func TestOpenReportsCorruptSnapshots(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "inventory.json")
+	require.NoError(t, os.WriteFile(path, []byte("{"), 0o600))
+
+	c, err := Open(path, 0)
+	assert.ErrorContains(t, err, "failed to parse inventory snapshot")
+	require.NotNil(t, c)
+	assert.Empty(t, c.WarmKeys())
+
+	var nilCache *Cache
+	_, warm := nilCache.Warm("aws://ec2/instances")
+	assert.False(t, warm)
+	assert.NoError(t, nilCache.Put("aws://ec2/instances", nil, time.Now()))
+}
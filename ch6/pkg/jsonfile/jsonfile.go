@@ -0,0 +1,65 @@
+// Package jsonfile loads and saves the JSON files the server's stores keep
+// their state in, e.g. ignored resources, notes and baselines. Files are
+// replaced atomically, so a crash while saving leaves the last complete
+// version behind.
+package jsonfile
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Load decodes the file at path into v. A file that does not exist yet
+// leaves v as it is. What names the contents in errors, e.g. notes.
+func Load(path, what string, v interface{}) error {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", what, err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", what, err)
+	}
+	return nil
+}
+
+// Save encodes v compactly and replaces the file at path with it, creating
+// its directory when needed
+func Save(path, what string, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s: %w", what, err)
+	}
+	return write(path, what, data)
+}
+
+// SaveIndent is Save for files people read and edit, which are indented
+func SaveIndent(path, what string, v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s: %w", what, err)
+	}
+	return write(path, what, data)
+}
+
+// write replaces the file at path by renaming a complete copy over it. The
+// file is only readable by the server's user.
+func write(path, what string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create %s directory: %w", what, err)
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write %s: %w", what, err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to save %s: %w", what, err)
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package jsonfile
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type record struct {
+	ID    string `json:"id"`
+	Count int    `json:"count"`
+}
+
+func TestSaveAndLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state", "records.json")
+	want := []record{{ID: "a", Count: 1}, {ID: "b", Count: 2}}
+	require.NoError(t, Save(path, "records", want), "the directory is created")
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, `[{"id":"a","count":1},{"id":"b","count":2}]`, string(data))
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+	_, err = os.Stat(path + ".tmp")
+	assert.ErrorIs(t, err, os.ErrNotExist, "the temporary copy is renamed")
+
+	var got []record
+	require.NoError(t, Load(path, "records", &got))
+	assert.Equal(t, want, got)
+
+	require.NoError(t, SaveIndent(path, "records", want[:1]))
+	data, err = os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "[\n  {\n    \"id\": \"a\",\n    \"count\": 1\n  }\n]", string(data))
+}
+
+func TestLoadMissingAndBrokenFiles(t *testing.T) {
+	dir := t.TempDir()
+	got := []record{{ID: "kept"}}
+	require.NoError(t, Load(filepath.Join(dir, "missing.json"), "records", &got))
+	assert.Equal(t, []record{{ID: "kept"}}, got, "a missing file leaves the value alone")
+
+	path := filepath.Join(dir, "broken.json")
+	require.NoError(t, os.WriteFile(path, []byte("[{"), 0o600))
+	assert.ErrorContains(t, Load(path, "records", &got), "failed to parse records")
+	assert.ErrorContains(t, Load(dir, "records", &got), "failed to read records")
+}
@@ -0,0 +1,507 @@
+package kb
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io/fs"
+	"math"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"aws-mcp-server/internal/config"
+	"aws-mcp-server/pkg/types"
+)
+
+const (
+	// defaultRefreshInterval applies when docs.refresh_interval is not configured
+	defaultRefreshInterval = 15 * time.Minute
+	// defaultMaxDocumentSize applies when docs.max_document_size is not configured
+	defaultMaxDocumentSize = 1 << 20
+	// snippetLength bounds the excerpt returned with a search result
+	snippetLength = 240
+)
+
+// BM25 parameters: k1 limits how much repeating a word raises the score and
+// b how much long documents are penalized
+const (
+	bm25K1 = 1.2
+	bm25B  = 0.75
+)
+
+// Words in titles and headings describe the whole document or section, so
+// they count as if they appeared several times
+const (
+	titleWeight   = 3
+	headingWeight = 2
+)
+
+// stopWords are too common in runbooks to tell documents apart
+var stopWords = map[string]bool{
+	"a": true, "an": true, "and": true, "are": true, "as": true, "at": true, "be": true, "by": true,
+	"for": true, "from": true, "how": true, "if": true, "in": true, "is": true, "it": true, "of": true,
+	"on": true, "or": true, "the": true, "this": true, "to": true, "what": true, "when": true,
+	"with": true, "you": true,
+}
+
+// ObjectStore lists and reads the objects under an S3 prefix
+type ObjectStore interface {
+	ListS3Objects(ctx context.Context, bucket, prefix string) ([]types.S3Object, error)
+	GetS3Object(ctx context.Context, bucket, key string, maxSize int64) ([]byte, error)
+}
+
+// Document is one markdown file. Path is relative to its source and
+// identifies the document in kb://docs/{path}.
+type Document struct {
+	Path     string    `json:"path"`
+	Source   string    `json:"source"`
+	Title    string    `json:"title"`
+	Headings []string  `json:"headings,omitempty"`
+	Content  string    `json:"content"`
+	Modified time.Time `json:"modified"`
+
+	terms  map[string]int
+	length int
+}
+
+// Result is a document matching a search with the passage that matched best
+type Result struct {
+	Document *Document
+	Score    float64
+	Snippet  string
+}
+
+// Index keeps the documents of the configured sources in memory for search.
+// It is rebuilt on the first use after the refresh interval; documents whose
+// file or object did not change since are reused. A nil Index has no
+// documents, so callers need not check whether it is configured.
+type Index struct {
+	sources  []string
+	objects  ObjectStore
+	refresh  time.Duration
+	maxSize  int64
+	now      func() time.Time
+	mu       sync.Mutex
+	docs     []*Document
+	byPath   map[string]*Document
+	failures map[string]string
+	loaded   time.Time
+}
+
+// New creates an index of the configured sources, or returns nil when none
+// are configured. Directories are read from disk and s3:// locations through
+// objects.
+func New(cfg config.DocsConfig, objects ObjectStore) (*Index, error) {
+	if len(cfg.Sources) == 0 {
+		return nil, nil
+	}
+
+	for _, source := range cfg.Sources {
+		if strings.HasPrefix(source, "s3://") {
+			if bucket, _ := splitS3(source); bucket == "" {
+				return nil, fmt.Errorf("invalid docs source %q, use s3://bucket/prefix", source)
+			}
+			if objects == nil {
+				return nil, fmt.Errorf("docs source %s needs an S3 client", source)
+			}
+		}
+	}
+
+	idx := &Index{
+		sources: cfg.Sources,
+		objects: objects,
+		refresh: cfg.RefreshInterval,
+		maxSize: cfg.MaxDocumentSize,
+		now:     time.Now,
+		byPath:  make(map[string]*Document),
+	}
+	if idx.refresh <= 0 {
+		idx.refresh = defaultRefreshInterval
+	}
+	if idx.maxSize <= 0 {
+		idx.maxSize = defaultMaxDocumentSize
+	}
+	return idx, nil
+}
+
+// Sources returns the configured sources
+func (idx *Index) Sources() []string {
+	if idx == nil {
+		return nil
+	}
+	return idx.sources
+}
+
+// Documents returns all documents, ordered by path, and the sources that
+// could not be read with why
+func (idx *Index) Documents(ctx context.Context) ([]*Document, map[string]string) {
+	if idx == nil {
+		return nil, nil
+	}
+
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	idx.load(ctx)
+	return idx.docs, idx.failures
+}
+
+// Get returns the document at path, or nil when there is none
+func (idx *Index) Get(ctx context.Context, docPath string) *Document {
+	if idx == nil {
+		return nil
+	}
+
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	idx.load(ctx)
+	return idx.byPath[docPath]
+}
+
+// Search returns up to limit documents matching query, best first. Documents
+// are ranked with BM25 over their words, words in titles and headings
+// weighing more, and must contain at least one word of the query.
+func (idx *Index) Search(ctx context.Context, query string, limit int) []Result {
+	if idx == nil {
+		return nil
+	}
+	words := uniqueTerms(query)
+	if len(words) == 0 {
+		return nil
+	}
+
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	idx.load(ctx)
+	if len(idx.docs) == 0 {
+		return nil
+	}
+
+	var totalLength int
+	frequency := make(map[string]int, len(words))
+	for _, doc := range idx.docs {
+		totalLength += doc.length
+		for _, word := range words {
+			if doc.terms[word] > 0 {
+				frequency[word]++
+			}
+		}
+	}
+	averageLength := float64(totalLength) / float64(len(idx.docs))
+	if averageLength == 0 {
+		averageLength = 1
+	}
+
+	var results []Result
+	for _, doc := range idx.docs {
+		var score float64
+		for _, word := range words {
+			count := float64(doc.terms[word])
+			if count == 0 {
+				continue
+			}
+			n := float64(frequency[word])
+			idf := math.Log(1 + (float64(len(idx.docs))-n+0.5)/(n+0.5))
+			score += idf * count * (bm25K1 + 1) /
+				(count + bm25K1*(1-bm25B+bm25B*float64(doc.length)/averageLength))
+		}
+		if score > 0 {
+			results = append(results, Result{Document: doc, Score: score, Snippet: snippet(doc.Content, words)})
+		}
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Score > results[j].Score
+	})
+	if limit > 0 && len(results) > limit {
+		results = results[:limit]
+	}
+	return results
+}
+
+// load rebuilds the index when it is stale. A source that cannot be read
+// keeps its documents from the previous load. Callers hold mu.
+func (idx *Index) load(ctx context.Context) {
+	if !idx.loaded.IsZero() && idx.now().Sub(idx.loaded) < idx.refresh {
+		return
+	}
+
+	previous := idx.byPath
+	idx.byPath = make(map[string]*Document, len(previous))
+	idx.failures = nil
+	for _, source := range idx.sources {
+		var docs []*Document
+		var err error
+		if strings.HasPrefix(source, "s3://") {
+			docs, err = idx.loadS3(ctx, source, previous)
+		} else {
+			docs, err = idx.loadDirectory(source, previous)
+		}
+		if err != nil {
+			if idx.failures == nil {
+				idx.failures = make(map[string]string)
+			}
+			idx.failures[source] = err.Error()
+			for _, doc := range previous {
+				if doc.Source == source {
+					docs = append(docs, doc)
+				}
+			}
+		}
+		for _, doc := range docs {
+			// The first source with a path wins, so sources listed first take precedence
+			if _, exists := idx.byPath[doc.Path]; !exists {
+				idx.byPath[doc.Path] = doc
+			}
+		}
+	}
+
+	idx.docs = make([]*Document, 0, len(idx.byPath))
+	for _, doc := range idx.byPath {
+		idx.docs = append(idx.docs, doc)
+	}
+	sort.Slice(idx.docs, func(i, j int) bool {
+		return idx.docs[i].Path < idx.docs[j].Path
+	})
+	idx.loaded = idx.now()
+}
+
+// loadDirectory reads the markdown files under a directory
+func (idx *Index) loadDirectory(dir string, previous map[string]*Document) ([]*Document, error) {
+	var docs []*Document
+	err := filepath.WalkDir(dir, func(file string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() || !isMarkdown(file) {
+			return nil
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		if info.Size() > idx.maxSize {
+			return nil
+		}
+
+		rel, err := filepath.Rel(dir, file)
+		if err != nil {
+			return err
+		}
+		docPath := filepath.ToSlash(rel)
+		if doc, ok := previous[docPath]; ok && doc.Source == dir && doc.Modified.Equal(info.ModTime()) {
+			docs = append(docs, doc)
+			return nil
+		}
+
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		docs = append(docs, Parse(docPath, dir, string(content), info.ModTime()))
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read docs directory: %w", err)
+	}
+	return docs, nil
+}
+
+// loadS3 reads the markdown objects under an s3://bucket/prefix location
+func (idx *Index) loadS3(ctx context.Context, source string, previous map[string]*Document) ([]*Document, error) {
+	bucket, prefix := splitS3(source)
+	objects, err := idx.objects.ListS3Objects(ctx, bucket, prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	var docs []*Document
+	for _, object := range objects {
+		if !isMarkdown(object.Key) || object.Size > idx.maxSize {
+			continue
+		}
+		docPath := strings.TrimPrefix(strings.TrimPrefix(object.Key, prefix), "/")
+		if doc, ok := previous[docPath]; ok && doc.Source == source && doc.Modified.Equal(object.LastModified) {
+			docs = append(docs, doc)
+			continue
+		}
+
+		content, err := idx.objects.GetS3Object(ctx, bucket, object.Key, idx.maxSize)
+		if err != nil {
+			return nil, err
+		}
+		docs = append(docs, Parse(docPath, source, string(content), object.LastModified))
+	}
+	return docs, nil
+}
+
+// Parse builds a document from markdown. The title is the first top-level
+// heading, or the file name when there is none.
+func Parse(docPath, source, content string, modified time.Time) *Document {
+	doc := &Document{
+		Path:     docPath,
+		Source:   source,
+		Content:  content,
+		Modified: modified,
+		terms:    make(map[string]int),
+	}
+
+	inCode := false
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1<<20)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "```") || strings.HasPrefix(line, "~~~") {
+			inCode = !inCode
+		}
+
+		weight := 1
+		if !inCode && strings.HasPrefix(line, "#") {
+			heading := strings.TrimSpace(strings.TrimLeft(line, "#"))
+			switch {
+			case heading == "":
+			case strings.HasPrefix(line, "# ") && doc.Title == "":
+				doc.Title = heading
+				weight = titleWeight
+			default:
+				doc.Headings = append(doc.Headings, heading)
+				weight = headingWeight
+			}
+		}
+
+		for _, word := range terms(line) {
+			doc.terms[word] += weight
+			doc.length++
+		}
+	}
+
+	if doc.Title == "" {
+		name := strings.TrimSuffix(path.Base(docPath), path.Ext(docPath))
+		doc.Title = strings.NewReplacer("-", " ", "_", " ").Replace(name)
+		for _, word := range terms(doc.Title) {
+			doc.terms[word] += titleWeight
+			doc.length++
+		}
+	}
+	return doc
+}
+
+// snippet returns the paragraph with the most query words, cut around the
+// first of them. Headings are left out since the title and headings are
+// listed anyway; when only they matched, the first paragraph is returned.
+func snippet(content string, words []string) string {
+	best, bestMatches := "", 0
+	for _, paragraph := range strings.Split(content, "\n\n") {
+		var lines []string
+		for _, line := range strings.Split(paragraph, "\n") {
+			if !strings.HasPrefix(strings.TrimSpace(line), "#") {
+				lines = append(lines, line)
+			}
+		}
+		paragraph = strings.Join(strings.Fields(strings.Join(lines, " ")), " ")
+		if paragraph == "" {
+			continue
+		}
+		if best == "" {
+			best = paragraph
+		}
+		have := make(map[string]bool)
+		for _, word := range terms(paragraph) {
+			have[word] = true
+		}
+		matches := 0
+		for _, word := range words {
+			if have[word] {
+				matches++
+			}
+		}
+		if matches > bestMatches {
+			best, bestMatches = paragraph, matches
+		}
+	}
+
+	if len(best) <= snippetLength {
+		return best
+	}
+
+	start := 0
+	lower := strings.ToLower(best)
+	for _, word := range words {
+		if i := strings.Index(lower, word); i >= 0 && (start == 0 || i < start) {
+			start = i
+		}
+	}
+	start = max(0, start-snippetLength/4)
+	for start > 0 && best[start-1] != ' ' {
+		start--
+	}
+	end := min(len(best), start+snippetLength)
+	for end < len(best) && best[end] != ' ' {
+		end++
+	}
+
+	excerpt := best[start:end]
+	if start > 0 {
+		excerpt = "..." + excerpt
+	}
+	if end < len(best) {
+		excerpt += "..."
+	}
+	return excerpt
+}
+
+// terms splits text into lower-case words without stop words. A trailing
+// plural s is dropped so "instances" finds "instance".
+func terms(text string) []string {
+	fields := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	words := fields[:0]
+	for _, word := range fields {
+		if stopWords[word] {
+			continue
+		}
+		if len(word) > 3 && strings.HasSuffix(word, "s") && !strings.HasSuffix(word, "ss") {
+			word = word[:len(word)-1]
+		}
+		words = append(words, word)
+	}
+	return words
+}
+
+// uniqueTerms returns the words of a query, each once
+func uniqueTerms(query string) []string {
+	seen := make(map[string]bool)
+	var words []string
+	for _, word := range terms(query) {
+		if !seen[word] {
+			seen[word] = true
+			words = append(words, word)
+		}
+	}
+	return words
+}
+
+// isMarkdown reports whether a file name is a markdown document
+func isMarkdown(name string) bool {
+	switch strings.ToLower(path.Ext(name)) {
+	case ".md", ".markdown":
+		return true
+	}
+	return false
+}
+
+// splitS3 splits s3://bucket/prefix into its bucket and prefix
+func splitS3(location string) (string, string) {
+	bucket, prefix, _ := strings.Cut(strings.TrimPrefix(location, "s3://"), "/")
+	return bucket, prefix
+}
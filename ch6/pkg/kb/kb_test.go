@@ -0,0 +1,128 @@
+package kb
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"aws-mcp-server/internal/config"
+	"aws-mcp-server/pkg/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeDoc(t *testing.T, dir, name, content string) {
+	t.Helper()
+	file := filepath.Join(dir, name)
+	require.NoError(t, os.MkdirAll(filepath.Dir(file), 0o755))
+	require.NoError(t, os.WriteFile(file, []byte(content), 0o600))
+}
+
+func TestParse(t *testing.T) {
+	doc := Parse("runbooks/rds-failover.md", "docs", "Intro line\n# RDS failover\n\n## Symptoms\n```\n# not a heading\n```\n## Steps\n", time.Time{})
+	assert.Equal(t, "RDS failover", doc.Title)
+	assert.Equal(t, []string{"Symptoms", "Steps"}, doc.Headings)
+	assert.Equal(t, titleWeight, doc.terms["failover"])
+
+	untitled := Parse("runbooks/high_cpu-alarm.md", "docs", "Check the top processes.", time.Time{})
+	assert.Equal(t, "high cpu alarm", untitled.Title)
+}
+
+func TestSearch(t *testing.T) {
+	dir := t.TempDir()
+	writeDoc(t, dir, "runbooks/rds-failover.md", "# RDS failover\n\nWhen the primary database fails, promote the replica.\n\nCheck replication lag first.")
+	writeDoc(t, dir, "runbooks/high-cpu.md", "# High CPU on web instances\n\nScale out the web tier before rebooting instances.")
+	writeDoc(t, dir, "architecture/payments.md", "# Payments\n\nThe payments service stores orders in the payments RDS database.")
+	writeDoc(t, dir, "notes.txt", "database database database")
+
+	idx, err := New(config.DocsConfig{Sources: []string{dir}}, nil)
+	require.NoError(t, err)
+
+	docs, failures := idx.Documents(context.Background())
+	assert.Empty(t, failures)
+	require.Len(t, docs, 3, "only markdown files are indexed")
+	assert.Equal(t, "architecture/payments.md", docs[0].Path)
+
+	results := idx.Search(context.Background(), "RDS failover", 10)
+	require.Len(t, results, 2)
+	assert.Equal(t, "runbooks/rds-failover.md", results[0].Document.Path)
+	assert.Equal(t, "When the primary database fails, promote the replica.", results[0].Snippet)
+
+	results = idx.Search(context.Background(), "rebooting instance", 10)
+	require.Len(t, results, 1)
+	assert.Equal(t, "High CPU on web instances", results[0].Document.Title)
+
+	assert.Empty(t, idx.Search(context.Background(), "the of", 10), "stop words alone match nothing")
+	assert.NotNil(t, idx.Get(context.Background(), "runbooks/high-cpu.md"))
+	assert.Nil(t, idx.Get(context.Background(), "runbooks/missing.md"))
+}
+
+func TestRefreshKeepsFailedSources(t *testing.T) {
+	dir := t.TempDir()
+	writeDoc(t, dir, "local.md", "# Local\n\nOn disk.")
+
+	objects := &fakeObjects{objects: map[string]string{"runbooks/s3.md": "# From S3\n\nIn a bucket."}}
+	idx, err := New(config.DocsConfig{Sources: []string{dir, "s3://ops-docs/runbooks/"}, RefreshInterval: time.Minute}, objects)
+	require.NoError(t, err)
+	now := time.Date(2025, 6, 2, 10, 0, 0, 0, time.UTC)
+	idx.now = func() time.Time { return now }
+
+	docs, _ := idx.Documents(context.Background())
+	require.Len(t, docs, 2)
+	assert.Equal(t, "s3.md", docs[1].Path)
+	assert.Equal(t, 1, objects.gets)
+
+	objects.err = errors.New("access denied")
+	docs, _ = idx.Documents(context.Background())
+	assert.Len(t, docs, 2, "not reloaded within the refresh interval")
+
+	now = now.Add(2 * time.Minute)
+	docs, failures := idx.Documents(context.Background())
+	assert.Len(t, docs, 2, "documents of a failed source are kept")
+	assert.Contains(t, failures["s3://ops-docs/runbooks/"], "access denied")
+
+	objects.err = nil
+	now = now.Add(2 * time.Minute)
+	_, failures = idx.Documents(context.Background())
+	assert.Empty(t, failures)
+	assert.Equal(t, 1, objects.gets, "unchanged objects are not read again")
+}
+
+func TestNewValidatesSources(t *testing.T) {
+	idx, err := New(config.DocsConfig{}, nil)
+	require.NoError(t, err)
+	assert.Nil(t, idx)
+	assert.Empty(t, idx.Search(context.Background(), "anything", 10))
+
+	_, err = New(config.DocsConfig{Sources: []string{"s3://"}}, &fakeObjects{})
+	assert.Error(t, err)
+	_, err = New(config.DocsConfig{Sources: []string{"s3://ops-docs"}}, nil)
+	assert.Error(t, err)
+}
+
+// fakeObjects serves markdown from memory as objects of the ops-docs bucket
+type fakeObjects struct {
+	objects map[string]string
+	err     error
+	gets    int
+}
+
+func (f *fakeObjects) ListS3Objects(ctx context.Context, bucket, prefix string) ([]types.S3Object, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	var objects []types.S3Object
+	for key, content := range f.objects {
+		objects = append(objects, types.S3Object{Bucket: bucket, Key: key, Size: int64(len(content)), LastModified: time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)})
+	}
+	return objects, nil
+}
+
+func (f *fakeObjects) GetS3Object(ctx context.Context, bucket, key string, maxSize int64) ([]byte, error) {
+	f.gets++
+	return []byte(f.objects[key]), nil
+}
@@ -0,0 +1,342 @@
+package knowledge
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+)
+
+// defaultMaxEntries applies when knowledge.max_entries is not configured
+const defaultMaxEntries = 5000
+
+// Verdicts as reported by verify-remediation
+const (
+	VerdictResolved     = "resolved"
+	VerdictFlapping     = "flapping"
+	VerdictNotResolved  = "not_resolved"
+	VerdictInconclusive = "inconclusive"
+)
+
+// Signals that do not come from an alarm's metric
+const (
+	SignalStatusCheckFailed = "status-check-failed"
+	SignalUnhealthyTargets  = "unhealthy-targets"
+)
+
+// metricSignals names the signal of well-known CloudWatch metrics. Metrics not
+// listed here are named after the metric itself.
+var metricSignals = map[string]string{
+	"CPUUtilization":                     "cpu",
+	"MemoryUtilization":                  "memory",
+	"mem_used_percent":                   "memory",
+	"FreeableMemory":                     "memory",
+	"DatabaseConnections":                "db-connections",
+	"FreeStorageSpace":                   "storage",
+	"disk_used_percent":                  "disk",
+	"DiskQueueDepth":                     "disk-queue",
+	"ReadLatency":                        "latency",
+	"WriteLatency":                       "latency",
+	"TargetResponseTime":                 "latency",
+	"Latency":                            "latency",
+	"HTTPCode_Target_5XX_Count":          "5xx-errors",
+	"HTTPCode_ELB_5XX_Count":             "5xx-errors",
+	"5XXError":                           "5xx-errors",
+	"Errors":                             "errors",
+	"Throttles":                          "throttles",
+	"ApproximateAgeOfOldestMessage":      "queue-age",
+	"ApproximateNumberOfMessagesVisible": "queue-depth",
+	"UnHealthyHostCount":                 SignalUnhealthyTargets,
+	"StatusCheckFailed":                  SignalStatusCheckFailed,
+	"StatusCheckFailed_Instance":         SignalStatusCheckFailed,
+	"StatusCheckFailed_System":           SignalStatusCheckFailed,
+}
+
+// Outcome is one remediation and whether verification showed it worked
+type Outcome struct {
+	Time time.Time `json:"time"`
+	// Signal is the kind of problem, e.g. high-cpu, used to find what worked before
+	Signal string `json:"signal"`
+	// Subject is the concrete signal that was watched, e.g. alarm web-cpu-high
+	Subject string `json:"subject"`
+	// Action is the tool that remediated, e.g. reboot-rds-instance, if known
+	Action      string `json:"action,omitempty"`
+	Remediation string `json:"remediation,omitempty"`
+	Resource    string `json:"resource,omitempty"`
+	Verdict     string `json:"verdict"`
+	Summary     string `json:"summary,omitempty"`
+}
+
+// Worked reports whether the remediation fixed the signal
+func (o Outcome) Worked() bool {
+	return o.Verdict == VerdictResolved
+}
+
+// key identifies the remediation for summaries: the tool, or the description
+// when no tool is known
+func (o Outcome) key() string {
+	if o.Action != "" {
+		return o.Action
+	}
+	return o.Remediation
+}
+
+// Store keeps recent outcomes in memory and, with a path, appends every
+// outcome to a JSON Lines file that is reloaded on startup. A nil Store
+// discards outcomes, so callers need not check whether it is configured.
+type Store struct {
+	mu         sync.Mutex
+	path       string
+	maxEntries int
+	outcomes   []Outcome
+}
+
+// Open creates a store. With an empty path outcomes are only kept in memory.
+func Open(path string, maxEntries int) (*Store, error) {
+	if maxEntries <= 0 {
+		maxEntries = defaultMaxEntries
+	}
+
+	s := &Store{path: path, maxEntries: maxEntries}
+	if path == "" {
+		return s, nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return s, fmt.Errorf("failed to create knowledge directory: %w", err)
+	}
+
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return s, fmt.Errorf("failed to open remediation outcomes: %w", err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+	for scanner.Scan() {
+		var outcome Outcome
+		if err := json.Unmarshal(scanner.Bytes(), &outcome); err != nil {
+			// A torn last line from a crash should not lose the rest of the history
+			continue
+		}
+		s.append(outcome)
+	}
+	if err := scanner.Err(); err != nil {
+		return s, fmt.Errorf("failed to read remediation outcomes: %w", err)
+	}
+
+	return s, nil
+}
+
+// Record adds an outcome and appends it to the store file. The signal is
+// normalized so lookups match however it was spelled.
+func (s *Store) Record(outcome Outcome) error {
+	if s == nil {
+		return nil
+	}
+	if outcome.Time.IsZero() {
+		outcome.Time = time.Now()
+	}
+	outcome.Signal = Normalize(outcome.Signal)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.append(outcome)
+	if s.path == "" {
+		return nil
+	}
+
+	line, err := json.Marshal(outcome)
+	if err != nil {
+		return fmt.Errorf("failed to marshal remediation outcome: %w", err)
+	}
+
+	file, err := os.OpenFile(s.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open remediation outcomes: %w", err)
+	}
+	defer file.Close()
+
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to write remediation outcome: %w", err)
+	}
+	return nil
+}
+
+// Find returns the outcomes for signals similar to signal, newest first. A
+// signal is similar when it has every word of the query, so "cpu" finds
+// high-cpu and low-cpu; an empty query returns all outcomes.
+func (s *Store) Find(signal string) []Outcome {
+	if s == nil {
+		return nil
+	}
+
+	words := strings.Split(Normalize(signal), "-")
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var found []Outcome
+	for _, outcome := range s.outcomes {
+		if signal == "" || hasWords(outcome.Signal, words) {
+			found = append(found, outcome)
+		}
+	}
+	sort.SliceStable(found, func(i, j int) bool {
+		return found[i].Time.After(found[j].Time)
+	})
+	return found
+}
+
+// append keeps at least the newest maxEntries outcomes in memory. Old ones
+// are dropped in batches so loading a long history stays linear.
+func (s *Store) append(outcome Outcome) {
+	s.outcomes = append(s.outcomes, outcome)
+	if len(s.outcomes) > s.maxEntries+s.maxEntries/4 {
+		s.outcomes = append([]Outcome(nil), s.outcomes[len(s.outcomes)-s.maxEntries:]...)
+	}
+}
+
+// hasWords reports whether a normalized signal contains every word
+func hasWords(signal string, words []string) bool {
+	have := strings.Split(signal, "-")
+	for _, word := range words {
+		found := false
+		for _, candidate := range have {
+			if candidate == word {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// ActionSummary is how one remediation fared for a signal
+type ActionSummary struct {
+	Action       string    `json:"action"`
+	Attempts     int       `json:"attempts"`
+	Resolved     int       `json:"resolved"`
+	Flapping     int       `json:"flapping"`
+	NotResolved  int       `json:"not_resolved"`
+	Inconclusive int       `json:"inconclusive"`
+	LastVerdict  string    `json:"last_verdict"`
+	LastTime     time.Time `json:"last_time"`
+}
+
+// SuccessRate is the share of verified attempts that resolved the signal.
+// Inconclusive attempts are not counted; with none verified it is zero.
+func (a ActionSummary) SuccessRate() float64 {
+	verified := a.Attempts - a.Inconclusive
+	if verified == 0 {
+		return 0
+	}
+	return float64(a.Resolved) / float64(verified)
+}
+
+// Summarize groups outcomes by remediation, the most successful first and,
+// among equally successful ones, the most tried. Outcomes must be newest
+// first, as Find returns them.
+func Summarize(outcomes []Outcome) []ActionSummary {
+	byAction := make(map[string]*ActionSummary)
+	var order []string
+	for _, outcome := range outcomes {
+		key := outcome.key()
+		if key == "" {
+			key = "unknown"
+		}
+		summary, ok := byAction[key]
+		if !ok {
+			summary = &ActionSummary{Action: key, LastVerdict: outcome.Verdict, LastTime: outcome.Time}
+			byAction[key] = summary
+			order = append(order, key)
+		}
+		summary.Attempts++
+		switch outcome.Verdict {
+		case VerdictResolved:
+			summary.Resolved++
+		case VerdictFlapping:
+			summary.Flapping++
+		case VerdictNotResolved:
+			summary.NotResolved++
+		default:
+			summary.Inconclusive++
+		}
+	}
+
+	summaries := make([]ActionSummary, 0, len(order))
+	for _, key := range order {
+		summaries = append(summaries, *byAction[key])
+	}
+	sort.SliceStable(summaries, func(i, j int) bool {
+		if summaries[i].SuccessRate() != summaries[j].SuccessRate() {
+			return summaries[i].SuccessRate() > summaries[j].SuccessRate()
+		}
+		return summaries[i].Attempts > summaries[j].Attempts
+	})
+	return summaries
+}
+
+// AlarmSignal names the signal of a metric alarm, e.g. high-cpu for
+// CPUUtilization above a threshold. Status checks and unhealthy hosts have no
+// direction.
+func AlarmSignal(metricName, comparison string) string {
+	name, known := metricSignals[metricName]
+	if !known {
+		name = Normalize(metricName)
+	}
+	if name == SignalStatusCheckFailed || name == SignalUnhealthyTargets || name == "" {
+		return name
+	}
+
+	switch {
+	case strings.HasPrefix(comparison, "GreaterThan"):
+		return "high-" + name
+	case strings.HasPrefix(comparison, "LessThan"):
+		return "low-" + name
+	}
+	return name
+}
+
+// Normalize turns a signal into lower-case words joined by dashes, so
+// "High CPU", "high_cpu" and "HighCPU" are all high-cpu
+func Normalize(signal string) string {
+	var b strings.Builder
+	runes := []rune(strings.TrimSpace(signal))
+	dash := func() {
+		if b.Len() > 0 && !strings.HasSuffix(b.String(), "-") {
+			b.WriteByte('-')
+		}
+	}
+	for i, r := range runes {
+		switch {
+		case unicode.IsUpper(r):
+			// A new word starts at an upper-case letter after a lower-case one,
+			// or before a lower-case one at the end of an acronym
+			if i > 0 && (unicode.IsLower(runes[i-1]) || (i+1 < len(runes) && unicode.IsLower(runes[i+1]) && unicode.IsUpper(runes[i-1]))) {
+				dash()
+			}
+			b.WriteRune(unicode.ToLower(r))
+		case unicode.IsLower(r) || unicode.IsDigit(r):
+			b.WriteRune(r)
+		default:
+			dash()
+		}
+	}
+	return strings.TrimSuffix(b.String(), "-")
+}
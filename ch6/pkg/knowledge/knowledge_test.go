@@ -0,0 +1,82 @@
+package knowledge
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStorePersistsAndFindsSimilarSignals(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "knowledge", "remediations.jsonl")
+	base := time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC)
+
+	store, err := Open(path, 0)
+	require.NoError(t, err)
+	require.NoError(t, store.Record(Outcome{Time: base, Signal: "High CPU", Subject: "alarm web-cpu", Action: "reboot-ec2-instance", Verdict: VerdictFlapping}))
+	require.NoError(t, store.Record(Outcome{Time: base.Add(time.Hour), Signal: "high-cpu", Subject: "alarm api-cpu", Action: "update-ecs-service", Verdict: VerdictResolved}))
+	require.NoError(t, store.Record(Outcome{Time: base.Add(2 * time.Hour), Signal: "low-storage", Subject: "alarm db-disk", Action: "modify-ebs-volume", Verdict: VerdictResolved}))
+
+	reloaded, err := Open(path, 0)
+	require.NoError(t, err)
+
+	found := reloaded.Find("high_cpu")
+	require.Len(t, found, 2)
+	assert.Equal(t, "update-ecs-service", found[0].Action, "newest first")
+	assert.Equal(t, "high-cpu", found[1].Signal, "signals are normalized when recorded")
+
+	assert.Len(t, reloaded.Find("cpu"), 2)
+	assert.Len(t, reloaded.Find(""), 3)
+	assert.Empty(t, reloaded.Find("memory"))
+}
+
+func TestSummarize(t *testing.T) {
+	base := time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC)
+	outcomes := []Outcome{
+		{Time: base.Add(4 * time.Hour), Action: "reboot-ec2-instance", Verdict: VerdictNotResolved},
+		{Time: base.Add(3 * time.Hour), Action: "update-ecs-service", Verdict: VerdictResolved},
+		{Time: base.Add(2 * time.Hour), Action: "reboot-ec2-instance", Verdict: VerdictResolved},
+		{Time: base.Add(time.Hour), Remediation: "restarted nginx", Verdict: VerdictInconclusive},
+		{Time: base, Action: "update-ecs-service", Verdict: VerdictResolved},
+	}
+
+	summaries := Summarize(outcomes)
+	require.Len(t, summaries, 3)
+	assert.Equal(t, "update-ecs-service", summaries[0].Action)
+	assert.Equal(t, 1.0, summaries[0].SuccessRate())
+	assert.Equal(t, base.Add(3*time.Hour), summaries[0].LastTime)
+	assert.Equal(t, ActionSummary{Action: "reboot-ec2-instance", Attempts: 2, Resolved: 1, NotResolved: 1, LastVerdict: VerdictNotResolved, LastTime: base.Add(4 * time.Hour)}, summaries[1])
+	assert.Equal(t, "restarted nginx", summaries[2].Action)
+	assert.Zero(t, summaries[2].SuccessRate(), "inconclusive attempts are not verified")
+}
+
+func TestAlarmSignal(t *testing.T) {
+	assert.Equal(t, "high-cpu", AlarmSignal("CPUUtilization", "GreaterThanOrEqualToThreshold"))
+	assert.Equal(t, "low-storage", AlarmSignal("FreeStorageSpace", "LessThanThreshold"))
+	assert.Equal(t, SignalStatusCheckFailed, AlarmSignal("StatusCheckFailed_System", "GreaterThanThreshold"))
+	assert.Equal(t, "high-consumer-lag", AlarmSignal("ConsumerLag", "GreaterThanThreshold"))
+	assert.Equal(t, "", AlarmSignal("", ""), "composite alarms have no metric")
+}
+
+func TestNormalize(t *testing.T) {
+	for input, expected := range map[string]string{
+		"High CPU":         "high-cpu",
+		"high_cpu":         "high-cpu",
+		"HighCPU":          "high-cpu",
+		"DBConnections":    "db-connections",
+		" 5xx  errors! ":   "5xx-errors",
+		"queue-age":        "queue-age",
+		"CPUUtilization":   "cpu-utilization",
+		"mem_used_percent": "mem-used-percent",
+	} {
+		assert.Equal(t, expected, Normalize(input), input)
+	}
+}
+
+func TestNilStore(t *testing.T) {
+	var store *Store
+	assert.NoError(t, store.Record(Outcome{Signal: "high-cpu"}))
+	assert.Nil(t, store.Find("high-cpu"))
+}
@@ -0,0 +1,46 @@
+package logs
+
+import (
+	"context"
+	"errors"
+
+	"aws-mcp-server/pkg/aws"
+)
+
+// CloudWatchBackend queries CloudWatch Logs log groups
+type CloudWatchBackend struct {
+	client *aws.Client
+}
+
+// NewCloudWatchBackend creates a backend on top of the server's AWS client
+func NewCloudWatchBackend(client *aws.Client) *CloudWatchBackend {
+	return &CloudWatchBackend{client: client}
+}
+
+// Name returns the backend name
+func (b *CloudWatchBackend) Name() string {
+	return BackendCloudWatch
+}
+
+// Query returns events of the log group in Source, oldest first
+func (b *CloudWatchBackend) Query(ctx context.Context, query Query) ([]Entry, error) {
+	if query.Source == "" {
+		return nil, errors.New("a log group is required for CloudWatch Logs")
+	}
+
+	events, err := b.client.FilterLogEvents(ctx, query.Source, query.Filter, query.Start, query.End, query.Limit)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]Entry, 0, len(events))
+	for _, event := range events {
+		entries = append(entries, Entry{
+			Timestamp: event.Timestamp,
+			Message:   event.Message,
+			Level:     DetectLevel(event.Message),
+			Stream:    event.LogStream,
+		})
+	}
+	return entries, nil
+}
@@ -0,0 +1,193 @@
+package logs
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"aws-mcp-server/internal/config"
+	"aws-mcp-server/internal/logging"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Default field names follow the Elastic Common Schema
+const (
+	defaultTimestampField = "@timestamp"
+	defaultMessageField   = "message"
+	defaultLevelField     = "log.level"
+)
+
+// ElasticsearchBackend searches log indices in Elasticsearch or OpenSearch
+type ElasticsearchBackend struct {
+	http           httpBackend
+	timestampField string
+	messageField   string
+	levelField     string
+	logger         *logging.Logger
+}
+
+// NewElasticsearchBackend creates a backend for an Elasticsearch or OpenSearch endpoint
+func NewElasticsearchBackend(cfg config.ElasticsearchConfig, timeout time.Duration, logger *logging.Logger) (*ElasticsearchBackend, error) {
+	if cfg.URL == "" {
+		return nil, errors.New("logs.elasticsearch.url is required")
+	}
+
+	headers := make(map[string]string, len(cfg.Headers)+1)
+	for key, value := range cfg.Headers {
+		headers[key] = value
+	}
+	if cfg.APIKey != "" {
+		headers["Authorization"] = "ApiKey " + cfg.APIKey
+	} else if cfg.Username != "" {
+		headers["Authorization"] = "Basic " + base64.StdEncoding.EncodeToString([]byte(cfg.Username+":"+cfg.Password))
+	}
+
+	h, err := newHTTPBackend(cfg.URL, headers, timeout)
+	if err != nil {
+		return nil, fmt.Errorf("elasticsearch: %w", err)
+	}
+
+	return &ElasticsearchBackend{
+		http:           h,
+		timestampField: valueOr(cfg.TimestampField, defaultTimestampField),
+		messageField:   valueOr(cfg.MessageField, defaultMessageField),
+		levelField:     valueOr(cfg.LevelField, defaultLevelField),
+		logger:         logger,
+	}, nil
+}
+
+// Name returns the backend name
+func (b *ElasticsearchBackend) Name() string {
+	return BackendElasticsearch
+}
+
+// esResponse is the part of a _search response used here
+type esResponse struct {
+	Hits struct {
+		Hits []struct {
+			Index  string                 `json:"_index"`
+			Source map[string]interface{} `json:"_source"`
+		} `json:"hits"`
+	} `json:"hits"`
+}
+
+// Query searches the index pattern in Source and returns the newest entries first
+func (b *ElasticsearchBackend) Query(ctx context.Context, query Query) ([]Entry, error) {
+	start := time.Now()
+
+	if query.Source == "" {
+		return nil, errors.New("an index pattern is required for Elasticsearch, e.g. logs-*")
+	}
+
+	boolQuery := map[string]interface{}{
+		"filter": []interface{}{
+			map[string]interface{}{
+				"range": map[string]interface{}{
+					b.timestampField: map[string]interface{}{
+						"gte":    query.Start.UTC().Format(time.RFC3339Nano),
+						"lte":    query.End.UTC().Format(time.RFC3339Nano),
+						"format": "strict_date_optional_time",
+					},
+				},
+			},
+		},
+	}
+	if query.Filter != "" {
+		boolQuery["must"] = []interface{}{
+			map[string]interface{}{
+				"query_string": map[string]interface{}{"query": query.Filter},
+			},
+		}
+	}
+
+	body := map[string]interface{}{
+		"size":    query.Limit,
+		"sort":    []interface{}{map[string]interface{}{b.timestampField: map[string]string{"order": "desc"}}},
+		"query":   map[string]interface{}{"bool": boolQuery},
+		"_source": []string{b.timestampField, b.messageField, b.levelField, "host.name", "service.name", "kubernetes.pod.name"},
+	}
+
+	var resp esResponse
+	path := "/" + strings.Trim(query.Source, "/") + "/_search"
+	if err := b.http.do(ctx, http.MethodPost, path, nil, body, &resp); err != nil {
+		return nil, fmt.Errorf("elasticsearch search failed: %w", err)
+	}
+
+	entries := make([]Entry, 0, len(resp.Hits.Hits))
+	for _, hit := range resp.Hits.Hits {
+		message, _ := lookup(hit.Source, b.messageField).(string)
+		entry := Entry{
+			Timestamp: parseTimestamp(lookup(hit.Source, b.timestampField)),
+			Message:   message,
+			Stream:    hit.Index,
+			Labels:    make(map[string]string),
+		}
+
+		if level, ok := lookup(hit.Source, b.levelField).(string); ok {
+			entry.Level = NormalizeLevel(level)
+		}
+		if entry.Level == "" {
+			entry.Level = DetectLevel(message)
+		}
+
+		for _, field := range []string{"host.name", "service.name", "kubernetes.pod.name"} {
+			if value, ok := lookup(hit.Source, field).(string); ok {
+				entry.Labels[field] = value
+			}
+		}
+
+		entries = append(entries, entry)
+	}
+
+	b.logger.WithFields(logrus.Fields{
+		"index":    query.Source,
+		"count":    len(entries),
+		"duration": time.Since(start),
+	}).Info("Retrieved Elasticsearch log entries")
+
+	return entries, nil
+}
+
+// lookup reads a dotted field from a document, supporting both nested objects
+// ({"log":{"level":"error"}}) and flattened keys ({"log.level":"error"})
+func lookup(doc map[string]interface{}, field string) interface{} {
+	if value, ok := doc[field]; ok {
+		return value
+	}
+
+	head, rest, found := strings.Cut(field, ".")
+	if !found {
+		return nil
+	}
+	nested, ok := doc[head].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	return lookup(nested, rest)
+}
+
+// parseTimestamp accepts RFC3339 strings and epoch milliseconds
+func parseTimestamp(value interface{}) time.Time {
+	switch v := value.(type) {
+	case string:
+		if t, err := time.Parse(time.RFC3339Nano, v); err == nil {
+			return t
+		}
+	case float64:
+		return time.UnixMilli(int64(v))
+	}
+	return time.Time{}
+}
+
+// valueOr returns value, or fallback when value is empty
+func valueOr(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
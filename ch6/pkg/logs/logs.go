@@ -0,0 +1,103 @@
+package logs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"aws-mcp-server/internal/config"
+	"aws-mcp-server/internal/logging"
+	"aws-mcp-server/pkg/aws"
+)
+
+// Supported backends
+const (
+	BackendCloudWatch    = "cloudwatch"
+	BackendLoki          = "loki"
+	BackendElasticsearch = "elasticsearch"
+)
+
+// Query selects log entries. Source and Filter are interpreted by the backend:
+//
+//	cloudwatch:    Source is a log group, Filter a CloudWatch filter pattern
+//	loki:          Source is a LogQL stream selector such as {app="api"}, Filter a line filter
+//	elasticsearch: Source is an index pattern, Filter a query_string query
+type Query struct {
+	Source string
+	Filter string
+	Start  time.Time
+	End    time.Time
+	Limit  int
+}
+
+// Entry is a log line in a backend-neutral form
+type Entry struct {
+	Timestamp time.Time         `json:"timestamp"`
+	Message   string            `json:"message"`
+	Level     string            `json:"level,omitempty"`
+	Stream    string            `json:"stream,omitempty"`
+	Labels    map[string]string `json:"labels,omitempty"`
+}
+
+// Backend runs log queries against one log store
+type Backend interface {
+	Name() string
+	Query(ctx context.Context, query Query) ([]Entry, error)
+}
+
+// New creates the configured backend. CloudWatch Logs is the default and uses
+// the server's AWS client.
+func New(cfg config.LogsConfig, awsClient *aws.Client, logger *logging.Logger) (Backend, error) {
+	switch strings.ToLower(cfg.Backend) {
+	case "", BackendCloudWatch:
+		if awsClient == nil {
+			return nil, errors.New("cloudwatch log backend needs an AWS client")
+		}
+		return NewCloudWatchBackend(awsClient), nil
+	case BackendLoki:
+		return NewLokiBackend(cfg.Loki, cfg.Timeout, logger)
+	case BackendElasticsearch:
+		return NewElasticsearchBackend(cfg.Elasticsearch, cfg.Timeout, logger)
+	default:
+		return nil, fmt.Errorf("unknown log backend %q (use cloudwatch, loki or elasticsearch)", cfg.Backend)
+	}
+}
+
+// levelPattern finds a severity keyword in unstructured and JSON log lines,
+// e.g. "ERROR ...", "level=warn", `"level":"info"` or "[Warning]"
+var levelPattern = regexp.MustCompile(`(?i)(?:^|[\s\[("=:|])(fatal|panic|critical|crit|error|err|warning|warn|info|debug|trace)(?:$|[\s\]),"|:])`)
+
+// DetectLevel returns the normalized level of a log line, or "" when none is found
+func DetectLevel(message string) string {
+	// Only the start of a line carries the level in every format we care about
+	if len(message) > 200 {
+		message = message[:200]
+	}
+
+	match := levelPattern.FindStringSubmatch(message)
+	if match == nil {
+		return ""
+	}
+	return NormalizeLevel(match[1])
+}
+
+// NormalizeLevel maps level spellings to error, warn, info, debug or trace
+func NormalizeLevel(level string) string {
+	switch strings.ToLower(level) {
+	case "fatal", "panic", "critical", "crit", "error", "err":
+		return "error"
+	case "warning", "warn":
+		return "warn"
+	case "info", "information", "notice":
+		return "info"
+	case "debug":
+		return "debug"
+	case "trace":
+		return "trace"
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,142 @@
+package logs
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"aws-mcp-server/internal/config"
+	"aws-mcp-server/internal/logging"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectLevel(t *testing.T) {
+	cases := map[string]string{
+		"2025-06-01T10:00:00Z ERROR payment failed":          "error",
+		`{"level":"warn","msg":"slow query"}`:                "warn",
+		"time=2025-06-01 level=info msg=started":             "info",
+		"[Warning] disk almost full":                         "warn",
+		"FATAL: could not connect":                           "error",
+		"GET /healthz 200":                                   "",
+		"user errorhandler registered (not a level keyword)": "",
+	}
+	for message, expected := range cases {
+		assert.Equal(t, expected, DetectLevel(message), message)
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	a := Normalize("2025-06-01T10:00:00.123Z request 4f1c2a3b-9d8e-4f00-a1b2-c3d4e5f60718 from 10.0.3.17:5432 took 153ms (instance i-0abc123def4567890)")
+	b := Normalize("2025-06-01T11:30:00.999Z request 0a1b2c3d-1111-2222-3333-444455556666 from 10.0.9.2:5432 took 8ms (instance i-0fff123def4567890)")
+	assert.Equal(t, a, b)
+	assert.Equal(t, "<time> request <uuid> from <ip> took <num> (instance <id>)", a)
+
+	assert.Equal(t, "trace <hex> failed", Normalize("trace 5f9c2b7e8a01 failed"))
+	assert.Equal(t, "panic: nil map", Normalize("panic: nil map\n\tgoroutine 1 [running]:"))
+}
+
+func TestSummarize(t *testing.T) {
+	base := time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC)
+	entries := []Entry{
+		{Timestamp: base, Message: "ERROR timeout calling db after 3000ms", Level: "error"},
+		{Timestamp: base.Add(time.Minute), Message: "GET /api/users/17 200", Level: ""},
+		{Timestamp: base.Add(2 * time.Minute), Message: "ERROR timeout calling db after 2999ms", Level: "error"},
+		{Timestamp: base.Add(3 * time.Minute), Message: "GET /api/users/18 200"},
+		{Timestamp: base.Add(4 * time.Minute), Message: "GET /api/users/19 200"},
+		{Timestamp: base.Add(5 * time.Minute), Message: "WARN retrying", Level: "warn"},
+	}
+
+	summary := Summarize(entries, 2)
+	assert.Equal(t, 6, summary.Total)
+	assert.Equal(t, map[string]int{"error": 2, "warn": 1, "unknown": 3}, summary.Levels)
+	assert.Equal(t, 3, summary.DistinctCount)
+	assert.Equal(t, base, summary.Start)
+	assert.Equal(t, base.Add(5*time.Minute), summary.End)
+
+	require.Len(t, summary.Patterns, 2)
+	assert.Equal(t, 3, summary.Patterns[0].Count)
+	assert.Equal(t, "GET /api/users/<num> <num>", summary.Patterns[0].Pattern)
+
+	require.Len(t, summary.ErrorPatterns, 2)
+	assert.Equal(t, "error", summary.ErrorPatterns[0].Level)
+	assert.Equal(t, 2, summary.ErrorPatterns[0].Count)
+	assert.Equal(t, base.Add(2*time.Minute), summary.ErrorPatterns[0].LastSeen)
+	assert.Equal(t, "warn", summary.ErrorPatterns[1].Level)
+}
+
+func TestLokiBackend(t *testing.T) {
+	var query, tenant string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query().Get("query")
+		tenant = r.Header.Get("X-Scope-OrgID")
+		w.Write([]byte(`{"status":"success","data":{"resultType":"streams","result":[
+			{"stream":{"app":"api","level":"error"},"values":[["1748772000000000000","db timeout"]]},
+			{"stream":{"app":"api"},"values":[["1748772060000000000","WARN slow request"],["1748771940000000000","started"]]}]}}`))
+	}))
+	defer server.Close()
+
+	backend, err := NewLokiBackend(config.LokiConfig{URL: server.URL, TenantID: "team-a"}, 0, logging.NewLogger("error", "text"))
+	require.NoError(t, err)
+
+	end := time.Unix(1748772100, 0)
+	entries, err := backend.Query(context.Background(), Query{Source: `{app="api"}`, Filter: "timeout", Start: end.Add(-time.Hour), End: end, Limit: 2})
+	require.NoError(t, err)
+
+	assert.Equal(t, "{app=\"api\"} |= `timeout`", query)
+	assert.Equal(t, "team-a", tenant)
+	require.Len(t, entries, 2, "merged streams are cut to the limit")
+	assert.Equal(t, "WARN slow request", entries[0].Message)
+	assert.Equal(t, "warn", entries[0].Level)
+	assert.Equal(t, "error", entries[1].Level, "the level label wins over detection")
+	assert.Equal(t, `{app="api",level="error"}`, entries[1].Stream)
+
+	_, err = backend.Query(context.Background(), Query{Source: "api", Start: end.Add(-time.Hour), End: end, Limit: 10})
+	assert.ErrorContains(t, err, "stream selector")
+}
+
+func TestElasticsearchBackend(t *testing.T) {
+	var path string
+	var body map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		json.NewDecoder(r.Body).Decode(&body)
+		w.Write([]byte(`{"hits":{"hits":[
+			{"_index":"logs-2025.06.01","_source":{"@timestamp":"2025-06-01T10:00:00Z","message":"payment failed","log":{"level":"ERROR"},"service":{"name":"billing"}}},
+			{"_index":"logs-2025.06.01","_source":{"@timestamp":"2025-06-01T09:59:00Z","message":"INFO started","log.level":""}}]}}`))
+	}))
+	defer server.Close()
+
+	backend, err := NewElasticsearchBackend(config.ElasticsearchConfig{URL: server.URL, APIKey: "key"}, 0, logging.NewLogger("error", "text"))
+	require.NoError(t, err)
+
+	end := time.Date(2025, 6, 1, 10, 30, 0, 0, time.UTC)
+	entries, err := backend.Query(context.Background(), Query{Source: "logs-*", Filter: "payment", Start: end.Add(-time.Hour), End: end, Limit: 50})
+	require.NoError(t, err)
+
+	assert.Equal(t, "/logs-*/_search", path)
+	assert.Equal(t, float64(50), body["size"])
+	require.Len(t, entries, 2)
+	assert.Equal(t, "error", entries[0].Level)
+	assert.Equal(t, "billing", entries[0].Labels["service.name"])
+	assert.Equal(t, time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC), entries[0].Timestamp)
+	assert.Equal(t, "info", entries[1].Level, "falls back to detecting the level in the message")
+}
+
+func TestNewSelectsBackend(t *testing.T) {
+	logger := logging.NewLogger("error", "text")
+
+	backend, err := New(config.LogsConfig{Backend: "loki", Loki: config.LokiConfig{URL: "http://loki:3100"}}, nil, logger)
+	require.NoError(t, err)
+	assert.Equal(t, BackendLoki, backend.Name())
+
+	_, err = New(config.LogsConfig{Backend: "elasticsearch"}, nil, logger)
+	assert.ErrorContains(t, err, "url is required")
+
+	_, err = New(config.LogsConfig{Backend: "splunk"}, nil, logger)
+	assert.ErrorContains(t, err, "unknown log backend")
+}
@@ -0,0 +1,178 @@
+package logs
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"aws-mcp-server/internal/config"
+	"aws-mcp-server/internal/logging"
+
+	"github.com/sirupsen/logrus"
+)
+
+// LokiBackend queries Grafana Loki with LogQL
+type LokiBackend struct {
+	http   httpBackend
+	logger *logging.Logger
+}
+
+// NewLokiBackend creates a backend for a Loki endpoint. TenantID sets the
+// X-Scope-OrgID header used by multi-tenant Loki and Grafana Cloud.
+func NewLokiBackend(cfg config.LokiConfig, timeout time.Duration, logger *logging.Logger) (*LokiBackend, error) {
+	if cfg.URL == "" {
+		return nil, errors.New("logs.loki.url is required")
+	}
+
+	headers := make(map[string]string, len(cfg.Headers)+2)
+	for key, value := range cfg.Headers {
+		headers[key] = value
+	}
+	if cfg.TenantID != "" {
+		headers["X-Scope-OrgID"] = cfg.TenantID
+	}
+	if cfg.BearerToken != "" {
+		headers["Authorization"] = "Bearer " + cfg.BearerToken
+	} else if cfg.Username != "" {
+		headers["Authorization"] = "Basic " + base64.StdEncoding.EncodeToString([]byte(cfg.Username+":"+cfg.Password))
+	}
+
+	h, err := newHTTPBackend(cfg.URL, headers, timeout)
+	if err != nil {
+		return nil, fmt.Errorf("loki: %w", err)
+	}
+	return &LokiBackend{http: h, logger: logger}, nil
+}
+
+// Name returns the backend name
+func (b *LokiBackend) Name() string {
+	return BackendLoki
+}
+
+// lokiResponse is the query_range response for log queries
+type lokiResponse struct {
+	Status string `json:"status"`
+	Data   struct {
+		ResultType string `json:"resultType"`
+		Result     []struct {
+			Stream map[string]string `json:"stream"`
+			Values [][2]string       `json:"values"`
+		} `json:"result"`
+	} `json:"data"`
+}
+
+// Query runs a LogQL log query and returns the newest entries first
+func (b *LokiBackend) Query(ctx context.Context, query Query) ([]Entry, error) {
+	start := time.Now()
+
+	logQL, err := lokiQuery(query.Source, query.Filter)
+	if err != nil {
+		return nil, err
+	}
+
+	params := url.Values{
+		"query":     {logQL},
+		"start":     {strconv.FormatInt(query.Start.UnixNano(), 10)},
+		"end":       {strconv.FormatInt(query.End.UnixNano(), 10)},
+		"limit":     {strconv.Itoa(query.Limit)},
+		"direction": {"backward"},
+	}
+
+	var resp lokiResponse
+	if err := b.http.do(ctx, http.MethodGet, "/loki/api/v1/query_range", params, nil, &resp); err != nil {
+		return nil, fmt.Errorf("loki query failed: %w", err)
+	}
+	if resp.Data.ResultType != "streams" {
+		return nil, fmt.Errorf("loki returned %q instead of log streams; metric queries are not supported", resp.Data.ResultType)
+	}
+
+	var entries []Entry
+	for _, stream := range resp.Data.Result {
+		level := NormalizeLevel(firstLabel(stream.Stream, "level", "detected_level", "severity"))
+		name := formatLabels(stream.Stream)
+
+		for _, value := range stream.Values {
+			nanos, err := strconv.ParseInt(value[0], 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("loki returned malformed timestamp %q", value[0])
+			}
+
+			entry := Entry{
+				Timestamp: time.Unix(0, nanos),
+				Message:   value[1],
+				Level:     level,
+				Stream:    name,
+				Labels:    stream.Stream,
+			}
+			if entry.Level == "" {
+				entry.Level = DetectLevel(entry.Message)
+			}
+			entries = append(entries, entry)
+		}
+	}
+
+	// Streams are returned separately; merge them newest first
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Timestamp.After(entries[j].Timestamp)
+	})
+	if len(entries) > query.Limit {
+		entries = entries[:query.Limit]
+	}
+
+	b.logger.WithFields(logrus.Fields{
+		"query":    logQL,
+		"count":    len(entries),
+		"duration": time.Since(start),
+	}).Info("Retrieved Loki log entries")
+
+	return entries, nil
+}
+
+// lokiQuery builds a LogQL query from a stream selector and a plain-text filter
+func lokiQuery(selector, filter string) (string, error) {
+	selector = strings.TrimSpace(selector)
+	if !strings.HasPrefix(selector, "{") {
+		return "", fmt.Errorf("loki needs a LogQL stream selector such as {app=\"api\"}, got %q", selector)
+	}
+	if filter == "" {
+		return selector, nil
+	}
+
+	quoted := "`" + filter + "`"
+	if strings.Contains(filter, "`") {
+		quoted = strconv.Quote(filter)
+	}
+	return selector + " |= " + quoted, nil
+}
+
+// firstLabel returns the value of the first label present
+func firstLabel(labels map[string]string, names ...string) string {
+	for _, name := range names {
+		if value, ok := labels[name]; ok {
+			return value
+		}
+	}
+	return ""
+}
+
+// formatLabels renders a label set as a stream selector
+func formatLabels(labels map[string]string) string {
+	keys := make([]string, 0, len(labels))
+	for key := range labels {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, 0, len(keys))
+	for _, key := range keys {
+		pairs = append(pairs, fmt.Sprintf("%s=%q", key, labels[key]))
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
@@ -0,0 +1,174 @@
+package logs
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// maxPatternLength keeps patterns of very long lines readable
+const maxPatternLength = 200
+
+// Pattern is a group of log lines that only differ in variable parts such as
+// IDs, numbers and addresses
+type Pattern struct {
+	Pattern   string    `json:"pattern"`
+	Level     string    `json:"level,omitempty"`
+	Count     int       `json:"count"`
+	Example   string    `json:"example"`
+	FirstSeen time.Time `json:"first_seen"`
+	LastSeen  time.Time `json:"last_seen"`
+}
+
+// Summary condenses many log entries into level counts and recurring patterns
+type Summary struct {
+	Total         int            `json:"total"`
+	Levels        map[string]int `json:"levels"`
+	DistinctCount int            `json:"distinct_patterns"`
+	Patterns      []Pattern      `json:"top_patterns"`
+	ErrorPatterns []Pattern      `json:"top_error_patterns"`
+	Start         time.Time      `json:"start"`
+	End           time.Time      `json:"end"`
+}
+
+// normalizers replace variable tokens with placeholders, most specific first
+var normalizers = []struct {
+	pattern     *regexp.Regexp
+	placeholder string
+}{
+	{regexp.MustCompile(`\b[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}\b`), "<uuid>"},
+	{regexp.MustCompile(`\d{4}-\d{2}-\d{2}[T ]\d{2}:\d{2}:\d{2}(?:[.,]\d+)?(?:Z|[+-]\d{2}:?\d{2})?`), "<time>"},
+	{regexp.MustCompile(`\b\d{1,3}(?:\.\d{1,3}){3}(?::\d+)?\b`), "<ip>"},
+	{regexp.MustCompile(`\b[a-z]{1,6}-[0-9a-f]{8,17}\b`), "<id>"},
+	{regexp.MustCompile(`\b(?:0x)?[0-9a-fA-F]{8,}\b`), "<hex>"},
+	{regexp.MustCompile(`\b\d+(?:\.\d+)?(?:ms|s|m|h|µs|us|ns|%|KB|MB|GB|B)?\b`), "<num>"},
+}
+
+// Normalize reduces a log line to its pattern
+func Normalize(message string) string {
+	line := strings.TrimSpace(message)
+	if i := strings.IndexByte(line, '\n'); i >= 0 {
+		// Stack traces and multi-line messages are grouped by their first line
+		line = line[:i]
+	}
+
+	for _, n := range normalizers {
+		if n.placeholder == "<hex>" {
+			// Hashes and trace IDs mix digits and letters; plain numbers and
+			// words such as "deadbeef" are left to the other rules
+			line = n.pattern.ReplaceAllStringFunc(line, func(match string) string {
+				digits := strings.TrimPrefix(match, "0x")
+				if strings.IndexAny(digits, "0123456789") < 0 || strings.IndexAny(digits, "abcdefABCDEF") < 0 {
+					return match
+				}
+				return n.placeholder
+			})
+			continue
+		}
+		line = n.pattern.ReplaceAllString(line, n.placeholder)
+	}
+
+	if len(line) > maxPatternLength {
+		line = line[:maxPatternLength] + "…"
+	}
+	return line
+}
+
+// Summarize groups entries by pattern and returns the top patterns overall and
+// the top error and warning patterns
+func Summarize(entries []Entry, top int) Summary {
+	summary := Summary{
+		Total:  len(entries),
+		Levels: make(map[string]int),
+	}
+
+	groups := make(map[string]*Pattern)
+	var order []*Pattern
+	for _, entry := range entries {
+		level := entry.Level
+		if level == "" {
+			level = "unknown"
+		}
+		summary.Levels[level]++
+
+		if summary.Start.IsZero() || entry.Timestamp.Before(summary.Start) {
+			summary.Start = entry.Timestamp
+		}
+		if entry.Timestamp.After(summary.End) {
+			summary.End = entry.Timestamp
+		}
+
+		pattern := Normalize(entry.Message)
+		key := entry.Level + "\x00" + pattern
+		group, exists := groups[key]
+		if !exists {
+			group = &Pattern{
+				Pattern:   pattern,
+				Level:     entry.Level,
+				Example:   firstLine(entry.Message),
+				FirstSeen: entry.Timestamp,
+				LastSeen:  entry.Timestamp,
+			}
+			groups[key] = group
+			order = append(order, group)
+		}
+
+		group.Count++
+		if entry.Timestamp.Before(group.FirstSeen) {
+			group.FirstSeen = entry.Timestamp
+		}
+		if entry.Timestamp.After(group.LastSeen) {
+			group.LastSeen = entry.Timestamp
+		}
+	}
+	summary.DistinctCount = len(order)
+
+	sort.SliceStable(order, func(i, j int) bool {
+		if order[i].Count != order[j].Count {
+			return order[i].Count > order[j].Count
+		}
+		return levelRank(order[i].Level) > levelRank(order[j].Level)
+	})
+
+	summary.Patterns = make([]Pattern, 0, top)
+	summary.ErrorPatterns = make([]Pattern, 0, top)
+	for _, group := range order {
+		if len(summary.Patterns) < top {
+			summary.Patterns = append(summary.Patterns, *group)
+		}
+		if levelRank(group.Level) >= levelRank("warn") && len(summary.ErrorPatterns) < top {
+			summary.ErrorPatterns = append(summary.ErrorPatterns, *group)
+		}
+	}
+
+	return summary
+}
+
+// levelRank orders levels by severity
+func levelRank(level string) int {
+	switch level {
+	case "error":
+		return 4
+	case "warn":
+		return 3
+	case "info":
+		return 2
+	case "debug", "trace":
+		return 1
+	default:
+		return 0
+	}
+}
+
+// firstLine returns the first line of a message, shortened for examples
+func firstLine(message string) string {
+	line := strings.TrimSpace(message)
+	if i := strings.IndexByte(line, '\n'); i >= 0 {
+		line = line[:i]
+	}
+	if len(line) > maxPatternLength*2 {
+		line = line[:maxPatternLength*2] + "…"
+	}
+	return line
+}
@@ -0,0 +1,253 @@
+// Package management serves the gRPC API through which platform teams manage
+// fleets of MCP servers from their own control plane.
+package management
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative managementpb/management.proto
+
+import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"aws-mcp-server/internal/config"
+	"aws-mcp-server/internal/logging"
+	"aws-mcp-server/pkg/elevation"
+	"aws-mcp-server/pkg/management/managementpb"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Session is an MCP client connected to the server
+type Session struct {
+	ID            string
+	Transport     string
+	RemoteAddress string
+	ClientName    string
+	ClientVersion string
+	ConnectedAt   time.Time
+	LastActiveAt  time.Time
+	Requests      int64
+}
+
+// Controller carries out management operations on the MCP server. The MCP
+// server implements it, so operations go through the same registries, caches
+// and audit log as tool calls.
+type Controller interface {
+	// Sessions returns the connected clients
+	Sessions() []Session
+	// Tokens returns the elevation grants in effect
+	Tokens() []elevation.Grant
+	// RevokeToken ends an elevation grant before it expires
+	RevokeToken(id, reason string) (elevation.Grant, error)
+	// FlushCaches flushes the named caches, all of them when none are named,
+	// and returns the names of the flushed caches
+	FlushCaches(caches []string) ([]string, error)
+	// Overrides returns the settings overridden at runtime
+	Overrides() map[string]string
+	// SetOverrides sets and clears overrides, all or none of them
+	SetOverrides(set map[string]string, clear []string) error
+}
+
+// Server serves the management API for a controller
+type Server struct {
+	managementpb.UnimplementedManagementServer
+
+	config     config.ManagementConfig
+	controller Controller
+	logger     *logging.Logger
+}
+
+// New creates the management API server, nil when it is disabled
+func New(cfg config.ManagementConfig, controller Controller, logger *logging.Logger) *Server {
+	if !cfg.Enabled {
+		return nil
+	}
+	return &Server{config: cfg, controller: controller, logger: logger}
+}
+
+// Validate rejects configurations that would expose the API: calls must
+// carry a token, and it is only sent in clear text to loopback addresses
+func Validate(cfg config.ManagementConfig) error {
+	if cfg.Address == "" {
+		return fmt.Errorf("management.address is required")
+	}
+	if cfg.Token == "" {
+		return fmt.Errorf("management.token is required, the API can revoke elevations and change settings")
+	}
+	if (cfg.TLSCert == "") != (cfg.TLSKey == "") {
+		return fmt.Errorf("management.tls_cert and management.tls_key must be set together")
+	}
+	if cfg.TLSCert == "" && !Loopback(cfg.Address) {
+		return fmt.Errorf("management.address %s is not a loopback address, set management.tls_cert and management.tls_key to serve it", cfg.Address)
+	}
+	return nil
+}
+
+// Loopback reports whether address only accepts connections from this host
+func Loopback(address string) bool {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return false
+	}
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// ListenAndServe serves the API on management.address until ctx is cancelled
+func (s *Server) ListenAndServe(ctx context.Context) error {
+	if err := Validate(s.config); err != nil {
+		return err
+	}
+	listener, err := net.Listen("tcp", s.config.Address)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", s.config.Address, err)
+	}
+	return s.Serve(ctx, listener)
+}
+
+// Serve serves the API on listener until ctx is cancelled, then lets running
+// calls finish
+func (s *Server) Serve(ctx context.Context, listener net.Listener) error {
+	options := []grpc.ServerOption{grpc.UnaryInterceptor(s.authenticate)}
+	if s.config.TLSCert != "" && s.config.TLSKey != "" {
+		creds, err := credentials.NewServerTLSFromFile(s.config.TLSCert, s.config.TLSKey)
+		if err != nil {
+			listener.Close()
+			return fmt.Errorf("failed to load the management TLS certificate: %w", err)
+		}
+		options = append(options, grpc.Creds(creds))
+	}
+
+	server := grpc.NewServer(options...)
+	managementpb.RegisterManagementServer(server, s)
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- server.Serve(listener)
+	}()
+
+	s.logger.WithField("address", listener.Addr().String()).Info("Management API listening")
+
+	select {
+	case err := <-errCh:
+		return fmt.Errorf("management API stopped: %w", err)
+	case <-ctx.Done():
+	}
+
+	server.GracefulStop()
+	return nil
+}
+
+// authenticate rejects calls without management.token as their bearer token
+func (s *Server) authenticate(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	var token string
+	if values := md.Get("authorization"); len(values) > 0 {
+		token, _ = strings.CutPrefix(values[0], "Bearer ")
+	}
+	// Without a configured token no call is let through
+	if s.config.Token == "" || subtle.ConstantTimeCompare([]byte(token), []byte(s.config.Token)) != 1 {
+		s.logger.WithField("method", info.FullMethod).Warn("Rejected a management call without a valid token")
+		return nil, status.Error(codes.Unauthenticated, "a valid bearer token is required")
+	}
+	return handler(ctx, req)
+}
+
+// ListSessions returns the MCP clients connected to the server
+func (s *Server) ListSessions(ctx context.Context, req *managementpb.ListSessionsRequest) (*managementpb.ListSessionsResponse, error) {
+	sessions := s.controller.Sessions()
+	response := &managementpb.ListSessionsResponse{Sessions: make([]*managementpb.Session, 0, len(sessions))}
+	for _, session := range sessions {
+		response.Sessions = append(response.Sessions, &managementpb.Session{
+			Id:            session.ID,
+			Transport:     session.Transport,
+			RemoteAddress: session.RemoteAddress,
+			ClientName:    session.ClientName,
+			ClientVersion: session.ClientVersion,
+			ConnectedAt:   timestamppb.New(session.ConnectedAt),
+			LastActiveAt:  timestamppb.New(session.LastActiveAt),
+			Requests:      session.Requests,
+		})
+	}
+	return response, nil
+}
+
+// ListTokens returns the elevation grants in effect
+func (s *Server) ListTokens(ctx context.Context, req *managementpb.ListTokensRequest) (*managementpb.ListTokensResponse, error) {
+	grants := s.controller.Tokens()
+	response := &managementpb.ListTokensResponse{Tokens: make([]*managementpb.Token, 0, len(grants))}
+	for _, grant := range grants {
+		response.Tokens = append(response.Tokens, tokenOf(grant))
+	}
+	return response, nil
+}
+
+// RevokeToken ends an elevation grant before it expires
+func (s *Server) RevokeToken(ctx context.Context, req *managementpb.RevokeTokenRequest) (*managementpb.RevokeTokenResponse, error) {
+	if req.GetId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "id is required")
+	}
+	if strings.TrimSpace(req.GetReason()) == "" {
+		return nil, status.Error(codes.InvalidArgument, "reason is required, it is recorded in the audit log")
+	}
+
+	grant, err := s.controller.RevokeToken(req.GetId(), req.GetReason())
+	if err != nil {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+	return &managementpb.RevokeTokenResponse{Token: tokenOf(grant)}, nil
+}
+
+// FlushCaches drops cached answers so the next reads go to their sources
+func (s *Server) FlushCaches(ctx context.Context, req *managementpb.FlushCachesRequest) (*managementpb.FlushCachesResponse, error) {
+	flushed, err := s.controller.FlushCaches(req.GetCaches())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return &managementpb.FlushCachesResponse{Flushed: flushed}, nil
+}
+
+// GetConfigOverrides returns the settings overridden at runtime
+func (s *Server) GetConfigOverrides(ctx context.Context, req *managementpb.GetConfigOverridesRequest) (*managementpb.ConfigOverrides, error) {
+	return s.overrides(), nil
+}
+
+// SetConfigOverrides changes settings at runtime
+func (s *Server) SetConfigOverrides(ctx context.Context, req *managementpb.SetConfigOverridesRequest) (*managementpb.ConfigOverrides, error) {
+	if err := s.controller.SetOverrides(req.GetSet(), req.GetClear()); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return s.overrides(), nil
+}
+
+// overrides returns the overridden settings along with the ones that can be
+func (s *Server) overrides() *managementpb.ConfigOverrides {
+	return &managementpb.ConfigOverrides{
+		Overrides:       s.controller.Overrides(),
+		OverridableKeys: config.OverridableKeys(),
+	}
+}
+
+// tokenOf converts an elevation grant for responses
+func tokenOf(grant elevation.Grant) *managementpb.Token {
+	return &managementpb.Token{
+		Id:         grant.ID,
+		Holder:     grant.Holder,
+		Role:       grant.Role,
+		Reason:     grant.Reason,
+		ApprovedBy: grant.ApprovedBy,
+		GrantedAt:  timestamppb.New(grant.GrantedAt),
+		ExpiresAt:  timestamppb.New(grant.ExpiresAt),
+	}
+}
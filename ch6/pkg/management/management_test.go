@@ -0,0 +1,207 @@
+package management
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"aws-mcp-server/internal/config"
+	"aws-mcp-server/internal/logging"
+	"aws-mcp-server/pkg/elevation"
+	"aws-mcp-server/pkg/management/managementpb"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// fakeController records the operations it is asked to carry out
+type fakeController struct {
+	sessions  []Session
+	grants    []elevation.Grant
+	revoked   []string
+	flushed   [][]string
+	overrides *config.Overrides
+}
+
+func (c *fakeController) Sessions() []Session       { return c.sessions }
+func (c *fakeController) Tokens() []elevation.Grant { return c.grants }
+
+func (c *fakeController) RevokeToken(id, reason string) (elevation.Grant, error) {
+	for _, grant := range c.grants {
+		if grant.ID == id {
+			c.revoked = append(c.revoked, id+": "+reason)
+			return grant, nil
+		}
+	}
+	return elevation.Grant{}, errors.New("elevation " + id + " is not in effect")
+}
+
+func (c *fakeController) FlushCaches(caches []string) ([]string, error) {
+	for _, name := range caches {
+		if name != "inventory" && name != "summaries" {
+			return nil, errors.New("unknown cache " + name)
+		}
+	}
+	c.flushed = append(c.flushed, caches)
+	return caches, nil
+}
+
+func (c *fakeController) Overrides() map[string]string { return c.overrides.All() }
+
+func (c *fakeController) SetOverrides(set map[string]string, clear []string) error {
+	return c.overrides.Apply(set, clear)
+}
+
+// testToken is the bearer token of the servers started by startServer
+const testToken = "s3cret"
+
+// withToken authenticates calls made with the returned context
+func withToken(ctx context.Context) context.Context {
+	return metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+testToken)
+}
+
+// startServer serves the API for controller in memory and returns a client
+func startServer(t *testing.T, cfg config.ManagementConfig, controller Controller) managementpb.ManagementClient {
+	t.Helper()
+	cfg.Enabled = true
+	server := New(cfg, controller, logging.NewLogger("error", "text"))
+	listener := bufconn.Listen(1 << 20)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- server.Serve(ctx, listener) }()
+	t.Cleanup(func() {
+		cancel()
+		assert.NoError(t, <-done)
+	})
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return managementpb.NewManagementClient(conn)
+}
+
+func TestNewIsNilWhenDisabled(t *testing.T) {
+	assert.Nil(t, New(config.ManagementConfig{}, &fakeController{}, logging.NewLogger("error", "text")))
+}
+
+func TestValidate(t *testing.T) {
+	assert.NoError(t, Validate(config.ManagementConfig{Address: "127.0.0.1:9090", Token: testToken}))
+	assert.NoError(t, Validate(config.ManagementConfig{Address: "localhost:9090", Token: testToken}))
+	assert.NoError(t, Validate(config.ManagementConfig{Address: "[::1]:9090", Token: testToken}))
+	assert.NoError(t, Validate(config.ManagementConfig{Address: "0.0.0.0:9090", Token: testToken, TLSCert: "cert.pem", TLSKey: "key.pem"}))
+
+	assert.ErrorContains(t, Validate(config.ManagementConfig{Address: "127.0.0.1:9090"}), "management.token is required")
+	assert.ErrorContains(t, Validate(config.ManagementConfig{Address: "0.0.0.0:9090", Token: testToken}), "not a loopback address")
+	assert.ErrorContains(t, Validate(config.ManagementConfig{Address: ":9090", Token: testToken}), "not a loopback address")
+	assert.ErrorContains(t, Validate(config.ManagementConfig{Address: "127.0.0.1:9090", Token: testToken, TLSCert: "cert.pem"}), "must be set together")
+}
+
+func TestCallsWithoutAConfiguredTokenAreRejected(t *testing.T) {
+	client := startServer(t, config.ManagementConfig{}, &fakeController{})
+
+	_, err := client.ListSessions(context.Background(), &managementpb.ListSessionsRequest{})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	_, err = client.ListSessions(metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "), &managementpb.ListSessionsRequest{})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err), "an empty token matches no configured one")
+}
+
+func TestCallsNeedTheToken(t *testing.T) {
+	client := startServer(t, config.ManagementConfig{Token: testToken}, &fakeController{})
+
+	_, err := client.ListSessions(context.Background(), &managementpb.ListSessionsRequest{})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer wrong")
+	_, err = client.ListSessions(ctx, &managementpb.ListSessionsRequest{})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	_, err = client.ListSessions(withToken(context.Background()), &managementpb.ListSessionsRequest{})
+	assert.NoError(t, err)
+}
+
+func TestSessionsAndTokens(t *testing.T) {
+	connected := time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC)
+	controller := &fakeController{
+		sessions: []Session{{
+			ID: "websocket-1", Transport: "websocket", RemoteAddress: "10.0.0.7:51234",
+			ClientName: "claude-desktop", ClientVersion: "1.2.0",
+			ConnectedAt: connected, LastActiveAt: connected.Add(time.Minute), Requests: 12,
+		}},
+		grants: []elevation.Grant{{
+			ID: "elev-1", Holder: "websocket-1", Role: "viewer", Reason: "restart stuck workers", ApprovedBy: "admin",
+			GrantedAt: connected, ExpiresAt: connected.Add(30 * time.Minute),
+		}},
+	}
+	client := startServer(t, config.ManagementConfig{Token: testToken}, controller)
+	ctx := withToken(context.Background())
+
+	sessions, err := client.ListSessions(ctx, &managementpb.ListSessionsRequest{})
+	require.NoError(t, err)
+	require.Len(t, sessions.Sessions, 1)
+	session := sessions.Sessions[0]
+	assert.Equal(t, "websocket-1", session.Id)
+	assert.Equal(t, "claude-desktop", session.ClientName)
+	assert.Equal(t, int64(12), session.Requests)
+	assert.Equal(t, connected, session.ConnectedAt.AsTime())
+
+	tokens, err := client.ListTokens(ctx, &managementpb.ListTokensRequest{})
+	require.NoError(t, err)
+	require.Len(t, tokens.Tokens, 1)
+	assert.Equal(t, "viewer", tokens.Tokens[0].Role)
+	assert.Equal(t, "websocket-1", tokens.Tokens[0].Holder)
+	assert.Equal(t, connected.Add(30*time.Minute), tokens.Tokens[0].ExpiresAt.AsTime())
+
+	_, err = client.RevokeToken(ctx, &managementpb.RevokeTokenRequest{Id: "elev-1"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err), "revocations need a reason")
+	revoked, err := client.RevokeToken(ctx, &managementpb.RevokeTokenRequest{Id: "elev-1", Reason: "incident closed"})
+	require.NoError(t, err)
+	assert.Equal(t, "elev-1", revoked.Token.Id)
+	assert.Equal(t, "websocket-1", revoked.Token.Holder)
+	assert.Equal(t, []string{"elev-1: incident closed"}, controller.revoked)
+	_, err = client.RevokeToken(ctx, &managementpb.RevokeTokenRequest{Id: "elev-9", Reason: "incident closed"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestFlushCachesAndOverrides(t *testing.T) {
+	controller := &fakeController{overrides: config.NewOverrides()}
+	client := startServer(t, config.ManagementConfig{Token: testToken}, controller)
+	ctx := withToken(context.Background())
+
+	flushed, err := client.FlushCaches(ctx, &managementpb.FlushCachesRequest{Caches: []string{"inventory"}})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"inventory"}, flushed.Flushed)
+	_, err = client.FlushCaches(ctx, &managementpb.FlushCachesRequest{Caches: []string{"dns"}})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	overrides, err := client.SetConfigOverrides(ctx, &managementpb.SetConfigOverridesRequest{
+		Set: map[string]string{"response.page_size": "50", "approvals.elicit": "false"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"response.page_size": "50", "approvals.elicit": "false"}, overrides.Overrides)
+	assert.Contains(t, overrides.OverridableKeys, "baselines.threshold")
+
+	_, err = client.SetConfigOverrides(ctx, &managementpb.SetConfigOverridesRequest{
+		Set:   map[string]string{"baselines.threshold": "4"},
+		Clear: []string{"aws.region"},
+	})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	overrides, err = client.SetConfigOverrides(ctx, &managementpb.SetConfigOverridesRequest{Clear: []string{"approvals.elicit"}})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"response.page_size": "50"}, overrides.Overrides, "failed changes apply none of their overrides")
+
+	got, err := client.GetConfigOverrides(ctx, &managementpb.GetConfigOverridesRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, overrides.Overrides, got.Overrides)
+}
@@ -0,0 +1,860 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        (unknown)
+// source: managementpb/management.proto
+
+package managementpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Session is an MCP client connected over a transport
+type Session struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// transport is stdio, websocket, streamable-http or sse
+	Transport     string `protobuf:"bytes,2,opt,name=transport,proto3" json:"transport,omitempty"`
+	RemoteAddress string `protobuf:"bytes,3,opt,name=remote_address,json=remoteAddress,proto3" json:"remote_address,omitempty"`
+	// client_name and client_version are reported by the client when it
+	// initializes the session
+	ClientName    string                 `protobuf:"bytes,4,opt,name=client_name,json=clientName,proto3" json:"client_name,omitempty"`
+	ClientVersion string                 `protobuf:"bytes,5,opt,name=client_version,json=clientVersion,proto3" json:"client_version,omitempty"`
+	ConnectedAt   *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=connected_at,json=connectedAt,proto3" json:"connected_at,omitempty"`
+	LastActiveAt  *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=last_active_at,json=lastActiveAt,proto3" json:"last_active_at,omitempty"`
+	Requests      int64                  `protobuf:"varint,8,opt,name=requests,proto3" json:"requests,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Session) Reset() {
+	*x = Session{}
+	mi := &file_managementpb_management_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Session) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Session) ProtoMessage() {}
+
+func (x *Session) ProtoReflect() protoreflect.Message {
+	mi := &file_managementpb_management_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Session.ProtoReflect.Descriptor instead.
+func (*Session) Descriptor() ([]byte, []int) {
+	return file_managementpb_management_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Session) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Session) GetTransport() string {
+	if x != nil {
+		return x.Transport
+	}
+	return ""
+}
+
+func (x *Session) GetRemoteAddress() string {
+	if x != nil {
+		return x.RemoteAddress
+	}
+	return ""
+}
+
+func (x *Session) GetClientName() string {
+	if x != nil {
+		return x.ClientName
+	}
+	return ""
+}
+
+func (x *Session) GetClientVersion() string {
+	if x != nil {
+		return x.ClientVersion
+	}
+	return ""
+}
+
+func (x *Session) GetConnectedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ConnectedAt
+	}
+	return nil
+}
+
+func (x *Session) GetLastActiveAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastActiveAt
+	}
+	return nil
+}
+
+func (x *Session) GetRequests() int64 {
+	if x != nil {
+		return x.Requests
+	}
+	return 0
+}
+
+type ListSessionsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListSessionsRequest) Reset() {
+	*x = ListSessionsRequest{}
+	mi := &file_managementpb_management_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListSessionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSessionsRequest) ProtoMessage() {}
+
+func (x *ListSessionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_managementpb_management_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSessionsRequest.ProtoReflect.Descriptor instead.
+func (*ListSessionsRequest) Descriptor() ([]byte, []int) {
+	return file_managementpb_management_proto_rawDescGZIP(), []int{1}
+}
+
+type ListSessionsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Sessions      []*Session             `protobuf:"bytes,1,rep,name=sessions,proto3" json:"sessions,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListSessionsResponse) Reset() {
+	*x = ListSessionsResponse{}
+	mi := &file_managementpb_management_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListSessionsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSessionsResponse) ProtoMessage() {}
+
+func (x *ListSessionsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_managementpb_management_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSessionsResponse.ProtoReflect.Descriptor instead.
+func (*ListSessionsResponse) Descriptor() ([]byte, []int) {
+	return file_managementpb_management_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ListSessionsResponse) GetSessions() []*Session {
+	if x != nil {
+		return x.Sessions
+	}
+	return nil
+}
+
+// Token is an elevation grant giving the holder, a session or chat user with
+// a read-only role, write access until it expires
+type Token struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	Id         string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Role       string                 `protobuf:"bytes,2,opt,name=role,proto3" json:"role,omitempty"`
+	Reason     string                 `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
+	ApprovedBy string                 `protobuf:"bytes,4,opt,name=approved_by,json=approvedBy,proto3" json:"approved_by,omitempty"`
+	GrantedAt  *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=granted_at,json=grantedAt,proto3" json:"granted_at,omitempty"`
+	ExpiresAt  *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	// holder is the MCP session or chat user the grant elevates
+	Holder        string `protobuf:"bytes,7,opt,name=holder,proto3" json:"holder,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Token) Reset() {
+	*x = Token{}
+	mi := &file_managementpb_management_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Token) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Token) ProtoMessage() {}
+
+func (x *Token) ProtoReflect() protoreflect.Message {
+	mi := &file_managementpb_management_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Token.ProtoReflect.Descriptor instead.
+func (*Token) Descriptor() ([]byte, []int) {
+	return file_managementpb_management_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Token) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Token) GetRole() string {
+	if x != nil {
+		return x.Role
+	}
+	return ""
+}
+
+func (x *Token) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *Token) GetApprovedBy() string {
+	if x != nil {
+		return x.ApprovedBy
+	}
+	return ""
+}
+
+func (x *Token) GetGrantedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.GrantedAt
+	}
+	return nil
+}
+
+func (x *Token) GetExpiresAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return nil
+}
+
+func (x *Token) GetHolder() string {
+	if x != nil {
+		return x.Holder
+	}
+	return ""
+}
+
+type ListTokensRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTokensRequest) Reset() {
+	*x = ListTokensRequest{}
+	mi := &file_managementpb_management_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTokensRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTokensRequest) ProtoMessage() {}
+
+func (x *ListTokensRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_managementpb_management_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTokensRequest.ProtoReflect.Descriptor instead.
+func (*ListTokensRequest) Descriptor() ([]byte, []int) {
+	return file_managementpb_management_proto_rawDescGZIP(), []int{4}
+}
+
+type ListTokensResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Tokens        []*Token               `protobuf:"bytes,1,rep,name=tokens,proto3" json:"tokens,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTokensResponse) Reset() {
+	*x = ListTokensResponse{}
+	mi := &file_managementpb_management_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTokensResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTokensResponse) ProtoMessage() {}
+
+func (x *ListTokensResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_managementpb_management_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTokensResponse.ProtoReflect.Descriptor instead.
+func (*ListTokensResponse) Descriptor() ([]byte, []int) {
+	return file_managementpb_management_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ListTokensResponse) GetTokens() []*Token {
+	if x != nil {
+		return x.Tokens
+	}
+	return nil
+}
+
+type RevokeTokenRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// reason is recorded in the audit log
+	Reason        string `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RevokeTokenRequest) Reset() {
+	*x = RevokeTokenRequest{}
+	mi := &file_managementpb_management_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RevokeTokenRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevokeTokenRequest) ProtoMessage() {}
+
+func (x *RevokeTokenRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_managementpb_management_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevokeTokenRequest.ProtoReflect.Descriptor instead.
+func (*RevokeTokenRequest) Descriptor() ([]byte, []int) {
+	return file_managementpb_management_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *RevokeTokenRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *RevokeTokenRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type RevokeTokenResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Token         *Token                 `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RevokeTokenResponse) Reset() {
+	*x = RevokeTokenResponse{}
+	mi := &file_managementpb_management_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RevokeTokenResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevokeTokenResponse) ProtoMessage() {}
+
+func (x *RevokeTokenResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_managementpb_management_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevokeTokenResponse.ProtoReflect.Descriptor instead.
+func (*RevokeTokenResponse) Descriptor() ([]byte, []int) {
+	return file_managementpb_management_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *RevokeTokenResponse) GetToken() *Token {
+	if x != nil {
+		return x.Token
+	}
+	return nil
+}
+
+type FlushCachesRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// caches names the caches to flush, all of them when empty
+	Caches        []string `protobuf:"bytes,1,rep,name=caches,proto3" json:"caches,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FlushCachesRequest) Reset() {
+	*x = FlushCachesRequest{}
+	mi := &file_managementpb_management_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FlushCachesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FlushCachesRequest) ProtoMessage() {}
+
+func (x *FlushCachesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_managementpb_management_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FlushCachesRequest.ProtoReflect.Descriptor instead.
+func (*FlushCachesRequest) Descriptor() ([]byte, []int) {
+	return file_managementpb_management_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *FlushCachesRequest) GetCaches() []string {
+	if x != nil {
+		return x.Caches
+	}
+	return nil
+}
+
+type FlushCachesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Flushed       []string               `protobuf:"bytes,1,rep,name=flushed,proto3" json:"flushed,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FlushCachesResponse) Reset() {
+	*x = FlushCachesResponse{}
+	mi := &file_managementpb_management_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FlushCachesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FlushCachesResponse) ProtoMessage() {}
+
+func (x *FlushCachesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_managementpb_management_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FlushCachesResponse.ProtoReflect.Descriptor instead.
+func (*FlushCachesResponse) Descriptor() ([]byte, []int) {
+	return file_managementpb_management_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *FlushCachesResponse) GetFlushed() []string {
+	if x != nil {
+		return x.Flushed
+	}
+	return nil
+}
+
+type GetConfigOverridesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetConfigOverridesRequest) Reset() {
+	*x = GetConfigOverridesRequest{}
+	mi := &file_managementpb_management_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetConfigOverridesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetConfigOverridesRequest) ProtoMessage() {}
+
+func (x *GetConfigOverridesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_managementpb_management_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetConfigOverridesRequest.ProtoReflect.Descriptor instead.
+func (*GetConfigOverridesRequest) Descriptor() ([]byte, []int) {
+	return file_managementpb_management_proto_rawDescGZIP(), []int{10}
+}
+
+type SetConfigOverridesRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// set overrides settings by their configuration key, e.g.
+	// response.page_size
+	Set map[string]string `protobuf:"bytes,1,rep,name=set,proto3" json:"set,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// clear reverts settings to their configured values
+	Clear         []string `protobuf:"bytes,2,rep,name=clear,proto3" json:"clear,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetConfigOverridesRequest) Reset() {
+	*x = SetConfigOverridesRequest{}
+	mi := &file_managementpb_management_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetConfigOverridesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetConfigOverridesRequest) ProtoMessage() {}
+
+func (x *SetConfigOverridesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_managementpb_management_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetConfigOverridesRequest.ProtoReflect.Descriptor instead.
+func (*SetConfigOverridesRequest) Descriptor() ([]byte, []int) {
+	return file_managementpb_management_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *SetConfigOverridesRequest) GetSet() map[string]string {
+	if x != nil {
+		return x.Set
+	}
+	return nil
+}
+
+func (x *SetConfigOverridesRequest) GetClear() []string {
+	if x != nil {
+		return x.Clear
+	}
+	return nil
+}
+
+type ConfigOverrides struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Overrides map[string]string      `protobuf:"bytes,1,rep,name=overrides,proto3" json:"overrides,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// overridable_keys are the settings that can be overridden
+	OverridableKeys []string `protobuf:"bytes,2,rep,name=overridable_keys,json=overridableKeys,proto3" json:"overridable_keys,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *ConfigOverrides) Reset() {
+	*x = ConfigOverrides{}
+	mi := &file_managementpb_management_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ConfigOverrides) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConfigOverrides) ProtoMessage() {}
+
+func (x *ConfigOverrides) ProtoReflect() protoreflect.Message {
+	mi := &file_managementpb_management_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConfigOverrides.ProtoReflect.Descriptor instead.
+func (*ConfigOverrides) Descriptor() ([]byte, []int) {
+	return file_managementpb_management_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *ConfigOverrides) GetOverrides() map[string]string {
+	if x != nil {
+		return x.Overrides
+	}
+	return nil
+}
+
+func (x *ConfigOverrides) GetOverridableKeys() []string {
+	if x != nil {
+		return x.OverridableKeys
+	}
+	return nil
+}
+
+var File_managementpb_management_proto protoreflect.FileDescriptor
+
+const file_managementpb_management_proto_rawDesc = "" +
+	"\n" +
+	"\x1dmanagementpb/management.proto\x12\x13aiops.management.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"\xc3\x02\n" +
+	"\aSession\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1c\n" +
+	"\ttransport\x18\x02 \x01(\tR\ttransport\x12%\n" +
+	"\x0eremote_address\x18\x03 \x01(\tR\rremoteAddress\x12\x1f\n" +
+	"\vclient_name\x18\x04 \x01(\tR\n" +
+	"clientName\x12%\n" +
+	"\x0eclient_version\x18\x05 \x01(\tR\rclientVersion\x12=\n" +
+	"\fconnected_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\vconnectedAt\x12@\n" +
+	"\x0elast_active_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\flastActiveAt\x12\x1a\n" +
+	"\brequests\x18\b \x01(\x03R\brequests\"\x15\n" +
+	"\x13ListSessionsRequest\"P\n" +
+	"\x14ListSessionsResponse\x128\n" +
+	"\bsessions\x18\x01 \x03(\v2\x1c.aiops.management.v1.SessionR\bsessions\"\xf2\x01\n" +
+	"\x05Token\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04role\x18\x02 \x01(\tR\x04role\x12\x16\n" +
+	"\x06reason\x18\x03 \x01(\tR\x06reason\x12\x1f\n" +
+	"\vapproved_by\x18\x04 \x01(\tR\n" +
+	"approvedBy\x129\n" +
+	"\n" +
+	"granted_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\tgrantedAt\x129\n" +
+	"\n" +
+	"expires_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\texpiresAt\x12\x16\n" +
+	"\x06holder\x18\a \x01(\tR\x06holder\"\x13\n" +
+	"\x11ListTokensRequest\"H\n" +
+	"\x12ListTokensResponse\x122\n" +
+	"\x06tokens\x18\x01 \x03(\v2\x1a.aiops.management.v1.TokenR\x06tokens\"<\n" +
+	"\x12RevokeTokenRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x16\n" +
+	"\x06reason\x18\x02 \x01(\tR\x06reason\"G\n" +
+	"\x13RevokeTokenResponse\x120\n" +
+	"\x05token\x18\x01 \x01(\v2\x1a.aiops.management.v1.TokenR\x05token\",\n" +
+	"\x12FlushCachesRequest\x12\x16\n" +
+	"\x06caches\x18\x01 \x03(\tR\x06caches\"/\n" +
+	"\x13FlushCachesResponse\x12\x18\n" +
+	"\aflushed\x18\x01 \x03(\tR\aflushed\"\x1b\n" +
+	"\x19GetConfigOverridesRequest\"\xb4\x01\n" +
+	"\x19SetConfigOverridesRequest\x12I\n" +
+	"\x03set\x18\x01 \x03(\v27.aiops.management.v1.SetConfigOverridesRequest.SetEntryR\x03set\x12\x14\n" +
+	"\x05clear\x18\x02 \x03(\tR\x05clear\x1a6\n" +
+	"\bSetEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xcd\x01\n" +
+	"\x0fConfigOverrides\x12Q\n" +
+	"\toverrides\x18\x01 \x03(\v23.aiops.management.v1.ConfigOverrides.OverridesEntryR\toverrides\x12)\n" +
+	"\x10overridable_keys\x18\x02 \x03(\tR\x0foverridableKeys\x1a<\n" +
+	"\x0eOverridesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x012\xec\x04\n" +
+	"\n" +
+	"Management\x12c\n" +
+	"\fListSessions\x12(.aiops.management.v1.ListSessionsRequest\x1a).aiops.management.v1.ListSessionsResponse\x12]\n" +
+	"\n" +
+	"ListTokens\x12&.aiops.management.v1.ListTokensRequest\x1a'.aiops.management.v1.ListTokensResponse\x12`\n" +
+	"\vRevokeToken\x12'.aiops.management.v1.RevokeTokenRequest\x1a(.aiops.management.v1.RevokeTokenResponse\x12`\n" +
+	"\vFlushCaches\x12'.aiops.management.v1.FlushCachesRequest\x1a(.aiops.management.v1.FlushCachesResponse\x12j\n" +
+	"\x12GetConfigOverrides\x12..aiops.management.v1.GetConfigOverridesRequest\x1a$.aiops.management.v1.ConfigOverrides\x12j\n" +
+	"\x12SetConfigOverrides\x12..aiops.management.v1.SetConfigOverridesRequest\x1a$.aiops.management.v1.ConfigOverridesB,Z*aws-mcp-server/pkg/management/managementpbb\x06proto3"
+
+var (
+	file_managementpb_management_proto_rawDescOnce sync.Once
+	file_managementpb_management_proto_rawDescData []byte
+)
+
+func file_managementpb_management_proto_rawDescGZIP() []byte {
+	file_managementpb_management_proto_rawDescOnce.Do(func() {
+		file_managementpb_management_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_managementpb_management_proto_rawDesc), len(file_managementpb_management_proto_rawDesc)))
+	})
+	return file_managementpb_management_proto_rawDescData
+}
+
+var file_managementpb_management_proto_msgTypes = make([]protoimpl.MessageInfo, 15)
+var file_managementpb_management_proto_goTypes = []any{
+	(*Session)(nil),                   // 0: aiops.management.v1.Session
+	(*ListSessionsRequest)(nil),       // 1: aiops.management.v1.ListSessionsRequest
+	(*ListSessionsResponse)(nil),      // 2: aiops.management.v1.ListSessionsResponse
+	(*Token)(nil),                     // 3: aiops.management.v1.Token
+	(*ListTokensRequest)(nil),         // 4: aiops.management.v1.ListTokensRequest
+	(*ListTokensResponse)(nil),        // 5: aiops.management.v1.ListTokensResponse
+	(*RevokeTokenRequest)(nil),        // 6: aiops.management.v1.RevokeTokenRequest
+	(*RevokeTokenResponse)(nil),       // 7: aiops.management.v1.RevokeTokenResponse
+	(*FlushCachesRequest)(nil),        // 8: aiops.management.v1.FlushCachesRequest
+	(*FlushCachesResponse)(nil),       // 9: aiops.management.v1.FlushCachesResponse
+	(*GetConfigOverridesRequest)(nil), // 10: aiops.management.v1.GetConfigOverridesRequest
+	(*SetConfigOverridesRequest)(nil), // 11: aiops.management.v1.SetConfigOverridesRequest
+	(*ConfigOverrides)(nil),           // 12: aiops.management.v1.ConfigOverrides
+	nil,                               // 13: aiops.management.v1.SetConfigOverridesRequest.SetEntry
+	nil,                               // 14: aiops.management.v1.ConfigOverrides.OverridesEntry
+	(*timestamppb.Timestamp)(nil),     // 15: google.protobuf.Timestamp
+}
+var file_managementpb_management_proto_depIdxs = []int32{
+	15, // 0: aiops.management.v1.Session.connected_at:type_name -> google.protobuf.Timestamp
+	15, // 1: aiops.management.v1.Session.last_active_at:type_name -> google.protobuf.Timestamp
+	0,  // 2: aiops.management.v1.ListSessionsResponse.sessions:type_name -> aiops.management.v1.Session
+	15, // 3: aiops.management.v1.Token.granted_at:type_name -> google.protobuf.Timestamp
+	15, // 4: aiops.management.v1.Token.expires_at:type_name -> google.protobuf.Timestamp
+	3,  // 5: aiops.management.v1.ListTokensResponse.tokens:type_name -> aiops.management.v1.Token
+	3,  // 6: aiops.management.v1.RevokeTokenResponse.token:type_name -> aiops.management.v1.Token
+	13, // 7: aiops.management.v1.SetConfigOverridesRequest.set:type_name -> aiops.management.v1.SetConfigOverridesRequest.SetEntry
+	14, // 8: aiops.management.v1.ConfigOverrides.overrides:type_name -> aiops.management.v1.ConfigOverrides.OverridesEntry
+	1,  // 9: aiops.management.v1.Management.ListSessions:input_type -> aiops.management.v1.ListSessionsRequest
+	4,  // 10: aiops.management.v1.Management.ListTokens:input_type -> aiops.management.v1.ListTokensRequest
+	6,  // 11: aiops.management.v1.Management.RevokeToken:input_type -> aiops.management.v1.RevokeTokenRequest
+	8,  // 12: aiops.management.v1.Management.FlushCaches:input_type -> aiops.management.v1.FlushCachesRequest
+	10, // 13: aiops.management.v1.Management.GetConfigOverrides:input_type -> aiops.management.v1.GetConfigOverridesRequest
+	11, // 14: aiops.management.v1.Management.SetConfigOverrides:input_type -> aiops.management.v1.SetConfigOverridesRequest
+	2,  // 15: aiops.management.v1.Management.ListSessions:output_type -> aiops.management.v1.ListSessionsResponse
+	5,  // 16: aiops.management.v1.Management.ListTokens:output_type -> aiops.management.v1.ListTokensResponse
+	7,  // 17: aiops.management.v1.Management.RevokeToken:output_type -> aiops.management.v1.RevokeTokenResponse
+	9,  // 18: aiops.management.v1.Management.FlushCaches:output_type -> aiops.management.v1.FlushCachesResponse
+	12, // 19: aiops.management.v1.Management.GetConfigOverrides:output_type -> aiops.management.v1.ConfigOverrides
+	12, // 20: aiops.management.v1.Management.SetConfigOverrides:output_type -> aiops.management.v1.ConfigOverrides
+	15, // [15:21] is the sub-list for method output_type
+	9,  // [9:15] is the sub-list for method input_type
+	9,  // [9:9] is the sub-list for extension type_name
+	9,  // [9:9] is the sub-list for extension extendee
+	0,  // [0:9] is the sub-list for field type_name
+}
+
+func init() { file_managementpb_management_proto_init() }
+func file_managementpb_management_proto_init() {
+	if File_managementpb_management_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_managementpb_management_proto_rawDesc), len(file_managementpb_management_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   15,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_managementpb_management_proto_goTypes,
+		DependencyIndexes: file_managementpb_management_proto_depIdxs,
+		MessageInfos:      file_managementpb_management_proto_msgTypes,
+	}.Build()
+	File_managementpb_management_proto = out.File
+	file_managementpb_management_proto_goTypes = nil
+	file_managementpb_management_proto_depIdxs = nil
+}
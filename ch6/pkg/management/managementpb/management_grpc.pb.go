@@ -0,0 +1,335 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: managementpb/management.proto
+
+package managementpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	Management_ListSessions_FullMethodName       = "/aiops.management.v1.Management/ListSessions"
+	Management_ListTokens_FullMethodName         = "/aiops.management.v1.Management/ListTokens"
+	Management_RevokeToken_FullMethodName        = "/aiops.management.v1.Management/RevokeToken"
+	Management_FlushCaches_FullMethodName        = "/aiops.management.v1.Management/FlushCaches"
+	Management_GetConfigOverrides_FullMethodName = "/aiops.management.v1.Management/GetConfigOverrides"
+	Management_SetConfigOverrides_FullMethodName = "/aiops.management.v1.Management/SetConfigOverrides"
+)
+
+// ManagementClient is the client API for Management service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Management lets platform teams run fleets of MCP servers from their own
+// control plane: see who is connected, take back elevated access, drop
+// cached answers and change settings without a restart.
+type ManagementClient interface {
+	// ListSessions returns the MCP clients connected to the server
+	ListSessions(ctx context.Context, in *ListSessionsRequest, opts ...grpc.CallOption) (*ListSessionsResponse, error)
+	// ListTokens returns the elevation grants in effect
+	ListTokens(ctx context.Context, in *ListTokensRequest, opts ...grpc.CallOption) (*ListTokensResponse, error)
+	// RevokeToken ends an elevation grant before it expires, so its holder
+	// is read-only again
+	RevokeToken(ctx context.Context, in *RevokeTokenRequest, opts ...grpc.CallOption) (*RevokeTokenResponse, error)
+	// FlushCaches drops cached answers so the next reads go to their sources
+	FlushCaches(ctx context.Context, in *FlushCachesRequest, opts ...grpc.CallOption) (*FlushCachesResponse, error)
+	// GetConfigOverrides returns the settings overridden at runtime
+	GetConfigOverrides(ctx context.Context, in *GetConfigOverridesRequest, opts ...grpc.CallOption) (*ConfigOverrides, error)
+	// SetConfigOverrides changes settings at runtime. Overrides last until
+	// they are cleared or the server restarts.
+	SetConfigOverrides(ctx context.Context, in *SetConfigOverridesRequest, opts ...grpc.CallOption) (*ConfigOverrides, error)
+}
+
+type managementClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewManagementClient(cc grpc.ClientConnInterface) ManagementClient {
+	return &managementClient{cc}
+}
+
+func (c *managementClient) ListSessions(ctx context.Context, in *ListSessionsRequest, opts ...grpc.CallOption) (*ListSessionsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListSessionsResponse)
+	err := c.cc.Invoke(ctx, Management_ListSessions_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *managementClient) ListTokens(ctx context.Context, in *ListTokensRequest, opts ...grpc.CallOption) (*ListTokensResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListTokensResponse)
+	err := c.cc.Invoke(ctx, Management_ListTokens_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *managementClient) RevokeToken(ctx context.Context, in *RevokeTokenRequest, opts ...grpc.CallOption) (*RevokeTokenResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RevokeTokenResponse)
+	err := c.cc.Invoke(ctx, Management_RevokeToken_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *managementClient) FlushCaches(ctx context.Context, in *FlushCachesRequest, opts ...grpc.CallOption) (*FlushCachesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(FlushCachesResponse)
+	err := c.cc.Invoke(ctx, Management_FlushCaches_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *managementClient) GetConfigOverrides(ctx context.Context, in *GetConfigOverridesRequest, opts ...grpc.CallOption) (*ConfigOverrides, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ConfigOverrides)
+	err := c.cc.Invoke(ctx, Management_GetConfigOverrides_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *managementClient) SetConfigOverrides(ctx context.Context, in *SetConfigOverridesRequest, opts ...grpc.CallOption) (*ConfigOverrides, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ConfigOverrides)
+	err := c.cc.Invoke(ctx, Management_SetConfigOverrides_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ManagementServer is the server API for Management service.
+// All implementations must embed UnimplementedManagementServer
+// for forward compatibility.
+//
+// Management lets platform teams run fleets of MCP servers from their own
+// control plane: see who is connected, take back elevated access, drop
+// cached answers and change settings without a restart.
+type ManagementServer interface {
+	// ListSessions returns the MCP clients connected to the server
+	ListSessions(context.Context, *ListSessionsRequest) (*ListSessionsResponse, error)
+	// ListTokens returns the elevation grants in effect
+	ListTokens(context.Context, *ListTokensRequest) (*ListTokensResponse, error)
+	// RevokeToken ends an elevation grant before it expires, so its holder
+	// is read-only again
+	RevokeToken(context.Context, *RevokeTokenRequest) (*RevokeTokenResponse, error)
+	// FlushCaches drops cached answers so the next reads go to their sources
+	FlushCaches(context.Context, *FlushCachesRequest) (*FlushCachesResponse, error)
+	// GetConfigOverrides returns the settings overridden at runtime
+	GetConfigOverrides(context.Context, *GetConfigOverridesRequest) (*ConfigOverrides, error)
+	// SetConfigOverrides changes settings at runtime. Overrides last until
+	// they are cleared or the server restarts.
+	SetConfigOverrides(context.Context, *SetConfigOverridesRequest) (*ConfigOverrides, error)
+	mustEmbedUnimplementedManagementServer()
+}
+
+// UnimplementedManagementServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedManagementServer struct{}
+
+func (UnimplementedManagementServer) ListSessions(context.Context, *ListSessionsRequest) (*ListSessionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListSessions not implemented")
+}
+func (UnimplementedManagementServer) ListTokens(context.Context, *ListTokensRequest) (*ListTokensResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListTokens not implemented")
+}
+func (UnimplementedManagementServer) RevokeToken(context.Context, *RevokeTokenRequest) (*RevokeTokenResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RevokeToken not implemented")
+}
+func (UnimplementedManagementServer) FlushCaches(context.Context, *FlushCachesRequest) (*FlushCachesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FlushCaches not implemented")
+}
+func (UnimplementedManagementServer) GetConfigOverrides(context.Context, *GetConfigOverridesRequest) (*ConfigOverrides, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetConfigOverrides not implemented")
+}
+func (UnimplementedManagementServer) SetConfigOverrides(context.Context, *SetConfigOverridesRequest) (*ConfigOverrides, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetConfigOverrides not implemented")
+}
+func (UnimplementedManagementServer) mustEmbedUnimplementedManagementServer() {}
+func (UnimplementedManagementServer) testEmbeddedByValue()                    {}
+
+// UnsafeManagementServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ManagementServer will
+// result in compilation errors.
+type UnsafeManagementServer interface {
+	mustEmbedUnimplementedManagementServer()
+}
+
+func RegisterManagementServer(s grpc.ServiceRegistrar, srv ManagementServer) {
+	// If the following call pancis, it indicates UnimplementedManagementServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&Management_ServiceDesc, srv)
+}
+
+func _Management_ListSessions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListSessionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ManagementServer).ListSessions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Management_ListSessions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ManagementServer).ListSessions(ctx, req.(*ListSessionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Management_ListTokens_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListTokensRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ManagementServer).ListTokens(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Management_ListTokens_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ManagementServer).ListTokens(ctx, req.(*ListTokensRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Management_RevokeToken_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RevokeTokenRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ManagementServer).RevokeToken(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Management_RevokeToken_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ManagementServer).RevokeToken(ctx, req.(*RevokeTokenRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Management_FlushCaches_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FlushCachesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ManagementServer).FlushCaches(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Management_FlushCaches_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ManagementServer).FlushCaches(ctx, req.(*FlushCachesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Management_GetConfigOverrides_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetConfigOverridesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ManagementServer).GetConfigOverrides(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Management_GetConfigOverrides_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ManagementServer).GetConfigOverrides(ctx, req.(*GetConfigOverridesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Management_SetConfigOverrides_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetConfigOverridesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ManagementServer).SetConfigOverrides(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Management_SetConfigOverrides_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ManagementServer).SetConfigOverrides(ctx, req.(*SetConfigOverridesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Management_ServiceDesc is the grpc.ServiceDesc for Management service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Management_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "aiops.management.v1.Management",
+	HandlerType: (*ManagementServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListSessions",
+			Handler:    _Management_ListSessions_Handler,
+		},
+		{
+			MethodName: "ListTokens",
+			Handler:    _Management_ListTokens_Handler,
+		},
+		{
+			MethodName: "RevokeToken",
+			Handler:    _Management_RevokeToken_Handler,
+		},
+		{
+			MethodName: "FlushCaches",
+			Handler:    _Management_FlushCaches_Handler,
+		},
+		{
+			MethodName: "GetConfigOverrides",
+			Handler:    _Management_GetConfigOverrides_Handler,
+		},
+		{
+			MethodName: "SetConfigOverrides",
+			Handler:    _Management_SetConfigOverrides_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "managementpb/management.proto",
+}
@@ -0,0 +1,168 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"aws-mcp-server/pkg/cloud"
+	"aws-mcp-server/pkg/types"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const (
+	// organizationAccountsURI lists the member accounts of the organization
+	organizationAccountsURI = "aws://organization/accounts"
+	// accountInstancesTemplate lists the EC2 instances of another account of
+	// the organization by its alias or ID
+	accountInstancesTemplate = "aws://{account}/ec2/instances"
+)
+
+// organizationAccount is a member account with the alias it is addressed by
+type organizationAccount struct {
+	types.Account
+	Alias string `json:"alias"`
+	// InstancesURI is set for active accounts when organization accounts are
+	// addressable, e.g. aws://prod/ec2/instances
+	InstancesURI string `json:"instancesUri,omitempty"`
+}
+
+// accountTools are the tools that take an account argument to act in another
+// account of the organization
+var accountTools = map[string]bool{
+	"create-ec2-instance":     true,
+	"start-ec2-instance":      true,
+	"stop-ec2-instance":       true,
+	"terminate-ec2-instance":  true,
+	"bulk-stop-ec2-instances": true,
+	"tag-resource":            true,
+	"untag-resource":          true,
+}
+
+// readOrganizationAccounts lists the member accounts of the organization with
+// their tags. Tags take one call per account; when they cannot be read the
+// accounts are listed without them.
+func (h *ResourceHandler) readOrganizationAccounts(ctx context.Context) (*mcp.ReadResourceResult, error) {
+	members, err := h.awsClient.ListAccounts(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var tagErr error
+	for i := range members {
+		tags, err := h.awsClient.ListAccountTags(ctx, members[i].ID)
+		if err != nil {
+			tagErr = err
+			break
+		}
+		members[i].Tags = tags
+	}
+
+	data := formatOrganizationAccounts(members, h.accounts.AliasOf, h.accounts != nil)
+	if tagErr != nil {
+		data["note"] = "Account tags are unavailable: " + tagErr.Error()
+	}
+
+	jsonData, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal organization accounts data: %w", err)
+	}
+
+	return &mcp.ReadResourceResult{
+		Contents: []mcp.ResourceContents{
+			&mcp.TextResourceContents{
+				URI:      organizationAccountsURI,
+				MIMEType: "application/json",
+				Text:     string(jsonData),
+			},
+		},
+	}, nil
+}
+
+// formatOrganizationAccounts lists the accounts by alias, accounts that are
+// not active first, with counts by state. Active accounts link to their
+// instances when accounts are addressable.
+func formatOrganizationAccounts(members []types.Account, aliasOf func(id, name string) string, addressable bool) map[string]interface{} {
+	accounts := make([]organizationAccount, 0, len(members))
+	byState := make(map[string]int)
+	for _, member := range members {
+		account := organizationAccount{Account: member, Alias: aliasOf(member.ID, member.Name)}
+		if addressable && member.State == "ACTIVE" {
+			account.InstancesURI = strings.Replace(accountInstancesTemplate, "{account}", account.Alias, 1)
+		}
+		accounts = append(accounts, account)
+		byState[member.State]++
+	}
+	sort.SliceStable(accounts, func(i, j int) bool {
+		if active := accounts[i].State == "ACTIVE"; active != (accounts[j].State == "ACTIVE") {
+			return !active
+		}
+		return accounts[i].Alias < accounts[j].Alias
+	})
+
+	return map[string]interface{}{
+		"accounts":         accounts,
+		"total":            len(accounts),
+		"summary_by_state": byState,
+	}
+}
+
+// readAccountInstances lists the instances of the account in the URI through
+// its assumed role. Unlike the server's own account the list is always read
+// from AWS, as the inventory only keeps the server's own instances.
+func (h *ResourceHandler) readAccountInstances(ctx context.Context, req resourceRequest) (*mcp.ReadResourceResult, error) {
+	client, account, err := h.accounts.Client(ctx, req.Param("account"))
+	if err != nil {
+		return nil, err
+	}
+
+	size, cursor, err := h.instancePage(req.Query)
+	if err != nil {
+		return nil, err
+	}
+	list, _, err := h.listInstancePage(ctx, cloud.NewAWSProvider(client), size, cursor)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list EC2 instances of account %s: %w", account.Alias, err)
+	}
+	list.Account = account.Alias
+	text, err := encodeInstanceList(list)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal instances data: %w", err)
+	}
+
+	return &mcp.ReadResourceResult{
+		Contents: []mcp.ResourceContents{
+			&mcp.TextResourceContents{
+				URI:      req.URI,
+				MIMEType: "application/json",
+				Text:     text,
+			},
+		},
+	}, nil
+}
+
+// inAccount returns the handler to run a tool call with: h itself, or when
+// the call names an account, a copy acting in that account through its
+// assumed role
+func (h *ToolHandler) inAccount(ctx context.Context, name string, arguments map[string]interface{}) (*ToolHandler, error) {
+	ref, _ := arguments["account"].(string)
+	if ref == "" {
+		return h, nil
+	}
+	if !accountTools[name] {
+		return nil, fmt.Errorf("%s does not take an account", name)
+	}
+
+	client, _, err := h.accounts.Client(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+
+	scoped := *h
+	scoped.awsClient = client
+	scoped.clouds = cloud.NewRegistry(cloud.NewAWSProvider(client))
+	return &scoped, nil
+}
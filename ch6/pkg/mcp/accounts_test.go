@@ -0,0 +1,84 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"aws-mcp-server/internal/config"
+	"aws-mcp-server/internal/logging"
+	"aws-mcp-server/pkg/accounts"
+	"aws-mcp-server/pkg/render"
+	"aws-mcp-server/pkg/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccountArgumentIsValidatedBeforeCallingAWS(t *testing.T) {
+	h := NewToolHandler(&config.Config{}, nil, logging.NewLogger("error", "text"))
+
+	result, err := h.CallTool(context.Background(), "find-orphans", map[string]interface{}{"account": "prod"})
+	require.NoError(t, err)
+	assert.Contains(t, decodeToolResult(t, result)["error"], "find-orphans does not take an account")
+
+	result, err = h.CallTool(context.Background(), "stop-ec2-instance", map[string]interface{}{"instanceId": "i-1", "account": "prod"})
+	require.NoError(t, err)
+	assert.Contains(t, decodeToolResult(t, result)["error"], "no organization accounts are configured")
+}
+
+func TestAccountInstancesRoute(t *testing.T) {
+	h := NewResourceHandler(&config.Config{}, nil)
+
+	_, req, ok := h.routes.Match("aws://prod-eu/ec2/instances")
+	require.True(t, ok)
+	assert.Equal(t, accountInstancesTemplate, req.Template)
+	assert.Equal(t, "prod-eu", req.Param("account"))
+
+	_, err := h.ReadResource(context.Background(), "aws://prod-eu/ec2/instances")
+	assert.ErrorContains(t, err, "no organization accounts are configured")
+
+	renderer, err := render.New(nil)
+	require.NoError(t, err)
+	payload, err := json.Marshal(instanceList{Account: "prod-eu", TotalInstances: 3, SummaryByState: map[string]int{"running": 2, "stopped": 1}})
+	require.NoError(t, err)
+	summary, ok := renderer.RenderJSON(accountInstancesTemplate, payload, nil)
+	require.True(t, ok)
+	assert.Equal(t, "3 EC2 instances in account prod-eu: 2 running 1 stopped", summary)
+}
+
+func TestFormatOrganizationAccounts(t *testing.T) {
+	joined := time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC)
+	members := []types.Account{
+		{ID: "111111111111", Name: "Prod (EU)", State: "ACTIVE", JoinedAt: joined, Tags: map[string]string{"env": "prod"}},
+		{ID: "222222222222", Name: "Old Sandbox", State: "SUSPENDED", JoinedAt: joined},
+		{ID: "333333333333", Name: "Data", State: "ACTIVE", JoinedAt: joined},
+	}
+
+	data := formatOrganizationAccounts(members, (*accounts.Directory)(nil).AliasOf, true)
+	assert.Equal(t, 3, data["total"])
+	assert.Equal(t, map[string]int{"ACTIVE": 2, "SUSPENDED": 1}, data["summary_by_state"])
+
+	listed := data["accounts"].([]organizationAccount)
+	var aliases []string
+	for _, account := range listed {
+		aliases = append(aliases, account.Alias)
+	}
+	assert.Equal(t, []string{"old-sandbox", "data", "prod-eu"}, aliases, "accounts that are not active come first, then by alias")
+	assert.Empty(t, listed[0].InstancesURI, "suspended accounts cannot be addressed")
+	assert.Equal(t, "aws://prod-eu/ec2/instances", listed[2].InstancesURI)
+
+	payload, err := json.Marshal(data)
+	require.NoError(t, err)
+	assert.Contains(t, string(payload), `"tags":{"env":"prod"}`, "account fields are inlined")
+
+	renderer, err := render.New(nil)
+	require.NoError(t, err)
+	summary, ok := renderer.RenderJSON(organizationAccountsURI, payload, nil)
+	require.True(t, ok)
+	assert.Equal(t, "3 organization accounts: 2 ACTIVE 1 SUSPENDED", summary)
+
+	data = formatOrganizationAccounts(members, (*accounts.Directory)(nil).AliasOf, false)
+	assert.Empty(t, data["accounts"].([]organizationAccount)[2].InstancesURI, "without a directory accounts are only listed")
+}
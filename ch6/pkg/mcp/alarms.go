@@ -0,0 +1,325 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"aws-mcp-server/pkg/alertgroup"
+	"aws-mcp-server/pkg/types"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const (
+	// alarmsURI lists every alarm grouped by state
+	alarmsURI = "aws://cloudwatch/alarms"
+	// alarmHistoryTemplate is the URI template of one alarm's state history
+	alarmHistoryTemplate = "aws://cloudwatch/alarms/{+alarmName}/history"
+	// maxAlarmHistory bounds the state changes returned for one alarm
+	maxAlarmHistory = 100
+)
+
+// alarmStates are the CloudWatch alarm states, worst first
+var alarmStates = []string{"ALARM", "INSUFFICIENT_DATA", "OK"}
+
+// comparisonSymbols shortens the threshold comparison operators; anomaly
+// detection operators keep their names
+var comparisonSymbols = map[string]string{
+	"GreaterThanThreshold":          ">",
+	"GreaterThanOrEqualToThreshold": ">=",
+	"LessThanThreshold":             "<",
+	"LessThanOrEqualToThreshold":    "<=",
+}
+
+// readAlarms returns all CloudWatch alarms grouped by state
+func (h *ResourceHandler) readAlarms(ctx context.Context) (*mcp.ReadResourceResult, error) {
+	alarms, err := h.awsClient.ListAlarms(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list CloudWatch alarms: %w", err)
+	}
+
+	jsonData, err := json.MarshalIndent(h.formatAlarmsForAI(alarms, h.instanceGroups(ctx, alarms)), "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal alarms data: %w", err)
+	}
+
+	return &mcp.ReadResourceResult{
+		Contents: []mcp.ResourceContents{
+			&mcp.TextResourceContents{
+				URI:      alarmsURI,
+				MIMEType: "application/json",
+				Text:     string(jsonData),
+			},
+		},
+	}, nil
+}
+
+// readAlarmHistory returns the recent state changes of one alarm, newest first
+func (h *ResourceHandler) readAlarmHistory(ctx context.Context, uri string) (*mcp.ReadResourceResult, error) {
+	alarmName, err := alarmNameFromURI(uri)
+	if err != nil {
+		return nil, err
+	}
+
+	alarms, err := h.awsClient.ListAlarms(ctx, alarmName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get alarm %s: %w", alarmName, err)
+	}
+	if len(alarms) == 0 {
+		return nil, fmt.Errorf("alarm %s not found", alarmName)
+	}
+
+	changes, err := h.awsClient.DescribeAlarmHistory(ctx, alarmName, maxAlarmHistory)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get alarm history: %w", err)
+	}
+
+	jsonData, err := json.MarshalIndent(h.formatAlarmHistory(alarms[0], changes), "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal alarm history: %w", err)
+	}
+
+	return &mcp.ReadResourceResult{
+		Contents: []mcp.ResourceContents{
+			&mcp.TextResourceContents{
+				URI:      uri,
+				MIMEType: "application/json",
+				Text:     string(jsonData),
+			},
+		},
+	}, nil
+}
+
+// alarmNameFromURI extracts the alarm name from a history URI. Names may
+// contain spaces and slashes, so clients URL-encode them.
+func alarmNameFromURI(uri string) (string, error) {
+	encoded, ok := strings.CutPrefix(uri, alarmsURI+"/")
+	if ok {
+		encoded, ok = strings.CutSuffix(encoded, "/history")
+	}
+	if !ok || encoded == "" {
+		return "", fmt.Errorf("invalid alarm history URI %s, use %s", uri, alarmHistoryTemplate)
+	}
+
+	alarmName, err := url.PathUnescape(encoded)
+	if err != nil {
+		return "", fmt.Errorf("invalid alarm name %q: %w", encoded, err)
+	}
+	return alarmName, nil
+}
+
+// instanceGroups maps the instances that alarms watch to their Auto Scaling
+// group when alarms are grouped by Auto Scaling group. Without the map, for
+// example when instances cannot be listed, instance alarms are still grouped
+// by instance.
+func (h *ResourceHandler) instanceGroups(ctx context.Context, alarms []types.Alarm) map[string]string {
+	if h.grouper == nil || !h.grouper.UsesAutoScalingGroups() {
+		return nil
+	}
+	watched := make(map[string]bool)
+	for _, alarm := range alarms {
+		if instanceID := alarm.Dimensions["InstanceId"]; instanceID != "" && alarm.State != "OK" {
+			watched[instanceID] = true
+		}
+	}
+	if len(watched) == 0 {
+		return nil
+	}
+
+	instances, err := h.awsClient.ListEC2Instances(ctx)
+	if err != nil {
+		return nil
+	}
+	groups := make(map[string]string)
+	for _, instance := range instances {
+		if group := instance.Tags[alertgroup.AutoScalingTag]; group != "" && watched[instance.ID] {
+			groups[instance.ID] = group
+		}
+	}
+	return groups
+}
+
+// formatAlarmsForAI groups alarms by state, alarms that changed most recently
+// first, with counts per state. Snoozed and suppressed alarms are left out and
+// acknowledged alarms are marked. With alert grouping, duplicate and related
+// alarms are listed once under a lead alarm.
+func (h *ResourceHandler) formatAlarmsForAI(alarms []types.Alarm, instanceGroups map[string]string) map[string]interface{} {
+	sorted := make([]types.Alarm, len(alarms))
+	copy(sorted, alarms)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].StateUpdated.After(sorted[j].StateUpdated)
+	})
+
+	byState := make(map[string][]map[string]interface{}, len(alarmStates))
+	stateCount := make(map[string]int, len(alarmStates))
+	for _, state := range alarmStates {
+		byState[state] = []map[string]interface{}{}
+		stateCount[state] = 0
+	}
+
+	now := time.Now()
+	actionsDisabled, silenced := 0, 0
+	visible := make([]types.Alarm, 0, len(sorted))
+	for _, alarm := range sorted {
+		if entry, isSilenced := h.silencedAlarm(alarm, now); isSilenced && entry.Hides(now) {
+			silenced++
+			continue
+		}
+		visible = append(visible, alarm)
+		stateCount[alarm.State]++
+		if !alarm.ActionsEnabled {
+			actionsDisabled++
+		}
+	}
+
+	listed := visible
+	var grouping alertgroup.Result
+	duplicates := make(map[string][]string)
+	if h.grouper != nil {
+		grouping = h.grouper.Group(visible, instanceGroups)
+		listed = grouping.Alarms
+		for duplicate, original := range grouping.Duplicates {
+			duplicates[original] = append(duplicates[original], duplicate)
+		}
+	}
+
+	for _, alarm := range listed {
+		formatted := h.formatAlarm(alarm)
+		if entry, isSilenced := h.silencedAlarm(alarm, now); isSilenced {
+			formatted["acknowledged"] = map[string]interface{}{
+				"reason": entry.Reason,
+				"by":     entry.CreatedBy,
+				"at":     h.times.Format(entry.CreatedAt),
+			}
+		}
+		if group, ok := grouping.Groups[alarm.Name]; ok {
+			formatted["group"] = map[string]interface{}{
+				"rule":   group.Rule,
+				"key":    group.Key,
+				"count":  len(group.Members) + 1,
+				"alarms": group.Names(),
+			}
+		}
+		if names, ok := duplicates[alarm.Name]; ok {
+			sort.Strings(names)
+			formatted["duplicates"] = names
+		}
+		byState[alarm.State] = append(byState[alarm.State], formatted)
+	}
+
+	result := map[string]interface{}{
+		"total_alarms":     len(alarms),
+		"alarms_by_state":  byState,
+		"summary_by_state": stateCount,
+		"actions_disabled": actionsDisabled,
+	}
+	if hidden := len(visible) - len(listed); hidden > 0 {
+		result["grouped_alarms"] = hidden
+		result["alarm_groups"] = len(grouping.Groups)
+		result["grouping_note"] = "Duplicate and related alarms are listed once, under the group or duplicates of the alarm that stands for them"
+	}
+	if silenced > 0 {
+		result["silenced_alarms"] = silenced
+		result["silenced_note"] = "Snoozed and suppressed alarms are left out, see " + suppressionsURI
+	}
+	return result
+}
+
+// formatAlarm formats one alarm with its condition in a readable form
+func (h *ResourceHandler) formatAlarm(alarm types.Alarm) map[string]interface{} {
+	formatted := map[string]interface{}{
+		"name":  alarm.Name,
+		"type":  alarm.Type,
+		"state": alarm.State,
+	}
+
+	if !alarm.StateUpdated.IsZero() {
+		formatted["state_updated"] = h.times.Format(alarm.StateUpdated)
+		if relative := h.times.Relative(alarm.StateUpdated); relative != "" {
+			formatted["since"] = relative
+		}
+	}
+	if alarm.StateReason != "" {
+		formatted["reason"] = alarm.StateReason
+	}
+	if alarm.Description != "" {
+		formatted["description"] = alarm.Description
+	}
+
+	if alarm.MetricName != "" {
+		formatted["metric"] = alarm.Namespace + "/" + alarm.MetricName
+	}
+	if len(alarm.Dimensions) > 0 {
+		formatted["dimensions"] = alarm.Dimensions
+	}
+	if condition := alarmCondition(alarm); condition != "" {
+		formatted["condition"] = condition
+	}
+	if alarm.Rule != "" {
+		formatted["rule"] = alarm.Rule
+	}
+	if !alarm.ActionsEnabled {
+		formatted["actions_enabled"] = false
+	}
+
+	return formatted
+}
+
+// alarmCondition renders a metric alarm's condition, e.g.
+// "Average > 80 for 3 x 300s" (three evaluation periods of five minutes)
+func alarmCondition(alarm types.Alarm) string {
+	if alarm.Threshold == nil {
+		return ""
+	}
+
+	operator := alarm.ComparisonOperator
+	if symbol, ok := comparisonSymbols[operator]; ok {
+		operator = symbol
+	}
+
+	condition := strings.TrimSpace(fmt.Sprintf("%s %s %g", alarm.Statistic, operator, *alarm.Threshold))
+	if alarm.EvaluationPeriods > 0 && alarm.Period > 0 {
+		condition += fmt.Sprintf(" for %d x %ds", alarm.EvaluationPeriods, alarm.Period)
+	}
+	return condition
+}
+
+// formatAlarmHistory formats an alarm's state changes with how often it fired
+func (h *ResourceHandler) formatAlarmHistory(alarm types.Alarm, changes []types.AlarmStateChange) map[string]interface{} {
+	formatted := make([]map[string]interface{}, 0, len(changes))
+	fired := 0
+	for _, change := range changes {
+		entry := map[string]interface{}{
+			"time": h.times.Format(change.Time),
+			"from": change.OldState,
+			"to":   change.NewState,
+		}
+		if relative := h.times.Relative(change.Time); relative != "" {
+			entry["since"] = relative
+		}
+		if change.Reason != "" {
+			entry["reason"] = change.Reason
+		} else {
+			entry["summary"] = change.Summary
+		}
+		formatted = append(formatted, entry)
+
+		if change.NewState == "ALARM" {
+			fired++
+		}
+	}
+
+	return map[string]interface{}{
+		"alarm":         h.formatAlarm(alarm),
+		"alarm_name":    alarm.Name,
+		"current_state": alarm.State,
+		"count":         len(formatted),
+		"times_fired":   fired,
+		"changes":       formatted,
+	}
+}
@@ -0,0 +1,110 @@
+package mcp
+
+import (
+	"testing"
+	"time"
+
+	"aws-mcp-server/internal/config"
+	"aws-mcp-server/pkg/alertgroup"
+	"aws-mcp-server/pkg/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatAlarmsForAI(t *testing.T) {
+	h := NewResourceHandler(&config.Config{}, nil)
+	now := time.Now()
+	threshold := 80.0
+
+	alarms := []types.Alarm{
+		{Name: "api-latency", Type: "metric", State: "OK", StateUpdated: now.Add(-2 * time.Hour), ActionsEnabled: true},
+		{
+			Name:               "web-cpu-high",
+			Type:               "metric",
+			State:              "ALARM",
+			StateReason:        "Threshold Crossed: 3 datapoints were greater than the threshold (80.0)",
+			StateUpdated:       now.Add(-10 * time.Minute),
+			Namespace:          "AWS/EC2",
+			MetricName:         "CPUUtilization",
+			Dimensions:         map[string]string{"AutoScalingGroupName": "web"},
+			Statistic:          "Average",
+			ComparisonOperator: "GreaterThanThreshold",
+			Threshold:          &threshold,
+			Period:             300,
+			EvaluationPeriods:  3,
+			ActionsEnabled:     true,
+		},
+		{Name: "checkout-health", Type: "composite", State: "OK", StateUpdated: now.Add(-time.Hour), Rule: `ALARM("api-latency")`},
+	}
+
+	formatted := h.formatAlarmsForAI(alarms, nil)
+
+	assert.Equal(t, 3, formatted["total_alarms"])
+	assert.Equal(t, map[string]int{"ALARM": 1, "INSUFFICIENT_DATA": 0, "OK": 2}, formatted["summary_by_state"])
+	assert.Equal(t, 1, formatted["actions_disabled"])
+
+	byState := formatted["alarms_by_state"].(map[string][]map[string]interface{})
+	require.Len(t, byState["ALARM"], 1)
+	assert.Equal(t, "AWS/EC2/CPUUtilization", byState["ALARM"][0]["metric"])
+	assert.Equal(t, "Average > 80 for 3 x 300s", byState["ALARM"][0]["condition"])
+	assert.Empty(t, byState["INSUFFICIENT_DATA"])
+
+	// Most recently changed first
+	require.Len(t, byState["OK"], 2)
+	assert.Equal(t, "checkout-health", byState["OK"][0]["name"])
+	assert.Equal(t, false, byState["OK"][0]["actions_enabled"])
+	assert.Equal(t, `ALARM("api-latency")`, byState["OK"][0]["rule"])
+	assert.Equal(t, "api-latency", byState["OK"][1]["name"])
+}
+
+func TestFormatAlarmsForAIGroupsRelatedAlarms(t *testing.T) {
+	h := NewResourceHandler(&config.Config{}, nil)
+	grouper, err := alertgroup.NewGrouper(nil)
+	require.NoError(t, err)
+	h.grouper = grouper
+	now := time.Now()
+	threshold := 80.0
+
+	alarm := func(name, metric, instanceID string, updated time.Duration) types.Alarm {
+		return types.Alarm{
+			Name: name, Type: "metric", State: "ALARM", StateUpdated: now.Add(-updated),
+			Namespace: "AWS/EC2", MetricName: metric, Dimensions: map[string]string{"InstanceId": instanceID},
+			Statistic: "Average", ComparisonOperator: "GreaterThanThreshold", Threshold: &threshold,
+		}
+	}
+	alarms := []types.Alarm{
+		alarm("web-1-cpu", "CPUUtilization", "i-1", 5*time.Minute),
+		alarm("web-1-cpu-legacy", "CPUUtilization", "i-1", 6*time.Minute),
+		alarm("web-1-memory", "mem_used_percent", "i-1", 7*time.Minute),
+		alarm("web-2-cpu", "CPUUtilization", "i-2", time.Minute),
+	}
+
+	formatted := h.formatAlarmsForAI(alarms, nil)
+	assert.Equal(t, 4, formatted["summary_by_state"].(map[string]int)["ALARM"])
+	assert.Equal(t, 2, formatted["grouped_alarms"])
+	assert.Equal(t, 1, formatted["alarm_groups"])
+
+	firing := formatted["alarms_by_state"].(map[string][]map[string]interface{})["ALARM"]
+	require.Len(t, firing, 2)
+	assert.Equal(t, "web-2-cpu", firing[0]["name"])
+	assert.NotContains(t, firing[0], "group")
+	assert.Equal(t, "web-1-cpu", firing[1]["name"])
+	assert.Equal(t, []string{"web-1-cpu-legacy"}, firing[1]["duplicates"])
+	assert.Equal(t, map[string]interface{}{"rule": "instance", "key": "i-1", "count": 2, "alarms": []string{"web-1-memory"}}, firing[1]["group"])
+}
+
+func TestAlarmNameFromURI(t *testing.T) {
+	name, err := alarmNameFromURI("aws://cloudwatch/alarms/web-cpu-high/history")
+	require.NoError(t, err)
+	assert.Equal(t, "web-cpu-high", name)
+
+	name, err = alarmNameFromURI("aws://cloudwatch/alarms/TargetTracking%2Fweb%20CPU/history")
+	require.NoError(t, err)
+	assert.Equal(t, "TargetTracking/web CPU", name)
+
+	_, err = alarmNameFromURI("aws://cloudwatch/alarms/web-cpu-high")
+	assert.Error(t, err)
+	_, err = alarmNameFromURI("aws://cloudwatch/alarms//history")
+	assert.Error(t, err)
+}
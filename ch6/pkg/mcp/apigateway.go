@@ -0,0 +1,368 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"aws-mcp-server/pkg/aws"
+	"aws-mcp-server/pkg/types"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const (
+	// apisURI lists the API Gateway APIs with their stages and throttling
+	apisURI = "aws://apigateway/apis"
+	// apiTemplate is the URI template of one API, by its ID
+	apiTemplate = "aws://apigateway/apis/{apiId}"
+)
+
+// readAPIs lists the REST, HTTP and WebSocket APIs with their stages, APIs
+// with a stage that needs attention first
+func (h *ResourceHandler) readAPIs(ctx context.Context) (*mcp.ReadResourceResult, error) {
+	apis, err := h.awsClient.ListAPIs(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list APIs: %w", err)
+	}
+
+	data := formatAPIs(apis)
+	// The account limit only adds context, so the resource is still served
+	// when it cannot be read
+	if account, err := h.awsClient.GetAPIAccountThrottle(ctx); err == nil && account != nil {
+		data["account_throttle"] = formatThrottle(*account)
+	}
+
+	jsonData, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal APIs data: %w", err)
+	}
+
+	return &mcp.ReadResourceResult{
+		Contents: []mcp.ResourceContents{
+			&mcp.TextResourceContents{
+				URI:      apisURI,
+				MIMEType: "application/json",
+				Text:     string(jsonData),
+			},
+		},
+	}, nil
+}
+
+// readAPI returns one API by the ID in the URI with the throttling of each
+// stage, including the limits set on single methods or routes
+func (h *ResourceHandler) readAPI(ctx context.Context, req resourceRequest) (*mcp.ReadResourceResult, error) {
+	uri, id := req.URI, req.Param("apiId")
+	if id == "" {
+		return nil, fmt.Errorf("invalid API URI %s, use %s", uri, apiTemplate)
+	}
+
+	api, err := findAPI(ctx, h.awsClient, id)
+	if err != nil {
+		return nil, err
+	}
+
+	data := formatAPI(*api)
+	data["endpoint"] = api.Endpoint
+	data["created"] = h.times.Format(api.CreatedAt)
+	stages := data["stages"].([]map[string]interface{})
+	for i, stage := range api.Stages {
+		if stage.LastUpdatedAt != nil {
+			stages[i]["last_updated"] = h.times.Format(*stage.LastUpdatedAt)
+		}
+		if len(stage.RouteThrottles) > 0 {
+			overrides := make(map[string]interface{}, len(stage.RouteThrottles))
+			for route, throttle := range stage.RouteThrottles {
+				overrides[route] = formatThrottle(throttle)
+			}
+			stages[i]["route_throttles"] = overrides
+		}
+	}
+
+	jsonData, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal API data: %w", err)
+	}
+
+	return &mcp.ReadResourceResult{
+		Contents: []mcp.ResourceContents{
+			&mcp.TextResourceContents{
+				URI:      uri,
+				MIMEType: "application/json",
+				Text:     string(jsonData),
+			},
+		},
+	}, nil
+}
+
+// formatAPIs orders the APIs with issues first and counts them per protocol
+func formatAPIs(apis []types.API) map[string]interface{} {
+	items := make([]map[string]interface{}, 0, len(apis))
+	byProtocol := make(map[string]int)
+	withIssues := []string{}
+	for _, api := range apis {
+		item := formatAPI(api)
+		if item["has_issues"].(bool) {
+			withIssues = append(withIssues, api.Name)
+		}
+		byProtocol[api.Protocol]++
+		items = append(items, item)
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		ii, ij := items[i]["has_issues"].(bool), items[j]["has_issues"].(bool)
+		if ii != ij {
+			return ii
+		}
+		return items[i]["name"].(string) < items[j]["name"].(string)
+	})
+	sort.Strings(withIssues)
+
+	return map[string]interface{}{
+		"total":       len(apis),
+		"by_protocol": byProtocol,
+		"with_issues": withIssues,
+		"apis":        items,
+	}
+}
+
+// formatAPI formats an API with the throttling and issues of its stages
+func formatAPI(api types.API) map[string]interface{} {
+	stages := make([]map[string]interface{}, 0, len(api.Stages))
+	hasIssues := false
+	for _, stage := range api.Stages {
+		item := map[string]interface{}{
+			"name":        stage.Name,
+			"auto_deploy": stage.AutoDeploy,
+		}
+		if stage.DeploymentID != "" {
+			item["deployment_id"] = stage.DeploymentID
+		}
+		if stage.Throttle != nil {
+			item["throttle"] = formatThrottle(*stage.Throttle)
+		} else if api.Protocol == "REST" {
+			item["throttle"] = "account limit"
+		} else {
+			item["throttle"] = "none"
+		}
+		if len(stage.RouteThrottles) > 0 {
+			item["route_throttle_overrides"] = len(stage.RouteThrottles)
+		}
+		if stage.DeploymentStatus != "" {
+			item["last_deployment_status"] = stage.DeploymentStatus
+		}
+		if issues := apiStageIssues(stage); len(issues) > 0 {
+			item["issues"] = issues
+			hasIssues = true
+		}
+		stages = append(stages, item)
+	}
+
+	item := map[string]interface{}{
+		"id":         api.ID,
+		"name":       api.Name,
+		"protocol":   api.Protocol,
+		"uri":        apisURI + "/" + api.ID,
+		"stages":     stages,
+		"has_issues": hasIssues,
+	}
+	if api.EndpointType != "" {
+		item["endpoint_type"] = api.EndpointType
+	}
+	return item
+}
+
+// formatThrottle formats a rate and burst limit
+func formatThrottle(throttle types.APIThrottle) map[string]interface{} {
+	return map[string]interface{}{
+		"rate_limit":  throttle.RateLimit,
+		"burst_limit": throttle.BurstLimit,
+	}
+}
+
+// apiStageIssues lists why a stage needs attention: limits of zero, which
+// reject every request with 429, or a failed automatic deployment
+func apiStageIssues(stage types.APIStage) []string {
+	var issues []string
+	if stage.Throttle != nil && (stage.Throttle.RateLimit == 0 || stage.Throttle.BurstLimit == 0) {
+		issues = append(issues, "the stage throttle is zero, every request is rejected with 429 Too Many Requests")
+	}
+
+	var blocked []string
+	for route, throttle := range stage.RouteThrottles {
+		if throttle.RateLimit == 0 || throttle.BurstLimit == 0 {
+			blocked = append(blocked, route)
+		}
+	}
+	if len(blocked) > 0 {
+		sort.Strings(blocked)
+		issues = append(issues, fmt.Sprintf("requests to %s are all rejected, their throttle is zero", strings.Join(blocked, ", ")))
+	}
+
+	if strings.Contains(strings.ToLower(stage.DeploymentStatus), "fail") {
+		issues = append(issues, fmt.Sprintf("the last automatic deployment failed: %s", stage.DeploymentStatus))
+	}
+	return issues
+}
+
+// findAPI looks up an API by ID or name. Names are not unique, so a name
+// shared by several APIs must be replaced by the ID.
+func findAPI(ctx context.Context, client *aws.Client, idOrName string) (*types.API, error) {
+	apis, err := client.ListAPIs(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list APIs: %w", err)
+	}
+
+	var matches []types.API
+	for _, api := range apis {
+		if api.ID == idOrName {
+			return &api, nil
+		}
+		if api.Name == idOrName {
+			matches = append(matches, api)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("API %s not found", idOrName)
+	case 1:
+		return &matches[0], nil
+	}
+	ids := make([]string, 0, len(matches))
+	for _, api := range matches {
+		ids = append(ids, api.ID)
+	}
+	return nil, fmt.Errorf("%d APIs are named %s, use one of the IDs %s", len(matches), idOrName, strings.Join(ids, ", "))
+}
+
+// apiStage returns the stage of an API by name
+func apiStage(api types.API, name string) (*types.APIStage, error) {
+	for _, stage := range api.Stages {
+		if stage.Name == name {
+			return &stage, nil
+		}
+	}
+	names := make([]string, 0, len(api.Stages))
+	for _, stage := range api.Stages {
+		names = append(names, stage.Name)
+	}
+	return nil, fmt.Errorf("API %s has no stage %s, its stages are %s", api.Name, name, strings.Join(names, ", "))
+}
+
+// deployAPIStage deploys the current configuration of an API to a stage, e.g.
+// to push a fixed integration live. The plan is returned for confirmation first.
+func (h *ToolHandler) deployAPIStage(ctx context.Context, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	apiID, _ := arguments["api"].(string)
+	stageName, _ := arguments["stage"].(string)
+	if apiID == "" || stageName == "" {
+		return h.createErrorResponse("api and stage are required")
+	}
+	description, _ := arguments["description"].(string)
+	if description == "" {
+		description = "Deployed by the AIOps server"
+	}
+
+	api, err := findAPI(ctx, h.awsClient, apiID)
+	if err != nil {
+		return h.createErrorResponse(err.Error())
+	}
+	stage, err := apiStage(*api, stageName)
+	if err != nil {
+		return h.createErrorResponse(err.Error())
+	}
+	if stage.AutoDeploy {
+		return h.createErrorResponse(fmt.Sprintf("stage %s of %s deploys automatically on every change, there is nothing to deploy", stage.Name, api.Name))
+	}
+
+	if !isConfirmed(arguments) {
+		plan := map[string]interface{}{
+			"api":         api.Name,
+			"apiId":       api.ID,
+			"protocol":    api.Protocol,
+			"stage":       stage.Name,
+			"description": description,
+		}
+		warnings := []string{"Every change made to the API since the stage was last deployed goes live at once"}
+		if stage.DeploymentID != "" {
+			plan["current_deployment"] = stage.DeploymentID
+			warnings = append(warnings, fmt.Sprintf("To roll back, point the stage at deployment %s again", stage.DeploymentID))
+		}
+		if stage.LastUpdatedAt != nil {
+			plan["stage_last_updated"] = h.times.Format(*stage.LastUpdatedAt)
+		}
+		return h.createConfirmationResponse("deploy-api-stage", plan, warnings)
+	}
+
+	deploymentID, err := h.awsClient.DeployAPIStage(ctx, *api, stage.Name, description)
+	if err != nil {
+		return h.createErrorResponse(err.Error())
+	}
+
+	data := map[string]interface{}{
+		"api":          api.Name,
+		"apiId":        api.ID,
+		"stage":        stage.Name,
+		"deploymentId": deploymentID,
+	}
+	if stage.DeploymentID != "" {
+		data["previousDeploymentId"] = stage.DeploymentID
+	}
+	return h.createSuccessResponse("API stage deployed successfully", data)
+}
+
+// updateAPIThrottling sets the default rate and burst limit of a stage, e.g.
+// to shed load from a struggling backend or to stop rejecting legitimate
+// traffic with 429s
+func (h *ToolHandler) updateAPIThrottling(ctx context.Context, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	apiID, _ := arguments["api"].(string)
+	stageName, _ := arguments["stage"].(string)
+	if apiID == "" || stageName == "" {
+		return h.createErrorResponse("api and stage are required")
+	}
+	rate, ok := arguments["rateLimit"].(float64)
+	if !ok || rate <= 0 {
+		return h.createErrorResponse("rateLimit must be a number of requests per second greater than 0")
+	}
+	burst, ok := arguments["burstLimit"].(float64)
+	if !ok || burst < 1 || burst != math.Trunc(burst) || burst > math.MaxInt32 {
+		return h.createErrorResponse("burstLimit must be a whole number of requests greater than 0")
+	}
+	throttle := types.APIThrottle{RateLimit: rate, BurstLimit: int32(burst)}
+
+	api, err := findAPI(ctx, h.awsClient, apiID)
+	if err != nil {
+		return h.createErrorResponse(err.Error())
+	}
+	stage, err := apiStage(*api, stageName)
+	if err != nil {
+		return h.createErrorResponse(err.Error())
+	}
+
+	if err := h.awsClient.UpdateAPIStageThrottle(ctx, *api, stage.Name, throttle); err != nil {
+		return h.createErrorResponse(err.Error())
+	}
+
+	data := map[string]interface{}{
+		"api":      api.Name,
+		"apiId":    api.ID,
+		"stage":    stage.Name,
+		"throttle": formatThrottle(throttle),
+	}
+	if stage.Throttle != nil {
+		data["previousThrottle"] = formatThrottle(*stage.Throttle)
+	}
+	var notes []string
+	if len(stage.RouteThrottles) > 0 {
+		notes = append(notes, fmt.Sprintf("%d %s keep their own limits", len(stage.RouteThrottles), plural(len(stage.RouteThrottles), "method or route", "methods or routes")))
+	}
+	if api.Protocol == "REST" {
+		notes = append(notes, "The account throttle still caps the stage when it is lower")
+	}
+	if len(notes) > 0 {
+		data["notes"] = notes
+	}
+	return h.createSuccessResponse("API stage throttling updated successfully", data)
+}
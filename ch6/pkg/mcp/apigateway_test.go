@@ -0,0 +1,90 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	"aws-mcp-server/internal/config"
+	"aws-mcp-server/internal/logging"
+	"aws-mcp-server/pkg/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatAPIs(t *testing.T) {
+	data := formatAPIs([]types.API{
+		{ID: "a1", Name: "orders", Protocol: "REST", EndpointType: "REGIONAL", Stages: []types.APIStage{
+			{Name: "prod", DeploymentID: "d1", Throttle: &types.APIThrottle{RateLimit: 500, BurstLimit: 1000}},
+			{Name: "dev"},
+		}},
+		{ID: "b2", Name: "checkout", Protocol: "HTTP", Stages: []types.APIStage{
+			{Name: "$default", AutoDeploy: true, DeploymentStatus: "Deployment attempt failed: integration not found"},
+		}},
+		{ID: "c3", Name: "chat", Protocol: "WEBSOCKET", Stages: []types.APIStage{{Name: "live"}}},
+	})
+
+	assert.Equal(t, 3, data["total"])
+	assert.Equal(t, map[string]int{"REST": 1, "HTTP": 1, "WEBSOCKET": 1}, data["by_protocol"])
+	assert.Equal(t, []string{"checkout"}, data["with_issues"])
+
+	apis := data["apis"].([]map[string]interface{})
+	require.Len(t, apis, 3)
+	assert.Equal(t, "checkout", apis[0]["name"], "APIs with issues come first")
+	assert.Equal(t, "chat", apis[1]["name"])
+	assert.Equal(t, "aws://apigateway/apis/a1", apis[2]["uri"])
+
+	stages := apis[2]["stages"].([]map[string]interface{})
+	assert.Equal(t, map[string]interface{}{"rate_limit": float64(500), "burst_limit": int32(1000)}, stages[0]["throttle"])
+	assert.Equal(t, "account limit", stages[1]["throttle"], "REST stages without a limit fall back to the account throttle")
+	assert.Equal(t, "none", apis[1]["stages"].([]map[string]interface{})[0]["throttle"])
+}
+
+func TestAPIStageIssues(t *testing.T) {
+	issues := apiStageIssues(types.APIStage{
+		Throttle: &types.APIThrottle{RateLimit: 0, BurstLimit: 100},
+		RouteThrottles: map[string]types.APIThrottle{
+			"POST /orders":  {RateLimit: 0, BurstLimit: 0},
+			"GET /orders":   {RateLimit: 100, BurstLimit: 200},
+			"DELETE /items": {RateLimit: 5, BurstLimit: 0},
+		},
+	})
+	require.Len(t, issues, 2)
+	assert.Contains(t, issues[0], "every request is rejected with 429")
+	assert.Equal(t, "requests to DELETE /items, POST /orders are all rejected, their throttle is zero", issues[1])
+
+	assert.Empty(t, apiStageIssues(types.APIStage{Throttle: &types.APIThrottle{RateLimit: 10, BurstLimit: 20}, DeploymentStatus: "Deployment succeeded"}))
+}
+
+func TestAPIStage(t *testing.T) {
+	api := types.API{Name: "orders", Stages: []types.APIStage{{Name: "prod"}, {Name: "dev"}}}
+
+	stage, err := apiStage(api, "dev")
+	require.NoError(t, err)
+	assert.Equal(t, "dev", stage.Name)
+
+	_, err = apiStage(api, "staging")
+	assert.EqualError(t, err, "API orders has no stage staging, its stages are prod, dev")
+}
+
+func TestAPIGatewayToolsValidateArguments(t *testing.T) {
+	h := NewToolHandler(&config.Config{}, nil, logging.NewLogger("error", "text"))
+
+	tests := []struct {
+		tool      string
+		arguments map[string]interface{}
+		problem   string
+	}{
+		{tool: "deploy-api-stage", arguments: map[string]interface{}{"api": "orders"}, problem: "api and stage are required"},
+		{tool: "update-api-throttling", arguments: map[string]interface{}{"stage": "prod"}, problem: "api and stage are required"},
+		{tool: "update-api-throttling", arguments: map[string]interface{}{"api": "orders", "stage": "prod", "rateLimit": float64(0), "burstLimit": float64(10)},
+			problem: "rateLimit must be a number of requests per second greater than 0"},
+		{tool: "update-api-throttling", arguments: map[string]interface{}{"api": "orders", "stage": "prod", "rateLimit": float64(50), "burstLimit": 2.5},
+			problem: "burstLimit must be a whole number of requests greater than 0"},
+	}
+	for _, tt := range tests {
+		result, err := h.CallTool(context.Background(), tt.tool, tt.arguments)
+		require.NoError(t, err)
+		assert.Equal(t, tt.problem, decodeToolResult(t, result)["error"], tt.tool)
+	}
+}
@@ -0,0 +1,364 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"aws-mcp-server/internal/notify"
+	"aws-mcp-server/pkg/approval"
+	"aws-mcp-server/pkg/types"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// contextKey namespaces values stored in the request context by this package
+type contextKey string
+
+const (
+	// roleContextKey carries the caller's role when the transport identifies it
+	roleContextKey contextKey = "role"
+	// principalContextKey carries the person behind a call when the transport
+	// identifies people rather than sessions, such as chat
+	principalContextKey contextKey = "principal"
+	// approvedContextKey marks a tool call that an admin approved from the queue
+	approvedContextKey contextKey = "approved"
+	// approvalContextKey carries the approved request behind such a call
+	approvalContextKey contextKey = "approval"
+)
+
+// mutatingTools change infrastructure and are subject to change freezes
+var mutatingTools = map[string]bool{
+	"audit-tags":                       true,
+	"create-ec2-instance":              true,
+	"start-ec2-instance":               true,
+	"stop-ec2-instance":                true,
+	"terminate-ec2-instance":           true,
+	"bulk-stop-ec2-instances":          true,
+	"start-gcp-instance":               true,
+	"stop-gcp-instance":                true,
+	"start-azure-vm":                   true,
+	"stop-azure-vm":                    true,
+	"update-ecs-service":               true,
+	"force-ecs-deployment":             true,
+	"purge-sqs-queue":                  true,
+	"redrive-sqs-dlq":                  true,
+	"start-rds-instance":               true,
+	"stop-rds-instance":                true,
+	"reboot-rds-instance":              true,
+	"reboot-cache-node":                true,
+	"create-rds-snapshot":              true,
+	"encrypt-volume":                   true,
+	"authorize-security-group-ingress": true,
+	"revoke-security-group-ingress":    true,
+	"create-ebs-snapshot":              true,
+	"delete-ebs-snapshot":              true,
+	"modify-ebs-volume":                true,
+	"deactivate-access-key":            true,
+	"put-parameter":                    true,
+	"rotate-secret":                    true,
+	"run-patch-baseline":               true,
+	"start-execution":                  true,
+	"redrive-execution":                true,
+	"update-shard-count":               true,
+	"deploy-api-stage":                 true,
+	"update-api-throttling":            true,
+	"request-quota-increase":           true,
+	"update-efs-throughput":            true,
+	"start-on-demand-backup":           true,
+	"tag-resource":                     true,
+	"untag-resource":                   true,
+	"publish-sns-message":              true,
+	"capture-traffic":                  true,
+	"get-windows-password":             true,
+}
+
+// WithRole returns a context identifying the caller's role for access checks.
+// Transports that authenticate callers use it; otherwise the configured role applies.
+func WithRole(ctx context.Context, role string) context.Context {
+	return context.WithValue(ctx, roleContextKey, role)
+}
+
+// WithPrincipal returns a context identifying the person behind a call, e.g.
+// a chat user. Elevations are granted to them instead of to the MCP session.
+func WithPrincipal(ctx context.Context, principal string) context.Context {
+	return context.WithValue(ctx, principalContextKey, principal)
+}
+
+// callerRole returns the role of the caller behind the request
+func (h *ToolHandler) callerRole(ctx context.Context) string {
+	if role, ok := ctx.Value(roleContextKey).(string); ok && role != "" {
+		return role
+	}
+	return h.config.Access.Role
+}
+
+// isAdmin reports whether the caller may decide on queued actions
+func (h *ToolHandler) isAdmin(ctx context.Context) bool {
+	role := h.callerRole(ctx)
+	for _, admin := range h.config.Access.AdminRoles {
+		if strings.EqualFold(role, admin) {
+			return true
+		}
+	}
+	return false
+}
+
+// isApproved reports whether the call is the execution of an approved request,
+// which bypasses guardrails and freeze windows
+func isApproved(ctx context.Context) bool {
+	approved, _ := ctx.Value(approvedContextKey).(bool)
+	return approved
+}
+
+// approvalFrom returns the approved request a tool call executes
+func approvalFrom(ctx context.Context) (approval.Request, bool) {
+	request, ok := ctx.Value(approvalContextKey).(approval.Request)
+	return request, ok
+}
+
+// IsMutating reports whether a tool call changes infrastructure, for tools
+// that judge recorded calls, such as the evaluation harness
+func IsMutating(name string, arguments map[string]interface{}) bool {
+	return isMutating(name, arguments)
+}
+
+// isMutating reports whether a tool call changes infrastructure. Disruptive tools
+// that only return a plan until confirmed are not mutating without confirmation.
+func isMutating(name string, arguments map[string]interface{}) bool {
+	switch name {
+	case "audit-tags":
+		autoRemediate, _ := arguments["autoRemediate"].(bool)
+		return autoRemediate
+	case "encrypt-volume", "delete-ebs-snapshot", "deactivate-access-key", "bulk-stop-ec2-instances", "purge-sqs-queue", "reboot-cache-node", "put-parameter", "rotate-secret", "deploy-api-stage", "request-quota-increase", "update-efs-throughput", "capture-traffic", "get-windows-password":
+		return isConfirmed(arguments)
+	case "run-patch-baseline":
+		operation, _ := arguments["operation"].(string)
+		return operation == "Install" && isConfirmed(arguments)
+	default:
+		return mutatingTools[name]
+	}
+}
+
+// checkFreeze blocks mutating calls during an active change freeze
+func (h *ToolHandler) checkFreeze(ctx context.Context, name string, arguments map[string]interface{}) (*mcp.CallToolResult, bool) {
+	if isApproved(ctx) || !isMutating(name, arguments) {
+		return nil, false
+	}
+
+	window := approval.ActiveFreeze(h.freezeWindows, time.Now().In(h.times.Location()))
+	if window == nil {
+		return nil, false
+	}
+
+	reason := fmt.Sprintf("change freeze %q is active", window.Name)
+	result, _ := h.blockAction(ctx, name, arguments, []string{reason}, nil)
+	return result, true
+}
+
+// blockAction asks the user at the MCP client to approve a blocked action when
+// the client supports elicitation, and otherwise queues it for admin approval,
+// or rejects it when queueing is disabled. details are added to the response
+// as-is.
+func (h *ToolHandler) blockAction(ctx context.Context, name string, arguments map[string]interface{}, reasons []string, details map[string]interface{}) (*mcp.CallToolResult, error) {
+	if decision, ok := h.askApproval(ctx, name, arguments, reasons, details); ok {
+		return h.decideAtClient(ctx, name, arguments, reasons, decision)
+	}
+
+	responseData := map[string]interface{}{
+		"reasons": reasons,
+	}
+	for key, value := range details {
+		responseData[key] = value
+	}
+	response := types.NewToolResponse(false, h.times.Now(), responseData)
+	response.Error = fmt.Sprintf("%s is blocked: %s", name, strings.Join(reasons, "; "))
+
+	if h.config.Overrides.Bool("approvals.queue_blocked", h.config.Approvals.QueueBlocked) && h.approvals != nil {
+		request, err := h.approvals.Enqueue(name, arguments, reasons, h.callerRole(ctx))
+		if err != nil {
+			return h.createErrorResponse(fmt.Sprintf("failed to queue action for approval: %v", err))
+		}
+
+		h.logger.WithField("requestId", request.ID).WithField("tool", name).Info("Queued blocked action for approval")
+		h.notifier.Notify(notify.Event{
+			Type:     notify.EventApprovalRequested,
+			Severity: notify.SeverityWarning,
+			Title:    fmt.Sprintf("Approval needed for %s (%s)", name, request.ID),
+			Message:  strings.Join(reasons, "; "),
+			Fields: map[string]interface{}{
+				"request_id":   request.ID,
+				"tool":         name,
+				"arguments":    redactArguments(name, arguments),
+				"requested_by": request.RequestedBy,
+			},
+			Resource: resourceFromArguments(arguments),
+		})
+
+		responseData["queued"] = true
+		responseData["approval_request_id"] = request.ID
+		response.Message = fmt.Sprintf("The action was queued as %s; an admin can run it with approve-action or drop it with reject-action", request.ID)
+	}
+
+	return toolResult(response)
+}
+
+// approveAction approves a queued request and executes the original tool call
+func (h *ToolHandler) approveAction(ctx context.Context, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	requestID, ok := arguments["requestId"].(string)
+	if !ok || requestID == "" {
+		return h.createErrorResponse("requestId is required")
+	}
+
+	if !h.isAdmin(ctx) {
+		return h.createErrorResponse(fmt.Sprintf("approve-action requires one of the roles %v", h.config.Access.AdminRoles))
+	}
+
+	note, _ := arguments["note"].(string)
+	request, err := h.approvals.Approve(requestID, h.callerRole(ctx), note)
+	if err != nil {
+		return h.createErrorResponse(err.Error())
+	}
+
+	return h.runApproved(ctx, request, "Action approved and executed")
+}
+
+// runApproved announces an approved request and executes its tool call. A
+// call that fails leaves the request failed, to be approved again or rejected.
+func (h *ToolHandler) runApproved(ctx context.Context, request approval.Request, message string) (*mcp.CallToolResult, error) {
+	h.logger.WithField("requestId", request.ID).WithField("tool", request.Tool).Info("Executing approved action")
+	h.notifyDecision(request)
+
+	ctx = context.WithValue(context.WithValue(ctx, approvedContextKey, true), approvalContextKey, request)
+	result, err := h.callTool(ctx, request.Tool, request.Arguments)
+	if err == nil {
+		if success, message := resultStatus(result); !success {
+			err = errors.New(message)
+		}
+	}
+	if err != nil {
+		if failed, failErr := h.approvals.Fail(request.ID, err.Error()); failErr == nil {
+			request = failed
+			h.notifyDecision(request)
+		}
+		response := types.NewToolResponse(false, h.times.Now(), map[string]interface{}{
+			"request": formatApprovalRequest(request, h.times.Format),
+		})
+		response.Error = fmt.Sprintf("approved action failed, approve %s again to retry or reject it: %v", request.ID, err)
+		return toolResult(response)
+	}
+
+	data := map[string]interface{}{
+		"request": formatApprovalRequest(request, h.times.Format),
+	}
+	if text, ok := textOf(result.Content[0]); ok && json.Valid([]byte(text)) {
+		data["result"] = json.RawMessage(text)
+	}
+
+	return h.createSuccessResponse(message, data)
+}
+
+// rejectAction rejects a queued request without executing it
+func (h *ToolHandler) rejectAction(ctx context.Context, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	requestID, ok := arguments["requestId"].(string)
+	if !ok || requestID == "" {
+		return h.createErrorResponse("requestId is required")
+	}
+
+	if !h.isAdmin(ctx) {
+		return h.createErrorResponse(fmt.Sprintf("reject-action requires one of the roles %v", h.config.Access.AdminRoles))
+	}
+
+	reason, _ := arguments["reason"].(string)
+	request, err := h.approvals.Reject(requestID, h.callerRole(ctx), reason)
+	if err != nil {
+		return h.createErrorResponse(err.Error())
+	}
+
+	h.notifyDecision(request)
+
+	return h.createSuccessResponse("Action rejected", map[string]interface{}{
+		"request": formatApprovalRequest(request, h.times.Format),
+	})
+}
+
+// notifyDecision announces an approval decision
+func (h *ToolHandler) notifyDecision(request approval.Request) {
+	h.notifier.Notify(notify.Event{
+		Type:     "approval." + string(request.Status),
+		Severity: notify.SeverityInfo,
+		Title:    fmt.Sprintf("%s %s by %s", request.ID, request.Status, request.DecidedBy),
+		Message:  request.Note,
+		Fields: map[string]interface{}{
+			"request_id": request.ID,
+			"tool":       request.Tool,
+			"arguments":  redactArguments(request.Tool, request.Arguments),
+		},
+		Resource: resourceFromArguments(request.Arguments),
+	})
+}
+
+// readPendingApprovals lists queued actions waiting for an admin decision
+func (h *ResourceHandler) readPendingApprovals(ctx context.Context) (*mcp.ReadResourceResult, error) {
+	pending := make([]map[string]interface{}, 0)
+	if h.approvals != nil {
+		for _, request := range h.approvals.Pending() {
+			formatted := formatApprovalRequest(request, h.times.Format)
+			if relative := h.times.Relative(request.RequestedAt); relative != "" {
+				formatted["requested"] = relative
+			}
+			pending = append(pending, formatted)
+		}
+	}
+
+	formatted := map[string]interface{}{
+		"count":          len(pending),
+		"pending":        pending,
+		"approver_roles": h.config.Access.AdminRoles,
+		"instructions":   "Use approve-action to execute a request or reject-action to drop it",
+	}
+
+	jsonData, err := json.MarshalIndent(formatted, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal pending approvals: %w", err)
+	}
+
+	return &mcp.ReadResourceResult{
+		Contents: []mcp.ResourceContents{
+			&mcp.TextResourceContents{
+				URI:      "aws://approvals/pending",
+				MIMEType: "application/json",
+				Text:     string(jsonData),
+			},
+		},
+	}, nil
+}
+
+// formatApprovalRequest converts a request for responses using the given time format
+func formatApprovalRequest(request approval.Request, formatTime func(time.Time) string) map[string]interface{} {
+	formatted := map[string]interface{}{
+		"id":           request.ID,
+		"tool":         request.Tool,
+		"arguments":    redactArguments(request.Tool, request.Arguments),
+		"reasons":      request.Reasons,
+		"status":       request.Status,
+		"requested_by": request.RequestedBy,
+		"requested_at": formatTime(request.RequestedAt),
+	}
+
+	if request.DecidedAt != nil {
+		formatted["decided_by"] = request.DecidedBy
+		formatted["decided_at"] = formatTime(*request.DecidedAt)
+	}
+	if request.Note != "" {
+		formatted["note"] = request.Note
+	}
+	if request.Error != "" {
+		formatted["error"] = request.Error
+	}
+
+	return formatted
+}
@@ -0,0 +1,133 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"aws-mcp-server/internal/config"
+	"aws-mcp-server/internal/logging"
+	"aws-mcp-server/pkg/approval"
+	"aws-mcp-server/pkg/aws"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// decodeToolResult parses the JSON payload of a tool result
+func decodeToolResult(t *testing.T, result *mcp.CallToolResult) map[string]interface{} {
+	t.Helper()
+
+	require.NotEmpty(t, result.Content)
+	text, ok := textOf(result.Content[0])
+	require.True(t, ok)
+
+	var data map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(text), &data))
+	return data
+}
+
+func TestFreezeWindowQueuesActions(t *testing.T) {
+	logger := logging.NewLogger("error", "text")
+	awsClient, err := aws.NewClient("us-west-2", "", logger)
+	if err != nil {
+		t.Skip("Skipping test due to AWS configuration requirement")
+	}
+
+	cfg := &config.Config{
+		Access: config.AccessConfig{Role: "operator", AdminRoles: []string{"admin"}},
+		Approvals: config.ApprovalsConfig{
+			QueueBlocked: true,
+			FreezeWindows: []config.FreezeWindowConfig{
+				{Name: "always", Days: []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}, Start: "00:00", End: "00:00"},
+			},
+		},
+	}
+	toolHandler := NewToolHandler(cfg, awsClient, logger)
+	ctx := context.Background()
+
+	result, err := toolHandler.CallTool(ctx, "stop-ec2-instance", map[string]interface{}{"instanceId": "i-abc"})
+	require.NoError(t, err)
+
+	data := decodeToolResult(t, result)
+	assert.Equal(t, false, data["success"])
+	assert.Equal(t, true, data["queued"])
+	requestID, _ := data["approval_request_id"].(string)
+	require.NotEmpty(t, requestID)
+	require.Len(t, toolHandler.approvals.Pending(), 1)
+
+	t.Run("operators cannot decide", func(t *testing.T) {
+		result, err := toolHandler.CallTool(ctx, "reject-action", map[string]interface{}{"requestId": requestID})
+		require.NoError(t, err)
+		assert.Contains(t, decodeToolResult(t, result)["error"], "requires one of the roles")
+	})
+
+	t.Run("admins can reject", func(t *testing.T) {
+		result, err := toolHandler.CallTool(WithRole(ctx, "admin"), "reject-action", map[string]interface{}{"requestId": requestID, "reason": "not during the freeze"})
+		require.NoError(t, err)
+		assert.Equal(t, true, decodeToolResult(t, result)["success"])
+		assert.Empty(t, toolHandler.approvals.Pending())
+	})
+}
+
+func TestFailedApprovedActionsCanBeRetried(t *testing.T) {
+	cfg := &config.Config{
+		Access: config.AccessConfig{Role: "operator", AdminRoles: []string{"admin"}},
+		Approvals: config.ApprovalsConfig{
+			QueueBlocked: true,
+			FreezeWindows: []config.FreezeWindowConfig{
+				{Name: "always", Days: []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}, Start: "00:00", End: "00:00"},
+			},
+		},
+	}
+	h := NewToolHandler(cfg, nil, logging.NewLogger("error", "text"))
+	ctx := context.Background()
+	admin := WithRole(ctx, "admin")
+
+	result, err := h.CallTool(ctx, "start-gcp-instance", map[string]interface{}{"instanceId": "web-1"})
+	require.NoError(t, err)
+	requestID, _ := decodeToolResult(t, result)["approval_request_id"].(string)
+	require.NotEmpty(t, requestID)
+
+	// GCP is not configured, so the approved call fails
+	for attempt := 0; attempt < 2; attempt++ {
+		result, err = h.CallTool(admin, "approve-action", map[string]interface{}{"requestId": requestID})
+		require.NoError(t, err)
+		assert.Contains(t, decodeToolResult(t, result)["error"], "approve "+requestID+" again to retry")
+
+		pending := h.approvals.Pending()
+		require.Len(t, pending, 1, "failed actions wait for a decision again")
+		assert.Equal(t, approval.StatusFailed, pending[0].Status)
+		assert.Equal(t, "this cloud provider is not configured", pending[0].Error)
+	}
+
+	result, err = h.CallTool(admin, "reject-action", map[string]interface{}{"requestId": requestID, "reason": "GCP is gone"})
+	require.NoError(t, err)
+	assert.Equal(t, true, decodeToolResult(t, result)["success"])
+	assert.Empty(t, h.approvals.Pending())
+}
+
+func TestPublishingToSNSIsAChange(t *testing.T) {
+	cfg := &config.Config{
+		Access: config.AccessConfig{Role: "operator", ReadOnlyRoles: []string{"viewer"}},
+		Approvals: config.ApprovalsConfig{
+			QueueBlocked: true,
+			FreezeWindows: []config.FreezeWindowConfig{
+				{Name: "always", Days: []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}, Start: "00:00", End: "00:00"},
+			},
+		},
+	}
+	h := NewToolHandler(cfg, nil, logging.NewLogger("error", "text"))
+	arguments := map[string]interface{}{"topic": "oncall", "message": "disk full on db-1"}
+
+	result, err := h.CallTool(WithRole(context.Background(), "viewer"), "publish-sns-message", arguments)
+	require.NoError(t, err)
+	assert.Contains(t, decodeToolResult(t, result)["error"], "role viewer is read-only")
+
+	result, err = h.CallTool(context.Background(), "publish-sns-message", arguments)
+	require.NoError(t, err)
+	assert.Equal(t, true, decodeToolResult(t, result)["queued"], "messages that page people wait out the freeze")
+	require.Len(t, h.approvals.Pending(), 1)
+	assert.Equal(t, "publish-sns-message", h.approvals.Pending()[0].Tool)
+}
@@ -0,0 +1,374 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"aws-mcp-server/internal/config"
+	"aws-mcp-server/pkg/aws"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const (
+	// defaultAthenaTimeout applies when athena.timeout is not configured
+	defaultAthenaTimeout = 5 * time.Minute
+	// maxAthenaTimeout bounds the per-call timeout so a tool call cannot hang for long
+	maxAthenaTimeout = 30 * time.Minute
+	// defaultAthenaMaxRows applies when athena.max_rows is not configured
+	defaultAthenaMaxRows = 1000
+	// athenaMinimumBilledBytes is the 10 MB Athena bills for every query
+	athenaMinimumBilledBytes = 10 << 20
+	bytesPerGB               = 1 << 30
+	bytesPerTB               = 1 << 40
+)
+
+// readOnlyAthenaStatements are the statements run-athena-query accepts; DDL,
+// INSERT, CTAS and UNLOAD would change tables or write data
+var readOnlyAthenaStatements = map[string]bool{
+	"SELECT":   true,
+	"WITH":     true,
+	"SHOW":     true,
+	"DESCRIBE": true,
+	"EXPLAIN":  true,
+	"VALUES":   true,
+}
+
+// runAthenaQuery runs a read-only SQL query in Athena within the configured
+// scan, cost and row limits
+func (h *ToolHandler) runAthenaQuery(ctx context.Context, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	cfg := h.config.Athena
+	params, err := athenaQuery(cfg, arguments)
+	if err != nil {
+		return h.createErrorResponse(err.Error())
+	}
+
+	if maxRows, ok := arguments["maxRows"].(float64); ok && maxRows > 0 {
+		params.MaxRows = int(min(maxRows, float64(params.MaxRows)))
+	}
+
+	if seconds, ok := arguments["timeout"].(float64); ok && seconds > 0 {
+		params.Timeout = min(time.Duration(seconds*float64(time.Second)), maxAthenaTimeout)
+	}
+
+	if scanGB, ok := arguments["maxScanGB"].(float64); ok && scanGB > 0 {
+		// Callers may lower the configured limit but not raise it
+		requested := int64(scanGB * bytesPerGB)
+		if params.MaxBytesScanned == 0 || requested < params.MaxBytesScanned {
+			params.MaxBytesScanned = requested
+		}
+	}
+
+	result, err := h.awsClient.RunAthenaQuery(ctx, params)
+	switch {
+	case errors.Is(err, aws.ErrAthenaScanLimit):
+		return h.createErrorResponse(fmt.Sprintf("athena query %s was stopped after scanning %s, over the %s limit; filter on partition columns or select fewer columns",
+			result.QueryExecutionID, formatBytes(result.DataScannedBytes), formatBytes(params.MaxBytesScanned)))
+	case errors.Is(err, aws.ErrAthenaTimeout):
+		return h.createErrorResponse(fmt.Sprintf("athena query %s did not complete within %s and was stopped after scanning %s; narrow the query or raise timeout",
+			result.QueryExecutionID, params.Timeout, formatBytes(result.DataScannedBytes)))
+	case err != nil && strings.Contains(strings.ToLower(err.Error()), "output location"):
+		return h.createErrorResponse(fmt.Sprintf("failed to run Athena query: %v; set athena.output_location or pass outputLocation, or configure a result location on workgroup %s", err, params.Workgroup))
+	case err != nil:
+		return h.createErrorResponse(fmt.Sprintf("failed to run Athena query: %v", err))
+	}
+
+	data := map[string]interface{}{
+		"queryExecutionId":   result.QueryExecutionID,
+		"workgroup":          params.Workgroup,
+		"database":           params.Database,
+		"state":              result.State,
+		"columns":            result.Columns,
+		"count":              len(result.Rows),
+		"rows":               result.Rows,
+		"data_scanned_mb":    math.Round(float64(result.DataScannedBytes)/(1<<20)*100) / 100,
+		"estimated_cost_usd": athenaCost(result.DataScannedBytes, cfg.PricePerTB),
+		"execution_time_ms":  result.ExecutionTimeMs,
+		"output_location":    result.OutputLocation,
+	}
+	if result.Truncated {
+		data["truncated"] = true
+		data["note"] = fmt.Sprintf("Only the first %d rows are returned; aggregate in SQL or read the full result from output_location", params.MaxRows)
+	}
+
+	return h.createSuccessResponse(fmt.Sprintf("Athena query returned %d rows", len(result.Rows)), data)
+}
+
+// athenaQuery returns the parameters of the read-only query in the arguments,
+// with the configured defaults for what they leave out
+func athenaQuery(cfg config.AthenaConfig, arguments map[string]interface{}) (aws.AthenaQueryParams, error) {
+	params := athenaParams(cfg)
+
+	params.SQL, _ = arguments["sql"].(string)
+	params.SQL = strings.TrimSpace(params.SQL)
+	if params.SQL == "" {
+		return params, fmt.Errorf("sql is required, e.g. SELECT line_item_product_code, sum(line_item_unblended_cost) FROM cur GROUP BY 1")
+	}
+	if statement := firstSQLKeyword(params.SQL); !readOnlyAthenaStatements[statement] {
+		return params, fmt.Errorf("only read-only queries (SELECT, WITH, SHOW, DESCRIBE, EXPLAIN) are allowed, got %s", statement)
+	}
+	if err := checkSingleQuery(params.SQL); err != nil {
+		return params, err
+	}
+
+	if database, ok := arguments["database"].(string); ok && database != "" {
+		params.Database = database
+	}
+	if workgroup, ok := arguments["workgroup"].(string); ok && workgroup != "" {
+		params.Workgroup = workgroup
+	}
+	if location, ok := arguments["outputLocation"].(string); ok && location != "" {
+		params.OutputLocation = location
+	}
+	if params.OutputLocation != "" && !strings.HasPrefix(params.OutputLocation, "s3://") {
+		return params, fmt.Errorf("invalid outputLocation %q, use an S3 URI such as s3://my-athena-results/ai/", params.OutputLocation)
+	}
+	return params, nil
+}
+
+// startAthenaQuery starts a read-only query without waiting for it, for
+// queries that run longer than a tool call should. The caller polls it with
+// get-athena-query and reads its rows with get-athena-query-results.
+func (h *ToolHandler) startAthenaQuery(ctx context.Context, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	params, err := athenaQuery(h.config.Athena, arguments)
+	if err != nil {
+		return h.createErrorResponse(err.Error())
+	}
+
+	executionID, err := h.awsClient.StartAthenaQuery(ctx, params)
+	if err != nil {
+		if strings.Contains(strings.ToLower(err.Error()), "output location") {
+			return h.createErrorResponse(fmt.Sprintf("%v; set athena.output_location or pass outputLocation, or configure a result location on workgroup %s", err, params.Workgroup))
+		}
+		return h.createErrorResponse(err.Error())
+	}
+
+	data := map[string]interface{}{
+		"queryExecutionId": executionID,
+		"workgroup":        params.Workgroup,
+		"database":         params.Database,
+		"note":             "Poll get-athena-query until the state is SUCCEEDED, then read the rows with get-athena-query-results",
+	}
+	return h.createSuccessResponse("Athena query started", data)
+}
+
+// getAthenaQuery returns the state and scan size of a query. Queries over the
+// configured scan limit are stopped when polled, like run-athena-query does.
+func (h *ToolHandler) getAthenaQuery(ctx context.Context, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	executionID, _ := arguments["queryExecutionId"].(string)
+	if executionID == "" {
+		return h.createErrorResponse("queryExecutionId is required")
+	}
+	cfg := h.config.Athena
+	limit := athenaParams(cfg).MaxBytesScanned
+
+	result, err := h.awsClient.GetAthenaQuery(ctx, executionID, limit)
+	switch {
+	case errors.Is(err, aws.ErrAthenaScanLimit):
+		return h.createErrorResponse(fmt.Sprintf("athena query %s was stopped after scanning %s, over the %s limit; filter on partition columns or select fewer columns",
+			executionID, formatBytes(result.DataScannedBytes), formatBytes(limit)))
+	case err != nil:
+		return h.createErrorResponse(err.Error())
+	}
+
+	data := map[string]interface{}{
+		"queryExecutionId":   executionID,
+		"state":              result.State,
+		"done":               result.Done(),
+		"data_scanned_mb":    math.Round(float64(result.DataScannedBytes)/(1<<20)*100) / 100,
+		"estimated_cost_usd": athenaCost(result.DataScannedBytes, cfg.PricePerTB),
+		"execution_time_ms":  result.ExecutionTimeMs,
+	}
+	if result.StateReason != "" {
+		data["reason"] = result.StateReason
+	}
+	if result.OutputLocation != "" {
+		data["output_location"] = result.OutputLocation
+	}
+	return h.createSuccessResponse(fmt.Sprintf("Athena query is %s", result.State), data)
+}
+
+// getAthenaQueryResults returns the rows of a query that succeeded, capped at
+// athena.max_rows
+func (h *ToolHandler) getAthenaQueryResults(ctx context.Context, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	executionID, _ := arguments["queryExecutionId"].(string)
+	if executionID == "" {
+		return h.createErrorResponse("queryExecutionId is required")
+	}
+	cfg := h.config.Athena
+	maxRows := athenaParams(cfg).MaxRows
+	if requested, ok := arguments["maxRows"].(float64); ok && requested > 0 {
+		maxRows = int(min(requested, float64(maxRows)))
+	}
+
+	result, err := h.awsClient.GetAthenaQueryResults(ctx, executionID, maxRows)
+	if err != nil {
+		return h.createErrorResponse(err.Error())
+	}
+
+	data := map[string]interface{}{
+		"queryExecutionId":   executionID,
+		"state":              result.State,
+		"columns":            result.Columns,
+		"count":              len(result.Rows),
+		"rows":               result.Rows,
+		"data_scanned_mb":    math.Round(float64(result.DataScannedBytes)/(1<<20)*100) / 100,
+		"estimated_cost_usd": athenaCost(result.DataScannedBytes, cfg.PricePerTB),
+		"output_location":    result.OutputLocation,
+	}
+	if result.Truncated {
+		data["truncated"] = true
+		data["note"] = fmt.Sprintf("Only the first %d rows are returned; aggregate in SQL or read the full result from output_location", maxRows)
+	}
+	return h.createSuccessResponse(fmt.Sprintf("Athena query returned %d rows", len(result.Rows)), data)
+}
+
+// athenaParams returns query parameters with the configured defaults and limits
+func athenaParams(cfg config.AthenaConfig) aws.AthenaQueryParams {
+	params := aws.AthenaQueryParams{
+		Database:        cfg.Database,
+		Workgroup:       cfg.Workgroup,
+		OutputLocation:  cfg.OutputLocation,
+		MaxRows:         cfg.MaxRows,
+		MaxBytesScanned: athenaScanLimit(cfg.MaxScanGB, cfg.MaxCostUSD, cfg.PricePerTB),
+		Timeout:         cfg.Timeout,
+	}
+	if params.Workgroup == "" {
+		params.Workgroup = "primary"
+	}
+	if params.MaxRows <= 0 {
+		params.MaxRows = defaultAthenaMaxRows
+	}
+	if params.Timeout <= 0 {
+		params.Timeout = defaultAthenaTimeout
+	}
+	return params
+}
+
+// athenaScanLimit returns the bytes a query may scan under the configured
+// scan size and cost limits, whichever is lower. Zero means no limit.
+func athenaScanLimit(maxScanGB, maxCostUSD, pricePerTB float64) int64 {
+	var limit int64
+	if maxScanGB > 0 {
+		limit = int64(maxScanGB * bytesPerGB)
+	}
+	if maxCostUSD > 0 && pricePerTB > 0 {
+		costLimit := int64(maxCostUSD / pricePerTB * bytesPerTB)
+		if limit == 0 || costLimit < limit {
+			limit = costLimit
+		}
+	}
+	return limit
+}
+
+// athenaCost estimates what a query cost, with the per-query minimum of 10 MB
+func athenaCost(scannedBytes int64, pricePerTB float64) float64 {
+	billed := max(scannedBytes, athenaMinimumBilledBytes)
+	return math.Round(float64(billed)/bytesPerTB*pricePerTB*10000) / 10000
+}
+
+// firstSQLKeyword returns the first keyword of a statement in upper case,
+// skipping comments and opening parentheses
+func firstSQLKeyword(sql string) string {
+	for {
+		sql = strings.TrimLeft(sql, " \t\r\n(")
+		switch {
+		case strings.HasPrefix(sql, "--"):
+			_, sql, _ = strings.Cut(sql, "\n")
+		case strings.HasPrefix(sql, "/*"):
+			_, sql, _ = strings.Cut(sql, "*/")
+		default:
+			keyword, _, _ := strings.Cut(sql, " ")
+			keyword, _, _ = strings.Cut(keyword, "\n")
+			return strings.ToUpper(strings.TrimSpace(keyword))
+		}
+	}
+}
+
+// checkSingleQuery rejects what the first keyword of a read-only query does
+// not catch: a second statement after a semicolon, and SELECT ... INTO, which
+// creates a table. String literals, quoted identifiers and comments are
+// skipped. Function calls with side effects still pass, so the database
+// user or role the queries run as is what keeps them read-only.
+func checkSingleQuery(sql string) error {
+	ended := false
+	for i := 0; i < len(sql); {
+		c := sql[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\r' || c == '\n':
+			i++
+			continue
+		case strings.HasPrefix(sql[i:], "--"):
+			end := strings.IndexByte(sql[i:], '\n')
+			if end < 0 {
+				return nil
+			}
+			i += end + 1
+			continue
+		case strings.HasPrefix(sql[i:], "/*"):
+			end := strings.Index(sql[i+2:], "*/")
+			if end < 0 {
+				return nil
+			}
+			i += end + 4
+			continue
+		}
+
+		if ended {
+			return fmt.Errorf("only one statement is allowed per query")
+		}
+		switch {
+		case c == ';':
+			ended = true
+			i++
+		case c == '\'' || c == '"' || c == '`':
+			// A doubled quote is an escaped one inside the literal
+			i++
+			for i < len(sql) {
+				if sql[i] == c {
+					if i+1 < len(sql) && sql[i+1] == c {
+						i += 2
+						continue
+					}
+					break
+				}
+				i++
+			}
+			i++
+		case isSQLWordByte(c):
+			start := i
+			for i < len(sql) && isSQLWordByte(sql[i]) {
+				i++
+			}
+			if strings.EqualFold(sql[start:i], "INTO") {
+				return fmt.Errorf("SELECT ... INTO writes a table; only read-only queries are allowed")
+			}
+		default:
+			i++
+		}
+	}
+	return nil
+}
+
+// isSQLWordByte reports whether c may be part of an SQL keyword or name
+func isSQLWordByte(c byte) bool {
+	return c == '_' || c == '$' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= 0x80
+}
+
+// formatBytes renders a byte count in the largest whole unit, e.g. 1.5 GB
+func formatBytes(bytes int64) string {
+	const unit = 1024
+	if bytes < unit {
+		return fmt.Sprintf("%d B", bytes)
+	}
+	value, exponent := float64(bytes), 0
+	for value >= unit && exponent < 4 {
+		value /= unit
+		exponent++
+	}
+	return fmt.Sprintf("%.1f %cB", value, " KMGT"[exponent])
+}
@@ -0,0 +1,97 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	"aws-mcp-server/internal/config"
+	"aws-mcp-server/internal/logging"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFirstSQLKeyword(t *testing.T) {
+	assert.Equal(t, "SELECT", firstSQLKeyword("select * from cur"))
+	assert.Equal(t, "WITH", firstSQLKeyword("  (with t as (select 1) select * from t)"))
+	assert.Equal(t, "SELECT", firstSQLKeyword("-- daily cost\n/* by service */ SELECT 1"))
+	assert.Equal(t, "DROP", firstSQLKeyword("DROP TABLE cur"))
+	assert.Equal(t, "SHOW", firstSQLKeyword("SHOW\nTABLES"))
+}
+
+func TestCheckSingleQuery(t *testing.T) {
+	assert.NoError(t, checkSingleQuery("SELECT 1;"))
+	assert.NoError(t, checkSingleQuery("SELECT 1; -- done\n"))
+	assert.NoError(t, checkSingleQuery("SELECT 'a;b', \"into\" FROM t -- select into"))
+	assert.NoError(t, checkSingleQuery("SELECT 'it''s; into' FROM `into` /* ; */"))
+	assert.NoError(t, checkSingleQuery("SELECT intotal, point_into FROM t"))
+
+	assert.ErrorContains(t, checkSingleQuery("SELECT 1; DROP TABLE cur"), "only one statement")
+	assert.ErrorContains(t, checkSingleQuery("SELECT 1;;"), "only one statement")
+	assert.ErrorContains(t, checkSingleQuery("SELECT * INTO new_table FROM orders"), "SELECT ... INTO writes a table")
+	assert.ErrorContains(t, checkSingleQuery("select *\ninto temp copy from orders"), "SELECT ... INTO writes a table")
+}
+
+func TestAthenaScanLimit(t *testing.T) {
+	assert.Equal(t, int64(0), athenaScanLimit(0, 0, 5))
+	assert.Equal(t, int64(10*bytesPerGB), athenaScanLimit(10, 0, 5))
+	// $0.05 at $5 per TB is 10 GB
+	assert.Equal(t, int64(bytesPerTB/100), athenaScanLimit(0, 0.05, 5))
+	assert.Equal(t, int64(bytesPerTB/100), athenaScanLimit(100, 0.05, 5))
+	assert.Equal(t, int64(bytesPerGB), athenaScanLimit(1, 0.05, 5))
+}
+
+func TestAthenaCost(t *testing.T) {
+	assert.Equal(t, 5.0, athenaCost(bytesPerTB, 5))
+	// Small queries are billed for 10 MB
+	assert.Equal(t, athenaCost(athenaMinimumBilledBytes, 5), athenaCost(1024, 5))
+	assert.Equal(t, "1.5 GB", formatBytes(3*bytesPerGB/2))
+	assert.Equal(t, "512 B", formatBytes(512))
+}
+
+func TestRunAthenaQueryValidatesArguments(t *testing.T) {
+	h := NewToolHandler(&config.Config{}, nil, logging.NewLogger("error", "text"))
+	ctx := context.Background()
+
+	result, err := h.CallTool(ctx, "run-athena-query", map[string]interface{}{})
+	require.NoError(t, err)
+	assert.Contains(t, decodeToolResult(t, result)["error"], "sql is required")
+
+	result, err = h.CallTool(ctx, "run-athena-query", map[string]interface{}{"sql": "DROP TABLE cur"})
+	require.NoError(t, err)
+	assert.Contains(t, decodeToolResult(t, result)["error"], "only read-only queries")
+
+	result, err = h.CallTool(ctx, "run-athena-query", map[string]interface{}{
+		"sql":            "SELECT 1",
+		"outputLocation": "my-bucket/results",
+	})
+	require.NoError(t, err)
+	assert.Contains(t, decodeToolResult(t, result)["error"], "invalid outputLocation")
+}
+
+func TestAsyncAthenaToolsValidateArguments(t *testing.T) {
+	h := NewToolHandler(&config.Config{}, nil, logging.NewLogger("error", "text"))
+	ctx := context.Background()
+
+	result, err := h.CallTool(ctx, "start-athena-query", map[string]interface{}{"sql": "INSERT INTO cur VALUES (1)"})
+	require.NoError(t, err)
+	assert.Contains(t, decodeToolResult(t, result)["error"], "only read-only queries")
+
+	for _, tool := range []string{"get-athena-query", "get-athena-query-results"} {
+		result, err = h.CallTool(ctx, tool, map[string]interface{}{})
+		require.NoError(t, err)
+		assert.Equal(t, "queryExecutionId is required", decodeToolResult(t, result)["error"], tool)
+	}
+}
+
+func TestAthenaQueryDefaults(t *testing.T) {
+	params, err := athenaQuery(config.AthenaConfig{Database: "cur", MaxScanGB: 10}, map[string]interface{}{
+		"sql":       "  SELECT 1 ",
+		"workgroup": "analysts",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT 1", params.SQL)
+	assert.Equal(t, "cur", params.Database)
+	assert.Equal(t, "analysts", params.Workgroup)
+	assert.Equal(t, int64(10*bytesPerGB), params.MaxBytesScanned)
+}
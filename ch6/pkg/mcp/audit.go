@@ -0,0 +1,54 @@
+package mcp
+
+import (
+	"context"
+
+	"aws-mcp-server/pkg/audit"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// decisionTools are audited alongside mutating tools because they release or
+// drop queued changes, silence alerts, discard learned baselines or run
+// queries that scan data at a cost
+var decisionTools = map[string]bool{
+	"approve-action":        true,
+	"reject-action":         true,
+	"acknowledge-alert":     true,
+	"snooze-alert":          true,
+	"suppress-alert":        true,
+	"unsuppress-alert":      true,
+	"reset-metric-baseline": true,
+	"add-note":              true,
+	"remove-note":           true,
+	"ignore-resource":       true,
+	"unignore-resource":     true,
+	"request-elevation":     true,
+	"end-elevation":         true,
+	"start-athena-query":    true,
+	"run-athena-query":      true,
+	"start-redshift-query":  true,
+}
+
+// recordAudit appends mutating calls and approval decisions to the audit log,
+// whether or not they succeeded
+func (h *ToolHandler) recordAudit(ctx context.Context, name string, arguments map[string]interface{}, result *mcp.CallToolResult) {
+	if !isMutating(name, arguments) && !decisionTools[name] {
+		return
+	}
+
+	success, message := resultStatus(result)
+	grant, _ := h.elevationOf(ctx)
+	err := h.audit.Record(audit.Entry{
+		Tool:         name,
+		Arguments:    arguments,
+		Role:         h.callerRole(ctx),
+		Success:      success,
+		Message:      message,
+		ChangeTicket: changeTicket(arguments),
+		Elevation:    grant.ID,
+	})
+	if err != nil {
+		h.logger.WithError(err).WithField("tool", name).Error("Failed to write audit entry")
+	}
+}
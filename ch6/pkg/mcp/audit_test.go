@@ -0,0 +1,87 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"aws-mcp-server/internal/config"
+	"aws-mcp-server/internal/logging"
+	"aws-mcp-server/pkg/audit"
+	"aws-mcp-server/pkg/aws"
+
+	sdkaws "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCallToolRecordsAudit(t *testing.T) {
+	cfg := &config.Config{Access: config.AccessConfig{Role: "admin", AdminRoles: []string{"admin"}}}
+	handler := NewToolHandler(cfg, nil, logging.NewLogger("error", "text"))
+	handler.audit, _ = audit.Open("", 0)
+
+	ctx := context.Background()
+	_, err := handler.CallTool(ctx, "reject-action", map[string]interface{}{"requestId": "missing"})
+	require.NoError(t, err)
+	_, err = handler.CallTool(ctx, "query-logs", map[string]interface{}{})
+	require.NoError(t, err)
+
+	entries := handler.audit.Between(time.Now().Add(-time.Minute), time.Now())
+	require.Len(t, entries, 1, "read-only tools are not audited")
+	assert.Equal(t, "reject-action", entries[0].Tool)
+	assert.Equal(t, "admin", entries[0].Role)
+	assert.False(t, entries[0].Success)
+	assert.NotEmpty(t, entries[0].Message)
+}
+
+func TestQueryToolsAreAudited(t *testing.T) {
+	// Athena and the Redshift Data API start every query they are sent
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/x-amz-json-1.1")
+		switch target := r.Header.Get("X-Amz-Target"); {
+		case strings.HasSuffix(target, ".StartQueryExecution"):
+			fmt.Fprint(w, `{"QueryExecutionId": "athena-1"}`)
+		case strings.HasSuffix(target, ".ExecuteStatement"):
+			fmt.Fprint(w, `{"Id": "redshift-1"}`)
+		default:
+			http.Error(w, "unexpected call "+target, http.StatusBadRequest)
+		}
+	}))
+	defer server.Close()
+
+	logger := logging.NewLogger("error", "text")
+	cfg := &config.Config{
+		Access:   config.AccessConfig{Role: "admin", AdminRoles: []string{"admin"}},
+		Redshift: config.RedshiftConfig{ClusterID: "analytics", Database: "dev", DBUser: "ai"},
+	}
+	client := aws.NewClientFromConfig(sdkaws.Config{
+		Region:       "us-east-1",
+		Credentials:  sdkaws.AnonymousCredentials{},
+		BaseEndpoint: sdkaws.String(server.URL),
+	}, logger)
+	handler := NewToolHandler(cfg, client, logger)
+	handler.audit, _ = audit.Open("", 0)
+
+	ctx := context.Background()
+	_, err := handler.CallTool(ctx, "start-athena-query", map[string]interface{}{"sql": "SELECT * FROM cur", "outputLocation": "s3://results/"})
+	require.NoError(t, err)
+	_, err = handler.CallTool(ctx, "run-athena-query", map[string]interface{}{"sql": "DROP TABLE cur"})
+	require.NoError(t, err)
+	_, err = handler.CallTool(ctx, "start-redshift-query", map[string]interface{}{"sql": "SELECT count(*) FROM orders"})
+	require.NoError(t, err)
+
+	entries := handler.audit.Between(time.Now().Add(-time.Minute), time.Now())
+	require.Len(t, entries, 3)
+	assert.Equal(t, "start-athena-query", entries[0].Tool)
+	assert.True(t, entries[0].Success)
+	assert.Equal(t, "SELECT * FROM cur", entries[0].Arguments["sql"])
+	assert.Equal(t, "run-athena-query", entries[1].Tool)
+	assert.False(t, entries[1].Success, "refused queries are audited too")
+	assert.Equal(t, "start-redshift-query", entries[2].Tool)
+	assert.True(t, entries[2].Success)
+	assert.Equal(t, "admin", entries[2].Role)
+}
@@ -0,0 +1,92 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"aws-mcp-server/pkg/azure"
+	"aws-mcp-server/pkg/types"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// azureResourceGroupsURI lists Azure resource groups with their VMs
+const azureResourceGroupsURI = "azure://vm/resource-groups"
+
+// readAzureResourceGroups returns the resource groups of the subscription with
+// VM counts by power state
+func (h *ResourceHandler) readAzureResourceGroups(ctx context.Context) (*mcp.ReadResourceResult, error) {
+	if h.azure == nil {
+		return nil, fmt.Errorf("Azure is not configured, set azure.subscription_id")
+	}
+
+	groups, err := h.azure.ListResourceGroups(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list Azure resource groups: %w", err)
+	}
+	vms, err := h.azure.ListInstances(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list Azure VMs: %w", err)
+	}
+
+	jsonData, err := json.MarshalIndent(formatResourceGroups(groups, vms), "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal resource groups data: %w", err)
+	}
+
+	return &mcp.ReadResourceResult{
+		Contents: []mcp.ResourceContents{
+			&mcp.TextResourceContents{
+				URI:      azureResourceGroupsURI,
+				MIMEType: "application/json",
+				Text:     string(jsonData),
+			},
+		},
+	}, nil
+}
+
+// formatResourceGroups formats resource groups for AI processing with the VMs
+// of each group counted by power state
+func formatResourceGroups(groups []azure.ResourceGroup, vms []types.CloudResource) map[string]interface{} {
+	vmsByGroup := make(map[string][]types.CloudResource)
+	for _, vm := range vms {
+		group := vm.AzureVM().ResourceGroup
+		// Resource group names are case-insensitive and IDs do not keep their case
+		vmsByGroup[strings.ToLower(group)] = append(vmsByGroup[strings.ToLower(group)], vm)
+	}
+
+	formatted := make([]map[string]interface{}, 0, len(groups))
+	for _, group := range groups {
+		members := vmsByGroup[strings.ToLower(group.Name)]
+
+		stateCount := make(map[string]int)
+		names := make([]string, 0, len(members))
+		for _, vm := range members {
+			stateCount[vm.State]++
+			names = append(names, vm.ID)
+		}
+
+		entry := map[string]interface{}{
+			"name":     group.Name,
+			"location": group.Location,
+			"state":    group.ProvisioningState,
+			"vm_count": len(members),
+		}
+		if len(members) > 0 {
+			entry["vms"] = names
+			entry["vms_by_state"] = stateCount
+		}
+		if len(group.Tags) > 0 {
+			entry["tags"] = group.Tags
+		}
+		formatted = append(formatted, entry)
+	}
+
+	return map[string]interface{}{
+		"total_resource_groups": len(groups),
+		"total_vms":             len(vms),
+		"resource_groups":       formatted,
+	}
+}
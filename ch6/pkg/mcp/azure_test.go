@@ -0,0 +1,37 @@
+package mcp
+
+import (
+	"testing"
+
+	"aws-mcp-server/pkg/azure"
+	"aws-mcp-server/pkg/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatResourceGroups(t *testing.T) {
+	groups := []azure.ResourceGroup{
+		{Name: "batch-rg", Location: "westeurope", ProvisioningState: "Succeeded"},
+		{Name: "web-rg", Location: "westeurope", ProvisioningState: "Succeeded", Tags: map[string]string{"Owner": "web-team"}},
+	}
+	vms := []types.CloudResource{
+		{ID: "web-1", State: "running", Details: &types.AzureVMDetails{ResourceGroup: "WEB-RG"}},
+		{ID: "web-2", State: "deallocated", Details: &types.AzureVMDetails{ResourceGroup: "web-rg"}},
+	}
+
+	formatted := formatResourceGroups(groups, vms)
+
+	assert.Equal(t, 2, formatted["total_resource_groups"])
+	assert.Equal(t, 2, formatted["total_vms"])
+
+	items := formatted["resource_groups"].([]map[string]interface{})
+	require.Len(t, items, 2)
+	assert.Equal(t, 0, items[0]["vm_count"])
+	assert.NotContains(t, items[0], "vms")
+
+	assert.Equal(t, 2, items[1]["vm_count"], "resource group names are case-insensitive")
+	assert.Equal(t, []string{"web-1", "web-2"}, items[1]["vms"])
+	assert.Equal(t, map[string]int{"running": 1, "deallocated": 1}, items[1]["vms_by_state"])
+	assert.Equal(t, map[string]string{"Owner": "web-team"}, items[1]["tags"])
+}
@@ -0,0 +1,257 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"aws-mcp-server/pkg/aws"
+	"aws-mcp-server/pkg/types"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const (
+	// backupJobsURI lists the recent AWS Backup jobs of the region
+	backupJobsURI = "aws://backup/jobs"
+	// backupResourceTemplate is the URI template of the backups of one
+	// resource by its ARN
+	backupResourceTemplate = "aws://backup/resources/{+resourceArn}"
+	// defaultBackupLookback is how far back backup jobs are listed when
+	// backup.job_lookback is not set
+	defaultBackupLookback = 7 * 24 * time.Hour
+)
+
+// backupProblemStates are the job states that leave no usable recovery point
+var backupProblemStates = map[string]bool{"FAILED": true, "ABORTED": true, "EXPIRED": true, "PARTIAL": true}
+
+// backupActiveStates are the job states of a backup still in progress
+var backupActiveStates = map[string]bool{"CREATED": true, "PENDING": true, "RUNNING": true}
+
+// backupLookback is how far back backup jobs are listed
+func backupLookback(lookback time.Duration) time.Duration {
+	if lookback <= 0 {
+		return defaultBackupLookback
+	}
+	return lookback
+}
+
+// readBackupJobs lists the recent backup jobs, failed ones first, with counts
+// by state
+func (h *ResourceHandler) readBackupJobs(ctx context.Context) (*mcp.ReadResourceResult, error) {
+	lookback := backupLookback(h.config.Backup.JobLookback)
+	jobs, err := h.awsClient.ListBackupJobs(ctx, time.Now().Add(-lookback), "")
+	if err != nil {
+		return nil, err
+	}
+
+	jsonData, err := json.MarshalIndent(formatBackupJobs(jobs, lookback), "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal backup jobs data: %w", err)
+	}
+
+	return &mcp.ReadResourceResult{
+		Contents: []mcp.ResourceContents{
+			&mcp.TextResourceContents{
+				URI:      backupJobsURI,
+				MIMEType: "application/json",
+				Text:     string(jsonData),
+			},
+		},
+	}, nil
+}
+
+// readBackupResource returns the recent backup jobs and the recovery points
+// of the resource in the URI
+func (h *ResourceHandler) readBackupResource(ctx context.Context, req resourceRequest) (*mcp.ReadResourceResult, error) {
+	uri, arn := req.URI, req.Param("resourceArn")
+	if !strings.HasPrefix(arn, "arn:") {
+		return nil, fmt.Errorf("invalid backup resource URI %s, use %s with a resource ARN", uri, backupResourceTemplate)
+	}
+
+	lookback := backupLookback(h.config.Backup.JobLookback)
+	jobs, err := h.awsClient.ListBackupJobs(ctx, time.Now().Add(-lookback), arn)
+	if err != nil {
+		return nil, err
+	}
+	points, err := h.awsClient.ListRecoveryPoints(ctx, arn)
+	if err != nil {
+		return nil, err
+	}
+
+	jsonData, err := json.MarshalIndent(formatBackupResource(arn, jobs, points, time.Now()), "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal backup resource data: %w", err)
+	}
+
+	return &mcp.ReadResourceResult{
+		Contents: []mcp.ResourceContents{
+			&mcp.TextResourceContents{
+				URI:      uri,
+				MIMEType: "application/json",
+				Text:     string(jsonData),
+			},
+		},
+	}, nil
+}
+
+// formatBackupJobs orders the jobs failed ones first, then newest first, and
+// counts them by state
+func formatBackupJobs(jobs []types.BackupJob, lookback time.Duration) map[string]interface{} {
+	sorted := sortBackupJobs(jobs)
+
+	byState := make(map[string]int)
+	failed := []string{}
+	seen := make(map[string]bool)
+	for _, job := range sorted {
+		byState[job.State]++
+		name := backupResourceName(job)
+		if backupProblemStates[job.State] && !seen[name] {
+			seen[name] = true
+			failed = append(failed, name)
+		}
+	}
+
+	return map[string]interface{}{
+		"jobs":             sorted,
+		"total":            len(jobs),
+		"lookback_hours":   lookback.Hours(),
+		"summary_by_state": byState,
+		"failed_resources": failed,
+	}
+}
+
+// formatBackupResource lists the backups of one resource, newest first, with
+// the latest usable recovery point and any backup still running
+func formatBackupResource(arn string, jobs []types.BackupJob, points []types.RecoveryPoint, now time.Time) map[string]interface{} {
+	sortedPoints := append([]types.RecoveryPoint{}, points...)
+	sort.SliceStable(sortedPoints, func(i, j int) bool {
+		return sortedPoints[i].Created.After(sortedPoints[j].Created)
+	})
+
+	data := map[string]interface{}{
+		"resource_arn":    arn,
+		"recent_jobs":     sortBackupJobs(jobs),
+		"recovery_points": sortedPoints,
+	}
+	for _, point := range sortedPoints {
+		if point.Status == "COMPLETED" || point.Status == "AVAILABLE" {
+			data["latest_recovery_point"] = point
+			data["latest_recovery_point_age_hours"] = now.Sub(point.Created).Hours()
+			break
+		}
+	}
+	if _, ok := data["latest_recovery_point"]; !ok {
+		data["note"] = "No usable recovery point; run start-on-demand-backup before changing this resource"
+	}
+	for _, job := range jobs {
+		if backupActiveStates[job.State] {
+			data["running_job"] = job
+			break
+		}
+	}
+	return data
+}
+
+// sortBackupJobs orders jobs that failed first, then newest first
+func sortBackupJobs(jobs []types.BackupJob) []types.BackupJob {
+	sorted := append([]types.BackupJob{}, jobs...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		iProblem, jProblem := backupProblemStates[sorted[i].State], backupProblemStates[sorted[j].State]
+		if iProblem != jProblem {
+			return iProblem
+		}
+		return sorted[i].Created.After(sorted[j].Created)
+	})
+	return sorted
+}
+
+// backupResourceName is the name of a job's resource, its ARN when it has none
+func backupResourceName(job types.BackupJob) string {
+	if job.ResourceName != "" {
+		return job.ResourceName
+	}
+	return job.ResourceARN
+}
+
+// startOnDemandBackup backs up a resource with AWS Backup, e.g. right before
+// changing it, so it can be restored if the change goes wrong
+func (h *ToolHandler) startOnDemandBackup(ctx context.Context, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	params, err := backupJobParams(h.config.Backup.VaultName, h.config.Backup.RoleARN, arguments)
+	if err != nil {
+		return h.createErrorResponse(err.Error())
+	}
+
+	var warnings []string
+	jobs, err := h.awsClient.ListBackupJobs(ctx, time.Now().Add(-backupLookback(h.config.Backup.JobLookback)), params.ResourceARN)
+	if err != nil {
+		h.logger.WithError(err).WithField("resourceArn", params.ResourceARN).Warn("Failed to check for running backup jobs")
+	}
+	for _, job := range jobs {
+		if backupActiveStates[job.State] {
+			warnings = append(warnings, fmt.Sprintf("Backup job %s of this resource is already %s", job.ID, strings.ToLower(job.State)))
+			break
+		}
+	}
+	if params.DeleteAfterDays == 0 {
+		warnings = append(warnings, "The recovery point is kept, and billed, until it is deleted; set deleteAfterDays to expire it")
+	}
+
+	job, err := h.awsClient.StartBackupJob(ctx, params)
+	if err != nil {
+		return h.createErrorResponse(err.Error())
+	}
+
+	data := map[string]interface{}{
+		"backupJobId":      job.ID,
+		"resourceArn":      job.ResourceARN,
+		"backupVaultName":  job.VaultName,
+		"state":            job.State,
+		"recoveryPointArn": job.RecoveryPointARN,
+		"note":             fmt.Sprintf("Read aws://backup/resources/%s to follow the backup job", job.ResourceARN),
+	}
+	if params.DeleteAfterDays > 0 {
+		data["deleteAfterDays"] = params.DeleteAfterDays
+	}
+	return h.createSuccessResponse("On-demand backup started successfully", data, warnings...)
+}
+
+// backupJobParams reads the on-demand backup arguments, with the vault and
+// role defaulting to the configured ones
+func backupJobParams(vault, role string, arguments map[string]interface{}) (aws.BackupJobParams, error) {
+	arn, _ := arguments["resourceArn"].(string)
+	if arn == "" {
+		return aws.BackupJobParams{}, fmt.Errorf("resourceArn is required")
+	}
+	if !strings.HasPrefix(arn, "arn:") {
+		return aws.BackupJobParams{}, fmt.Errorf("resourceArn must be an ARN, got %q", arn)
+	}
+
+	if name, _ := arguments["backupVaultName"].(string); name != "" {
+		vault = name
+	}
+	if vault == "" {
+		return aws.BackupJobParams{}, fmt.Errorf("backupVaultName is required when backup.vault_name is not configured")
+	}
+	if arn, _ := arguments["iamRoleArn"].(string); arn != "" {
+		role = arn
+	}
+	if role == "" {
+		return aws.BackupJobParams{}, fmt.Errorf("iamRoleArn is required when backup.role_arn is not configured, e.g. the ARN of the AWSBackupDefaultServiceRole")
+	}
+
+	days, _ := arguments["deleteAfterDays"].(float64)
+	if days < 0 || days != float64(int64(days)) {
+		return aws.BackupJobParams{}, fmt.Errorf("deleteAfterDays must be a whole number of days")
+	}
+
+	return aws.BackupJobParams{
+		ResourceARN:     arn,
+		VaultName:       vault,
+		RoleARN:         role,
+		DeleteAfterDays: int64(days),
+	}, nil
+}
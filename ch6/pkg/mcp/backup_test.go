@@ -0,0 +1,126 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"aws-mcp-server/internal/config"
+	"aws-mcp-server/internal/logging"
+	"aws-mcp-server/pkg/render"
+	"aws-mcp-server/pkg/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatBackupJobs(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	data := formatBackupJobs([]types.BackupJob{
+		{ID: "1", ResourceName: "orders-db", State: "COMPLETED", Created: now.Add(-2 * time.Hour)},
+		{ID: "2", ResourceName: "uploads", State: "FAILED", Created: now.Add(-48 * time.Hour)},
+		{ID: "3", ResourceName: "orders-db", State: "RUNNING", Created: now.Add(-time.Hour)},
+		{ID: "4", ResourceARN: "arn:aws:ec2:us-east-1:123456789012:volume/vol-1", State: "EXPIRED", Created: now.Add(-3 * time.Hour)},
+		{ID: "5", ResourceName: "uploads", State: "FAILED", Created: now.Add(-24 * time.Hour)},
+	}, 7*24*time.Hour)
+
+	assert.Equal(t, 5, data["total"])
+	assert.Equal(t, map[string]int{"COMPLETED": 1, "FAILED": 2, "RUNNING": 1, "EXPIRED": 1}, data["summary_by_state"])
+	assert.Equal(t, []string{"arn:aws:ec2:us-east-1:123456789012:volume/vol-1", "uploads"}, data["failed_resources"],
+		"failed resources are listed once, most recent failure first")
+
+	jobs := data["jobs"].([]types.BackupJob)
+	var ids []string
+	for _, job := range jobs {
+		ids = append(ids, job.ID)
+	}
+	assert.Equal(t, []string{"4", "5", "2", "3", "1"}, ids, "failed jobs come first, each group newest first")
+
+	renderer, err := render.New(nil)
+	require.NoError(t, err)
+	payload, err := json.Marshal(data)
+	require.NoError(t, err)
+	summary, ok := renderer.RenderJSON(backupJobsURI, payload, nil)
+	require.True(t, ok)
+	assert.Equal(t, "5 backup jobs in the last 168h: 1 COMPLETED 1 EXPIRED 2 FAILED 1 RUNNING; failed: arn:aws:ec2:us-east-1:123456789012:volume/vol-1, uploads", summary)
+}
+
+func TestFormatBackupResource(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	arn := "arn:aws:rds:us-east-1:123456789012:db:orders"
+
+	data := formatBackupResource(arn, []types.BackupJob{
+		{ID: "done", State: "COMPLETED", Created: now.Add(-26 * time.Hour)},
+		{ID: "now", State: "RUNNING", Created: now.Add(-10 * time.Minute)},
+	}, []types.RecoveryPoint{
+		{ARN: "old", Status: "COMPLETED", Created: now.Add(-50 * time.Hour)},
+		{ARN: "creating", Status: "CREATING", Created: now.Add(-10 * time.Minute)},
+		{ARN: "latest", Status: "COMPLETED", Created: now.Add(-25 * time.Hour)},
+	}, now)
+
+	assert.Equal(t, "latest", data["latest_recovery_point"].(types.RecoveryPoint).ARN, "recovery points still being created are not usable")
+	assert.Equal(t, 25.0, data["latest_recovery_point_age_hours"])
+	assert.Equal(t, "now", data["running_job"].(types.BackupJob).ID)
+	assert.NotContains(t, data, "note")
+
+	renderer, err := render.New(nil)
+	require.NoError(t, err)
+	payload, err := json.Marshal(data)
+	require.NoError(t, err)
+	summary, ok := renderer.RenderJSON(backupResourceTemplate, payload, nil)
+	require.True(t, ok)
+	assert.Equal(t, "3 recovery points of "+arn+", the latest 25h old; backup job now RUNNING", summary)
+
+	data = formatBackupResource(arn, nil, nil, now)
+	assert.Contains(t, data["note"], "start-on-demand-backup")
+	assert.NotContains(t, data, "running_job")
+}
+
+func TestBackupJobParams(t *testing.T) {
+	arn := "arn:aws:dynamodb:us-east-1:123456789012:table/orders"
+	role := "arn:aws:iam::123456789012:role/service-role/AWSBackupDefaultServiceRole"
+
+	params, err := backupJobParams("Default", role, map[string]interface{}{"resourceArn": arn, "deleteAfterDays": float64(14)})
+	require.NoError(t, err)
+	assert.Equal(t, "Default", params.VaultName)
+	assert.Equal(t, role, params.RoleARN)
+	assert.Equal(t, int64(14), params.DeleteAfterDays)
+
+	params, err = backupJobParams("Default", role, map[string]interface{}{"resourceArn": arn, "backupVaultName": "pre-change"})
+	require.NoError(t, err)
+	assert.Equal(t, "pre-change", params.VaultName, "the argument overrides the configured vault")
+
+	tests := []struct {
+		name      string
+		vault     string
+		role      string
+		arguments map[string]interface{}
+		wantErr   string
+	}{
+		{"missing resource", "Default", role, map[string]interface{}{}, "resourceArn is required"},
+		{"not an ARN", "Default", role, map[string]interface{}{"resourceArn": "vol-1"}, "must be an ARN"},
+		{"no vault", "", role, map[string]interface{}{"resourceArn": arn}, "backupVaultName is required"},
+		{"no role", "Default", "", map[string]interface{}{"resourceArn": arn}, "iamRoleArn is required"},
+		{"negative retention", "Default", role, map[string]interface{}{"resourceArn": arn, "deleteAfterDays": float64(-1)}, "whole number"},
+		{"fractional retention", "Default", role, map[string]interface{}{"resourceArn": arn, "deleteAfterDays": 1.5}, "whole number"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := backupJobParams(tt.vault, tt.role, tt.arguments)
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestStartOnDemandBackupValidatesBeforeCallingAWS(t *testing.T) {
+	h := NewToolHandler(&config.Config{}, nil, logging.NewLogger("error", "text"))
+
+	result, err := h.startOnDemandBackup(context.Background(), map[string]interface{}{
+		"resourceArn": "arn:aws:ec2:us-east-1:123456789012:volume/vol-1",
+	})
+	require.NoError(t, err)
+	response := decodeToolResult(t, result)
+	assert.Equal(t, false, response["success"])
+	assert.Contains(t, response["error"], "backupVaultName is required")
+}
@@ -0,0 +1,238 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"aws-mcp-server/pkg/aws"
+	"aws-mcp-server/pkg/baseline"
+	"aws-mcp-server/pkg/types"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const (
+	// defaultAnomalyThreshold is how many standard deviations from the
+	// baseline a point must be to be flagged when no threshold is configured
+	defaultAnomalyThreshold = 3.0
+	// maxReportedAnomalies keeps responses short when a whole window is off
+	maxReportedAnomalies = 20
+)
+
+// weekdays names the days of a baseline's weekly profile, Monday first
+var weekdays = []string{"monday", "tuesday", "wednesday", "thursday", "friday", "saturday", "sunday"}
+
+// detectAnomalies scores datapoints against the metric's learned baseline and
+// then learns the new ones, so the baseline keeps up with the resource. The
+// period still in progress at now is scored but not learned, since its value
+// is not final yet.
+func (h *ToolHandler) detectAnomalies(params aws.MetricDataParams, datapoints []types.MetricDatapoint, now time.Time) map[string]interface{} {
+	resource := baselineResource(params.Namespace, params.Dimensions)
+	metric := baselineMetric(params)
+	period := time.Duration(params.Period) * time.Second
+
+	points := make([]baseline.Point, 0, len(datapoints))
+	complete := make([]baseline.Point, 0, len(datapoints))
+	for _, datapoint := range datapoints {
+		point := baseline.Point{Time: datapoint.Timestamp, Value: datapoint.Value}
+		points = append(points, point)
+		if !datapoint.Timestamp.Add(period).After(now) {
+			complete = append(complete, point)
+		}
+	}
+
+	threshold := h.config.Overrides.Float("baselines.threshold", h.config.Baselines.Threshold)
+	if threshold <= 0 {
+		threshold = defaultAnomalyThreshold
+	}
+
+	anomalies, learnedBefore, trained := h.baselines.Detect(resource, metric, points, threshold)
+	learned, err := h.baselines.Learn(resource, metric, complete)
+	if err != nil {
+		h.logger.WithError(err).WithField("metric", metric).Error("Failed to save metric baselines")
+	}
+
+	result := map[string]interface{}{
+		"resource": resource,
+		"metric":   metric,
+		"samples":  learnedBefore.Overall.Count + learned,
+		"learned":  learned,
+		"trained":  trained,
+	}
+	// Ignored resources keep their baseline current for when they are back
+	if entry, ok := h.ignored.Any(dimensionValues(params.Dimensions), now); ok {
+		result["ignored"] = formatIgnored(entry, h.times.Format)
+		result["note"] = "The resource is ignored, so its datapoints are learned but not scored; unignore-resource brings the anomalies back"
+		return result
+	}
+	if !trained {
+		result["note"] = fmt.Sprintf("The baseline is still learning (%d of %d samples); anomalies are flagged once it is trained",
+			learnedBefore.Overall.Count+learned, baseline.MinSamples)
+		return result
+	}
+
+	h.exporter.RecordAnomalies(resource, metric, anomalies, now)
+	result["threshold"] = threshold
+	result["anomaly_count"] = len(anomalies)
+	sort.SliceStable(anomalies, func(i, j int) bool {
+		return math.Abs(anomalies[i].Z) > math.Abs(anomalies[j].Z)
+	})
+	if len(anomalies) > maxReportedAnomalies {
+		anomalies = anomalies[:maxReportedAnomalies]
+		result["truncated"] = true
+	}
+
+	items := make([]map[string]interface{}, 0, len(anomalies))
+	for _, anomaly := range anomalies {
+		items = append(items, map[string]interface{}{
+			"time":     h.times.Format(anomaly.Time),
+			"value":    anomaly.Value,
+			"expected": roundBaseline(anomaly.Expected),
+			"z":        roundBaseline(anomaly.Z),
+			"seasonal": anomaly.Seasonal,
+		})
+	}
+	result["anomalies"] = items
+
+	return result
+}
+
+// getMetricBaseline shows what the anomaly detector has learned about a resource
+func (h *ToolHandler) getMetricBaseline(ctx context.Context, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	resource, _ := arguments["resource"].(string)
+	if resource == "" {
+		return h.createErrorResponse("resource is required, e.g. i-0abc123 or InstanceId=i-0abc123")
+	}
+	metric, _ := arguments["metric"].(string)
+
+	found := h.baselines.Find(resource, metric)
+	if len(found) == 0 {
+		return h.createErrorResponse(fmt.Sprintf("no baseline learned for %s; baselines are learned from get-cloudwatch-metrics", resource))
+	}
+
+	items := make([]map[string]interface{}, 0, len(found))
+	for _, b := range found {
+		items = append(items, formatBaseline(b, h.times.Format))
+	}
+
+	return h.createSuccessResponse(fmt.Sprintf("Found %d baselines for %s", len(items), resource), map[string]interface{}{
+		"resource":  resource,
+		"count":     len(items),
+		"baselines": items,
+	})
+}
+
+// resetMetricBaseline discards learned baselines, for example after an
+// incident or load test was learned as normal behavior
+func (h *ToolHandler) resetMetricBaseline(ctx context.Context, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	resource, _ := arguments["resource"].(string)
+	if resource == "" {
+		return h.createErrorResponse("resource is required, e.g. i-0abc123 or InstanceId=i-0abc123")
+	}
+	metric, _ := arguments["metric"].(string)
+
+	found := h.baselines.Find(resource, metric)
+	if len(found) == 0 {
+		return h.createErrorResponse(fmt.Sprintf("no baseline learned for %s", resource))
+	}
+
+	if !isConfirmed(arguments) {
+		metrics := make([]map[string]interface{}, 0, len(found))
+		for _, b := range found {
+			metrics = append(metrics, map[string]interface{}{
+				"resource":   b.Resource,
+				"metric":     b.Metric,
+				"samples":    b.Overall.Count,
+				"first_seen": h.times.Format(b.FirstSeen),
+			})
+		}
+		plan := map[string]interface{}{
+			"resource":  resource,
+			"baselines": metrics,
+		}
+		warnings := []string{
+			fmt.Sprintf("Anomalies are not flagged for these metrics until %d new samples are learned", baseline.MinSamples),
+			"Daily and weekly patterns are relearned from scratch, which takes a week of regular queries",
+		}
+		return h.createConfirmationResponse("reset-metric-baseline", plan, warnings)
+	}
+
+	removed, err := h.baselines.Reset(resource, metric)
+	if err != nil {
+		h.logger.WithError(err).WithField("resource", resource).Error("Failed to save metric baselines")
+		return h.createErrorResponse(fmt.Sprintf("failed to reset baselines: %v", err))
+	}
+
+	metrics := make([]string, 0, len(removed))
+	for _, b := range removed {
+		metrics = append(metrics, b.Metric)
+	}
+
+	return h.createSuccessResponse(fmt.Sprintf("Reset %d baselines for %s", len(removed), resource), map[string]interface{}{
+		"resource": resource,
+		"count":    len(removed),
+		"metrics":  metrics,
+	})
+}
+
+// formatBaseline summarizes a baseline with its weekly profile: the mean of
+// each hour of each day, or null for hours without enough samples
+func formatBaseline(b baseline.Baseline, formatTime func(time.Time) string) map[string]interface{} {
+	profile := make(map[string][]interface{}, len(weekdays))
+	hoursCovered := 0
+	for day, name := range weekdays {
+		hours := make([]interface{}, 24)
+		for hour := range hours {
+			bucket := b.Hours[day*24+hour]
+			if bucket.Count < baseline.MinHourSamples {
+				continue
+			}
+			hours[hour] = roundBaseline(bucket.Mean)
+			hoursCovered++
+		}
+		profile[name] = hours
+	}
+
+	return map[string]interface{}{
+		"resource":      b.Resource,
+		"metric":        b.Metric,
+		"samples":       b.Overall.Count,
+		"trained":       b.Trained(),
+		"mean":          roundBaseline(b.Overall.Mean),
+		"stddev":        roundBaseline(b.Overall.Stddev()),
+		"first_seen":    formatTime(b.FirstSeen),
+		"last_seen":     formatTime(b.LastSeen),
+		"hours_covered": hoursCovered,
+		"profile_utc":   profile,
+	}
+}
+
+// baselineResource names the resource a metric belongs to by its dimensions,
+// e.g. InstanceId=i-0abc123, or by the namespace for metrics without any
+func baselineResource(namespace string, dimensions map[string]string) string {
+	if len(dimensions) == 0 {
+		return namespace
+	}
+
+	pairs := make([]string, 0, len(dimensions))
+	for name, value := range dimensions {
+		pairs = append(pairs, name+"="+value)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+// baselineMetric names a metric with its statistic and period, since sums and
+// counts over different periods are not comparable
+func baselineMetric(params aws.MetricDataParams) string {
+	return fmt.Sprintf("%s/%s %s %ds", params.Namespace, params.MetricName, params.Statistic, params.Period)
+}
+
+// roundBaseline keeps learned values readable
+func roundBaseline(value float64) float64 {
+	return math.Round(value*1000) / 1000
+}
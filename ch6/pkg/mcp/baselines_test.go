@@ -0,0 +1,72 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"aws-mcp-server/internal/config"
+	"aws-mcp-server/internal/logging"
+	"aws-mcp-server/pkg/aws"
+	"aws-mcp-server/pkg/baseline"
+	"aws-mcp-server/pkg/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectAnomalies(t *testing.T) {
+	h := NewToolHandler(&config.Config{}, nil, logging.NewLogger("error", "text"))
+	params := aws.MetricDataParams{
+		Namespace:  "AWS/EC2",
+		MetricName: "CPUUtilization",
+		Statistic:  "Average",
+		Period:     300,
+		Dimensions: map[string]string{"InstanceId": "i-1"},
+	}
+
+	start := time.Date(2025, 6, 2, 0, 0, 0, 0, time.UTC)
+	var datapoints []types.MetricDatapoint
+	for i := 0; i < 40; i++ {
+		datapoints = append(datapoints, types.MetricDatapoint{Timestamp: start.Add(time.Duration(i) * 5 * time.Minute), Value: 20 + float64(i%2)})
+	}
+	now := datapoints[len(datapoints)-1].Timestamp.Add(time.Minute)
+
+	result := h.detectAnomalies(params, datapoints, now)
+	assert.Equal(t, "InstanceId=i-1", result["resource"])
+	assert.Equal(t, "AWS/EC2/CPUUtilization Average 300s", result["metric"])
+	assert.Equal(t, false, result["trained"])
+	assert.Equal(t, 39, result["learned"], "the period in progress is not learned")
+
+	later := []types.MetricDatapoint{
+		{Timestamp: now.Add(5 * time.Minute), Value: 20.5},
+		{Timestamp: now.Add(10 * time.Minute), Value: 95},
+	}
+	result = h.detectAnomalies(params, later, now.Add(time.Hour))
+	require.Equal(t, true, result["trained"])
+	assert.Equal(t, 1, result["anomaly_count"])
+	anomalies := result["anomalies"].([]map[string]interface{})
+	assert.Equal(t, 95.0, anomalies[0]["value"])
+	assert.Equal(t, 20.487, anomalies[0]["expected"])
+}
+
+func TestResetMetricBaselineRequiresConfirmation(t *testing.T) {
+	h := NewToolHandler(&config.Config{}, nil, logging.NewLogger("error", "text"))
+	ctx := context.Background()
+	_, err := h.baselines.Learn("InstanceId=i-1", "AWS/EC2/CPUUtilization Average 300s", []baseline.Point{{Time: time.Now(), Value: 1}})
+	require.NoError(t, err)
+
+	result, err := h.CallTool(ctx, "reset-metric-baseline", map[string]interface{}{"resource": "i-1"})
+	require.NoError(t, err)
+	assert.Equal(t, true, decodeToolResult(t, result)["confirmation_required"])
+	assert.Len(t, h.baselines.Find("i-1", ""), 1)
+
+	result, err = h.CallTool(ctx, "reset-metric-baseline", map[string]interface{}{"resource": "i-1", "confirm": true})
+	require.NoError(t, err)
+	assert.Equal(t, float64(1), decodeToolResult(t, result)["count"])
+	assert.Empty(t, h.baselines.Find("i-1", ""))
+
+	result, err = h.CallTool(ctx, "get-metric-baseline", map[string]interface{}{"resource": "i-1"})
+	require.NoError(t, err)
+	assert.Contains(t, decodeToolResult(t, result)["error"], "no baseline learned for i-1")
+}
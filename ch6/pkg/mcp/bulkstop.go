@@ -0,0 +1,347 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"aws-mcp-server/pkg/shutdown"
+	"aws-mcp-server/pkg/types"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const (
+	// defaultStaggerSeconds is the pause between waves of a bulk stop
+	defaultStaggerSeconds = 30
+	maxStaggerSeconds     = 600
+	// defaultWaveTimeoutSeconds bounds how long a wave may take to stop and
+	// leave the remaining targets healthy before the rest is abandoned
+	defaultWaveTimeoutSeconds = 300
+	minWaveTimeoutSeconds     = 30
+	maxWaveTimeoutSeconds     = 1800
+	// maxBulkInstances keeps a single bulk stop reviewable
+	maxBulkInstances = 50
+	// wavePollInterval is how often instance state and target health are checked
+	wavePollInterval = 10 * time.Second
+)
+
+// bulkStopEC2Instances stops or terminates several instances in dependency
+// order: the plan is returned for confirmation first, then waves are stopped
+// one at a time with a pause and health checks in between. A wave that fails
+// or leaves the remaining targets unhealthy stops the rest of the plan.
+func (h *ToolHandler) bulkStopEC2Instances(ctx context.Context, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	instanceIDs := stringList(arguments["instanceIds"])
+	if len(instanceIDs) == 0 {
+		return h.createErrorResponse("instanceIds is required")
+	}
+	if len(instanceIDs) > maxBulkInstances {
+		return h.createErrorResponse(fmt.Sprintf("at most %d instances can be stopped at once, got %d", maxBulkInstances, len(instanceIDs)))
+	}
+
+	action, _ := arguments["action"].(string)
+	if action == "" {
+		action = "stop"
+	}
+	if action != "stop" && action != "terminate" {
+		return h.createErrorResponse("action must be stop or terminate")
+	}
+
+	stagger := defaultStaggerSeconds
+	if value, ok := arguments["staggerSeconds"].(float64); ok {
+		if value < 0 || value > maxStaggerSeconds {
+			return h.createErrorResponse(fmt.Sprintf("staggerSeconds must be between 0 and %d", maxStaggerSeconds))
+		}
+		stagger = int(value)
+	}
+	timeout := defaultWaveTimeoutSeconds
+	if value, ok := arguments["waveTimeoutSeconds"].(float64); ok {
+		if value < minWaveTimeoutSeconds || value > maxWaveTimeoutSeconds {
+			return h.createErrorResponse(fmt.Sprintf("waveTimeoutSeconds must be between %d and %d", minWaveTimeoutSeconds, maxWaveTimeoutSeconds))
+		}
+		timeout = int(value)
+	}
+
+	instances, err := h.awsClient.ListEC2Instances(ctx)
+	if err != nil {
+		return h.createErrorResponse(fmt.Sprintf("failed to list EC2 instances: %v", err))
+	}
+	planned, missing := plannedInstances(instances, instanceIDs)
+	if len(missing) > 0 {
+		return h.createErrorResponse(fmt.Sprintf("instances not found: %s", strings.Join(missing, ", ")))
+	}
+
+	// Without security groups or target groups the plan is less careful, but
+	// still valid, so lookup failures become warnings
+	var warnings []string
+	groups, err := h.awsClient.ListSecurityGroups(ctx)
+	if err != nil {
+		warnings = append(warnings, fmt.Sprintf("Security groups could not be read, so dependencies between instances are not ordered: %v", err))
+	}
+	targetGroups, err := h.awsClient.ListTargetGroups(ctx)
+	if err != nil {
+		warnings = append(warnings, fmt.Sprintf("Target groups could not be read, so load-balanced instances are not staggered: %v", err))
+	}
+
+	plan := shutdown.Build(planned, groups, targetGroups)
+	warnings = append(warnings, plan.Warnings...)
+	if action == "terminate" {
+		warnings = append(warnings, "Terminated instances and their instance store data cannot be recovered")
+	}
+
+	if !isConfirmed(arguments) {
+		return h.createConfirmationResponse("bulk-stop-ec2-instances", formatShutdownPlan(plan, action, stagger, timeout), warnings)
+	}
+
+	run := &bulkRun{
+		action:  action,
+		stagger: time.Duration(stagger) * time.Second,
+		timeout: time.Duration(timeout) * time.Second,
+		healthy: healthyTargets(targetGroups, plan.TargetGroups),
+		stopped: make(map[string]bool),
+	}
+	results := h.runShutdownPlan(ctx, run, plan)
+
+	data := map[string]interface{}{
+		"action":    action,
+		"requested": len(instanceIDs),
+		"completed": len(run.stopped),
+		"waves":     results,
+	}
+	if run.aborted != "" {
+		data["aborted"] = run.aborted
+		return h.createSuccessResponse(fmt.Sprintf("Bulk %s aborted after %d of %d instances: %s", action, len(run.stopped), len(instanceIDs), run.aborted), data, warnings...)
+	}
+	return h.createSuccessResponse(fmt.Sprintf("Bulk %s of %d instances completed in %d waves", action, len(instanceIDs), len(plan.Waves)), data, warnings...)
+}
+
+// bulkRun tracks the progress of a confirmed bulk stop
+type bulkRun struct {
+	action  string
+	stagger time.Duration
+	timeout time.Duration
+	// healthy maps target group ARNs to the targets that were healthy and are
+	// not being stopped; they must stay healthy for the plan to continue
+	healthy map[string][]string
+	stopped map[string]bool
+	aborted string
+}
+
+// runShutdownPlan executes the waves in order and reports each one
+func (h *ToolHandler) runShutdownPlan(ctx context.Context, run *bulkRun, plan shutdown.Plan) []map[string]interface{} {
+	results := make([]map[string]interface{}, 0, len(plan.Waves))
+	for i, wave := range plan.Waves {
+		result := map[string]interface{}{
+			"wave":      i + 1,
+			"instances": wave,
+		}
+		results = append(results, result)
+
+		if run.aborted != "" {
+			result["status"] = "skipped"
+			continue
+		}
+		if i > 0 && run.stagger > 0 {
+			select {
+			case <-time.After(run.stagger):
+			case <-ctx.Done():
+				run.aborted = "the request was cancelled"
+				result["status"] = "skipped"
+				continue
+			}
+		}
+
+		var failures []string
+		for _, instanceID := range wave {
+			var err error
+			if run.action == "terminate" {
+				err = h.awsClient.TerminateEC2Instance(ctx, instanceID)
+			} else {
+				err = h.awsClient.StopEC2Instance(ctx, instanceID)
+			}
+			if err != nil {
+				failures = append(failures, fmt.Sprintf("%s: %v", instanceID, err))
+				continue
+			}
+			run.stopped[instanceID] = true
+		}
+		if len(failures) > 0 {
+			result["status"] = "failed"
+			result["errors"] = failures
+			run.aborted = fmt.Sprintf("wave %d failed", i+1)
+			continue
+		}
+
+		started := time.Now()
+		if problem := h.waitForWave(ctx, run, wave); problem != "" {
+			result["status"] = "unhealthy"
+			result["health_check"] = problem
+			run.aborted = fmt.Sprintf("wave %d health check failed: %s", i+1, problem)
+			continue
+		}
+		result["status"] = "done"
+		result["duration_seconds"] = int(time.Since(started).Seconds())
+	}
+	return results
+}
+
+// waitForWave waits until the wave's instances reach their final state and
+// the targets that should keep serving are still healthy. It returns what
+// was still wrong when the wave timed out, or an empty string.
+func (h *ToolHandler) waitForWave(ctx context.Context, run *bulkRun, wave []string) string {
+	final := finalState(run.action)
+
+	ctx, cancel := context.WithTimeout(ctx, run.timeout)
+	defer cancel()
+
+	for {
+		problem := ""
+		for _, instanceID := range wave {
+			instance, err := h.awsClient.GetEC2Instance(ctx, instanceID)
+			if err != nil {
+				problem = fmt.Sprintf("could not read %s: %v", instanceID, err)
+				break
+			}
+			if instance.State != final {
+				problem = fmt.Sprintf("%s is %s, not %s", instanceID, instance.State, final)
+				break
+			}
+		}
+		if problem == "" {
+			problem = h.checkRemainingTargets(ctx, run)
+		}
+		if problem == "" {
+			return ""
+		}
+
+		select {
+		case <-time.After(wavePollInterval):
+		case <-ctx.Done():
+			return problem
+		}
+	}
+}
+
+// checkRemainingTargets returns the first target that was healthy before the
+// bulk stop and is not being stopped but is no longer healthy
+func (h *ToolHandler) checkRemainingTargets(ctx context.Context, run *bulkRun) string {
+	for arn, expected := range run.healthy {
+		targets, err := h.awsClient.GetTargetHealth(ctx, arn)
+		if err != nil {
+			return fmt.Sprintf("could not read target health: %v", err)
+		}
+		state := make(map[string]string, len(targets))
+		for _, target := range targets {
+			state[target.ID] = target.State
+		}
+		for _, id := range expected {
+			if state[id] != "healthy" {
+				return fmt.Sprintf("remaining target %s of %s is %s", id, targetGroupName(arn), state[id])
+			}
+		}
+	}
+	return ""
+}
+
+// plannedInstances converts the requested instances for the planner, in the
+// requested order, and returns the IDs that do not exist
+func plannedInstances(instances []types.CloudResource, instanceIDs []string) ([]shutdown.Instance, []string) {
+	byID := make(map[string]types.CloudResource, len(instances))
+	for _, instance := range instances {
+		byID[instance.ID] = instance
+	}
+
+	var planned []shutdown.Instance
+	var missing []string
+	seen := make(map[string]bool, len(instanceIDs))
+	for _, id := range instanceIDs {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		instance, ok := byID[id]
+		if !ok {
+			missing = append(missing, id)
+			continue
+		}
+		securityGroups := instance.EC2Instance().SecurityGroups
+		planned = append(planned, shutdown.Instance{
+			ID:             id,
+			Name:           instance.Tags["Name"],
+			Role:           instance.Tags["Role"],
+			SecurityGroups: securityGroups,
+		})
+	}
+	return planned, missing
+}
+
+// healthyTargets maps target groups touched by the plan to their healthy
+// targets that are not part of it. Groups losing every healthy target are
+// left out, since the plan already warned about them.
+func healthyTargets(targetGroups []types.TargetGroup, planned map[string][]string) map[string][]string {
+	healthy := make(map[string][]string)
+	for _, group := range targetGroups {
+		members, ok := planned[group.Name]
+		if !ok {
+			continue
+		}
+		stopping := make(map[string]bool, len(members))
+		for _, id := range members {
+			stopping[id] = true
+		}
+		for _, target := range group.Targets {
+			if target.State == "healthy" && !stopping[target.ID] {
+				healthy[group.ARN] = append(healthy[group.ARN], target.ID)
+			}
+		}
+	}
+	return healthy
+}
+
+// formatShutdownPlan renders the plan for confirmation
+func formatShutdownPlan(plan shutdown.Plan, action string, stagger, timeout int) map[string]interface{} {
+	waves := make([]map[string]interface{}, 0, len(plan.Waves))
+	for i, wave := range plan.Waves {
+		waves = append(waves, map[string]interface{}{
+			"wave":      i + 1,
+			"instances": wave,
+		})
+	}
+
+	dependencies := plan.Dependencies
+	if dependencies == nil {
+		dependencies = []shutdown.Dependency{}
+	}
+
+	formatted := map[string]interface{}{
+		"action":               action,
+		"waves":                waves,
+		"dependencies":         dependencies,
+		"stagger_seconds":      stagger,
+		"wave_timeout_seconds": timeout,
+		"health_checks":        "Each wave must reach the " + finalState(action) + " state and leave the other healthy targets of its target groups healthy before the next wave starts",
+	}
+	if len(plan.TargetGroups) > 0 {
+		formatted["target_groups"] = plan.TargetGroups
+	}
+	return formatted
+}
+
+// finalState is the instance state an action ends in
+func finalState(action string) string {
+	if action == "terminate" {
+		return "terminated"
+	}
+	return "stopped"
+}
+
+// targetGroupName returns the name part of a target group ARN
+func targetGroupName(arn string) string {
+	parts := strings.Split(arn, "/")
+	if len(parts) >= 2 {
+		return parts[len(parts)-2]
+	}
+	return arn
+}
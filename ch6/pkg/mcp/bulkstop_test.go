@@ -0,0 +1,77 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	"aws-mcp-server/internal/config"
+	"aws-mcp-server/internal/logging"
+	"aws-mcp-server/pkg/shutdown"
+	"aws-mcp-server/pkg/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlannedInstances(t *testing.T) {
+	instances := []types.CloudResource{
+		{ID: "i-web", Tags: map[string]string{"Name": "web-1"}, Details: &types.EC2InstanceDetails{SecurityGroups: []string{"sg-web"}}},
+		{ID: "i-jump", Tags: map[string]string{"Role": "bastion"}, Details: &types.EC2InstanceDetails{}},
+	}
+
+	planned, missing := plannedInstances(instances, []string{"i-jump", "i-web", "i-jump", "i-gone"})
+	assert.Equal(t, []string{"i-gone"}, missing)
+	assert.Equal(t, []shutdown.Instance{
+		{ID: "i-jump", Role: "bastion"},
+		{ID: "i-web", Name: "web-1", SecurityGroups: []string{"sg-web"}},
+	}, planned)
+}
+
+func TestHealthyTargets(t *testing.T) {
+	targetGroups := []types.TargetGroup{
+		{ARN: "arn:aws:elasticloadbalancing:us-east-1:123456789012:targetgroup/web/abc", Name: "web", Targets: []types.Target{
+			{ID: "i-web1", State: "healthy"},
+			{ID: "i-web2", State: "healthy"},
+			{ID: "i-web3", State: "draining"},
+		}},
+		{ARN: "arn:aws:elasticloadbalancing:us-east-1:123456789012:targetgroup/api/def", Name: "api", Targets: []types.Target{
+			{ID: "i-api", State: "healthy"},
+		}},
+	}
+
+	healthy := healthyTargets(targetGroups, map[string][]string{"web": {"i-web1"}})
+	assert.Equal(t, map[string][]string{targetGroups[0].ARN: {"i-web2"}}, healthy, "untouched target groups are not checked")
+	assert.Equal(t, "web", targetGroupName(targetGroups[0].ARN))
+}
+
+func TestFormatShutdownPlan(t *testing.T) {
+	plan := shutdown.Plan{
+		Waves:        [][]string{{"i-web1"}, {"i-web2"}, {"i-jump"}},
+		TargetGroups: map[string][]string{"web": {"i-web1", "i-web2"}},
+	}
+
+	formatted := formatShutdownPlan(plan, "terminate", 30, 300)
+	assert.Equal(t, []shutdown.Dependency{}, formatted["dependencies"])
+	assert.Contains(t, formatted["health_checks"], "terminated")
+	waves := formatted["waves"].([]map[string]interface{})
+	require.Len(t, waves, 3)
+	assert.Equal(t, 3, waves[2]["wave"])
+	assert.Equal(t, []string{"i-jump"}, waves[2]["instances"])
+}
+
+func TestBulkStopValidatesArguments(t *testing.T) {
+	h := NewToolHandler(&config.Config{}, nil, logging.NewLogger("error", "text"))
+
+	for message, arguments := range map[string]map[string]interface{}{
+		"instanceIds is required":                        {},
+		"action must be stop or terminate":               {"instanceIds": []interface{}{"i-1"}, "action": "reboot"},
+		"staggerSeconds must be between 0 and 600":       {"instanceIds": "i-1,i-2", "staggerSeconds": float64(-1)},
+		"waveTimeoutSeconds must be between 30 and 1800": {"instanceIds": "i-1", "waveTimeoutSeconds": float64(5)},
+	} {
+		result, err := h.CallTool(context.Background(), "bulk-stop-ec2-instances", arguments)
+		require.NoError(t, err)
+		data := decodeToolResult(t, result)
+		assert.Equal(t, false, data["success"])
+		assert.Equal(t, message, data["error"])
+	}
+}
@@ -0,0 +1,378 @@
+package mcp
+
+import (
+	"bufio"
+	"cmp"
+	"context"
+	"fmt"
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"aws-mcp-server/pkg/types"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// Hard limits of capture-traffic. The configuration can lower them but never
+// raise them, so a capture stays short, small and free of payloads.
+const (
+	maxCaptureDuration = 2 * time.Minute
+	maxCaptureSizeMB   = 100
+	maxCaptureLink     = 24 * time.Hour
+	// captureSnapLength keeps the IP and transport headers of each packet but
+	// drops application payloads
+	captureSnapLength = 128
+	// maxCaptureFlows is how many of the busiest flows are summarized
+	maxCaptureFlows = 20
+	// defaultCaptureDuration and defaultCaptureSizeMB apply when the caller
+	// does not ask for a shorter or smaller capture
+	defaultCaptureDuration = 30 * time.Second
+	defaultCaptureSizeMB   = 10
+)
+
+var (
+	// captureFilterPattern accepts tcpdump filter expressions such as
+	// "tcp port 443 and host 10.0.1.5" but no quotes or shell syntax
+	captureFilterPattern = regexp.MustCompile(`^[A-Za-z0-9 .:/\[\]()!&|<>=-]*$`)
+	// captureInterfacePattern accepts network interface names
+	captureInterfacePattern = regexp.MustCompile(`^[A-Za-z0-9._-]+$`)
+)
+
+// captureRequest is a validated capture within the limits
+type captureRequest struct {
+	instanceID string
+	iface      string
+	filter     string
+	duration   time.Duration
+	sizeMB     int
+}
+
+// captureFlow is one source and destination pair seen in a capture
+type captureFlow struct {
+	Source      string `json:"source"`
+	Destination string `json:"destination"`
+	Protocol    string `json:"protocol"`
+	Packets     int    `json:"packets"`
+}
+
+// captureSummary is what the capture script reports about the pcap
+type captureSummary struct {
+	Bytes    int64
+	Packets  int
+	Flows    []captureFlow
+	Uploaded bool
+}
+
+// captureLimits returns the longest duration and largest size a capture may
+// have: the configured limits, capped by the hard limits
+func captureLimits(duration time.Duration, sizeMB int) (time.Duration, int) {
+	if duration <= 0 || duration > maxCaptureDuration {
+		duration = maxCaptureDuration
+	}
+	if sizeMB <= 0 || sizeMB > maxCaptureSizeMB {
+		sizeMB = maxCaptureSizeMB
+	}
+	return duration, sizeMB
+}
+
+// parseCaptureRequest validates the capture-traffic arguments against the limits
+func parseCaptureRequest(arguments map[string]interface{}, maxDuration time.Duration, maxSizeMB int) (captureRequest, error) {
+	request := captureRequest{
+		iface:    "any",
+		duration: min(defaultCaptureDuration, maxDuration),
+		sizeMB:   min(defaultCaptureSizeMB, maxSizeMB),
+	}
+
+	request.instanceID, _ = arguments["instanceId"].(string)
+	if request.instanceID == "" {
+		return request, fmt.Errorf("instanceId is required")
+	}
+	if value, ok := arguments["durationSeconds"].(float64); ok {
+		request.duration = time.Duration(value) * time.Second
+		if request.duration < time.Second || request.duration > maxDuration {
+			return request, fmt.Errorf("durationSeconds must be between 1 and %d", int(maxDuration.Seconds()))
+		}
+	}
+	if value, ok := arguments["maxSizeMB"].(float64); ok {
+		request.sizeMB = int(value)
+		if request.sizeMB < 1 || request.sizeMB > maxSizeMB {
+			return request, fmt.Errorf("maxSizeMB must be between 1 and %d", maxSizeMB)
+		}
+	}
+	if value, _ := arguments["interface"].(string); value != "" {
+		if !captureInterfacePattern.MatchString(value) {
+			return request, fmt.Errorf("invalid interface %q", value)
+		}
+		request.iface = value
+	}
+	if value, _ := arguments["filter"].(string); value != "" {
+		value = strings.TrimSpace(value)
+		if len(value) > 200 || !captureFilterPattern.MatchString(value) {
+			return request, fmt.Errorf("invalid filter %q, use a tcpdump expression such as \"tcp port 443 and host 10.0.1.5\" without quotes", value)
+		}
+		request.filter = value
+	}
+	return request, nil
+}
+
+// captureScript returns the shell commands that capture packet headers for
+// the request's duration, stop at its size, summarize the busiest flows and
+// upload the pcap to uploadURL. The filter and interface are validated, so
+// quoting them is safe.
+func captureScript(request captureRequest, uploadURL string) []string {
+	sizeBytes := int64(request.sizeMB) << 20
+	return []string{
+		"set -u",
+		`for tool in tcpdump curl timeout; do command -v "$tool" >/dev/null 2>&1 || { echo "$tool is not installed on the instance" >&2; exit 3; }; done`,
+		`pcap=$(mktemp /tmp/capture-XXXXXX.pcap)`,
+		`trap 'rm -f "$pcap"' EXIT`,
+		fmt.Sprintf(`timeout %d tcpdump -i %s -nn -s %d -U -w - '%s' 2>/dev/null | head -c %d > "$pcap"`,
+			int(request.duration.Seconds()), request.iface, captureSnapLength, request.filter, sizeBytes),
+		`echo "bytes $(stat -c %s "$pcap")"`,
+		`echo "packets $(tcpdump -nn -r "$pcap" 2>/dev/null | wc -l)"`,
+		fmt.Sprintf(`tcpdump -nn -q -t -r "$pcap" 2>/dev/null | awk '{for (i = 1; i <= NF - 4; i++) if ($i == "IP" || $i == "IP6") { d = $(i + 3); sub(/:$/, "", d); print $(i + 1), d, $(i + 4); break }}' | sort | uniq -c | sort -rn | head -n %d | awk '{print "flow", $1, $2, $3, $4}'`, maxCaptureFlows),
+		fmt.Sprintf(`curl -sSf -X PUT --upload-file "$pcap" '%s' && echo uploaded`, uploadURL),
+	}
+}
+
+// pktmonFilterArgs translates a tcpdump filter into the arguments of a pktmon
+// filter for Windows captures. A pktmon filter matches one protocol, port and
+// IP address, so only filters joining those with "and" can be translated.
+func pktmonFilterArgs(filter string) ([]string, error) {
+	var args []string
+	seen := make(map[string]bool)
+	add := func(flag, value string) error {
+		if seen[flag] {
+			return fmt.Errorf("filter %q cannot be used on Windows: pktmon matches one protocol, port and host at a time", filter)
+		}
+		seen[flag] = true
+		args = append(args, flag, value)
+		return nil
+	}
+
+	tokens := strings.Fields(strings.ToLower(filter))
+	for i := 0; i < len(tokens); i++ {
+		var err error
+		switch token := tokens[i]; {
+		case token == "and":
+			continue
+		case token == "tcp" || token == "udp" || token == "icmp":
+			err = add("-t", strings.ToUpper(token))
+		case token == "port" && i+1 < len(tokens):
+			i++
+			if port, convErr := strconv.Atoi(tokens[i]); convErr != nil || port < 1 || port > 65535 {
+				return nil, fmt.Errorf("invalid port %q in filter", tokens[i])
+			}
+			err = add("-p", tokens[i])
+		case token == "host" && i+1 < len(tokens):
+			i++
+			if net.ParseIP(tokens[i]) == nil {
+				return nil, fmt.Errorf("invalid host %q in filter, Windows captures need an IP address", tokens[i])
+			}
+			err = add("-i", tokens[i])
+		default:
+			return nil, fmt.Errorf("filter %q cannot be used on Windows: only tcp, udp, icmp, port N and host IP joined by and are supported", filter)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return args, nil
+}
+
+// windowsCaptureScript returns the PowerShell commands that capture packet
+// headers with pktmon for the request's duration, convert the trace to pcapng
+// and upload it to uploadURL. pktmon filters are global, so the script clears
+// them before and after the capture.
+func windowsCaptureScript(request captureRequest, filterArgs []string, uploadURL string) []string {
+	script := []string{
+		"$ErrorActionPreference = 'Stop'",
+		"if (-not (Get-Command pktmon.exe -ErrorAction SilentlyContinue)) { [Console]::Error.WriteLine('pktmon is not available, it needs Windows Server 2019 or later'); exit 3 }",
+		"$etl = Join-Path $env:TEMP ('capture-' + [guid]::NewGuid().ToString() + '.etl')",
+		"$pcap = [IO.Path]::ChangeExtension($etl, '.pcapng')",
+		"try {",
+		"pktmon filter remove | Out-Null",
+	}
+	if len(filterArgs) > 0 {
+		script = append(script, fmt.Sprintf("pktmon filter add capture %s | Out-Null", strings.Join(filterArgs, " ")))
+	}
+	return append(script,
+		fmt.Sprintf("pktmon start --capture --pkt-size %d --file-name $etl --file-size %d --log-mode circular | Out-Null", captureSnapLength, request.sizeMB),
+		fmt.Sprintf("Start-Sleep -Seconds %d", int(request.duration.Seconds())),
+		"pktmon stop | Out-Null",
+		"pktmon etl2pcap $etl --out $pcap | Out-Null",
+		`"bytes $((Get-Item $pcap).Length)"`,
+		fmt.Sprintf("Invoke-WebRequest -UseBasicParsing -Method Put -InFile $pcap -Uri '%s' | Out-Null", uploadURL),
+		"'uploaded'",
+		"} finally {",
+		"pktmon stop 2>$null | Out-Null",
+		"pktmon filter remove | Out-Null",
+		"Remove-Item -Force -ErrorAction SilentlyContinue $etl, $pcap",
+		"}",
+	)
+}
+
+// parseCaptureOutput reads the summary lines the capture script prints
+func parseCaptureOutput(output string) captureSummary {
+	var summary captureSummary
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		switch {
+		case fields[0] == "bytes" && len(fields) == 2:
+			summary.Bytes, _ = strconv.ParseInt(fields[1], 10, 64)
+		case fields[0] == "packets" && len(fields) == 2:
+			summary.Packets, _ = strconv.Atoi(fields[1])
+		case fields[0] == "flow" && len(fields) == 5:
+			packets, err := strconv.Atoi(fields[1])
+			if err != nil {
+				continue
+			}
+			summary.Flows = append(summary.Flows, captureFlow{
+				Source:      fields[2],
+				Destination: fields[3],
+				Protocol:    strings.ToLower(fields[4]),
+				Packets:     packets,
+			})
+		case fields[0] == "uploaded":
+			summary.Uploaded = true
+		}
+	}
+	return summary
+}
+
+// captureTraffic records packet headers on an instance through SSM, with
+// tcpdump on Linux and pktmon on Windows, uploads the pcap to the capture
+// bucket and returns a download link with the busiest flows where tcpdump can
+// summarize them. Duration and size are capped in code, and the capture only
+// runs once confirmed.
+func (h *ToolHandler) captureTraffic(ctx context.Context, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	cfg := h.config.Capture
+	if cfg.Bucket == "" {
+		return h.createErrorResponse("traffic capture is not configured; set capture.bucket")
+	}
+
+	maxDuration, maxSizeMB := captureLimits(cfg.MaxDuration, cfg.MaxSizeMB)
+	request, err := parseCaptureRequest(arguments, maxDuration, maxSizeMB)
+	if err != nil {
+		return h.createErrorResponse(err.Error())
+	}
+
+	managed, err := h.awsClient.ListManagedInstances(ctx)
+	if err != nil {
+		return h.createErrorResponse(fmt.Sprintf("failed to list SSM managed instances: %v", err))
+	}
+	if problems := unreachableInstances([]string{request.instanceID}, managed); len(problems) > 0 {
+		return h.createErrorResponse(fmt.Sprintf("cannot capture: %s", problems[0]))
+	}
+	platform := ""
+	for _, instance := range managed {
+		if instance.InstanceID == request.instanceID {
+			platform = instance.PlatformType
+		}
+	}
+
+	var filterArgs []string
+	extension := "pcap"
+	switch platform {
+	case "Linux":
+	case "Windows":
+		if request.iface != "any" {
+			return h.createErrorResponse("interface cannot be chosen on Windows, pktmon captures on every network adapter")
+		}
+		if filterArgs, err = pktmonFilterArgs(request.filter); err != nil {
+			return h.createErrorResponse(err.Error())
+		}
+		extension = "pcapng"
+	default:
+		return h.createErrorResponse(fmt.Sprintf("cannot capture: %s runs %s, only Linux and Windows instances are supported", request.instanceID, platform))
+	}
+
+	if !isConfirmed(arguments) {
+		plan := map[string]interface{}{
+			"instanceId":      request.instanceID,
+			"platform":        strings.ToLower(platform),
+			"interface":       request.iface,
+			"durationSeconds": int(request.duration.Seconds()),
+			"maxSizeMB":       request.sizeMB,
+			"bucket":          cfg.Bucket,
+		}
+		if request.filter != "" {
+			plan["filter"] = request.filter
+		}
+		warnings := []string{
+			fmt.Sprintf("Runs a packet capture as root on %s through SSM for %d seconds", request.instanceID, int(request.duration.Seconds())),
+			fmt.Sprintf("Packet headers, including peer addresses and ports, are uploaded to s3://%s", cfg.Bucket),
+		}
+		return h.createConfirmationResponse("capture-traffic", plan, warnings)
+	}
+
+	key := fmt.Sprintf("%s%s/%s.%s", cfg.Prefix, request.instanceID, time.Now().UTC().Format("20060102T150405Z"), extension)
+	uploadURL, err := h.awsClient.PresignS3Upload(ctx, cfg.Bucket, key, request.duration+10*time.Minute)
+	if err != nil {
+		return h.createErrorResponse(err.Error())
+	}
+
+	var result *types.CommandResult
+	if platform == "Windows" {
+		result, err = h.awsClient.RunPowerShellCommand(ctx, request.instanceID, windowsCaptureScript(request, filterArgs, uploadURL),
+			"capture-traffic", request.duration+time.Minute)
+	} else {
+		result, err = h.awsClient.RunShellCommand(ctx, request.instanceID, captureScript(request, uploadURL),
+			"capture-traffic", request.duration+time.Minute)
+	}
+	if err != nil {
+		return h.createErrorResponse(fmt.Sprintf("failed to run capture: %v", err))
+	}
+	if result.Status != "Success" {
+		return h.createErrorResponse(fmt.Sprintf("capture %s on %s: %s", strings.ToLower(result.Status), request.instanceID, cmp.Or(strings.TrimSpace(result.Error), "no error output")))
+	}
+
+	summary := parseCaptureOutput(result.Output)
+	if !summary.Uploaded {
+		return h.createErrorResponse(fmt.Sprintf("capture finished but the pcap could not be uploaded: %s", strings.TrimSpace(result.Error)))
+	}
+
+	linkExpiry := cfg.LinkExpiry
+	if linkExpiry <= 0 || linkExpiry > maxCaptureLink {
+		linkExpiry = maxCaptureLink
+	}
+	downloadURL, err := h.awsClient.PresignS3Download(ctx, cfg.Bucket, key, linkExpiry)
+	if err != nil {
+		return h.createErrorResponse(err.Error())
+	}
+
+	data := map[string]interface{}{
+		"instanceId":      request.instanceID,
+		"platform":        strings.ToLower(platform),
+		"interface":       request.iface,
+		"durationSeconds": int(request.duration.Seconds()),
+		"sizeBytes":       summary.Bytes,
+		"s3Uri":           fmt.Sprintf("s3://%s/%s", cfg.Bucket, key),
+		"downloadUrl":     downloadURL,
+		"linkExpires":     h.times.Format(time.Now().Add(linkExpiry)),
+		"commandId":       result.CommandID,
+	}
+	if platform == "Windows" {
+		data["note"] = "pktmon keeps the newest packets once the size limit is reached; open the pcapng in Wireshark to see the flows"
+	} else {
+		flows := summary.Flows
+		if flows == nil {
+			flows = []captureFlow{}
+		}
+		data["packets"] = summary.Packets
+		data["truncated"] = summary.Bytes >= int64(request.sizeMB)<<20
+		data["topFlows"] = flows
+	}
+	if request.filter != "" {
+		data["filter"] = request.filter
+	}
+
+	return h.createSuccessResponse("Traffic captured successfully", data)
+}
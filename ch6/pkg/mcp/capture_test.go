@@ -0,0 +1,175 @@
+package mcp
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"aws-mcp-server/internal/config"
+	"aws-mcp-server/internal/logging"
+	"aws-mcp-server/pkg/aws"
+
+	sdkaws "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCaptureLimits(t *testing.T) {
+	duration, size := captureLimits(0, 0)
+	assert.Equal(t, maxCaptureDuration, duration)
+	assert.Equal(t, maxCaptureSizeMB, size)
+
+	duration, size = captureLimits(time.Hour, 1000)
+	assert.Equal(t, maxCaptureDuration, duration, "the configuration cannot raise the hard limits")
+	assert.Equal(t, maxCaptureSizeMB, size)
+
+	duration, size = captureLimits(20*time.Second, 5)
+	assert.Equal(t, 20*time.Second, duration)
+	assert.Equal(t, 5, size)
+}
+
+func TestParseCaptureRequest(t *testing.T) {
+	request, err := parseCaptureRequest(map[string]interface{}{"instanceId": "i-1"}, 20*time.Second, 50)
+	require.NoError(t, err)
+	assert.Equal(t, 20*time.Second, request.duration, "the default is lowered to the limit")
+	assert.Equal(t, defaultCaptureSizeMB, request.sizeMB)
+	assert.Equal(t, "any", request.iface)
+
+	request, err = parseCaptureRequest(map[string]interface{}{
+		"instanceId": "i-1", "filter": "tcp port 443 and (host 10.0.1.5 or net 10.1.0.0/16)", "interface": "eth0", "durationSeconds": float64(10),
+	}, maxCaptureDuration, maxCaptureSizeMB)
+	require.NoError(t, err)
+	assert.Equal(t, "tcp port 443 and (host 10.0.1.5 or net 10.1.0.0/16)", request.filter)
+	assert.Equal(t, 10*time.Second, request.duration)
+
+	tests := []struct {
+		name      string
+		arguments map[string]interface{}
+		problem   string
+	}{
+		{name: "missing instance", arguments: map[string]interface{}{}, problem: "instanceId is required"},
+		{name: "too long", arguments: map[string]interface{}{"instanceId": "i-1", "durationSeconds": float64(600)}, problem: "durationSeconds must be between 1 and 120"},
+		{name: "too large", arguments: map[string]interface{}{"instanceId": "i-1", "maxSizeMB": float64(500)}, problem: "maxSizeMB must be between 1 and 100"},
+		{name: "shell in filter", arguments: map[string]interface{}{"instanceId": "i-1", "filter": "port 80'; rm -rf /tmp; echo '"}, problem: "invalid filter"},
+		{name: "substitution in filter", arguments: map[string]interface{}{"instanceId": "i-1", "filter": "host $(hostname)"}, problem: "invalid filter"},
+		{name: "shell in interface", arguments: map[string]interface{}{"instanceId": "i-1", "interface": "eth0;reboot"}, problem: "invalid interface"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseCaptureRequest(tt.arguments, maxCaptureDuration, maxCaptureSizeMB)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.problem)
+		})
+	}
+}
+
+func TestCaptureScript(t *testing.T) {
+	script := strings.Join(captureScript(captureRequest{
+		instanceID: "i-1", iface: "eth0", filter: "tcp port 443", duration: 15 * time.Second, sizeMB: 2,
+	}, "https://bucket.s3.amazonaws.com/captures/i-1.pcap?X-Amz-Signature=abc"), "\n")
+
+	assert.Contains(t, script, "timeout 15 tcpdump -i eth0 -nn -s 128 -U -w - 'tcp port 443' 2>/dev/null | head -c 2097152")
+	assert.Contains(t, script, "'https://bucket.s3.amazonaws.com/captures/i-1.pcap?X-Amz-Signature=abc' && echo uploaded")
+	assert.Contains(t, script, "head -n 20")
+}
+
+func TestParseCaptureOutput(t *testing.T) {
+	summary := parseCaptureOutput("bytes 48213\npackets 412\nflow 300 10.0.1.5.51234 10.0.2.9.443 tcp\nflow 12 10.0.1.5.40000 10.0.0.2.53 UDP\nflow x bad line here\nuploaded\n")
+
+	assert.Equal(t, int64(48213), summary.Bytes)
+	assert.Equal(t, 412, summary.Packets)
+	assert.True(t, summary.Uploaded)
+	require.Len(t, summary.Flows, 2)
+	assert.Equal(t, captureFlow{Source: "10.0.1.5.51234", Destination: "10.0.2.9.443", Protocol: "tcp", Packets: 300}, summary.Flows[0])
+	assert.Equal(t, "udp", summary.Flows[1].Protocol)
+
+	assert.False(t, parseCaptureOutput("bytes 0\npackets 0\n").Uploaded)
+}
+
+func TestCaptureTrafficRequiresBucket(t *testing.T) {
+	h := NewToolHandler(&config.Config{}, nil, logging.NewLogger("error", "text"))
+
+	result, err := h.CallTool(context.Background(), "capture-traffic", map[string]interface{}{"instanceId": "i-1"})
+	require.NoError(t, err)
+	assert.Contains(t, decodeToolResult(t, result)["error"], "capture.bucket")
+}
+
+func TestCaptureTrafficRequiresConfirmation(t *testing.T) {
+	// SSM knows one online Linux instance; any other call would start the capture
+	var mu sync.Mutex
+	var operations []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		operations = append(operations, r.Header.Get("X-Amz-Target"))
+		mu.Unlock()
+		if r.Header.Get("X-Amz-Target") != "AmazonSSM.DescribeInstanceInformation" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/x-amz-json-1.1")
+		w.Write([]byte(`{"InstanceInformationList":[{"InstanceId":"i-1","PingStatus":"Online","PlatformType":"Linux"}]}`))
+	}))
+	defer server.Close()
+
+	logger := logging.NewLogger("error", "text")
+	awsClient := aws.NewClientFromConfig(sdkaws.Config{
+		Region:       "us-east-1",
+		Credentials:  sdkaws.AnonymousCredentials{},
+		BaseEndpoint: sdkaws.String(server.URL),
+	}, logger)
+	h := NewToolHandler(&config.Config{Capture: config.CaptureConfig{Bucket: "captures"}}, awsClient, logger)
+
+	arguments := map[string]interface{}{"instanceId": "i-1", "filter": "tcp port 443", "durationSeconds": float64(20)}
+	assert.False(t, isMutating("capture-traffic", arguments), "a plan changes nothing")
+	result, err := h.CallTool(context.Background(), "capture-traffic", arguments)
+	require.NoError(t, err)
+
+	response := decodeToolResult(t, result)
+	assert.Equal(t, true, response["confirmation_required"])
+	plan := response["plan"].(map[string]interface{})
+	assert.Equal(t, "i-1", plan["instanceId"])
+	assert.Equal(t, float64(20), plan["durationSeconds"])
+	assert.Equal(t, "tcp port 443", plan["filter"])
+	assert.Equal(t, []string{"AmazonSSM.DescribeInstanceInformation"}, operations, "nothing runs on the instance")
+
+	arguments["confirm"] = true
+	assert.True(t, isMutating("capture-traffic", arguments))
+}
+
+func TestPktmonFilterArgs(t *testing.T) {
+	args, err := pktmonFilterArgs("tcp port 443 and host 10.0.1.5")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"-t", "TCP", "-p", "443", "-i", "10.0.1.5"}, args)
+
+	args, err = pktmonFilterArgs("")
+	require.NoError(t, err)
+	assert.Empty(t, args)
+
+	for filter, problem := range map[string]string{
+		"tcp or udp":           "only tcp, udp, icmp",
+		"port 80 and port 443": "one protocol, port and host at a time",
+		"port 99999":           "invalid port",
+		"host db.internal":     "need an IP address",
+		"net 10.0.0.0/16":      "only tcp, udp, icmp",
+		"not (tcp port 22)":    "only tcp, udp, icmp",
+	} {
+		_, err := pktmonFilterArgs(filter)
+		assert.ErrorContains(t, err, problem, filter)
+	}
+}
+
+func TestWindowsCaptureScript(t *testing.T) {
+	script := strings.Join(windowsCaptureScript(captureRequest{
+		instanceID: "i-1", iface: "any", duration: 15 * time.Second, sizeMB: 2,
+	}, []string{"-t", "TCP", "-p", "443"}, "https://bucket.s3.amazonaws.com/captures/i-1.pcapng?X-Amz-Signature=abc"), "\n")
+
+	assert.Contains(t, script, "pktmon filter add capture -t TCP -p 443")
+	assert.Contains(t, script, "pktmon start --capture --pkt-size 128 --file-name $etl --file-size 2")
+	assert.Contains(t, script, "Start-Sleep -Seconds 15")
+	assert.Contains(t, script, "-Uri 'https://bucket.s3.amazonaws.com/captures/i-1.pcapng?X-Amz-Signature=abc'")
+	assert.Contains(t, script, "} finally {", "filters are cleared even when the capture fails")
+}
@@ -0,0 +1,75 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// acceptsChangeTicket reports whether a tool can change infrastructure and so
+// takes a changeTicket argument
+func acceptsChangeTicket(name string) bool {
+	return mutatingTools[name]
+}
+
+// changeTicket returns the change ticket passed to a tool call, if any
+func changeTicket(arguments map[string]interface{}) string {
+	ticket, _ := arguments["changeTicket"].(string)
+	return strings.TrimSpace(ticket)
+}
+
+// checkChangeTicket rejects mutating calls whose change ticket is missing
+// when tickets are required, malformed, or unknown to the ticket system.
+// Approved calls were checked when they were queued.
+func (h *ToolHandler) checkChangeTicket(ctx context.Context, name string, arguments map[string]interface{}) (*mcp.CallToolResult, bool) {
+	if isApproved(ctx) || !isMutating(name, arguments) {
+		return nil, false
+	}
+
+	ticket := changeTicket(arguments)
+	if ticket == "" {
+		if !h.changes.Required() {
+			return nil, false
+		}
+		result, _ := h.createErrorResponse(fmt.Sprintf("%s needs a changeTicket: every change must reference an approved change ticket", name))
+		return result, true
+	}
+
+	if _, err := h.changes.Validate(ctx, ticket); err != nil {
+		h.logger.WithError(err).WithField("tool", name).Warn("Rejected change ticket")
+		result, _ := h.createErrorResponse(err.Error())
+		return result, true
+	}
+	return nil, false
+}
+
+// annotateChange adds a note naming the change ticket to the resources a
+// successful change was made to, so their detail views show why they changed
+func (h *ToolHandler) annotateChange(ctx context.Context, name string, arguments map[string]interface{}) {
+	ticket := changeTicket(arguments)
+	if ticket == "" {
+		return
+	}
+
+	text := fmt.Sprintf("Changed by %s under change ticket %s", name, ticket)
+	for _, resource := range changedResources(arguments) {
+		if _, err := h.notes.Add(resource, text, h.callerRole(ctx), time.Now()); err != nil {
+			h.logger.WithError(err).WithField("resource", resource).Error("Failed to save change ticket note")
+		}
+	}
+}
+
+// changedResources returns the resources named by a tool call's arguments,
+// including every resource of a bulk call
+func changedResources(arguments map[string]interface{}) []string {
+	if resource := resourceFromArguments(arguments); resource != "" {
+		return []string{resource}
+	}
+	if resources := stringList(arguments["instanceIds"]); len(resources) > 0 {
+		return resources
+	}
+	return stringList(arguments["resourceIds"])
+}
@@ -0,0 +1,54 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"aws-mcp-server/internal/config"
+	"aws-mcp-server/internal/logging"
+	"aws-mcp-server/pkg/audit"
+	"aws-mcp-server/pkg/change"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChangeTickets(t *testing.T) {
+	h := NewToolHandler(&config.Config{Access: config.AccessConfig{Role: "operator"}}, nil, logging.NewLogger("error", "text"))
+	h.audit, _ = audit.Open("", 0)
+	var err error
+	h.changes, err = change.New(config.ChangeConfig{Required: true, Pattern: `^CHG-\d+$`}, logging.NewLogger("error", "text"))
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	result, err := h.CallTool(ctx, "start-gcp-instance", map[string]interface{}{"instanceId": "web-1"})
+	require.NoError(t, err)
+	assert.Equal(t, "start-gcp-instance needs a changeTicket: every change must reference an approved change ticket", decodeToolResult(t, result)["error"])
+
+	result, err = h.CallTool(ctx, "start-gcp-instance", map[string]interface{}{"instanceId": "web-1", "changeTicket": "tbd"})
+	require.NoError(t, err)
+	assert.Contains(t, decodeToolResult(t, result)["error"], "does not match the required format")
+
+	// Plans of disruptive tools need no ticket until they are confirmed
+	result, err = h.CallTool(ctx, "purge-sqs-queue", map[string]interface{}{})
+	require.NoError(t, err)
+	assert.NotContains(t, decodeToolResult(t, result)["error"], "changeTicket")
+
+	result, err = h.CallTool(ctx, "start-gcp-instance", map[string]interface{}{"instanceId": "web-1", "changeTicket": "CHG-7"})
+	require.NoError(t, err)
+	assert.Equal(t, "this cloud provider is not configured", decodeToolResult(t, result)["error"])
+
+	entries := h.audit.Between(time.Now().Add(-time.Minute), time.Now())
+	require.NotEmpty(t, entries)
+	assert.Equal(t, "CHG-7", entries[len(entries)-1].ChangeTicket)
+	assert.Empty(t, h.notes.For("web-1"), "failed changes are not annotated")
+
+	h.annotateChange(ctx, "bulk-stop-ec2-instances", map[string]interface{}{"instanceIds": []interface{}{"i-1", "i-2"}, "changeTicket": "CHG-7"})
+	for _, id := range []string{"i-1", "i-2"} {
+		annotations := h.notes.For(id)
+		require.Len(t, annotations, 1)
+		assert.Equal(t, "Changed by bulk-stop-ec2-instances under change ticket CHG-7", annotations[0].Text)
+		assert.Equal(t, "operator", annotations[0].CreatedBy)
+	}
+}
@@ -0,0 +1,182 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"aws-mcp-server/pkg/aws"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const (
+	// defaultMetricStatistic is used when no statistic is given
+	defaultMetricStatistic = "Average"
+	// metricPeriodPoints is the number of datapoints an automatic period aims for
+	metricPeriodPoints = 60
+	// maxMetricDatapoints keeps responses readable when an explicit period is small
+	maxMetricDatapoints = 1440
+)
+
+// statisticPattern matches the CloudWatch statistics: the basic ones and
+// percentiles or trimmed statistics such as p99, p99.9 and tm90
+var statisticPattern = regexp.MustCompile(`^(Average|Sum|Minimum|Maximum|SampleCount|IQM|(p|tm|tc|ts|wm)\d{1,2}(\.\d+)?|p100)$`)
+
+// getCloudWatchMetrics returns one statistic of a CloudWatch metric as a time series
+func (h *ToolHandler) getCloudWatchMetrics(ctx context.Context, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	now := time.Now()
+
+	params := aws.MetricDataParams{Statistic: defaultMetricStatistic}
+	params.Namespace, _ = arguments["namespace"].(string)
+	if params.Namespace == "" {
+		return h.createErrorResponse("namespace is required, e.g. AWS/EC2")
+	}
+	params.MetricName, _ = arguments["metricName"].(string)
+	if params.MetricName == "" {
+		return h.createErrorResponse("metricName is required, e.g. CPUUtilization")
+	}
+
+	if statistic, ok := arguments["statistic"].(string); ok && statistic != "" {
+		params.Statistic = statistic
+	}
+	if !statisticPattern.MatchString(params.Statistic) {
+		return h.createErrorResponse(fmt.Sprintf("invalid statistic %q, use Average, Sum, Minimum, Maximum, SampleCount or a percentile such as p99", params.Statistic))
+	}
+
+	var err error
+	if params.Dimensions, err = parseDimensions(arguments["dimensions"]); err != nil {
+		return h.createErrorResponse(err.Error())
+	}
+
+	if params.Start, params.End, err = parseWindowArgs(arguments, now, defaultMetricsWindow); err != nil {
+		return h.createErrorResponse(err.Error())
+	}
+
+	if period, ok := arguments["period"].(float64); ok && period > 0 {
+		params.Period = int32(period)
+		if !validMetricPeriod(params.Period) {
+			return h.createErrorResponse(fmt.Sprintf("invalid period %d: use 1, 5, 10, 30 or a multiple of 60 seconds", params.Period))
+		}
+		if points := params.End.Sub(params.Start) / (time.Duration(params.Period) * time.Second); points > maxMetricDatapoints {
+			return h.createErrorResponse(fmt.Sprintf("period %ds would return %d datapoints for this window; use a larger period or a shorter window (at most %d datapoints)",
+				params.Period, points, maxMetricDatapoints))
+		}
+	} else {
+		params.Period = metricPeriod(params.Start, params.End, now)
+	}
+
+	data, err := h.awsClient.GetMetricData(ctx, params)
+	if err != nil {
+		return h.createErrorResponse(fmt.Sprintf("failed to get CloudWatch metrics: %v", err))
+	}
+
+	points := make([][2]interface{}, 0, len(data.Datapoints))
+	values := make([]float64, 0, len(data.Datapoints))
+	for _, datapoint := range data.Datapoints {
+		points = append(points, [2]interface{}{h.times.Format(datapoint.Timestamp), datapoint.Value})
+		values = append(values, datapoint.Value)
+	}
+
+	result := map[string]interface{}{
+		"namespace":       params.Namespace,
+		"metric_name":     params.MetricName,
+		"dimensions":      params.Dimensions,
+		"statistic":       params.Statistic,
+		"period_seconds":  params.Period,
+		"datapoint_count": len(points),
+		"points":          points,
+		"range": map[string]interface{}{
+			"start": h.times.Format(params.Start),
+			"end":   h.times.Format(params.End),
+		},
+	}
+	for key, value := range pointStats(values) {
+		result[key] = value
+	}
+	if data.StatusCode == "PartialData" {
+		result["partial"] = true
+	}
+	if len(data.Messages) > 0 {
+		result["messages"] = data.Messages
+	}
+	if len(points) > 0 {
+		result["baseline"] = h.detectAnomalies(params, data.Datapoints, now)
+	}
+	if len(points) == 0 {
+		result["note"] = "No datapoints in this window; namespace, metric and dimension names are case-sensitive and every dimension of the metric must be given"
+	}
+
+	return h.createSuccessResponse(fmt.Sprintf("Retrieved %d datapoints for %s/%s", len(points), params.Namespace, params.MetricName), result)
+}
+
+// parseDimensions accepts dimensions as an object ({"InstanceId": "i-123"}) or,
+// for chat clients, as a string such as "InstanceId=i-123,AutoScalingGroupName=web"
+func parseDimensions(value interface{}) (map[string]string, error) {
+	dimensions := make(map[string]string)
+
+	switch v := value.(type) {
+	case nil:
+	case map[string]interface{}:
+		for name, raw := range v {
+			dimensionValue, ok := raw.(string)
+			if !ok {
+				return nil, fmt.Errorf("dimension %s must be a string", name)
+			}
+			dimensions[name] = dimensionValue
+		}
+	case string:
+		for _, pair := range strings.Split(v, ",") {
+			if strings.TrimSpace(pair) == "" {
+				continue
+			}
+			name, dimensionValue, found := strings.Cut(pair, "=")
+			if !found || strings.TrimSpace(name) == "" {
+				return nil, fmt.Errorf("invalid dimension %q, use Name=Value", pair)
+			}
+			dimensions[strings.TrimSpace(name)] = strings.TrimSpace(dimensionValue)
+		}
+	default:
+		return nil, fmt.Errorf("dimensions must be an object of name/value pairs")
+	}
+
+	if len(dimensions) > 30 {
+		return nil, fmt.Errorf("a metric has at most 30 dimensions, got %d", len(dimensions))
+	}
+	return dimensions, nil
+}
+
+// validMetricPeriod reports whether CloudWatch accepts a period: high-resolution
+// periods of 1, 5, 10 or 30 seconds, or any multiple of 60
+func validMetricPeriod(period int32) bool {
+	switch period {
+	case 1, 5, 10, 30:
+		return true
+	}
+	return period > 0 && period%60 == 0
+}
+
+// metricPeriod picks a period that returns about metricPeriodPoints datapoints,
+// in whole minutes and no finer than CloudWatch retains for the window's age:
+// 1 minute for 15 days, 5 minutes for 63 days and 1 hour after that
+func metricPeriod(start, end, now time.Time) int32 {
+	minutes := int32((end.Sub(start) / metricPeriodPoints).Minutes())
+
+	minimum := int32(1)
+	switch age := now.Sub(start); {
+	case age > 63*24*time.Hour:
+		minimum = 60
+	case age > 15*24*time.Hour:
+		minimum = 5
+	}
+
+	// Round up to the retention resolution so every point is a whole bucket
+	if minutes < minimum {
+		minutes = minimum
+	} else if remainder := minutes % minimum; remainder != 0 {
+		minutes += minimum - remainder
+	}
+	return minutes * 60
+}
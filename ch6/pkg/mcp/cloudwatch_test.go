@@ -0,0 +1,74 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"aws-mcp-server/internal/config"
+	"aws-mcp-server/internal/logging"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDimensions(t *testing.T) {
+	dimensions, err := parseDimensions(map[string]interface{}{"InstanceId": "i-123"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"InstanceId": "i-123"}, dimensions)
+
+	dimensions, err = parseDimensions("LoadBalancer=app/web/abc, TargetGroup=targetgroup/web/def")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"LoadBalancer": "app/web/abc", "TargetGroup": "targetgroup/web/def"}, dimensions)
+
+	dimensions, err = parseDimensions(nil)
+	require.NoError(t, err)
+	assert.Empty(t, dimensions)
+
+	_, err = parseDimensions("InstanceId")
+	assert.Error(t, err)
+	_, err = parseDimensions(map[string]interface{}{"Port": 80.0})
+	assert.Error(t, err)
+}
+
+func TestMetricPeriod(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, int32(60), metricPeriod(now.Add(-time.Hour), now, now))
+	assert.Equal(t, int32(60), metricPeriod(now.Add(-10*time.Minute), now, now))
+	assert.Equal(t, int32(24*60), metricPeriod(now.Add(-24*time.Hour), now, now))
+
+	// Older data is only kept at 5 minute and 1 hour resolution
+	start := now.Add(-20 * 24 * time.Hour)
+	assert.Equal(t, int32(300), metricPeriod(start, start.Add(time.Hour), now))
+	start = now.Add(-90 * 24 * time.Hour)
+	assert.Equal(t, int32(3600), metricPeriod(start, start.Add(time.Hour), now))
+	assert.Equal(t, int32(7200), metricPeriod(start, start.Add(90*time.Hour), now))
+
+	assert.True(t, validMetricPeriod(1))
+	assert.True(t, validMetricPeriod(300))
+	assert.False(t, validMetricPeriod(45))
+	assert.False(t, validMetricPeriod(0))
+}
+
+func TestGetCloudWatchMetricsValidation(t *testing.T) {
+	h := NewToolHandler(&config.Config{}, nil, logging.NewLogger("error", "text"))
+	ctx := context.Background()
+
+	tests := []struct {
+		arguments map[string]interface{}
+		error     string
+	}{
+		{map[string]interface{}{"metricName": "CPUUtilization"}, "namespace is required"},
+		{map[string]interface{}{"namespace": "AWS/EC2", "metricName": "CPUUtilization", "statistic": "Median"}, "invalid statistic"},
+		{map[string]interface{}{"namespace": "AWS/EC2", "metricName": "CPUUtilization", "period": 45.0}, "invalid period 45"},
+		{map[string]interface{}{"namespace": "AWS/EC2", "metricName": "CPUUtilization", "period": 60.0, "since": "2d"}, "would return 2880 datapoints"},
+		{map[string]interface{}{"namespace": "AWS/EC2", "metricName": "CPUUtilization", "since": "soon"}, "invalid since"},
+	}
+
+	for _, tt := range tests {
+		result, err := h.CallTool(ctx, "get-cloudwatch-metrics", tt.arguments)
+		require.NoError(t, err)
+		assert.Contains(t, decodeToolResult(t, result)["error"], tt.error)
+	}
+}
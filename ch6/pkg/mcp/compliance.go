@@ -0,0 +1,236 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"aws-mcp-server/pkg/types"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const (
+	// configRulesURI lists the AWS Config rules with their compliance
+	configRulesURI = "aws://config/rules"
+	// configRuleTemplate is the URI template of one rule with the resources
+	// it found non-compliant
+	configRuleTemplate = "aws://config/rules/{ruleName}"
+	// configNonCompliantURI lists the non-compliant resources of all rules
+	configNonCompliantURI = "aws://config/noncompliant-resources"
+	// configResourceLimit is the most non-compliant resources read per rule
+	configResourceLimit = 100
+)
+
+// readConfigRules lists the Config rules, non-compliant ones first, with
+// counts by compliance
+func (h *ResourceHandler) readConfigRules(ctx context.Context) (*mcp.ReadResourceResult, error) {
+	rules, err := h.awsClient.ListConfigRules(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	jsonData, err := json.MarshalIndent(formatConfigRules(rules), "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal Config rules data: %w", err)
+	}
+
+	return &mcp.ReadResourceResult{
+		Contents: []mcp.ResourceContents{
+			&mcp.TextResourceContents{
+				URI:      configRulesURI,
+				MIMEType: "application/json",
+				Text:     string(jsonData),
+			},
+		},
+	}, nil
+}
+
+// readConfigRule returns the rule in the URI with the resources it found
+// non-compliant
+func (h *ResourceHandler) readConfigRule(ctx context.Context, req resourceRequest) (*mcp.ReadResourceResult, error) {
+	uri, name := req.URI, req.Param("ruleName")
+	if name == "" {
+		return nil, fmt.Errorf("invalid Config rule URI %s, use %s", uri, configRuleTemplate)
+	}
+
+	rule, err := h.awsClient.GetConfigRule(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	evaluations := []types.ConfigEvaluation{}
+	if rule.Compliance == "NON_COMPLIANT" {
+		if evaluations, err = h.awsClient.ListNonCompliantResources(ctx, name, configResourceLimit); err != nil {
+			return nil, err
+		}
+	}
+
+	data := map[string]interface{}{
+		"rule":                   rule,
+		"noncompliant_resources": evaluations,
+		"remediation":            remediationHint(*rule),
+	}
+	if rule.NonCompliantCount > len(evaluations) || rule.CountCapped {
+		data["note"] = fmt.Sprintf("Only the first %d non-compliant resources are listed", len(evaluations))
+	}
+
+	jsonData, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal Config rule data: %w", err)
+	}
+
+	return &mcp.ReadResourceResult{
+		Contents: []mcp.ResourceContents{
+			&mcp.TextResourceContents{
+				URI:      uri,
+				MIMEType: "application/json",
+				Text:     string(jsonData),
+			},
+		},
+	}, nil
+}
+
+// readConfigNonCompliant lists the resources that fail at least one rule,
+// those failing the most rules first
+func (h *ResourceHandler) readConfigNonCompliant(ctx context.Context) (*mcp.ReadResourceResult, error) {
+	rules, err := h.awsClient.ListConfigRules(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var evaluations []types.ConfigEvaluation
+	failed := map[string]string{}
+	for _, rule := range rules {
+		if rule.Compliance != "NON_COMPLIANT" {
+			continue
+		}
+		ruleEvaluations, err := h.awsClient.ListNonCompliantResources(ctx, rule.Name, configResourceLimit)
+		if err != nil {
+			failed[rule.Name] = err.Error()
+			continue
+		}
+		evaluations = append(evaluations, ruleEvaluations...)
+	}
+
+	data := formatNonCompliantResources(evaluations, rules)
+	if len(failed) > 0 {
+		data["unavailable_rules"] = failed
+	}
+
+	jsonData, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal non-compliant resources data: %w", err)
+	}
+
+	return &mcp.ReadResourceResult{
+		Contents: []mcp.ResourceContents{
+			&mcp.TextResourceContents{
+				URI:      configNonCompliantURI,
+				MIMEType: "application/json",
+				Text:     string(jsonData),
+			},
+		},
+	}, nil
+}
+
+// formatConfigRules orders the rules by non-compliant resources, then by
+// name, and counts them by compliance
+func formatConfigRules(rules []types.ConfigRule) map[string]interface{} {
+	sorted := append([]types.ConfigRule(nil), rules...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].NonCompliantCount != sorted[j].NonCompliantCount {
+			return sorted[i].NonCompliantCount > sorted[j].NonCompliantCount
+		}
+		return sorted[i].Name < sorted[j].Name
+	})
+
+	byCompliance := make(map[string]int)
+	nonCompliant := []string{}
+	for _, rule := range sorted {
+		byCompliance[rule.Compliance]++
+		if rule.Compliance == "NON_COMPLIANT" {
+			nonCompliant = append(nonCompliant, rule.Name)
+		}
+	}
+
+	return map[string]interface{}{
+		"rules":                 sorted,
+		"total":                 len(rules),
+		"summary_by_compliance": byCompliance,
+		"noncompliant_rules":    nonCompliant,
+	}
+}
+
+// formatNonCompliantResources groups the evaluations by resource with the
+// rules each fails and their annotations
+func formatNonCompliantResources(evaluations []types.ConfigEvaluation, rules []types.ConfigRule) map[string]interface{} {
+	remediations := make(map[string]*types.ConfigRemediation, len(rules))
+	for _, rule := range rules {
+		remediations[rule.Name] = rule.Remediation
+	}
+
+	type failure struct {
+		Rule        string                   `json:"rule"`
+		Annotation  string                   `json:"annotation,omitempty"`
+		Remediation *types.ConfigRemediation `json:"remediation,omitempty"`
+	}
+	type resource struct {
+		ResourceType string    `json:"resource_type"`
+		ResourceID   string    `json:"resource_id"`
+		Rules        []failure `json:"rules"`
+	}
+
+	byKey := make(map[string]*resource)
+	var resources []*resource
+	byType := make(map[string]int)
+	for _, evaluation := range evaluations {
+		key := evaluation.ResourceType + "/" + evaluation.ResourceID
+		item, ok := byKey[key]
+		if !ok {
+			item = &resource{ResourceType: evaluation.ResourceType, ResourceID: evaluation.ResourceID}
+			byKey[key] = item
+			resources = append(resources, item)
+			byType[evaluation.ResourceType]++
+		}
+		item.Rules = append(item.Rules, failure{
+			Rule:        evaluation.RuleName,
+			Annotation:  evaluation.Annotation,
+			Remediation: remediations[evaluation.RuleName],
+		})
+	}
+	sort.SliceStable(resources, func(i, j int) bool {
+		if len(resources[i].Rules) != len(resources[j].Rules) {
+			return len(resources[i].Rules) > len(resources[j].Rules)
+		}
+		if resources[i].ResourceType != resources[j].ResourceType {
+			return resources[i].ResourceType < resources[j].ResourceType
+		}
+		return resources[i].ResourceID < resources[j].ResourceID
+	})
+	if resources == nil {
+		resources = []*resource{}
+	}
+
+	return map[string]interface{}{
+		"resources":       resources,
+		"total":           len(resources),
+		"summary_by_type": byType,
+		"per_rule_limit":  configResourceLimit,
+	}
+}
+
+// remediationHint describes how the resources failing a rule can be fixed:
+// with the rule's remediation action when it has one
+func remediationHint(rule types.ConfigRule) string {
+	switch {
+	case rule.Compliance != "NON_COMPLIANT":
+		return "No resources to remediate"
+	case rule.Remediation == nil:
+		return "The rule has no remediation action; fix the resources by hand or attach an SSM Automation document to the rule"
+	case rule.Remediation.Automatic:
+		return fmt.Sprintf("%s runs automatically on non-compliant resources; resources still listed failed it or are being retried", rule.Remediation.TargetID)
+	default:
+		return fmt.Sprintf("Run the remediation action %s on the resources from the AWS Config console or with StartRemediationExecution", rule.Remediation.TargetID)
+	}
+}
@@ -0,0 +1,82 @@
+package mcp
+
+import (
+	"encoding/json"
+	"testing"
+
+	"aws-mcp-server/pkg/render"
+	"aws-mcp-server/pkg/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatConfigRules(t *testing.T) {
+	data := formatConfigRules([]types.ConfigRule{
+		{Name: "encrypted-volumes", Compliance: "COMPLIANT"},
+		{Name: "s3-public-read", Compliance: "NON_COMPLIANT", NonCompliantCount: 2},
+		{Name: "restricted-ssh", Compliance: "NON_COMPLIANT", NonCompliantCount: 7},
+		{Name: "iam-password-policy", Compliance: "INSUFFICIENT_DATA"},
+	})
+
+	assert.Equal(t, 4, data["total"])
+	assert.Equal(t, map[string]int{"COMPLIANT": 1, "NON_COMPLIANT": 2, "INSUFFICIENT_DATA": 1}, data["summary_by_compliance"])
+	assert.Equal(t, []string{"restricted-ssh", "s3-public-read"}, data["noncompliant_rules"], "rules with the most non-compliant resources come first")
+
+	rules := data["rules"].([]types.ConfigRule)
+	assert.Equal(t, "encrypted-volumes", rules[2].Name, "rules without non-compliant resources are sorted by name")
+
+	renderer, err := render.New(nil)
+	require.NoError(t, err)
+	payload, err := json.Marshal(data)
+	require.NoError(t, err)
+	summary, ok := renderer.RenderJSON(configRulesURI, payload, nil)
+	require.True(t, ok)
+	assert.Equal(t, "4 Config rules: 1 COMPLIANT 1 INSUFFICIENT_DATA 2 NON_COMPLIANT; failing: restricted-ssh, s3-public-read", summary)
+}
+
+func TestFormatNonCompliantResources(t *testing.T) {
+	remediation := &types.ConfigRemediation{TargetType: "SSM_DOCUMENT", TargetID: "AWS-DisableIncomingSSHOnPort22"}
+	data := formatNonCompliantResources([]types.ConfigEvaluation{
+		{RuleName: "s3-public-read", ResourceType: "AWS::S3::Bucket", ResourceID: "logs", Annotation: "Bucket allows public read"},
+		{RuleName: "restricted-ssh", ResourceType: "AWS::EC2::SecurityGroup", ResourceID: "sg-1"},
+		{RuleName: "sg-unused", ResourceType: "AWS::EC2::SecurityGroup", ResourceID: "sg-1"},
+	}, []types.ConfigRule{
+		{Name: "restricted-ssh", Remediation: remediation},
+	})
+
+	assert.Equal(t, 2, data["total"])
+	assert.Equal(t, map[string]int{"AWS::S3::Bucket": 1, "AWS::EC2::SecurityGroup": 1}, data["summary_by_type"])
+
+	payload, err := json.Marshal(data["resources"])
+	require.NoError(t, err)
+	var resources []struct {
+		ResourceID string `json:"resource_id"`
+		Rules      []struct {
+			Rule        string                   `json:"rule"`
+			Annotation  string                   `json:"annotation"`
+			Remediation *types.ConfigRemediation `json:"remediation"`
+		} `json:"rules"`
+	}
+	require.NoError(t, json.Unmarshal(payload, &resources))
+	require.Len(t, resources, 2)
+	assert.Equal(t, "sg-1", resources[0].ResourceID, "resources failing the most rules come first")
+	require.Len(t, resources[0].Rules, 2)
+	assert.Equal(t, "AWS-DisableIncomingSSHOnPort22", resources[0].Rules[0].Remediation.TargetID)
+	assert.Nil(t, resources[0].Rules[1].Remediation)
+	assert.Equal(t, "Bucket allows public read", resources[1].Rules[0].Annotation)
+
+	empty := formatNonCompliantResources(nil, nil)
+	payload, err = json.Marshal(empty["resources"])
+	require.NoError(t, err)
+	assert.Equal(t, "[]", string(payload))
+}
+
+func TestRemediationHint(t *testing.T) {
+	assert.Equal(t, "No resources to remediate", remediationHint(types.ConfigRule{Compliance: "COMPLIANT"}))
+	assert.Contains(t, remediationHint(types.ConfigRule{Compliance: "NON_COMPLIANT"}), "has no remediation action")
+	assert.Contains(t, remediationHint(types.ConfigRule{Compliance: "NON_COMPLIANT", Remediation: &types.ConfigRemediation{TargetID: "AWS-EnableS3BucketEncryption"}}),
+		"Run the remediation action AWS-EnableS3BucketEncryption")
+	assert.Contains(t, remediationHint(types.ConfigRule{Compliance: "NON_COMPLIANT", Remediation: &types.ConfigRemediation{TargetID: "AWS-EnableS3BucketEncryption", Automatic: true}}),
+		"runs automatically")
+}
@@ -0,0 +1,28 @@
+package mcp
+
+import (
+	"aws-mcp-server/pkg/types"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// isConfirmed reports whether the caller explicitly confirmed a disruptive action
+// by passing confirm=true. Disruptive tools first return a plan with warnings and
+// only execute once the AI (or the human behind it) re-invokes them with confirmation.
+func isConfirmed(arguments map[string]interface{}) bool {
+	confirmed, _ := arguments["confirm"].(bool)
+	return confirmed
+}
+
+// createConfirmationResponse describes what a disruptive action would do and asks
+// the caller to re-invoke the tool with confirm=true
+func (h *ToolHandler) createConfirmationResponse(action string, plan map[string]interface{}, warnings []string) (*mcp.CallToolResult, error) {
+	response := types.NewToolResponse(false, h.times.Now(), map[string]interface{}{
+		"confirmation_required": true,
+		"action":                action,
+		"plan":                  plan,
+	})
+	response.Message = "This action is disruptive. Review the plan and warnings, then call the tool again with confirm=true to proceed."
+	response.Warnings = warnings
+	return toolResult(response)
+}
@@ -0,0 +1,378 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/netip"
+	"sort"
+	"strings"
+
+	"aws-mcp-server/pkg/aws"
+	"aws-mcp-server/pkg/types"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// Hop results of a connectivity diagnosis
+const (
+	hopPass    = "pass"
+	hopBlocked = "blocked"
+	hopSkipped = "skipped"
+)
+
+// defaultConnectivitySource is anywhere on the internet
+const defaultConnectivitySource = "0.0.0.0/0"
+
+// responsePorts are client ports the responses of a connection go back to:
+// the start of the Linux and Windows ephemeral ranges and the top of the range.
+// Network ACLs are stateless, so their outbound rules must allow them.
+var responsePorts = []int32{32768, 49152, 65535}
+
+// protocolNumbers maps protocol names to the IANA numbers network ACLs use
+var protocolNumbers = map[string]string{
+	"tcp": "6",
+	"udp": "17",
+}
+
+// connectivityHop is the verdict of one hop on the path to an instance port
+type connectivityHop struct {
+	Hop      string `json:"hop"`
+	Resource string `json:"resource,omitempty"`
+	Status   string `json:"status"`
+	Detail   string `json:"detail"`
+}
+
+// diagnoseConnectivity checks every hop between a source and an instance port
+// and reports the first one that blocks traffic
+func (h *ToolHandler) diagnoseConnectivity(ctx context.Context, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	instanceID, ok := arguments["instanceId"].(string)
+	if !ok || instanceID == "" {
+		return h.createErrorResponse("instanceId is required")
+	}
+
+	portArg, ok := arguments["port"].(float64)
+	if !ok || portArg < 1 || portArg > 65535 || portArg != float64(int32(portArg)) {
+		return h.createErrorResponse("port is required and must be between 1 and 65535")
+	}
+	port := int32(portArg)
+
+	protocol := "tcp"
+	if value, ok := arguments["protocol"].(string); ok && value != "" {
+		protocol = strings.ToLower(value)
+	}
+	if _, ok := protocolNumbers[protocol]; !ok {
+		return h.createErrorResponse(fmt.Sprintf("invalid protocol %q, use tcp or udp", protocol))
+	}
+
+	sourceArg, _ := arguments["source"].(string)
+	if sourceArg == "" {
+		sourceArg = defaultConnectivitySource
+	}
+	source, err := parseConnectivitySource(sourceArg)
+	if err != nil {
+		return h.createErrorResponse(err.Error())
+	}
+
+	network, err := h.awsClient.GetInstanceNetwork(ctx, instanceID)
+	if err != nil {
+		return h.createErrorResponse(fmt.Sprintf("failed to get the network path of %s: %v", instanceID, err))
+	}
+
+	hops := connectivityHops(*network, source, protocol, port)
+	data := map[string]interface{}{
+		"instanceId": instanceID,
+		"source":     source.String(),
+		"protocol":   protocol,
+		"port":       port,
+		"reachable":  true,
+		"hops":       hops,
+	}
+	for _, hop := range hops {
+		if hop.Status == hopBlocked {
+			data["reachable"] = false
+			data["blocked_at"] = hop.Hop
+			data["reason"] = hop.Detail
+			break
+		}
+	}
+
+	if useAnalyzer, _ := arguments["useReachabilityAnalyzer"].(bool); useAnalyzer {
+		data["reachability_analyzer"] = h.runReachabilityAnalyzer(ctx, *network, source, protocol, port)
+	}
+
+	message := fmt.Sprintf("%s/%d on %s is reachable from %s", protocol, port, instanceID, source)
+	if blockedAt, ok := data["blocked_at"]; ok {
+		message = fmt.Sprintf("%s/%d on %s is blocked at %s", protocol, port, instanceID, blockedAt)
+	}
+	return h.createSuccessResponse(message, data)
+}
+
+// runReachabilityAnalyzer confirms the diagnosis with VPC Reachability
+// Analyzer, which traces internet sources from the VPC's internet gateway.
+// Failures are reported in the result instead of failing the diagnosis.
+func (h *ToolHandler) runReachabilityAnalyzer(ctx context.Context, network types.InstanceNetwork, source netip.Prefix, protocol string, port int32) map[string]interface{} {
+	if !isInternetSource(network, source) {
+		return map[string]interface{}{"status": hopSkipped, "note": "Reachability Analyzer is only run for internet sources, from the internet gateway"}
+	}
+	if network.InternetGateway == "" {
+		return map[string]interface{}{"status": hopSkipped, "note": "The VPC has no internet gateway to trace from"}
+	}
+
+	sourceIP := ""
+	if source.IsSingleIP() {
+		sourceIP = source.Addr().String()
+	}
+
+	analysis, err := h.awsClient.RunReachabilityAnalysis(ctx, network.InternetGateway, sourceIP, network.InstanceID, protocol, port)
+	if errors.Is(err, aws.ErrReachabilityTimeout) {
+		return map[string]interface{}{"status": "timeout", "analysisId": analysis.AnalysisID, "note": "The analysis did not finish in time and was removed; the hop checks above still apply"}
+	}
+	if err != nil {
+		return map[string]interface{}{"status": "error", "error": err.Error()}
+	}
+
+	return map[string]interface{}{
+		"status":       analysis.Status,
+		"message":      analysis.Message,
+		"path_found":   analysis.PathFound,
+		"explanations": analysis.Explanations,
+	}
+}
+
+// parseConnectivitySource accepts an IPv4 address or CIDR block
+func parseConnectivitySource(value string) (netip.Prefix, error) {
+	value = strings.TrimSpace(value)
+	if strings.Contains(value, "/") {
+		prefix, err := netip.ParsePrefix(value)
+		if err != nil || !prefix.Addr().Is4() {
+			return netip.Prefix{}, fmt.Errorf("invalid source %q, use an IPv4 address or CIDR block such as 203.0.113.0/24", value)
+		}
+		return prefix.Masked(), nil
+	}
+
+	addr, err := netip.ParseAddr(value)
+	if err != nil || !addr.Is4() {
+		return netip.Prefix{}, fmt.Errorf("invalid source %q, use an IPv4 address or CIDR block such as 203.0.113.0/24", value)
+	}
+	return netip.PrefixFrom(addr, 32), nil
+}
+
+// connectivityHops evaluates each hop in the order traffic crosses them: the
+// instance itself, its public address and the internet gateway for internet
+// sources, the subnet's network ACL, the security groups, and on the way back
+// the network ACL again and the subnet's route table. A rule only applies to
+// a source range when it covers the whole range.
+func connectivityHops(network types.InstanceNetwork, source netip.Prefix, protocol string, port int32) []connectivityHop {
+	internet := isInternetSource(network, source)
+	hops := make([]connectivityHop, 0, 7)
+
+	// Instance
+	instanceHop := connectivityHop{Hop: "instance", Resource: network.InstanceID, Status: hopPass, Detail: "Instance is running"}
+	if network.State != "running" {
+		instanceHop.Status = hopBlocked
+		instanceHop.Detail = fmt.Sprintf("Instance is %s; start it first", network.State)
+	}
+	hops = append(hops, instanceHop)
+
+	// Public IP and internet gateway
+	publicIPHop := connectivityHop{Hop: "public_ip", Status: hopSkipped, Detail: "Source is not on the internet"}
+	gatewayHop := connectivityHop{Hop: "internet_gateway", Status: hopSkipped, Detail: "Source is not on the internet"}
+	if internet {
+		if network.PublicIP != "" {
+			publicIPHop.Status = hopPass
+			publicIPHop.Resource = network.PublicIP
+			publicIPHop.Detail = fmt.Sprintf("Instance has public IP %s", network.PublicIP)
+		} else {
+			publicIPHop.Status = hopBlocked
+			publicIPHop.Detail = "Instance has no public IP; associate an Elastic IP or reach it through a load balancer"
+		}
+
+		if network.InternetGateway != "" {
+			gatewayHop.Status = hopPass
+			gatewayHop.Resource = network.InternetGateway
+			gatewayHop.Detail = fmt.Sprintf("Internet gateway %s is attached to %s", network.InternetGateway, network.VpcID)
+		} else {
+			gatewayHop.Status = hopBlocked
+			gatewayHop.Resource = network.VpcID
+			gatewayHop.Detail = fmt.Sprintf("No internet gateway is attached to %s", network.VpcID)
+		}
+	}
+	hops = append(hops, publicIPHop, gatewayHop)
+
+	// Network ACL, inbound
+	inboundHop := connectivityHop{Hop: "network_acl_inbound", Resource: network.NetworkACL.ID}
+	entry := firstACLEntry(network.NetworkACL, false, protocol, port, source)
+	switch {
+	case entry == nil:
+		inboundHop.Status = hopBlocked
+		inboundHop.Detail = fmt.Sprintf("No inbound rule matches %s/%d from %s, so it is denied", protocol, port, source)
+	case !entry.Allow:
+		inboundHop.Status = hopBlocked
+		inboundHop.Detail = fmt.Sprintf("Inbound rule %d denies %s/%d from %s; add an allow rule with a lower number", entry.RuleNumber, protocol, port, entry.CIDR)
+	default:
+		inboundHop.Status = hopPass
+		inboundHop.Detail = fmt.Sprintf("Inbound rule %d allows %s/%d from %s", entry.RuleNumber, protocol, port, entry.CIDR)
+	}
+	hops = append(hops, inboundHop)
+
+	// Security groups
+	hops = append(hops, securityGroupHop(network.SecurityGroups, source, protocol, port))
+
+	// Network ACL, outbound responses
+	outboundHop := connectivityHop{Hop: "network_acl_outbound", Resource: network.NetworkACL.ID, Status: hopPass,
+		Detail: fmt.Sprintf("Outbound rules allow responses to %s on ephemeral ports", source)}
+	for _, responsePort := range responsePorts {
+		entry := firstACLEntry(network.NetworkACL, true, protocol, responsePort, source)
+		if entry == nil || !entry.Allow {
+			outboundHop.Status = hopBlocked
+			rule := "no outbound rule matches"
+			if entry != nil {
+				rule = fmt.Sprintf("outbound rule %d denies them", entry.RuleNumber)
+			}
+			outboundHop.Detail = fmt.Sprintf("Responses to %s on port %d are dropped because %s; network ACLs are stateless, so allow ephemeral ports 1024-65535 outbound",
+				source, responsePort, rule)
+			break
+		}
+	}
+	hops = append(hops, outboundHop)
+
+	// Route table, for the responses
+	hops = append(hops, routeHop(network, source, internet))
+
+	return hops
+}
+
+// securityGroupHop checks whether any security group of the instance allows
+// the traffic. Security groups are stateful, so responses need no rule.
+func securityGroupHop(groups []types.SecurityGroup, source netip.Prefix, protocol string, port int32) connectivityHop {
+	hop := connectivityHop{Hop: "security_groups", Status: hopBlocked}
+
+	ids := make([]string, 0, len(groups))
+	var openTo []string
+	for _, group := range groups {
+		ids = append(ids, group.ID)
+		for _, rule := range group.Ingress {
+			if !ruleCoversPort(rule.Protocol, rule.FromPort, rule.ToPort, protocol, port) {
+				continue
+			}
+			for _, cidr := range rule.CIDRs {
+				if prefixCovers(cidr, source) {
+					hop.Status = hopPass
+					hop.Resource = group.ID
+					hop.Detail = fmt.Sprintf("%s allows %s/%d from %s", group.ID, protocol, port, cidr)
+					return hop
+				}
+				openTo = append(openTo, cidr)
+			}
+			openTo = append(openTo, rule.SourceGroups...)
+		}
+	}
+
+	hop.Resource = strings.Join(ids, ",")
+	if len(groups) == 0 {
+		hop.Detail = "Instance has no security groups"
+		return hop
+	}
+	hop.Detail = fmt.Sprintf("No security group allows %s/%d from %s; add an inbound rule to one of %s with authorize-security-group-ingress", protocol, port, source, hop.Resource)
+	if len(openTo) > 0 {
+		hop.Detail += fmt.Sprintf(" (the port is only open to %s)", strings.Join(openTo, ", "))
+	}
+	return hop
+}
+
+// routeHop checks that the subnet routes responses back to the source
+func routeHop(network types.InstanceNetwork, source netip.Prefix, internet bool) connectivityHop {
+	hop := connectivityHop{Hop: "route_table", Resource: network.RouteTable.ID, Status: hopBlocked}
+
+	route := longestRoute(network.RouteTable, source)
+	switch {
+	case route == nil:
+		hop.Detail = fmt.Sprintf("No route covers %s, so responses cannot get back", source)
+	case route.State == "blackhole":
+		hop.Detail = fmt.Sprintf("Route %s points to %s, which no longer exists (blackhole)", route.Destination, route.Target)
+	case internet && strings.HasPrefix(route.Target, "nat-"):
+		hop.Detail = fmt.Sprintf("Route %s goes through NAT gateway %s, so this is a private subnet and connections from the internet cannot be answered; "+
+			"use a load balancer in a public subnet or move the instance", route.Destination, route.Target)
+	case internet && !strings.HasPrefix(route.Target, "igw-"):
+		hop.Detail = fmt.Sprintf("Route %s goes to %s instead of an internet gateway", route.Destination, route.Target)
+	case !internet && strings.HasPrefix(route.Target, "igw-"):
+		hop.Detail = fmt.Sprintf("Responses to private source %s would go to internet gateway %s; no VPN, peering or transit gateway route covers it", source, route.Target)
+	case route.Target == "local":
+		hop.Status = hopPass
+		hop.Detail = fmt.Sprintf("Local route %s covers the source", route.Destination)
+	case internet:
+		hop.Status = hopPass
+		hop.Detail = fmt.Sprintf("Route %s goes to internet gateway %s", route.Destination, route.Target)
+	default:
+		hop.Status = hopPass
+		hop.Detail = fmt.Sprintf("Route %s goes to %s; hops beyond it are not checked", route.Destination, route.Target)
+	}
+	return hop
+}
+
+// isInternetSource reports whether a source is outside the VPC and not a
+// private address reached through a VPN, peering or transit gateway
+func isInternetSource(network types.InstanceNetwork, source netip.Prefix) bool {
+	if route := longestRoute(network.RouteTable, source); route != nil && route.Target == "local" {
+		return false
+	}
+	return !source.Addr().IsPrivate()
+}
+
+// firstACLEntry returns the network ACL rule that decides the traffic: the
+// lowest numbered rule that matches, or nil when none does
+func firstACLEntry(acl types.NetworkACL, egress bool, protocol string, port int32, peer netip.Prefix) *types.NetworkACLEntry {
+	entries := make([]types.NetworkACLEntry, 0, len(acl.Entries))
+	for _, entry := range acl.Entries {
+		if entry.Egress == egress {
+			entries = append(entries, entry)
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].RuleNumber < entries[j].RuleNumber })
+
+	for i, entry := range entries {
+		if ruleCoversPort(entry.Protocol, entry.FromPort, entry.ToPort, protocol, port) && prefixCovers(entry.CIDR, peer) {
+			return &entries[i]
+		}
+	}
+	return nil
+}
+
+// ruleCoversPort reports whether a rule's protocol, given as a name, an IANA
+// number or -1 for all, and port range include the traffic
+func ruleCoversPort(ruleProtocol string, fromPort, toPort int32, protocol string, port int32) bool {
+	if ruleProtocol == "-1" {
+		return true
+	}
+	if ruleProtocol != protocol && ruleProtocol != protocolNumbers[protocol] {
+		return false
+	}
+	return fromPort <= port && port <= toPort
+}
+
+// prefixCovers reports whether a CIDR block contains the whole source range
+func prefixCovers(cidr string, source netip.Prefix) bool {
+	prefix, err := netip.ParsePrefix(cidr)
+	if err != nil || prefix.Addr().Is4() != source.Addr().Is4() {
+		return false
+	}
+	return prefix.Bits() <= source.Bits() && prefix.Contains(source.Addr())
+}
+
+// longestRoute returns the most specific route covering the source
+func longestRoute(table types.RouteTable, source netip.Prefix) *types.Route {
+	var best *types.Route
+	bestBits := -1
+	for i, route := range table.Routes {
+		prefix, err := netip.ParsePrefix(route.Destination)
+		if err != nil || !prefixCovers(route.Destination, source) {
+			continue
+		}
+		if prefix.Bits() > bestBits {
+			best = &table.Routes[i]
+			bestBits = prefix.Bits()
+		}
+	}
+	return best
+}
@@ -0,0 +1,165 @@
+package mcp
+
+import (
+	"context"
+	"net/netip"
+	"testing"
+
+	"aws-mcp-server/internal/config"
+	"aws-mcp-server/internal/logging"
+	"aws-mcp-server/pkg/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// publicWebServer is an instance in a public subnet serving HTTPS to the internet
+func publicWebServer() types.InstanceNetwork {
+	return types.InstanceNetwork{
+		InstanceID: "i-web",
+		State:      "running",
+		PublicIP:   "54.1.2.3",
+		VpcID:      "vpc-1",
+		SubnetID:   "subnet-public",
+		SecurityGroups: []types.SecurityGroup{
+			{ID: "sg-ssh", Ingress: []types.SecurityGroupRule{{Protocol: "tcp", FromPort: 22, ToPort: 22, CIDRs: []string{"10.0.0.0/8"}}}},
+			{ID: "sg-web", Ingress: []types.SecurityGroupRule{{Protocol: "tcp", FromPort: 443, ToPort: 443, CIDRs: []string{"0.0.0.0/0"}}}},
+		},
+		NetworkACL: types.NetworkACL{
+			ID:      "acl-1",
+			Default: true,
+			Entries: []types.NetworkACLEntry{
+				{RuleNumber: 100, Protocol: "-1", CIDR: "0.0.0.0/0", Allow: true},
+				{RuleNumber: 32767, Protocol: "-1", CIDR: "0.0.0.0/0"},
+				{RuleNumber: 100, Egress: true, Protocol: "-1", CIDR: "0.0.0.0/0", Allow: true},
+				{RuleNumber: 32767, Egress: true, Protocol: "-1", CIDR: "0.0.0.0/0"},
+			},
+		},
+		RouteTable: types.RouteTable{
+			ID: "rtb-public",
+			Routes: []types.Route{
+				{Destination: "10.0.0.0/16", Target: "local", State: "active"},
+				{Destination: "0.0.0.0/0", Target: "igw-1", State: "active"},
+			},
+		},
+		InternetGateway: "igw-1",
+	}
+}
+
+// hopStatuses maps each hop to its status
+func hopStatuses(hops []connectivityHop) map[string]string {
+	statuses := make(map[string]string, len(hops))
+	for _, hop := range hops {
+		statuses[hop.Hop] = hop.Status
+	}
+	return statuses
+}
+
+// firstBlocked returns the first blocking hop, or nil
+func firstBlocked(hops []connectivityHop) *connectivityHop {
+	for i := range hops {
+		if hops[i].Status == hopBlocked {
+			return &hops[i]
+		}
+	}
+	return nil
+}
+
+func TestConnectivityHopsReachable(t *testing.T) {
+	internet := netip.MustParsePrefix("0.0.0.0/0")
+
+	hops := connectivityHops(publicWebServer(), internet, "tcp", 443)
+	assert.Nil(t, firstBlocked(hops))
+	assert.Equal(t, map[string]string{
+		"instance":             hopPass,
+		"public_ip":            hopPass,
+		"internet_gateway":     hopPass,
+		"network_acl_inbound":  hopPass,
+		"security_groups":      hopPass,
+		"network_acl_outbound": hopPass,
+		"route_table":          hopPass,
+	}, hopStatuses(hops))
+
+	// Sources inside the VPC skip the internet hops
+	hops = connectivityHops(publicWebServer(), netip.MustParsePrefix("10.0.3.7/32"), "tcp", 22)
+	assert.Nil(t, firstBlocked(hops))
+	assert.Equal(t, hopSkipped, hopStatuses(hops)["public_ip"])
+	assert.Equal(t, hopSkipped, hopStatuses(hops)["internet_gateway"])
+}
+
+func TestConnectivityHopsBlocked(t *testing.T) {
+	internet := netip.MustParsePrefix("0.0.0.0/0")
+
+	// Security group only open to the VPC
+	blocked := firstBlocked(connectivityHops(publicWebServer(), internet, "tcp", 22))
+	require.NotNil(t, blocked)
+	assert.Equal(t, "security_groups", blocked.Hop)
+	assert.Contains(t, blocked.Detail, "only open to 10.0.0.0/8")
+
+	// Inbound deny rule ahead of the allow rule
+	network := publicWebServer()
+	network.NetworkACL.Entries = append(network.NetworkACL.Entries,
+		types.NetworkACLEntry{RuleNumber: 90, Protocol: "6", FromPort: 443, ToPort: 443, CIDR: "0.0.0.0/0"})
+	blocked = firstBlocked(connectivityHops(network, internet, "tcp", 443))
+	require.NotNil(t, blocked)
+	assert.Equal(t, "network_acl_inbound", blocked.Hop)
+	assert.Contains(t, blocked.Detail, "rule 90 denies")
+
+	// Outbound rules that do not allow responses on ephemeral ports
+	network = publicWebServer()
+	network.NetworkACL.Entries[2] = types.NetworkACLEntry{RuleNumber: 100, Egress: true, Protocol: "6", FromPort: 443, ToPort: 443, CIDR: "0.0.0.0/0", Allow: true}
+	blocked = firstBlocked(connectivityHops(network, internet, "tcp", 443))
+	require.NotNil(t, blocked)
+	assert.Equal(t, "network_acl_outbound", blocked.Hop)
+	assert.Contains(t, blocked.Detail, "port 32768")
+
+	// Private subnet behind a NAT gateway without a public IP
+	network = publicWebServer()
+	network.PublicIP = ""
+	network.RouteTable.Routes[1].Target = "nat-1"
+	hops := connectivityHops(network, internet, "tcp", 443)
+	blocked = firstBlocked(hops)
+	require.NotNil(t, blocked)
+	assert.Equal(t, "public_ip", blocked.Hop)
+	assert.Equal(t, hopBlocked, hopStatuses(hops)["route_table"])
+
+	// Stopped instance
+	network = publicWebServer()
+	network.State = "stopped"
+	blocked = firstBlocked(connectivityHops(network, internet, "tcp", 443))
+	require.NotNil(t, blocked)
+	assert.Equal(t, "instance", blocked.Hop)
+
+	// Private source without a route back
+	blocked = firstBlocked(connectivityHops(publicWebServer(), netip.MustParsePrefix("192.168.1.10/32"), "tcp", 443))
+	require.NotNil(t, blocked)
+	assert.Equal(t, "route_table", blocked.Hop)
+}
+
+func TestParseConnectivitySource(t *testing.T) {
+	source, err := parseConnectivitySource("203.0.113.9")
+	require.NoError(t, err)
+	assert.Equal(t, "203.0.113.9/32", source.String())
+
+	source, err = parseConnectivitySource("203.0.113.9/24")
+	require.NoError(t, err)
+	assert.Equal(t, "203.0.113.0/24", source.String())
+
+	for _, value := range []string{"example.com", "2001:db8::1", "10.0.0.0/33"} {
+		_, err = parseConnectivitySource(value)
+		assert.Error(t, err, value)
+	}
+}
+
+func TestDiagnoseConnectivityValidatesArguments(t *testing.T) {
+	h := NewToolHandler(&config.Config{}, nil, logging.NewLogger("error", "text"))
+	ctx := context.Background()
+
+	result, err := h.CallTool(ctx, "diagnose-connectivity", map[string]interface{}{"instanceId": "i-web", "port": float64(70000)})
+	require.NoError(t, err)
+	assert.Equal(t, "port is required and must be between 1 and 65535", decodeToolResult(t, result)["error"])
+
+	result, err = h.CallTool(ctx, "diagnose-connectivity", map[string]interface{}{"instanceId": "i-web", "port": float64(443), "protocol": "icmp"})
+	require.NoError(t, err)
+	assert.Equal(t, `invalid protocol "icmp", use tcp or udp`, decodeToolResult(t, result)["error"])
+}
@@ -0,0 +1,129 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// consoleTailLines is how many of the last console output lines are returned
+// unless the caller asks for another number
+const consoleTailLines = 200
+
+// bootFailures are console messages of an instance that failed to boot, with
+// what they usually mean
+var bootFailures = []struct {
+	pattern string
+	hint    string
+}{
+	{"kernel panic", "the kernel panicked; a recent kernel or initramfs update is the usual cause, boot the previous kernel by fixing the root volume from a rescue instance"},
+	{"unable to mount root fs", "the root file system cannot be mounted; the root volume or its drivers (NVMe, ENA) may be missing from the initramfs"},
+	{"you are in emergency mode", "systemd fell back to emergency mode, usually because an /etc/fstab entry failed to mount; mark volumes not needed to boot with nofail"},
+	{"give root password for maintenance", "the boot stopped for maintenance, usually after a failed file system check or mount"},
+	{"dependency failed for", "a unit needed to boot failed, often the mount of a detached or renamed volume"},
+	{"no space left on device", "a file system is full"},
+	{"out of memory: kill", "the OOM killer ran; the workload does not fit the instance type"},
+	{"failed to start", "a systemd service failed to start"},
+}
+
+// getInstanceConsoleOutput returns the last lines of the serial console output
+// of an instance, with the boot failures they show
+func (h *ToolHandler) getInstanceConsoleOutput(ctx context.Context, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	instanceID, _ := arguments["instanceId"].(string)
+	if instanceID == "" {
+		return h.createErrorResponse("instanceId is required")
+	}
+	tail := consoleTailLines
+	if requested, ok := arguments["tailLines"].(float64); ok && requested > 0 {
+		tail = int(requested)
+	}
+	latest, _ := arguments["latest"].(bool)
+
+	output, captured, err := h.awsClient.GetConsoleOutput(ctx, instanceID, latest)
+	if err != nil {
+		return h.createErrorResponse(err.Error())
+	}
+	if strings.TrimSpace(output) == "" {
+		return h.createErrorResponse(fmt.Sprintf("%s has no console output yet; EC2 captures it a few minutes after the instance starts, "+
+			"or right away with latest=true on Nitro instances", instanceID))
+	}
+
+	lines, truncated := tailLines(output, tail)
+	data := map[string]interface{}{
+		"instanceId":   instanceID,
+		"lines":        lines,
+		"truncated":    truncated,
+		"bootProblems": detectBootFailures(output),
+	}
+	if captured != nil {
+		data["capturedAt"] = h.times.Format(*captured)
+	}
+	if !latest {
+		data["note"] = "The output is the snapshot EC2 took at the last boot, stop or termination; pass latest=true on Nitro instances for the output as of now"
+	}
+	return h.createSuccessResponse("Console output retrieved successfully", data)
+}
+
+// getInstanceScreenshot captures the console of a running instance, e.g. to
+// see a Windows instance stuck on a boot or update screen. The JPEG is
+// returned as image content after the response.
+func (h *ToolHandler) getInstanceScreenshot(ctx context.Context, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	instanceID, _ := arguments["instanceId"].(string)
+	if instanceID == "" {
+		return h.createErrorResponse("instanceId is required")
+	}
+
+	image, err := h.awsClient.GetConsoleScreenshot(ctx, instanceID)
+	if err != nil {
+		return h.createErrorResponse(err.Error())
+	}
+	if image == "" {
+		return h.createErrorResponse(fmt.Sprintf("EC2 returned no screenshot of %s", instanceID))
+	}
+
+	result, err := h.createSuccessResponse("Console screenshot captured successfully", map[string]interface{}{
+		"instanceId": instanceID,
+		"mimeType":   "image/jpeg",
+		"capturedAt": h.times.Now(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	result.Content = append(result.Content, mcp.NewImageContent(image, "image/jpeg"))
+	return result, nil
+}
+
+// tailLines returns the last n lines of console output, without carriage
+// returns, and whether earlier lines were left out
+func tailLines(output string, n int) ([]string, bool) {
+	lines := strings.Split(strings.TrimRight(strings.ReplaceAll(output, "\r", ""), "\n"), "\n")
+	if len(lines) <= n {
+		return lines, false
+	}
+	return lines[len(lines)-n:], true
+}
+
+// detectBootFailures reports each known boot failure found in the console
+// output once, with the last line showing it, which belongs to the latest boot
+func detectBootFailures(output string) []map[string]interface{} {
+	last := make(map[int]string)
+	for _, line := range strings.Split(strings.ReplaceAll(output, "\r", ""), "\n") {
+		lower := strings.ToLower(line)
+		for i, failure := range bootFailures {
+			if strings.Contains(lower, failure.pattern) {
+				last[i] = strings.TrimSpace(line)
+				break
+			}
+		}
+	}
+
+	problems := []map[string]interface{}{}
+	for i, failure := range bootFailures {
+		if line, ok := last[i]; ok {
+			problems = append(problems, map[string]interface{}{"line": line, "hint": failure.hint})
+		}
+	}
+	return problems
+}
@@ -0,0 +1,61 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"aws-mcp-server/internal/config"
+	"aws-mcp-server/internal/logging"
+	"aws-mcp-server/pkg/render"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const failedBootOutput = "[    0.000000] Linux version 5.10.0\r\n" +
+	"[  OK  ] Reached target Local File Systems (Pre).\r\n" +
+	"[ TIME ] Timed out waiting for device /dev/xvdf.\r\n" +
+	"[DEPEND] Dependency failed for /data.\r\n" +
+	"[DEPEND] Dependency failed for Local File Systems.\r\n" +
+	"You are in emergency mode. After logging in, type \"journalctl -xb\" to view system logs.\r\n"
+
+func TestTailLines(t *testing.T) {
+	lines, truncated := tailLines(failedBootOutput, 2)
+	assert.True(t, truncated)
+	assert.Equal(t, []string{
+		"[DEPEND] Dependency failed for Local File Systems.",
+		`You are in emergency mode. After logging in, type "journalctl -xb" to view system logs.`,
+	}, lines)
+
+	lines, truncated = tailLines(failedBootOutput, consoleTailLines)
+	assert.False(t, truncated)
+	assert.Len(t, lines, 6)
+}
+
+func TestDetectBootFailures(t *testing.T) {
+	problems := detectBootFailures(failedBootOutput)
+	require.Len(t, problems, 2)
+	assert.Contains(t, problems[0]["hint"], "emergency mode")
+	assert.Equal(t, "[DEPEND] Dependency failed for Local File Systems.", problems[1]["line"], "the last line of a failure is reported")
+
+	assert.Empty(t, detectBootFailures("[  OK  ] Reached target Multi-User System.\n"))
+
+	renderer, err := render.New(nil)
+	require.NoError(t, err)
+	payload, err := json.Marshal(map[string]interface{}{"instanceId": "i-1", "lines": []string{"a", "b"}, "bootProblems": problems})
+	require.NoError(t, err)
+	summary, ok := renderer.RenderJSON("get-instance-console-output", payload, nil)
+	require.True(t, ok)
+	assert.Equal(t, "2 console output lines of i-1; boot problem: "+bootFailures[2].hint, summary)
+}
+
+func TestConsoleToolsRequireInstance(t *testing.T) {
+	h := NewToolHandler(&config.Config{}, nil, logging.NewLogger("error", "text"))
+
+	for _, name := range []string{"get-instance-console-output", "get-instance-screenshot"} {
+		result, err := h.CallTool(context.Background(), name, map[string]interface{}{})
+		require.NoError(t, err)
+		assert.Equal(t, "instanceId is required", decodeToolResult(t, result)["error"], name)
+	}
+}
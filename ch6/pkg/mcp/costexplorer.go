@@ -0,0 +1,391 @@
+package mcp
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/url"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"aws-mcp-server/pkg/aws"
+	"aws-mcp-server/pkg/types"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const (
+	// costDailyURI is the cost per day or month from Cost Explorer
+	costDailyURI = "aws://cost/daily"
+	// costDailyTemplate overrides the window, granularity and breakdown of costDailyURI
+	costDailyTemplate = "aws://cost/daily{?days,granularity,groupBy}"
+	// costByServiceURI is the cost per service compared with the previous window
+	costByServiceURI = "aws://cost/by-service"
+	// costByServiceTemplate overrides the window of costByServiceURI
+	costByServiceTemplate = "aws://cost/by-service{?days}"
+	// defaultCostDays applies when cost.explorer.days is not configured
+	defaultCostDays = 30
+	// maxCostDays is how far back Cost Explorer keeps daily data
+	maxCostDays = 365
+	// costPeriodGroups is how many groups each period lists
+	costPeriodGroups = 5
+	// costTopGroups is how many groups the window breakdown lists
+	costTopGroups = 10
+)
+
+// costGranularities are the granularities the cost resources accept
+var costGranularities = []string{"DAILY", "MONTHLY"}
+
+// readCostDaily returns the cost per period with its trend and, when
+// grouped, the groups that cost the most and those that grew
+func (h *ResourceHandler) readCostDaily(ctx context.Context, uri string) (*mcp.ReadResourceResult, error) {
+	params, err := costParams(uri)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := h.config.Cost.Explorer
+	days, err := costDays(params.Get("days"), cfg.Days)
+	if err != nil {
+		return nil, err
+	}
+	granularity := strings.ToUpper(cmp.Or(params.Get("granularity"), cfg.Granularity, "DAILY"))
+	if !slices.Contains(costGranularities, granularity) {
+		return nil, fmt.Errorf("invalid granularity %q, use DAILY or MONTHLY", granularity)
+	}
+	groupBy := cmp.Or(params.Get("groupBy"), cfg.GroupBy)
+
+	start, end := costWindow(time.Now(), days)
+	periods, err := h.awsClient.GetCostAndUsage(ctx, aws.CostQuery{
+		Start:       start,
+		End:         end,
+		Granularity: granularity,
+		Metric:      h.costMetric(),
+		GroupBy:     groupBy,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cost from Cost Explorer: %w", err)
+	}
+
+	data := formatCostDaily(periods)
+	data["start"] = start.Format(time.DateOnly)
+	data["end"] = end.AddDate(0, 0, -1).Format(time.DateOnly)
+	data["days"] = days
+	data["granularity"] = granularity
+	data["metric"] = h.costMetric()
+	if groupBy != "" {
+		data["group_by"] = groupBy
+	}
+	data["note"] = "Cost Explorer lags up to a day behind and today is excluded; estimated periods may still change"
+
+	jsonData, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal cost data: %w", err)
+	}
+
+	return &mcp.ReadResourceResult{
+		Contents: []mcp.ResourceContents{
+			&mcp.TextResourceContents{
+				URI:      uri,
+				MIMEType: "application/json",
+				Text:     string(jsonData),
+			},
+		},
+	}, nil
+}
+
+// readCostByService returns the cost per service over the window compared
+// with the window before it
+func (h *ResourceHandler) readCostByService(ctx context.Context, uri string) (*mcp.ReadResourceResult, error) {
+	params, err := costParams(uri)
+	if err != nil {
+		return nil, err
+	}
+	days, err := costDays(params.Get("days"), h.config.Cost.Explorer.Days)
+	if err != nil {
+		return nil, err
+	}
+
+	start, end := costWindow(time.Now(), days)
+	previousStart := start.AddDate(0, 0, -days)
+
+	query := aws.CostQuery{
+		Start:       start,
+		End:         end,
+		Granularity: "MONTHLY",
+		Metric:      h.costMetric(),
+		GroupBy:     "SERVICE",
+	}
+	current, err := h.awsClient.GetCostAndUsage(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cost by service from Cost Explorer: %w", err)
+	}
+	query.Start, query.End = previousStart, start
+	previous, err := h.awsClient.GetCostAndUsage(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cost by service of the previous window: %w", err)
+	}
+
+	data := formatCostByService(current, previous)
+	data["start"] = start.Format(time.DateOnly)
+	data["end"] = end.AddDate(0, 0, -1).Format(time.DateOnly)
+	data["previous_start"] = previousStart.Format(time.DateOnly)
+	data["days"] = days
+	data["metric"] = h.costMetric()
+
+	jsonData, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal cost data: %w", err)
+	}
+
+	return &mcp.ReadResourceResult{
+		Contents: []mcp.ResourceContents{
+			&mcp.TextResourceContents{
+				URI:      uri,
+				MIMEType: "application/json",
+				Text:     string(jsonData),
+			},
+		},
+	}, nil
+}
+
+// costMetric returns the configured Cost Explorer metric
+func (h *ResourceHandler) costMetric() string {
+	return cmp.Or(h.config.Cost.Explorer.Metric, "UnblendedCost")
+}
+
+// costParams parses the query parameters of a cost resource URI
+func costParams(uri string) (url.Values, error) {
+	parsed, err := url.Parse(uri)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cost URI: %w", err)
+	}
+	return parsed.Query(), nil
+}
+
+// costDays parses the days parameter, falling back to the configured window
+func costDays(value string, configured int) (int, error) {
+	if value == "" {
+		if configured > 0 {
+			return min(configured, maxCostDays), nil
+		}
+		return defaultCostDays, nil
+	}
+	days, err := strconv.Atoi(value)
+	if err != nil || days < 1 || days > maxCostDays {
+		return 0, fmt.Errorf("invalid days %q, use 1 to %d", value, maxCostDays)
+	}
+	return days, nil
+}
+
+// costWindow returns the window of the last days complete days; the end is
+// today, which Cost Explorer excludes
+func costWindow(now time.Time, days int) (time.Time, time.Time) {
+	end := now.UTC().Truncate(24 * time.Hour)
+	return end.AddDate(0, 0, -days), end
+}
+
+// formatCostDaily describes the cost per period: the total, the trend between
+// the halves of the window, the most expensive period and, for grouped
+// periods, the most expensive groups and how they changed
+func formatCostDaily(periods []types.CostPeriod) map[string]interface{} {
+	total := 0.0
+	unit := "USD"
+	var peak *types.CostPeriod
+	items := make([]map[string]interface{}, 0, len(periods))
+	groupTotals := make(map[string]float64)
+	firstHalf := make(map[string]float64)
+	secondHalf := make(map[string]float64)
+
+	for i := range periods {
+		period := &periods[i]
+		total += period.Total
+		if period.Unit != "" {
+			unit = period.Unit
+		}
+		if peak == nil || period.Total > peak.Total {
+			peak = period
+		}
+
+		item := map[string]interface{}{
+			"start": period.Start.Format(time.DateOnly),
+			"usd":   roundCost(period.Total),
+		}
+		if period.Estimated {
+			item["estimated"] = true
+		}
+		if len(period.Groups) > 0 {
+			groups := append([]types.CostGroup(nil), period.Groups...)
+			sort.SliceStable(groups, func(a, b int) bool { return groups[a].Amount > groups[b].Amount })
+			top := make([]map[string]interface{}, 0, costPeriodGroups)
+			for _, group := range groups[:min(len(groups), costPeriodGroups)] {
+				top = append(top, map[string]interface{}{"key": group.Key, "usd": roundCost(group.Amount)})
+			}
+			item["top_groups"] = top
+		}
+		items = append(items, item)
+
+		for _, group := range period.Groups {
+			groupTotals[group.Key] += group.Amount
+			if i < len(periods)/2 {
+				firstHalf[group.Key] += group.Amount
+			} else {
+				secondHalf[group.Key] += group.Amount
+			}
+		}
+	}
+
+	data := map[string]interface{}{
+		"total_usd": roundCost(total),
+		"unit":      unit,
+		"periods":   items,
+	}
+	if len(periods) == 0 {
+		return data
+	}
+	data["average_usd"] = roundCost(total / float64(len(periods)))
+	data["peak"] = map[string]interface{}{"start": peak.Start.Format(time.DateOnly), "usd": roundCost(peak.Total)}
+
+	if len(periods) >= 2 {
+		half := len(periods) / 2
+		first, second := sumCostPeriods(periods[:half]), sumCostPeriods(periods[half:])
+		trend := map[string]interface{}{
+			"first_half_average_usd":  roundCost(first / float64(half)),
+			"second_half_average_usd": roundCost(second / float64(len(periods)-half)),
+		}
+		if change, ok := percentChange(first/float64(half), second/float64(len(periods)-half)); ok {
+			trend["change_percent"] = change
+		}
+		data["trend"] = trend
+	}
+
+	if len(groupTotals) > 0 {
+		keys := make([]string, 0, len(groupTotals))
+		for key := range groupTotals {
+			keys = append(keys, key)
+		}
+		sort.SliceStable(keys, func(a, b int) bool {
+			if groupTotals[keys[a]] != groupTotals[keys[b]] {
+				return groupTotals[keys[a]] > groupTotals[keys[b]]
+			}
+			return keys[a] < keys[b]
+		})
+		groups := make([]map[string]interface{}, 0, costTopGroups)
+		for _, key := range keys[:min(len(keys), costTopGroups)] {
+			group := map[string]interface{}{
+				"key":           key,
+				"usd":           roundCost(groupTotals[key]),
+				"share_percent": math.Round(groupTotals[key]/total*1000) / 10,
+			}
+			half := len(periods) / 2
+			if change, ok := percentChange(firstHalf[key]/float64(max(half, 1)), secondHalf[key]/float64(len(periods)-half)); ok {
+				group["half_over_half_percent"] = change
+			}
+			groups = append(groups, group)
+		}
+		data["groups"] = groups
+	}
+	return data
+}
+
+// formatCostByService sums the cost per service over the window, most
+// expensive first, with the change from the previous window and the services
+// that grew the most
+func formatCostByService(current, previous []types.CostPeriod) map[string]interface{} {
+	now, before := sumCostGroups(current), sumCostGroups(previous)
+	total, previousTotal := sumCostPeriods(current), sumCostPeriods(previous)
+
+	keys := make([]string, 0, len(now))
+	for key := range now {
+		keys = append(keys, key)
+	}
+	sort.SliceStable(keys, func(a, b int) bool {
+		if now[keys[a]] != now[keys[b]] {
+			return now[keys[a]] > now[keys[b]]
+		}
+		return keys[a] < keys[b]
+	})
+
+	services := make([]map[string]interface{}, 0, len(keys))
+	var increases []map[string]interface{}
+	for _, key := range keys {
+		if roundCost(now[key]) == 0 && roundCost(before[key]) == 0 {
+			continue
+		}
+		service := map[string]interface{}{
+			"service":      key,
+			"usd":          roundCost(now[key]),
+			"previous_usd": roundCost(before[key]),
+			"change_usd":   roundCost(now[key] - before[key]),
+		}
+		if total > 0 {
+			service["share_percent"] = math.Round(now[key]/total*1000) / 10
+		}
+		if change, ok := percentChange(before[key], now[key]); ok {
+			service["change_percent"] = change
+		} else if now[key] > 0 {
+			service["new"] = true
+		}
+		services = append(services, service)
+		if now[key]-before[key] > 0 {
+			increases = append(increases, service)
+		}
+	}
+
+	sort.SliceStable(increases, func(a, b int) bool {
+		return increases[a]["change_usd"].(float64) > increases[b]["change_usd"].(float64)
+	})
+	largest := make([]map[string]interface{}, 0, costPeriodGroups)
+	for _, service := range increases[:min(len(increases), costPeriodGroups)] {
+		largest = append(largest, map[string]interface{}{
+			"service":    service["service"],
+			"change_usd": service["change_usd"],
+		})
+	}
+
+	data := map[string]interface{}{
+		"total_usd":          roundCost(total),
+		"previous_total_usd": roundCost(previousTotal),
+		"total_services":     len(services),
+		"services":           services,
+		"largest_increases":  largest,
+	}
+	if change, ok := percentChange(previousTotal, total); ok {
+		data["change_percent"] = change
+	}
+	return data
+}
+
+// sumCostPeriods returns the total cost of periods
+func sumCostPeriods(periods []types.CostPeriod) float64 {
+	total := 0.0
+	for _, period := range periods {
+		total += period.Total
+	}
+	return total
+}
+
+// sumCostGroups returns the cost of each group over all periods
+func sumCostGroups(periods []types.CostPeriod) map[string]float64 {
+	totals := make(map[string]float64)
+	for _, period := range periods {
+		for _, group := range period.Groups {
+			totals[group.Key] += group.Amount
+		}
+	}
+	return totals
+}
+
+// percentChange returns the change from before to after in percent, rounded
+// to one decimal; there is none when before is zero
+func percentChange(before, after float64) (float64, bool) {
+	if roundCost(before) == 0 {
+		return 0, false
+	}
+	return math.Round((after-before)/before*1000) / 10, true
+}
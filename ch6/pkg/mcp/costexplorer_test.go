@@ -0,0 +1,103 @@
+package mcp
+
+import (
+	"testing"
+	"time"
+
+	"aws-mcp-server/pkg/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func costDay(day int, groups ...types.CostGroup) types.CostPeriod {
+	period := types.CostPeriod{Start: time.Date(2025, 6, day, 0, 0, 0, 0, time.UTC), Unit: "USD", Groups: groups}
+	for _, group := range groups {
+		period.Total += group.Amount
+	}
+	return period
+}
+
+func TestFormatCostDaily(t *testing.T) {
+	periods := []types.CostPeriod{
+		costDay(1, types.CostGroup{Key: "Amazon EC2", Amount: 10}, types.CostGroup{Key: "Amazon RDS", Amount: 5}),
+		costDay(2, types.CostGroup{Key: "Amazon EC2", Amount: 10}, types.CostGroup{Key: "Amazon RDS", Amount: 5}),
+		costDay(3, types.CostGroup{Key: "Amazon EC2", Amount: 20}, types.CostGroup{Key: "Amazon RDS", Amount: 5}),
+		costDay(4, types.CostGroup{Key: "Amazon EC2", Amount: 20}, types.CostGroup{Key: "Amazon RDS", Amount: 5}),
+	}
+	periods[3].Estimated = true
+
+	data := formatCostDaily(periods)
+	assert.Equal(t, 80.0, data["total_usd"])
+	assert.Equal(t, 20.0, data["average_usd"])
+	assert.Equal(t, map[string]interface{}{"start": "2025-06-03", "usd": 25.0}, data["peak"], "the first of equal periods is the peak")
+
+	trend := data["trend"].(map[string]interface{})
+	assert.Equal(t, 15.0, trend["first_half_average_usd"])
+	assert.Equal(t, 25.0, trend["second_half_average_usd"])
+	assert.Equal(t, 66.7, trend["change_percent"])
+
+	items := data["periods"].([]map[string]interface{})
+	require.Len(t, items, 4)
+	assert.Equal(t, true, items[3]["estimated"])
+	assert.Equal(t, "Amazon EC2", items[0]["top_groups"].([]map[string]interface{})[0]["key"])
+
+	groups := data["groups"].([]map[string]interface{})
+	require.Len(t, groups, 2)
+	assert.Equal(t, "Amazon EC2", groups[0]["key"])
+	assert.Equal(t, 75.0, groups[0]["share_percent"])
+	assert.Equal(t, 100.0, groups[0]["half_over_half_percent"])
+	assert.Equal(t, 0.0, groups[1]["half_over_half_percent"])
+
+	empty := formatCostDaily(nil)
+	assert.Equal(t, 0.0, empty["total_usd"])
+	assert.Nil(t, empty["trend"])
+}
+
+func TestFormatCostByService(t *testing.T) {
+	current := []types.CostPeriod{
+		costDay(1, types.CostGroup{Key: "Amazon EC2", Amount: 120}, types.CostGroup{Key: "AWS Lambda", Amount: 30}, types.CostGroup{Key: "Tax", Amount: 0}),
+	}
+	previous := []types.CostPeriod{
+		costDay(1, types.CostGroup{Key: "Amazon EC2", Amount: 100}, types.CostGroup{Key: "Amazon S3", Amount: 10}),
+	}
+
+	data := formatCostByService(current, previous)
+	assert.Equal(t, 150.0, data["total_usd"])
+	assert.Equal(t, 110.0, data["previous_total_usd"])
+	assert.Equal(t, 36.4, data["change_percent"])
+	assert.Equal(t, 2, data["total_services"], "services without cost in either window are left out")
+
+	services := data["services"].([]map[string]interface{})
+	require.Len(t, services, 2)
+	assert.Equal(t, "Amazon EC2", services[0]["service"])
+	assert.Equal(t, 80.0, services[0]["share_percent"])
+	assert.Equal(t, 20.0, services[0]["change_percent"])
+	assert.Equal(t, true, services[1]["new"])
+
+	increases := data["largest_increases"].([]map[string]interface{})
+	require.Len(t, increases, 2)
+	assert.Equal(t, "AWS Lambda", increases[0]["service"])
+	assert.Equal(t, 30.0, increases[0]["change_usd"])
+}
+
+func TestCostDaysAndWindow(t *testing.T) {
+	days, err := costDays("", 0)
+	require.NoError(t, err)
+	assert.Equal(t, defaultCostDays, days)
+
+	days, err = costDays("", 1000)
+	require.NoError(t, err)
+	assert.Equal(t, maxCostDays, days)
+
+	days, err = costDays("7", 30)
+	require.NoError(t, err)
+	assert.Equal(t, 7, days)
+
+	_, err = costDays("0", 30)
+	assert.ErrorContains(t, err, "invalid days")
+
+	start, end := costWindow(time.Date(2025, 6, 10, 15, 30, 0, 0, time.UTC), 7)
+	assert.Equal(t, "2025-06-03", start.Format(time.DateOnly))
+	assert.Equal(t, "2025-06-10", end.Format(time.DateOnly))
+}
@@ -0,0 +1,229 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"aws-mcp-server/internal/config"
+	"aws-mcp-server/pkg/types"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// defaultCredentialAgeDays is used when the security thresholds are not configured
+const defaultCredentialAgeDays = 90
+
+// credentialFinding describes a single credential hygiene problem of an IAM user
+type credentialFinding struct {
+	UserName    string `json:"user_name"`
+	AccessKeyID string `json:"access_key_id,omitempty"`
+	Detail      string `json:"detail"`
+}
+
+// credentialReport groups credential hygiene findings by category
+type credentialReport struct {
+	OldAccessKeys     []credentialFinding `json:"old_access_keys"`
+	UnusedCredentials []credentialFinding `json:"unused_credentials"`
+	ConsoleWithoutMFA []credentialFinding `json:"console_users_without_mfa"`
+}
+
+// readCredentialHygiene reports IAM users with old or unused credentials and console users without MFA
+func (h *ResourceHandler) readCredentialHygiene(ctx context.Context) (*mcp.ReadResourceResult, error) {
+	users, err := h.awsClient.ListIAMUsers(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list IAM users: %w", err)
+	}
+
+	policy := h.config.Security
+	report := findCredentialIssues(users, policy, time.Now())
+
+	formatted := map[string]interface{}{
+		"thresholds": map[string]int{
+			"access_key_max_age_days": thresholdDays(policy.AccessKeyMaxAgeDays),
+			"unused_credential_days":  thresholdDays(policy.UnusedCredentialDays),
+		},
+		"summary": map[string]int{
+			"users_checked":             len(users),
+			"old_access_keys":           len(report.OldAccessKeys),
+			"unused_credentials":        len(report.UnusedCredentials),
+			"console_users_without_mfa": len(report.ConsoleWithoutMFA),
+		},
+		"findings": report,
+		"remediation": map[string]string{
+			"old_access_keys":           "Rotate the key: create a new key, update its consumers, then use the deactivate-access-key tool on the old one",
+			"unused_credentials":        "Use the deactivate-access-key tool for unused keys and remove unused console passwords",
+			"console_users_without_mfa": "Require the user to register an MFA device or remove their console password",
+		},
+	}
+
+	jsonData, err := json.MarshalIndent(formatted, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal credential hygiene data: %w", err)
+	}
+
+	return &mcp.ReadResourceResult{
+		Contents: []mcp.ResourceContents{
+			&mcp.TextResourceContents{
+				URI:      "aws://iam/credential-hygiene",
+				MIMEType: "application/json",
+				Text:     string(jsonData),
+			},
+		},
+	}, nil
+}
+
+// findCredentialIssues checks every user against the configured thresholds.
+// Inactive access keys are ignored because they cannot be used to sign requests.
+func findCredentialIssues(users []types.IAMUser, policy config.SecurityConfig, now time.Time) credentialReport {
+	maxAge := thresholdDays(policy.AccessKeyMaxAgeDays)
+	unusedAfter := thresholdDays(policy.UnusedCredentialDays)
+
+	report := credentialReport{
+		OldAccessKeys:     make([]credentialFinding, 0),
+		UnusedCredentials: make([]credentialFinding, 0),
+		ConsoleWithoutMFA: make([]credentialFinding, 0),
+	}
+
+	for _, user := range users {
+		for _, key := range user.AccessKeys {
+			if !key.IsActive() {
+				continue
+			}
+
+			if age := daysSince(key.CreateDate, now); age > maxAge {
+				report.OldAccessKeys = append(report.OldAccessKeys, credentialFinding{
+					UserName:    user.UserName,
+					AccessKeyID: key.ID,
+					Detail:      fmt.Sprintf("created %d days ago", age),
+				})
+			}
+
+			if detail, unused := unusedSince(key.LastUsed, key.CreateDate, unusedAfter, now); unused {
+				report.UnusedCredentials = append(report.UnusedCredentials, credentialFinding{
+					UserName:    user.UserName,
+					AccessKeyID: key.ID,
+					Detail:      "access key " + detail,
+				})
+			}
+		}
+
+		if !user.ConsoleAccess {
+			continue
+		}
+
+		if detail, unused := unusedSince(user.PasswordLastUsed, user.CreateDate, unusedAfter, now); unused {
+			report.UnusedCredentials = append(report.UnusedCredentials, credentialFinding{
+				UserName: user.UserName,
+				Detail:   "console password " + detail,
+			})
+		}
+
+		if !user.MFAEnabled {
+			report.ConsoleWithoutMFA = append(report.ConsoleWithoutMFA, credentialFinding{
+				UserName: user.UserName,
+				Detail:   "console password enabled without an MFA device",
+			})
+		}
+	}
+
+	return report
+}
+
+// unusedSince reports whether a credential has not been used for longer than the
+// threshold. Credentials that were never used are measured from their creation date.
+func unusedSince(lastUsed *time.Time, created time.Time, threshold int, now time.Time) (string, bool) {
+	if lastUsed == nil {
+		age := daysSince(created, now)
+		return fmt.Sprintf("never used (created %d days ago)", age), age > threshold
+	}
+
+	idle := daysSince(*lastUsed, now)
+	return fmt.Sprintf("last used %d days ago", idle), idle > threshold
+}
+
+// daysSince returns the number of whole days between t and now
+func daysSince(t time.Time, now time.Time) int {
+	return int(now.Sub(t).Hours() / 24)
+}
+
+// thresholdDays falls back to the default threshold when none is configured
+func thresholdDays(days int) int {
+	if days <= 0 {
+		return defaultCredentialAgeDays
+	}
+	return days
+}
+
+// deactivateAccessKey marks an IAM access key as inactive.
+// Without confirm=true it only returns the key details and the impact warnings.
+func (h *ToolHandler) deactivateAccessKey(ctx context.Context, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	userName, ok := arguments["userName"].(string)
+	if !ok || userName == "" {
+		return h.createErrorResponse("userName is required")
+	}
+
+	accessKeyID, ok := arguments["accessKeyId"].(string)
+	if !ok || accessKeyID == "" {
+		return h.createErrorResponse("accessKeyId is required")
+	}
+
+	keys, err := h.awsClient.ListAccessKeys(ctx, userName)
+	if err != nil {
+		return h.createErrorResponse(fmt.Sprintf("failed to list access keys: %v", err))
+	}
+
+	var key *types.AccessKey
+	for i := range keys {
+		if keys[i].ID == accessKeyID {
+			key = &keys[i]
+			break
+		}
+	}
+	if key == nil {
+		return h.createErrorResponse(fmt.Sprintf("access key %s not found for user %s", accessKeyID, userName))
+	}
+
+	if !key.IsActive() {
+		return h.createErrorResponse(fmt.Sprintf("access key %s is already inactive", accessKeyID))
+	}
+
+	if !isConfirmed(arguments) {
+		now := time.Now()
+		plan := map[string]interface{}{
+			"userName":    userName,
+			"accessKeyId": accessKeyID,
+			"ageDays":     daysSince(key.CreateDate, now),
+			"steps": []string{
+				"Set the access key status to Inactive",
+			},
+		}
+
+		warnings := []string{
+			"Any application or script signing requests with this key will start failing immediately",
+		}
+
+		if key.LastUsed != nil {
+			plan["lastUsed"] = h.times.Format(*key.LastUsed)
+			plan["lastUsedService"] = key.LastUsedService
+			warnings = append(warnings, fmt.Sprintf("The key was last used %d days ago (service: %s)",
+				daysSince(*key.LastUsed, now), key.LastUsedService))
+		} else {
+			plan["lastUsed"] = "never"
+		}
+
+		return h.createConfirmationResponse("deactivate-access-key", plan, warnings)
+	}
+
+	if err := h.awsClient.DeactivateAccessKey(ctx, userName, accessKeyID); err != nil {
+		return h.createErrorResponse(fmt.Sprintf("failed to deactivate access key: %v", err))
+	}
+
+	return h.createSuccessResponse("Access key deactivated successfully", map[string]interface{}{
+		"userName":    userName,
+		"accessKeyId": accessKeyID,
+		"status":      "Inactive",
+		"rollback":    "Set the key status back to Active in IAM if a consumer still depends on it",
+	})
+}
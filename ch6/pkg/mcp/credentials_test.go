@@ -0,0 +1,73 @@
+package mcp
+
+import (
+	"testing"
+	"time"
+
+	"aws-mcp-server/internal/config"
+	"aws-mcp-server/pkg/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindCredentialIssues(t *testing.T) {
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	daysAgo := func(days int) time.Time { return now.AddDate(0, 0, -days) }
+	recent := daysAgo(2)
+
+	users := []types.IAMUser{
+		{
+			UserName:   "ci-deployer",
+			CreateDate: daysAgo(400),
+			AccessKeys: []types.AccessKey{
+				{ID: "AKIAOLD", Status: "Active", CreateDate: daysAgo(200), LastUsed: &recent},
+				{ID: "AKIANEVERUSED", Status: "Active", CreateDate: daysAgo(120)},
+				{ID: "AKIAINACTIVE", Status: "Inactive", CreateDate: daysAgo(500)},
+			},
+		},
+		{
+			UserName:      "alice",
+			CreateDate:    daysAgo(30),
+			ConsoleAccess: true,
+			MFAEnabled:    false,
+		},
+		{
+			UserName:      "bob",
+			CreateDate:    daysAgo(300),
+			ConsoleAccess: true,
+			MFAEnabled:    true,
+		},
+	}
+
+	report := findCredentialIssues(users, config.SecurityConfig{}, now)
+
+	require.Len(t, report.OldAccessKeys, 2)
+	assert.Equal(t, "AKIAOLD", report.OldAccessKeys[0].AccessKeyID)
+	assert.Equal(t, "created 200 days ago", report.OldAccessKeys[0].Detail)
+	assert.Equal(t, "AKIANEVERUSED", report.OldAccessKeys[1].AccessKeyID)
+
+	require.Len(t, report.UnusedCredentials, 2)
+	assert.Equal(t, "AKIANEVERUSED", report.UnusedCredentials[0].AccessKeyID)
+	assert.Equal(t, "access key never used (created 120 days ago)", report.UnusedCredentials[0].Detail)
+	assert.Equal(t, "bob", report.UnusedCredentials[1].UserName)
+	assert.Equal(t, "console password never used (created 300 days ago)", report.UnusedCredentials[1].Detail)
+
+	require.Len(t, report.ConsoleWithoutMFA, 1)
+	assert.Equal(t, "alice", report.ConsoleWithoutMFA[0].UserName)
+}
+
+func TestFindCredentialIssuesThresholds(t *testing.T) {
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	users := []types.IAMUser{
+		{
+			UserName:   "svc",
+			AccessKeys: []types.AccessKey{{ID: "AKIA45", Status: "Active", CreateDate: now.AddDate(0, 0, -45)}},
+		},
+	}
+
+	report := findCredentialIssues(users, config.SecurityConfig{AccessKeyMaxAgeDays: 30, UnusedCredentialDays: 60}, now)
+
+	assert.Len(t, report.OldAccessKeys, 1)
+	assert.Empty(t, report.UnusedCredentials)
+}
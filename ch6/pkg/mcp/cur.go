@@ -0,0 +1,345 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"aws-mcp-server/pkg/aws"
+	"aws-mcp-server/pkg/types"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const (
+	// curDailyURI lists the daily cost of the most expensive resources
+	curDailyURI = "aws://cost/cur/daily"
+	// curResourceTemplate is the URI template of one resource's daily cost by usage type
+	curResourceTemplate = "aws://cost/cur/resources/{+resourceId}"
+	// defaultCURDays applies when cost.cur.days is not configured
+	defaultCURDays = 14
+	// curTopResources is how many of the most expensive resources curDailyURI lists
+	curTopResources = 50
+	// curFormatV2 selects the billing_period partitions of CUR 2.0 exports
+	curFormatV2 = "cur2"
+)
+
+// sqlIdentifierPattern restricts configured database and table names so they
+// can be quoted into SQL safely
+var sqlIdentifierPattern = regexp.MustCompile(`^[A-Za-z0-9_]+$`)
+
+// readCURDaily returns the daily cost of the most expensive resources from the
+// Cost and Usage Report
+func (h *ResourceHandler) readCURDaily(ctx context.Context) (*mcp.ReadResourceResult, error) {
+	table, err := h.curTable()
+	if err != nil {
+		return nil, err
+	}
+
+	days := h.curDays()
+	end := time.Now().UTC()
+	start := end.AddDate(0, 0, -days).Truncate(24 * time.Hour)
+
+	result, err := h.runCURQuery(ctx, curDailySQL(table, h.curPartitions(start, end), start), curTopResources*(days+1))
+	if err != nil {
+		return nil, err
+	}
+
+	data := formatCURDaily(result.Rows)
+	data["days"] = days
+	data["start"] = start.Format(time.DateOnly)
+	data["data_scanned_mb"] = math.Round(float64(result.DataScannedBytes)/(1<<20)*100) / 100
+	data["note"] = "The Cost and Usage Report lags up to a day behind; the latest day may be incomplete"
+
+	jsonData, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal CUR cost data: %w", err)
+	}
+
+	return &mcp.ReadResourceResult{
+		Contents: []mcp.ResourceContents{
+			&mcp.TextResourceContents{
+				URI:      curDailyURI,
+				MIMEType: "application/json",
+				Text:     string(jsonData),
+			},
+		},
+	}, nil
+}
+
+// readCURResource returns the daily cost of one resource by usage type
+func (h *ResourceHandler) readCURResource(ctx context.Context, uri string) (*mcp.ReadResourceResult, error) {
+	encoded, _ := strings.CutPrefix(uri, "aws://cost/cur/resources/")
+	resourceID, err := url.PathUnescape(encoded)
+	if err != nil || resourceID == "" {
+		return nil, fmt.Errorf("invalid CUR resource URI %s, use %s and URL-encode ARNs", uri, curResourceTemplate)
+	}
+
+	table, err := h.curTable()
+	if err != nil {
+		return nil, err
+	}
+
+	days := h.curDays()
+	end := time.Now().UTC()
+	start := end.AddDate(0, 0, -days).Truncate(24 * time.Hour)
+
+	result, err := h.runCURQuery(ctx, curResourceSQL(table, h.curPartitions(start, end), start, resourceID), 0)
+	if err != nil {
+		return nil, err
+	}
+
+	data := formatCURResource(resourceID, result.Rows)
+	data["days"] = days
+	data["start"] = start.Format(time.DateOnly)
+	if strings.HasPrefix(resourceID, "i-") {
+		data["instance_uri"] = "aws://ec2/instances/" + resourceID
+	}
+
+	jsonData, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal CUR cost data: %w", err)
+	}
+
+	return &mcp.ReadResourceResult{
+		Contents: []mcp.ResourceContents{
+			&mcp.TextResourceContents{
+				URI:      uri,
+				MIMEType: "application/json",
+				Text:     string(jsonData),
+			},
+		},
+	}, nil
+}
+
+// runCURQuery runs a CUR query with the Athena workgroup and limits. A
+// maxRows of zero keeps the configured row cap.
+func (h *ResourceHandler) runCURQuery(ctx context.Context, sql string, maxRows int) (*types.AthenaResult, error) {
+	params := athenaParams(h.config.Athena)
+	params.SQL = sql
+	if maxRows > 0 {
+		params.MaxRows = maxRows
+	}
+
+	result, err := h.awsClient.RunAthenaQuery(ctx, params)
+	switch {
+	case errors.Is(err, aws.ErrAthenaScanLimit):
+		return nil, fmt.Errorf("CUR query %s was stopped after scanning %s, over the %s limit; raise athena.max_scan_gb or shorten cost.cur.days",
+			result.QueryExecutionID, formatBytes(result.DataScannedBytes), formatBytes(params.MaxBytesScanned))
+	case errors.Is(err, aws.ErrAthenaTimeout):
+		return nil, fmt.Errorf("CUR query %s did not complete within %s", result.QueryExecutionID, params.Timeout)
+	case err != nil:
+		return nil, fmt.Errorf("failed to query the Cost and Usage Report: %w", err)
+	}
+	return result, nil
+}
+
+// curTable returns the quoted CUR table, qualified with its database
+func (h *ResourceHandler) curTable() (string, error) {
+	cfg := h.config.Cost.CUR
+	database := cfg.Database
+	if database == "" {
+		database = h.config.Athena.Database
+	}
+	table := cfg.Table
+	if qualifiedDatabase, name, found := strings.Cut(table, "."); found {
+		database, table = qualifiedDatabase, name
+	}
+
+	if !sqlIdentifierPattern.MatchString(table) {
+		return "", fmt.Errorf("invalid cost.cur.table %q: use letters, digits and underscores", cfg.Table)
+	}
+	if database == "" {
+		return fmt.Sprintf(`"%s"`, table), nil
+	}
+	if !sqlIdentifierPattern.MatchString(database) {
+		return "", fmt.Errorf("invalid CUR database %q: use letters, digits and underscores", database)
+	}
+	return fmt.Sprintf(`"%s"."%s"`, database, table), nil
+}
+
+// curDays returns the configured window of daily costs
+func (h *ResourceHandler) curDays() int {
+	if days := h.config.Cost.CUR.Days; days > 0 {
+		return days
+	}
+	return defaultCURDays
+}
+
+// curPartitions returns the partition filter covering start to end, so Athena
+// only scans the billing months of the window
+func (h *ResourceHandler) curPartitions(start, end time.Time) string {
+	return curPartitionFilter(h.config.Cost.CUR.Format, start, end)
+}
+
+// curPartitionFilter selects the billing months between start and end: year and
+// month partitions (month without a leading zero) in the legacy CUR, or
+// billing_period partitions such as 2025-06 in CUR 2.0
+func curPartitionFilter(format string, start, end time.Time) string {
+	var legacy, periods []string
+	for month := time.Date(start.Year(), start.Month(), 1, 0, 0, 0, 0, time.UTC); !month.After(end); month = month.AddDate(0, 1, 0) {
+		legacy = append(legacy, fmt.Sprintf("(year = '%d' AND month = '%d')", month.Year(), month.Month()))
+		periods = append(periods, fmt.Sprintf("'%s'", month.Format("2006-01")))
+	}
+
+	if format == curFormatV2 {
+		return fmt.Sprintf("billing_period IN (%s)", strings.Join(periods, ", "))
+	}
+	return fmt.Sprintf("(%s)", strings.Join(legacy, " OR "))
+}
+
+// curDailySQL sums the unblended cost per resource and day since start, for
+// the resources that cost the most over the window
+func curDailySQL(table, partitions string, start time.Time) string {
+	return fmt.Sprintf(`WITH daily AS (
+  SELECT line_item_resource_id AS resource_id,
+         line_item_product_code AS service,
+         date(line_item_usage_start_date) AS day,
+         sum(line_item_unblended_cost) AS cost
+  FROM %s
+  WHERE %s
+    AND line_item_usage_start_date >= timestamp '%s'
+    AND line_item_resource_id <> ''
+  GROUP BY 1, 2, 3
+),
+top AS (
+  SELECT resource_id FROM daily GROUP BY 1 ORDER BY sum(cost) DESC LIMIT %d
+)
+SELECT d.resource_id, d.service, cast(d.day AS varchar) AS day, d.cost
+FROM daily d JOIN top t ON d.resource_id = t.resource_id
+ORDER BY d.resource_id, d.day`, table, partitions, start.Format(time.DateTime), curTopResources)
+}
+
+// curResourceSQL sums the cost and usage of one resource per day and usage type
+func curResourceSQL(table, partitions string, start time.Time, resourceID string) string {
+	return fmt.Sprintf(`SELECT cast(date(line_item_usage_start_date) AS varchar) AS day,
+       line_item_product_code AS service,
+       line_item_usage_type AS usage_type,
+       sum(line_item_unblended_cost) AS cost,
+       sum(line_item_usage_amount) AS usage
+FROM %s
+WHERE %s
+  AND line_item_usage_start_date >= timestamp '%s'
+  AND line_item_resource_id = '%s'
+GROUP BY 1, 2, 3
+ORDER BY 1, 4 DESC`, table, partitions, start.Format(time.DateTime), strings.ReplaceAll(resourceID, "'", "''"))
+}
+
+// formatCURDaily groups daily cost rows by resource, most expensive first
+func formatCURDaily(rows []map[string]string) map[string]interface{} {
+	type resourceCost struct {
+		id, service string
+		total       float64
+		daily       []map[string]interface{}
+	}
+
+	byID := make(map[string]*resourceCost)
+	var order []*resourceCost
+	total := 0.0
+	for _, row := range rows {
+		cost, _ := strconv.ParseFloat(row["cost"], 64)
+		resource, ok := byID[row["resource_id"]]
+		if !ok {
+			resource = &resourceCost{id: row["resource_id"], service: row["service"]}
+			byID[resource.id] = resource
+			order = append(order, resource)
+		}
+		resource.total += cost
+		resource.daily = append(resource.daily, map[string]interface{}{"date": row["day"], "usd": roundCost(cost)})
+		total += cost
+	}
+
+	sort.SliceStable(order, func(i, j int) bool { return order[i].total > order[j].total })
+
+	resources := make([]map[string]interface{}, 0, len(order))
+	for _, resource := range order {
+		item := map[string]interface{}{
+			"resource_id": resource.id,
+			"service":     resource.service,
+			"total_usd":   roundCost(resource.total),
+			"daily":       resource.daily,
+		}
+		if strings.HasPrefix(resource.id, "i-") {
+			item["instance_uri"] = "aws://ec2/instances/" + resource.id
+		}
+		resources = append(resources, item)
+	}
+
+	return map[string]interface{}{
+		"total_usd": roundCost(total),
+		"resources": resources,
+	}
+}
+
+// formatCURResource sums one resource's cost rows per day and per usage type
+func formatCURResource(resourceID string, rows []map[string]string) map[string]interface{} {
+	byDay := make(map[string]float64)
+	var days []string
+	type usageCost struct {
+		usageType, service string
+		cost, usage        float64
+	}
+	byUsage := make(map[string]*usageCost)
+	total := 0.0
+
+	for _, row := range rows {
+		cost, _ := strconv.ParseFloat(row["cost"], 64)
+		usage, _ := strconv.ParseFloat(row["usage"], 64)
+
+		if _, ok := byDay[row["day"]]; !ok {
+			days = append(days, row["day"])
+		}
+		byDay[row["day"]] += cost
+
+		entry, ok := byUsage[row["usage_type"]]
+		if !ok {
+			entry = &usageCost{usageType: row["usage_type"], service: row["service"]}
+			byUsage[row["usage_type"]] = entry
+		}
+		entry.cost += cost
+		entry.usage += usage
+		total += cost
+	}
+
+	daily := make([]map[string]interface{}, 0, len(days))
+	for _, day := range days {
+		daily = append(daily, map[string]interface{}{"date": day, "usd": roundCost(byDay[day])})
+	}
+
+	usageTypes := make([]*usageCost, 0, len(byUsage))
+	for _, entry := range byUsage {
+		usageTypes = append(usageTypes, entry)
+	}
+	sort.Slice(usageTypes, func(i, j int) bool { return usageTypes[i].cost > usageTypes[j].cost })
+
+	byUsageType := make([]map[string]interface{}, 0, len(usageTypes))
+	for _, entry := range usageTypes {
+		byUsageType = append(byUsageType, map[string]interface{}{
+			"usage_type": entry.usageType,
+			"service":    entry.service,
+			"usd":        roundCost(entry.cost),
+			"usage":      entry.usage,
+		})
+	}
+
+	return map[string]interface{}{
+		"resource_id":   resourceID,
+		"total_usd":     roundCost(total),
+		"daily":         daily,
+		"by_usage_type": byUsageType,
+	}
+}
+
+// roundCost rounds a cost in USD to a hundredth of a cent, which keeps the
+// hourly cost of small resources visible
+func roundCost(usd float64) float64 {
+	return math.Round(usd*10000) / 10000
+}
@@ -0,0 +1,91 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"aws-mcp-server/internal/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCURPartitionFilter(t *testing.T) {
+	start := time.Date(2025, 12, 20, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2026, 1, 3, 8, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, "((year = '2025' AND month = '12') OR (year = '2026' AND month = '1'))", curPartitionFilter("legacy", start, end))
+	assert.Equal(t, "billing_period IN ('2025-12', '2026-01')", curPartitionFilter("cur2", start, end))
+	assert.Equal(t, "((year = '2026' AND month = '1'))", curPartitionFilter("", end.AddDate(0, 0, -2), end))
+}
+
+func TestCURTable(t *testing.T) {
+	h := NewResourceHandler(&config.Config{
+		Athena: config.AthenaConfig{Database: "billing"},
+		Cost:   config.CostConfig{CUR: config.CURConfig{Table: "cur_daily"}},
+	}, nil)
+	table, err := h.curTable()
+	require.NoError(t, err)
+	assert.Equal(t, `"billing"."cur_daily"`, table)
+
+	h.config.Cost.CUR.Table = "athenacurcfn.hourly"
+	table, err = h.curTable()
+	require.NoError(t, err)
+	assert.Equal(t, `"athenacurcfn"."hourly"`, table)
+
+	h.config.Cost.CUR.Table = `cur"; DROP TABLE x; --`
+	_, err = h.curTable()
+	assert.Error(t, err)
+}
+
+func TestCURResourceSQLEscapesResourceID(t *testing.T) {
+	sql := curResourceSQL(`"cur"`, "billing_period IN ('2025-06')", time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC), "i-1' OR '1'='1")
+	assert.Contains(t, sql, "line_item_resource_id = 'i-1'' OR ''1''=''1'")
+	assert.Contains(t, sql, "timestamp '2025-06-01 00:00:00'")
+}
+
+func TestFormatCURDaily(t *testing.T) {
+	rows := []map[string]string{
+		{"resource_id": "bucket-logs", "service": "AmazonS3", "day": "2025-06-01", "cost": "0.5"},
+		{"resource_id": "i-0abc", "service": "AmazonEC2", "day": "2025-06-01", "cost": "2.304"},
+		{"resource_id": "i-0abc", "service": "AmazonEC2", "day": "2025-06-02", "cost": "2.304"},
+	}
+
+	formatted := formatCURDaily(rows)
+	assert.Equal(t, 5.108, formatted["total_usd"])
+
+	resources := formatted["resources"].([]map[string]interface{})
+	require.Len(t, resources, 2)
+	assert.Equal(t, "i-0abc", resources[0]["resource_id"])
+	assert.Equal(t, 4.608, resources[0]["total_usd"])
+	assert.Equal(t, "aws://ec2/instances/i-0abc", resources[0]["instance_uri"])
+	assert.Len(t, resources[0]["daily"], 2)
+	assert.NotContains(t, resources[1], "instance_uri")
+}
+
+func TestFormatCURResource(t *testing.T) {
+	rows := []map[string]string{
+		{"day": "2025-06-01", "service": "AmazonEC2", "usage_type": "BoxUsage:m5.large", "cost": "2.304", "usage": "24"},
+		{"day": "2025-06-01", "service": "AmazonEC2", "usage_type": "DataTransfer-Out-Bytes", "cost": "0.09", "usage": "1"},
+		{"day": "2025-06-02", "service": "AmazonEC2", "usage_type": "BoxUsage:m5.large", "cost": "2.304", "usage": "24"},
+	}
+
+	formatted := formatCURResource("i-0abc", rows)
+	assert.Equal(t, 4.698, formatted["total_usd"])
+	assert.Equal(t, []map[string]interface{}{
+		{"date": "2025-06-01", "usd": 2.394},
+		{"date": "2025-06-02", "usd": 2.304},
+	}, formatted["daily"])
+
+	usageTypes := formatted["by_usage_type"].([]map[string]interface{})
+	require.Len(t, usageTypes, 2)
+	assert.Equal(t, "BoxUsage:m5.large", usageTypes[0]["usage_type"])
+	assert.Equal(t, float64(48), usageTypes[0]["usage"])
+}
+
+func TestReadCURResourceRequiresID(t *testing.T) {
+	h := NewResourceHandler(&config.Config{Cost: config.CostConfig{CUR: config.CURConfig{Table: "cur"}}}, nil)
+	_, err := h.ReadResource(context.Background(), "aws://cost/cur/resources/")
+	assert.ErrorContains(t, err, "invalid CUR resource URI")
+}
@@ -0,0 +1,69 @@
+package mcp
+
+import (
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// demoImageNote replaces images in demo mode, since they cannot be pseudonymized
+const demoImageNote = "An image was withheld because demo mode cannot pseudonymize it"
+
+// pseudonymize replaces the identifiers in a tool result in demo mode. Names
+// are collected from every document first so each text is fully replaced.
+func (h *ToolHandler) pseudonymize(result *mcp.CallToolResult) *mcp.CallToolResult {
+	if h.demo == nil || result == nil {
+		return result
+	}
+
+	for _, content := range result.Content {
+		if text, ok := textOf(content); ok {
+			h.demo.Collect(text)
+		}
+	}
+
+	contents := make([]mcp.Content, 0, len(result.Content))
+	for _, content := range result.Content {
+		switch c := content.(type) {
+		case mcp.TextContent:
+			c.Text = h.demo.Text(c.Text)
+			contents = append(contents, c)
+		case *mcp.TextContent:
+			copied := *c
+			copied.Text = h.demo.Text(c.Text)
+			contents = append(contents, &copied)
+		case mcp.ImageContent, *mcp.ImageContent:
+			contents = append(contents, mcp.NewTextContent(demoImageNote))
+		default:
+			contents = append(contents, content)
+		}
+	}
+
+	pseudonymized := *result
+	pseudonymized.Content = contents
+	return &pseudonymized
+}
+
+// pseudonymize replaces the identifiers in a resource in demo mode
+func (h *ResourceHandler) pseudonymize(result *mcp.ReadResourceResult) *mcp.ReadResourceResult {
+	if h.demo == nil || result == nil {
+		return result
+	}
+
+	for _, contents := range result.Contents {
+		if text, ok := contents.(*mcp.TextResourceContents); ok {
+			h.demo.Collect(text.Text)
+		}
+	}
+
+	pseudonymized := make([]mcp.ResourceContents, 0, len(result.Contents))
+	for _, contents := range result.Contents {
+		if text, ok := contents.(*mcp.TextResourceContents); ok {
+			copied := *text
+			copied.URI = h.demo.Text(text.URI)
+			copied.Text = h.demo.Text(text.Text)
+			contents = &copied
+		}
+		pseudonymized = append(pseudonymized, contents)
+	}
+
+	return &mcp.ReadResourceResult{Result: result.Result, Contents: pseudonymized}
+}
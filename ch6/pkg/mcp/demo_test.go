@@ -0,0 +1,68 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"aws-mcp-server/internal/config"
+	"aws-mcp-server/internal/logging"
+	"aws-mcp-server/pkg/pseudonym"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDemoModePseudonymizesOutputs(t *testing.T) {
+	demo, err := pseudonym.New("book-exercises")
+	require.NoError(t, err)
+	tools := NewToolHandler(&config.Config{}, nil, logging.NewLogger("error", "text"))
+	tools.demo = demo
+	resources := NewResourceHandler(&config.Config{}, nil)
+	resources.ignored = tools.ignored
+	resources.demo = demo
+	ctx := context.Background()
+
+	result, err := tools.CallTool(ctx, "ignore-resource", map[string]interface{}{"resource": "i-0abc1234def567890", "reason": "kept for 123456789012"})
+	require.NoError(t, err)
+	data := decodeToolResult(t, result)
+	pseudonymized, _ := data["resource"].(string)
+	assert.Regexp(t, `^i-[0-9a-f]{17}$`, pseudonymized)
+	assert.NotEqual(t, "i-0abc1234def567890", pseudonymized)
+	assert.NotContains(t, data["reason"], "123456789012")
+
+	read, err := resources.ReadResource(ctx, ignoredResourcesURI)
+	require.NoError(t, err)
+	text := read.Contents[0].(*mcp.TextResourceContents).Text
+	assert.NotContains(t, text, "i-0abc1234def567890")
+	assert.Contains(t, text, pseudonymized, "outputs agree on pseudonyms")
+
+	// Callers act on what they were shown
+	result, err = tools.CallTool(ctx, "unignore-resource", map[string]interface{}{"resource": pseudonymized})
+	require.NoError(t, err)
+	assert.Equal(t, true, decodeToolResult(t, result)["success"])
+	_, ignored := tools.ignored.Get("i-0abc1234def567890", time.Now())
+	assert.False(t, ignored)
+}
+
+func TestDemoModeWithholdsImages(t *testing.T) {
+	demo, err := pseudonym.New("book-exercises")
+	require.NoError(t, err)
+	h := NewToolHandler(&config.Config{}, nil, logging.NewLogger("error", "text"))
+	h.demo = demo
+
+	payload, err := json.Marshal(map[string]interface{}{"instanceId": "i-0abc1234def567890"})
+	require.NoError(t, err)
+	result := h.pseudonymize(&mcp.CallToolResult{Content: []mcp.Content{
+		mcp.NewTextContent(string(payload)),
+		mcp.NewImageContent("aW1hZ2U=", "image/jpeg"),
+	}})
+
+	require.Len(t, result.Content, 2)
+	text, _ := textOf(result.Content[0])
+	assert.NotContains(t, text, "i-0abc1234def567890")
+	note, _ := textOf(result.Content[1])
+	assert.Equal(t, demoImageNote, note)
+}
@@ -0,0 +1,134 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/url"
+	"strings"
+
+	"aws-mcp-server/pkg/kb"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const (
+	// docsURI lists the runbooks and architecture docs of the knowledge base
+	docsURI = "kb://docs"
+	// docsSearchTemplate searches the knowledge base by keywords
+	docsSearchTemplate = "kb://docs{?query}"
+	// docTemplate is the URI template of one document, by its path
+	docTemplate = "kb://docs/{+path}"
+	// maxDocResults is how many documents a search returns
+	maxDocResults = 10
+)
+
+// readDocs lists the knowledge base documents or, with a query, the ones
+// matching it best so the AI can look up how things are run here
+func (h *ResourceHandler) readDocs(ctx context.Context, uri string) (*mcp.ReadResourceResult, error) {
+	if h.docs == nil {
+		return nil, fmt.Errorf("no knowledge base is configured, set docs.sources to directories or s3:// locations of markdown documents")
+	}
+
+	parsed, err := url.Parse(uri)
+	if err != nil {
+		return nil, fmt.Errorf("invalid docs URI: %w", err)
+	}
+	query := strings.TrimSpace(parsed.Query().Get("query"))
+
+	docs, failures := h.docs.Documents(ctx)
+	data := map[string]interface{}{
+		"total_documents": len(docs),
+		"sources":         h.docs.Sources(),
+	}
+	if len(failures) > 0 {
+		data["unavailable_sources"] = failures
+	}
+
+	if query == "" {
+		items := make([]map[string]interface{}, 0, len(docs))
+		for _, doc := range docs {
+			items = append(items, h.formatDocSummary(doc))
+		}
+		data["documents"] = items
+	} else {
+		results := h.docs.Search(ctx, query, maxDocResults)
+		items := make([]map[string]interface{}, 0, len(results))
+		for _, result := range results {
+			item := h.formatDocSummary(result.Document)
+			item["score"] = math.Round(result.Score*100) / 100
+			item["snippet"] = result.Snippet
+			items = append(items, item)
+		}
+		data["query"] = query
+		data["results"] = items
+		if len(items) == 0 {
+			data["note"] = fmt.Sprintf("No documents mention %q; try fewer or different words", query)
+		}
+	}
+
+	jsonData, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal docs data: %w", err)
+	}
+
+	return &mcp.ReadResourceResult{
+		Contents: []mcp.ResourceContents{
+			&mcp.TextResourceContents{
+				URI:      uri,
+				MIMEType: "application/json",
+				Text:     string(jsonData),
+			},
+		},
+	}, nil
+}
+
+// readDoc returns one document with its full markdown content
+func (h *ResourceHandler) readDoc(ctx context.Context, uri string) (*mcp.ReadResourceResult, error) {
+	docPath, _ := strings.CutPrefix(uri, docsURI+"/")
+	if unescaped, err := url.PathUnescape(docPath); err == nil {
+		docPath = unescaped
+	}
+	if docPath == "" {
+		return nil, fmt.Errorf("invalid document URI %s, use %s", uri, docTemplate)
+	}
+
+	doc := h.docs.Get(ctx, docPath)
+	if doc == nil {
+		return nil, fmt.Errorf("document %s not found, search %s to find documents", docPath, docsSearchTemplate)
+	}
+
+	data := h.formatDocSummary(doc)
+	data["source"] = doc.Source
+	data["content"] = doc.Content
+
+	jsonData, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal document data: %w", err)
+	}
+
+	return &mcp.ReadResourceResult{
+		Contents: []mcp.ResourceContents{
+			&mcp.TextResourceContents{
+				URI:      uri,
+				MIMEType: "application/json",
+				Text:     string(jsonData),
+			},
+		},
+	}, nil
+}
+
+// formatDocSummary describes a document without its content
+func (h *ResourceHandler) formatDocSummary(doc *kb.Document) map[string]interface{} {
+	item := map[string]interface{}{
+		"path":     doc.Path,
+		"title":    doc.Title,
+		"modified": h.times.Format(doc.Modified),
+		"uri":      docsURI + "/" + doc.Path,
+	}
+	if len(doc.Headings) > 0 {
+		item["headings"] = doc.Headings
+	}
+	return item
+}
@@ -0,0 +1,58 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"aws-mcp-server/internal/config"
+	"aws-mcp-server/pkg/kb"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadDocs(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "runbooks"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "runbooks", "rds-failover.md"), []byte("# RDS failover\n\n## Steps\n\nPromote the replica."), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "payments.md"), []byte("# Payments\n\nOrders are stored in RDS."), 0o600))
+
+	h := NewResourceHandler(&config.Config{}, nil)
+	_, err := h.ReadResource(context.Background(), "kb://docs")
+	assert.ErrorContains(t, err, "docs.sources")
+
+	h.docs, err = kb.New(config.DocsConfig{Sources: []string{dir}}, nil)
+	require.NoError(t, err)
+
+	read := func(uri string) map[string]interface{} {
+		result, err := h.ReadResource(context.Background(), uri)
+		require.NoError(t, err)
+		require.NotEmpty(t, result.Contents)
+		var data map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(result.Contents[0].(*mcp.TextResourceContents).Text), &data))
+		return data
+	}
+
+	data := read("kb://docs")
+	assert.Equal(t, float64(2), data["total_documents"])
+	assert.Len(t, data["documents"], 2)
+
+	data = read("kb://docs?query=replica+failover")
+	results := data["results"].([]interface{})
+	require.Len(t, results, 1)
+	assert.Equal(t, "kb://docs/runbooks/rds-failover.md", results[0].(map[string]interface{})["uri"])
+
+	data = read("kb://docs?query=kafka")
+	assert.Contains(t, data["note"], "No documents mention")
+
+	data = read("kb://docs/runbooks/rds-failover.md")
+	assert.Equal(t, "RDS failover", data["title"])
+	assert.Contains(t, data["content"], "Promote the replica.")
+
+	_, err = h.ReadResource(context.Background(), "kb://docs/runbooks/missing.md")
+	assert.ErrorContains(t, err, "not found")
+}
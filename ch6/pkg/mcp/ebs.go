@@ -0,0 +1,348 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"aws-mcp-server/pkg/aws"
+	"aws-mcp-server/pkg/types"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const (
+	ebsVolumesURI   = "aws://ebs/volumes"
+	ebsSnapshotsURI = "aws://ebs/snapshots"
+)
+
+// ebsVolumeTypes are the volume types modify-ebs-volume accepts
+var ebsVolumeTypes = map[string]bool{
+	"gp2":      true,
+	"gp3":      true,
+	"io1":      true,
+	"io2":      true,
+	"st1":      true,
+	"sc1":      true,
+	"standard": true,
+}
+
+// readEBSVolumes returns all EBS volumes with unattached volumes first
+func (h *ResourceHandler) readEBSVolumes(ctx context.Context) (*mcp.ReadResourceResult, error) {
+	volumes, err := h.awsClient.ListEBSVolumes(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list EBS volumes: %w", err)
+	}
+
+	jsonData, err := json.MarshalIndent(formatEBSVolumes(volumes), "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal EBS volumes data: %w", err)
+	}
+
+	return &mcp.ReadResourceResult{
+		Contents: []mcp.ResourceContents{
+			&mcp.TextResourceContents{
+				URI:      ebsVolumesURI,
+				MIMEType: "application/json",
+				Text:     string(jsonData),
+			},
+		},
+	}, nil
+}
+
+// readEBSSnapshots returns the EBS snapshots owned by the account, newest first
+func (h *ResourceHandler) readEBSSnapshots(ctx context.Context) (*mcp.ReadResourceResult, error) {
+	snapshots, err := h.awsClient.ListEBSSnapshots(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list EBS snapshots: %w", err)
+	}
+
+	jsonData, err := json.MarshalIndent(h.formatEBSSnapshots(snapshots), "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal EBS snapshots data: %w", err)
+	}
+
+	return &mcp.ReadResourceResult{
+		Contents: []mcp.ResourceContents{
+			&mcp.TextResourceContents{
+				URI:      ebsSnapshotsURI,
+				MIMEType: "application/json",
+				Text:     string(jsonData),
+			},
+		},
+	}, nil
+}
+
+// formatEBSVolumes formats EBS volumes for AI processing. Unattached volumes are
+// listed first because they are billed without serving any instance.
+func formatEBSVolumes(volumes []types.CloudResource) map[string]interface{} {
+	formatted := make([]map[string]interface{}, 0, len(volumes))
+	typeCount := make(map[string]int)
+	var totalGiB, unattachedGiB int32
+	unattached := 0
+
+	for _, volume := range volumes {
+		details := volume.EBSVolume()
+		volumeType, size := details.VolumeType, details.SizeGiB
+
+		item := map[string]interface{}{
+			"id":                volume.ID,
+			"state":             volume.State,
+			"type":              volumeType,
+			"size_gib":          size,
+			"encrypted":         details.Encrypted,
+			"availability_zone": details.AvailabilityZone,
+		}
+		if details.IOPS > 0 {
+			item["iops"] = details.IOPS
+		}
+		if details.Throughput > 0 {
+			item["throughput_mibps"] = details.Throughput
+		}
+		if name, exists := volume.Tags["Name"]; exists {
+			item["name"] = name
+		}
+
+		item["attached"] = len(details.Attachments) > 0
+		if len(details.Attachments) > 0 {
+			attachment := details.Attachments[0]
+			item["attached_to"] = attachment.InstanceID
+			item["instance_uri"] = fmt.Sprintf("aws://ec2/instances/%s", attachment.InstanceID)
+			item["device"] = attachment.Device
+			item["delete_on_termination"] = attachment.DeleteOnTermination
+		} else {
+			unattached++
+			unattachedGiB += size
+		}
+
+		formatted = append(formatted, item)
+		typeCount[volumeType]++
+		totalGiB += size
+	}
+
+	sort.SliceStable(formatted, func(i, j int) bool {
+		return !formatted[i]["attached"].(bool) && formatted[j]["attached"].(bool)
+	})
+
+	return map[string]interface{}{
+		"total_volumes":      len(volumes),
+		"total_gib":          totalGiB,
+		"unattached_volumes": unattached,
+		"unattached_gib":     unattachedGiB,
+		"summary_by_type":    typeCount,
+		"volumes":            formatted,
+	}
+}
+
+// formatEBSSnapshots formats EBS snapshots for AI processing, newest first
+func (h *ResourceHandler) formatEBSSnapshots(snapshots []types.CloudResource) map[string]interface{} {
+	sorted := append([]types.CloudResource(nil), snapshots...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return snapshotStart(sorted[i]).After(snapshotStart(sorted[j]))
+	})
+
+	formatted := make([]map[string]interface{}, 0, len(sorted))
+	var totalGiB int32
+	for _, snapshot := range sorted {
+		details := snapshot.EBSSnapshot()
+		size := details.SizeGiB
+
+		item := map[string]interface{}{
+			"id":           snapshot.ID,
+			"state":        snapshot.State,
+			"volume_id":    details.VolumeID,
+			"size_gib":     size,
+			"encrypted":    details.Encrypted,
+			"storage_tier": details.StorageTier,
+			"start_time":   h.formatDetails(snapshot.Details)["startTime"],
+		}
+		if details.Description != "" {
+			item["description"] = details.Description
+		}
+		if name, exists := snapshot.Tags["Name"]; exists {
+			item["name"] = name
+		}
+
+		formatted = append(formatted, item)
+		totalGiB += size
+	}
+
+	return map[string]interface{}{
+		"total_snapshots": len(snapshots),
+		"total_gib":       totalGiB,
+		"snapshots":       formatted,
+		"note":            "Snapshots are incremental: size_gib is the source volume size, not the billed storage",
+	}
+}
+
+// snapshotStart returns when a snapshot was started, or the zero time
+func snapshotStart(snapshot types.CloudResource) (start time.Time) {
+	if started := snapshot.EBSSnapshot().StartTime; started != nil {
+		start = *started
+	}
+	return start
+}
+
+// createEBSSnapshot starts a snapshot of an EBS volume
+func (h *ToolHandler) createEBSSnapshot(ctx context.Context, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	volumeID, ok := arguments["volumeId"].(string)
+	if !ok || volumeID == "" {
+		return h.createErrorResponse("volumeId is required")
+	}
+
+	description, _ := arguments["description"].(string)
+	if description == "" {
+		description = fmt.Sprintf("Manual snapshot of %s", volumeID)
+	}
+
+	snapshot, err := h.awsClient.CreateEBSSnapshot(ctx, volumeID, description)
+	if err != nil {
+		return h.createErrorResponse(fmt.Sprintf("failed to create EBS snapshot: %v", err))
+	}
+
+	return h.createSuccessResponse("EBS snapshot creation initiated successfully", map[string]interface{}{
+		"volumeId":    volumeID,
+		"snapshotId":  snapshot.ID,
+		"state":       snapshot.State,
+		"sizeGiB":     snapshot.EBSSnapshot().SizeGiB,
+		"description": description,
+	})
+}
+
+// deleteEBSSnapshot deletes an EBS snapshot. Without confirm=true it only
+// returns the snapshot that would be deleted.
+func (h *ToolHandler) deleteEBSSnapshot(ctx context.Context, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	snapshotID, ok := arguments["snapshotId"].(string)
+	if !ok || snapshotID == "" {
+		return h.createErrorResponse("snapshotId is required")
+	}
+
+	if !isConfirmed(arguments) {
+		snapshot, err := h.awsClient.GetEBSSnapshot(ctx, snapshotID)
+		if err != nil {
+			return h.createErrorResponse(fmt.Sprintf("failed to get EBS snapshot: %v", err))
+		}
+
+		plan := map[string]interface{}{
+			"snapshotId": snapshotID,
+			"volumeId":   snapshot.EBSSnapshot().VolumeID,
+			"sizeGiB":    snapshot.EBSSnapshot().SizeGiB,
+			"state":      snapshot.State,
+		}
+		if description := snapshot.EBSSnapshot().Description; description != "" {
+			plan["description"] = description
+		}
+		if len(snapshot.Tags) > 0 {
+			plan["tags"] = snapshot.Tags
+		}
+
+		warnings := []string{
+			"Deleted snapshots cannot be recovered unless a Recycle Bin retention rule covers them",
+			"Snapshots used by an AMI cannot be deleted until the AMI is deregistered",
+		}
+
+		return h.createConfirmationResponse("delete-ebs-snapshot", plan, warnings)
+	}
+
+	if err := h.awsClient.DeleteEBSSnapshot(ctx, snapshotID); err != nil {
+		return h.createErrorResponse(fmt.Sprintf("failed to delete EBS snapshot: %v", err))
+	}
+
+	return h.createSuccessResponse("EBS snapshot deleted successfully", map[string]interface{}{
+		"snapshotId": snapshotID,
+		"action":     "delete",
+	})
+}
+
+// modifyEBSVolume changes the size, type, IOPS or throughput of an EBS volume in place
+func (h *ToolHandler) modifyEBSVolume(ctx context.Context, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	params := aws.ModifyVolumeParams{}
+	params.VolumeID, _ = arguments["volumeId"].(string)
+	if params.VolumeID == "" {
+		return h.createErrorResponse("volumeId is required")
+	}
+
+	if size, ok := arguments["sizeGiB"].(float64); ok {
+		params.SizeGiB = int32(size)
+	}
+	params.VolumeType, _ = arguments["volumeType"].(string)
+	if iops, ok := arguments["iops"].(float64); ok {
+		params.IOPS = int32(iops)
+	}
+	if throughput, ok := arguments["throughput"].(float64); ok {
+		params.Throughput = int32(throughput)
+	}
+
+	volume, err := h.awsClient.GetEBSVolume(ctx, params.VolumeID)
+	if err != nil {
+		return h.createErrorResponse(fmt.Sprintf("failed to get EBS volume: %v", err))
+	}
+
+	if err := validateVolumeModification(volume, params); err != nil {
+		return h.createErrorResponse(err.Error())
+	}
+
+	result, err := h.awsClient.ModifyEBSVolume(ctx, params)
+	if err != nil {
+		return h.createErrorResponse(fmt.Sprintf("failed to modify EBS volume: %v", err))
+	}
+
+	data := map[string]interface{}{
+		"volumeId":           result.VolumeID,
+		"state":              result.State,
+		"originalSizeGiB":    result.OriginalSizeGiB,
+		"targetSizeGiB":      result.TargetSizeGiB,
+		"originalVolumeType": result.OriginalVolumeType,
+		"targetVolumeType":   result.TargetVolumeType,
+		"note":               "The volume stays in use while it is optimized; the next modification is possible after 6 hours",
+	}
+	if result.TargetIOPS > 0 {
+		data["targetIops"] = result.TargetIOPS
+	}
+	if result.TargetThroughput > 0 {
+		data["targetThroughput"] = result.TargetThroughput
+	}
+	if result.TargetSizeGiB > result.OriginalSizeGiB {
+		data["nextStep"] = "Extend the partition and file system on the instance (e.g. growpart and resize2fs or xfs_growfs) to use the new size"
+	}
+
+	return h.createSuccessResponse("EBS volume modification initiated successfully", data)
+}
+
+// validateVolumeModification checks a modification against the current volume:
+// volumes can only grow, IOPS apply to gp3, io1 and io2, and throughput to gp3
+func validateVolumeModification(volume *types.CloudResource, params aws.ModifyVolumeParams) error {
+	if params.SizeGiB == 0 && params.VolumeType == "" && params.IOPS == 0 && params.Throughput == 0 {
+		return fmt.Errorf("set at least one of sizeGiB, volumeType, iops or throughput")
+	}
+	if params.SizeGiB < 0 || params.IOPS < 0 || params.Throughput < 0 {
+		return fmt.Errorf("sizeGiB, iops and throughput must be positive")
+	}
+
+	if currentSize := volume.EBSVolume().SizeGiB; params.SizeGiB > 0 && params.SizeGiB < currentSize {
+		return fmt.Errorf("cannot shrink volume %s from %d GiB to %d GiB: EBS volumes can only grow", volume.ID, currentSize, params.SizeGiB)
+	}
+
+	volumeType := volume.EBSVolume().VolumeType
+	if params.VolumeType != "" {
+		if !ebsVolumeTypes[params.VolumeType] {
+			return fmt.Errorf("invalid volumeType %q, use gp3, gp2, io2, io1, st1, sc1 or standard", params.VolumeType)
+		}
+		volumeType = params.VolumeType
+	}
+
+	switch volumeType {
+	case "gp3":
+	case "io1", "io2":
+		if params.Throughput > 0 {
+			return fmt.Errorf("throughput can only be set on gp3 volumes, volume %s is %s", volume.ID, volumeType)
+		}
+	default:
+		if params.IOPS > 0 || params.Throughput > 0 {
+			return fmt.Errorf("iops and throughput cannot be set on %s volumes, change volumeType to gp3 or io2 as well", volumeType)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"aws-mcp-server/internal/config"
+	"aws-mcp-server/internal/logging"
+	"aws-mcp-server/pkg/aws"
+	"aws-mcp-server/pkg/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatEBSVolumes(t *testing.T) {
+	volumes := []types.CloudResource{
+		{
+			ID:    "vol-root",
+			State: "in-use",
+			Tags:  map[string]string{"Name": "web-root"},
+			Details: &types.EBSVolumeDetails{
+				VolumeType: "gp3",
+				SizeGiB:    20,
+				IOPS:       3000,
+				Throughput: 125,
+				Attachments: []types.EBSAttachment{
+					{InstanceID: "i-web", Device: "/dev/xvda", DeleteOnTermination: true},
+				},
+			},
+		},
+		{
+			ID:    "vol-old",
+			State: "available",
+			Details: &types.EBSVolumeDetails{
+				VolumeType:  "gp2",
+				SizeGiB:     100,
+				Attachments: []types.EBSAttachment{},
+			},
+		},
+	}
+
+	formatted := formatEBSVolumes(volumes)
+	assert.Equal(t, int32(120), formatted["total_gib"])
+	assert.Equal(t, 1, formatted["unattached_volumes"])
+	assert.Equal(t, int32(100), formatted["unattached_gib"])
+	assert.Equal(t, map[string]int{"gp3": 1, "gp2": 1}, formatted["summary_by_type"])
+
+	items := formatted["volumes"].([]map[string]interface{})
+	require.Len(t, items, 2)
+	assert.Equal(t, "vol-old", items[0]["id"])
+	assert.Equal(t, false, items[0]["attached"])
+	assert.NotContains(t, items[0], "iops")
+	assert.Equal(t, "aws://ec2/instances/i-web", items[1]["instance_uri"])
+	assert.Equal(t, "web-root", items[1]["name"])
+}
+
+func TestFormatEBSSnapshotsNewestFirst(t *testing.T) {
+	older := time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC)
+	newer := older.AddDate(0, 1, 0)
+	h := NewResourceHandler(&config.Config{}, nil)
+
+	formatted := h.formatEBSSnapshots([]types.CloudResource{
+		{ID: "snap-old", State: "completed", Details: &types.EBSSnapshotDetails{SizeGiB: 8, StartTime: &older}},
+		{ID: "snap-new", State: "pending", Details: &types.EBSSnapshotDetails{SizeGiB: 20, StartTime: &newer, Description: "before upgrade"}},
+	})
+
+	assert.Equal(t, int32(28), formatted["total_gib"])
+	items := formatted["snapshots"].([]map[string]interface{})
+	require.Len(t, items, 2)
+	assert.Equal(t, "snap-new", items[0]["id"])
+	assert.Equal(t, "before upgrade", items[0]["description"])
+	assert.NotContains(t, items[1], "description")
+}
+
+func TestValidateVolumeModification(t *testing.T) {
+	volume := &types.CloudResource{ID: "vol-1", Details: &types.EBSVolumeDetails{VolumeType: "gp2", SizeGiB: 100}}
+
+	assert.NoError(t, validateVolumeModification(volume, aws.ModifyVolumeParams{SizeGiB: 200}))
+	assert.NoError(t, validateVolumeModification(volume, aws.ModifyVolumeParams{VolumeType: "gp3", IOPS: 6000, Throughput: 250}))
+
+	err := validateVolumeModification(volume, aws.ModifyVolumeParams{})
+	assert.ErrorContains(t, err, "set at least one")
+
+	err = validateVolumeModification(volume, aws.ModifyVolumeParams{SizeGiB: 50})
+	assert.ErrorContains(t, err, "can only grow")
+
+	err = validateVolumeModification(volume, aws.ModifyVolumeParams{VolumeType: "gp4"})
+	assert.ErrorContains(t, err, `invalid volumeType "gp4"`)
+
+	err = validateVolumeModification(volume, aws.ModifyVolumeParams{IOPS: 6000})
+	assert.ErrorContains(t, err, "cannot be set on gp2 volumes")
+
+	err = validateVolumeModification(volume, aws.ModifyVolumeParams{VolumeType: "io2", Throughput: 250})
+	assert.ErrorContains(t, err, "only be set on gp3")
+}
+
+func TestEBSToolsValidateArguments(t *testing.T) {
+	h := NewToolHandler(&config.Config{}, nil, logging.NewLogger("error", "text"))
+	ctx := context.Background()
+
+	result, err := h.CallTool(ctx, "create-ebs-snapshot", map[string]interface{}{})
+	require.NoError(t, err)
+	assert.Equal(t, "volumeId is required", decodeToolResult(t, result)["error"])
+
+	result, err = h.CallTool(ctx, "delete-ebs-snapshot", map[string]interface{}{"confirm": true})
+	require.NoError(t, err)
+	assert.Equal(t, "snapshotId is required", decodeToolResult(t, result)["error"])
+
+	result, err = h.CallTool(ctx, "modify-ebs-volume", map[string]interface{}{"sizeGiB": float64(200)})
+	require.NoError(t, err)
+	assert.Equal(t, "volumeId is required", decodeToolResult(t, result)["error"])
+}
@@ -0,0 +1,274 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"aws-mcp-server/pkg/types"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const (
+	// ecrRepositoriesURI lists the ECR repositories of the account
+	ecrRepositoriesURI = "aws://ecr/repositories"
+	// ecrImagesTemplate is the URI template of the images of one repository
+	ecrImagesTemplate = "aws://ecr/repositories/{+name}"
+	// ecrImageTemplate is the URI template of the scan findings of one image,
+	// referenced by tag or by sha256: digest
+	ecrImageTemplate = "aws://ecr/repositories/{+name}/images/{reference}"
+	// maxECRImages caps how many of the newest images a repository lists
+	maxECRImages = 50
+)
+
+// ecrSeverities are the scan finding severities, most severe first
+var ecrSeverities = []string{"CRITICAL", "HIGH", "MEDIUM", "LOW", "INFORMATIONAL", "UNDEFINED"}
+
+// ecrImageReference returns the ECR repository and the tag or digest of a
+// container image, e.g. web and sha256:... for
+// 123456789012.dkr.ecr.us-east-1.amazonaws.com/web:v42 running as that digest.
+// It returns false for images not hosted in ECR.
+func ecrImageReference(image, digest string) (string, string, bool) {
+	host, path, ok := strings.Cut(image, "/")
+	if !ok || !strings.Contains(host, ".dkr.ecr.") {
+		return "", "", false
+	}
+
+	repository, reference := path, "latest"
+	if name, pinned, ok := strings.Cut(path, "@"); ok {
+		repository, reference = name, pinned
+	} else if i := strings.LastIndex(path, ":"); i >= 0 {
+		repository, reference = path[:i], path[i+1:]
+	}
+	if digest != "" {
+		reference = digest
+	}
+	return repository, reference, repository != ""
+}
+
+// ecrImageURI returns the URI of the scan findings of an image in a repository
+func ecrImageURI(repository, reference string) string {
+	return ecrRepositoriesURI + "/" + repository + "/images/" + reference
+}
+
+// readECRRepositories lists the ECR repositories with their scan and tag settings
+func (h *ResourceHandler) readECRRepositories(ctx context.Context) (*mcp.ReadResourceResult, error) {
+	repositories, err := h.awsClient.ListECRRepositories(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list ECR repositories: %w", err)
+	}
+
+	sort.Slice(repositories, func(i, j int) bool { return repositories[i].Name < repositories[j].Name })
+	items := make([]map[string]interface{}, 0, len(repositories))
+	withoutScanOnPush := 0
+	for _, repository := range repositories {
+		if !repository.ScanOnPush {
+			withoutScanOnPush++
+		}
+		items = append(items, map[string]interface{}{
+			"name":           repository.Name,
+			"uri":            ecrRepositoriesURI + "/" + repository.Name,
+			"repository_uri": repository.URI,
+			"created":        h.times.Format(repository.CreatedAt),
+			"tag_mutability": repository.TagMutability,
+			"scan_on_push":   repository.ScanOnPush,
+			"encryption":     repository.EncryptionType,
+		})
+	}
+
+	jsonData, err := json.MarshalIndent(map[string]interface{}{
+		"total":                len(repositories),
+		"without_scan_on_push": withoutScanOnPush,
+		"repositories":         items,
+	}, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal ECR repositories data: %w", err)
+	}
+
+	return &mcp.ReadResourceResult{
+		Contents: []mcp.ResourceContents{
+			&mcp.TextResourceContents{
+				URI:      ecrRepositoriesURI,
+				MIMEType: "application/json",
+				Text:     string(jsonData),
+			},
+		},
+	}, nil
+}
+
+// readECRImages lists the newest images of the repository in the URI with
+// their tags, digests and scan finding counts
+func (h *ResourceHandler) readECRImages(ctx context.Context, uri string) (*mcp.ReadResourceResult, error) {
+	repository, _ := strings.CutPrefix(uri, ecrRepositoriesURI+"/")
+	if unescaped, err := url.PathUnescape(repository); err == nil {
+		repository = unescaped
+	}
+	if repository == "" {
+		return nil, fmt.Errorf("invalid ECR repository URI %s, use %s", uri, ecrImagesTemplate)
+	}
+
+	images, err := h.awsClient.ListECRImages(ctx, repository)
+	if err != nil {
+		return nil, err
+	}
+
+	jsonData, err := json.MarshalIndent(h.formatECRImages(repository, images), "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal ECR images data: %w", err)
+	}
+
+	return &mcp.ReadResourceResult{
+		Contents: []mcp.ResourceContents{
+			&mcp.TextResourceContents{
+				URI:      uri,
+				MIMEType: "application/json",
+				Text:     string(jsonData),
+			},
+		},
+	}, nil
+}
+
+// formatECRImages orders the images of a repository newest first, keeps the
+// newest maxECRImages and counts the images with critical or high findings
+func (h *ResourceHandler) formatECRImages(repository string, images []types.ECRImage) map[string]interface{} {
+	sort.SliceStable(images, func(i, j int) bool { return images[i].PushedAt.After(images[j].PushedAt) })
+
+	untagged, vulnerable := 0, 0
+	for _, image := range images {
+		if len(image.Tags) == 0 {
+			untagged++
+		}
+		if image.FindingCounts["CRITICAL"] > 0 || image.FindingCounts["HIGH"] > 0 {
+			vulnerable++
+		}
+	}
+
+	shown := images[:min(len(images), maxECRImages)]
+	items := make([]map[string]interface{}, 0, len(shown))
+	for _, image := range shown {
+		reference, tags := image.Digest, image.Tags
+		if len(tags) > 0 {
+			reference = tags[0]
+		} else {
+			tags = []string{}
+		}
+		item := map[string]interface{}{
+			"digest":      image.Digest,
+			"tags":        tags,
+			"pushed":      h.times.Format(image.PushedAt),
+			"size_mib":    float64(image.SizeBytes/1024) / 1024,
+			"uri":         ecrImageURI(repository, reference),
+			"scan_status": image.ScanStatus,
+		}
+		if image.LastPulledAt != nil {
+			item["last_pulled"] = h.times.Format(*image.LastPulledAt)
+		}
+		if image.ScannedAt != nil {
+			item["scanned"] = h.times.Format(*image.ScannedAt)
+		}
+		if len(image.FindingCounts) > 0 {
+			item["findings"] = image.FindingCounts
+		}
+		items = append(items, item)
+	}
+
+	data := map[string]interface{}{
+		"repository":            repository,
+		"total":                 len(images),
+		"untagged":              untagged,
+		"with_critical_or_high": vulnerable,
+		"images":                items,
+	}
+	if len(images) > len(shown) {
+		data["note"] = fmt.Sprintf("Only the newest %d images are listed", maxECRImages)
+	}
+	return data
+}
+
+// readECRImage returns the scan findings of the image in the URI, most
+// severe first
+func (h *ResourceHandler) readECRImage(ctx context.Context, uri string) (*mcp.ReadResourceResult, error) {
+	path, _ := strings.CutPrefix(uri, ecrRepositoriesURI+"/")
+	i := strings.LastIndex(path, "/images/")
+	if i <= 0 {
+		return nil, fmt.Errorf("invalid ECR image URI %s, use %s", uri, ecrImageTemplate)
+	}
+	repository, reference := path[:i], path[i+len("/images/"):]
+	if unescaped, err := url.PathUnescape(repository); err == nil {
+		repository = unescaped
+	}
+	if unescaped, err := url.PathUnescape(reference); err == nil {
+		reference = unescaped
+	}
+	if reference == "" {
+		return nil, fmt.Errorf("invalid ECR image URI %s, use %s", uri, ecrImageTemplate)
+	}
+
+	findings, err := h.awsClient.GetECRScanFindings(ctx, repository, reference)
+	if err != nil {
+		return nil, err
+	}
+
+	data := h.formatECRScanFindings(*findings)
+	data["repository"] = repository
+	data["reference"] = reference
+
+	jsonData, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal ECR image data: %w", err)
+	}
+
+	return &mcp.ReadResourceResult{
+		Contents: []mcp.ResourceContents{
+			&mcp.TextResourceContents{
+				URI:      uri,
+				MIMEType: "application/json",
+				Text:     string(jsonData),
+			},
+		},
+	}, nil
+}
+
+// formatECRScanFindings orders the findings of an image scan most severe
+// first and notes findings that have a fixed package version
+func (h *ResourceHandler) formatECRScanFindings(findings types.ECRScanFindings) map[string]interface{} {
+	rank := func(severity string) int {
+		for i, s := range ecrSeverities {
+			if s == severity {
+				return i
+			}
+		}
+		return len(ecrSeverities)
+	}
+	sort.SliceStable(findings.Findings, func(i, j int) bool {
+		return rank(findings.Findings[i].Severity) < rank(findings.Findings[j].Severity)
+	})
+
+	fixable := 0
+	for _, finding := range findings.Findings {
+		if finding.FixedIn != "" {
+			fixable++
+		}
+	}
+
+	data := map[string]interface{}{
+		"digest":      findings.Digest,
+		"scan_status": findings.ScanStatus,
+		"total":       len(findings.Findings),
+		"fixable":     fixable,
+		"findings":    findings.Findings,
+	}
+	if findings.ScannedAt != nil {
+		data["scanned"] = h.times.Format(*findings.ScannedAt)
+		data["scan_age_days"] = daysSince(*findings.ScannedAt, time.Now())
+	}
+	if len(findings.FindingCounts) > 0 {
+		data["by_severity"] = findings.FindingCounts
+	}
+	return data
+}
@@ -0,0 +1,83 @@
+package mcp
+
+import (
+	"testing"
+	"time"
+
+	"aws-mcp-server/internal/config"
+	"aws-mcp-server/pkg/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestECRImageReference(t *testing.T) {
+	tests := []struct {
+		name       string
+		image      string
+		digest     string
+		repository string
+		reference  string
+		ok         bool
+	}{
+		{name: "tag", image: "123456789012.dkr.ecr.us-east-1.amazonaws.com/web:v42", repository: "web", reference: "v42", ok: true},
+		{name: "running digest wins", image: "123456789012.dkr.ecr.us-east-1.amazonaws.com/team/web:v42", digest: "sha256:abc", repository: "team/web", reference: "sha256:abc", ok: true},
+		{name: "pinned digest", image: "123456789012.dkr.ecr.us-east-1.amazonaws.com/web@sha256:def", repository: "web", reference: "sha256:def", ok: true},
+		{name: "implicit latest", image: "123456789012.dkr.ecr.us-east-1.amazonaws.com/web", repository: "web", reference: "latest", ok: true},
+		{name: "docker hub", image: "nginx:1.27"},
+		{name: "other registry", image: "ghcr.io/org/app:1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repository, reference, ok := ecrImageReference(tt.image, tt.digest)
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.repository, repository)
+			assert.Equal(t, tt.reference, reference)
+		})
+	}
+}
+
+func TestFormatECRImages(t *testing.T) {
+	h := NewResourceHandler(&config.Config{}, nil)
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	data := h.formatECRImages("team/web", []types.ECRImage{
+		{Digest: "sha256:old", PushedAt: now.AddDate(0, 0, -30), SizeBytes: 50 << 20},
+		{Digest: "sha256:new", Tags: []string{"v42", "latest"}, PushedAt: now, SizeBytes: 100 << 20,
+			FindingCounts: map[string]int32{"HIGH": 2, "LOW": 5}},
+	})
+
+	assert.Equal(t, 2, data["total"])
+	assert.Equal(t, 1, data["untagged"])
+	assert.Equal(t, 1, data["with_critical_or_high"])
+	images := data["images"].([]map[string]interface{})
+	require.Len(t, images, 2)
+	assert.Equal(t, "sha256:new", images[0]["digest"], "newest first")
+	assert.Equal(t, "aws://ecr/repositories/team/web/images/v42", images[0]["uri"])
+	assert.Equal(t, 100.0, images[0]["size_mib"])
+	assert.Equal(t, []string{}, images[1]["tags"])
+	assert.Equal(t, "aws://ecr/repositories/team/web/images/sha256:old", images[1]["uri"])
+	assert.NotContains(t, data, "note")
+}
+
+func TestFormatECRScanFindings(t *testing.T) {
+	h := NewResourceHandler(&config.Config{}, nil)
+
+	data := h.formatECRScanFindings(types.ECRScanFindings{
+		Digest:     "sha256:abc",
+		ScanStatus: "COMPLETE",
+		Findings: []types.ECRScanFinding{
+			{Name: "CVE-1", Severity: "LOW"},
+			{Name: "CVE-2", Severity: "CRITICAL", Package: "openssl", FixedIn: "3.0.14"},
+			{Name: "CVE-3", Severity: "MEDIUM"},
+		},
+	})
+
+	assert.Equal(t, 3, data["total"])
+	assert.Equal(t, 1, data["fixable"])
+	findings := data["findings"].([]types.ECRScanFinding)
+	assert.Equal(t, "CVE-2", findings[0].Name)
+	assert.Equal(t, "CVE-3", findings[1].Name)
+	assert.Equal(t, "CVE-1", findings[2].Name)
+}
@@ -0,0 +1,415 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"aws-mcp-server/pkg/types"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const (
+	// ecsClustersURI lists the ECS clusters with their service and task counts
+	ecsClustersURI = "aws://ecs/clusters"
+	// ecsServicesTemplate is the URI template of the services of one cluster
+	ecsServicesTemplate = "aws://ecs/clusters/{cluster}/services"
+	// ecsTasksTemplate is the URI template of the tasks of one service
+	ecsTasksTemplate = "aws://ecs/clusters/{cluster}/services/{service}/tasks"
+	// ecsStuckDeploymentAge is how long a rollout may stay in progress before
+	// the service is reported as stuck
+	ecsStuckDeploymentAge = 30 * time.Minute
+	// maxECSDesiredCount is the ECS quota on tasks per service
+	maxECSDesiredCount = 5000
+)
+
+// readECSClusters returns all ECS clusters with their service and task counts
+func (h *ResourceHandler) readECSClusters(ctx context.Context) (*mcp.ReadResourceResult, error) {
+	clusters, err := h.awsClient.ListECSClusters(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list ECS clusters: %w", err)
+	}
+
+	formatted := make([]map[string]interface{}, 0, len(clusters))
+	runningTasks := int32(0)
+	for _, cluster := range clusters {
+		formatted = append(formatted, map[string]interface{}{
+			"name":                cluster.Name,
+			"status":              cluster.Status,
+			"active_services":     cluster.ActiveServices,
+			"running_tasks":       cluster.RunningTasks,
+			"pending_tasks":       cluster.PendingTasks,
+			"container_instances": cluster.ContainerInstances,
+			"capacity_providers":  cluster.CapacityProviders,
+			"services_uri":        ecsServicesURI(cluster.Name),
+		})
+		runningTasks += cluster.RunningTasks
+	}
+
+	data := map[string]interface{}{
+		"total_clusters": len(clusters),
+		"running_tasks":  runningTasks,
+		"clusters":       formatted,
+	}
+
+	jsonData, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal ECS clusters data: %w", err)
+	}
+
+	return &mcp.ReadResourceResult{
+		Contents: []mcp.ResourceContents{
+			&mcp.TextResourceContents{
+				URI:      ecsClustersURI,
+				MIMEType: "application/json",
+				Text:     string(jsonData),
+			},
+		},
+	}, nil
+}
+
+// readECSServices returns the services of a cluster, those that look stuck first
+func (h *ResourceHandler) readECSServices(ctx context.Context, uri string) (*mcp.ReadResourceResult, error) {
+	cluster, service, err := ecsPathFromURI(uri)
+	if err != nil {
+		return nil, err
+	}
+	if service != "" {
+		return nil, fmt.Errorf("invalid ECS services URI %s, use %s", uri, ecsServicesTemplate)
+	}
+
+	services, err := h.awsClient.ListECSServices(ctx, cluster)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list ECS services: %w", err)
+	}
+
+	jsonData, err := json.MarshalIndent(h.formatECSServices(cluster, services, time.Now()), "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal ECS services data: %w", err)
+	}
+
+	return &mcp.ReadResourceResult{
+		Contents: []mcp.ResourceContents{
+			&mcp.TextResourceContents{
+				URI:      uri,
+				MIMEType: "application/json",
+				Text:     string(jsonData),
+			},
+		},
+	}, nil
+}
+
+// readECSTasks returns the running and recently stopped tasks of a service
+func (h *ResourceHandler) readECSTasks(ctx context.Context, uri string) (*mcp.ReadResourceResult, error) {
+	cluster, service, err := ecsPathFromURI(uri)
+	if err != nil {
+		return nil, err
+	}
+	if service == "" {
+		return nil, fmt.Errorf("invalid ECS tasks URI %s, use %s", uri, ecsTasksTemplate)
+	}
+
+	tasks, err := h.awsClient.ListECSTasks(ctx, cluster, service)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list ECS tasks: %w", err)
+	}
+
+	jsonData, err := json.MarshalIndent(h.formatECSTasks(cluster, service, tasks), "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal ECS tasks data: %w", err)
+	}
+
+	return &mcp.ReadResourceResult{
+		Contents: []mcp.ResourceContents{
+			&mcp.TextResourceContents{
+				URI:      uri,
+				MIMEType: "application/json",
+				Text:     string(jsonData),
+			},
+		},
+	}, nil
+}
+
+// ecsServicesURI returns the services URI of a cluster
+func ecsServicesURI(cluster string) string {
+	return fmt.Sprintf("%s/%s/services", ecsClustersURI, cluster)
+}
+
+// ecsPathFromURI extracts the cluster, and the service for task URIs, from
+// aws://ecs/clusters/{cluster}/services[/{service}/tasks]
+func ecsPathFromURI(uri string) (cluster, service string, err error) {
+	path, _ := strings.CutPrefix(uri, ecsClustersURI+"/")
+	segments := strings.Split(path, "/")
+
+	switch {
+	case len(segments) == 2 && segments[0] != "" && segments[1] == "services":
+		return segments[0], "", nil
+	case len(segments) == 4 && segments[0] != "" && segments[1] == "services" && segments[2] != "" && segments[3] == "tasks":
+		return segments[0], segments[2], nil
+	}
+	return "", "", fmt.Errorf("invalid ECS URI %s, use %s or %s", uri, ecsServicesTemplate, ecsTasksTemplate)
+}
+
+// formatECSServices formats the services of a cluster for AI processing.
+// Services with issues come first so stuck rollouts stand out.
+func (h *ResourceHandler) formatECSServices(cluster string, services []types.ECSService, now time.Time) map[string]interface{} {
+	formatted := make([]map[string]interface{}, 0, len(services))
+	var unhealthy []string
+	for _, service := range services {
+		item := h.formatECSService(service)
+		issues := ecsServiceIssues(service, now)
+		if len(issues) > 0 {
+			item["issues"] = issues
+			unhealthy = append(unhealthy, service.Name)
+		}
+		formatted = append(formatted, item)
+	}
+
+	sort.SliceStable(formatted, func(i, j int) bool {
+		_, iIssues := formatted[i]["issues"]
+		_, jIssues := formatted[j]["issues"]
+		return iIssues && !jIssues
+	})
+
+	return map[string]interface{}{
+		"cluster":            cluster,
+		"total_services":     len(services),
+		"unhealthy_services": len(unhealthy),
+		"unhealthy":          unhealthy,
+		"services":           formatted,
+	}
+}
+
+// formatECSService formats one service with its deployments and latest events
+func (h *ResourceHandler) formatECSService(service types.ECSService) map[string]interface{} {
+	deployments := make([]map[string]interface{}, 0, len(service.Deployments))
+	for _, deployment := range service.Deployments {
+		item := map[string]interface{}{
+			"id":              deployment.ID,
+			"status":          deployment.Status,
+			"task_definition": deployment.TaskDefinition,
+			"desired":         deployment.DesiredCount,
+			"running":         deployment.RunningCount,
+			"pending":         deployment.PendingCount,
+			"failed_tasks":    deployment.FailedTasks,
+			"created":         h.times.Format(deployment.CreatedAt),
+			"updated":         h.times.Format(deployment.UpdatedAt),
+		}
+		if deployment.RolloutState != "" {
+			item["rollout_state"] = deployment.RolloutState
+		}
+		if deployment.RolloutStateReason != "" {
+			item["rollout_reason"] = deployment.RolloutStateReason
+		}
+		deployments = append(deployments, item)
+	}
+
+	events := make([]map[string]interface{}, 0, len(service.Events))
+	for _, event := range service.Events {
+		events = append(events, map[string]interface{}{
+			"time":    h.times.Format(event.Time),
+			"message": event.Message,
+		})
+	}
+
+	return map[string]interface{}{
+		"name":            service.Name,
+		"status":          service.Status,
+		"launch_type":     service.LaunchType,
+		"task_definition": service.TaskDefinition,
+		"desired":         service.DesiredCount,
+		"running":         service.RunningCount,
+		"pending":         service.PendingCount,
+		"deployments":     deployments,
+		"recent_events":   events,
+		"tasks_uri":       fmt.Sprintf("%s/%s/tasks", ecsServicesURI(service.Cluster), service.Name),
+	}
+}
+
+// ecsServiceIssues lists why a service looks stuck: a failed or long-running
+// rollout, tasks failing to start or fewer running tasks than desired
+func ecsServiceIssues(service types.ECSService, now time.Time) []string {
+	var issues []string
+
+	for _, deployment := range service.Deployments {
+		if deployment.Status != "PRIMARY" {
+			continue
+		}
+		switch {
+		case deployment.RolloutState == "FAILED":
+			issue := fmt.Sprintf("deployment %s failed", deployment.ID)
+			if deployment.RolloutStateReason != "" {
+				issue += ": " + deployment.RolloutStateReason
+			}
+			issues = append(issues, issue)
+		case deployment.RolloutState == "IN_PROGRESS" && now.Sub(deployment.CreatedAt) > ecsStuckDeploymentAge:
+			issues = append(issues, fmt.Sprintf("deployment %s has been in progress for %s",
+				deployment.ID, now.Sub(deployment.CreatedAt).Round(time.Minute)))
+		}
+		if deployment.FailedTasks > 0 {
+			issues = append(issues, fmt.Sprintf("%d tasks of deployment %s failed to start", deployment.FailedTasks, deployment.ID))
+		}
+	}
+
+	if service.Status == "ACTIVE" && service.RunningCount < service.DesiredCount {
+		issues = append(issues, fmt.Sprintf("%d of %d desired tasks running", service.RunningCount, service.DesiredCount))
+	}
+
+	return issues
+}
+
+// formatECSTasks formats the tasks of a service with counts by status and the
+// reasons stopped tasks gave
+func (h *ResourceHandler) formatECSTasks(cluster, service string, tasks []types.ECSTask) map[string]interface{} {
+	formatted := make([]map[string]interface{}, 0, len(tasks))
+	statusCount := make(map[string]int)
+	stoppedReasons := make(map[string]int)
+
+	for _, task := range tasks {
+		item := map[string]interface{}{
+			"id":              task.ID,
+			"task_definition": task.TaskDefinition,
+			"last_status":     task.LastStatus,
+			"desired_status":  task.DesiredStatus,
+			"health":          task.HealthStatus,
+			"launch_type":     task.LaunchType,
+		}
+		if task.StartedAt != nil {
+			item["started"] = h.times.Format(*task.StartedAt)
+		}
+		if task.StoppedAt != nil {
+			item["stopped"] = h.times.Format(*task.StoppedAt)
+		}
+		if task.StopCode != "" {
+			item["stop_code"] = task.StopCode
+		}
+		if task.StoppedReason != "" {
+			item["stopped_reason"] = task.StoppedReason
+			stoppedReasons[task.StoppedReason]++
+		}
+
+		containers := make([]map[string]interface{}, 0, len(task.Containers))
+		for _, container := range task.Containers {
+			entry := map[string]interface{}{
+				"name":        container.Name,
+				"last_status": container.LastStatus,
+				"health":      container.HealthStatus,
+			}
+			if container.ExitCode != nil {
+				entry["exit_code"] = *container.ExitCode
+			}
+			if container.Reason != "" {
+				entry["reason"] = container.Reason
+			}
+			if container.Image != "" {
+				entry["image"] = container.Image
+			}
+			if container.ImageDigest != "" {
+				entry["image_digest"] = container.ImageDigest
+			}
+			if repository, reference, ok := ecrImageReference(container.Image, container.ImageDigest); ok {
+				entry["image_uri"] = ecrImageURI(repository, reference)
+			}
+			containers = append(containers, entry)
+		}
+		item["containers"] = containers
+
+		formatted = append(formatted, item)
+		statusCount[task.LastStatus]++
+	}
+
+	return map[string]interface{}{
+		"cluster":           cluster,
+		"service":           service,
+		"total_tasks":       len(tasks),
+		"summary_by_status": statusCount,
+		"stopped_reasons":   stoppedReasons,
+		"tasks":             formatted,
+	}
+}
+
+// ecsServiceArgs reads the cluster and service arguments of the ECS tools
+func ecsServiceArgs(arguments map[string]interface{}) (cluster, service, problem string) {
+	cluster, _ = arguments["cluster"].(string)
+	service, _ = arguments["service"].(string)
+	switch {
+	case cluster == "":
+		return "", "", "cluster is required"
+	case service == "":
+		return "", "", "service is required"
+	}
+	return cluster, service, ""
+}
+
+// updateECSService sets the desired task count of an ECS service
+func (h *ToolHandler) updateECSService(ctx context.Context, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	cluster, service, problem := ecsServiceArgs(arguments)
+	if problem != "" {
+		return h.createErrorResponse(problem)
+	}
+
+	count, ok := arguments["desiredCount"].(float64)
+	if !ok {
+		return h.createErrorResponse("desiredCount is required")
+	}
+	if count < 0 || count > maxECSDesiredCount || count != math.Trunc(count) {
+		return h.createErrorResponse(fmt.Sprintf("desiredCount must be a whole number between 0 and %d, got %g", maxECSDesiredCount, count))
+	}
+
+	current, err := h.awsClient.GetECSService(ctx, cluster, service)
+	if err != nil {
+		return h.createErrorResponse(fmt.Sprintf("failed to get ECS service: %v", err))
+	}
+
+	updated, err := h.awsClient.UpdateECSServiceDesiredCount(ctx, cluster, service, int32(count))
+	if err != nil {
+		return h.createErrorResponse(fmt.Sprintf("failed to update ECS service: %v", err))
+	}
+
+	data := map[string]interface{}{
+		"cluster":              cluster,
+		"service":              service,
+		"previousDesiredCount": current.DesiredCount,
+		"desiredCount":         updated.DesiredCount,
+		"runningCount":         updated.RunningCount,
+	}
+	if updated.DesiredCount == 0 {
+		data["note"] = "The service now runs no tasks; scale it up again to restore it"
+	}
+
+	return h.createSuccessResponse("ECS service desired count updated successfully", data)
+}
+
+// forceECSDeployment replaces every task of an ECS service with new ones from
+// the same task definition
+func (h *ToolHandler) forceECSDeployment(ctx context.Context, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	cluster, service, problem := ecsServiceArgs(arguments)
+	if problem != "" {
+		return h.createErrorResponse(problem)
+	}
+
+	updated, err := h.awsClient.ForceNewECSDeployment(ctx, cluster, service)
+	if err != nil {
+		return h.createErrorResponse(fmt.Sprintf("failed to force new ECS deployment: %v", err))
+	}
+
+	data := map[string]interface{}{
+		"cluster":        cluster,
+		"service":        service,
+		"taskDefinition": updated.TaskDefinition,
+		"desiredCount":   updated.DesiredCount,
+		"tasksUri":       fmt.Sprintf("%s/%s/tasks", ecsServicesURI(cluster), service),
+	}
+	for _, deployment := range updated.Deployments {
+		if deployment.Status == "PRIMARY" {
+			data["deploymentId"] = deployment.ID
+		}
+	}
+
+	return h.createSuccessResponse("ECS deployment initiated successfully", data)
+}
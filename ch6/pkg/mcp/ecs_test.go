@@ -0,0 +1,149 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"aws-mcp-server/internal/config"
+	"aws-mcp-server/internal/logging"
+	"aws-mcp-server/pkg/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestECSServiceIssues(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	healthy := types.ECSService{
+		Name: "web", Status: "ACTIVE", DesiredCount: 2, RunningCount: 2,
+		Deployments: []types.ECSDeployment{{ID: "ecs-svc/1", Status: "PRIMARY", RolloutState: "COMPLETED", CreatedAt: now.Add(-24 * time.Hour)}},
+	}
+	assert.Empty(t, ecsServiceIssues(healthy, now))
+
+	// A fresh rollout is still starting tasks and is not stuck yet
+	starting := types.ECSService{
+		Name: "web", Status: "ACTIVE", DesiredCount: 2, RunningCount: 2,
+		Deployments: []types.ECSDeployment{{ID: "ecs-svc/2", Status: "PRIMARY", RolloutState: "IN_PROGRESS", CreatedAt: now.Add(-5 * time.Minute)}},
+	}
+	assert.Empty(t, ecsServiceIssues(starting, now))
+
+	stuck := types.ECSService{
+		Name: "api", Status: "ACTIVE", DesiredCount: 3, RunningCount: 1,
+		Deployments: []types.ECSDeployment{
+			{ID: "ecs-svc/3", Status: "PRIMARY", RolloutState: "IN_PROGRESS", FailedTasks: 4, CreatedAt: now.Add(-90 * time.Minute)},
+			{ID: "ecs-svc/2", Status: "ACTIVE", RolloutState: "COMPLETED", CreatedAt: now.Add(-48 * time.Hour)},
+		},
+	}
+	assert.Equal(t, []string{
+		"deployment ecs-svc/3 has been in progress for 1h30m0s",
+		"4 tasks of deployment ecs-svc/3 failed to start",
+		"1 of 3 desired tasks running",
+	}, ecsServiceIssues(stuck, now))
+
+	failed := types.ECSService{
+		Name: "worker", Status: "ACTIVE", DesiredCount: 1, RunningCount: 1,
+		Deployments: []types.ECSDeployment{{ID: "ecs-svc/4", Status: "PRIMARY", RolloutState: "FAILED",
+			RolloutStateReason: "ECS deployment circuit breaker: tasks failed to start.", CreatedAt: now.Add(-time.Hour)}},
+	}
+	assert.Equal(t, []string{"deployment ecs-svc/4 failed: ECS deployment circuit breaker: tasks failed to start."},
+		ecsServiceIssues(failed, now))
+}
+
+func TestFormatECSServices(t *testing.T) {
+	h := NewResourceHandler(&config.Config{}, nil)
+	now := time.Now()
+
+	services := []types.ECSService{
+		{Name: "web", Cluster: "prod", Status: "ACTIVE", DesiredCount: 2, RunningCount: 2, TaskDefinition: "web:7"},
+		{
+			Name: "api", Cluster: "prod", Status: "ACTIVE", DesiredCount: 3, RunningCount: 1, TaskDefinition: "api:12",
+			Events: []types.ECSServiceEvent{{Time: now, Message: "(service api) is unable to consistently start tasks successfully."}},
+		},
+	}
+
+	formatted := h.formatECSServices("prod", services, now)
+
+	assert.Equal(t, 2, formatted["total_services"])
+	assert.Equal(t, 1, formatted["unhealthy_services"])
+	assert.Equal(t, []string{"api"}, formatted["unhealthy"])
+
+	items := formatted["services"].([]map[string]interface{})
+	require.Len(t, items, 2)
+	assert.Equal(t, "api", items[0]["name"], "services with issues come first")
+	assert.Equal(t, []string{"1 of 3 desired tasks running"}, items[0]["issues"])
+	assert.Equal(t, "aws://ecs/clusters/prod/services/api/tasks", items[0]["tasks_uri"])
+	assert.Len(t, items[0]["recent_events"], 1)
+	assert.NotContains(t, items[1], "issues")
+}
+
+func TestFormatECSTasks(t *testing.T) {
+	h := NewResourceHandler(&config.Config{}, nil)
+	started := time.Now().Add(-time.Hour)
+	stopped := time.Now().Add(-10 * time.Minute)
+	exitCode := int32(137)
+
+	tasks := []types.ECSTask{
+		{ID: "a1", LastStatus: "RUNNING", DesiredStatus: "RUNNING", HealthStatus: "HEALTHY", StartedAt: &started},
+		{
+			ID: "b2", LastStatus: "STOPPED", DesiredStatus: "STOPPED", StartedAt: &started, StoppedAt: &stopped,
+			StopCode: "EssentialContainerExited", StoppedReason: "Essential container in task exited",
+			Containers: []types.ECSContainer{{Name: "app", LastStatus: "STOPPED", ExitCode: &exitCode, Reason: "OutOfMemoryError: Container killed due to memory usage"}},
+		},
+	}
+
+	formatted := h.formatECSTasks("prod", "api", tasks)
+
+	assert.Equal(t, 2, formatted["total_tasks"])
+	assert.Equal(t, map[string]int{"RUNNING": 1, "STOPPED": 1}, formatted["summary_by_status"])
+	assert.Equal(t, map[string]int{"Essential container in task exited": 1}, formatted["stopped_reasons"])
+
+	items := formatted["tasks"].([]map[string]interface{})
+	require.Len(t, items, 2)
+	assert.NotContains(t, items[0], "stopped")
+	containers := items[1]["containers"].([]map[string]interface{})
+	require.Len(t, containers, 1)
+	assert.Equal(t, int32(137), containers[0]["exit_code"])
+}
+
+func TestECSPathFromURI(t *testing.T) {
+	cluster, service, err := ecsPathFromURI("aws://ecs/clusters/prod/services")
+	require.NoError(t, err)
+	assert.Equal(t, "prod", cluster)
+	assert.Empty(t, service)
+
+	cluster, service, err = ecsPathFromURI("aws://ecs/clusters/prod/services/api/tasks")
+	require.NoError(t, err)
+	assert.Equal(t, "prod", cluster)
+	assert.Equal(t, "api", service)
+
+	for _, uri := range []string{
+		"aws://ecs/clusters/prod",
+		"aws://ecs/clusters//services",
+		"aws://ecs/clusters/prod/services/api",
+		"aws://ecs/clusters/prod/services//tasks",
+	} {
+		_, _, err = ecsPathFromURI(uri)
+		assert.Error(t, err, uri)
+	}
+}
+
+func TestECSToolsValidateArguments(t *testing.T) {
+	h := NewToolHandler(&config.Config{}, nil, logging.NewLogger("error", "text"))
+	ctx := context.Background()
+
+	result, err := h.CallTool(ctx, "force-ecs-deployment", map[string]interface{}{"cluster": "prod"})
+	require.NoError(t, err)
+	assert.Equal(t, "service is required", decodeToolResult(t, result)["error"])
+
+	result, err = h.CallTool(ctx, "update-ecs-service", map[string]interface{}{"cluster": "prod", "service": "api"})
+	require.NoError(t, err)
+	assert.Equal(t, "desiredCount is required", decodeToolResult(t, result)["error"])
+
+	for _, count := range []float64{-1, 2.5, 10000} {
+		result, err = h.CallTool(ctx, "update-ecs-service", map[string]interface{}{"cluster": "prod", "service": "api", "desiredCount": count})
+		require.NoError(t, err)
+		assert.Contains(t, decodeToolResult(t, result)["error"], "desiredCount must be a whole number", count)
+	}
+}
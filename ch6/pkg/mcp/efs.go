@@ -0,0 +1,259 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"aws-mcp-server/pkg/types"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const (
+	// efsFileSystemsURI lists the EFS file systems of the region
+	efsFileSystemsURI = "aws://efs/file-systems"
+	// efsFileSystemTemplate is the URI template of one file system by its ID
+	efsFileSystemTemplate = "aws://efs/file-systems/{fileSystemId}"
+	// efsBurstCreditWarning is the burst credit balance in bytes below which
+	// a bursting file system is about to drop to its baseline throughput
+	efsBurstCreditWarning = 100 << 30
+	// efsIOLimitWarning is the PercentIOLimit from which a General Purpose
+	// file system is close to its I/O limit
+	efsIOLimitWarning = 90
+)
+
+// efsThroughputModes are the throughput modes a file system can switch to
+var efsThroughputModes = map[string]bool{"bursting": true, "provisioned": true, "elastic": true}
+
+// readEFSFileSystems lists the file systems, the constrained ones first, with
+// counts by throughput mode
+func (h *ResourceHandler) readEFSFileSystems(ctx context.Context) (*mcp.ReadResourceResult, error) {
+	fileSystems, err := h.awsClient.ListFileSystems(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	jsonData, err := json.MarshalIndent(formatFileSystems(fileSystems), "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal EFS file systems data: %w", err)
+	}
+
+	return &mcp.ReadResourceResult{
+		Contents: []mcp.ResourceContents{
+			&mcp.TextResourceContents{
+				URI:      efsFileSystemsURI,
+				MIMEType: "application/json",
+				Text:     string(jsonData),
+			},
+		},
+	}, nil
+}
+
+// readEFSFileSystem returns the file system in the URI with its mount targets
+func (h *ResourceHandler) readEFSFileSystem(ctx context.Context, req resourceRequest) (*mcp.ReadResourceResult, error) {
+	uri, id := req.URI, req.Param("fileSystemId")
+	if id == "" {
+		return nil, fmt.Errorf("invalid EFS file system URI %s, use %s", uri, efsFileSystemTemplate)
+	}
+
+	fileSystem, err := h.awsClient.GetFileSystem(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	jsonData, err := json.MarshalIndent(formatFileSystem(*fileSystem), "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal EFS file system data: %w", err)
+	}
+
+	return &mcp.ReadResourceResult{
+		Contents: []mcp.ResourceContents{
+			&mcp.TextResourceContents{
+				URI:      uri,
+				MIMEType: "application/json",
+				Text:     string(jsonData),
+			},
+		},
+	}, nil
+}
+
+// formatFileSystems orders the file systems constrained ones first, then by
+// name, and counts them by throughput mode
+func formatFileSystems(fileSystems []types.FileSystem) map[string]interface{} {
+	items := make([]map[string]interface{}, 0, len(fileSystems))
+	byMode := make(map[string]int)
+	constrained := []string{}
+	for _, fileSystem := range fileSystems {
+		item := formatFileSystem(fileSystem)
+		if _, ok := item["constraints"]; ok {
+			constrained = append(constrained, fileSystemName(fileSystem))
+		}
+		byMode[fileSystem.ThroughputMode]++
+		items = append(items, item)
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		_, iConstrained := items[i]["constraints"]
+		_, jConstrained := items[j]["constraints"]
+		if iConstrained != jConstrained {
+			return iConstrained
+		}
+		return items[i]["name"].(string) < items[j]["name"].(string)
+	})
+	sort.Strings(constrained)
+
+	return map[string]interface{}{
+		"file_systems":      items,
+		"total":             len(fileSystems),
+		"summary_by_mode":   byMode,
+		"constrained":       constrained,
+		"constrained_count": len(constrained),
+	}
+}
+
+// formatFileSystem formats one file system with what constrains its
+// throughput, if anything
+func formatFileSystem(fileSystem types.FileSystem) map[string]interface{} {
+	item := map[string]interface{}{
+		"id":               fileSystem.ID,
+		"name":             fileSystemName(fileSystem),
+		"state":            fileSystem.State,
+		"performance_mode": fileSystem.PerformanceMode,
+		"throughput_mode":  fileSystem.ThroughputMode,
+		"size_gib":         float64(fileSystem.SizeBytes) / (1 << 30),
+		"encrypted":        fileSystem.Encrypted,
+		"created":          fileSystem.Created,
+		"mount_targets":    fileSystem.MountTargets,
+	}
+	if fileSystem.ThroughputMode == "provisioned" {
+		item["provisioned_mibps"] = fileSystem.ProvisionedMiBps
+	}
+	if metrics := fileSystem.Metrics; metrics != nil {
+		item["burst_credit_gib"] = metrics.BurstCreditBytes / (1 << 30)
+		item["permitted_throughput_mibps"] = metrics.PermittedBytesPerSecond / (1 << 20)
+		item["percent_io_limit"] = metrics.PercentIOLimit
+	}
+	if constraints := fileSystemConstraints(fileSystem); len(constraints) > 0 {
+		item["constraints"] = constraints
+	}
+	return item
+}
+
+// fileSystemConstraints explains what limits a file system's performance:
+// burst credits running out in bursting mode or I/O near the General Purpose
+// limit
+func fileSystemConstraints(fileSystem types.FileSystem) []string {
+	metrics := fileSystem.Metrics
+	if metrics == nil {
+		return nil
+	}
+
+	var constraints []string
+	if fileSystem.ThroughputMode == "bursting" && metrics.BurstCreditBytes < efsBurstCreditWarning {
+		constraints = append(constraints, fmt.Sprintf("burst credits low at %.1f GiB, throughput drops to the baseline of %.1f MiB/s when they run out",
+			metrics.BurstCreditBytes/(1<<30), metrics.PermittedBytesPerSecond/(1<<20)))
+	}
+	if metrics.PercentIOLimit >= efsIOLimitWarning {
+		constraints = append(constraints, fmt.Sprintf("I/O at %.0f%% of the General Purpose limit", metrics.PercentIOLimit))
+	}
+	return constraints
+}
+
+// fileSystemName is the name of a file system, its ID when it has none
+func fileSystemName(fileSystem types.FileSystem) string {
+	if fileSystem.Name != "" {
+		return fileSystem.Name
+	}
+	return fileSystem.ID
+}
+
+// updateEFSThroughput switches a file system's throughput mode, e.g. to
+// provisioned or elastic when it runs out of burst credits during an
+// incident. The plan is returned for confirmation first.
+func (h *ToolHandler) updateEFSThroughput(ctx context.Context, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	id, _ := arguments["fileSystemId"].(string)
+	mode, _ := arguments["throughputMode"].(string)
+	if id == "" || mode == "" {
+		return h.createErrorResponse("fileSystemId and throughputMode are required")
+	}
+	mibps, _ := arguments["provisionedThroughputMibps"].(float64)
+
+	fileSystem, err := h.awsClient.GetFileSystem(ctx, id)
+	if err != nil {
+		return h.createErrorResponse(fmt.Sprintf("failed to get file system: %v", err))
+	}
+	if err := validateThroughputChange(*fileSystem, mode, mibps); err != nil {
+		return h.createErrorResponse(err.Error())
+	}
+
+	if !isConfirmed(arguments) {
+		plan := formatFileSystem(*fileSystem)
+		plan["target_throughput_mode"] = mode
+		if mode == "provisioned" {
+			plan["target_provisioned_mibps"] = mibps
+		}
+		return h.createConfirmationResponse("update-efs-throughput", plan, throughputChangeWarnings(*fileSystem, mode, mibps))
+	}
+
+	updated, err := h.awsClient.UpdateThroughputMode(ctx, id, mode, mibps)
+	if err != nil {
+		return h.createErrorResponse(err.Error())
+	}
+
+	data := map[string]interface{}{
+		"fileSystemId":   id,
+		"name":           fileSystemName(*fileSystem),
+		"previousMode":   fileSystem.ThroughputMode,
+		"throughputMode": updated.ThroughputMode,
+		"state":          updated.State,
+		"note":           fmt.Sprintf("Read %s/%s to follow the burst credits and permitted throughput", efsFileSystemsURI, id),
+	}
+	if updated.ThroughputMode == "provisioned" {
+		data["provisionedThroughputMibps"] = updated.ProvisionedMiBps
+	}
+	return h.createSuccessResponse("EFS throughput mode updated successfully", data)
+}
+
+// validateThroughputChange checks that a file system can switch to the mode
+// and, in provisioned mode, the throughput
+func validateThroughputChange(fileSystem types.FileSystem, mode string, mibps float64) error {
+	if !efsThroughputModes[mode] {
+		return fmt.Errorf("throughputMode must be bursting, provisioned or elastic")
+	}
+	if mode == "provisioned" && mibps <= 0 {
+		return fmt.Errorf("provisionedThroughputMibps is required for provisioned mode")
+	}
+	if mode != "provisioned" && mibps != 0 {
+		return fmt.Errorf("provisionedThroughputMibps only applies to provisioned mode")
+	}
+	if mode == "elastic" && fileSystem.PerformanceMode == "maxIO" {
+		return fmt.Errorf("elastic throughput requires the General Purpose performance mode, %s uses Max I/O", fileSystemName(fileSystem))
+	}
+	if fileSystem.State != "available" {
+		return fmt.Errorf("file system %s is %s, not available", fileSystemName(fileSystem), fileSystem.State)
+	}
+	if mode == fileSystem.ThroughputMode && (mode != "provisioned" || mibps == fileSystem.ProvisionedMiBps) {
+		return fmt.Errorf("file system %s already uses %s throughput", fileSystemName(fileSystem), mode)
+	}
+	return nil
+}
+
+// throughputChangeWarnings lists what to know before changing a file
+// system's throughput mode
+func throughputChangeWarnings(fileSystem types.FileSystem, mode string, mibps float64) []string {
+	var warnings []string
+	if fileSystem.ThroughputMode == "provisioned" && (mode != "provisioned" || mibps < fileSystem.ProvisionedMiBps) {
+		warnings = append(warnings, "Leaving provisioned mode or lowering provisioned throughput is allowed once every 24 hours; "+
+			"the file system cannot be switched back or lowered again until then")
+	}
+	switch mode {
+	case "provisioned":
+		warnings = append(warnings, "Provisioned throughput above what the storage size allows in bursting mode is billed per MiB/s-month")
+	case "elastic":
+		warnings = append(warnings, "Elastic throughput is billed per GiB read and written, which costs more than bursting under sustained load")
+	case "bursting":
+		warnings = append(warnings, "In bursting mode throughput scales with the storage size and drops to the baseline once burst credits run out")
+	}
+	return warnings
+}
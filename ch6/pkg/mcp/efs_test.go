@@ -0,0 +1,81 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	"aws-mcp-server/internal/config"
+	"aws-mcp-server/internal/logging"
+	"aws-mcp-server/pkg/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatFileSystems(t *testing.T) {
+	data := formatFileSystems([]types.FileSystem{
+		{ID: "fs-1", Name: "shared", State: "available", ThroughputMode: "bursting", SizeBytes: 10 << 30,
+			Metrics: &types.FileSystemMetrics{BurstCreditBytes: 2 << 40, PermittedBytesPerSecond: 100 << 20}},
+		{ID: "fs-2", State: "available", ThroughputMode: "bursting",
+			Metrics: &types.FileSystemMetrics{BurstCreditBytes: 5 << 30, PermittedBytesPerSecond: 1 << 20}},
+		{ID: "fs-3", Name: "builds", State: "available", ThroughputMode: "provisioned", ProvisionedMiBps: 256,
+			Metrics: &types.FileSystemMetrics{PercentIOLimit: 95}},
+		{ID: "fs-4", Name: "archive", State: "available", ThroughputMode: "bursting"},
+	})
+
+	assert.Equal(t, 4, data["total"])
+	assert.Equal(t, map[string]int{"bursting": 3, "provisioned": 1}, data["summary_by_mode"])
+	assert.Equal(t, []string{"builds", "fs-2"}, data["constrained"])
+
+	fileSystems := data["file_systems"].([]map[string]interface{})
+	require.Len(t, fileSystems, 4)
+	assert.Equal(t, "builds", fileSystems[0]["name"], "constrained file systems come first")
+	assert.Equal(t, float64(256), fileSystems[0]["provisioned_mibps"])
+	assert.Equal(t, []string{"I/O at 95% of the General Purpose limit"}, fileSystems[0]["constraints"])
+	assert.Equal(t, "fs-2", fileSystems[1]["name"], "file systems without a name go by their ID")
+	assert.Contains(t, fileSystems[1]["constraints"].([]string)[0], "burst credits low at 5.0 GiB")
+	assert.Equal(t, "archive", fileSystems[2]["name"])
+	assert.NotContains(t, fileSystems[2], "burst_credit_gib", "file systems without metrics have none")
+	assert.Equal(t, "shared", fileSystems[3]["name"])
+	assert.NotContains(t, fileSystems[3], "provisioned_mibps")
+}
+
+func TestValidateThroughputChange(t *testing.T) {
+	fileSystem := types.FileSystem{ID: "fs-1", State: "available", PerformanceMode: "generalPurpose", ThroughputMode: "bursting"}
+
+	assert.NoError(t, validateThroughputChange(fileSystem, "provisioned", 128))
+	assert.NoError(t, validateThroughputChange(fileSystem, "elastic", 0))
+	assert.ErrorContains(t, validateThroughputChange(fileSystem, "fast", 0), "must be bursting, provisioned or elastic")
+	assert.ErrorContains(t, validateThroughputChange(fileSystem, "provisioned", 0), "required for provisioned mode")
+	assert.ErrorContains(t, validateThroughputChange(fileSystem, "elastic", 128), "only applies to provisioned mode")
+	assert.ErrorContains(t, validateThroughputChange(fileSystem, "bursting", 0), "already uses bursting")
+
+	provisioned := fileSystem
+	provisioned.ThroughputMode, provisioned.ProvisionedMiBps = "provisioned", 128
+	assert.NoError(t, validateThroughputChange(provisioned, "provisioned", 256), "provisioned throughput can be changed")
+	assert.ErrorContains(t, validateThroughputChange(provisioned, "provisioned", 128), "already uses provisioned")
+
+	maxIO := fileSystem
+	maxIO.PerformanceMode = "maxIO"
+	assert.ErrorContains(t, validateThroughputChange(maxIO, "elastic", 0), "requires the General Purpose performance mode")
+
+	creating := fileSystem
+	creating.State = "creating"
+	assert.ErrorContains(t, validateThroughputChange(creating, "elastic", 0), "is creating")
+}
+
+func TestThroughputChangeWarnings(t *testing.T) {
+	provisioned := types.FileSystem{ID: "fs-1", ThroughputMode: "provisioned", ProvisionedMiBps: 256}
+
+	assert.Contains(t, throughputChangeWarnings(provisioned, "elastic", 0)[0], "once every 24 hours")
+	assert.Contains(t, throughputChangeWarnings(provisioned, "provisioned", 128)[0], "once every 24 hours")
+	assert.Len(t, throughputChangeWarnings(provisioned, "provisioned", 512), 1, "raising provisioned throughput is not restricted")
+}
+
+func TestUpdateEFSThroughputRequiresArguments(t *testing.T) {
+	handler := NewToolHandler(&config.Config{}, nil, logging.NewLogger("error", "text"))
+
+	result, err := handler.updateEFSThroughput(context.Background(), map[string]interface{}{"fileSystemId": "fs-1"})
+	require.NoError(t, err)
+	assert.Equal(t, "fileSystemId and throughputMode are required", decodeToolResult(t, result)["error"])
+}
@@ -0,0 +1,414 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"aws-mcp-server/pkg/types"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const (
+	// cacheClustersURI lists the ElastiCache clusters with node status
+	cacheClustersURI = "aws://elasticache/clusters"
+	// cacheClusterTemplate is the URI template of one cluster with its nodes and events
+	cacheClusterTemplate = "aws://elasticache/clusters/{clusterId}"
+	// cacheEventsWindow is how far back the events of a cluster are listed
+	cacheEventsWindow = 24 * time.Hour
+	// cacheMaintenanceSoon is how close a maintenance window must be for its
+	// pending changes to be flagged
+	cacheMaintenanceSoon = 24 * time.Hour
+	// cacheUpdateDueSoon is how close the apply-by date of an important
+	// service update must be for it to be flagged
+	cacheUpdateDueSoon = 7 * 24 * time.Hour
+)
+
+// maintenanceDays maps the day names of maintenance windows to weekdays
+var maintenanceDays = map[string]time.Weekday{
+	"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday,
+	"thu": time.Thursday, "fri": time.Friday, "sat": time.Saturday,
+}
+
+// readCacheClusters returns all ElastiCache clusters, those needing attention first
+func (h *ResourceHandler) readCacheClusters(ctx context.Context) (*mcp.ReadResourceResult, error) {
+	clusters, err := h.awsClient.ListCacheClusters(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list ElastiCache clusters: %w", err)
+	}
+
+	jsonData, err := json.MarshalIndent(h.formatCacheClusters(clusters, time.Now()), "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal ElastiCache clusters data: %w", err)
+	}
+
+	return &mcp.ReadResourceResult{
+		Contents: []mcp.ResourceContents{
+			&mcp.TextResourceContents{
+				URI:      cacheClustersURI,
+				MIMEType: "application/json",
+				Text:     string(jsonData),
+			},
+		},
+	}, nil
+}
+
+// readCacheCluster returns one cluster with its nodes, pending maintenance and
+// the events of the last day
+func (h *ResourceHandler) readCacheCluster(ctx context.Context, req resourceRequest) (*mcp.ReadResourceResult, error) {
+	uri, clusterID := req.URI, req.Param("clusterId")
+	if clusterID == "" {
+		return nil, fmt.Errorf("invalid ElastiCache cluster URI %s, use %s", uri, cacheClusterTemplate)
+	}
+
+	cluster, err := h.awsClient.GetCacheCluster(ctx, clusterID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ElastiCache cluster: %w", err)
+	}
+
+	now := time.Now()
+	data := h.formatCacheCluster(*cluster, now)
+
+	nodes := make([]map[string]interface{}, 0, len(cluster.Nodes))
+	for _, node := range cluster.Nodes {
+		item := map[string]interface{}{
+			"id":                     node.ID,
+			"status":                 node.Status,
+			"availability_zone":      node.AvailabilityZone,
+			"parameter_group_status": node.ParameterGroupStatus,
+			"created":                h.times.Format(node.CreatedAt),
+		}
+		if node.Endpoint != "" {
+			item["endpoint"] = node.Endpoint
+		}
+		nodes = append(nodes, item)
+	}
+	data["nodes"] = nodes
+	data["snapshot_retention_days"] = cluster.SnapshotRetention
+	if cluster.SnapshotWindow != "" {
+		data["snapshot_window"] = cluster.SnapshotWindow
+	}
+	data["auto_minor_version_upgrade"] = cluster.AutoMinorVersionUpgrade
+	data["transit_encryption"] = cluster.TransitEncryption
+	data["at_rest_encryption"] = cluster.AtRestEncryption
+
+	if len(cluster.PendingUpdates) > 0 {
+		updates := make([]map[string]interface{}, 0, len(cluster.PendingUpdates))
+		for _, update := range cluster.PendingUpdates {
+			item := map[string]interface{}{
+				"name":     update.Name,
+				"type":     update.Type,
+				"severity": update.Severity,
+				"status":   update.Status,
+			}
+			if !update.ApplyBy.IsZero() {
+				item["apply_by"] = h.times.Format(update.ApplyBy)
+			}
+			updates = append(updates, item)
+		}
+		data["pending_updates"] = updates
+	}
+
+	events, err := h.awsClient.ListCacheEvents(ctx, clusterID, now.Add(-cacheEventsWindow))
+	if err != nil {
+		data["events_unavailable"] = err.Error()
+	} else {
+		formatted := make([]map[string]interface{}, 0, len(events))
+		for _, event := range events {
+			formatted = append(formatted, map[string]interface{}{
+				"time":    h.times.Format(event.Time),
+				"message": event.Message,
+			})
+		}
+		data["recent_events"] = formatted
+	}
+
+	jsonData, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal ElastiCache cluster data: %w", err)
+	}
+
+	return &mcp.ReadResourceResult{
+		Contents: []mcp.ResourceContents{
+			&mcp.TextResourceContents{
+				URI:      uri,
+				MIMEType: "application/json",
+				Text:     string(jsonData),
+			},
+		},
+	}, nil
+}
+
+// formatCacheClusters formats clusters for AI processing with counts by engine
+func (h *ResourceHandler) formatCacheClusters(clusters []types.CacheCluster, now time.Time) map[string]interface{} {
+	formatted := make([]map[string]interface{}, 0, len(clusters))
+	engineCount := make(map[string]int)
+	totalNodes := 0
+	var attention []string
+	for _, cluster := range clusters {
+		item := h.formatCacheCluster(cluster, now)
+		formatted = append(formatted, item)
+		engineCount[cluster.Engine]++
+		totalNodes += len(cluster.Nodes)
+		if _, ok := item["issues"]; ok {
+			attention = append(attention, cluster.ID)
+		}
+	}
+
+	sort.SliceStable(formatted, func(i, j int) bool {
+		_, iIssues := formatted[i]["issues"]
+		_, jIssues := formatted[j]["issues"]
+		return iIssues && !jIssues
+	})
+
+	return map[string]interface{}{
+		"total_clusters":    len(clusters),
+		"total_nodes":       totalNodes,
+		"summary_by_engine": engineCount,
+		"needs_attention":   attention,
+		"clusters":          formatted,
+	}
+}
+
+// formatCacheCluster formats one cluster with its node counts, next
+// maintenance window and issues
+func (h *ResourceHandler) formatCacheCluster(cluster types.CacheCluster, now time.Time) map[string]interface{} {
+	available := 0
+	for _, node := range cluster.Nodes {
+		if node.Status == "available" {
+			available++
+		}
+	}
+
+	item := map[string]interface{}{
+		"id":              cluster.ID,
+		"engine":          cluster.Engine,
+		"engine_version":  cluster.EngineVersion,
+		"node_type":       cluster.NodeType,
+		"status":          cluster.Status,
+		"nodes":           len(cluster.Nodes),
+		"nodes_available": available,
+		"uri":             cacheClustersURI + "/" + cluster.ID,
+	}
+	if cluster.ReplicationGroupID != "" {
+		item["replication_group"] = cluster.ReplicationGroupID
+		item["cluster_mode"] = cluster.ClusterModeEnabled
+	}
+	if cluster.MaintenanceWindow != "" {
+		item["maintenance_window"] = cluster.MaintenanceWindow + " UTC"
+		if start, _, ok := nextMaintenanceWindow(cluster.MaintenanceWindow, now); ok {
+			item["next_maintenance"] = h.times.Format(start)
+		}
+	}
+	if len(cluster.PendingChanges) > 0 {
+		item["pending_changes"] = cluster.PendingChanges
+	}
+	if len(cluster.PendingUpdates) > 0 {
+		item["pending_updates_count"] = len(cluster.PendingUpdates)
+	}
+	if issues := cacheClusterIssues(cluster, now); len(issues) > 0 {
+		item["issues"] = issues
+	}
+	return item
+}
+
+// cacheClusterIssues describes what keeps a cluster from serving normally or
+// what is about to change under it
+func cacheClusterIssues(cluster types.CacheCluster, now time.Time) []string {
+	var issues []string
+	if cluster.Status != "available" {
+		issues = append(issues, fmt.Sprintf("cluster is %s", cluster.Status))
+	}
+
+	var pendingReboot []string
+	for _, node := range cluster.Nodes {
+		if node.Status != "available" {
+			issues = append(issues, fmt.Sprintf("node %s is %s", node.ID, node.Status))
+		}
+		if node.ParameterGroupStatus == "pending-reboot" {
+			pendingReboot = append(pendingReboot, node.ID)
+		}
+	}
+	if len(pendingReboot) > 0 {
+		issues = append(issues, fmt.Sprintf("parameter changes take effect only after node %s %s rebooted",
+			strings.Join(pendingReboot, ", "), map[bool]string{true: "is", false: "are"}[len(pendingReboot) == 1]))
+	}
+
+	for _, update := range cluster.PendingUpdates {
+		if update.Severity != "critical" && update.Severity != "important" || update.ApplyBy.IsZero() {
+			continue
+		}
+		switch {
+		case update.ApplyBy.Before(now):
+			issues = append(issues, fmt.Sprintf("%s service update %s was due by %s", update.Severity, update.Name, update.ApplyBy.UTC().Format("2006-01-02")))
+		case update.ApplyBy.Before(now.Add(cacheUpdateDueSoon)):
+			issues = append(issues, fmt.Sprintf("%s service update %s is due by %s", update.Severity, update.Name, update.ApplyBy.UTC().Format("2006-01-02")))
+		}
+	}
+
+	if len(cluster.PendingChanges) > 0 {
+		if start, end, ok := nextMaintenanceWindow(cluster.MaintenanceWindow, now); ok && start.Before(now.Add(cacheMaintenanceSoon)) {
+			changes := make([]string, 0, len(cluster.PendingChanges))
+			for name := range cluster.PendingChanges {
+				changes = append(changes, name)
+			}
+			sort.Strings(changes)
+			when := fmt.Sprintf("starting %s UTC", start.Format("Mon 15:04"))
+			if !start.After(now) {
+				when = fmt.Sprintf("open now until %s UTC", end.Format("15:04"))
+			}
+			issues = append(issues, fmt.Sprintf("pending changes to %s are applied in the maintenance window %s", strings.Join(changes, ", "), when))
+		}
+	}
+	return issues
+}
+
+// nextMaintenanceWindow returns the start and end of the maintenance window
+// in progress or next to come. Windows are weekly ranges in UTC such as
+// sun:23:00-mon:01:30.
+func nextMaintenanceWindow(window string, now time.Time) (time.Time, time.Time, bool) {
+	from, to, ok := strings.Cut(strings.ToLower(window), "-")
+	if !ok {
+		return time.Time{}, time.Time{}, false
+	}
+	startDay, startMinute, ok := parseWeeklyTime(from)
+	if !ok {
+		return time.Time{}, time.Time{}, false
+	}
+	endDay, endMinute, ok := parseWeeklyTime(to)
+	if !ok {
+		return time.Time{}, time.Time{}, false
+	}
+
+	const week = 7 * 24 * 60
+	length := (int(endDay)-int(startDay))*24*60 + endMinute - startMinute
+	length = ((length % week) + week) % week
+	if length == 0 {
+		length = week
+	}
+	duration := time.Duration(length) * time.Minute
+
+	now = now.UTC()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	days := (int(startDay) - int(now.Weekday()) + 7) % 7
+	start := today.AddDate(0, 0, days).Add(time.Duration(startMinute) * time.Minute)
+
+	// The window may have started last week or earlier today and still be open
+	if previous := start.AddDate(0, 0, -7); previous.Add(duration).After(now) {
+		start = previous
+	}
+	if !start.Add(duration).After(now) {
+		start = start.AddDate(0, 0, 7)
+	}
+	return start, start.Add(duration), true
+}
+
+// parseWeeklyTime parses ddd:hh:mm into a weekday and minutes past midnight
+func parseWeeklyTime(value string) (time.Weekday, int, bool) {
+	parts := strings.Split(value, ":")
+	if len(parts) != 3 {
+		return 0, 0, false
+	}
+	day, ok := maintenanceDays[parts[0]]
+	if !ok {
+		return 0, 0, false
+	}
+	clock, err := time.Parse("15:04", parts[1]+":"+parts[2])
+	if err != nil {
+		return 0, 0, false
+	}
+	return day, clock.Hour()*60 + clock.Minute(), true
+}
+
+// rebootCacheNode reboots nodes of an ElastiCache cluster, all of them by
+// default, e.g. to apply parameter changes or recover a hung node
+func (h *ToolHandler) rebootCacheNode(ctx context.Context, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	clusterID, _ := arguments["clusterId"].(string)
+	if clusterID == "" {
+		return h.createErrorResponse("clusterId is required")
+	}
+	nodeIDs := stringList(arguments["nodeIds"])
+
+	cluster, err := h.awsClient.GetCacheCluster(ctx, clusterID)
+	if err != nil {
+		return h.createErrorResponse(fmt.Sprintf("failed to get ElastiCache cluster: %v", err))
+	}
+
+	nodeIDs, problem := cacheRebootNodes(*cluster, nodeIDs)
+	if problem != "" {
+		return h.createErrorResponse(problem)
+	}
+
+	if !isConfirmed(arguments) {
+		plan := map[string]interface{}{
+			"clusterId": cluster.ID,
+			"engine":    cluster.Engine,
+			"nodeIds":   nodeIDs,
+		}
+		if cluster.ReplicationGroupID != "" {
+			plan["replicationGroup"] = cluster.ReplicationGroupID
+		}
+		return h.createConfirmationResponse("reboot-cache-node", plan, cacheRebootWarnings(*cluster, nodeIDs))
+	}
+
+	if err := h.awsClient.RebootCacheNodes(ctx, cluster.ID, nodeIDs); err != nil {
+		return h.createErrorResponse(fmt.Sprintf("failed to reboot ElastiCache nodes: %v", err))
+	}
+
+	return h.createSuccessResponse("ElastiCache node reboot initiated successfully", map[string]interface{}{
+		"clusterId": cluster.ID,
+		"nodeIds":   nodeIDs,
+		"action":    "reboot",
+		"note":      fmt.Sprintf("Read %s/%s until the nodes are available again", cacheClustersURI, cluster.ID),
+	})
+}
+
+// cacheRebootNodes checks that the nodes of a cluster can be rebooted and
+// returns them, all nodes when none are named, or why they cannot
+func cacheRebootNodes(cluster types.CacheCluster, nodeIDs []string) ([]string, string) {
+	if cluster.ClusterModeEnabled {
+		return nil, fmt.Sprintf("%s belongs to replication group %s with cluster mode enabled, whose nodes ElastiCache cannot reboot",
+			cluster.ID, cluster.ReplicationGroupID)
+	}
+	if cluster.Status != "available" {
+		return nil, fmt.Sprintf("cluster %s is %s, nodes can only be rebooted while it is available", cluster.ID, cluster.Status)
+	}
+
+	known := make(map[string]bool, len(cluster.Nodes))
+	var all []string
+	for _, node := range cluster.Nodes {
+		known[node.ID] = true
+		all = append(all, node.ID)
+	}
+	if len(nodeIDs) == 0 {
+		return all, ""
+	}
+	for _, id := range nodeIDs {
+		if !known[id] {
+			return nil, fmt.Sprintf("cluster %s has no node %s (nodes: %s)", cluster.ID, id, strings.Join(all, ", "))
+		}
+	}
+	return nodeIDs, ""
+}
+
+// cacheRebootWarnings describes what clients lose while the nodes reboot
+func cacheRebootWarnings(cluster types.CacheCluster, nodeIDs []string) []string {
+	var warnings []string
+	switch {
+	case cluster.Engine == "memcached":
+		warnings = append(warnings, "Rebooting a Memcached node flushes all of its data; requests miss the cache until it is warmed up again")
+	case cluster.ReplicationGroupID == "":
+		warnings = append(warnings, fmt.Sprintf("%s is a standalone %s node without replicas; data not persisted to a backup is lost", cluster.ID, cluster.Engine))
+	default:
+		warnings = append(warnings, fmt.Sprintf("%s is a node of replication group %s; if it is the primary, writes fail until it is back or a replica is promoted",
+			cluster.ID, cluster.ReplicationGroupID))
+	}
+	if len(nodeIDs) > 1 && len(nodeIDs) == len(cluster.Nodes) {
+		warnings = append(warnings, fmt.Sprintf("All %d nodes reboot, so the cache is unavailable until they are back", len(nodeIDs)))
+	}
+	return warnings
+}
@@ -0,0 +1,131 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"aws-mcp-server/internal/config"
+	"aws-mcp-server/internal/logging"
+	"aws-mcp-server/pkg/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNextMaintenanceWindow(t *testing.T) {
+	// Wednesday 2025-06-04 10:00 UTC
+	now := time.Date(2025, 6, 4, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		window string
+		start  time.Time
+		end    time.Time
+	}{
+		{"thu:03:00-thu:04:00", time.Date(2025, 6, 5, 3, 0, 0, 0, time.UTC), time.Date(2025, 6, 5, 4, 0, 0, 0, time.UTC)},
+		{"wed:09:30-wed:10:30", time.Date(2025, 6, 4, 9, 30, 0, 0, time.UTC), time.Date(2025, 6, 4, 10, 30, 0, 0, time.UTC)},
+		{"wed:08:00-wed:09:00", time.Date(2025, 6, 11, 8, 0, 0, 0, time.UTC), time.Date(2025, 6, 11, 9, 0, 0, 0, time.UTC)},
+		{"tue:23:00-wed:11:00", time.Date(2025, 6, 3, 23, 0, 0, 0, time.UTC), time.Date(2025, 6, 4, 11, 0, 0, 0, time.UTC)},
+		{"sat:23:00-sun:01:30", time.Date(2025, 6, 7, 23, 0, 0, 0, time.UTC), time.Date(2025, 6, 8, 1, 30, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.window, func(t *testing.T) {
+			start, end, ok := nextMaintenanceWindow(tt.window, now)
+			require.True(t, ok)
+			assert.Equal(t, tt.start, start)
+			assert.Equal(t, tt.end, end)
+		})
+	}
+
+	_, _, ok := nextMaintenanceWindow("someday", now)
+	assert.False(t, ok)
+	_, _, ok = nextMaintenanceWindow("xyz:01:00-mon:02:00", now)
+	assert.False(t, ok)
+}
+
+func TestCacheClusterIssues(t *testing.T) {
+	now := time.Date(2025, 6, 4, 10, 0, 0, 0, time.UTC)
+	cluster := types.CacheCluster{
+		ID:     "sessions-001",
+		Status: "available",
+		Nodes: []types.CacheNode{
+			{ID: "0001", Status: "available", ParameterGroupStatus: "pending-reboot"},
+			{ID: "0002", Status: "rebooting cache cluster nodes", ParameterGroupStatus: "in-sync"},
+		},
+		MaintenanceWindow: "wed:22:00-wed:23:00",
+		PendingChanges:    map[string]string{"nodeType": "cache.r7g.large"},
+		PendingUpdates: []types.CacheServiceUpdate{
+			{Name: "elasticache-20250501-001", Severity: "critical", ApplyBy: now.Add(-24 * time.Hour)},
+			{Name: "elasticache-20250520-002", Severity: "important", ApplyBy: now.Add(72 * time.Hour)},
+			{Name: "elasticache-20250601-003", Severity: "low", ApplyBy: now.Add(24 * time.Hour)},
+		},
+	}
+
+	assert.Equal(t, []string{
+		"node 0002 is rebooting cache cluster nodes",
+		"parameter changes take effect only after node 0001 is rebooted",
+		"critical service update elasticache-20250501-001 was due by 2025-06-03",
+		"important service update elasticache-20250520-002 is due by 2025-06-07",
+		"pending changes to nodeType are applied in the maintenance window starting Wed 22:00 UTC",
+	}, cacheClusterIssues(cluster, now))
+
+	healthy := types.CacheCluster{ID: "cache", Status: "available", Nodes: []types.CacheNode{{ID: "0001", Status: "available"}}}
+	assert.Empty(t, cacheClusterIssues(healthy, now))
+}
+
+func TestFormatCacheClusters(t *testing.T) {
+	h := NewResourceHandler(&config.Config{}, nil)
+	now := time.Date(2025, 6, 4, 10, 0, 0, 0, time.UTC)
+	clusters := []types.CacheCluster{
+		{ID: "cache", Engine: "memcached", Status: "available", Nodes: []types.CacheNode{{ID: "0001", Status: "available"}}},
+		{ID: "sessions-001", Engine: "redis", Status: "modifying", ReplicationGroupID: "sessions", MaintenanceWindow: "sun:05:00-sun:06:00",
+			Nodes: []types.CacheNode{{ID: "0001", Status: "available"}}},
+	}
+
+	formatted := h.formatCacheClusters(clusters, now)
+	assert.Equal(t, 2, formatted["total_clusters"])
+	assert.Equal(t, 2, formatted["total_nodes"])
+	assert.Equal(t, []string{"sessions-001"}, formatted["needs_attention"])
+
+	items := formatted["clusters"].([]map[string]interface{})
+	require.Len(t, items, 2)
+	assert.Equal(t, "sessions-001", items[0]["id"])
+	assert.Equal(t, "sessions", items[0]["replication_group"])
+	assert.Equal(t, "sun:05:00-sun:06:00 UTC", items[0]["maintenance_window"])
+	assert.Contains(t, items[0], "next_maintenance")
+	assert.NotContains(t, items[1], "replication_group")
+}
+
+func TestCacheRebootNodes(t *testing.T) {
+	cluster := types.CacheCluster{
+		ID:     "cache",
+		Engine: "memcached",
+		Status: "available",
+		Nodes:  []types.CacheNode{{ID: "0001"}, {ID: "0002"}},
+	}
+
+	nodes, problem := cacheRebootNodes(cluster, nil)
+	assert.Empty(t, problem)
+	assert.Equal(t, []string{"0001", "0002"}, nodes)
+	warnings := cacheRebootWarnings(cluster, nodes)
+	assert.Contains(t, warnings[0], "flushes all of its data")
+	assert.Equal(t, "All 2 nodes reboot, so the cache is unavailable until they are back", warnings[1])
+
+	_, problem = cacheRebootNodes(cluster, []string{"0003"})
+	assert.Equal(t, "cluster cache has no node 0003 (nodes: 0001, 0002)", problem)
+
+	cluster.Status = "modifying"
+	_, problem = cacheRebootNodes(cluster, nil)
+	assert.Contains(t, problem, "only be rebooted while it is available")
+
+	sharded := types.CacheCluster{ID: "orders-0001-001", Status: "available", ReplicationGroupID: "orders", ClusterModeEnabled: true}
+	_, problem = cacheRebootNodes(sharded, nil)
+	assert.Contains(t, problem, "cluster mode enabled")
+}
+
+func TestRebootCacheNodeRequiresCluster(t *testing.T) {
+	h := NewToolHandler(&config.Config{}, nil, logging.NewLogger("error", "text"))
+	result, err := h.CallTool(context.Background(), "reboot-cache-node", map[string]interface{}{"confirm": true})
+	require.NoError(t, err)
+	assert.Equal(t, "clusterId is required", decodeToolResult(t, result)["error"])
+}
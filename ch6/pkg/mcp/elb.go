@@ -0,0 +1,234 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"aws-mcp-server/pkg/types"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const (
+	// loadBalancersURI lists the ELBv2 load balancers with the health of their targets
+	loadBalancersURI = "aws://elb/load-balancers"
+	// targetGroupsURI lists the target groups with per-target health
+	targetGroupsURI = "aws://elb/target-groups"
+)
+
+// unhealthyTargetStates are the target states that mean a target gets no traffic
+// because of a problem, as opposed to initial, draining or unused
+var unhealthyTargetStates = map[string]bool{
+	"unhealthy":   true,
+	"unavailable": true,
+}
+
+// readLoadBalancers returns all load balancers with their listeners and target health
+func (h *ResourceHandler) readLoadBalancers(ctx context.Context) (*mcp.ReadResourceResult, error) {
+	loadBalancers, err := h.awsClient.ListLoadBalancers(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list load balancers: %w", err)
+	}
+
+	groups, err := h.awsClient.ListTargetGroups(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list target groups: %w", err)
+	}
+
+	jsonData, err := json.MarshalIndent(formatLoadBalancers(loadBalancers, groups), "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal load balancers data: %w", err)
+	}
+
+	return &mcp.ReadResourceResult{
+		Contents: []mcp.ResourceContents{
+			&mcp.TextResourceContents{
+				URI:      loadBalancersURI,
+				MIMEType: "application/json",
+				Text:     string(jsonData),
+			},
+		},
+	}, nil
+}
+
+// readTargetGroups returns all target groups with the health of each target,
+// naming the EC2 instances behind instance targets
+func (h *ResourceHandler) readTargetGroups(ctx context.Context) (*mcp.ReadResourceResult, error) {
+	groups, err := h.awsClient.ListTargetGroups(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list target groups: %w", err)
+	}
+
+	instances, err := h.awsClient.ListEC2Instances(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list EC2 instances: %w", err)
+	}
+
+	jsonData, err := json.MarshalIndent(formatTargetGroups(groups, instances), "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal target groups data: %w", err)
+	}
+
+	return &mcp.ReadResourceResult{
+		Contents: []mcp.ResourceContents{
+			&mcp.TextResourceContents{
+				URI:      targetGroupsURI,
+				MIMEType: "application/json",
+				Text:     string(jsonData),
+			},
+		},
+	}, nil
+}
+
+// formatLoadBalancers formats load balancers for AI processing with the target
+// health of the groups behind each one
+func formatLoadBalancers(loadBalancers []types.LoadBalancer, groups []types.TargetGroup) map[string]interface{} {
+	groupsByLoadBalancer := make(map[string][]types.TargetGroup)
+	for _, group := range groups {
+		for _, arn := range group.LoadBalancerARNs {
+			groupsByLoadBalancer[arn] = append(groupsByLoadBalancer[arn], group)
+		}
+	}
+
+	formatted := make([]map[string]interface{}, 0, len(loadBalancers))
+	degraded := make([]string, 0)
+	for _, lb := range loadBalancers {
+		healthy, unhealthy, total := 0, 0, 0
+		groupNames := make([]string, 0)
+		for _, group := range groupsByLoadBalancer[lb.ARN] {
+			groupNames = append(groupNames, group.Name)
+			for _, target := range group.Targets {
+				total++
+				if target.State == "healthy" {
+					healthy++
+				} else if unhealthyTargetStates[target.State] {
+					unhealthy++
+				}
+			}
+		}
+
+		health := "healthy"
+		switch {
+		case total == 0:
+			health = "no targets"
+		case healthy == 0:
+			health = "no healthy targets"
+		case unhealthy > 0:
+			health = "degraded"
+		}
+		if health != "healthy" {
+			degraded = append(degraded, lb.Name)
+		}
+
+		formatted = append(formatted, map[string]interface{}{
+			"name":          lb.Name,
+			"type":          lb.Type,
+			"scheme":        lb.Scheme,
+			"state":         lb.State,
+			"dns_name":      lb.DNSName,
+			"listeners":     lb.Listeners,
+			"target_groups": groupNames,
+			"health":        health,
+			"targets": map[string]int{
+				"total":     total,
+				"healthy":   healthy,
+				"unhealthy": unhealthy,
+			},
+		})
+	}
+
+	return map[string]interface{}{
+		"total_load_balancers": len(loadBalancers),
+		"load_balancers":       formatted,
+		"needs_attention":      degraded,
+	}
+}
+
+// formatTargetGroups formats target groups with per-target health. Instance
+// targets carry the instance's name, state and resource URI, and unhealthy
+// targets are also listed together so they can be matched to instances.
+func formatTargetGroups(groups []types.TargetGroup, instances []types.CloudResource) map[string]interface{} {
+	instancesByID := make(map[string]types.CloudResource, len(instances))
+	for _, instance := range instances {
+		instancesByID[instance.ID] = instance
+	}
+
+	formatted := make([]map[string]interface{}, 0, len(groups))
+	stateCount := make(map[string]int)
+	unhealthy := make([]map[string]interface{}, 0)
+	totalTargets := 0
+
+	for _, group := range groups {
+		targets := make([]map[string]interface{}, 0, len(group.Targets))
+		for _, target := range group.Targets {
+			item := map[string]interface{}{
+				"id":    target.ID,
+				"state": target.State,
+			}
+			if target.Port != 0 {
+				item["port"] = target.Port
+			}
+			if target.Reason != "" {
+				item["reason"] = target.Reason
+			}
+			if target.Description != "" {
+				item["description"] = target.Description
+			}
+			if instance, ok := instancesByID[target.ID]; ok {
+				item["instance_state"] = instance.State
+				item["instance_uri"] = "aws://ec2/instances/" + instance.ID
+				if name, exists := instance.Tags["Name"]; exists {
+					item["instance_name"] = name
+				}
+			}
+			targets = append(targets, item)
+
+			stateCount[target.State]++
+			totalTargets++
+			if unhealthyTargetStates[target.State] {
+				entry := map[string]interface{}{"target_group": group.Name}
+				for key, value := range item {
+					entry[key] = value
+				}
+				unhealthy = append(unhealthy, entry)
+			}
+		}
+
+		loadBalancers := make([]string, 0, len(group.LoadBalancerARNs))
+		for _, arn := range group.LoadBalancerARNs {
+			loadBalancers = append(loadBalancers, loadBalancerName(arn))
+		}
+		sort.Strings(loadBalancers)
+
+		formatted = append(formatted, map[string]interface{}{
+			"name":           group.Name,
+			"protocol":       group.Protocol,
+			"port":           group.Port,
+			"target_type":    group.TargetType,
+			"load_balancers": loadBalancers,
+			"healthy":        group.HealthyCount(),
+			"targets":        targets,
+		})
+	}
+
+	return map[string]interface{}{
+		"total_target_groups": len(groups),
+		"total_targets":       totalTargets,
+		"summary_by_state":    stateCount,
+		"unhealthy_targets":   unhealthy,
+		"target_groups":       formatted,
+	}
+}
+
+// loadBalancerName extracts the name from a load balancer ARN such as
+// arn:aws:elasticloadbalancing:us-east-1:123456789012:loadbalancer/app/web/50dc6c495c0c9188
+func loadBalancerName(arn string) string {
+	segments := strings.Split(arn, "/")
+	if len(segments) >= 3 {
+		return segments[len(segments)-2]
+	}
+	return arn
+}
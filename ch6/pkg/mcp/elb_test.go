@@ -0,0 +1,72 @@
+package mcp
+
+import (
+	"testing"
+
+	"aws-mcp-server/pkg/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const webLoadBalancerARN = "arn:aws:elasticloadbalancing:us-east-1:123456789012:loadbalancer/app/web/50dc6c495c0c9188"
+
+func targetGroupsFixture() []types.TargetGroup {
+	return []types.TargetGroup{
+		{
+			Name:             "web-tg",
+			Protocol:         "HTTP",
+			Port:             80,
+			TargetType:       "instance",
+			LoadBalancerARNs: []string{webLoadBalancerARN},
+			Targets: []types.Target{
+				{ID: "i-1", Port: 80, State: "healthy"},
+				{ID: "i-2", Port: 80, State: "unhealthy", Reason: "Target.ResponseCodeMismatch", Description: "Health checks failed with these codes: [502]"},
+			},
+		},
+		{Name: "idle-tg", TargetType: "ip"},
+	}
+}
+
+func TestFormatLoadBalancers(t *testing.T) {
+	loadBalancers := []types.LoadBalancer{
+		{ARN: webLoadBalancerARN, Name: "web", Type: "application", Scheme: "internet-facing", State: "active"},
+		{ARN: "arn:aws:elasticloadbalancing:us-east-1:123456789012:loadbalancer/net/internal/1", Name: "internal", Type: "network"},
+	}
+
+	formatted := formatLoadBalancers(loadBalancers, targetGroupsFixture())
+
+	assert.Equal(t, 2, formatted["total_load_balancers"])
+	assert.Equal(t, []string{"web", "internal"}, formatted["needs_attention"])
+
+	items := formatted["load_balancers"].([]map[string]interface{})
+	require.Len(t, items, 2)
+	assert.Equal(t, "degraded", items[0]["health"])
+	assert.Equal(t, []string{"web-tg"}, items[0]["target_groups"])
+	assert.Equal(t, map[string]int{"total": 2, "healthy": 1, "unhealthy": 1}, items[0]["targets"])
+	assert.Equal(t, "no targets", items[1]["health"])
+}
+
+func TestFormatTargetGroups(t *testing.T) {
+	instances := []types.CloudResource{
+		{ID: "i-2", State: "running", Tags: map[string]string{"Name": "web-02"}},
+	}
+
+	formatted := formatTargetGroups(targetGroupsFixture(), instances)
+
+	assert.Equal(t, 2, formatted["total_target_groups"])
+	assert.Equal(t, 2, formatted["total_targets"])
+	assert.Equal(t, map[string]int{"healthy": 1, "unhealthy": 1}, formatted["summary_by_state"])
+
+	unhealthy := formatted["unhealthy_targets"].([]map[string]interface{})
+	require.Len(t, unhealthy, 1)
+	assert.Equal(t, "web-tg", unhealthy[0]["target_group"])
+	assert.Equal(t, "web-02", unhealthy[0]["instance_name"])
+	assert.Equal(t, "aws://ec2/instances/i-2", unhealthy[0]["instance_uri"])
+	assert.Equal(t, "Health checks failed with these codes: [502]", unhealthy[0]["description"])
+
+	groups := formatted["target_groups"].([]map[string]interface{})
+	assert.Equal(t, []string{"web"}, groups[0]["load_balancers"])
+	assert.Equal(t, 1, groups[0]["healthy"])
+	assert.NotContains(t, groups[0]["targets"].([]map[string]interface{})[0], "instance_name")
+}
@@ -0,0 +1,230 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"strings"
+	"time"
+
+	"aws-mcp-server/internal/notify"
+	"aws-mcp-server/pkg/audit"
+	"aws-mcp-server/pkg/elevation"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// defaultMaxElevation caps elevation when access.max_elevation is not set
+const defaultMaxElevation = time.Hour
+
+// readOnlyTools only read, so read-only roles may run them without an
+// elevation, along with the tools that ask for and give up elevation. Calls
+// of mutating tools that only return a plan are reads too; read-only roles
+// are refused every other tool, including tools added without a decision.
+var readOnlyTools = map[string]bool{
+	"diagnose-connectivity":       true,
+	"draft-postmortem":            true,
+	"find-orphans":                true,
+	"find-public-exposure":        true,
+	"get-athena-query":            true,
+	"get-athena-query-results":    true,
+	"get-cloudwatch-metrics":      true,
+	"get-instance-console-output": true,
+	"get-instance-screenshot":     true,
+	"get-metric-baseline":         true,
+	"get-redshift-query":          true,
+	"get-redshift-query-results":  true,
+	"query-cloudwatch-logs":       true,
+	"query-logs":                  true,
+	"search":                      true,
+	"simulate-action":             true,
+	"summarize-logs":              true,
+	"verify-remediation":          true,
+	"request-elevation":           true,
+	"end-elevation":               true,
+}
+
+// isReadOnlyCall reports whether a read-only role may make a call without
+// an elevation
+func isReadOnlyCall(name string, arguments map[string]interface{}) bool {
+	return readOnlyTools[name] || mutatingTools[name] && !isMutating(name, arguments)
+}
+
+// isReadOnly reports whether the caller's role may only run write tools
+// while elevated
+func (h *ToolHandler) isReadOnly(ctx context.Context) bool {
+	role := h.callerRole(ctx)
+	for _, readOnly := range h.config.Access.ReadOnlyRoles {
+		if strings.EqualFold(role, readOnly) {
+			return true
+		}
+	}
+	return false
+}
+
+// holderOf returns who an elevation of the caller belongs to: the person the
+// transport identified, or else the MCP session of the call. Calls made
+// outside of sessions without a principal cannot be elevated.
+func holderOf(ctx context.Context) string {
+	if principal, ok := ctx.Value(principalContextKey).(string); ok && principal != "" {
+		return principal
+	}
+	return connectionFrom(ctx).id
+}
+
+// elevationOf returns the grant a read-only caller runs under, if any
+func (h *ToolHandler) elevationOf(ctx context.Context) (elevation.Grant, bool) {
+	holder := holderOf(ctx)
+	if holder == "" || !h.isReadOnly(ctx) {
+		return elevation.Grant{}, false
+	}
+	grant, ok := h.elevations.Active(holder, time.Now())
+	if !ok || !strings.EqualFold(grant.Role, h.callerRole(ctx)) {
+		return elevation.Grant{}, false
+	}
+	return grant, true
+}
+
+// checkElevation rejects calls by read-only roles without an active
+// elevation unless they only read. Approved calls run on an admin's authority.
+func (h *ToolHandler) checkElevation(ctx context.Context, name string, arguments map[string]interface{}) (*mcp.CallToolResult, bool) {
+	if isApproved(ctx) || isReadOnlyCall(name, arguments) || !h.isReadOnly(ctx) {
+		return nil, false
+	}
+	if _, ok := h.elevationOf(ctx); ok {
+		return nil, false
+	}
+
+	result, _ := h.createErrorResponse(fmt.Sprintf("%s is not a read-only tool and role %s is read-only; use request-elevation to ask for time-boxed write access", name, h.callerRole(ctx)))
+	return result, true
+}
+
+// requestElevation asks for write access for a read-only role. The request is
+// blocked for approval at the MCP client or in the queue; once approved it
+// runs again and grants access for the requested minutes, after which the
+// role reverts to read-only on its own.
+func (h *ToolHandler) requestElevation(ctx context.Context, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	reason, _ := arguments["reason"].(string)
+	if strings.TrimSpace(reason) == "" {
+		return h.createErrorResponse("reason is required, e.g. restart the stuck order workers for INC-123")
+	}
+	minutes, _ := arguments["minutes"].(float64)
+	if minutes < 1 {
+		return h.createErrorResponse("minutes is required and must be at least 1")
+	}
+	maxElevation := h.config.Overrides.Duration("access.max_elevation", h.config.Access.MaxElevation)
+	if maxElevation <= 0 {
+		maxElevation = defaultMaxElevation
+	}
+	duration := time.Duration(minutes) * time.Minute
+	if duration > maxElevation {
+		return h.createErrorResponse(fmt.Sprintf("elevation is limited to %d minutes", int(maxElevation.Minutes())))
+	}
+
+	// The approved request runs on the approver's context; the holder and
+	// role to elevate are the ones that asked
+	if request, ok := approvalFrom(ctx); ok {
+		holder, _ := request.Arguments["holder"].(string)
+		if holder == "" {
+			return h.createErrorResponse("the approved request does not name who asked for the elevation")
+		}
+		return h.grantElevation(holder, request.RequestedBy, reason, request.DecidedBy, duration)
+	}
+
+	if !h.isReadOnly(ctx) {
+		return h.createErrorResponse(fmt.Sprintf("role %s is not read-only and needs no elevation", h.callerRole(ctx)))
+	}
+	holder := holderOf(ctx)
+	if holder == "" {
+		return h.createErrorResponse("elevation is granted to an MCP session or chat user, and this call comes from neither")
+	}
+	if grant, ok := h.elevationOf(ctx); ok {
+		return h.createErrorResponse(fmt.Sprintf("%s is already elevated until %s (%s); use end-elevation first to ask again",
+			grant.Holder, h.times.Format(grant.ExpiresAt), grant.ID))
+	}
+
+	// The holder is set here, so callers cannot ask on behalf of others
+	arguments = maps.Clone(arguments)
+	arguments["holder"] = holder
+	reasons := []string{fmt.Sprintf("%s with role %s asks for write access for %d minutes: %s", holder, h.callerRole(ctx), int(minutes), reason)}
+	return h.blockAction(ctx, "request-elevation", arguments, reasons, nil)
+}
+
+// grantElevation elevates the holder with a role, records the grant
+// prominently and arranges for the holder to revert to read-only when it
+// expires
+func (h *ToolHandler) grantElevation(holder, role, reason, approvedBy string, duration time.Duration) (*mcp.CallToolResult, error) {
+	grant := h.elevations.Grant(holder, role, reason, approvedBy, time.Now(), duration)
+	time.AfterFunc(duration, func() {
+		if expired, ok := h.elevations.Expire(grant.ID, time.Now()); ok {
+			h.recordElevation(expired, notify.EventElevationEnded, fmt.Sprintf("ELEVATION EXPIRED: %s with role %s is read-only again", expired.Holder, expired.Role))
+		}
+	})
+
+	message := fmt.Sprintf("ELEVATION GRANTED: %s with role %s may run write tools until %s, approved by %s",
+		holder, role, grant.ExpiresAt.UTC().Format(time.RFC3339), approvedBy)
+	h.recordElevation(grant, notify.EventElevationGranted, message)
+
+	return h.createSuccessResponse(fmt.Sprintf("%s has write access for %d minutes", holder, int(duration.Minutes())), formatGrant(grant, h.times.Format))
+}
+
+// endElevation reverts the caller to read-only before its grant expires.
+// Other holders with the same role keep their grants.
+func (h *ToolHandler) endElevation(ctx context.Context, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	holder := holderOf(ctx)
+	if holder == "" {
+		return h.createErrorResponse("elevation is granted to an MCP session or chat user, and this call comes from neither")
+	}
+	grant, err := h.elevations.End(holder)
+	if err != nil {
+		return h.createErrorResponse(err.Error())
+	}
+
+	h.recordElevation(grant, notify.EventElevationEnded, fmt.Sprintf("ELEVATION ENDED: %s with role %s gave up write access early and is read-only again", grant.Holder, grant.Role))
+	return h.createSuccessResponse(fmt.Sprintf("%s is read-only again", grant.Holder), formatGrant(grant, h.times.Format))
+}
+
+// recordElevation writes a grant's start or end to the audit log and
+// announces it, so elevations stand out in reviews and postmortems
+func (h *ToolHandler) recordElevation(grant elevation.Grant, event, message string) {
+	h.logger.WithField("holder", grant.Holder).WithField("role", grant.Role).WithField("elevation", grant.ID).Warn(message)
+
+	err := h.audit.Record(audit.Entry{
+		Tool:      event,
+		Arguments: map[string]interface{}{"holder": grant.Holder, "reason": grant.Reason, "approvedBy": grant.ApprovedBy},
+		Role:      grant.Role,
+		Success:   true,
+		Message:   message,
+		Elevation: grant.ID,
+	})
+	if err != nil {
+		h.logger.WithError(err).WithField("elevation", grant.ID).Error("Failed to write audit entry")
+	}
+
+	h.notifier.Notify(notify.Event{
+		Type:     event,
+		Severity: notify.SeverityWarning,
+		Title:    message,
+		Message:  grant.Reason,
+		Fields: map[string]interface{}{
+			"elevation":   grant.ID,
+			"holder":      grant.Holder,
+			"role":        grant.Role,
+			"approved_by": grant.ApprovedBy,
+			"expires_at":  grant.ExpiresAt,
+		},
+	})
+}
+
+// formatGrant converts a grant for responses using the given time format
+func formatGrant(grant elevation.Grant, formatTime func(time.Time) string) map[string]interface{} {
+	return map[string]interface{}{
+		"id":          grant.ID,
+		"holder":      grant.Holder,
+		"role":        grant.Role,
+		"reason":      grant.Reason,
+		"approved_by": grant.ApprovedBy,
+		"granted_at":  formatTime(grant.GrantedAt),
+		"expires_at":  formatTime(grant.ExpiresAt),
+	}
+}
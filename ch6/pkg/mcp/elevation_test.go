@@ -0,0 +1,95 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"aws-mcp-server/internal/config"
+	"aws-mcp-server/internal/logging"
+	"aws-mcp-server/pkg/audit"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestElevation(t *testing.T) {
+	cfg := &config.Config{
+		Access:    config.AccessConfig{Role: "viewer", AdminRoles: []string{"admin"}, ReadOnlyRoles: []string{"viewer"}, MaxElevation: time.Hour},
+		Approvals: config.ApprovalsConfig{QueueBlocked: true},
+	}
+	h := NewToolHandler(cfg, nil, logging.NewLogger("error", "text"))
+	h.audit, _ = audit.Open("", 0)
+	viewer := withConnection(context.Background(), connection{id: "stdio-1", transport: transportStdio})
+	admin := WithRole(withConnection(context.Background(), connection{id: "stdio-2", transport: transportStdio}), "admin")
+
+	result, err := h.CallTool(viewer, "start-gcp-instance", map[string]interface{}{"instanceId": "web-1"})
+	require.NoError(t, err)
+	assert.Contains(t, decodeToolResult(t, result)["error"], "role viewer is read-only; use request-elevation")
+
+	result, err = h.CallTool(viewer, "search", map[string]interface{}{"query": "web"})
+	require.NoError(t, err)
+	assert.NotContains(t, decodeToolResult(t, result)["error"], "read-only", "read-only tools need no elevation")
+
+	result, err = h.CallTool(viewer, "request-elevation", map[string]interface{}{"minutes": float64(120), "reason": "restart workers"})
+	require.NoError(t, err)
+	assert.Equal(t, "elevation is limited to 60 minutes", decodeToolResult(t, result)["error"])
+
+	result, err = h.CallTool(viewer, "request-elevation", map[string]interface{}{"minutes": float64(15), "reason": "restart workers"})
+	require.NoError(t, err)
+	requestID, _ := decodeToolResult(t, result)["approval_request_id"].(string)
+	require.NotEmpty(t, requestID, "elevation waits for approval")
+
+	result, err = h.CallTool(admin, "approve-action", map[string]interface{}{"requestId": requestID})
+	require.NoError(t, err)
+	assert.Equal(t, true, decodeToolResult(t, result)["success"])
+	grant, ok := h.elevationOf(viewer)
+	require.True(t, ok)
+	assert.Equal(t, "admin", grant.ApprovedBy)
+	assert.Equal(t, "stdio-1", grant.Holder)
+	other := withConnection(context.Background(), connection{id: "stdio-3", transport: transportStdio})
+	_, ok = h.elevationOf(other)
+	assert.False(t, ok, "other sessions of the role stay read-only")
+
+	result, err = h.CallTool(viewer, "start-gcp-instance", map[string]interface{}{"instanceId": "web-1"})
+	require.NoError(t, err)
+	assert.Equal(t, "this cloud provider is not configured", decodeToolResult(t, result)["error"], "elevated roles may run write tools")
+
+	entries := h.audit.Between(time.Now().Add(-time.Minute), time.Now())
+	var granted, elevatedCall bool
+	for _, entry := range entries {
+		granted = granted || entry.Tool == "elevation.granted" && entry.Role == "viewer"
+		elevatedCall = elevatedCall || entry.Tool == "start-gcp-instance" && entry.Elevation == grant.ID
+	}
+	assert.True(t, granted, "grants are audited")
+	assert.True(t, elevatedCall, "calls under elevation name the grant")
+
+	result, err = h.CallTool(viewer, "end-elevation", map[string]interface{}{})
+	require.NoError(t, err)
+	assert.Equal(t, "stdio-1 is read-only again", decodeToolResult(t, result)["message"])
+	_, ok = h.elevationOf(viewer)
+	assert.False(t, ok)
+}
+
+func TestElevationExpires(t *testing.T) {
+	cfg := &config.Config{Access: config.AccessConfig{Role: "viewer", ReadOnlyRoles: []string{"viewer"}}}
+	h := NewToolHandler(cfg, nil, logging.NewLogger("error", "text"))
+	h.audit, _ = audit.Open("", 0)
+
+	viewer := withConnection(context.Background(), connection{id: "stdio-1", transport: transportStdio})
+	_, err := h.grantElevation("stdio-1", "viewer", "restart workers", "admin", 20*time.Millisecond)
+	require.NoError(t, err)
+	_, ok := h.elevationOf(viewer)
+	require.True(t, ok)
+
+	require.Eventually(t, func() bool {
+		for _, entry := range h.audit.Between(time.Now().Add(-time.Minute), time.Now()) {
+			if entry.Tool == "elevation.ended" {
+				return true
+			}
+		}
+		return false
+	}, time.Second, 10*time.Millisecond, "expiry is audited")
+	_, ok = h.elevationOf(viewer)
+	assert.False(t, ok)
+}
@@ -0,0 +1,331 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"aws-mcp-server/pkg/cost"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const (
+	// peerContextKey carries the connection to the MCP client a request came from
+	peerContextKey contextKey = "peer"
+	// methodElicitationCreate asks the client to collect input from its user
+	methodElicitationCreate = "elicitation/create"
+)
+
+// approvalSchema is the form an approval is asked with: approve or deny, and
+// the reason recorded with the decision
+var approvalSchema = map[string]interface{}{
+	"type": "object",
+	"properties": map[string]interface{}{
+		"decision": map[string]interface{}{
+			"type":      "string",
+			"title":     "Decision",
+			"enum":      []string{"approve", "deny"},
+			"enumNames": []string{"Approve and run", "Deny"},
+		},
+		"reason": map[string]interface{}{
+			"type":        "string",
+			"title":       "Reason",
+			"description": "Recorded with the decision in the approvals log",
+		},
+	},
+	"required": []string{"decision"},
+}
+
+// clientPeer is the stdio or WebSocket connection to the MCP client, through
+// which the server sends requests of its own, such as elicitations, while it
+// serves the client's requests
+type clientPeer struct {
+	writeMu sync.Mutex
+	write   func(message []byte) error
+
+	mu          sync.Mutex
+	nextID      int64
+	pending     map[string]chan peerResponse
+	elicitation bool
+}
+
+// peerResponse is the client's response to a request of the server
+type peerResponse struct {
+	Result json.RawMessage `json:"result"`
+	Error  *struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// elicitResult is the client's answer to an elicitation: accept with the
+// content of the form, decline or cancel
+type elicitResult struct {
+	Action  string                 `json:"action"`
+	Content map[string]interface{} `json:"content"`
+}
+
+// newClientPeer returns a peer sending each message with write
+func newClientPeer(write func(message []byte) error) *clientPeer {
+	return &clientPeer{write: write, pending: make(map[string]chan peerResponse)}
+}
+
+// withPeer returns a context whose tool calls can reach the client through peer
+func withPeer(ctx context.Context, peer *clientPeer) context.Context {
+	return context.WithValue(ctx, peerContextKey, peer)
+}
+
+// peerFrom returns the client connection of the request, nil when the
+// transport has none (e.g. the chat gateway)
+func peerFrom(ctx context.Context) *clientPeer {
+	peer, _ := ctx.Value(peerContextKey).(*clientPeer)
+	return peer
+}
+
+// send writes one message to the client. Responses and the server's own
+// requests are written from several goroutines, one message at a time.
+func (p *clientPeer) send(message []byte) error {
+	p.writeMu.Lock()
+	defer p.writeMu.Unlock()
+	return p.write(message)
+}
+
+// observe looks at a message from the client before it is handled: it notes
+// from the initialize request whether the client supports elicitation, and
+// takes the responses to the server's own requests, reporting them consumed
+func (p *clientPeer) observe(message []byte) bool {
+	var envelope struct {
+		ID     json.RawMessage `json:"id"`
+		Method string          `json:"method"`
+		Params struct {
+			Capabilities struct {
+				Elicitation json.RawMessage `json:"elicitation"`
+			} `json:"capabilities"`
+		} `json:"params"`
+	}
+	if err := json.Unmarshal(message, &envelope); err != nil {
+		return false
+	}
+
+	switch {
+	case envelope.Method == string(mcp.MethodInitialize):
+		elicitation := envelope.Params.Capabilities.Elicitation
+		p.mu.Lock()
+		p.elicitation = len(elicitation) > 0 && string(elicitation) != "null"
+		p.mu.Unlock()
+		return false
+	case envelope.Method == "" && len(envelope.ID) > 0:
+		p.mu.Lock()
+		waiting, ok := p.pending[string(envelope.ID)]
+		delete(p.pending, string(envelope.ID))
+		p.mu.Unlock()
+		if !ok {
+			return false
+		}
+
+		// A malformed response leaves no result, which the waiting request
+		// reports when it decodes it
+		var response peerResponse
+		_ = json.Unmarshal(message, &response)
+		waiting <- response
+		return true
+	}
+	return false
+}
+
+// supportsElicitation reports whether the client declared the elicitation
+// capability. A nil peer supports nothing.
+func (p *clientPeer) supportsElicitation() bool {
+	if p == nil {
+		return false
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.elicitation
+}
+
+// request sends a request to the client and waits for its result
+func (p *clientPeer) request(ctx context.Context, method string, params interface{}) (json.RawMessage, error) {
+	p.mu.Lock()
+	p.nextID++
+	id := p.nextID
+	key := fmt.Sprint(id)
+	waiting := make(chan peerResponse, 1)
+	p.pending[key] = waiting
+	p.mu.Unlock()
+
+	forget := func() {
+		p.mu.Lock()
+		delete(p.pending, key)
+		p.mu.Unlock()
+	}
+
+	message, err := json.Marshal(map[string]interface{}{
+		"jsonrpc": mcp.JSONRPC_VERSION,
+		"id":      id,
+		"method":  method,
+		"params":  params,
+	})
+	if err != nil {
+		forget()
+		return nil, fmt.Errorf("failed to marshal %s request: %w", method, err)
+	}
+	if err := p.send(message); err != nil {
+		forget()
+		return nil, fmt.Errorf("failed to send %s request: %w", method, err)
+	}
+
+	select {
+	case response := <-waiting:
+		if response.Error != nil {
+			return nil, fmt.Errorf("%s failed: %s", method, response.Error.Message)
+		}
+		return response.Result, nil
+	case <-ctx.Done():
+		forget()
+		return nil, ctx.Err()
+	}
+}
+
+// elicit asks the client's user to fill in a form described by schema
+func (p *clientPeer) elicit(ctx context.Context, message string, schema map[string]interface{}) (elicitResult, error) {
+	var result elicitResult
+	if !p.supportsElicitation() {
+		return result, errors.New("the MCP client does not support elicitation")
+	}
+
+	raw, err := p.request(ctx, methodElicitationCreate, map[string]interface{}{
+		"message":         message,
+		"requestedSchema": schema,
+	})
+	if err != nil {
+		return result, err
+	}
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return result, fmt.Errorf("failed to decode elicitation result: %w", err)
+	}
+	return result, nil
+}
+
+// approvalDecision is what the user at the client decided about a blocked
+// action
+type approvalDecision struct {
+	approved bool
+	reason   string
+}
+
+// askApproval asks the user at the MCP client to approve a blocked action
+// through an elicitation form. The caller answers its own request, so only
+// admins, who could approve it from the queue anyway, are asked, and never
+// for their own elevation. It reports false when the client cannot be asked
+// or gave no decision in time, so that the action is queued instead.
+func (h *ToolHandler) askApproval(ctx context.Context, name string, arguments map[string]interface{}, reasons []string, details map[string]interface{}) (approvalDecision, bool) {
+	peer := peerFrom(ctx)
+	if !h.config.Overrides.Bool("approvals.elicit", h.config.Approvals.Elicit) || !peer.supportsElicitation() {
+		return approvalDecision{}, false
+	}
+	if name == "request-elevation" || !h.isAdmin(ctx) {
+		return approvalDecision{}, false
+	}
+
+	if timeout := h.config.Approvals.ElicitTimeout; timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	result, err := peer.elicit(ctx, approvalMessage(name, arguments, reasons, details), approvalSchema)
+	if err != nil {
+		h.logger.WithError(err).WithField("tool", name).Warn("Failed to ask for approval at the MCP client")
+		return approvalDecision{}, false
+	}
+	if result.Action != "accept" {
+		h.logger.WithField("tool", name).WithField("action", result.Action).Info("Approval at the MCP client was not answered")
+		return approvalDecision{}, false
+	}
+
+	decision, _ := result.Content["decision"].(string)
+	reason, _ := result.Content["reason"].(string)
+	switch decision {
+	case "approve":
+		return approvalDecision{approved: true, reason: reason}, true
+	case "deny":
+		return approvalDecision{reason: reason}, true
+	default:
+		return approvalDecision{}, false
+	}
+}
+
+// decideAtClient records the decision taken at the MCP client in the
+// approvals log, then runs the action when it was approved
+func (h *ToolHandler) decideAtClient(ctx context.Context, name string, arguments map[string]interface{}, reasons []string, decision approvalDecision) (*mcp.CallToolResult, error) {
+	request, err := h.approvals.Enqueue(name, arguments, reasons, h.callerRole(ctx))
+	if err != nil {
+		return h.createErrorResponse(fmt.Sprintf("failed to record approval: %v", err))
+	}
+
+	decidedBy := h.callerRole(ctx) + " at the MCP client"
+	if !decision.approved {
+		request, err = h.approvals.Reject(request.ID, decidedBy, decision.reason)
+		if err != nil {
+			return h.createErrorResponse(err.Error())
+		}
+		h.notifyDecision(request)
+
+		message := fmt.Sprintf("%s was denied at the MCP client", name)
+		if decision.reason != "" {
+			message += ": " + decision.reason
+		}
+		return h.createErrorResponse(message)
+	}
+
+	request, err = h.approvals.Approve(request.ID, decidedBy, decision.reason)
+	if err != nil {
+		return h.createErrorResponse(err.Error())
+	}
+	return h.runApproved(ctx, request, "Action approved at the MCP client and executed")
+}
+
+// approvalMessage explains a blocked action to the user asked to approve it:
+// why it is blocked, what it would change and with which arguments
+func approvalMessage(name string, arguments map[string]interface{}, reasons []string, details map[string]interface{}) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s is blocked: %s.", name, strings.Join(reasons, "; "))
+
+	var impact []string
+	if resource := resourceFromArguments(arguments); resource != "" {
+		impact = append(impact, "it changes "+resource)
+	}
+	if violation, ok := details["guardrail"].(*cost.Violation); ok && violation != nil {
+		impact = append(impact, fmt.Sprintf("it is estimated at $%.2f per month", violation.MonthlyUSD))
+	}
+	if len(impact) > 0 {
+		fmt.Fprintf(&b, "\nImpact: %s.", strings.Join(impact, ", "))
+	}
+
+	redacted := redactArguments(name, arguments)
+	keys := make([]string, 0, len(redacted))
+	for key := range redacted {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		fmt.Fprintf(&b, "\n  %s: %v", key, redacted[key])
+	}
+
+	b.WriteString("\nApprove to run it now, or deny it.")
+	return b.String()
+}
+
+// isToolCall reports whether a message from the client calls a tool
+func isToolCall(message []byte) bool {
+	var envelope struct {
+		Method string `json:"method"`
+	}
+	return json.Unmarshal(message, &envelope) == nil && envelope.Method == string(mcp.MethodToolsCall)
+}
@@ -0,0 +1,157 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"aws-mcp-server/internal/config"
+	"aws-mcp-server/internal/logging"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeClient answers the elicitations of a peer with answer, recording the
+// requests it was sent
+func fakeClient(t *testing.T, answer func(params map[string]interface{}) string) (*clientPeer, *[]map[string]interface{}) {
+	t.Helper()
+
+	var requests []map[string]interface{}
+	var peer *clientPeer
+	peer = newClientPeer(func(message []byte) error {
+		var request struct {
+			ID     int64                  `json:"id"`
+			Method string                 `json:"method"`
+			Params map[string]interface{} `json:"params"`
+		}
+		require.NoError(t, json.Unmarshal(message, &request))
+		require.Equal(t, methodElicitationCreate, request.Method)
+		requests = append(requests, request.Params)
+
+		response := fmt.Sprintf(`{"jsonrpc":"2.0","id":%d,"result":%s}`, request.ID, answer(request.Params))
+		go func() { assert.True(t, peer.observe([]byte(response))) }()
+		return nil
+	})
+	require.False(t, peer.observe([]byte(`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"capabilities":{"elicitation":{}}}}`)))
+	return peer, &requests
+}
+
+func TestPeerObserve(t *testing.T) {
+	peer := newClientPeer(func([]byte) error { return nil })
+	assert.False(t, peer.supportsElicitation())
+
+	assert.False(t, peer.observe([]byte(`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"capabilities":{"roots":{}}}}`)))
+	assert.False(t, peer.supportsElicitation())
+	assert.False(t, peer.observe([]byte(`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"capabilities":{"elicitation":{}}}}`)))
+	assert.True(t, peer.supportsElicitation())
+
+	assert.False(t, peer.observe([]byte(`{"jsonrpc":"2.0","id":7,"result":{}}`)), "responses the server did not ask for are handled as usual")
+	assert.False(t, peer.observe([]byte(`{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{}}`)))
+	assert.True(t, isToolCall([]byte(`{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{}}`)))
+
+	var none *clientPeer
+	assert.False(t, none.supportsElicitation())
+}
+
+func TestElicitTimesOut(t *testing.T) {
+	peer := newClientPeer(func([]byte) error { return nil })
+	peer.observe([]byte(`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"capabilities":{"elicitation":{}}}}`))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err := peer.elicit(ctx, "approve?", approvalSchema)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Empty(t, peer.pending, "an unanswered request is forgotten")
+}
+
+func TestBlockActionAsksTheClient(t *testing.T) {
+	cfg := &config.Config{
+		Access:    config.AccessConfig{Role: "operator", AdminRoles: []string{"operator"}},
+		Approvals: config.ApprovalsConfig{QueueBlocked: true, Elicit: true, ElicitTimeout: time.Second},
+	}
+	reasons := []string{`change freeze "release" is active`}
+
+	t.Run("approved actions run", func(t *testing.T) {
+		h := NewToolHandler(cfg, nil, logging.NewLogger("error", "text"))
+		peer, requests := fakeClient(t, func(map[string]interface{}) string {
+			return `{"action":"accept","content":{"decision":"approve","reason":"hotfix"}}`
+		})
+
+		result, err := h.blockAction(withPeer(context.Background(), peer), "start-on-demand-backup", map[string]interface{}{"vaultName": "prod"}, reasons, nil)
+		require.NoError(t, err)
+
+		// The tool ran and failed on its arguments, so the request waits to
+		// be approved again or rejected
+		data := decodeToolResult(t, result)
+		assert.Equal(t, false, data["success"])
+		assert.Contains(t, data["error"], "resourceArn", "the tool ran")
+		require.Len(t, h.approvals.Pending(), 1)
+
+		request := data["request"].(map[string]interface{})
+		assert.Equal(t, "failed", request["status"])
+		assert.Equal(t, "operator at the MCP client", request["decided_by"])
+
+		require.Len(t, *requests, 1)
+		message := (*requests)[0]["message"].(string)
+		assert.Contains(t, message, `start-on-demand-backup is blocked: change freeze "release" is active.`)
+		assert.Contains(t, message, "vaultName: prod")
+		assert.Contains(t, (*requests)[0], "requestedSchema")
+	})
+
+	t.Run("denied actions are rejected", func(t *testing.T) {
+		h := NewToolHandler(cfg, nil, logging.NewLogger("error", "text"))
+		peer, _ := fakeClient(t, func(map[string]interface{}) string {
+			return `{"action":"accept","content":{"decision":"deny","reason":"wait for Monday"}}`
+		})
+
+		result, err := h.blockAction(withPeer(context.Background(), peer), "start-on-demand-backup", map[string]interface{}{}, reasons, nil)
+		require.NoError(t, err)
+		assert.Equal(t, "start-on-demand-backup was denied at the MCP client: wait for Monday", decodeToolResult(t, result)["error"])
+		assert.Empty(t, h.approvals.Pending())
+	})
+
+	t.Run("dismissed forms fall back to the queue", func(t *testing.T) {
+		h := NewToolHandler(cfg, nil, logging.NewLogger("error", "text"))
+		peer, _ := fakeClient(t, func(map[string]interface{}) string { return `{"action":"cancel"}` })
+
+		result, err := h.blockAction(withPeer(context.Background(), peer), "start-on-demand-backup", map[string]interface{}{}, reasons, nil)
+		require.NoError(t, err)
+		assert.Equal(t, true, decodeToolResult(t, result)["queued"])
+		assert.Len(t, h.approvals.Pending(), 1)
+	})
+
+	t.Run("non-admins use the queue", func(t *testing.T) {
+		h := NewToolHandler(cfg, nil, logging.NewLogger("error", "text"))
+		peer, requests := fakeClient(t, func(map[string]interface{}) string {
+			return `{"action":"accept","content":{"decision":"approve"}}`
+		})
+
+		result, err := h.blockAction(WithRole(withPeer(context.Background(), peer), "viewer"), "start-on-demand-backup", map[string]interface{}{}, reasons, nil)
+		require.NoError(t, err)
+		assert.Equal(t, true, decodeToolResult(t, result)["queued"])
+		assert.Empty(t, *requests, "callers cannot approve their own actions")
+	})
+
+	t.Run("elevations use the queue", func(t *testing.T) {
+		h := NewToolHandler(cfg, nil, logging.NewLogger("error", "text"))
+		peer, requests := fakeClient(t, func(map[string]interface{}) string {
+			return `{"action":"accept","content":{"decision":"approve"}}`
+		})
+
+		result, err := h.blockAction(withPeer(context.Background(), peer), "request-elevation", map[string]interface{}{"minutes": float64(15)}, []string{"elevation needs approval"}, nil)
+		require.NoError(t, err)
+		assert.Equal(t, true, decodeToolResult(t, result)["queued"])
+		assert.Empty(t, *requests, "break-glass access is approved by someone else")
+	})
+
+	t.Run("clients without elicitation use the queue", func(t *testing.T) {
+		h := NewToolHandler(cfg, nil, logging.NewLogger("error", "text"))
+
+		result, err := h.blockAction(context.Background(), "start-on-demand-backup", map[string]interface{}{}, reasons, nil)
+		require.NoError(t, err)
+		assert.Equal(t, true, decodeToolResult(t, result)["queued"])
+	})
+}
@@ -0,0 +1,188 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"aws-mcp-server/pkg/aws"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// readUnencryptedResources lists EBS volumes, snapshots and RDS instances stored without encryption
+func (h *ResourceHandler) readUnencryptedResources(ctx context.Context) (*mcp.ReadResourceResult, error) {
+	volumes, err := h.awsClient.ListEBSVolumes(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list EBS volumes: %w", err)
+	}
+
+	snapshots, err := h.awsClient.ListEBSSnapshots(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list EBS snapshots: %w", err)
+	}
+
+	dbInstances, err := h.awsClient.ListDBInstances(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list RDS instances: %w", err)
+	}
+
+	unencryptedVolumes := make([]map[string]interface{}, 0)
+	for _, volume := range volumes {
+		details := volume.EBSVolume()
+		if details.Encrypted {
+			continue
+		}
+
+		formatted := map[string]interface{}{
+			"id":       volume.ID,
+			"state":    volume.State,
+			"type":     details.VolumeType,
+			"size_gib": details.SizeGiB,
+		}
+		if name, exists := volume.Tags["Name"]; exists {
+			formatted["name"] = name
+		}
+		if len(details.Attachments) > 0 {
+			formatted["attached_to"] = details.Attachments[0].InstanceID
+		}
+		unencryptedVolumes = append(unencryptedVolumes, formatted)
+	}
+
+	unencryptedSnapshots := make([]map[string]interface{}, 0)
+	for _, snapshot := range snapshots {
+		details := snapshot.EBSSnapshot()
+		if details.Encrypted {
+			continue
+		}
+
+		unencryptedSnapshots = append(unencryptedSnapshots, map[string]interface{}{
+			"id":         snapshot.ID,
+			"volume_id":  details.VolumeID,
+			"size_gib":   details.SizeGiB,
+			"start_time": h.formatDetails(snapshot.Details)["startTime"],
+		})
+	}
+
+	unencryptedDBInstances := make([]map[string]interface{}, 0)
+	for _, instance := range dbInstances {
+		details := instance.RDSInstance()
+		if details.StorageEncrypted {
+			continue
+		}
+
+		unencryptedDBInstances = append(unencryptedDBInstances, map[string]interface{}{
+			"id":     instance.ID,
+			"state":  instance.State,
+			"engine": details.Engine,
+			"class":  details.InstanceClass,
+		})
+	}
+
+	formatted := map[string]interface{}{
+		"summary": map[string]int{
+			"unencrypted_volumes":      len(unencryptedVolumes),
+			"unencrypted_snapshots":    len(unencryptedSnapshots),
+			"unencrypted_db_instances": len(unencryptedDBInstances),
+		},
+		"volumes":      unencryptedVolumes,
+		"snapshots":    unencryptedSnapshots,
+		"db_instances": unencryptedDBInstances,
+		"remediation": map[string]string{
+			"volumes":      "Use the encrypt-volume tool to replace a volume with an encrypted copy",
+			"snapshots":    "Copy the snapshot with encryption enabled and delete the unencrypted original",
+			"db_instances": "RDS cannot encrypt in place: snapshot the instance, copy the snapshot with encryption and restore from it",
+		},
+	}
+
+	jsonData, err := json.MarshalIndent(formatted, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal unencrypted resources data: %w", err)
+	}
+
+	return &mcp.ReadResourceResult{
+		Contents: []mcp.ResourceContents{
+			&mcp.TextResourceContents{
+				URI:      "aws://security/unencrypted",
+				MIMEType: "application/json",
+				Text:     string(jsonData),
+			},
+		},
+	}, nil
+}
+
+// encryptVolume replaces an unencrypted EBS volume with an encrypted copy.
+// Without confirm=true it only returns the plan and the expected downtime.
+func (h *ToolHandler) encryptVolume(ctx context.Context, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	volumeID, ok := arguments["volumeId"].(string)
+	if !ok || volumeID == "" {
+		return h.createErrorResponse("volumeId is required")
+	}
+
+	kmsKeyID, _ := arguments["kmsKeyId"].(string)
+
+	volume, err := h.awsClient.GetEBSVolume(ctx, volumeID)
+	if err != nil {
+		return h.createErrorResponse(fmt.Sprintf("failed to get EBS volume: %v", err))
+	}
+
+	details := volume.EBSVolume()
+	if details.Encrypted {
+		return h.createErrorResponse(fmt.Sprintf("volume %s is already encrypted", volumeID))
+	}
+
+	if !isConfirmed(arguments) {
+		plan := map[string]interface{}{
+			"volumeId": volumeID,
+			"sizeGiB":  details.SizeGiB,
+			"steps": []string{
+				"Create a snapshot of the volume",
+				"Copy the snapshot with encryption enabled",
+				"Create a new encrypted volume from the copy in the same availability zone",
+				"Detach the original volume and attach the encrypted volume on the same device",
+			},
+		}
+
+		warnings := []string{
+			fmt.Sprintf("Snapshot and copy time grows with volume size (%d GiB) and can take a long time", details.SizeGiB),
+			"The original volume and snapshots are kept for rollback and must be deleted manually afterwards",
+		}
+
+		if len(details.Attachments) > 0 {
+			instanceID := details.Attachments[0].InstanceID
+			plan["instanceId"] = instanceID
+			plan["device"] = details.Attachments[0].Device
+			warnings = append(warnings, fmt.Sprintf(
+				"Volume is attached to %s: the instance must be stopped before the swap and stays down until encryption completes", instanceID))
+		}
+
+		return h.createConfirmationResponse("encrypt-volume", plan, warnings)
+	}
+
+	result, err := h.awsClient.EncryptEBSVolume(ctx, aws.EncryptVolumeParams{
+		VolumeID: volumeID,
+		KMSKeyID: kmsKeyID,
+	})
+	if err != nil {
+		message := fmt.Sprintf("failed to encrypt volume: %v", err)
+		if result != nil && result.SourceSnapshotID != "" {
+			message = fmt.Sprintf("%s (created so far: source snapshot %s, encrypted snapshot %s, new volume %s)",
+				message, result.SourceSnapshotID, result.EncryptedSnapshotID, result.NewVolumeID)
+		}
+		return h.createErrorResponse(message)
+	}
+
+	data := map[string]interface{}{
+		"originalVolumeId":    result.OriginalVolumeID,
+		"newVolumeId":         result.NewVolumeID,
+		"sourceSnapshotId":    result.SourceSnapshotID,
+		"encryptedSnapshotId": result.EncryptedSnapshotID,
+	}
+	if result.InstanceID != "" {
+		data["instanceId"] = result.InstanceID
+		data["device"] = result.Device
+		data["nextStep"] = fmt.Sprintf("Start instance %s to resume service", result.InstanceID)
+	}
+
+	return h.createSuccessResponse("EBS volume encrypted successfully", data)
+}
@@ -0,0 +1,88 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"aws-mcp-server/internal/config"
+	"aws-mcp-server/internal/logging"
+	"aws-mcp-server/pkg/aws"
+	"aws-mcp-server/pkg/fake"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// unencryptedResources is the payload of aws://security/unencrypted
+type unencryptedResources struct {
+	Summary map[string]int `json:"summary"`
+	Volumes []struct {
+		ID         string `json:"id"`
+		AttachedTo string `json:"attached_to"`
+	} `json:"volumes"`
+	Snapshots []struct {
+		VolumeID string `json:"volume_id"`
+	} `json:"snapshots"`
+}
+
+func readUnencrypted(t *testing.T, h *ResourceHandler) unencryptedResources {
+	t.Helper()
+	result, err := h.readUnencryptedResources(context.Background())
+	require.NoError(t, err)
+	var resources unencryptedResources
+	require.NoError(t, json.Unmarshal([]byte(result.Contents[0].(*mcp.TextResourceContents).Text), &resources))
+	return resources
+}
+
+func TestEncryptVolumeOfFleet(t *testing.T) {
+	logger := logging.NewLogger("error", "text")
+	fleet := fake.New(config.FakeConfig{Seed: 3}, "us-east-1")
+	client := aws.NewClientFromConfig(fleet.Config(), logger)
+	resources := NewResourceHandler(&config.Config{}, client)
+	tools := NewToolHandler(&config.Config{}, client, logger)
+	ctx := context.Background()
+
+	before := readUnencrypted(t, resources)
+	assert.Equal(t, map[string]int{"unencrypted_volumes": 4, "unencrypted_snapshots": 0, "unencrypted_db_instances": 0}, before.Summary)
+
+	// Pick the volume of a stopped instance; the others must not be swapped
+	var volumeID, instanceID string
+	for _, volume := range before.Volumes {
+		instance, err := client.GetEC2Instance(ctx, volume.AttachedTo)
+		require.NoError(t, err)
+		if instance.State == "stopped" {
+			volumeID, instanceID = volume.ID, volume.AttachedTo
+		}
+	}
+	require.NotEmpty(t, volumeID)
+
+	result, err := tools.encryptVolume(ctx, map[string]interface{}{"volumeId": volumeID})
+	require.NoError(t, err)
+	plan := decodeToolResult(t, result)
+	assert.Equal(t, true, plan["confirmation_required"])
+	assert.Equal(t, 4, len(readUnencrypted(t, resources).Volumes), "the plan changes nothing")
+
+	result, err = tools.encryptVolume(ctx, map[string]interface{}{"volumeId": volumeID, "confirm": true})
+	require.NoError(t, err)
+	data := decodeToolResult(t, result)
+	require.Equal(t, true, data["success"], data["error"])
+	assert.Equal(t, volumeID, data["originalVolumeId"])
+	assert.Equal(t, instanceID, data["instanceId"])
+	assert.Equal(t, "/dev/xvda", data["device"])
+
+	after := readUnencrypted(t, resources)
+	assert.Equal(t, 4, after.Summary["unencrypted_volumes"], "the detached original is kept for rollback")
+	require.Len(t, after.Snapshots, 1, "so is the unencrypted source snapshot")
+	assert.Equal(t, volumeID, after.Snapshots[0].VolumeID)
+	for _, volume := range after.Volumes {
+		if volume.ID == volumeID {
+			assert.Empty(t, volume.AttachedTo)
+		}
+	}
+
+	result, err = tools.encryptVolume(ctx, map[string]interface{}{"volumeId": data["newVolumeId"], "confirm": true})
+	require.NoError(t, err)
+	assert.Contains(t, decodeToolResult(t, result)["error"], "already encrypted")
+}
@@ -0,0 +1,213 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"aws-mcp-server/internal/notify"
+	"aws-mcp-server/pkg/metrics"
+	"aws-mcp-server/pkg/prometheus"
+	"aws-mcp-server/pkg/slo"
+	"aws-mcp-server/pkg/types"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// metricsErrorRatioWindow is how far back a metrics provider is asked for the
+// latest error ratio; the query itself covers the SLO window
+const metricsErrorRatioWindow = 15 * time.Minute
+
+// budgetGatedTools cause downtime and are held back while the error budget of
+// the affected service is nearly spent
+var budgetGatedTools = map[string]bool{
+	"stop-ec2-instance":       true,
+	"terminate-ec2-instance":  true,
+	"bulk-stop-ec2-instances": true,
+	"encrypt-volume":          true,
+	"stop-rds-instance":       true,
+	"reboot-rds-instance":     true,
+	"reboot-cache-node":       true,
+	"run-patch-baseline":      true,
+}
+
+// checkErrorBudget blocks disruptive calls on resources of a service whose
+// error budget is at or below the threshold, unless the caller passes
+// overrideErrorBudget=true. Budgets that cannot be evaluated do not block.
+func (h *ToolHandler) checkErrorBudget(ctx context.Context, name string, arguments map[string]interface{}) (*mcp.CallToolResult, bool) {
+	if h.slo == nil || !budgetGatedTools[name] || !isMutating(name, arguments) || isApproved(ctx) {
+		return nil, false
+	}
+
+	// A call on several services, e.g. a bulk stop, is blocked when any of
+	// them is out of budget; the one with the least budget left is reported
+	var status *slo.Status
+	for _, service := range h.servicesOf(ctx, name, arguments) {
+		checked, err := h.slo.Check(ctx, service)
+		if err != nil {
+			h.logger.WithError(err).WithField("service", service).Warn("Failed to check the error budget, not gating the action")
+			continue
+		}
+		if checked != nil && checked.Exhausted && (status == nil || checked.BudgetRemaining < status.BudgetRemaining) {
+			status = checked
+		}
+	}
+	if status == nil {
+		return nil, false
+	}
+
+	fields := map[string]interface{}{
+		"tool":             name,
+		"arguments":        redactArguments(name, arguments),
+		"service":          status.Service,
+		"budget_remaining": status.BudgetRemaining,
+		"role":             h.callerRole(ctx),
+	}
+
+	if override, _ := arguments["overrideErrorBudget"].(bool); override {
+		h.logger.WithField("service", status.Service).WithField("tool", name).Warn("Running action despite an exhausted error budget")
+		h.notifier.Notify(notify.Event{
+			Type:     notify.EventBudgetOverridden,
+			Severity: notify.SeverityWarning,
+			Title:    fmt.Sprintf("%s run on %s with %s of its error budget left", name, status.Service, budgetPercent(status.BudgetRemaining)),
+			Fields:   fields,
+		})
+		return nil, false
+	}
+
+	reason := fmt.Sprintf("%s has %s of its error budget left (threshold %s)",
+		status.Service, budgetPercent(status.BudgetRemaining), budgetPercent(status.Threshold))
+	h.notifier.Notify(notify.Event{
+		Type:     notify.EventBudgetBlocked,
+		Severity: notify.SeverityWarning,
+		Title:    fmt.Sprintf("%s blocked: %s", name, reason),
+		Fields:   fields,
+	})
+
+	response := types.NewToolResponse(false, h.times.Now(), map[string]interface{}{
+		"error_budget": status,
+		"override":     "Call the tool again with overrideErrorBudget=true if the change cannot wait; the override is announced in notifications",
+	})
+	response.Error = fmt.Sprintf("%s is blocked: %s", name, reason)
+	result, _ := toolResult(response)
+	return result, true
+}
+
+// servicesOf returns the services a tool call affects, read from the service
+// tag of the target resources. Lookup failures mean no service.
+func (h *ToolHandler) servicesOf(ctx context.Context, name string, arguments map[string]interface{}) []string {
+	if name != "bulk-stop-ec2-instances" {
+		if service := h.serviceOf(ctx, name, arguments); service != "" {
+			return []string{service}
+		}
+		return nil
+	}
+
+	var services []string
+	for _, instanceID := range stringList(arguments["instanceIds"]) {
+		instance, err := h.awsClient.GetEC2Instance(ctx, instanceID)
+		if err != nil {
+			h.logger.WithError(err).WithField("instanceId", instanceID).Debug("Failed to look up instance service")
+			continue
+		}
+		if service := instance.Tags[h.config.SLO.ServiceTag]; service != "" && !slices.Contains(services, service) {
+			services = append(services, service)
+		}
+	}
+	return services
+}
+
+// serviceOf returns the service a call on a single resource affects, read
+// from the service tag of the resource. Lookup failures mean no service.
+func (h *ToolHandler) serviceOf(ctx context.Context, name string, arguments map[string]interface{}) string {
+	tag := h.config.SLO.ServiceTag
+
+	switch name {
+	case "stop-ec2-instance", "terminate-ec2-instance":
+		instanceID, _ := arguments["instanceId"].(string)
+		instance, err := h.awsClient.GetEC2Instance(ctx, instanceID)
+		if err != nil {
+			h.logger.WithError(err).WithField("instanceId", instanceID).Debug("Failed to look up instance service")
+			return ""
+		}
+		return instance.Tags[tag]
+	case "encrypt-volume":
+		volumeID, _ := arguments["volumeId"].(string)
+		volume, err := h.awsClient.GetEBSVolume(ctx, volumeID)
+		if err != nil {
+			h.logger.WithError(err).WithField("volumeId", volumeID).Debug("Failed to look up volume service")
+			return ""
+		}
+		return volume.Tags[tag]
+	case "reboot-cache-node":
+		clusterID, _ := arguments["clusterId"].(string)
+		cluster, err := h.awsClient.GetCacheCluster(ctx, clusterID)
+		if err != nil {
+			h.logger.WithError(err).WithField("clusterId", clusterID).Debug("Failed to look up ElastiCache cluster service")
+			return ""
+		}
+		tags, err := h.awsClient.GetResourceTags(ctx, cluster.ARN)
+		if err != nil {
+			h.logger.WithError(err).WithField("clusterId", clusterID).Debug("Failed to look up ElastiCache cluster service")
+			return ""
+		}
+		return tags[tag]
+	case "stop-rds-instance", "reboot-rds-instance":
+		dbInstanceID, _ := arguments["dbInstanceId"].(string)
+		instances, err := h.awsClient.ListDBInstances(ctx)
+		if err != nil {
+			h.logger.WithError(err).WithField("dbInstanceId", dbInstanceID).Debug("Failed to look up RDS instance service")
+			return ""
+		}
+		for _, instance := range instances {
+			if instance.ID == dbInstanceID {
+				return instance.Tags[tag]
+			}
+		}
+	}
+	return ""
+}
+
+// budgetPercent renders a budget fraction as a percentage
+func budgetPercent(fraction float64) string {
+	return fmt.Sprintf("%.1f%%", fraction*100)
+}
+
+// promErrorRatio evaluates error ratio queries as PromQL instant queries
+func promErrorRatio(client *prometheus.Client) slo.Evaluator {
+	return func(ctx context.Context, query string) (float64, error) {
+		result, err := client.Query(ctx, query, time.Now())
+		if err != nil {
+			return 0, err
+		}
+		if len(result.Series) == 0 || len(result.Series[0].Points) == 0 {
+			return 0, fmt.Errorf("query returned no data")
+		}
+		if len(result.Series) > 1 {
+			return 0, fmt.Errorf("query returned %d series, aggregate it to a single error ratio", len(result.Series))
+		}
+		points := result.Series[0].Points
+		return points[len(points)-1].Value, nil
+	}
+}
+
+// metricsErrorRatio evaluates error ratio queries with the metrics provider,
+// using the latest point of the single series returned
+func metricsErrorRatio(provider metrics.Provider) slo.Evaluator {
+	return func(ctx context.Context, query string) (float64, error) {
+		end := time.Now()
+		series, err := provider.Query(ctx, metrics.Query{Expr: query, Start: end.Add(-metricsErrorRatioWindow), End: end})
+		if err != nil {
+			return 0, err
+		}
+		if len(series) == 0 || len(series[0].Points) == 0 {
+			return 0, fmt.Errorf("query returned no data")
+		}
+		if len(series) > 1 {
+			return 0, fmt.Errorf("query returned %d series, aggregate it to a single error ratio", len(series))
+		}
+		points := series[0].Points
+		return points[len(points)-1].Value, nil
+	}
+}
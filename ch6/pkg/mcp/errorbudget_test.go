@@ -0,0 +1,96 @@
+package mcp
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"aws-mcp-server/internal/config"
+	"aws-mcp-server/internal/logging"
+	"aws-mcp-server/pkg/aws"
+	"aws-mcp-server/pkg/fake"
+	"aws-mcp-server/pkg/prometheus"
+	"aws-mcp-server/pkg/slo"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPromErrorRatio(t *testing.T) {
+	body := `{"status":"success","data":{"resultType":"vector","result":[{"metric":{},"value":[1700000000,"0.0004"]}]}}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	client, err := prometheus.NewClient(config.PrometheusConfig{URL: server.URL}, logging.NewLogger("error", "text"))
+	require.NoError(t, err)
+	evaluate := promErrorRatio(client)
+
+	ratio, err := evaluate(context.Background(), "checkout:error_ratio:30d")
+	require.NoError(t, err)
+	assert.Equal(t, 0.0004, ratio)
+
+	body = `{"status":"success","data":{"resultType":"vector","result":[
+		{"metric":{"job":"a"},"value":[1700000000,"0.1"]},
+		{"metric":{"job":"b"},"value":[1700000000,"0.2"]}]}}`
+	_, err = evaluate(context.Background(), "error_ratio")
+	assert.ErrorContains(t, err, "aggregate it to a single error ratio")
+
+	body = `{"status":"success","data":{"resultType":"vector","result":[]}}`
+	_, err = evaluate(context.Background(), "error_ratio")
+	assert.ErrorContains(t, err, "no data")
+}
+
+func TestErrorBudgetIgnoresUngatedCalls(t *testing.T) {
+	h := NewToolHandler(&config.Config{}, nil, logging.NewLogger("error", "text"))
+
+	// Without a policy nothing is gated and no resource lookup happens
+	result, blocked := h.checkErrorBudget(context.Background(), "stop-ec2-instance", map[string]interface{}{"instanceId": "i-1"})
+	assert.False(t, blocked)
+	assert.Nil(t, result)
+
+	assert.Equal(t, "12.5%", budgetPercent(0.125))
+	assert.Equal(t, "-40.0%", budgetPercent(-0.4))
+}
+
+func TestErrorBudgetChecksEveryServiceOfABulkStop(t *testing.T) {
+	logger := logging.NewLogger("error", "text")
+	fleet := fake.New(config.FakeConfig{Seed: 3}, "us-east-1")
+	cfg := &config.Config{SLO: config.SLOConfig{
+		ServiceTag: "Service",
+		Services: []config.SLOServiceConfig{
+			{Name: "web", Objective: 99.9, ErrorRatioQuery: "web"},
+			{Name: "api", Objective: 99.9, ErrorRatioQuery: "api"},
+		},
+	}}
+	// web is healthy, api has burnt twice its budget
+	ratios := map[string]float64{"web": 0.0001, "api": 0.002}
+	policy, err := slo.NewPolicy(cfg.SLO, map[string]slo.Evaluator{
+		slo.SourcePrometheus: func(ctx context.Context, query string) (float64, error) { return ratios[query], nil },
+	})
+	require.NoError(t, err)
+
+	client := aws.NewClientFromConfig(fleet.Config(), logger)
+	h := NewToolHandler(cfg, client, logger)
+	h.slo = policy
+
+	instances, err := client.ListEC2Instances(context.Background())
+	require.NoError(t, err)
+	byService := make(map[string]string)
+	for _, instance := range instances {
+		if _, seen := byService[instance.Tags["Service"]]; !seen {
+			byService[instance.Tags["Service"]] = instance.ID
+		}
+	}
+
+	arguments := map[string]interface{}{"instanceIds": []interface{}{byService["web"], byService["worker"]}, "confirm": true}
+	_, blocked := h.checkErrorBudget(context.Background(), "bulk-stop-ec2-instances", arguments)
+	assert.False(t, blocked)
+
+	arguments = map[string]interface{}{"instanceIds": []interface{}{byService["web"], byService["api"]}, "confirm": true}
+	result, blocked := h.checkErrorBudget(context.Background(), "bulk-stop-ec2-instances", arguments)
+	require.True(t, blocked, "the second instance's service is out of budget")
+	assert.Contains(t, decodeToolResult(t, result)["error"], "api has -100.0% of its error budget left")
+}
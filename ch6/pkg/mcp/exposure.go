@@ -0,0 +1,179 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+
+	"aws-mcp-server/pkg/types"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// worldCIDRs are the source ranges that make a rule reachable from the internet
+var worldCIDRs = map[string]bool{
+	"0.0.0.0/0": true,
+	"::/0":      true,
+}
+
+// exposedPort describes a port range opened to the internet by a security group rule
+type exposedPort struct {
+	SecurityGroup string `json:"security_group"`
+	Protocol      string `json:"protocol"`
+	Ports         string `json:"ports"`
+	Source        string `json:"source"`
+}
+
+// findPublicExposure reports which ports of instances and load balancers are reachable from the internet
+func (h *ToolHandler) findPublicExposure(ctx context.Context, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	instances, err := h.awsClient.ListEC2Instances(ctx)
+	if err != nil {
+		return h.createErrorResponse(fmt.Sprintf("failed to list EC2 instances: %v", err))
+	}
+
+	groups, err := h.awsClient.ListSecurityGroups(ctx)
+	if err != nil {
+		return h.createErrorResponse(fmt.Sprintf("failed to list security groups: %v", err))
+	}
+
+	loadBalancers, err := h.awsClient.ListLoadBalancers(ctx)
+	if err != nil {
+		return h.createErrorResponse(fmt.Sprintf("failed to list load balancers: %v", err))
+	}
+
+	groupsByID := make(map[string]types.SecurityGroup, len(groups))
+	openGroups := make([]string, 0)
+	for _, group := range groups {
+		groupsByID[group.ID] = group
+		if len(worldOpenPorts(group)) > 0 {
+			openGroups = append(openGroups, group.ID)
+		}
+	}
+
+	exposedInstances := make([]map[string]interface{}, 0)
+	for _, instance := range instances {
+		details := instance.EC2Instance()
+		publicIP := details.PublicIPAddress
+		if publicIP == "" || instance.State == "terminated" {
+			continue
+		}
+
+		var ports []exposedPort
+		for _, groupID := range details.SecurityGroups {
+			ports = append(ports, worldOpenPorts(groupsByID[groupID])...)
+		}
+
+		if len(ports) == 0 {
+			continue
+		}
+
+		entry := map[string]interface{}{
+			"id":            instance.ID,
+			"state":         instance.State,
+			"public_ip":     publicIP,
+			"exposed_ports": ports,
+		}
+		if name, exists := instance.Tags["Name"]; exists {
+			entry["name"] = name
+		}
+		exposedInstances = append(exposedInstances, entry)
+	}
+
+	exposedLoadBalancers := make([]map[string]interface{}, 0)
+	for _, lb := range loadBalancers {
+		if lb.Scheme != "internet-facing" {
+			continue
+		}
+
+		var reachable []types.LoadBalancerListener
+		for _, listener := range lb.Listeners {
+			if listenerReachable(lb, listener, groupsByID) {
+				reachable = append(reachable, listener)
+			}
+		}
+
+		if len(reachable) == 0 {
+			continue
+		}
+
+		exposedLoadBalancers = append(exposedLoadBalancers, map[string]interface{}{
+			"name":            lb.Name,
+			"type":            lb.Type,
+			"dns_name":        lb.DNSName,
+			"reachable_ports": reachable,
+		})
+	}
+
+	data := map[string]interface{}{
+		"exposed_instances":      exposedInstances,
+		"exposed_load_balancers": exposedLoadBalancers,
+		"world_open_groups":      openGroups,
+		"summary": map[string]int{
+			"exposed_instances":      len(exposedInstances),
+			"exposed_load_balancers": len(exposedLoadBalancers),
+			"world_open_groups":      len(openGroups),
+		},
+	}
+
+	return h.createSuccessResponse("Public exposure scan completed", data)
+}
+
+// worldOpenPorts returns the ingress rules of a group that accept traffic from anywhere
+func worldOpenPorts(group types.SecurityGroup) []exposedPort {
+	var ports []exposedPort
+	for _, rule := range group.Ingress {
+		for _, cidr := range rule.CIDRs {
+			if !worldCIDRs[cidr] {
+				continue
+			}
+			ports = append(ports, exposedPort{
+				SecurityGroup: group.ID,
+				Protocol:      formatProtocol(rule.Protocol),
+				Ports:         formatPortRange(rule),
+				Source:        cidr,
+			})
+		}
+	}
+	return ports
+}
+
+// listenerReachable reports whether an internet-facing listener accepts traffic from anywhere.
+// Load balancers without security groups (e.g. classic NLBs) accept all traffic on their listeners.
+func listenerReachable(lb types.LoadBalancer, listener types.LoadBalancerListener, groupsByID map[string]types.SecurityGroup) bool {
+	if len(lb.SecurityGroups) == 0 {
+		return true
+	}
+
+	for _, groupID := range lb.SecurityGroups {
+		for _, rule := range groupsByID[groupID].Ingress {
+			if !rule.CoversPort(listener.Port) {
+				continue
+			}
+			for _, cidr := range rule.CIDRs {
+				if worldCIDRs[cidr] {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// formatProtocol converts the AWS protocol notation to a readable name
+func formatProtocol(protocol string) string {
+	if protocol == "-1" {
+		return "all"
+	}
+	return protocol
+}
+
+// formatPortRange renders the port range of a rule for AI consumption
+func formatPortRange(rule types.SecurityGroupRule) string {
+	switch {
+	case rule.AllowsAllPorts():
+		return "all"
+	case rule.FromPort == rule.ToPort:
+		return fmt.Sprintf("%d", rule.FromPort)
+	default:
+		return fmt.Sprintf("%d-%d", rule.FromPort, rule.ToPort)
+	}
+}
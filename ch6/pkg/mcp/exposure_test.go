@@ -0,0 +1,126 @@
+package mcp
+
+import (
+	"testing"
+
+	"aws-mcp-server/pkg/types"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWorldOpenPorts(t *testing.T) {
+	tests := []struct {
+		name  string
+		rules []types.SecurityGroupRule
+		want  []exposedPort
+	}{
+		{
+			name:  "IPv4 anywhere",
+			rules: []types.SecurityGroupRule{{Protocol: "tcp", FromPort: 22, ToPort: 22, CIDRs: []string{"0.0.0.0/0"}}},
+			want:  []exposedPort{{SecurityGroup: "sg-1", Protocol: "tcp", Ports: "22", Source: "0.0.0.0/0"}},
+		},
+		{
+			name:  "IPv6 anywhere",
+			rules: []types.SecurityGroupRule{{Protocol: "tcp", FromPort: 443, ToPort: 443, CIDRs: []string{"::/0"}}},
+			want:  []exposedPort{{SecurityGroup: "sg-1", Protocol: "tcp", Ports: "443", Source: "::/0"}},
+		},
+		{
+			name:  "both families on one rule",
+			rules: []types.SecurityGroupRule{{Protocol: "udp", FromPort: 53, ToPort: 53, CIDRs: []string{"0.0.0.0/0", "::/0"}}},
+			want: []exposedPort{
+				{SecurityGroup: "sg-1", Protocol: "udp", Ports: "53", Source: "0.0.0.0/0"},
+				{SecurityGroup: "sg-1", Protocol: "udp", Ports: "53", Source: "::/0"},
+			},
+		},
+		{
+			name:  "port range",
+			rules: []types.SecurityGroupRule{{Protocol: "tcp", FromPort: 8000, ToPort: 8080, CIDRs: []string{"0.0.0.0/0"}}},
+			want:  []exposedPort{{SecurityGroup: "sg-1", Protocol: "tcp", Ports: "8000-8080", Source: "0.0.0.0/0"}},
+		},
+		{
+			name:  "all protocols",
+			rules: []types.SecurityGroupRule{{Protocol: "-1", FromPort: -1, ToPort: -1, CIDRs: []string{"::/0"}}},
+			want:  []exposedPort{{SecurityGroup: "sg-1", Protocol: "all", Ports: "all", Source: "::/0"}},
+		},
+		{
+			name: "private ranges and group references",
+			rules: []types.SecurityGroupRule{
+				{Protocol: "tcp", FromPort: 22, ToPort: 22, CIDRs: []string{"10.0.0.0/8", "0.0.0.0/1"}},
+				{Protocol: "-1", SourceGroups: []string{"sg-lb"}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := worldOpenPorts(types.SecurityGroup{ID: "sg-1", Ingress: tt.rules})
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestListenerReachable(t *testing.T) {
+	groups := map[string]types.SecurityGroup{
+		"sg-https": {ID: "sg-https", Ingress: []types.SecurityGroupRule{
+			{Protocol: "tcp", FromPort: 443, ToPort: 443, CIDRs: []string{"0.0.0.0/0"}},
+			{Protocol: "tcp", FromPort: 80, ToPort: 80, CIDRs: []string{"10.0.0.0/16"}},
+		}},
+		"sg-v6": {ID: "sg-v6", Ingress: []types.SecurityGroupRule{
+			{Protocol: "tcp", FromPort: 8000, ToPort: 8999, CIDRs: []string{"::/0"}},
+		}},
+		"sg-all": {ID: "sg-all", Ingress: []types.SecurityGroupRule{
+			{Protocol: "-1", FromPort: -1, ToPort: -1, CIDRs: []string{"0.0.0.0/0"}},
+		}},
+		"sg-udp": {ID: "sg-udp", Ingress: []types.SecurityGroupRule{
+			{Protocol: "udp", FromPort: 443, ToPort: 443, CIDRs: []string{"0.0.0.0/0"}},
+		}},
+		"sg-peer": {ID: "sg-peer", Ingress: []types.SecurityGroupRule{
+			{Protocol: "tcp", FromPort: 443, ToPort: 443, SourceGroups: []string{"sg-cdn"}},
+		}},
+	}
+
+	tests := []struct {
+		name   string
+		groups []string
+		port   int32
+		want   bool
+	}{
+		{"no security groups", nil, 443, true},
+		{"world-open port", []string{"sg-https"}, 443, true},
+		{"port open to the VPC only", []string{"sg-https"}, 80, false},
+		{"port outside every rule", []string{"sg-https"}, 8443, false},
+		{"IPv6 range start", []string{"sg-v6"}, 8000, true},
+		{"IPv6 range end", []string{"sg-v6"}, 8999, true},
+		{"past the IPv6 range", []string{"sg-v6"}, 9000, false},
+		{"all protocols", []string{"sg-all"}, 9443, true},
+		{"UDP rule on a TCP listener", []string{"sg-udp"}, 443, false},
+		{"group reference only", []string{"sg-peer"}, 443, false},
+		{"second group opens it", []string{"sg-peer", "sg-https"}, 443, true},
+		{"unknown group", []string{"sg-gone"}, 443, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lb := types.LoadBalancer{Name: "web", Scheme: "internet-facing", SecurityGroups: tt.groups}
+			listener := types.LoadBalancerListener{Port: tt.port, Protocol: "HTTPS"}
+			assert.Equal(t, tt.want, listenerReachable(lb, listener, groups))
+		})
+	}
+}
+
+func TestFormatPortRange(t *testing.T) {
+	tests := []struct {
+		rule types.SecurityGroupRule
+		want string
+	}{
+		{types.SecurityGroupRule{Protocol: "tcp", FromPort: 22, ToPort: 22}, "22"},
+		{types.SecurityGroupRule{Protocol: "tcp", FromPort: 0, ToPort: 65535}, "0-65535"},
+		{types.SecurityGroupRule{Protocol: "udp", FromPort: 1024, ToPort: 2048}, "1024-2048"},
+		{types.SecurityGroupRule{Protocol: "-1", FromPort: -1, ToPort: -1}, "all"},
+		{types.SecurityGroupRule{Protocol: "-1"}, "all"},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, formatPortRange(tt.rule), "%+v", tt.rule)
+	}
+
+	assert.Equal(t, "all", formatProtocol("-1"))
+	assert.Equal(t, "tcp", formatProtocol("tcp"))
+}
@@ -0,0 +1,32 @@
+package mcp
+
+import (
+	"context"
+
+	"aws-mcp-server/pkg/cost"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// costOverridden reports whether the caller asked to bypass a violated cost
+// guardrail and the configuration permits it
+func (h *ToolHandler) costOverridden(arguments map[string]interface{}) bool {
+	override, _ := arguments["overrideCostGuardrail"].(bool)
+	return override && h.config.Cost.AllowOverride
+}
+
+// blockOnGuardrail explains why a create action was blocked by the cost guardrail
+// and queues it for approval when queueing is enabled
+func (h *ToolHandler) blockOnGuardrail(ctx context.Context, name string, arguments map[string]interface{}, violation *cost.Violation) (*mcp.CallToolResult, error) {
+	details := map[string]interface{}{
+		"guardrail": violation,
+	}
+
+	if h.config.Cost.AllowOverride {
+		details["override"] = "Call the tool again with overrideCostGuardrail=true if the cost is intended"
+	} else {
+		details["override"] = "Overrides are disabled; raise the cost limits in the configuration or choose a cheaper option"
+	}
+
+	return h.blockAction(ctx, name, arguments, violation.Reasons, details)
+}
@@ -0,0 +1,275 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"aws-mcp-server/internal/config"
+	"aws-mcp-server/pkg/management"
+	"aws-mcp-server/pkg/session"
+	"aws-mcp-server/pkg/websocket"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	// httpShutdownTimeout bounds how long the HTTP transport waits for
+	// running requests once the server stops
+	httpShutdownTimeout = 10 * time.Second
+
+	// postedMessageContextKey carries the message a request posted to the
+	// HTTP transport
+	postedMessageContextKey contextKey = "posted-message"
+)
+
+// HTTPHandler returns the handler of the HTTP transport: streamable HTTP on
+// /mcp, WebSocket on /ws and, for clients that predate streamable HTTP, SSE
+// on /sse with messages posted to /message
+func (s *Server) HTTPHandler() http.Handler {
+	return s.httpHandler(&websocketHandler{server: s})
+}
+
+// httpHandler routes the endpoints of the HTTP transport. Streamable HTTP
+// and SSE share the WebSocket recorder.
+func (s *Server) httpHandler(ws *websocketHandler) http.Handler {
+	sse := server.NewSSEServer(s.mcpServer, server.WithKeepAlive(true))
+	streamable := server.NewStreamableHTTPServer(s.mcpServer, server.WithLogger(s.logger))
+
+	mux := http.NewServeMux()
+	mux.Handle("/mcp", s.sessions.track(transportStreamableHTTP, s.readPostedMessages(ws.recorder, streamable)))
+	mux.Handle("/ws", ws)
+	mux.Handle("/sse", s.sessions.track(transportSSE, sse))
+	mux.Handle("/message", s.sessions.track(transportSSE, s.readPostedMessages(ws.recorder, sse)))
+	return s.authorize(mux)
+}
+
+// postedMessage is a JSON-RPC message posted to the HTTP transport. The
+// transports of mcp-go hand it to the MCP server directly, so it travels in
+// the request context to the hooks and tool middleware that replay and
+// record it.
+type postedMessage struct {
+	body     []byte
+	received time.Time
+	recorder *session.Recorder
+}
+
+// readPostedMessages keeps the messages posted to an endpoint in the
+// request context, with the recorder of the HTTP transport
+func (s *Server) readPostedMessages(recorder *session.Recorder, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, websocket.MaxMessageSize))
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			http.Error(w, "message too large", http.StatusRequestEntityTooLarge)
+			return
+		}
+		if err != nil {
+			http.Error(w, "failed to read the message", http.StatusBadRequest)
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		message := &postedMessage{body: body, received: time.Now(), recorder: recorder}
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), postedMessageContextKey, message)))
+
+		// Notifications get no response, so no hook records them
+		if recorder != nil && isNotification(body) {
+			s.record(recorder, body, nil, message.received)
+		}
+	})
+}
+
+// postedMessageFrom returns the message a request posted to the HTTP
+// transport, nil on the other transports
+func postedMessageFrom(ctx context.Context) *postedMessage {
+	message, _ := ctx.Value(postedMessageContextKey).(*postedMessage)
+	return message
+}
+
+// recordPostedMessages adds the hooks recording the exchanges of the HTTP
+// transport, as serveMessage does for stdio and WebSocket
+func (s *Server) recordPostedMessages(hooks *server.Hooks) {
+	record := func(ctx context.Context, response any) {
+		message := postedMessageFrom(ctx)
+		if message == nil || message.recorder == nil {
+			return
+		}
+		encoded, err := json.Marshal(response)
+		if err != nil {
+			s.logger.WithError(err).Warn("Failed to record session entry")
+			return
+		}
+		s.record(message.recorder, message.body, encoded, message.received)
+	}
+
+	hooks.AddOnSuccess(func(ctx context.Context, id any, _ mcp.MCPMethod, _ any, result any) {
+		record(ctx, mcp.JSONRPCResponse{JSONRPC: mcp.JSONRPC_VERSION, ID: mcp.NewRequestId(id), Result: result})
+	})
+	hooks.AddOnError(func(ctx context.Context, id any, _ mcp.MCPMethod, _ any, err error) {
+		response := mcp.NewJSONRPCError(mcp.NewRequestId(id), mcp.INTERNAL_ERROR, err.Error(), nil)
+		var requestErr interface{ ToJSONRPCError() mcp.JSONRPCError }
+		if errors.As(err, &requestErr) {
+			response = requestErr.ToJSONRPCError()
+		}
+		record(ctx, response)
+	})
+}
+
+// replayPostedCalls answers write calls retried over the HTTP transport with
+// their first result, as HandleMessage does for stdio and WebSocket. The
+// stored response is the JSON-RPC response the client got.
+func (s *Server) replayPostedCalls(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		message := postedMessageFrom(ctx)
+		if message == nil {
+			return next(ctx, request)
+		}
+		key, ok := replayKey(s.instance+"/"+connectionFrom(ctx).id, message.body)
+		if !ok {
+			return next(ctx, request)
+		}
+
+		var envelope struct {
+			ID json.RawMessage `json:"id"`
+		}
+		json.Unmarshal(message.body, &envelope)
+		response, err := s.replay(key, func() ([]byte, error) {
+			result, err := next(ctx, request)
+			if err != nil {
+				return nil, err
+			}
+			return json.Marshal(mcp.JSONRPCResponse{JSONRPC: mcp.JSONRPC_VERSION, ID: mcp.NewRequestId(envelope.ID), Result: result})
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		var decoded struct {
+			Result *mcp.CallToolResult `json:"result"`
+		}
+		if err := json.Unmarshal(response, &decoded); err != nil {
+			return nil, fmt.Errorf("failed to decode the stored response: %w", err)
+		}
+		return decoded.Result, nil
+	}
+}
+
+// isNotification reports whether a message is a notification, a request
+// without an ID that gets no response
+func isNotification(message []byte) bool {
+	var envelope struct {
+		ID     json.RawMessage `json:"id"`
+		Method string          `json:"method"`
+	}
+	return json.Unmarshal(message, &envelope) == nil && envelope.Method != "" && len(envelope.ID) == 0
+}
+
+// ValidateHTTP rejects HTTP transport configurations that would let anyone
+// who reaches the port call tools as access.role: off loopback, requests
+// must carry mcp.http_token
+func ValidateHTTP(cfg *config.Config) error {
+	address := net.JoinHostPort(cfg.Server.Host, strconv.Itoa(cfg.Server.Port))
+	if cfg.MCP.HTTPToken == "" && !management.Loopback(address) {
+		return fmt.Errorf("server.host %q is not a loopback address, set mcp.http_token to serve the HTTP transport on it", cfg.Server.Host)
+	}
+	return nil
+}
+
+// authorize rejects requests without mcp.http_token as their bearer token,
+// when one is set, and requests from web pages of other origins
+func (s *Server) authorize(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		logger := s.logger.WithField("remote", r.RemoteAddr).WithField("path", r.URL.Path)
+		if !allowedOrigin(r, s.config.MCP.WebSocketOrigins) {
+			logger.WithField("origin", r.Header.Get("Origin")).Warn("Rejected a request from a page of another origin")
+			http.Error(w, "origin not allowed", http.StatusForbidden)
+			return
+		}
+
+		if token := s.config.MCP.HTTPToken; token != "" {
+			bearer, _ := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if subtle.ConstantTimeCompare([]byte(bearer), []byte(token)) != 1 {
+				logger.Warn("Rejected a request without a valid token")
+				w.Header().Set("WWW-Authenticate", "Bearer")
+				http.Error(w, "a valid bearer token is required", http.StatusUnauthorized)
+				return
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// allowedOrigin reports whether a request may be served. Browsers send the
+// origin of the page making the request, which must be the server's own or
+// one of the allowed origins, so that other pages the user visits cannot
+// drive the server; other clients send no origin.
+func allowedOrigin(r *http.Request, origins []string) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	for _, allowed := range origins {
+		if allowed == "*" || strings.EqualFold(strings.TrimSuffix(allowed, "/"), origin) {
+			return true
+		}
+	}
+	parsed, err := url.Parse(origin)
+	return err == nil && strings.EqualFold(parsed.Host, r.Host)
+}
+
+// serveHTTP serves the HTTP transport on server.host and server.port until
+// ctx is cancelled, recording the sessions with recorder when set.
+// Requests run under ctx, so open SSE streams and WebSocket connections end
+// with it instead of holding up the shutdown.
+func (s *Server) serveHTTP(ctx context.Context, recorder *session.Recorder) error {
+	if err := ValidateHTTP(s.config); err != nil {
+		return err
+	}
+	ws := &websocketHandler{server: s, recorder: recorder}
+	defer ws.conns.Wait()
+
+	address := net.JoinHostPort(s.config.Server.Host, strconv.Itoa(s.config.Server.Port))
+	httpServer := &http.Server{
+		Addr:              address,
+		Handler:           s.httpHandler(ws),
+		ReadHeaderTimeout: 10 * time.Second,
+		BaseContext:       func(net.Listener) context.Context { return ctx },
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- httpServer.ListenAndServe()
+	}()
+
+	s.logger.WithField("address", address).Info("Serving MCP over HTTP: streamable HTTP on /mcp, WebSocket on /ws, SSE on /sse")
+
+	select {
+	case err := <-errCh:
+		return fmt.Errorf("HTTP transport stopped: %w", err)
+	case <-ctx.Done():
+	}
+
+	s.logger.Info("Shutdown signal received, stopping server")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), httpShutdownTimeout)
+	defer cancel()
+	if err := httpServer.Shutdown(shutdownCtx); err != nil {
+		return err
+	}
+	return ctx.Err()
+}
@@ -0,0 +1,227 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"aws-mcp-server/internal/config"
+	"aws-mcp-server/internal/logging"
+	"aws-mcp-server/pkg/aws"
+	"aws-mcp-server/pkg/fake"
+	"aws-mcp-server/pkg/session"
+
+	"github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/client/transport"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newHTTPTestServer(t *testing.T) (*fake.Fleet, *httptest.Server) {
+	t.Helper()
+	logger := logging.NewLogger("error", "text")
+	fleet := fake.New(config.FakeConfig{Seed: 3}, "us-east-1")
+	cfg := &config.Config{MCP: config.MCPConfig{
+		ServerName:       "aws-mcp-server",
+		Version:          "test",
+		Transport:        config.MCPTransportHTTP,
+		WebSocketOrigins: []string{"http://localhost:3000"},
+	}}
+	s := NewServer(cfg, aws.NewClientFromConfig(fleet.Config(), logger), logger)
+
+	server := httptest.NewServer(s.HTTPHandler())
+	t.Cleanup(server.Close)
+	return fleet, server
+}
+
+func initializeClient(t *testing.T, c *client.Client) {
+	t.Helper()
+	ctx := context.Background()
+	require.NoError(t, c.Start(ctx))
+	t.Cleanup(func() { c.Close() })
+
+	request := mcp.InitializeRequest{}
+	request.Params.ProtocolVersion = mcp.LATEST_PROTOCOL_VERSION
+	request.Params.ClientInfo = mcp.Implementation{Name: "http-test", Version: "1.0.0"}
+	result, err := c.Initialize(ctx, request)
+	require.NoError(t, err)
+	assert.Equal(t, "aws-mcp-server", result.ServerInfo.Name)
+}
+
+func TestStreamableHTTPTransport(t *testing.T) {
+	fleet, server := newHTTPTestServer(t)
+	c, err := client.NewStreamableHttpClient(server.URL + "/mcp")
+	require.NoError(t, err)
+	initializeClient(t, c)
+	ctx := context.Background()
+
+	tools, err := c.ListTools(ctx, mcp.ListToolsRequest{})
+	require.NoError(t, err)
+	assert.NotEmpty(t, tools.Tools)
+
+	request := mcp.ReadResourceRequest{}
+	request.Params.URI = "aws://ec2/instances"
+	resource, err := c.ReadResource(ctx, request)
+	require.NoError(t, err)
+	require.NotEmpty(t, resource.Contents)
+	text, ok := resource.Contents[0].(mcp.TextResourceContents)
+	require.True(t, ok)
+	assert.Contains(t, text.Text, fleet.Anomalies()[0].InstanceID)
+}
+
+func TestSSETransport(t *testing.T) {
+	_, server := newHTTPTestServer(t)
+	c, err := client.NewSSEMCPClient(server.URL + "/sse")
+	require.NoError(t, err)
+	initializeClient(t, c)
+
+	prompts, err := c.ListPrompts(context.Background(), mcp.ListPromptsRequest{})
+	require.NoError(t, err)
+	assert.Len(t, prompts.Prompts, len(runbookPrompts))
+}
+
+func TestHTTPTransportToken(t *testing.T) {
+	logger := logging.NewLogger("error", "text")
+	fleet := fake.New(config.FakeConfig{Seed: 3}, "us-east-1")
+	cfg := &config.Config{MCP: config.MCPConfig{ServerName: "aws-mcp-server", Transport: config.MCPTransportHTTP, HTTPToken: "s3cret"}}
+	s := NewServer(cfg, aws.NewClientFromConfig(fleet.Config(), logger), logger)
+	server := httptest.NewServer(s.HTTPHandler())
+	t.Cleanup(server.Close)
+
+	for _, path := range []string{"/mcp", "/sse", "/message", "/ws"} {
+		for _, authorization := range []string{"", "Bearer wrong"} {
+			request, err := http.NewRequest(http.MethodGet, server.URL+path, nil)
+			require.NoError(t, err)
+			request.Header.Set("Authorization", authorization)
+			response, err := http.DefaultClient.Do(request)
+			require.NoError(t, err)
+			response.Body.Close()
+			assert.Equal(t, http.StatusUnauthorized, response.StatusCode, path)
+		}
+	}
+
+	request, err := http.NewRequest(http.MethodPost, server.URL+"/mcp", strings.NewReader(`{}`))
+	require.NoError(t, err)
+	request.Header.Set("Authorization", "Bearer s3cret")
+	request.Header.Set("Origin", "https://evil.example")
+	response, err := http.DefaultClient.Do(request)
+	require.NoError(t, err)
+	response.Body.Close()
+	assert.Equal(t, http.StatusForbidden, response.StatusCode, "pages of other origins are refused on every endpoint")
+
+	c, err := client.NewStreamableHttpClient(server.URL+"/mcp", transport.WithHTTPHeaders(map[string]string{"Authorization": "Bearer s3cret"}))
+	require.NoError(t, err)
+	initializeClient(t, c)
+}
+
+// postMessage posts a JSON-RPC message to the streamable HTTP endpoint and
+// returns the session the server assigned and the response, if any
+func postMessage(t *testing.T, url, sessionID, message string) (string, []byte) {
+	t.Helper()
+	request, err := http.NewRequest(http.MethodPost, url, strings.NewReader(message))
+	require.NoError(t, err)
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("Accept", "application/json, text/event-stream")
+	if sessionID != "" {
+		request.Header.Set(server.HeaderKeySessionID, sessionID)
+	}
+	response, err := http.DefaultClient.Do(request)
+	require.NoError(t, err)
+	defer response.Body.Close()
+	body, err := io.ReadAll(response.Body)
+	require.NoError(t, err)
+	require.Less(t, response.StatusCode, 300, string(body))
+
+	// Responses following notifications come as the last event of a stream
+	if strings.HasPrefix(response.Header.Get("Content-Type"), "text/event-stream") {
+		for _, line := range strings.Split(string(body), "\n") {
+			if data, ok := strings.CutPrefix(line, "data: "); ok {
+				body = []byte(data)
+			}
+		}
+	}
+	if sessionID == "" {
+		sessionID = response.Header.Get(server.HeaderKeySessionID)
+	}
+	return sessionID, body
+}
+
+const initializeMessage = `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-03-26","capabilities":{},"clientInfo":{"name":"http-test","version":"1.0.0"}}}`
+
+func TestHTTPReplaysRetriedWriteCalls(t *testing.T) {
+	logger := logging.NewLogger("error", "text")
+	fleet := fake.New(config.FakeConfig{Seed: 3}, "us-east-1")
+	cfg := &config.Config{
+		MCP:         config.MCPConfig{ServerName: "aws-mcp-server", Version: "test", Transport: config.MCPTransportHTTP},
+		Idempotency: config.IdempotencyConfig{Window: time.Minute},
+	}
+	s := NewServer(cfg, aws.NewClientFromConfig(fleet.Config(), logger), logger)
+	httpServer := httptest.NewServer(s.HTTPHandler())
+	t.Cleanup(httpServer.Close)
+	instanceID := fleet.Anomalies()[0].InstanceID
+
+	sessionID, _ := postMessage(t, httpServer.URL+"/mcp", "", initializeMessage)
+	require.NotEmpty(t, sessionID)
+	call := func(id int, name string) map[string]interface{} {
+		message := fmt.Sprintf(`{"jsonrpc":"2.0","id":%d,"method":"tools/call","params":{"name":%q,"arguments":{"instanceId":%q}}}`, id, name, instanceID)
+		_, response := postMessage(t, httpServer.URL+"/mcp", sessionID, message)
+		var decoded struct {
+			Result *mcp.CallToolResult `json:"result"`
+		}
+		require.NoError(t, json.Unmarshal(response, &decoded), string(response))
+		return decodeToolResult(t, decoded.Result)
+	}
+
+	assert.Equal(t, true, call(3, "stop-ec2-instance")["success"])
+	assert.Equal(t, true, call(4, "terminate-ec2-instance")["success"])
+	assert.Equal(t, true, call(3, "stop-ec2-instance")["success"], "a retry in the session gets the first response")
+
+	result := call(5, "stop-ec2-instance")
+	assert.Equal(t, false, result["success"], "a new request runs again")
+	assert.Contains(t, result["error"], "not in a state")
+}
+
+func TestHTTPSessionsAreRecorded(t *testing.T) {
+	logger := logging.NewLogger("error", "text")
+	fleet := fake.New(config.FakeConfig{Seed: 3}, "us-east-1")
+	cfg := &config.Config{MCP: config.MCPConfig{ServerName: "aws-mcp-server", Version: "test", Transport: config.MCPTransportHTTP}}
+	s := NewServer(cfg, aws.NewClientFromConfig(fleet.Config(), logger), logger)
+	recorder, err := session.NewRecorder(t.TempDir())
+	require.NoError(t, err)
+	httpServer := httptest.NewServer(s.httpHandler(&websocketHandler{server: s, recorder: recorder}))
+	t.Cleanup(httpServer.Close)
+
+	sessionID, _ := postMessage(t, httpServer.URL+"/mcp", "", initializeMessage)
+	postMessage(t, httpServer.URL+"/mcp", sessionID, `{"jsonrpc":"2.0","method":"notifications/initialized"}`)
+	postMessage(t, httpServer.URL+"/mcp", sessionID, `{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"find-orphans","arguments":{}}}`)
+	postMessage(t, httpServer.URL+"/mcp", sessionID, `{"jsonrpc":"2.0","id":3,"method":"resources/read","params":{"uri":"aws://no/such/resource"}}`)
+	require.NoError(t, recorder.Close())
+
+	entries, err := session.Load(recorder.Path())
+	require.NoError(t, err)
+	require.Len(t, entries, 4)
+	assert.Contains(t, string(entries[0].Response), `"serverInfo"`)
+	assert.Contains(t, string(entries[1].Request), "notifications/initialized")
+	assert.Nil(t, entries[1].Response, "notifications have no response")
+	assert.Contains(t, string(entries[2].Request), "find-orphans")
+	assert.Contains(t, string(entries[2].Response), `"id":2`)
+	assert.Contains(t, string(entries[3].Response), `"error"`, "failed requests are recorded with their error")
+}
+
+func TestValidateHTTP(t *testing.T) {
+	cfg := &config.Config{Server: config.ServerConfig{Host: "localhost", Port: 8080}}
+	assert.NoError(t, ValidateHTTP(cfg), "loopback needs no token")
+
+	cfg.Server.Host = "0.0.0.0"
+	assert.EqualError(t, ValidateHTTP(cfg), `server.host "0.0.0.0" is not a loopback address, set mcp.http_token to serve the HTTP transport on it`)
+	cfg.MCP.HTTPToken = "s3cret"
+	assert.NoError(t, ValidateHTTP(cfg))
+}
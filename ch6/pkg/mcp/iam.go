@@ -0,0 +1,419 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"slices"
+	"sort"
+	"strings"
+
+	"aws-mcp-server/pkg/types"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const (
+	// iamRolesURI lists every IAM role with who may assume it and its policies
+	iamRolesURI = "aws://iam/roles"
+	// iamUsersURI lists every IAM user with its direct and group policies
+	iamUsersURI = "aws://iam/users"
+	// iamPoliciesURI is the prefix of managed policy URIs
+	iamPoliciesURI = "aws://iam/policies"
+	// iamPolicyTemplate is the URI template of one managed policy
+	iamPolicyTemplate = "aws://iam/policies/{+arn}"
+	// adminPolicyARN is the AWS managed policy granting full access
+	adminPolicyARN = "arn:aws:iam::aws:policy/AdministratorAccess"
+)
+
+// policyStrings is a policy element that is either a string or a list of strings
+type policyStrings []string
+
+// UnmarshalJSON accepts both forms of the element
+func (l *policyStrings) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*l = policyStrings{single}
+		return nil
+	}
+	var list []string
+	if err := json.Unmarshal(data, &list); err != nil {
+		return err
+	}
+	*l = list
+	return nil
+}
+
+// policyStatement is one statement of an IAM policy document
+type policyStatement struct {
+	Sid          string                 `json:"Sid"`
+	Effect       string                 `json:"Effect"`
+	Principal    json.RawMessage        `json:"Principal"`
+	NotPrincipal json.RawMessage        `json:"NotPrincipal"`
+	Action       policyStrings          `json:"Action"`
+	NotAction    policyStrings          `json:"NotAction"`
+	Resource     policyStrings          `json:"Resource"`
+	NotResource  policyStrings          `json:"NotResource"`
+	Condition    map[string]interface{} `json:"Condition"`
+}
+
+// parsePolicyDocument returns the statements of a policy document, whose
+// Statement element may be a single statement or a list
+func parsePolicyDocument(document string) ([]policyStatement, error) {
+	var parsed struct {
+		Statement json.RawMessage `json:"Statement"`
+	}
+	if err := json.Unmarshal([]byte(document), &parsed); err != nil {
+		return nil, fmt.Errorf("invalid policy document: %w", err)
+	}
+
+	var statements []policyStatement
+	if err := json.Unmarshal(parsed.Statement, &statements); err == nil {
+		return statements, nil
+	}
+	var statement policyStatement
+	if err := json.Unmarshal(parsed.Statement, &statement); err != nil {
+		return nil, fmt.Errorf("invalid policy statement: %w", err)
+	}
+	return []policyStatement{statement}, nil
+}
+
+// principals flattens a Principal element to entries such as
+// "AWS:arn:aws:iam::123456789012:root" or "Service:ec2.amazonaws.com"
+func principals(element json.RawMessage) []string {
+	if len(element) == 0 {
+		return nil
+	}
+
+	var everyone string
+	if err := json.Unmarshal(element, &everyone); err == nil {
+		return []string{everyone}
+	}
+
+	var byType map[string]policyStrings
+	if err := json.Unmarshal(element, &byType); err != nil {
+		return nil
+	}
+	var flattened []string
+	for kind, values := range byType {
+		for _, value := range values {
+			flattened = append(flattened, kind+":"+value)
+		}
+	}
+	sort.Strings(flattened)
+	return flattened
+}
+
+// formatStatements renders a policy document as readable statements. An
+// unparseable document is returned as-is so nothing is hidden.
+func formatStatements(document string) interface{} {
+	statements, err := parsePolicyDocument(document)
+	if err != nil {
+		return document
+	}
+
+	formatted := make([]map[string]interface{}, 0, len(statements))
+	for _, statement := range statements {
+		item := map[string]interface{}{
+			"effect": statement.Effect,
+		}
+		if statement.Sid != "" {
+			item["sid"] = statement.Sid
+		}
+		for key, values := range map[string]policyStrings{
+			"actions":       statement.Action,
+			"not_actions":   statement.NotAction,
+			"resources":     statement.Resource,
+			"not_resources": statement.NotResource,
+		} {
+			if len(values) > 0 {
+				item[key] = []string(values)
+			}
+		}
+		if p := principals(statement.Principal); len(p) > 0 {
+			item["principals"] = p
+		}
+		if p := principals(statement.NotPrincipal); len(p) > 0 {
+			item["not_principals"] = p
+		}
+		if len(statement.Condition) > 0 {
+			item["conditions"] = statement.Condition
+		}
+		formatted = append(formatted, item)
+	}
+	return formatted
+}
+
+// grantsFullAccess reports whether a policy document allows every action on
+// every resource
+func grantsFullAccess(document string) bool {
+	statements, err := parsePolicyDocument(document)
+	if err != nil {
+		return false
+	}
+	for _, statement := range statements {
+		if statement.Effect != "Allow" || len(statement.Condition) > 0 {
+			continue
+		}
+		if slices.Contains(statement.Action, "*") && slices.Contains(statement.Resource, "*") {
+			return true
+		}
+	}
+	return false
+}
+
+// hasFullAccess reports whether attached or inline policies grant full access
+func hasFullAccess(attached []types.IAMPolicyRef, inline []types.IAMInlinePolicy) bool {
+	for _, policy := range attached {
+		if policy.ARN == adminPolicyARN {
+			return true
+		}
+	}
+	for _, policy := range inline {
+		if grantsFullAccess(policy.Document) {
+			return true
+		}
+	}
+	return false
+}
+
+// formatInlinePolicies renders inline policies with their statements
+func formatInlinePolicies(policies []types.IAMInlinePolicy) []map[string]interface{} {
+	formatted := make([]map[string]interface{}, 0, len(policies))
+	for _, policy := range policies {
+		formatted = append(formatted, map[string]interface{}{
+			"name":       policy.Name,
+			"statements": formatStatements(policy.Document),
+		})
+	}
+	return formatted
+}
+
+// readIAMRoles returns all IAM roles with who may assume them and their policies
+func (h *ResourceHandler) readIAMRoles(ctx context.Context) (*mcp.ReadResourceResult, error) {
+	roles, err := h.awsClient.ListIAMRoles(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list IAM roles: %w", err)
+	}
+
+	jsonData, err := json.MarshalIndent(h.formatIAMRoles(roles), "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal IAM roles data: %w", err)
+	}
+
+	return &mcp.ReadResourceResult{
+		Contents: []mcp.ResourceContents{
+			&mcp.TextResourceContents{
+				URI:      iamRolesURI,
+				MIMEType: "application/json",
+				Text:     string(jsonData),
+			},
+		},
+	}, nil
+}
+
+// formatIAMRoles lists roles by name. Service-linked roles are counted but
+// left out, since AWS manages them and they rarely explain access questions.
+func (h *ResourceHandler) formatIAMRoles(roles []types.IAMRole) map[string]interface{} {
+	sorted := append([]types.IAMRole(nil), roles...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].RoleName < sorted[j].RoleName
+	})
+
+	items := make([]map[string]interface{}, 0, len(sorted))
+	fullAccess := []string{}
+	serviceLinked := 0
+	for _, role := range sorted {
+		if strings.HasPrefix(role.Path, "/aws-service-role/") {
+			serviceLinked++
+			continue
+		}
+
+		item := map[string]interface{}{
+			"name":              role.RoleName,
+			"arn":               role.ARN,
+			"attached_policies": role.AttachedPolicies,
+			"inline_policies":   formatInlinePolicies(role.InlinePolicies),
+		}
+		if trust := formatStatements(role.TrustPolicy); trust != nil {
+			item["trust_policy"] = trust
+		}
+		if role.LastUsed != nil {
+			item["last_used"] = h.times.Format(*role.LastUsed)
+			if relative := h.times.Relative(*role.LastUsed); relative != "" {
+				item["last_used_ago"] = relative
+			}
+			if role.LastUsedRegion != "" {
+				item["last_used_region"] = role.LastUsedRegion
+			}
+		} else {
+			item["last_used"] = "never (or not within the 400-day tracking period)"
+		}
+		if role.PermissionsBoundary != "" {
+			item["permissions_boundary"] = role.PermissionsBoundary
+		}
+		if len(role.InstanceProfiles) > 0 {
+			item["instance_profiles"] = role.InstanceProfiles
+		}
+		if len(role.Tags) > 0 {
+			item["tags"] = role.Tags
+		}
+		if hasFullAccess(role.AttachedPolicies, role.InlinePolicies) {
+			item["full_access"] = true
+			fullAccess = append(fullAccess, role.RoleName)
+		}
+		items = append(items, item)
+	}
+
+	return map[string]interface{}{
+		"count":                len(items),
+		"service_linked_roles": serviceLinked,
+		"full_access":          fullAccess,
+		"roles":                items,
+		"note":                 "Managed policy documents are not inlined; read " + iamPolicyTemplate + " for the statements of an attached policy",
+	}
+}
+
+// readIAMUsers returns all IAM users with the policies attached to them
+// directly and through groups
+func (h *ResourceHandler) readIAMUsers(ctx context.Context) (*mcp.ReadResourceResult, error) {
+	users, err := h.awsClient.ListIAMUserPermissions(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list IAM users: %w", err)
+	}
+
+	jsonData, err := json.MarshalIndent(formatIAMUsers(users), "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal IAM users data: %w", err)
+	}
+
+	return &mcp.ReadResourceResult{
+		Contents: []mcp.ResourceContents{
+			&mcp.TextResourceContents{
+				URI:      iamUsersURI,
+				MIMEType: "application/json",
+				Text:     string(jsonData),
+			},
+		},
+	}, nil
+}
+
+// formatIAMUsers lists users by name with their group policies nested under
+// each group
+func formatIAMUsers(users []types.IAMUserPermissions) map[string]interface{} {
+	sorted := append([]types.IAMUserPermissions(nil), users...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].UserName < sorted[j].UserName
+	})
+
+	items := make([]map[string]interface{}, 0, len(sorted))
+	fullAccess := []string{}
+	for _, user := range sorted {
+		groups := make([]map[string]interface{}, 0, len(user.Groups))
+		full := hasFullAccess(user.AttachedPolicies, user.InlinePolicies)
+		for _, group := range user.Groups {
+			groups = append(groups, map[string]interface{}{
+				"name":              group.GroupName,
+				"attached_policies": group.AttachedPolicies,
+				"inline_policies":   formatInlinePolicies(group.InlinePolicies),
+			})
+			full = full || hasFullAccess(group.AttachedPolicies, group.InlinePolicies)
+		}
+
+		item := map[string]interface{}{
+			"name":              user.UserName,
+			"arn":               user.ARN,
+			"attached_policies": user.AttachedPolicies,
+			"inline_policies":   formatInlinePolicies(user.InlinePolicies),
+			"groups":            groups,
+		}
+		if user.PermissionsBoundary != "" {
+			item["permissions_boundary"] = user.PermissionsBoundary
+		}
+		if len(user.Tags) > 0 {
+			item["tags"] = user.Tags
+		}
+		if full {
+			item["full_access"] = true
+			fullAccess = append(fullAccess, user.UserName)
+		}
+		items = append(items, item)
+	}
+
+	return map[string]interface{}{
+		"count":       len(items),
+		"full_access": fullAccess,
+		"users":       items,
+		"note":        "Credential age, MFA and console access are in aws://iam/credential-hygiene; read " + iamPolicyTemplate + " for the statements of an attached policy",
+	}
+}
+
+// readIAMPolicy returns a managed policy's statements and where it is attached
+func (h *ResourceHandler) readIAMPolicy(ctx context.Context, uri string) (*mcp.ReadResourceResult, error) {
+	policyARN, err := policyARNFromURI(uri)
+	if err != nil {
+		return nil, err
+	}
+
+	policy, err := h.awsClient.GetIAMPolicy(ctx, policyARN)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get IAM policy: %w", err)
+	}
+
+	formatted := map[string]interface{}{
+		"name":             policy.Name,
+		"arn":              policy.ARN,
+		"aws_managed":      policy.AWSManaged,
+		"default_version":  policy.DefaultVersion,
+		"updated":          h.times.Format(policy.UpdateDate),
+		"statements":       formatStatements(policy.Document),
+		"attachment_count": policy.AttachmentCount,
+		"attached_to": map[string][]string{
+			"users":  nonNil(policy.Users),
+			"groups": nonNil(policy.Groups),
+			"roles":  nonNil(policy.Roles),
+		},
+		"full_access": policy.ARN == adminPolicyARN || grantsFullAccess(policy.Document),
+	}
+	if policy.Description != "" {
+		formatted["description"] = policy.Description
+	}
+
+	jsonData, err := json.MarshalIndent(formatted, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal IAM policy data: %w", err)
+	}
+
+	return &mcp.ReadResourceResult{
+		Contents: []mcp.ResourceContents{
+			&mcp.TextResourceContents{
+				URI:      uri,
+				MIMEType: "application/json",
+				Text:     string(jsonData),
+			},
+		},
+	}, nil
+}
+
+// policyARNFromURI extracts the policy ARN from a policy URI. ARNs contain
+// slashes, so clients may pass them as-is or URL-encoded.
+func policyARNFromURI(uri string) (string, error) {
+	encoded, _ := strings.CutPrefix(uri, iamPoliciesURI+"/")
+	policyARN, err := url.PathUnescape(encoded)
+	if err != nil {
+		return "", fmt.Errorf("invalid policy ARN %q: %w", encoded, err)
+	}
+	if !strings.HasPrefix(policyARN, "arn:") || !strings.Contains(policyARN, ":policy/") {
+		return "", fmt.Errorf("invalid policy URI %s, use %s with an ARN such as arn:aws:iam::aws:policy/ReadOnlyAccess", uri, iamPolicyTemplate)
+	}
+	return policyARN, nil
+}
+
+// nonNil returns an empty list instead of nil so JSON shows [] rather than null
+func nonNil(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
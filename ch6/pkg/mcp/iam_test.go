@@ -0,0 +1,87 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	"aws-mcp-server/internal/config"
+	"aws-mcp-server/pkg/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const ec2TrustPolicy = `{"Version":"2012-10-17","Statement":{"Effect":"Allow","Principal":{"Service":"ec2.amazonaws.com"},"Action":"sts:AssumeRole"}}`
+
+func TestParsePolicyDocument(t *testing.T) {
+	statements, err := parsePolicyDocument(ec2TrustPolicy)
+	require.NoError(t, err)
+	require.Len(t, statements, 1)
+	assert.Equal(t, policyStrings{"sts:AssumeRole"}, statements[0].Action)
+	assert.Equal(t, []string{"Service:ec2.amazonaws.com"}, principals(statements[0].Principal))
+
+	statements, err = parsePolicyDocument(`{"Statement":[{"Effect":"Allow","Action":["s3:GetObject","s3:PutObject"],"Resource":"arn:aws:s3:::logs/*"},{"Effect":"Deny","Principal":"*","NotAction":"s3:*"}]}`)
+	require.NoError(t, err)
+	require.Len(t, statements, 2)
+	assert.Equal(t, policyStrings{"s3:GetObject", "s3:PutObject"}, statements[0].Action)
+	assert.Equal(t, []string{"*"}, principals(statements[1].Principal))
+
+	_, err = parsePolicyDocument("not json")
+	assert.Error(t, err)
+	assert.Equal(t, "not json", formatStatements("not json"))
+}
+
+func TestFormatIAMRoles(t *testing.T) {
+	h := NewResourceHandler(&config.Config{}, nil)
+	formatted := h.formatIAMRoles([]types.IAMRole{
+		{RoleName: "web", ARN: "arn:aws:iam::123456789012:role/web", Path: "/", TrustPolicy: ec2TrustPolicy,
+			InlinePolicies: []types.IAMInlinePolicy{{Name: "logs", Document: `{"Statement":{"Effect":"Allow","Action":"s3:GetObject","Resource":"*"}}`}}},
+		{RoleName: "AWSServiceRoleForECS", Path: "/aws-service-role/ecs.amazonaws.com/"},
+		{RoleName: "admin", ARN: "arn:aws:iam::123456789012:role/admin", Path: "/",
+			AttachedPolicies: []types.IAMPolicyRef{{Name: "AdministratorAccess", ARN: adminPolicyARN}}},
+	})
+
+	assert.Equal(t, 2, formatted["count"])
+	assert.Equal(t, 1, formatted["service_linked_roles"])
+	assert.Equal(t, []string{"admin"}, formatted["full_access"])
+
+	roles := formatted["roles"].([]map[string]interface{})
+	require.Len(t, roles, 2)
+	assert.Equal(t, "admin", roles[0]["name"])
+	assert.Equal(t, "web", roles[1]["name"])
+	assert.Nil(t, roles[1]["full_access"])
+	trust := roles[1]["trust_policy"].([]map[string]interface{})
+	assert.Equal(t, []string{"Service:ec2.amazonaws.com"}, trust[0]["principals"])
+}
+
+func TestFormatIAMUsersGroupAccess(t *testing.T) {
+	formatted := formatIAMUsers([]types.IAMUserPermissions{
+		{UserName: "bob", Groups: []types.IAMGroup{{GroupName: "readers"}}},
+		{UserName: "alice", Groups: []types.IAMGroup{{GroupName: "ops",
+			InlinePolicies: []types.IAMInlinePolicy{{Name: "all", Document: `{"Statement":[{"Effect":"Allow","Action":"*","Resource":"*"}]}`}}}}},
+	})
+
+	assert.Equal(t, []string{"alice"}, formatted["full_access"])
+	users := formatted["users"].([]map[string]interface{})
+	require.Len(t, users, 2)
+	assert.Equal(t, "alice", users[0]["name"])
+	assert.Equal(t, true, users[0]["full_access"])
+	assert.Nil(t, users[1]["full_access"])
+}
+
+func TestPolicyARNFromURI(t *testing.T) {
+	policyARN, err := policyARNFromURI("aws://iam/policies/arn:aws:iam::aws:policy/ReadOnlyAccess")
+	require.NoError(t, err)
+	assert.Equal(t, "arn:aws:iam::aws:policy/ReadOnlyAccess", policyARN)
+
+	policyARN, err = policyARNFromURI("aws://iam/policies/arn%3Aaws%3Aiam%3A%3A123456789012%3Apolicy%2Fteam%2Fdeploy")
+	require.NoError(t, err)
+	assert.Equal(t, "arn:aws:iam::123456789012:policy/team/deploy", policyARN)
+
+	_, err = policyARNFromURI("aws://iam/policies/ReadOnlyAccess")
+	assert.Error(t, err)
+
+	h := NewResourceHandler(&config.Config{}, nil)
+	_, err = h.ReadResource(context.Background(), "aws://iam/policies/")
+	assert.Error(t, err)
+}
@@ -0,0 +1,180 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"aws-mcp-server/pkg/ignore"
+	"aws-mcp-server/pkg/types"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// ignoredResourcesURI lists the resources left out of anomaly detection,
+// orphan reports and the daily digest
+const ignoredResourcesURI = "aws://resources/ignored"
+
+// ignoreResource leaves a resource out of anomaly detection, orphan reports
+// and the daily digest, for good or until the optional duration has passed.
+// The reason is required so the list explains itself at review.
+func (h *ToolHandler) ignoreResource(ctx context.Context, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	resource, _ := arguments["resource"].(string)
+	resource = strings.TrimSpace(resource)
+	if resource == "" {
+		return h.createErrorResponse("resource is required, e.g. an instance or volume ID")
+	}
+	reason, _ := arguments["reason"].(string)
+	if strings.TrimSpace(reason) == "" {
+		return h.createErrorResponse("reason is required, e.g. kept for forensics of INC-123")
+	}
+
+	now := time.Now()
+	entry := ignore.Entry{
+		Resource:  resource,
+		Reason:    reason,
+		CreatedBy: h.callerRole(ctx),
+		CreatedAt: now,
+	}
+	if value, _ := arguments["duration"].(string); value != "" {
+		duration, err := parseDurationParam(value)
+		if err != nil || duration <= 0 {
+			return h.createErrorResponse(fmt.Sprintf("invalid duration %q, use e.g. 4h, 7d or 90d", value))
+		}
+		expires := now.Add(duration)
+		entry.ExpiresAt = &expires
+	}
+
+	if err := h.ignored.Add(entry); err != nil {
+		h.logger.WithError(err).WithField("resource", resource).Error("Failed to save ignored resources")
+		return h.createErrorResponse(fmt.Sprintf("failed to save the ignored resource: %v", err))
+	}
+
+	message := fmt.Sprintf("Ignoring %s until it is unignored", resource)
+	if entry.ExpiresAt != nil {
+		message = fmt.Sprintf("Ignoring %s until %s", resource, entry.ExpiresAt.UTC().Format(time.RFC3339))
+	}
+	return h.createSuccessResponse(message, formatIgnored(entry, h.times.Format))
+}
+
+// unignoreResource brings an ignored resource back into the reports
+func (h *ToolHandler) unignoreResource(ctx context.Context, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	resource, _ := arguments["resource"].(string)
+	if resource == "" {
+		return h.createErrorResponse("resource is required")
+	}
+
+	entry, err := h.ignored.Remove(strings.TrimSpace(resource))
+	if err != nil {
+		return h.createErrorResponse(err.Error())
+	}
+
+	return h.createSuccessResponse(fmt.Sprintf("%s is no longer ignored", entry.Resource), map[string]interface{}{
+		"resource": entry.Resource,
+		"removed":  formatIgnored(entry, h.times.Format),
+	})
+}
+
+// readIgnoredResources lists the resources that are currently ignored, for
+// review
+func (h *ResourceHandler) readIgnoredResources(ctx context.Context) (*mcp.ReadResourceResult, error) {
+	active := h.ignored.Active(time.Now())
+
+	entries := make([]map[string]interface{}, 0, len(active))
+	expiring := 0
+	for _, entry := range active {
+		formatted := formatIgnored(entry, h.times.Format)
+		if entry.ExpiresAt != nil {
+			expiring++
+			if relative := h.times.Relative(*entry.ExpiresAt); relative != "" {
+				formatted["expires"] = relative
+			}
+		}
+		entries = append(entries, formatted)
+	}
+
+	formatted := map[string]interface{}{
+		"count":        len(entries),
+		"expiring":     expiring,
+		"ignored":      entries,
+		"instructions": "Ignored resources are left out of anomaly detection, find-orphans and the daily digest; use unignore-resource to bring one back",
+	}
+
+	jsonData, err := json.MarshalIndent(formatted, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal ignored resources: %w", err)
+	}
+
+	return &mcp.ReadResourceResult{
+		Contents: []mcp.ResourceContents{
+			&mcp.TextResourceContents{
+				URI:      ignoredResourcesURI,
+				MIMEType: "application/json",
+				Text:     string(jsonData),
+			},
+		},
+	}, nil
+}
+
+// withoutIgnoredOrphans leaves the ignored resources out of an orphan report
+// and returns how many were left out
+func withoutIgnoredOrphans(found []orphan, ignored *ignore.List, now time.Time) ([]orphan, int) {
+	kept := found[:0]
+	for _, item := range found {
+		if _, ok := ignored.Get(item.ID, now); !ok {
+			kept = append(kept, item)
+		}
+	}
+	return kept, len(found) - len(kept)
+}
+
+// withoutIgnoredAlarms leaves the alarms watching an ignored resource, and
+// their state changes, out of the daily digest and returns how many alarms
+// were left out
+func withoutIgnoredAlarms(alarms []types.Alarm, changes []types.AlarmStateChange, ignored *ignore.List, now time.Time) ([]types.Alarm, []types.AlarmStateChange, int) {
+	skipped := make(map[string]bool)
+	kept := make([]types.Alarm, 0, len(alarms))
+	for _, alarm := range alarms {
+		if _, ok := ignored.Any(dimensionValues(alarm.Dimensions), now); ok {
+			skipped[alarm.Name] = true
+			continue
+		}
+		kept = append(kept, alarm)
+	}
+
+	keptChanges := make([]types.AlarmStateChange, 0, len(changes))
+	for _, change := range changes {
+		if !skipped[change.AlarmName] {
+			keptChanges = append(keptChanges, change)
+		}
+	}
+	return kept, keptChanges, len(skipped)
+}
+
+// dimensionValues returns the values of metric dimensions, which name the
+// resources a metric or alarm is about, e.g. an instance ID
+func dimensionValues(dimensions map[string]string) []string {
+	values := make([]string, 0, len(dimensions))
+	for _, value := range dimensions {
+		values = append(values, value)
+	}
+	sort.Strings(values)
+	return values
+}
+
+// formatIgnored converts an entry for responses using the given time format
+func formatIgnored(entry ignore.Entry, formatTime func(time.Time) string) map[string]interface{} {
+	formatted := map[string]interface{}{
+		"resource":   entry.Resource,
+		"reason":     entry.Reason,
+		"created_by": entry.CreatedBy,
+		"created_at": formatTime(entry.CreatedAt),
+	}
+	if entry.ExpiresAt != nil {
+		formatted["expires_at"] = formatTime(*entry.ExpiresAt)
+	}
+	return formatted
+}
@@ -0,0 +1,90 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"aws-mcp-server/internal/config"
+	"aws-mcp-server/internal/logging"
+	"aws-mcp-server/pkg/aws"
+	"aws-mcp-server/pkg/ignore"
+	"aws-mcp-server/pkg/render"
+	"aws-mcp-server/pkg/types"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIgnoreResourceTools(t *testing.T) {
+	h := NewToolHandler(&config.Config{Access: config.AccessConfig{Role: "operator"}}, nil, logging.NewLogger("error", "text"))
+	ctx := context.Background()
+
+	result, err := h.CallTool(ctx, "ignore-resource", map[string]interface{}{"resource": "vol-1"})
+	require.NoError(t, err)
+	assert.Contains(t, decodeToolResult(t, result)["error"], "reason is required")
+
+	result, err = h.CallTool(ctx, "ignore-resource", map[string]interface{}{"resource": "vol-1", "reason": "kept for forensics", "duration": "7d"})
+	require.NoError(t, err)
+	data := decodeToolResult(t, result)
+	assert.Equal(t, true, data["success"])
+	assert.Equal(t, "operator", data["created_by"])
+	assert.NotEmpty(t, data["expires_at"])
+
+	renderer, err := render.New(nil)
+	require.NoError(t, err)
+	payload, err := json.Marshal(map[string]interface{}{"resource": "vol-1", "reason": "kept for forensics"})
+	require.NoError(t, err)
+	summary, ok := renderer.RenderJSON("ignore-resource", payload, nil)
+	require.True(t, ok)
+	assert.Equal(t, "Ignoring vol-1: kept for forensics", summary)
+
+	resources := NewResourceHandler(&config.Config{}, nil)
+	resources.ignored = h.ignored
+	read, err := resources.ReadResource(ctx, ignoredResourcesURI)
+	require.NoError(t, err)
+	var listed map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(read.Contents[0].(*mcp.TextResourceContents).Text), &listed))
+	assert.Equal(t, float64(1), listed["count"])
+	assert.Equal(t, float64(1), listed["expiring"])
+
+	result, err = h.CallTool(ctx, "unignore-resource", map[string]interface{}{"resource": "vol-1"})
+	require.NoError(t, err)
+	assert.Equal(t, "vol-1 is no longer ignored", decodeToolResult(t, result)["message"])
+
+	result, err = h.CallTool(ctx, "unignore-resource", map[string]interface{}{"resource": "vol-1"})
+	require.NoError(t, err)
+	assert.Equal(t, "resource vol-1 is not ignored", decodeToolResult(t, result)["error"])
+}
+
+func TestIgnoredResourcesAreLeftOut(t *testing.T) {
+	now := time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC)
+	ignored := ignore.NewList()
+	require.NoError(t, ignored.Add(ignore.Entry{Resource: "vol-1", Reason: "kept for forensics", CreatedAt: now}))
+	require.NoError(t, ignored.Add(ignore.Entry{Resource: "i-1", Reason: "load test", CreatedAt: now}))
+
+	found, skipped := withoutIgnoredOrphans([]orphan{{Kind: "volume", ID: "vol-1"}, {Kind: "volume", ID: "vol-2"}}, ignored, now)
+	assert.Equal(t, 1, skipped)
+	require.Len(t, found, 1)
+	assert.Equal(t, "vol-2", found[0].ID)
+
+	alarms := []types.Alarm{
+		{Name: "cpu-high-i-1", Dimensions: map[string]string{"InstanceId": "i-1"}},
+		{Name: "cpu-high-i-2", Dimensions: map[string]string{"InstanceId": "i-2"}},
+	}
+	changes := []types.AlarmStateChange{{AlarmName: "cpu-high-i-1"}, {AlarmName: "cpu-high-i-2"}}
+	keptAlarms, keptChanges, skipped := withoutIgnoredAlarms(alarms, changes, ignored, now)
+	assert.Equal(t, 1, skipped)
+	assert.Equal(t, []types.Alarm{alarms[1]}, keptAlarms)
+	assert.Equal(t, []types.AlarmStateChange{changes[1]}, keptChanges)
+
+	h := NewToolHandler(&config.Config{}, nil, logging.NewLogger("error", "text"))
+	h.ignored = ignored
+	params := aws.MetricDataParams{Namespace: "AWS/EC2", MetricName: "CPUUtilization", Dimensions: map[string]string{"InstanceId": "i-1"}, Statistic: "Average", Period: 300}
+	result := h.detectAnomalies(params, []types.MetricDatapoint{{Timestamp: now.Add(-time.Hour), Value: 99}}, now)
+	assert.Contains(t, result, "ignored")
+	assert.Equal(t, 1, result["learned"], "ignored resources keep learning")
+	assert.NotContains(t, result, "anomalies")
+}
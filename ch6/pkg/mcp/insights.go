@@ -0,0 +1,130 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"aws-mcp-server/pkg/aws"
+	"aws-mcp-server/pkg/logs"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const (
+	// defaultInsightsLimit keeps query-cloudwatch-logs responses readable
+	defaultInsightsLimit = 100
+	// maxInsightsLimit is the most rows Logs Insights returns for a query
+	maxInsightsLimit = 10000
+	// defaultInsightsTimeout applies when logs.insights_timeout is not configured
+	defaultInsightsTimeout = time.Minute
+	// maxInsightsTimeout bounds the per-call timeout so a tool call cannot hang for long
+	maxInsightsTimeout = 15 * time.Minute
+	// maxInsightsLogGroups is the Logs Insights limit on log groups per query
+	maxInsightsLogGroups = 50
+	// insightsTimestampLayout is how Logs Insights renders @timestamp (UTC)
+	insightsTimestampLayout = "2006-01-02 15:04:05.000"
+)
+
+// queryCloudWatchLogs runs a Logs Insights query and returns its rows
+func (h *ToolHandler) queryCloudWatchLogs(ctx context.Context, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	params := aws.InsightsQueryParams{Limit: defaultInsightsLimit}
+
+	params.Query, _ = arguments["query"].(string)
+	params.Query = strings.TrimSpace(params.Query)
+	if params.Query == "" {
+		return h.createErrorResponse("query is required, e.g. fields @timestamp, @message | filter @message like /ERROR/ | sort @timestamp desc")
+	}
+
+	params.LogGroups = stringList(arguments["logGroups"])
+	backend := h.config.Logs.Backend
+	if len(params.LogGroups) == 0 && h.config.Logs.DefaultSource != "" && (backend == "" || backend == logs.BackendCloudWatch) {
+		params.LogGroups = []string{h.config.Logs.DefaultSource}
+	}
+	if len(params.LogGroups) == 0 {
+		return h.createErrorResponse("logGroups is required because logs.default_source is not a CloudWatch log group")
+	}
+	if len(params.LogGroups) > maxInsightsLogGroups {
+		return h.createErrorResponse(fmt.Sprintf("at most %d log groups can be queried at once, got %d", maxInsightsLogGroups, len(params.LogGroups)))
+	}
+
+	if limit, ok := arguments["limit"].(float64); ok && limit > 0 {
+		params.Limit = int32(min(limit, maxInsightsLimit))
+	}
+
+	params.Timeout = h.config.Logs.InsightsTimeout
+	if params.Timeout <= 0 {
+		params.Timeout = defaultInsightsTimeout
+	}
+	if seconds, ok := arguments["timeout"].(float64); ok && seconds > 0 {
+		params.Timeout = min(time.Duration(seconds*float64(time.Second)), maxInsightsTimeout)
+	}
+
+	var err error
+	if params.Start, params.End, err = parseWindowArgs(arguments, time.Now(), defaultLogWindow); err != nil {
+		return h.createErrorResponse(err.Error())
+	}
+
+	result, err := h.awsClient.RunInsightsQuery(ctx, params)
+	if errors.Is(err, aws.ErrInsightsTimeout) {
+		return h.createErrorResponse(fmt.Sprintf("logs insights query %s did not complete within %s and was stopped; narrow the window or log groups, or raise timeout",
+			result.QueryID, params.Timeout))
+	}
+	if err != nil {
+		return h.createErrorResponse(fmt.Sprintf("failed to query CloudWatch Logs Insights: %v", err))
+	}
+
+	// Logs Insights renders timestamps in UTC without a zone; use the configured format
+	for _, row := range result.Rows {
+		if value, ok := row["@timestamp"]; ok {
+			if t, err := time.ParseInLocation(insightsTimestampLayout, value, time.UTC); err == nil {
+				row["@timestamp"] = h.times.Format(t)
+			}
+		}
+	}
+
+	data := map[string]interface{}{
+		"queryId":    result.QueryID,
+		"log_groups": params.LogGroups,
+		"query":      params.Query,
+		"start":      h.times.Format(params.Start),
+		"end":        h.times.Format(params.End),
+		"status":     result.Status,
+		"fields":     result.Fields,
+		"count":      len(result.Rows),
+		"rows":       result.Rows,
+		"statistics": map[string]float64{
+			"records_matched": result.RecordsMatched,
+			"records_scanned": result.RecordsScanned,
+			"bytes_scanned":   result.BytesScanned,
+		},
+	}
+	if len(result.Rows) >= int(params.Limit) {
+		data["truncated"] = true
+		data["note"] = "The limit was reached; aggregate with stats or narrow the query for complete results"
+	}
+
+	return h.createSuccessResponse(fmt.Sprintf("Logs Insights query returned %d rows", len(result.Rows)), data)
+}
+
+// stringList accepts a JSON array of strings or a comma-separated string
+func stringList(value interface{}) []string {
+	var items []string
+	switch v := value.(type) {
+	case []interface{}:
+		for _, item := range v {
+			if s, ok := item.(string); ok && strings.TrimSpace(s) != "" {
+				items = append(items, strings.TrimSpace(s))
+			}
+		}
+	case string:
+		for _, item := range strings.Split(v, ",") {
+			if strings.TrimSpace(item) != "" {
+				items = append(items, strings.TrimSpace(item))
+			}
+		}
+	}
+	return items
+}
@@ -0,0 +1,41 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	"aws-mcp-server/internal/config"
+	"aws-mcp-server/internal/logging"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStringList(t *testing.T) {
+	assert.Equal(t, []string{"/aws/lambda/orders", "/ecs/web"}, stringList([]interface{}{"/aws/lambda/orders", " ", "/ecs/web"}))
+	assert.Equal(t, []string{"/aws/lambda/orders", "/ecs/web"}, stringList("/aws/lambda/orders, /ecs/web,"))
+	assert.Empty(t, stringList(nil))
+}
+
+func TestQueryCloudWatchLogsValidatesArguments(t *testing.T) {
+	h := NewToolHandler(&config.Config{}, nil, logging.NewLogger("error", "text"))
+	ctx := context.Background()
+
+	result, err := h.CallTool(ctx, "query-cloudwatch-logs", map[string]interface{}{})
+	require.NoError(t, err)
+	assert.Contains(t, decodeToolResult(t, result)["error"], "query is required")
+
+	result, err = h.CallTool(ctx, "query-cloudwatch-logs", map[string]interface{}{
+		"query": "fields @message",
+	})
+	require.NoError(t, err)
+	assert.Contains(t, decodeToolResult(t, result)["error"], "logGroups is required")
+
+	result, err = h.CallTool(ctx, "query-cloudwatch-logs", map[string]interface{}{
+		"query":     "fields @message",
+		"logGroups": "/ecs/web",
+		"since":     "yesterday",
+	})
+	require.NoError(t, err)
+	assert.Contains(t, decodeToolResult(t, result)["error"], "invalid since")
+}
@@ -0,0 +1,198 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"aws-mcp-server/pkg/aws"
+	"aws-mcp-server/pkg/cloud"
+	"aws-mcp-server/pkg/types"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const (
+	// instanceGroupingPrefix starts the last segment of the EC2 grouping views,
+	// e.g. aws://ec2/instances/by-vpc; EC2 instance IDs never start with it
+	instanceGroupingPrefix = "by-"
+	// instanceGroupingKey names the summary template of every grouping view
+	instanceGroupingKey = "aws://ec2/instances/by-{grouping}"
+	// asgTag is the tag EC2 Auto Scaling puts on the instances it launches
+	asgTag = "aws:autoscaling:groupName"
+	// ungroupedKey groups the instances without a value for the grouping
+	ungroupedKey = "(none)"
+)
+
+// instanceGroupings are the EC2 grouping views and what they group by
+var instanceGroupings = []struct {
+	name        string
+	title       string
+	description string
+}{
+	{"asg", "EC2 Instances by Auto Scaling Group", "EC2 instances grouped by the Auto Scaling group that launched them, with state and type counts per group"},
+	{"vpc", "EC2 Instances by VPC", "EC2 instances grouped by VPC, with state and type counts per VPC"},
+	{"environment", "EC2 Instances by Environment", "EC2 instances grouped by the first of tagging.environment_tags they carry, e.g. Environment=prod"},
+	{"owner", "EC2 Instances by Owner", "EC2 instances grouped by owning team, resolved like aws://ownership/{+resourceId} or from tagging.owner_tags"},
+}
+
+// isInstanceGrouping reports whether an instance ID from a provider URI is
+// one of the AWS grouping views
+func isInstanceGrouping(provider cloud.Provider, instanceID string) bool {
+	return provider.Name() == aws.ProviderName && strings.HasPrefix(instanceID, instanceGroupingPrefix)
+}
+
+// readInstanceGrouping returns the EC2 instances grouped by Auto Scaling
+// group, VPC, environment or owner, largest group first
+func (h *ResourceHandler) readInstanceGrouping(ctx context.Context, provider cloud.Provider, view string) (*mcp.ReadResourceResult, error) {
+	grouping, _ := strings.CutPrefix(view, instanceGroupingPrefix)
+
+	var keyOf func(instance types.CloudResource) string
+	switch grouping {
+	case "asg":
+		keyOf = func(instance types.CloudResource) string { return instance.Tags[asgTag] }
+	case "vpc":
+		keyOf = func(instance types.CloudResource) string { return instance.EC2Instance().VpcID }
+	case "environment":
+		keyOf = func(instance types.CloudResource) string {
+			return firstTag(instance.Tags, h.config.Tagging.EnvironmentTags)
+		}
+	case "owner":
+		keyOf = func(instance types.CloudResource) string { return h.instanceOwner(ctx, instance) }
+	default:
+		names := make([]string, 0, len(instanceGroupings))
+		for _, g := range instanceGroupings {
+			names = append(names, instanceGroupingPrefix+g.name)
+		}
+		return nil, fmt.Errorf("unknown instance grouping %q, use %s", view, strings.Join(names, ", "))
+	}
+
+	instances, err := provider.ListInstances(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s instances: %w", provider.Label(), err)
+	}
+
+	data := groupInstancesBy(instances, keyOf)
+	data["grouping"] = grouping
+
+	uri := cloud.InstancesURI(provider) + "/" + view
+	jsonData, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal instance groups: %w", err)
+	}
+
+	return &mcp.ReadResourceResult{
+		Contents: []mcp.ResourceContents{
+			&mcp.TextResourceContents{
+				URI:      uri,
+				MIMEType: "application/json",
+				Text:     string(jsonData),
+			},
+		},
+	}, nil
+}
+
+// instanceOwner returns the team owning an instance: from the ownership
+// resolver when it is configured, otherwise from the owner tags
+func (h *ResourceHandler) instanceOwner(ctx context.Context, instance types.CloudResource) string {
+	if h.owners != nil {
+		if owner, err := h.owners.Resolve(ctx, instance.ID); err == nil && len(owner.Teams) > 0 {
+			return strings.Join(owner.Teams, ",")
+		}
+	}
+	return firstTag(instance.Tags, h.config.Tagging.OwnerTags)
+}
+
+// firstTag returns the value of the first of keys that is tagged, matching
+// keys case-insensitively since teams spell Environment and environment alike
+func firstTag(tags map[string]string, keys []string) string {
+	for _, key := range keys {
+		for tagKey, value := range tags {
+			if strings.EqualFold(tagKey, key) && strings.TrimSpace(value) != "" {
+				return strings.TrimSpace(value)
+			}
+		}
+	}
+	return ""
+}
+
+// groupInstancesBy groups instances by key with their state and type counts,
+// largest group first and ungrouped instances last
+func groupInstancesBy(instances []types.CloudResource, keyOf func(types.CloudResource) string) map[string]interface{} {
+	type group struct {
+		key       string
+		instances []map[string]interface{}
+		states    map[string]int
+		types     map[string]int
+	}
+
+	byKey := make(map[string]*group)
+	for _, instance := range instances {
+		key := keyOf(instance)
+		if key == "" {
+			key = ungroupedKey
+		}
+		g, ok := byKey[key]
+		if !ok {
+			g = &group{key: key, states: make(map[string]int), types: make(map[string]int)}
+			byKey[key] = g
+		}
+
+		item := map[string]interface{}{
+			"id":    instance.ID,
+			"state": instance.State,
+			"uri":   "aws://ec2/instances/" + instance.ID,
+		}
+		if name := instance.Tags["Name"]; name != "" {
+			item["name"] = name
+		}
+		if instanceType := instance.InstanceType(); instanceType != "" {
+			item["type"] = instanceType
+			g.types[instanceType]++
+		}
+		g.instances = append(g.instances, item)
+		g.states[instance.State]++
+	}
+
+	groups := make([]*group, 0, len(byKey))
+	for _, g := range byKey {
+		groups = append(groups, g)
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		if (groups[i].key == ungroupedKey) != (groups[j].key == ungroupedKey) {
+			return groups[j].key == ungroupedKey
+		}
+		if len(groups[i].instances) != len(groups[j].instances) {
+			return len(groups[i].instances) > len(groups[j].instances)
+		}
+		return groups[i].key < groups[j].key
+	})
+
+	formatted := make([]map[string]interface{}, 0, len(groups))
+	ungrouped := 0
+	for _, g := range groups {
+		if g.key == ungroupedKey {
+			ungrouped = len(g.instances)
+		}
+		formatted = append(formatted, map[string]interface{}{
+			"key":             g.key,
+			"total_instances": len(g.instances),
+			"by_state":        g.states,
+			"by_type":         g.types,
+			"instances":       g.instances,
+		})
+	}
+
+	totalGroups := len(groups)
+	if ungrouped > 0 {
+		totalGroups--
+	}
+	return map[string]interface{}{
+		"total_instances": len(instances),
+		"total_groups":    totalGroups,
+		"ungrouped":       ungrouped,
+		"groups":          formatted,
+	}
+}
@@ -0,0 +1,50 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	"aws-mcp-server/internal/config"
+	"aws-mcp-server/pkg/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGroupInstancesBy(t *testing.T) {
+	instances := []types.CloudResource{
+		{ID: "i-1", State: "running", Tags: map[string]string{"Name": "web-1", asgTag: "web"}, Details: &types.EC2InstanceDetails{InstanceType: "t3.micro"}},
+		{ID: "i-2", State: "stopped", Tags: map[string]string{asgTag: "web"}, Details: &types.EC2InstanceDetails{InstanceType: "t3.micro"}},
+		{ID: "i-3", State: "running", Tags: map[string]string{asgTag: "api"}, Details: &types.EC2InstanceDetails{InstanceType: "m5.large"}},
+		{ID: "i-4", State: "running", Details: &types.EC2InstanceDetails{}},
+	}
+
+	data := groupInstancesBy(instances, func(instance types.CloudResource) string { return instance.Tags[asgTag] })
+	assert.Equal(t, 4, data["total_instances"])
+	assert.Equal(t, 2, data["total_groups"])
+	assert.Equal(t, 1, data["ungrouped"])
+
+	groups := data["groups"].([]map[string]interface{})
+	require.Len(t, groups, 3)
+	assert.Equal(t, "web", groups[0]["key"])
+	assert.Equal(t, map[string]int{"running": 1, "stopped": 1}, groups[0]["by_state"])
+	assert.Equal(t, map[string]int{"t3.micro": 2}, groups[0]["by_type"])
+	first := groups[0]["instances"].([]map[string]interface{})[0]
+	assert.Equal(t, "web-1", first["name"])
+	assert.Equal(t, "aws://ec2/instances/i-1", first["uri"])
+	assert.Equal(t, "api", groups[1]["key"])
+	assert.Equal(t, ungroupedKey, groups[2]["key"], "instances without a group come last")
+}
+
+func TestFirstTag(t *testing.T) {
+	tags := map[string]string{"env": "prod", "Stage": "blue", "Team": " "}
+	assert.Equal(t, "prod", firstTag(tags, []string{"Environment", "Env", "Stage"}))
+	assert.Equal(t, "blue", firstTag(tags, []string{"Stage", "Env"}))
+	assert.Empty(t, firstTag(tags, []string{"Team"}), "blank values do not count")
+}
+
+func TestReadInstanceGroupingUnknown(t *testing.T) {
+	h := NewResourceHandler(&config.Config{}, nil)
+	_, err := h.ReadResource(context.Background(), "aws://ec2/instances/by-color")
+	assert.ErrorContains(t, err, "unknown instance grouping")
+}
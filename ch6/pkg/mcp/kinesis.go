@@ -0,0 +1,271 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"aws-mcp-server/pkg/aws"
+	"aws-mcp-server/pkg/types"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const (
+	// kinesisStreamsURI lists the Kinesis data streams with their shard counts
+	// and throughput metrics
+	kinesisStreamsURI = "aws://kinesis/streams"
+	// kinesisStreamTemplate is the URI template of one stream, by its name
+	kinesisStreamTemplate = "aws://kinesis/streams/{name}"
+	// kinesisIteratorAgeWarning is how far consumers may lag behind before
+	// the stream is reported at risk
+	kinesisIteratorAgeWarning = time.Minute
+	// kinesisShardBytesPerSecond and kinesisShardRecordsPerSecond are the
+	// write capacity of one shard
+	kinesisShardBytesPerSecond   = 1 << 20
+	kinesisShardRecordsPerSecond = 1000
+	// kinesisTargetUtilization is the share of the write capacity a suggested
+	// shard count leaves the peak load at
+	kinesisTargetUtilization = 0.7
+)
+
+// readKinesisStreams lists the streams, those being throttled or whose
+// consumers fall behind first
+func (h *ResourceHandler) readKinesisStreams(ctx context.Context) (*mcp.ReadResourceResult, error) {
+	streams, err := h.awsClient.ListKinesisStreams(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list Kinesis streams: %w", err)
+	}
+
+	jsonData, err := json.MarshalIndent(formatKinesisStreams(streams), "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal Kinesis streams data: %w", err)
+	}
+
+	return &mcp.ReadResourceResult{
+		Contents: []mcp.ResourceContents{
+			&mcp.TextResourceContents{
+				URI:      kinesisStreamsURI,
+				MIMEType: "application/json",
+				Text:     string(jsonData),
+			},
+		},
+	}, nil
+}
+
+// readKinesisStream returns one stream by the name in the URI with its
+// metrics and configuration
+func (h *ResourceHandler) readKinesisStream(ctx context.Context, req resourceRequest) (*mcp.ReadResourceResult, error) {
+	uri, name := req.URI, req.Param("name")
+	if name == "" {
+		return nil, fmt.Errorf("invalid Kinesis stream URI %s, use %s", uri, kinesisStreamTemplate)
+	}
+
+	stream, err := h.awsClient.GetKinesisStream(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	data := formatKinesisStream(*stream)
+	data["arn"] = stream.ARN
+	data["consumers"] = stream.Consumers
+	data["retention_hours"] = stream.RetentionHours
+	data["encryption"] = stream.Encryption
+	data["created"] = h.times.Format(stream.CreatedAt)
+
+	jsonData, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal Kinesis stream data: %w", err)
+	}
+
+	return &mcp.ReadResourceResult{
+		Contents: []mcp.ResourceContents{
+			&mcp.TextResourceContents{
+				URI:      uri,
+				MIMEType: "application/json",
+				Text:     string(jsonData),
+			},
+		},
+	}, nil
+}
+
+// formatKinesisStreams orders the streams with issues first, then by shard count
+func formatKinesisStreams(streams []types.KinesisStream) map[string]interface{} {
+	items := make([]map[string]interface{}, 0, len(streams))
+	atRisk := []string{}
+	totalShards := int32(0)
+	for _, stream := range streams {
+		item := formatKinesisStream(stream)
+		if _, ok := item["issues"]; ok {
+			atRisk = append(atRisk, stream.Name)
+		}
+		items = append(items, item)
+		totalShards += stream.OpenShards
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		_, iIssues := items[i]["issues"]
+		_, jIssues := items[j]["issues"]
+		if iIssues != jIssues {
+			return iIssues
+		}
+		return items[i]["open_shards"].(int32) > items[j]["open_shards"].(int32)
+	})
+	sort.Strings(atRisk)
+
+	return map[string]interface{}{
+		"total":           len(streams),
+		"open_shards":     totalShards,
+		"streams_at_risk": len(atRisk),
+		"at_risk":         atRisk,
+		"metrics_window":  fmt.Sprintf("last %d minutes", int(aws.KinesisMetricsWindow.Minutes())),
+		"streams":         items,
+	}
+}
+
+// formatKinesisStream formats one stream with its metrics, the issues they
+// point to and, for provisioned streams short of capacity, a shard count
+func formatKinesisStream(stream types.KinesisStream) map[string]interface{} {
+	item := map[string]interface{}{
+		"name":        stream.Name,
+		"status":      stream.Status,
+		"mode":        stream.Mode,
+		"open_shards": stream.OpenShards,
+		"uri":         kinesisStreamsURI + "/" + stream.Name,
+	}
+	if stream.Metrics == nil {
+		item["metrics"] = "unavailable"
+		return item
+	}
+
+	m := stream.Metrics
+	item["iterator_age_seconds"] = int64(m.IteratorAgeMs / 1000)
+	item["incoming_records"] = int64(m.IncomingRecords)
+	item["incoming_bytes"] = int64(m.IncomingBytes)
+	item["write_throttled"] = int64(m.WriteThrottled)
+	item["read_throttled"] = int64(m.ReadThrottled)
+	if stream.Mode == "PROVISIONED" && stream.OpenShards > 0 {
+		item["peak_write_utilization_percent"] = math.Round(kinesisWriteUtilization(stream) * 100)
+		if suggested := suggestedShardCount(stream); suggested > stream.OpenShards {
+			item["suggested_shard_count"] = suggested
+		}
+	}
+	if issues := kinesisStreamIssues(stream); len(issues) > 0 {
+		item["issues"] = issues
+	}
+	return item
+}
+
+// kinesisWriteUtilization is the peak one-minute write rate as a share of the
+// stream's write capacity, whichever of bytes and records is closer to it
+func kinesisWriteUtilization(stream types.KinesisStream) float64 {
+	if stream.Metrics == nil || stream.OpenShards == 0 {
+		return 0
+	}
+	shards := float64(stream.OpenShards)
+	return max(stream.Metrics.PeakBytesPerSecond/(shards*kinesisShardBytesPerSecond),
+		stream.Metrics.PeakRecordsPerSecond/(shards*kinesisShardRecordsPerSecond))
+}
+
+// suggestedShardCount returns the shard count that keeps the peak write rate
+// at the target utilization, at most double the current count since that is
+// the most one update can scale. Throttled writes at a low average rate point
+// to a hot shard, which more shards only help by chance, so they do not raise
+// the suggestion on their own.
+func suggestedShardCount(stream types.KinesisStream) int32 {
+	if stream.Mode != "PROVISIONED" || stream.OpenShards == 0 {
+		return stream.OpenShards
+	}
+	needed := int32(math.Ceil(kinesisWriteUtilization(stream) * float64(stream.OpenShards) / kinesisTargetUtilization))
+	return min(max(needed, stream.OpenShards), 2*stream.OpenShards)
+}
+
+// kinesisStreamIssues lists why a stream needs attention: throttled writes or
+// reads, consumers falling behind, or writes close to the shard capacity
+func kinesisStreamIssues(stream types.KinesisStream) []string {
+	m := stream.Metrics
+	if m == nil {
+		return nil
+	}
+
+	var issues []string
+	if m.WriteThrottled > 0 {
+		issue := fmt.Sprintf("%d writes were throttled in the last %d minutes", int64(m.WriteThrottled), m.WindowMinutes)
+		if kinesisWriteUtilization(stream) < kinesisTargetUtilization {
+			issue += "; the stream is below capacity overall, so a hot partition key may overload one shard"
+		}
+		issues = append(issues, issue)
+	}
+	if m.ReadThrottled > 0 {
+		issues = append(issues, fmt.Sprintf("%d reads were throttled; consumers exceed 5 reads or 2 MB per second per shard, consider enhanced fan-out",
+			int64(m.ReadThrottled)))
+	}
+	if age := time.Duration(m.IteratorAgeMs) * time.Millisecond; age >= kinesisIteratorAgeWarning {
+		issue := fmt.Sprintf("consumers are %s behind", age.Round(time.Second))
+		if retention := time.Duration(stream.RetentionHours) * time.Hour; retention > 0 && age >= retention/2 {
+			issue += fmt.Sprintf(" and records expire after %s", retention)
+		}
+		issues = append(issues, issue)
+	}
+	if stream.Mode == "PROVISIONED" && m.WriteThrottled == 0 && kinesisWriteUtilization(stream) >= kinesisTargetUtilization*1.2 {
+		issues = append(issues, fmt.Sprintf("writes peaked at %.0f%% of the shard capacity", kinesisWriteUtilization(stream)*100))
+	}
+	return issues
+}
+
+// updateShardCount scales a provisioned stream to a new number of shards,
+// e.g. when writes are throttled during a traffic spike. Kinesis allows at
+// most doubling or halving the shards in one update.
+func (h *ToolHandler) updateShardCount(ctx context.Context, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	name, _ := arguments["stream"].(string)
+	if name == "" {
+		return h.createErrorResponse("stream is required")
+	}
+	value, ok := arguments["targetShardCount"].(float64)
+	if !ok || value < 1 || value != math.Trunc(value) {
+		return h.createErrorResponse("targetShardCount must be a positive whole number")
+	}
+	target := int32(value)
+
+	stream, err := h.awsClient.GetKinesisStream(ctx, name)
+	if err != nil {
+		return h.createErrorResponse(fmt.Sprintf("failed to get Kinesis stream: %v", err))
+	}
+	if err := validateShardCount(*stream, target); err != nil {
+		return h.createErrorResponse(err.Error())
+	}
+
+	previous, err := h.awsClient.UpdateShardCount(ctx, stream.Name, target)
+	if err != nil {
+		return h.createErrorResponse(fmt.Sprintf("failed to update shard count: %v", err))
+	}
+
+	return h.createSuccessResponse("Shard count update started successfully", map[string]interface{}{
+		"stream":           stream.Name,
+		"previousShards":   previous,
+		"targetShardCount": target,
+		"note": fmt.Sprintf("Resharding takes a few minutes per shard and the stream stays writable; read %s/%s until it is ACTIVE again",
+			kinesisStreamsURI, stream.Name),
+	})
+}
+
+// validateShardCount checks a shard count update against the stream's mode,
+// status and the scaling limits of one update
+func validateShardCount(stream types.KinesisStream, target int32) error {
+	switch {
+	case stream.Mode != "PROVISIONED":
+		return fmt.Errorf("stream %s is %s and scales by itself; only provisioned streams have a shard count", stream.Name, stream.Mode)
+	case stream.Status != "ACTIVE":
+		return fmt.Errorf("stream %s is %s; wait until it is ACTIVE, a previous update may still be resharding", stream.Name, stream.Status)
+	case target == stream.OpenShards:
+		return fmt.Errorf("stream %s already has %d %s", stream.Name, target, plural(int(target), "shard", "shards"))
+	case target > 2*stream.OpenShards:
+		return fmt.Errorf("stream %s has %d shards and can be scaled to at most %d in one update", stream.Name, stream.OpenShards, 2*stream.OpenShards)
+	case target < (stream.OpenShards+1)/2:
+		return fmt.Errorf("stream %s has %d shards and can be scaled to no fewer than %d in one update", stream.Name, stream.OpenShards, (stream.OpenShards+1)/2)
+	}
+	return nil
+}
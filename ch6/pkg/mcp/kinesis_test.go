@@ -0,0 +1,92 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	"aws-mcp-server/internal/config"
+	"aws-mcp-server/internal/logging"
+	"aws-mcp-server/pkg/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatKinesisStreams(t *testing.T) {
+	data := formatKinesisStreams([]types.KinesisStream{
+		{Name: "quiet", Status: "ACTIVE", Mode: "PROVISIONED", OpenShards: 8,
+			Metrics: &types.KinesisStreamMetrics{WindowMinutes: 15, PeakBytesPerSecond: 1 << 20}},
+		{Name: "busy", Status: "ACTIVE", Mode: "PROVISIONED", OpenShards: 2,
+			Metrics: &types.KinesisStreamMetrics{WindowMinutes: 15, PeakBytesPerSecond: 1.8 * (1 << 20), WriteThrottled: 40}},
+		{Name: "lagging", Status: "ACTIVE", Mode: "ON_DEMAND", OpenShards: 4, RetentionHours: 24,
+			Metrics: &types.KinesisStreamMetrics{WindowMinutes: 15, IteratorAgeMs: 5 * 60 * 1000}},
+		{Name: "unknown", Status: "ACTIVE", Mode: "PROVISIONED", OpenShards: 1},
+	})
+
+	assert.Equal(t, 4, data["total"])
+	assert.Equal(t, int32(15), data["open_shards"])
+	assert.Equal(t, []string{"busy", "lagging"}, data["at_risk"])
+
+	streams := data["streams"].([]map[string]interface{})
+	require.Len(t, streams, 4)
+	assert.Equal(t, "lagging", streams[0]["name"], "streams with issues come first, by shard count")
+	assert.Equal(t, "busy", streams[1]["name"])
+	assert.Equal(t, int32(3), streams[1]["suggested_shard_count"], "a 90% peak on 2 shards needs 3 to stay at 70%")
+	assert.Equal(t, []string{"consumers are 5m0s behind"}, streams[0]["issues"])
+	assert.NotContains(t, streams[0], "suggested_shard_count", "on-demand streams scale by themselves")
+	assert.Equal(t, "quiet", streams[2]["name"])
+	assert.NotContains(t, streams[2], "suggested_shard_count")
+	assert.Equal(t, "unavailable", streams[3]["metrics"])
+}
+
+func TestKinesisStreamIssues(t *testing.T) {
+	hotShard := types.KinesisStream{Mode: "PROVISIONED", OpenShards: 10,
+		Metrics: &types.KinesisStreamMetrics{WindowMinutes: 15, PeakRecordsPerSecond: 2000, WriteThrottled: 12}}
+	issues := kinesisStreamIssues(hotShard)
+	require.Len(t, issues, 1)
+	assert.Contains(t, issues[0], "12 writes were throttled")
+	assert.Contains(t, issues[0], "hot partition key")
+	assert.Equal(t, int32(10), suggestedShardCount(hotShard), "throttling below capacity does not call for more shards")
+
+	nearCapacity := types.KinesisStream{Mode: "PROVISIONED", OpenShards: 4,
+		Metrics: &types.KinesisStreamMetrics{WindowMinutes: 15, PeakRecordsPerSecond: 3600}}
+	assert.Equal(t, []string{"writes peaked at 90% of the shard capacity"}, kinesisStreamIssues(nearCapacity))
+	assert.Equal(t, int32(6), suggestedShardCount(nearCapacity))
+
+	expiring := types.KinesisStream{Mode: "PROVISIONED", OpenShards: 1, RetentionHours: 24,
+		Metrics: &types.KinesisStreamMetrics{IteratorAgeMs: 13 * 3600 * 1000, ReadThrottled: 3}}
+	issues = kinesisStreamIssues(expiring)
+	require.Len(t, issues, 2)
+	assert.Contains(t, issues[0], "enhanced fan-out")
+	assert.Equal(t, "consumers are 13h0m0s behind and records expire after 24h0m0s", issues[1])
+}
+
+func TestValidateShardCount(t *testing.T) {
+	stream := types.KinesisStream{Name: "orders", Status: "ACTIVE", Mode: "PROVISIONED", OpenShards: 5}
+
+	assert.NoError(t, validateShardCount(stream, 10))
+	assert.NoError(t, validateShardCount(stream, 3))
+	assert.ErrorContains(t, validateShardCount(stream, 11), "at most 10")
+	assert.ErrorContains(t, validateShardCount(stream, 2), "no fewer than 3")
+	assert.ErrorContains(t, validateShardCount(stream, 5), "already has 5 shards")
+
+	updating := stream
+	updating.Status = "UPDATING"
+	assert.ErrorContains(t, validateShardCount(updating, 6), "wait until it is ACTIVE")
+
+	onDemand := stream
+	onDemand.Mode = "ON_DEMAND"
+	assert.ErrorContains(t, validateShardCount(onDemand, 6), "scales by itself")
+}
+
+func TestUpdateShardCountValidatesArguments(t *testing.T) {
+	h := NewToolHandler(&config.Config{}, nil, logging.NewLogger("error", "text"))
+
+	result, err := h.CallTool(context.Background(), "update-shard-count", map[string]interface{}{})
+	require.NoError(t, err)
+	assert.Equal(t, "stream is required", decodeToolResult(t, result)["error"])
+
+	result, err = h.CallTool(context.Background(), "update-shard-count", map[string]interface{}{"stream": "orders", "targetShardCount": 2.5})
+	require.NoError(t, err)
+	assert.Equal(t, "targetShardCount must be a positive whole number", decodeToolResult(t, result)["error"])
+}
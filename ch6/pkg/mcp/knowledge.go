@@ -0,0 +1,122 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+
+	"aws-mcp-server/pkg/knowledge"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const (
+	// remediationsURI lists verified remediation outcomes by action
+	remediationsURI = "aws://knowledge/remediations"
+	// remediationsTemplate narrows the outcomes to signals similar to one
+	remediationsTemplate = "aws://knowledge/remediations{?signal}"
+	// maxRecentOutcomes is how many individual outcomes are listed
+	maxRecentOutcomes = 10
+)
+
+// readRemediations returns what was done about a kind of signal before and how
+// often each action worked, so the AI can prefer what worked in this environment
+func (h *ResourceHandler) readRemediations(ctx context.Context, uri string) (*mcp.ReadResourceResult, error) {
+	parsed, err := url.Parse(uri)
+	if err != nil {
+		return nil, fmt.Errorf("invalid remediations URI: %w", err)
+	}
+	signal := knowledge.Normalize(parsed.Query().Get("signal"))
+
+	jsonData, err := json.MarshalIndent(h.formatRemediations(signal, h.outcomes.Find(signal)), "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal remediations data: %w", err)
+	}
+
+	return &mcp.ReadResourceResult{
+		Contents: []mcp.ResourceContents{
+			&mcp.TextResourceContents{
+				URI:      uri,
+				MIMEType: "application/json",
+				Text:     string(jsonData),
+			},
+		},
+	}, nil
+}
+
+// formatRemediations summarizes outcomes, newest first, by action with the
+// latest outcomes for detail
+func (h *ResourceHandler) formatRemediations(signal string, outcomes []knowledge.Outcome) map[string]interface{} {
+	summaries := knowledge.Summarize(outcomes)
+	actions := make([]map[string]interface{}, 0, len(summaries))
+	for _, summary := range summaries {
+		actions = append(actions, map[string]interface{}{
+			"action":       summary.Action,
+			"attempts":     summary.Attempts,
+			"resolved":     summary.Resolved,
+			"flapping":     summary.Flapping,
+			"not_resolved": summary.NotResolved,
+			"inconclusive": summary.Inconclusive,
+			"success_rate": summary.SuccessRate(),
+			"last_verdict": summary.LastVerdict,
+			"last_used":    h.times.Format(summary.LastTime),
+		})
+	}
+
+	recent := make([]map[string]interface{}, 0, min(len(outcomes), maxRecentOutcomes))
+	signals := make(map[string]bool)
+	for i, outcome := range outcomes {
+		signals[outcome.Signal] = true
+		if i >= maxRecentOutcomes {
+			continue
+		}
+		item := map[string]interface{}{
+			"time":    h.times.Format(outcome.Time),
+			"signal":  outcome.Signal,
+			"subject": outcome.Subject,
+			"verdict": outcome.Verdict,
+		}
+		for key, value := range map[string]string{
+			"action":      outcome.Action,
+			"remediation": outcome.Remediation,
+			"resource":    outcome.Resource,
+			"summary":     outcome.Summary,
+		} {
+			if value != "" {
+				item[key] = value
+			}
+		}
+		recent = append(recent, item)
+	}
+
+	data := map[string]interface{}{
+		"total_outcomes": len(outcomes),
+		"actions":        actions,
+		"recent":         recent,
+	}
+	if signal != "" {
+		data["signal"] = signal
+		if len(signals) > 1 || (len(signals) == 1 && !signals[signal]) {
+			matched := make([]string, 0, len(signals))
+			for name := range signals {
+				matched = append(matched, name)
+			}
+			sort.Strings(matched)
+			data["similar_signals"] = matched
+		}
+	}
+
+	switch {
+	case len(outcomes) == 0 && signal != "":
+		data["note"] = fmt.Sprintf("No verified remediations of %s yet; outcomes are recorded by verify-remediation", signal)
+	case len(outcomes) == 0:
+		data["note"] = "No verified remediations yet; outcomes are recorded by verify-remediation"
+	case summaries[0].Resolved > 0:
+		best := summaries[0]
+		data["recommendation"] = fmt.Sprintf("%s worked best: it resolved the signal in %d of %d verified attempts",
+			best.Action, best.Resolved, best.Attempts-best.Inconclusive)
+	}
+	return data
+}
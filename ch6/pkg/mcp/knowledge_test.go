@@ -0,0 +1,61 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"aws-mcp-server/internal/config"
+	"aws-mcp-server/internal/logging"
+	"aws-mcp-server/pkg/audit"
+	"aws-mcp-server/pkg/knowledge"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadRemediations(t *testing.T) {
+	outcomes, err := knowledge.Open("", 0)
+	require.NoError(t, err)
+	base := time.Date(2025, 6, 2, 10, 0, 0, 0, time.UTC)
+	require.NoError(t, outcomes.Record(knowledge.Outcome{Time: base, Signal: "high-cpu", Subject: "alarm web-cpu", Action: "reboot-ec2-instance", Verdict: knowledge.VerdictNotResolved}))
+	require.NoError(t, outcomes.Record(knowledge.Outcome{Time: base.Add(time.Hour), Signal: "high-cpu-credits", Subject: "alarm api-credits", Action: "update-ecs-service", Verdict: knowledge.VerdictResolved}))
+	require.NoError(t, outcomes.Record(knowledge.Outcome{Time: base.Add(2 * time.Hour), Signal: "low-storage", Subject: "alarm db-disk", Action: "modify-ebs-volume", Verdict: knowledge.VerdictResolved}))
+
+	h := NewResourceHandler(&config.Config{}, nil)
+	h.outcomes = outcomes
+
+	result, err := h.ReadResource(context.Background(), "aws://knowledge/remediations?signal=high-cpu")
+	require.NoError(t, err)
+	require.NotEmpty(t, result.Contents)
+
+	var data map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(result.Contents[0].(*mcp.TextResourceContents).Text), &data))
+	assert.Equal(t, "high-cpu", data["signal"])
+	assert.Equal(t, float64(2), data["total_outcomes"])
+	assert.Equal(t, []interface{}{"high-cpu", "high-cpu-credits"}, data["similar_signals"])
+	assert.Equal(t, "update-ecs-service worked best: it resolved the signal in 1 of 1 verified attempts", data["recommendation"])
+
+	formatted := h.formatRemediations("memory", outcomes.Find("memory"))
+	assert.Contains(t, formatted["note"], "No verified remediations of memory yet")
+	assert.Equal(t, 3, h.formatRemediations("", outcomes.Find(""))["total_outcomes"])
+}
+
+func TestRecentActionFromAuditLog(t *testing.T) {
+	h := NewToolHandler(&config.Config{}, nil, logging.NewLogger("error", "text"))
+	log, err := audit.Open("", 0)
+	require.NoError(t, err)
+	h.audit = log
+
+	now := time.Now()
+	require.NoError(t, log.Record(audit.Entry{Time: now.Add(-2 * time.Hour), Tool: "stop-ec2-instance", Arguments: map[string]interface{}{"instanceId": "i-0abc"}, Success: true}))
+	require.NoError(t, log.Record(audit.Entry{Time: now.Add(-20 * time.Minute), Tool: "start-ec2-instance", Arguments: map[string]interface{}{"instanceId": "i-0abc"}, Success: true}))
+	require.NoError(t, log.Record(audit.Entry{Time: now.Add(-10 * time.Minute), Tool: "terminate-ec2-instance", Arguments: map[string]interface{}{"instanceId": "i-0abc"}}))
+	require.NoError(t, log.Record(audit.Entry{Time: now.Add(-5 * time.Minute), Tool: "stop-ec2-instance", Arguments: map[string]interface{}{"instanceId": "i-0def"}, Success: true}))
+
+	assert.Equal(t, "start-ec2-instance", h.recentAction("i-0abc", now), "failed calls and other resources are skipped")
+	assert.Empty(t, h.recentAction("i-0abc", now.Add(-3*time.Hour)))
+	assert.Empty(t, h.recentAction("", now))
+}
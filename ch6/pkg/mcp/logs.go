@@ -0,0 +1,185 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"aws-mcp-server/pkg/logs"
+	"aws-mcp-server/pkg/summarize"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const (
+	// defaultLogWindow is how far back log tools look when no window is given
+	defaultLogWindow = time.Hour
+	// defaultQueryLogsLimit keeps query-logs responses small enough to read
+	defaultQueryLogsLimit = 100
+	// defaultSummarizeLogsLimit is how many entries summarize-logs groups by default
+	defaultSummarizeLogsLimit = 2000
+	// defaultLogPatterns is how many patterns summarize-logs returns by default
+	defaultLogPatterns = 10
+	// defaultMaxLogEntries applies when logs.max_entries is not configured
+	defaultMaxLogEntries = 5000
+)
+
+// logQuery builds a backend query from tool arguments. The window is either
+// start/end or the last "since" (default one hour).
+func (h *ToolHandler) logQuery(arguments map[string]interface{}, defaultLimit int) (logs.Query, error) {
+	now := time.Now()
+
+	query := logs.Query{
+		Source: h.config.Logs.DefaultSource,
+		Limit:  defaultLimit,
+	}
+	if source, ok := arguments["source"].(string); ok && source != "" {
+		query.Source = source
+	}
+	if query.Source == "" {
+		return logs.Query{}, fmt.Errorf("source is required (a log group, Loki stream selector or index pattern) because logs.default_source is not configured")
+	}
+	query.Filter, _ = arguments["filter"].(string)
+
+	if limit, ok := arguments["limit"].(float64); ok && limit > 0 {
+		query.Limit = int(limit)
+	}
+	maxEntries := h.config.Logs.MaxEntries
+	if maxEntries <= 0 {
+		maxEntries = defaultMaxLogEntries
+	}
+	if query.Limit > maxEntries {
+		query.Limit = maxEntries
+	}
+
+	var err error
+	if query.Start, query.End, err = parseWindowArgs(arguments, now, defaultLogWindow); err != nil {
+		return logs.Query{}, err
+	}
+
+	return query, nil
+}
+
+// queryLogs returns matching log entries in chronological order
+func (h *ToolHandler) queryLogs(ctx context.Context, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	if h.logs == nil {
+		return h.createErrorResponse("no log backend is configured; check the logs section of the configuration")
+	}
+
+	query, err := h.logQuery(arguments, defaultQueryLogsLimit)
+	if err != nil {
+		return h.createErrorResponse(err.Error())
+	}
+
+	entries, err := h.logs.Query(ctx, query)
+	if err != nil {
+		return h.createErrorResponse(fmt.Sprintf("failed to query logs: %v", err))
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Timestamp.Before(entries[j].Timestamp)
+	})
+
+	formatted := make([]map[string]interface{}, 0, len(entries))
+	for _, entry := range entries {
+		item := map[string]interface{}{
+			"timestamp": h.times.Format(entry.Timestamp),
+			"message":   entry.Message,
+		}
+		if entry.Level != "" {
+			item["level"] = entry.Level
+		}
+		if entry.Stream != "" {
+			item["stream"] = entry.Stream
+		}
+		formatted = append(formatted, item)
+	}
+
+	data := h.logWindow(query)
+	data["count"] = len(entries)
+	data["entries"] = formatted
+	if len(entries) >= query.Limit {
+		data["truncated"] = true
+		data["note"] = "The limit was reached; narrow the window or filter, or use summarize-logs for an overview"
+	}
+
+	return h.createSuccessResponse(fmt.Sprintf("Found %d log entries", len(entries)), data)
+}
+
+// summarizeLogs groups log entries into recurring patterns with level counts
+func (h *ToolHandler) summarizeLogs(ctx context.Context, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	if h.logs == nil {
+		return h.createErrorResponse("no log backend is configured; check the logs section of the configuration")
+	}
+
+	query, err := h.logQuery(arguments, defaultSummarizeLogsLimit)
+	if err != nil {
+		return h.createErrorResponse(err.Error())
+	}
+
+	top := defaultLogPatterns
+	if value, ok := arguments["top"].(float64); ok && value > 0 {
+		top = int(value)
+	}
+
+	entries, err := h.logs.Query(ctx, query)
+	if err != nil {
+		return h.createErrorResponse(fmt.Sprintf("failed to query logs: %v", err))
+	}
+
+	summary := logs.Summarize(entries, top)
+
+	data := h.logWindow(query)
+	data["total_entries"] = summary.Total
+	data["levels"] = summary.Levels
+	data["distinct_patterns"] = summary.DistinctCount
+	data["top_patterns"] = h.formatLogPatterns(summary.Patterns)
+	data["top_error_patterns"] = h.formatLogPatterns(summary.ErrorPatterns)
+	if summary.Total > 0 {
+		data["first_entry"] = h.times.Format(summary.Start)
+		data["last_entry"] = h.times.Format(summary.End)
+	}
+	if summary.Total >= query.Limit {
+		data["sampled"] = true
+		data["note"] = fmt.Sprintf("Only %d entries were analysed; narrow the window or raise the limit for exact counts", query.Limit)
+	}
+	if narrative, _ := arguments["narrative"].(bool); narrative && summary.Total > 0 {
+		addNarrative(ctx, h.summaries, summarize.KindLogs, logNarrativeInput(query.Source, summary), data)
+	}
+
+	return h.createSuccessResponse(fmt.Sprintf("Summarized %d log entries into %d patterns", summary.Total, summary.DistinctCount), data)
+}
+
+// logWindow describes the backend, source and window of a log query
+func (h *ToolHandler) logWindow(query logs.Query) map[string]interface{} {
+	data := map[string]interface{}{
+		"backend": h.logs.Name(),
+		"source":  query.Source,
+		"start":   h.times.Format(query.Start),
+		"end":     h.times.Format(query.End),
+	}
+	if query.Filter != "" {
+		data["filter"] = query.Filter
+	}
+	return data
+}
+
+// formatLogPatterns renders pattern times in the configured timezone
+func (h *ToolHandler) formatLogPatterns(patterns []logs.Pattern) []map[string]interface{} {
+	formatted := make([]map[string]interface{}, 0, len(patterns))
+	for _, pattern := range patterns {
+		item := map[string]interface{}{
+			"pattern":    pattern.Pattern,
+			"count":      pattern.Count,
+			"example":    pattern.Example,
+			"first_seen": h.times.Format(pattern.FirstSeen),
+			"last_seen":  h.times.Format(pattern.LastSeen),
+		}
+		if pattern.Level != "" {
+			item["level"] = pattern.Level
+		}
+		formatted = append(formatted, item)
+	}
+	return formatted
+}
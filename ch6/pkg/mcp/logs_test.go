@@ -0,0 +1,89 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"aws-mcp-server/internal/config"
+	"aws-mcp-server/internal/logging"
+	"aws-mcp-server/pkg/logs"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeLogBackend records the last query and returns fixed entries
+type fakeLogBackend struct {
+	query   logs.Query
+	entries []logs.Entry
+}
+
+func (f *fakeLogBackend) Name() string { return "fake" }
+
+func (f *fakeLogBackend) Query(ctx context.Context, query logs.Query) ([]logs.Entry, error) {
+	f.query = query
+	return f.entries, nil
+}
+
+func TestLogQuery(t *testing.T) {
+	handler := NewToolHandler(&config.Config{Logs: config.LogsConfig{MaxEntries: 500}}, nil, logging.NewLogger("error", "text"))
+
+	_, err := handler.logQuery(map[string]interface{}{}, 100)
+	assert.ErrorContains(t, err, "source is required")
+
+	query, err := handler.logQuery(map[string]interface{}{"source": "/aws/lambda/api", "since": "15m", "limit": float64(10000)}, 100)
+	require.NoError(t, err)
+	assert.Equal(t, 15*time.Minute, query.End.Sub(query.Start))
+	assert.Equal(t, 500, query.Limit, "limit is capped by logs.max_entries")
+
+	handler.config.Logs.DefaultSource = `{app="api"}`
+	query, err = handler.logQuery(map[string]interface{}{"start": "2025-06-01T10:00:00Z", "end": "2025-06-01T11:00:00Z"}, 100)
+	require.NoError(t, err)
+	assert.Equal(t, `{app="api"}`, query.Source)
+	assert.Equal(t, time.Hour, query.End.Sub(query.Start))
+	assert.Equal(t, 100, query.Limit)
+
+	_, err = handler.logQuery(map[string]interface{}{"since": "yesterday"}, 100)
+	assert.ErrorContains(t, err, "invalid since")
+	_, err = handler.logQuery(map[string]interface{}{"start": "now", "end": "now-1h"}, 100)
+	assert.ErrorContains(t, err, "end must be after start")
+}
+
+func TestQueryAndSummarizeLogs(t *testing.T) {
+	base := time.Now().Add(-10 * time.Minute)
+	backend := &fakeLogBackend{entries: []logs.Entry{
+		{Timestamp: base.Add(2 * time.Minute), Message: "ERROR db timeout after 3000ms", Level: "error"},
+		{Timestamp: base, Message: "ERROR db timeout after 2500ms", Level: "error"},
+		{Timestamp: base.Add(time.Minute), Message: "request served"},
+	}}
+
+	handler := NewToolHandler(&config.Config{}, nil, logging.NewLogger("error", "text"))
+	ctx := context.Background()
+
+	result, err := handler.CallTool(ctx, "query-logs", map[string]interface{}{"source": "app"})
+	require.NoError(t, err)
+	assert.Equal(t, false, decodeToolResult(t, result)["success"])
+
+	handler.logs = backend
+
+	result, err = handler.CallTool(ctx, "query-logs", map[string]interface{}{"source": "app", "filter": "timeout", "limit": float64(3)})
+	require.NoError(t, err)
+	payload := decodeToolResult(t, result)
+	assert.Equal(t, "timeout", backend.query.Filter)
+	assert.Equal(t, "fake", payload["backend"])
+	assert.Equal(t, true, payload["truncated"])
+	entries := payload["entries"].([]interface{})
+	require.Len(t, entries, 3)
+	assert.Equal(t, "ERROR db timeout after 2500ms", entries[0].(map[string]interface{})["message"], "entries are returned oldest first")
+
+	result, err = handler.CallTool(ctx, "summarize-logs", map[string]interface{}{"source": "app"})
+	require.NoError(t, err)
+	payload = decodeToolResult(t, result)
+	assert.Equal(t, defaultSummarizeLogsLimit, backend.query.Limit)
+	assert.Equal(t, float64(2), payload["distinct_patterns"])
+	errorPatterns := payload["top_error_patterns"].([]interface{})
+	require.Len(t, errorPatterns, 1)
+	assert.Equal(t, "ERROR db timeout after <num>", errorPatterns[0].(map[string]interface{})["pattern"])
+	assert.Equal(t, float64(2), errorPatterns[0].(map[string]interface{})["count"])
+}
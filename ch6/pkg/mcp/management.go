@@ -0,0 +1,119 @@
+package mcp
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"aws-mcp-server/internal/notify"
+	"aws-mcp-server/pkg/audit"
+	"aws-mcp-server/pkg/elevation"
+	"aws-mcp-server/pkg/management"
+)
+
+// managementRole is the role management operations are audited under
+const managementRole = "management"
+
+// Server carries out the operations of the management API
+var _ management.Controller = (*Server)(nil)
+
+// Sessions returns the MCP clients connected over any transport
+func (s *Server) Sessions() []management.Session {
+	sessions := s.sessions.all(time.Now())
+	result := make([]management.Session, 0, len(sessions))
+	for _, session := range sessions {
+		result = append(result, management.Session(session))
+	}
+	return result
+}
+
+// Tokens returns the elevation grants in effect
+func (s *Server) Tokens() []elevation.Grant {
+	return s.toolHandler.elevations.All(time.Now())
+}
+
+// RevokeToken ends an elevation grant, so its holder is read-only again, and
+// records it like grants ended by their holder
+func (s *Server) RevokeToken(id, reason string) (elevation.Grant, error) {
+	grant, err := s.toolHandler.elevations.Revoke(id)
+	if err != nil {
+		return elevation.Grant{}, err
+	}
+
+	s.toolHandler.recordElevation(grant, notify.EventElevationEnded,
+		fmt.Sprintf("ELEVATION REVOKED: %s with role %s is read-only again, revoked through the management API: %s", grant.Holder, grant.Role, reason))
+	return grant, nil
+}
+
+// flushers flush the caches of the server by name
+func (s *Server) flushers() map[string]func() {
+	return map[string]func(){
+		"inventory": s.resourceHandler.inventory.Flush,
+		"oncall":    s.resourceHandler.oncall.Flush,
+		"ownership": s.resourceHandler.owners.Flush,
+		"slo":       s.toolHandler.slo.Flush,
+		"summaries": s.toolHandler.summaries.Flush,
+	}
+}
+
+// FlushCaches flushes the named caches, all of them when none are named
+func (s *Server) FlushCaches(caches []string) ([]string, error) {
+	flushers := s.flushers()
+	if len(caches) == 0 {
+		for name := range flushers {
+			caches = append(caches, name)
+		}
+		sort.Strings(caches)
+	}
+
+	for _, name := range caches {
+		if _, ok := flushers[name]; !ok {
+			names := make([]string, 0, len(flushers))
+			for name := range flushers {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			return nil, fmt.Errorf("unknown cache %q, expected one of %s", name, strings.Join(names, ", "))
+		}
+	}
+	for _, name := range caches {
+		flushers[name]()
+	}
+
+	s.auditManagement("management.flush_caches", map[string]interface{}{"caches": caches},
+		fmt.Sprintf("Flushed caches: %s", strings.Join(caches, ", ")))
+	return caches, nil
+}
+
+// Overrides returns the settings overridden at runtime
+func (s *Server) Overrides() map[string]string {
+	return s.config.Overrides.All()
+}
+
+// SetOverrides sets and clears overrides of settings read on every request
+func (s *Server) SetOverrides(set map[string]string, clear []string) error {
+	if err := s.config.Overrides.Apply(set, clear); err != nil {
+		return err
+	}
+
+	arguments := map[string]interface{}{"set": set, "clear": clear}
+	s.auditManagement("management.set_config_overrides", arguments, "Changed configuration overrides")
+	return nil
+}
+
+// auditManagement records a management operation in the audit log
+func (s *Server) auditManagement(operation string, arguments map[string]interface{}, message string) {
+	s.logger.WithField("operation", operation).Warn(message)
+
+	err := s.toolHandler.audit.Record(audit.Entry{
+		Tool:      operation,
+		Arguments: arguments,
+		Role:      managementRole,
+		Success:   true,
+		Message:   message,
+	})
+	if err != nil {
+		s.logger.WithError(err).WithField("operation", operation).Error("Failed to write audit entry")
+	}
+}
@@ -0,0 +1,124 @@
+package mcp
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"aws-mcp-server/internal/config"
+	"aws-mcp-server/internal/logging"
+	"aws-mcp-server/pkg/aws"
+	"aws-mcp-server/pkg/fake"
+	"aws-mcp-server/pkg/management"
+	"aws-mcp-server/pkg/websocket"
+
+	"github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newManagedServer(t *testing.T, instances int) *Server {
+	t.Helper()
+	logger := logging.NewLogger("error", "text")
+	fleet := fake.New(config.FakeConfig{Instances: instances, Seed: 3}, "us-east-1")
+	cfg := &config.Config{
+		MCP:      config.MCPConfig{ServerName: "aws-mcp-server", Version: "test", Transport: config.MCPTransportHTTP},
+		Response: config.ResponseConfig{PageSize: 500},
+	}
+	return NewServer(cfg, aws.NewClientFromConfig(fleet.Config(), logger), logger)
+}
+
+func TestManagementSessions(t *testing.T) {
+	s := newManagedServer(t, 3)
+	server := httptest.NewServer(s.HTTPHandler())
+	t.Cleanup(server.Close)
+
+	c, err := client.NewStreamableHttpClient(server.URL + "/mcp")
+	require.NoError(t, err)
+	initializeClient(t, c)
+
+	conn, err := websocket.Dial("ws"+strings.TrimPrefix(server.URL, "http")+"/ws", websocketProtocol, nil)
+	require.NoError(t, err)
+	websocketCall(t, conn, 1, "initialize", map[string]interface{}{
+		"protocolVersion": "2025-03-26",
+		"capabilities":    map[string]interface{}{},
+		"clientInfo":      map[string]interface{}{"name": "browser", "version": "1.0.0"},
+	})
+	websocketCall(t, conn, 2, "ping", map[string]interface{}{})
+
+	byTransport := make(map[string]management.Session)
+	for _, session := range s.Sessions() {
+		byTransport[session.Transport] = session
+	}
+	require.Len(t, byTransport, 2)
+	assert.Equal(t, "http-test", byTransport[transportStreamableHTTP].ClientName)
+	assert.NotEmpty(t, byTransport[transportStreamableHTTP].RemoteAddress)
+	ws := byTransport[transportWebSocket]
+	assert.Equal(t, "browser", ws.ClientName)
+	assert.Equal(t, "1.0.0", ws.ClientVersion)
+	assert.Equal(t, int64(2), ws.Requests)
+
+	conn.Close()
+	assert.Eventually(t, func() bool { return len(s.Sessions()) == 1 }, 5*time.Second, 10*time.Millisecond,
+		"sessions end when their connection closes")
+}
+
+func TestManagementRevokeToken(t *testing.T) {
+	s := newManagedServer(t, 3)
+	grant := s.toolHandler.elevations.Grant("stdio-1", "viewer", "restart workers", "admin", time.Now(), time.Hour)
+	require.Len(t, s.Tokens(), 1)
+
+	revoked, err := s.RevokeToken(grant.ID, "incident closed")
+	require.NoError(t, err)
+	assert.Equal(t, "viewer", revoked.Role)
+	assert.Empty(t, s.Tokens())
+	_, err = s.RevokeToken(grant.ID, "incident closed")
+	assert.Error(t, err)
+
+	entries := s.toolHandler.audit.Between(time.Now().Add(-time.Minute), time.Now())
+	require.NotEmpty(t, entries)
+	last := entries[len(entries)-1]
+	assert.Equal(t, "elevation.ended", last.Tool)
+	assert.Equal(t, grant.ID, last.Elevation)
+	assert.Contains(t, last.Message, "ELEVATION REVOKED: stdio-1 with role viewer is read-only again")
+	assert.Contains(t, last.Message, "incident closed")
+}
+
+func TestManagementFlushCaches(t *testing.T) {
+	s := newManagedServer(t, 3)
+
+	flushed, err := s.FlushCaches(nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"inventory", "oncall", "ownership", "slo", "summaries"}, flushed, "unconfigured caches flush as no-ops")
+
+	_, err = s.FlushCaches([]string{"inventory", "dns"})
+	assert.ErrorContains(t, err, `unknown cache "dns"`)
+
+	entries := s.toolHandler.audit.Between(time.Now().Add(-time.Minute), time.Now())
+	require.Len(t, entries, 1, "failed flushes are not audited")
+	assert.Equal(t, managementRole, entries[0].Role)
+}
+
+func TestManagementOverridesApplyToNextRequest(t *testing.T) {
+	s := newManagedServer(t, 10)
+	read := func(uri string) instanceList {
+		return readInstancePage(t, func(uri string) (*mcp.ReadResourceResult, error) {
+			return s.resourceHandler.ReadResource(context.Background(), uri)
+		}, uri)
+	}
+	assert.Len(t, read("aws://ec2/instances").Instances, 10)
+
+	require.NoError(t, s.SetOverrides(map[string]string{"response.page_size": "6"}, nil))
+	assert.Equal(t, map[string]string{"response.page_size": "6"}, s.Overrides())
+	list := read("aws://ec2/instances")
+	assert.Len(t, list.Instances, 6)
+	assert.NotEmpty(t, list.NextCursor)
+
+	assert.Error(t, s.SetOverrides(map[string]string{"response.page_size": "many"}, nil))
+	require.NoError(t, s.SetOverrides(nil, []string{"response.page_size"}))
+	assert.Empty(t, s.Overrides())
+	assert.Len(t, read("aws://ec2/instances").Instances, 10)
+}
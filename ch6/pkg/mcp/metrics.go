@@ -0,0 +1,158 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"aws-mcp-server/pkg/metrics"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const (
+	// metricsQueryTemplate is the registered template for provider metric queries
+	metricsQueryTemplate = "metrics://query{+params}"
+	// defaultMetricsWindow is how far back a metrics query looks without a start
+	defaultMetricsWindow = time.Hour
+	// defaultMetricsMaxSeries applies when metrics.max_series is not configured
+	defaultMetricsMaxSeries = 50
+)
+
+// parseMetricsQuery parses metrics://query?q=...[&start=...][&end=...][&step=...].
+// The window defaults to the last hour.
+func parseMetricsQuery(uri string, now time.Time) (metrics.Query, error) {
+	parsed, err := url.Parse(uri)
+	if err != nil {
+		return metrics.Query{}, fmt.Errorf("invalid metrics URI: %w", err)
+	}
+
+	params := parsed.Query()
+	query := metrics.Query{Expr: strings.TrimSpace(params.Get("q"))}
+	if query.Expr == "" {
+		return metrics.Query{}, fmt.Errorf("q is required, e.g. metrics://query?q=avg:system.cpu.user{*}")
+	}
+
+	if query.End, err = parseTimeParam(params.Get("end"), now); err != nil {
+		return metrics.Query{}, fmt.Errorf("invalid end: %w", err)
+	}
+	if start := params.Get("start"); start != "" {
+		if query.Start, err = parseTimeParam(start, now); err != nil {
+			return metrics.Query{}, fmt.Errorf("invalid start: %w", err)
+		}
+	} else {
+		query.Start = query.End.Add(-defaultMetricsWindow)
+	}
+	if !query.End.After(query.Start) {
+		return metrics.Query{}, fmt.Errorf("end must be after start")
+	}
+
+	if step := params.Get("step"); step != "" {
+		query.Step, err = parseDurationParam(step)
+		if err != nil || query.Step <= 0 {
+			return metrics.Query{}, fmt.Errorf("invalid step %q", step)
+		}
+	}
+
+	return query, nil
+}
+
+// readMetricsQuery runs a query against the configured metrics provider
+func (h *ResourceHandler) readMetricsQuery(ctx context.Context, uri string) (*mcp.ReadResourceResult, error) {
+	if h.metrics == nil {
+		return nil, fmt.Errorf("no metrics provider is configured; set metrics.provider to enable metrics:// resources")
+	}
+
+	query, err := parseMetricsQuery(uri, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	series, err := h.metrics.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	maxSeries := h.config.Metrics.MaxSeries
+	if maxSeries <= 0 {
+		maxSeries = defaultMetricsMaxSeries
+	}
+	formatted := h.formatMetricSeries(query, series, maxSeries)
+
+	jsonData, err := json.MarshalIndent(formatted, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal metrics result: %w", err)
+	}
+
+	return &mcp.ReadResourceResult{
+		Contents: []mcp.ResourceContents{
+			&mcp.TextResourceContents{
+				URI:      uri,
+				MIMEType: "application/json",
+				Text:     string(jsonData),
+			},
+		},
+	}, nil
+}
+
+// formatMetricSeries shapes provider series the same way as PromQL results:
+// ordered by latest value, capped at maxSeries and summarized with min, max,
+// avg and last
+func (h *ResourceHandler) formatMetricSeries(query metrics.Query, series []metrics.Series, maxSeries int) map[string]interface{} {
+	formatted := map[string]interface{}{
+		"provider":     h.metrics.Name(),
+		"query":        query.Expr,
+		"series_count": len(series),
+		"range": map[string]interface{}{
+			"start": h.times.Format(query.Start),
+			"end":   h.times.Format(query.End),
+		},
+	}
+
+	series = append([]metrics.Series(nil), series...)
+	sort.SliceStable(series, func(i, j int) bool {
+		return latest(metricValues(series[i].Points)) > latest(metricValues(series[j].Points))
+	})
+	if len(series) > maxSeries {
+		series = series[:maxSeries]
+		formatted["truncated"] = true
+		formatted["note"] = fmt.Sprintf("Showing the %d series with the highest latest value; aggregate or filter the query to narrow the result", maxSeries)
+	}
+
+	items := make([]map[string]interface{}, 0, len(series))
+	for _, s := range series {
+		item := map[string]interface{}{
+			"name": s.Name,
+		}
+		if len(s.Labels) > 0 {
+			item["labels"] = s.Labels
+		}
+
+		points := make([][2]interface{}, 0, len(s.Points))
+		for _, p := range s.Points {
+			points = append(points, [2]interface{}{h.times.Format(p.Time), promValue(p.Value)})
+		}
+		item["points"] = points
+		for key, value := range pointStats(metricValues(s.Points)) {
+			item[key] = value
+		}
+
+		items = append(items, item)
+	}
+	formatted["series"] = items
+
+	return formatted
+}
+
+// metricValues returns the sample values of a series
+func metricValues(points []metrics.Point) []float64 {
+	values := make([]float64, len(points))
+	for i, p := range points {
+		values[i] = p.Value
+	}
+	return values
+}
@@ -0,0 +1,84 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"aws-mcp-server/internal/config"
+	"aws-mcp-server/pkg/metrics"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeMetricsProvider records the last query and returns fixed series
+type fakeMetricsProvider struct {
+	query  metrics.Query
+	series []metrics.Series
+}
+
+func (f *fakeMetricsProvider) Name() string { return "fake" }
+
+func (f *fakeMetricsProvider) Query(ctx context.Context, query metrics.Query) ([]metrics.Series, error) {
+	f.query = query
+	return f.series, nil
+}
+
+func TestParseMetricsQuery(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	query, err := parseMetricsQuery("metrics://query?q=avg:system.cpu.user{env:prod}%20by%20{host}", now)
+	require.NoError(t, err)
+	assert.Equal(t, "avg:system.cpu.user{env:prod} by {host}", query.Expr)
+	assert.Equal(t, now.Add(-time.Hour), query.Start)
+	assert.Equal(t, now, query.End)
+	assert.Zero(t, query.Step)
+
+	query, err = parseMetricsQuery("metrics://query?q=SELECT%20count(*)%20FROM%20Transaction&start=now-1d&step=1h", now)
+	require.NoError(t, err)
+	assert.Equal(t, now.Add(-24*time.Hour), query.Start)
+	assert.Equal(t, time.Hour, query.Step)
+
+	_, err = parseMetricsQuery("metrics://query?start=-1h", now)
+	assert.ErrorContains(t, err, "q is required")
+	_, err = parseMetricsQuery("metrics://query?q=x&start=now&end=now-1h", now)
+	assert.ErrorContains(t, err, "end must be after start")
+}
+
+func TestReadMetricsQuery(t *testing.T) {
+	base := time.Now().Add(-time.Hour)
+	provider := &fakeMetricsProvider{series: []metrics.Series{
+		{Name: "cpu", Labels: map[string]string{"host": "low"}, Points: []metrics.Point{{Time: base, Value: 5}}},
+		{Name: "cpu", Labels: map[string]string{"host": "high"}, Points: []metrics.Point{{Time: base, Value: 10}, {Time: base.Add(time.Minute), Value: 90}}},
+		{Name: "cpu", Labels: map[string]string{"host": "mid"}, Points: []metrics.Point{{Time: base, Value: 50}}},
+	}}
+
+	handler := NewResourceHandler(&config.Config{Metrics: config.MetricsConfig{MaxSeries: 2}}, nil)
+
+	_, err := handler.ReadResource(context.Background(), "metrics://query?q=cpu")
+	assert.ErrorContains(t, err, "no metrics provider")
+
+	handler.metrics = provider
+	result, err := handler.ReadResource(context.Background(), "metrics://query?q=cpu&step=5m")
+	require.NoError(t, err)
+	assert.Equal(t, 5*time.Minute, provider.query.Step)
+
+	contents, ok := result.Contents[0].(*mcp.TextResourceContents)
+	require.True(t, ok)
+
+	var payload map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(contents.Text), &payload))
+	assert.Equal(t, "fake", payload["provider"])
+	assert.Equal(t, float64(3), payload["series_count"])
+	assert.Equal(t, true, payload["truncated"])
+
+	series := payload["series"].([]interface{})
+	require.Len(t, series, 2)
+	first := series[0].(map[string]interface{})
+	assert.Equal(t, "high", first["labels"].(map[string]interface{})["host"])
+	assert.Equal(t, 50.0, first["avg"])
+	assert.Equal(t, 90.0, first["last"])
+}
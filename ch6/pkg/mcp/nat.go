@@ -0,0 +1,247 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"aws-mcp-server/pkg/aws"
+	"aws-mcp-server/pkg/types"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const (
+	// natGatewaysURI lists the NAT gateways of the region with their metrics
+	natGatewaysURI = "aws://vpc/nat-gateways"
+	// vpcEndpointsURI lists the VPC endpoints of the region
+	vpcEndpointsURI = "aws://vpc/endpoints"
+	// natPacketsDroppedWarning is the number of dropped packets over the
+	// metric window from which a NAT gateway is reported
+	natPacketsDroppedWarning = 1000
+)
+
+// gatewayEndpointServices are the services reachable through free gateway
+// endpoints; without one their traffic from private subnets goes through the
+// NAT gateway and is billed for data processing
+var gatewayEndpointServices = []string{"dynamodb", "s3"}
+
+// readNATGateways lists the NAT gateways, the troubled ones first, with their
+// traffic and port allocation errors over the metric window
+func (h *ResourceHandler) readNATGateways(ctx context.Context) (*mcp.ReadResourceResult, error) {
+	gateways, err := h.awsClient.ListNATGateways(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	jsonData, err := json.MarshalIndent(formatNATGateways(gateways), "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal NAT gateways data: %w", err)
+	}
+
+	return &mcp.ReadResourceResult{
+		Contents: []mcp.ResourceContents{
+			&mcp.TextResourceContents{
+				URI:      natGatewaysURI,
+				MIMEType: "application/json",
+				Text:     string(jsonData),
+			},
+		},
+	}, nil
+}
+
+// readVPCEndpoints lists the VPC endpoints and the VPCs whose S3 or DynamoDB
+// traffic goes through a NAT gateway for lack of a gateway endpoint
+func (h *ResourceHandler) readVPCEndpoints(ctx context.Context) (*mcp.ReadResourceResult, error) {
+	endpoints, err := h.awsClient.ListVPCEndpoints(ctx)
+	if err != nil {
+		return nil, err
+	}
+	topology, err := h.awsClient.GetVPCTopology(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get VPC topology: %w", err)
+	}
+
+	jsonData, err := json.MarshalIndent(formatVPCEndpoints(endpoints, topology.NATGateways), "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal VPC endpoints data: %w", err)
+	}
+
+	return &mcp.ReadResourceResult{
+		Contents: []mcp.ResourceContents{
+			&mcp.TextResourceContents{
+				URI:      vpcEndpointsURI,
+				MIMEType: "application/json",
+				Text:     string(jsonData),
+			},
+		},
+	}, nil
+}
+
+// formatNATGateways orders the NAT gateways troubled ones first, then by the
+// traffic they processed, and counts them by state
+func formatNATGateways(gateways []types.NATGateway) map[string]interface{} {
+	items := make([]map[string]interface{}, 0, len(gateways))
+	byState := make(map[string]int)
+	troubled := []string{}
+	totalGB := 0.0
+	for _, gateway := range gateways {
+		item := formatNATGateway(gateway)
+		if _, ok := item["problems"]; ok {
+			troubled = append(troubled, natGatewayName(gateway))
+		}
+		if gb, ok := item["data_processed_gb"].(float64); ok {
+			totalGB += gb
+		}
+		byState[gateway.State]++
+		items = append(items, item)
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		_, iTroubled := items[i]["problems"]
+		_, jTroubled := items[j]["problems"]
+		if iTroubled != jTroubled {
+			return iTroubled
+		}
+		iGB, _ := items[i]["data_processed_gb"].(float64)
+		jGB, _ := items[j]["data_processed_gb"].(float64)
+		return iGB > jGB
+	})
+	sort.Strings(troubled)
+
+	return map[string]interface{}{
+		"nat_gateways":            items,
+		"total":                   len(gateways),
+		"summary_by_state":        byState,
+		"troubled":                troubled,
+		"window_minutes":          aws.NATMetricsWindow.Minutes(),
+		"total_data_processed_gb": totalGB,
+	}
+}
+
+// formatNATGateway formats one NAT gateway with its metrics and what is wrong
+// with it, if anything
+func formatNATGateway(gateway types.NATGateway) map[string]interface{} {
+	item := map[string]interface{}{
+		"id":                gateway.ID,
+		"name":              natGatewayName(gateway),
+		"vpc_id":            gateway.VpcID,
+		"subnet_id":         gateway.SubnetID,
+		"state":             gateway.State,
+		"connectivity_type": gateway.ConnectivityType,
+		"addresses":         gateway.Addresses,
+	}
+	if gateway.PublicIP != "" {
+		item["public_ip"] = gateway.PublicIP
+	}
+	if metrics := gateway.Metrics; metrics != nil {
+		item["data_processed_gb"] = metrics.BytesProcessed / (1 << 30)
+		item["port_allocation_errors"] = metrics.ErrorPortAllocation
+		item["packets_dropped"] = metrics.PacketsDropped
+		item["peak_active_connections"] = metrics.ActiveConnections
+	}
+	if problems := natGatewayProblems(gateway); len(problems) > 0 {
+		item["problems"] = problems
+	}
+	return item
+}
+
+// natGatewayProblems explains what is wrong with a NAT gateway: it failed,
+// ran out of source ports or dropped packets
+func natGatewayProblems(gateway types.NATGateway) []string {
+	var problems []string
+	if gateway.State == "failed" {
+		problems = append(problems, "failed: "+gateway.FailureMessage)
+	}
+
+	metrics := gateway.Metrics
+	if metrics == nil {
+		return problems
+	}
+	if metrics.ErrorPortAllocation > 0 {
+		problems = append(problems, fmt.Sprintf("%.0f connections failed for lack of a source port: each of its %d %s allows about 55,000 concurrent connections "+
+			"to one destination; add secondary IPs, spread the traffic over more NAT gateways or reach AWS services through VPC endpoints",
+			metrics.ErrorPortAllocation, gateway.Addresses, plural(gateway.Addresses, "address", "addresses")))
+	}
+	if metrics.PacketsDropped >= natPacketsDroppedWarning {
+		problems = append(problems, fmt.Sprintf("dropped %.0f packets", metrics.PacketsDropped))
+	}
+	return problems
+}
+
+// natGatewayName is the name of a NAT gateway, its ID when it has none
+func natGatewayName(gateway types.NATGateway) string {
+	if gateway.Name != "" {
+		return gateway.Name
+	}
+	return gateway.ID
+}
+
+// formatVPCEndpoints orders the endpoints those that are not available first,
+// then by service, counts them by type and lists the VPCs with a NAT gateway
+// but no gateway endpoint for S3 or DynamoDB
+func formatVPCEndpoints(endpoints []types.VPCEndpoint, gateways []types.NATGateway) map[string]interface{} {
+	sorted := append([]types.VPCEndpoint{}, endpoints...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		iAvailable := strings.EqualFold(sorted[i].State, "available")
+		if iAvailable != strings.EqualFold(sorted[j].State, "available") {
+			return !iAvailable
+		}
+		return sorted[i].ServiceName < sorted[j].ServiceName
+	})
+
+	byType := make(map[string]int)
+	unavailable := []string{}
+	covered := make(map[string]bool)
+	for _, endpoint := range sorted {
+		byType[endpoint.Type]++
+		if !strings.EqualFold(endpoint.State, "available") {
+			unavailable = append(unavailable, endpoint.ID)
+			continue
+		}
+		if endpoint.Type == "Gateway" {
+			covered[endpoint.VpcID+" "+endpointService(endpoint.ServiceName)] = true
+		}
+	}
+
+	// Each VPC is reported once however many NAT gateways it has
+	uncovered := []map[string]interface{}{}
+	seen := make(map[string]bool)
+	for _, gateway := range gateways {
+		if gateway.State != "available" || gateway.ConnectivityType == "private" || seen[gateway.VpcID] {
+			continue
+		}
+		seen[gateway.VpcID] = true
+
+		var missing []string
+		for _, service := range gatewayEndpointServices {
+			if !covered[gateway.VpcID+" "+service] {
+				missing = append(missing, service)
+			}
+		}
+		if len(missing) > 0 {
+			uncovered = append(uncovered, map[string]interface{}{"vpc_id": gateway.VpcID, "missing_gateway_endpoints": missing})
+		}
+	}
+	sort.Slice(uncovered, func(i, j int) bool { return uncovered[i]["vpc_id"].(string) < uncovered[j]["vpc_id"].(string) })
+
+	data := map[string]interface{}{
+		"endpoints":                     sorted,
+		"total":                         len(endpoints),
+		"summary_by_type":               byType,
+		"unavailable":                   unavailable,
+		"nat_without_gateway_endpoints": uncovered,
+	}
+	if len(uncovered) > 0 {
+		data["note"] = "Traffic from these VPCs to the missing services goes through a NAT gateway, where it is billed for data processing and uses source ports; " +
+			"gateway endpoints for S3 and DynamoDB are free"
+	}
+	return data
+}
+
+// endpointService is the service of an endpoint service name, e.g. s3 for
+// com.amazonaws.us-east-1.s3
+func endpointService(serviceName string) string {
+	return serviceName[strings.LastIndex(serviceName, ".")+1:]
+}
@@ -0,0 +1,77 @@
+package mcp
+
+import (
+	"encoding/json"
+	"testing"
+
+	"aws-mcp-server/pkg/render"
+	"aws-mcp-server/pkg/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatNATGateways(t *testing.T) {
+	data := formatNATGateways([]types.NATGateway{
+		{ID: "nat-quiet", State: "available", Addresses: 1, Metrics: &types.NATGatewayMetrics{BytesProcessed: 1 << 30}},
+		{ID: "nat-busy", Name: "egress-a", State: "available", Addresses: 1, Metrics: &types.NATGatewayMetrics{BytesProcessed: 3 << 30, ErrorPortAllocation: 120}},
+		{ID: "nat-big", State: "available", Addresses: 2, Metrics: &types.NATGatewayMetrics{BytesProcessed: 5 << 30}},
+		{ID: "nat-broken", State: "failed", FailureMessage: "Elastic IP address is already associated"},
+		{ID: "nat-unknown", State: "available"},
+	})
+
+	assert.Equal(t, 5, data["total"])
+	assert.Equal(t, map[string]int{"available": 4, "failed": 1}, data["summary_by_state"])
+	assert.Equal(t, []string{"egress-a", "nat-broken"}, data["troubled"])
+	assert.Equal(t, 9.0, data["total_data_processed_gb"])
+
+	items := data["nat_gateways"].([]map[string]interface{})
+	var ids []string
+	for _, item := range items {
+		ids = append(ids, item["id"].(string))
+	}
+	assert.Equal(t, []string{"nat-busy", "nat-broken", "nat-big", "nat-quiet", "nat-unknown"}, ids, "troubled gateways first, then by traffic")
+	assert.Contains(t, items[0]["problems"].([]string)[0], "120 connections failed for lack of a source port: each of its 1 address")
+	assert.NotContains(t, items[4], "data_processed_gb", "gateways without metrics have no traffic figures")
+
+	renderer, err := render.New(nil)
+	require.NoError(t, err)
+	payload, err := json.Marshal(data)
+	require.NoError(t, err)
+	summary, ok := renderer.RenderJSON(natGatewaysURI, payload, nil)
+	require.True(t, ok)
+	assert.Equal(t, "5 NAT gateways processed 9.0 GB in the last 60 minutes; troubled: egress-a, nat-broken", summary)
+}
+
+func TestFormatVPCEndpoints(t *testing.T) {
+	endpoints := []types.VPCEndpoint{
+		{ID: "vpce-s3", VpcID: "vpc-a", ServiceName: "com.amazonaws.us-east-1.s3", Type: "Gateway", State: "Available"},
+		{ID: "vpce-ssm", VpcID: "vpc-a", ServiceName: "com.amazonaws.us-east-1.ssm", Type: "Interface", State: "Available"},
+		{ID: "vpce-ddb", VpcID: "vpc-b", ServiceName: "com.amazonaws.us-east-1.dynamodb", Type: "Gateway", State: "Pending"},
+	}
+	gateways := []types.NATGateway{
+		{ID: "nat-1", VpcID: "vpc-a", State: "available", ConnectivityType: "public"},
+		{ID: "nat-2", VpcID: "vpc-b", State: "available", ConnectivityType: "public"},
+		{ID: "nat-3", VpcID: "vpc-b", State: "available", ConnectivityType: "public"},
+		{ID: "nat-private", VpcID: "vpc-c", State: "available", ConnectivityType: "private"},
+	}
+
+	data := formatVPCEndpoints(endpoints, gateways)
+	assert.Equal(t, 3, data["total"])
+	assert.Equal(t, map[string]int{"Gateway": 2, "Interface": 1}, data["summary_by_type"])
+	assert.Equal(t, []string{"vpce-ddb"}, data["unavailable"])
+	assert.Equal(t, "vpce-ddb", data["endpoints"].([]types.VPCEndpoint)[0].ID, "endpoints that are not available come first")
+	assert.Equal(t, []map[string]interface{}{
+		{"vpc_id": "vpc-a", "missing_gateway_endpoints": []string{"dynamodb"}},
+		{"vpc_id": "vpc-b", "missing_gateway_endpoints": []string{"dynamodb", "s3"}},
+	}, data["nat_without_gateway_endpoints"], "a pending endpoint does not cover its VPC yet and private NAT gateways do not reach AWS services")
+	assert.Contains(t, data["note"], "gateway endpoints for S3 and DynamoDB are free")
+
+	renderer, err := render.New(nil)
+	require.NoError(t, err)
+	payload, err := json.Marshal(data)
+	require.NoError(t, err)
+	summary, ok := renderer.RenderJSON(vpcEndpointsURI, payload, nil)
+	require.True(t, ok)
+	assert.Equal(t, "3 VPC endpoints: 2 Gateway 1 Interface; 1 not available; 2 VPCs send S3 or DynamoDB traffic through NAT", summary)
+}
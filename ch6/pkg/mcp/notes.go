@@ -0,0 +1,154 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"aws-mcp-server/pkg/notes"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const (
+	// notesURI lists the notes attached to resources
+	notesURI = "aws://notes"
+	// notesTemplate lists the notes of one resource by its ID or ARN
+	notesTemplate = "aws://notes/{+resource}"
+)
+
+// addNote attaches a note to a resource. Notes are kept by the server, not in
+// AWS tags, so they need no permission on the resource and carry context
+// across sessions.
+func (h *ToolHandler) addNote(ctx context.Context, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	resource, _ := arguments["resource"].(string)
+	if strings.TrimSpace(resource) == "" {
+		return h.createErrorResponse("resource is required, e.g. an instance ID or ARN")
+	}
+	text, _ := arguments["text"].(string)
+	if strings.TrimSpace(text) == "" {
+		return h.createErrorResponse("text is required")
+	}
+
+	// The author names who wrote the note when the caller's role does not,
+	// e.g. the agent or engineer behind a shared role
+	createdBy := h.callerRole(ctx)
+	if author, _ := arguments["author"].(string); strings.TrimSpace(author) != "" {
+		createdBy = fmt.Sprintf("%s (%s)", strings.TrimSpace(author), createdBy)
+	}
+
+	note, err := h.notes.Add(resource, text, createdBy, time.Now())
+	if err != nil {
+		h.logger.WithError(err).WithField("resource", resource).Error("Failed to save notes")
+		return h.createErrorResponse(fmt.Sprintf("failed to save the note: %v", err))
+	}
+
+	data := formatNote(note, h.times.Format)
+	data["resourceNotes"] = len(h.notes.For(note.Resource))
+	return h.createSuccessResponse(fmt.Sprintf("Added note %s to %s", note.ID, note.Resource), data)
+}
+
+// removeNote deletes a note that no longer applies
+func (h *ToolHandler) removeNote(ctx context.Context, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	noteID, _ := arguments["noteId"].(string)
+	if noteID == "" {
+		return h.createErrorResponse("noteId is required")
+	}
+
+	note, err := h.notes.Remove(noteID)
+	if err != nil {
+		return h.createErrorResponse(err.Error())
+	}
+
+	return h.createSuccessResponse(fmt.Sprintf("Removed note %s from %s", note.ID, note.Resource), map[string]interface{}{
+		"removed": formatNote(note, h.times.Format),
+	})
+}
+
+// readNotes lists the notes of every resource
+func (h *ResourceHandler) readNotes(ctx context.Context) (*mcp.ReadResourceResult, error) {
+	jsonData, err := json.MarshalIndent(formatNotes(h.notes.All(), h.times.Format), "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal notes: %w", err)
+	}
+
+	return &mcp.ReadResourceResult{
+		Contents: []mcp.ResourceContents{
+			&mcp.TextResourceContents{
+				URI:      notesURI,
+				MIMEType: "application/json",
+				Text:     string(jsonData),
+			},
+		},
+	}, nil
+}
+
+// readResourceNotes lists the notes of the resource in the URI
+func (h *ResourceHandler) readResourceNotes(ctx context.Context, req resourceRequest) (*mcp.ReadResourceResult, error) {
+	resource := req.Param("resource")
+	data := formatNotes(h.notes.For(resource), h.times.Format)
+	data["resource"] = resource
+
+	jsonData, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal notes: %w", err)
+	}
+
+	return &mcp.ReadResourceResult{
+		Contents: []mcp.ResourceContents{
+			&mcp.TextResourceContents{
+				URI:      req.URI,
+				MIMEType: "application/json",
+				Text:     string(jsonData),
+			},
+		},
+	}, nil
+}
+
+// resourceNotes formats the notes of a resource for its detail view, nil when
+// it has none
+func (h *ResourceHandler) resourceNotes(resource string) []map[string]interface{} {
+	attached := h.notes.For(resource)
+	if len(attached) == 0 {
+		return nil
+	}
+
+	formatted := make([]map[string]interface{}, 0, len(attached))
+	for _, note := range attached {
+		formatted = append(formatted, formatNote(note, h.times.Format))
+	}
+	return formatted
+}
+
+// formatNotes groups notes by resource, oldest first within a resource. notes
+// must be ordered by resource, as Store.All returns them.
+func formatNotes(all []notes.Note, formatTime func(time.Time) string) map[string]interface{} {
+	resources := []map[string]interface{}{}
+	for i, note := range all {
+		if i == 0 || note.Resource != all[i-1].Resource {
+			resources = append(resources, map[string]interface{}{"resource": note.Resource, "notes": []map[string]interface{}{}})
+		}
+		current := resources[len(resources)-1]
+		current["notes"] = append(current["notes"].([]map[string]interface{}), formatNote(note, formatTime))
+	}
+
+	return map[string]interface{}{
+		"resources":       resources,
+		"total":           len(all),
+		"total_resources": len(resources),
+		"instructions":    "Use add-note to record context about a resource and remove-note once it no longer applies",
+	}
+}
+
+// formatNote converts a note for responses using the given time format
+func formatNote(note notes.Note, formatTime func(time.Time) string) map[string]interface{} {
+	return map[string]interface{}{
+		"id":         note.ID,
+		"resource":   note.Resource,
+		"text":       note.Text,
+		"created_by": note.CreatedBy,
+		"created_at": formatTime(note.CreatedAt),
+	}
+}
@@ -0,0 +1,85 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"aws-mcp-server/internal/config"
+	"aws-mcp-server/internal/logging"
+	"aws-mcp-server/pkg/render"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNoteTools(t *testing.T) {
+	h := NewToolHandler(&config.Config{Access: config.AccessConfig{Role: "operator"}}, nil, logging.NewLogger("error", "text"))
+	ctx := context.Background()
+
+	result, err := h.CallTool(ctx, "add-note", map[string]interface{}{"resource": "i-1"})
+	require.NoError(t, err)
+	assert.Equal(t, "text is required", decodeToolResult(t, result)["error"])
+
+	result, err = h.CallTool(ctx, "add-note", map[string]interface{}{"resource": "i-1", "text": "known flaky, vendor ticket #123", "author": "agent"})
+	require.NoError(t, err)
+	data := decodeToolResult(t, result)
+	assert.Equal(t, true, data["success"])
+	assert.Equal(t, "note-1", data["id"])
+	assert.Equal(t, "agent (operator)", data["created_by"])
+	assert.Equal(t, float64(1), data["resourceNotes"])
+
+	renderer, err := render.New(nil)
+	require.NoError(t, err)
+	payload, err := json.Marshal(data)
+	require.NoError(t, err)
+	summary, ok := renderer.RenderJSON("add-note", payload, nil)
+	require.True(t, ok)
+	assert.Equal(t, "Added note note-1 to i-1, which has 1 note", summary)
+
+	result, err = h.CallTool(ctx, "remove-note", map[string]interface{}{"noteId": "note-1"})
+	require.NoError(t, err)
+	assert.Equal(t, "Removed note note-1 from i-1", decodeToolResult(t, result)["message"])
+	assert.Empty(t, h.notes.For("i-1"))
+
+	result, err = h.CallTool(ctx, "remove-note", map[string]interface{}{"noteId": "note-1"})
+	require.NoError(t, err)
+	assert.Equal(t, "note note-1 not found", decodeToolResult(t, result)["error"])
+}
+
+func TestNotesResources(t *testing.T) {
+	tools := NewToolHandler(&config.Config{}, nil, logging.NewLogger("error", "text"))
+	h := NewResourceHandler(&config.Config{}, nil)
+	h.notes = tools.notes
+
+	arn := "arn:aws:sqs:us-east-1:111111111111:orders"
+	for _, note := range []struct{ resource, text string }{
+		{"i-1", "known flaky, vendor ticket #123"},
+		{arn, "consumers are paused on Sundays"},
+		{"i-1", "reboot fixed it last time"},
+	} {
+		_, err := tools.notes.Add(note.resource, note.text, "sre", time.Now())
+		require.NoError(t, err)
+	}
+
+	result, err := h.ReadResource(context.Background(), notesURI)
+	require.NoError(t, err)
+	var all map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(result.Contents[0].(*mcp.TextResourceContents).Text), &all))
+	assert.Equal(t, float64(3), all["total"])
+	assert.Equal(t, float64(2), all["total_resources"])
+
+	result, err = h.ReadResource(context.Background(), "aws://notes/"+arn)
+	require.NoError(t, err)
+	var one map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(result.Contents[0].(*mcp.TextResourceContents).Text), &one))
+	assert.Equal(t, arn, one["resource"])
+	assert.Equal(t, float64(1), one["total"])
+
+	attached := h.resourceNotes("i-1")
+	require.Len(t, attached, 2)
+	assert.Equal(t, "known flaky, vendor ticket #123", attached[0]["text"], "notes are oldest first")
+	assert.Nil(t, h.resourceNotes("i-2"))
+}
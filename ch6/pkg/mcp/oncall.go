@@ -0,0 +1,106 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"aws-mcp-server/internal/notify"
+	"aws-mcp-server/pkg/oncall"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// readOnCall lists who is on call right now, with the primary responders first
+// so notifications and tickets can name the right person
+func (h *ResourceHandler) readOnCall(ctx context.Context) (*mcp.ReadResourceResult, error) {
+	if h.oncall == nil {
+		return nil, fmt.Errorf("no on-call provider is configured; set oncall.provider to pagerduty or opsgenie")
+	}
+
+	shifts, err := h.oncall.Current(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up on-call: %w", err)
+	}
+
+	formatted := map[string]interface{}{
+		"provider": h.oncall.Provider(),
+		"count":    len(shifts),
+		"primary":  h.formatShifts(oncall.Primary(shifts)),
+		"on_call":  h.formatShifts(shifts),
+	}
+	if len(shifts) == 0 {
+		formatted["note"] = "Nobody is on call in the configured schedules"
+	}
+
+	jsonData, err := json.MarshalIndent(formatted, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal on-call: %w", err)
+	}
+
+	return &mcp.ReadResourceResult{
+		Contents: []mcp.ResourceContents{
+			&mcp.TextResourceContents{
+				URI:      "aws://oncall/current",
+				MIMEType: "application/json",
+				Text:     string(jsonData),
+			},
+		},
+	}, nil
+}
+
+// formatShifts renders shifts with a ready-to-use mention for each person
+func (h *ResourceHandler) formatShifts(shifts []oncall.Shift) []map[string]interface{} {
+	formatted := make([]map[string]interface{}, 0, len(shifts))
+	for _, shift := range shifts {
+		item := map[string]interface{}{
+			"name":     shift.Name,
+			"schedule": shift.Schedule,
+			"mention":  "@" + shift.Name,
+		}
+		if shift.Email != "" {
+			item["email"] = shift.Email
+		}
+		if shift.SlackID != "" {
+			item["slack_id"] = shift.SlackID
+			item["mention"] = "<@" + shift.SlackID + ">"
+		}
+		if shift.EscalationLevel > 0 {
+			item["escalation_level"] = shift.EscalationLevel
+		}
+		if !shift.Start.IsZero() {
+			item["shift_start"] = h.times.Format(shift.Start)
+		}
+		if !shift.End.IsZero() {
+			item["shift_end"] = h.times.Format(shift.End)
+			if relative := h.times.Relative(shift.End); relative != "" {
+				item["shift_ends"] = relative
+			}
+		}
+		formatted = append(formatted, item)
+	}
+	return formatted
+}
+
+// onCallResponders adapts a roster to the notifier, which mentions the
+// primary responders on important events
+func onCallResponders(roster *oncall.Roster) notify.OnCallLookup {
+	return func(ctx context.Context) ([]notify.Responder, error) {
+		shifts, err := roster.Current(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		primary := oncall.Primary(shifts)
+		responders := make([]notify.Responder, 0, len(primary))
+		for _, shift := range primary {
+			responders = append(responders, notify.Responder{
+				Name:     shift.Name,
+				Email:    shift.Email,
+				SlackID:  shift.SlackID,
+				Schedule: shift.Schedule,
+			})
+		}
+		return responders, nil
+	}
+}
@@ -0,0 +1,53 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"aws-mcp-server/internal/config"
+	"aws-mcp-server/pkg/oncall"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// staticOnCall returns fixed shifts
+type staticOnCall []oncall.Shift
+
+func (s staticOnCall) Name() string { return "fake" }
+
+func (s staticOnCall) Current(ctx context.Context) ([]oncall.Shift, error) {
+	return append([]oncall.Shift(nil), s...), nil
+}
+
+func TestReadOnCall(t *testing.T) {
+	handler := NewResourceHandler(&config.Config{}, nil)
+
+	_, err := handler.ReadResource(context.Background(), "aws://oncall/current")
+	assert.ErrorContains(t, err, "no on-call provider")
+
+	handler.oncall = oncall.NewRoster(staticOnCall{
+		{Schedule: "Platform", Name: "Sam Ortiz", Email: "sam@example.com", EscalationLevel: 2},
+		{Schedule: "Platform", Name: "Dana Lee", Email: "dana@example.com", EscalationLevel: 1, End: time.Now().Add(3 * time.Hour)},
+	}, map[string]string{"dana@example.com": "U123"}, time.Minute)
+
+	result, err := handler.ReadResource(context.Background(), "aws://oncall/current")
+	require.NoError(t, err)
+
+	contents, ok := result.Contents[0].(*mcp.TextResourceContents)
+	require.True(t, ok)
+
+	var payload map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(contents.Text), &payload))
+	assert.Equal(t, float64(2), payload["count"])
+
+	primary := payload["primary"].([]interface{})
+	require.Len(t, primary, 1)
+	assert.Equal(t, "<@U123>", primary[0].(map[string]interface{})["mention"])
+
+	onCall := payload["on_call"].([]interface{})
+	assert.Equal(t, "@Sam Ortiz", onCall[1].(map[string]interface{})["mention"])
+}
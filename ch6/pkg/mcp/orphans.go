@@ -0,0 +1,394 @@
+package mcp
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"aws-mcp-server/pkg/cost"
+	"aws-mcp-server/pkg/types"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// defaultOrphanMinAgeDays is how old AMIs and snapshots must be to be reported
+const defaultOrphanMinAgeDays = 90
+
+// orphanKinds are the kinds of unused resources find-orphans looks for
+var orphanKinds = []string{"volume", "elastic-ip", "load-balancer", "target-group", "ami", "snapshot"}
+
+// managedSnapshotTagPrefixes mark snapshots whose lifecycle is owned by AWS
+// Backup or Data Lifecycle Manager, which delete them on their own schedule
+var managedSnapshotTagPrefixes = []string{"aws:backup:", "aws:dlm:"}
+
+// orphan is an unused resource that keeps costing money
+type orphan struct {
+	Kind         string  `json:"kind"`
+	ID           string  `json:"id"`
+	Name         string  `json:"name,omitempty"`
+	Reason       string  `json:"reason"`
+	AgeDays      int     `json:"age_days,omitempty"`
+	MonthlyUSD   float64 `json:"estimated_monthly_usd"`
+	PriceUnknown bool    `json:"price_unknown,omitempty"`
+}
+
+// findOrphans reports unattached volumes, unassociated Elastic IPs, idle load
+// balancers, empty target groups and old AMIs and snapshots with what they cost.
+// Kinds that cannot be listed are reported as unavailable rather than failing
+// the whole scan.
+func (h *ToolHandler) findOrphans(ctx context.Context, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	kinds := stringList(arguments["kinds"])
+	for _, kind := range kinds {
+		if !slices.Contains(orphanKinds, kind) {
+			return h.createErrorResponse(fmt.Sprintf("unknown kind %q, use %s", kind, strings.Join(orphanKinds, ", ")))
+		}
+	}
+	if len(kinds) == 0 {
+		kinds = orphanKinds
+	}
+
+	minAgeDays := defaultOrphanMinAgeDays
+	if value, ok := arguments["minAgeDays"].(float64); ok {
+		if value < 0 {
+			return h.createErrorResponse("minAgeDays must not be negative")
+		}
+		minAgeDays = int(value)
+	}
+	now := time.Now()
+	cutoff := now.AddDate(0, 0, -minAgeDays)
+
+	// Several kinds need the same inventory, so each list is fetched at most once
+	volumes := sync.OnceValues(func() ([]types.CloudResource, error) { return h.awsClient.ListEBSVolumes(ctx) })
+	snapshots := sync.OnceValues(func() ([]types.CloudResource, error) { return h.awsClient.ListEBSSnapshots(ctx) })
+	images := sync.OnceValues(func() ([]types.MachineImage, error) { return h.awsClient.ListMachineImages(ctx) })
+	instances := sync.OnceValues(func() ([]types.CloudResource, error) { return h.awsClient.ListEC2Instances(ctx) })
+	targetGroups := sync.OnceValues(func() ([]types.TargetGroup, error) { return h.awsClient.ListTargetGroups(ctx) })
+
+	finders := map[string]func() ([]orphan, error){
+		"volume": func() ([]orphan, error) {
+			v, err := volumes()
+			if err != nil {
+				return nil, fmt.Errorf("failed to list EBS volumes: %w", err)
+			}
+			return unattachedVolumes(v, now), nil
+		},
+		"elastic-ip": func() ([]orphan, error) {
+			addresses, err := h.awsClient.ListElasticIPs(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to list Elastic IPs: %w", err)
+			}
+			return unassociatedElasticIPs(addresses), nil
+		},
+		"load-balancer": func() ([]orphan, error) {
+			loadBalancers, err := h.awsClient.ListLoadBalancers(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to list load balancers: %w", err)
+			}
+			groups, err := targetGroups()
+			if err != nil {
+				return nil, fmt.Errorf("failed to list target groups: %w", err)
+			}
+			return idleLoadBalancers(loadBalancers, groups), nil
+		},
+		"target-group": func() ([]orphan, error) {
+			groups, err := targetGroups()
+			if err != nil {
+				return nil, fmt.Errorf("failed to list target groups: %w", err)
+			}
+			return emptyTargetGroups(groups), nil
+		},
+		"ami": func() ([]orphan, error) {
+			i, err := images()
+			if err != nil {
+				return nil, fmt.Errorf("failed to list AMIs: %w", err)
+			}
+			running, err := instances()
+			if err != nil {
+				return nil, fmt.Errorf("failed to list EC2 instances: %w", err)
+			}
+			return unusedImages(i, running, cutoff, now), nil
+		},
+		"snapshot": func() ([]orphan, error) {
+			s, err := snapshots()
+			if err != nil {
+				return nil, fmt.Errorf("failed to list EBS snapshots: %w", err)
+			}
+			i, err := images()
+			if err != nil {
+				return nil, fmt.Errorf("failed to list AMIs: %w", err)
+			}
+			v, err := volumes()
+			if err != nil {
+				return nil, fmt.Errorf("failed to list EBS volumes: %w", err)
+			}
+			return oldSnapshots(s, i, v, cutoff, now), nil
+		},
+	}
+
+	var found []orphan
+	unavailable := make(map[string]string)
+	for _, kind := range kinds {
+		items, err := finders[kind]()
+		if err != nil {
+			unavailable[kind] = err.Error()
+			continue
+		}
+		found = append(found, items...)
+	}
+	if len(unavailable) == len(kinds) {
+		return h.createErrorResponse(fmt.Sprintf("failed to scan for orphaned resources: %s", unavailable[kinds[0]]))
+	}
+
+	found, ignored := withoutIgnoredOrphans(found, h.ignored, now)
+	data := formatOrphans(found)
+	data["min_age_days"] = minAgeDays
+	if ignored > 0 {
+		data["ignored"] = ignored
+		data["ignored_note"] = "Ignored resources are left out, see " + ignoredResourcesURI
+	}
+	if len(unavailable) > 0 {
+		data["unavailable"] = unavailable
+	}
+
+	return h.createSuccessResponse("Orphaned resource scan completed", data)
+}
+
+// unattachedVolumes returns the volumes not attached to any instance
+func unattachedVolumes(volumes []types.CloudResource, now time.Time) []orphan {
+	var found []orphan
+	for _, volume := range volumes {
+		if volume.State != "available" {
+			continue
+		}
+
+		details := volume.EBSVolume()
+		volumeType, sizeGiB := details.VolumeType, details.SizeGiB
+		monthly, known := cost.EBSVolumeMonthlyUSD(volumeType, sizeGiB, details.IOPS, details.Throughput)
+
+		found = append(found, orphan{
+			Kind:         "volume",
+			ID:           volume.ID,
+			Name:         volume.Tags["Name"],
+			Reason:       fmt.Sprintf("%d GiB %s volume not attached to any instance", sizeGiB, volumeType),
+			AgeDays:      ageDays(timeOrZero(details.CreateTime), now),
+			MonthlyUSD:   roundCost(monthly),
+			PriceUnknown: !known,
+		})
+	}
+	return found
+}
+
+// unassociatedElasticIPs returns the Elastic IPs not associated with an
+// instance or network interface
+func unassociatedElasticIPs(addresses []types.ElasticIP) []orphan {
+	var found []orphan
+	for _, address := range addresses {
+		if address.AssociationID != "" || address.NetworkInterfaceID != "" {
+			continue
+		}
+		found = append(found, orphan{
+			Kind:       "elastic-ip",
+			ID:         cmp.Or(address.AllocationID, address.PublicIP),
+			Name:       cmp.Or(address.Tags["Name"], address.PublicIP),
+			Reason:     "Elastic IP not associated with any instance or network interface",
+			MonthlyUSD: roundCost(cost.ElasticIPHourly * cost.HoursPerMonth),
+		})
+	}
+	return found
+}
+
+// idleLoadBalancers returns the load balancers without target groups or
+// without a healthy target in any of them
+func idleLoadBalancers(loadBalancers []types.LoadBalancer, groups []types.TargetGroup) []orphan {
+	var found []orphan
+	for _, lb := range loadBalancers {
+		if lb.State != "active" {
+			continue
+		}
+
+		attached, healthy := 0, 0
+		for _, group := range groups {
+			if slices.Contains(group.LoadBalancerARNs, lb.ARN) {
+				attached++
+				healthy += group.HealthyCount()
+			}
+		}
+		if healthy > 0 {
+			continue
+		}
+
+		reason := fmt.Sprintf("%s load balancer has no target groups", lb.Type)
+		if attached > 0 {
+			reason = fmt.Sprintf("%s load balancer has no healthy targets in any of its target groups", lb.Type)
+		}
+		monthly, known := cost.LoadBalancerMonthlyUSD(lb.Type)
+		found = append(found, orphan{
+			Kind:         "load-balancer",
+			ID:           lb.ARN,
+			Name:         lb.Name,
+			Reason:       reason,
+			MonthlyUSD:   roundCost(monthly),
+			PriceUnknown: !known,
+		})
+	}
+	return found
+}
+
+// emptyTargetGroups returns the target groups without registered targets.
+// Target groups are free, but they are leftovers of services that went away.
+func emptyTargetGroups(groups []types.TargetGroup) []orphan {
+	var found []orphan
+	for _, group := range groups {
+		if len(group.Targets) > 0 {
+			continue
+		}
+		reason := "target group has no registered targets"
+		if len(group.LoadBalancerARNs) == 0 {
+			reason = "target group has no registered targets and no load balancer"
+		}
+		found = append(found, orphan{
+			Kind:   "target-group",
+			ID:     group.ARN,
+			Name:   group.Name,
+			Reason: reason,
+		})
+	}
+	return found
+}
+
+// unusedImages returns the AMIs created before cutoff that no instance runs
+func unusedImages(images []types.MachineImage, instances []types.CloudResource, cutoff, now time.Time) []orphan {
+	inUse := make(map[string]bool)
+	for _, instance := range instances {
+		if instance.State == "terminated" {
+			continue
+		}
+		if imageID := instance.EC2Instance().ImageID; imageID != "" {
+			inUse[imageID] = true
+		}
+	}
+
+	var found []orphan
+	for _, image := range images {
+		if inUse[image.ID] || image.CreatedAt.IsZero() || !image.CreatedAt.Before(cutoff) {
+			continue
+		}
+		found = append(found, orphan{
+			Kind:       "ami",
+			ID:         image.ID,
+			Name:       image.Name,
+			Reason:     fmt.Sprintf("AMI not used by any instance, backed by %d GiB of snapshots", image.SizeGiB),
+			AgeDays:    ageDays(image.CreatedAt, now),
+			MonthlyUSD: roundCost(cost.SnapshotMonthlyUSD(image.SizeGiB)),
+		})
+	}
+	return found
+}
+
+// oldSnapshots returns the snapshots started before cutoff that no AMI is
+// backed by and no backup policy manages. Snapshots of AMIs are reported with
+// their AMI, since they cannot be deleted while it is registered.
+func oldSnapshots(snapshots []types.CloudResource, images []types.MachineImage, volumes []types.CloudResource, cutoff, now time.Time) []orphan {
+	backing := make(map[string]bool)
+	for _, image := range images {
+		for _, snapshotID := range image.SnapshotIDs {
+			backing[snapshotID] = true
+		}
+	}
+	volumeExists := make(map[string]bool, len(volumes))
+	for _, volume := range volumes {
+		volumeExists[volume.ID] = true
+	}
+
+	var found []orphan
+	for _, snapshot := range snapshots {
+		details := snapshot.EBSSnapshot()
+		started := timeOrZero(details.StartTime)
+		if backing[snapshot.ID] || started.IsZero() || !started.Before(cutoff) || managedSnapshot(snapshot.Tags) {
+			continue
+		}
+
+		sizeGiB, volumeID := details.SizeGiB, details.VolumeID
+		reason := fmt.Sprintf("%d GiB snapshot not used by any AMI", sizeGiB)
+		if volumeID != "" && !volumeExists[volumeID] {
+			reason += fmt.Sprintf(", source volume %s no longer exists", volumeID)
+		}
+		found = append(found, orphan{
+			Kind:       "snapshot",
+			ID:         snapshot.ID,
+			Name:       snapshot.Tags["Name"],
+			Reason:     reason,
+			AgeDays:    ageDays(started, now),
+			MonthlyUSD: roundCost(cost.SnapshotMonthlyUSD(sizeGiB)),
+		})
+	}
+	return found
+}
+
+// formatOrphans orders the orphans by cost and totals them per kind
+func formatOrphans(found []orphan) map[string]interface{} {
+	sort.SliceStable(found, func(i, j int) bool {
+		if found[i].MonthlyUSD != found[j].MonthlyUSD {
+			return found[i].MonthlyUSD > found[j].MonthlyUSD
+		}
+		return found[i].ID < found[j].ID
+	})
+
+	byKind := make(map[string]map[string]interface{})
+	total := 0.0
+	for _, item := range found {
+		summary, ok := byKind[item.Kind]
+		if !ok {
+			summary = map[string]interface{}{"count": 0, "estimated_monthly_usd": 0.0}
+			byKind[item.Kind] = summary
+		}
+		summary["count"] = summary["count"].(int) + 1
+		summary["estimated_monthly_usd"] = roundCost(summary["estimated_monthly_usd"].(float64) + item.MonthlyUSD)
+		total += item.MonthlyUSD
+	}
+
+	if found == nil {
+		found = []orphan{}
+	}
+	return map[string]interface{}{
+		"total":                 len(found),
+		"estimated_monthly_usd": roundCost(total),
+		"by_kind":               byKind,
+		"orphans":               found,
+		"note": "Estimates use us-east-1 list prices; snapshot costs assume full-size copies and are upper bounds. " +
+			"Check launch templates and Auto Scaling groups before deregistering AMIs.",
+	}
+}
+
+// managedSnapshot reports whether a backup policy owns the snapshot's lifecycle
+func managedSnapshot(tags map[string]string) bool {
+	for key := range tags {
+		for _, prefix := range managedSnapshotTagPrefixes {
+			if strings.HasPrefix(key, prefix) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// timeOrZero returns a timestamp from resource details, zero when it is missing
+func timeOrZero(t *time.Time) time.Time {
+	if t == nil {
+		return time.Time{}
+	}
+	return *t
+}
+
+// ageDays returns how many whole days ago created was, zero when it is unknown
+func ageDays(created, now time.Time) int {
+	if created.IsZero() {
+		return 0
+	}
+	return int(now.Sub(created).Hours() / 24)
+}
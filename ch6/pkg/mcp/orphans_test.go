@@ -0,0 +1,146 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"aws-mcp-server/internal/config"
+	"aws-mcp-server/internal/logging"
+	"aws-mcp-server/pkg/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnattachedVolumes(t *testing.T) {
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	created := now.AddDate(0, 0, -10)
+	volumes := []types.CloudResource{
+		{ID: "vol-1", State: "available", Tags: map[string]string{"Name": "old-data"}, Details: &types.EBSVolumeDetails{VolumeType: "gp3", SizeGiB: 100, IOPS: 3000, Throughput: 125, CreateTime: &created}},
+		{ID: "vol-2", State: "in-use", Details: &types.EBSVolumeDetails{VolumeType: "gp3", SizeGiB: 100}},
+		{ID: "vol-3", State: "available", Details: &types.EBSVolumeDetails{VolumeType: "future", SizeGiB: 5}},
+	}
+
+	found := unattachedVolumes(volumes, now)
+	require.Len(t, found, 2)
+	assert.Equal(t, "vol-1", found[0].ID)
+	assert.Equal(t, "old-data", found[0].Name)
+	assert.Equal(t, 10, found[0].AgeDays)
+	assert.InDelta(t, 8.0, found[0].MonthlyUSD, 0.0001)
+	assert.False(t, found[0].PriceUnknown)
+	assert.True(t, found[1].PriceUnknown, "unknown volume types are flagged rather than priced at zero")
+}
+
+func TestUnassociatedElasticIPs(t *testing.T) {
+	found := unassociatedElasticIPs([]types.ElasticIP{
+		{AllocationID: "eipalloc-1", PublicIP: "3.3.3.3"},
+		{AllocationID: "eipalloc-2", PublicIP: "4.4.4.4", AssociationID: "eipassoc-1", InstanceID: "i-1"},
+	})
+	require.Len(t, found, 1)
+	assert.Equal(t, "eipalloc-1", found[0].ID)
+	assert.Equal(t, "3.3.3.3", found[0].Name)
+	assert.InDelta(t, 3.65, found[0].MonthlyUSD, 0.0001)
+}
+
+func TestIdleLoadBalancersAndEmptyTargetGroups(t *testing.T) {
+	loadBalancers := []types.LoadBalancer{
+		{ARN: "arn:lb/web", Name: "web", Type: "application", State: "active"},
+		{ARN: "arn:lb/old", Name: "old", Type: "application", State: "active"},
+		{ARN: "arn:lb/bare", Name: "bare", Type: "network", State: "active"},
+		{ARN: "arn:lb/new", Name: "new", Type: "application", State: "provisioning"},
+	}
+	groups := []types.TargetGroup{
+		{ARN: "arn:tg/web", Name: "web", LoadBalancerARNs: []string{"arn:lb/web"}, Targets: []types.Target{{ID: "i-1", State: "healthy"}}},
+		{ARN: "arn:tg/old", Name: "old", LoadBalancerARNs: []string{"arn:lb/old"}, Targets: []types.Target{{ID: "i-2", State: "unhealthy"}}},
+		{ARN: "arn:tg/empty", Name: "empty", LoadBalancerARNs: []string{"arn:lb/old"}},
+		{ARN: "arn:tg/detached", Name: "detached"},
+	}
+
+	idle := idleLoadBalancers(loadBalancers, groups)
+	require.Len(t, idle, 2)
+	assert.Equal(t, "old", idle[0].Name)
+	assert.Contains(t, idle[0].Reason, "no healthy targets")
+	assert.Equal(t, "bare", idle[1].Name)
+	assert.Contains(t, idle[1].Reason, "no target groups")
+	assert.InDelta(t, 16.425, idle[1].MonthlyUSD, 0.0001)
+
+	empty := emptyTargetGroups(groups)
+	require.Len(t, empty, 2)
+	assert.Equal(t, "empty", empty[0].Name)
+	assert.Equal(t, "detached", empty[1].Name)
+	assert.Contains(t, empty[1].Reason, "no load balancer")
+	assert.Zero(t, empty[1].MonthlyUSD)
+}
+
+func TestUnusedImagesAndOldSnapshots(t *testing.T) {
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	cutoff := now.AddDate(0, 0, -90)
+	old := now.AddDate(0, 0, -200)
+	recent := now.AddDate(0, 0, -5)
+
+	images := []types.MachineImage{
+		{ID: "ami-used", CreatedAt: old, SnapshotIDs: []string{"snap-used"}, SizeGiB: 8},
+		{ID: "ami-old", Name: "base-2024", CreatedAt: old, SnapshotIDs: []string{"snap-ami"}, SizeGiB: 20},
+		{ID: "ami-new", CreatedAt: recent, SnapshotIDs: []string{"snap-new"}, SizeGiB: 8},
+	}
+	instances := []types.CloudResource{
+		{ID: "i-1", State: "running", Details: &types.EC2InstanceDetails{ImageID: "ami-used"}},
+		{ID: "i-2", State: "terminated", Details: &types.EC2InstanceDetails{ImageID: "ami-old"}},
+	}
+
+	unused := unusedImages(images, instances, cutoff, now)
+	require.Len(t, unused, 1)
+	assert.Equal(t, "ami-old", unused[0].ID)
+	assert.Equal(t, 200, unused[0].AgeDays)
+	assert.InDelta(t, 1.0, unused[0].MonthlyUSD, 0.0001)
+
+	snapshots := []types.CloudResource{
+		{ID: "snap-ami", Details: &types.EBSSnapshotDetails{SizeGiB: 20, VolumeID: "vol-gone", StartTime: &old}},
+		{ID: "snap-gone", Details: &types.EBSSnapshotDetails{SizeGiB: 50, VolumeID: "vol-gone", StartTime: &old}},
+		{ID: "snap-kept", Details: &types.EBSSnapshotDetails{SizeGiB: 10, VolumeID: "vol-1", StartTime: &old}},
+		{ID: "snap-recent", Details: &types.EBSSnapshotDetails{SizeGiB: 10, VolumeID: "vol-1", StartTime: &recent}},
+		{ID: "snap-backup", Tags: map[string]string{"aws:backup:source-resource": "vol-1"}, Details: &types.EBSSnapshotDetails{SizeGiB: 10, StartTime: &old}},
+	}
+	volumes := []types.CloudResource{{ID: "vol-1"}}
+
+	found := oldSnapshots(snapshots, images, volumes, cutoff, now)
+	require.Len(t, found, 2)
+	assert.Equal(t, "snap-gone", found[0].ID)
+	assert.Contains(t, found[0].Reason, "source volume vol-gone no longer exists")
+	assert.InDelta(t, 2.5, found[0].MonthlyUSD, 0.0001)
+	assert.Equal(t, "snap-kept", found[1].ID)
+	assert.NotContains(t, found[1].Reason, "no longer exists")
+}
+
+func TestFormatOrphans(t *testing.T) {
+	data := formatOrphans([]orphan{
+		{Kind: "snapshot", ID: "snap-1", MonthlyUSD: 2.5},
+		{Kind: "elastic-ip", ID: "eipalloc-1", MonthlyUSD: 3.65},
+		{Kind: "snapshot", ID: "snap-2", MonthlyUSD: 1},
+	})
+
+	assert.Equal(t, 3, data["total"])
+	assert.InDelta(t, 7.15, data["estimated_monthly_usd"], 0.0001)
+	found := data["orphans"].([]orphan)
+	assert.Equal(t, "eipalloc-1", found[0].ID, "most expensive first")
+	byKind := data["by_kind"].(map[string]map[string]interface{})
+	assert.Equal(t, 2, byKind["snapshot"]["count"])
+	assert.InDelta(t, 3.5, byKind["snapshot"]["estimated_monthly_usd"], 0.0001)
+
+	empty := formatOrphans(nil)
+	assert.Equal(t, 0, empty["total"])
+	assert.NotNil(t, empty["orphans"])
+}
+
+func TestFindOrphansValidatesArguments(t *testing.T) {
+	h := NewToolHandler(&config.Config{}, nil, logging.NewLogger("error", "text"))
+
+	result, err := h.CallTool(context.Background(), "find-orphans", map[string]interface{}{"kinds": []interface{}{"bucket"}})
+	require.NoError(t, err)
+	assert.Contains(t, decodeToolResult(t, result)["error"], `unknown kind "bucket"`)
+
+	result, err = h.CallTool(context.Background(), "find-orphans", map[string]interface{}{"minAgeDays": float64(-1)})
+	require.NoError(t, err)
+	assert.Contains(t, decodeToolResult(t, result)["error"], "minAgeDays")
+}
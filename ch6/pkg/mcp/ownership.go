@@ -0,0 +1,116 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"aws-mcp-server/internal/notify"
+	"aws-mcp-server/pkg/ownership"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const (
+	ownershipURI      = "aws://ownership"
+	ownershipTemplate = "aws://ownership/{+resourceId}"
+)
+
+// resourceArguments are the tool arguments naming the resource a tool acts
+// on, in order of preference
+var resourceArguments = []string{
+	"instanceId", "dbInstanceId", "volumeId", "snapshotId",
+	"securityGroupId", "groupId", "subnetId", "imageId",
+}
+
+// readOwnership returns the team owning a resource and how to reach it
+func (h *ResourceHandler) readOwnership(ctx context.Context, uri string) (*mcp.ReadResourceResult, error) {
+	if h.owners == nil {
+		return nil, fmt.Errorf("resource ownership is not configured; check the ownership section of the configuration")
+	}
+
+	resourceID := strings.TrimPrefix(uri, ownershipURI+"/")
+	if resourceID == "" {
+		return nil, fmt.Errorf("resource ID is required, e.g. %s/i-0abc123", ownershipURI)
+	}
+
+	owner, err := h.owners.Resolve(ctx, resourceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve the owner of %s: %w", resourceID, err)
+	}
+
+	jsonData, err := json.MarshalIndent(formatOwner(owner, h.owners), "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal ownership data: %w", err)
+	}
+
+	return &mcp.ReadResourceResult{
+		Contents: []mcp.ResourceContents{
+			&mcp.TextResourceContents{
+				URI:      uri,
+				MIMEType: "application/json",
+				Text:     string(jsonData),
+			},
+		},
+	}, nil
+}
+
+// formatOwner renders an owner with the contact details of each team, so the
+// agent knows which queue a ticket goes to and which channel to write in
+func formatOwner(owner ownership.Owner, owners *ownership.Resolver) map[string]interface{} {
+	formatted := map[string]interface{}{
+		"resource":        owner.Resource,
+		"owned":           len(owner.Teams) > 0,
+		"sources_checked": owners.Sources(),
+	}
+	if len(owner.Teams) == 0 {
+		formatted["note"] = "No source names an owner; add an owner tag or a rule to the owners file, and route the issue to the on-call responders meanwhile"
+		return formatted
+	}
+
+	teams := make([]map[string]interface{}, 0, len(owner.Teams))
+	for _, name := range owner.Teams {
+		item := map[string]interface{}{"name": name}
+		if team, ok := owners.Team(name); ok {
+			if team.Email != "" {
+				item["email"] = team.Email
+			}
+			if team.SlackChannel != "" {
+				item["slack_channel"] = team.SlackChannel
+			}
+			if team.TicketQueue != "" {
+				item["ticket_queue"] = team.TicketQueue
+			}
+		}
+		teams = append(teams, item)
+	}
+
+	formatted["team"] = owner.Team()
+	formatted["teams"] = teams
+	formatted["source"] = owner.Source
+	formatted["rule"] = owner.Rule
+	return formatted
+}
+
+// ownerTeams adapts a resolver to the notifier, which routes events about a
+// resource to the routes of its owning teams
+func ownerTeams(owners *ownership.Resolver) notify.OwnerLookup {
+	return func(ctx context.Context, resource string) ([]string, error) {
+		owner, err := owners.Resolve(ctx, resource)
+		if err != nil {
+			return nil, err
+		}
+		return owner.Teams, nil
+	}
+}
+
+// resourceFromArguments returns the resource a tool call acts on, if any
+func resourceFromArguments(arguments map[string]interface{}) string {
+	for _, key := range resourceArguments {
+		if value, _ := arguments[key].(string); value != "" {
+			return value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,52 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	"aws-mcp-server/internal/config"
+	"aws-mcp-server/pkg/ownership"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadOwnership(t *testing.T) {
+	h := NewResourceHandler(&config.Config{}, nil)
+	_, err := h.ReadResource(context.Background(), "aws://ownership/i-0abc")
+	assert.ErrorContains(t, err, "not configured")
+
+	owners, err := ownership.New(config.OwnershipConfig{
+		Sources: []string{ownership.SourceTags},
+		Teams:   []config.OwnershipTeamConfig{{Name: "payments", SlackChannel: "#payments-oncall", TicketQueue: "PAY"}},
+	}, []string{"Team"}, func(ctx context.Context, resourceID string) (map[string]string, error) {
+		if resourceID == "i-0abc" {
+			return map[string]string{"Team": "payments"}, nil
+		}
+		return nil, nil
+	})
+	require.NoError(t, err)
+	h.owners = owners
+
+	result, err := h.ReadResource(context.Background(), "aws://ownership/i-0abc")
+	require.NoError(t, err)
+	require.NotEmpty(t, result.Contents)
+
+	owner, err := owners.Resolve(context.Background(), "i-0abc")
+	require.NoError(t, err)
+	formatted := formatOwner(owner, owners)
+	assert.Equal(t, "payments", formatted["team"])
+	assert.Equal(t, []map[string]interface{}{{"name": "payments", "slack_channel": "#payments-oncall", "ticket_queue": "PAY"}}, formatted["teams"])
+
+	owner, err = owners.Resolve(context.Background(), "vol-0def")
+	require.NoError(t, err)
+	formatted = formatOwner(owner, owners)
+	assert.Equal(t, false, formatted["owned"])
+	assert.NotEmpty(t, formatted["note"])
+}
+
+func TestResourceFromArguments(t *testing.T) {
+	assert.Equal(t, "i-0abc", resourceFromArguments(map[string]interface{}{"instanceId": "i-0abc", "confirm": true}))
+	assert.Equal(t, "sg-0abc", resourceFromArguments(map[string]interface{}{"groupId": "sg-0abc", "cidr": "0.0.0.0/0"}))
+	assert.Empty(t, resourceFromArguments(map[string]interface{}{"alarmName": "cpu-high"}))
+}
@@ -0,0 +1,149 @@
+package mcp
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"aws-mcp-server/pkg/cloud"
+	"aws-mcp-server/pkg/types"
+)
+
+// instancePageQuery declares the query parameters of instance list pages in
+// resource templates, e.g. aws://ec2/instances?cursor=...&limit=100
+const instancePageQuery = "{?cursor,limit}"
+
+// maxInstancePageSize caps the limit a read of an instance list asks for
+const maxInstancePageSize = 1000
+
+// errInvalidCursor rejects cursors the server did not hand out
+var errInvalidCursor = errors.New("invalid cursor: read the list without one and follow its next_cursor")
+
+// listCursor is where the next page of an instance list starts: an offset
+// into a whole list, or the provider's own token when the list is read from
+// the provider a page at a time. Cursors are opaque to clients.
+type listCursor struct {
+	offset int
+	token  string
+}
+
+// String encodes the cursor for a URI query
+func (c listCursor) String() string {
+	value := "o" + strconv.Itoa(c.offset)
+	if c.token != "" {
+		value = "t" + c.token
+	}
+	return base64.RawURLEncoding.EncodeToString([]byte(value))
+}
+
+// parseCursor decodes a cursor; an empty one starts at the first page
+func parseCursor(value string) (listCursor, error) {
+	if value == "" {
+		return listCursor{}, nil
+	}
+	decoded, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil || len(decoded) < 2 {
+		return listCursor{}, errInvalidCursor
+	}
+
+	switch rest := string(decoded[1:]); decoded[0] {
+	case 't':
+		return listCursor{token: rest}, nil
+	case 'o':
+		offset, err := strconv.Atoi(rest)
+		if err != nil || offset < 0 {
+			return listCursor{}, errInvalidCursor
+		}
+		return listCursor{offset: offset}, nil
+	default:
+		return listCursor{}, errInvalidCursor
+	}
+}
+
+// instancePage returns the page size and cursor of an instance list read.
+// The limit query parameter overrides response.page_size; a size of 0 reads
+// the whole list.
+func (h *ResourceHandler) instancePage(query url.Values) (int, listCursor, error) {
+	size := h.config.Overrides.Int("response.page_size", h.config.Response.PageSize)
+	if limit := strings.TrimSpace(query.Get("limit")); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n <= 0 {
+			return 0, listCursor{}, fmt.Errorf("invalid limit %q: must be a positive number", limit)
+		}
+		size = n
+	}
+	size = min(max(size, 0), maxInstancePageSize)
+
+	cursor, err := parseCursor(query.Get("cursor"))
+	return size, cursor, err
+}
+
+// listInstancePage lists the instances of a page of a provider's list.
+// Providers that page themselves are read one page at a time, following
+// their token, and the list is then Partial. Otherwise all instances are
+// listed and returned along with the page cut out of them.
+func (h *ResourceHandler) listInstancePage(ctx context.Context, provider cloud.Provider, size int, cursor listCursor) (list instanceList, all []types.CloudResource, err error) {
+	paged, ok := provider.(cloud.PagedProvider)
+	if cursor.token != "" && !ok {
+		return instanceList{}, nil, errInvalidCursor
+	}
+
+	if ok && (cursor.token != "" || size > 0 && cursor.offset == 0) {
+		if size == 0 {
+			size = maxInstancePageSize
+		}
+		instances, next, err := paged.ListInstancesPage(ctx, cursor.token, size)
+		if err != nil {
+			return instanceList{}, nil, err
+		}
+		list = h.formatInstancesForAI(instances)
+		list.Partial = true
+		if next != "" {
+			list.NextCursor = listCursor{token: next}.String()
+		}
+		return list, nil, nil
+	}
+
+	all, err = provider.ListInstances(ctx)
+	if err != nil {
+		return instanceList{}, nil, err
+	}
+	return pageOf(h.formatInstancesForAI(all), cursor.offset, size), all, nil
+}
+
+// refreshSnapshot lists a provider's instances for its inventory snapshot on
+// a goroutine of its own, unless that is already under way. The listing
+// outlives the request that started it.
+func (h *ResourceHandler) refreshSnapshot(ctx context.Context, provider cloud.Provider) {
+	uri := cloud.InstancesURI(provider)
+	if _, running := h.refreshing.LoadOrStore(uri, true); running {
+		return
+	}
+	go func() {
+		defer h.refreshing.Delete(uri)
+		_, _ = h.inventory.Update(context.WithoutCancel(ctx), provider, h.config.Inventory.FullRefreshInterval)
+	}()
+}
+
+// pageOf cuts the page starting at offset out of a whole list. The totals and
+// summaries keep covering the whole list.
+func pageOf(list instanceList, offset, size int) instanceList {
+	if size == 0 && offset == 0 {
+		return list
+	}
+
+	start := min(offset, len(list.Instances))
+	end := len(list.Instances)
+	if size > 0 {
+		end = min(start+size, end)
+	}
+	if end < len(list.Instances) {
+		list.NextCursor = listCursor{offset: end}.String()
+	}
+	list.Instances = list.Instances[start:end]
+	return list
+}
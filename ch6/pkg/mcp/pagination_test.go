@@ -0,0 +1,172 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"aws-mcp-server/internal/config"
+	"aws-mcp-server/internal/logging"
+	"aws-mcp-server/pkg/aws"
+	"aws-mcp-server/pkg/fake"
+	"aws-mcp-server/pkg/inventory"
+	"aws-mcp-server/pkg/types"
+
+	"github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// readInstancePage reads an instance list page and decodes its JSON
+func readInstancePage(t *testing.T, read func(uri string) (*mcp.ReadResourceResult, error), uri string) instanceList {
+	t.Helper()
+	result, err := read(uri)
+	require.NoError(t, err)
+	require.NotEmpty(t, result.Contents)
+
+	var text string
+	switch contents := result.Contents[0].(type) {
+	case mcp.TextResourceContents:
+		text = contents.Text
+	case *mcp.TextResourceContents:
+		text = contents.Text
+	}
+	var list instanceList
+	require.NoError(t, json.Unmarshal([]byte(text), &list))
+	return list
+}
+
+func TestInstanceListPagesFollowNextToken(t *testing.T) {
+	logger := logging.NewLogger("error", "text")
+	fleet := fake.New(config.FakeConfig{Instances: 23, Seed: 3}, "us-east-1")
+	cfg := &config.Config{
+		MCP:      config.MCPConfig{ServerName: "aws-mcp-server", Version: "test"},
+		Response: config.ResponseConfig{PageSize: 10},
+	}
+	s := NewServer(cfg, aws.NewClientFromConfig(fleet.Config(), logger), logger)
+	server := httptest.NewServer(s.HTTPHandler())
+	t.Cleanup(server.Close)
+
+	// Pages are read through the client, so the page template must match
+	c, err := client.NewStreamableHttpClient(server.URL + "/mcp")
+	require.NoError(t, err)
+	initializeClient(t, c)
+	read := func(uri string) (*mcp.ReadResourceResult, error) {
+		request := mcp.ReadResourceRequest{}
+		request.Params.URI = uri
+		return c.ReadResource(context.Background(), request)
+	}
+
+	seen := make(map[string]bool)
+	uri := "aws://ec2/instances"
+	pages := 0
+	for {
+		list := readInstancePage(t, read, uri)
+		pages++
+		assert.True(t, list.Partial)
+		assert.Equal(t, len(list.Instances), list.TotalInstances, "totals cover the page")
+		for _, instance := range list.Instances {
+			assert.False(t, seen[instance.ID], "%s is on one page only", instance.ID)
+			seen[instance.ID] = true
+		}
+		if list.NextCursor == "" {
+			break
+		}
+		assert.Len(t, list.Instances, 10)
+		uri = "aws://ec2/instances?cursor=" + list.NextCursor
+	}
+	assert.Equal(t, 3, pages)
+	assert.Len(t, seen, 23)
+}
+
+func TestInstanceListPagesSaveTheSnapshotOnTheLastPage(t *testing.T) {
+	logger := logging.NewLogger("error", "text")
+	fleet := fake.New(config.FakeConfig{Instances: 23, Seed: 3}, "us-east-1")
+	path := filepath.Join(t.TempDir(), "inventory.json")
+	cfg := &config.Config{
+		Inventory: config.InventoryConfig{Path: path},
+		Response:  config.ResponseConfig{PageSize: 10},
+	}
+	h := NewResourceHandler(cfg, aws.NewClientFromConfig(fleet.Config(), logger))
+	var err error
+	h.inventory, err = inventory.Open(path, 0)
+	require.NoError(t, err)
+	read := func(uri string) (*mcp.ReadResourceResult, error) {
+		return h.ReadResource(context.Background(), uri)
+	}
+
+	list := readInstancePage(t, read, "aws://ec2/instances")
+	require.True(t, list.Partial)
+	assert.Empty(t, h.inventory.Snapshots(), "a page is not a snapshot")
+	for list.NextCursor != "" {
+		list = readInstancePage(t, read, "aws://ec2/instances?cursor="+list.NextCursor)
+	}
+
+	// The next run warms up from the snapshot of all instances, which is
+	// listed in the background
+	require.Eventually(t, func() bool { return len(h.inventory.Snapshots()) == 1 }, 5*time.Second, 10*time.Millisecond)
+	restarted, err := inventory.Open(path, 0)
+	require.NoError(t, err)
+	snapshot, ok := restarted.Warm("aws://ec2/instances")
+	require.True(t, ok)
+	assert.Len(t, snapshot.Instances, 23)
+}
+
+func TestInstanceListPagesOfSnapshot(t *testing.T) {
+	logger := logging.NewLogger("error", "text")
+	fleet := fake.New(config.FakeConfig{Seed: 3}, "us-east-1")
+	cfg := &config.Config{
+		Inventory: config.InventoryConfig{RefreshInterval: time.Hour},
+		Response:  config.ResponseConfig{PageSize: 500},
+	}
+	h := NewResourceHandler(cfg, aws.NewClientFromConfig(fleet.Config(), logger))
+	h.inventory = inventory.NewCache()
+	var instances []types.CloudResource
+	for i := 0; i < 9; i++ {
+		instances = append(instances, types.CloudResource{ID: fmt.Sprintf("i-%02d", i), Provider: "aws", State: "running"})
+	}
+	require.NoError(t, h.inventory.Put("aws://ec2/instances", instances, time.Now()))
+	read := func(uri string) (*mcp.ReadResourceResult, error) {
+		return h.ReadResource(context.Background(), uri)
+	}
+
+	list := readInstancePage(t, read, "aws://ec2/instances")
+	assert.Len(t, list.Instances, 9, "the whole list fits the configured page")
+	assert.Empty(t, list.NextCursor)
+
+	list = readInstancePage(t, read, "aws://ec2/instances?limit=4")
+	assert.Equal(t, []string{"i-00", "i-01", "i-02", "i-03"}, instanceIDs(list))
+	assert.Equal(t, 9, list.TotalInstances, "totals cover the whole snapshot")
+	assert.Equal(t, 9, list.SummaryByState["running"])
+	assert.False(t, list.Partial)
+
+	list = readInstancePage(t, read, "aws://ec2/instances?limit=4&cursor="+list.NextCursor)
+	assert.Equal(t, []string{"i-04", "i-05", "i-06", "i-07"}, instanceIDs(list))
+	list = readInstancePage(t, read, "aws://ec2/instances?limit=4&cursor="+list.NextCursor)
+	assert.Equal(t, []string{"i-08"}, instanceIDs(list))
+	assert.Empty(t, list.NextCursor)
+}
+
+func TestInstancePageRejectsInvalidQueries(t *testing.T) {
+	logger := logging.NewLogger("error", "text")
+	fleet := fake.New(config.FakeConfig{Seed: 3}, "us-east-1")
+	h := NewResourceHandler(&config.Config{}, aws.NewClientFromConfig(fleet.Config(), logger))
+
+	_, err := h.ReadResource(context.Background(), "aws://ec2/instances?cursor=bogus")
+	assert.ErrorIs(t, err, errInvalidCursor)
+	_, err = h.ReadResource(context.Background(), "aws://ec2/instances?limit=0")
+	assert.ErrorContains(t, err, `invalid limit "0"`)
+}
+
+func instanceIDs(list instanceList) []string {
+	ids := make([]string, 0, len(list.Instances))
+	for _, instance := range list.Instances {
+		ids = append(ids, instance.ID)
+	}
+	return ids
+}
@@ -0,0 +1,295 @@
+package mcp
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"net/url"
+	"slices"
+	"sort"
+	"strings"
+
+	"aws-mcp-server/pkg/aws"
+	"aws-mcp-server/pkg/types"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const (
+	// parametersURI lists the SSM parameters under the configured path prefix
+	parametersURI = "aws://ssm/parameters"
+	// parametersPathTemplate lists the parameters under a narrower path
+	parametersPathTemplate = "aws://ssm/parameters{?path}"
+	// parameterTemplate is the URI template of one parameter, by its name
+	parameterTemplate = "aws://ssm/parameters/{+name}"
+	// redactedValue replaces SecureString values in responses, logs and audit entries
+	redactedValue = "[redacted]"
+	// secureStringType is the parameter type encrypted with KMS
+	secureStringType = "SecureString"
+)
+
+// parameterTypes are the SSM parameter types put-parameter accepts
+var parameterTypes = []string{"String", "StringList", secureStringType}
+
+// parameterPathPrefix returns the configured path prefix without a trailing slash
+func parameterPathPrefix(prefix string) string {
+	prefix = strings.TrimSpace(prefix)
+	if !strings.HasPrefix(prefix, "/") {
+		prefix = "/" + prefix
+	}
+	if prefix != "/" {
+		prefix = strings.TrimSuffix(prefix, "/")
+	}
+	return prefix
+}
+
+// underParameterPath reports whether a parameter name or path is within prefix
+func underParameterPath(name, prefix string) bool {
+	if prefix == "/" {
+		return strings.HasPrefix(name, "/")
+	}
+	return name == prefix || strings.HasPrefix(name, prefix+"/")
+}
+
+// readParameters lists the parameters under the path prefix, or a narrower
+// path given as ?path=, with SecureString values redacted unless configured
+func (h *ResourceHandler) readParameters(ctx context.Context, uri string) (*mcp.ReadResourceResult, error) {
+	parsed, err := url.Parse(uri)
+	if err != nil {
+		return nil, fmt.Errorf("invalid parameters URI: %w", err)
+	}
+
+	prefix := parameterPathPrefix(h.config.Parameters.PathPrefix)
+	path := prefix
+	if value := strings.TrimSpace(parsed.Query().Get("path")); value != "" {
+		path = parameterPathPrefix(value)
+		if !underParameterPath(path, prefix) {
+			return nil, fmt.Errorf("path %s is outside the configured prefix %s", path, prefix)
+		}
+	}
+
+	reveal := h.config.Parameters.RevealSecureValues
+	parameters, err := h.awsClient.ListParameters(ctx, path, reveal)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list SSM parameters: %w", err)
+	}
+
+	sort.Slice(parameters, func(i, j int) bool { return parameters[i].Name < parameters[j].Name })
+	byType := make(map[string]int)
+	items := make([]map[string]interface{}, 0, len(parameters))
+	for _, parameter := range parameters {
+		byType[parameter.Type]++
+		items = append(items, h.formatParameter(parameter, reveal))
+	}
+
+	data := map[string]interface{}{
+		"path":       path,
+		"total":      len(parameters),
+		"by_type":    byType,
+		"parameters": items,
+	}
+	if !reveal && byType[secureStringType] > 0 {
+		data["note"] = "SecureString values are redacted; set parameters.reveal_secure_values to show them"
+	}
+
+	jsonData, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal parameters data: %w", err)
+	}
+
+	return &mcp.ReadResourceResult{
+		Contents: []mcp.ResourceContents{
+			&mcp.TextResourceContents{
+				URI:      uri,
+				MIMEType: "application/json",
+				Text:     string(jsonData),
+			},
+		},
+	}, nil
+}
+
+// readParameter returns one parameter by the name in the URI, e.g.
+// aws://ssm/parameters/app/prod/db-host for /app/prod/db-host
+func (h *ResourceHandler) readParameter(ctx context.Context, uri string) (*mcp.ReadResourceResult, error) {
+	name, _ := strings.CutPrefix(uri, parametersURI)
+	if unescaped, err := url.PathUnescape(name); err == nil {
+		name = unescaped
+	}
+	if name == "/" || name == "" {
+		return nil, fmt.Errorf("invalid parameter URI %s, use %s", uri, parameterTemplate)
+	}
+
+	prefix := parameterPathPrefix(h.config.Parameters.PathPrefix)
+	if !underParameterPath(name, prefix) {
+		return nil, fmt.Errorf("parameter %s is outside the configured prefix %s", name, prefix)
+	}
+
+	reveal := h.config.Parameters.RevealSecureValues
+	parameter, err := h.awsClient.GetParameter(ctx, name, reveal)
+	if err != nil {
+		return nil, err
+	}
+
+	jsonData, err := json.MarshalIndent(h.formatParameter(*parameter, reveal), "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal parameter data: %w", err)
+	}
+
+	return &mcp.ReadResourceResult{
+		Contents: []mcp.ResourceContents{
+			&mcp.TextResourceContents{
+				URI:      uri,
+				MIMEType: "application/json",
+				Text:     string(jsonData),
+			},
+		},
+	}, nil
+}
+
+// formatParameter converts a parameter for responses, redacting SecureString
+// values unless reveal is set
+func (h *ResourceHandler) formatParameter(parameter types.Parameter, reveal bool) map[string]interface{} {
+	item := map[string]interface{}{
+		"name":          parameter.Name,
+		"type":          parameter.Type,
+		"version":       parameter.Version,
+		"last_modified": h.times.Format(parameter.LastModified),
+		"uri":           parametersURI + parameter.Name,
+		"value":         parameter.Value,
+	}
+	if parameter.Type == secureStringType && !reveal {
+		item["value"] = redactedValue
+	}
+	if parameter.DataType != "" && parameter.DataType != "text" {
+		item["data_type"] = parameter.DataType
+	}
+	return item
+}
+
+// putParameter creates or updates a parameter under the path prefix, e.g. to
+// put back a value that drifted. Without confirm=true it only returns the
+// current and new value.
+func (h *ToolHandler) putParameter(ctx context.Context, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	params := aws.PutParameterParams{KMSKeyID: h.config.Parameters.KMSKeyID}
+	params.Name, _ = arguments["name"].(string)
+	params.Name = strings.TrimSpace(params.Name)
+	if params.Name == "" {
+		return h.createErrorResponse("name is required")
+	}
+	value, ok := arguments["value"].(string)
+	if !ok || value == "" {
+		return h.createErrorResponse("value is required")
+	}
+	params.Value = value
+	params.Type, _ = arguments["type"].(string)
+	if params.Type != "" && !slices.Contains(parameterTypes, params.Type) {
+		return h.createErrorResponse(fmt.Sprintf("invalid type %q, use %s", params.Type, strings.Join(parameterTypes, ", ")))
+	}
+	params.Description, _ = arguments["description"].(string)
+
+	prefix := parameterPathPrefix(h.config.Parameters.PathPrefix)
+	if !underParameterPath(params.Name, prefix) {
+		return h.createErrorResponse(fmt.Sprintf("parameter %s is outside the configured prefix %s", params.Name, prefix))
+	}
+
+	reveal := h.config.Parameters.RevealSecureValues
+	current, err := h.awsClient.GetParameter(ctx, params.Name, reveal)
+	if err != nil && !errors.Is(err, aws.ErrParameterNotFound) {
+		return h.createErrorResponse(fmt.Sprintf("failed to get parameter: %v", err))
+	}
+
+	action := "created"
+	if current != nil {
+		action = "updated"
+		params.Type = cmp.Or(params.Type, current.Type)
+		params.Overwrite = true
+		if current.Type == params.Type && current.Value == params.Value && (current.Type != secureStringType || reveal) {
+			return h.createSuccessResponse("Parameter already has this value", map[string]interface{}{
+				"name":    params.Name,
+				"type":    current.Type,
+				"version": current.Version,
+				"action":  "unchanged",
+			})
+		}
+	}
+	params.Type = cmp.Or(params.Type, "String")
+
+	if !isConfirmed(arguments) {
+		plan, warnings := parameterPlan(current, params, reveal)
+		return h.createConfirmationResponse("put-parameter", plan, warnings)
+	}
+
+	version, err := h.awsClient.PutParameter(ctx, params)
+	if err != nil {
+		return h.createErrorResponse(fmt.Sprintf("failed to put parameter: %v", err))
+	}
+
+	data := map[string]interface{}{
+		"name":    params.Name,
+		"type":    params.Type,
+		"version": version,
+		"action":  action,
+	}
+	if current != nil {
+		data["previous_version"] = current.Version
+	}
+
+	return h.createSuccessResponse(fmt.Sprintf("Parameter %s successfully", action), data)
+}
+
+// parameterPlan describes what put-parameter would change, with SecureString
+// values redacted unless reveal is set
+func parameterPlan(current *types.Parameter, params aws.PutParameterParams, reveal bool) (map[string]interface{}, []string) {
+	shown := func(parameterType, value string) string {
+		if parameterType == secureStringType && !reveal {
+			return redactedValue
+		}
+		return value
+	}
+
+	plan := map[string]interface{}{
+		"name":      params.Name,
+		"action":    "create",
+		"type":      params.Type,
+		"new_value": shown(params.Type, params.Value),
+	}
+	if params.Description != "" {
+		plan["description"] = params.Description
+	}
+
+	warnings := []string{"Applications pick up the new value the next time they read the parameter, which for many is on restart"}
+	if current != nil {
+		plan["action"] = "update"
+		plan["current_value"] = shown(current.Type, current.Value)
+		plan["current_version"] = current.Version
+		warnings = append(warnings, fmt.Sprintf("Version %d stays in the parameter history if the change has to be rolled back", current.Version))
+		if current.Type != params.Type {
+			warnings = append(warnings, fmt.Sprintf("The type changes from %s to %s", current.Type, params.Type))
+			if current.Type == secureStringType {
+				warnings = append(warnings, "The value will no longer be encrypted")
+			}
+		}
+	}
+	return plan, warnings
+}
+
+// redactArguments returns the arguments of a call as they may be logged,
+// audited, recorded and sent in notifications: values written by put-parameter are
+// replaced unless the call explicitly writes a plain String or StringList
+func redactArguments(name string, arguments map[string]interface{}) map[string]interface{} {
+	if name != "put-parameter" {
+		return arguments
+	}
+	if parameterType, _ := arguments["type"].(string); parameterType == "String" || parameterType == "StringList" {
+		return arguments
+	}
+	if _, ok := arguments["value"]; !ok {
+		return arguments
+	}
+	redacted := maps.Clone(arguments)
+	redacted["value"] = redactedValue
+	return redacted
+}
@@ -0,0 +1,88 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"aws-mcp-server/internal/config"
+	"aws-mcp-server/internal/logging"
+	"aws-mcp-server/pkg/audit"
+	"aws-mcp-server/pkg/aws"
+	"aws-mcp-server/pkg/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParameterPaths(t *testing.T) {
+	assert.Equal(t, "/", parameterPathPrefix(""))
+	assert.Equal(t, "/app/prod", parameterPathPrefix("app/prod/"))
+
+	assert.True(t, underParameterPath("/app/prod/db-host", "/app/prod"))
+	assert.True(t, underParameterPath("/app/prod", "/app/prod"))
+	assert.False(t, underParameterPath("/app/production/db-host", "/app/prod"), "sibling paths sharing a prefix are outside")
+	assert.True(t, underParameterPath("/anything", "/"))
+	assert.False(t, underParameterPath("flat-name", "/"))
+}
+
+func TestParameterPlan(t *testing.T) {
+	current := &types.Parameter{Name: "/app/prod/db-password", Type: secureStringType, Value: "old", Version: 4}
+	params := aws.PutParameterParams{Name: "/app/prod/db-password", Type: "String", Value: "new"}
+
+	plan, warnings := parameterPlan(current, params, false)
+	assert.Equal(t, "update", plan["action"])
+	assert.Equal(t, redactedValue, plan["current_value"])
+	assert.Equal(t, "new", plan["new_value"])
+	assert.Equal(t, int64(4), plan["current_version"])
+	assert.Contains(t, warnings, "The value will no longer be encrypted")
+
+	plan, _ = parameterPlan(current, params, true)
+	assert.Equal(t, "old", plan["current_value"], "values are shown when reveal_secure_values is set")
+
+	plan, warnings = parameterPlan(nil, aws.PutParameterParams{Name: "/app/prod/token", Type: secureStringType, Value: "secret"}, false)
+	assert.Equal(t, "create", plan["action"])
+	assert.Equal(t, redactedValue, plan["new_value"])
+	assert.Len(t, warnings, 1)
+}
+
+func TestRedactArguments(t *testing.T) {
+	arguments := map[string]interface{}{"name": "/app/prod/token", "value": "secret", "type": secureStringType}
+	redacted := redactArguments("put-parameter", arguments)
+	assert.Equal(t, redactedValue, redacted["value"])
+	assert.Equal(t, "secret", arguments["value"], "the arguments of the call are not modified")
+
+	untyped := redactArguments("put-parameter", map[string]interface{}{"name": "/app/prod/token", "value": "secret"})
+	assert.Equal(t, redactedValue, untyped["value"], "the current type may be SecureString")
+
+	plain := redactArguments("put-parameter", map[string]interface{}{"value": "v2", "type": "String"})
+	assert.Equal(t, "v2", plain["value"])
+
+	other := map[string]interface{}{"value": "x"}
+	assert.Equal(t, "x", redactArguments("publish-sns-message", other)["value"])
+}
+
+func TestPutParameterValidation(t *testing.T) {
+	cfg := &config.Config{Parameters: config.ParametersConfig{PathPrefix: "/app/prod"}}
+	h := NewToolHandler(cfg, nil, logging.NewLogger("error", "text"))
+	h.audit, _ = audit.Open("", 0)
+	ctx := context.Background()
+
+	result, err := h.CallTool(ctx, "put-parameter", map[string]interface{}{"name": "/app/prod/x"})
+	require.NoError(t, err)
+	assert.Equal(t, "value is required", decodeToolResult(t, result)["error"])
+
+	result, err = h.CallTool(ctx, "put-parameter", map[string]interface{}{"name": "/app/prod/x", "value": "v", "type": "Binary"})
+	require.NoError(t, err)
+	assert.Contains(t, decodeToolResult(t, result)["error"], "invalid type")
+
+	result, err = h.CallTool(ctx, "put-parameter", map[string]interface{}{
+		"name": "/app/staging/token", "value": "secret", "type": secureStringType, "confirm": true,
+	})
+	require.NoError(t, err)
+	assert.Contains(t, decodeToolResult(t, result)["error"], "outside the configured prefix /app/prod")
+
+	entries := h.audit.Between(time.Now().Add(-time.Minute), time.Now())
+	require.Len(t, entries, 1)
+	assert.Equal(t, redactedValue, entries[0].Arguments["value"], "SecureString values are not audited")
+}
@@ -0,0 +1,572 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"aws-mcp-server/pkg/aws"
+	"aws-mcp-server/pkg/types"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const (
+	// managedInstancesURI lists the SSM managed instances with agent and patch status
+	managedInstancesURI = "aws://ssm/instances"
+	// instanceInventoryTemplate is the URI template of the installed
+	// applications of one instance, optionally filtered by name
+	instanceInventoryTemplate = "aws://ssm/instances/{instanceId}/inventory{?name}"
+	// instancePatchesTemplate is the URI template of the patch compliance of one instance
+	instancePatchesTemplate = "aws://ssm/instances/{instanceId}/patches"
+	// patchScanMaxAge is how old the last patch scan may be before its
+	// compliance data is reported as stale
+	patchScanMaxAge = 7 * 24 * time.Hour
+	// maxPatchInstances keeps a single patch run reviewable
+	maxPatchInstances = 50
+)
+
+// Patch compliance statuses of an instance, in the order they need attention
+const (
+	patchFailed        = "failed"
+	patchNonCompliant  = "non-compliant"
+	patchPendingReboot = "pending-reboot"
+	patchNotScanned    = "not-scanned"
+	patchStale         = "stale"
+	patchCompliant     = "compliant"
+)
+
+// patchSeverity orders instances so those needing attention come first
+var patchSeverity = map[string]int{
+	patchFailed:        0,
+	patchNonCompliant:  1,
+	patchPendingReboot: 2,
+	patchNotScanned:    3,
+	patchStale:         4,
+	patchCompliant:     5,
+}
+
+// patchOperations are the AWS-RunPatchBaseline operations run-patch-baseline accepts
+var patchOperations = []string{"Scan", "Install"}
+
+// patchCompliance tells whether an instance is patched and why not, from the
+// result of its last patch operation. A nil state means it was never scanned.
+func patchCompliance(state *types.InstancePatchState, now time.Time) (string, string) {
+	if state == nil {
+		return patchNotScanned, "no patch scan has reported for the instance"
+	}
+	switch {
+	case state.FailedCount > 0:
+		return patchFailed, fmt.Sprintf("%d %s failed to install", state.FailedCount, plural(int(state.FailedCount), "patch", "patches"))
+	case state.MissingCount > 0:
+		detail := fmt.Sprintf("%d missing %s", state.MissingCount, plural(int(state.MissingCount), "patch", "patches"))
+		if state.CriticalNonCompliant > 0 {
+			detail += fmt.Sprintf(", %d critical", state.CriticalNonCompliant)
+		}
+		if state.SecurityNonCompliant > 0 {
+			detail += fmt.Sprintf(", %d security", state.SecurityNonCompliant)
+		}
+		return patchNonCompliant, detail
+	case state.PendingRebootCount > 0:
+		return patchPendingReboot, fmt.Sprintf("%d installed %s wait for a reboot", state.PendingRebootCount, plural(int(state.PendingRebootCount), "patch", "patches"))
+	case now.Sub(state.OperationEndTime) > patchScanMaxAge:
+		return patchStale, fmt.Sprintf("last patch %s finished %d days ago", strings.ToLower(state.Operation), daysSince(state.OperationEndTime, now))
+	}
+	return patchCompliant, ""
+}
+
+// plural picks the singular or plural form of a word for a count
+func plural(count int, singular, pluralForm string) string {
+	if count == 1 {
+		return singular
+	}
+	return pluralForm
+}
+
+// windowCoversInstance reports whether a maintenance window target selects
+// the instance. Targets that cannot be evaluated from the instance ID and
+// tags, such as resource groups, are reported as unknown.
+func windowCoversInstance(window types.MaintenanceWindow, instanceID string, tags map[string]string) (covers bool, unknown bool) {
+	for _, target := range window.Targets {
+		key := target.Key
+		switch {
+		case strings.EqualFold(key, "InstanceIds"):
+			if slices.Contains(target.Values, instanceID) || slices.Contains(target.Values, "*") {
+				return true, false
+			}
+		case strings.HasPrefix(key, "tag:"):
+			if value, ok := tags[strings.TrimPrefix(key, "tag:")]; ok && slices.Contains(target.Values, value) {
+				return true, false
+			}
+		case strings.EqualFold(key, "tag-key"):
+			for _, tagKey := range target.Values {
+				if _, ok := tags[tagKey]; ok {
+					return true, false
+				}
+			}
+		default:
+			unknown = true
+		}
+	}
+	return false, unknown
+}
+
+// patchWindowsFor returns the enabled maintenance windows that patch the
+// instance, and those whose targets could not be evaluated
+func patchWindowsFor(windows []types.MaintenanceWindow, instanceID string, tags map[string]string) (covering, unknown []types.MaintenanceWindow) {
+	for _, window := range windows {
+		if !window.Enabled || !window.PatchTasks {
+			continue
+		}
+		covers, undecided := windowCoversInstance(window, instanceID, tags)
+		switch {
+		case covers:
+			covering = append(covering, window)
+		case undecided:
+			unknown = append(unknown, window)
+		}
+	}
+	return covering, unknown
+}
+
+// formatMaintenanceWindow describes a window and when it runs next
+func (h *ResourceHandler) formatMaintenanceWindow(window types.MaintenanceWindow) map[string]interface{} {
+	item := map[string]interface{}{
+		"id":             window.ID,
+		"name":           window.Name,
+		"schedule":       window.Schedule,
+		"duration_hours": window.DurationHours,
+	}
+	if window.Timezone != "" {
+		item["timezone"] = window.Timezone
+	}
+	if window.NextExecution != nil {
+		item["next_execution"] = h.times.Format(*window.NextExecution)
+	}
+	return item
+}
+
+// readManagedInstances lists the SSM managed instances with their agent state
+// and patch compliance, those needing attention first
+func (h *ResourceHandler) readManagedInstances(ctx context.Context) (*mcp.ReadResourceResult, error) {
+	instances, err := h.awsClient.ListManagedInstances(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list SSM managed instances: %w", err)
+	}
+
+	instanceIDs := make([]string, 0, len(instances))
+	for _, instance := range instances {
+		instanceIDs = append(instanceIDs, instance.InstanceID)
+	}
+	states, err := h.awsClient.ListInstancePatchStates(ctx, instanceIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list patch states: %w", err)
+	}
+
+	jsonData, err := json.MarshalIndent(h.formatManagedInstances(instances, states, time.Now()), "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal managed instances data: %w", err)
+	}
+
+	return &mcp.ReadResourceResult{
+		Contents: []mcp.ResourceContents{
+			&mcp.TextResourceContents{
+				URI:      managedInstancesURI,
+				MIMEType: "application/json",
+				Text:     string(jsonData),
+			},
+		},
+	}, nil
+}
+
+// formatManagedInstances joins the managed instances with their patch states,
+// orders them by compliance and counts offline agents and outdated versions
+func (h *ResourceHandler) formatManagedInstances(instances []types.ManagedInstance, states []types.InstancePatchState, now time.Time) map[string]interface{} {
+	byInstance := make(map[string]*types.InstancePatchState, len(states))
+	for i := range states {
+		byInstance[states[i].InstanceID] = &states[i]
+	}
+
+	items := make([]map[string]interface{}, 0, len(instances))
+	byStatus := make(map[string]int)
+	offline, outdated := 0, 0
+	for _, instance := range instances {
+		state := byInstance[instance.InstanceID]
+		status, detail := patchCompliance(state, now)
+		byStatus[status]++
+		if instance.PingStatus != "Online" {
+			offline++
+		}
+		if !instance.LatestAgent {
+			outdated++
+		}
+
+		item := map[string]interface{}{
+			"instance_id":   instance.InstanceID,
+			"ping_status":   instance.PingStatus,
+			"agent_version": instance.AgentVersion,
+			"latest_agent":  instance.LatestAgent,
+			"platform":      strings.TrimSpace(instance.PlatformName + " " + instance.PlatformVersion),
+			"patch_status":  status,
+			"patches_uri":   fmt.Sprintf("%s/%s/patches", managedInstancesURI, instance.InstanceID),
+			"inventory_uri": fmt.Sprintf("%s/%s/inventory", managedInstancesURI, instance.InstanceID),
+		}
+		if instance.ComputerName != "" {
+			item["computer_name"] = instance.ComputerName
+		}
+		if instance.LastPingAt != nil {
+			item["last_ping"] = h.times.Format(*instance.LastPingAt)
+		}
+		if detail != "" {
+			item["patch_detail"] = detail
+		}
+		if state != nil {
+			item["last_patch_operation"] = state.Operation
+			item["last_patch_time"] = h.times.Format(state.OperationEndTime)
+			if state.PatchGroup != "" {
+				item["patch_group"] = state.PatchGroup
+			}
+		}
+		items = append(items, item)
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		si, sj := patchSeverity[items[i]["patch_status"].(string)], patchSeverity[items[j]["patch_status"].(string)]
+		if si != sj {
+			return si < sj
+		}
+		return items[i]["instance_id"].(string) < items[j]["instance_id"].(string)
+	})
+
+	return map[string]interface{}{
+		"total":           len(instances),
+		"by_patch_status": byStatus,
+		"offline_agents":  offline,
+		"outdated_agents": outdated,
+		"instances":       items,
+	}
+}
+
+// managedInstanceID returns the instance ID from an aws://ssm/instances URI
+// with the given suffix
+func managedInstanceID(uri, suffix, template string) (string, error) {
+	path := strings.TrimPrefix(uri, managedInstancesURI+"/")
+	path, _, _ = strings.Cut(path, "?")
+	instanceID, ok := strings.CutSuffix(path, suffix)
+	if !ok || instanceID == "" || strings.Contains(instanceID, "/") {
+		return "", fmt.Errorf("invalid URI %s, use %s", uri, template)
+	}
+	return instanceID, nil
+}
+
+// readInstanceInventory returns the applications installed on an instance,
+// optionally only those whose name contains ?name=
+func (h *ResourceHandler) readInstanceInventory(ctx context.Context, uri string) (*mcp.ReadResourceResult, error) {
+	instanceID, err := managedInstanceID(uri, "/inventory", instanceInventoryTemplate)
+	if err != nil {
+		return nil, err
+	}
+	var filter string
+	if parsed, err := url.Parse(uri); err == nil {
+		filter = strings.TrimSpace(parsed.Query().Get("name"))
+	}
+
+	applications, err := h.awsClient.ListInventoryApplications(ctx, instanceID)
+	if err != nil {
+		return nil, err
+	}
+
+	jsonData, err := json.MarshalIndent(formatInventory(instanceID, applications, filter), "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal inventory data: %w", err)
+	}
+
+	return &mcp.ReadResourceResult{
+		Contents: []mcp.ResourceContents{
+			&mcp.TextResourceContents{
+				URI:      uri,
+				MIMEType: "application/json",
+				Text:     string(jsonData),
+			},
+		},
+	}, nil
+}
+
+// formatInventory sorts the installed applications by name and keeps those
+// whose name contains filter, case-insensitively
+func formatInventory(instanceID string, applications []types.InstalledApplication, filter string) map[string]interface{} {
+	matched := make([]types.InstalledApplication, 0, len(applications))
+	for _, application := range applications {
+		if filter == "" || strings.Contains(strings.ToLower(application.Name), strings.ToLower(filter)) {
+			matched = append(matched, application)
+		}
+	}
+	sort.SliceStable(matched, func(i, j int) bool {
+		return strings.ToLower(matched[i].Name) < strings.ToLower(matched[j].Name)
+	})
+
+	data := map[string]interface{}{
+		"instance_id":  instanceID,
+		"total":        len(applications),
+		"matched":      len(matched),
+		"applications": matched,
+	}
+	if filter != "" {
+		data["filter"] = filter
+	}
+	if len(applications) == 0 {
+		data["note"] = "SSM Inventory has no applications for the instance; check that an inventory association targets it"
+	}
+	return data
+}
+
+// readInstancePatches returns the patch compliance of an instance with the
+// patches that are missing, failed or wait for a reboot, and the maintenance
+// windows that patch it
+func (h *ResourceHandler) readInstancePatches(ctx context.Context, uri string) (*mcp.ReadResourceResult, error) {
+	instanceID, err := managedInstanceID(uri, "/patches", instancePatchesTemplate)
+	if err != nil {
+		return nil, err
+	}
+
+	states, err := h.awsClient.ListInstancePatchStates(ctx, []string{instanceID})
+	if err != nil {
+		return nil, err
+	}
+	var state *types.InstancePatchState
+	if len(states) > 0 {
+		state = &states[0]
+	}
+
+	status, detail := patchCompliance(state, time.Now())
+	data := map[string]interface{}{
+		"instance_id":  instanceID,
+		"patch_status": status,
+	}
+	if detail != "" {
+		data["detail"] = detail
+	}
+	if state != nil {
+		data["baseline_id"] = state.BaselineID
+		data["patch_group"] = state.PatchGroup
+		data["last_operation"] = state.Operation
+		data["last_operation_time"] = h.times.Format(state.OperationEndTime)
+		data["counts"] = map[string]int32{
+			"installed":      state.InstalledCount,
+			"missing":        state.MissingCount,
+			"failed":         state.FailedCount,
+			"pending_reboot": state.PendingRebootCount,
+			"critical":       state.CriticalNonCompliant,
+			"security":       state.SecurityNonCompliant,
+		}
+
+		patches, err := h.awsClient.ListInstancePatches(ctx, instanceID)
+		if err != nil {
+			return nil, err
+		}
+		sort.SliceStable(patches, func(i, j int) bool { return patches[i].State < patches[j].State })
+		data["patches"] = patches
+	}
+
+	// Windows are context for when the instance gets patched next, so a
+	// failure to read them does not fail the resource
+	if windows, err := h.awsClient.ListMaintenanceWindows(ctx); err != nil {
+		data["maintenance_windows_error"] = err.Error()
+	} else {
+		covering, unknown := patchWindowsFor(windows, instanceID, h.instanceTags(ctx, instanceID))
+		formatted := make([]map[string]interface{}, 0, len(covering))
+		for _, window := range covering {
+			formatted = append(formatted, h.formatMaintenanceWindow(window))
+		}
+		data["maintenance_windows"] = formatted
+		if len(unknown) > 0 {
+			data["note"] = fmt.Sprintf("%d patching %s target resource groups and may also cover the instance",
+				len(unknown), plural(len(unknown), "window", "windows"))
+		}
+	}
+
+	jsonData, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal patch data: %w", err)
+	}
+
+	return &mcp.ReadResourceResult{
+		Contents: []mcp.ResourceContents{
+			&mcp.TextResourceContents{
+				URI:      uri,
+				MIMEType: "application/json",
+				Text:     string(jsonData),
+			},
+		},
+	}, nil
+}
+
+// instanceTags returns the tags of an EC2 instance, or none when it cannot be
+// read, e.g. for on-premises managed instances
+func (h *ResourceHandler) instanceTags(ctx context.Context, instanceID string) map[string]string {
+	return ec2InstanceTags(ctx, h.awsClient, []string{instanceID})[instanceID]
+}
+
+// ec2InstanceTags returns the tags of the given EC2 instances by ID
+func ec2InstanceTags(ctx context.Context, client *aws.Client, instanceIDs []string) map[string]map[string]string {
+	tags := make(map[string]map[string]string, len(instanceIDs))
+	instances, err := client.ListEC2Instances(ctx)
+	if err != nil {
+		return tags
+	}
+	for _, instance := range instances {
+		if slices.Contains(instanceIDs, instance.ID) {
+			tags[instance.ID] = instance.Tags
+		}
+	}
+	return tags
+}
+
+// runPatchBaseline runs AWS-RunPatchBaseline on managed instances. Scans run
+// right away; installs return a plan until confirmed and are refused on
+// instances a maintenance window already patches unless
+// ignoreMaintenanceWindow=true.
+func (h *ToolHandler) runPatchBaseline(ctx context.Context, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	instanceIDs := stringList(arguments["instanceIds"])
+	if len(instanceIDs) == 0 {
+		return h.createErrorResponse("instanceIds is required")
+	}
+	if len(instanceIDs) > maxPatchInstances {
+		return h.createErrorResponse(fmt.Sprintf("at most %d instances can be patched at once, got %d", maxPatchInstances, len(instanceIDs)))
+	}
+	operation, _ := arguments["operation"].(string)
+	if operation == "" {
+		operation = "Scan"
+	}
+	if !slices.Contains(patchOperations, operation) {
+		return h.createErrorResponse(fmt.Sprintf("invalid operation %q, use %s", operation, strings.Join(patchOperations, " or ")))
+	}
+	reboot := true
+	if value, ok := arguments["reboot"].(bool); ok {
+		reboot = value
+	}
+
+	managed, err := h.awsClient.ListManagedInstances(ctx)
+	if err != nil {
+		return h.createErrorResponse(fmt.Sprintf("failed to list SSM managed instances: %v", err))
+	}
+	if problems := unreachableInstances(instanceIDs, managed); len(problems) > 0 {
+		return h.createErrorResponse(fmt.Sprintf("cannot patch: %s", strings.Join(problems, "; ")))
+	}
+
+	params := aws.RunPatchBaselineParams{InstanceIDs: instanceIDs, Operation: operation, Reboot: reboot}
+	if operation == "Install" {
+		windows, err := h.awsClient.ListMaintenanceWindows(ctx)
+		if err != nil {
+			return h.createErrorResponse(fmt.Sprintf("failed to list maintenance windows: %v", err))
+		}
+		tags := ec2InstanceTags(ctx, h.awsClient, instanceIDs)
+		ignoreWindows, _ := arguments["ignoreMaintenanceWindow"].(bool)
+
+		plan, warnings, covered := h.patchPlan(params, windows, tags)
+		if len(covered) > 0 && !ignoreWindows {
+			return h.createErrorResponse(fmt.Sprintf("%s patched by maintenance windows: %s; wait for the window or pass ignoreMaintenanceWindow=true to patch now",
+				plural(len(covered), "instance is", "instances are"), strings.Join(covered, "; ")))
+		}
+		if !isConfirmed(arguments) {
+			return h.createConfirmationResponse("run-patch-baseline", plan, warnings)
+		}
+	}
+
+	commandID, err := h.awsClient.RunPatchBaseline(ctx, params)
+	if err != nil {
+		return h.createErrorResponse(fmt.Sprintf("failed to run patch baseline: %v", err))
+	}
+
+	return h.createSuccessResponse(fmt.Sprintf("Patch %s started", strings.ToLower(operation)), map[string]interface{}{
+		"commandId":   commandID,
+		"operation":   operation,
+		"instanceIds": instanceIDs,
+		"reboot":      reboot,
+		"note":        fmt.Sprintf("The command runs asynchronously; read %s/{instanceId}/patches once it finishes for the new compliance", managedInstancesURI),
+	})
+}
+
+// unreachableInstances lists the requested instances that are not managed by
+// Systems Manager or whose agent is not online
+func unreachableInstances(instanceIDs []string, managed []types.ManagedInstance) []string {
+	byID := make(map[string]types.ManagedInstance, len(managed))
+	for _, instance := range managed {
+		byID[instance.InstanceID] = instance
+	}
+
+	var problems []string
+	for _, instanceID := range instanceIDs {
+		instance, ok := byID[instanceID]
+		switch {
+		case !ok:
+			problems = append(problems, fmt.Sprintf("%s is not managed by Systems Manager", instanceID))
+		case instance.PingStatus != "Online":
+			problems = append(problems, fmt.Sprintf("the SSM agent of %s is %s", instanceID, instance.PingStatus))
+		}
+	}
+	return problems
+}
+
+// patchPlan describes a patch install with the maintenance windows of each
+// instance. covered lists the instances an enabled patching window targets.
+func (h *ToolHandler) patchPlan(params aws.RunPatchBaselineParams, windows []types.MaintenanceWindow, tags map[string]map[string]string) (map[string]interface{}, []string, []string) {
+	var covered []string
+	unknown := make(map[string]bool)
+	instances := make([]map[string]interface{}, 0, len(params.InstanceIDs))
+	for _, instanceID := range params.InstanceIDs {
+		item := map[string]interface{}{"instance_id": instanceID}
+		covering, undecided := patchWindowsFor(windows, instanceID, tags[instanceID])
+		if len(covering) > 0 {
+			names := make([]string, 0, len(covering))
+			for _, window := range covering {
+				name := window.Name
+				if window.NextExecution != nil {
+					name += " next at " + h.times.Format(*window.NextExecution)
+				}
+				names = append(names, name)
+			}
+			item["maintenance_windows"] = names
+			covered = append(covered, fmt.Sprintf("%s (%s)", instanceID, strings.Join(names, ", ")))
+		}
+		for _, window := range undecided {
+			unknown[window.Name] = true
+		}
+		instances = append(instances, item)
+	}
+
+	rebootOption := "RebootIfNeeded"
+	if !params.Reboot {
+		rebootOption = "NoReboot"
+	}
+	plan := map[string]interface{}{
+		"operation":       params.Operation,
+		"reboot_option":   rebootOption,
+		"instances":       instances,
+		"max_concurrency": "25%",
+		"max_errors":      1,
+	}
+
+	var warnings []string
+	if params.Reboot {
+		warnings = append(warnings, "Instances reboot when a patch requires it; a quarter of them are patched at a time")
+	} else {
+		warnings = append(warnings, "Patches that need a reboot stay pending until the instances are rebooted")
+	}
+	if len(covered) > 0 {
+		warnings = append(warnings, fmt.Sprintf("Patching outside the maintenance window of %d %s", len(covered), plural(len(covered), "instance", "instances")))
+	}
+	if len(unknown) > 0 {
+		names := make([]string, 0, len(unknown))
+		for name := range unknown {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		warnings = append(warnings, fmt.Sprintf("Maintenance windows %s target resource groups and may also patch these instances", strings.Join(names, ", ")))
+	}
+	return plan, warnings, covered
+}
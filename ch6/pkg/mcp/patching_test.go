@@ -0,0 +1,157 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"aws-mcp-server/internal/config"
+	"aws-mcp-server/internal/logging"
+	"aws-mcp-server/pkg/aws"
+	"aws-mcp-server/pkg/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPatchCompliance(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	recent := now.Add(-time.Hour)
+
+	tests := []struct {
+		name   string
+		state  *types.InstancePatchState
+		status string
+		detail string
+	}{
+		{name: "never scanned", status: patchNotScanned},
+		{name: "failed install", state: &types.InstancePatchState{FailedCount: 1, MissingCount: 3, OperationEndTime: recent}, status: patchFailed, detail: "1 patch failed"},
+		{name: "missing", state: &types.InstancePatchState{MissingCount: 3, CriticalNonCompliant: 2, OperationEndTime: recent}, status: patchNonCompliant, detail: "3 missing patches, 2 critical"},
+		{name: "pending reboot", state: &types.InstancePatchState{PendingRebootCount: 2, OperationEndTime: recent}, status: patchPendingReboot, detail: "2 installed patches wait"},
+		{name: "old scan", state: &types.InstancePatchState{Operation: "Scan", OperationEndTime: now.AddDate(0, 0, -10)}, status: patchStale, detail: "last patch scan finished 10 days ago"},
+		{name: "compliant", state: &types.InstancePatchState{InstalledCount: 40, OperationEndTime: recent}, status: patchCompliant},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, detail := patchCompliance(tt.state, now)
+			assert.Equal(t, tt.status, status)
+			assert.Contains(t, detail, tt.detail)
+		})
+	}
+}
+
+func TestPatchWindowsFor(t *testing.T) {
+	windows := []types.MaintenanceWindow{
+		{ID: "mw-ids", Name: "by-id", Enabled: true, PatchTasks: true, Targets: []types.MaintenanceWindowTarget{{Key: "InstanceIds", Values: []string{"i-1"}}}},
+		{ID: "mw-tag", Name: "by-tag", Enabled: true, PatchTasks: true, Targets: []types.MaintenanceWindowTarget{{Key: "tag:Patch", Values: []string{"sunday"}}}},
+		{ID: "mw-off", Name: "disabled", PatchTasks: true, Targets: []types.MaintenanceWindowTarget{{Key: "InstanceIds", Values: []string{"i-2"}}}},
+		{ID: "mw-backup", Name: "backups", Enabled: true, Targets: []types.MaintenanceWindowTarget{{Key: "InstanceIds", Values: []string{"i-2"}}}},
+		{ID: "mw-rg", Name: "resource-group", Enabled: true, PatchTasks: true, Targets: []types.MaintenanceWindowTarget{{Key: "resource-groups:Name", Values: []string{"web"}}}},
+	}
+
+	covering, unknown := patchWindowsFor(windows, "i-1", nil)
+	require.Len(t, covering, 1)
+	assert.Equal(t, "mw-ids", covering[0].ID)
+	require.Len(t, unknown, 1)
+	assert.Equal(t, "mw-rg", unknown[0].ID)
+
+	covering, _ = patchWindowsFor(windows, "i-2", map[string]string{"Patch": "sunday"})
+	require.Len(t, covering, 1)
+	assert.Equal(t, "mw-tag", covering[0].ID, "disabled windows and windows without patch tasks do not count")
+}
+
+func TestFormatManagedInstances(t *testing.T) {
+	h := NewResourceHandler(&config.Config{}, nil)
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	data := h.formatManagedInstances([]types.ManagedInstance{
+		{InstanceID: "i-a", PingStatus: "Online", LatestAgent: true},
+		{InstanceID: "i-b", PingStatus: "ConnectionLost"},
+		{InstanceID: "i-c", PingStatus: "Online", LatestAgent: true},
+	}, []types.InstancePatchState{
+		{InstanceID: "i-a", OperationEndTime: now.Add(-time.Hour)},
+		{InstanceID: "i-c", MissingCount: 4, OperationEndTime: now.Add(-time.Hour)},
+	}, now)
+
+	assert.Equal(t, 1, data["offline_agents"])
+	assert.Equal(t, 1, data["outdated_agents"])
+	assert.Equal(t, map[string]int{patchCompliant: 1, patchNonCompliant: 1, patchNotScanned: 1}, data["by_patch_status"])
+	instances := data["instances"].([]map[string]interface{})
+	require.Len(t, instances, 3)
+	assert.Equal(t, "i-c", instances[0]["instance_id"])
+	assert.Equal(t, "i-b", instances[1]["instance_id"])
+	assert.Equal(t, "i-a", instances[2]["instance_id"])
+	assert.Equal(t, "aws://ssm/instances/i-c/patches", instances[0]["patches_uri"])
+}
+
+func TestFormatInventory(t *testing.T) {
+	data := formatInventory("i-1", []types.InstalledApplication{
+		{Name: "openssl-libs", Version: "3.0.8"},
+		{Name: "curl", Version: "8.5.0"},
+		{Name: "OpenSSL", Version: "3.0.8"},
+	}, "openssl")
+
+	assert.Equal(t, 3, data["total"])
+	assert.Equal(t, 2, data["matched"])
+	applications := data["applications"].([]types.InstalledApplication)
+	assert.Equal(t, "OpenSSL", applications[0].Name)
+	assert.Equal(t, "openssl-libs", applications[1].Name)
+}
+
+func TestManagedInstanceID(t *testing.T) {
+	instanceID, err := managedInstanceID("aws://ssm/instances/i-0abc/inventory?name=curl", "/inventory", instanceInventoryTemplate)
+	require.NoError(t, err)
+	assert.Equal(t, "i-0abc", instanceID)
+
+	_, err = managedInstanceID("aws://ssm/instances//patches", "/patches", instancePatchesTemplate)
+	assert.Error(t, err)
+}
+
+func TestPatchPlan(t *testing.T) {
+	h := NewToolHandler(&config.Config{}, nil, logging.NewLogger("error", "text"))
+	next := time.Date(2025, 6, 8, 2, 0, 0, 0, time.UTC)
+	windows := []types.MaintenanceWindow{
+		{Name: "sunday-patching", Enabled: true, PatchTasks: true, NextExecution: &next,
+			Targets: []types.MaintenanceWindowTarget{{Key: "tag:Patch", Values: []string{"sunday"}}}},
+	}
+
+	plan, warnings, covered := h.patchPlan(aws.RunPatchBaselineParams{
+		InstanceIDs: []string{"i-1", "i-2"}, Operation: "Install", Reboot: true,
+	}, windows, map[string]map[string]string{"i-2": {"Patch": "sunday"}})
+
+	require.Len(t, covered, 1)
+	assert.Contains(t, covered[0], "i-2 (sunday-patching next at")
+	assert.Equal(t, "RebootIfNeeded", plan["reboot_option"])
+	instances := plan["instances"].([]map[string]interface{})
+	assert.NotContains(t, instances[0], "maintenance_windows")
+	assert.Contains(t, instances[1], "maintenance_windows")
+	assert.Contains(t, warnings, "Patching outside the maintenance window of 1 instance")
+}
+
+func TestUnreachableInstances(t *testing.T) {
+	problems := unreachableInstances([]string{"i-1", "i-2", "i-3"}, []types.ManagedInstance{
+		{InstanceID: "i-1", PingStatus: "Online"},
+		{InstanceID: "i-2", PingStatus: "ConnectionLost"},
+	})
+	assert.Equal(t, []string{
+		"the SSM agent of i-2 is ConnectionLost",
+		"i-3 is not managed by Systems Manager",
+	}, problems)
+}
+
+func TestRunPatchBaselineValidation(t *testing.T) {
+	h := NewToolHandler(&config.Config{}, nil, logging.NewLogger("error", "text"))
+	ctx := context.Background()
+
+	result, err := h.CallTool(ctx, "run-patch-baseline", map[string]interface{}{})
+	require.NoError(t, err)
+	assert.Equal(t, "instanceIds is required", decodeToolResult(t, result)["error"])
+
+	result, err = h.CallTool(ctx, "run-patch-baseline", map[string]interface{}{"instanceIds": []interface{}{"i-1"}, "operation": "Reboot"})
+	require.NoError(t, err)
+	assert.Contains(t, decodeToolResult(t, result)["error"], `invalid operation "Reboot"`)
+
+	assert.False(t, isMutating("run-patch-baseline", map[string]interface{}{"operation": "Scan", "confirm": true}))
+	assert.True(t, isMutating("run-patch-baseline", map[string]interface{}{"operation": "Install", "confirm": true}))
+}
@@ -0,0 +1,144 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"aws-mcp-server/pkg/postmortem"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const (
+	// defaultPostmortemEvents is how many events each source contributes by default
+	defaultPostmortemEvents = 200
+	// maxPostmortemEvents caps each source so drafts stay readable
+	maxPostmortemEvents = 1000
+)
+
+// draftPostmortem assembles a timeline for an incident window from alarm
+// history, CloudTrail changes and the audit log, and renders it as markdown.
+// Sources that cannot be read are reported as gaps instead of failing the draft.
+func (h *ToolHandler) draftPostmortem(ctx context.Context, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	now := time.Now()
+
+	startArg, _ := arguments["start"].(string)
+	if startArg == "" {
+		return h.createErrorResponse("start is required, e.g. 2025-06-01T09:30:00Z or now-3h")
+	}
+	start, err := parseTimeParam(startArg, now)
+	if err != nil {
+		return h.createErrorResponse(fmt.Sprintf("invalid start: %v", err))
+	}
+	endArg, _ := arguments["end"].(string)
+	end, err := parseTimeParam(endArg, now)
+	if err != nil {
+		return h.createErrorResponse(fmt.Sprintf("invalid end: %v", err))
+	}
+	if !end.After(start) {
+		return h.createErrorResponse("end must be after start")
+	}
+
+	limit := defaultPostmortemEvents
+	if value, ok := arguments["maxEvents"].(float64); ok && value > 0 {
+		limit = int(value)
+	}
+	if limit > maxPostmortemEvents {
+		limit = maxPostmortemEvents
+	}
+
+	title, _ := arguments["title"].(string)
+	if title == "" {
+		title = "Incident on " + h.times.Format(start)
+	}
+
+	draft := postmortem.Draft{Title: title, Start: start, End: end}
+
+	alarms, err := h.awsClient.DescribeAlarmStateChanges(ctx, start, end, limit)
+	if err != nil {
+		draft.Gaps = append(draft.Gaps, fmt.Sprintf("Alarm history could not be read: %v", err))
+	}
+	for _, alarm := range alarms {
+		draft.Timeline = append(draft.Timeline, postmortem.Event{
+			Time:    alarm.Time,
+			Source:  postmortem.SourceAlarm,
+			Summary: fmt.Sprintf("%s %s → %s", alarm.AlarmName, alarm.OldState, alarm.NewState),
+			Detail:  alarm.Reason,
+			State:   alarm.NewState,
+		})
+	}
+
+	changes, err := h.awsClient.LookupChangeEvents(ctx, start, end, limit)
+	if err != nil {
+		draft.Gaps = append(draft.Gaps, fmt.Sprintf("CloudTrail changes could not be read: %v", err))
+	}
+	for _, change := range changes {
+		var detail []string
+		if change.Username != "" {
+			detail = append(detail, "by "+change.Username)
+		}
+		if len(change.Resources) > 0 {
+			detail = append(detail, strings.Join(change.Resources, ", "))
+		}
+		draft.Timeline = append(draft.Timeline, postmortem.Event{
+			Time:    change.Time,
+			Source:  postmortem.SourceChange,
+			Summary: strings.TrimSuffix(change.EventSource, ".amazonaws.com") + ":" + change.EventName,
+			Detail:  strings.Join(detail, "; "),
+			Failed:  change.ErrorCode != "",
+		})
+	}
+
+	for _, entry := range h.audit.Between(start, end) {
+		summary := fmt.Sprintf("%s by %s", entry.Tool, entry.Role)
+		if entry.ChangeTicket != "" {
+			summary += " under " + entry.ChangeTicket
+		}
+		draft.Timeline = append(draft.Timeline, postmortem.Event{
+			Time:    entry.Time,
+			Source:  postmortem.SourceAction,
+			Summary: summary,
+			Detail:  entry.Message,
+			Failed:  !entry.Success,
+		})
+	}
+
+	draft.Sort()
+
+	timeline := make([]map[string]interface{}, 0, len(draft.Timeline))
+	for _, event := range draft.Timeline {
+		item := map[string]interface{}{
+			"time":    h.times.Format(event.Time),
+			"source":  event.Source,
+			"summary": event.Summary,
+		}
+		if event.Detail != "" {
+			item["detail"] = event.Detail
+		}
+		if event.Failed {
+			item["failed"] = true
+		}
+		timeline = append(timeline, item)
+	}
+
+	data := map[string]interface{}{
+		"title":    title,
+		"start":    h.times.Format(start),
+		"end":      h.times.Format(end),
+		"markdown": draft.Markdown(h.times.Format),
+		"timeline": timeline,
+		"counts": map[string]int{
+			"alarms":  draft.Count(postmortem.SourceAlarm),
+			"changes": draft.Count(postmortem.SourceChange),
+			"actions": draft.Count(postmortem.SourceAction),
+		},
+		"instructions": "Polish the markdown: write the summary, impact and root cause from the timeline, and propose action items",
+	}
+	if len(draft.Gaps) > 0 {
+		data["gaps"] = draft.Gaps
+	}
+
+	return h.createSuccessResponse(fmt.Sprintf("Drafted postmortem with %d timeline events", len(draft.Timeline)), data)
+}
@@ -0,0 +1,246 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"aws-mcp-server/pkg/prometheus"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const (
+	// promQueryTemplate is the registered template for PromQL queries. Reserved
+	// expansion lets clients send expressions without encoding every bracket.
+	promQueryTemplate = "prom://query{+params}"
+	// promRangePoints is the number of points a range query aims for when no step is given
+	promRangePoints = 60
+	// defaultPromMaxSeries applies when prometheus.max_series is not configured
+	defaultPromMaxSeries = 50
+)
+
+// promQuery is a parsed prom://query URI
+type promQuery struct {
+	Expr  string
+	Time  time.Time
+	Start time.Time
+	End   time.Time
+	Step  time.Duration
+	Range bool
+}
+
+// parsePromQuery parses prom://query?expr=...&time=... for an instant query or
+// prom://query?expr=...&start=...[&end=...][&step=...] for a range query
+func parsePromQuery(uri string, now time.Time) (promQuery, error) {
+	parsed, err := url.Parse(uri)
+	if err != nil {
+		return promQuery{}, fmt.Errorf("invalid Prometheus URI: %w", err)
+	}
+
+	params := parsed.Query()
+	query := promQuery{Expr: strings.TrimSpace(params.Get("expr"))}
+	if query.Expr == "" {
+		return promQuery{}, fmt.Errorf("expr is required, e.g. prom://query?expr=up")
+	}
+
+	if params.Get("start") == "" {
+		query.Time, err = parseTimeParam(params.Get("time"), now)
+		if err != nil {
+			return promQuery{}, fmt.Errorf("invalid time: %w", err)
+		}
+		return query, nil
+	}
+
+	query.Range = true
+	if query.Start, err = parseTimeParam(params.Get("start"), now); err != nil {
+		return promQuery{}, fmt.Errorf("invalid start: %w", err)
+	}
+	if query.End, err = parseTimeParam(params.Get("end"), now); err != nil {
+		return promQuery{}, fmt.Errorf("invalid end: %w", err)
+	}
+	if !query.End.After(query.Start) {
+		return promQuery{}, fmt.Errorf("end must be after start")
+	}
+
+	if step := params.Get("step"); step != "" {
+		query.Step, err = parseDurationParam(step)
+		if err != nil || query.Step <= 0 {
+			return promQuery{}, fmt.Errorf("invalid step %q", step)
+		}
+	} else {
+		query.Step = (query.End.Sub(query.Start) / promRangePoints).Round(time.Second)
+		if query.Step < time.Second {
+			query.Step = time.Second
+		}
+	}
+
+	return query, nil
+}
+
+// readPromQuery runs a PromQL query against the configured Prometheus endpoint
+func (h *ResourceHandler) readPromQuery(ctx context.Context, uri string) (*mcp.ReadResourceResult, error) {
+	if h.prometheus == nil {
+		return nil, fmt.Errorf("prometheus is not configured; set prometheus.url to enable prom:// resources")
+	}
+
+	query, err := parsePromQuery(uri, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	var result *prometheus.Result
+	if query.Range {
+		result, err = h.prometheus.QueryRange(ctx, query.Expr, query.Start, query.End, query.Step)
+	} else {
+		result, err = h.prometheus.Query(ctx, query.Expr, query.Time)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	maxSeries := h.config.Prometheus.MaxSeries
+	if maxSeries <= 0 {
+		maxSeries = defaultPromMaxSeries
+	}
+	formatted := h.formatPromResult(query, result, maxSeries)
+
+	jsonData, err := json.MarshalIndent(formatted, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal Prometheus result: %w", err)
+	}
+
+	return &mcp.ReadResourceResult{
+		Contents: []mcp.ResourceContents{
+			&mcp.TextResourceContents{
+				URI:      uri,
+				MIMEType: "application/json",
+				Text:     string(jsonData),
+			},
+		},
+	}, nil
+}
+
+// formatPromResult shapes a query result for AI consumption. Series are ordered
+// by their latest value, highest first, and capped at maxSeries. Range series
+// carry min, max, avg and last so trends are readable without every point.
+func (h *ResourceHandler) formatPromResult(query promQuery, result *prometheus.Result, maxSeries int) map[string]interface{} {
+	formatted := map[string]interface{}{
+		"expr":         query.Expr,
+		"result_type":  result.Type,
+		"series_count": len(result.Series),
+	}
+
+	if query.Range {
+		formatted["range"] = map[string]interface{}{
+			"start": h.times.Format(query.Start),
+			"end":   h.times.Format(query.End),
+			"step":  query.Step.String(),
+		}
+	} else {
+		formatted["evaluated_at"] = h.times.Format(query.Time)
+	}
+
+	if len(result.Warnings) > 0 {
+		formatted["warnings"] = result.Warnings
+	}
+
+	if result.Type == prometheus.ResultString {
+		formatted["value"] = result.Text
+		return formatted
+	}
+
+	series := append([]prometheus.Series(nil), result.Series...)
+	sort.SliceStable(series, func(i, j int) bool {
+		return latest(promValues(series[i].Points)) > latest(promValues(series[j].Points))
+	})
+	if len(series) > maxSeries {
+		series = series[:maxSeries]
+		formatted["truncated"] = true
+		formatted["note"] = fmt.Sprintf("Showing the %d series with the highest latest value; aggregate with sum/topk to narrow the result", maxSeries)
+	}
+
+	items := make([]map[string]interface{}, 0, len(series))
+	for _, s := range series {
+		item := map[string]interface{}{
+			"metric": s.Metric,
+		}
+
+		if len(s.Points) == 1 && !query.Range {
+			item["value"] = promValue(s.Points[0].Value)
+			item["time"] = h.times.Format(s.Points[0].Time)
+		} else {
+			points := make([][2]interface{}, 0, len(s.Points))
+			for _, p := range s.Points {
+				points = append(points, [2]interface{}{h.times.Format(p.Time), promValue(p.Value)})
+			}
+			item["points"] = points
+			for key, value := range pointStats(promValues(s.Points)) {
+				item[key] = value
+			}
+		}
+
+		items = append(items, item)
+	}
+	formatted["series"] = items
+
+	return formatted
+}
+
+// promValues returns the sample values of a series
+func promValues(points []prometheus.Point) []float64 {
+	values := make([]float64, len(points))
+	for i, p := range points {
+		values[i] = p.Value
+	}
+	return values
+}
+
+// latest returns the last finite value of a series for ordering
+func latest(values []float64) float64 {
+	for i := len(values) - 1; i >= 0; i-- {
+		if v := values[i]; !math.IsNaN(v) && !math.IsInf(v, 0) {
+			return v
+		}
+	}
+	return math.Inf(-1)
+}
+
+// pointStats summarizes the finite values of a series
+func pointStats(values []float64) map[string]interface{} {
+	count := 0
+	sum, lo, hi := 0.0, math.Inf(1), math.Inf(-1)
+	for _, v := range values {
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			continue
+		}
+		count++
+		sum += v
+		lo = math.Min(lo, v)
+		hi = math.Max(hi, v)
+	}
+	if count == 0 {
+		return nil
+	}
+
+	return map[string]interface{}{
+		"min":  lo,
+		"max":  hi,
+		"avg":  sum / float64(count),
+		"last": promValue(values[len(values)-1]),
+	}
+}
+
+// promValue returns a sample value that JSON can encode; NaN and ±Inf become strings
+func promValue(v float64) interface{} {
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	return v
+}
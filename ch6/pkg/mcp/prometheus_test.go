@@ -0,0 +1,84 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"aws-mcp-server/internal/config"
+	"aws-mcp-server/internal/logging"
+	"aws-mcp-server/pkg/prometheus"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePromQuery(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	query, err := parsePromQuery("prom://query?expr=rate(http_requests_total[5m])&time=now-1h", now)
+	require.NoError(t, err)
+	assert.False(t, query.Range)
+	assert.Equal(t, "rate(http_requests_total[5m])", query.Expr)
+	assert.Equal(t, now.Add(-time.Hour), query.Time)
+
+	query, err = parsePromQuery("prom://query?expr=sum%20by%20(job)%20(up)&start=-6h", now)
+	require.NoError(t, err)
+	assert.True(t, query.Range)
+	assert.Equal(t, now.Add(-6*time.Hour), query.Start)
+	assert.Equal(t, now, query.End)
+	assert.Equal(t, 6*time.Minute, query.Step, "default step aims for 60 points")
+
+	query, err = parsePromQuery("prom://query?expr=up&start=2025-05-31T00:00:00Z&end=1748736000&step=1d", now)
+	require.NoError(t, err)
+	assert.Equal(t, 24*time.Hour, query.Step)
+	assert.Equal(t, time.Unix(1748736000, 0), query.End)
+
+	_, err = parsePromQuery("prom://query?time=now", now)
+	assert.ErrorContains(t, err, "expr is required")
+	_, err = parsePromQuery("prom://query?expr=up&start=now&end=now-1h", now)
+	assert.ErrorContains(t, err, "end must be after start")
+	_, err = parsePromQuery("prom://query?expr=up&start=-1h&step=soon", now)
+	assert.ErrorContains(t, err, "invalid step")
+}
+
+func TestReadPromQuery(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"status":"success","data":{"resultType":"vector","result":[
+			{"metric":{"job":"low"},"value":[1700000000,"1"]},
+			{"metric":{"job":"high"},"value":[1700000000,"+Inf"]},
+			{"metric":{"job":"mid"},"value":[1700000000,"5"]}]}}`))
+	}))
+	defer server.Close()
+
+	cfg := &config.Config{Prometheus: config.PrometheusConfig{URL: server.URL, MaxSeries: 2}}
+	client, err := prometheus.NewClient(cfg.Prometheus, logging.NewLogger("error", "text"))
+	require.NoError(t, err)
+
+	handler := NewResourceHandler(cfg, nil)
+	handler.prometheus = client
+
+	result, err := handler.ReadResource(context.Background(), "prom://query?expr=up")
+	require.NoError(t, err)
+
+	contents, ok := result.Contents[0].(*mcp.TextResourceContents)
+	require.True(t, ok)
+
+	var payload map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(contents.Text), &payload))
+	assert.Equal(t, float64(3), payload["series_count"])
+	assert.Equal(t, true, payload["truncated"])
+
+	series := payload["series"].([]interface{})
+	require.Len(t, series, 2)
+	assert.Equal(t, "mid", series[0].(map[string]interface{})["metric"].(map[string]interface{})["job"])
+	assert.Equal(t, 5.0, series[0].(map[string]interface{})["value"])
+
+	handler.prometheus = nil
+	_, err = handler.ReadResource(context.Background(), "prom://query?expr=up")
+	assert.ErrorContains(t, err, "not configured")
+}
@@ -0,0 +1,186 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// promptArgument is an argument of a runbook prompt. Optional arguments fall
+// back to Default when the client leaves them out.
+type promptArgument struct {
+	Name        string
+	Description string
+	Required    bool
+	Default     string
+}
+
+// runbookPrompt is a built-in prompt that walks the model through an AIOps
+// workflow with this server's tools and resources
+type runbookPrompt struct {
+	Name        string
+	Description string
+	Arguments   []promptArgument
+	Template    string
+}
+
+// runbookPrompts are offered to MCP clients as ready-made workflows. Their
+// templates receive the arguments by name.
+var runbookPrompts = []runbookPrompt{
+	{
+		Name:        "triage-high-cpu",
+		Description: "Triage high CPU on an EC2 instance: confirm it against the baseline, find the cause and propose a safe fix",
+		Arguments: []promptArgument{
+			{Name: "instanceId", Description: "EC2 instance ID, e.g. i-0abc123", Required: true},
+			{Name: "since", Description: "How far back to look, e.g. 1h or 6h", Default: "1h"},
+		},
+		Template: `Triage high CPU on EC2 instance {{.instanceId}} over the last {{.since}}.
+
+1. Read aws://ec2/instances/{{.instanceId}} for its type, state, tags and notes, and aws://ownership/{{.instanceId}} for the owning team.
+2. Call get-cloudwatch-metrics with namespace AWS/EC2, metricName CPUUtilization, dimensions {"InstanceId": "{{.instanceId}}"} and since {{.since}}. Compare it with get-metric-baseline to tell a real anomaly from the usual daily peak.
+3. If the instance is a T family type, also check CPUCreditBalance; an empty balance throttles the instance rather than overloading it.
+4. Look for the cause with query-logs on the instance's application logs and in aws://cloudwatch/alarms for related alarms on the same instance or its load balancer.
+5. Read aws://knowledge/remediations?signal=CPUUtilization for fixes that worked before.
+6. Propose the fix with the least disruption. Run simulate-action before any resize or stop, and do not change anything without confirmation.
+7. After a fix, call verify-remediation with instanceId {{.instanceId}} to confirm CPU settles.
+
+Finish with the cause, the evidence and the recommended next step.`,
+	},
+	{
+		Name:        "cost-review",
+		Description: "Review AWS spend, overall or for one service: trends, the biggest drivers and savings to act on",
+		Arguments: []promptArgument{
+			{Name: "service", Description: "Cost Explorer service name to focus on, e.g. Amazon Elastic Compute Cloud - Compute; leave empty for all services"},
+			{Name: "days", Description: "How many days to review", Default: "30"},
+		},
+		Template: `Review AWS spend over the last {{.days}} days{{with .service}} for {{.}}{{end}}.
+
+1. Read aws://cost/by-service?days={{.days}} for the cost per service{{with .service}} and find {{.}} in it{{end}}.
+2. Read aws://cost/daily?days={{.days}}&groupBy=SERVICE to find days where spend jumped and which service caused it.
+3. Call find-orphans for unattached volumes, old snapshots, idle load balancers and unused Elastic IPs that cost money for nothing.
+{{- if .service}}
+4. Dig into {{.service}}: list its resources, e.g. aws://ec2/instances or aws://rds/instances, and look for idle or oversized ones with get-cloudwatch-metrics.
+{{- else}}
+4. For the three most expensive services, list their resources and look for idle or oversized ones with get-cloudwatch-metrics.
+{{- end}}
+5. Check aws://ec2/instances/by-owner so each saving can go to the team that owns it.
+
+Finish with a table of savings ordered by estimated monthly amount, each with its owner and the change that realizes it. Do not change anything.`,
+	},
+	{
+		Name:        "investigate-alarm",
+		Description: "Investigate a CloudWatch alarm: what it watches, how often it fired, who owns it and what fixed it before",
+		Arguments: []promptArgument{
+			{Name: "alarmName", Description: "Name of the CloudWatch alarm", Required: true},
+		},
+		Template: `Investigate the CloudWatch alarm {{.alarmName}}.
+
+1. Find it in aws://cloudwatch/alarms to learn its metric, dimensions, threshold and state.
+2. Read aws://cloudwatch/alarms/{{.alarmName}}/history to see how often it fired and whether it flaps.
+3. Call get-cloudwatch-metrics for the alarm's metric and dimensions around the latest state change, and compare it with get-metric-baseline.
+4. Read aws://ownership/<resource> for the resource the alarm watches and aws://oncall/current for who to involve.
+5. Read aws://knowledge/remediations?signal={{.alarmName}} for fixes that worked before.
+
+Say whether this is a real problem, noise to tune with the threshold, or a known issue to acknowledge-alert or snooze-alert, and recommend the next step.`,
+	},
+	{
+		Name:        "security-review",
+		Description: "Review the account's security posture: public exposure, unencrypted data and stale credentials",
+		Template: `Review the security posture of this AWS account.
+
+1. Call find-public-exposure for instances and load balancers reachable from the internet and check the open ports against what the service needs.
+2. Read aws://security/unencrypted for unencrypted volumes, snapshots and databases.
+3. Read aws://iam/credential-hygiene for old access keys, unused credentials and stale secrets.
+4. Read aws://ec2/security-groups for rules open to 0.0.0.0/0 on administrative ports such as 22 and 3389.
+
+Finish with the findings ordered by risk, each with the affected resource, its owner from aws://ownership/<resource> and the fix. Do not change anything; fixes such as revoke-security-group-ingress or encrypt-volume need confirmation.`,
+	},
+	{
+		Name:        "morning-check",
+		Description: "Start-of-shift check: what fired overnight, what waits for a decision and what needs attention today",
+		Template: `Run the start-of-shift check.
+
+1. Read aws://digest/daily for alarms, changes and anomalies of the last day.
+2. Read aws://cloudwatch/alarms for alarms still firing.
+3. Read aws://approvals/pending for actions waiting for a decision.
+4. Read aws://oncall/current to see who is on call.
+
+Summarize in a few bullets what needs attention today, most urgent first, and who should look at each.`,
+	},
+	{
+		Name:        "incident-postmortem",
+		Description: "Draft a blameless postmortem for an incident window from the audit log, CloudTrail and alarm history",
+		Arguments: []promptArgument{
+			{Name: "start", Description: "Incident start as RFC3339 or now-<duration>, e.g. now-3h", Required: true},
+			{Name: "end", Description: "Incident end as RFC3339 or now-<duration>", Default: "now"},
+			{Name: "title", Description: "Short incident title"},
+		},
+		Template: `Draft a blameless postmortem for the incident between {{.start}} and {{.end}}{{with .title}}: {{.}}{{end}}.
+
+1. Call draft-postmortem with start {{.start}} and end {{.end}}{{with .title}} and title "{{.}}"{{end}} for the timeline of alarms, changes and actions.
+2. Identify the trigger, the detection time, the mitigation and the resolution in the timeline.
+3. Check aws://knowledge/remediations for how the fix compares with earlier ones.
+
+Write the postmortem with a summary, impact, timeline, root cause, what went well, what went badly and action items with owners. Blame systems, not people.`,
+	},
+}
+
+// compiledPrompts are the parsed templates of the runbook prompts by name
+var compiledPrompts = func() map[string]*template.Template {
+	compiled := make(map[string]*template.Template, len(runbookPrompts))
+	for _, prompt := range runbookPrompts {
+		compiled[prompt.Name] = template.Must(template.New(prompt.Name).Option("missingkey=zero").Parse(prompt.Template))
+	}
+	return compiled
+}()
+
+// toMCP describes the prompt for prompts/list
+func (p runbookPrompt) toMCP() mcp.Prompt {
+	options := []mcp.PromptOption{mcp.WithPromptDescription(p.Description)}
+	for _, argument := range p.Arguments {
+		argumentOptions := []mcp.ArgumentOption{mcp.ArgumentDescription(argument.Description)}
+		if argument.Required {
+			argumentOptions = append(argumentOptions, mcp.RequiredArgument())
+		}
+		options = append(options, mcp.WithArgument(argument.Name, argumentOptions...))
+	}
+	return mcp.NewPrompt(p.Name, options...)
+}
+
+// getPrompt renders a runbook prompt with the client's arguments
+func getPrompt(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+	for _, prompt := range runbookPrompts {
+		if prompt.Name == request.Params.Name {
+			return renderPrompt(prompt, request.Params.Arguments)
+		}
+	}
+	return nil, fmt.Errorf("unknown prompt %s", request.Params.Name)
+}
+
+// renderPrompt fills a prompt's template, applying defaults to optional
+// arguments and rejecting missing required ones
+func renderPrompt(prompt runbookPrompt, arguments map[string]string) (*mcp.GetPromptResult, error) {
+	values := make(map[string]string, len(prompt.Arguments))
+	for _, argument := range prompt.Arguments {
+		value := strings.TrimSpace(arguments[argument.Name])
+		if value == "" && argument.Required {
+			return nil, fmt.Errorf("prompt %s needs the argument %s", prompt.Name, argument.Name)
+		}
+		if value == "" {
+			value = argument.Default
+		}
+		values[argument.Name] = value
+	}
+
+	var text strings.Builder
+	if err := compiledPrompts[prompt.Name].Execute(&text, values); err != nil {
+		return nil, fmt.Errorf("failed to render prompt %s: %w", prompt.Name, err)
+	}
+
+	return mcp.NewGetPromptResult(prompt.Description, []mcp.PromptMessage{
+		mcp.NewPromptMessage(mcp.RoleUser, mcp.NewTextContent(text.String())),
+	}), nil
+}
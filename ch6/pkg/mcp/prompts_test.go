@@ -0,0 +1,59 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunbookPrompts(t *testing.T) {
+	names := make(map[string]bool)
+	for _, prompt := range runbookPrompts {
+		assert.False(t, names[prompt.Name], "duplicate prompt %s", prompt.Name)
+		names[prompt.Name] = true
+
+		// Every prompt renders with only its required arguments
+		arguments := make(map[string]string)
+		for _, argument := range prompt.Arguments {
+			if argument.Required {
+				arguments[argument.Name] = "example"
+			}
+		}
+		result, err := renderPrompt(prompt, arguments)
+		require.NoError(t, err, prompt.Name)
+		require.Len(t, result.Messages, 1)
+		assert.NotContains(t, result.Messages[0].Content.(mcp.TextContent).Text, "<no value>", prompt.Name)
+	}
+}
+
+func TestGetPrompt(t *testing.T) {
+	ctx := context.Background()
+	request := mcp.GetPromptRequest{}
+	request.Params.Name = "triage-high-cpu"
+
+	_, err := getPrompt(ctx, request)
+	assert.EqualError(t, err, "prompt triage-high-cpu needs the argument instanceId")
+
+	request.Params.Arguments = map[string]string{"instanceId": "i-0abc123"}
+	result, err := getPrompt(ctx, request)
+	require.NoError(t, err)
+	assert.Equal(t, mcp.RoleUser, result.Messages[0].Role)
+	text := result.Messages[0].Content.(mcp.TextContent).Text
+	assert.Contains(t, text, "Triage high CPU on EC2 instance i-0abc123 over the last 1h.")
+	assert.Contains(t, text, "aws://ec2/instances/i-0abc123")
+
+	request.Params.Name = "cost-review"
+	request.Params.Arguments = map[string]string{"service": "Amazon Relational Database Service", "days": "7"}
+	result, err = getPrompt(ctx, request)
+	require.NoError(t, err)
+	text = result.Messages[0].Content.(mcp.TextContent).Text
+	assert.Contains(t, text, "Review AWS spend over the last 7 days for Amazon Relational Database Service.")
+	assert.Contains(t, text, "aws://cost/by-service?days=7")
+
+	request.Params.Name = "rollback-deploy"
+	_, err = getPrompt(ctx, request)
+	assert.EqualError(t, err, "unknown prompt rollback-deploy")
+}
@@ -0,0 +1,190 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"time"
+
+	"aws-mcp-server/pkg/types"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// snapshotIDPattern is the RDS rule for snapshot identifiers: a letter first, then
+// letters, digits and single hyphens, without a trailing hyphen, up to 255 characters
+var snapshotIDPattern = regexp.MustCompile(`^[A-Za-z](?:-?[A-Za-z0-9])*$`)
+
+// readRDSInstances returns a formatted list of all RDS instances
+func (h *ResourceHandler) readRDSInstances(ctx context.Context) (*mcp.ReadResourceResult, error) {
+	instances, err := h.awsClient.ListDBInstances(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list RDS instances: %w", err)
+	}
+
+	jsonData, err := json.MarshalIndent(formatDBInstances(instances), "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal RDS instances data: %w", err)
+	}
+
+	return &mcp.ReadResourceResult{
+		Contents: []mcp.ResourceContents{
+			&mcp.TextResourceContents{
+				URI:      "aws://rds/instances",
+				MIMEType: "application/json",
+				Text:     string(jsonData),
+			},
+		},
+	}, nil
+}
+
+// formatDBInstances formats RDS instances for AI processing with counts by status and engine
+func formatDBInstances(instances []types.CloudResource) map[string]interface{} {
+	formatted := make([]map[string]interface{}, 0, len(instances))
+	stateCount := make(map[string]int)
+	engineCount := make(map[string]int)
+
+	for _, instance := range instances {
+		details := instance.RDSInstance()
+		engine := details.Engine
+
+		item := map[string]interface{}{
+			"id":                  instance.ID,
+			"state":               instance.State,
+			"engine":              engine,
+			"engine_version":      details.EngineVersion,
+			"class":               details.InstanceClass,
+			"multi_az":            details.MultiAZ,
+			"storage_gb":          details.AllocatedStorage,
+			"encrypted":           details.StorageEncrypted,
+			"publicly_accessible": details.PubliclyAccessible,
+		}
+		if details.Endpoint != "" {
+			item["endpoint"] = details.Endpoint
+		}
+		if details.AvailabilityZone != "" {
+			item["availability_zone"] = details.AvailabilityZone
+		}
+		if name, exists := instance.Tags["Name"]; exists {
+			item["name"] = name
+		}
+		if env, exists := instance.Tags["Environment"]; exists {
+			item["environment"] = env
+		}
+
+		formatted = append(formatted, item)
+		stateCount[instance.State]++
+		engineCount[engine]++
+	}
+
+	return map[string]interface{}{
+		"total_instances":   len(instances),
+		"instances":         formatted,
+		"summary_by_state":  stateCount,
+		"summary_by_engine": engineCount,
+	}
+}
+
+// startRDSInstance starts a stopped RDS instance
+func (h *ToolHandler) startRDSInstance(ctx context.Context, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	dbInstanceID, ok := arguments["dbInstanceId"].(string)
+	if !ok || dbInstanceID == "" {
+		return h.createErrorResponse("dbInstanceId is required")
+	}
+
+	if err := h.awsClient.StartDBInstance(ctx, dbInstanceID); err != nil {
+		return h.createErrorResponse(fmt.Sprintf("failed to start RDS instance: %v", err))
+	}
+
+	return h.createSuccessResponse("RDS instance start initiated successfully", map[string]interface{}{
+		"dbInstanceId": dbInstanceID,
+		"action":       "start",
+	})
+}
+
+// stopRDSInstance stops a running RDS instance, optionally taking a snapshot first
+func (h *ToolHandler) stopRDSInstance(ctx context.Context, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	dbInstanceID, ok := arguments["dbInstanceId"].(string)
+	if !ok || dbInstanceID == "" {
+		return h.createErrorResponse("dbInstanceId is required")
+	}
+
+	snapshotID, _ := arguments["snapshotId"].(string)
+	if snapshotID != "" && !snapshotIDPattern.MatchString(snapshotID) {
+		return h.createErrorResponse(fmt.Sprintf("invalid snapshotId %q: use letters, digits and single hyphens, starting with a letter", snapshotID))
+	}
+
+	if err := h.awsClient.StopDBInstance(ctx, dbInstanceID, snapshotID); err != nil {
+		return h.createErrorResponse(fmt.Sprintf("failed to stop RDS instance: %v", err))
+	}
+
+	data := map[string]interface{}{
+		"dbInstanceId": dbInstanceID,
+		"action":       "stop",
+		"note":         "RDS starts stopped instances again automatically after 7 days",
+	}
+	if snapshotID != "" {
+		data["snapshotId"] = snapshotID
+	}
+
+	return h.createSuccessResponse("RDS instance stop initiated successfully", data)
+}
+
+// rebootRDSInstance reboots an RDS instance, optionally failing over to the Multi-AZ standby
+func (h *ToolHandler) rebootRDSInstance(ctx context.Context, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	dbInstanceID, ok := arguments["dbInstanceId"].(string)
+	if !ok || dbInstanceID == "" {
+		return h.createErrorResponse("dbInstanceId is required")
+	}
+
+	forceFailover, _ := arguments["forceFailover"].(bool)
+
+	if err := h.awsClient.RebootDBInstance(ctx, dbInstanceID, forceFailover); err != nil {
+		return h.createErrorResponse(fmt.Sprintf("failed to reboot RDS instance: %v", err))
+	}
+
+	return h.createSuccessResponse("RDS instance reboot initiated successfully", map[string]interface{}{
+		"dbInstanceId":  dbInstanceID,
+		"action":        "reboot",
+		"forceFailover": forceFailover,
+	})
+}
+
+// createRDSSnapshot starts a manual snapshot of an RDS instance
+func (h *ToolHandler) createRDSSnapshot(ctx context.Context, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	dbInstanceID, ok := arguments["dbInstanceId"].(string)
+	if !ok || dbInstanceID == "" {
+		return h.createErrorResponse("dbInstanceId is required")
+	}
+
+	snapshotID, _ := arguments["snapshotId"].(string)
+	if snapshotID == "" {
+		snapshotID = defaultSnapshotID(dbInstanceID, time.Now())
+	}
+	if !snapshotIDPattern.MatchString(snapshotID) || len(snapshotID) > 255 {
+		return h.createErrorResponse(fmt.Sprintf("invalid snapshotId %q: use letters, digits and single hyphens, starting with a letter", snapshotID))
+	}
+
+	snapshot, err := h.awsClient.CreateDBSnapshot(ctx, dbInstanceID, snapshotID)
+	if err != nil {
+		return h.createErrorResponse(fmt.Sprintf("failed to create RDS snapshot: %v", err))
+	}
+
+	data := map[string]interface{}{
+		"dbInstanceId": dbInstanceID,
+		"snapshotId":   snapshot.ID,
+		"state":        snapshot.State,
+	}
+	if arn := snapshot.RDSSnapshot().ARN; arn != "" {
+		data["snapshotArn"] = arn
+	}
+
+	return h.createSuccessResponse("RDS snapshot creation initiated successfully", data)
+}
+
+// defaultSnapshotID names a manual snapshot after its instance and the current
+// UTC time, e.g. orders-db-20250601-101500
+func defaultSnapshotID(dbInstanceID string, now time.Time) string {
+	return fmt.Sprintf("%s-%s", dbInstanceID, now.UTC().Format("20060102-150405"))
+}
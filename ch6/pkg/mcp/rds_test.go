@@ -0,0 +1,76 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"aws-mcp-server/internal/config"
+	"aws-mcp-server/internal/logging"
+	"aws-mcp-server/pkg/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatDBInstances(t *testing.T) {
+	instances := []types.CloudResource{
+		{
+			ID:    "orders-db",
+			State: "available",
+			Tags:  map[string]string{"Name": "orders", "Environment": "prod"},
+			Details: &types.RDSInstanceDetails{
+				Engine:        "postgres",
+				InstanceClass: "db.r6g.large",
+				MultiAZ:       true,
+				Endpoint:      "orders-db.abc.us-east-1.rds.amazonaws.com:5432",
+			},
+		},
+		{
+			ID:      "reports-db",
+			State:   "stopped",
+			Tags:    map[string]string{},
+			Details: &types.RDSInstanceDetails{Engine: "mysql"},
+		},
+	}
+
+	formatted := formatDBInstances(instances)
+
+	assert.Equal(t, 2, formatted["total_instances"])
+	assert.Equal(t, map[string]int{"available": 1, "stopped": 1}, formatted["summary_by_state"])
+	assert.Equal(t, map[string]int{"postgres": 1, "mysql": 1}, formatted["summary_by_engine"])
+
+	items := formatted["instances"].([]map[string]interface{})
+	require.Len(t, items, 2)
+	assert.Equal(t, "orders", items[0]["name"])
+	assert.Equal(t, "prod", items[0]["environment"])
+	assert.Equal(t, "orders-db.abc.us-east-1.rds.amazonaws.com:5432", items[0]["endpoint"])
+	assert.NotContains(t, items[1], "endpoint")
+}
+
+func TestSnapshotIDs(t *testing.T) {
+	now := time.Date(2025, 6, 1, 10, 15, 0, 0, time.UTC)
+	assert.Equal(t, "orders-db-20250601-101500", defaultSnapshotID("orders-db", now))
+
+	assert.True(t, snapshotIDPattern.MatchString("orders-db-20250601-101500"))
+	assert.False(t, snapshotIDPattern.MatchString("1-orders"))
+	assert.False(t, snapshotIDPattern.MatchString("orders--db"))
+	assert.False(t, snapshotIDPattern.MatchString("orders-"))
+	assert.False(t, snapshotIDPattern.MatchString("orders_db"))
+}
+
+func TestRDSToolsValidateArguments(t *testing.T) {
+	h := NewToolHandler(&config.Config{}, nil, logging.NewLogger("error", "text"))
+	ctx := context.Background()
+
+	result, err := h.CallTool(ctx, "reboot-rds-instance", map[string]interface{}{})
+	require.NoError(t, err)
+	assert.Equal(t, "dbInstanceId is required", decodeToolResult(t, result)["error"])
+
+	result, err = h.CallTool(ctx, "stop-rds-instance", map[string]interface{}{
+		"dbInstanceId": "orders-db",
+		"snapshotId":   "before_stop",
+	})
+	require.NoError(t, err)
+	assert.Contains(t, decodeToolResult(t, result)["error"], "invalid snapshotId")
+}
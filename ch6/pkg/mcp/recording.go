@@ -0,0 +1,214 @@
+package mcp
+
+import (
+	"bytes"
+	"encoding/json"
+	"slices"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// secretFields are the fields of tool results that hold secrets, by tool.
+// Session recordings keep the rest of the result but not these values, and
+// the results are not kept for replays.
+var secretFields = map[string][]string{
+	"get-windows-password": {"password"},
+}
+
+// recordedSecrets returns the secret fields of the results of a tool.
+// approve-action returns the result of whichever tool it ran.
+func recordedSecrets(name string) []string {
+	if name != "approve-action" {
+		return secretFields[name]
+	}
+	var fields []string
+	for _, secrets := range secretFields {
+		fields = append(fields, secrets...)
+	}
+	return fields
+}
+
+// resultSecrets returns the fields of the result of a call that hold secrets.
+// Plans of put-parameter show the current value when reveal_secure_values is
+// set, and so the new value of SecureStrings.
+func resultSecrets(name string, arguments map[string]interface{}) []string {
+	fields := slices.Clone(recordedSecrets(name))
+	if name == "put-parameter" {
+		fields = append(fields, "current_value")
+		if value, _ := redactArguments(name, arguments)["value"].(string); value == redactedValue {
+			fields = append(fields, "new_value")
+		}
+	}
+	return fields
+}
+
+// redactRecording returns a JSON-RPC request and its response as they may be
+// written to a session recording. Values written by put-parameter are
+// redacted like in logs, and so are the secrets of tool results and the
+// SecureString values of parameter resources. Messages that cannot be parsed
+// are recorded as they are.
+func redactRecording(request, response []byte) ([]byte, []byte) {
+	var message struct {
+		Method string `json:"method"`
+		Params struct {
+			Name      string                 `json:"name"`
+			Arguments map[string]interface{} `json:"arguments"`
+			URI       string                 `json:"uri"`
+		} `json:"params"`
+	}
+	if json.Unmarshal(request, &message) != nil {
+		return request, response
+	}
+
+	switch params := message.Params; message.Method {
+	case "tools/call":
+		fields := resultSecrets(params.Name, params.Arguments)
+		redacted := redactArguments(params.Name, params.Arguments)
+		if value, _ := redacted["value"].(string); value == redactedValue {
+			request = redactRequestArguments(request, redacted)
+		}
+		if len(fields) > 0 {
+			response = redactResponse(response, "content", func(document interface{}) { redactFields(document, fields) })
+		}
+	case "resources/read":
+		if strings.HasPrefix(params.URI, parametersURI) {
+			response = redactResponse(response, "contents", redactSecureValues)
+		}
+	}
+	return request, response
+}
+
+// redactRequestArguments replaces the arguments of a tools/call request
+func redactRequestArguments(request []byte, arguments map[string]interface{}) []byte {
+	message, ok := decodeJSON(request).(map[string]interface{})
+	if !ok {
+		return request
+	}
+	params, _ := message["params"].(map[string]interface{})
+	if params == nil {
+		return request
+	}
+	params["arguments"] = arguments
+	redacted, err := json.Marshal(message)
+	if err != nil {
+		return request
+	}
+	return redacted
+}
+
+// redactResponse applies redact to the JSON text items of a response, the
+// content of tool results or the contents of resources
+func redactResponse(response []byte, items string, redact func(document interface{})) []byte {
+	message, ok := decodeJSON(response).(map[string]interface{})
+	if !ok {
+		return response
+	}
+	result, _ := message["result"].(map[string]interface{})
+	contents, _ := result[items].([]interface{})
+	for _, content := range contents {
+		item, _ := content.(map[string]interface{})
+		text, ok := item["text"].(string)
+		if !ok {
+			continue
+		}
+		// Text that is not JSON holds no fields to redact
+		document := decodeJSON([]byte(text))
+		if document == nil {
+			continue
+		}
+		redact(document)
+		if redacted, err := json.MarshalIndent(document, "", "  "); err == nil {
+			item["text"] = string(redacted)
+		}
+	}
+
+	redacted, err := json.Marshal(message)
+	if err != nil {
+		return response
+	}
+	return redacted
+}
+
+// redactResult replaces the named fields in the JSON text of a tool result,
+// for results shown to people other than the caller
+func redactResult(result *mcp.CallToolResult, fields []string) {
+	if result == nil || len(fields) == 0 {
+		return
+	}
+	for i, content := range result.Content {
+		var text string
+		switch c := content.(type) {
+		case *mcp.TextContent:
+			text = c.Text
+		case mcp.TextContent:
+			text = c.Text
+		default:
+			continue
+		}
+		document := decodeJSON([]byte(text))
+		if document == nil {
+			continue
+		}
+		redactFields(document, fields)
+		if redacted, err := json.MarshalIndent(document, "", "  "); err == nil {
+			result.Content[i] = &mcp.TextContent{Type: "text", Text: string(redacted)}
+		}
+	}
+}
+
+// decodeJSON decodes a JSON document keeping numbers as written, nil when it
+// is not valid
+func decodeJSON(data []byte) interface{} {
+	var document interface{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if decoder.Decode(&document) != nil {
+		return nil
+	}
+	return document
+}
+
+// redactFields replaces the values of the named fields at any depth of a
+// decoded JSON document
+func redactFields(document interface{}, fields []string) {
+	switch value := document.(type) {
+	case map[string]interface{}:
+		for key, field := range value {
+			redacted := false
+			for _, name := range fields {
+				if key == name {
+					value[key] = redactedValue
+					redacted = true
+				}
+			}
+			if !redacted {
+				redactFields(field, fields)
+			}
+		}
+	case []interface{}:
+		for _, item := range value {
+			redactFields(item, fields)
+		}
+	}
+}
+
+// redactSecureValues replaces the values of SecureString parameters at any
+// depth of a decoded JSON document
+func redactSecureValues(document interface{}) {
+	switch value := document.(type) {
+	case map[string]interface{}:
+		if value["type"] == secureStringType {
+			if _, ok := value["value"]; ok {
+				value["value"] = redactedValue
+			}
+		}
+		for _, field := range value {
+			redactSecureValues(field)
+		}
+	case []interface{}:
+		for _, item := range value {
+			redactSecureValues(item)
+		}
+	}
+}
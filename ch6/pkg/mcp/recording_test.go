@@ -0,0 +1,138 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"aws-mcp-server/internal/config"
+	"aws-mcp-server/internal/logging"
+	"aws-mcp-server/pkg/aws"
+	"aws-mcp-server/pkg/session"
+
+	sdkaws "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// toolCallExchange builds a recorded tools/call request and its response
+func toolCallExchange(t *testing.T, name string, arguments, result map[string]interface{}) ([]byte, []byte) {
+	t.Helper()
+	request, err := json.Marshal(map[string]interface{}{
+		"jsonrpc": "2.0", "id": 7, "method": "tools/call",
+		"params": map[string]interface{}{"name": name, "arguments": arguments},
+	})
+	require.NoError(t, err)
+	text, err := json.Marshal(result)
+	require.NoError(t, err)
+	response, err := json.Marshal(map[string]interface{}{
+		"jsonrpc": "2.0", "id": 7,
+		"result": map[string]interface{}{"content": []interface{}{map[string]interface{}{"type": "text", "text": string(text)}}},
+	})
+	require.NoError(t, err)
+	return request, response
+}
+
+func TestRedactRecordingLeavesOutPasswords(t *testing.T) {
+	request, response := toolCallExchange(t, "get-windows-password", map[string]interface{}{"instanceId": "i-1", "confirm": true},
+		map[string]interface{}{"success": true, "username": "Administrator", "password": "Pa55w0rd!", "instanceId": "i-1"})
+
+	_, redacted := redactRecording(request, response)
+	recorded := string(redacted)
+	assert.NotContains(t, recorded, "Pa55w0rd!")
+	assert.Contains(t, recorded, redactedValue)
+	assert.Contains(t, recorded, "Administrator", "the rest of the result is kept")
+	assert.Contains(t, recorded, `"id":7`)
+
+	// Approved calls return the result of the tool they ran
+	request, response = toolCallExchange(t, "approve-action", map[string]interface{}{"requestId": "req-1"},
+		map[string]interface{}{"success": true, "result": map[string]interface{}{"password": "Pa55w0rd!"}})
+	_, redacted = redactRecording(request, response)
+	assert.NotContains(t, string(redacted), "Pa55w0rd!")
+
+	request, response = toolCallExchange(t, "describe-instance", map[string]interface{}{"password": "kept"},
+		map[string]interface{}{"password": "kept"})
+	recordedRequest, redacted := redactRecording(request, response)
+	assert.Equal(t, request, recordedRequest)
+	assert.Equal(t, response, redacted, "results of other tools are recorded as they are")
+}
+
+func TestRedactResultForChat(t *testing.T) {
+	result := &mcp.CallToolResult{Content: []mcp.Content{
+		&mcp.TextContent{Type: "text", Text: `{"success": true, "result": {"username": "Administrator", "password": "Pa55w0rd!"}}`},
+		mcp.TextContent{Type: "text", Text: "Decrypted the password of i-1"},
+	}}
+	redactResult(result, recordedSecrets("approve-action"))
+
+	text := result.Content[0].(*mcp.TextContent).Text
+	assert.NotContains(t, text, "Pa55w0rd!")
+	assert.Contains(t, text, "Administrator")
+	assert.Equal(t, "Decrypted the password of i-1", result.Content[1].(mcp.TextContent).Text, "summaries are kept")
+
+	assert.ElementsMatch(t, []string{"current_value", "new_value"}, resultSecrets("put-parameter", map[string]interface{}{"type": "SecureString", "value": "s3cret"}))
+	assert.Equal(t, []string{"current_value"}, resultSecrets("put-parameter", map[string]interface{}{"type": "String", "value": "plain"}))
+
+	policy := chatToolPolicy()
+	assert.Equal(t, []string{"get-windows-password"}, policy.SecretTools)
+	assert.Contains(t, policy.DefaultTools, "search")
+	assert.NotContains(t, policy.DefaultTools, "stop-ec2-instance")
+}
+
+func TestRecordingsLeaveOutSecureStrings(t *testing.T) {
+	// SSM holds one SecureString, decrypted since reveal_secure_values is set
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/x-amz-json-1.1")
+		switch r.Header.Get("X-Amz-Target") {
+		case "AmazonSSM.GetParameter":
+			w.Write([]byte(`{"Parameter":{"Name":"/app/prod/db-password","Type":"SecureString","Value":"current-s3cret","Version":3,"DataType":"text"}}`))
+		case "AmazonSSM.PutParameter":
+			w.Write([]byte(`{"Version":4}`))
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer server.Close()
+
+	logger := logging.NewLogger("error", "text")
+	awsClient := aws.NewClientFromConfig(sdkaws.Config{
+		Region:       "us-east-1",
+		Credentials:  sdkaws.AnonymousCredentials{},
+		BaseEndpoint: sdkaws.String(server.URL),
+	}, logger)
+	cfg := &config.Config{
+		MCP:        config.MCPConfig{ServerName: "aws-mcp-server", Version: "test"},
+		Parameters: config.ParametersConfig{PathPrefix: "/app/prod", RevealSecureValues: true},
+	}
+	s := NewServer(cfg, awsClient, logger)
+	recorder, err := session.NewRecorder(t.TempDir())
+	require.NoError(t, err)
+	peer := newClientPeer(func([]byte) error { return nil })
+
+	messages := []string{
+		`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-03-26","capabilities":{},"clientInfo":{"name":"test","version":"1.0.0"}}}`,
+		`{"jsonrpc":"2.0","id":2,"method":"resources/read","params":{"uri":"aws://ssm/parameters/app/prod/db-password"}}`,
+		`{"jsonrpc":"2.0","id":3,"method":"tools/call","params":{"name":"put-parameter","arguments":{"name":"/app/prod/db-password","value":"new-s3cret"}}}`,
+		`{"jsonrpc":"2.0","id":4,"method":"tools/call","params":{"name":"put-parameter","arguments":{"name":"/app/prod/db-password","value":"new-s3cret","confirm":true}}}`,
+	}
+	for _, message := range messages {
+		s.serveMessage(context.Background(), peer, recorder, []byte(message))
+	}
+	require.NoError(t, recorder.Close())
+
+	entries, err := session.Load(recorder.Path())
+	require.NoError(t, err)
+	require.Len(t, entries, len(messages))
+	assert.Contains(t, string(entries[1].Response), redactedValue, "the parameter was read")
+	assert.Contains(t, string(entries[2].Response), "confirmation_required", "the plan was returned")
+	assert.Contains(t, string(entries[3].Response), "updated successfully")
+
+	recorded, err := os.ReadFile(recorder.Path())
+	require.NoError(t, err)
+	assert.NotContains(t, string(recorded), "current-s3cret")
+	assert.NotContains(t, string(recorded), "new-s3cret")
+	assert.Contains(t, string(recorded), "/app/prod/db-password", "the rest of the exchanges is recorded")
+}
@@ -0,0 +1,163 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"aws-mcp-server/internal/config"
+	"aws-mcp-server/pkg/aws"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// defaultRedshiftMaxRows applies when redshift.max_rows is not configured
+const defaultRedshiftMaxRows = 1000
+
+// readOnlyRedshiftStatements are the statements start-redshift-query accepts
+var readOnlyRedshiftStatements = map[string]bool{
+	"SELECT":  true,
+	"WITH":    true,
+	"SHOW":    true,
+	"EXPLAIN": true,
+}
+
+// redshiftQuery returns the parameters of the read-only query in the
+// arguments, with the configured cluster or workgroup, database and
+// credentials for what they leave out
+func redshiftQuery(cfg config.RedshiftConfig, arguments map[string]interface{}) (aws.RedshiftQueryParams, error) {
+	params := aws.RedshiftQueryParams{
+		ClusterID: cfg.ClusterID,
+		Workgroup: cfg.Workgroup,
+		Database:  cfg.Database,
+		DBUser:    cfg.DBUser,
+		SecretARN: cfg.SecretARN,
+	}
+
+	params.SQL, _ = arguments["sql"].(string)
+	params.SQL = strings.TrimSpace(params.SQL)
+	if params.SQL == "" {
+		return params, fmt.Errorf("sql is required, e.g. SELECT status, count(*) FROM orders GROUP BY 1")
+	}
+	if statement := firstSQLKeyword(params.SQL); !readOnlyRedshiftStatements[statement] {
+		return params, fmt.Errorf("only read-only queries (SELECT, WITH, SHOW, EXPLAIN) are allowed, got %s", statement)
+	}
+	if err := checkSingleQuery(params.SQL); err != nil {
+		return params, err
+	}
+
+	// A cluster or workgroup in the arguments replaces both configured ones
+	clusterID, _ := arguments["clusterId"].(string)
+	workgroup, _ := arguments["workgroup"].(string)
+	if clusterID != "" || workgroup != "" {
+		params.ClusterID, params.Workgroup = clusterID, workgroup
+	}
+	if database, ok := arguments["database"].(string); ok && database != "" {
+		params.Database = database
+	}
+
+	switch {
+	case params.ClusterID != "" && params.Workgroup != "":
+		return params, fmt.Errorf("pass either clusterId or workgroup, not both")
+	case params.ClusterID == "" && params.Workgroup == "":
+		return params, fmt.Errorf("clusterId or workgroup is required; set redshift.cluster_id or redshift.workgroup to query one by default")
+	case params.Database == "":
+		return params, fmt.Errorf("database is required")
+	case params.Workgroup != "" && params.SecretARN == "":
+		// Serverless takes the caller's IAM identity instead of a database user
+		params.DBUser = ""
+	case params.ClusterID != "" && params.SecretARN == "" && params.DBUser == "":
+		return params, fmt.Errorf("set redshift.secret_arn or redshift.db_user to authenticate to cluster %s", params.ClusterID)
+	}
+	return params, nil
+}
+
+// startRedshiftQuery submits a read-only query with the Redshift Data API.
+// The caller polls it with get-redshift-query and reads its rows with
+// get-redshift-query-results.
+func (h *ToolHandler) startRedshiftQuery(ctx context.Context, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	params, err := redshiftQuery(h.config.Redshift, arguments)
+	if err != nil {
+		return h.createErrorResponse(err.Error())
+	}
+
+	statementID, err := h.awsClient.StartRedshiftQuery(ctx, params)
+	if err != nil {
+		return h.createErrorResponse(err.Error())
+	}
+
+	data := map[string]interface{}{
+		"statementId": statementID,
+		"database":    params.Database,
+		"note":        "Poll get-redshift-query until the state is FINISHED, then read the rows with get-redshift-query-results",
+	}
+	if params.ClusterID != "" {
+		data["cluster"] = params.ClusterID
+	} else {
+		data["workgroup"] = params.Workgroup
+	}
+	return h.createSuccessResponse("Redshift query started", data)
+}
+
+// getRedshiftQuery returns the state of a query
+func (h *ToolHandler) getRedshiftQuery(ctx context.Context, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	statementID, _ := arguments["statementId"].(string)
+	if statementID == "" {
+		return h.createErrorResponse("statementId is required")
+	}
+
+	result, err := h.awsClient.GetRedshiftQuery(ctx, statementID)
+	if err != nil {
+		return h.createErrorResponse(err.Error())
+	}
+
+	data := map[string]interface{}{
+		"statementId": statementID,
+		"state":       result.State,
+		"done":        result.Done(),
+		"duration_ms": result.DurationMs,
+	}
+	if result.State == "FINISHED" {
+		data["total_rows"] = result.TotalRows
+	}
+	if result.Error != "" {
+		data["reason"] = result.Error
+	}
+	return h.createSuccessResponse(fmt.Sprintf("Redshift query is %s", result.State), data)
+}
+
+// getRedshiftQueryResults returns the rows of a finished query, capped at
+// redshift.max_rows
+func (h *ToolHandler) getRedshiftQueryResults(ctx context.Context, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	statementID, _ := arguments["statementId"].(string)
+	if statementID == "" {
+		return h.createErrorResponse("statementId is required")
+	}
+	maxRows := h.config.Redshift.MaxRows
+	if maxRows <= 0 {
+		maxRows = defaultRedshiftMaxRows
+	}
+	if requested, ok := arguments["maxRows"].(float64); ok && requested > 0 {
+		maxRows = int(min(requested, float64(maxRows)))
+	}
+
+	result, err := h.awsClient.GetRedshiftQueryResults(ctx, statementID, maxRows)
+	if err != nil {
+		return h.createErrorResponse(err.Error())
+	}
+
+	data := map[string]interface{}{
+		"statementId": statementID,
+		"state":       result.State,
+		"columns":     result.Columns,
+		"count":       len(result.Rows),
+		"rows":        result.Rows,
+		"total_rows":  result.TotalRows,
+		"duration_ms": result.DurationMs,
+	}
+	if result.Truncated {
+		data["truncated"] = true
+		data["note"] = fmt.Sprintf("Only the first %d of %d rows are returned; aggregate in SQL to see the rest", maxRows, result.TotalRows)
+	}
+	return h.createSuccessResponse(fmt.Sprintf("Redshift query returned %d rows", len(result.Rows)), data)
+}
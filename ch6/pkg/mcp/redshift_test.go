@@ -0,0 +1,60 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	"aws-mcp-server/internal/config"
+	"aws-mcp-server/internal/logging"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedshiftQuery(t *testing.T) {
+	cfg := config.RedshiftConfig{ClusterID: "analytics", Database: "dev", DBUser: "readonly"}
+
+	params, err := redshiftQuery(cfg, map[string]interface{}{"sql": "SELECT 1", "database": "ops"})
+	require.NoError(t, err)
+	assert.Equal(t, "analytics", params.ClusterID)
+	assert.Equal(t, "ops", params.Database)
+	assert.Equal(t, "readonly", params.DBUser)
+
+	params, err = redshiftQuery(cfg, map[string]interface{}{"sql": "SELECT 1", "workgroup": "serverless"})
+	require.NoError(t, err)
+	assert.Empty(t, params.ClusterID, "a workgroup in the arguments replaces the configured cluster")
+	assert.Equal(t, "serverless", params.Workgroup)
+	assert.Empty(t, params.DBUser, "Serverless uses the caller's IAM identity")
+
+	_, err = redshiftQuery(cfg, map[string]interface{}{"sql": "DELETE FROM orders"})
+	assert.ErrorContains(t, err, "only read-only queries")
+
+	_, err = redshiftQuery(cfg, map[string]interface{}{"sql": "SELECT * INTO orders_copy FROM orders"})
+	assert.ErrorContains(t, err, "only read-only queries")
+
+	_, err = redshiftQuery(cfg, map[string]interface{}{"sql": "SELECT 1; DELETE FROM orders"})
+	assert.ErrorContains(t, err, "only one statement")
+
+	_, err = redshiftQuery(config.RedshiftConfig{Database: "dev"}, map[string]interface{}{"sql": "SELECT 1"})
+	assert.ErrorContains(t, err, "clusterId or workgroup is required")
+
+	_, err = redshiftQuery(config.RedshiftConfig{Database: "dev"}, map[string]interface{}{"sql": "SELECT 1", "clusterId": "analytics", "workgroup": "serverless"})
+	assert.ErrorContains(t, err, "not both")
+
+	_, err = redshiftQuery(config.RedshiftConfig{ClusterID: "analytics", Database: "dev"}, map[string]interface{}{"sql": "SELECT 1"})
+	assert.ErrorContains(t, err, "redshift.secret_arn or redshift.db_user")
+
+	params, err = redshiftQuery(config.RedshiftConfig{ClusterID: "analytics", Database: "dev", SecretARN: "arn:aws:secretsmanager:us-west-2:123456789012:secret:ro"}, map[string]interface{}{"sql": "WITH t AS (SELECT 1) SELECT * FROM t"})
+	require.NoError(t, err)
+	assert.NotEmpty(t, params.SecretARN)
+}
+
+func TestRedshiftToolsRequireStatementID(t *testing.T) {
+	h := NewToolHandler(&config.Config{}, nil, logging.NewLogger("error", "text"))
+
+	for _, tool := range []string{"get-redshift-query", "get-redshift-query-results"} {
+		result, err := h.CallTool(context.Background(), tool, map[string]interface{}{})
+		require.NoError(t, err)
+		assert.Equal(t, "statementId is required", decodeToolResult(t, result)["error"], tool)
+	}
+}
@@ -3,49 +3,290 @@ package mcp
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"net/url"
 	"strings"
+	"sync"
+	"time"
 
+	"aws-mcp-server/internal/config"
+	"aws-mcp-server/pkg/accounts"
+	"aws-mcp-server/pkg/alertgroup"
+	"aws-mcp-server/pkg/approval"
 	"aws-mcp-server/pkg/aws"
+	"aws-mcp-server/pkg/azure"
+	"aws-mcp-server/pkg/cloud"
+	"aws-mcp-server/pkg/ignore"
+	"aws-mcp-server/pkg/inventory"
+	"aws-mcp-server/pkg/kb"
+	"aws-mcp-server/pkg/knowledge"
+	"aws-mcp-server/pkg/metrics"
+	"aws-mcp-server/pkg/notes"
+	"aws-mcp-server/pkg/oncall"
+	"aws-mcp-server/pkg/ownership"
+	"aws-mcp-server/pkg/prometheus"
+	"aws-mcp-server/pkg/pseudonym"
+	"aws-mcp-server/pkg/render"
+	"aws-mcp-server/pkg/summarize"
+	"aws-mcp-server/pkg/suppress"
 	"aws-mcp-server/pkg/types"
 
 	"github.com/mark3labs/mcp-go/mcp"
 )
 
 type ResourceHandler struct {
-	awsClient *aws.Client
+	config     *config.Config
+	awsClient  *aws.Client
+	clouds     *cloud.Registry
+	azure      *azure.Client
+	renderer   *render.Renderer
+	times      *render.TimeFormatter
+	approvals  *approval.Queue
+	prometheus *prometheus.Client
+	metrics    metrics.Provider
+	oncall     *oncall.Roster
+	owners     *ownership.Resolver
+
+	suppressions *suppress.List
+	notes        *notes.Store
+	ignored      *ignore.List
+	demo         *pseudonym.Pseudonymizer
+	grouper      *alertgroup.Grouper
+	outcomes     *knowledge.Store
+	docs         *kb.Index
+	summaries    *summarize.Summarizer
+	inventory    *inventory.Cache
+	accounts     *accounts.Directory
+	// refreshing holds the instance list URIs whose snapshot is being
+	// listed in the background
+	refreshing sync.Map
+
+	routes *resourceRouter
 }
 
-func NewResourceHandler(awsClient *aws.Client) *ResourceHandler {
-	return &ResourceHandler{
+func NewResourceHandler(cfg *config.Config, awsClient *aws.Client) *ResourceHandler {
+	h := &ResourceHandler{
+		config:    cfg,
 		awsClient: awsClient,
+		clouds:    cloud.NewRegistry(cloud.NewAWSProvider(awsClient)),
+		times:     render.NewTimeFormatter(cfg.Response.Timezone, cfg.Response.RelativeTimes),
+		routes:    &resourceRouter{},
 	}
+	h.registerRoutes()
+	return h
 }
 
 // ReadResource handles requests for specific resources
 func (h *ResourceHandler) ReadResource(ctx context.Context, uri string) (*mcp.ReadResourceResult, error) {
+	// In demo mode URIs may name resources by their pseudonyms
+	result, err := h.readResource(ctx, h.demo.Reveal(uri))
+	if err != nil {
+		if h.demo != nil {
+			return nil, errors.New(h.demo.Text(err.Error()))
+		}
+		return nil, err
+	}
+	return h.pseudonymize(result), nil
+}
+
+// readResource reads a resource by its real URI
+func (h *ResourceHandler) readResource(ctx context.Context, uri string) (*mcp.ReadResourceResult, error) {
+	// Instance lists take their page in the query, e.g. aws://ec2/instances?cursor=...
+	path, rawQuery, _ := strings.Cut(uri, "?")
+	if provider, instanceID, ok := h.clouds.Resolve(path); ok {
+		query, err := url.ParseQuery(rawQuery)
+		if err != nil {
+			return nil, fmt.Errorf("invalid query in %s: %w", uri, err)
+		}
+		return h.readCloudInstances(ctx, provider, instanceID, query)
+	}
+
+	result, req, err := h.routes.Read(ctx, uri)
+	if err != nil {
+		return nil, err
+	}
+
+	// The route's template names the summary template
+	return h.addSummary(req.Template, result), nil
+}
+
+// registerRoutes adds the routes of the resources read from AWS, Azure and
+// the configured integrations. Instance URIs depend on the configured cloud
+// providers and are resolved before these routes.
+func (h *ResourceHandler) registerRoutes() {
+	r := h.routes
+	r.Handle(azureResourceGroupsURI, static(h.readAzureResourceGroups))
+	r.Handle(alarmsURI, static(h.readAlarms))
+	r.Handle(alarmHistoryTemplate, byURI(h.readAlarmHistory))
+	r.Handle(ecsClustersURI, static(h.readECSClusters))
+	r.Handle(ecsTasksTemplate, byURI(h.readECSTasks))
+	r.Handle(ecsServicesTemplate, byURI(h.readECSServices))
+	r.Handle(sqsQueuesURI, static(h.readSQSQueues))
+	r.Handle(sqsQueueTemplate, h.readSQSQueue)
+	r.Handle(snsTopicsURI, static(h.readSNSTopics))
+	r.Handle(snsTopicTemplate, h.readSNSTopic)
+	r.Handle(loadBalancersURI, static(h.readLoadBalancers))
+	r.Handle(targetGroupsURI, static(h.readTargetGroups))
+	r.Handle(curDailyURI, static(h.readCURDaily))
+	r.Handle(curResourceTemplate, byURI(h.readCURResource))
+	r.Handle(dailyDigestURI, static(h.readDailyDigest))
+	r.Handle(costDailyURI, byURI(h.readCostDaily))
+	r.Handle(costByServiceURI, byURI(h.readCostByService))
+	r.Handle(cacheClustersURI, static(h.readCacheClusters))
+	r.Handle(cacheClusterTemplate, h.readCacheCluster)
+	r.Handle(parametersURI, byURI(h.readParameters))
+	r.Handle(parameterTemplate, byURI(h.readParameter))
+	r.Handle(secretsURI, static(h.readSecrets))
+	r.Handle(secretTemplate, byURI(h.readSecret))
+	r.Handle(apisURI, static(h.readAPIs))
+	r.Handle(apiTemplate, h.readAPI)
+	r.Handle(kinesisStreamsURI, static(h.readKinesisStreams))
+	r.Handle(kinesisStreamTemplate, h.readKinesisStream)
+	r.Handle(stateMachinesURI, static(h.readStateMachines))
+	r.Handle(executionsTemplate, h.readExecutions)
+	r.Handle(quotasURI, static(h.readQuotas))
+	r.Handle(quotaServiceTemplate, h.readServiceQuotas)
+	r.Handle(managedInstancesURI, static(h.readManagedInstances))
+	r.Handle(instanceInventoryTemplate, byURI(h.readInstanceInventory))
+	r.Handle(instancePatchesTemplate, byURI(h.readInstancePatches))
+	r.Handle(ecrRepositoriesURI, static(h.readECRRepositories))
+	r.Handle(ecrImageTemplate, byURI(h.readECRImage))
+	r.Handle(ecrImagesTemplate, byURI(h.readECRImages))
+	r.Handle("aws://rds/instances", static(h.readRDSInstances))
+	r.Handle(securityGroupsURI, static(h.readSecurityGroups))
+	r.Handle(securityGroupTemplate, byURI(h.readSecurityGroup))
+	r.Handle(vpcTopologyURI, static(h.readVPCTopology))
+	r.Handle(natGatewaysURI, static(h.readNATGateways))
+	r.Handle(vpcEndpointsURI, static(h.readVPCEndpoints))
+	r.Handle(ebsVolumesURI, static(h.readEBSVolumes))
+	r.Handle(ebsSnapshotsURI, static(h.readEBSSnapshots))
+	r.Handle(spotRequestsURI, static(h.readSpotRequests))
+	r.Handle(spotInterruptionsURI, static(h.readSpotInterruptions))
+	r.Handle(efsFileSystemsURI, static(h.readEFSFileSystems))
+	r.Handle(efsFileSystemTemplate, h.readEFSFileSystem)
+	r.Handle(configRulesURI, static(h.readConfigRules))
+	r.Handle(configRuleTemplate, h.readConfigRule)
+	r.Handle(configNonCompliantURI, static(h.readConfigNonCompliant))
+	r.Handle(backupJobsURI, static(h.readBackupJobs))
+	r.Handle(backupResourceTemplate, h.readBackupResource)
+	r.Handle("aws://security/unencrypted", static(h.readUnencryptedResources))
+	r.Handle("aws://iam/credential-hygiene", static(h.readCredentialHygiene))
+	r.Handle(iamRolesURI, static(h.readIAMRoles))
+	r.Handle(iamUsersURI, static(h.readIAMUsers))
+	r.Handle(iamPolicyTemplate, byURI(h.readIAMPolicy))
+	r.Handle(suppressionsURI, static(h.readSuppressions))
+	r.Handle(notesURI, static(h.readNotes))
+	r.Handle(notesTemplate, h.readResourceNotes)
+	r.Handle(ignoredResourcesURI, static(h.readIgnoredResources))
+	r.Handle("aws://approvals/pending", static(h.readPendingApprovals))
+	r.Handle("aws://oncall/current", static(h.readOnCall))
+	r.Handle(remediationsURI, byURI(h.readRemediations))
+	r.Handle(docsURI, byURI(h.readDocs))
+	r.Handle(docTemplate, byURI(h.readDoc))
+	r.Handle(ownershipTemplate, byURI(h.readOwnership))
+	r.Handle(promQueryTemplate, byURI(h.readPromQuery))
+	r.Handle(metricsQueryTemplate, byURI(h.readMetricsQuery))
+	r.Handle(organizationAccountsURI, static(h.readOrganizationAccounts))
+	r.Handle(orgInventoryURI, static(h.readOrgInventory))
+	r.Handle(orgCostDailyURI, byURI(h.readOrgCostDaily))
+	r.Handle(accountInstancesTemplate, h.readAccountInstances)
+}
+
+// readCloudInstances reads a page of the instance list, an instance grouping
+// or one instance of a cloud provider
+func (h *ResourceHandler) readCloudInstances(ctx context.Context, provider cloud.Provider, instanceID string, query url.Values) (*mcp.ReadResourceResult, error) {
+	var result *mcp.ReadResourceResult
+	var err error
+
+	summaryKey := cloud.InstancesURI(provider)
 	switch {
-	case uri == "aws://ec2/instances":
-		return h.readEC2InstancesList(ctx)
-	case strings.HasPrefix(uri, "aws://ec2/instances/"):
-		instanceID := strings.TrimPrefix(uri, "aws://ec2/instances/")
-		return h.readEC2Instance(ctx, instanceID)
+	case instanceID == "":
+		result, err = h.readInstancesList(ctx, provider, query)
+	case isInstanceGrouping(provider, instanceID):
+		summaryKey = instanceGroupingKey
+		result, err = h.readInstanceGrouping(ctx, provider, instanceID)
 	default:
-		return nil, fmt.Errorf("unknown resource URI: %s", uri)
+		summaryKey += "/{instanceId}"
+		result, err = h.readInstance(ctx, provider, instanceID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return h.addSummary(summaryKey, result), nil
+}
+
+// addSummary appends a plain-text summary to a JSON resource
+func (h *ResourceHandler) addSummary(key string, result *mcp.ReadResourceResult) *mcp.ReadResourceResult {
+	if h.renderer == nil || len(result.Contents) != 1 {
+		return result
 	}
+
+	contents, ok := result.Contents[0].(*mcp.TextResourceContents)
+	if !ok {
+		return result
+	}
+
+	summary, ok := h.renderer.RenderJSON(key, []byte(contents.Text), map[string]interface{}{
+		"region": h.config.AWS.Region,
+	})
+	if !ok {
+		return result
+	}
+
+	result.Contents = append(result.Contents, &mcp.TextResourceContents{
+		URI:      contents.URI,
+		MIMEType: "text/plain",
+		Text:     summary,
+	})
+	return result
 }
 
-// readEC2InstancesList returns a formatted list of all EC2 instances
-func (h *ResourceHandler) readEC2InstancesList(ctx context.Context) (*mcp.ReadResourceResult, error) {
-	instances, err := h.awsClient.ListEC2Instances(ctx)
+// readInstancesList returns a page of the formatted list of all instances of
+// a provider. Pages of the inventory snapshot and of whole lists carry the
+// totals and summaries of all instances; pages read from the provider one at
+// a time only those of the page, and the snapshot is saved when the last page
+// is read.
+func (h *ResourceHandler) readInstancesList(ctx context.Context, provider cloud.Provider, query url.Values) (*mcp.ReadResourceResult, error) {
+	uri := cloud.InstancesURI(provider)
+	size, cursor, err := h.instancePage(query)
 	if err != nil {
-		return nil, fmt.Errorf("failed to list EC2 instances: %w", err)
+		return nil, err
 	}
 
-	// Format the data for AI consumption
-	formatted := h.formatInstancesForAI(instances)
+	// Right after a restart the previous run's snapshot answers, marked as
+	// possibly stale, while the provider is listed again in the background.
+	// Cursors of pages read from the provider keep reading from it.
+	var list instanceList
+	if snapshot, ok := h.inventory.Warm(uri); ok && cursor.token == "" {
+		list = pageOf(h.formatInstancesForAI(snapshot.Instances), cursor.offset, size)
+		list.LastRefresh = h.times.Format(snapshot.RefreshedAt)
+		list.Stale = true
+	} else if snapshot, ok := h.inventory.Current(uri, 2*h.config.Inventory.RefreshInterval); ok && cursor.token == "" {
+		// The background refresher keeps the snapshot current; a refresh
+		// running late is given one more interval
+		list = pageOf(h.formatInstancesForAI(snapshot.Instances), cursor.offset, size)
+		list.LastRefresh = h.times.Format(snapshot.RefreshedAt)
+	} else {
+		var instances []types.CloudResource
+		list, instances, err = h.listInstancePage(ctx, provider, size, cursor)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list %s instances: %w", provider.Label(), err)
+		}
+		// A snapshot that cannot be saved only costs the next warm start.
+		// Pages read from the provider hold part of its instances, so once
+		// the last one is read they are all listed for the snapshot, in the
+		// background rather than within the read of the last page.
+		switch {
+		case !list.Partial:
+			_ = h.inventory.Put(uri, instances, time.Now())
+		case list.NextCursor == "" && h.config.Inventory.Path != "":
+			h.refreshSnapshot(ctx, provider)
+		}
+	}
 
-	jsonData, err := json.MarshalIndent(formatted, "", "  ")
+	text, err := encodeInstanceList(list)
 	if err != nil {
 		return nil, fmt.Errorf("failed to marshal instances data: %w", err)
 	}
@@ -53,23 +294,26 @@ func (h *ResourceHandler) readEC2InstancesList(ctx context.Context) (*mcp.ReadRe
 	return &mcp.ReadResourceResult{
 		Contents: []mcp.ResourceContents{
 			&mcp.TextResourceContents{
-				URI:      "aws://ec2/instances",
+				URI:      uri,
 				MIMEType: "application/json",
-				Text:     string(jsonData),
+				Text:     text,
 			},
 		},
 	}, nil
 }
 
-// readEC2Instance returns detailed information about a specific instance
-func (h *ResourceHandler) readEC2Instance(ctx context.Context, instanceID string) (*mcp.ReadResourceResult, error) {
-	instance, err := h.awsClient.GetEC2Instance(ctx, instanceID)
+// readInstance returns detailed information about a specific instance
+func (h *ResourceHandler) readInstance(ctx context.Context, provider cloud.Provider, instanceID string) (*mcp.ReadResourceResult, error) {
+	instance, err := provider.GetInstance(ctx, instanceID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get EC2 instance: %w", err)
+		return nil, fmt.Errorf("failed to get %s instance: %w", provider.Label(), err)
 	}
 
 	// Format for AI consumption
 	formatted := h.formatInstanceForAI(*instance)
+	if attached := h.resourceNotes(instanceID); attached != nil {
+		formatted["notes"] = attached
+	}
 
 	jsonData, err := json.MarshalIndent(formatted, "", "  ")
 	if err != nil {
@@ -79,7 +323,7 @@ func (h *ResourceHandler) readEC2Instance(ctx context.Context, instanceID string
 	return &mcp.ReadResourceResult{
 		Contents: []mcp.ResourceContents{
 			&mcp.TextResourceContents{
-				URI:      fmt.Sprintf("aws://ec2/instances/%s", instanceID),
+				URI:      cloud.InstancesURI(provider) + "/" + instanceID,
 				MIMEType: "application/json",
 				Text:     string(jsonData),
 			},
@@ -87,65 +331,118 @@ func (h *ResourceHandler) readEC2Instance(ctx context.Context, instanceID string
 	}, nil
 }
 
-// formatInstancesForAI formats instance data optimally for AI processing
-func (h *ResourceHandler) formatInstancesForAI(instances []types.AWSResource) map[string]interface{} {
-	summary := map[string]interface{}{
-		"total_instances":  len(instances),
-		"instances":        make([]map[string]interface{}, 0, len(instances)),
-		"summary_by_state": make(map[string]int),
-		"summary_by_type":  make(map[string]int),
-	}
+// instanceList is the instance list of a provider. Its fields, like those of
+// instanceSummary, are in the order of their JSON names, which keeps the
+// encoding identical to that of a map. Lists served from the inventory
+// snapshot say when it was refreshed, and are Stale when it is the previous
+// run's. Lists of another organization account name its alias. Lists read a
+// page at a time have a NextCursor until the last page, and are Partial when
+// their totals and summaries cover just the page.
+type instanceList struct {
+	Account           string            `json:"account,omitempty"`
+	Instances         []instanceSummary `json:"instances"`
+	LastRefresh       string            `json:"last_refresh,omitempty"`
+	NextCursor        string            `json:"next_cursor,omitempty"`
+	Partial           bool              `json:"partial,omitempty"`
+	Stale             bool              `json:"stale,omitempty"`
+	SummaryByPlatform map[string]int    `json:"summary_by_platform,omitempty"`
+	SummaryByState    map[string]int    `json:"summary_by_state"`
+	SummaryByType     map[string]int    `json:"summary_by_type"`
+	TotalInstances    int               `json:"total_instances"`
+}
 
-	stateCount := make(map[string]int)
-	typeCount := make(map[string]int)
+// instanceSummary is one instance of an instance list
+type instanceSummary struct {
+	ID         string `json:"id"`
+	LaunchTime string `json:"launch_time,omitempty"`
+	Launched   string `json:"launched,omitempty"`
+	Name       string `json:"name,omitempty"`
+	Platform   string `json:"platform,omitempty"`
+	PrivateIP  string `json:"private_ip,omitempty"`
+	PublicIP   string `json:"public_ip,omitempty"`
+	Region     string `json:"region"`
+	State      string `json:"state"`
+	Type       string `json:"type"`
+}
 
-	for _, instance := range instances {
-		formatted := map[string]interface{}{
-			"id":     instance.ID,
-			"state":  instance.State,
-			"type":   instance.Details["instanceType"],
-			"region": instance.Region,
-		}
+// formatInstancesForAI formats instance data optimally for AI processing.
+// Accounts can have thousands of instances, so the list is built in one
+// preallocated slice of structs rather than a map per instance.
+func (h *ResourceHandler) formatInstancesForAI(instances []types.CloudResource) instanceList {
+	list := instanceList{
+		Instances:      make([]instanceSummary, len(instances)),
+		SummaryByState: make(map[string]int),
+		SummaryByType:  make(map[string]int),
+		TotalInstances: len(instances),
+	}
 
-		// Add name if available from tags
-		if name, exists := instance.Tags["Name"]; exists {
-			formatted["name"] = name
-		}
+	for i, instance := range instances {
+		formatted := &list.Instances[i]
+		formatted.ID = instance.ID
+		formatted.State = instance.State
+		formatted.Type = instance.InstanceType()
+		formatted.Region = instance.Region
+		formatted.Name = instance.Tags["Name"]
+		formatted.PublicIP = instance.PublicIPAddress()
+		formatted.PrivateIP = instance.PrivateIPAddress()
 
-		// Add IP addresses if available
-		if publicIP := instance.Details["publicIpAddress"]; publicIP != nil {
-			formatted["public_ip"] = publicIP
+		if details, ok := instance.Details.(*types.EC2InstanceDetails); ok {
+			formatted.Platform = details.Platform
+			if list.SummaryByPlatform == nil {
+				list.SummaryByPlatform = make(map[string]int)
+			}
+			list.SummaryByPlatform[details.Platform]++
 		}
 
-		if privateIP := instance.Details["privateIpAddress"]; privateIP != nil {
-			formatted["private_ip"] = privateIP
+		if launched, ok := launchTime(instance); ok {
+			formatted.LaunchTime = h.times.Format(launched)
+			formatted.Launched = h.times.Relative(launched)
 		}
 
-		summary["instances"] = append(summary["instances"].([]map[string]interface{}), formatted)
-
 		// Update counters
-		stateCount[instance.State]++
-		if instanceType, ok := instance.Details["instanceType"].(string); ok {
-			typeCount[instanceType]++
+		list.SummaryByState[instance.State]++
+		if formatted.Type != "" {
+			list.SummaryByType[formatted.Type]++
 		}
 	}
 
-	summary["summary_by_state"] = stateCount
-	summary["summary_by_type"] = typeCount
+	return list
+}
+
+// instanceListBytes estimates the size of one instance in the encoded list
+const instanceListBytes = 320
+
+// encodeInstanceList encodes an instance list as indented JSON straight into
+// the resource text, sized up front so large lists are not copied as they grow
+func encodeInstanceList(list instanceList) (string, error) {
+	var text strings.Builder
+	text.Grow(len(list.Instances)*instanceListBytes + 512)
 
-	return summary
+	encoder := json.NewEncoder(&text)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(list); err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(text.String(), "\n"), nil
 }
 
 // formatInstanceForAI formats a single instance with comprehensive details
-func (h *ResourceHandler) formatInstanceForAI(instance types.AWSResource) map[string]interface{} {
+func (h *ResourceHandler) formatInstanceForAI(instance types.CloudResource) map[string]interface{} {
 	formatted := map[string]interface{}{
 		"id":        instance.ID,
+		"provider":  instance.Provider,
 		"type":      instance.Type,
 		"state":     instance.State,
 		"region":    instance.Region,
 		"tags":      instance.Tags,
-		"details":   instance.Details,
-		"last_seen": instance.LastSeen.Format("2006-01-02T15:04:05Z07:00"),
+		"details":   h.formatDetails(instance.Details),
+		"last_seen": h.times.Format(instance.LastSeen),
+	}
+
+	if launched, ok := launchTime(instance); ok {
+		if relative := h.times.Relative(launched); relative != "" {
+			formatted["launched"] = relative
+		}
 	}
 
 	// Add computed fields that AI systems find useful
@@ -160,5 +457,53 @@ func (h *ResourceHandler) formatInstanceForAI(instance types.AWSResource) map[st
 		formatted["environment"] = env
 	}
 
+	// Windows instances are managed with PowerShell and reached over RDP
+	if details, ok := instance.Details.(*types.EC2InstanceDetails); ok {
+		formatted["platform"] = details.Platform
+		if details.Platform == "windows" {
+			formatted["remote_access"] = "RDP on port 3389; SSM commands run as PowerShell"
+			if details.KeyName != "" {
+				formatted["remote_access"] = "RDP on port 3389 with the Administrator password from get-windows-password; SSM commands run as PowerShell"
+			}
+		}
+	}
+
 	return formatted
 }
+
+// formatDetails copies resource details with timestamps rendered in the configured timezone
+func (h *ResourceHandler) formatDetails(details types.ResourceDetails) map[string]interface{} {
+	if details == nil {
+		return map[string]interface{}{}
+	}
+	fields := details.Fields()
+	formatted := make(map[string]interface{}, len(fields))
+	for key, value := range fields {
+		switch v := value.(type) {
+		case time.Time:
+			formatted[key] = h.times.Format(v)
+		case *time.Time:
+			if v != nil {
+				formatted[key] = h.times.Format(*v)
+			}
+		default:
+			formatted[key] = value
+		}
+	}
+	return formatted
+}
+
+// launchTime returns the launch time recorded in an instance's details
+func launchTime(instance types.CloudResource) (time.Time, bool) {
+	var launched *time.Time
+	switch details := instance.Details.(type) {
+	case *types.EC2InstanceDetails:
+		launched = details.LaunchTime
+	case *types.ComputeEngineInstanceDetails:
+		launched = details.LaunchTime
+	}
+	if launched == nil {
+		return time.Time{}, false
+	}
+	return *launched, true
+}
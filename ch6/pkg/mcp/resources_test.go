@@ -0,0 +1,226 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"aws-mcp-server/internal/config"
+	"aws-mcp-server/internal/logging"
+	"aws-mcp-server/pkg/cloud"
+	"aws-mcp-server/pkg/inventory"
+	"aws-mcp-server/pkg/types"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeProvider serves a fixed set of instances
+type fakeProvider struct {
+	instances []types.CloudResource
+	started   []string
+}
+
+func (p *fakeProvider) Name() string    { return "fake" }
+func (p *fakeProvider) Service() string { return "vm" }
+func (p *fakeProvider) Label() string   { return "Fake" }
+
+func (p *fakeProvider) ListInstances(context.Context) ([]types.CloudResource, error) {
+	return p.instances, nil
+}
+
+func (p *fakeProvider) GetInstance(_ context.Context, instanceID string) (*types.CloudResource, error) {
+	for _, instance := range p.instances {
+		if instance.ID == instanceID {
+			return &instance, nil
+		}
+	}
+	return nil, fmt.Errorf("instance %s not found", instanceID)
+}
+
+func (p *fakeProvider) StartInstance(_ context.Context, instanceID string) error {
+	p.started = append(p.started, instanceID)
+	return nil
+}
+
+func (p *fakeProvider) StopInstance(context.Context, string) error {
+	return nil
+}
+
+func readJSONResource(t *testing.T, h *ResourceHandler, uri string) map[string]interface{} {
+	t.Helper()
+	result, err := h.ReadResource(context.Background(), uri)
+	require.NoError(t, err)
+	require.NotEmpty(t, result.Contents)
+
+	contents, ok := result.Contents[0].(*mcp.TextResourceContents)
+	require.True(t, ok)
+	assert.Equal(t, uri, contents.URI)
+
+	var data map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(contents.Text), &data))
+	return data
+}
+
+func TestInstanceResourcesUseCloudProviders(t *testing.T) {
+	provider := &fakeProvider{instances: []types.CloudResource{
+		{ID: "web-1", Provider: "fake", Type: "vm", State: "running", Tags: map[string]string{"Name": "web"}, Details: &types.ComputeEngineInstanceDetails{InstanceType: "small"}},
+		{ID: "batch-1", Provider: "fake", Type: "vm", State: "stopped", Details: &types.ComputeEngineInstanceDetails{InstanceType: "large"}},
+	}}
+
+	h := NewResourceHandler(&config.Config{}, nil)
+	h.clouds = cloud.NewRegistry(provider)
+
+	list := readJSONResource(t, h, "fake://vm/instances")
+	assert.Equal(t, float64(2), list["total_instances"])
+	assert.Equal(t, map[string]interface{}{"running": float64(1), "stopped": float64(1)}, list["summary_by_state"])
+
+	detail := readJSONResource(t, h, "fake://vm/instances/web-1")
+	assert.Equal(t, "web", detail["name"])
+	assert.Equal(t, "fake", detail["provider"])
+
+	_, err := h.ReadResource(context.Background(), "fake://vm/instances/missing")
+	assert.Error(t, err)
+
+	tools := NewToolHandler(&config.Config{}, nil, logging.NewLogger("error", "text"))
+	result, err := tools.startInstance(context.Background(), provider, map[string]interface{}{"instanceId": "web-1"})
+	require.NoError(t, err)
+	response := decodeToolResult(t, result)
+	assert.Equal(t, true, response["success"])
+	assert.Equal(t, "Fake instance start initiated successfully", response["message"])
+	assert.Equal(t, []string{"web-1"}, provider.started)
+}
+
+func TestInstanceListServesWarmSnapshot(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "inventory.json")
+	previous, err := inventory.Open(path, 0)
+	require.NoError(t, err)
+	require.NoError(t, previous.Put("fake://vm/instances", []types.CloudResource{
+		{ID: "old-1", Provider: "fake", Type: "compute-instance", State: "running", Details: &types.ComputeEngineInstanceDetails{InstanceType: "small"}},
+	}, time.Date(2026, 10, 15, 8, 0, 0, 0, time.UTC)))
+
+	provider := &fakeProvider{instances: []types.CloudResource{
+		{ID: "web-1", Provider: "fake", Type: "compute-instance", State: "running"},
+		{ID: "web-2", Provider: "fake", Type: "compute-instance", State: "stopped"},
+	}}
+	h := NewResourceHandler(&config.Config{}, nil)
+	h.clouds = cloud.NewRegistry(provider)
+	h.inventory, err = inventory.Open(path, 0)
+	require.NoError(t, err)
+
+	list := readJSONResource(t, h, "fake://vm/instances")
+	assert.Equal(t, true, list["stale"])
+	assert.Equal(t, "2026-10-15T08:00:00Z", list["last_refresh"])
+	assert.Equal(t, float64(1), list["total_instances"])
+	assert.Equal(t, map[string]interface{}{"small": float64(1)}, list["summary_by_type"])
+
+	_, err = h.inventory.Update(context.Background(), provider, 0)
+	require.NoError(t, err)
+	list = readJSONResource(t, h, "fake://vm/instances")
+	assert.NotContains(t, list, "stale")
+	assert.NotContains(t, list, "last_refresh")
+	assert.Equal(t, float64(2), list["total_instances"])
+}
+
+func TestFormatDetailsMatchesJSON(t *testing.T) {
+	h := NewResourceHandler(&config.Config{}, nil)
+	launched := time.Date(2025, 6, 1, 8, 0, 0, 0, time.UTC)
+	details := &types.EC2InstanceDetails{
+		InstanceType:   "t3.micro",
+		LaunchTime:     &launched,
+		Platform:       "linux",
+		VpcID:          "vpc-1",
+		SecurityGroups: []string{"sg-web"},
+	}
+
+	encoded, err := json.Marshal(details)
+	require.NoError(t, err)
+	var keys map[string]interface{}
+	require.NoError(t, json.Unmarshal(encoded, &keys))
+
+	formatted := h.formatDetails(details)
+	for key := range keys {
+		assert.Contains(t, formatted, key)
+	}
+	assert.Len(t, formatted, len(keys))
+	assert.NotContains(t, formatted, "publicIpAddress")
+	assert.Equal(t, h.times.Format(launched), formatted["launchTime"])
+	assert.Empty(t, h.formatDetails(nil))
+}
+
+// syntheticInstances returns n EC2 instances with the attributes an instance
+// list shows
+func syntheticInstances(n int) []types.CloudResource {
+	launched := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	instances := make([]types.CloudResource, n)
+	for i := range instances {
+		platform := "linux"
+		if i%10 == 0 {
+			platform = "windows"
+		}
+		instances[i] = types.CloudResource{
+			ID:     fmt.Sprintf("i-%017x", i),
+			State:  []string{"running", "stopped"}[i%2],
+			Region: "us-east-1",
+			Tags:   map[string]string{"Name": fmt.Sprintf("web-%d", i)},
+			Details: &types.EC2InstanceDetails{
+				InstanceType:     []string{"m5.large", "t3.micro", "c6g.xlarge"}[i%3],
+				Platform:         platform,
+				LaunchTime:       &launched,
+				PrivateIPAddress: fmt.Sprintf("10.0.%d.%d", i/256%256, i%256),
+				PublicIPAddress:  "203.0.113.10",
+			},
+		}
+	}
+	return instances
+}
+
+func TestEncodeInstanceListMatchesMarshalIndent(t *testing.T) {
+	h := NewResourceHandler(&config.Config{}, nil)
+	list := h.formatInstancesForAI(append(syntheticInstances(3), types.CloudResource{ID: "vm-1", State: "RUNNING",
+		Details: &types.ComputeEngineInstanceDetails{InstanceType: "e2-small"}}))
+
+	text, err := encodeInstanceList(list)
+	require.NoError(t, err)
+	expected, err := json.MarshalIndent(list, "", "  ")
+	require.NoError(t, err)
+	assert.Equal(t, string(expected), text)
+
+	assert.Equal(t, 4, list.TotalInstances)
+	assert.Equal(t, map[string]int{"linux": 2, "windows": 1}, list.SummaryByPlatform, "only EC2 instances have a platform")
+	assert.Equal(t, map[string]int{"running": 2, "stopped": 1, "RUNNING": 1}, list.SummaryByState)
+	assert.Equal(t, "web-0", list.Instances[0].Name)
+	assert.Equal(t, "2026-01-02T03:04:05Z", list.Instances[0].LaunchTime)
+	assert.Empty(t, list.Instances[3].Platform)
+}
+
+// BenchmarkInstancesList measures formatting and encoding the instance list
+// of accounts of growing size, the work behind each read of an instances URI
+func BenchmarkInstancesList(b *testing.B) {
+	h := NewResourceHandler(&config.Config{}, nil)
+	for _, n := range []int{100, 1000, 5000} {
+		instances := syntheticInstances(n)
+		b.Run(fmt.Sprintf("instances=%d", n), func(b *testing.B) {
+			b.ReportAllocs()
+			for b.Loop() {
+				if _, err := encodeInstanceList(h.formatInstancesForAI(instances)); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkFormatInstancesForAI measures formatting alone, without encoding
+func BenchmarkFormatInstancesForAI(b *testing.B) {
+	h := NewResourceHandler(&config.Config{}, nil)
+	instances := syntheticInstances(5000)
+	b.ReportAllocs()
+	for b.Loop() {
+		h.formatInstancesForAI(instances)
+	}
+}
@@ -0,0 +1,293 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"aws-mcp-server/pkg/accounts"
+	"aws-mcp-server/pkg/aws"
+	"aws-mcp-server/pkg/cloud"
+	"aws-mcp-server/pkg/types"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const (
+	// orgInventoryURI rolls up the EC2 instances of every organization account
+	orgInventoryURI = "aws://org/inventory"
+	// orgCostDailyURI rolls up the daily cost of every organization account
+	orgCostDailyURI = "aws://org/cost/daily"
+	// orgCostDailyTemplate overrides the window of orgCostDailyURI
+	orgCostDailyTemplate = "aws://org/cost/daily{?days}"
+	// rollupConcurrency is how many accounts a rollup reads at a time
+	rollupConcurrency = 8
+)
+
+// accountFailure is an account a rollup could not read
+type accountFailure struct {
+	Alias string `json:"alias"`
+	ID    string `json:"id,omitempty"`
+	Error string `json:"error"`
+}
+
+// accountInventory is the instance counts of one account
+type accountInventory struct {
+	Alias          string         `json:"alias"`
+	ID             string         `json:"id"`
+	InstancesURI   string         `json:"instances_uri"`
+	SummaryByState map[string]int `json:"summary_by_state"`
+	SummaryByType  map[string]int `json:"summary_by_type"`
+	TotalInstances int            `json:"total_instances"`
+}
+
+// accountCost is the cost of one account over the window
+type accountCost struct {
+	Alias        string  `json:"alias"`
+	ID           string  `json:"id"`
+	SharePercent float64 `json:"share_percent"`
+	TotalUSD     float64 `json:"total_usd"`
+	periods      []types.CostPeriod
+}
+
+// forEachAccount calls read for every account, rollupConcurrency accounts at
+// a time, and returns the accounts it failed for. Calls run concurrently, so
+// read must only write to the index of its account.
+func (h *ResourceHandler) forEachAccount(ctx context.Context, list []accounts.Account, read func(ctx context.Context, i int, client *aws.Client) error) []accountFailure {
+	failures := make([]error, len(list))
+	slots := make(chan struct{}, rollupConcurrency)
+	var wg sync.WaitGroup
+	for i, account := range list {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			slots <- struct{}{}
+			defer func() { <-slots }()
+
+			failures[i] = read(ctx, i, h.accounts.ClientFor(ctx, account))
+		}()
+	}
+	wg.Wait()
+
+	failed := []accountFailure{}
+	for i, err := range failures {
+		if err != nil {
+			failed = append(failed, accountFailure{Alias: list[i].Alias, ID: list[i].ID, Error: err.Error()})
+		}
+	}
+	return failed
+}
+
+// rollupAccounts returns the accounts a rollup covers. When the organization
+// cannot be listed the configured accounts are still covered and the listing
+// is reported as failed.
+func (h *ResourceHandler) rollupAccounts(ctx context.Context) ([]accounts.Account, []accountFailure, error) {
+	if h.accounts == nil {
+		return nil, nil, accounts.ErrNotConfigured
+	}
+
+	list, err := h.accounts.Accounts(ctx)
+	if err != nil {
+		return list, []accountFailure{{Alias: "organization", Error: err.Error()}}, nil
+	}
+	return list, nil, nil
+}
+
+// readOrgInventory counts the EC2 instances of every account, read
+// concurrently through the accounts' assumed roles
+func (h *ResourceHandler) readOrgInventory(ctx context.Context) (*mcp.ReadResourceResult, error) {
+	list, failed, err := h.rollupAccounts(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	inventories := make([]*accountInventory, len(list))
+	failed = append(failed, h.forEachAccount(ctx, list, func(ctx context.Context, i int, client *aws.Client) error {
+		instances, err := cloud.NewAWSProvider(client).ListInstances(ctx)
+		if err != nil {
+			return err
+		}
+		inventories[i] = summarizeAccountInstances(list[i], instances)
+		return nil
+	})...)
+
+	jsonData, err := json.MarshalIndent(formatOrgInventory(inventories, failed), "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal organization inventory data: %w", err)
+	}
+
+	return &mcp.ReadResourceResult{
+		Contents: []mcp.ResourceContents{
+			&mcp.TextResourceContents{
+				URI:      orgInventoryURI,
+				MIMEType: "application/json",
+				Text:     string(jsonData),
+			},
+		},
+	}, nil
+}
+
+// readOrgCostDaily sums the daily cost of every account over the window, read
+// concurrently from each account's Cost Explorer
+func (h *ResourceHandler) readOrgCostDaily(ctx context.Context, uri string) (*mcp.ReadResourceResult, error) {
+	params, err := costParams(uri)
+	if err != nil {
+		return nil, err
+	}
+	days, err := costDays(params.Get("days"), h.config.Cost.Explorer.Days)
+	if err != nil {
+		return nil, err
+	}
+
+	list, failed, err := h.rollupAccounts(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	start, end := costWindow(time.Now(), days)
+	costs := make([]*accountCost, len(list))
+	failed = append(failed, h.forEachAccount(ctx, list, func(ctx context.Context, i int, client *aws.Client) error {
+		periods, err := client.GetCostAndUsage(ctx, aws.CostQuery{
+			Start:       start,
+			End:         end,
+			Granularity: "DAILY",
+			Metric:      h.costMetric(),
+		})
+		if err != nil {
+			return err
+		}
+		costs[i] = &accountCost{Alias: list[i].Alias, ID: list[i].ID, periods: periods}
+		return nil
+	})...)
+
+	data := formatOrgCost(costs, failed)
+	data["start"] = start.Format(time.DateOnly)
+	data["end"] = end.AddDate(0, 0, -1).Format(time.DateOnly)
+	data["days"] = days
+	data["metric"] = h.costMetric()
+	data["note"] = "Each account's own Cost Explorer view; the management account's consolidated bill is at aws://cost/daily?groupBy=LINKED_ACCOUNT"
+
+	jsonData, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal organization cost data: %w", err)
+	}
+
+	return &mcp.ReadResourceResult{
+		Contents: []mcp.ResourceContents{
+			&mcp.TextResourceContents{
+				URI:      uri,
+				MIMEType: "application/json",
+				Text:     string(jsonData),
+			},
+		},
+	}, nil
+}
+
+// summarizeAccountInstances counts the instances of one account by state and
+// type
+func summarizeAccountInstances(account accounts.Account, instances []types.CloudResource) *accountInventory {
+	inventory := &accountInventory{
+		Alias:          account.Alias,
+		ID:             account.ID,
+		InstancesURI:   strings.Replace(accountInstancesTemplate, "{account}", account.Alias, 1),
+		SummaryByState: make(map[string]int),
+		SummaryByType:  make(map[string]int),
+		TotalInstances: len(instances),
+	}
+	for _, instance := range instances {
+		inventory.SummaryByState[instance.State]++
+		if instanceType := instance.InstanceType(); instanceType != "" {
+			inventory.SummaryByType[instanceType]++
+		}
+	}
+	return inventory
+}
+
+// formatOrgInventory adds up the instance counts of the accounts that were
+// read, listing the accounts with the most instances first. Accounts that
+// failed are nil.
+func formatOrgInventory(inventories []*accountInventory, failed []accountFailure) map[string]interface{} {
+	read := []accountInventory{}
+	byState := make(map[string]int)
+	byType := make(map[string]int)
+	total := 0
+	for _, inventory := range inventories {
+		if inventory == nil {
+			continue
+		}
+		read = append(read, *inventory)
+		total += inventory.TotalInstances
+		for state, count := range inventory.SummaryByState {
+			byState[state] += count
+		}
+		for instanceType, count := range inventory.SummaryByType {
+			byType[instanceType] += count
+		}
+	}
+	sort.SliceStable(read, func(i, j int) bool {
+		if read[i].TotalInstances != read[j].TotalInstances {
+			return read[i].TotalInstances > read[j].TotalInstances
+		}
+		return read[i].Alias < read[j].Alias
+	})
+
+	return map[string]interface{}{
+		"accounts":         read,
+		"failed_accounts":  failed,
+		"summary_by_state": byState,
+		"summary_by_type":  byType,
+		"total_accounts":   len(read),
+		"total_instances":  total,
+	}
+}
+
+// formatOrgCost adds up the daily cost of the accounts that were read, with
+// each account's total and share, the most expensive first. Accounts that
+// failed are nil.
+func formatOrgCost(costs []*accountCost, failed []accountFailure) map[string]interface{} {
+	read := []accountCost{}
+	byDay := make(map[string]float64)
+	total := 0.0
+	for _, cost := range costs {
+		if cost == nil {
+			continue
+		}
+		for _, period := range cost.periods {
+			cost.TotalUSD += period.Total
+			byDay[period.Start.Format(time.DateOnly)] += period.Total
+		}
+		total += cost.TotalUSD
+		read = append(read, *cost)
+	}
+	for i := range read {
+		if total > 0 {
+			read[i].SharePercent = math.Round(read[i].TotalUSD/total*1000) / 10
+		}
+		read[i].TotalUSD = math.Round(read[i].TotalUSD*100) / 100
+	}
+	sort.SliceStable(read, func(i, j int) bool {
+		if read[i].TotalUSD != read[j].TotalUSD {
+			return read[i].TotalUSD > read[j].TotalUSD
+		}
+		return read[i].Alias < read[j].Alias
+	})
+
+	periods := make([]map[string]interface{}, 0, len(byDay))
+	for day, amount := range byDay {
+		periods = append(periods, map[string]interface{}{"start": day, "total_usd": math.Round(amount*100) / 100})
+	}
+	sort.Slice(periods, func(i, j int) bool { return periods[i]["start"].(string) < periods[j]["start"].(string) })
+
+	return map[string]interface{}{
+		"accounts":        read,
+		"failed_accounts": failed,
+		"periods":         periods,
+		"total_accounts":  len(read),
+		"total_usd":       math.Round(total*100) / 100,
+	}
+}
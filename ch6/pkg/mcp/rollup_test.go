@@ -0,0 +1,94 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"aws-mcp-server/internal/config"
+	"aws-mcp-server/pkg/accounts"
+	"aws-mcp-server/pkg/render"
+	"aws-mcp-server/pkg/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatOrgInventory(t *testing.T) {
+	instance := func(state, instanceType string) types.CloudResource {
+		return types.CloudResource{State: state, Details: &types.EC2InstanceDetails{InstanceType: instanceType}}
+	}
+	prod := summarizeAccountInstances(accounts.Account{Alias: "prod", ID: "111111111111"}, []types.CloudResource{
+		instance("running", "m5.large"), instance("running", "m5.large"), instance("stopped", "t3.micro"),
+	})
+	dev := summarizeAccountInstances(accounts.Account{Alias: "dev", ID: "222222222222"}, []types.CloudResource{
+		instance("running", "t3.micro"),
+	})
+	assert.Equal(t, "aws://prod/ec2/instances", prod.InstancesURI)
+
+	failed := []accountFailure{{Alias: "sandbox", ID: "333333333333", Error: "AccessDenied"}}
+	data := formatOrgInventory([]*accountInventory{dev, nil, prod}, failed)
+	assert.Equal(t, 2, data["total_accounts"], "failed accounts are not counted")
+	assert.Equal(t, 4, data["total_instances"])
+	assert.Equal(t, map[string]int{"running": 3, "stopped": 1}, data["summary_by_state"])
+	assert.Equal(t, map[string]int{"m5.large": 2, "t3.micro": 2}, data["summary_by_type"])
+	assert.Equal(t, "prod", data["accounts"].([]accountInventory)[0].Alias, "accounts with the most instances come first")
+
+	renderer, err := render.New(nil)
+	require.NoError(t, err)
+	payload, err := json.Marshal(data)
+	require.NoError(t, err)
+	summary, ok := renderer.RenderJSON(orgInventoryURI, payload, nil)
+	require.True(t, ok)
+	assert.Equal(t, "4 EC2 instances across 2 accounts: 3 running 1 stopped; 1 failed: sandbox", summary)
+}
+
+func TestFormatOrgCost(t *testing.T) {
+	day := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	periods := func(amounts ...float64) []types.CostPeriod {
+		var list []types.CostPeriod
+		for i, amount := range amounts {
+			list = append(list, types.CostPeriod{Start: day.AddDate(0, 0, i), Total: amount, Unit: "USD"})
+		}
+		return list
+	}
+
+	data := formatOrgCost([]*accountCost{
+		{Alias: "dev", ID: "222222222222", periods: periods(10, 15)},
+		nil,
+		{Alias: "prod", ID: "111111111111", periods: periods(30, 45)},
+	}, []accountFailure{})
+	assert.Equal(t, 100.0, data["total_usd"])
+	assert.Equal(t, 2, data["total_accounts"])
+	assert.Equal(t, []map[string]interface{}{
+		{"start": "2024-05-01", "total_usd": 40.0},
+		{"start": "2024-05-02", "total_usd": 60.0},
+	}, data["periods"])
+
+	costs := data["accounts"].([]accountCost)
+	require.Len(t, costs, 2)
+	assert.Equal(t, "prod", costs[0].Alias, "the most expensive account comes first")
+	assert.Equal(t, 75.0, costs[0].TotalUSD)
+	assert.Equal(t, 75.0, costs[0].SharePercent)
+	assert.Equal(t, 25.0, costs[1].SharePercent)
+
+	data["days"] = 2
+	renderer, err := render.New(nil)
+	require.NoError(t, err)
+	payload, err := json.Marshal(data)
+	require.NoError(t, err)
+	summary, ok := renderer.RenderJSON(orgCostDailyURI, payload, nil)
+	require.True(t, ok)
+	assert.Equal(t, "$100.00 across 2 accounts over 2 days, led by prod", summary)
+}
+
+func TestOrgRollupsNeedAccounts(t *testing.T) {
+	h := NewResourceHandler(&config.Config{}, nil)
+
+	_, err := h.ReadResource(context.Background(), orgInventoryURI)
+	assert.ErrorIs(t, err, accounts.ErrNotConfigured)
+
+	_, err = h.ReadResource(context.Background(), orgCostDailyURI+"?days=0")
+	assert.ErrorContains(t, err, "invalid days", "parameters are checked first")
+}
@@ -0,0 +1,135 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// resourceRequest is a resource read matched to a route
+type resourceRequest struct {
+	// URI is the URI as requested
+	URI string
+	// Template is the URI template of the matched route, which also names the
+	// summary template of the resource
+	Template string
+	// Params are the values of the template's variables, percent-decoded
+	Params map[string]string
+	// Query is the URI's query, whether or not the template declares it
+	Query url.Values
+}
+
+// Param returns the value of a template variable, empty when it is not set
+func (r resourceRequest) Param(name string) string {
+	return r.Params[name]
+}
+
+// resourceRouteHandler reads the resource of a matched route
+type resourceRouteHandler func(ctx context.Context, req resourceRequest) (*mcp.ReadResourceResult, error)
+
+// resourceRoute is a URI template and the handler of the URIs it matches
+type resourceRoute struct {
+	template string
+	pattern  *regexp.Regexp
+	names    []string
+	handler  resourceRouteHandler
+}
+
+// resourceRouter dispatches resource reads to the handler of the first route
+// whose URI template matches, so services add their resources by registering
+// routes. Templates use the RFC 6570 forms of the registered resource
+// templates: {name} matches one path segment, {+name} any text including
+// slashes, and {?a,b} declares query parameters, which are parsed for every
+// route. Routes are tried in the order they were added, so a more specific
+// template must be added before a more general one sharing its prefix.
+type resourceRouter struct {
+	routes []resourceRoute
+}
+
+// templateExpression matches the variable expressions of a URI template
+var templateExpression = regexp.MustCompile(`\{([+?]?)([A-Za-z0-9_,]+)\}`)
+
+// Handle adds a route for the URIs matching template
+func (r *resourceRouter) Handle(template string, handler resourceRouteHandler) {
+	var pattern strings.Builder
+	var names []string
+	pattern.WriteString("^")
+	last := 0
+	for _, match := range templateExpression.FindAllStringSubmatchIndex(template, -1) {
+		pattern.WriteString(regexp.QuoteMeta(template[last:match[0]]))
+		last = match[1]
+
+		operator, name := template[match[2]:match[3]], template[match[4]:match[5]]
+		switch operator {
+		case "?":
+			// Query parameters are parsed from every URI, not matched
+		case "+":
+			pattern.WriteString("(.*)")
+			names = append(names, name)
+		default:
+			pattern.WriteString("([^/]*)")
+			names = append(names, name)
+		}
+	}
+	pattern.WriteString(regexp.QuoteMeta(template[last:]))
+	pattern.WriteString("$")
+
+	r.routes = append(r.routes, resourceRoute{
+		template: template,
+		pattern:  regexp.MustCompile(pattern.String()),
+		names:    names,
+		handler:  handler,
+	})
+}
+
+// Match returns the handler and request of the first route matching uri
+func (r *resourceRouter) Match(uri string) (resourceRouteHandler, resourceRequest, bool) {
+	path, rawQuery, _ := strings.Cut(uri, "?")
+	for _, route := range r.routes {
+		values := route.pattern.FindStringSubmatch(path)
+		if values == nil {
+			continue
+		}
+
+		params := make(map[string]string, len(route.names))
+		for i, name := range route.names {
+			value := values[i+1]
+			if unescaped, err := url.PathUnescape(value); err == nil {
+				value = unescaped
+			}
+			params[name] = value
+		}
+		query, _ := url.ParseQuery(rawQuery)
+
+		return route.handler, resourceRequest{URI: uri, Template: route.template, Params: params, Query: query}, true
+	}
+	return nil, resourceRequest{}, false
+}
+
+// Read reads the resource of the first route matching uri
+func (r *resourceRouter) Read(ctx context.Context, uri string) (*mcp.ReadResourceResult, resourceRequest, error) {
+	handler, req, ok := r.Match(uri)
+	if !ok {
+		return nil, req, fmt.Errorf("unknown resource URI: %s", uri)
+	}
+	result, err := handler(ctx, req)
+	return result, req, err
+}
+
+// static adapts a handler of a resource without parameters to a route
+func static(read func(ctx context.Context) (*mcp.ReadResourceResult, error)) resourceRouteHandler {
+	return func(ctx context.Context, _ resourceRequest) (*mcp.ReadResourceResult, error) {
+		return read(ctx)
+	}
+}
+
+// byURI adapts a handler that parses the requested URI itself to a route
+func byURI(read func(ctx context.Context, uri string) (*mcp.ReadResourceResult, error)) resourceRouteHandler {
+	return func(ctx context.Context, req resourceRequest) (*mcp.ReadResourceResult, error) {
+		return read(ctx, req.URI)
+	}
+}
@@ -0,0 +1,58 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	"aws-mcp-server/internal/config"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResourceRouterMatch(t *testing.T) {
+	read := func(ctx context.Context, req resourceRequest) (*mcp.ReadResourceResult, error) { return nil, nil }
+	r := &resourceRouter{}
+	r.Handle("aws://ecr/repositories/{+name}/images/{reference}", read)
+	r.Handle("aws://ecr/repositories/{+name}", read)
+	r.Handle("aws://sqs/queues/{queue}", read)
+	r.Handle("aws://ssm/instances/{instanceId}/inventory{?name}", read)
+	r.Handle("prom://query{+params}", read)
+
+	_, req, ok := r.Match("aws://ecr/repositories/team/api/images/sha256%3Aabc")
+	require.True(t, ok)
+	assert.Equal(t, "aws://ecr/repositories/{+name}/images/{reference}", req.Template)
+	assert.Equal(t, "team/api", req.Param("name"))
+	assert.Equal(t, "sha256:abc", req.Param("reference"), "values are percent-decoded")
+
+	_, req, ok = r.Match("aws://ecr/repositories/team/api")
+	require.True(t, ok)
+	assert.Equal(t, "aws://ecr/repositories/{+name}", req.Template)
+
+	_, req, ok = r.Match("aws://sqs/queues/orders%20dlq")
+	require.True(t, ok)
+	assert.Equal(t, "orders dlq", req.Param("queue"))
+	_, _, ok = r.Match("aws://sqs/queues/orders/extra")
+	assert.False(t, ok, "a simple variable matches one segment")
+
+	_, req, ok = r.Match("aws://ssm/instances/i-1/inventory?name=nginx")
+	require.True(t, ok)
+	assert.Equal(t, "i-1", req.Param("instanceId"))
+	assert.Equal(t, "nginx", req.Query.Get("name"))
+
+	_, req, ok = r.Match("prom://query?expr=up&step=1m")
+	require.True(t, ok)
+	assert.Equal(t, "up", req.Query.Get("expr"))
+	assert.Equal(t, "prom://query?expr=up&step=1m", req.URI)
+
+	_, _, ok = r.Match("aws://sns/topics")
+	assert.False(t, ok)
+}
+
+func TestReadResourceUnknownURI(t *testing.T) {
+	h := NewResourceHandler(&config.Config{}, nil)
+
+	_, err := h.ReadResource(context.Background(), "aws://unknown/things")
+	assert.ErrorContains(t, err, "unknown resource URI")
+}
@@ -0,0 +1,174 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"slices"
+	"time"
+
+	"aws-mcp-server/pkg/audit"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// toolFault is a tool call that panicked or ran past its timeout. It is
+// answered with an error response rather than failing the request.
+type toolFault struct {
+	message string
+}
+
+func (f *toolFault) Error() string {
+	return f.message
+}
+
+// toolOutcome is what a tool handler returned
+type toolOutcome struct {
+	result *mcp.CallToolResult
+	err    error
+}
+
+// toolTimeoutMargin is added to a tool's own limits, so the tool can report
+// on running into them before its context is cancelled
+const toolTimeoutMargin = time.Minute
+
+// timeoutGrace is how long a tool that ran past its timeout is given to
+// return what it has done so far after its context is cancelled
+const timeoutGrace = 10 * time.Second
+
+// runTool runs a tool call on its own goroutine under the tool's timeout, so
+// a handler that panics or hangs cannot take the server down or block the
+// caller forever. A handler that overruns has its context cancelled; what it
+// returns within timeoutGrace is passed on with a warning, otherwise it is
+// abandoned and may still finish whatever AWS call it had in flight.
+func (h *ToolHandler) runTool(ctx context.Context, name string, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	timeout := h.toolTimeout(name, arguments)
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	done := make(chan toolOutcome, 1)
+	go func() {
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				h.logger.WithField("tool", name).WithField("panic", recovered).WithField("stack", string(debug.Stack())).Error("Tool handler panicked")
+				done <- toolOutcome{err: &toolFault{message: fmt.Sprintf("%s failed with an internal error: %v", name, recovered)}}
+			}
+		}()
+
+		result, err := h.callTool(ctx, name, arguments)
+		done <- toolOutcome{result: result, err: err}
+	}()
+
+	timedOut := func() bool {
+		return timeout > 0 && errors.Is(ctx.Err(), context.DeadlineExceeded)
+	}
+	timeoutMessage := fmt.Sprintf("%s timed out after %s; it may still complete, check the resource before retrying", name, timeout)
+	// A handler that gave up because its context expired failed on the
+	// timeout, unless it reports what it got done, e.g. the waves of a bulk
+	// stop, which is kept
+	onTimeout := func(outcome toolOutcome) (*mcp.CallToolResult, error) {
+		h.logger.WithField("tool", name).WithField("timeout", timeout).Error("Tool call timed out")
+		if outcome.err == nil && outcome.result != nil && isSuccess(outcome.result) {
+			return addWarnings(outcome.result, timeoutMessage), nil
+		}
+		return nil, &toolFault{message: timeoutMessage}
+	}
+
+	select {
+	case outcome := <-done:
+		if timedOut() {
+			return onTimeout(outcome)
+		}
+		return outcome.result, outcome.err
+	case <-ctx.Done():
+		if !timedOut() {
+			return nil, ctx.Err()
+		}
+		select {
+		case outcome := <-done:
+			return onTimeout(outcome)
+		case <-time.After(timeoutGrace):
+			return onTimeout(toolOutcome{})
+		}
+	}
+}
+
+// toolTimeout returns how long a call may run: mcp.tool_timeouts for the
+// tool, or else mcp.tool_timeout raised to what the call's own limits allow,
+// such as the timeout of a query or the waves of a bulk stop. Approving a
+// queued action runs it under the timeout of its tool. Zero is unbounded.
+func (h *ToolHandler) toolTimeout(name string, arguments map[string]interface{}) time.Duration {
+	if timeout, ok := h.config.MCP.ToolTimeouts[name]; ok {
+		return timeout
+	}
+	if name == "approve-action" {
+		if id, _ := arguments["requestId"].(string); id != "" {
+			if request, ok := h.approvals.Get(id); ok {
+				return h.toolTimeout(request.Tool, request.Arguments)
+			}
+		}
+	}
+
+	timeout := h.config.MCP.ToolTimeout
+	if limit := h.toolLimit(name, arguments); timeout > 0 && limit > 0 {
+		timeout = max(timeout, limit+toolTimeoutMargin)
+	}
+	return timeout
+}
+
+// toolLimit returns the longest a call may run by the limits its tool
+// enforces itself, or zero for tools without such limits. Arguments out of
+// range are rejected by the tool, so the longest valid value is assumed.
+func (h *ToolHandler) toolLimit(name string, arguments map[string]interface{}) time.Duration {
+	seconds := func(key string, fallback, limit time.Duration) time.Duration {
+		value, ok := arguments[key].(float64)
+		if !ok || value <= 0 {
+			return fallback
+		}
+		return min(time.Duration(value*float64(time.Second)), limit)
+	}
+
+	switch name {
+	case "run-athena-query":
+		return seconds("timeout", athenaParams(h.config.Athena).Timeout, maxAthenaTimeout)
+	case "query-cloudwatch-logs":
+		fallback := h.config.Logs.InsightsTimeout
+		if fallback <= 0 {
+			fallback = defaultInsightsTimeout
+		}
+		return seconds("timeout", fallback, maxInsightsTimeout)
+	case "verify-remediation":
+		fallback := h.config.Remediation.VerifyDuration
+		if fallback <= 0 {
+			fallback = defaultVerifyDuration
+		}
+		return seconds("durationSeconds", fallback, maxVerifyDuration)
+	case "bulk-stop-ec2-instances":
+		// Every instance may end up in a wave of its own
+		instances := len(slices.Compact(slices.Sorted(slices.Values(stringList(arguments["instanceIds"])))))
+		wave := seconds("waveTimeoutSeconds", defaultWaveTimeoutSeconds*time.Second, maxWaveTimeoutSeconds*time.Second)
+		stagger := seconds("staggerSeconds", defaultStaggerSeconds*time.Second, maxStaggerSeconds*time.Second)
+		return time.Duration(min(instances, maxBulkInstances)) * (wave + stagger)
+	}
+	return 0
+}
+
+// recordFault appends a panicked or timed out call to the audit log. Unlike
+// recordAudit it records every tool: a faulting handler is a bug to look into
+// and may have been halfway through a change.
+func (h *ToolHandler) recordFault(ctx context.Context, name string, arguments map[string]interface{}, fault *toolFault) {
+	err := h.audit.Record(audit.Entry{
+		Tool:      name,
+		Arguments: arguments,
+		Role:      h.callerRole(ctx),
+		Success:   false,
+		Message:   fault.Error(),
+	})
+	if err != nil {
+		h.logger.WithError(err).WithField("tool", name).Error("Failed to write audit entry")
+	}
+}
@@ -0,0 +1,148 @@
+package mcp
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"aws-mcp-server/internal/config"
+	"aws-mcp-server/internal/logging"
+	"aws-mcp-server/pkg/audit"
+	"aws-mcp-server/pkg/aws"
+	"aws-mcp-server/pkg/fake"
+
+	sdkaws "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPanickingToolReturnsErrorResponse(t *testing.T) {
+	// Without an AWS client the handler dereferences nil and panics
+	h := NewToolHandler(&config.Config{}, nil, logging.NewLogger("error", "text"))
+	auditLog, err := audit.Open("", 0)
+	require.NoError(t, err)
+	h.audit = auditLog
+
+	result, err := h.CallTool(context.Background(), "find-orphans", map[string]interface{}{})
+	require.NoError(t, err)
+	response := decodeToolResult(t, result)
+	assert.Equal(t, false, response["success"])
+	assert.Contains(t, response["error"], "find-orphans failed with an internal error")
+
+	// Faults are audited even for read-only tools
+	entries := auditLog.Between(time.Time{}, time.Now().Add(time.Minute))
+	require.Len(t, entries, 1)
+	assert.Equal(t, "find-orphans", entries[0].Tool)
+	assert.False(t, entries[0].Success)
+	assert.Contains(t, entries[0].Message, "internal error")
+
+	// The handler keeps serving calls after a panic
+	result, err = h.CallTool(context.Background(), "start-on-demand-backup", map[string]interface{}{})
+	require.NoError(t, err)
+	assert.Contains(t, decodeToolResult(t, result)["error"], "resourceArn is required")
+}
+
+func TestSlowToolTimesOut(t *testing.T) {
+	// An endpoint that never answers stands in for a hung AWS API
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	logger := logging.NewLogger("error", "text")
+	awsClient := aws.NewClientFromConfig(sdkaws.Config{
+		Region:       "us-east-1",
+		Credentials:  sdkaws.AnonymousCredentials{},
+		BaseEndpoint: sdkaws.String(server.URL),
+	}, logger)
+	h := NewToolHandler(&config.Config{MCP: config.MCPConfig{
+		ToolTimeout:  time.Minute,
+		ToolTimeouts: map[string]time.Duration{"create-ebs-snapshot": 50 * time.Millisecond},
+	}}, awsClient, logger)
+
+	start := time.Now()
+	result, err := h.CallTool(context.Background(), "create-ebs-snapshot", map[string]interface{}{"volumeId": "vol-1"})
+	require.NoError(t, err)
+	assert.Less(t, time.Since(start), 30*time.Second, "the per-tool timeout applies, not the default")
+
+	response := decodeToolResult(t, result)
+	assert.Equal(t, false, response["success"])
+	assert.Contains(t, response["error"], "create-ebs-snapshot timed out after 50ms")
+}
+
+func TestToolTimeoutFollowsToolLimits(t *testing.T) {
+	h := NewToolHandler(&config.Config{MCP: config.MCPConfig{
+		ToolTimeout:  10 * time.Minute,
+		ToolTimeouts: map[string]time.Duration{"encrypt-volume": 3 * time.Hour},
+	}}, nil, logging.NewLogger("error", "text"))
+
+	assert.Equal(t, 10*time.Minute, h.toolTimeout("list-ec2-instances", nil))
+	assert.Equal(t, 10*time.Minute, h.toolTimeout("run-athena-query", nil), "the default query timeout fits")
+	assert.Equal(t, 31*time.Minute, h.toolTimeout("run-athena-query", map[string]interface{}{"timeout": float64(7200)}))
+	assert.Equal(t, 16*time.Minute, h.toolTimeout("query-cloudwatch-logs", map[string]interface{}{"timeout": float64(900)}))
+	assert.Equal(t, 21*time.Minute, h.toolTimeout("verify-remediation", map[string]interface{}{"durationSeconds": float64(1200)}))
+
+	// Three instances may take three waves of 1800s with 600s between them
+	bulk := map[string]interface{}{"instanceIds": []interface{}{"i-1", "i-2", "i-3", "i-3"}, "waveTimeoutSeconds": float64(1800), "staggerSeconds": float64(600)}
+	assert.Equal(t, 2*time.Hour+time.Minute, h.toolTimeout("bulk-stop-ec2-instances", bulk))
+
+	// Approving a queued action runs it under the timeout of its tool
+	request, err := h.approvals.Enqueue("encrypt-volume", map[string]interface{}{"volumeId": "vol-1"}, []string{"change freeze"}, "operator")
+	require.NoError(t, err)
+	assert.Equal(t, 3*time.Hour, h.toolTimeout("approve-action", map[string]interface{}{"requestId": request.ID}))
+	assert.Equal(t, 10*time.Minute, h.toolTimeout("approve-action", map[string]interface{}{"requestId": "apr-unknown"}))
+}
+
+// hangingHook holds the EC2 calls naming instances until they are cancelled
+type hangingHook struct{}
+
+func (hangingHook) OnRequest(ctx context.Context, call aws.Call) (context.Context, error) {
+	if input, ok := call.Input.(*ec2.DescribeInstancesInput); ok && len(input.InstanceIds) > 0 {
+		<-ctx.Done()
+		return ctx, ctx.Err()
+	}
+	return ctx, nil
+}
+
+func (hangingHook) OnResponse(ctx context.Context, call aws.Call, output interface{}, duration time.Duration) {
+}
+
+func (hangingHook) OnError(ctx context.Context, call aws.Call, err error, duration time.Duration) {}
+
+func TestTimedOutToolKeepsPartialResults(t *testing.T) {
+	logger := logging.NewLogger("error", "text")
+	fleet := fake.New(config.FakeConfig{Seed: 3}, "us-east-1")
+	client := aws.NewClientFromConfig(fleet.Config(), logger)
+	h := NewToolHandler(&config.Config{MCP: config.MCPConfig{
+		ToolTimeouts: map[string]time.Duration{"bulk-stop-ec2-instances": 200 * time.Millisecond},
+	}}, client, logger)
+
+	instances, err := client.ListEC2Instances(context.Background())
+	require.NoError(t, err)
+	var ids []interface{}
+	for _, instance := range instances {
+		if instance.State == "running" {
+			ids = append(ids, instance.ID)
+		}
+	}
+	require.NotEmpty(t, ids)
+
+	// The instances are stopped, then the timeout hits while the bulk stop
+	// waits for them to reach the stopped state
+	client.AddHook(hangingHook{})
+	result, err := h.CallTool(context.Background(), "bulk-stop-ec2-instances", map[string]interface{}{"instanceIds": ids, "confirm": true})
+	require.NoError(t, err)
+	response := decodeToolResult(t, result)
+	require.Equal(t, true, response["success"], response["error"])
+	assert.EqualValues(t, len(ids), response["completed"], "the stopped instances are reported")
+	assert.Contains(t, response["aborted"], "health check failed")
+	assert.Contains(t, response["warnings"], "bulk-stop-ec2-instances timed out after 200ms; it may still complete, check the resource before retrying")
+}
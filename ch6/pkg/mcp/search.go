@@ -0,0 +1,345 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/url"
+	"slices"
+	"sort"
+	"strings"
+
+	"aws-mcp-server/internal/config"
+	"aws-mcp-server/pkg/aws"
+	"aws-mcp-server/pkg/cloud"
+	"aws-mcp-server/pkg/kb"
+	"aws-mcp-server/pkg/search"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const (
+	// defaultSearchResults is how many results the search tool returns by default
+	defaultSearchResults = 10
+	// maxSearchResults caps the limit argument of the search tool
+	maxSearchResults = 50
+)
+
+// Kinds of items the search tool finds
+const (
+	searchKindInstance     = "instance"
+	searchKindDatabase     = "database"
+	searchKindCache        = "cache"
+	searchKindQueue        = "queue"
+	searchKindTopic        = "topic"
+	searchKindLoadBalancer = "load-balancer"
+	searchKindECSService   = "ecs-service"
+	searchKindDoc          = "doc"
+)
+
+// searchKinds are the kinds accepted by the search tool's kinds argument
+var searchKinds = []string{
+	searchKindInstance, searchKindDatabase, searchKindCache, searchKindQueue,
+	searchKindTopic, searchKindLoadBalancer, searchKindECSService, searchKindDoc,
+}
+
+// newSearchIndex creates the index behind the search tool from the search
+// configuration, over the inventory of the providers and the knowledge base
+func newSearchIndex(cfg config.SearchConfig, awsClient *aws.Client, clouds *cloud.Registry, docs *kb.Index) (*search.Index, error) {
+	var embedder search.Embedder
+	switch cfg.Embedder {
+	case "", search.EmbedderLocal:
+		embedder = search.NewHashEmbedder(cfg.Dimensions)
+	case search.EmbedderBedrock:
+		if cfg.BedrockModel == "" {
+			return nil, fmt.Errorf("search.bedrock_model is required with the bedrock embedder")
+		}
+		embedder = search.NewTextEmbedder(search.EmbedderBedrock+":"+cfg.BedrockModel, func(ctx context.Context, text string) ([]float32, error) {
+			return awsClient.EmbedText(ctx, cfg.BedrockModel, text, cfg.Dimensions)
+		})
+	default:
+		return nil, fmt.Errorf("unknown search.embedder %q, use %s or %s", cfg.Embedder, search.EmbedderLocal, search.EmbedderBedrock)
+	}
+
+	return search.New(embedder, searchSources(awsClient, clouds, docs), cfg.RefreshInterval), nil
+}
+
+// searchSources lists what the search tool indexes. The text of an item
+// starts with words for its kind, so "payments database" prefers an RDS
+// instance over a queue of the same name.
+func searchSources(awsClient *aws.Client, clouds *cloud.Registry, docs *kb.Index) []search.Source {
+	var sources []search.Source
+
+	for _, provider := range clouds.Providers() {
+		sources = append(sources, search.Source{
+			Name: provider.Label() + " instances",
+			List: func(ctx context.Context) ([]search.Item, error) {
+				instances, err := provider.ListInstances(ctx)
+				if err != nil {
+					return nil, err
+				}
+				items := make([]search.Item, 0, len(instances))
+				for _, instance := range instances {
+					name := instance.Tags["Name"]
+					items = append(items, search.Item{
+						Kind: searchKindInstance,
+						ID:   instance.ID,
+						Name: name,
+						URI:  cloud.InstancesURI(provider) + "/" + instance.ID,
+						Text: searchText(provider.Label()+" instance server vm "+instance.Type, instance.ID, name, instance.Tags),
+					})
+				}
+				return items, nil
+			},
+		})
+	}
+
+	sources = append(sources,
+		search.Source{
+			Name: "RDS instances",
+			List: func(ctx context.Context) ([]search.Item, error) {
+				instances, err := awsClient.ListDBInstances(ctx)
+				if err != nil {
+					return nil, err
+				}
+				items := make([]search.Item, 0, len(instances))
+				for _, instance := range instances {
+					engine := instance.RDSInstance().Engine
+					items = append(items, search.Item{
+						Kind: searchKindDatabase,
+						ID:   instance.ID,
+						URI:  "aws://rds/instances",
+						Text: searchText("RDS database db "+engine, instance.ID, "", instance.Tags),
+					})
+				}
+				return items, nil
+			},
+		},
+		search.Source{
+			Name: "ElastiCache clusters",
+			List: func(ctx context.Context) ([]search.Item, error) {
+				clusters, err := awsClient.ListCacheClusters(ctx)
+				if err != nil {
+					return nil, err
+				}
+				items := make([]search.Item, 0, len(clusters))
+				for _, cluster := range clusters {
+					items = append(items, search.Item{
+						Kind: searchKindCache,
+						ID:   cluster.ID,
+						Name: cluster.ReplicationGroupID,
+						URI:  cacheClustersURI + "/" + cluster.ID,
+						Text: searchText("ElastiCache cache "+cluster.Engine, cluster.ID, cluster.ReplicationGroupID, nil),
+					})
+				}
+				return items, nil
+			},
+		},
+		search.Source{
+			Name: "SQS queues",
+			List: func(ctx context.Context) ([]search.Item, error) {
+				queues, err := awsClient.ListSQSQueues(ctx)
+				if err != nil {
+					return nil, err
+				}
+				items := make([]search.Item, 0, len(queues))
+				for _, queue := range queues {
+					items = append(items, search.Item{
+						Kind: searchKindQueue,
+						ID:   queue.Name,
+						URI:  sqsQueuesURI + "/" + url.PathEscape(queue.Name),
+						Text: searchText("SQS queue", queue.Name, "", nil),
+					})
+				}
+				return items, nil
+			},
+		},
+		search.Source{
+			Name: "SNS topics",
+			List: func(ctx context.Context) ([]search.Item, error) {
+				topics, err := awsClient.ListSNSTopics(ctx)
+				if err != nil {
+					return nil, err
+				}
+				items := make([]search.Item, 0, len(topics))
+				for _, topic := range topics {
+					items = append(items, search.Item{
+						Kind: searchKindTopic,
+						ID:   topic.Name,
+						Name: topic.DisplayName,
+						URI:  snsTopicsURI + "/" + url.PathEscape(topic.Name),
+						Text: searchText("SNS topic notifications", topic.Name, topic.DisplayName, nil),
+					})
+				}
+				return items, nil
+			},
+		},
+		search.Source{
+			Name: "load balancers",
+			List: func(ctx context.Context) ([]search.Item, error) {
+				loadBalancers, err := awsClient.ListLoadBalancers(ctx)
+				if err != nil {
+					return nil, err
+				}
+				items := make([]search.Item, 0, len(loadBalancers))
+				for _, lb := range loadBalancers {
+					items = append(items, search.Item{
+						Kind: searchKindLoadBalancer,
+						ID:   lb.Name,
+						URI:  loadBalancersURI,
+						Text: searchText("load balancer "+lb.Type+" "+lb.Scheme, lb.Name, lb.DNSName, nil),
+					})
+				}
+				return items, nil
+			},
+		},
+		search.Source{
+			Name: "ECS services",
+			List: func(ctx context.Context) ([]search.Item, error) {
+				clusters, err := awsClient.ListECSClusters(ctx)
+				if err != nil {
+					return nil, err
+				}
+				var items []search.Item
+				for _, cluster := range clusters {
+					services, err := awsClient.ListECSServices(ctx, cluster.Name)
+					if err != nil {
+						return nil, err
+					}
+					for _, service := range services {
+						items = append(items, search.Item{
+							Kind: searchKindECSService,
+							ID:   service.Name,
+							Name: cluster.Name + "/" + service.Name,
+							URI:  ecsClustersURI + "/" + url.PathEscape(cluster.Name) + "/services",
+							Text: searchText("ECS service container "+service.LaunchType, service.Name, cluster.Name, nil),
+						})
+					}
+				}
+				return items, nil
+			},
+		},
+	)
+
+	if docs != nil {
+		sources = append(sources, search.Source{
+			Name: "knowledge base",
+			List: func(ctx context.Context) ([]search.Item, error) {
+				documents, failures := docs.Documents(ctx)
+				if len(documents) == 0 && len(failures) > 0 {
+					return nil, fmt.Errorf("no docs source could be read: %s", strings.Join(sortedValues(failures), "; "))
+				}
+				items := make([]search.Item, 0, len(documents))
+				for _, doc := range documents {
+					items = append(items, search.Item{
+						Kind: searchKindDoc,
+						ID:   doc.Path,
+						Name: doc.Title,
+						URI:  docsURI + "/" + doc.Path,
+						Text: "doc runbook " + doc.Title + " " + strings.Join(doc.Headings, " ") + " " + doc.Content,
+					})
+				}
+				return items, nil
+			},
+		})
+	}
+	return sources
+}
+
+// searchText is the text embedded for a resource: words for its kind, its
+// identifiers and its tags. Tag values are what people call resources, e.g.
+// Service=payments.
+func searchText(kind, id, name string, tags map[string]string) string {
+	parts := []string{kind, id}
+	if name != "" && name != id {
+		parts = append(parts, name)
+	}
+	keys := make([]string, 0, len(tags))
+	for key := range tags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if strings.HasPrefix(key, "aws:") {
+			continue
+		}
+		parts = append(parts, key+" "+tags[key])
+	}
+	return strings.Join(parts, " ")
+}
+
+// sortedValues returns the values of a map ordered by key
+func sortedValues(values map[string]string) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	sorted := make([]string, 0, len(keys))
+	for _, key := range keys {
+		sorted = append(sorted, key+": "+values[key])
+	}
+	return sorted
+}
+
+// searchResources finds resources and documents by what they are called
+// rather than their identifiers, e.g. "the payments database"
+func (h *ToolHandler) searchResources(ctx context.Context, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	if h.search == nil {
+		return h.createErrorResponse("search is not enabled, set search.enabled to index the inventory and knowledge base")
+	}
+
+	query, _ := arguments["query"].(string)
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return h.createErrorResponse("query is required")
+	}
+
+	kinds := stringList(arguments["kinds"])
+	for _, kind := range kinds {
+		if !slices.Contains(searchKinds, kind) {
+			return h.createErrorResponse(fmt.Sprintf("unknown kind %q, use %s", kind, strings.Join(searchKinds, ", ")))
+		}
+	}
+
+	limit := defaultSearchResults
+	if value, ok := arguments["limit"].(float64); ok && value > 0 {
+		limit = int(min(value, maxSearchResults))
+	}
+
+	results, failures, err := h.search.Search(ctx, query, kinds, limit)
+	if err != nil {
+		return h.createErrorResponse(fmt.Sprintf("search failed: %v", err))
+	}
+
+	items := make([]map[string]interface{}, 0, len(results))
+	for _, result := range results {
+		item := map[string]interface{}{
+			"kind":  result.Kind,
+			"id":    result.ID,
+			"uri":   result.URI,
+			"score": math.Round(result.Score*100) / 100,
+		}
+		if result.Name != "" {
+			item["name"] = result.Name
+		}
+		items = append(items, item)
+	}
+
+	data := map[string]interface{}{
+		"query":    query,
+		"embedder": h.search.Embedder(),
+		"results":  items,
+	}
+	if len(kinds) > 0 {
+		data["kinds"] = kinds
+	}
+	if len(failures) > 0 {
+		data["unavailable_sources"] = failures
+	}
+	if len(items) == 0 {
+		data["note"] = fmt.Sprintf("Nothing matches %q; try other words or read the inventory resources", query)
+	}
+
+	return h.createSuccessResponse(fmt.Sprintf("Found %d results for %q", len(items), query), data)
+}
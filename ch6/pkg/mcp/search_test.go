@@ -0,0 +1,62 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"aws-mcp-server/internal/config"
+	"aws-mcp-server/internal/logging"
+	"aws-mcp-server/pkg/search"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearchText(t *testing.T) {
+	text := searchText("RDS database db postgres", "db-7f3a", "", map[string]string{
+		"Service":                  "payments",
+		"Env":                      "prod",
+		"aws:cloudformation:stack": "core",
+	})
+	assert.Equal(t, "RDS database db postgres db-7f3a Env prod Service payments", text)
+}
+
+func TestSearchTool(t *testing.T) {
+	h := NewToolHandler(&config.Config{}, nil, logging.NewLogger("error", "text"))
+	ctx := context.Background()
+
+	result, err := h.CallTool(ctx, "search", map[string]interface{}{"query": "payments"})
+	require.NoError(t, err)
+	assert.Contains(t, decodeToolResult(t, result)["error"], "search.enabled")
+
+	h.search = search.New(search.NewHashEmbedder(0), []search.Source{
+		{Name: "RDS instances", List: func(context.Context) ([]search.Item, error) {
+			return []search.Item{{
+				Kind: searchKindDatabase, ID: "db-7f3a", URI: "aws://rds/instances",
+				Text: searchText("RDS database db postgres", "db-7f3a", "", map[string]string{"Service": "payments"}),
+			}}, nil
+		}},
+		{Name: "SQS queues", List: func(context.Context) ([]search.Item, error) {
+			return nil, errors.New("access denied")
+		}},
+	}, 0)
+
+	result, err = h.CallTool(ctx, "search", map[string]interface{}{"query": "payments database", "kinds": []interface{}{"database"}})
+	require.NoError(t, err)
+	data := decodeToolResult(t, result)
+	require.Nil(t, data["error"])
+	results := data["results"].([]interface{})
+	require.Len(t, results, 1)
+	assert.Equal(t, "db-7f3a", results[0].(map[string]interface{})["id"])
+	assert.Equal(t, "local", data["embedder"])
+	assert.Contains(t, data["unavailable_sources"], "SQS queues")
+
+	result, err = h.CallTool(ctx, "search", map[string]interface{}{"query": "payments", "kinds": []interface{}{"bucket"}})
+	require.NoError(t, err)
+	assert.Contains(t, decodeToolResult(t, result)["error"], "unknown kind")
+
+	result, err = h.CallTool(ctx, "search", map[string]interface{}{"query": " "})
+	require.NoError(t, err)
+	assert.Contains(t, decodeToolResult(t, result)["error"], "query is required")
+}
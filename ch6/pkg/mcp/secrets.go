@@ -0,0 +1,306 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"aws-mcp-server/pkg/types"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const (
+	// secretsURI lists the Secrets Manager secrets with their rotation status
+	secretsURI = "aws://secretsmanager/secrets"
+	// secretTemplate is the URI template of one secret, by its name
+	secretTemplate = "aws://secretsmanager/secrets/{+name}"
+	// rotationGrace is how late a scheduled rotation may run before it is overdue
+	rotationGrace = 24 * time.Hour
+)
+
+// Rotation statuses of a secret, in the order they need attention
+const (
+	rotationFailing  = "failing"
+	rotationOverdue  = "overdue"
+	rotationStale    = "stale"
+	rotationDisabled = "disabled"
+	rotationDeleting = "scheduled-for-deletion"
+	rotationOK       = "ok"
+)
+
+// rotationSeverity orders secrets so those needing attention come first
+var rotationSeverity = map[string]int{
+	rotationFailing:  0,
+	rotationOverdue:  1,
+	rotationStale:    2,
+	rotationDisabled: 3,
+	rotationDeleting: 4,
+	rotationOK:       5,
+}
+
+// secretRotationStatus tells whether a secret's rotation is healthy and why
+// not. A version left in AWSPENDING means the last rotation did not finish.
+func secretRotationStatus(secret types.Secret, maxAgeDays int, now time.Time) (string, string) {
+	if secret.DeletedAt != nil {
+		return rotationDeleting, "the secret is scheduled for deletion"
+	}
+	if pending := secret.PendingVersion(); pending != "" {
+		return rotationFailing, fmt.Sprintf("version %s is still AWSPENDING, the last rotation did not finish", pending)
+	}
+
+	if !secret.RotationEnabled {
+		changed := secret.CreatedAt
+		if secret.LastChanged != nil {
+			changed = *secret.LastChanged
+		}
+		if age := daysSince(changed, now); age > maxAgeDays {
+			return rotationStale, fmt.Sprintf("rotation is disabled and the value has not changed for %d days", age)
+		}
+		return rotationDisabled, "rotation is disabled"
+	}
+
+	if secret.NextRotation != nil && now.After(secret.NextRotation.Add(rotationGrace)) {
+		return rotationOverdue, fmt.Sprintf("rotation was due %d days ago", daysSince(*secret.NextRotation, now))
+	}
+	if secret.RotationDays > 0 {
+		rotated := secret.CreatedAt
+		if secret.LastRotated != nil {
+			rotated = *secret.LastRotated
+		}
+		due := rotated.AddDate(0, 0, int(secret.RotationDays))
+		if now.After(due.Add(rotationGrace)) {
+			return rotationOverdue, fmt.Sprintf("last rotated %d days ago, rotation is every %d days", daysSince(rotated, now), secret.RotationDays)
+		}
+	}
+	return rotationOK, ""
+}
+
+// readSecrets lists the secrets with their rotation status, those needing
+// attention first. Secret values are never read.
+func (h *ResourceHandler) readSecrets(ctx context.Context) (*mcp.ReadResourceResult, error) {
+	secrets, err := h.awsClient.ListSecrets(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list secrets: %w", err)
+	}
+
+	jsonData, err := json.MarshalIndent(h.formatSecrets(secrets, time.Now()), "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal secrets data: %w", err)
+	}
+
+	return &mcp.ReadResourceResult{
+		Contents: []mcp.ResourceContents{
+			&mcp.TextResourceContents{
+				URI:      secretsURI,
+				MIMEType: "application/json",
+				Text:     string(jsonData),
+			},
+		},
+	}, nil
+}
+
+// formatSecrets orders the secrets by rotation status and counts them per status
+func (h *ResourceHandler) formatSecrets(secrets []types.Secret, now time.Time) map[string]interface{} {
+	maxAge := thresholdDays(h.config.Security.SecretMaxAgeDays)
+
+	items := make([]map[string]interface{}, 0, len(secrets))
+	byStatus := make(map[string]int)
+	for _, secret := range secrets {
+		item := h.formatSecret(secret, maxAge, now)
+		byStatus[item["rotation_status"].(string)]++
+		items = append(items, item)
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		si, sj := rotationSeverity[items[i]["rotation_status"].(string)], rotationSeverity[items[j]["rotation_status"].(string)]
+		if si != sj {
+			return si < sj
+		}
+		return items[i]["name"].(string) < items[j]["name"].(string)
+	})
+
+	return map[string]interface{}{
+		"total":               len(secrets),
+		"by_status":           byStatus,
+		"secret_max_age_days": maxAge,
+		"secrets":             items,
+		"remediation": map[string]string{
+			rotationFailing: "Check the rotation function's CloudWatch logs for the error, fix it, then retry with rotate-secret",
+			rotationOverdue: "Use rotate-secret to rotate now, and check why the schedule did not run",
+			rotationStale:   "Enable rotation for the secret or rotate its value manually",
+		},
+	}
+}
+
+// formatSecret describes a secret's metadata and rotation status
+func (h *ResourceHandler) formatSecret(secret types.Secret, maxAgeDays int, now time.Time) map[string]interface{} {
+	status, detail := secretRotationStatus(secret, maxAgeDays, now)
+	item := map[string]interface{}{
+		"name":             secret.Name,
+		"uri":              secretsURI + "/" + secret.Name,
+		"rotation_status":  status,
+		"rotation_enabled": secret.RotationEnabled,
+		"created":          h.times.Format(secret.CreatedAt),
+	}
+	if detail != "" {
+		item["detail"] = detail
+	}
+	if secret.Description != "" {
+		item["description"] = secret.Description
+	}
+	if secret.RotationDays > 0 {
+		item["rotation_days"] = secret.RotationDays
+	}
+	if secret.RotationSchedule != "" {
+		item["rotation_schedule"] = secret.RotationSchedule
+	}
+	if secret.RotationLambdaARN != "" {
+		item["rotation_function"] = secret.RotationLambdaARN
+	}
+	if secret.OwningService != "" {
+		item["owning_service"] = secret.OwningService
+	}
+	for key, value := range map[string]*time.Time{
+		"last_rotated":  secret.LastRotated,
+		"next_rotation": secret.NextRotation,
+		"last_changed":  secret.LastChanged,
+		"last_accessed": secret.LastAccessed,
+	} {
+		if value != nil {
+			item[key] = h.times.Format(*value)
+		}
+	}
+	return item
+}
+
+// readSecret returns the metadata, rotation status and version stages of one
+// secret by the name in the URI
+func (h *ResourceHandler) readSecret(ctx context.Context, uri string) (*mcp.ReadResourceResult, error) {
+	name, _ := strings.CutPrefix(uri, secretsURI+"/")
+	if unescaped, err := url.PathUnescape(name); err == nil {
+		name = unescaped
+	}
+	if name == "" {
+		return nil, fmt.Errorf("invalid secret URI %s, use %s", uri, secretTemplate)
+	}
+
+	secret, err := h.awsClient.GetSecret(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	data := h.formatSecret(*secret, thresholdDays(h.config.Security.SecretMaxAgeDays), time.Now())
+	data["arn"] = secret.ARN
+	data["kms_key"] = secret.KMSKeyID
+	if secret.KMSKeyID == "" {
+		data["kms_key"] = "aws/secretsmanager"
+	}
+	if len(secret.Tags) > 0 {
+		data["tags"] = secret.Tags
+	}
+	data["versions"] = secretVersions(*secret)
+
+	jsonData, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal secret data: %w", err)
+	}
+
+	return &mcp.ReadResourceResult{
+		Contents: []mcp.ResourceContents{
+			&mcp.TextResourceContents{
+				URI:      uri,
+				MIMEType: "application/json",
+				Text:     string(jsonData),
+			},
+		},
+	}, nil
+}
+
+// secretVersions lists the versions of a secret with their staging labels,
+// the current version first
+func secretVersions(secret types.Secret) []map[string]interface{} {
+	rank := func(stages []string) int {
+		for i, stage := range []string{"AWSCURRENT", "AWSPENDING", "AWSPREVIOUS"} {
+			if slices.Contains(stages, stage) {
+				return i
+			}
+		}
+		return 3
+	}
+
+	versions := make([]map[string]interface{}, 0, len(secret.VersionStages))
+	for id, stages := range secret.VersionStages {
+		versions = append(versions, map[string]interface{}{"version_id": id, "stages": stages})
+	}
+	sort.Slice(versions, func(i, j int) bool {
+		ri, rj := rank(versions[i]["stages"].([]string)), rank(versions[j]["stages"].([]string))
+		if ri != rj {
+			return ri < rj
+		}
+		return versions[i]["version_id"].(string) < versions[j]["version_id"].(string)
+	})
+	return versions
+}
+
+// rotateSecret starts an immediate rotation of a secret with its configured
+// rotation function. Without confirm=true it only returns the rotation plan.
+func (h *ToolHandler) rotateSecret(ctx context.Context, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	secretID, _ := arguments["secretId"].(string)
+	if secretID == "" {
+		return h.createErrorResponse("secretId is required")
+	}
+
+	secret, err := h.awsClient.GetSecret(ctx, secretID)
+	if err != nil {
+		return h.createErrorResponse(fmt.Sprintf("failed to get secret: %v", err))
+	}
+	if secret.DeletedAt != nil {
+		return h.createErrorResponse(fmt.Sprintf("secret %s is scheduled for deletion, restore it before rotating", secret.Name))
+	}
+	if !secret.RotationConfigured() {
+		return h.createErrorResponse(fmt.Sprintf("secret %s has no rotation function, configure rotation before rotating it", secret.Name))
+	}
+
+	if !isConfirmed(arguments) {
+		status, detail := secretRotationStatus(*secret, thresholdDays(h.config.Security.SecretMaxAgeDays), time.Now())
+		plan := map[string]interface{}{
+			"secretId":        secret.Name,
+			"rotation_status": status,
+		}
+		if detail != "" {
+			plan["detail"] = detail
+		}
+		if secret.RotationLambdaARN != "" {
+			plan["rotation_function"] = secret.RotationLambdaARN
+		}
+		if secret.LastRotated != nil {
+			plan["last_rotated"] = h.times.Format(*secret.LastRotated)
+		}
+
+		warnings := []string{
+			"Applications that cache the secret keep using the previous value until they read it again",
+			"The previous value stays valid as AWSPREVIOUS only if the rotation function keeps it; single-user rotation invalidates it",
+		}
+		if pending := secret.PendingVersion(); pending != "" {
+			warnings = append(warnings, fmt.Sprintf("Version %s is AWSPENDING from an unfinished rotation; rotating retries it, so fix the rotation function first if it failed", pending))
+		}
+
+		return h.createConfirmationResponse("rotate-secret", plan, warnings)
+	}
+
+	versionID, err := h.awsClient.RotateSecret(ctx, secret.ARN)
+	if err != nil {
+		return h.createErrorResponse(fmt.Sprintf("failed to rotate secret: %v", err))
+	}
+
+	return h.createSuccessResponse("Secret rotation started successfully", map[string]interface{}{
+		"secretId":  secret.Name,
+		"versionId": versionID,
+		"note":      fmt.Sprintf("Rotation runs asynchronously; read %s/%s to see when AWSCURRENT moves to the new version", secretsURI, secret.Name),
+	})
+}
@@ -0,0 +1,120 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"aws-mcp-server/internal/config"
+	"aws-mcp-server/internal/logging"
+	"aws-mcp-server/pkg/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSecretRotationStatus(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	daysAgo := func(days int) *time.Time {
+		at := now.AddDate(0, 0, -days)
+		return &at
+	}
+
+	tests := []struct {
+		name   string
+		secret types.Secret
+		status string
+		detail string
+	}{
+		{
+			name:   "rotated on schedule",
+			secret: types.Secret{RotationEnabled: true, RotationDays: 30, CreatedAt: *daysAgo(400), LastRotated: daysAgo(10)},
+			status: rotationOK,
+		},
+		{
+			name: "unfinished rotation",
+			secret: types.Secret{RotationEnabled: true, RotationDays: 30, CreatedAt: *daysAgo(400), LastRotated: daysAgo(10),
+				VersionStages: map[string][]string{"v1": {"AWSCURRENT"}, "v2": {"AWSPENDING"}}},
+			status: rotationFailing,
+			detail: "version v2 is still AWSPENDING",
+		},
+		{
+			name:   "missed schedule",
+			secret: types.Secret{RotationEnabled: true, RotationDays: 30, CreatedAt: *daysAgo(400), LastRotated: daysAgo(45)},
+			status: rotationOverdue,
+			detail: "last rotated 45 days ago",
+		},
+		{
+			name:   "next rotation in the past",
+			secret: types.Secret{RotationEnabled: true, CreatedAt: *daysAgo(400), NextRotation: daysAgo(3)},
+			status: rotationOverdue,
+			detail: "rotation was due 3 days ago",
+		},
+		{
+			name:   "unrotated and unchanged",
+			secret: types.Secret{CreatedAt: *daysAgo(400), LastChanged: daysAgo(120)},
+			status: rotationStale,
+			detail: "has not changed for 120 days",
+		},
+		{
+			name:   "unrotated but recently changed",
+			secret: types.Secret{CreatedAt: *daysAgo(400), LastChanged: daysAgo(5)},
+			status: rotationDisabled,
+		},
+		{
+			name:   "deleted",
+			secret: types.Secret{CreatedAt: *daysAgo(400), DeletedAt: daysAgo(1)},
+			status: rotationDeleting,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, detail := secretRotationStatus(tt.secret, 90, now)
+			assert.Equal(t, tt.status, status)
+			assert.Contains(t, detail, tt.detail)
+		})
+	}
+}
+
+func TestFormatSecrets(t *testing.T) {
+	h := NewResourceHandler(&config.Config{}, nil)
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	recent := now.AddDate(0, 0, -2)
+
+	data := h.formatSecrets([]types.Secret{
+		{Name: "b-ok", RotationEnabled: true, RotationDays: 30, CreatedAt: recent, LastRotated: &recent},
+		{Name: "a-disabled", CreatedAt: recent},
+		{Name: "c-failing", RotationEnabled: true, CreatedAt: recent, VersionStages: map[string][]string{"v9": {"AWSPENDING"}}},
+	}, now)
+
+	assert.Equal(t, 3, data["total"])
+	assert.Equal(t, map[string]int{rotationOK: 1, rotationDisabled: 1, rotationFailing: 1}, data["by_status"])
+	secrets := data["secrets"].([]map[string]interface{})
+	require.Len(t, secrets, 3)
+	assert.Equal(t, "c-failing", secrets[0]["name"], "failing rotations come first")
+	assert.Equal(t, "a-disabled", secrets[1]["name"])
+	assert.Equal(t, "b-ok", secrets[2]["name"])
+	assert.Equal(t, "aws://secretsmanager/secrets/b-ok", secrets[2]["uri"])
+	assert.NotContains(t, secrets[2], "value")
+}
+
+func TestSecretVersions(t *testing.T) {
+	versions := secretVersions(types.Secret{VersionStages: map[string][]string{
+		"old":  {"AWSPREVIOUS"},
+		"next": {"AWSPENDING"},
+		"live": {"AWSCURRENT"},
+	}})
+	require.Len(t, versions, 3)
+	assert.Equal(t, "live", versions[0]["version_id"])
+	assert.Equal(t, "next", versions[1]["version_id"])
+	assert.Equal(t, "old", versions[2]["version_id"])
+}
+
+func TestRotateSecretRequiresSecretID(t *testing.T) {
+	h := NewToolHandler(&config.Config{}, nil, logging.NewLogger("error", "text"))
+
+	result, err := h.CallTool(context.Background(), "rotate-secret", map[string]interface{}{})
+	require.NoError(t, err)
+	assert.Equal(t, "secretId is required", decodeToolResult(t, result)["error"])
+}
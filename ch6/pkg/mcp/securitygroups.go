@@ -0,0 +1,388 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/netip"
+	"sort"
+	"strings"
+
+	"aws-mcp-server/pkg/aws"
+	"aws-mcp-server/pkg/types"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const (
+	// securityGroupsURI lists every security group with its inbound rules
+	securityGroupsURI = "aws://ec2/security-groups"
+	// securityGroupTemplate is the URI template of one security group
+	securityGroupTemplate = "aws://ec2/security-groups/{groupId}"
+	// maxRuleDescription is the EC2 limit on rule descriptions
+	maxRuleDescription = 255
+)
+
+// ingressProtocols maps the protocols the rule tools accept to EC2 protocol names
+var ingressProtocols = map[string]string{
+	"tcp":  "tcp",
+	"udp":  "udp",
+	"icmp": "icmp",
+	"all":  "-1",
+}
+
+// adminPorts are ports that are commonly attacked when open to the internet
+var adminPorts = map[int32]string{
+	22:    "SSH",
+	3389:  "RDP",
+	3306:  "MySQL",
+	5432:  "PostgreSQL",
+	1433:  "SQL Server",
+	6379:  "Redis",
+	9200:  "Elasticsearch",
+	27017: "MongoDB",
+}
+
+// readSecurityGroups returns all security groups with their inbound rules and
+// the instances that use them
+func (h *ResourceHandler) readSecurityGroups(ctx context.Context) (*mcp.ReadResourceResult, error) {
+	groups, err := h.awsClient.ListSecurityGroups(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list security groups: %w", err)
+	}
+
+	instances, err := h.awsClient.ListEC2Instances(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list EC2 instances: %w", err)
+	}
+
+	jsonData, err := json.MarshalIndent(formatSecurityGroups(groups, instances), "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal security groups data: %w", err)
+	}
+
+	return &mcp.ReadResourceResult{
+		Contents: []mcp.ResourceContents{
+			&mcp.TextResourceContents{
+				URI:      securityGroupsURI,
+				MIMEType: "application/json",
+				Text:     string(jsonData),
+			},
+		},
+	}, nil
+}
+
+// readSecurityGroup returns one security group with its inbound and outbound
+// rules and the instances that use it
+func (h *ResourceHandler) readSecurityGroup(ctx context.Context, uri string) (*mcp.ReadResourceResult, error) {
+	groupID := strings.TrimPrefix(uri, securityGroupsURI+"/")
+	if !strings.HasPrefix(groupID, "sg-") {
+		return nil, fmt.Errorf("invalid security group URI %s, use %s", uri, securityGroupTemplate)
+	}
+
+	group, err := h.awsClient.GetSecurityGroup(ctx, groupID)
+	if err != nil {
+		return nil, err
+	}
+
+	instances, err := h.awsClient.ListEC2Instances(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list EC2 instances: %w", err)
+	}
+
+	formatted := formatSecurityGroup(*group, groupInstances(instances)[group.ID])
+	egress := make([]map[string]interface{}, 0, len(group.Egress))
+	for _, rule := range group.Egress {
+		egress = append(egress, formatRule(rule))
+	}
+	formatted["egress"] = egress
+
+	jsonData, err := json.MarshalIndent(formatted, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal security group data: %w", err)
+	}
+
+	return &mcp.ReadResourceResult{
+		Contents: []mcp.ResourceContents{
+			&mcp.TextResourceContents{
+				URI:      uri,
+				MIMEType: "application/json",
+				Text:     string(jsonData),
+			},
+		},
+	}, nil
+}
+
+// formatSecurityGroups formats security groups for AI processing, groups open
+// to the internet first
+func formatSecurityGroups(groups []types.SecurityGroup, instances []types.CloudResource) map[string]interface{} {
+	byGroup := groupInstances(instances)
+
+	formatted := make([]map[string]interface{}, 0, len(groups))
+	worldOpen, unused := 0, 0
+	for _, group := range groups {
+		item := formatSecurityGroup(group, byGroup[group.ID])
+		if _, open := item["world_open_ports"]; open {
+			worldOpen++
+		}
+		if len(byGroup[group.ID]) == 0 {
+			unused++
+		}
+		formatted = append(formatted, item)
+	}
+
+	sort.SliceStable(formatted, func(i, j int) bool {
+		_, iOpen := formatted[i]["world_open_ports"]
+		_, jOpen := formatted[j]["world_open_ports"]
+		return iOpen && !jOpen
+	})
+
+	return map[string]interface{}{
+		"total_groups":      len(groups),
+		"world_open_groups": worldOpen,
+		"without_instances": unused,
+		"security_groups":   formatted,
+		"note":              "Groups without instances may still be used by load balancers, databases or other network interfaces",
+	}
+}
+
+// formatSecurityGroup formats one security group with its inbound rules
+func formatSecurityGroup(group types.SecurityGroup, instanceIDs []string) map[string]interface{} {
+	ingress := make([]map[string]interface{}, 0, len(group.Ingress))
+	for _, rule := range group.Ingress {
+		ingress = append(ingress, formatRule(rule))
+	}
+
+	item := map[string]interface{}{
+		"id":        group.ID,
+		"name":      group.Name,
+		"vpc_id":    group.VpcID,
+		"ingress":   ingress,
+		"instances": append([]string{}, instanceIDs...),
+	}
+	if group.Description != "" {
+		item["description"] = group.Description
+	}
+	if ports := worldOpenPorts(group); len(ports) > 0 {
+		item["world_open_ports"] = ports
+	}
+	return item
+}
+
+// formatRule renders a rule with its protocol, port range and sources
+func formatRule(rule types.SecurityGroupRule) map[string]interface{} {
+	sources := append(append([]string{}, rule.CIDRs...), rule.SourceGroups...)
+	return map[string]interface{}{
+		"protocol": formatProtocol(rule.Protocol),
+		"ports":    formatPortRange(rule),
+		"sources":  sources,
+	}
+}
+
+// groupInstances maps security group IDs to the instances that use them
+func groupInstances(instances []types.CloudResource) map[string][]string {
+	byGroup := make(map[string][]string)
+	for _, instance := range instances {
+		if instance.State == "terminated" {
+			continue
+		}
+		for _, groupID := range instance.EC2Instance().SecurityGroups {
+			byGroup[groupID] = append(byGroup[groupID], instance.ID)
+		}
+	}
+	return byGroup
+}
+
+// authorizeSecurityGroupIngress adds an inbound rule to a security group. Rules
+// open to the internet only return a plan until confirmed.
+func (h *ToolHandler) authorizeSecurityGroupIngress(ctx context.Context, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	groupID, permission, err := parseIngressArgs(arguments)
+	if err != nil {
+		return h.createErrorResponse(err.Error())
+	}
+
+	if warnings := worldOpenWarnings(permission); len(warnings) > 0 && !isConfirmed(arguments) {
+		plan := ingressData(groupID, permission)
+		return h.createConfirmationResponse("authorize-security-group-ingress", plan, warnings)
+	}
+
+	ruleIDs, err := h.awsClient.AuthorizeSecurityGroupIngress(ctx, groupID, permission)
+	if err != nil {
+		if strings.Contains(err.Error(), "InvalidPermission.Duplicate") {
+			return h.createErrorResponse(fmt.Sprintf("security group %s already has this rule", groupID))
+		}
+		return h.createErrorResponse(fmt.Sprintf("failed to authorize security group ingress: %v", err))
+	}
+
+	data := ingressData(groupID, permission)
+	data["securityGroupRuleIds"] = ruleIDs
+	data["action"] = "authorize"
+
+	return h.createSuccessResponse("Security group ingress authorized successfully", data)
+}
+
+// revokeSecurityGroupIngress removes an inbound rule from a security group
+func (h *ToolHandler) revokeSecurityGroupIngress(ctx context.Context, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	groupID, permission, err := parseIngressArgs(arguments)
+	if err != nil {
+		return h.createErrorResponse(err.Error())
+	}
+
+	ruleIDs, err := h.awsClient.RevokeSecurityGroupIngress(ctx, groupID, permission)
+	if errors.Is(err, aws.ErrRuleNotFound) || (err != nil && strings.Contains(err.Error(), "InvalidPermission.NotFound")) {
+		return h.createErrorResponse(fmt.Sprintf("security group %s has no rule for %s from %s; read %s/%s for its rules",
+			groupID, formatPermissionPorts(permission), ingressSource(permission), securityGroupsURI, groupID))
+	}
+	if err != nil {
+		return h.createErrorResponse(fmt.Sprintf("failed to revoke security group ingress: %v", err))
+	}
+
+	data := ingressData(groupID, permission)
+	data["securityGroupRuleIds"] = ruleIDs
+	data["action"] = "revoke"
+
+	return h.createSuccessResponse("Security group ingress revoked successfully", data)
+}
+
+// parseIngressArgs validates the group, protocol, ports and source of a rule.
+// A single port may be given as port; a bare IP address is a /32 or /128 block.
+func parseIngressArgs(arguments map[string]interface{}) (string, aws.IngressPermission, error) {
+	var permission aws.IngressPermission
+
+	groupID, _ := arguments["groupId"].(string)
+	if !strings.HasPrefix(groupID, "sg-") {
+		return "", permission, fmt.Errorf("groupId is required, e.g. sg-0123456789abcdef0")
+	}
+
+	protocol, _ := arguments["protocol"].(string)
+	if protocol == "" {
+		protocol = "tcp"
+	}
+	protocol = strings.ToLower(protocol)
+	if protocol == "-1" {
+		protocol = "all"
+	}
+	name, ok := ingressProtocols[protocol]
+	if !ok {
+		return "", permission, fmt.Errorf("invalid protocol %q, use tcp, udp, icmp or all", protocol)
+	}
+	permission.Protocol = name
+
+	if protocol == "tcp" || protocol == "udp" {
+		from, hasFrom := arguments["fromPort"].(float64)
+		if port, ok := arguments["port"].(float64); ok {
+			from, hasFrom = port, true
+		}
+		if !hasFrom {
+			return "", permission, fmt.Errorf("port or fromPort is required for %s rules", protocol)
+		}
+		to, ok := arguments["toPort"].(float64)
+		if !ok || arguments["port"] != nil {
+			to = from
+		}
+		if from < 0 || to > 65535 || from > to {
+			return "", permission, fmt.Errorf("invalid port range %g-%g, ports are 0-65535 with fromPort <= toPort", from, to)
+		}
+		permission.FromPort, permission.ToPort = int32(from), int32(to)
+	} else {
+		// All ICMP types and codes, or all ports
+		permission.FromPort, permission.ToPort = -1, -1
+	}
+
+	cidr, _ := arguments["cidr"].(string)
+	permission.SourceGroupID, _ = arguments["sourceGroupId"].(string)
+	switch {
+	case cidr != "" && permission.SourceGroupID != "":
+		return "", permission, fmt.Errorf("set either cidr or sourceGroupId, not both")
+	case permission.SourceGroupID != "":
+		if !strings.HasPrefix(permission.SourceGroupID, "sg-") {
+			return "", permission, fmt.Errorf("invalid sourceGroupId %q, use a security group ID such as sg-0123456789abcdef0", permission.SourceGroupID)
+		}
+	case cidr != "":
+		prefix, err := parseRuleCIDR(cidr)
+		if err != nil {
+			return "", permission, err
+		}
+		permission.CIDR = prefix.String()
+	default:
+		return "", permission, fmt.Errorf("cidr or sourceGroupId is required, e.g. cidr 203.0.113.0/24")
+	}
+
+	permission.Description, _ = arguments["description"].(string)
+	if len(permission.Description) > maxRuleDescription {
+		return "", permission, fmt.Errorf("description is longer than %d characters", maxRuleDescription)
+	}
+
+	return groupID, permission, nil
+}
+
+// parseRuleCIDR parses an IPv4 or IPv6 CIDR block. EC2 rejects blocks with
+// host bits set, so those are reported with the block they belong to.
+func parseRuleCIDR(value string) (netip.Prefix, error) {
+	if addr, err := netip.ParseAddr(value); err == nil {
+		return netip.PrefixFrom(addr, addr.BitLen()), nil
+	}
+
+	prefix, err := netip.ParsePrefix(value)
+	if err != nil {
+		return netip.Prefix{}, fmt.Errorf("invalid cidr %q, use a CIDR block such as 203.0.113.0/24 or an IP address", value)
+	}
+	if masked := prefix.Masked(); masked != prefix {
+		return netip.Prefix{}, fmt.Errorf("invalid cidr %q has host bits set, did you mean %s?", value, masked)
+	}
+	return prefix, nil
+}
+
+// worldOpenWarnings explains the risk of a rule that accepts traffic from
+// anywhere; other rules need no confirmation
+func worldOpenWarnings(permission aws.IngressPermission) []string {
+	if permission.CIDR == "" || !worldCIDRs[permission.CIDR] {
+		return nil
+	}
+
+	warnings := []string{
+		fmt.Sprintf("The rule opens %s to the whole internet (%s)", formatPermissionPorts(permission), permission.CIDR),
+	}
+	if permission.Protocol == "-1" {
+		warnings = append(warnings, "Every port and protocol becomes reachable; open only the ports the service needs")
+	}
+	for port, service := range adminPorts {
+		if permission.Protocol == "tcp" && permission.FromPort <= port && port <= permission.ToPort {
+			warnings = append(warnings, fmt.Sprintf("Port %d (%s) is a frequent attack target; prefer a VPN, bastion or Session Manager, or restrict the source", port, service))
+		}
+	}
+	sort.Strings(warnings[1:])
+	return warnings
+}
+
+// ingressData describes a rule in tool responses
+func ingressData(groupID string, permission aws.IngressPermission) map[string]interface{} {
+	data := map[string]interface{}{
+		"groupId":  groupID,
+		"protocol": formatProtocol(permission.Protocol),
+		"ports":    formatPermissionPorts(permission),
+		"source":   ingressSource(permission),
+	}
+	if permission.Description != "" {
+		data["description"] = permission.Description
+	}
+	return data
+}
+
+// formatPermissionPorts renders the protocol and port range of a rule, e.g. tcp/443
+func formatPermissionPorts(permission aws.IngressPermission) string {
+	rule := types.SecurityGroupRule{Protocol: permission.Protocol, FromPort: permission.FromPort, ToPort: permission.ToPort}
+	if permission.Protocol == "icmp" || rule.AllowsAllPorts() {
+		return formatProtocol(permission.Protocol)
+	}
+	return permission.Protocol + "/" + formatPortRange(rule)
+}
+
+// ingressSource returns the CIDR block or security group a rule accepts traffic from
+func ingressSource(permission aws.IngressPermission) string {
+	if permission.SourceGroupID != "" {
+		return permission.SourceGroupID
+	}
+	return permission.CIDR
+}
@@ -0,0 +1,91 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	"aws-mcp-server/internal/config"
+	"aws-mcp-server/internal/logging"
+	"aws-mcp-server/pkg/aws"
+	"aws-mcp-server/pkg/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatSecurityGroups(t *testing.T) {
+	groups := []types.SecurityGroup{
+		{ID: "sg-internal", Name: "internal", Ingress: []types.SecurityGroupRule{{Protocol: "tcp", FromPort: 5432, ToPort: 5432, SourceGroups: []string{"sg-web"}}}},
+		{ID: "sg-web", Name: "web", Description: "Public web servers", Ingress: []types.SecurityGroupRule{{Protocol: "tcp", FromPort: 443, ToPort: 443, CIDRs: []string{"0.0.0.0/0"}}}},
+	}
+	instances := []types.CloudResource{
+		{ID: "i-web", State: "running", Details: &types.EC2InstanceDetails{SecurityGroups: []string{"sg-web"}}},
+		{ID: "i-old", State: "terminated", Details: &types.EC2InstanceDetails{SecurityGroups: []string{"sg-internal"}}},
+	}
+
+	formatted := formatSecurityGroups(groups, instances)
+	assert.Equal(t, 1, formatted["world_open_groups"])
+	assert.Equal(t, 1, formatted["without_instances"])
+
+	items := formatted["security_groups"].([]map[string]interface{})
+	require.Len(t, items, 2)
+	assert.Equal(t, "sg-web", items[0]["id"])
+	assert.Equal(t, []string{"i-web"}, items[0]["instances"])
+	assert.Equal(t, "Public web servers", items[0]["description"])
+	assert.Equal(t, []map[string]interface{}{{"protocol": "tcp", "ports": "5432", "sources": []string{"sg-web"}}}, items[1]["ingress"])
+	assert.Empty(t, items[1]["instances"])
+}
+
+func TestParseIngressArgs(t *testing.T) {
+	groupID, permission, err := parseIngressArgs(map[string]interface{}{"groupId": "sg-1", "port": float64(443), "cidr": "203.0.113.7"})
+	require.NoError(t, err)
+	assert.Equal(t, "sg-1", groupID)
+	assert.Equal(t, aws.IngressPermission{Protocol: "tcp", FromPort: 443, ToPort: 443, CIDR: "203.0.113.7/32"}, permission)
+
+	_, permission, err = parseIngressArgs(map[string]interface{}{"groupId": "sg-1", "protocol": "all", "sourceGroupId": "sg-2"})
+	require.NoError(t, err)
+	assert.Equal(t, aws.IngressPermission{Protocol: "-1", FromPort: -1, ToPort: -1, SourceGroupID: "sg-2"}, permission)
+
+	_, permission, err = parseIngressArgs(map[string]interface{}{"groupId": "sg-1", "protocol": "udp", "fromPort": float64(3000), "toPort": float64(3100), "cidr": "2001:db8::/32"})
+	require.NoError(t, err)
+	assert.Equal(t, int32(3100), permission.ToPort)
+	assert.Equal(t, "2001:db8::/32", permission.CIDR)
+
+	invalid := map[string]map[string]interface{}{
+		"groupId is required":          {"port": float64(22), "cidr": "10.0.0.0/8"},
+		`invalid protocol "gre"`:       {"groupId": "sg-1", "protocol": "gre", "cidr": "10.0.0.0/8"},
+		"port or fromPort is required": {"groupId": "sg-1", "cidr": "10.0.0.0/8"},
+		"invalid port range":           {"groupId": "sg-1", "fromPort": float64(9000), "toPort": float64(8000), "cidr": "10.0.0.0/8"},
+		"did you mean 10.0.0.0/24?":    {"groupId": "sg-1", "port": float64(22), "cidr": "10.0.0.5/24"},
+		"not both":                     {"groupId": "sg-1", "port": float64(22), "cidr": "10.0.0.0/8", "sourceGroupId": "sg-2"},
+		"cidr or sourceGroupId":        {"groupId": "sg-1", "port": float64(22)},
+	}
+	for message, arguments := range invalid {
+		_, _, err := parseIngressArgs(arguments)
+		assert.ErrorContains(t, err, message)
+	}
+}
+
+func TestWorldOpenWarnings(t *testing.T) {
+	assert.Empty(t, worldOpenWarnings(aws.IngressPermission{Protocol: "tcp", FromPort: 22, ToPort: 22, CIDR: "10.0.0.0/8"}))
+
+	warnings := worldOpenWarnings(aws.IngressPermission{Protocol: "tcp", FromPort: 20, ToPort: 25, CIDR: "0.0.0.0/0"})
+	require.Len(t, warnings, 2)
+	assert.Contains(t, warnings[0], "tcp/20-25 to the whole internet")
+	assert.Contains(t, warnings[1], "Port 22 (SSH)")
+}
+
+func TestAuthorizeIngressRequiresConfirmationForInternet(t *testing.T) {
+	h := NewToolHandler(&config.Config{}, nil, logging.NewLogger("error", "text"))
+
+	result, err := h.CallTool(context.Background(), "authorize-security-group-ingress", map[string]interface{}{
+		"groupId": "sg-1",
+		"port":    float64(3389),
+		"cidr":    "0.0.0.0/0",
+	})
+	require.NoError(t, err)
+
+	response := decodeToolResult(t, result)
+	assert.Equal(t, true, response["confirmation_required"])
+	assert.Equal(t, "tcp/3389", response["plan"].(map[string]interface{})["ports"])
+}
@@ -2,14 +2,51 @@ package mcp
 
 import (
 	"bufio"
+	"bytes"
 	"context"
+	"crypto/rand"
 	"encoding/json"
 	"fmt"
+	"io"
+	"maps"
 	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
 
 	"aws-mcp-server/internal/config"
 	"aws-mcp-server/internal/logging"
+	"aws-mcp-server/internal/notify"
+	"aws-mcp-server/pkg/accounts"
+	"aws-mcp-server/pkg/alertgroup"
+	"aws-mcp-server/pkg/audit"
 	"aws-mcp-server/pkg/aws"
+	"aws-mcp-server/pkg/azure"
+	"aws-mcp-server/pkg/baseline"
+	"aws-mcp-server/pkg/change"
+	"aws-mcp-server/pkg/chatops"
+	"aws-mcp-server/pkg/cloud"
+	"aws-mcp-server/pkg/export"
+	"aws-mcp-server/pkg/gcp"
+	"aws-mcp-server/pkg/idempotency"
+	"aws-mcp-server/pkg/ignore"
+	"aws-mcp-server/pkg/inventory"
+	"aws-mcp-server/pkg/kb"
+	"aws-mcp-server/pkg/knowledge"
+	"aws-mcp-server/pkg/logs"
+	"aws-mcp-server/pkg/management"
+	"aws-mcp-server/pkg/metrics"
+	"aws-mcp-server/pkg/notes"
+	"aws-mcp-server/pkg/oncall"
+	"aws-mcp-server/pkg/ownership"
+	"aws-mcp-server/pkg/prometheus"
+	"aws-mcp-server/pkg/pseudonym"
+	"aws-mcp-server/pkg/render"
+	"aws-mcp-server/pkg/session"
+	"aws-mcp-server/pkg/slo"
+	"aws-mcp-server/pkg/summarize"
+	"aws-mcp-server/pkg/suppress"
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
@@ -22,25 +59,298 @@ type Server struct {
 	toolHandler     *ToolHandler
 	logger          *logging.Logger
 	mcpServer       *server.MCPServer
+	heartbeat       *notify.Heartbeat
+	idempotency     *idempotency.Cache
+	// instance tells the sessions of this run from those of earlier runs,
+	// which reused the same session IDs, in the idempotency cache
+	instance string
+	sessions *sessionRegistry
 }
 
 func NewServer(cfg *config.Config, awsClient *aws.Client, logger *logging.Logger) *Server {
 
+	// Settings overridden through the management API apply from the next
+	// request that reads them
+	if cfg.Overrides == nil {
+		cfg.Overrides = config.NewOverrides()
+	}
+
+	// Sessions on every transport are followed for the management API
+	sessions := newSessionRegistry()
+
+	s := &Server{
+		config:          cfg,
+		awsClient:       awsClient,
+		resourceHandler: NewResourceHandler(cfg, awsClient),
+		toolHandler:     NewToolHandler(cfg, awsClient, logger),
+		logger:          logger,
+		instance:        rand.Text(),
+		sessions:        sessions,
+	}
+
+	// Messages posted to the HTTP transport skip HandleMessage, so their
+	// retried write calls are replayed and their exchanges recorded from
+	// inside the MCP server
+	hooks := sessions.hooks()
+	s.recordPostedMessages(hooks)
+
 	// Create MCP server
-	mcpServer := server.NewMCPServer(
+	s.mcpServer = server.NewMCPServer(
 		cfg.MCP.ServerName,
 		cfg.MCP.Version,
 		server.WithResourceCapabilities(true, true),
 		server.WithToolCapabilities(true),
+		server.WithPromptCapabilities(false),
+		server.WithHooks(hooks),
+		server.WithToolHandlerMiddleware(s.replayPostedCalls),
 	)
 
-	s := &Server{
-		config:          cfg,
-		awsClient:       awsClient,
-		resourceHandler: NewResourceHandler(awsClient),
-		toolHandler:     NewToolHandler(awsClient, logger),
-		logger:          logger,
-		mcpServer:       mcpServer,
+	// Outbound AWS calls are capped, so bulk tools and fan-out reads queue
+	// instead of tripping account-wide throttling
+	if limiter := aws.NewConcurrencyLimiter(cfg.AWS.MaxConcurrency, cfg.AWS.ServiceConcurrency); limiter != nil {
+		awsClient.AddHook(limiter)
+	}
+
+	// Notifications fan out to the configured sinks
+	notifier, err := notify.New(cfg.Notify, awsClient.Config(), logger)
+	if err != nil {
+		logger.WithError(err).Error("Some notification sinks or routes are invalid and were skipped")
+	}
+	s.toolHandler.notifier = notifier
+
+	// Heartbeats let monitoring alert when the server itself stops running
+	heartbeat, err := notify.NewHeartbeat(cfg.Heartbeat, awsClient.Config(), cfg.MCP.ServerName, logger)
+	if err != nil {
+		logger.WithError(err).Error("Some heartbeat targets are invalid and were skipped")
+	}
+	s.heartbeat = heartbeat
+
+	// On-call lookups feed aws://oncall/current and mentions in notifications
+	roster, err := oncall.New(cfg.OnCall, logger)
+	if err != nil {
+		logger.WithError(err).Error("Invalid on-call configuration, on-call lookups are disabled")
+	} else if roster != nil {
+		s.resourceHandler.oncall = roster
+
+		severity, err := notify.ParseSeverity(cfg.OnCall.NotifySeverity)
+		if err != nil {
+			logger.WithError(err).Warn("Invalid oncall.notify_severity, mentioning on-call for critical events only")
+			severity = notify.SeverityCritical
+		}
+		notifier.SetOnCall(onCallResponders(roster), severity)
+	}
+
+	// Resource owners feed aws://ownership/{+resourceId} and route notifications
+	// about a resource to its team
+	owners, err := ownership.New(cfg.Ownership, cfg.Tagging.OwnerTags, awsClient.GetResourceTags)
+	if err != nil {
+		logger.WithError(err).Error("Invalid ownership configuration, resource owners are not resolved")
+	} else {
+		s.resourceHandler.owners = owners
+		notifier.SetOwnership(ownerTeams(owners))
+	}
+
+	// Changes made through the server are audited for postmortems
+	auditLog, err := audit.Open(cfg.Audit.Path, cfg.Audit.MaxEntries)
+	if err != nil {
+		logger.WithError(err).Error("Failed to load the audit log, earlier entries are unavailable")
+	}
+	s.toolHandler.audit = auditLog
+
+	// Retried write requests get the first response instead of running twice
+	replays, err := idempotency.Open(cfg.Idempotency.Path, cfg.Idempotency.Window)
+	if err != nil {
+		logger.WithError(err).Error("Failed to load the idempotency cache, earlier responses are not replayed")
+	}
+	s.idempotency = replays
+
+	// Blocked actions queued by tools are listed by the approvals resource
+	s.resourceHandler.approvals = s.toolHandler.approvals
+
+	// Silenced alerts are left out of or marked in the alarms resource
+	suppressions, err := suppress.Open(cfg.Suppressions.Path)
+	if err != nil {
+		logger.WithError(err).Error("Failed to load suppressions, earlier entries are unavailable")
+	}
+	s.toolHandler.suppressions = suppressions
+	s.resourceHandler.suppressions = suppressions
+
+	// Notes attached to resources are shown in their detail views
+	resourceNotes, err := notes.Open(cfg.Notes.Path)
+	if err != nil {
+		logger.WithError(err).Error("Failed to load notes, earlier notes are unavailable")
+	}
+	s.toolHandler.notes = resourceNotes
+	s.resourceHandler.notes = resourceNotes
+
+	// Ignored resources are left out of anomaly detection, orphan reports and digests
+	ignored, err := ignore.Open(cfg.Ignore.Path)
+	if err != nil {
+		logger.WithError(err).Error("Failed to load ignored resources, earlier entries are unavailable")
+	}
+	s.toolHandler.ignored = ignored
+	s.resourceHandler.ignored = ignored
+
+	// Demo mode pseudonymizes identifiers in every output
+	if cfg.Demo.Enabled {
+		demo, err := pseudonym.New(cfg.Demo.Seed)
+		if err != nil {
+			logger.WithError(err).Error("Failed to start demo mode, identifiers are not pseudonymized")
+		} else {
+			logger.Warn("Demo mode is on, identifiers in tool and resource outputs are pseudonymized")
+			s.toolHandler.demo = demo
+			s.resourceHandler.demo = demo
+		}
+	}
+
+	// Write tools take change tickets, checked against the ticket system; main
+	// validates the configuration
+	changes, err := change.New(cfg.Change, logger)
+	if err != nil {
+		logger.WithError(err).Error("Invalid change ticket configuration, change tickets are not validated")
+	}
+	s.toolHandler.changes = changes
+
+	// Related alarms are grouped in the alarms resource; main validates the rules
+	if cfg.Alerts.Grouping.Enabled {
+		grouper, err := alertgroup.NewGrouper(cfg.Alerts.Grouping.Rules)
+		if err != nil {
+			logger.WithError(err).Error("Invalid alert grouping rules, alarms are not grouped")
+		}
+		s.resourceHandler.grouper = grouper
+	}
+
+	// Metric baselines learned by the anomaly detector survive restarts
+	baselines, err := baseline.Open(cfg.Baselines.Path)
+	if err != nil {
+		logger.WithError(err).Error("Failed to load metric baselines, anomaly detection starts from scratch")
+	}
+	s.toolHandler.baselines = baselines
+
+	// Verified remediation outcomes are consulted before acting on a signal again
+	outcomes, err := knowledge.Open(cfg.Remediation.OutcomesPath, cfg.Remediation.MaxOutcomes)
+	if err != nil {
+		logger.WithError(err).Error("Failed to load remediation outcomes, earlier outcomes are unavailable")
+	}
+	s.toolHandler.outcomes = outcomes
+	s.resourceHandler.outcomes = outcomes
+
+	// Runbooks and architecture docs give the AI organizational context
+	docs, err := kb.New(cfg.Docs, awsClient)
+	if err != nil {
+		logger.WithError(err).Error("Invalid docs configuration, the knowledge base is disabled")
+	} else {
+		s.resourceHandler.docs = docs
+	}
+
+	// Heavyweight narratives are written by a Bedrock model and cached
+	if cfg.Summaries.Enabled {
+		summaries := summarize.New(cfg.Summaries, func(ctx context.Context, system, prompt string) (string, error) {
+			return awsClient.GenerateText(ctx, cfg.Summaries.Model, system, prompt, cfg.Summaries.MaxTokens)
+		})
+		s.toolHandler.summaries = summaries
+		s.resourceHandler.summaries = summaries
+	}
+
+	// The last instance lists answer instance resources until the first refresh
+	snapshot, err := inventory.Open(cfg.Inventory.Path, cfg.Inventory.MaxAge)
+	if err != nil {
+		logger.WithError(err).Error("Failed to load the inventory snapshot, the first instance reads go to the providers")
+	}
+	s.resourceHandler.inventory = snapshot
+
+	// Audit entries, inventory snapshots and anomalies are exported to S3
+	// for analysis in Athena
+	s.toolHandler.exporter = export.New(cfg.Export, cfg.MCP.ServerName, awsClient, auditLog, snapshot, logger)
+
+	// Instance resources and tools are served by the same cloud providers:
+	// AWS always, GCP and Azure when a project or subscription is configured
+	providers := []cloud.Provider{cloud.NewAWSProvider(awsClient)}
+	if cfg.GCP.Project != "" {
+		gcpClient, err := gcp.NewClient(cfg.GCP, logger)
+		if err != nil {
+			logger.WithError(err).Error("Failed to create the GCP client, Compute Engine instances are not served")
+		} else {
+			providers = append(providers, cloud.NewGCPProvider(gcpClient))
+		}
+	}
+	if cfg.Azure.SubscriptionID != "" {
+		azureClient, err := azure.NewClient(cfg.Azure, logger)
+		if err != nil {
+			logger.WithError(err).Error("Failed to create the Azure client, Azure VMs are not served")
+		} else {
+			providers = append(providers, cloud.NewAzureProvider(azureClient))
+			s.resourceHandler.azure = azureClient
+		}
+	}
+	s.resourceHandler.clouds = cloud.NewRegistry(providers...)
+	s.toolHandler.clouds = s.resourceHandler.clouds
+
+	// Other accounts of the organization are reached through assumed roles
+	directory, err := accounts.New(cfg.Organization, awsClient)
+	if err != nil {
+		logger.WithError(err).Error("Some organization accounts are invalid and were skipped")
+	}
+	s.resourceHandler.accounts = directory
+	s.toolHandler.accounts = directory
+
+	// Semantic search finds resources and docs by what people call them
+	if cfg.Search.Enabled {
+		index, err := newSearchIndex(cfg.Search, awsClient, s.toolHandler.clouds, s.resourceHandler.docs)
+		if err != nil {
+			logger.WithError(err).Error("Invalid search configuration, the search tool is disabled")
+		} else {
+			s.toolHandler.search = index
+		}
+	}
+
+	// Log tools read from the configured backend
+	logBackend, err := logs.New(cfg.Logs, awsClient, logger)
+	if err != nil {
+		logger.WithError(err).Error("Invalid log backend configuration, log tools are disabled")
+	} else {
+		s.toolHandler.logs = logBackend
+	}
+
+	// PromQL resources are only offered when a Prometheus endpoint is configured
+	if cfg.Prometheus.URL != "" {
+		client, err := prometheus.NewClient(cfg.Prometheus, logger)
+		if err != nil {
+			logger.WithError(err).Error("Invalid Prometheus configuration, prom:// resources are disabled")
+		}
+		s.resourceHandler.prometheus = client
+	}
+
+	// Metric resources read from a SaaS provider when one is configured
+	provider, err := metrics.New(cfg.Metrics, logger)
+	if err != nil {
+		logger.WithError(err).Error("Invalid metrics provider configuration, metrics:// resources are disabled")
+	} else {
+		s.resourceHandler.metrics = provider
+	}
+
+	// Error budgets gate disruptive tools on services close to breaching their SLO
+	evaluators := make(map[string]slo.Evaluator)
+	if s.resourceHandler.prometheus != nil {
+		evaluators[slo.SourcePrometheus] = promErrorRatio(s.resourceHandler.prometheus)
+	}
+	if s.resourceHandler.metrics != nil {
+		evaluators[slo.SourceMetrics] = metricsErrorRatio(s.resourceHandler.metrics)
+	}
+	policy, err := slo.NewPolicy(cfg.SLO, evaluators)
+	if err != nil {
+		logger.WithError(err).Error("Some SLO services are invalid and are not gated")
+	}
+	s.toolHandler.slo = policy
+
+	// Human-readable summaries are rendered from the JSON payloads
+	if cfg.Response.Summaries {
+		renderer, err := render.New(cfg.Response.Templates)
+		if err != nil {
+			logger.WithError(err).Warn("Some summary templates are invalid and were skipped")
+		}
+		s.toolHandler.renderer = renderer
+		s.resourceHandler.renderer = renderer
 	}
 
 	// Register resources
@@ -49,181 +359,1930 @@ func NewServer(cfg *config.Config, awsClient *aws.Client, logger *logging.Logger
 	// Register tools
 	s.registerTools()
 
+	// Register prompts
+	s.registerPrompts()
+
 	return s
 }
 
 // registerResources sets up all the MCP resources
 func (s *Server) registerResources() {
-	// Register EC2 instances list resource
+	// Register instance list and detail resources of every cloud provider,
+	// e.g. aws://ec2/instances and aws://ec2/instances/{instanceId}
+	for _, provider := range s.resourceHandler.clouds.Providers() {
+		uri := cloud.InstancesURI(provider)
+
+		s.mcpServer.AddResource(
+			mcp.NewResource(uri, provider.Label()+" Instances",
+				mcp.WithResourceDescription("List the "+provider.Label()+" instances in the region, response.page_size at a time; next_cursor reads the next page"),
+				mcp.WithMIMEType("application/json"),
+			),
+			s.readResource,
+		)
+
+		// Lists longer than response.page_size come in pages, read with the
+		// next_cursor of the previous page
+		s.mcpServer.AddResourceTemplate(
+			mcp.NewResourceTemplate(uri+instancePageQuery, provider.Label()+" Instances Page",
+				mcp.WithTemplateDescription("A page of the "+provider.Label()+" instances, e.g. "+uri+"?cursor=<next_cursor>; "+
+					"limit overrides the page size, up to 1000"),
+				mcp.WithTemplateMIMEType("application/json"),
+			),
+			s.readResource,
+		)
+
+		// The server matches URIs to templates, so the handler gets the full URI
+		s.mcpServer.AddResourceTemplate(
+			mcp.NewResourceTemplate(uri+"/{instanceId}", provider.Label()+" Instance Details",
+				mcp.WithTemplateDescription("Detailed information about a specific "+provider.Label()+" instance"),
+				mcp.WithTemplateMIMEType("application/json"),
+			),
+			s.readResource,
+		)
+	}
+
+	// Register the organization's accounts and the instance list of other
+	// organization accounts
+	if s.config.Organization.Enabled {
+		s.mcpServer.AddResource(
+			mcp.NewResource(organizationAccountsURI, "Organization Accounts",
+				mcp.WithResourceDescription("Member accounts of the AWS organization with their alias, status, tags and when they joined; "+
+					"accounts that are not active come first. Needs credentials of the management account or a delegated administrator."),
+				mcp.WithMIMEType("application/json"),
+			),
+			s.readResource,
+		)
+	}
+	if s.resourceHandler.accounts != nil {
+		s.mcpServer.AddResourceTemplate(
+			mcp.NewResourceTemplate(accountInstancesTemplate, "Organization Account EC2 Instances",
+				mcp.WithTemplateDescription("List the EC2 instances of another account of the organization by its alias or ID, through the account's assumed role"),
+				mcp.WithTemplateMIMEType("application/json"),
+			),
+			s.readResource,
+		)
+		s.mcpServer.AddResourceTemplate(
+			mcp.NewResourceTemplate(accountInstancesTemplate+instancePageQuery, "Organization Account EC2 Instances Page",
+				mcp.WithTemplateDescription("A page of the EC2 instances of another account of the organization, e.g. aws://prod/ec2/instances?cursor=<next_cursor>"),
+				mcp.WithTemplateMIMEType("application/json"),
+			),
+			s.readResource,
+		)
+
+		// Register the rollups across organization accounts
+		s.mcpServer.AddResource(
+			mcp.NewResource(orgInventoryURI, "Organization Inventory",
+				mcp.WithResourceDescription("EC2 instance counts by state and type across all organization accounts with a breakdown per account; "+
+					"accounts that could not be read are listed with the error"),
+				mcp.WithMIMEType("application/json"),
+			),
+			s.readResource,
+		)
+		s.mcpServer.AddResource(
+			mcp.NewResource(orgCostDailyURI, "Organization Daily Cost",
+				mcp.WithResourceDescription("Cost per day summed across all organization accounts over cost.explorer.days, with each account's total and share; "+
+					"accounts that could not be read are listed with the error"),
+				mcp.WithMIMEType("application/json"),
+			),
+			s.readResource,
+		)
+		s.mcpServer.AddResourceTemplate(
+			mcp.NewResourceTemplate(orgCostDailyTemplate, "Organization Cost over a Window",
+				mcp.WithTemplateDescription("Cost per day across all organization accounts over a chosen number of days, e.g. aws://org/cost/daily?days=7"),
+				mcp.WithTemplateMIMEType("application/json"),
+			),
+			s.readResource,
+		)
+	}
+
+	// Register EC2 grouping views, e.g. aws://ec2/instances/by-asg
+	for _, grouping := range instanceGroupings {
+		s.mcpServer.AddResource(
+			mcp.NewResource("aws://ec2/instances/"+instanceGroupingPrefix+grouping.name, grouping.title,
+				mcp.WithResourceDescription(grouping.description),
+				mcp.WithMIMEType("application/json"),
+			),
+			s.readResource,
+		)
+	}
+
+	// Register CloudWatch alarms resource and alarm history template
 	s.mcpServer.AddResource(
-		mcp.NewResource("aws://ec2/instances", "EC2 Instances",
-			mcp.WithResourceDescription("List all EC2 instances in the region"),
+		mcp.NewResource(alarmsURI, "CloudWatch Alarms",
+			mcp.WithResourceDescription("All CloudWatch metric and composite alarms grouped by state (ALARM, INSUFFICIENT_DATA, OK) with their conditions and reasons"),
 			mcp.WithMIMEType("application/json"),
 		),
-		func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
-			s.logger.Info("Received request for EC2 instances list")
+		s.readResource,
+	)
+	s.mcpServer.AddResourceTemplate(
+		mcp.NewResourceTemplate(alarmHistoryTemplate, "CloudWatch Alarm History",
+			mcp.WithTemplateDescription("State changes of one alarm over the last 30 days, newest first. URL-encode alarm names with spaces or slashes."),
+			mcp.WithTemplateMIMEType("application/json"),
+		),
+		s.readResource,
+	)
 
-			// Use our resource handler to get the instances
-			result, err := s.resourceHandler.ReadResource(ctx, "aws://ec2/instances")
-			if err != nil {
-				s.logger.WithError(err).Error("Failed to read EC2 instances resource")
-				return nil, err
-			}
+	// Register silenced alerts resource
+	s.mcpServer.AddResource(
+		mcp.NewResource(suppressionsURI, "Silenced Alerts",
+			mcp.WithResourceDescription("Acknowledged, snoozed and suppressed alarms with who silenced them, why and until when"),
+			mcp.WithMIMEType("application/json"),
+		),
+		s.readResource,
+	)
 
-			return result.Contents, nil
-		},
+	// Register ignored resources list
+	s.mcpServer.AddResource(
+		mcp.NewResource(ignoredResourcesURI, "Ignored Resources",
+			mcp.WithResourceDescription("Resources left out of anomaly detection, orphan reports and the daily digest, with who ignored them, why and until when"),
+			mcp.WithMIMEType("application/json"),
+		),
+		s.readResource,
 	)
 
-	// Register EC2 instance details resource template (supports dynamic instance IDs)
-	template := mcp.NewResourceTemplate(
-		"aws://ec2/instances/{instanceId}",
-		"EC2 Instance Details",
-		mcp.WithTemplateDescription("Detailed information about a specific EC2 instance"),
-		mcp.WithTemplateMIMEType("application/json"),
+	// Register resource notes
+	s.mcpServer.AddResource(
+		mcp.NewResource(notesURI, "Resource Notes",
+			mcp.WithResourceDescription("Notes operators and agents attached to resources with add-note, grouped by resource"),
+			mcp.WithMIMEType("application/json"),
+		),
+		s.readResource,
+	)
+	s.mcpServer.AddResourceTemplate(
+		mcp.NewResourceTemplate(notesTemplate, "Notes of a Resource",
+			mcp.WithTemplateDescription("Notes attached to one resource by the ID or ARN they were added with, oldest first"),
+			mcp.WithTemplateMIMEType("application/json"),
+		),
+		s.readResource,
 	)
 
-	s.mcpServer.AddResourceTemplate(template, func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
-		s.logger.WithField("uri", request.Params.URI).Info("Received read resource request for specific EC2 instance")
+	// Register remediation knowledge resource and signal template
+	s.mcpServer.AddResource(
+		mcp.NewResource(remediationsURI, "Remediation Knowledge",
+			mcp.WithResourceDescription("Remediations verified by verify-remediation in this environment, grouped by action with how often each resolved the signal"),
+			mcp.WithMIMEType("application/json"),
+		),
+		s.readResource,
+	)
+	s.mcpServer.AddResourceTemplate(
+		mcp.NewResourceTemplate(remediationsTemplate, "Remediations for a Signal",
+			mcp.WithTemplateDescription("What worked before for a kind of signal, e.g. aws://knowledge/remediations?signal=high-cpu. "+
+				"Signals are named like high-cpu, low-storage, high-5xx-errors, status-check-failed or unhealthy-targets; "+
+				"a single word such as cpu matches every signal containing it. Consult it before remediating."),
+			mcp.WithTemplateMIMEType("application/json"),
+		),
+		s.readResource,
+	)
 
-		// The server automatically matches URIs to templates, so we can use the full URI directly
-		result, err := s.resourceHandler.ReadResource(ctx, request.Params.URI)
-		if err != nil {
-			s.logger.WithError(err).WithField("uri", request.Params.URI).Error("Failed to read resource")
-			return nil, err
-		}
+	// Register daily digest resource when server-side summaries are enabled
+	if s.resourceHandler.summaries != nil {
+		s.mcpServer.AddResource(
+			mcp.NewResource(dailyDigestURI, "Daily Digest",
+				mcp.WithResourceDescription("A short narrative of the last 24 hours written by the server's Bedrock model: alarms firing, alarm state changes and actions waiting for approval"),
+				mcp.WithMIMEType("application/json"),
+			),
+			s.readResource,
+		)
+	}
 
-		return result.Contents, nil
-	})
-}
+	// Register knowledge base resources when docs sources are configured
+	if s.resourceHandler.docs != nil {
+		s.mcpServer.AddResource(
+			mcp.NewResource(docsURI, "Knowledge Base",
+				mcp.WithResourceDescription("Runbooks and architecture docs indexed from the configured directories and S3 locations, with their titles and headings"),
+				mcp.WithMIMEType("application/json"),
+			),
+			s.readResource,
+		)
+		s.mcpServer.AddResourceTemplate(
+			mcp.NewResourceTemplate(docsSearchTemplate, "Knowledge Base Search",
+				mcp.WithTemplateDescription("Documents matching keywords, best first with the passage that matched, e.g. kb://docs?query=rds+failover. "+
+					"Search it for the runbook of an alarm or how a service is built before remediating."),
+				mcp.WithTemplateMIMEType("application/json"),
+			),
+			s.readResource,
+		)
+		s.mcpServer.AddResourceTemplate(
+			mcp.NewResourceTemplate(docTemplate, "Knowledge Base Document",
+				mcp.WithTemplateDescription("The full markdown of one document, by the path listed in kb://docs"),
+				mcp.WithTemplateMIMEType("application/json"),
+			),
+			s.readResource,
+		)
+	}
 
-// registerTools sets up all the MCP tools
-// NOTE: In production, it's better to declare tools as an array of structs and use a loop
-// to register them. This approach reduces code duplication and makes it easier to manage
-// many tools. For this chapter, we write each tool registration separately to make the
-// code cleaner and easier to understand.
-//
-// Production approach would look like:
-//
-//	type ToolDefinition struct {
-//	    Name        string
-//	    Description string
-//	    Parameters  []mcp.ToolParameter
-//	    Handler     string
-//	}
-//
-//	tools := []ToolDefinition{
-//	    {Name: "create-ec2-instance", Description: "Create a new EC2 instance", ...},
-//	    {Name: "start-ec2-instance", Description: "Start a stopped EC2 instance", ...},
-//	    // ... more tools
-//	}
-//
-//	for _, tool := range tools {
-//	    s.mcpServer.AddTool(mcp.NewTool(tool.Name, tool.Parameters...), s.getHandlerFunc(tool.Handler))
-//	}
-func (s *Server) registerTools() {
-	// Register create EC2 instance tool
-	s.mcpServer.AddTool(
-		mcp.NewTool("create-ec2-instance",
-			mcp.WithDescription("Create a new EC2 instance"),
-			mcp.WithString("imageId", mcp.Description("AMI ID to use for the instance"), mcp.Required()),
-			mcp.WithString("instanceType", mcp.Description("EC2 instance type (e.g., t2.micro, t3.small)"), mcp.Required()),
-			mcp.WithString("keyName", mcp.Description("Name of the key pair to use for SSH access")),
-			mcp.WithString("securityGroupId", mcp.Description("Security group ID to assign to the instance")),
-			mcp.WithString("subnetId", mcp.Description("Subnet ID where the instance should be launched")),
-			mcp.WithString("name", mcp.Description("Name tag for the instance")),
+	// Register Azure resource groups resource
+	if s.resourceHandler.azure != nil {
+		s.mcpServer.AddResource(
+			mcp.NewResource(azureResourceGroupsURI, "Azure Resource Groups",
+				mcp.WithResourceDescription("Resource groups of the Azure subscription with their VMs counted by power state"),
+				mcp.WithMIMEType("application/json"),
+			),
+			s.readResource,
+		)
+	}
+
+	// Register ECS cluster resource and service and task templates
+	s.mcpServer.AddResource(
+		mcp.NewResource(ecsClustersURI, "ECS Clusters",
+			mcp.WithResourceDescription("ECS clusters with their active services, running and pending tasks and capacity providers"),
+			mcp.WithMIMEType("application/json"),
 		),
-		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-			arguments, ok := request.Params.Arguments.(map[string]interface{})
-			if !ok {
-				return nil, fmt.Errorf("invalid arguments format")
-			}
-			return s.toolHandler.CallTool(ctx, "create-ec2-instance", arguments)
-		},
+		s.readResource,
+	)
+	s.mcpServer.AddResourceTemplate(
+		mcp.NewResourceTemplate(ecsServicesTemplate, "ECS Services",
+			mcp.WithTemplateDescription("Services of an ECS cluster with desired and running counts, deployments and recent events. "+
+				"Services with a failed or stalled rollout, failing tasks or fewer running tasks than desired come first with their issues."),
+			mcp.WithTemplateMIMEType("application/json"),
+		),
+		s.readResource,
+	)
+	s.mcpServer.AddResourceTemplate(
+		mcp.NewResourceTemplate(ecsTasksTemplate, "ECS Tasks",
+			mcp.WithTemplateDescription("Running and recently stopped tasks of an ECS service with container exit codes and stop reasons"),
+			mcp.WithTemplateMIMEType("application/json"),
+		),
+		s.readResource,
 	)
 
-	// Register start EC2 instance tool
-	s.mcpServer.AddTool(
-		mcp.NewTool("start-ec2-instance",
-			mcp.WithDescription("Start a stopped EC2 instance"),
-			mcp.WithString("instanceId", mcp.Description("EC2 instance ID to start"), mcp.Required()),
+	// Register SQS queue resource and queue template
+	s.mcpServer.AddResource(
+		mcp.NewResource(sqsQueuesURI, "SQS Queues",
+			mcp.WithResourceDescription("SQS queues with visible, in-flight and delayed messages and the age of the oldest message. "+
+				"Backed-up queues, dead letter queues holding messages and messages close to expiry come first with their issues."),
+			mcp.WithMIMEType("application/json"),
 		),
-		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-			arguments, ok := request.Params.Arguments.(map[string]interface{})
-			if !ok {
-				return nil, fmt.Errorf("invalid arguments format")
-			}
-			return s.toolHandler.CallTool(ctx, "start-ec2-instance", arguments)
-		},
+		s.readResource,
+	)
+	s.mcpServer.AddResourceTemplate(
+		mcp.NewResourceTemplate(sqsQueueTemplate, "SQS Queue",
+			mcp.WithTemplateDescription("One SQS queue with its depth, retention and redrive policy; for a dead letter queue, the queues feeding it and its latest redrives"),
+			mcp.WithTemplateMIMEType("application/json"),
+		),
+		s.readResource,
 	)
 
-	// Register stop EC2 instance tool
-	s.mcpServer.AddTool(
-		mcp.NewTool("stop-ec2-instance",
-			mcp.WithDescription("Stop a running EC2 instance"),
-			mcp.WithString("instanceId", mcp.Description("EC2 instance ID to stop"), mcp.Required()),
+	// Register SNS topic resource and topic template
+	s.mcpServer.AddResource(
+		mcp.NewResource(snsTopicsURI, "SNS Topics",
+			mcp.WithResourceDescription("SNS topics with their confirmed and pending subscription counts. "+
+				"Topics whose messages reach no one or that have unconfirmed subscriptions come first."),
+			mcp.WithMIMEType("application/json"),
 		),
-		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-			arguments, ok := request.Params.Arguments.(map[string]interface{})
-			if !ok {
-				return nil, fmt.Errorf("invalid arguments format")
-			}
-			return s.toolHandler.CallTool(ctx, "stop-ec2-instance", arguments)
-		},
+		s.readResource,
+	)
+	s.mcpServer.AddResourceTemplate(
+		mcp.NewResourceTemplate(snsTopicTemplate, "SNS Topic",
+			mcp.WithTemplateDescription("One SNS topic, by name, with the protocol, endpoint and confirmation status of each subscription"),
+			mcp.WithTemplateMIMEType("application/json"),
+		),
+		s.readResource,
 	)
 
-	// Register terminate EC2 instance tool
-	s.mcpServer.AddTool(
-		mcp.NewTool("terminate-ec2-instance",
-			mcp.WithDescription("Terminate an EC2 instance (permanent deletion)"),
-			mcp.WithString("instanceId", mcp.Description("EC2 instance ID to terminate"), mcp.Required()),
+	// Register load balancer and target group health resources
+	s.mcpServer.AddResource(
+		mcp.NewResource(loadBalancersURI, "Load Balancers",
+			mcp.WithResourceDescription("ELBv2 load balancers with their listeners, target groups and how many targets are healthy"),
+			mcp.WithMIMEType("application/json"),
 		),
-		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-			arguments, ok := request.Params.Arguments.(map[string]interface{})
-			if !ok {
-				return nil, fmt.Errorf("invalid arguments format")
-			}
-			return s.toolHandler.CallTool(ctx, "terminate-ec2-instance", arguments)
-		},
+		s.readResource,
+	)
+	s.mcpServer.AddResource(
+		mcp.NewResource(targetGroupsURI, "Target Groups",
+			mcp.WithResourceDescription("ELBv2 target groups with the health and failure reason of every target, naming the EC2 instance behind instance targets"),
+			mcp.WithMIMEType("application/json"),
+		),
+		s.readResource,
 	)
-}
 
-// Start begins the stdio message loop for the MCP server
-func (s *Server) Start(ctx context.Context) error {
-	s.logger.Info("Starting MCP server message loop on stdio...")
-	scanner := bufio.NewScanner(os.Stdin)
+	// Register Cost Explorer resources and their templates
+	s.mcpServer.AddResource(
+		mcp.NewResource(costDailyURI, "Daily Cost",
+			mcp.WithResourceDescription("Cost per day from Cost Explorer over cost.explorer.days with the average, peak and trend between the halves of the window; "+
+				"broken down by cost.explorer.group_by when it is set"),
+			mcp.WithMIMEType("application/json"),
+		),
+		s.readResource,
+	)
+	s.mcpServer.AddResourceTemplate(
+		mcp.NewResourceTemplate(costDailyTemplate, "Cost Trend",
+			mcp.WithTemplateDescription("Cost per period over a chosen window, e.g. aws://cost/daily?days=90&granularity=MONTHLY&groupBy=LINKED_ACCOUNT. "+
+				"groupBy is a dimension such as SERVICE, LINKED_ACCOUNT, REGION or USAGE_TYPE, or tag:<key> for a cost allocation tag"),
+			mcp.WithTemplateMIMEType("application/json"),
+		),
+		s.readResource,
+	)
+	s.mcpServer.AddResource(
+		mcp.NewResource(costByServiceURI, "Cost by Service",
+			mcp.WithResourceDescription("Cost per AWS service over cost.explorer.days, most expensive first, with each service's share and change from the previous window"),
+			mcp.WithMIMEType("application/json"),
+		),
+		s.readResource,
+	)
+	s.mcpServer.AddResourceTemplate(
+		mcp.NewResourceTemplate(costByServiceTemplate, "Cost by Service over a Window",
+			mcp.WithTemplateDescription("Cost per service over a chosen number of days compared with the days before, e.g. aws://cost/by-service?days=7"),
+			mcp.WithTemplateMIMEType("application/json"),
+		),
+		s.readResource,
+	)
 
-	for scanner.Scan() {
-		select {
-		case <-ctx.Done():
-			s.logger.Info("Shutdown signal received, stopping server")
-			return ctx.Err()
-		default:
-			line := scanner.Bytes()
-			if len(line) == 0 {
-				continue
-			}
+	// Register Cost and Usage Report resources
+	if s.resourceHandler.config.Cost.CUR.Table != "" {
+		s.mcpServer.AddResource(
+			mcp.NewResource(curDailyURI, "Daily Cost per Resource",
+				mcp.WithResourceDescription("Daily unblended cost of the 50 most expensive resources over cost.cur.days, queried from the Cost and Usage Report with Athena"),
+				mcp.WithMIMEType("application/json"),
+			),
+			s.readResource,
+		)
+		s.mcpServer.AddResourceTemplate(
+			mcp.NewResourceTemplate(curResourceTemplate, "Resource Cost",
+				mcp.WithTemplateDescription("Daily cost of one resource, such as an instance ID, with its cost by usage type from the Cost and Usage Report. URL-encode ARNs."),
+				mcp.WithTemplateMIMEType("application/json"),
+			),
+			s.readResource,
+		)
+	}
 
-			// Handle the JSON-RPC message
-			response := s.mcpServer.HandleMessage(ctx, line)
+	// Register ElastiCache cluster resource and cluster template
+	s.mcpServer.AddResource(
+		mcp.NewResource(cacheClustersURI, "ElastiCache Clusters",
+			mcp.WithResourceDescription("ElastiCache Redis OSS, Valkey and Memcached clusters with node status, maintenance windows and pending service updates. "+
+				"Clusters with unavailable nodes, parameter changes awaiting a reboot or updates due soon come first."),
+			mcp.WithMIMEType("application/json"),
+		),
+		s.readResource,
+	)
+	s.mcpServer.AddResourceTemplate(
+		mcp.NewResourceTemplate(cacheClusterTemplate, "ElastiCache Cluster",
+			mcp.WithTemplateDescription("One ElastiCache cluster with each node's status and endpoint, pending changes and updates, and its events of the last 24 hours"),
+			mcp.WithTemplateMIMEType("application/json"),
+		),
+		s.readResource,
+	)
 
-			// Write response to stdout
-			if response != nil {
-				responseBytes, err := json.Marshal(response)
-				if err != nil {
-					s.logger.WithError(err).Error("Failed to marshal response")
-					continue
-				}
+	// Register RDS instances list resource
+	s.mcpServer.AddResource(
+		mcp.NewResource("aws://rds/instances", "RDS Instances",
+			mcp.WithResourceDescription("List all RDS database instances in the region with engine, class, status and endpoint"),
+			mcp.WithMIMEType("application/json"),
+		),
+		s.readResource,
+	)
 
-				os.Stdout.Write(responseBytes)
-				os.Stdout.Write([]byte("\n"))
-			}
-		}
-	}
+	// Register security group list resource and detail template
+	s.mcpServer.AddResource(
+		mcp.NewResource(securityGroupsURI, "Security Groups",
+			mcp.WithResourceDescription("Security groups with their inbound rules and the instances using them, groups open to the internet first"),
+			mcp.WithMIMEType("application/json"),
+		),
+		s.readResource,
+	)
+	s.mcpServer.AddResourceTemplate(
+		mcp.NewResourceTemplate(securityGroupTemplate, "Security Group Details",
+			mcp.WithTemplateDescription("Inbound and outbound rules of one security group and the instances using it"),
+			mcp.WithTemplateMIMEType("application/json"),
+		),
+		s.readResource,
+	)
 
-	if err := scanner.Err(); err != nil {
-		s.logger.WithError(err).Error("Error reading from stdin")
-		return err
-	}
+	// Register SSM Parameter Store resources
+	s.mcpServer.AddResource(
+		mcp.NewResource(parametersURI, "SSM Parameters",
+			mcp.WithResourceDescription("SSM Parameter Store parameters under parameters.path_prefix with their types, versions and values; SecureString values are redacted unless configured"),
+			mcp.WithMIMEType("application/json"),
+		),
+		s.readResource,
+	)
+	s.mcpServer.AddResourceTemplate(
+		mcp.NewResourceTemplate(parametersPathTemplate, "SSM Parameters by Path",
+			mcp.WithTemplateDescription("Parameters under a narrower path, e.g. aws://ssm/parameters?path=/app/prod"),
+			mcp.WithTemplateMIMEType("application/json"),
+		),
+		s.readResource,
+	)
+	s.mcpServer.AddResourceTemplate(
+		mcp.NewResourceTemplate(parameterTemplate, "SSM Parameter",
+			mcp.WithTemplateDescription("One parameter by name, e.g. aws://ssm/parameters/app/prod/db-host for /app/prod/db-host"),
+			mcp.WithTemplateMIMEType("application/json"),
+		),
+		s.readResource,
+	)
 
-	return nil
+	// Register Secrets Manager resources
+	s.mcpServer.AddResource(
+		mcp.NewResource(secretsURI, "Secrets",
+			mcp.WithResourceDescription("Secrets Manager secrets with their rotation status and last and next rotation dates, failing and overdue rotations first; values are never read"),
+			mcp.WithMIMEType("application/json"),
+		),
+		s.readResource,
+	)
+	s.mcpServer.AddResourceTemplate(
+		mcp.NewResourceTemplate(secretTemplate, "Secret Details",
+			mcp.WithTemplateDescription("Rotation status, rotation function and version stages of one secret, e.g. aws://secretsmanager/secrets/prod/db-password"),
+			mcp.WithTemplateMIMEType("application/json"),
+		),
+		s.readResource,
+	)
+
+	// Register API Gateway resource and API template
+	s.mcpServer.AddResource(
+		mcp.NewResource(apisURI, "API Gateway APIs",
+			mcp.WithResourceDescription("REST, HTTP and WebSocket APIs with their stages, deployments and throttling; "+
+				"APIs with a stage that rejects all requests or failed to deploy come first"),
+			mcp.WithMIMEType("application/json"),
+		),
+		s.readResource,
+	)
+	s.mcpServer.AddResourceTemplate(
+		mcp.NewResourceTemplate(apiTemplate, "API Gateway API",
+			mcp.WithTemplateDescription("One API with the throttling of each stage, including the limits set on single methods or routes"),
+			mcp.WithTemplateMIMEType("application/json"),
+		),
+		s.readResource,
+	)
+
+	// Register Service Quotas resource and service template
+	s.mcpServer.AddResource(
+		mcp.NewResource(quotasURI, "Service Quotas",
+			mcp.WithResourceDescription("Quotas of EC2, Lambda and VPC that are in use, with their peak usage over the last hour as a share of the limit; "+
+				"quotas at 80% or more are listed at risk, with the increase requests AWS is still reviewing"),
+			mcp.WithMIMEType("application/json"),
+		),
+		s.readResource,
+	)
+	s.mcpServer.AddResourceTemplate(
+		mcp.NewResourceTemplate(quotaServiceTemplate, "Service Quotas of a service",
+			mcp.WithTemplateDescription("All applied quotas of one service by its service code, e.g. ec2, lambda or vpc, whether or not they are in use"),
+			mcp.WithTemplateMIMEType("application/json"),
+		),
+		s.readResource,
+	)
+
+	// Register Kinesis stream resource and stream template
+	s.mcpServer.AddResource(
+		mcp.NewResource(kinesisStreamsURI, "Kinesis Streams",
+			mcp.WithResourceDescription("Kinesis data streams with shard counts, iterator age and throttled reads and writes over the last 15 minutes; "+
+				"streams that are throttled or whose consumers fall behind come first, with a suggested shard count when writes near capacity"),
+			mcp.WithMIMEType("application/json"),
+		),
+		s.readResource,
+	)
+	s.mcpServer.AddResourceTemplate(
+		mcp.NewResourceTemplate(kinesisStreamTemplate, "Kinesis Stream",
+			mcp.WithTemplateDescription("One Kinesis stream with its throughput metrics, retention, encryption and consumer count"),
+			mcp.WithTemplateMIMEType("application/json"),
+		),
+		s.readResource,
+	)
+
+	// Register Step Functions state machine resource and executions template
+	s.mcpServer.AddResource(
+		mcp.NewResource(stateMachinesURI, "Step Functions State Machines",
+			mcp.WithResourceDescription("Step Functions state machines with the statuses of their recent executions; machines whose last execution failed come first"),
+			mcp.WithMIMEType("application/json"),
+		),
+		s.readResource,
+	)
+	s.mcpServer.AddResourceTemplate(
+		mcp.NewResourceTemplate(executionsTemplate, "Step Functions Executions",
+			mcp.WithTemplateDescription("Recent executions of a state machine; the latest failures include the error, cause, failed state and whether they can be redriven"),
+			mcp.WithTemplateMIMEType("application/json"),
+		),
+		s.readResource,
+	)
+
+	// Register SSM managed instance resource with inventory and patch templates
+	s.mcpServer.AddResource(
+		mcp.NewResource(managedInstancesURI, "SSM Managed Instances",
+			mcp.WithResourceDescription("Instances managed by Systems Manager with agent version and ping status and Patch Manager compliance. "+
+				"Instances with failed or missing patches come first."),
+			mcp.WithMIMEType("application/json"),
+		),
+		s.readResource,
+	)
+	s.mcpServer.AddResourceTemplate(
+		mcp.NewResourceTemplate(instanceInventoryTemplate, "SSM Instance Inventory",
+			mcp.WithTemplateDescription("Applications and packages SSM Inventory collected from an instance, optionally only those whose name contains ?name=, e.g. openssl"),
+			mcp.WithTemplateMIMEType("application/json"),
+		),
+		s.readResource,
+	)
+	s.mcpServer.AddResourceTemplate(
+		mcp.NewResourceTemplate(instancePatchesTemplate, "SSM Instance Patches",
+			mcp.WithTemplateDescription("Patch compliance of an instance with its missing, failed and pending-reboot patches and the maintenance windows that patch it"),
+			mcp.WithTemplateMIMEType("application/json"),
+		),
+		s.readResource,
+	)
+
+	// Register ECR repository resource and image templates
+	s.mcpServer.AddResource(
+		mcp.NewResource(ecrRepositoriesURI, "ECR Repositories",
+			mcp.WithResourceDescription("ECR repositories with their repository URI, tag mutability and whether images are scanned on push"),
+			mcp.WithMIMEType("application/json"),
+		),
+		s.readResource,
+	)
+	s.mcpServer.AddResourceTemplate(
+		mcp.NewResourceTemplate(ecrImagesTemplate, "ECR Images",
+			mcp.WithTemplateDescription("The newest images of an ECR repository with their tags, digests, push and pull times and scan finding counts by severity"),
+			mcp.WithTemplateMIMEType("application/json"),
+		),
+		s.readResource,
+	)
+	s.mcpServer.AddResourceTemplate(
+		mcp.NewResourceTemplate(ecrImageTemplate, "ECR Image Scan Findings",
+			mcp.WithTemplateDescription("Scan findings of one image by tag or sha256: digest, most severe first with the fixed package version. "+
+				"ECS tasks link the image each container runs here."),
+			mcp.WithTemplateMIMEType("application/json"),
+		),
+		s.readResource,
+	)
+
+	// Register VPC topology resource
+	s.mcpServer.AddResource(
+		mcp.NewResource(vpcTopologyURI, "VPC Topology",
+			mcp.WithResourceDescription("VPCs with their subnets, route tables, NAT and internet gateways, each subnet's tier and path to the internet, and likely connectivity issues"),
+			mcp.WithMIMEType("application/json"),
+		),
+		s.readResource,
+	)
+
+	// Register NAT gateway and VPC endpoint resources
+	s.mcpServer.AddResource(
+		mcp.NewResource(natGatewaysURI, "NAT Gateways",
+			mcp.WithResourceDescription("NAT gateways with the data they processed, port allocation errors, dropped packets and peak connections over the last hour; "+
+				"failed gateways and those running out of source ports come first"),
+			mcp.WithMIMEType("application/json"),
+		),
+		s.readResource,
+	)
+	s.mcpServer.AddResource(
+		mcp.NewResource(vpcEndpointsURI, "VPC Endpoints",
+			mcp.WithResourceDescription("Interface and gateway VPC endpoints with their service, state, subnets and route tables, "+
+				"and the VPCs whose S3 or DynamoDB traffic goes through a NAT gateway for lack of a gateway endpoint"),
+			mcp.WithMIMEType("application/json"),
+		),
+		s.readResource,
+	)
+
+	// Register EBS volume and snapshot resources
+	s.mcpServer.AddResource(
+		mcp.NewResource(ebsVolumesURI, "EBS Volumes",
+			mcp.WithResourceDescription("EBS volumes with size, type, IOPS, throughput and attachment, unattached volumes first"),
+			mcp.WithMIMEType("application/json"),
+		),
+		s.readResource,
+	)
+	s.mcpServer.AddResource(
+		mcp.NewResource(ebsSnapshotsURI, "EBS Snapshots",
+			mcp.WithResourceDescription("EBS snapshots owned by the account with source volume and size, newest first"),
+			mcp.WithMIMEType("application/json"),
+		),
+		s.readResource,
+	)
+
+	// Register Spot request and interruption resources
+	s.mcpServer.AddResource(
+		mcp.NewResource(spotRequestsURI, "Spot Instance Requests",
+			mcp.WithResourceDescription("Spot Instance requests with state, status, instance and maximum price, newest first"),
+			mcp.WithMIMEType("application/json"),
+		),
+		s.readResource,
+	)
+	s.mcpServer.AddResource(
+		mcp.NewResource(spotInterruptionsURI, "Spot Interruptions",
+			mcp.WithResourceDescription("Spot Instances with a pending interruption notice and those interrupted in the last 7 days"),
+			mcp.WithMIMEType("application/json"),
+		),
+		s.readResource,
+	)
+
+	// Register EFS file system resource and file system template
+	s.mcpServer.AddResource(
+		mcp.NewResource(efsFileSystemsURI, "EFS File Systems",
+			mcp.WithResourceDescription("EFS file systems with throughput mode, burst credits, permitted throughput, I/O limit and mount targets; "+
+				"file systems running out of burst credits or near the I/O limit come first"),
+			mcp.WithMIMEType("application/json"),
+		),
+		s.readResource,
+	)
+	s.mcpServer.AddResourceTemplate(
+		mcp.NewResourceTemplate(efsFileSystemTemplate, "EFS File System",
+			mcp.WithTemplateDescription("One EFS file system by ID with its throughput, burst credits and mount targets"),
+			mcp.WithTemplateMIMEType("application/json"),
+		),
+		s.readResource,
+	)
+
+	// Register AWS Config compliance resources and rule template
+	s.mcpServer.AddResource(
+		mcp.NewResource(configRulesURI, "AWS Config Rules",
+			mcp.WithResourceDescription("AWS Config rules with their compliance, count of non-compliant resources and remediation action; "+
+				"rules with the most non-compliant resources come first"),
+			mcp.WithMIMEType("application/json"),
+		),
+		s.readResource,
+	)
+	s.mcpServer.AddResourceTemplate(
+		mcp.NewResourceTemplate(configRuleTemplate, "AWS Config Rule",
+			mcp.WithTemplateDescription("One AWS Config rule by name with the resources it found non-compliant, the rule's annotations and how to remediate them"),
+			mcp.WithTemplateMIMEType("application/json"),
+		),
+		s.readResource,
+	)
+	s.mcpServer.AddResource(
+		mcp.NewResource(configNonCompliantURI, "Non-compliant Resources",
+			mcp.WithResourceDescription("Resources failing AWS Config rules, with the rules each fails, their annotations and remediation actions; "+
+				"resources failing the most rules come first"),
+			mcp.WithMIMEType("application/json"),
+		),
+		s.readResource,
+	)
+
+	// Register AWS Backup job resource and per-resource template
+	s.mcpServer.AddResource(
+		mcp.NewResource(backupJobsURI, "AWS Backup Jobs",
+			mcp.WithResourceDescription("AWS Backup jobs created within backup.job_lookback with state, size and recovery point; "+
+				"failed, aborted, expired and partial jobs come first"),
+			mcp.WithMIMEType("application/json"),
+		),
+		s.readResource,
+	)
+	s.mcpServer.AddResourceTemplate(
+		mcp.NewResourceTemplate(backupResourceTemplate, "AWS Backup Resource",
+			mcp.WithTemplateDescription("Recent backup jobs and recovery points of one resource by ARN, with the latest usable recovery point and its age"),
+			mcp.WithTemplateMIMEType("application/json"),
+		),
+		s.readResource,
+	)
+
+	// Register unencrypted resources report
+	s.mcpServer.AddResource(
+		mcp.NewResource("aws://security/unencrypted", "Unencrypted Resources",
+			mcp.WithResourceDescription("EBS volumes, snapshots and RDS instances stored without encryption"),
+			mcp.WithMIMEType("application/json"),
+		),
+		s.readResource,
+	)
+
+	// Register IAM credential hygiene report
+	s.mcpServer.AddResource(
+		mcp.NewResource("aws://iam/credential-hygiene", "IAM Credential Hygiene",
+			mcp.WithResourceDescription("IAM users with old or unused access keys, unused console passwords and console access without MFA"),
+			mcp.WithMIMEType("application/json"),
+		),
+		s.readResource,
+	)
+
+	// Register read-only IAM roles, users and policies
+	s.mcpServer.AddResource(
+		mcp.NewResource(iamRolesURI, "IAM Roles",
+			mcp.WithResourceDescription("IAM roles with who may assume them, their attached and inline policy statements and when they were last used"),
+			mcp.WithMIMEType("application/json"),
+		),
+		s.readResource,
+	)
+	s.mcpServer.AddResource(
+		mcp.NewResource(iamUsersURI, "IAM Users",
+			mcp.WithResourceDescription("IAM users with the policies attached to them directly and through their groups"),
+			mcp.WithMIMEType("application/json"),
+		),
+		s.readResource,
+	)
+	s.mcpServer.AddResourceTemplate(
+		mcp.NewResourceTemplate(iamPolicyTemplate, "IAM Policy",
+			mcp.WithTemplateDescription("Statements of a managed policy's default version and the users, groups and roles it is attached to"),
+			mcp.WithTemplateMIMEType("application/json"),
+		),
+		s.readResource,
+	)
+
+	// Register PromQL query template
+	if s.resourceHandler.prometheus != nil {
+		s.mcpServer.AddResourceTemplate(
+			mcp.NewResourceTemplate(promQueryTemplate, "Prometheus Query",
+				mcp.WithTemplateDescription("Run PromQL against the configured Prometheus/Thanos endpoint. "+
+					"Instant query: prom://query?expr=<PromQL>[&time=<RFC3339|unix|now-1h>]. "+
+					"Range query: prom://query?expr=<PromQL>&start=now-6h[&end=now][&step=5m]. "+
+					"URL-encode the expression, in particular + as %2B."),
+				mcp.WithTemplateMIMEType("application/json"),
+			),
+			s.readResource,
+		)
+	}
+
+	// Register provider metrics query template
+	if s.resourceHandler.metrics != nil {
+		s.mcpServer.AddResourceTemplate(
+			mcp.NewResourceTemplate(metricsQueryTemplate, "Metrics Query",
+				mcp.WithTemplateDescription("Query time series from the configured "+s.resourceHandler.metrics.Name()+" account. "+
+					"metrics://query?q=<query>[&start=now-6h][&end=now][&step=5m]; the window defaults to the last hour. "+
+					"q is a Datadog metric query (avg:system.cpu.user{env:prod} by {host}) or NRQL "+
+					"(SELECT average(cpuPercent) FROM SystemSample FACET hostname) depending on the provider. URL-encode the query."),
+				mcp.WithTemplateMIMEType("application/json"),
+			),
+			s.readResource,
+		)
+	}
+
+	// Register current on-call responders
+	if s.resourceHandler.oncall != nil {
+		s.mcpServer.AddResource(
+			mcp.NewResource("aws://oncall/current", "Current On-Call",
+				mcp.WithResourceDescription("Who is on call right now in "+s.resourceHandler.oncall.Provider()+
+					", primary responders first, with mentions to use in notifications and tickets"),
+				mcp.WithMIMEType("application/json"),
+			),
+			s.readResource,
+		)
+	}
+
+	// Register resource ownership lookups
+	if s.resourceHandler.owners != nil {
+		s.mcpServer.AddResourceTemplate(
+			mcp.NewResourceTemplate(ownershipTemplate, "Resource Owner",
+				mcp.WithTemplateDescription("Which team owns a resource (EC2 ID, ARN or Name tag), decided by "+
+					strings.Join(s.resourceHandler.owners.Sources(), ", then ")+
+					", with the team's ticket queue and channels. Notifications about the resource are routed to the same team."),
+				mcp.WithTemplateMIMEType("application/json"),
+			),
+			s.readResource,
+		)
+	}
+
+	// Register pending approvals queue
+	s.mcpServer.AddResource(
+		mcp.NewResource("aws://approvals/pending", "Pending Approvals",
+			mcp.WithResourceDescription("Actions blocked by cost guardrails or change freezes, waiting for an admin decision"),
+			mcp.WithMIMEType("application/json"),
+		),
+		s.readResource,
+	)
+}
+
+// readResource serves a registered resource through the ResourceHandler
+func (s *Server) readResource(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	s.logger.WithField("uri", request.Params.URI).Info("Received read resource request")
+
+	result, err := s.resourceHandler.ReadResource(ctx, request.Params.URI)
+	if err != nil {
+		s.logger.WithError(err).WithField("uri", request.Params.URI).Error("Failed to read resource")
+		return nil, err
+	}
+
+	return result.Contents, nil
+}
+
+// registerTools sets up all the MCP tools
+// NOTE: In production, it's better to declare tools as an array of structs and use a loop
+// to register them. This approach reduces code duplication and makes it easier to manage
+// many tools. For this chapter, we write each tool registration separately to make the
+// code cleaner and easier to understand.
+//
+// Production approach would look like:
+//
+//	type ToolDefinition struct {
+//	    Name        string
+//	    Description string
+//	    Parameters  []mcp.ToolParameter
+//	    Handler     string
+//	}
+//
+//	tools := []ToolDefinition{
+//	    {Name: "create-ec2-instance", Description: "Create a new EC2 instance", ...},
+//	    {Name: "start-ec2-instance", Description: "Start a stopped EC2 instance", ...},
+//	    // ... more tools
+//	}
+//
+//	for _, tool := range tools {
+//	    s.mcpServer.AddTool(mcp.NewTool(tool.Name, tool.Parameters...), s.getHandlerFunc(tool.Handler))
+//	}
+func (s *Server) registerTools() {
+	// Register create EC2 instance tool
+	s.addTool(
+		mcp.NewTool("create-ec2-instance",
+			mcp.WithDescription("Create a new EC2 instance"),
+			mcp.WithString("imageId", mcp.Description("AMI ID to use for the instance"), mcp.Required()),
+			mcp.WithString("instanceType", mcp.Description("EC2 instance type (e.g., t2.micro, t3.small)"), mcp.Required()),
+			mcp.WithString("keyName", mcp.Description("Name of the key pair to use for SSH access")),
+			mcp.WithString("securityGroupId", mcp.Description("Security group ID to assign to the instance")),
+			mcp.WithString("subnetId", mcp.Description("Subnet ID where the instance should be launched")),
+			mcp.WithString("name", mcp.Description("Name tag for the instance")),
+			mcp.WithBoolean("spot", mcp.Description("Launch the instance on the Spot market; AWS can interrupt it with a two-minute notice")),
+			mcp.WithString("spotMaxPrice", mcp.Description("Maximum Spot price in USD per hour (defaults to the On-Demand price)")),
+			mcp.WithString("spotInterruptionBehavior", mcp.Description("What happens on interruption: terminate (default), stop or hibernate; stop and hibernate make the Spot request persistent")),
+			mcp.WithBoolean("overrideCostGuardrail", mcp.Description("Create the instance even if its estimated cost exceeds the configured guardrail")),
+			mcp.WithString("account", mcp.Description("Alias or ID of the organization account to act in (default: the server's own account)")),
+		),
+	)
+
+	// Register start EC2 instance tool
+	s.addTool(
+		mcp.NewTool("start-ec2-instance",
+			mcp.WithDescription("Start a stopped EC2 instance"),
+			mcp.WithString("instanceId", mcp.Description("EC2 instance ID to start"), mcp.Required()),
+			mcp.WithString("account", mcp.Description("Alias or ID of the organization account to act in (default: the server's own account)")),
+		),
+	)
+
+	// Register stop EC2 instance tool
+	s.addTool(
+		mcp.NewTool("stop-ec2-instance",
+			mcp.WithDescription("Stop a running EC2 instance"),
+			mcp.WithString("instanceId", mcp.Description("EC2 instance ID to stop"), mcp.Required()),
+			mcp.WithString("account", mcp.Description("Alias or ID of the organization account to act in (default: the server's own account)")),
+			mcp.WithBoolean("overrideErrorBudget", mcp.Description("Run even though the affected service has nearly spent its error budget")),
+		),
+	)
+
+	// Register Compute Engine start/stop tools
+	if s.toolHandler.clouds.Get(gcp.ProviderName) != nil {
+		s.addTool(
+			mcp.NewTool("start-gcp-instance",
+				mcp.WithDescription("Start a stopped (TERMINATED) GCP Compute Engine instance"),
+				mcp.WithString("instanceId", mcp.Description("Compute Engine instance name to start"), mcp.Required()),
+			),
+		)
+		s.addTool(
+			mcp.NewTool("stop-gcp-instance",
+				mcp.WithDescription("Stop a running GCP Compute Engine instance"),
+				mcp.WithString("instanceId", mcp.Description("Compute Engine instance name to stop"), mcp.Required()),
+			),
+		)
+	}
+
+	// Register Azure VM start/stop tools
+	if s.toolHandler.clouds.Get(azure.ProviderName) != nil {
+		s.addTool(
+			mcp.NewTool("start-azure-vm",
+				mcp.WithDescription("Start a stopped or deallocated Azure virtual machine"),
+				mcp.WithString("instanceId", mcp.Description("Azure VM name to start"), mcp.Required()),
+			),
+		)
+		s.addTool(
+			mcp.NewTool("stop-azure-vm",
+				mcp.WithDescription("Stop and deallocate a running Azure virtual machine so its compute is no longer billed"),
+				mcp.WithString("instanceId", mcp.Description("Azure VM name to stop"), mcp.Required()),
+			),
+		)
+	}
+
+	// Register terminate EC2 instance tool
+	s.addTool(
+		mcp.NewTool("terminate-ec2-instance",
+			mcp.WithDescription("Terminate an EC2 instance (permanent deletion)"),
+			mcp.WithString("instanceId", mcp.Description("EC2 instance ID to terminate"), mcp.Required()),
+			mcp.WithString("account", mcp.Description("Alias or ID of the organization account to act in (default: the server's own account)")),
+			mcp.WithBoolean("overrideErrorBudget", mcp.Description("Run even though the affected service has nearly spent its error budget")),
+		),
+	)
+
+	// Register bulk stop tool
+	s.addTool(
+		mcp.NewTool("bulk-stop-ec2-instances",
+			mcp.WithDescription("Stop or terminate several EC2 instances in dependency order. Clients stop before the instances they connect to, "+
+				"bastions stop last and instances behind the same target group are staggered. Waves run one at a time with a pause and health "+
+				"checks in between; a failed wave stops the rest. Returns the plan for review unless confirm=true."),
+			mcp.WithArray("instanceIds", mcp.Description("EC2 instance IDs to stop"), mcp.WithStringItems(), mcp.Required()),
+			mcp.WithString("account", mcp.Description("Alias or ID of the organization account to act in (default: the server's own account)")),
+			mcp.WithString("action", mcp.Description("stop (default) or terminate"), mcp.Enum("stop", "terminate")),
+			mcp.WithNumber("staggerSeconds", mcp.Description("Pause between waves in seconds (default 30)")),
+			mcp.WithNumber("waveTimeoutSeconds", mcp.Description("How long a wave may take to stop and pass health checks before the rest is abandoned (default 300)")),
+			mcp.WithBoolean("confirm", mcp.Description("Set to true to run the plan after reviewing it")),
+			mcp.WithBoolean("overrideErrorBudget", mcp.Description("Run even though the affected service has nearly spent its error budget")),
+		),
+	)
+
+	// Register remediation verification tool
+	s.addTool(
+		mcp.NewTool("verify-remediation",
+			mcp.WithDescription("After an action, watch the signal that prompted it and report whether the remediation worked: resolved, flapping, "+
+				"not_resolved or inconclusive. Give exactly one of alarmName (OK is healthy), instanceId (status checks) or targetGroupArn (target health). "+
+				"The call lasts for the whole verification period. The outcome is recorded for aws://knowledge/remediations."),
+			mcp.WithString("alarmName", mcp.Description("CloudWatch alarm that fired")),
+			mcp.WithString("instanceId", mcp.Description("EC2 instance whose status checks failed")),
+			mcp.WithString("targetGroupArn", mcp.Description("Target group whose targets were unhealthy")),
+			mcp.WithString("targetId", mcp.Description("Only check this target of the target group")),
+			mcp.WithString("remediation", mcp.Description("What was done, e.g. the tool call, for the report and notification")),
+			mcp.WithString("action", mcp.Description("Tool that remediated, e.g. reboot-rds-instance (default: the latest change to the resource in the audit log)")),
+			mcp.WithString("signal", mcp.Description("Kind of signal for aws://knowledge/remediations, e.g. high-cpu (default: derived from the alarm metric or what is watched)")),
+			mcp.WithNumber("durationSeconds", mcp.Description("How long to watch the signal (default remediation.verify_duration, 5 minutes)")),
+			mcp.WithNumber("intervalSeconds", mcp.Description("How often to check it (default remediation.verify_interval, 30 seconds)")),
+		),
+	)
+
+	// Register SQS queue tools
+	s.addTool(
+		mcp.NewTool("purge-sqs-queue",
+			mcp.WithDescription("Delete every message in an SQS queue, e.g. to drop a backlog of poison or obsolete messages. "+
+				"Returns the message counts for review unless confirm=true."),
+			mcp.WithString("queue", mcp.Description("SQS queue name"), mcp.Required()),
+			mcp.WithBoolean("confirm", mcp.Description("Set to true to purge after reviewing the plan")),
+		),
+	)
+
+	s.addTool(
+		mcp.NewTool("redrive-sqs-dlq",
+			mcp.WithDescription("Move the messages of a dead letter queue back to the queues they failed in, or to destinationQueue, once the cause of the failures is fixed"),
+			mcp.WithString("queue", mcp.Description("Dead letter queue name"), mcp.Required()),
+			mcp.WithString("destinationQueue", mcp.Description("Queue to move the messages to (default: their source queues)")),
+			mcp.WithNumber("maxMessagesPerSecond", mcp.Description("Limit the redrive rate so consumers are not overwhelmed (1-500, default: chosen by SQS)")),
+		),
+	)
+
+	// Register SNS publish tool
+	s.addTool(
+		mcp.NewTool("publish-sns-message",
+			mcp.WithDescription("Publish a message to an SNS topic, e.g. to page the on-call humans subscribed to it or fan an event out to other systems. "+
+				"Check aws://sns/topics/{topic} first to see who receives it."),
+			mcp.WithString("topic", mcp.Description("SNS topic name or ARN"), mcp.Required()),
+			mcp.WithString("message", mcp.Description("Message body, at most 256 KB"), mcp.Required()),
+			mcp.WithString("subject", mcp.Description("Subject used by email subscriptions, at most 100 printable ASCII characters on one line")),
+			mcp.WithObject("attributes", mcp.Description("String message attributes subscribers can filter on, e.g. {\"severity\": \"critical\"}")),
+			mcp.WithString("messageGroupId", mcp.Description("Message group, required for FIFO topics")),
+			mcp.WithString("deduplicationId", mcp.Description("Deduplication ID for FIFO topics without content-based deduplication")),
+		),
+	)
+
+	// Register ECS service tools
+	s.addTool(
+		mcp.NewTool("update-ecs-service",
+			mcp.WithDescription("Set the desired task count of an ECS service to scale it out or in"),
+			mcp.WithString("cluster", mcp.Description("ECS cluster name"), mcp.Required()),
+			mcp.WithString("service", mcp.Description("ECS service name"), mcp.Required()),
+			mcp.WithNumber("desiredCount", mcp.Description("Number of tasks the service should run (0 stops all tasks)"), mcp.Required()),
+		),
+	)
+
+	s.addTool(
+		mcp.NewTool("force-ecs-deployment",
+			mcp.WithDescription("Force a new deployment of an ECS service, replacing all tasks with the same task definition, e.g. to recover stuck tasks or pull an image pushed under the same tag"),
+			mcp.WithString("cluster", mcp.Description("ECS cluster name"), mcp.Required()),
+			mcp.WithString("service", mcp.Description("ECS service name"), mcp.Required()),
+		),
+	)
+
+	// Register RDS instance tools
+	s.addTool(
+		mcp.NewTool("start-rds-instance",
+			mcp.WithDescription("Start a stopped RDS database instance"),
+			mcp.WithString("dbInstanceId", mcp.Description("RDS DB instance identifier to start"), mcp.Required()),
+		),
+	)
+
+	s.addTool(
+		mcp.NewTool("stop-rds-instance",
+			mcp.WithDescription("Stop a running RDS database instance (RDS starts it again automatically after 7 days)"),
+			mcp.WithString("dbInstanceId", mcp.Description("RDS DB instance identifier to stop"), mcp.Required()),
+			mcp.WithString("snapshotId", mcp.Description("Take a snapshot with this identifier before stopping")),
+			mcp.WithBoolean("overrideErrorBudget", mcp.Description("Run even though the affected service has nearly spent its error budget")),
+		),
+	)
+
+	s.addTool(
+		mcp.NewTool("reboot-rds-instance",
+			mcp.WithDescription("Reboot an RDS database instance, causing a brief outage"),
+			mcp.WithString("dbInstanceId", mcp.Description("RDS DB instance identifier to reboot"), mcp.Required()),
+			mcp.WithBoolean("forceFailover", mcp.Description("Reboot a Multi-AZ instance by failing over to its standby")),
+			mcp.WithBoolean("overrideErrorBudget", mcp.Description("Run even though the affected service has nearly spent its error budget")),
+		),
+	)
+
+	// Register ElastiCache node tools
+	s.addTool(
+		mcp.NewTool("reboot-cache-node",
+			mcp.WithDescription("Reboot nodes of an ElastiCache cluster, e.g. to apply parameter changes or recover a hung node. "+
+				"Returns the nodes and what clients lose for review unless confirm=true."),
+			mcp.WithString("clusterId", mcp.Description("ElastiCache cluster ID, e.g. sessions-001"), mcp.Required()),
+			mcp.WithArray("nodeIds", mcp.Description("Node IDs to reboot, e.g. [\"0001\"] (default: all nodes of the cluster)"), mcp.WithStringItems()),
+			mcp.WithBoolean("confirm", mcp.Description("Set to true to reboot after reviewing the plan")),
+			mcp.WithBoolean("overrideErrorBudget", mcp.Description("Run even though the affected service has nearly spent its error budget")),
+		),
+	)
+
+	s.addTool(
+		mcp.NewTool("create-rds-snapshot",
+			mcp.WithDescription("Create a manual snapshot of an RDS database instance"),
+			mcp.WithString("dbInstanceId", mcp.Description("RDS DB instance identifier to snapshot"), mcp.Required()),
+			mcp.WithString("snapshotId", mcp.Description("Snapshot identifier (default: <dbInstanceId>-<UTC timestamp>)")),
+		),
+	)
+
+	// Register tag compliance audit tool
+	s.addTool(
+		mcp.NewTool("audit-tags",
+			mcp.WithDescription("Audit EC2 instances against the required-tag policy and group non-compliant resources by owner"),
+			mcp.WithBoolean("autoRemediate", mcp.Description("Apply the configured default tags to non-compliant resources")),
+		),
+	)
+
+	// Register tag management tools
+	s.addTool(
+		mcp.NewTool("tag-resource",
+			mcp.WithDescription("Add or overwrite tags on EC2 resources of any type, such as instances, volumes, snapshots, AMIs, security groups, "+
+				"subnets and VPCs, e.g. to apply missing tags reported by audit-tags"),
+			mcp.WithArray("resourceIds", mcp.Description("EC2 resource IDs to tag, e.g. i-0abc123 or vol-0abc123"), mcp.WithStringItems(), mcp.Required()),
+			mcp.WithObject("tags", mcp.Description("Tag keys and values to apply, e.g. {\"Owner\": \"payments\"}"), mcp.Required()),
+			mcp.WithString("account", mcp.Description("Alias or ID of the organization account to act in (default: the server's own account)")),
+		),
+	)
+
+	s.addTool(
+		mcp.NewTool("untag-resource",
+			mcp.WithDescription("Remove tags from EC2 resources of any type, whatever their values"),
+			mcp.WithArray("resourceIds", mcp.Description("EC2 resource IDs to remove the tags from"), mcp.WithStringItems(), mcp.Required()),
+			mcp.WithArray("tagKeys", mcp.Description("Keys of the tags to remove"), mcp.WithStringItems(), mcp.Required()),
+			mcp.WithString("account", mcp.Description("Alias or ID of the organization account to act in (default: the server's own account)")),
+		),
+	)
+
+	// Register public exposure scanner tool
+	s.addTool(
+		mcp.NewTool("find-public-exposure",
+			mcp.WithDescription("Report which ports of instances and load balancers are reachable from the internet"),
+		),
+	)
+
+	// Register orphaned resource finder tool
+	s.addTool(
+		mcp.NewTool("find-orphans",
+			mcp.WithDescription("Find resources that cost money while nothing uses them: unattached EBS volumes, unassociated Elastic IPs, "+
+				"load balancers without healthy targets, empty target groups and old unused AMIs and snapshots, with the estimated monthly cost of each"),
+			mcp.WithArray("kinds", mcp.Description("Only look for these kinds: volume, elastic-ip, load-balancer, target-group, ami or snapshot"), mcp.WithStringItems()),
+			mcp.WithNumber("minAgeDays", mcp.Description("Only report AMIs and snapshots older than this many days (default: 90)")),
+		),
+	)
+
+	// Register semantic search tool when search is enabled
+	if s.toolHandler.search != nil {
+		s.addTool(
+			mcp.NewTool("search",
+				mcp.WithDescription("Find resources and knowledge base docs by what they are called rather than their IDs, e.g. \"payments database\" or \"checkout queue\". "+
+					"Matches names, tags and doc contents and returns the resource URI to read next"),
+				mcp.WithString("query", mcp.Description("What to look for, in plain words"), mcp.Required()),
+				mcp.WithArray("kinds", mcp.Description("Only return these kinds: instance, database, cache, queue, topic, load-balancer, ecs-service or doc"), mcp.WithStringItems()),
+				mcp.WithNumber("limit", mcp.Description("Maximum number of results (default: 10, max: 50)")),
+			),
+		)
+	}
+
+	// Register connectivity troubleshooting tool
+	s.addTool(
+		mcp.NewTool("diagnose-connectivity",
+			mcp.WithDescription("Find out why an EC2 instance port cannot be reached: checks the instance state, public IP, internet gateway, "+
+				"network ACL in both directions, security groups and route table, and reports the hop that blocks traffic"),
+			mcp.WithString("instanceId", mcp.Description("EC2 instance ID to connect to"), mcp.Required()),
+			mcp.WithNumber("port", mcp.Description("Destination port, e.g. 443"), mcp.Required()),
+			mcp.WithString("protocol", mcp.Description("tcp or udp (default: tcp)")),
+			mcp.WithString("source", mcp.Description("Client IPv4 address or CIDR block (default: 0.0.0.0/0, anywhere on the internet)")),
+			mcp.WithBoolean("useReachabilityAnalyzer", mcp.Description("Also trace internet sources with VPC Reachability Analyzer (billed per analysis, takes up to 2 minutes)")),
+		),
+	)
+
+	// Register EBS volume encryption tool
+	s.addTool(
+		mcp.NewTool("encrypt-volume",
+			mcp.WithDescription("Replace an unencrypted EBS volume with an encrypted copy (requires the attached instance to be stopped)"),
+			mcp.WithString("volumeId", mcp.Description("EBS volume ID to encrypt"), mcp.Required()),
+			mcp.WithString("kmsKeyId", mcp.Description("KMS key ID or ARN to encrypt with (defaults to the account EBS key)")),
+			mcp.WithBoolean("confirm", mcp.Description("Set to true to execute after reviewing the plan and downtime warnings")),
+			mcp.WithBoolean("overrideErrorBudget", mcp.Description("Run even though the affected service has nearly spent its error budget")),
+		),
+	)
+
+	// Register secret rotation tool
+	s.addTool(
+		mcp.NewTool("rotate-secret",
+			mcp.WithDescription("Rotate a Secrets Manager secret now with its configured rotation function, e.g. after a failed or overdue rotation. "+
+				"Returns the rotation plan until called with confirm=true"),
+			mcp.WithString("secretId", mcp.Description("Secret name or ARN, e.g. prod/db-password"), mcp.Required()),
+			mcp.WithBoolean("confirm", mcp.Description("Set to true to start the rotation after reviewing the warnings")),
+		),
+	)
+
+	// Register API Gateway stage tools
+	s.addTool(
+		mcp.NewTool("deploy-api-stage",
+			mcp.WithDescription("Deploy the current configuration of an API Gateway API to a stage, e.g. to push a fixed integration live. "+
+				"Returns the plan with the deployment being replaced until called with confirm=true"),
+			mcp.WithString("api", mcp.Description("API ID or name"), mcp.Required()),
+			mcp.WithString("stage", mcp.Description("Stage name, e.g. prod"), mcp.Required()),
+			mcp.WithString("description", mcp.Description("Description of the deployment, e.g. the incident it fixes")),
+			mcp.WithBoolean("confirm", mcp.Description("Set to true to deploy after reviewing the plan")),
+		),
+	)
+
+	s.addTool(
+		mcp.NewTool("update-api-throttling",
+			mcp.WithDescription("Set the default rate and burst limit of an API Gateway stage, e.g. to shed load from a struggling backend "+
+				"or to stop rejecting legitimate traffic with 429s. Limits set on single methods or routes are kept"),
+			mcp.WithString("api", mcp.Description("API ID or name"), mcp.Required()),
+			mcp.WithString("stage", mcp.Description("Stage name, e.g. prod"), mcp.Required()),
+			mcp.WithNumber("rateLimit", mcp.Description("Steady-state requests per second"), mcp.Required()),
+			mcp.WithNumber("burstLimit", mcp.Description("Requests allowed in a burst above the rate"), mcp.Required()),
+		),
+	)
+
+	// Register quota increase tool
+	s.addTool(
+		mcp.NewTool("request-quota-increase",
+			mcp.WithDescription("Request an increase of an adjustable service quota, e.g. when instances fail to launch on the vCPU limit. "+
+				"Returns the plan with the current value and usage until called with confirm=true"),
+			mcp.WithString("serviceCode", mcp.Description("Service code, e.g. ec2, lambda or vpc"), mcp.Required()),
+			mcp.WithString("quotaCode", mcp.Description("Quota code, e.g. L-1216C47A, see quota_code in aws://servicequotas/quotas"), mcp.Required()),
+			mcp.WithNumber("desiredValue", mcp.Description("New value of the quota, above the current one"), mcp.Required()),
+			mcp.WithBoolean("confirm", mcp.Description("Set to true to submit the request after reviewing the plan")),
+		),
+	)
+
+	// Register EFS throughput mode tool
+	s.addTool(
+		mcp.NewTool("update-efs-throughput",
+			mcp.WithDescription("Switch the throughput mode of an EFS file system, e.g. to provisioned or elastic when it runs out of burst credits. "+
+				"Returns the plan with the current mode and burst credits until called with confirm=true"),
+			mcp.WithString("fileSystemId", mcp.Description("File system ID, e.g. fs-0123456789abcdef0"), mcp.Required()),
+			mcp.WithString("throughputMode", mcp.Description("New throughput mode: bursting, provisioned or elastic"), mcp.Required()),
+			mcp.WithNumber("provisionedThroughputMibps", mcp.Description("Throughput in MiB/s, required for provisioned mode")),
+			mcp.WithBoolean("confirm", mcp.Description("Set to true to apply the change after reviewing the plan")),
+		),
+	)
+
+	// Register AWS Backup on-demand backup tool
+	s.addTool(
+		mcp.NewTool("start-on-demand-backup",
+			mcp.WithDescription("Start an AWS Backup job of a resource, e.g. an EBS volume, RDS instance, EFS file system or DynamoDB table, "+
+				"to snapshot it before changing it. Follow the job in aws://backup/resources/{resourceArn}"),
+			mcp.WithString("resourceArn", mcp.Description("ARN of the resource to back up"), mcp.Required()),
+			mcp.WithString("backupVaultName", mcp.Description("Backup vault to store the recovery point in (default: backup.vault_name)")),
+			mcp.WithString("iamRoleArn", mcp.Description("IAM role AWS Backup assumes to create the backup (default: backup.role_arn)")),
+			mcp.WithNumber("deleteAfterDays", mcp.Description("Delete the recovery point after this many days (default: keep it)")),
+		),
+	)
+
+	// Register Windows password tool
+	s.addTool(
+		mcp.NewTool("get-windows-password",
+			mcp.WithDescription("Decrypt the Administrator password EC2 generated when a Windows instance launched, for RDP access during an incident. "+
+				"Uses the private key of the instance's key pair from windows.key_directory; calls are audited. "+
+				"Returns the plan for review unless confirm=true, and read-only roles need an elevation"),
+			mcp.WithString("instanceId", mcp.Description("Windows instance ID, e.g. i-0123456789abcdef0"), mcp.Required()),
+			mcp.WithBoolean("confirm", mcp.Description("Set to true to reveal the password after reviewing the plan")),
+		),
+	)
+
+	// Register console diagnostics tools
+	s.addTool(
+		mcp.NewTool("get-instance-console-output",
+			mcp.WithDescription("Get the last lines of the serial console output of an EC2 instance, to diagnose an instance that fails to boot or "+
+				"whose status checks fail while it is unreachable. Known boot failures such as kernel panics or emergency mode are pointed out"),
+			mcp.WithString("instanceId", mcp.Description("EC2 instance ID, e.g. i-0123456789abcdef0"), mcp.Required()),
+			mcp.WithNumber("tailLines", mcp.Description("Number of last lines to return (default: 200)")),
+			mcp.WithBoolean("latest", mcp.Description("Get the output as of now instead of the snapshot taken at the last boot; Nitro instances only (default: false)")),
+		),
+	)
+	s.addTool(
+		mcp.NewTool("get-instance-screenshot",
+			mcp.WithDescription("Capture a screenshot of the console of a running EC2 instance, returned as an image, "+
+				"e.g. to see a Windows instance stuck on an update or boot screen"),
+			mcp.WithString("instanceId", mcp.Description("EC2 instance ID, e.g. i-0123456789abcdef0"), mcp.Required()),
+		),
+	)
+
+	// Register Kinesis scaling tool
+	s.addTool(
+		mcp.NewTool("update-shard-count",
+			mcp.WithDescription("Change the number of shards of a provisioned Kinesis stream, e.g. when writes are throttled during a traffic spike. "+
+				"One update can at most double or halve the shards; the stream stays writable while it reshards"),
+			mcp.WithString("stream", mcp.Description("Kinesis stream name"), mcp.Required()),
+			mcp.WithNumber("targetShardCount", mcp.Description("Number of open shards after scaling, see suggested_shard_count in aws://kinesis/streams"), mcp.Required()),
+		),
+	)
+
+	// Register Step Functions execution tools
+	s.addTool(
+		mcp.NewTool("start-execution",
+			mcp.WithDescription("Start a new execution of a Step Functions state machine, e.g. to rerun a workflow after its failure was fixed"),
+			mcp.WithString("stateMachine", mcp.Description("State machine name or ARN"), mcp.Required()),
+			mcp.WithString("input", mcp.Description("Execution input as a JSON document (default: {})")),
+			mcp.WithString("name", mcp.Description("Execution name, unique for 90 days; reusing a name makes the call idempotent (default: generated)")),
+		),
+	)
+
+	s.addTool(
+		mcp.NewTool("redrive-execution",
+			mcp.WithDescription("Redrive a failed, timed out or aborted standard execution from the state that did not succeed, keeping the results of the states that did"),
+			mcp.WithString("executionArn", mcp.Description("ARN of the execution to redrive"), mcp.Required()),
+		),
+	)
+
+	// Register traffic capture tool
+	s.addTool(
+		mcp.NewTool("capture-traffic",
+			mcp.WithDescription("Record packet headers on an instance through SSM for a short time, e.g. to see which peers a service talks to or "+
+				"whether SYNs get answers. Payloads are cut off, duration and size are capped, and the pcap is uploaded to capture.bucket; "+
+				"returns a time-limited download link and, on Linux, the busiest flows. Needs tcpdump and curl on Linux and pktmon "+
+				"(Windows Server 2019 or later) on Windows. Returns the plan for review unless confirm=true."),
+			mcp.WithString("instanceId", mcp.Description("Instance to capture on, e.g. i-0123456789abcdef0"), mcp.Required()),
+			mcp.WithString("filter", mcp.Description("tcpdump filter expression, e.g. tcp port 443 and host 10.0.1.5 (default: all traffic). "+
+				"On Windows only tcp, udp, icmp, port N and host IP joined by and are supported")),
+			mcp.WithNumber("durationSeconds", mcp.Description("How long to capture, at most 120 seconds or capture.max_duration (default: 30)")),
+			mcp.WithNumber("maxSizeMB", mcp.Description("Stop once the pcap reaches this size, at most 100 MB or capture.max_size_mb (default: 10)")),
+			mcp.WithString("interface", mcp.Description("Network interface, e.g. eth0 (default: any); Windows captures always use every adapter")),
+			mcp.WithBoolean("confirm", mcp.Description("Set to true to capture after reviewing the plan")),
+		),
+	)
+
+	// Register patch baseline tool
+	s.addTool(
+		mcp.NewTool("run-patch-baseline",
+			mcp.WithDescription("Run AWS-RunPatchBaseline on SSM managed instances to scan for or install missing patches. "+
+				"Installs are refused on instances a maintenance window patches unless ignoreMaintenanceWindow=true, "+
+				"and return the plan until called with confirm=true"),
+			mcp.WithArray("instanceIds", mcp.Description("Instance IDs to patch, at most 50"), mcp.Required(), mcp.WithStringItems()),
+			mcp.WithString("operation", mcp.Description("Scan to report compliance or Install to install missing patches (default: Scan)")),
+			mcp.WithBoolean("reboot", mcp.Description("Reboot instances when a patch requires it (default: true)")),
+			mcp.WithBoolean("ignoreMaintenanceWindow", mcp.Description("Install now even on instances a maintenance window patches")),
+			mcp.WithBoolean("confirm", mcp.Description("Set to true to install after reviewing the plan")),
+			mcp.WithBoolean("overrideErrorBudget", mcp.Description("Run even though the affected service has nearly spent its error budget")),
+		),
+	)
+
+	// Register SSM parameter editing tool
+	s.addTool(
+		mcp.NewTool("put-parameter",
+			mcp.WithDescription("Create or update an SSM Parameter Store parameter under parameters.path_prefix, e.g. to put back a configuration value that drifted. "+
+				"Returns the current and new value for review until called with confirm=true"),
+			mcp.WithString("name", mcp.Description("Full parameter name, e.g. /app/prod/db-host"), mcp.Required()),
+			mcp.WithString("value", mcp.Description("New value; StringList values are comma-separated"), mcp.Required()),
+			mcp.WithString("type", mcp.Description("String, StringList or SecureString (default: the current type, or String for new parameters)")),
+			mcp.WithString("description", mcp.Description("Parameter description, e.g. the ticket or reason")),
+			mcp.WithBoolean("confirm", mcp.Description("Set to true to write the parameter after reviewing the plan")),
+		),
+	)
+
+	// Register security group rule editing tools
+	s.addTool(
+		mcp.NewTool("authorize-security-group-ingress",
+			mcp.WithDescription("Add an inbound rule to a security group, e.g. to open a blocked port; rules open to 0.0.0.0/0 or ::/0 need confirm=true"),
+			mcp.WithString("groupId", mcp.Description("Security group ID, e.g. sg-0123456789abcdef0"), mcp.Required()),
+			mcp.WithString("protocol", mcp.Description("tcp, udp, icmp or all (default: tcp)")),
+			mcp.WithNumber("port", mcp.Description("Single port to open, e.g. 443")),
+			mcp.WithNumber("fromPort", mcp.Description("First port of a range")),
+			mcp.WithNumber("toPort", mcp.Description("Last port of a range (default: fromPort)")),
+			mcp.WithString("cidr", mcp.Description("Source IPv4 or IPv6 CIDR block or IP address, e.g. 203.0.113.0/24")),
+			mcp.WithString("sourceGroupId", mcp.Description("Source security group instead of a CIDR block")),
+			mcp.WithString("description", mcp.Description("Rule description, e.g. the ticket or reason")),
+			mcp.WithBoolean("confirm", mcp.Description("Set to true to open the rule to the internet after reviewing the warnings")),
+		),
+	)
+	s.addTool(
+		mcp.NewTool("revoke-security-group-ingress",
+			mcp.WithDescription("Remove an inbound rule from a security group; protocol, ports and source must match the rule exactly"),
+			mcp.WithString("groupId", mcp.Description("Security group ID, e.g. sg-0123456789abcdef0"), mcp.Required()),
+			mcp.WithString("protocol", mcp.Description("tcp, udp, icmp or all (default: tcp)")),
+			mcp.WithNumber("port", mcp.Description("Single port of the rule, e.g. 22")),
+			mcp.WithNumber("fromPort", mcp.Description("First port of the rule's range")),
+			mcp.WithNumber("toPort", mcp.Description("Last port of the rule's range (default: fromPort)")),
+			mcp.WithString("cidr", mcp.Description("Source CIDR block of the rule, e.g. 0.0.0.0/0")),
+			mcp.WithString("sourceGroupId", mcp.Description("Source security group of the rule instead of a CIDR block")),
+		),
+	)
+
+	// Register EBS snapshot and volume modification tools
+	s.addTool(
+		mcp.NewTool("create-ebs-snapshot",
+			mcp.WithDescription("Start a snapshot of an EBS volume; the volume's tags are copied to the snapshot"),
+			mcp.WithString("volumeId", mcp.Description("EBS volume ID to snapshot"), mcp.Required()),
+			mcp.WithString("description", mcp.Description("Snapshot description (default: Manual snapshot of the volume)")),
+		),
+	)
+	s.addTool(
+		mcp.NewTool("delete-ebs-snapshot",
+			mcp.WithDescription("Delete an EBS snapshot (irreversible; snapshots used by an AMI cannot be deleted)"),
+			mcp.WithString("snapshotId", mcp.Description("EBS snapshot ID to delete"), mcp.Required()),
+			mcp.WithBoolean("confirm", mcp.Description("Set to true to delete after reviewing the snapshot details")),
+		),
+	)
+	s.addTool(
+		mcp.NewTool("modify-ebs-volume",
+			mcp.WithDescription("Grow an EBS volume or change its type, IOPS or throughput while it stays in use (once every 6 hours per volume)"),
+			mcp.WithString("volumeId", mcp.Description("EBS volume ID to modify"), mcp.Required()),
+			mcp.WithNumber("sizeGiB", mcp.Description("New size in GiB, at least the current size")),
+			mcp.WithString("volumeType", mcp.Description("New volume type: gp3, gp2, io2, io1, st1, sc1 or standard")),
+			mcp.WithNumber("iops", mcp.Description("Provisioned IOPS for gp3, io1 and io2 volumes")),
+			mcp.WithNumber("throughput", mcp.Description("Throughput in MiB/s for gp3 volumes")),
+		),
+	)
+
+	// Register access key deactivation tool
+	s.addTool(
+		mcp.NewTool("deactivate-access-key",
+			mcp.WithDescription("Deactivate an IAM user access key (the key is kept and can be re-activated)"),
+			mcp.WithString("userName", mcp.Description("IAM user that owns the access key"), mcp.Required()),
+			mcp.WithString("accessKeyId", mcp.Description("Access key ID to deactivate"), mcp.Required()),
+			mcp.WithBoolean("confirm", mcp.Description("Set to true to execute after reviewing the plan and warnings")),
+		),
+	)
+
+	// Register CloudWatch metrics tool
+	s.addTool(
+		mcp.NewTool("get-cloudwatch-metrics",
+			mcp.WithDescription("Get a CloudWatch metric statistic as a time series with min, max, average and last values, flagging datapoints that stray from the learned baseline"),
+			mcp.WithString("namespace", mcp.Description("Metric namespace, e.g. AWS/EC2, AWS/RDS or AWS/ApplicationELB"), mcp.Required()),
+			mcp.WithString("metricName", mcp.Description("Metric name, e.g. CPUUtilization"), mcp.Required()),
+			mcp.WithObject("dimensions", mcp.Description("Dimension name/value pairs, e.g. {\"InstanceId\": \"i-0abc123\"}; a string such as InstanceId=i-0abc123 is also accepted")),
+			mcp.WithString("statistic", mcp.Description("Average (default), Sum, Minimum, Maximum, SampleCount or a percentile such as p99")),
+			mcp.WithNumber("period", mcp.Description("Seconds per datapoint: 1, 5, 10, 30 or a multiple of 60 (default: about 60 datapoints over the window)")),
+			mcp.WithString("since", mcp.Description("How far back to look, e.g. 15m, 6h or 2d (default 1h); ignored when start is set")),
+			mcp.WithString("start", mcp.Description("Window start as RFC3339, Unix seconds or now-<duration>")),
+			mcp.WithString("end", mcp.Description("Window end as RFC3339, Unix seconds or now-<duration> (default now)")),
+		),
+	)
+
+	// Register metric baseline tools for the anomaly detector
+	s.addTool(
+		mcp.NewTool("get-metric-baseline",
+			mcp.WithDescription("Show the baselines the anomaly detector has learned for a resource: sample count, mean, spread and the mean for each hour of the week"),
+			mcp.WithString("resource", mcp.Description("Resource ID such as i-0abc123, or its dimensions such as InstanceId=i-0abc123"), mcp.Required()),
+			mcp.WithString("metric", mcp.Description("Only this metric, as listed by this tool, e.g. AWS/EC2/CPUUtilization Average 300s")),
+		),
+	)
+	s.addTool(
+		mcp.NewTool("reset-metric-baseline",
+			mcp.WithDescription("Discard learned baselines of a resource, e.g. after an incident or load test was learned as normal, so they are relearned from new datapoints"),
+			mcp.WithString("resource", mcp.Description("Resource ID such as i-0abc123, or its dimensions such as InstanceId=i-0abc123"), mcp.Required()),
+			mcp.WithString("metric", mcp.Description("Only reset this metric (default: every metric of the resource)")),
+			mcp.WithBoolean("confirm", mcp.Description("Set to true to execute after reviewing the plan and warnings")),
+		),
+	)
+
+	// Register log tools (CloudWatch Logs, Loki or Elasticsearch, per configuration)
+	s.addTool(
+		mcp.NewTool("query-logs",
+			mcp.WithDescription("Search logs in the configured backend (CloudWatch Logs, Loki or Elasticsearch) and return matching entries in time order"),
+			mcp.WithString("source", mcp.Description("Log group (CloudWatch), LogQL stream selector such as {app=\"api\"} (Loki) or index pattern (Elasticsearch); defaults to logs.default_source")),
+			mcp.WithString("filter", mcp.Description("CloudWatch filter pattern, Loki line filter text or Elasticsearch query_string")),
+			mcp.WithString("since", mcp.Description("How far back to search, e.g. 15m, 6h or 2d (default 1h); ignored when start is set")),
+			mcp.WithString("start", mcp.Description("Window start as RFC3339, Unix seconds or now-<duration>")),
+			mcp.WithString("end", mcp.Description("Window end as RFC3339, Unix seconds or now-<duration> (default now)")),
+			mcp.WithNumber("limit", mcp.Description("Maximum number of entries to return (default 100)")),
+		),
+	)
+
+	s.addTool(
+		mcp.NewTool("summarize-logs",
+			mcp.WithDescription("Group logs into recurring patterns with counts per level, so large volumes can be understood at a glance"),
+			mcp.WithString("source", mcp.Description("Log group (CloudWatch), LogQL stream selector (Loki) or index pattern (Elasticsearch); defaults to logs.default_source")),
+			mcp.WithString("filter", mcp.Description("CloudWatch filter pattern, Loki line filter text or Elasticsearch query_string")),
+			mcp.WithString("since", mcp.Description("How far back to look, e.g. 15m, 6h or 2d (default 1h); ignored when start is set")),
+			mcp.WithString("start", mcp.Description("Window start as RFC3339, Unix seconds or now-<duration>")),
+			mcp.WithString("end", mcp.Description("Window end as RFC3339, Unix seconds or now-<duration> (default now)")),
+			mcp.WithNumber("limit", mcp.Description("Maximum number of entries to analyse (default 2000)")),
+			mcp.WithNumber("top", mcp.Description("Number of patterns to return (default 10)")),
+			mcp.WithBoolean("narrative", mcp.Description("Also have the server's Bedrock model explain the patterns in a few sentences; needs summaries.enabled (default: false)")),
+		),
+	)
+
+	// Register CloudWatch Logs Insights tool
+	s.addTool(
+		mcp.NewTool("query-cloudwatch-logs",
+			mcp.WithDescription("Run a CloudWatch Logs Insights query across log groups and return the result rows, e.g. to count errors by message or find the slowest requests"),
+			mcp.WithString("query", mcp.Description("Logs Insights query, e.g. fields @timestamp, @message | filter @message like /ERROR/ | stats count() by bin(5m)"), mcp.Required()),
+			mcp.WithArray("logGroups", mcp.Description("Log group names to query (default: logs.default_source when the log backend is CloudWatch)"), mcp.WithStringItems()),
+			mcp.WithString("since", mcp.Description("How far back to query, e.g. 15m, 6h or 2d (default 1h); ignored when start is set")),
+			mcp.WithString("start", mcp.Description("Window start as RFC3339, Unix seconds or now-<duration>")),
+			mcp.WithString("end", mcp.Description("Window end as RFC3339, Unix seconds or now-<duration> (default now)")),
+			mcp.WithNumber("limit", mcp.Description("Maximum number of rows to return (default 100, max 10000)")),
+			mcp.WithNumber("timeout", mcp.Description("Seconds to wait for the query before stopping it (default logs.insights_timeout, max 900)")),
+		),
+	)
+
+	// Register Athena query tool
+	s.addTool(
+		mcp.NewTool("run-athena-query",
+			mcp.WithDescription("Run a read-only SQL query in Athena, e.g. over the Cost and Usage Report or VPC flow logs. "+
+				"Queries are stopped when they scan more than the configured limit, and results are capped at athena.max_rows rows."),
+			mcp.WithString("sql", mcp.Description("SELECT, WITH, SHOW, DESCRIBE or EXPLAIN statement; filter on partition columns to limit the data scanned"), mcp.Required()),
+			mcp.WithString("database", mcp.Description("Glue database to query (default athena.database)")),
+			mcp.WithString("workgroup", mcp.Description("Athena workgroup (default athena.workgroup)")),
+			mcp.WithString("outputLocation", mcp.Description("S3 URI for the query results when the workgroup does not set one (default athena.output_location)")),
+			mcp.WithNumber("maxRows", mcp.Description("Maximum number of rows to return (default and max athena.max_rows)")),
+			mcp.WithNumber("maxScanGB", mcp.Description("Stop the query after scanning this many GB; can only lower the configured limit")),
+			mcp.WithNumber("timeout", mcp.Description("Seconds to wait for the query before stopping it (default athena.timeout, max 1800)")),
+		),
+	)
+
+	// Register asynchronous Athena query tools
+	s.addTool(
+		mcp.NewTool("start-athena-query",
+			mcp.WithDescription("Start a read-only SQL query in Athena without waiting for it, for queries that take longer than run-athena-query waits. "+
+				"Poll it with get-athena-query and read the rows with get-athena-query-results"),
+			mcp.WithString("sql", mcp.Description("SELECT, WITH, SHOW, DESCRIBE or EXPLAIN statement; filter on partition columns to limit the data scanned"), mcp.Required()),
+			mcp.WithString("database", mcp.Description("Glue database to query (default athena.database)")),
+			mcp.WithString("workgroup", mcp.Description("Athena workgroup (default athena.workgroup)")),
+			mcp.WithString("outputLocation", mcp.Description("S3 URI for the query results when the workgroup does not set one (default athena.output_location)")),
+		),
+	)
+	s.addTool(
+		mcp.NewTool("get-athena-query",
+			mcp.WithDescription("Get the state, data scanned and cost of an Athena query; queries over the configured scan limit are stopped"),
+			mcp.WithString("queryExecutionId", mcp.Description("Query execution ID returned by start-athena-query"), mcp.Required()),
+		),
+	)
+	s.addTool(
+		mcp.NewTool("get-athena-query-results",
+			mcp.WithDescription("Get the rows of an Athena query that SUCCEEDED, capped at athena.max_rows rows"),
+			mcp.WithString("queryExecutionId", mcp.Description("Query execution ID returned by start-athena-query"), mcp.Required()),
+			mcp.WithNumber("maxRows", mcp.Description("Maximum number of rows to return (default and max athena.max_rows)")),
+		),
+	)
+
+	// Register Redshift query tools
+	s.addTool(
+		mcp.NewTool("start-redshift-query",
+			mcp.WithDescription("Start a read-only SQL query on a Redshift cluster or Serverless workgroup with the Data API. "+
+				"Poll it with get-redshift-query and read the rows with get-redshift-query-results"),
+			mcp.WithString("sql", mcp.Description("A single SELECT, WITH, SHOW or EXPLAIN statement"), mcp.Required()),
+			mcp.WithString("clusterId", mcp.Description("Provisioned cluster to query (default redshift.cluster_id)")),
+			mcp.WithString("workgroup", mcp.Description("Serverless workgroup to query instead of a cluster (default redshift.workgroup)")),
+			mcp.WithString("database", mcp.Description("Database to query (default redshift.database)")),
+		),
+	)
+	s.addTool(
+		mcp.NewTool("get-redshift-query",
+			mcp.WithDescription("Get the state, duration and row count of a Redshift query, with the error of a failed one"),
+			mcp.WithString("statementId", mcp.Description("Statement ID returned by start-redshift-query"), mcp.Required()),
+		),
+	)
+	s.addTool(
+		mcp.NewTool("get-redshift-query-results",
+			mcp.WithDescription("Get the rows of a FINISHED Redshift query, capped at redshift.max_rows rows"),
+			mcp.WithString("statementId", mcp.Description("Statement ID returned by start-redshift-query"), mcp.Required()),
+			mcp.WithNumber("maxRows", mcp.Description("Maximum number of rows to return (default and max redshift.max_rows)")),
+		),
+	)
+
+	// Register postmortem drafting tool
+	s.addTool(
+		mcp.NewTool("draft-postmortem",
+			mcp.WithDescription("Assemble a postmortem draft in markdown for an incident window from CloudWatch alarm history, CloudTrail changes and the audit log of actions taken through this server"),
+			mcp.WithString("start", mcp.Description("Incident start as RFC3339, Unix seconds or now-<duration>"), mcp.Required()),
+			mcp.WithString("end", mcp.Description("Incident end as RFC3339, Unix seconds or now-<duration> (default now)")),
+			mcp.WithString("title", mcp.Description("Incident title (default: the start time)")),
+			mcp.WithNumber("maxEvents", mcp.Description("Maximum events taken from each source (default 200, max 1000)")),
+		),
+	)
+
+	// Register what-if simulation tool
+	s.addTool(
+		mcp.NewTool("simulate-action",
+			mcp.WithDescription("Predict the effects of an action on load balancer targets, availability and cost without executing it"),
+			mcp.WithString("action", mcp.Description("Action to simulate: create-ec2-instance, start-ec2-instance, stop-ec2-instance, terminate-ec2-instance or resize-ec2-instance"), mcp.Required()),
+			mcp.WithString("instanceId", mcp.Description("EC2 instance ID the action applies to (all actions except create-ec2-instance)")),
+			mcp.WithString("instanceType", mcp.Description("Instance type to create or resize to (create-ec2-instance and resize-ec2-instance)")),
+		),
+	)
+
+	// Register approval queue tools (restricted to admin roles)
+	s.addTool(
+		mcp.NewTool("approve-action",
+			mcp.WithDescription("Approve a queued action and execute it (admin roles only)"),
+			mcp.WithString("requestId", mcp.Description("ID of the pending approval request"), mcp.Required()),
+			mcp.WithString("note", mcp.Description("Optional note recorded with the decision")),
+		),
+	)
+
+	s.addTool(
+		mcp.NewTool("reject-action",
+			mcp.WithDescription("Reject a queued action without executing it (admin roles only)"),
+			mcp.WithString("requestId", mcp.Description("ID of the pending approval request"), mcp.Required()),
+			mcp.WithString("reason", mcp.Description("Why the action was rejected")),
+		),
+	)
+
+	// Register time-boxed elevation tools for read-only roles
+	s.addTool(
+		mcp.NewTool("request-elevation",
+			mcp.WithDescription("Ask for write access for this session of a read-only role for a limited number of minutes, like a break-glass account. "+
+				"The request needs approval at the MCP client or from an admin with approve-action; the session reverts to read-only when the time is up."),
+			mcp.WithNumber("minutes", mcp.Description("How long write access is needed, up to the configured maximum"), mcp.Required()),
+			mcp.WithString("reason", mcp.Description("Why write access is needed, e.g. restart the stuck order workers for INC-123"), mcp.Required()),
+		),
+	)
+
+	s.addTool(
+		mcp.NewTool("end-elevation",
+			mcp.WithDescription("Give up the write access granted with request-elevation before it expires"),
+		),
+	)
+
+	// Register alert acknowledgment, snooze and suppression tools
+	s.addTool(
+		mcp.NewTool("acknowledge-alert",
+			mcp.WithDescription("Acknowledge a CloudWatch alarm as a known issue; it stays listed but marked until its state changes"),
+			mcp.WithString("alarmName", mcp.Description("Name of the alarm to acknowledge"), mcp.Required()),
+			mcp.WithString("reason", mcp.Description("Why the alarm is known, e.g. the incident or ticket tracking it"), mcp.Required()),
+		),
+	)
+	s.addTool(
+		mcp.NewTool("snooze-alert",
+			mcp.WithDescription("Hide a CloudWatch alarm from aws://cloudwatch/alarms for a while (up to 30 days)"),
+			mcp.WithString("alarmName", mcp.Description("Name of the alarm to snooze"), mcp.Required()),
+			mcp.WithString("duration", mcp.Description("How long to snooze, e.g. 30m, 4h or 2d"), mcp.Required()),
+			mcp.WithString("reason", mcp.Description("Why the alarm can be ignored for now"), mcp.Required()),
+		),
+	)
+	s.addTool(
+		mcp.NewTool("suppress-alert",
+			mcp.WithDescription("Hide a CloudWatch alarm from aws://cloudwatch/alarms until it is unsuppressed, for alarms that are expected to fire"),
+			mcp.WithString("alarmName", mcp.Description("Name of the alarm to suppress"), mcp.Required()),
+			mcp.WithString("reason", mcp.Description("Why the alarm is expected or irrelevant"), mcp.Required()),
+			mcp.WithString("duration", mcp.Description("Optional expiry, e.g. 90d (default: until unsuppressed)")),
+		),
+	)
+	s.addTool(
+		mcp.NewTool("unsuppress-alert",
+			mcp.WithDescription("Lift an acknowledgment, snooze or suppression so the alarm is listed normally again"),
+			mcp.WithString("alarmName", mcp.Description("Name of the silenced alarm"), mcp.Required()),
+		),
+	)
+
+	// Register resource ignore tools
+	s.addTool(
+		mcp.NewTool("ignore-resource",
+			mcp.WithDescription("Leave a resource out of anomaly detection, find-orphans and the daily digest, e.g. a volume kept for forensics or an instance under load test. "+
+				"Ignored resources are listed in aws://resources/ignored"),
+			mcp.WithString("resource", mcp.Description("ID or ARN of the resource, e.g. i-0123456789abcdef0 or vol-0123456789abcdef0"), mcp.Required()),
+			mcp.WithString("reason", mcp.Description("Why the resource can be ignored"), mcp.Required()),
+			mcp.WithString("duration", mcp.Description("Optional expiry, e.g. 7d (default: until unignored)")),
+		),
+	)
+	s.addTool(
+		mcp.NewTool("unignore-resource",
+			mcp.WithDescription("Bring an ignored resource back into anomaly detection, orphan reports and the daily digest"),
+			mcp.WithString("resource", mcp.Description("ID or ARN of the ignored resource"), mcp.Required()),
+		),
+	)
+
+	// Register resource note tools
+	s.addTool(
+		mcp.NewTool("add-note",
+			mcp.WithDescription("Attach a note to a resource, e.g. \"known flaky, vendor ticket #123\", so the context is there in later sessions. "+
+				"Notes are kept by the server, not as AWS tags, and are shown in the resource's detail view and aws://notes"),
+			mcp.WithString("resource", mcp.Description("ID or ARN of the resource, e.g. i-0123456789abcdef0"), mcp.Required()),
+			mcp.WithString("text", mcp.Description("The note"), mcp.Required()),
+			mcp.WithString("author", mcp.Description("Who wrote the note, recorded with the caller's role (default: the role alone)")),
+		),
+	)
+	s.addTool(
+		mcp.NewTool("remove-note",
+			mcp.WithDescription("Remove a note that no longer applies"),
+			mcp.WithString("noteId", mcp.Description("ID of the note, e.g. note-3"), mcp.Required()),
+		),
+	)
+}
+
+// registerPrompts offers the built-in runbook prompts, ready-made AIOps
+// workflows that MCP clients can list and fill in
+func (s *Server) registerPrompts() {
+	for _, prompt := range runbookPrompts {
+		s.mcpServer.AddPrompt(prompt.toMCP(), getPrompt)
+	}
+}
+
+// addTool registers a tool whose calls are dispatched through the ToolHandler.
+// Write tools get a changeTicket argument linking the change to a ticket.
+func (s *Server) addTool(tool mcp.Tool) {
+	if acceptsChangeTicket(tool.Name) {
+		if tool.InputSchema.Properties == nil {
+			tool.InputSchema.Properties = make(map[string]interface{})
+		}
+		tool.InputSchema.Properties["changeTicket"] = map[string]interface{}{
+			"type":        "string",
+			"description": s.toolHandler.changes.Describe(),
+		}
+	}
+	s.mcpServer.AddTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		arguments, ok := request.Params.Arguments.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("invalid arguments format")
+		}
+		return s.toolHandler.CallTool(ctx, tool.Name, arguments)
+	})
+}
+
+// refreshInventory updates the inventory snapshots in the background. Once
+// at startup it replaces the snapshots the previous run left, so reads go
+// live; with a refresh interval it then keeps every provider's snapshot up
+// to date until ctx is done.
+func (s *Server) refreshInventory(ctx context.Context) {
+	cache := s.resourceHandler.inventory
+	interval := s.config.Inventory.RefreshInterval
+	for {
+		for _, provider := range s.resourceHandler.clouds.Providers() {
+			uri := cloud.InstancesURI(provider)
+			if _, warm := cache.Warm(uri); interval <= 0 && !warm {
+				continue
+			}
+
+			started := time.Now()
+			refresh, err := cache.Update(ctx, provider, s.config.Inventory.FullRefreshInterval)
+			if refresh.DeltaErr != nil {
+				s.logger.WithError(refresh.DeltaErr).WithField("uri", uri).Warn("Failed to list changed instances, listing all of them")
+			}
+			if err != nil {
+				s.logger.WithError(err).WithField("uri", uri).Warn("Failed to refresh the inventory snapshot")
+				continue
+			}
+			s.logger.WithField("uri", uri).WithField("full", refresh.Full).WithField("changed", refresh.Changed).
+				WithField("gone", refresh.Gone).WithField("duration", time.Since(started).String()).
+				Debug("Refreshed the inventory snapshot")
+		}
+
+		if interval <= 0 {
+			return
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(interval):
+		}
+	}
+}
+
+// Start serves the MCP server on the configured transport until ctx is
+// cancelled or, on stdio, the client closes stdin
+func (s *Server) Start(ctx context.Context) error {
+	// Background services stop when Start returns; in-flight notifications,
+	// final digests and running chat calls are allowed to finish
+	background, stopBackground := context.WithCancel(ctx)
+	s.toolHandler.notifier.Start(background)
+	defer s.toolHandler.notifier.Wait()
+	defer stopBackground()
+
+	// Instance lists answered from the inventory snapshot are refreshed
+	go s.refreshInventory(background)
+
+	// Heartbeats stop with the server, so their absence raises the alarm
+	go s.heartbeat.Run(background)
+
+	// The last export runs on shutdown, before Start returns
+	exportDone := make(chan struct{})
+	go func() {
+		defer close(exportDone)
+		s.toolHandler.exporter.Run(background)
+	}()
+	defer func() {
+		stopBackground()
+		<-exportDone
+	}()
+
+	// The chat gateway shares the tool handler, so chat calls get the same checks
+	var gatewayDone chan struct{}
+	if s.config.ChatOps.Enabled {
+		gateway := chatops.NewSlackGateway(s.config.ChatOps, chatToolPolicy(), s.callToolAs, s.logger)
+		gatewayDone = make(chan struct{})
+		go func() {
+			defer close(gatewayDone)
+			if err := gateway.ListenAndServe(background); err != nil {
+				s.logger.WithError(err).Error("Slack chat gateway failed")
+			}
+		}()
+		defer func() {
+			stopBackground()
+			<-gatewayDone
+		}()
+	}
+
+	// The management API lets a control plane manage the server while it runs
+	if api := management.New(s.config.Management, s, s.logger); api != nil {
+		managementDone := make(chan struct{})
+		go func() {
+			defer close(managementDone)
+			if err := api.ListenAndServe(background); err != nil {
+				s.logger.WithError(err).Error("Management API failed")
+			}
+		}()
+		defer func() {
+			stopBackground()
+			<-managementDone
+		}()
+	}
+
+	var recorder *session.Recorder
+	if s.config.MCP.RecordDir != "" {
+		var err error
+		recorder, err = session.NewRecorder(s.config.MCP.RecordDir)
+		if err != nil {
+			return fmt.Errorf("failed to start session recording: %w", err)
+		}
+		defer recorder.Close()
+		s.logger.WithField("path", recorder.Path()).Info("Recording MCP session")
+	}
+
+	if s.config.MCP.Transport == config.MCPTransportHTTP {
+		return s.serveHTTP(ctx, recorder)
+	}
+	s.logger.Info("Starting MCP server message loop on stdio...")
+
+	// The peer carries the server's own requests to the client, such as
+	// approvals asked through elicitation, alongside the responses
+	peer := newClientPeer(func(message []byte) error {
+		_, err := os.Stdout.Write(append(message, '\n'))
+		return err
+	})
+
+	scanner := bufio.NewScanner(os.Stdin)
+	err := s.serveClient(ctx, transportStdio, "", peer, recorder, func() ([]byte, error) {
+		if !scanner.Scan() {
+			if err := scanner.Err(); err != nil {
+				return nil, err
+			}
+			return nil, io.EOF
+		}
+		return bytes.Clone(scanner.Bytes()), nil
+	})
+	switch {
+	case ctx.Err() != nil && err == ctx.Err():
+		s.logger.Info("Shutdown signal received, stopping server")
+		return err
+	case err != io.EOF:
+		s.logger.WithError(err).Error("Error reading from stdin")
+		return err
+	}
+
+	// Without an MCP client the server can still run as a chat gateway
+	if gatewayDone != nil {
+		s.logger.Info("stdin closed, the chat gateway keeps running until shutdown")
+		select {
+		case <-ctx.Done():
+		case <-gatewayDone:
+		}
+	}
+
+	return nil
+}
+
+// serveClient serves the messages of one client, read with next until it
+// fails, through the peer connected to the client. It returns ctx's error
+// once ctx is cancelled and io.EOF when the client is gone.
+func (s *Server) serveClient(ctx context.Context, transport, remote string, peer *clientPeer, recorder *session.Recorder, next func() ([]byte, error)) error {
+	conn := s.sessions.open(transport, remote, time.Now())
+	defer s.sessions.end(conn.id)
+	ctx = withConnection(withPeer(ctx, peer), conn)
+
+	// A tool call waiting for the client's answer to an elicitation must not
+	// hold up the messages that carry it, so with clients supporting
+	// elicitation tool calls run alongside the loop
+	var inflight sync.WaitGroup
+	defer inflight.Wait()
+
+	for {
+		message, err := next()
+		if err != nil {
+			return err
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if len(message) == 0 || peer.observe(message) {
+			continue
+		}
+
+		if peer.supportsElicitation() && isToolCall(message) {
+			inflight.Add(1)
+			go func() {
+				defer inflight.Done()
+				s.serveMessage(ctx, peer, recorder, message)
+			}()
+			continue
+		}
+		s.serveMessage(ctx, peer, recorder, message)
+	}
+}
+
+// serveMessage handles one JSON-RPC message from the client and writes the
+// response, recording both when the session is recorded
+func (s *Server) serveMessage(ctx context.Context, peer *clientPeer, recorder *session.Recorder, line []byte) {
+	started := time.Now()
+	responseBytes, err := s.HandleMessage(ctx, line)
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to marshal response")
+		return
+	}
+
+	if responseBytes != nil {
+		if err := peer.send(responseBytes); err != nil {
+			s.logger.WithError(err).Error("Failed to write response")
+		}
+	}
+
+	if recorder != nil {
+		s.record(recorder, line, responseBytes, started)
+	}
+}
+
+// record appends one exchange to the session recording. Secrets exchanged
+// with the client are left out.
+func (s *Server) record(recorder *session.Recorder, request, response []byte, started time.Time) {
+	duration := time.Since(started)
+	request, response = redactRecording(request, response)
+	if err := recorder.Record(request, response, started, duration); err != nil {
+		s.logger.WithError(err).Warn("Failed to record session entry")
+	}
+}
+
+// HandleMessage handles one JSON-RPC message and returns the encoded
+// response, nil for notifications. It is safe for concurrent use, so other
+// drivers than the stdio loop, such as load tests, can share the server.
+func (s *Server) HandleMessage(ctx context.Context, message []byte) ([]byte, error) {
+	key, ok := replayKey(s.instance+"/"+connectionFrom(ctx).id, message)
+	if !ok {
+		return s.handleMessage(ctx, message)
+	}
+
+	return s.replay(key, func() ([]byte, error) {
+		return s.handleMessage(ctx, message)
+	})
+}
+
+// replay runs a write call once for its idempotency key and returns the
+// stored response to the retries of the call
+func (s *Server) replay(key string, run func() ([]byte, error)) ([]byte, error) {
+	response, replayed, err := s.idempotency.Do(key, run)
+	if replayed {
+		s.logger.WithField("request", key).Warn("Duplicate request, returning the earlier response instead of running the tool again")
+	}
+	if err != nil && response != nil {
+		s.logger.WithError(err).Warn("Failed to save the idempotency cache")
+		return response, nil
+	}
+	return response, err
+}
+
+// handleMessage passes one JSON-RPC message to the MCP server
+func (s *Server) handleMessage(ctx context.Context, message []byte) ([]byte, error) {
+	response := s.mcpServer.HandleMessage(ctx, message)
+	if response == nil {
+		return nil, nil
+	}
+	return json.Marshal(response)
+}
+
+// replayKey returns the idempotency key of a call to a write tool in a
+// session. Clients number their requests per session, so the same ID and
+// payload in another session is a new call. Reads, notifications and calls
+// that only return a plan are run every time.
+func replayKey(session string, message []byte) (string, bool) {
+	var request struct {
+		ID     json.RawMessage `json:"id"`
+		Method string          `json:"method"`
+		Params json.RawMessage `json:"params"`
+	}
+	if err := json.Unmarshal(message, &request); err != nil || request.Method != string(mcp.MethodToolsCall) {
+		return "", false
+	}
+	if len(request.ID) == 0 || string(request.ID) == "null" {
+		return "", false
+	}
+
+	var params struct {
+		Name      string                 `json:"name"`
+		Arguments map[string]interface{} `json:"arguments"`
+	}
+	if err := json.Unmarshal(request.Params, &params); err != nil || !isMutating(params.Name, params.Arguments) {
+		return "", false
+	}
+	// Results holding secrets are not stored; asking again changes nothing
+	if len(secretFields[params.Name]) > 0 {
+		return "", false
+	}
+
+	key, err := idempotency.Key(session, request.ID, request.Params)
+	if err != nil {
+		return "", false
+	}
+	return key, true
+}
+
+// callToolAs runs a tool for a chat user with the given role. Results are
+// posted to the channel, so their secrets are redacted like in recordings.
+func (s *Server) callToolAs(ctx context.Context, user, role, tool string, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	result, err := s.toolHandler.CallTool(WithPrincipal(WithRole(ctx, role), "slack:"+user), tool, arguments)
+	redactResult(result, resultSecrets(tool, arguments))
+	return result, err
+}
+
+// chatToolPolicy tells the chat gateway which tools to offer when
+// chatops.tools is empty, the read-only ones, and which tools return secrets
+// that must not be posted to a channel
+func chatToolPolicy() chatops.ToolPolicy {
+	return chatops.ToolPolicy{
+		DefaultTools: slices.Sorted(maps.Keys(readOnlyTools)),
+		SecretTools:  slices.Sorted(maps.Keys(secretFields)),
+	}
 }
@@ -0,0 +1,288 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+
+	"aws-mcp-server/pkg/aws"
+	"aws-mcp-server/pkg/types"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const (
+	// quotasURI lists the used quotas of EC2, Lambda and VPC with their usage
+	quotasURI = "aws://servicequotas/quotas"
+	// quotaServiceTemplate is the URI template of all quotas of one service,
+	// by its service code, e.g. ec2
+	quotaServiceTemplate = "aws://servicequotas/quotas/{serviceCode}"
+	// quotaUtilizationWarning is the share of a quota in use from which it is
+	// reported at risk of exhaustion
+	quotaUtilizationWarning = 0.8
+)
+
+// quotaServices are the services whose quotas are exhausted most often during
+// incidents: vCPUs and Elastic IPs, concurrent executions, VPCs and interfaces
+var quotaServices = []string{"ec2", "lambda", "vpc"}
+
+// readQuotas lists the quotas of EC2, Lambda and VPC that are in use, those
+// closest to their limit first, with the increase requests still open
+func (h *ResourceHandler) readQuotas(ctx context.Context) (*mcp.ReadResourceResult, error) {
+	var quotas []types.ServiceQuota
+	var requests []types.QuotaIncreaseRequest
+	failed := map[string]string{}
+	for _, service := range quotaServices {
+		serviceQuotas, err := h.awsClient.ListServiceQuotas(ctx, service)
+		if err != nil {
+			failed[service] = err.Error()
+			continue
+		}
+		for _, quota := range serviceQuotas {
+			if quota.Usage != nil && *quota.Usage > 0 {
+				quotas = append(quotas, quota)
+			}
+		}
+
+		serviceRequests, err := h.awsClient.ListQuotaIncreaseRequests(ctx, service)
+		if err != nil {
+			failed[service] = err.Error()
+			continue
+		}
+		requests = append(requests, serviceRequests...)
+	}
+	if len(failed) == len(quotaServices) {
+		return nil, fmt.Errorf("failed to list service quotas: %s", failed[quotaServices[0]])
+	}
+
+	data := formatQuotas(quotas, openQuotaRequests(requests))
+	if len(failed) > 0 {
+		data["unavailable_services"] = failed
+	}
+
+	jsonData, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal service quotas data: %w", err)
+	}
+
+	return &mcp.ReadResourceResult{
+		Contents: []mcp.ResourceContents{
+			&mcp.TextResourceContents{
+				URI:      quotasURI,
+				MIMEType: "application/json",
+				Text:     string(jsonData),
+			},
+		},
+	}, nil
+}
+
+// readServiceQuotas returns all quotas of the service in the URI, whether or
+// not they are in use, with its open increase requests
+func (h *ResourceHandler) readServiceQuotas(ctx context.Context, req resourceRequest) (*mcp.ReadResourceResult, error) {
+	uri, service := req.URI, req.Param("serviceCode")
+	if service == "" {
+		return nil, fmt.Errorf("invalid service quotas URI %s, use %s", uri, quotaServiceTemplate)
+	}
+
+	quotas, err := h.awsClient.ListServiceQuotas(ctx, service)
+	if err != nil {
+		return nil, err
+	}
+	requests, err := h.awsClient.ListQuotaIncreaseRequests(ctx, service)
+	if err != nil {
+		return nil, err
+	}
+
+	data := formatQuotas(quotas, openQuotaRequests(requests))
+	data["service"] = service
+
+	jsonData, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal service quotas data: %w", err)
+	}
+
+	return &mcp.ReadResourceResult{
+		Contents: []mcp.ResourceContents{
+			&mcp.TextResourceContents{
+				URI:      uri,
+				MIMEType: "application/json",
+				Text:     string(jsonData),
+			},
+		},
+	}, nil
+}
+
+// formatQuotas orders the quotas by utilization, those without usage last by
+// name, and lists the ones at risk of exhaustion
+func formatQuotas(quotas []types.ServiceQuota, requests []types.QuotaIncreaseRequest) map[string]interface{} {
+	pending := make(map[string]types.QuotaIncreaseRequest, len(requests))
+	for _, request := range requests {
+		pending[request.ServiceCode+"/"+request.QuotaCode] = request
+	}
+
+	sorted := append([]types.ServiceQuota(nil), quotas...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		iUsage, jUsage := quotaUtilization(sorted[i]), quotaUtilization(sorted[j])
+		if iUsage != jUsage {
+			return iUsage > jUsage
+		}
+		return sorted[i].QuotaName < sorted[j].QuotaName
+	})
+
+	items := make([]map[string]interface{}, 0, len(sorted))
+	atRisk := []string{}
+	for _, quota := range sorted {
+		item := formatQuota(quota)
+		if request, ok := pending[quota.ServiceCode+"/"+quota.QuotaCode]; ok {
+			item["pending_increase"] = request.DesiredValue
+		}
+		if quota.Usage != nil && quotaUtilization(quota) >= quotaUtilizationWarning {
+			atRisk = append(atRisk, fmt.Sprintf("%s: %s", quota.ServiceCode, quota.QuotaName))
+		}
+		items = append(items, item)
+	}
+
+	openRequests := make([]map[string]interface{}, 0, len(requests))
+	for _, request := range requests {
+		openRequest := map[string]interface{}{
+			"id":            request.ID,
+			"service":       request.ServiceCode,
+			"quota_code":    request.QuotaCode,
+			"quota_name":    request.QuotaName,
+			"desired_value": request.DesiredValue,
+			"status":        request.Status,
+		}
+		if request.CaseID != "" {
+			openRequest["support_case"] = request.CaseID
+		}
+		openRequests = append(openRequests, openRequest)
+	}
+
+	return map[string]interface{}{
+		"total":          len(quotas),
+		"quotas_at_risk": len(atRisk),
+		"at_risk":        atRisk,
+		"usage_window":   fmt.Sprintf("peak of the last %d minutes", int(aws.QuotaUsageWindow.Minutes())),
+		"quotas":         items,
+		"open_requests":  openRequests,
+	}
+}
+
+// formatQuota formats one quota with its usage as a share of the limit
+func formatQuota(quota types.ServiceQuota) map[string]interface{} {
+	item := map[string]interface{}{
+		"service":    quota.ServiceCode,
+		"quota_code": quota.QuotaCode,
+		"name":       quota.QuotaName,
+		"value":      quota.Value,
+		"adjustable": quota.Adjustable,
+	}
+	if quota.Unit != "" && quota.Unit != "None" {
+		item["unit"] = quota.Unit
+	}
+	if quota.Usage != nil {
+		item["usage"] = *quota.Usage
+		if quota.Value > 0 {
+			item["utilization_percent"] = math.Round(quotaUtilization(quota) * 100)
+		}
+	}
+	return item
+}
+
+// quotaUtilization is the share of a quota in use, zero when the usage is not
+// known or the quota is zero
+func quotaUtilization(quota types.ServiceQuota) float64 {
+	if quota.Usage == nil || quota.Value <= 0 {
+		return 0
+	}
+	return *quota.Usage / quota.Value
+}
+
+// openQuotaRequests returns the increase requests AWS is still reviewing
+func openQuotaRequests(requests []types.QuotaIncreaseRequest) []types.QuotaIncreaseRequest {
+	open := []types.QuotaIncreaseRequest{}
+	for _, request := range requests {
+		if request.Open() {
+			open = append(open, request)
+		}
+	}
+	return open
+}
+
+// requestQuotaIncrease asks AWS to raise a quota, e.g. when instances fail to
+// launch on the vCPU limit. The plan is returned for confirmation first.
+func (h *ToolHandler) requestQuotaIncrease(ctx context.Context, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	service, _ := arguments["serviceCode"].(string)
+	quotaCode, _ := arguments["quotaCode"].(string)
+	if service == "" || quotaCode == "" {
+		return h.createErrorResponse("serviceCode and quotaCode are required")
+	}
+	desired, ok := arguments["desiredValue"].(float64)
+	if !ok {
+		return h.createErrorResponse("desiredValue is required")
+	}
+
+	quota, err := h.awsClient.GetServiceQuota(ctx, service, quotaCode)
+	if err != nil {
+		return h.createErrorResponse(fmt.Sprintf("failed to get service quota: %v", err))
+	}
+	requests, err := h.awsClient.ListQuotaIncreaseRequests(ctx, service)
+	if err != nil {
+		return h.createErrorResponse(fmt.Sprintf("failed to list quota increase requests: %v", err))
+	}
+	if err := validateQuotaIncrease(*quota, desired, requests); err != nil {
+		return h.createErrorResponse(err.Error())
+	}
+
+	if !isConfirmed(arguments) {
+		plan := formatQuota(*quota)
+		plan["desired_value"] = desired
+		warnings := []string{
+			"AWS reviews the request; large increases open a support case and can take days",
+			"An approved increase cannot be lowered again through the API",
+		}
+		if quota.GlobalQuota {
+			warnings = append(warnings, "The quota is global and applies to every region of the account")
+		}
+		return h.createConfirmationResponse("request-quota-increase", plan, warnings)
+	}
+
+	request, err := h.awsClient.RequestQuotaIncrease(ctx, service, quotaCode, desired)
+	if err != nil {
+		return h.createErrorResponse(err.Error())
+	}
+
+	data := map[string]interface{}{
+		"requestId":     request.ID,
+		"service":       service,
+		"quotaCode":     quotaCode,
+		"quotaName":     quota.QuotaName,
+		"previousValue": quota.Value,
+		"desiredValue":  desired,
+		"status":        request.Status,
+		"note":          fmt.Sprintf("Read %s/%s to follow the request", quotasURI, service),
+	}
+	if request.CaseID != "" {
+		data["supportCase"] = request.CaseID
+	}
+	return h.createSuccessResponse("Quota increase requested successfully", data)
+}
+
+// validateQuotaIncrease checks that a quota can be raised to the desired value
+// and is not already waiting on a request
+func validateQuotaIncrease(quota types.ServiceQuota, desired float64, requests []types.QuotaIncreaseRequest) error {
+	if !quota.Adjustable {
+		return fmt.Errorf("quota %s of %s is fixed and cannot be increased", quota.QuotaName, quota.ServiceCode)
+	}
+	if desired <= quota.Value {
+		return fmt.Errorf("desiredValue must be above the current value of %s, which is %g", quota.QuotaName, quota.Value)
+	}
+	for _, request := range requests {
+		if request.QuotaCode == quota.QuotaCode && request.Open() {
+			return fmt.Errorf("an increase of %s to %g is already %s (request %s)", quota.QuotaName, request.DesiredValue, request.Status, request.ID)
+		}
+	}
+	return nil
+}
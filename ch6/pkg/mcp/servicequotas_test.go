@@ -0,0 +1,87 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	"aws-mcp-server/internal/config"
+	"aws-mcp-server/internal/logging"
+	"aws-mcp-server/pkg/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func quotaUsage(value float64) *float64 {
+	return &value
+}
+
+func TestFormatQuotas(t *testing.T) {
+	data := formatQuotas([]types.ServiceQuota{
+		{ServiceCode: "vpc", QuotaCode: "L-F678F1CE", QuotaName: "VPCs per Region", Value: 5, Unit: "None", Adjustable: true, Usage: quotaUsage(2)},
+		{ServiceCode: "ec2", QuotaCode: "L-1216C47A", QuotaName: "Running On-Demand Standard instances", Value: 256, Adjustable: true, Usage: quotaUsage(240)},
+		{ServiceCode: "lambda", QuotaCode: "L-B99A9384", QuotaName: "Concurrent executions", Value: 1000, Adjustable: true},
+		{ServiceCode: "ec2", QuotaCode: "L-0263D0A3", QuotaName: "EC2-VPC Elastic IPs", Value: 5, Adjustable: true, Usage: quotaUsage(5)},
+	}, []types.QuotaIncreaseRequest{
+		{ID: "req-1", ServiceCode: "ec2", QuotaCode: "L-0263D0A3", QuotaName: "EC2-VPC Elastic IPs", DesiredValue: 10, Status: "CASE_OPENED", CaseID: "case-1"},
+	})
+
+	assert.Equal(t, 4, data["total"])
+	assert.Equal(t, []string{"ec2: EC2-VPC Elastic IPs", "ec2: Running On-Demand Standard instances"}, data["at_risk"])
+
+	quotas := data["quotas"].([]map[string]interface{})
+	require.Len(t, quotas, 4)
+	assert.Equal(t, "EC2-VPC Elastic IPs", quotas[0]["name"], "quotas closest to their limit come first")
+	assert.Equal(t, float64(100), quotas[0]["utilization_percent"])
+	assert.Equal(t, float64(10), quotas[0]["pending_increase"])
+	assert.Equal(t, float64(94), quotas[1]["utilization_percent"])
+	assert.Equal(t, "VPCs per Region", quotas[2]["name"])
+	assert.NotContains(t, quotas[2], "unit", "quotas counted without a unit leave it out")
+	assert.Equal(t, "Concurrent executions", quotas[3]["name"])
+	assert.NotContains(t, quotas[3], "usage", "quotas without a usage metric have no usage")
+
+	requests := data["open_requests"].([]map[string]interface{})
+	require.Len(t, requests, 1)
+	assert.Equal(t, "case-1", requests[0]["support_case"])
+}
+
+func TestOpenQuotaRequests(t *testing.T) {
+	open := openQuotaRequests([]types.QuotaIncreaseRequest{
+		{ID: "pending", Status: "PENDING"},
+		{ID: "approved", Status: "APPROVED"},
+		{ID: "case", Status: "CASE_OPENED"},
+		{ID: "denied", Status: "DENIED"},
+	})
+	require.Len(t, open, 2)
+	assert.Equal(t, "pending", open[0].ID)
+	assert.Equal(t, "case", open[1].ID)
+}
+
+func TestValidateQuotaIncrease(t *testing.T) {
+	quota := types.ServiceQuota{ServiceCode: "ec2", QuotaCode: "L-1216C47A", QuotaName: "Running On-Demand Standard instances", Value: 256, Adjustable: true}
+
+	assert.NoError(t, validateQuotaIncrease(quota, 512, nil))
+	assert.ErrorContains(t, validateQuotaIncrease(quota, 256, nil), "above the current value")
+	assert.ErrorContains(t, validateQuotaIncrease(quota, 512, []types.QuotaIncreaseRequest{
+		{ID: "req-1", QuotaCode: "L-1216C47A", DesiredValue: 384, Status: "PENDING"},
+	}), "already PENDING")
+	assert.NoError(t, validateQuotaIncrease(quota, 512, []types.QuotaIncreaseRequest{
+		{ID: "req-0", QuotaCode: "L-1216C47A", DesiredValue: 128, Status: "APPROVED"},
+	}), "closed requests do not block a new one")
+
+	fixed := quota
+	fixed.Adjustable = false
+	assert.ErrorContains(t, validateQuotaIncrease(fixed, 512, nil), "cannot be increased")
+}
+
+func TestRequestQuotaIncreaseRequiresArguments(t *testing.T) {
+	handler := NewToolHandler(&config.Config{}, nil, logging.NewLogger("error", "text"))
+
+	result, err := handler.requestQuotaIncrease(context.Background(), map[string]interface{}{"serviceCode": "ec2"})
+	require.NoError(t, err)
+	assert.Equal(t, "serviceCode and quotaCode are required", decodeToolResult(t, result)["error"])
+
+	result, err = handler.requestQuotaIncrease(context.Background(), map[string]interface{}{"serviceCode": "ec2", "quotaCode": "L-1216C47A"})
+	require.NoError(t, err)
+	assert.Equal(t, "desiredValue is required", decodeToolResult(t, result)["error"])
+}
@@ -0,0 +1,200 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	// connectionContextKey carries the transport connection a request came in on
+	connectionContextKey contextKey = "connection"
+
+	transportStdio          = "stdio"
+	transportWebSocket      = "websocket"
+	transportStreamableHTTP = "streamable-http"
+	transportSSE            = "sse"
+
+	// streamableSessionIdle is how long a streamable HTTP session is listed
+	// without requests. Its requests hold no connection, so clients that go
+	// away without ending the session are only noticed by their silence.
+	streamableSessionIdle = 30 * time.Minute
+)
+
+// connection is the transport connection of a client. Stdio and WebSocket
+// sessions have one connection each; HTTP requests take their session ID
+// from the MCP session they belong to.
+type connection struct {
+	id        string
+	transport string
+	remote    string
+}
+
+// withConnection returns a context whose requests are counted for conn's session
+func withConnection(ctx context.Context, conn connection) context.Context {
+	return context.WithValue(ctx, connectionContextKey, conn)
+}
+
+// connectionFrom returns the connection of a request and the ID of its
+// session, which is empty outside of MCP sessions (e.g. the chat gateway)
+func connectionFrom(ctx context.Context) connection {
+	conn, _ := ctx.Value(connectionContextKey).(connection)
+	if session := server.ClientSessionFromContext(ctx); session != nil && conn.id == "" {
+		conn.id = session.SessionID()
+	}
+	return conn
+}
+
+// clientSession is an MCP client connected to the server
+type clientSession struct {
+	ID            string
+	Transport     string
+	RemoteAddress string
+	ClientName    string
+	ClientVersion string
+	ConnectedAt   time.Time
+	LastActiveAt  time.Time
+	Requests      int64
+}
+
+// sessionRegistry keeps the sessions of the clients connected over any
+// transport, for the management API
+type sessionRegistry struct {
+	mu       sync.Mutex
+	nextID   int
+	sessions map[string]*clientSession
+}
+
+// newSessionRegistry creates a registry without sessions
+func newSessionRegistry() *sessionRegistry {
+	return &sessionRegistry{sessions: make(map[string]*clientSession)}
+}
+
+// hooks returns the MCP server hooks counting the requests of sessions and
+// following SSE sessions, which end when their stream closes
+func (r *sessionRegistry) hooks() *server.Hooks {
+	hooks := &server.Hooks{}
+	hooks.AddBeforeAny(func(ctx context.Context, _ any, _ mcp.MCPMethod, message any) {
+		var client *mcp.Implementation
+		if initialize, ok := message.(*mcp.InitializeRequest); ok {
+			client = &initialize.Params.ClientInfo
+		}
+		r.observe(connectionFrom(ctx), client, time.Now())
+	})
+	hooks.AddOnRegisterSession(func(ctx context.Context, session server.ClientSession) {
+		if conn := connectionFrom(ctx); conn.transport == transportSSE {
+			conn.id = session.SessionID()
+			r.touch(conn, time.Now())
+		}
+	})
+	hooks.AddOnUnregisterSession(func(ctx context.Context, session server.ClientSession) {
+		if conn := connectionFrom(ctx); conn.transport == transportSSE {
+			r.end(session.SessionID())
+		}
+	})
+	return hooks
+}
+
+// track marks the requests of an HTTP endpoint with its transport and the
+// client's address. Streamable HTTP sessions end when the client deletes them.
+func (r *sessionRegistry) track(transport string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		conn := connection{transport: transport, remote: req.RemoteAddr}
+		next.ServeHTTP(w, req.WithContext(withConnection(req.Context(), conn)))
+
+		if id := req.Header.Get(server.HeaderKeySessionID); req.Method == http.MethodDelete && id != "" {
+			r.end(id)
+		}
+	})
+}
+
+// open starts the session of a stdio or WebSocket connection and returns
+// the connection with its session ID
+func (r *sessionRegistry) open(transport, remote string, now time.Time) connection {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.nextID++
+	conn := connection{id: fmt.Sprintf("%s-%d", transport, r.nextID), transport: transport, remote: remote}
+	r.sessionOf(conn, now)
+	return conn
+}
+
+// touch adds the session of a connection if it is new
+func (r *sessionRegistry) touch(conn connection, now time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.sessionOf(conn, now)
+}
+
+// observe counts a request of a session and notes the client it reports
+// when it initializes
+func (r *sessionRegistry) observe(conn connection, client *mcp.Implementation, now time.Time) {
+	if conn.id == "" {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	session := r.sessionOf(conn, now)
+	session.LastActiveAt = now
+	session.Requests++
+	if client != nil {
+		session.ClientName = client.Name
+		session.ClientVersion = client.Version
+	}
+}
+
+// sessionOf returns the session of a connection, adding it when it is new.
+// Callers hold r.mu.
+func (r *sessionRegistry) sessionOf(conn connection, now time.Time) *clientSession {
+	session, ok := r.sessions[conn.id]
+	if !ok {
+		session = &clientSession{
+			ID:            conn.id,
+			Transport:     conn.transport,
+			RemoteAddress: conn.remote,
+			ConnectedAt:   now,
+			LastActiveAt:  now,
+		}
+		r.sessions[conn.id] = session
+	}
+	return session
+}
+
+// end removes a session once its client is gone
+func (r *sessionRegistry) end(id string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.sessions, id)
+}
+
+// all returns the sessions connected at now, oldest first. Streamable HTTP
+// sessions idle for too long are dropped.
+func (r *sessionRegistry) all(now time.Time) []clientSession {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	sessions := make([]clientSession, 0, len(r.sessions))
+	for id, session := range r.sessions {
+		if session.Transport == transportStreamableHTTP && now.Sub(session.LastActiveAt) > streamableSessionIdle {
+			delete(r.sessions, id)
+			continue
+		}
+		sessions = append(sessions, *session)
+	}
+	sort.Slice(sessions, func(i, j int) bool {
+		if !sessions[i].ConnectedAt.Equal(sessions[j].ConnectedAt) {
+			return sessions[i].ConnectedAt.Before(sessions[j].ConnectedAt)
+		}
+		return sessions[i].ID < sessions[j].ID
+	})
+	return sessions
+}
@@ -0,0 +1,105 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"aws-mcp-server/pkg/approval"
+	"aws-mcp-server/pkg/cost"
+	"aws-mcp-server/pkg/simulate"
+	"aws-mcp-server/pkg/types"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// simulateAction predicts the effects of an action on load balancer targets,
+// cost and availability without executing it
+func (h *ToolHandler) simulateAction(ctx context.Context, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	action, _ := arguments["action"].(string)
+	if !simulate.Supported(action) {
+		return h.createErrorResponse(fmt.Sprintf("unsupported action %q, supported actions are: %s", action, strings.Join(simulate.Actions, ", ")))
+	}
+
+	instanceType, _ := arguments["instanceType"].(string)
+	prices := h.config.Cost.InstancePrices
+
+	var prediction simulate.Prediction
+	if action == simulate.ActionCreate {
+		if instanceType == "" {
+			return h.createErrorResponse("instanceType is required to simulate create-ec2-instance")
+		}
+		prediction = simulate.CreateInstance(instanceType, prices)
+
+		// The guardrail is only checked, not recorded, since nothing is created
+		if violation := h.guardrail.Check(cost.EstimateEC2(instanceType, prices)); violation != nil {
+			prediction.Add(simulate.SeverityWarning, "cost-guardrail", "The cost guardrail would block this action: %s", strings.Join(violation.Reasons, "; "))
+		}
+	} else {
+		instanceID, _ := arguments["instanceId"].(string)
+		if instanceID == "" {
+			return h.createErrorResponse(fmt.Sprintf("instanceId is required to simulate %s", action))
+		}
+		if action == simulate.ActionResize && instanceType == "" {
+			return h.createErrorResponse("instanceType is required to simulate resize-ec2-instance")
+		}
+
+		resource, err := h.awsClient.GetEC2Instance(ctx, instanceID)
+		if err != nil {
+			return h.createErrorResponse(fmt.Sprintf("failed to get EC2 instance: %v", err))
+		}
+		instance := simulatedInstance(resource)
+
+		groups, groupsErr := h.awsClient.ListTargetGroups(ctx)
+		if groupsErr != nil {
+			h.logger.WithError(groupsErr).Warn("Failed to list target groups for simulation")
+		}
+
+		switch action {
+		case simulate.ActionStart:
+			prediction = simulate.StartInstance(instance, groups, prices)
+		case simulate.ActionStop:
+			prediction = simulate.StopInstance(instance, groups, prices)
+		case simulate.ActionTerminate:
+			prediction = simulate.TerminateInstance(instance, groups, prices)
+		case simulate.ActionResize:
+			prediction = simulate.ResizeInstance(instance, instanceType, groups, prices)
+		}
+
+		if groupsErr != nil {
+			prediction.Add(simulate.SeverityWarning, "elbv2", "Load balancer targets could not be checked: %v", groupsErr)
+		}
+	}
+
+	if mutatingTools[action] {
+		if window := approval.ActiveFreeze(h.freezeWindows, time.Now().In(h.times.Location())); window != nil {
+			prediction.Add(simulate.SeverityWarning, "change-freeze", "Change freeze %q is active, so the action would need admin approval", window.Name)
+		}
+	}
+
+	data := map[string]interface{}{
+		"action":   prediction.Action,
+		"target":   prediction.Target,
+		"risk":     prediction.Risk,
+		"effects":  prediction.Effects,
+		"executed": false,
+	}
+	if prediction.CostKnown {
+		data["monthlyCostDeltaUsd"] = prediction.MonthlyCostDeltaUSD
+	}
+
+	return h.createSuccessResponse(fmt.Sprintf("Simulated %s on %s without executing it", action, prediction.Target), data)
+}
+
+// simulatedInstance extracts what a simulation needs from an EC2 instance
+func simulatedInstance(resource *types.CloudResource) simulate.Instance {
+	details := resource.EC2Instance()
+	return simulate.Instance{
+		ID:           resource.ID,
+		Name:         resource.Tags["Name"],
+		State:        resource.State,
+		InstanceType: details.InstanceType,
+		PublicIP:     details.PublicIPAddress,
+	}
+}
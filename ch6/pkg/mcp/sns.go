@@ -0,0 +1,245 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"aws-mcp-server/pkg/aws"
+	"aws-mcp-server/pkg/types"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const (
+	// snsTopicsURI lists the SNS topics with their subscription counts
+	snsTopicsURI = "aws://sns/topics"
+	// snsTopicTemplate is the URI template of one topic with its subscriptions
+	snsTopicTemplate = "aws://sns/topics/{topic}"
+	// snsSubjectLimit is the longest subject SNS accepts
+	snsSubjectLimit = 100
+	// snsMessageLimit is the largest message SNS accepts, in bytes
+	snsMessageLimit = 256 * 1024
+)
+
+// readSNSTopics returns all topics, those whose messages reach no one first
+func (h *ResourceHandler) readSNSTopics(ctx context.Context) (*mcp.ReadResourceResult, error) {
+	topics, err := h.awsClient.ListSNSTopics(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list SNS topics: %w", err)
+	}
+
+	jsonData, err := json.MarshalIndent(formatSNSTopics(topics), "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal SNS topics data: %w", err)
+	}
+
+	return &mcp.ReadResourceResult{
+		Contents: []mcp.ResourceContents{
+			&mcp.TextResourceContents{
+				URI:      snsTopicsURI,
+				MIMEType: "application/json",
+				Text:     string(jsonData),
+			},
+		},
+	}, nil
+}
+
+// readSNSTopic returns one topic, by name or ARN, with its subscriptions
+func (h *ResourceHandler) readSNSTopic(ctx context.Context, req resourceRequest) (*mcp.ReadResourceResult, error) {
+	uri, name := req.URI, req.Param("topic")
+	if name == "" {
+		return nil, fmt.Errorf("invalid SNS topic URI %s, use %s", uri, snsTopicTemplate)
+	}
+
+	arn, err := resolveSNSTopic(ctx, h.awsClient, name)
+	if err != nil {
+		return nil, err
+	}
+	topic, err := h.awsClient.GetSNSTopic(ctx, arn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get SNS topic: %w", err)
+	}
+	subscriptions, err := h.awsClient.ListSNSSubscriptions(ctx, arn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list SNS subscriptions: %w", err)
+	}
+
+	data := formatSNSTopic(*topic)
+	data["arn"] = topic.ARN
+	data["subscriptions"] = formatSNSSubscriptions(subscriptions)
+
+	jsonData, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal SNS topic data: %w", err)
+	}
+
+	return &mcp.ReadResourceResult{
+		Contents: []mcp.ResourceContents{
+			&mcp.TextResourceContents{
+				URI:      uri,
+				MIMEType: "application/json",
+				Text:     string(jsonData),
+			},
+		},
+	}, nil
+}
+
+// resolveSNSTopic accepts a topic name or ARN and returns the ARN
+func resolveSNSTopic(ctx context.Context, client *aws.Client, topic string) (string, error) {
+	if strings.HasPrefix(topic, "arn:") {
+		return topic, nil
+	}
+	arn, err := client.FindSNSTopicARN(ctx, topic)
+	if err != nil {
+		return "", fmt.Errorf("failed to find SNS topic: %w", err)
+	}
+	return arn, nil
+}
+
+// formatSNSTopics formats topics for AI processing
+func formatSNSTopics(topics []types.SNSTopic) map[string]interface{} {
+	formatted := make([]map[string]interface{}, 0, len(topics))
+	var silent []string
+	for _, topic := range topics {
+		formatted = append(formatted, formatSNSTopic(topic))
+		if topic.Confirmed == 0 {
+			silent = append(silent, topic.Name)
+		}
+	}
+
+	sort.SliceStable(formatted, func(i, j int) bool {
+		_, iIssues := formatted[i]["issues"]
+		_, jIssues := formatted[j]["issues"]
+		return iIssues && !jIssues
+	})
+
+	return map[string]interface{}{
+		"total_topics":               len(topics),
+		"topics_without_subscribers": len(silent),
+		"without_subscribers":        silent,
+		"topics":                     formatted,
+	}
+}
+
+// formatSNSTopic formats one topic with what keeps its messages from arriving
+func formatSNSTopic(topic types.SNSTopic) map[string]interface{} {
+	item := map[string]interface{}{
+		"name":                    topic.Name,
+		"fifo":                    topic.FIFO,
+		"encrypted":               topic.Encrypted,
+		"confirmed_subscriptions": topic.Confirmed,
+		"pending_subscriptions":   topic.Pending,
+		"uri":                     snsTopicsURI + "/" + topic.Name,
+	}
+	if topic.DisplayName != "" {
+		item["display_name"] = topic.DisplayName
+	}
+
+	var issues []string
+	if topic.Confirmed == 0 {
+		issues = append(issues, "no confirmed subscriptions, so published messages reach no one")
+	}
+	if topic.Pending > 0 {
+		issues = append(issues, fmt.Sprintf("messages are not delivered to %d %s awaiting confirmation",
+			topic.Pending, map[bool]string{true: "subscription", false: "subscriptions"}[topic.Pending == 1]))
+	}
+	if len(issues) > 0 {
+		item["issues"] = issues
+	}
+	return item
+}
+
+// formatSNSSubscriptions lists subscriptions, confirmed ones first
+func formatSNSSubscriptions(subscriptions []types.SNSSubscription) []map[string]interface{} {
+	formatted := make([]map[string]interface{}, 0, len(subscriptions))
+	for _, subscription := range subscriptions {
+		item := map[string]interface{}{
+			"protocol": subscription.Protocol,
+			"endpoint": subscription.Endpoint,
+			"status":   "confirmed",
+		}
+		if subscription.Pending {
+			item["status"] = "pending_confirmation"
+		}
+		formatted = append(formatted, item)
+	}
+	sort.SliceStable(formatted, func(i, j int) bool {
+		return formatted[i]["status"] == "confirmed" && formatted[j]["status"] != "confirmed"
+	})
+	return formatted
+}
+
+// publishSNSMessage publishes a message to a topic, e.g. to page the humans
+// subscribed to it or fan an event out to other systems
+func (h *ToolHandler) publishSNSMessage(ctx context.Context, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	topicName, _ := arguments["topic"].(string)
+	message, _ := arguments["message"].(string)
+	subject, _ := arguments["subject"].(string)
+	switch {
+	case topicName == "":
+		return h.createErrorResponse("topic is required")
+	case strings.TrimSpace(message) == "":
+		return h.createErrorResponse("message is required")
+	case len(message) > snsMessageLimit:
+		return h.createErrorResponse(fmt.Sprintf("message is %d bytes, SNS accepts at most %d", len(message), snsMessageLimit))
+	}
+	if problem := snsSubjectProblem(subject); problem != "" {
+		return h.createErrorResponse(problem)
+	}
+
+	params := aws.SNSPublishParams{Subject: subject, Message: message}
+	params.GroupID, _ = arguments["messageGroupId"].(string)
+	params.DeduplicationID, _ = arguments["deduplicationId"].(string)
+	if attributes, ok := arguments["attributes"].(map[string]interface{}); ok {
+		params.Attributes = make(map[string]string, len(attributes))
+		for name, value := range attributes {
+			params.Attributes[name] = fmt.Sprint(value)
+		}
+	}
+
+	arn, err := resolveSNSTopic(ctx, h.awsClient, topicName)
+	if err != nil {
+		return h.createErrorResponse(err.Error())
+	}
+	topic, err := h.awsClient.GetSNSTopic(ctx, arn)
+	if err != nil {
+		return h.createErrorResponse(fmt.Sprintf("failed to get SNS topic: %v", err))
+	}
+	if topic.FIFO && params.GroupID == "" {
+		return h.createErrorResponse(fmt.Sprintf("topic %s is a FIFO topic, messageGroupId is required", topic.Name))
+	}
+	params.TopicARN = arn
+
+	messageID, err := h.awsClient.PublishSNSMessage(ctx, params)
+	if err != nil {
+		return h.createErrorResponse(fmt.Sprintf("failed to publish SNS message: %v", err))
+	}
+
+	data := map[string]interface{}{
+		"topic":       topic.Name,
+		"messageId":   messageID,
+		"subscribers": topic.Confirmed,
+	}
+	var warnings []string
+	if topic.Confirmed == 0 {
+		warnings = append(warnings, "The topic has no confirmed subscriptions, so the message reached no one")
+	}
+	return h.createSuccessResponse("SNS message published successfully", data, warnings...)
+}
+
+// snsSubjectProblem describes why SNS would reject a subject: it is used as
+// the email subject line, so it must be short printable ASCII on one line
+func snsSubjectProblem(subject string) string {
+	if len(subject) > snsSubjectLimit {
+		return fmt.Sprintf("subject must be at most %d characters", snsSubjectLimit)
+	}
+	for _, r := range subject {
+		if r < ' ' || r > '~' {
+			return "subject must be printable ASCII without line breaks"
+		}
+	}
+	return ""
+}
@@ -0,0 +1,70 @@
+package mcp
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"aws-mcp-server/internal/config"
+	"aws-mcp-server/internal/logging"
+	"aws-mcp-server/pkg/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatSNSTopics(t *testing.T) {
+	topics := []types.SNSTopic{
+		{Name: "deploys", Confirmed: 3},
+		{Name: "oncall", Confirmed: 1, Pending: 1},
+		{Name: "orphaned", Deleted: 2},
+	}
+
+	formatted := formatSNSTopics(topics)
+	assert.Equal(t, 3, formatted["total_topics"])
+	assert.Equal(t, 1, formatted["topics_without_subscribers"])
+	assert.Equal(t, []string{"orphaned"}, formatted["without_subscribers"])
+
+	items := formatted["topics"].([]map[string]interface{})
+	require.Len(t, items, 3)
+	assert.Equal(t, "oncall", items[0]["name"])
+	assert.Equal(t, []string{"messages are not delivered to 1 subscription awaiting confirmation"}, items[0]["issues"])
+	assert.Equal(t, "orphaned", items[1]["name"])
+	assert.Contains(t, items[1]["issues"].([]string)[0], "reach no one")
+	assert.NotContains(t, items[2], "issues")
+	assert.Equal(t, "aws://sns/topics/deploys", items[2]["uri"])
+}
+
+func TestFormatSNSSubscriptions(t *testing.T) {
+	formatted := formatSNSSubscriptions([]types.SNSSubscription{
+		{Protocol: "email", Endpoint: "new@example.com", Pending: true},
+		{Protocol: "sqs", Endpoint: "arn:aws:sqs:us-east-1:123456789012:events", ARN: "arn:aws:sns:us-east-1:123456789012:oncall:1"},
+	})
+	require.Len(t, formatted, 2)
+	assert.Equal(t, "confirmed", formatted[0]["status"])
+	assert.Equal(t, "pending_confirmation", formatted[1]["status"])
+}
+
+func TestPublishSNSMessageValidatesArguments(t *testing.T) {
+	h := NewToolHandler(&config.Config{}, nil, logging.NewLogger("error", "text"))
+
+	tests := []struct {
+		name      string
+		arguments map[string]interface{}
+		expected  string
+	}{
+		{"missing topic", map[string]interface{}{"message": "disk full"}, "topic is required"},
+		{"blank message", map[string]interface{}{"topic": "oncall", "message": "  "}, "message is required"},
+		{"long subject", map[string]interface{}{"topic": "oncall", "message": "disk full", "subject": strings.Repeat("x", 101)},
+			"subject must be at most 100 characters"},
+		{"multi-line subject", map[string]interface{}{"topic": "oncall", "message": "disk full", "subject": "disk\nfull"},
+			"subject must be printable ASCII without line breaks"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := h.CallTool(context.Background(), "publish-sns-message", tt.arguments)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, decodeToolResult(t, result)["error"])
+		})
+	}
+}
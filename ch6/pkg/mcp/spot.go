@@ -0,0 +1,189 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"aws-mcp-server/pkg/aws"
+	"aws-mcp-server/pkg/types"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const (
+	// spotRequestsURI lists the Spot Instance requests of the region
+	spotRequestsURI = "aws://ec2/spot-requests"
+	// spotInterruptionsURI lists pending interruption notices and recent
+	// interruptions of Spot Instances
+	spotInterruptionsURI = "aws://ec2/spot-interruptions"
+	// spotInterruptionDays is how far back interruptions are looked up
+	spotInterruptionDays = 7
+)
+
+// spotInterruptionCodes are the request status codes of interrupted Spot
+// Instances, and of those about to be
+var spotInterruptionCodes = map[string]bool{
+	"marked-for-termination":                      true,
+	"marked-for-stop":                             true,
+	"marked-for-hibernation":                      true,
+	"instance-terminated-by-price":                true,
+	"instance-terminated-no-capacity":             true,
+	"instance-terminated-capacity-oversubscribed": true,
+	"instance-stopped-by-price":                   true,
+	"instance-stopped-no-capacity":                true,
+	"instance-stopped-capacity-oversubscribed":    true,
+	"instance-hibernated-by-price":                true,
+	"instance-hibernated-no-capacity":             true,
+}
+
+// spotOptions returns the Spot options of create-ec2-instance, nil for an
+// On-Demand instance
+func spotOptions(arguments map[string]interface{}) (*aws.SpotOptions, error) {
+	spot, _ := arguments["spot"].(bool)
+	maxPrice, _ := arguments["spotMaxPrice"].(string)
+	behavior, _ := arguments["spotInterruptionBehavior"].(string)
+	if !spot {
+		if maxPrice != "" || behavior != "" {
+			return nil, fmt.Errorf("spotMaxPrice and spotInterruptionBehavior require spot=true")
+		}
+		return nil, nil
+	}
+
+	if maxPrice != "" {
+		price, err := strconv.ParseFloat(maxPrice, 64)
+		if err != nil || price <= 0 {
+			return nil, fmt.Errorf("spotMaxPrice must be a positive price in USD per hour, e.g. 0.05")
+		}
+	}
+	switch behavior {
+	case "", "terminate", "stop", "hibernate":
+	default:
+		return nil, fmt.Errorf("spotInterruptionBehavior must be terminate, stop or hibernate")
+	}
+
+	return &aws.SpotOptions{MaxPrice: maxPrice, InterruptionBehavior: behavior}, nil
+}
+
+// readSpotRequests lists the Spot Instance requests, newest first, with
+// counts by state and status
+func (h *ResourceHandler) readSpotRequests(ctx context.Context) (*mcp.ReadResourceResult, error) {
+	requests, err := h.awsClient.ListSpotRequests(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	jsonData, err := json.MarshalIndent(formatSpotRequests(requests), "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal Spot requests data: %w", err)
+	}
+
+	return &mcp.ReadResourceResult{
+		Contents: []mcp.ResourceContents{
+			&mcp.TextResourceContents{
+				URI:      spotRequestsURI,
+				MIMEType: "application/json",
+				Text:     string(jsonData),
+			},
+		},
+	}, nil
+}
+
+// formatSpotRequests sorts the requests newest first and counts them by
+// state and status
+func formatSpotRequests(requests []types.SpotRequest) map[string]interface{} {
+	sort.SliceStable(requests, func(i, j int) bool {
+		return requests[i].Created.After(requests[j].Created)
+	})
+
+	byState := make(map[string]int)
+	byStatus := make(map[string]int)
+	for _, request := range requests {
+		byState[request.State]++
+		byStatus[request.StatusCode]++
+	}
+
+	return map[string]interface{}{
+		"requests":          requests,
+		"total":             len(requests),
+		"summary_by_state":  byState,
+		"summary_by_status": byStatus,
+	}
+}
+
+// readSpotInterruptions lists the Spot Instances AWS has given an
+// interruption notice and those it interrupted recently
+func (h *ResourceHandler) readSpotInterruptions(ctx context.Context) (*mcp.ReadResourceResult, error) {
+	requests, err := h.awsClient.ListSpotRequests(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// CloudTrail reaches further back than the requests, which AWS drops a
+	// few hours after they close
+	since := time.Now().AddDate(0, 0, -spotInterruptionDays)
+	history, err := h.awsClient.ListSpotInterruptions(ctx, since)
+
+	data := formatSpotInterruptions(requests, history)
+	if err != nil {
+		data["history_error"] = err.Error()
+	}
+
+	jsonData, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal Spot interruptions data: %w", err)
+	}
+
+	return &mcp.ReadResourceResult{
+		Contents: []mcp.ResourceContents{
+			&mcp.TextResourceContents{
+				URI:      spotInterruptionsURI,
+				MIMEType: "application/json",
+				Text:     string(jsonData),
+			},
+		},
+	}, nil
+}
+
+// formatSpotInterruptions splits the requests of interrupted instances into
+// pending notices, whose instances have about two minutes left, and past
+// interruptions, which are merged with those CloudTrail recorded
+func formatSpotInterruptions(requests []types.SpotRequest, history []types.SpotInterruption) map[string]interface{} {
+	notices := make([]types.SpotRequest, 0)
+	interrupted := make(map[string]types.SpotInterruption)
+	for _, request := range requests {
+		if !spotInterruptionCodes[request.StatusCode] {
+			continue
+		}
+		if strings.HasPrefix(request.StatusCode, "marked-for-") {
+			notices = append(notices, request)
+			continue
+		}
+		if request.InstanceID != "" && request.StatusUpdated != nil {
+			interrupted[request.InstanceID] = types.SpotInterruption{Time: *request.StatusUpdated, InstanceID: request.InstanceID}
+		}
+	}
+	for _, interruption := range history {
+		if _, seen := interrupted[interruption.InstanceID]; !seen {
+			interrupted[interruption.InstanceID] = interruption
+		}
+	}
+
+	interruptions := make([]types.SpotInterruption, 0, len(interrupted))
+	for _, interruption := range interrupted {
+		interruptions = append(interruptions, interruption)
+	}
+	sort.Slice(interruptions, func(i, j int) bool {
+		return interruptions[i].Time.After(interruptions[j].Time)
+	})
+
+	return map[string]interface{}{
+		"notices":       notices,
+		"interruptions": interruptions,
+		"days":          spotInterruptionDays,
+	}
+}
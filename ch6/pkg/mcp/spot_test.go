@@ -0,0 +1,85 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"aws-mcp-server/internal/config"
+	"aws-mcp-server/internal/logging"
+	"aws-mcp-server/pkg/aws"
+	"aws-mcp-server/pkg/render"
+	"aws-mcp-server/pkg/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSpotOptions(t *testing.T) {
+	spot, err := spotOptions(map[string]interface{}{})
+	require.NoError(t, err)
+	assert.Nil(t, spot, "instances are On-Demand by default")
+
+	spot, err = spotOptions(map[string]interface{}{"spot": true, "spotMaxPrice": "0.05", "spotInterruptionBehavior": "stop"})
+	require.NoError(t, err)
+	assert.Equal(t, &aws.SpotOptions{MaxPrice: "0.05", InterruptionBehavior: "stop"}, spot)
+
+	_, err = spotOptions(map[string]interface{}{"spot": true, "spotMaxPrice": "cheap"})
+	assert.ErrorContains(t, err, "positive price")
+	_, err = spotOptions(map[string]interface{}{"spot": true, "spotInterruptionBehavior": "pause"})
+	assert.ErrorContains(t, err, "terminate, stop or hibernate")
+	_, err = spotOptions(map[string]interface{}{"spotMaxPrice": "0.05"})
+	assert.ErrorContains(t, err, "require spot=true")
+
+	h := NewToolHandler(&config.Config{}, nil, logging.NewLogger("error", "text"))
+	result, err := h.CallTool(context.Background(), "create-ec2-instance", map[string]interface{}{
+		"imageId": "ami-1", "instanceType": "t3.micro", "spot": true, "spotInterruptionBehavior": "pause",
+	})
+	require.NoError(t, err)
+	assert.Contains(t, decodeToolResult(t, result)["error"], "spotInterruptionBehavior")
+}
+
+func TestFormatSpotInterruptions(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	earlier := now.Add(-2 * time.Hour)
+	data := formatSpotInterruptions([]types.SpotRequest{
+		{ID: "sir-1", State: "active", StatusCode: "fulfilled", InstanceID: "i-1"},
+		{ID: "sir-2", State: "active", StatusCode: "marked-for-termination", InstanceID: "i-2", StatusUpdated: &now},
+		{ID: "sir-3", State: "closed", StatusCode: "instance-terminated-no-capacity", InstanceID: "i-3", StatusUpdated: &earlier},
+	}, []types.SpotInterruption{
+		{Time: earlier.Add(time.Second), InstanceID: "i-3"},
+		{Time: now.Add(-48 * time.Hour), InstanceID: "i-4"},
+	})
+
+	notices := data["notices"].([]types.SpotRequest)
+	require.Len(t, notices, 1)
+	assert.Equal(t, "i-2", notices[0].InstanceID)
+
+	interruptions := data["interruptions"].([]types.SpotInterruption)
+	require.Len(t, interruptions, 2, "interruptions in both the requests and CloudTrail are listed once")
+	assert.Equal(t, "i-3", interruptions[0].InstanceID)
+	assert.True(t, earlier.Equal(interruptions[0].Time))
+	assert.Equal(t, "i-4", interruptions[1].InstanceID)
+
+	renderer, err := render.New(nil)
+	require.NoError(t, err)
+	payload, err := json.Marshal(data)
+	require.NoError(t, err)
+	summary, ok := renderer.RenderJSON(spotInterruptionsURI, payload, nil)
+	require.True(t, ok)
+	assert.Equal(t, "1 Spot instance with an interruption notice: i-2 (marked-for-termination); 2 interruptions in the last 7 days", summary)
+}
+
+func TestFormatSpotRequests(t *testing.T) {
+	older := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	data := formatSpotRequests([]types.SpotRequest{
+		{ID: "sir-old", State: "closed", StatusCode: "instance-terminated-by-price", Created: older},
+		{ID: "sir-new", State: "active", StatusCode: "fulfilled", Created: older.Add(time.Hour)},
+	})
+
+	requests := data["requests"].([]types.SpotRequest)
+	assert.Equal(t, "sir-new", requests[0].ID)
+	assert.Equal(t, map[string]int{"active": 1, "closed": 1}, data["summary_by_state"])
+	assert.Equal(t, 2, data["total"])
+}
@@ -0,0 +1,334 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"aws-mcp-server/pkg/types"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const (
+	// sqsQueuesURI lists the SQS queues with their depth and oldest message age
+	sqsQueuesURI = "aws://sqs/queues"
+	// sqsQueueTemplate is the URI template of one queue with its redrive state
+	sqsQueueTemplate = "aws://sqs/queues/{queue}"
+	// sqsBacklogAge is how long the oldest message may wait before the queue
+	// is reported as backed up
+	sqsBacklogAge = 15 * time.Minute
+	// sqsRetentionWarning is the share of the retention period after which
+	// messages are reported as about to expire
+	sqsRetentionWarning = 0.8
+	// maxSQSRedriveRate is the highest rate SQS accepts for a message move task
+	maxSQSRedriveRate = 500
+)
+
+// readSQSQueues returns all queues, those with a backlog or dead-lettered
+// messages first
+func (h *ResourceHandler) readSQSQueues(ctx context.Context) (*mcp.ReadResourceResult, error) {
+	queues, err := h.awsClient.ListSQSQueues(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list SQS queues: %w", err)
+	}
+
+	jsonData, err := json.MarshalIndent(formatSQSQueues(queues), "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal SQS queues data: %w", err)
+	}
+
+	return &mcp.ReadResourceResult{
+		Contents: []mcp.ResourceContents{
+			&mcp.TextResourceContents{
+				URI:      sqsQueuesURI,
+				MIMEType: "application/json",
+				Text:     string(jsonData),
+			},
+		},
+	}, nil
+}
+
+// readSQSQueue returns one queue with the queues that dead-letter into it and
+// its latest redrives
+func (h *ResourceHandler) readSQSQueue(ctx context.Context, req resourceRequest) (*mcp.ReadResourceResult, error) {
+	uri, name := req.URI, req.Param("queue")
+	if name == "" {
+		return nil, fmt.Errorf("invalid SQS queue URI %s, use %s", uri, sqsQueueTemplate)
+	}
+
+	queue, err := h.awsClient.GetSQSQueue(ctx, name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get SQS queue: %w", err)
+	}
+	sources, err := h.awsClient.ListSQSDeadLetterSources(ctx, queue.URL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list SQS source queues: %w", err)
+	}
+
+	data := formatSQSQueue(*queue, len(sources) > 0)
+	data["url"] = queue.URL
+	data["visibility_timeout_seconds"] = queue.VisibilityTimeout
+	data["retention_period_seconds"] = queue.RetentionPeriod
+	if !queue.CreatedAt.IsZero() {
+		data["created"] = h.times.Format(queue.CreatedAt)
+	}
+	if len(sources) > 0 {
+		data["dead_letter_sources"] = sources
+
+		tasks, err := h.awsClient.ListSQSMoveTasks(ctx, queue.ARN)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list SQS redrives: %w", err)
+		}
+		redrives := make([]map[string]interface{}, 0, len(tasks))
+		for _, task := range tasks {
+			redrives = append(redrives, h.formatSQSMoveTask(task))
+		}
+		data["redrives"] = redrives
+	}
+
+	jsonData, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal SQS queue data: %w", err)
+	}
+
+	return &mcp.ReadResourceResult{
+		Contents: []mcp.ResourceContents{
+			&mcp.TextResourceContents{
+				URI:      uri,
+				MIMEType: "application/json",
+				Text:     string(jsonData),
+			},
+		},
+	}, nil
+}
+
+// formatSQSQueues formats queues for AI processing. A queue is a dead letter
+// queue when another queue's redrive policy points at it.
+func formatSQSQueues(queues []types.SQSQueue) map[string]interface{} {
+	deadLetter := make(map[string]bool)
+	for _, queue := range queues {
+		if queue.DeadLetterQueueARN != "" {
+			deadLetter[queue.DeadLetterQueueARN] = true
+		}
+	}
+
+	formatted := make([]map[string]interface{}, 0, len(queues))
+	var atRisk []string
+	totalVisible := int64(0)
+	for _, queue := range queues {
+		item := formatSQSQueue(queue, deadLetter[queue.ARN])
+		if _, ok := item["issues"]; ok {
+			atRisk = append(atRisk, queue.Name)
+		}
+		formatted = append(formatted, item)
+		totalVisible += queue.Visible
+	}
+
+	sort.SliceStable(formatted, func(i, j int) bool {
+		_, iIssues := formatted[i]["issues"]
+		_, jIssues := formatted[j]["issues"]
+		if iIssues != jIssues {
+			return iIssues
+		}
+		return formatted[i]["visible"].(int64) > formatted[j]["visible"].(int64)
+	})
+
+	return map[string]interface{}{
+		"total_queues":     len(queues),
+		"visible_messages": totalVisible,
+		"queues_at_risk":   len(atRisk),
+		"at_risk":          atRisk,
+		"queues":           formatted,
+	}
+}
+
+// formatSQSQueue formats one queue with the issues its depth and age point to
+func formatSQSQueue(queue types.SQSQueue, isDeadLetter bool) map[string]interface{} {
+	item := map[string]interface{}{
+		"name":              queue.Name,
+		"fifo":              queue.FIFO,
+		"visible":           queue.Visible,
+		"in_flight":         queue.InFlight,
+		"delayed":           queue.Delayed,
+		"dead_letter_queue": isDeadLetter,
+		"uri":               sqsQueuesURI + "/" + queue.Name,
+	}
+	if queue.OldestMessageAge != nil {
+		item["oldest_message_age_seconds"] = int64(*queue.OldestMessageAge)
+	}
+	if queue.DeadLetterQueueARN != "" {
+		item["redrives_to"] = queue.DeadLetterQueueARN[strings.LastIndex(queue.DeadLetterQueueARN, ":")+1:]
+		item["max_receive_count"] = queue.MaxReceiveCount
+	}
+	if issues := sqsQueueIssues(queue, isDeadLetter); len(issues) > 0 {
+		item["issues"] = issues
+	}
+	return item
+}
+
+// sqsQueueIssues lists why a queue needs attention: messages in a dead letter
+// queue, consumers falling behind or messages about to expire
+func sqsQueueIssues(queue types.SQSQueue, isDeadLetter bool) []string {
+	var issues []string
+
+	if isDeadLetter && queue.Visible > 0 {
+		issues = append(issues, fmt.Sprintf("%d messages failed processing and were dead-lettered; redrive them once the cause is fixed", queue.Visible))
+	}
+	if queue.OldestMessageAge == nil || queue.Visible == 0 {
+		return issues
+	}
+
+	age := time.Duration(*queue.OldestMessageAge) * time.Second
+	retention := time.Duration(queue.RetentionPeriod) * time.Second
+	switch {
+	case retention > 0 && float64(age) >= float64(retention)*sqsRetentionWarning:
+		issues = append(issues, fmt.Sprintf("oldest message is %s old and is deleted by the %s retention period in %s",
+			age.Round(time.Minute), retention, (retention-age).Round(time.Minute)))
+	case !isDeadLetter && age >= sqsBacklogAge:
+		issues = append(issues, fmt.Sprintf("oldest message has waited %s; consumers are not keeping up with %d messages",
+			age.Round(time.Minute), queue.Visible))
+	}
+	return issues
+}
+
+// formatSQSMoveTask formats a redrive of a dead letter queue
+func (h *ResourceHandler) formatSQSMoveTask(task types.SQSMoveTask) map[string]interface{} {
+	item := map[string]interface{}{
+		"status":  task.Status,
+		"moved":   task.Moved,
+		"started": h.times.Format(task.StartedAt),
+	}
+	if task.ToMove != nil {
+		item["to_move"] = *task.ToMove
+	}
+	if task.DestinationARN != "" {
+		item["destination"] = task.DestinationARN
+	}
+	if task.MaxPerSecond != nil {
+		item["max_per_second"] = *task.MaxPerSecond
+	}
+	if task.FailureReason != "" {
+		item["failure_reason"] = task.FailureReason
+	}
+	return item
+}
+
+// purgeSQSQueue deletes every message in a queue. The plan with the message
+// counts is returned for confirmation first.
+func (h *ToolHandler) purgeSQSQueue(ctx context.Context, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	name, _ := arguments["queue"].(string)
+	if name == "" {
+		return h.createErrorResponse("queue is required")
+	}
+
+	queue, err := h.awsClient.GetSQSQueue(ctx, name)
+	if err != nil {
+		return h.createErrorResponse(fmt.Sprintf("failed to get SQS queue: %v", err))
+	}
+
+	if !isConfirmed(arguments) {
+		warnings := []string{"Purged messages cannot be recovered"}
+		if queue.InFlight > 0 {
+			warnings = append(warnings, fmt.Sprintf("%d messages being processed are deleted as well and cannot be acknowledged", queue.InFlight))
+		}
+		sources, err := h.awsClient.ListSQSDeadLetterSources(ctx, queue.URL)
+		if err == nil && len(sources) > 0 {
+			warnings = append(warnings, fmt.Sprintf("%s is the dead letter queue of %s; consider redrive-sqs-dlq to reprocess the messages instead",
+				queue.Name, strings.Join(sources, ", ")))
+		}
+		plan := map[string]interface{}{
+			"queue":     queue.Name,
+			"visible":   queue.Visible,
+			"in_flight": queue.InFlight,
+			"delayed":   queue.Delayed,
+		}
+		return h.createConfirmationResponse("purge-sqs-queue", plan, warnings)
+	}
+
+	if err := h.awsClient.PurgeSQSQueue(ctx, queue.URL); err != nil {
+		return h.createErrorResponse(fmt.Sprintf("failed to purge SQS queue: %v", err))
+	}
+
+	data := map[string]interface{}{
+		"queue":  queue.Name,
+		"purged": queue.Visible + queue.InFlight + queue.Delayed,
+		"note":   "Purging takes up to 60 seconds; messages sent meanwhile may be deleted too",
+	}
+	return h.createSuccessResponse("SQS queue purged successfully", data)
+}
+
+// redriveSQSDLQ moves the messages of a dead letter queue back to the queues
+// they failed in, or to another queue
+func (h *ToolHandler) redriveSQSDLQ(ctx context.Context, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	name, _ := arguments["queue"].(string)
+	if name == "" {
+		return h.createErrorResponse("queue is required")
+	}
+	rate := 0
+	if value, ok := arguments["maxMessagesPerSecond"].(float64); ok {
+		if value < 1 || value > maxSQSRedriveRate {
+			return h.createErrorResponse(fmt.Sprintf("maxMessagesPerSecond must be between 1 and %d", maxSQSRedriveRate))
+		}
+		rate = int(value)
+	}
+
+	queue, err := h.awsClient.GetSQSQueue(ctx, name)
+	if err != nil {
+		return h.createErrorResponse(fmt.Sprintf("failed to get SQS queue: %v", err))
+	}
+	if queue.Visible == 0 {
+		return h.createErrorResponse(fmt.Sprintf("queue %s has no messages to redrive", queue.Name))
+	}
+
+	destinationARN := ""
+	destination, _ := arguments["destinationQueue"].(string)
+	if destination != "" {
+		target, err := h.awsClient.GetSQSQueue(ctx, destination)
+		if err != nil {
+			return h.createErrorResponse(fmt.Sprintf("failed to get destination queue: %v", err))
+		}
+		destinationARN = target.ARN
+	} else {
+		// Without a destination SQS returns messages to their source queues,
+		// which only works for a queue that is some queue's dead letter queue
+		sources, err := h.awsClient.ListSQSDeadLetterSources(ctx, queue.URL)
+		if err != nil {
+			return h.createErrorResponse(fmt.Sprintf("failed to list source queues: %v", err))
+		}
+		if len(sources) == 0 {
+			return h.createErrorResponse(fmt.Sprintf("queue %s is not a dead letter queue of any queue; pass destinationQueue", queue.Name))
+		}
+		destination = strings.Join(sources, ", ")
+	}
+
+	tasks, err := h.awsClient.ListSQSMoveTasks(ctx, queue.ARN)
+	if err != nil {
+		return h.createErrorResponse(fmt.Sprintf("failed to list redrives: %v", err))
+	}
+	for _, task := range tasks {
+		if task.Status == "RUNNING" {
+			return h.createErrorResponse(fmt.Sprintf("a redrive of %s is already running (%d messages moved)", queue.Name, task.Moved))
+		}
+	}
+
+	handle, err := h.awsClient.StartSQSRedrive(ctx, queue.ARN, destinationARN, int32(rate))
+	if err != nil {
+		return h.createErrorResponse(fmt.Sprintf("failed to start redrive: %v", err))
+	}
+
+	data := map[string]interface{}{
+		"queue":       queue.Name,
+		"destination": destination,
+		"messages":    queue.Visible,
+		"taskHandle":  handle,
+		"queueUri":    sqsQueuesURI + "/" + queue.Name,
+	}
+	if rate > 0 {
+		data["maxMessagesPerSecond"] = rate
+	}
+	return h.createSuccessResponse("SQS redrive started successfully", data)
+}
@@ -0,0 +1,66 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	"aws-mcp-server/internal/config"
+	"aws-mcp-server/internal/logging"
+	"aws-mcp-server/pkg/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func age(seconds float64) *float64 {
+	return &seconds
+}
+
+func TestFormatSQSQueues(t *testing.T) {
+	queues := []types.SQSQueue{
+		{Name: "quiet", ARN: "arn:aws:sqs:us-east-1:123456789012:quiet", Visible: 2, OldestMessageAge: age(30), RetentionPeriod: 345600},
+		{Name: "orders", ARN: "arn:aws:sqs:us-east-1:123456789012:orders", Visible: 900, InFlight: 10, OldestMessageAge: age(3600), RetentionPeriod: 345600,
+			DeadLetterQueueARN: "arn:aws:sqs:us-east-1:123456789012:orders-dlq", MaxReceiveCount: 5},
+		{Name: "orders-dlq", ARN: "arn:aws:sqs:us-east-1:123456789012:orders-dlq", Visible: 12, OldestMessageAge: age(7200), RetentionPeriod: 1209600},
+		{Name: "busy", ARN: "arn:aws:sqs:us-east-1:123456789012:busy", Visible: 50},
+	}
+
+	formatted := formatSQSQueues(queues)
+	assert.Equal(t, 4, formatted["total_queues"])
+	assert.Equal(t, int64(964), formatted["visible_messages"])
+	assert.Equal(t, []string{"orders", "orders-dlq"}, formatted["at_risk"])
+
+	items := formatted["queues"].([]map[string]interface{})
+	require.Len(t, items, 4)
+	assert.Equal(t, []string{"orders", "orders-dlq", "busy", "quiet"}, []string{
+		items[0]["name"].(string), items[1]["name"].(string), items[2]["name"].(string), items[3]["name"].(string),
+	})
+	assert.Equal(t, "orders-dlq", items[0]["redrives_to"])
+	assert.Contains(t, items[0]["issues"].([]string)[0], "consumers are not keeping up with 900 messages")
+	assert.Equal(t, true, items[1]["dead_letter_queue"])
+	assert.Contains(t, items[1]["issues"].([]string)[0], "12 messages failed processing")
+	assert.Len(t, items[1]["issues"], 1, "an old message in a dead letter queue is not a backlog")
+	assert.NotContains(t, items[2], "oldest_message_age_seconds")
+}
+
+func TestSQSQueueIssuesRetention(t *testing.T) {
+	queue := types.SQSQueue{Name: "events", Visible: 40, OldestMessageAge: age(3.5 * 86400), RetentionPeriod: 4 * 86400}
+	issues := sqsQueueIssues(queue, false)
+	require.Len(t, issues, 1)
+	assert.Contains(t, issues[0], "deleted by the 96h0m0s retention period in 12h0m0s")
+
+	queue.Visible = 0
+	assert.Empty(t, sqsQueueIssues(queue, false), "an empty queue has no backlog")
+}
+
+func TestSQSToolsValidateArguments(t *testing.T) {
+	h := NewToolHandler(&config.Config{}, nil, logging.NewLogger("error", "text"))
+
+	result, err := h.CallTool(context.Background(), "purge-sqs-queue", map[string]interface{}{})
+	require.NoError(t, err)
+	assert.Equal(t, "queue is required", decodeToolResult(t, result)["error"])
+
+	result, err = h.CallTool(context.Background(), "redrive-sqs-dlq", map[string]interface{}{"queue": "orders-dlq", "maxMessagesPerSecond": float64(1000)})
+	require.NoError(t, err)
+	assert.Equal(t, "maxMessagesPerSecond must be between 1 and 500", decodeToolResult(t, result)["error"])
+}
@@ -0,0 +1,306 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"aws-mcp-server/pkg/aws"
+	"aws-mcp-server/pkg/types"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const (
+	// stateMachinesURI lists the Step Functions state machines with the
+	// outcome of their recent executions
+	stateMachinesURI = "aws://stepfunctions/state-machines"
+	// executionsTemplate is the URI template of the recent executions of one
+	// state machine, by its name
+	executionsTemplate = "aws://stepfunctions/state-machines/{name}/executions"
+	// recentExecutions is how many of the newest executions are summarized
+	// per state machine
+	recentExecutions = 20
+	// maxListedExecutions caps the executions listed for one state machine
+	maxListedExecutions = 50
+	// maxDescribedFailures caps how many failed executions are described with
+	// their error, cause and failed state
+	maxDescribedFailures = 5
+)
+
+// failedExecutionStatuses are the statuses of executions that did not succeed
+// and can be redriven
+var failedExecutionStatuses = map[string]bool{"FAILED": true, "TIMED_OUT": true, "ABORTED": true}
+
+// readStateMachines lists the state machines with the statuses of their most
+// recent executions, those whose last execution failed first
+func (h *ResourceHandler) readStateMachines(ctx context.Context) (*mcp.ReadResourceResult, error) {
+	machines, err := h.awsClient.ListStateMachines(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list state machines: %w", err)
+	}
+
+	recent := make(map[string][]types.Execution, len(machines))
+	for _, machine := range machines {
+		executions, err := h.awsClient.ListExecutions(ctx, machine.ARN, recentExecutions)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list executions of %s: %w", machine.Name, err)
+		}
+		recent[machine.ARN] = executions
+	}
+
+	jsonData, err := json.MarshalIndent(h.formatStateMachines(machines, recent), "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal state machines data: %w", err)
+	}
+
+	return &mcp.ReadResourceResult{
+		Contents: []mcp.ResourceContents{
+			&mcp.TextResourceContents{
+				URI:      stateMachinesURI,
+				MIMEType: "application/json",
+				Text:     string(jsonData),
+			},
+		},
+	}, nil
+}
+
+// formatStateMachines counts the recent executions of each state machine by
+// status and orders the machines whose last execution failed first
+func (h *ResourceHandler) formatStateMachines(machines []types.StateMachine, recent map[string][]types.Execution) map[string]interface{} {
+	items := make([]map[string]interface{}, 0, len(machines))
+	failing := []string{}
+	for _, machine := range machines {
+		executions := recent[machine.ARN]
+		byStatus := make(map[string]int)
+		for _, execution := range executions {
+			byStatus[execution.Status]++
+		}
+
+		item := map[string]interface{}{
+			"name":           machine.Name,
+			"type":           machine.Type,
+			"created":        h.times.Format(machine.CreatedAt),
+			"executions_uri": fmt.Sprintf("%s/%s/executions", stateMachinesURI, machine.Name),
+			"recent":         byStatus,
+			"last_failed":    false,
+		}
+		if len(executions) > 0 {
+			last := executions[0]
+			item["last_status"] = last.Status
+			item["last_started"] = h.times.Format(last.StartedAt)
+			if failedExecutionStatuses[last.Status] {
+				item["last_failed"] = true
+				failing = append(failing, machine.Name)
+			}
+		}
+		items = append(items, item)
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		fi, fj := items[i]["last_failed"].(bool), items[j]["last_failed"].(bool)
+		if fi != fj {
+			return fi
+		}
+		return items[i]["name"].(string) < items[j]["name"].(string)
+	})
+	sort.Strings(failing)
+
+	return map[string]interface{}{
+		"total":                  len(machines),
+		"failing":                failing,
+		"recent_executions_each": recentExecutions,
+		"state_machines":         items,
+	}
+}
+
+// findStateMachine looks up a state machine by name or ARN
+func findStateMachine(ctx context.Context, client *aws.Client, nameOrARN string) (*types.StateMachine, error) {
+	machines, err := client.ListStateMachines(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list state machines: %w", err)
+	}
+	for _, machine := range machines {
+		if machine.Name == nameOrARN || machine.ARN == nameOrARN {
+			return &machine, nil
+		}
+	}
+	return nil, fmt.Errorf("state machine %s not found", nameOrARN)
+}
+
+// readExecutions lists the recent executions of the state machine in the URI
+// with the error, cause and failed state of the latest failures
+func (h *ResourceHandler) readExecutions(ctx context.Context, req resourceRequest) (*mcp.ReadResourceResult, error) {
+	uri, name := req.URI, req.Param("name")
+	if name == "" {
+		return nil, fmt.Errorf("invalid executions URI %s, use %s", uri, executionsTemplate)
+	}
+
+	machine, err := findStateMachine(ctx, h.awsClient, name)
+	if err != nil {
+		return nil, err
+	}
+	executions, err := h.awsClient.ListExecutions(ctx, machine.ARN, maxListedExecutions)
+	if err != nil {
+		return nil, err
+	}
+
+	described := 0
+	for i, execution := range executions {
+		if !failedExecutionStatuses[execution.Status] || described == maxDescribedFailures {
+			continue
+		}
+		detail, err := h.awsClient.DescribeExecution(ctx, execution.ARN)
+		if err != nil {
+			return nil, err
+		}
+		executions[i] = *detail
+		described++
+	}
+
+	data := h.formatExecutions(*machine, executions)
+	jsonData, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal executions data: %w", err)
+	}
+
+	return &mcp.ReadResourceResult{
+		Contents: []mcp.ResourceContents{
+			&mcp.TextResourceContents{
+				URI:      uri,
+				MIMEType: "application/json",
+				Text:     string(jsonData),
+			},
+		},
+	}, nil
+}
+
+// formatExecutions describes the executions of a state machine and groups the
+// described failures by error so repeated causes stand out
+func (h *ResourceHandler) formatExecutions(machine types.StateMachine, executions []types.Execution) map[string]interface{} {
+	items := make([]map[string]interface{}, 0, len(executions))
+	byStatus := make(map[string]int)
+	byError := make(map[string]int)
+	for _, execution := range executions {
+		byStatus[execution.Status]++
+		item := map[string]interface{}{
+			"name":          execution.Name,
+			"arn":           execution.ARN,
+			"status":        execution.Status,
+			"started":       h.times.Format(execution.StartedAt),
+			"redrive_count": execution.RedriveCount,
+		}
+		if execution.StoppedAt != nil {
+			item["stopped"] = h.times.Format(*execution.StoppedAt)
+			item["duration_seconds"] = int(execution.StoppedAt.Sub(execution.StartedAt).Seconds())
+		}
+		if execution.Error != "" {
+			item["error"] = execution.Error
+			byError[execution.Error]++
+		}
+		if execution.Cause != "" {
+			item["cause"] = execution.Cause
+		}
+		if execution.FailedState != "" {
+			item["failed_state"] = execution.FailedState
+		}
+		if execution.RedriveStatus != "" {
+			item["redrivable"] = execution.RedriveStatus == "REDRIVABLE"
+			if execution.RedriveStatusReason != "" {
+				item["redrive_status_reason"] = execution.RedriveStatusReason
+			}
+		}
+		items = append(items, item)
+	}
+
+	data := map[string]interface{}{
+		"state_machine": machine.Name,
+		"arn":           machine.ARN,
+		"type":          machine.Type,
+		"total":         len(executions),
+		"by_status":     byStatus,
+		"executions":    items,
+	}
+	if len(byError) > 0 {
+		data["failures_by_error"] = byError
+	}
+	if machine.Type == "EXPRESS" {
+		data["note"] = "Express executions cannot be redriven; start a new execution instead"
+	}
+	return data
+}
+
+// startExecution starts a new execution of a state machine, e.g. to rerun a
+// workflow whose failure was fixed, with the given JSON input
+func (h *ToolHandler) startExecution(ctx context.Context, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	stateMachine, _ := arguments["stateMachine"].(string)
+	if stateMachine == "" {
+		return h.createErrorResponse("stateMachine is required")
+	}
+	input, _ := arguments["input"].(string)
+	if strings.TrimSpace(input) == "" {
+		input = "{}"
+	}
+	if !json.Valid([]byte(input)) {
+		return h.createErrorResponse("input must be valid JSON")
+	}
+	name, _ := arguments["name"].(string)
+
+	machine, err := findStateMachine(ctx, h.awsClient, stateMachine)
+	if err != nil {
+		return h.createErrorResponse(err.Error())
+	}
+
+	executionARN, err := h.awsClient.StartExecution(ctx, machine.ARN, name, input)
+	if err != nil {
+		return h.createErrorResponse(fmt.Sprintf("failed to start execution: %v", err))
+	}
+
+	return h.createSuccessResponse("Execution started successfully", map[string]interface{}{
+		"stateMachine": machine.Name,
+		"executionArn": executionARN,
+		"startedAt":    h.times.Format(time.Now()),
+		"note":         fmt.Sprintf("Read %s/%s/executions to follow its status", stateMachinesURI, machine.Name),
+	})
+}
+
+// redriveExecution restarts a failed, timed out or aborted standard execution
+// from the state that did not succeed, keeping the results of the states that did
+func (h *ToolHandler) redriveExecution(ctx context.Context, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	executionARN, _ := arguments["executionArn"].(string)
+	if executionARN == "" {
+		return h.createErrorResponse("executionArn is required")
+	}
+
+	execution, err := h.awsClient.DescribeExecution(ctx, executionARN)
+	if err != nil {
+		return h.createErrorResponse(fmt.Sprintf("failed to describe execution: %v", err))
+	}
+	if !failedExecutionStatuses[execution.Status] {
+		return h.createErrorResponse(fmt.Sprintf("execution %s is %s; only failed, timed out or aborted executions can be redriven", execution.Name, execution.Status))
+	}
+	if execution.RedriveStatus != "" && execution.RedriveStatus != "REDRIVABLE" {
+		reason := execution.RedriveStatusReason
+		if reason == "" {
+			reason = strings.ToLower(execution.RedriveStatus)
+		}
+		return h.createErrorResponse(fmt.Sprintf("execution %s cannot be redriven: %s; start a new execution instead", execution.Name, reason))
+	}
+
+	if err := h.awsClient.RedriveExecution(ctx, executionARN); err != nil {
+		return h.createErrorResponse(fmt.Sprintf("failed to redrive execution: %v", err))
+	}
+
+	data := map[string]interface{}{
+		"executionArn":   executionARN,
+		"name":           execution.Name,
+		"previousStatus": execution.Status,
+		"redriveCount":   execution.RedriveCount + 1,
+	}
+	if execution.FailedState != "" {
+		data["resumesAt"] = execution.FailedState
+	}
+	return h.createSuccessResponse("Execution redriven successfully", data)
+}
@@ -0,0 +1,83 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"aws-mcp-server/internal/config"
+	"aws-mcp-server/internal/logging"
+	"aws-mcp-server/pkg/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatStateMachines(t *testing.T) {
+	h := NewResourceHandler(&config.Config{}, nil)
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	data := h.formatStateMachines([]types.StateMachine{
+		{ARN: "arn:sm:billing", Name: "billing", Type: "STANDARD"},
+		{ARN: "arn:sm:etl", Name: "etl", Type: "STANDARD"},
+		{ARN: "arn:sm:idle", Name: "idle", Type: "EXPRESS"},
+	}, map[string][]types.Execution{
+		"arn:sm:billing": {{Status: "SUCCEEDED", StartedAt: now}, {Status: "FAILED", StartedAt: now.Add(-time.Hour)}},
+		"arn:sm:etl":     {{Status: "TIMED_OUT", StartedAt: now}, {Status: "SUCCEEDED", StartedAt: now.Add(-time.Hour)}},
+	})
+
+	assert.Equal(t, 3, data["total"])
+	assert.Equal(t, []string{"etl"}, data["failing"], "only machines whose last execution failed")
+	machines := data["state_machines"].([]map[string]interface{})
+	require.Len(t, machines, 3)
+	assert.Equal(t, "etl", machines[0]["name"])
+	assert.Equal(t, "billing", machines[1]["name"])
+	assert.Equal(t, map[string]int{"SUCCEEDED": 1, "FAILED": 1}, machines[1]["recent"])
+	assert.Equal(t, "aws://stepfunctions/state-machines/billing/executions", machines[1]["executions_uri"])
+	assert.NotContains(t, machines[2], "last_status")
+}
+
+func TestFormatExecutions(t *testing.T) {
+	h := NewResourceHandler(&config.Config{}, nil)
+	started := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	stopped := started.Add(90 * time.Second)
+
+	data := h.formatExecutions(types.StateMachine{Name: "etl", Type: "STANDARD"}, []types.Execution{
+		{Name: "run-3", Status: "FAILED", StartedAt: started, StoppedAt: &stopped, Error: "States.TaskFailed",
+			Cause: "Lambda timed out", FailedState: "LoadWarehouse", RedriveStatus: "REDRIVABLE"},
+		{Name: "run-2", Status: "FAILED", StartedAt: started, Error: "States.TaskFailed",
+			RedriveStatus: "NOT_REDRIVABLE", RedriveStatusReason: "Execution redrivable period exceeded"},
+		{Name: "run-1", Status: "SUCCEEDED", StartedAt: started},
+	})
+
+	assert.Equal(t, map[string]int{"FAILED": 2, "SUCCEEDED": 1}, data["by_status"])
+	assert.Equal(t, map[string]int{"States.TaskFailed": 2}, data["failures_by_error"])
+	executions := data["executions"].([]map[string]interface{})
+	assert.Equal(t, "LoadWarehouse", executions[0]["failed_state"])
+	assert.Equal(t, 90, executions[0]["duration_seconds"])
+	assert.Equal(t, true, executions[0]["redrivable"])
+	assert.Equal(t, false, executions[1]["redrivable"])
+	assert.NotContains(t, executions[2], "redrivable")
+	assert.NotContains(t, data, "note")
+
+	express := h.formatExecutions(types.StateMachine{Name: "events", Type: "EXPRESS"}, nil)
+	assert.Contains(t, express["note"], "cannot be redriven")
+	assert.NotContains(t, express, "failures_by_error")
+}
+
+func TestStepFunctionsToolValidation(t *testing.T) {
+	h := NewToolHandler(&config.Config{}, nil, logging.NewLogger("error", "text"))
+	ctx := context.Background()
+
+	result, err := h.CallTool(ctx, "start-execution", map[string]interface{}{})
+	require.NoError(t, err)
+	assert.Equal(t, "stateMachine is required", decodeToolResult(t, result)["error"])
+
+	result, err = h.CallTool(ctx, "start-execution", map[string]interface{}{"stateMachine": "etl", "input": "{not json"})
+	require.NoError(t, err)
+	assert.Equal(t, "input must be valid JSON", decodeToolResult(t, result)["error"])
+
+	result, err = h.CallTool(ctx, "redrive-execution", map[string]interface{}{})
+	require.NoError(t, err)
+	assert.Equal(t, "executionArn is required", decodeToolResult(t, result)["error"])
+}
@@ -0,0 +1,164 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"aws-mcp-server/pkg/approval"
+	"aws-mcp-server/pkg/logs"
+	"aws-mcp-server/pkg/summarize"
+	"aws-mcp-server/pkg/types"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const (
+	// dailyDigestURI is a narrative of the last day written server-side
+	dailyDigestURI = "aws://digest/daily"
+	// digestPeriod is how far back the daily digest looks
+	digestPeriod = 24 * time.Hour
+	// maxDigestChanges caps the alarm state changes the digest is written from
+	maxDigestChanges = 200
+)
+
+// logNarrativeInput describes a log summary as plain text for the model
+func logNarrativeInput(source string, summary logs.Summary) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Source: %s\n", source)
+	fmt.Fprintf(&b, "Entries: %d from %s to %s\n", summary.Total, summary.Start.UTC().Format(time.RFC3339), summary.End.UTC().Format(time.RFC3339))
+
+	levels := make([]string, 0, len(summary.Levels))
+	for level, count := range summary.Levels {
+		levels = append(levels, fmt.Sprintf("%s=%d", level, count))
+	}
+	sort.Strings(levels)
+	fmt.Fprintf(&b, "Levels: %s\n", strings.Join(levels, " "))
+
+	writePatterns := func(title string, patterns []logs.Pattern) {
+		fmt.Fprintf(&b, "\n%s:\n", title)
+		for _, pattern := range patterns {
+			fmt.Fprintf(&b, "- %dx %s (first %s, last %s), e.g. %s\n", pattern.Count, pattern.Pattern,
+				pattern.FirstSeen.UTC().Format(time.RFC3339), pattern.LastSeen.UTC().Format(time.RFC3339), pattern.Example)
+		}
+	}
+	writePatterns("Top patterns", summary.Patterns)
+	writePatterns("Top error patterns", summary.ErrorPatterns)
+	return b.String()
+}
+
+// addNarrative asks the summarizer for a narrative of input and adds it to
+// data. Failures are reported in data rather than failing the tool, since
+// the structured summary is still useful without the narrative.
+func addNarrative(ctx context.Context, summaries *summarize.Summarizer, kind, input string, data map[string]interface{}) {
+	if summaries == nil {
+		data["narrative_error"] = "server-side summaries are disabled, set summaries.enabled to have Bedrock write them"
+		return
+	}
+
+	summary, err := summaries.Summarize(ctx, kind, input)
+	if err != nil {
+		data["narrative_error"] = err.Error()
+		return
+	}
+	data["narrative"] = summary.Text
+	data["narrative_model"] = summary.Model
+	data["narrative_cached"] = summary.Cached
+	if summary.Truncated {
+		data["narrative_truncated"] = true
+	}
+}
+
+// readDailyDigest returns a narrative of the last day: alarms firing now,
+// alarm state changes and actions waiting for approval
+func (h *ResourceHandler) readDailyDigest(ctx context.Context) (*mcp.ReadResourceResult, error) {
+	if h.summaries == nil {
+		return nil, fmt.Errorf("server-side summaries are disabled, set summaries.enabled to have Bedrock write the digest")
+	}
+
+	end := time.Now()
+	start := end.Add(-digestPeriod)
+
+	alarms, err := h.awsClient.ListAlarms(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list CloudWatch alarms: %w", err)
+	}
+	changes, err := h.awsClient.DescribeAlarmStateChanges(ctx, start, end, maxDigestChanges)
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe alarm history: %w", err)
+	}
+	var pending []approval.Request
+	if h.approvals != nil {
+		pending = h.approvals.Pending()
+	}
+
+	alarms, changes, ignored := withoutIgnoredAlarms(alarms, changes, h.ignored, end)
+	input, counts := digestInput(alarms, changes, pending, start, end)
+	counts["ignored_alarms"] = ignored
+	summary, err := h.summaries.Summarize(ctx, summarize.KindDigest, input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to write the daily digest: %w", err)
+	}
+
+	data := map[string]interface{}{
+		"start":        h.times.Format(start),
+		"end":          h.times.Format(end),
+		"counts":       counts,
+		"digest":       summary.Text,
+		"model":        summary.Model,
+		"generated_at": h.times.Format(summary.GeneratedAt),
+		"cached":       summary.Cached,
+	}
+
+	jsonData, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal digest data: %w", err)
+	}
+
+	return &mcp.ReadResourceResult{
+		Contents: []mcp.ResourceContents{
+			&mcp.TextResourceContents{
+				URI:      dailyDigestURI,
+				MIMEType: "application/json",
+				Text:     string(jsonData),
+			},
+		},
+	}, nil
+}
+
+// digestInput describes the facts of the digest as plain text for the model.
+// The window is truncated to the hour so the digest stays cached within it.
+func digestInput(alarms []types.Alarm, changes []types.AlarmStateChange, pending []approval.Request, start, end time.Time) (string, map[string]int) {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Period: %s to %s\n", start.UTC().Truncate(time.Hour).Format(time.RFC3339), end.UTC().Truncate(time.Hour).Format(time.RFC3339))
+
+	firing := 0
+	b.WriteString("\nAlarms firing now:\n")
+	for _, alarm := range alarms {
+		if alarm.State != "ALARM" {
+			continue
+		}
+		firing++
+		fmt.Fprintf(&b, "- %s since %s: %s\n", alarm.Name, alarm.StateUpdated.UTC().Format(time.RFC3339), alarm.StateReason)
+	}
+
+	b.WriteString("\nAlarm state changes:\n")
+	for _, change := range changes {
+		fmt.Fprintf(&b, "- %s %s: %s -> %s\n", change.Time.UTC().Format(time.RFC3339), change.AlarmName, change.OldState, change.NewState)
+	}
+
+	b.WriteString("\nActions waiting for approval:\n")
+	for _, request := range pending {
+		fmt.Fprintf(&b, "- %s requested by %s at %s: %s\n", request.Tool, request.RequestedBy,
+			request.RequestedAt.UTC().Format(time.RFC3339), strings.Join(request.Reasons, "; "))
+	}
+
+	return b.String(), map[string]int{
+		"alarms_firing":     firing,
+		"alarm_changes":     len(changes),
+		"pending_approvals": len(pending),
+	}
+}
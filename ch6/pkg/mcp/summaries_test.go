@@ -0,0 +1,75 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"aws-mcp-server/internal/config"
+	"aws-mcp-server/internal/logging"
+	"aws-mcp-server/pkg/approval"
+	"aws-mcp-server/pkg/logs"
+	"aws-mcp-server/pkg/summarize"
+	"aws-mcp-server/pkg/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSummarizeLogsNarrative(t *testing.T) {
+	base := time.Now().Add(-10 * time.Minute)
+	handler := NewToolHandler(&config.Config{}, nil, logging.NewLogger("error", "text"))
+	handler.logs = &fakeLogBackend{entries: []logs.Entry{
+		{Timestamp: base, Message: "ERROR db timeout after 2500ms", Level: "error"},
+		{Timestamp: base.Add(time.Minute), Message: "request served"},
+	}}
+	ctx := context.Background()
+
+	result, err := handler.CallTool(ctx, "summarize-logs", map[string]interface{}{"source": "app", "narrative": true})
+	require.NoError(t, err)
+	payload := decodeToolResult(t, result)
+	assert.Equal(t, true, payload["success"], "the summary is returned without the narrative")
+	assert.Contains(t, payload["narrative_error"], "summaries.enabled")
+
+	var prompt string
+	handler.summaries = summarize.New(config.SummariesConfig{Model: "haiku"}, func(_ context.Context, _, input string) (string, error) {
+		prompt = input
+		return "Database timeouts began ten minutes ago.", nil
+	})
+
+	result, err = handler.CallTool(ctx, "summarize-logs", map[string]interface{}{"source": "app", "narrative": true})
+	require.NoError(t, err)
+	payload = decodeToolResult(t, result)
+	assert.Equal(t, "Database timeouts began ten minutes ago.", payload["narrative"])
+	assert.Equal(t, false, payload["narrative_cached"])
+	assert.Contains(t, prompt, "1x ERROR db timeout after <num>")
+	assert.Contains(t, prompt, "Levels: error=1")
+
+	result, err = handler.CallTool(ctx, "summarize-logs", map[string]interface{}{"source": "app"})
+	require.NoError(t, err)
+	assert.Nil(t, decodeToolResult(t, result)["narrative"], "narratives are only written on request")
+}
+
+func TestDigestInput(t *testing.T) {
+	end := time.Date(2025, 6, 2, 9, 41, 0, 0, time.UTC)
+	alarms := []types.Alarm{
+		{Name: "api-5xx", State: "ALARM", StateReason: "5xx above 5%", StateUpdated: end.Add(-time.Hour)},
+		{Name: "db-cpu", State: "OK"},
+	}
+	changes := []types.AlarmStateChange{
+		{Time: end.Add(-time.Hour), AlarmName: "api-5xx", OldState: "OK", NewState: "ALARM"},
+	}
+	pending := []approval.Request{{Tool: "terminate-ec2-instance", RequestedBy: "alice", RequestedAt: end, Reasons: []string{"production"}}}
+
+	input, counts := digestInput(alarms, changes, pending, end.Add(-digestPeriod), end)
+	assert.Equal(t, map[string]int{"alarms_firing": 1, "alarm_changes": 1, "pending_approvals": 1}, counts)
+	assert.Contains(t, input, "Period: 2025-06-01T09:00:00Z to 2025-06-02T09:00:00Z")
+	assert.Contains(t, input, "- api-5xx since 2025-06-02T08:41:00Z: 5xx above 5%")
+	assert.NotContains(t, input, "db-cpu")
+	assert.Contains(t, input, "api-5xx: OK -> ALARM")
+	assert.Contains(t, input, "terminate-ec2-instance requested by alice")
+
+	h := NewResourceHandler(&config.Config{}, nil)
+	_, err := h.ReadResource(context.Background(), dailyDigestURI)
+	assert.ErrorContains(t, err, "summaries.enabled")
+}
@@ -0,0 +1,201 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"aws-mcp-server/pkg/suppress"
+	"aws-mcp-server/pkg/types"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const (
+	// suppressionsURI lists the acknowledged, snoozed and suppressed alerts
+	suppressionsURI = "aws://alerts/suppressions"
+	// maxSnooze bounds snoozes; longer silences are suppressions
+	maxSnooze = 30 * 24 * time.Hour
+)
+
+// acknowledgeAlert marks an alarm as known in its current state
+func (h *ToolHandler) acknowledgeAlert(ctx context.Context, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	return h.silenceAlert(ctx, suppress.KindAcknowledge, arguments)
+}
+
+// snoozeAlert hides an alarm for a while
+func (h *ToolHandler) snoozeAlert(ctx context.Context, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	return h.silenceAlert(ctx, suppress.KindSnooze, arguments)
+}
+
+// suppressAlert hides an alarm until it is unsuppressed or the optional duration ends
+func (h *ToolHandler) suppressAlert(ctx context.Context, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	return h.silenceAlert(ctx, suppress.KindSuppress, arguments)
+}
+
+// silenceAlert records an acknowledgment, snooze or suppression of an alarm.
+// The reason is required so the audit log explains why the alert was silenced.
+func (h *ToolHandler) silenceAlert(ctx context.Context, kind suppress.Kind, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	alarmName, _ := arguments["alarmName"].(string)
+	if alarmName == "" {
+		return h.createErrorResponse("alarmName is required")
+	}
+
+	reason, _ := arguments["reason"].(string)
+	if strings.TrimSpace(reason) == "" {
+		return h.createErrorResponse("reason is required, e.g. known issue tracked in INC-123")
+	}
+
+	now := time.Now()
+	entry := suppress.Entry{
+		Alert:     alarmName,
+		Kind:      kind,
+		Reason:    reason,
+		CreatedBy: h.callerRole(ctx),
+		CreatedAt: now,
+	}
+
+	value, _ := arguments["duration"].(string)
+	if kind == suppress.KindSnooze && value == "" {
+		return h.createErrorResponse("duration is required, e.g. 4h or 2d")
+	}
+	if value != "" && kind != suppress.KindAcknowledge {
+		duration, err := parseDurationParam(value)
+		if err != nil || duration <= 0 {
+			return h.createErrorResponse(fmt.Sprintf("invalid duration %q, use e.g. 30m, 4h or 2d", value))
+		}
+		if kind == suppress.KindSnooze && duration > maxSnooze {
+			return h.createErrorResponse("snoozes last at most 30 days, use suppress-alert for longer silences")
+		}
+		expires := now.Add(duration)
+		entry.ExpiresAt = &expires
+	}
+
+	alarms, err := h.awsClient.ListAlarms(ctx, alarmName)
+	if err != nil {
+		return h.createErrorResponse(fmt.Sprintf("failed to get alarm: %v", err))
+	}
+	if len(alarms) == 0 {
+		return h.createErrorResponse(fmt.Sprintf("alarm %s not found", alarmName))
+	}
+
+	if err := h.suppressions.Add(entry); err != nil {
+		h.logger.WithError(err).WithField("alarmName", alarmName).Error("Failed to save suppressions")
+		return h.createErrorResponse(fmt.Sprintf("failed to save the %s: %v", kind, err))
+	}
+
+	data := formatSuppression(entry, h.times.Format)
+	data["alarmState"] = alarms[0].State
+
+	var message string
+	switch {
+	case kind == suppress.KindAcknowledge:
+		message = fmt.Sprintf("Acknowledged alarm %s in state %s; the acknowledgment lapses when its state changes", alarmName, alarms[0].State)
+	case entry.ExpiresAt != nil:
+		message = fmt.Sprintf("%s alarm %s until %s", pastTense(kind), alarmName, entry.ExpiresAt.UTC().Format(time.RFC3339))
+	default:
+		message = fmt.Sprintf("Suppressed alarm %s until it is unsuppressed", alarmName)
+	}
+
+	return h.createSuccessResponse(message, data)
+}
+
+// unsuppressAlert lifts an acknowledgment, snooze or suppression
+func (h *ToolHandler) unsuppressAlert(ctx context.Context, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	alarmName, _ := arguments["alarmName"].(string)
+	if alarmName == "" {
+		return h.createErrorResponse("alarmName is required")
+	}
+
+	entry, err := h.suppressions.Remove(alarmName)
+	if err != nil {
+		return h.createErrorResponse(err.Error())
+	}
+
+	return h.createSuccessResponse(fmt.Sprintf("Alarm %s is no longer silenced", alarmName), map[string]interface{}{
+		"alert":   alarmName,
+		"removed": formatSuppression(entry, h.times.Format),
+	})
+}
+
+// readSuppressions lists the alerts that are currently silenced
+func (h *ResourceHandler) readSuppressions(ctx context.Context) (*mcp.ReadResourceResult, error) {
+	active := h.suppressions.Active(time.Now())
+
+	entries := make([]map[string]interface{}, 0, len(active))
+	kindCount := map[suppress.Kind]int{
+		suppress.KindAcknowledge: 0,
+		suppress.KindSnooze:      0,
+		suppress.KindSuppress:    0,
+	}
+	for _, entry := range active {
+		formatted := formatSuppression(entry, h.times.Format)
+		if entry.ExpiresAt != nil {
+			if relative := h.times.Relative(*entry.ExpiresAt); relative != "" {
+				formatted["expires"] = relative
+			}
+		}
+		entries = append(entries, formatted)
+		kindCount[entry.Kind]++
+	}
+
+	formatted := map[string]interface{}{
+		"count":        len(entries),
+		"suppressions": entries,
+		"summary":      kindCount,
+		"instructions": "Use unsuppress-alert to bring an alarm back; snoozed and suppressed alarms are left out of aws://cloudwatch/alarms",
+	}
+
+	jsonData, err := json.MarshalIndent(formatted, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal suppressions: %w", err)
+	}
+
+	return &mcp.ReadResourceResult{
+		Contents: []mcp.ResourceContents{
+			&mcp.TextResourceContents{
+				URI:      suppressionsURI,
+				MIMEType: "application/json",
+				Text:     string(jsonData),
+			},
+		},
+	}, nil
+}
+
+// silencedAlarm reports how an alarm is silenced at now. Acknowledgments only
+// cover the state the alarm was in when it was acknowledged.
+func (h *ResourceHandler) silencedAlarm(alarm types.Alarm, now time.Time) (suppress.Entry, bool) {
+	entry, ok := h.suppressions.Get(alarm.Name, now)
+	if !ok {
+		return suppress.Entry{}, false
+	}
+	if entry.Kind == suppress.KindAcknowledge && alarm.StateUpdated.After(entry.CreatedAt) {
+		return suppress.Entry{}, false
+	}
+	return entry, true
+}
+
+// formatSuppression converts an entry for responses using the given time format
+func formatSuppression(entry suppress.Entry, formatTime func(time.Time) string) map[string]interface{} {
+	formatted := map[string]interface{}{
+		"alert":      entry.Alert,
+		"kind":       entry.Kind,
+		"reason":     entry.Reason,
+		"created_by": entry.CreatedBy,
+		"created_at": formatTime(entry.CreatedAt),
+	}
+	if entry.ExpiresAt != nil {
+		formatted["expires_at"] = formatTime(*entry.ExpiresAt)
+	}
+	return formatted
+}
+
+// pastTense names what a snooze or suppression did, e.g. Snoozed
+func pastTense(kind suppress.Kind) string {
+	if kind == suppress.KindSnooze {
+		return "Snoozed"
+	}
+	return "Suppressed"
+}
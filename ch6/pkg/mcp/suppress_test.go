@@ -0,0 +1,65 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"aws-mcp-server/internal/config"
+	"aws-mcp-server/internal/logging"
+	"aws-mcp-server/pkg/suppress"
+	"aws-mcp-server/pkg/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatAlarmsForAISilencedAlarms(t *testing.T) {
+	h := NewResourceHandler(&config.Config{}, nil)
+	h.suppressions = suppress.NewList()
+	now := time.Now()
+	until := now.Add(time.Hour)
+
+	require.NoError(t, h.suppressions.Add(suppress.Entry{Alert: "batch-errors", Kind: suppress.KindSnooze, Reason: "backfill", CreatedAt: now, ExpiresAt: &until}))
+	require.NoError(t, h.suppressions.Add(suppress.Entry{Alert: "disk-full", Kind: suppress.KindAcknowledge, Reason: "cleanup running", CreatedBy: "sre", CreatedAt: now.Add(-time.Minute)}))
+	require.NoError(t, h.suppressions.Add(suppress.Entry{Alert: "cpu-high", Kind: suppress.KindAcknowledge, Reason: "load test", CreatedAt: now.Add(-time.Hour)}))
+
+	alarms := []types.Alarm{
+		{Name: "batch-errors", State: "ALARM", StateUpdated: now.Add(-2 * time.Hour)},
+		{Name: "disk-full", State: "ALARM", StateUpdated: now.Add(-time.Hour)},
+		// Changed state after it was acknowledged, so it needs attention again
+		{Name: "cpu-high", State: "ALARM", StateUpdated: now.Add(-10 * time.Minute)},
+	}
+
+	formatted := h.formatAlarmsForAI(alarms, nil)
+	assert.Equal(t, 1, formatted["silenced_alarms"])
+	assert.Equal(t, 2, formatted["summary_by_state"].(map[string]int)["ALARM"])
+
+	firing := formatted["alarms_by_state"].(map[string][]map[string]interface{})["ALARM"]
+	require.Len(t, firing, 2)
+	assert.Equal(t, "cpu-high", firing[0]["name"])
+	assert.NotContains(t, firing[0], "acknowledged")
+	assert.Equal(t, "disk-full", firing[1]["name"])
+	assert.Equal(t, "cleanup running", firing[1]["acknowledged"].(map[string]interface{})["reason"])
+}
+
+func TestSilenceAlertValidatesArguments(t *testing.T) {
+	h := NewToolHandler(&config.Config{}, nil, logging.NewLogger("error", "text"))
+	ctx := context.Background()
+
+	result, err := h.CallTool(ctx, "acknowledge-alert", map[string]interface{}{"alarmName": "cpu-high"})
+	require.NoError(t, err)
+	assert.Equal(t, "reason is required, e.g. known issue tracked in INC-123", decodeToolResult(t, result)["error"])
+
+	result, err = h.CallTool(ctx, "snooze-alert", map[string]interface{}{"alarmName": "cpu-high", "reason": "load test"})
+	require.NoError(t, err)
+	assert.Equal(t, "duration is required, e.g. 4h or 2d", decodeToolResult(t, result)["error"])
+
+	result, err = h.CallTool(ctx, "snooze-alert", map[string]interface{}{"alarmName": "cpu-high", "reason": "load test", "duration": "45d"})
+	require.NoError(t, err)
+	assert.Contains(t, decodeToolResult(t, result)["error"], "at most 30 days")
+
+	result, err = h.CallTool(ctx, "unsuppress-alert", map[string]interface{}{"alarmName": "cpu-high"})
+	require.NoError(t, err)
+	assert.Equal(t, "alert cpu-high is not acknowledged, snoozed or suppressed", decodeToolResult(t, result)["error"])
+}
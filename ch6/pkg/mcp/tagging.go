@@ -0,0 +1,274 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"aws-mcp-server/internal/config"
+	"aws-mcp-server/pkg/aws"
+	"aws-mcp-server/pkg/types"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// unownedGroup is the owner bucket used for resources without any owner tag
+const unownedGroup = "unowned"
+
+// EC2 tag limits
+const (
+	maxTagsPerResource = 50
+	maxTagKeyLength    = 128
+	maxTagValueLength  = 256
+)
+
+// tagViolation describes a resource that is missing one or more required tags
+type tagViolation struct {
+	ID          string   `json:"id"`
+	Name        string   `json:"name,omitempty"`
+	State       string   `json:"state"`
+	MissingTags []string `json:"missing_tags"`
+	owner       string
+}
+
+// auditTags scans the inventory against the required-tag policy and optionally
+// applies the configured default tags to non-compliant resources
+func (h *ToolHandler) auditTags(ctx context.Context, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	autoRemediate, _ := arguments["autoRemediate"].(bool)
+
+	policy := h.config.Tagging
+	if len(policy.RequiredTags) == 0 {
+		return h.createErrorResponse("no required tags are configured in the tagging policy")
+	}
+
+	instances, err := h.awsClient.ListEC2Instances(ctx)
+	if err != nil {
+		return h.createErrorResponse(fmt.Sprintf("failed to list EC2 instances: %v", err))
+	}
+
+	violations := findTagViolations(instances, policy)
+
+	var remediated []map[string]interface{}
+	var remediationErrors []string
+	if autoRemediate {
+		for i := range violations {
+			applied := defaultTagsFor(violations[i].MissingTags, policy.DefaultTags)
+			if len(applied) == 0 {
+				continue
+			}
+
+			if err := h.awsClient.TagEC2Instance(ctx, violations[i].ID, applied); err != nil {
+				remediationErrors = append(remediationErrors, fmt.Sprintf("%s: %v", violations[i].ID, err))
+				continue
+			}
+
+			remediated = append(remediated, map[string]interface{}{
+				"id":           violations[i].ID,
+				"applied_tags": applied,
+			})
+
+			// Only tags without a default remain missing after remediation
+			var stillMissing []string
+			for _, key := range violations[i].MissingTags {
+				if _, ok := applied[key]; !ok {
+					stillMissing = append(stillMissing, key)
+				}
+			}
+			violations[i].MissingTags = stillMissing
+		}
+	}
+
+	byOwner := make(map[string][]tagViolation)
+	nonCompliant := 0
+	for _, violation := range violations {
+		if len(violation.MissingTags) == 0 {
+			continue
+		}
+		byOwner[violation.owner] = append(byOwner[violation.owner], violation)
+		nonCompliant++
+	}
+
+	data := map[string]interface{}{
+		"required_tags":           policy.RequiredTags,
+		"total_resources":         len(instances),
+		"compliant_resources":     len(instances) - nonCompliant,
+		"non_compliant_resources": nonCompliant,
+		"non_compliant_by_owner":  byOwner,
+	}
+
+	if autoRemediate {
+		data["remediated"] = remediated
+		if len(remediationErrors) > 0 {
+			data["remediation_errors"] = remediationErrors
+		}
+	}
+
+	return h.createSuccessResponse("Tag compliance audit completed", data)
+}
+
+// tagResource applies tags to EC2 resources of any type, such as instances,
+// volumes, snapshots, security groups or VPCs, overwriting existing values
+func (h *ToolHandler) tagResource(ctx context.Context, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	resourceIDs, err := taggableResources(arguments)
+	if err != nil {
+		return h.createErrorResponse(err.Error())
+	}
+
+	raw, _ := arguments["tags"].(map[string]interface{})
+	if len(raw) == 0 {
+		return h.createErrorResponse(`tags is required, e.g. {"Owner": "payments", "Environment": "prod"}`)
+	}
+	if len(raw) > maxTagsPerResource {
+		return h.createErrorResponse(fmt.Sprintf("at most %d tags can be applied at once", maxTagsPerResource))
+	}
+	tags := make(map[string]string, len(raw))
+	for key, value := range raw {
+		if err := checkTagKey(key); err != nil {
+			return h.createErrorResponse(err.Error())
+		}
+		text, ok := value.(string)
+		if !ok {
+			text = fmt.Sprint(value)
+		}
+		if len(text) > maxTagValueLength {
+			return h.createErrorResponse(fmt.Sprintf("the value of tag %s is longer than %d characters", key, maxTagValueLength))
+		}
+		tags[key] = text
+	}
+
+	if err := h.awsClient.TagEC2Resources(ctx, resourceIDs, tags); err != nil {
+		return h.createErrorResponse(err.Error())
+	}
+
+	return h.createSuccessResponse(fmt.Sprintf("Tagged %d %s with %d %s", len(resourceIDs), plural(len(resourceIDs), "resource", "resources"), len(tags), plural(len(tags), "tag", "tags")), map[string]interface{}{
+		"resourceIds": resourceIDs,
+		"tags":        tags,
+	})
+}
+
+// untagResource removes tags from EC2 resources of any type. Removing a tag
+// the tagging policy requires is allowed but warned about.
+func (h *ToolHandler) untagResource(ctx context.Context, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	resourceIDs, err := taggableResources(arguments)
+	if err != nil {
+		return h.createErrorResponse(err.Error())
+	}
+
+	keys := stringList(arguments["tagKeys"])
+	if len(keys) == 0 {
+		return h.createErrorResponse(`tagKeys is required, e.g. ["Temporary"]`)
+	}
+	for _, key := range keys {
+		if err := checkTagKey(key); err != nil {
+			return h.createErrorResponse(err.Error())
+		}
+	}
+
+	if err := h.awsClient.UntagEC2Resources(ctx, resourceIDs, keys); err != nil {
+		return h.createErrorResponse(err.Error())
+	}
+
+	var warnings []string
+	for _, key := range keys {
+		for _, required := range h.config.Tagging.RequiredTags {
+			if strings.EqualFold(key, required) {
+				warnings = append(warnings, fmt.Sprintf("%s is a required tag; the resources now fail audit-tags until it is set again", required))
+			}
+		}
+	}
+
+	return h.createSuccessResponse(fmt.Sprintf("Removed %d %s from %d %s", len(keys), plural(len(keys), "tag", "tags"), len(resourceIDs), plural(len(resourceIDs), "resource", "resources")), map[string]interface{}{
+		"resourceIds": resourceIDs,
+		"tagKeys":     keys,
+	}, warnings...)
+}
+
+// taggableResources returns the EC2 resource IDs a tag call applies to
+func taggableResources(arguments map[string]interface{}) ([]string, error) {
+	resourceIDs := stringList(arguments["resourceIds"])
+	if len(resourceIDs) == 0 {
+		return nil, errors.New(`resourceIds is required, e.g. ["i-0abc123", "vol-0abc123"]`)
+	}
+	for _, id := range resourceIDs {
+		if !aws.IsEC2ResourceID(id) {
+			return nil, fmt.Errorf("%s is not an EC2 resource ID; tag-resource and untag-resource take IDs such as i-0abc123, vol-0abc123 or sg-0abc123", id)
+		}
+	}
+	return resourceIDs, nil
+}
+
+// checkTagKey rejects tag keys EC2 does not accept
+func checkTagKey(key string) error {
+	switch {
+	case strings.TrimSpace(key) == "":
+		return errors.New("tag keys must not be empty")
+	case len(key) > maxTagKeyLength:
+		return fmt.Errorf("tag key %s is longer than %d characters", key, maxTagKeyLength)
+	case strings.HasPrefix(strings.ToLower(key), "aws:"):
+		return fmt.Errorf("tag key %s uses the aws: prefix, which is reserved for AWS", key)
+	}
+	return nil
+}
+
+// findTagViolations returns every resource missing at least one required tag,
+// sorted by owner and then by resource ID
+func findTagViolations(resources []types.CloudResource, policy config.TaggingConfig) []tagViolation {
+	var violations []tagViolation
+
+	for _, resource := range resources {
+		var missing []string
+		for _, key := range policy.RequiredTags {
+			if resource.Tags[key] == "" {
+				missing = append(missing, key)
+			}
+		}
+
+		if len(missing) == 0 {
+			continue
+		}
+
+		violations = append(violations, tagViolation{
+			ID:          resource.ID,
+			Name:        resource.Tags["Name"],
+			State:       resource.State,
+			MissingTags: missing,
+			owner:       resourceOwner(resource.Tags, policy.OwnerTags),
+		})
+	}
+
+	sort.Slice(violations, func(i, j int) bool {
+		if violations[i].owner != violations[j].owner {
+			return violations[i].owner < violations[j].owner
+		}
+		return violations[i].ID < violations[j].ID
+	})
+
+	return violations
+}
+
+// resourceOwner returns the value of the first owner tag present on the resource
+func resourceOwner(tags map[string]string, ownerTags []string) string {
+	for _, key := range ownerTags {
+		if owner := tags[key]; owner != "" {
+			return owner
+		}
+	}
+	return unownedGroup
+}
+
+// defaultTagsFor picks the configured default values for the missing tags.
+// Keys are matched case-insensitively because viper lowercases map keys.
+func defaultTagsFor(missing []string, defaults map[string]string) map[string]string {
+	applied := make(map[string]string)
+	for _, key := range missing {
+		for defaultKey, value := range defaults {
+			if strings.EqualFold(defaultKey, key) && value != "" {
+				applied[key] = value
+				break
+			}
+		}
+	}
+	return applied
+}
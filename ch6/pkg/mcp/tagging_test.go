@@ -0,0 +1,86 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"aws-mcp-server/internal/config"
+	"aws-mcp-server/internal/logging"
+	"aws-mcp-server/pkg/render"
+	"aws-mcp-server/pkg/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindTagViolations(t *testing.T) {
+	policy := config.TaggingConfig{
+		RequiredTags: []string{"Name", "Environment", "Owner"},
+		OwnerTags:    []string{"Owner", "Team"},
+	}
+
+	resources := []types.CloudResource{
+		{ID: "i-compliant", State: "running", Tags: map[string]string{"Name": "web-01", "Environment": "prod", "Owner": "payments"}},
+		{ID: "i-team", State: "running", Tags: map[string]string{"Name": "worker-01", "Team": "platform"}},
+		{ID: "i-untagged", State: "stopped"},
+	}
+
+	violations := findTagViolations(resources, policy)
+	require.Len(t, violations, 2)
+
+	assert.Equal(t, "i-team", violations[0].ID)
+	assert.Equal(t, "platform", violations[0].owner)
+	assert.Equal(t, []string{"Environment", "Owner"}, violations[0].MissingTags)
+
+	assert.Equal(t, "i-untagged", violations[1].ID)
+	assert.Equal(t, unownedGroup, violations[1].owner)
+	assert.Equal(t, []string{"Name", "Environment", "Owner"}, violations[1].MissingTags)
+}
+
+func TestDefaultTagsFor(t *testing.T) {
+	// viper lowercases map keys, so defaults arrive in lowercase
+	defaults := map[string]string{"environment": "unknown", "owner": ""}
+
+	applied := defaultTagsFor([]string{"Environment", "Owner", "Name"}, defaults)
+
+	assert.Equal(t, map[string]string{"Environment": "unknown"}, applied)
+}
+
+func TestTagResourceValidation(t *testing.T) {
+	h := NewToolHandler(&config.Config{}, nil, logging.NewLogger("error", "text"))
+	ctx := context.Background()
+
+	for _, tc := range []struct {
+		tool      string
+		arguments map[string]interface{}
+		err       string
+	}{
+		{"tag-resource", map[string]interface{}{"tags": map[string]interface{}{"Owner": "payments"}}, "resourceIds is required"},
+		{"tag-resource", map[string]interface{}{"resourceIds": []interface{}{"my-bucket"}, "tags": map[string]interface{}{"Owner": "payments"}}, "my-bucket is not an EC2 resource ID"},
+		{"tag-resource", map[string]interface{}{"resourceIds": []interface{}{"vol-0abc12345"}}, "tags is required"},
+		{"tag-resource", map[string]interface{}{"resourceIds": []interface{}{"vol-0abc12345"}, "tags": map[string]interface{}{"aws:cloudformation:stack-name": "web"}}, "reserved for AWS"},
+		{"untag-resource", map[string]interface{}{"resourceIds": []interface{}{"sg-0abc12345"}}, "tagKeys is required"},
+	} {
+		result, err := h.CallTool(ctx, tc.tool, tc.arguments)
+		require.NoError(t, err)
+		assert.Contains(t, decodeToolResult(t, result)["error"], tc.err)
+	}
+}
+
+func TestTagResourceSummaries(t *testing.T) {
+	renderer, err := render.New(nil)
+	require.NoError(t, err)
+
+	payload, err := json.Marshal(map[string]interface{}{"resourceIds": []string{"i-0abc12345", "vol-0abc12345"}, "tags": map[string]string{"Owner": "payments", "Environment": "prod"}})
+	require.NoError(t, err)
+	summary, ok := renderer.RenderJSON("tag-resource", payload, nil)
+	require.True(t, ok)
+	assert.Equal(t, "Tagged 2 resources with Environment=prod Owner=payments", summary)
+
+	payload, err = json.Marshal(map[string]interface{}{"resourceIds": []string{"sg-0abc12345"}, "tagKeys": []string{"Temporary", "Expiry"}})
+	require.NoError(t, err)
+	summary, ok = renderer.RenderJSON("untag-resource", payload, nil)
+	require.True(t, ok)
+	assert.Equal(t, "Removed Temporary, Expiry from 1 resource", summary)
+}
@@ -0,0 +1,83 @@
+package mcp
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// parseTimeParam parses a time argument: RFC3339, Unix seconds, "now" or an
+// offset such as "now-1h" or "-1h". An empty value means now.
+func parseTimeParam(value string, now time.Time) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	switch {
+	case value == "" || value == "now":
+		return now, nil
+	case strings.HasPrefix(value, "now-"), strings.HasPrefix(value, "-"):
+		offset, err := parseDurationParam(strings.TrimPrefix(strings.TrimPrefix(value, "now"), "-"))
+		if err != nil {
+			return time.Time{}, err
+		}
+		return now.Add(-offset), nil
+	}
+
+	if seconds, err := strconv.ParseFloat(value, 64); err == nil {
+		return time.UnixMilli(int64(math.Round(seconds * 1000))), nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
+
+// parseDurationParam parses a duration argument: a Go duration, plain seconds,
+// or days and weeks as in Prometheus ("7d", "2w")
+func parseDurationParam(value string) (time.Duration, error) {
+	if seconds, err := strconv.ParseFloat(value, 64); err == nil {
+		return time.Duration(seconds * float64(time.Second)), nil
+	}
+
+	for suffix, unit := range map[string]time.Duration{"d": 24 * time.Hour, "w": 7 * 24 * time.Hour} {
+		if count, found := strings.CutSuffix(value, suffix); found {
+			n, err := strconv.Atoi(count)
+			if err != nil {
+				return 0, fmt.Errorf("invalid duration %q", value)
+			}
+			return time.Duration(n) * unit, nil
+		}
+	}
+
+	return time.ParseDuration(value)
+}
+
+// parseWindowArgs reads the start, end and since tool arguments. end defaults to
+// now; without start the window is the last "since", or defaultWindow.
+func parseWindowArgs(arguments map[string]interface{}, now time.Time, defaultWindow time.Duration) (time.Time, time.Time, error) {
+	var start, end time.Time
+	var err error
+
+	end = now
+	if value, ok := arguments["end"].(string); ok && value != "" {
+		if end, err = parseTimeParam(value, now); err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid end: %w", err)
+		}
+	}
+
+	if value, ok := arguments["start"].(string); ok && value != "" {
+		if start, err = parseTimeParam(value, now); err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid start: %w", err)
+		}
+	} else {
+		window := defaultWindow
+		if since, ok := arguments["since"].(string); ok && since != "" {
+			if window, err = parseDurationParam(since); err != nil || window <= 0 {
+				return time.Time{}, time.Time{}, fmt.Errorf("invalid since %q, use a duration such as 15m, 6h or 2d", since)
+			}
+		}
+		start = end.Add(-window)
+	}
+
+	if !end.After(start) {
+		return time.Time{}, time.Time{}, fmt.Errorf("end must be after start")
+	}
+	return start, end, nil
+}
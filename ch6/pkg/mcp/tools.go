@@ -3,40 +3,341 @@ package mcp
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"time"
+	"math"
+	"os"
+	"strings"
 
+	"aws-mcp-server/internal/config"
 	"aws-mcp-server/internal/logging"
+	"aws-mcp-server/internal/notify"
+	"aws-mcp-server/pkg/accounts"
+	"aws-mcp-server/pkg/approval"
+	"aws-mcp-server/pkg/audit"
 	"aws-mcp-server/pkg/aws"
+	"aws-mcp-server/pkg/azure"
+	"aws-mcp-server/pkg/baseline"
+	"aws-mcp-server/pkg/change"
+	"aws-mcp-server/pkg/cloud"
+	"aws-mcp-server/pkg/cost"
+	"aws-mcp-server/pkg/elevation"
+	"aws-mcp-server/pkg/export"
+	"aws-mcp-server/pkg/gcp"
+	"aws-mcp-server/pkg/ignore"
+	"aws-mcp-server/pkg/knowledge"
+	"aws-mcp-server/pkg/logs"
+	"aws-mcp-server/pkg/notes"
+	"aws-mcp-server/pkg/pseudonym"
+	"aws-mcp-server/pkg/render"
+	"aws-mcp-server/pkg/search"
+	"aws-mcp-server/pkg/slo"
+	"aws-mcp-server/pkg/summarize"
+	"aws-mcp-server/pkg/suppress"
+	"aws-mcp-server/pkg/types"
 
 	"github.com/mark3labs/mcp-go/mcp"
 )
 
 type ToolHandler struct {
+	config    *config.Config
 	awsClient *aws.Client
+	clouds    *cloud.Registry
 	logger    *logging.Logger
+	renderer  *render.Renderer
+	times     *render.TimeFormatter
+	guardrail *cost.Guardrail
+	approvals *approval.Queue
+	notifier  *notify.Notifier
+	logs      logs.Backend
+	audit     *audit.Log
+	slo       *slo.Policy
+	changes   *change.Validator
+
+	suppressions *suppress.List
+	notes        *notes.Store
+	ignored      *ignore.List
+	baselines    *baseline.Store
+	exporter     *export.Exporter
+	outcomes     *knowledge.Store
+	search       *search.Index
+	summaries    *summarize.Summarizer
+	accounts     *accounts.Directory
+	elevations   *elevation.Registry
+	demo         *pseudonym.Pseudonymizer
+
+	freezeWindows []approval.FreezeWindow
+	// regionWarning tells callers of changing AWS tools that the server acts
+	// in another region than the shell's AWS default, empty when they match
+	regionWarning string
 }
 
-func NewToolHandler(awsClient *aws.Client, logger *logging.Logger) *ToolHandler {
+func NewToolHandler(cfg *config.Config, awsClient *aws.Client, logger *logging.Logger) *ToolHandler {
+	// main validates freeze windows at startup, so errors here only affect tests
+	freezeWindows, err := approval.ParseFreezeWindows(cfg.Approvals.FreezeWindows)
+	if err != nil {
+		logger.WithError(err).Error("Invalid freeze windows, change freezes are not enforced")
+	}
+
+	times := render.NewTimeFormatter(cfg.Response.Timezone, cfg.Response.RelativeTimes)
 	return &ToolHandler{
+		config:    cfg,
 		awsClient: awsClient,
+		clouds:    cloud.NewRegistry(cloud.NewAWSProvider(awsClient)),
 		logger:    logger,
+		times:     times,
+		guardrail: cost.NewGuardrail(cost.Limits{
+			MaxHourlyPerAction:  cfg.Cost.MaxHourlyPerAction,
+			MaxMonthlyPerAction: cfg.Cost.MaxMonthlyPerAction,
+			MaxMonthlyPerDay:    cfg.Cost.MaxMonthlyPerDay,
+		}, times.Location()),
+		approvals:     approval.NewQueue(),
+		elevations:    elevation.NewRegistry(),
+		suppressions:  suppress.NewList(),
+		notes:         notes.NewStore(),
+		ignored:       ignore.NewList(),
+		baselines:     baseline.NewStore(),
+		freezeWindows: freezeWindows,
+		regionWarning: regionWarning(cfg.AWS.Region),
+	}
+}
+
+// regionWarning warns when the configured region differs from the default
+// region of the AWS CLI and SDKs in the server's environment, so a caller
+// comparing results with the console or CLI knows where changes were made
+func regionWarning(region string) string {
+	defaultRegion := os.Getenv("AWS_REGION")
+	if defaultRegion == "" {
+		defaultRegion = os.Getenv("AWS_DEFAULT_REGION")
 	}
+	if defaultRegion == "" || region == "" || defaultRegion == region {
+		return ""
+	}
+	return fmt.Sprintf("Changes were made in %s, which differs from the default region %s of the AWS CLI", region, defaultRegion)
 }
 
 // CallTool handles requests for specific tools
 func (h *ToolHandler) CallTool(ctx context.Context, name string, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
-	h.logger.LogMCPCallTool(name, arguments)
+	// In demo mode callers pass back the pseudonyms they were shown
+	arguments = h.demo.RevealArguments(arguments)
+
+	// SecureString values must not end up in logs, the audit log or notifications
+	logged := redactArguments(name, arguments)
+	h.logger.LogMCPCallTool(name, logged)
+
+	result, err := h.runTool(ctx, name, arguments)
+	var fault *toolFault
+	if errors.As(err, &fault) {
+		h.recordFault(ctx, name, logged, fault)
+		result, err = h.createErrorResponse(fault.Error())
+	} else if err == nil {
+		h.recordAudit(ctx, name, logged, result)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if isMutating(name, arguments) && isSuccess(result) {
+		h.notifier.Notify(notify.Event{
+			Type:     notify.EventToolExecuted,
+			Severity: notify.SeverityInfo,
+			Title:    fmt.Sprintf("%s executed", name),
+			Fields: map[string]interface{}{
+				"tool":      name,
+				"arguments": logged,
+				"role":      h.callerRole(ctx),
+			},
+			Resource: resourceFromArguments(arguments),
+		})
+		h.annotateChange(ctx, name, arguments)
+	}
+
+	if grant, ok := h.elevationOf(ctx); ok && isMutating(name, arguments) {
+		result = addWarnings(result, fmt.Sprintf("Run by read-only role %s under elevation %s, which ends %s", grant.Role, grant.ID, h.times.Format(grant.ExpiresAt)))
+	}
+
+	if isMutating(name, arguments) && h.regionWarning != "" && !strings.Contains(name, "-gcp-") && !strings.Contains(name, "-azure-") {
+		result = addWarnings(result, h.regionWarning)
+	}
+
+	return h.pseudonymize(h.addSummary(name, result)), nil
+}
+
+// callTool dispatches a tool call to its handler
+func (h *ToolHandler) callTool(ctx context.Context, name string, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	// A call naming an account runs on a handler acting in that account
+	scoped, err := h.inAccount(ctx, name, arguments)
+	if err != nil {
+		return h.createErrorResponse(err.Error())
+	}
+	h = scoped
+
+	if rejected, ok := h.checkElevation(ctx, name, arguments); ok {
+		return rejected, nil
+	}
+	if rejected, ok := h.checkChangeTicket(ctx, name, arguments); ok {
+		return rejected, nil
+	}
+	if blocked, ok := h.checkFreeze(ctx, name, arguments); ok {
+		return blocked, nil
+	}
+	if blocked, ok := h.checkErrorBudget(ctx, name, arguments); ok {
+		return blocked, nil
+	}
 
 	switch name {
 	case "create-ec2-instance":
 		return h.createEC2Instance(ctx, arguments)
 	case "start-ec2-instance":
-		return h.startEC2Instance(ctx, arguments)
+		return h.startInstance(ctx, h.clouds.Get(aws.ProviderName), arguments)
 	case "stop-ec2-instance":
-		return h.stopEC2Instance(ctx, arguments)
+		return h.stopInstance(ctx, h.clouds.Get(aws.ProviderName), arguments)
 	case "terminate-ec2-instance":
 		return h.terminateEC2Instance(ctx, arguments)
+	case "bulk-stop-ec2-instances":
+		return h.bulkStopEC2Instances(ctx, arguments)
+	case "verify-remediation":
+		return h.verifyRemediation(ctx, arguments)
+	case "start-gcp-instance":
+		return h.startInstance(ctx, h.clouds.Get(gcp.ProviderName), arguments)
+	case "stop-gcp-instance":
+		return h.stopInstance(ctx, h.clouds.Get(gcp.ProviderName), arguments)
+	case "start-azure-vm":
+		return h.startInstance(ctx, h.clouds.Get(azure.ProviderName), arguments)
+	case "stop-azure-vm":
+		return h.stopInstance(ctx, h.clouds.Get(azure.ProviderName), arguments)
+	case "publish-sns-message":
+		return h.publishSNSMessage(ctx, arguments)
+	case "purge-sqs-queue":
+		return h.purgeSQSQueue(ctx, arguments)
+	case "redrive-sqs-dlq":
+		return h.redriveSQSDLQ(ctx, arguments)
+	case "update-ecs-service":
+		return h.updateECSService(ctx, arguments)
+	case "force-ecs-deployment":
+		return h.forceECSDeployment(ctx, arguments)
+	case "start-rds-instance":
+		return h.startRDSInstance(ctx, arguments)
+	case "stop-rds-instance":
+		return h.stopRDSInstance(ctx, arguments)
+	case "reboot-cache-node":
+		return h.rebootCacheNode(ctx, arguments)
+	case "reboot-rds-instance":
+		return h.rebootRDSInstance(ctx, arguments)
+	case "create-rds-snapshot":
+		return h.createRDSSnapshot(ctx, arguments)
+	case "audit-tags":
+		return h.auditTags(ctx, arguments)
+	case "request-elevation":
+		return h.requestElevation(ctx, arguments)
+	case "end-elevation":
+		return h.endElevation(ctx, arguments)
+	case "tag-resource":
+		return h.tagResource(ctx, arguments)
+	case "untag-resource":
+		return h.untagResource(ctx, arguments)
+	case "search":
+		return h.searchResources(ctx, arguments)
+	case "find-public-exposure":
+		return h.findPublicExposure(ctx, arguments)
+	case "rotate-secret":
+		return h.rotateSecret(ctx, arguments)
+	case "deploy-api-stage":
+		return h.deployAPIStage(ctx, arguments)
+	case "update-api-throttling":
+		return h.updateAPIThrottling(ctx, arguments)
+	case "request-quota-increase":
+		return h.requestQuotaIncrease(ctx, arguments)
+	case "update-efs-throughput":
+		return h.updateEFSThroughput(ctx, arguments)
+	case "start-on-demand-backup":
+		return h.startOnDemandBackup(ctx, arguments)
+	case "get-windows-password":
+		return h.getWindowsPassword(ctx, arguments)
+	case "get-instance-console-output":
+		return h.getInstanceConsoleOutput(ctx, arguments)
+	case "get-instance-screenshot":
+		return h.getInstanceScreenshot(ctx, arguments)
+	case "add-note":
+		return h.addNote(ctx, arguments)
+	case "remove-note":
+		return h.removeNote(ctx, arguments)
+	case "ignore-resource":
+		return h.ignoreResource(ctx, arguments)
+	case "unignore-resource":
+		return h.unignoreResource(ctx, arguments)
+	case "update-shard-count":
+		return h.updateShardCount(ctx, arguments)
+	case "start-execution":
+		return h.startExecution(ctx, arguments)
+	case "redrive-execution":
+		return h.redriveExecution(ctx, arguments)
+	case "capture-traffic":
+		return h.captureTraffic(ctx, arguments)
+	case "run-patch-baseline":
+		return h.runPatchBaseline(ctx, arguments)
+	case "put-parameter":
+		return h.putParameter(ctx, arguments)
+	case "find-orphans":
+		return h.findOrphans(ctx, arguments)
+	case "diagnose-connectivity":
+		return h.diagnoseConnectivity(ctx, arguments)
+	case "acknowledge-alert":
+		return h.acknowledgeAlert(ctx, arguments)
+	case "snooze-alert":
+		return h.snoozeAlert(ctx, arguments)
+	case "suppress-alert":
+		return h.suppressAlert(ctx, arguments)
+	case "unsuppress-alert":
+		return h.unsuppressAlert(ctx, arguments)
+	case "get-metric-baseline":
+		return h.getMetricBaseline(ctx, arguments)
+	case "reset-metric-baseline":
+		return h.resetMetricBaseline(ctx, arguments)
+	case "authorize-security-group-ingress":
+		return h.authorizeSecurityGroupIngress(ctx, arguments)
+	case "revoke-security-group-ingress":
+		return h.revokeSecurityGroupIngress(ctx, arguments)
+	case "encrypt-volume":
+		return h.encryptVolume(ctx, arguments)
+	case "create-ebs-snapshot":
+		return h.createEBSSnapshot(ctx, arguments)
+	case "delete-ebs-snapshot":
+		return h.deleteEBSSnapshot(ctx, arguments)
+	case "modify-ebs-volume":
+		return h.modifyEBSVolume(ctx, arguments)
+	case "deactivate-access-key":
+		return h.deactivateAccessKey(ctx, arguments)
+	case "get-cloudwatch-metrics":
+		return h.getCloudWatchMetrics(ctx, arguments)
+	case "query-logs":
+		return h.queryLogs(ctx, arguments)
+	case "summarize-logs":
+		return h.summarizeLogs(ctx, arguments)
+	case "query-cloudwatch-logs":
+		return h.queryCloudWatchLogs(ctx, arguments)
+	case "run-athena-query":
+		return h.runAthenaQuery(ctx, arguments)
+	case "start-athena-query":
+		return h.startAthenaQuery(ctx, arguments)
+	case "get-athena-query":
+		return h.getAthenaQuery(ctx, arguments)
+	case "get-athena-query-results":
+		return h.getAthenaQueryResults(ctx, arguments)
+	case "start-redshift-query":
+		return h.startRedshiftQuery(ctx, arguments)
+	case "get-redshift-query":
+		return h.getRedshiftQuery(ctx, arguments)
+	case "get-redshift-query-results":
+		return h.getRedshiftQueryResults(ctx, arguments)
+	case "draft-postmortem":
+		return h.draftPostmortem(ctx, arguments)
+	case "simulate-action":
+		return h.simulateAction(ctx, arguments)
+	case "approve-action":
+		return h.approveAction(ctx, arguments)
+	case "reject-action":
+		return h.rejectAction(ctx, arguments)
 	default:
 		return nil, fmt.Errorf("unknown tool: %s", name)
 	}
@@ -72,6 +373,16 @@ func (h *ToolHandler) createEC2Instance(ctx context.Context, arguments map[strin
 	if val, exists := arguments["name"]; exists {
 		name, _ = val.(string)
 	}
+	spot, err := spotOptions(arguments)
+	if err != nil {
+		return h.createErrorResponse(err.Error())
+	}
+
+	// Check the estimated cost before creating anything
+	estimate := cost.EstimateEC2(instanceType, h.config.Cost.InstancePrices)
+	if violation := h.guardrail.Check(estimate); violation != nil && !h.costOverridden(arguments) && !isApproved(ctx) {
+		return h.blockOnGuardrail(ctx, "create-ec2-instance", arguments, violation)
+	}
 
 	params := aws.CreateInstanceParams{
 		ImageID:         imageID,
@@ -80,6 +391,7 @@ func (h *ToolHandler) createEC2Instance(ctx context.Context, arguments map[strin
 		SecurityGroupID: securityGroupID,
 		SubnetID:        subnetID,
 		Name:            name,
+		Spot:            spot,
 	}
 
 	resource, err := h.awsClient.CreateEC2Instance(ctx, params)
@@ -87,53 +399,82 @@ func (h *ToolHandler) createEC2Instance(ctx context.Context, arguments map[strin
 		return h.createErrorResponse(fmt.Sprintf("failed to create EC2 instance: %v", err))
 	}
 
+	h.guardrail.Record(estimate)
+
 	data := map[string]interface{}{
 		"instanceId":   resource.ID,
 		"state":        resource.State,
-		"instanceType": resource.Details["instanceType"],
+		"instanceType": resource.InstanceType(),
+	}
+	if estimate.Known {
+		data["estimatedHourlyCost"] = estimate.HourlyUSD
+		data["estimatedMonthlyCost"] = math.Round(estimate.MonthlyUSD()*100) / 100
+	}
+	if name != "" {
+		data["name"] = name
+	}
+	if spot != nil {
+		// The estimate is the On-Demand price, which caps the Spot price
+		// unless a higher maximum was set
+		data["market"] = "spot"
+		data["spotInstanceRequestId"] = resource.EC2Instance().SpotRequestID
 	}
 
 	return h.createSuccessResponse("EC2 instance created successfully", data)
 }
 
-// startEC2Instance starts a stopped EC2 instance
-func (h *ToolHandler) startEC2Instance(ctx context.Context, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+// startInstance starts a stopped instance of any provider
+func (h *ToolHandler) startInstance(ctx context.Context, provider cloud.Provider, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	if provider == nil {
+		return h.createErrorResponse("this cloud provider is not configured")
+	}
+
 	instanceID, ok := arguments["instanceId"].(string)
 	if !ok || instanceID == "" {
 		return h.createErrorResponse("instanceId is required")
 	}
 
-	err := h.awsClient.StartEC2Instance(ctx, instanceID)
+	err := provider.StartInstance(ctx, instanceID)
 	if err != nil {
-		return h.createErrorResponse(fmt.Sprintf("failed to start EC2 instance: %v", err))
+		return h.createErrorResponse(fmt.Sprintf("failed to start %s instance: %v", provider.Label(), err))
 	}
 
 	data := map[string]interface{}{
 		"instanceId": instanceID,
 		"action":     "start",
 	}
+	h.addInstanceLabels(ctx, provider, instanceID, data)
 
-	return h.createSuccessResponse("EC2 instance start initiated successfully", data)
+	return h.createSuccessResponse(fmt.Sprintf("%s instance start initiated successfully", provider.Label()), data)
 }
 
-// stopEC2Instance stops a running EC2 instance
-func (h *ToolHandler) stopEC2Instance(ctx context.Context, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+// stopInstance stops a running instance of any provider
+func (h *ToolHandler) stopInstance(ctx context.Context, provider cloud.Provider, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	if provider == nil {
+		return h.createErrorResponse("this cloud provider is not configured")
+	}
+
 	instanceID, ok := arguments["instanceId"].(string)
 	if !ok || instanceID == "" {
 		return h.createErrorResponse("instanceId is required")
 	}
 
-	err := h.awsClient.StopEC2Instance(ctx, instanceID)
+	err := provider.StopInstance(ctx, instanceID)
 	if err != nil {
-		return h.createErrorResponse(fmt.Sprintf("failed to stop EC2 instance: %v", err))
+		return h.createErrorResponse(fmt.Sprintf("failed to stop %s instance: %v", provider.Label(), err))
 	}
 
 	data := map[string]interface{}{
 		"instanceId": instanceID,
 		"action":     "stop",
 	}
+	instance := h.addInstanceLabels(ctx, provider, instanceID, data)
 
-	return h.createSuccessResponse("EC2 instance stop initiated successfully", data)
+	var warnings []string
+	if warning := autoScalingWarning(instance); warning != "" {
+		warnings = append(warnings, warning)
+	}
+	return h.createSuccessResponse(fmt.Sprintf("%s instance stop initiated successfully", provider.Label()), data, warnings...)
 }
 
 // terminateEC2Instance terminates an EC2 instance
@@ -152,44 +493,179 @@ func (h *ToolHandler) terminateEC2Instance(ctx context.Context, arguments map[st
 		"instanceId": instanceID,
 		"action":     "terminate",
 	}
+	instance := h.addInstanceLabels(ctx, h.clouds.Get(aws.ProviderName), instanceID, data)
 
-	return h.createSuccessResponse("EC2 instance termination initiated successfully", data)
+	var warnings []string
+	if warning := autoScalingWarning(instance); warning != "" {
+		warnings = append(warnings, warning)
+	}
+	return h.createSuccessResponse("EC2 instance termination initiated successfully", data, warnings...)
 }
 
-// createErrorResponse creates a standardized error response for tool actions
-func (h *ToolHandler) createErrorResponse(message string) (*mcp.CallToolResult, error) {
-	errorData := map[string]interface{}{
-		"success":   false,
-		"error":     message,
-		"timestamp": time.Now().UTC().Format("2006-01-02T15:04:05Z"),
+// addSummary appends a one-line human-readable summary to successful tool results.
+// Error and confirmation responses are returned unchanged.
+func (h *ToolHandler) addSummary(name string, result *mcp.CallToolResult) *mcp.CallToolResult {
+	if h.renderer == nil || len(result.Content) != 1 {
+		return result
 	}
 
-	jsonData, _ := json.MarshalIndent(errorData, "", "  ")
+	text, ok := textOf(result.Content[0])
+	if !ok || !isSuccess(result) {
+		return result
+	}
 
-	return &mcp.CallToolResult{
-		Content: []mcp.Content{
-			&mcp.TextContent{
-				Type: "text",
-				Text: string(jsonData),
-			},
-		},
-	}, nil
+	summary, ok := h.renderer.RenderJSON(name, []byte(text), map[string]interface{}{
+		"region": h.config.AWS.Region,
+	})
+	if !ok {
+		return result
+	}
+
+	for _, warning := range resultWarnings(result) {
+		summary += "\nWarning: " + warning
+	}
+
+	result.Content = append(result.Content, &mcp.TextContent{
+		Type: "text",
+		Text: summary,
+	})
+	return result
+}
+
+// addWarnings appends warnings to a successful standard response. Other
+// results are returned unchanged.
+func addWarnings(result *mcp.CallToolResult, warnings ...string) *mcp.CallToolResult {
+	if !isSuccess(result) {
+		return result
+	}
+	text, _ := textOf(result.Content[0])
+
+	var response map[string]interface{}
+	if err := json.Unmarshal([]byte(text), &response); err != nil {
+		return result
+	}
+	existing, _ := response["warnings"].([]interface{})
+	for _, warning := range warnings {
+		existing = append(existing, warning)
+	}
+	response["warnings"] = existing
+
+	jsonData, _ := json.MarshalIndent(response, "", "  ")
+	content := append([]mcp.Content{&mcp.TextContent{Type: "text", Text: string(jsonData)}}, result.Content[1:]...)
+	return &mcp.CallToolResult{Content: content, IsError: result.IsError}
+}
+
+// resultWarnings reads the warnings of a standard response
+func resultWarnings(result *mcp.CallToolResult) []string {
+	if len(result.Content) == 0 {
+		return nil
+	}
+	text, ok := textOf(result.Content[0])
+	if !ok {
+		return nil
+	}
+
+	var response struct {
+		Warnings []string `json:"warnings"`
+	}
+	if err := json.Unmarshal([]byte(text), &response); err != nil {
+		return nil
+	}
+	return response.Warnings
+}
+
+// isSuccess reports whether a tool result is a successful standard response
+func isSuccess(result *mcp.CallToolResult) bool {
+	success, _ := resultStatus(result)
+	return success
 }
 
-// createSuccessResponse creates a standardized success response for tool actions
-func (h *ToolHandler) createSuccessResponse(message string, data map[string]interface{}) (*mcp.CallToolResult, error) {
-	responseData := map[string]interface{}{
-		"success":   true,
-		"message":   message,
-		"timestamp": time.Now().UTC().Format("2006-01-02T15:04:05Z"),
+// resultStatus reads the success flag and the message or error of a tool response
+func resultStatus(result *mcp.CallToolResult) (bool, string) {
+	if len(result.Content) == 0 {
+		return false, ""
 	}
+	text, ok := textOf(result.Content[0])
+	if !ok {
+		return false, ""
+	}
+
+	var status struct {
+		Success bool   `json:"success"`
+		Message string `json:"message"`
+		Error   string `json:"error"`
+	}
+	if err := json.Unmarshal([]byte(text), &status); err != nil {
+		return false, ""
+	}
+	if status.Error != "" {
+		return status.Success, status.Error
+	}
+	return status.Success, status.Message
+}
+
+// textOf returns the text of a text content item. Handlers build *mcp.TextContent,
+// which mcp.AsTextContent does not match.
+func textOf(content mcp.Content) (string, bool) {
+	switch c := content.(type) {
+	case *mcp.TextContent:
+		return c.Text, true
+	case mcp.TextContent:
+		return c.Text, true
+	default:
+		return "", false
+	}
+}
+
+// addInstanceLabels adds the Name and Environment tags of an instance to a response
+// so summaries can refer to it by name, and returns the instance. Lookup failures
+// are ignored and return nil.
+func (h *ToolHandler) addInstanceLabels(ctx context.Context, provider cloud.Provider, instanceID string, data map[string]interface{}) *types.CloudResource {
+	instance, err := provider.GetInstance(ctx, instanceID)
+	if err != nil {
+		h.logger.WithError(err).WithField("instanceId", instanceID).Debug("Failed to look up instance labels")
+		return nil
+	}
+
+	if name := instance.Tags["Name"]; name != "" {
+		data["name"] = name
+	}
+	if env := instance.Tags["Environment"]; env != "" {
+		data["environment"] = env
+	}
+	return instance
+}
 
-	// Add any additional data
-	for key, value := range data {
-		responseData[key] = value
+// autoScalingWarning warns that an instance of an Auto Scaling group may be
+// replaced after it is stopped or terminated, empty for other instances
+func autoScalingWarning(instance *types.CloudResource) string {
+	if instance == nil || instance.Tags[asgTag] == "" {
+		return ""
 	}
+	return fmt.Sprintf("%s is in Auto Scaling group %s, which may replace it with a new instance; "+
+		"lower the group's desired capacity or suspend its processes to keep it down", instance.ID, instance.Tags[asgTag])
+}
+
+// createErrorResponse creates a standardized error response for tool actions
+func (h *ToolHandler) createErrorResponse(message string) (*mcp.CallToolResult, error) {
+	response := types.NewToolResponse(false, h.times.Now(), nil)
+	response.Error = message
+	return toolResult(response)
+}
+
+// createSuccessResponse creates a standardized success response for tool actions.
+// Warnings are non-fatal caveats of the result, e.g. that the instance may be
+// replaced; the envelope always has the array, empty when there are none.
+func (h *ToolHandler) createSuccessResponse(message string, data map[string]interface{}, warnings ...string) (*mcp.CallToolResult, error) {
+	response := types.NewToolResponse(true, h.times.Now(), data)
+	response.Message = message
+	response.Warnings = warnings
+	return toolResult(response)
+}
 
-	jsonData, _ := json.MarshalIndent(responseData, "", "  ")
+// toolResult returns a response envelope as the text of a tool result
+func toolResult(response types.ToolResponse) (*mcp.CallToolResult, error) {
+	jsonData, _ := json.MarshalIndent(response, "", "  ")
 
 	return &mcp.CallToolResult{
 		Content: []mcp.Content{
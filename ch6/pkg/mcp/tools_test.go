@@ -5,9 +5,14 @@ import (
 	"encoding/json"
 	"fmt"
 	"testing"
+	"time"
 
+	"aws-mcp-server/internal/config"
 	"aws-mcp-server/internal/logging"
 	"aws-mcp-server/pkg/aws"
+	"aws-mcp-server/pkg/fake"
+	"aws-mcp-server/pkg/render"
+	"aws-mcp-server/pkg/types"
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/stretchr/testify/assert"
@@ -30,7 +35,7 @@ func TestToolHandler_CallTool(t *testing.T) {
 	}
 
 	// Create tool handler
-	toolHandler := NewToolHandler(awsClient, logger)
+	toolHandler := NewToolHandler(&config.Config{}, awsClient, logger)
 
 	ctx := context.Background()
 
@@ -189,9 +194,140 @@ func TestNewToolHandler(t *testing.T) {
 		t.Skip("Skipping test due to AWS configuration requirement")
 	}
 
-	toolHandler := NewToolHandler(awsClient, logger)
+	toolHandler := NewToolHandler(&config.Config{}, awsClient, logger)
 
 	require.NotNil(t, toolHandler)
 	assert.NotNil(t, toolHandler.awsClient)
 	assert.NotNil(t, toolHandler.logger)
 }
+
+func TestSuccessResponseWarnings(t *testing.T) {
+	handler := NewToolHandler(&config.Config{}, nil, logging.NewLogger("error", "text"))
+
+	result, err := handler.createSuccessResponse("done", map[string]interface{}{"instanceId": "i-1"})
+	require.NoError(t, err)
+	assert.Equal(t, []interface{}{}, decodeToolResult(t, result)["warnings"], "the envelope always has the array")
+
+	result, err = handler.createSuccessResponse("done", nil, "instance is in an Auto Scaling group")
+	require.NoError(t, err)
+	result = addWarnings(result, "region differs")
+	assert.Equal(t, []string{"instance is in an Auto Scaling group", "region differs"}, resultWarnings(result))
+
+	handler.renderer, err = render.New(map[string]string{"test-tool": "Did it"})
+	require.NoError(t, err)
+	summarized := handler.addSummary("test-tool", result)
+	require.Len(t, summarized.Content, 2)
+	summary, _ := textOf(summarized.Content[1])
+	assert.Equal(t, "Did it\nWarning: instance is in an Auto Scaling group\nWarning: region differs", summary)
+
+	failed, err := handler.createErrorResponse("failed")
+	require.NoError(t, err)
+	assert.Same(t, failed, addWarnings(failed, "region differs"), "error responses are left alone")
+}
+
+func TestRegionWarning(t *testing.T) {
+	t.Setenv("AWS_REGION", "")
+	t.Setenv("AWS_DEFAULT_REGION", "us-east-1")
+	assert.Contains(t, regionWarning("eu-west-1"), "in eu-west-1, which differs from the default region us-east-1")
+	assert.Empty(t, regionWarning("us-east-1"))
+
+	t.Setenv("AWS_REGION", "eu-west-1")
+	assert.Empty(t, regionWarning("eu-west-1"), "AWS_REGION takes precedence")
+
+	t.Setenv("AWS_REGION", "")
+	t.Setenv("AWS_DEFAULT_REGION", "")
+	assert.Empty(t, regionWarning("eu-west-1"))
+}
+
+func TestAutoScalingWarning(t *testing.T) {
+	assert.Empty(t, autoScalingWarning(nil))
+	assert.Empty(t, autoScalingWarning(&types.CloudResource{ID: "i-1"}))
+	assert.Contains(t, autoScalingWarning(&types.CloudResource{ID: "i-1", Tags: map[string]string{asgTag: "web"}}),
+		"i-1 is in Auto Scaling group web")
+}
+
+func TestResponseEnvelopeIsVersioned(t *testing.T) {
+	handler := NewToolHandler(&config.Config{}, nil, logging.NewLogger("error", "text"))
+
+	success, err := handler.createSuccessResponse("done", map[string]interface{}{"success": false, "state": "running"})
+	require.NoError(t, err)
+	data := decodeToolResult(t, success)
+	assert.Equal(t, float64(types.ResponseSchemaVersion), data["schemaVersion"])
+	assert.Equal(t, true, data["success"], "envelope fields win over the tool's data")
+	assert.Equal(t, "running", data["state"])
+	assert.NotContains(t, data, "error")
+
+	failed, err := handler.createErrorResponse("failed")
+	require.NoError(t, err)
+	data = decodeToolResult(t, failed)
+	assert.Equal(t, float64(types.ResponseSchemaVersion), data["schemaVersion"])
+	assert.Equal(t, "failed", data["error"])
+	assert.Equal(t, []interface{}{}, data["warnings"])
+	assert.NotContains(t, data, "message")
+
+	confirmation, err := handler.createConfirmationResponse("purge-sqs-queue", map[string]interface{}{"queue": "orders"}, []string{"Messages are lost"})
+	require.NoError(t, err)
+	data = decodeToolResult(t, confirmation)
+	assert.Equal(t, float64(types.ResponseSchemaVersion), data["schemaVersion"])
+	assert.Equal(t, true, data["confirmation_required"])
+	assert.Equal(t, []interface{}{"Messages are lost"}, data["warnings"])
+}
+
+func TestReplayKeyOnlyCoversWriteToolCalls(t *testing.T) {
+	call := func(id, name, arguments string) []byte {
+		return []byte(fmt.Sprintf(`{"jsonrpc":"2.0","id":%s,"method":"tools/call","params":{"name":%q,"arguments":%s}}`, id, name, arguments))
+	}
+
+	key, ok := replayKey("stdio-1", call("1", "stop-ec2-instance", `{"instanceId":"i-1"}`))
+	require.True(t, ok)
+	retried, ok := replayKey("stdio-1", call("1", "stop-ec2-instance", `{ "instanceId": "i-1" }`))
+	require.True(t, ok)
+	assert.Equal(t, key, retried)
+	other, ok := replayKey("websocket-2", call("1", "stop-ec2-instance", `{"instanceId":"i-1"}`))
+	require.True(t, ok)
+	assert.NotEqual(t, key, other, "sessions number their requests separately")
+
+	_, ok = replayKey("stdio-1", call("2", "run-athena-query", `{"query":"SELECT 1"}`))
+	assert.False(t, ok, "read tools run every time")
+	_, ok = replayKey("stdio-1", call("3", "delete-ebs-snapshot", `{"snapshotId":"snap-1"}`))
+	assert.False(t, ok, "calls that only return a plan run every time")
+	_, ok = replayKey("stdio-1", call("null", "stop-ec2-instance", `{"instanceId":"i-1"}`))
+	assert.False(t, ok, "requests without an ID cannot be told apart")
+	_, ok = replayKey("stdio-1", []byte(`{"jsonrpc":"2.0","id":4,"method":"resources/read","params":{"uri":"aws://ec2/instances"}}`))
+	assert.False(t, ok)
+}
+
+func TestReplaysAreScopedToTheSession(t *testing.T) {
+	logger := logging.NewLogger("error", "text")
+	fleet := fake.New(config.FakeConfig{Seed: 3}, "us-east-1")
+	cfg := &config.Config{
+		MCP:         config.MCPConfig{ServerName: "aws-mcp-server", Version: "test"},
+		Idempotency: config.IdempotencyConfig{Window: time.Minute},
+	}
+	s := NewServer(cfg, aws.NewClientFromConfig(fleet.Config(), logger), logger)
+	instanceID := fleet.Anomalies()[0].InstanceID
+
+	call := func(ctx context.Context, id int, name string) map[string]interface{} {
+		message := fmt.Sprintf(`{"jsonrpc":"2.0","id":%d,"method":"tools/call","params":{"name":%q,"arguments":{"instanceId":%q}}}`, id, name, instanceID)
+		response, err := s.HandleMessage(ctx, []byte(message))
+		require.NoError(t, err)
+		var decoded struct {
+			Result *mcp.CallToolResult `json:"result"`
+		}
+		require.NoError(t, json.Unmarshal(response, &decoded))
+		return decodeToolResult(t, decoded.Result)
+	}
+
+	first := withConnection(context.Background(), connection{id: "websocket-1", transport: transportWebSocket})
+	second := withConnection(context.Background(), connection{id: "websocket-2", transport: transportWebSocket})
+
+	assert.Equal(t, true, call(first, 3, "stop-ec2-instance")["success"])
+	assert.Equal(t, true, call(first, 4, "terminate-ec2-instance")["success"])
+	assert.Equal(t, true, call(first, 3, "stop-ec2-instance")["success"], "a retry in the session gets the first response")
+
+	// The other client's request 3 is a new call, which fails on the
+	// terminated instance
+	result := call(second, 3, "stop-ec2-instance")
+	assert.Equal(t, false, result["success"])
+	assert.Contains(t, result["error"], "not in a state")
+}
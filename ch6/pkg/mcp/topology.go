@@ -0,0 +1,368 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"aws-mcp-server/pkg/types"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const (
+	// vpcTopologyURI aggregates VPCs, subnets, route tables and gateways
+	vpcTopologyURI = "aws://vpc/topology"
+	// lowSubnetIPs is the number of free addresses below which a subnet is reported
+	lowSubnetIPs = 16
+)
+
+// Subnet tiers, derived from where the default route of a subnet leads
+const (
+	tierPublic   = "public"
+	tierPrivate  = "private"
+	tierRouted   = "routed"
+	tierIsolated = "isolated"
+)
+
+// tierOrder lists subnets from the most to the least exposed
+var tierOrder = map[string]int{tierPublic: 0, tierPrivate: 1, tierRouted: 2, tierIsolated: 3}
+
+// readVPCTopology returns the network layout of every VPC as one document
+func (h *ResourceHandler) readVPCTopology(ctx context.Context) (*mcp.ReadResourceResult, error) {
+	topology, err := h.awsClient.GetVPCTopology(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get VPC topology: %w", err)
+	}
+
+	jsonData, err := json.MarshalIndent(formatVPCTopology(topology), "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal VPC topology: %w", err)
+	}
+
+	return &mcp.ReadResourceResult{
+		Contents: []mcp.ResourceContents{
+			&mcp.TextResourceContents{
+				URI:      vpcTopologyURI,
+				MIMEType: "application/json",
+				Text:     string(jsonData),
+			},
+		},
+	}, nil
+}
+
+// topologyIndex looks up the parts of a VPC topology by ID
+type topologyIndex struct {
+	subnets          map[string]types.Subnet
+	natGateways      map[string]types.NATGateway
+	internetGateways map[string]string
+	// routeTables maps each subnet to the route table that applies to it:
+	// its explicit association or else the main table of its VPC
+	routeTables map[string]types.RouteTable
+	// implicit lists the subnets that use the main table of their VPC
+	implicit map[string][]string
+}
+
+// newTopologyIndex indexes a topology for path lookups
+func newTopologyIndex(topology *types.VPCTopology) topologyIndex {
+	index := topologyIndex{
+		subnets:          make(map[string]types.Subnet),
+		natGateways:      make(map[string]types.NATGateway),
+		internetGateways: make(map[string]string),
+		routeTables:      make(map[string]types.RouteTable),
+		implicit:         make(map[string][]string),
+	}
+
+	for _, subnet := range topology.Subnets {
+		index.subnets[subnet.ID] = subnet
+	}
+	for _, gateway := range topology.NATGateways {
+		index.natGateways[gateway.ID] = gateway
+	}
+	for _, gateway := range topology.InternetGateways {
+		for _, vpcID := range gateway.VpcIDs {
+			index.internetGateways[vpcID] = gateway.ID
+		}
+	}
+
+	mainTables := make(map[string]types.RouteTable)
+	for _, table := range topology.RouteTables {
+		if table.Main {
+			mainTables[table.VpcID] = table
+		}
+		for _, subnetID := range table.SubnetIDs {
+			index.routeTables[subnetID] = table
+		}
+	}
+	for _, subnet := range topology.Subnets {
+		if _, explicit := index.routeTables[subnet.ID]; explicit {
+			continue
+		}
+		if table, ok := mainTables[subnet.VpcID]; ok {
+			index.routeTables[subnet.ID] = table
+			index.implicit[table.ID] = append(index.implicit[table.ID], subnet.ID)
+		}
+	}
+
+	return index
+}
+
+// defaultRoute returns the IPv4 default route of a subnet, if it has one
+func (index topologyIndex) defaultRoute(subnetID string) (types.Route, bool) {
+	for _, route := range index.routeTables[subnetID].Routes {
+		if route.Destination == "0.0.0.0/0" {
+			return route, true
+		}
+	}
+	return types.Route{}, false
+}
+
+// tier classifies a subnet by the target of its default route
+func (index topologyIndex) tier(subnetID string) string {
+	route, ok := index.defaultRoute(subnetID)
+	switch {
+	case !ok || route.Target == "":
+		return tierIsolated
+	case strings.HasPrefix(route.Target, "igw-"):
+		return tierPublic
+	case strings.HasPrefix(route.Target, "nat-"):
+		return tierPrivate
+	default:
+		return tierRouted
+	}
+}
+
+// internetPath follows default routes from a subnet to an internet gateway,
+// through a NAT gateway and its subnet for private subnets. It returns the hops
+// and whether they reach the internet.
+func (index topologyIndex) internetPath(subnetID string) ([]string, bool) {
+	path := []string{subnetID}
+	for range 2 {
+		route, ok := index.defaultRoute(subnetID)
+		if !ok {
+			return path, false
+		}
+		path = append(path, index.routeTables[subnetID].ID, route.Target)
+		if route.State == "blackhole" {
+			return path, false
+		}
+
+		switch {
+		case strings.HasPrefix(route.Target, "igw-"):
+			return path, true
+		case strings.HasPrefix(route.Target, "nat-"):
+			gateway, ok := index.natGateways[route.Target]
+			if !ok || gateway.State != "available" || gateway.ConnectivityType == "private" {
+				return path, false
+			}
+			subnetID = gateway.SubnetID
+			path = append(path, subnetID)
+		default:
+			// Transit gateways, peering and appliances lead outside this view
+			return path, false
+		}
+	}
+	return path, false
+}
+
+// formatVPCTopology shapes the topology as one nested document per VPC, with
+// each subnet's tier and path to the internet resolved and likely causes of
+// connectivity problems listed as issues
+func formatVPCTopology(topology *types.VPCTopology) map[string]interface{} {
+	index := newTopologyIndex(topology)
+	tierCount := map[string]int{tierPublic: 0, tierPrivate: 0, tierRouted: 0, tierIsolated: 0}
+	var issues []string
+
+	subnetsByVPC := make(map[string][]types.Subnet)
+	for _, subnet := range topology.Subnets {
+		subnetsByVPC[subnet.VpcID] = append(subnetsByVPC[subnet.VpcID], subnet)
+	}
+	tablesByVPC := make(map[string][]types.RouteTable)
+	for _, table := range topology.RouteTables {
+		tablesByVPC[table.VpcID] = append(tablesByVPC[table.VpcID], table)
+	}
+	natsByVPC := make(map[string][]types.NATGateway)
+	for _, gateway := range topology.NATGateways {
+		natsByVPC[gateway.VpcID] = append(natsByVPC[gateway.VpcID], gateway)
+	}
+
+	vpcs := append([]types.VPC(nil), topology.VPCs...)
+	sort.Slice(vpcs, func(i, j int) bool {
+		return vpcs[i].ID < vpcs[j].ID
+	})
+
+	items := make([]map[string]interface{}, 0, len(vpcs))
+	for _, vpc := range vpcs {
+		subnets := subnetsByVPC[vpc.ID]
+		sort.Slice(subnets, func(i, j int) bool {
+			ti, tj := tierOrder[index.tier(subnets[i].ID)], tierOrder[index.tier(subnets[j].ID)]
+			if ti != tj {
+				return ti < tj
+			}
+			if subnets[i].AvailabilityZone != subnets[j].AvailabilityZone {
+				return subnets[i].AvailabilityZone < subnets[j].AvailabilityZone
+			}
+			return subnets[i].ID < subnets[j].ID
+		})
+
+		subnetItems := make([]map[string]interface{}, 0, len(subnets))
+		for _, subnet := range subnets {
+			tier := index.tier(subnet.ID)
+			tierCount[tier]++
+			subnetItems = append(subnetItems, formatTopologySubnet(index, subnet, tier))
+			issues = append(issues, subnetIssues(index, subnet, tier)...)
+		}
+
+		tables := tablesByVPC[vpc.ID]
+		sort.Slice(tables, func(i, j int) bool {
+			if tables[i].Main != tables[j].Main {
+				return tables[i].Main
+			}
+			return tables[i].ID < tables[j].ID
+		})
+		tableItems := make([]map[string]interface{}, 0, len(tables))
+		for _, table := range tables {
+			subnetIDs := append(append([]string(nil), table.SubnetIDs...), index.implicit[table.ID]...)
+			sort.Strings(subnetIDs)
+			routes := make([]map[string]interface{}, 0, len(table.Routes))
+			for _, route := range table.Routes {
+				formatted := map[string]interface{}{
+					"destination": route.Destination,
+					"target":      route.Target,
+				}
+				if route.State == "blackhole" {
+					formatted["state"] = route.State
+					issue := fmt.Sprintf("Route %s in %s points to %s, which no longer exists (blackhole)", route.Destination, table.ID, route.Target)
+					if len(subnetIDs) > 0 {
+						issue += "; affects " + strings.Join(subnetIDs, ", ")
+					}
+					issues = append(issues, issue)
+				}
+				routes = append(routes, formatted)
+			}
+			tableItems = append(tableItems, map[string]interface{}{
+				"id":      table.ID,
+				"main":    table.Main,
+				"subnets": subnetIDs,
+				"routes":  routes,
+			})
+		}
+
+		natItems := make([]map[string]interface{}, 0, len(natsByVPC[vpc.ID]))
+		for _, gateway := range natsByVPC[vpc.ID] {
+			formatted := map[string]interface{}{
+				"id":           gateway.ID,
+				"subnet":       gateway.SubnetID,
+				"state":        gateway.State,
+				"connectivity": gateway.ConnectivityType,
+			}
+			if subnet, ok := index.subnets[gateway.SubnetID]; ok {
+				formatted["availability_zone"] = subnet.AvailabilityZone
+			}
+			if gateway.PublicIP != "" {
+				formatted["public_ip"] = gateway.PublicIP
+			}
+			natItems = append(natItems, formatted)
+
+			if gateway.ConnectivityType != "private" && index.tier(gateway.SubnetID) != tierPublic {
+				issues = append(issues, fmt.Sprintf("NAT gateway %s is in subnet %s, which has no route to an internet gateway, so subnets behind it cannot reach the internet",
+					gateway.ID, gateway.SubnetID))
+			}
+		}
+
+		item := map[string]interface{}{
+			"id":           vpc.ID,
+			"cidrs":        vpc.CIDRs,
+			"default":      vpc.Default,
+			"subnets":      subnetItems,
+			"route_tables": tableItems,
+			"nat_gateways": natItems,
+		}
+		if vpc.Name != "" {
+			item["name"] = vpc.Name
+		}
+		if gateway, ok := index.internetGateways[vpc.ID]; ok {
+			item["internet_gateway"] = gateway
+		}
+		items = append(items, item)
+	}
+
+	formatted := map[string]interface{}{
+		"vpc_count":        len(items),
+		"subnet_count":     len(topology.Subnets),
+		"subnets_by_tier":  tierCount,
+		"nat_gateways":     len(topology.NATGateways),
+		"vpcs":             items,
+		"issues":           issues,
+		"issue_count":      len(issues),
+		"tier_explanation": "Tiers follow each subnet's 0.0.0.0/0 route: public via an internet gateway, private via a NAT gateway, routed via a transit gateway, peering connection or appliance, isolated without one",
+		"note":             "Security groups and network ACLs also decide connectivity; use diagnose-connectivity for a specific instance port",
+	}
+	if issues == nil {
+		formatted["issues"] = []string{}
+	}
+	return formatted
+}
+
+// formatTopologySubnet describes a subnet with its route table and the path
+// its traffic takes to the internet
+func formatTopologySubnet(index topologyIndex, subnet types.Subnet, tier string) map[string]interface{} {
+	formatted := map[string]interface{}{
+		"id":                subnet.ID,
+		"availability_zone": subnet.AvailabilityZone,
+		"cidr":              subnet.CIDR,
+		"tier":              tier,
+		"available_ips":     subnet.AvailableIPs,
+		"map_public_ip":     subnet.MapPublicIP,
+	}
+	if subnet.Name != "" {
+		formatted["name"] = subnet.Name
+	}
+	if table, ok := index.routeTables[subnet.ID]; ok {
+		formatted["route_table"] = table.ID
+	}
+	if route, ok := index.defaultRoute(subnet.ID); ok {
+		formatted["default_route"] = route.Target
+	}
+
+	path, reachable := index.internetPath(subnet.ID)
+	formatted["internet_access"] = reachable
+	if len(path) > 1 {
+		formatted["path_to_internet"] = path
+	}
+
+	return formatted
+}
+
+// subnetIssues reports problems of a subnet that commonly explain failed
+// connections or launches
+func subnetIssues(index topologyIndex, subnet types.Subnet, tier string) []string {
+	var issues []string
+
+	if subnet.AvailableIPs == 0 {
+		issues = append(issues, fmt.Sprintf("Subnet %s has no free IP addresses; new instances, interfaces and Lambda functions in it fail to launch", subnet.ID))
+	} else if subnet.AvailableIPs < lowSubnetIPs {
+		issues = append(issues, fmt.Sprintf("Subnet %s has only %d free IP addresses", subnet.ID, subnet.AvailableIPs))
+	}
+
+	if tier != tierPrivate {
+		return issues
+	}
+	route, _ := index.defaultRoute(subnet.ID)
+	gateway, ok := index.natGateways[route.Target]
+	switch {
+	case !ok:
+		// Reported as a blackhole route of the route table
+	case gateway.State != "available":
+		issues = append(issues, fmt.Sprintf("Subnet %s routes to the internet through NAT gateway %s, which is %s", subnet.ID, gateway.ID, gateway.State))
+	default:
+		if natSubnet, ok := index.subnets[gateway.SubnetID]; ok && natSubnet.AvailabilityZone != subnet.AvailabilityZone {
+			issues = append(issues, fmt.Sprintf("Subnet %s in %s uses NAT gateway %s in %s; an outage of %s cuts it off and its traffic is billed as cross-AZ",
+				subnet.ID, subnet.AvailabilityZone, gateway.ID, natSubnet.AvailabilityZone, natSubnet.AvailabilityZone))
+		}
+	}
+
+	return issues
+}
@@ -0,0 +1,85 @@
+package mcp
+
+import (
+	"testing"
+
+	"aws-mcp-server/pkg/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testTopology() *types.VPCTopology {
+	return &types.VPCTopology{
+		VPCs: []types.VPC{{ID: "vpc-1", Name: "prod", CIDRs: []string{"10.0.0.0/16"}}},
+		Subnets: []types.Subnet{
+			{ID: "subnet-app-b", VpcID: "vpc-1", CIDR: "10.0.11.0/24", AvailabilityZone: "us-east-1b", AvailableIPs: 240},
+			{ID: "subnet-db", VpcID: "vpc-1", CIDR: "10.0.20.0/24", AvailabilityZone: "us-east-1a", AvailableIPs: 0},
+			{ID: "subnet-public", VpcID: "vpc-1", CIDR: "10.0.0.0/24", AvailabilityZone: "us-east-1a", AvailableIPs: 250, MapPublicIP: true},
+			{ID: "subnet-app-a", VpcID: "vpc-1", CIDR: "10.0.10.0/24", AvailabilityZone: "us-east-1a", AvailableIPs: 200},
+		},
+		RouteTables: []types.RouteTable{
+			// The main table has no default route, so subnets without an association are isolated
+			{ID: "rtb-main", VpcID: "vpc-1", Main: true, Routes: []types.Route{{Destination: "10.0.0.0/16", Target: "local", State: "active"}}},
+			{ID: "rtb-public", VpcID: "vpc-1", SubnetIDs: []string{"subnet-public"}, Routes: []types.Route{
+				{Destination: "10.0.0.0/16", Target: "local", State: "active"},
+				{Destination: "0.0.0.0/0", Target: "igw-1", State: "active"},
+			}},
+			{ID: "rtb-private", VpcID: "vpc-1", SubnetIDs: []string{"subnet-app-a", "subnet-app-b"}, Routes: []types.Route{
+				{Destination: "10.0.0.0/16", Target: "local", State: "active"},
+				{Destination: "0.0.0.0/0", Target: "nat-1", State: "active"},
+			}},
+		},
+		NATGateways:      []types.NATGateway{{ID: "nat-1", VpcID: "vpc-1", SubnetID: "subnet-public", State: "available", ConnectivityType: "public", PublicIP: "203.0.113.10"}},
+		InternetGateways: []types.InternetGateway{{ID: "igw-1", VpcIDs: []string{"vpc-1"}}},
+	}
+}
+
+func TestFormatVPCTopology(t *testing.T) {
+	formatted := formatVPCTopology(testTopology())
+	assert.Equal(t, 1, formatted["vpc_count"])
+	assert.Equal(t, map[string]int{"public": 1, "private": 2, "routed": 0, "isolated": 1}, formatted["subnets_by_tier"])
+
+	vpcs := formatted["vpcs"].([]map[string]interface{})
+	require.Len(t, vpcs, 1)
+	assert.Equal(t, "igw-1", vpcs[0]["internet_gateway"])
+
+	subnets := vpcs[0]["subnets"].([]map[string]interface{})
+	require.Len(t, subnets, 4)
+	assert.Equal(t, "subnet-public", subnets[0]["id"])
+	assert.Equal(t, []string{"subnet-public", "rtb-public", "igw-1"}, subnets[0]["path_to_internet"])
+	assert.Equal(t, "subnet-app-a", subnets[1]["id"])
+	assert.Equal(t, true, subnets[1]["internet_access"])
+	assert.Equal(t, []string{"subnet-app-a", "rtb-private", "nat-1", "subnet-public", "rtb-public", "igw-1"}, subnets[1]["path_to_internet"])
+	assert.Equal(t, "subnet-db", subnets[3]["id"])
+	assert.Equal(t, "isolated", subnets[3]["tier"])
+	assert.Equal(t, "rtb-main", subnets[3]["route_table"])
+	assert.Equal(t, false, subnets[3]["internet_access"])
+
+	tables := vpcs[0]["route_tables"].([]map[string]interface{})
+	assert.Equal(t, "rtb-main", tables[0]["id"])
+	assert.Equal(t, []string{"subnet-db"}, tables[0]["subnets"])
+
+	issues := formatted["issues"].([]string)
+	require.Len(t, issues, 2)
+	assert.Contains(t, issues[0], "subnet-app-b in us-east-1b uses NAT gateway nat-1 in us-east-1a")
+	assert.Contains(t, issues[1], "Subnet subnet-db has no free IP addresses")
+}
+
+func TestFormatVPCTopologyBrokenNAT(t *testing.T) {
+	topology := testTopology()
+	// The NAT gateway was deleted, leaving a blackhole route, and a new one
+	// was created in a private subnet by mistake
+	topology.RouteTables[2].Routes[1] = types.Route{Destination: "0.0.0.0/0", Target: "nat-1", State: "blackhole"}
+	topology.NATGateways = []types.NATGateway{{ID: "nat-2", VpcID: "vpc-1", SubnetID: "subnet-app-a", State: "available", ConnectivityType: "public"}}
+
+	formatted := formatVPCTopology(topology)
+	subnets := formatted["vpcs"].([]map[string]interface{})[0]["subnets"].([]map[string]interface{})
+	assert.Equal(t, "subnet-app-a", subnets[1]["id"])
+	assert.Equal(t, false, subnets[1]["internet_access"])
+	assert.Equal(t, []string{"subnet-app-a", "rtb-private", "nat-1"}, subnets[1]["path_to_internet"])
+
+	issues := formatted["issues"].([]string)
+	assert.Contains(t, issues, "Route 0.0.0.0/0 in rtb-private points to nat-1, which no longer exists (blackhole); affects subnet-app-a, subnet-app-b")
+	assert.Contains(t, issues, "NAT gateway nat-2 is in subnet subnet-app-a, which has no route to an internet gateway, so subnets behind it cannot reach the internet")
+}
@@ -0,0 +1,347 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+
+	"aws-mcp-server/internal/notify"
+	"aws-mcp-server/pkg/knowledge"
+	"aws-mcp-server/pkg/types"
+	"aws-mcp-server/pkg/verify"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const (
+	// defaultVerifyDuration and defaultVerifyInterval apply when
+	// remediation.verify_duration and verify_interval are not set
+	defaultVerifyDuration = 5 * time.Minute
+	defaultVerifyInterval = 30 * time.Second
+	// maxVerifyDuration bounds how long a single tool call may watch a signal
+	maxVerifyDuration = 30 * time.Minute
+	// minVerifyInterval keeps polling within API rate limits
+	minVerifyInterval = 5 * time.Second
+	// remediationLookback is how far back the audit log is searched for the
+	// action being verified when the caller does not name it
+	remediationLookback = time.Hour
+)
+
+// verifyRemediation watches the signal that prompted an action, such as an
+// alarm, an instance's status checks or a target's health, and reports
+// whether the action fixed it. The verdict is announced and recorded with the
+// action, so the loop from alert to action to outcome is closed and later
+// incidents can look up what worked.
+func (h *ToolHandler) verifyRemediation(ctx context.Context, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	signal, resource, check, err := h.remediationSignal(arguments)
+	if err != nil {
+		return h.createErrorResponse(err.Error())
+	}
+
+	duration := h.config.Remediation.VerifyDuration
+	if duration <= 0 {
+		duration = defaultVerifyDuration
+	}
+	if value, ok := arguments["durationSeconds"].(float64); ok {
+		duration = time.Duration(value) * time.Second
+	}
+	interval := h.config.Remediation.VerifyInterval
+	if interval <= 0 {
+		interval = defaultVerifyInterval
+	}
+	if value, ok := arguments["intervalSeconds"].(float64); ok {
+		interval = time.Duration(value) * time.Second
+	}
+	if duration <= 0 || duration > maxVerifyDuration {
+		return h.createErrorResponse(fmt.Sprintf("durationSeconds must be between 1 and %d", int(maxVerifyDuration.Seconds())))
+	}
+	if interval < minVerifyInterval || interval > duration {
+		return h.createErrorResponse(fmt.Sprintf("intervalSeconds must be at least %d and no longer than the duration", int(minVerifyInterval.Seconds())))
+	}
+
+	// A signal that cannot be read at all would only produce an inconclusive
+	// verdict after the whole duration, so it fails fast instead
+	if _, err := check(ctx); err != nil {
+		return h.createErrorResponse(fmt.Sprintf("failed to read %s: %v", signal, err))
+	}
+
+	started := time.Now()
+	signalType, alarmResource := h.signalContext(ctx, arguments)
+	if resource == "" {
+		resource = alarmResource
+	}
+	action, _ := arguments["action"].(string)
+	if action == "" {
+		action = h.recentAction(resource, started)
+	}
+
+	result := verify.Watch(ctx, check, duration, interval)
+	remediation, _ := arguments["remediation"].(string)
+
+	err = h.outcomes.Record(knowledge.Outcome{
+		Signal:      signalType,
+		Subject:     signal,
+		Action:      action,
+		Remediation: remediation,
+		Resource:    resource,
+		Verdict:     result.Verdict,
+		Summary:     result.Summary(signal),
+	})
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to record remediation outcome")
+	}
+
+	data := map[string]interface{}{
+		"signal":           signal,
+		"verdict":          result.Verdict,
+		"worked":           result.Worked(),
+		"summary":          result.Summary(signal),
+		"sample_count":     len(result.Samples),
+		"duration_seconds": int(time.Since(started).Seconds()),
+		"timeline":         h.formatTimeline(result.Samples),
+		"signal_type":      signalType,
+		"knowledge_uri":    remediationsURI + "?signal=" + url.QueryEscape(signalType),
+	}
+	if !result.HealthySince.IsZero() {
+		data["healthy_since"] = h.times.Format(result.HealthySince)
+	}
+	if result.Regressions > 0 {
+		data["regressions"] = result.Regressions
+	}
+	if remediation != "" {
+		data["remediation"] = remediation
+	}
+	if action != "" {
+		data["action"] = action
+	}
+
+	event := notify.Event{
+		Type:     notify.EventRemediationOK,
+		Severity: notify.SeverityInfo,
+		Title:    fmt.Sprintf("Remediation verified: %s", result.Summary(signal)),
+		Message:  remediation,
+		Fields: map[string]interface{}{
+			"signal":  signal,
+			"verdict": result.Verdict,
+			"action":  action,
+		},
+		Resource: resource,
+	}
+	if !result.Worked() {
+		event.Type = notify.EventRemediationFailed
+		event.Severity = notify.SeverityWarning
+		event.Title = fmt.Sprintf("Remediation not verified: %s", result.Summary(signal))
+	}
+	h.notifier.Notify(event)
+
+	return h.createSuccessResponse(result.Summary(signal), data)
+}
+
+// remediationSignal returns the signal named by the arguments, the resource
+// it belongs to and how to check it. Exactly one signal must be given.
+func (h *ToolHandler) remediationSignal(arguments map[string]interface{}) (string, string, verify.Check, error) {
+	alarmName, _ := arguments["alarmName"].(string)
+	instanceID, _ := arguments["instanceId"].(string)
+	targetGroupARN, _ := arguments["targetGroupArn"].(string)
+	targetID, _ := arguments["targetId"].(string)
+
+	given := 0
+	for _, value := range []string{alarmName, instanceID, targetGroupARN} {
+		if value != "" {
+			given++
+		}
+	}
+	if given != 1 {
+		return "", "", nil, fmt.Errorf("exactly one of alarmName, instanceId or targetGroupArn is required")
+	}
+
+	switch {
+	case alarmName != "":
+		return "alarm " + alarmName, "", func(ctx context.Context) (verify.Sample, error) {
+			alarms, err := h.awsClient.ListAlarms(ctx, alarmName)
+			if err != nil {
+				return verify.Sample{}, err
+			}
+			if len(alarms) == 0 {
+				return verify.Sample{}, fmt.Errorf("alarm %s not found", alarmName)
+			}
+			return alarmSample(alarms[0]), nil
+		}, nil
+
+	case instanceID != "":
+		return "status checks of " + instanceID, instanceID, func(ctx context.Context) (verify.Sample, error) {
+			status, err := h.awsClient.GetEC2InstanceStatus(ctx, instanceID)
+			if err != nil {
+				return verify.Sample{}, err
+			}
+			return instanceSample(*status), nil
+		}, nil
+
+	default:
+		signal := "targets of " + targetGroupName(targetGroupARN)
+		if targetID != "" {
+			signal = fmt.Sprintf("target %s of %s", targetID, targetGroupName(targetGroupARN))
+		}
+		return signal, targetID, func(ctx context.Context) (verify.Sample, error) {
+			targets, err := h.awsClient.GetTargetHealth(ctx, targetGroupARN)
+			if err != nil {
+				return verify.Sample{}, err
+			}
+			return targetSample(targets, targetID), nil
+		}, nil
+	}
+}
+
+// signalContext names the kind of signal for the outcome store and returns
+// the instance or database an alarm watches. An explicit signal argument wins;
+// otherwise the kind comes from the alarm's metric or from what is watched.
+func (h *ToolHandler) signalContext(ctx context.Context, arguments map[string]interface{}) (signalType, alarmResource string) {
+	signalType, _ = arguments["signal"].(string)
+	signalType = knowledge.Normalize(signalType)
+
+	alarmName, _ := arguments["alarmName"].(string)
+	switch {
+	case alarmName != "":
+		alarms, err := h.awsClient.ListAlarms(ctx, alarmName)
+		if err == nil && len(alarms) > 0 {
+			if signalType == "" {
+				signalType = knowledge.AlarmSignal(alarms[0].MetricName, alarms[0].ComparisonOperator)
+			}
+			for _, dimension := range []string{"InstanceId", "DBInstanceIdentifier"} {
+				if value := alarms[0].Dimensions[dimension]; value != "" {
+					alarmResource = value
+					break
+				}
+			}
+		}
+		if signalType == "" {
+			signalType = knowledge.Normalize(alarmName)
+		}
+	case signalType != "":
+	case arguments["instanceId"] != nil:
+		signalType = knowledge.SignalStatusCheckFailed
+	default:
+		signalType = knowledge.SignalUnhealthyTargets
+	}
+	return signalType, alarmResource
+}
+
+// recentAction returns the tool of the latest successful change to resource
+// in the hour before verification, most likely the remediation being verified
+func (h *ToolHandler) recentAction(resource string, before time.Time) string {
+	if resource == "" {
+		return ""
+	}
+	entries := h.audit.Between(before.Add(-remediationLookback), before)
+	for i := len(entries) - 1; i >= 0; i-- {
+		if entries[i].Success && resourceFromArguments(entries[i].Arguments) == resource {
+			return entries[i].Tool
+		}
+	}
+	return ""
+}
+
+// alarmSample reads an alarm: OK is healthy, ALARM unhealthy and
+// INSUFFICIENT_DATA unknown
+func alarmSample(alarm types.Alarm) verify.Sample {
+	sample := verify.Sample{State: alarm.State, Detail: alarm.StateReason}
+	switch alarm.State {
+	case "OK":
+		sample.Status = verify.StatusHealthy
+	case "ALARM":
+		sample.Status = verify.StatusUnhealthy
+	default:
+		sample.Status = verify.StatusUnknown
+	}
+	return sample
+}
+
+// instanceSample reads status checks: healthy when the instance runs and both
+// checks pass, unknown while they initialize
+func instanceSample(status types.InstanceStatus) verify.Sample {
+	sample := verify.Sample{
+		State:  status.State,
+		Detail: fmt.Sprintf("system %s, instance %s", status.SystemStatus, status.InstanceStatus),
+	}
+	switch {
+	case status.State == "pending":
+		sample.Status = verify.StatusUnknown
+	case status.State != "running":
+		sample.Status = verify.StatusUnhealthy
+		sample.Detail = ""
+	case status.SystemStatus == "ok" && status.InstanceStatus == "ok":
+		sample.Status = verify.StatusHealthy
+	case status.SystemStatus == "impaired" || status.InstanceStatus == "impaired":
+		sample.Status = verify.StatusUnhealthy
+	default:
+		sample.Status = verify.StatusUnknown
+	}
+	return sample
+}
+
+// targetSample reads target health: one target when targetID is given,
+// otherwise every registered target must be healthy. Targets still in their
+// initial health checks are unknown.
+func targetSample(targets []types.Target, targetID string) verify.Sample {
+	if targetID != "" {
+		for _, target := range targets {
+			if target.ID != targetID {
+				continue
+			}
+			sample := verify.Sample{State: target.State, Detail: target.Description}
+			switch target.State {
+			case "healthy":
+				sample.Status = verify.StatusHealthy
+			case "initial":
+				sample.Status = verify.StatusUnknown
+			default:
+				sample.Status = verify.StatusUnhealthy
+			}
+			return sample
+		}
+		return verify.Sample{Status: verify.StatusUnhealthy, State: "not registered"}
+	}
+
+	healthy, initial := 0, 0
+	for _, target := range targets {
+		switch target.State {
+		case "healthy":
+			healthy++
+		case "initial":
+			initial++
+		}
+	}
+	sample := verify.Sample{State: fmt.Sprintf("%d/%d healthy", healthy, len(targets))}
+	switch {
+	case len(targets) > 0 && healthy == len(targets):
+		sample.Status = verify.StatusHealthy
+	case healthy+initial == len(targets) && initial > 0:
+		sample.Status = verify.StatusUnknown
+	default:
+		sample.Status = verify.StatusUnhealthy
+	}
+	return sample
+}
+
+// formatTimeline lists the samples at which the signal changed, plus the last
+// one, so a long verification stays readable
+func (h *ToolHandler) formatTimeline(samples []verify.Sample) []map[string]interface{} {
+	timeline := make([]map[string]interface{}, 0, len(samples))
+	for i, sample := range samples {
+		changed := i == 0 || sample.Status != samples[i-1].Status || sample.State != samples[i-1].State
+		if !changed && i != len(samples)-1 {
+			continue
+		}
+		item := map[string]interface{}{
+			"time":   h.times.Format(sample.Time),
+			"status": sample.Status,
+			"state":  sample.State,
+		}
+		if sample.Detail != "" {
+			item["detail"] = sample.Detail
+		}
+		timeline = append(timeline, item)
+	}
+	return timeline
+}
@@ -0,0 +1,68 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"aws-mcp-server/internal/config"
+	"aws-mcp-server/internal/logging"
+	"aws-mcp-server/pkg/types"
+	"aws-mcp-server/pkg/verify"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRemediationSamples(t *testing.T) {
+	assert.Equal(t, verify.StatusHealthy, alarmSample(types.Alarm{State: "OK"}).Status)
+	assert.Equal(t, verify.StatusUnhealthy, alarmSample(types.Alarm{State: "ALARM"}).Status)
+	assert.Equal(t, verify.StatusUnknown, alarmSample(types.Alarm{State: "INSUFFICIENT_DATA"}).Status)
+
+	assert.Equal(t, verify.StatusHealthy, instanceSample(types.InstanceStatus{State: "running", SystemStatus: "ok", InstanceStatus: "ok"}).Status)
+	assert.Equal(t, verify.StatusUnknown, instanceSample(types.InstanceStatus{State: "running", SystemStatus: "ok", InstanceStatus: "initializing"}).Status)
+	assert.Equal(t, verify.StatusUnhealthy, instanceSample(types.InstanceStatus{State: "running", SystemStatus: "ok", InstanceStatus: "impaired"}).Status)
+	assert.Equal(t, verify.StatusUnhealthy, instanceSample(types.InstanceStatus{State: "stopped"}).Status)
+
+	targets := []types.Target{{ID: "i-1", State: "healthy"}, {ID: "i-2", State: "initial"}}
+	assert.Equal(t, verify.Sample{Status: verify.StatusUnknown, State: "1/2 healthy"}, targetSample(targets, ""))
+	assert.Equal(t, verify.StatusHealthy, targetSample(targets, "i-1").Status)
+	assert.Equal(t, verify.Sample{Status: verify.StatusUnhealthy, State: "not registered"}, targetSample(targets, "i-3"))
+	targets[1].State = "unhealthy"
+	assert.Equal(t, verify.StatusUnhealthy, targetSample(targets, "").Status)
+}
+
+func TestFormatTimelineKeepsChanges(t *testing.T) {
+	h := NewToolHandler(&config.Config{}, nil, logging.NewLogger("error", "text"))
+	start := time.Date(2025, 6, 2, 10, 0, 0, 0, time.UTC)
+	samples := []verify.Sample{
+		{Time: start, Status: verify.StatusUnhealthy, State: "ALARM"},
+		{Time: start.Add(30 * time.Second), Status: verify.StatusUnhealthy, State: "ALARM"},
+		{Time: start.Add(time.Minute), Status: verify.StatusHealthy, State: "OK"},
+		{Time: start.Add(90 * time.Second), Status: verify.StatusHealthy, State: "OK"},
+		{Time: start.Add(2 * time.Minute), Status: verify.StatusHealthy, State: "OK"},
+	}
+
+	timeline := h.formatTimeline(samples)
+	require.Len(t, timeline, 3)
+	assert.Equal(t, "ALARM", timeline[0]["state"])
+	assert.Equal(t, "OK", timeline[1]["state"])
+	assert.Equal(t, h.times.Format(start.Add(2*time.Minute)), timeline[2]["time"])
+}
+
+func TestVerifyRemediationRequiresOneSignal(t *testing.T) {
+	h := NewToolHandler(&config.Config{}, nil, logging.NewLogger("error", "text"))
+
+	for _, arguments := range []map[string]interface{}{
+		{},
+		{"alarmName": "cpu-high", "instanceId": "i-1"},
+	} {
+		result, err := h.CallTool(context.Background(), "verify-remediation", arguments)
+		require.NoError(t, err)
+		assert.Equal(t, "exactly one of alarmName, instanceId or targetGroupArn is required", decodeToolResult(t, result)["error"])
+	}
+
+	result, err := h.CallTool(context.Background(), "verify-remediation", map[string]interface{}{"alarmName": "cpu-high", "intervalSeconds": float64(1)})
+	require.NoError(t, err)
+	assert.Contains(t, decodeToolResult(t, result)["error"], "intervalSeconds must be at least 5")
+}
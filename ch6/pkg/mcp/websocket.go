@@ -0,0 +1,51 @@
+package mcp
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"sync"
+
+	"aws-mcp-server/pkg/session"
+	"aws-mcp-server/pkg/websocket"
+)
+
+// websocketProtocol is the subprotocol MCP clients offer on WebSocket
+const websocketProtocol = "mcp"
+
+// websocketHandler serves one MCP session per WebSocket connection, with
+// one JSON-RPC message per WebSocket message. Connections run through the
+// same loop as stdio, so elicitation, replays of retried write calls and
+// session recording work as they do there.
+type websocketHandler struct {
+	server   *Server
+	recorder *session.Recorder
+	conns    sync.WaitGroup
+}
+
+// ServeHTTP upgrades the request and serves the client until it disconnects
+// or the server stops
+func (h *websocketHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	logger := h.server.logger.WithField("remote", r.RemoteAddr)
+	conn, err := websocket.Upgrade(w, r, websocketProtocol)
+	if err != nil {
+		logger.WithError(err).Warn("Failed to open a WebSocket connection")
+		return
+	}
+	h.conns.Add(1)
+	defer h.conns.Done()
+	defer conn.Close()
+
+	// Closing the connection ends its read, so clients do not hold up the
+	// shutdown
+	ctx := r.Context()
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
+	logger.Info("WebSocket client connected")
+	err = h.server.serveClient(ctx, transportWebSocket, r.RemoteAddr, newClientPeer(conn.WriteMessage), h.recorder, conn.ReadMessage)
+	if err != io.EOF && ctx.Err() == nil {
+		logger.WithError(err).Warn("WebSocket connection failed")
+	}
+	logger.Info("WebSocket client disconnected")
+}
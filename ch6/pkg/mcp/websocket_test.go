@@ -0,0 +1,66 @@
+package mcp
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"aws-mcp-server/pkg/websocket"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// websocketCall sends a JSON-RPC request and decodes the response
+func websocketCall(t *testing.T, conn *websocket.Conn, id int, method string, params interface{}) map[string]interface{} {
+	t.Helper()
+	request, err := json.Marshal(map[string]interface{}{"jsonrpc": "2.0", "id": id, "method": method, "params": params})
+	require.NoError(t, err)
+	require.NoError(t, conn.WriteMessage(request))
+
+	message, err := conn.ReadMessage()
+	require.NoError(t, err)
+	var response map[string]interface{}
+	require.NoError(t, json.Unmarshal(message, &response))
+	assert.EqualValues(t, id, response["id"])
+	return response
+}
+
+func TestWebSocketTransport(t *testing.T) {
+	fleet, server := newHTTPTestServer(t)
+	conn, err := websocket.Dial("ws"+strings.TrimPrefix(server.URL, "http")+"/ws", websocketProtocol, nil)
+	require.NoError(t, err)
+	defer conn.Close()
+	assert.Equal(t, "mcp", conn.Protocol())
+
+	response := websocketCall(t, conn, 1, "initialize", map[string]interface{}{
+		"protocolVersion": "2025-03-26",
+		"capabilities":    map[string]interface{}{},
+		"clientInfo":      map[string]interface{}{"name": "browser", "version": "1.0.0"},
+	})
+	require.Contains(t, response, "result")
+	require.NoError(t, conn.WriteMessage([]byte(`{"jsonrpc":"2.0","method":"notifications/initialized"}`)))
+
+	response = websocketCall(t, conn, 2, "resources/read", map[string]interface{}{"uri": "aws://ec2/instances"})
+	contents := response["result"].(map[string]interface{})["contents"].([]interface{})
+	require.NotEmpty(t, contents)
+	assert.Contains(t, contents[0].(map[string]interface{})["text"], fleet.Anomalies()[0].InstanceID)
+
+	response = websocketCall(t, conn, 3, "tools/call", map[string]interface{}{"name": "no-such-tool"})
+	assert.Contains(t, response, "error")
+}
+
+func TestWebSocketOrigins(t *testing.T) {
+	_, server := newHTTPTestServer(t)
+	url := "ws" + strings.TrimPrefix(server.URL, "http") + "/ws"
+
+	_, err := websocket.Dial(url, websocketProtocol, http.Header{"Origin": {"https://evil.example"}})
+	assert.ErrorContains(t, err, "403")
+
+	for _, origin := range []string{"http://localhost:3000", server.URL} {
+		conn, err := websocket.Dial(url, websocketProtocol, http.Header{"Origin": {origin}})
+		require.NoError(t, err, origin)
+		conn.Close()
+	}
+}
@@ -0,0 +1,141 @@
+package mcp
+
+import (
+	"context"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// decryptWindowsPassword decrypts the base64 password data of a Windows
+// instance with the PEM private key of its key pair. EC2 encrypts it with
+// RSA PKCS #1 v1.5, so only RSA key pairs can be used.
+func decryptWindowsPassword(passwordData string, keyPEM []byte) (string, error) {
+	block, _ := pem.Decode(keyPEM)
+	if block == nil {
+		return "", fmt.Errorf("the key file is not PEM encoded")
+	}
+
+	var key *rsa.PrivateKey
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		parsed, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+		if err != nil {
+			return "", fmt.Errorf("failed to parse RSA private key: %w", err)
+		}
+		key = parsed
+	case "PRIVATE KEY":
+		parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return "", fmt.Errorf("failed to parse private key: %w", err)
+		}
+		rsaKey, ok := parsed.(*rsa.PrivateKey)
+		if !ok {
+			return "", fmt.Errorf("the key is not an RSA key; Windows passwords can only be decrypted with RSA key pairs")
+		}
+		key = rsaKey
+	default:
+		return "", fmt.Errorf("unsupported key type %q, use an RSA private key", block.Type)
+	}
+
+	encrypted, err := base64.StdEncoding.DecodeString(strings.TrimSpace(passwordData))
+	if err != nil {
+		return "", fmt.Errorf("failed to decode password data: %w", err)
+	}
+	password, err := rsa.DecryptPKCS1v15(nil, key, encrypted)
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt password, the key does not belong to the instance's key pair: %w", err)
+	}
+	return string(password), nil
+}
+
+// keyPairFile returns the path of a key pair's private key in the key
+// directory, refusing names that would point outside of it
+func keyPairFile(directory, keyName string) (string, error) {
+	if keyName == "" || keyName != filepath.Base(keyName) || strings.HasPrefix(keyName, ".") {
+		return "", fmt.Errorf("invalid key pair name %q", keyName)
+	}
+	return filepath.Join(directory, keyName+".pem"), nil
+}
+
+// getWindowsPassword decrypts the Administrator password EC2 generated when a
+// Windows instance launched, with the private key of its key pair from the
+// configured key directory. The password is only revealed once confirmed.
+func (h *ToolHandler) getWindowsPassword(ctx context.Context, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	instanceID, _ := arguments["instanceId"].(string)
+	if instanceID == "" {
+		return h.createErrorResponse("instanceId is required")
+	}
+	directory := h.config.Windows.KeyDirectory
+	if directory == "" {
+		return h.createErrorResponse("Windows password retrieval is not configured; set windows.key_directory")
+	}
+
+	instance, err := h.awsClient.GetEC2Instance(ctx, instanceID)
+	if err != nil {
+		return h.createErrorResponse(fmt.Sprintf("failed to get instance: %v", err))
+	}
+	details := instance.EC2Instance()
+	if details.Platform != "windows" {
+		return h.createErrorResponse(fmt.Sprintf("instance %s does not run Windows", instanceID))
+	}
+	keyName := details.KeyName
+	if keyName == "" {
+		return h.createErrorResponse(fmt.Sprintf("instance %s was launched without a key pair, so EC2 did not generate a password; use Session Manager instead", instanceID))
+	}
+
+	path, err := keyPairFile(directory, keyName)
+	if err != nil {
+		return h.createErrorResponse(err.Error())
+	}
+	keyPEM, err := os.ReadFile(path)
+	if err != nil {
+		return h.createErrorResponse(fmt.Sprintf("private key of key pair %s is not available: %v", keyName, err))
+	}
+
+	if !isConfirmed(arguments) {
+		plan := map[string]interface{}{
+			"instanceId": instanceID,
+			"keyName":    keyName,
+			"username":   "Administrator",
+		}
+		warnings := []string{
+			"The Administrator password is returned in the response, where the MCP client and its transcripts keep it",
+			"The call is audited; rotate the password on the instance once the incident is over",
+		}
+		return h.createConfirmationResponse("get-windows-password", plan, warnings)
+	}
+
+	passwordData, generated, err := h.awsClient.GetWindowsPasswordData(ctx, instanceID)
+	if err != nil {
+		return h.createErrorResponse(err.Error())
+	}
+	if passwordData == "" {
+		return h.createErrorResponse(fmt.Sprintf("the password of %s is not available; Windows generates it during the first boot, which takes a few minutes, "+
+			"and AMIs configured not to generate one never have it", instanceID))
+	}
+
+	password, err := decryptWindowsPassword(passwordData, keyPEM)
+	if err != nil {
+		return h.createErrorResponse(err.Error())
+	}
+
+	data := map[string]interface{}{
+		"instanceId": instanceID,
+		"keyName":    keyName,
+		"username":   "Administrator",
+		"password":   password,
+		"note":       "This is the password set at launch; it no longer works if it was changed on the instance",
+	}
+	if generated != nil {
+		data["generatedAt"] = h.times.Format(*generated)
+	}
+	return h.createSuccessResponse("Windows password retrieved successfully", data)
+}
@@ -0,0 +1,135 @@
+package mcp
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"aws-mcp-server/internal/config"
+	"aws-mcp-server/internal/logging"
+	"aws-mcp-server/pkg/aws"
+	"aws-mcp-server/pkg/fake"
+	"aws-mcp-server/pkg/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecryptWindowsPassword(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	encrypted, err := rsa.EncryptPKCS1v15(rand.Reader, &key.PublicKey, []byte("Pa55w0rd!"))
+	require.NoError(t, err)
+	passwordData := base64.StdEncoding.EncodeToString(encrypted)
+
+	pkcs1 := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	password, err := decryptWindowsPassword("\n"+passwordData+"\n", pkcs1)
+	require.NoError(t, err)
+	assert.Equal(t, "Pa55w0rd!", password)
+
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+	password, err = decryptWindowsPassword(passwordData, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}))
+	require.NoError(t, err)
+	assert.Equal(t, "Pa55w0rd!", password)
+
+	other, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	_, err = decryptWindowsPassword(passwordData, pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(other)}))
+	assert.ErrorContains(t, err, "does not belong to the instance's key pair")
+
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	der, err = x509.MarshalPKCS8PrivateKey(ecKey)
+	require.NoError(t, err)
+	_, err = decryptWindowsPassword(passwordData, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}))
+	assert.ErrorContains(t, err, "only be decrypted with RSA key pairs")
+
+	_, err = decryptWindowsPassword(passwordData, []byte("not a key"))
+	assert.ErrorContains(t, err, "not PEM encoded")
+}
+
+func TestKeyPairFile(t *testing.T) {
+	path, err := keyPairFile("/etc/aiops/keys", "prod-windows")
+	require.NoError(t, err)
+	assert.Equal(t, "/etc/aiops/keys/prod-windows.pem", path)
+
+	for _, name := range []string{"", "../secrets", "a/b", ".hidden"} {
+		_, err := keyPairFile("/etc/aiops/keys", name)
+		assert.Error(t, err, name)
+	}
+}
+
+func TestFormatWindowsInstance(t *testing.T) {
+	h := NewResourceHandler(&config.Config{}, nil)
+
+	formatted := h.formatInstanceForAI(types.CloudResource{ID: "i-1", State: "running",
+		Details: &types.EC2InstanceDetails{Platform: "windows", KeyName: "prod"}})
+	assert.Equal(t, "windows", formatted["platform"])
+	assert.Contains(t, formatted["remote_access"], "get-windows-password")
+
+	list := h.formatInstancesForAI([]types.CloudResource{
+		{ID: "i-1", Details: &types.EC2InstanceDetails{Platform: "windows"}},
+		{ID: "i-2", Details: &types.EC2InstanceDetails{Platform: "linux"}},
+		{ID: "i-3", Details: &types.EC2InstanceDetails{Platform: "linux"}},
+	})
+	assert.Equal(t, map[string]int{"windows": 1, "linux": 2}, list.SummaryByPlatform)
+}
+
+func TestGetWindowsPasswordValidatesArguments(t *testing.T) {
+	h := NewToolHandler(&config.Config{}, nil, logging.NewLogger("error", "text"))
+
+	result, err := h.CallTool(context.Background(), "get-windows-password", map[string]interface{}{})
+	require.NoError(t, err)
+	assert.Equal(t, "instanceId is required", decodeToolResult(t, result)["error"])
+
+	result, err = h.CallTool(context.Background(), "get-windows-password", map[string]interface{}{"instanceId": "i-1"})
+	require.NoError(t, err)
+	assert.Equal(t, "Windows password retrieval is not configured; set windows.key_directory", decodeToolResult(t, result)["error"])
+}
+
+func TestGetWindowsPasswordNeedsConfirmationAndElevation(t *testing.T) {
+	logger := logging.NewLogger("error", "text")
+	fleet := fake.New(config.FakeConfig{Instances: 20, Seed: 3}, "us-east-1")
+	awsClient := aws.NewClientFromConfig(fleet.Config(), logger)
+	instances, err := awsClient.ListEC2Instances(context.Background())
+	require.NoError(t, err)
+	var instanceID string
+	for _, instance := range instances {
+		if instance.EC2Instance().Platform == "windows" {
+			instanceID = instance.ID
+		}
+	}
+	require.NotEmpty(t, instanceID, "the fleet runs Windows instances")
+
+	directory := t.TempDir()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(directory, "ops.pem"),
+		pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}), 0o600))
+
+	h := NewToolHandler(&config.Config{
+		Access:  config.AccessConfig{ReadOnlyRoles: []string{"viewer"}},
+		Windows: config.WindowsConfig{KeyDirectory: directory},
+	}, awsClient, logger)
+
+	result, err := h.CallTool(context.Background(), "get-windows-password", map[string]interface{}{"instanceId": instanceID})
+	require.NoError(t, err)
+	response := decodeToolResult(t, result)
+	assert.Equal(t, true, response["confirmation_required"])
+	assert.Equal(t, "ops", response["plan"].(map[string]interface{})["keyName"])
+	assert.NotContains(t, response, "password")
+
+	viewer := WithRole(context.Background(), "viewer")
+	result, err = h.CallTool(viewer, "get-windows-password", map[string]interface{}{"instanceId": instanceID, "confirm": true})
+	require.NoError(t, err)
+	assert.Contains(t, decodeToolResult(t, result)["error"], "role viewer is read-only")
+}
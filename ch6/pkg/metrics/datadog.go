@@ -0,0 +1,129 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"aws-mcp-server/internal/config"
+	"aws-mcp-server/internal/logging"
+
+	"github.com/sirupsen/logrus"
+)
+
+// defaultDatadogSite is the US1 site
+const defaultDatadogSite = "datadoghq.com"
+
+// DatadogProvider queries the Datadog metrics API
+type DatadogProvider struct {
+	api    apiClient
+	logger *logging.Logger
+}
+
+// NewDatadogProvider creates a provider for a Datadog site. Both an API key and
+// an application key are needed to read metrics.
+func NewDatadogProvider(cfg config.DatadogConfig, timeout time.Duration, logger *logging.Logger) (*DatadogProvider, error) {
+	if cfg.APIKey == "" || cfg.AppKey == "" {
+		return nil, errors.New("metrics.datadog.api_key and metrics.datadog.app_key are required")
+	}
+
+	endpoint := cfg.URL
+	if endpoint == "" {
+		site := cfg.Site
+		if site == "" {
+			site = defaultDatadogSite
+		}
+		endpoint = "https://api." + site
+	}
+
+	api, err := newAPIClient(endpoint, map[string]string{
+		"DD-API-KEY":         cfg.APIKey,
+		"DD-APPLICATION-KEY": cfg.AppKey,
+	}, timeout)
+	if err != nil {
+		return nil, fmt.Errorf("datadog: %w", err)
+	}
+	return &DatadogProvider{api: api, logger: logger}, nil
+}
+
+// Name returns the provider name
+func (p *DatadogProvider) Name() string {
+	return ProviderDatadog
+}
+
+// datadogResponse is the v1 timeseries query response
+type datadogResponse struct {
+	Status string `json:"status"`
+	Error  string `json:"error"`
+	Series []struct {
+		Metric     string        `json:"metric"`
+		Expression string        `json:"expression"`
+		TagSet     []string      `json:"tag_set"`
+		PointList  [][2]*float64 `json:"pointlist"`
+	} `json:"series"`
+}
+
+// Query runs a Datadog metric query. Datadog picks the rollup interval for the
+// window; append .rollup(avg, <seconds>) to the query to force one, so Step
+// is not used.
+func (p *DatadogProvider) Query(ctx context.Context, query Query) ([]Series, error) {
+	start := time.Now()
+
+	if err := query.validate(); err != nil {
+		return nil, err
+	}
+
+	params := url.Values{
+		"query": {query.Expr},
+		"from":  {strconv.FormatInt(query.Start.Unix(), 10)},
+		"to":    {strconv.FormatInt(query.End.Unix(), 10)},
+	}
+
+	var resp datadogResponse
+	if err := p.api.do(ctx, http.MethodGet, "/api/v1/query", params, nil, &resp); err != nil {
+		return nil, fmt.Errorf("datadog query failed: %w", err)
+	}
+	if resp.Status == "error" {
+		return nil, fmt.Errorf("datadog query failed: %s", resp.Error)
+	}
+
+	series := make([]Series, 0, len(resp.Series))
+	for _, s := range resp.Series {
+		item := Series{
+			Name:   s.Metric,
+			Labels: make(map[string]string, len(s.TagSet)),
+		}
+		if item.Name == "" {
+			item.Name = s.Expression
+		}
+		for _, tag := range s.TagSet {
+			key, value, _ := strings.Cut(tag, ":")
+			item.Labels[key] = value
+		}
+
+		for _, point := range s.PointList {
+			// Gaps in the series are reported as null values
+			if point[0] == nil || point[1] == nil {
+				continue
+			}
+			item.Points = append(item.Points, Point{
+				Time:  time.UnixMilli(int64(*point[0])),
+				Value: *point[1],
+			})
+		}
+		series = append(series, item)
+	}
+
+	p.logger.WithFields(logrus.Fields{
+		"query":    query.Expr,
+		"series":   len(series),
+		"duration": time.Since(start),
+	}).Debug("Executed Datadog metric query")
+
+	return series, nil
+}
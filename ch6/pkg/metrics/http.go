@@ -0,0 +1,102 @@
+package metrics
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// maxResponseSize bounds how much of a provider response is read
+const maxResponseSize = 32 << 20
+
+// defaultTimeout applies when metrics.timeout is not configured
+const defaultTimeout = 30 * time.Second
+
+// apiClient holds what the HTTP-based providers share: the endpoint, auth
+// headers and client
+type apiClient struct {
+	baseURL *url.URL
+	headers http.Header
+	client  *http.Client
+}
+
+// newAPIClient validates the endpoint and prepares the request headers
+func newAPIClient(rawURL string, headers map[string]string, timeout time.Duration) (apiClient, error) {
+	baseURL, err := url.Parse(strings.TrimSuffix(rawURL, "/"))
+	if err != nil || baseURL.Scheme == "" || baseURL.Host == "" {
+		return apiClient{}, fmt.Errorf("invalid url %q", rawURL)
+	}
+
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+
+	c := apiClient{
+		baseURL: baseURL,
+		headers: make(http.Header),
+		client:  &http.Client{Timeout: timeout},
+	}
+	for key, value := range headers {
+		c.headers.Set(key, value)
+	}
+	return c, nil
+}
+
+// do sends a request to path and decodes the JSON response into out
+func (c apiClient) do(ctx context.Context, method, path string, params url.Values, body interface{}, out interface{}) error {
+	endpoint := c.baseURL.JoinPath(path)
+	if params != nil {
+		endpoint.RawQuery = params.Encode()
+	}
+
+	var reader io.Reader
+	if body != nil {
+		encoded, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to marshal request: %w", err)
+		}
+		reader = bytes.NewReader(encoded)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, endpoint.String(), reader)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	for key, values := range c.headers {
+		req.Header[key] = values
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("request to %s failed: %w", c.baseURL.Host, err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		snippet := bytes.TrimSpace(data)
+		if len(snippet) > 512 {
+			snippet = snippet[:512]
+		}
+		return fmt.Errorf("%s returned %s: %s", c.baseURL.Host, resp.Status, snippet)
+	}
+
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"aws-mcp-server/internal/config"
+	"aws-mcp-server/internal/logging"
+)
+
+// Supported providers
+const (
+	ProviderDatadog  = "datadog"
+	ProviderNewRelic = "newrelic"
+)
+
+// Query asks a provider for time series over a window. Expr is in the
+// provider's own language:
+//
+//	datadog:  a metric query such as avg:system.cpu.user{env:prod} by {host}
+//	newrelic: an NRQL query such as SELECT average(cpuPercent) FROM SystemSample FACET hostname
+//
+// Step is the requested resolution; providers may round it to what they support.
+type Query struct {
+	Expr  string
+	Start time.Time
+	End   time.Time
+	Step  time.Duration
+}
+
+// Point is one sample of a series
+type Point struct {
+	Time  time.Time
+	Value float64
+}
+
+// Series is one time series in a provider-neutral form. Name is the metric or
+// aggregate the values belong to and Labels identify the series (tags, facets).
+type Series struct {
+	Name   string
+	Labels map[string]string
+	Points []Point
+}
+
+// Provider runs metric queries against one monitoring platform
+type Provider interface {
+	Name() string
+	Query(ctx context.Context, query Query) ([]Series, error)
+}
+
+// New creates the configured provider. An empty provider means none is
+// configured and returns a nil Provider.
+func New(cfg config.MetricsConfig, logger *logging.Logger) (Provider, error) {
+	switch strings.ToLower(cfg.Provider) {
+	case "":
+		return nil, nil
+	case ProviderDatadog:
+		return NewDatadogProvider(cfg.Datadog, cfg.Timeout, logger)
+	case ProviderNewRelic:
+		return NewNewRelicProvider(cfg.NewRelic, cfg.Timeout, logger)
+	default:
+		return nil, fmt.Errorf("unknown metrics provider %q (use datadog or newrelic)", cfg.Provider)
+	}
+}
+
+// validate checks the parts of a query every provider needs
+func (q Query) validate() error {
+	if strings.TrimSpace(q.Expr) == "" {
+		return errors.New("a query expression is required")
+	}
+	if !q.End.After(q.Start) {
+		return errors.New("end must be after start")
+	}
+	return nil
+}
@@ -0,0 +1,138 @@
+package metrics
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"aws-mcp-server/internal/config"
+	"aws-mcp-server/internal/logging"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDatadogProvider(t *testing.T) {
+	var request *http.Request
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		request = r
+		w.Write([]byte(`{"status":"ok","series":[
+			{"metric":"system.cpu.user","expression":"avg:system.cpu.user{host:web-1}","tag_set":["host:web-1","env:prod"],
+			 "pointlist":[[1748772000000.0,12.5],[1748772060000.0,null],[1748772120000.0,40]]}]}`))
+	}))
+	defer server.Close()
+
+	provider, err := NewDatadogProvider(config.DatadogConfig{URL: server.URL, APIKey: "api", AppKey: "app"}, 0, logging.NewLogger("error", "text"))
+	require.NoError(t, err)
+
+	end := time.Unix(1748772200, 0)
+	series, err := provider.Query(context.Background(), Query{Expr: "avg:system.cpu.user{*} by {host}", Start: end.Add(-time.Hour), End: end})
+	require.NoError(t, err)
+
+	assert.Equal(t, "/api/v1/query", request.URL.Path)
+	assert.Equal(t, "avg:system.cpu.user{*} by {host}", request.URL.Query().Get("query"))
+	assert.Equal(t, "1748768600", request.URL.Query().Get("from"))
+	assert.Equal(t, "api", request.Header.Get("DD-API-KEY"))
+	assert.Equal(t, "app", request.Header.Get("DD-APPLICATION-KEY"))
+
+	require.Len(t, series, 1)
+	assert.Equal(t, "system.cpu.user", series[0].Name)
+	assert.Equal(t, map[string]string{"host": "web-1", "env": "prod"}, series[0].Labels)
+	require.Len(t, series[0].Points, 2, "null points are gaps")
+	assert.Equal(t, time.UnixMilli(1748772120000), series[0].Points[1].Time)
+	assert.Equal(t, 40.0, series[0].Points[1].Value)
+
+	_, err = NewDatadogProvider(config.DatadogConfig{APIKey: "api"}, 0, logging.NewLogger("error", "text"))
+	assert.ErrorContains(t, err, "app_key")
+}
+
+func TestNewRelicProvider(t *testing.T) {
+	var body struct {
+		Query     string                 `json:"query"`
+		Variables map[string]interface{} `json:"variables"`
+	}
+	var apiKey string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		apiKey = r.Header.Get("API-Key")
+		json.NewDecoder(r.Body).Decode(&body)
+		w.Write([]byte(`{"data":{"actor":{"account":{"nrql":{"results":[
+			{"beginTimeSeconds":1748772060,"endTimeSeconds":1748772120,"facet":"web-1","hostname":"web-1","average.cpuPercent":30,"percentile.duration":{"95":0.8}},
+			{"beginTimeSeconds":1748772000,"endTimeSeconds":1748772060,"facet":"web-1","hostname":"web-1","average.cpuPercent":20,"percentile.duration":{"95":0.5}},
+			{"beginTimeSeconds":1748772000,"endTimeSeconds":1748772060,"facet":"web-2","hostname":"web-2","average.cpuPercent":70,"percentile.duration":{"95":1.2}}]}}}}}`))
+	}))
+	defer server.Close()
+
+	provider, err := NewNewRelicProvider(config.NewRelicConfig{URL: server.URL, APIKey: "key", AccountID: 42}, 0, logging.NewLogger("error", "text"))
+	require.NoError(t, err)
+
+	end := time.UnixMilli(1748772120000)
+	series, err := provider.Query(context.Background(), Query{
+		Expr:  "SELECT average(cpuPercent), percentile(duration, 95) FROM SystemSample FACET hostname",
+		Start: end.Add(-2 * time.Minute),
+		End:   end,
+		Step:  time.Minute,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "key", apiKey)
+	assert.Equal(t, float64(42), body.Variables["accountId"])
+	assert.Equal(t, "SELECT average(cpuPercent), percentile(duration, 95) FROM SystemSample FACET hostname SINCE 1748772000000 UNTIL 1748772120000 TIMESERIES 1 minute", body.Variables["nrql"])
+
+	require.Len(t, series, 4)
+	assert.Equal(t, "average.cpuPercent", series[0].Name)
+	assert.Equal(t, map[string]string{"hostname": "web-1"}, series[0].Labels)
+	require.Len(t, series[0].Points, 2)
+	assert.Equal(t, 20.0, series[0].Points[0].Value, "points are in time order")
+	assert.Equal(t, "percentile.duration.95", series[3].Name)
+	assert.Equal(t, 1.2, series[3].Points[0].Value)
+}
+
+func TestNewRelicErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"data":{"actor":{"account":{"nrql":null}}},"errors":[{"message":"NRQL Syntax Error"}]}`))
+	}))
+	defer server.Close()
+
+	provider, err := NewNewRelicProvider(config.NewRelicConfig{URL: server.URL, APIKey: "key", AccountID: 42}, 0, logging.NewLogger("error", "text"))
+	require.NoError(t, err)
+
+	end := time.Now()
+	_, err = provider.Query(context.Background(), Query{Expr: "SELECT nonsense", Start: end.Add(-time.Hour), End: end})
+	assert.ErrorContains(t, err, "NRQL Syntax Error")
+}
+
+func TestWindowNRQL(t *testing.T) {
+	end := time.UnixMilli(1748772000000)
+
+	assert.Equal(t, "SELECT count(*) FROM Transaction SINCE 1748768400000 UNTIL 1748772000000 TIMESERIES AUTO",
+		windowNRQL(Query{Expr: "SELECT count(*) FROM Transaction", Start: end.Add(-time.Hour), End: end}))
+
+	assert.Equal(t, "SELECT count(*) FROM Transaction since 1 day ago timeseries",
+		windowNRQL(Query{Expr: "SELECT count(*) FROM Transaction since 1 day ago timeseries", Start: end.Add(-time.Hour), End: end}),
+		"clauses the caller wrote are kept")
+
+	// A week at one-minute buckets exceeds the bucket limit and is widened
+	assert.Contains(t, windowNRQL(Query{Expr: "SELECT count(*) FROM Transaction", Start: end.Add(-7 * 24 * time.Hour), End: end, Step: time.Minute}),
+		"TIMESERIES 32 minutes")
+}
+
+func TestNewSelectsProvider(t *testing.T) {
+	logger := logging.NewLogger("error", "text")
+
+	provider, err := New(config.MetricsConfig{}, logger)
+	require.NoError(t, err)
+	assert.Nil(t, provider)
+
+	provider, err = New(config.MetricsConfig{Provider: "NewRelic", NewRelic: config.NewRelicConfig{APIKey: "key", AccountID: 1, Region: "eu"}}, logger)
+	require.NoError(t, err)
+	assert.Equal(t, ProviderNewRelic, provider.Name())
+
+	_, err = New(config.MetricsConfig{Provider: "newrelic", NewRelic: config.NewRelicConfig{APIKey: "key", AccountID: 1, Region: "apac"}}, logger)
+	assert.ErrorContains(t, err, "unknown New Relic region")
+
+	_, err = New(config.MetricsConfig{Provider: "dynatrace"}, logger)
+	assert.ErrorContains(t, err, "unknown metrics provider")
+}
@@ -0,0 +1,244 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"aws-mcp-server/internal/config"
+	"aws-mcp-server/internal/logging"
+
+	"github.com/sirupsen/logrus"
+)
+
+// NerdGraph endpoints per data center region
+const (
+	newRelicUSEndpoint = "https://api.newrelic.com/graphql"
+	newRelicEUEndpoint = "https://api.eu.newrelic.com/graphql"
+)
+
+// maxNRQLBuckets is the most buckets a NRQL TIMESERIES clause may produce
+const maxNRQLBuckets = 366
+
+// nrqlQuery runs NRQL through NerdGraph for one account
+const nrqlQuery = `query($accountId: Int!, $nrql: Nrql!) {
+  actor { account(id: $accountId) { nrql(query: $nrql) { results } } }
+}`
+
+// nrqlClause detects window and bucketing clauses the caller already wrote
+var (
+	nrqlSince      = regexp.MustCompile(`(?i)\bSINCE\b`)
+	nrqlTimeseries = regexp.MustCompile(`(?i)\bTIMESERIES\b`)
+)
+
+// NewRelicProvider runs NRQL queries through the NerdGraph API
+type NewRelicProvider struct {
+	api       apiClient
+	accountID int
+	logger    *logging.Logger
+}
+
+// NewNewRelicProvider creates a provider for one New Relic account using a
+// user API key
+func NewNewRelicProvider(cfg config.NewRelicConfig, timeout time.Duration, logger *logging.Logger) (*NewRelicProvider, error) {
+	if cfg.APIKey == "" || cfg.AccountID == 0 {
+		return nil, errors.New("metrics.newrelic.api_key and metrics.newrelic.account_id are required")
+	}
+
+	endpoint := cfg.URL
+	if endpoint == "" {
+		switch strings.ToLower(cfg.Region) {
+		case "", "us":
+			endpoint = newRelicUSEndpoint
+		case "eu":
+			endpoint = newRelicEUEndpoint
+		default:
+			return nil, fmt.Errorf("unknown New Relic region %q (use us or eu)", cfg.Region)
+		}
+	}
+
+	api, err := newAPIClient(endpoint, map[string]string{"API-Key": cfg.APIKey}, timeout)
+	if err != nil {
+		return nil, fmt.Errorf("newrelic: %w", err)
+	}
+	return &NewRelicProvider{api: api, accountID: cfg.AccountID, logger: logger}, nil
+}
+
+// Name returns the provider name
+func (p *NewRelicProvider) Name() string {
+	return ProviderNewRelic
+}
+
+// nerdGraphResponse is the NRQL part of a NerdGraph response
+type nerdGraphResponse struct {
+	Data struct {
+		Actor struct {
+			Account struct {
+				NRQL *struct {
+					Results []map[string]interface{} `json:"results"`
+				} `json:"nrql"`
+			} `json:"account"`
+		} `json:"actor"`
+	} `json:"data"`
+	Errors []struct {
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+// Query runs a NRQL query. The window and TIMESERIES clause are added from the
+// query unless the NRQL already has them.
+func (p *NewRelicProvider) Query(ctx context.Context, query Query) ([]Series, error) {
+	start := time.Now()
+
+	if err := query.validate(); err != nil {
+		return nil, err
+	}
+
+	nrql := windowNRQL(query)
+	body := map[string]interface{}{
+		"query": nrqlQuery,
+		"variables": map[string]interface{}{
+			"accountId": p.accountID,
+			"nrql":      nrql,
+		},
+	}
+
+	var resp nerdGraphResponse
+	if err := p.api.do(ctx, http.MethodPost, "", nil, body, &resp); err != nil {
+		return nil, fmt.Errorf("new relic query failed: %w", err)
+	}
+	if len(resp.Errors) > 0 {
+		messages := make([]string, 0, len(resp.Errors))
+		for _, e := range resp.Errors {
+			messages = append(messages, e.Message)
+		}
+		return nil, fmt.Errorf("new relic query failed: %s", strings.Join(messages, "; "))
+	}
+	if resp.Data.Actor.Account.NRQL == nil {
+		return nil, fmt.Errorf("new relic returned no NRQL result for account %d", p.accountID)
+	}
+
+	series := nrqlSeries(resp.Data.Actor.Account.NRQL.Results, query.End)
+
+	p.logger.WithFields(logrus.Fields{
+		"nrql":     nrql,
+		"series":   len(series),
+		"duration": time.Since(start),
+	}).Debug("Executed New Relic NRQL query")
+
+	return series, nil
+}
+
+// windowNRQL appends SINCE/UNTIL and TIMESERIES clauses for the query window.
+// NRQL buckets are whole minutes here and are widened to stay within the
+// bucket limit.
+func windowNRQL(query Query) string {
+	nrql := strings.TrimSpace(query.Expr)
+
+	if !nrqlSince.MatchString(nrql) {
+		nrql += fmt.Sprintf(" SINCE %d UNTIL %d", query.Start.UnixMilli(), query.End.UnixMilli())
+	}
+	if nrqlTimeseries.MatchString(nrql) {
+		return nrql
+	}
+	if query.Step <= 0 {
+		return nrql + " TIMESERIES AUTO"
+	}
+
+	minutes := int(query.Step.Round(time.Minute) / time.Minute)
+	if minutes < 1 {
+		minutes = 1
+	}
+	window := int(query.End.Sub(query.Start) / time.Minute)
+	for window/minutes > maxNRQLBuckets {
+		minutes *= 2
+	}
+	if minutes == 1 {
+		return nrql + " TIMESERIES 1 minute"
+	}
+	return fmt.Sprintf("%s TIMESERIES %d minutes", nrql, minutes)
+}
+
+// nrqlSeries turns NRQL result rows into series. Numeric fields are values,
+// string fields are facet labels and nested objects (percentile and similar
+// functions) become one series per key.
+func nrqlSeries(rows []map[string]interface{}, end time.Time) []Series {
+	index := make(map[string]*Series)
+	var order []string
+
+	for _, row := range rows {
+		at := end
+		if begin, ok := row["beginTimeSeconds"].(float64); ok {
+			at = time.Unix(int64(begin), 0)
+		}
+
+		labels := make(map[string]string)
+		values := make(map[string]float64)
+		for key, value := range row {
+			switch key {
+			case "beginTimeSeconds", "endTimeSeconds", "facet":
+				continue
+			}
+			switch v := value.(type) {
+			case string:
+				labels[key] = v
+			case float64:
+				values[key] = v
+			case map[string]interface{}:
+				for sub, nested := range v {
+					if f, ok := nested.(float64); ok {
+						values[key+"."+sub] = f
+					}
+				}
+			}
+		}
+		if len(labels) == 0 && row["facet"] != nil {
+			labels["facet"] = fmt.Sprint(row["facet"])
+		}
+
+		for name, value := range values {
+			key := name + "\x00" + seriesKey(labels)
+			s, exists := index[key]
+			if !exists {
+				s = &Series{Name: name, Labels: labels}
+				index[key] = s
+				order = append(order, key)
+			}
+			s.Points = append(s.Points, Point{Time: at, Value: value})
+		}
+	}
+
+	sort.Strings(order)
+	series := make([]Series, 0, len(order))
+	for _, key := range order {
+		s := index[key]
+		sort.SliceStable(s.Points, func(i, j int) bool {
+			return s.Points[i].Time.Before(s.Points[j].Time)
+		})
+		series = append(series, *s)
+	}
+	return series
+}
+
+// seriesKey renders a label set in a stable order
+func seriesKey(labels map[string]string) string {
+	keys := make([]string, 0, len(labels))
+	for key := range labels {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	for _, key := range keys {
+		b.WriteString(key)
+		b.WriteByte('=')
+		b.WriteString(labels[key])
+		b.WriteByte(',')
+	}
+	return b.String()
+}
@@ -0,0 +1,170 @@
+package notes
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"aws-mcp-server/pkg/jsonfile"
+)
+
+// Note is context an operator or an AI agent attached to a resource, e.g.
+// "known flaky, vendor ticket #123". Resources are identified by the ID or
+// ARN tools take, such as an instance ID.
+type Note struct {
+	ID        string    `json:"id"`
+	Resource  string    `json:"resource"`
+	Text      string    `json:"text"`
+	CreatedBy string    `json:"createdBy"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// Store holds the notes of all resources. With a path, the notes are saved as
+// JSON after every change and reloaded on startup, so they carry over from one
+// session to the next.
+type Store struct {
+	mu     sync.Mutex
+	path   string
+	notes  map[string]Note
+	nextID int
+}
+
+// NewStore creates an empty in-memory store
+func NewStore() *Store {
+	return &Store{notes: make(map[string]Note), nextID: 1}
+}
+
+// Open creates a store saved to path. With an empty path notes are only kept in memory.
+func Open(path string) (*Store, error) {
+	s := NewStore()
+	s.path = path
+	if path == "" {
+		return s, nil
+	}
+
+	var notes []Note
+	if err := jsonfile.Load(path, "notes", &notes); err != nil {
+		return s, err
+	}
+	for _, note := range notes {
+		s.notes[note.ID] = note
+		// New notes are numbered after the highest ID on file
+		if n, err := strconv.Atoi(strings.TrimPrefix(note.ID, "note-")); err == nil && n >= s.nextID {
+			s.nextID = n + 1
+		}
+	}
+	return s, nil
+}
+
+// Add attaches a note to a resource and returns it with its ID
+func (s *Store) Add(resource, text, createdBy string, at time.Time) (Note, error) {
+	resource = strings.TrimSpace(resource)
+	text = strings.TrimSpace(text)
+	if resource == "" {
+		return Note{}, errors.New("a note needs a resource")
+	}
+	if text == "" {
+		return Note{}, errors.New("a note needs a text")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	note := Note{
+		ID:        fmt.Sprintf("note-%d", s.nextID),
+		Resource:  resource,
+		Text:      text,
+		CreatedBy: createdBy,
+		CreatedAt: at,
+	}
+	s.nextID++
+	s.notes[note.ID] = note
+	return note, s.save()
+}
+
+// Remove deletes a note and returns it
+func (s *Store) Remove(id string) (Note, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	note, exists := s.notes[id]
+	if !exists {
+		return Note{}, fmt.Errorf("note %s not found", id)
+	}
+
+	delete(s.notes, id)
+	return note, s.save()
+}
+
+// For returns the notes of a resource, oldest first
+func (s *Store) For(resource string) []Note {
+	if s == nil {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var notes []Note
+	for _, note := range s.notes {
+		if note.Resource == resource {
+			notes = append(notes, note)
+		}
+	}
+	sortNotes(notes)
+	return notes
+}
+
+// All returns every note by resource, oldest first within a resource
+func (s *Store) All() []Note {
+	if s == nil {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	notes := make([]Note, 0, len(s.notes))
+	for _, note := range s.notes {
+		notes = append(notes, note)
+	}
+	sort.SliceStable(notes, func(i, j int) bool {
+		if notes[i].Resource != notes[j].Resource {
+			return notes[i].Resource < notes[j].Resource
+		}
+		return less(notes[i], notes[j])
+	})
+	return notes
+}
+
+// sortNotes orders notes oldest first
+func sortNotes(notes []Note) {
+	sort.Slice(notes, func(i, j int) bool { return less(notes[i], notes[j]) })
+}
+
+// less orders notes by creation time, then by ID for notes created together
+func less(a, b Note) bool {
+	if !a.CreatedAt.Equal(b.CreatedAt) {
+		return a.CreatedAt.Before(b.CreatedAt)
+	}
+	return a.ID < b.ID
+}
+
+// save writes all notes to the store file, replacing it atomically
+func (s *Store) save() error {
+	if s.path == "" {
+		return nil
+	}
+
+	notes := make([]Note, 0, len(s.notes))
+	for _, note := range s.notes {
+		notes = append(notes, note)
+	}
+	sortNotes(notes)
+
+	return jsonfile.SaveIndent(s.path, "notes", notes)
+}
@@ -0,0 +1,65 @@
+package notes
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStoreNotesByResource(t *testing.T) {
+	s := NewStore()
+	now := time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC)
+
+	_, err := s.Add("i-1", "  ", "sre", now)
+	assert.ErrorContains(t, err, "needs a text")
+
+	second, err := s.Add("i-1", "reboot fixed it on 2025-06-01", "agent", now.Add(time.Hour))
+	require.NoError(t, err)
+	first, err := s.Add(" i-1 ", "known flaky, vendor ticket #123", "sre", now)
+	require.NoError(t, err)
+	_, err = s.Add("orders-queue", "consumers are paused on Sundays", "sre", now)
+	require.NoError(t, err)
+
+	assert.Equal(t, []Note{first, second}, s.For("i-1"), "notes of a resource are oldest first")
+	assert.Equal(t, "i-1", first.Resource)
+	assert.Empty(t, s.For("i-2"))
+
+	all := s.All()
+	require.Len(t, all, 3)
+	assert.Equal(t, "orders-queue", all[2].Resource)
+
+	removed, err := s.Remove(first.ID)
+	require.NoError(t, err)
+	assert.Equal(t, first, removed)
+	_, err = s.Remove(first.ID)
+	assert.ErrorContains(t, err, "not found")
+
+	var none *Store
+	assert.Empty(t, none.For("i-1"))
+}
+
+func TestStorePersists(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state", "notes.json")
+	now := time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC)
+
+	s, err := Open(path)
+	require.NoError(t, err)
+	_, err = s.Add("i-1", "known flaky, vendor ticket #123", "sre", now)
+	require.NoError(t, err)
+	_, err = s.Add("i-1", "draining for the migration", "agent", now)
+	require.NoError(t, err)
+
+	reopened, err := Open(path)
+	require.NoError(t, err)
+	notes := reopened.For("i-1")
+	require.Len(t, notes, 2)
+	assert.Equal(t, "note-1", notes[0].ID)
+	assert.Equal(t, "sre", notes[0].CreatedBy)
+
+	added, err := reopened.Add("i-1", "back in service", "sre", now)
+	require.NoError(t, err)
+	assert.Equal(t, "note-3", added.ID, "IDs continue after the notes on file")
+}
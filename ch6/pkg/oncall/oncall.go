@@ -0,0 +1,206 @@
+package oncall
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"aws-mcp-server/internal/config"
+	"aws-mcp-server/internal/logging"
+)
+
+// Supported providers
+const (
+	ProviderPagerDuty = "pagerduty"
+	ProviderOpsgenie  = "opsgenie"
+)
+
+const (
+	// defaultCacheTTL is how long on-call answers are reused when oncall.cache_ttl is not set
+	defaultCacheTTL = time.Minute
+	// requestTimeout bounds a single provider API call
+	requestTimeout = 10 * time.Second
+	// maxResponseSize bounds how much of a provider response is read
+	maxResponseSize = 4 << 20
+)
+
+// Shift is one person currently on call. Start and End are zero when the
+// provider does not report shift boundaries or the person is always on call.
+type Shift struct {
+	Schedule        string    `json:"schedule"`
+	Name            string    `json:"name"`
+	Email           string    `json:"email,omitempty"`
+	SlackID         string    `json:"slack_id,omitempty"`
+	EscalationLevel int       `json:"escalation_level,omitempty"`
+	Start           time.Time `json:"start,omitempty"`
+	End             time.Time `json:"end,omitempty"`
+}
+
+// Provider asks an incident management platform who is on call right now
+type Provider interface {
+	Name() string
+	Current(ctx context.Context) ([]Shift, error)
+}
+
+// Roster answers who is on call from a provider, caching answers briefly and
+// resolving Slack users so notifications can mention them
+type Roster struct {
+	provider   Provider
+	slackUsers map[string]string
+	ttl        time.Duration
+
+	mu      sync.Mutex
+	shifts  []Shift
+	fetched time.Time
+}
+
+// New creates a roster for the configured provider. An empty provider means
+// on-call lookups are disabled and returns a nil Roster.
+func New(cfg config.OnCallConfig, logger *logging.Logger) (*Roster, error) {
+	var provider Provider
+	var err error
+
+	switch strings.ToLower(cfg.Provider) {
+	case "":
+		return nil, nil
+	case ProviderPagerDuty:
+		provider, err = NewPagerDutyProvider(cfg.PagerDuty, logger)
+	case ProviderOpsgenie:
+		provider, err = NewOpsgenieProvider(cfg.Opsgenie, logger)
+	default:
+		return nil, fmt.Errorf("unknown on-call provider %q (use pagerduty or opsgenie)", cfg.Provider)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return NewRoster(provider, cfg.SlackUsers, cfg.CacheTTL), nil
+}
+
+// NewRoster wraps a provider. slackUsers maps email addresses to Slack user IDs.
+func NewRoster(provider Provider, slackUsers map[string]string, ttl time.Duration) *Roster {
+	if ttl <= 0 {
+		ttl = defaultCacheTTL
+	}
+
+	// viper lowercases map keys, so emails are matched case-insensitively
+	users := make(map[string]string, len(slackUsers))
+	for email, id := range slackUsers {
+		users[strings.ToLower(email)] = id
+	}
+
+	return &Roster{provider: provider, slackUsers: users, ttl: ttl}
+}
+
+// Provider returns the name of the underlying provider
+func (r *Roster) Provider() string {
+	return r.provider.Name()
+}
+
+// Current returns who is on call, ordered by escalation level and schedule.
+// Answers are cached for the roster's TTL.
+func (r *Roster) Current(ctx context.Context) ([]Shift, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.shifts != nil && time.Since(r.fetched) < r.ttl {
+		return r.shifts, nil
+	}
+
+	shifts, err := r.provider.Current(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range shifts {
+		if shifts[i].Email != "" {
+			shifts[i].SlackID = r.slackUsers[strings.ToLower(shifts[i].Email)]
+		}
+	}
+	sort.SliceStable(shifts, func(i, j int) bool {
+		if shifts[i].EscalationLevel != shifts[j].EscalationLevel {
+			return shifts[i].EscalationLevel < shifts[j].EscalationLevel
+		}
+		return shifts[i].Schedule < shifts[j].Schedule
+	})
+
+	r.shifts = shifts
+	r.fetched = time.Now()
+	return shifts, nil
+}
+
+// Flush drops the cached shifts, so the next lookup asks the provider
+func (r *Roster) Flush() {
+	if r == nil {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.shifts = nil
+}
+
+// Primary returns the first-level responders: the lowest escalation level
+// present, or everyone when levels are not reported
+func Primary(shifts []Shift) []Shift {
+	if len(shifts) == 0 {
+		return nil
+	}
+
+	level := shifts[0].EscalationLevel
+	for _, shift := range shifts {
+		if shift.EscalationLevel < level {
+			level = shift.EscalationLevel
+		}
+	}
+
+	var primary []Shift
+	for _, shift := range shifts {
+		if shift.EscalationLevel == level {
+			primary = append(primary, shift)
+		}
+	}
+	return primary
+}
+
+// getJSON sends a GET request with the given headers and decodes the JSON response
+func getJSON(ctx context.Context, client *http.Client, url string, headers map[string]string, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		snippet := bytes.TrimSpace(data)
+		if len(snippet) > 512 {
+			snippet = snippet[:512]
+		}
+		return fmt.Errorf("%s: %s", resp.Status, snippet)
+	}
+
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
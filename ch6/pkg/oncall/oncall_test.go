@@ -0,0 +1,136 @@
+package oncall
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"aws-mcp-server/internal/config"
+	"aws-mcp-server/internal/logging"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPagerDutyProvider(t *testing.T) {
+	var requests []*http.Request
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r)
+		if r.URL.Query().Get("offset") == "0" {
+			w.Write([]byte(`{"more":true,"oncalls":[
+				{"user":{"id":"P1","summary":"Dana Lee","name":"Dana Lee","email":"dana@example.com"},
+				 "schedule":{"id":"S1","summary":"Platform primary"},"escalation_policy":{"summary":"Platform"},
+				 "escalation_level":1,"start":"2025-06-01T09:00:00Z","end":"2025-06-08T09:00:00Z"},
+				{"user":{"id":"P2","summary":"Sam Ortiz","email":"sam@example.com"},
+				 "schedule":null,"escalation_policy":{"summary":"Platform"},"escalation_level":2,"start":null,"end":null}]}`))
+			return
+		}
+		// The same entry can appear twice across pages
+		w.Write([]byte(`{"more":false,"oncalls":[
+			{"user":{"id":"P1","summary":"Dana Lee","name":"Dana Lee","email":"dana@example.com"},
+			 "schedule":{"id":"S1","summary":"Platform primary"},"escalation_policy":{"summary":"Platform"},"escalation_level":1}]}`))
+	}))
+	defer server.Close()
+
+	provider, err := NewPagerDutyProvider(config.PagerDutyConfig{URL: server.URL, APIToken: "token", ScheduleIDs: []string{"S1"}}, logging.NewLogger("error", "text"))
+	require.NoError(t, err)
+
+	shifts, err := provider.Current(context.Background())
+	require.NoError(t, err)
+
+	require.Len(t, requests, 2)
+	assert.Equal(t, "/oncalls", requests[0].URL.Path)
+	assert.Equal(t, "Token token=token", requests[0].Header.Get("Authorization"))
+	assert.Equal(t, []string{"S1"}, requests[0].URL.Query()["schedule_ids[]"])
+	assert.Equal(t, "2", requests[1].URL.Query().Get("offset"))
+
+	require.Len(t, shifts, 2)
+	assert.Equal(t, Shift{
+		Schedule:        "Platform primary",
+		Name:            "Dana Lee",
+		Email:           "dana@example.com",
+		EscalationLevel: 1,
+		Start:           time.Date(2025, 6, 1, 9, 0, 0, 0, time.UTC),
+		End:             time.Date(2025, 6, 8, 9, 0, 0, 0, time.UTC),
+	}, shifts[0])
+	assert.Equal(t, "Platform", shifts[1].Schedule, "direct escalation entries use the policy name")
+	assert.Equal(t, "Sam Ortiz", shifts[1].Name)
+	assert.True(t, shifts[1].End.IsZero())
+}
+
+func TestOpsgenieProvider(t *testing.T) {
+	var paths []string
+	var auth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		auth = r.Header.Get("Authorization")
+		w.Write([]byte(`{"data":{"_parent":{"name":"Payments Rota"},"onCallRecipients":["kim@example.com"]}}`))
+	}))
+	defer server.Close()
+
+	provider, err := NewOpsgenieProvider(config.OpsgenieConfig{URL: server.URL, APIKey: "key", Schedules: []string{"Payments Rota"}}, logging.NewLogger("error", "text"))
+	require.NoError(t, err)
+
+	shifts, err := provider.Current(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"/v2/schedules/Payments Rota/on-calls"}, paths)
+	assert.Equal(t, "GenieKey key", auth)
+	assert.Equal(t, []Shift{{Schedule: "Payments Rota", Name: "kim@example.com", Email: "kim@example.com"}}, shifts)
+
+	_, err = NewOpsgenieProvider(config.OpsgenieConfig{APIKey: "key"}, logging.NewLogger("error", "text"))
+	assert.ErrorContains(t, err, "at least one schedule")
+}
+
+// countingProvider returns fixed shifts and counts calls
+type countingProvider struct {
+	calls  int
+	shifts []Shift
+}
+
+func (p *countingProvider) Name() string { return "fake" }
+
+func (p *countingProvider) Current(ctx context.Context) ([]Shift, error) {
+	p.calls++
+	return append([]Shift(nil), p.shifts...), nil
+}
+
+func TestRoster(t *testing.T) {
+	provider := &countingProvider{shifts: []Shift{
+		{Schedule: "Secondary", Name: "Sam", Email: "sam@example.com", EscalationLevel: 2},
+		{Schedule: "Primary", Name: "Dana", Email: "Dana@Example.com", EscalationLevel: 1},
+	}}
+	roster := NewRoster(provider, map[string]string{"dana@example.com": "U123"}, time.Minute)
+
+	shifts, err := roster.Current(context.Background())
+	require.NoError(t, err)
+	_, err = roster.Current(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, provider.calls, "answers are cached")
+	require.Len(t, shifts, 2)
+	assert.Equal(t, "Dana", shifts[0].Name, "ordered by escalation level")
+	assert.Equal(t, "U123", shifts[0].SlackID, "emails match case-insensitively")
+	assert.Empty(t, shifts[1].SlackID)
+
+	primary := Primary(shifts)
+	require.Len(t, primary, 1)
+	assert.Equal(t, "Dana", primary[0].Name)
+}
+
+func TestNewSelectsProvider(t *testing.T) {
+	logger := logging.NewLogger("error", "text")
+
+	roster, err := New(config.OnCallConfig{}, logger)
+	require.NoError(t, err)
+	assert.Nil(t, roster)
+
+	roster, err = New(config.OnCallConfig{Provider: "PagerDuty", PagerDuty: config.PagerDutyConfig{APIToken: "token"}}, logger)
+	require.NoError(t, err)
+	assert.Equal(t, ProviderPagerDuty, roster.Provider())
+
+	_, err = New(config.OnCallConfig{Provider: "victorops"}, logger)
+	assert.ErrorContains(t, err, "unknown on-call provider")
+}
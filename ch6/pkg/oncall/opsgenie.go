@@ -0,0 +1,121 @@
+package oncall
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"aws-mcp-server/internal/config"
+	"aws-mcp-server/internal/logging"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Opsgenie API endpoints per region
+const (
+	opsgenieUSAPI = "https://api.opsgenie.com"
+	opsgenieEUAPI = "https://api.eu.opsgenie.com"
+)
+
+// OpsgenieProvider reads current on-call recipients of Opsgenie schedules
+type OpsgenieProvider struct {
+	baseURL   string
+	apiKey    string
+	schedules []string
+	client    *http.Client
+	logger    *logging.Logger
+}
+
+// NewOpsgenieProvider creates a provider for the named schedules using an API
+// integration key with read access
+func NewOpsgenieProvider(cfg config.OpsgenieConfig, logger *logging.Logger) (*OpsgenieProvider, error) {
+	if cfg.APIKey == "" {
+		return nil, errors.New("oncall.opsgenie.api_key is required")
+	}
+	if len(cfg.Schedules) == 0 {
+		return nil, errors.New("oncall.opsgenie.schedules must list at least one schedule")
+	}
+
+	baseURL := cfg.URL
+	if baseURL == "" {
+		switch strings.ToLower(cfg.Region) {
+		case "", "us":
+			baseURL = opsgenieUSAPI
+		case "eu":
+			baseURL = opsgenieEUAPI
+		default:
+			return nil, fmt.Errorf("unknown Opsgenie region %q (use us or eu)", cfg.Region)
+		}
+	}
+
+	return &OpsgenieProvider{
+		baseURL:   strings.TrimSuffix(baseURL, "/"),
+		apiKey:    cfg.APIKey,
+		schedules: cfg.Schedules,
+		client:    &http.Client{Timeout: requestTimeout},
+		logger:    logger,
+	}, nil
+}
+
+// Name returns the provider name
+func (p *OpsgenieProvider) Name() string {
+	return ProviderOpsgenie
+}
+
+// opsgenieOnCalls is the flat response of GET /v2/schedules/{name}/on-calls
+type opsgenieOnCalls struct {
+	Data struct {
+		Parent struct {
+			Name string `json:"name"`
+		} `json:"_parent"`
+		OnCallRecipients []string `json:"onCallRecipients"`
+	} `json:"data"`
+}
+
+// Current returns the users on call for each configured schedule. Opsgenie
+// identifies users by their username, which is their email address.
+func (p *OpsgenieProvider) Current(ctx context.Context) ([]Shift, error) {
+	start := time.Now()
+
+	headers := map[string]string{
+		"Authorization": "GenieKey " + p.apiKey,
+		"Accept":        "application/json",
+	}
+
+	var shifts []Shift
+	for _, schedule := range p.schedules {
+		endpoint := fmt.Sprintf("%s/v2/schedules/%s/on-calls?%s", p.baseURL, url.PathEscape(schedule), url.Values{
+			"scheduleIdentifierType": {"name"},
+			"flat":                   {"true"},
+		}.Encode())
+
+		var resp opsgenieOnCalls
+		if err := getJSON(ctx, p.client, endpoint, headers, &resp); err != nil {
+			return nil, fmt.Errorf("failed to get on-calls for Opsgenie schedule %q: %w", schedule, err)
+		}
+
+		name := resp.Data.Parent.Name
+		if name == "" {
+			name = schedule
+		}
+		for _, recipient := range resp.Data.OnCallRecipients {
+			shift := Shift{Schedule: name, Name: recipient}
+			if strings.Contains(recipient, "@") {
+				shift.Email = recipient
+			}
+			shifts = append(shifts, shift)
+		}
+	}
+
+	p.logger.WithFields(logrus.Fields{
+		"schedules": len(p.schedules),
+		"count":     len(shifts),
+		"duration":  time.Since(start),
+	}).Debug("Retrieved Opsgenie on-calls")
+
+	return shifts, nil
+}
@@ -0,0 +1,155 @@
+package oncall
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"aws-mcp-server/internal/config"
+	"aws-mcp-server/internal/logging"
+
+	"github.com/sirupsen/logrus"
+)
+
+// pagerDutyAPI is the PagerDuty REST API endpoint
+const pagerDutyAPI = "https://api.pagerduty.com"
+
+// PagerDutyProvider reads current on-call entries from PagerDuty
+type PagerDutyProvider struct {
+	baseURL            string
+	token              string
+	scheduleIDs        []string
+	escalationPolicies []string
+	client             *http.Client
+	logger             *logging.Logger
+}
+
+// NewPagerDutyProvider creates a provider using a read-only REST API key.
+// Lookups can be limited to schedules and escalation policies; otherwise
+// every on-call entry in the account is returned.
+func NewPagerDutyProvider(cfg config.PagerDutyConfig, logger *logging.Logger) (*PagerDutyProvider, error) {
+	if cfg.APIToken == "" {
+		return nil, errors.New("oncall.pagerduty.api_token is required")
+	}
+
+	baseURL := cfg.URL
+	if baseURL == "" {
+		baseURL = pagerDutyAPI
+	}
+
+	return &PagerDutyProvider{
+		baseURL:            strings.TrimSuffix(baseURL, "/"),
+		token:              cfg.APIToken,
+		scheduleIDs:        cfg.ScheduleIDs,
+		escalationPolicies: cfg.EscalationPolicyIDs,
+		client:             &http.Client{Timeout: requestTimeout},
+		logger:             logger,
+	}, nil
+}
+
+// Name returns the provider name
+func (p *PagerDutyProvider) Name() string {
+	return ProviderPagerDuty
+}
+
+// pagerDutyReference is a PagerDuty object reference
+type pagerDutyReference struct {
+	ID      string `json:"id"`
+	Summary string `json:"summary"`
+	Name    string `json:"name"`
+	Email   string `json:"email"`
+}
+
+// pagerDutyOnCalls is the response of GET /oncalls
+type pagerDutyOnCalls struct {
+	OnCalls []struct {
+		User             pagerDutyReference  `json:"user"`
+		Schedule         *pagerDutyReference `json:"schedule"`
+		EscalationPolicy pagerDutyReference  `json:"escalation_policy"`
+		EscalationLevel  int                 `json:"escalation_level"`
+		Start            *time.Time          `json:"start"`
+		End              *time.Time          `json:"end"`
+	} `json:"oncalls"`
+	More   bool `json:"more"`
+	Offset int  `json:"offset"`
+	Limit  int  `json:"limit"`
+}
+
+// Current returns the on-call entries active now, one per user, schedule and level
+func (p *PagerDutyProvider) Current(ctx context.Context) ([]Shift, error) {
+	start := time.Now()
+
+	params := url.Values{
+		"include[]": {"users"},
+		"limit":     {"100"},
+	}
+	for _, id := range p.scheduleIDs {
+		params.Add("schedule_ids[]", id)
+	}
+	for _, id := range p.escalationPolicies {
+		params.Add("escalation_policy_ids[]", id)
+	}
+
+	headers := map[string]string{
+		"Authorization": "Token token=" + p.token,
+		"Accept":        "application/vnd.pagerduty+json;version=2",
+	}
+
+	var shifts []Shift
+	seen := make(map[string]bool)
+	for offset := 0; ; {
+		params.Set("offset", fmt.Sprint(offset))
+
+		var resp pagerDutyOnCalls
+		if err := getJSON(ctx, p.client, p.baseURL+"/oncalls?"+params.Encode(), headers, &resp); err != nil {
+			return nil, fmt.Errorf("failed to list PagerDuty on-calls: %w", err)
+		}
+
+		for _, entry := range resp.OnCalls {
+			// Without a schedule the user is on call directly through the escalation policy
+			schedule := entry.EscalationPolicy.Summary
+			if entry.Schedule != nil {
+				schedule = entry.Schedule.Summary
+			}
+
+			key := fmt.Sprintf("%s/%s/%d", entry.User.ID, schedule, entry.EscalationLevel)
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+
+			shift := Shift{
+				Schedule:        schedule,
+				Name:            entry.User.Name,
+				Email:           entry.User.Email,
+				EscalationLevel: entry.EscalationLevel,
+			}
+			if shift.Name == "" {
+				shift.Name = entry.User.Summary
+			}
+			if entry.Start != nil {
+				shift.Start = *entry.Start
+			}
+			if entry.End != nil {
+				shift.End = *entry.End
+			}
+			shifts = append(shifts, shift)
+		}
+
+		if !resp.More || len(resp.OnCalls) == 0 {
+			break
+		}
+		offset += len(resp.OnCalls)
+	}
+
+	p.logger.WithFields(logrus.Fields{
+		"count":    len(shifts),
+		"duration": time.Since(start),
+	}).Debug("Retrieved PagerDuty on-calls")
+
+	return shifts, nil
+}
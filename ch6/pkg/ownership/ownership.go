@@ -0,0 +1,369 @@
+package ownership
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"aws-mcp-server/internal/config"
+)
+
+// Sources of ownership, in the order they are tried by default
+const (
+	SourceTags = "tags"
+	SourceFile = "file"
+	SourceAPI  = "api"
+)
+
+const (
+	// defaultCacheTTL is how long answers are reused when ownership.cache_ttl is not set
+	defaultCacheTTL = 5 * time.Minute
+	// requestTimeout bounds a single ownership API call
+	requestTimeout = 10 * time.Second
+	// maxResponseSize bounds how much of an API response is read
+	maxResponseSize = 1 << 20
+)
+
+// Owner is who owns a resource and how that was decided. Rule is the tag,
+// owners file line or API that named the teams; the first team is primary.
+type Owner struct {
+	Resource string   `json:"resource"`
+	Teams    []string `json:"teams"`
+	Source   string   `json:"source"`
+	Rule     string   `json:"rule"`
+}
+
+// Team returns the primary owning team, or an empty string when unowned
+func (o Owner) Team() string {
+	if len(o.Teams) == 0 {
+		return ""
+	}
+	return o.Teams[0]
+}
+
+// TagLookup returns the tags of a resource, or nil when it has none or tags
+// cannot be read for that kind of resource
+type TagLookup func(ctx context.Context, resourceID string) (map[string]string, error)
+
+// rule is one line of an owners file
+type rule struct {
+	line    int
+	pattern string
+	match   *regexp.Regexp
+	teams   []string
+}
+
+// cached is a resolved owner and when it was resolved
+type cached struct {
+	owner    Owner
+	resolved time.Time
+}
+
+// Resolver maps resources to their owning teams. Sources are tried in order
+// and the first to name a team wins; answers are cached briefly since
+// notifications about the same resource tend to come in bursts.
+type Resolver struct {
+	sources   []string
+	ownerTags []string
+	tags      TagLookup
+	rules     []rule
+	apiURL    string
+	headers   map[string]string
+	client    *http.Client
+	teams     map[string]config.OwnershipTeamConfig
+	ttl       time.Duration
+
+	mu    sync.Mutex
+	cache map[string]cached
+}
+
+// New creates a resolver from the ownership configuration. ownerTags are the
+// tag keys naming a resource's team, checked in order, and tags reads them;
+// without it the tags source is skipped.
+func New(cfg config.OwnershipConfig, ownerTags []string, tags TagLookup) (*Resolver, error) {
+	ttl := cfg.CacheTTL
+	if ttl <= 0 {
+		ttl = defaultCacheTTL
+	}
+
+	r := &Resolver{
+		ownerTags: ownerTags,
+		tags:      tags,
+		apiURL:    cfg.API.URL,
+		headers:   cfg.API.Headers,
+		client:    &http.Client{Timeout: requestTimeout},
+		teams:     make(map[string]config.OwnershipTeamConfig, len(cfg.Teams)),
+		ttl:       ttl,
+		cache:     make(map[string]cached),
+	}
+
+	for _, source := range cfg.Sources {
+		switch source {
+		case SourceTags:
+		case SourceFile:
+			if cfg.File == "" {
+				continue
+			}
+			rules, err := loadRules(cfg.File)
+			if err != nil {
+				return nil, err
+			}
+			r.rules = rules
+		case SourceAPI:
+			if cfg.API.URL == "" {
+				continue
+			}
+			if !strings.Contains(cfg.API.URL, "{resource}") {
+				return nil, fmt.Errorf("ownership.api.url must contain {resource}, e.g. https://catalog.example.com/owners/{resource}")
+			}
+		default:
+			return nil, fmt.Errorf("unknown ownership source %q (use tags, file or api)", source)
+		}
+		r.sources = append(r.sources, source)
+	}
+
+	for _, team := range cfg.Teams {
+		if team.Name == "" {
+			return nil, errors.New("ownership team name is required")
+		}
+		r.teams[normalizeTeam(team.Name)] = team
+	}
+
+	return r, nil
+}
+
+// Sources returns the enabled sources in the order they are tried
+func (r *Resolver) Sources() []string {
+	return r.sources
+}
+
+// Team returns how to reach a team, if it is configured
+func (r *Resolver) Team(name string) (config.OwnershipTeamConfig, bool) {
+	team, ok := r.teams[normalizeTeam(name)]
+	return team, ok
+}
+
+// Resolve returns the owner of a resource. An unowned resource is not an
+// error: the returned owner has no teams. Failing sources are skipped and
+// reported together if no later source names an owner.
+func (r *Resolver) Resolve(ctx context.Context, resourceID string) (Owner, error) {
+	r.mu.Lock()
+	entry, ok := r.cache[resourceID]
+	r.mu.Unlock()
+	if ok && time.Since(entry.resolved) < r.ttl {
+		return entry.owner, nil
+	}
+
+	owner := Owner{Resource: resourceID}
+	var errs []error
+
+	// Tags are read once for both the tags source and Name patterns in the file
+	var tags map[string]string
+	if r.tags != nil {
+		var err error
+		if tags, err = r.tags(ctx, resourceID); err != nil {
+			errs = append(errs, fmt.Errorf("tags: %w", err))
+		}
+	}
+
+	for _, source := range r.sources {
+		var teams []string
+		var ruleName string
+		var err error
+
+		switch source {
+		case SourceTags:
+			teams, ruleName = r.fromTags(tags)
+		case SourceFile:
+			teams, ruleName = r.fromFile(resourceID, tags["Name"])
+		case SourceAPI:
+			teams, ruleName, err = r.fromAPI(ctx, resourceID)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", source, err))
+			continue
+		}
+		if len(teams) > 0 {
+			owner.Teams = teams
+			owner.Source = source
+			owner.Rule = ruleName
+			break
+		}
+	}
+
+	if len(owner.Teams) == 0 && len(errs) > 0 {
+		// Failures are not cached so the next lookup tries again
+		return owner, errors.Join(errs...)
+	}
+
+	r.mu.Lock()
+	r.cache[resourceID] = cached{owner: owner, resolved: time.Now()}
+	r.mu.Unlock()
+	return owner, nil
+}
+
+// Flush drops the cached owners, so the next lookups resolve them again
+func (r *Resolver) Flush() {
+	if r == nil {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cache = make(map[string]cached)
+}
+
+// fromTags returns the team named by the first owner tag present
+func (r *Resolver) fromTags(tags map[string]string) ([]string, string) {
+	for _, key := range r.ownerTags {
+		if value := strings.TrimSpace(tags[key]); value != "" {
+			return []string{normalizeTeam(value)}, "tag " + key
+		}
+	}
+	return nil, ""
+}
+
+// fromFile returns the teams of the last owners file rule matching the
+// resource ID or its Name tag; like CODEOWNERS, later lines take precedence
+func (r *Resolver) fromFile(resourceID, name string) ([]string, string) {
+	for i := len(r.rules) - 1; i >= 0; i-- {
+		rule := r.rules[i]
+		if rule.match.MatchString(resourceID) || (name != "" && rule.match.MatchString(name)) {
+			return rule.teams, fmt.Sprintf("line %d: %s", rule.line, rule.pattern)
+		}
+	}
+	return nil, ""
+}
+
+// apiResponse is what the ownership API returns; either field may be used
+type apiResponse struct {
+	Team  string   `json:"team"`
+	Teams []string `json:"teams"`
+}
+
+// fromAPI asks the external ownership service. A 404 means the service does
+// not know the resource.
+func (r *Resolver) fromAPI(ctx context.Context, resourceID string) ([]string, string, error) {
+	endpoint := strings.ReplaceAll(r.apiURL, "{resource}", url.PathEscape(resourceID))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	for key, value := range r.headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return nil, "", fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, "", nil
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		snippet := bytes.TrimSpace(data)
+		if len(snippet) > 512 {
+			snippet = snippet[:512]
+		}
+		return nil, "", fmt.Errorf("%s: %s", resp.Status, snippet)
+	}
+
+	var parsed apiResponse
+	if err := json.Unmarshal(data, &parsed); err != nil {
+		return nil, "", fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	var teams []string
+	if parsed.Team != "" {
+		teams = append(teams, normalizeTeam(parsed.Team))
+	}
+	for _, team := range parsed.Teams {
+		if team = normalizeTeam(team); team != "" && (len(teams) == 0 || teams[0] != team) {
+			teams = append(teams, team)
+		}
+	}
+	return teams, endpoint, nil
+}
+
+// loadRules reads an owners file. Each line is a pattern followed by one or
+// more teams; # starts a comment. Patterns match resource IDs, ARNs or Name
+// tags, and * and ? are wildcards that also match slashes, e.g.
+//
+//	arn:aws:rds:*:*:db:orders-*   @payments @dba
+//	web-*                          @frontend
+func loadRules(path string) ([]rule, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open owners file: %w", err)
+	}
+	defer file.Close()
+
+	var rules []rule
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		text, _, _ := strings.Cut(scanner.Text(), "#")
+		fields := strings.Fields(text)
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) < 2 {
+			return nil, fmt.Errorf("owners file %s line %d: expected a pattern followed by at least one team", path, line)
+		}
+
+		teams := make([]string, 0, len(fields)-1)
+		for _, team := range fields[1:] {
+			teams = append(teams, normalizeTeam(team))
+		}
+		rules = append(rules, rule{
+			line:    line,
+			pattern: fields[0],
+			match:   compilePattern(fields[0]),
+			teams:   teams,
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read owners file: %w", err)
+	}
+	return rules, nil
+}
+
+// compilePattern turns an owners file pattern into an anchored expression
+func compilePattern(pattern string) *regexp.Regexp {
+	var b strings.Builder
+	b.WriteString("^")
+	for _, r := range pattern {
+		switch r {
+		case '*':
+			b.WriteString(".*")
+		case '?':
+			b.WriteString(".")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	b.WriteString("$")
+	return regexp.MustCompile(b.String())
+}
+
+// normalizeTeam drops the @ prefix CODEOWNERS uses for teams
+func normalizeTeam(team string) string {
+	return strings.TrimPrefix(strings.TrimSpace(team), "@")
+}
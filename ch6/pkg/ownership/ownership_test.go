@@ -0,0 +1,123 @@
+package ownership
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"aws-mcp-server/internal/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const ownersFile = `# Fallback for everything else
+*                              @platform
+
+arn:aws:rds:*:*:db:orders-*    @payments @dba
+web-*                          @frontend   # matched against the Name tag
+`
+
+func writeOwners(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "OWNERS")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	return path
+}
+
+func TestResolveSourcesInOrder(t *testing.T) {
+	tags := map[string]map[string]string{
+		"i-0aaa": {"Team": "@search", "Name": "web-1"},
+		"i-0bbb": {"Name": "web-2"},
+	}
+	lookups := 0
+	lookup := func(ctx context.Context, resourceID string) (map[string]string, error) {
+		lookups++
+		return tags[resourceID], nil
+	}
+
+	r, err := New(config.OwnershipConfig{
+		Sources: []string{SourceTags, SourceFile, SourceAPI},
+		File:    writeOwners(t, ownersFile),
+		Teams:   []config.OwnershipTeamConfig{{Name: "@payments", TicketQueue: "PAY"}},
+	}, []string{"Owner", "Team"}, lookup)
+	require.NoError(t, err)
+	assert.Equal(t, []string{SourceTags, SourceFile}, r.Sources(), "the API is skipped without a URL")
+
+	owner, err := r.Resolve(context.Background(), "i-0aaa")
+	require.NoError(t, err)
+	assert.Equal(t, Owner{Resource: "i-0aaa", Teams: []string{"search"}, Source: SourceTags, Rule: "tag Team"}, owner)
+
+	owner, err = r.Resolve(context.Background(), "i-0bbb")
+	require.NoError(t, err)
+	assert.Equal(t, "frontend", owner.Team())
+	assert.Equal(t, "line 5: web-*", owner.Rule)
+
+	owner, err = r.Resolve(context.Background(), "arn:aws:rds:us-east-1:123456789012:db:orders-primary")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"payments", "dba"}, owner.Teams)
+	team, ok := r.Team(owner.Team())
+	require.True(t, ok)
+	assert.Equal(t, "PAY", team.TicketQueue)
+
+	owner, err = r.Resolve(context.Background(), "vol-0ccc")
+	require.NoError(t, err)
+	assert.Equal(t, "platform", owner.Team(), "the catch-all rule applies last")
+
+	_, err = r.Resolve(context.Background(), "i-0aaa")
+	require.NoError(t, err)
+	assert.Equal(t, 4, lookups, "answers are cached")
+}
+
+func TestResolveFromAPI(t *testing.T) {
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.EscapedPath())
+		assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+		switch r.URL.Path {
+		case "/owners/i-0aaa":
+			w.Write([]byte(`{"team":"payments","teams":["payments","dba"]}`))
+		case "/owners/i-0broken":
+			http.Error(w, "catalog unavailable", http.StatusBadGateway)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	r, err := New(config.OwnershipConfig{
+		Sources: []string{SourceTags, SourceAPI},
+		API:     config.OwnershipAPIConfig{URL: server.URL + "/owners/{resource}", Headers: map[string]string{"Authorization": "Bearer token"}},
+	}, []string{"Owner"}, func(ctx context.Context, resourceID string) (map[string]string, error) {
+		return nil, errors.New("access denied")
+	})
+	require.NoError(t, err)
+
+	owner, err := r.Resolve(context.Background(), "i-0aaa")
+	require.NoError(t, err, "a failing source is skipped when a later one answers")
+	assert.Equal(t, []string{"payments", "dba"}, owner.Teams)
+	assert.Equal(t, SourceAPI, owner.Source)
+
+	owner, err = r.Resolve(context.Background(), "arn:aws:s3:::logs/archive")
+	assert.Error(t, err, "the tag failure is reported when nobody owns the resource")
+	assert.Empty(t, owner.Teams)
+	assert.Contains(t, paths, "/owners/arn:aws:s3:::logs%2Farchive")
+
+	_, err = r.Resolve(context.Background(), "i-0broken")
+	assert.ErrorContains(t, err, "502 Bad Gateway")
+}
+
+func TestNewRejectsInvalidConfig(t *testing.T) {
+	_, err := New(config.OwnershipConfig{Sources: []string{"cmdb"}}, nil, nil)
+	assert.ErrorContains(t, err, "unknown ownership source")
+
+	_, err = New(config.OwnershipConfig{Sources: []string{SourceAPI}, API: config.OwnershipAPIConfig{URL: "https://catalog.example.com/owners"}}, nil, nil)
+	assert.ErrorContains(t, err, "{resource}")
+
+	_, err = New(config.OwnershipConfig{Sources: []string{SourceFile}, File: writeOwners(t, "i-0aaa\n")}, nil, nil)
+	assert.ErrorContains(t, err, "line 1")
+}
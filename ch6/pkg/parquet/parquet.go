@@ -0,0 +1,317 @@
+// Package parquet writes flat tables as Apache Parquet files that Athena,
+// QuickSight and other query engines read. Files have one row group with one
+// plain-encoded, uncompressed page per column, which suits the small
+// periodic exports of the server; nested columns and compression are not
+// supported.
+package parquet
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"time"
+)
+
+// magic starts and ends every Parquet file
+const magic = "PAR1"
+
+// createdBy names the writer in the file metadata
+const createdBy = "aws-mcp-server"
+
+// Type is the type of a column
+type Type int
+
+// Column types. Timestamps are stored as milliseconds since the epoch.
+const (
+	String Type = iota
+	Int64
+	Double
+	Boolean
+	Timestamp
+)
+
+// Physical types, encodings and annotations of the Parquet format
+const (
+	physicalBoolean   = 0
+	physicalInt64     = 2
+	physicalDouble    = 5
+	physicalByteArray = 6
+
+	repetitionRequired = 0
+	repetitionOptional = 1
+
+	convertedUTF8            = 0
+	convertedTimestampMillis = 9
+
+	encodingPlain = 0
+	encodingRLE   = 3
+
+	codecUncompressed = 0
+	pageTypeData      = 0
+)
+
+// Column describes a column. Optional columns take nil values.
+type Column struct {
+	Name     string
+	Type     Type
+	Optional bool
+}
+
+// Writer collects rows and writes them as one Parquet file
+type Writer struct {
+	columns []Column
+	values  [][]interface{}
+	rows    int
+}
+
+// NewWriter creates a writer for a table with the columns
+func NewWriter(columns ...Column) *Writer {
+	return &Writer{columns: columns, values: make([][]interface{}, len(columns))}
+}
+
+// Rows returns how many rows were appended
+func (w *Writer) Rows() int {
+	return w.rows
+}
+
+// Append adds a row with one value per column: a string, an int or int64, a
+// float64, a bool or a time.Time for the column's type, or nil when the
+// column is optional
+func (w *Writer) Append(row ...interface{}) error {
+	if len(row) != len(w.columns) {
+		return fmt.Errorf("row has %d values for %d columns", len(row), len(w.columns))
+	}
+
+	converted := make([]interface{}, len(row))
+	for i, value := range row {
+		column := w.columns[i]
+		if value == nil {
+			if !column.Optional {
+				return fmt.Errorf("column %s is required", column.Name)
+			}
+			continue
+		}
+
+		var ok bool
+		switch column.Type {
+		case String:
+			converted[i], ok = value.(string)
+		case Int64:
+			switch v := value.(type) {
+			case int:
+				converted[i], ok = int64(v), true
+			case int64:
+				converted[i], ok = v, true
+			}
+		case Double:
+			converted[i], ok = value.(float64)
+		case Boolean:
+			converted[i], ok = value.(bool)
+		case Timestamp:
+			var t time.Time
+			if t, ok = value.(time.Time); ok {
+				converted[i] = t.UnixMilli()
+			}
+		}
+		if !ok {
+			return fmt.Errorf("column %s does not take %T values", column.Name, value)
+		}
+	}
+
+	for i, value := range converted {
+		w.values[i] = append(w.values[i], value)
+	}
+	w.rows++
+	return nil
+}
+
+// WriteTo writes the file
+func (w *Writer) WriteTo(out io.Writer) (int64, error) {
+	var file bytes.Buffer
+	file.WriteString(magic)
+
+	chunks := make([]chunk, len(w.columns))
+	for i, column := range w.columns {
+		page := w.page(i)
+		header := pageHeader(w.rows, len(page))
+
+		chunks[i] = chunk{column: column, offset: int64(file.Len()), size: int64(len(header) + len(page))}
+		file.Write(header)
+		file.Write(page)
+	}
+
+	footer := w.fileMetadata(chunks)
+	file.Write(footer)
+	file.Write(binary.LittleEndian.AppendUint32(nil, uint32(len(footer))))
+	file.WriteString(magic)
+
+	n, err := out.Write(file.Bytes())
+	return int64(n), err
+}
+
+// chunk is where a column's page landed in the file
+type chunk struct {
+	column Column
+	offset int64
+	size   int64
+}
+
+// page encodes the values of a column: the definition levels of optional
+// columns, then the values that are set
+func (w *Writer) page(i int) []byte {
+	column := w.columns[i]
+	var page bytes.Buffer
+
+	if column.Optional {
+		levels := definitionLevels(w.values[i])
+		page.Write(binary.LittleEndian.AppendUint32(nil, uint32(len(levels))))
+		page.Write(levels)
+	}
+
+	var bits []bool
+	for _, value := range w.values[i] {
+		switch v := value.(type) {
+		case nil:
+		case string:
+			page.Write(binary.LittleEndian.AppendUint32(nil, uint32(len(v))))
+			page.WriteString(v)
+		case int64:
+			page.Write(binary.LittleEndian.AppendUint64(nil, uint64(v)))
+		case float64:
+			page.Write(binary.LittleEndian.AppendUint64(nil, math.Float64bits(v)))
+		case bool:
+			bits = append(bits, v)
+		}
+	}
+	if column.Type == Boolean {
+		packed := make([]byte, (len(bits)+7)/8)
+		for j, bit := range bits {
+			if bit {
+				packed[j/8] |= 1 << (j % 8)
+			}
+		}
+		page.Write(packed)
+	}
+	return page.Bytes()
+}
+
+// definitionLevels encodes which values are set, 1 for set and 0 for nil, as
+// runs of the RLE/bit-packing hybrid encoding with a bit width of 1
+func definitionLevels(values []interface{}) []byte {
+	var levels []byte
+	for start := 0; start < len(values); {
+		set := values[start] != nil
+		end := start + 1
+		for end < len(values) && (values[end] != nil) == set {
+			end++
+		}
+		levels = binary.AppendUvarint(levels, uint64(end-start)<<1)
+		if set {
+			levels = append(levels, 1)
+		} else {
+			levels = append(levels, 0)
+		}
+		start = end
+	}
+	return levels
+}
+
+// pageHeader encodes the header of a data page
+func pageHeader(rows, size int) []byte {
+	var t thriftWriter
+	t.i32(1, pageTypeData)
+	t.i32(2, int32(size))
+	t.i32(3, int32(size))
+	t.beginStruct(5)
+	t.i32(1, int32(rows))
+	t.i32(2, encodingPlain)
+	t.i32(3, encodingRLE)
+	t.i32(4, encodingRLE)
+	t.endStruct()
+	t.stop()
+	return t.buf.Bytes()
+}
+
+// fileMetadata encodes the footer: the schema and the row group
+func (w *Writer) fileMetadata(chunks []chunk) []byte {
+	var t thriftWriter
+	t.i32(1, 1)
+
+	t.listHeader(2, thriftStruct, len(w.columns)+1)
+	t.beginElement()
+	t.binary(4, "schema")
+	t.i32(5, int32(len(w.columns)))
+	t.endStruct()
+	for _, column := range w.columns {
+		physical, converted := physicalType(column.Type)
+		repetition := int32(repetitionRequired)
+		if column.Optional {
+			repetition = repetitionOptional
+		}
+
+		t.beginElement()
+		t.i32(1, physical)
+		t.i32(3, repetition)
+		t.binary(4, column.Name)
+		if converted >= 0 {
+			t.i32(6, converted)
+		}
+		t.endStruct()
+	}
+
+	t.i64(3, int64(w.rows))
+
+	var total int64
+	for _, c := range chunks {
+		total += c.size
+	}
+	t.listHeader(4, thriftStruct, 1)
+	t.beginElement()
+	t.listHeader(1, thriftStruct, len(chunks))
+	for _, c := range chunks {
+		physical, _ := physicalType(c.column.Type)
+		t.beginElement()
+		t.i64(2, c.offset)
+		t.beginStruct(3)
+		t.i32(1, physical)
+		t.listHeader(2, thriftI32, 2)
+		t.varint(zigzag(encodingPlain))
+		t.varint(zigzag(encodingRLE))
+		t.listHeader(3, thriftBinary, 1)
+		t.varint(uint64(len(c.column.Name)))
+		t.buf.WriteString(c.column.Name)
+		t.i32(4, codecUncompressed)
+		t.i64(5, int64(w.rows))
+		t.i64(6, c.size)
+		t.i64(7, c.size)
+		t.i64(9, c.offset)
+		t.endStruct()
+		t.endStruct()
+	}
+	t.i64(2, total)
+	t.i64(3, int64(w.rows))
+	t.endStruct()
+
+	t.binary(6, createdBy)
+	t.stop()
+	return t.buf.Bytes()
+}
+
+// physicalType returns the Parquet type and annotation of a column type; -1
+// means no annotation
+func physicalType(columnType Type) (int32, int32) {
+	switch columnType {
+	case String:
+		return physicalByteArray, convertedUTF8
+	case Double:
+		return physicalDouble, -1
+	case Boolean:
+		return physicalBoolean, -1
+	case Timestamp:
+		return physicalInt64, convertedTimestampMillis
+	default:
+		return physicalInt64, -1
+	}
+}
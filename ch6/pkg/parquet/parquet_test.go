@@ -0,0 +1,226 @@
+package parquet
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"testing"
+	"time"
+
+	parquetgo "github.com/parquet-go/parquet-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// thriftReader decodes Thrift compact structs into maps keyed by field ID
+type thriftReader struct {
+	data []byte
+	pos  int
+}
+
+func (r *thriftReader) varint() uint64 {
+	value, n := binary.Uvarint(r.data[r.pos:])
+	r.pos += n
+	return value
+}
+
+func (r *thriftReader) signed() int64 {
+	value := r.varint()
+	return int64(value>>1) ^ -int64(value&1)
+}
+
+func (r *thriftReader) readStruct() map[int16]interface{} {
+	fields := make(map[int16]interface{})
+	var last int16
+	for {
+		header := r.data[r.pos]
+		r.pos++
+		if header == 0 {
+			return fields
+		}
+		id := last + int16(header>>4)
+		if header>>4 == 0 {
+			id = int16(r.signed())
+		}
+		last = id
+		fields[id] = r.value(header & 0x0f)
+	}
+}
+
+func (r *thriftReader) value(valueType byte) interface{} {
+	switch valueType {
+	case thriftI32, thriftI64:
+		return r.signed()
+	case thriftBinary:
+		n := int(r.varint())
+		r.pos += n
+		return string(r.data[r.pos-n : r.pos])
+	case thriftList:
+		header := r.data[r.pos]
+		r.pos++
+		n := int(header >> 4)
+		if n == 15 {
+			n = int(r.varint())
+		}
+		list := make([]interface{}, n)
+		for i := range list {
+			list[i] = r.value(header & 0x0f)
+		}
+		return list
+	case thriftStruct:
+		return r.readStruct()
+	default:
+		panic("unexpected thrift type")
+	}
+}
+
+// readFile decodes the footer of a file and the page of every column
+func readFile(t *testing.T, file []byte) (map[int16]interface{}, map[string][]byte) {
+	t.Helper()
+	require.Equal(t, magic, string(file[:4]))
+	require.Equal(t, magic, string(file[len(file)-4:]))
+	footerSize := int(binary.LittleEndian.Uint32(file[len(file)-8:]))
+	footerStart := len(file) - 8 - footerSize
+	reader := &thriftReader{data: file[:len(file)-8], pos: footerStart}
+	metadata := reader.readStruct()
+	require.Equal(t, len(file)-8, reader.pos, "the footer is read to its end")
+
+	pages := make(map[string][]byte)
+	rowGroup := metadata[4].([]interface{})[0].(map[int16]interface{})
+	for _, c := range rowGroup[1].([]interface{}) {
+		meta := c.(map[int16]interface{})[3].(map[int16]interface{})
+		name := meta[3].([]interface{})[0].(string)
+		offset := int(meta[9].(int64))
+
+		pageReader := &thriftReader{data: file, pos: offset}
+		header := pageReader.readStruct()
+		size := int(header[3].(int64))
+		pages[name] = file[pageReader.pos : pageReader.pos+size]
+		assert.Equal(t, meta[7].(int64), int64(pageReader.pos+size-offset), "column %s size", name)
+	}
+	return metadata, pages
+}
+
+func TestWriteFile(t *testing.T) {
+	at := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	w := NewWriter(
+		Column{Name: "tool", Type: String},
+		Column{Name: "time", Type: Timestamp},
+		Column{Name: "count", Type: Int64},
+		Column{Name: "score", Type: Double, Optional: true},
+		Column{Name: "success", Type: Boolean},
+	)
+	require.NoError(t, w.Append("stop-ec2-instance", at, 1, 2.5, true))
+	require.NoError(t, w.Append("tag-resource", at.Add(time.Second), int64(2), nil, false))
+	require.NoError(t, w.Append("start-ec2-instance", at.Add(time.Minute), 3, nil, true))
+	assert.Equal(t, 3, w.Rows())
+
+	var out bytes.Buffer
+	_, err := w.WriteTo(&out)
+	require.NoError(t, err)
+	metadata, pages := readFile(t, out.Bytes())
+
+	assert.Equal(t, int64(3), metadata[3], "rows")
+	assert.Equal(t, createdBy, metadata[6])
+	schema := metadata[2].([]interface{})
+	require.Len(t, schema, 6)
+	assert.Equal(t, int64(5), schema[0].(map[int16]interface{})[5], "the root has all columns")
+	timeColumn := schema[2].(map[int16]interface{})
+	assert.Equal(t, "time", timeColumn[4])
+	assert.Equal(t, int64(physicalInt64), timeColumn[1])
+	assert.Equal(t, int64(convertedTimestampMillis), timeColumn[6])
+	assert.Equal(t, int64(repetitionOptional), schema[4].(map[int16]interface{})[3])
+
+	tools := pages["tool"]
+	assert.Equal(t, uint32(len("stop-ec2-instance")), binary.LittleEndian.Uint32(tools))
+	assert.Equal(t, "stop-ec2-instance", string(tools[4:4+len("stop-ec2-instance")]))
+
+	assert.Equal(t, uint64(at.UnixMilli()), binary.LittleEndian.Uint64(pages["time"]))
+	assert.Equal(t, uint64(2), binary.LittleEndian.Uint64(pages["count"][8:]))
+
+	// One set value, then a run of two nil ones, then the value itself
+	scores := pages["score"]
+	levels := int(binary.LittleEndian.Uint32(scores))
+	assert.Equal(t, []byte{1 << 1, 1, 2 << 1, 0}, scores[4:4+levels])
+	assert.Equal(t, 2.5, math.Float64frombits(binary.LittleEndian.Uint64(scores[4+levels:])))
+
+	assert.Equal(t, []byte{0b101}, pages["success"])
+}
+
+func TestAppendChecksValues(t *testing.T) {
+	w := NewWriter(Column{Name: "id", Type: String}, Column{Name: "size", Type: Int64, Optional: true})
+	assert.ErrorContains(t, w.Append("i-1"), "row has 1 values for 2 columns")
+	assert.ErrorContains(t, w.Append(nil, 1), "column id is required")
+	assert.ErrorContains(t, w.Append("i-1", "big"), "column size does not take string values")
+	require.NoError(t, w.Append("i-1", nil))
+	assert.Equal(t, 1, w.Rows())
+}
+
+// exportedRow is how a Parquet reader maps the columns of TestReadBack
+type exportedRow struct {
+	Tool    string    `parquet:"tool"`
+	Time    time.Time `parquet:"time,timestamp(millisecond)"`
+	Count   int64     `parquet:"count"`
+	Score   *float64  `parquet:"score,optional"`
+	Success bool      `parquet:"success"`
+}
+
+func TestReadBack(t *testing.T) {
+	at := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	w := NewWriter(
+		Column{Name: "tool", Type: String},
+		Column{Name: "time", Type: Timestamp},
+		Column{Name: "count", Type: Int64},
+		Column{Name: "score", Type: Double, Optional: true},
+		Column{Name: "success", Type: Boolean},
+	)
+	// Enough rows for long runs of definition levels and several bytes of
+	// packed booleans
+	var want []exportedRow
+	for i := range 1000 {
+		row := exportedRow{Tool: fmt.Sprintf("tool-%d", i%7), Time: at.Add(time.Duration(i) * time.Second), Count: int64(i), Success: i%3 == 0}
+		var score interface{}
+		if i < 10 || i%200 == 0 {
+			value := float64(i) / 4
+			row.Score, score = &value, value
+		}
+		require.NoError(t, w.Append(row.Tool, row.Time, i, score, row.Success))
+		want = append(want, row)
+	}
+
+	var out bytes.Buffer
+	_, err := w.WriteTo(&out)
+	require.NoError(t, err)
+
+	file, err := parquetgo.OpenFile(bytes.NewReader(out.Bytes()), int64(out.Len()))
+	require.NoError(t, err)
+	assert.Equal(t, int64(1000), file.NumRows())
+	var names []string
+	for _, field := range file.Schema().Fields() {
+		names = append(names, field.Name())
+	}
+	assert.Equal(t, []string{"tool", "time", "count", "score", "success"}, names)
+
+	got, err := parquetgo.Read[exportedRow](bytes.NewReader(out.Bytes()), int64(out.Len()))
+	require.NoError(t, err)
+	require.Len(t, got, len(want))
+	for i := range want {
+		assert.Equal(t, want[i].Tool, got[i].Tool)
+		assert.True(t, want[i].Time.Equal(got[i].Time), "row %d time %s", i, got[i].Time)
+		assert.Equal(t, want[i].Count, got[i].Count)
+		assert.Equal(t, want[i].Score, got[i].Score)
+		assert.Equal(t, want[i].Success, got[i].Success)
+	}
+}
+
+func TestReadBackEmpty(t *testing.T) {
+	w := NewWriter(Column{Name: "id", Type: String}, Column{Name: "size", Type: Int64, Optional: true})
+	var out bytes.Buffer
+	_, err := w.WriteTo(&out)
+	require.NoError(t, err)
+
+	file, err := parquetgo.OpenFile(bytes.NewReader(out.Bytes()), int64(out.Len()))
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), file.NumRows())
+}
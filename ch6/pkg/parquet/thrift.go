@@ -0,0 +1,93 @@
+package parquet
+
+import (
+	"bytes"
+	"encoding/binary"
+)
+
+// Types of the Thrift compact protocol the Parquet metadata is encoded in
+const (
+	thriftI32    = 5
+	thriftI64    = 6
+	thriftBinary = 8
+	thriftList   = 9
+	thriftStruct = 12
+)
+
+// thriftWriter encodes structs with the Thrift compact protocol. Fields must
+// be written in the order of their IDs.
+type thriftWriter struct {
+	buf   bytes.Buffer
+	last  int16
+	outer []int16
+}
+
+// field writes a field header, as a delta from the previous field when it fits
+func (t *thriftWriter) field(id int16, fieldType byte) {
+	if delta := id - t.last; delta > 0 && delta <= 15 {
+		t.buf.WriteByte(byte(delta)<<4 | fieldType)
+	} else {
+		t.buf.WriteByte(fieldType)
+		t.varint(zigzag(int64(id)))
+	}
+	t.last = id
+}
+
+func (t *thriftWriter) i32(id int16, value int32) {
+	t.field(id, thriftI32)
+	t.varint(zigzag(int64(value)))
+}
+
+func (t *thriftWriter) i64(id int16, value int64) {
+	t.field(id, thriftI64)
+	t.varint(zigzag(value))
+}
+
+func (t *thriftWriter) binary(id int16, value string) {
+	t.field(id, thriftBinary)
+	t.varint(uint64(len(value)))
+	t.buf.WriteString(value)
+}
+
+// listHeader starts a list field of n elements, which follow without headers
+func (t *thriftWriter) listHeader(id int16, elementType byte, n int) {
+	t.field(id, thriftList)
+	if n < 15 {
+		t.buf.WriteByte(byte(n)<<4 | elementType)
+		return
+	}
+	t.buf.WriteByte(0xf0 | elementType)
+	t.varint(uint64(n))
+}
+
+// beginStruct starts a struct field; endStruct closes it
+func (t *thriftWriter) beginStruct(id int16) {
+	t.field(id, thriftStruct)
+	t.beginElement()
+}
+
+// beginElement starts a struct in a list; endStruct closes it
+func (t *thriftWriter) beginElement() {
+	t.outer = append(t.outer, t.last)
+	t.last = 0
+}
+
+func (t *thriftWriter) endStruct() {
+	t.stop()
+	t.last = t.outer[len(t.outer)-1]
+	t.outer = t.outer[:len(t.outer)-1]
+}
+
+// stop ends the fields of a struct
+func (t *thriftWriter) stop() {
+	t.buf.WriteByte(0)
+}
+
+func (t *thriftWriter) varint(value uint64) {
+	t.buf.Write(binary.AppendUvarint(nil, value))
+}
+
+// zigzag maps signed integers to unsigned ones, small magnitudes first
+func zigzag(value int64) uint64 {
+	return uint64(value<<1) ^ uint64(value>>63)
+}
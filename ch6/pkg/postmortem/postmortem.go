@@ -0,0 +1,222 @@
+package postmortem
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Timeline sources
+const (
+	SourceAlarm  = "alarm"
+	SourceChange = "change"
+	SourceAction = "action"
+)
+
+// todo marks the parts a human or the AI still has to write
+const todo = "_TODO_"
+
+// Event is one line of the incident timeline
+type Event struct {
+	Time    time.Time `json:"time"`
+	Source  string    `json:"source"`
+	Summary string    `json:"summary"`
+	Detail  string    `json:"detail,omitempty"`
+	Failed  bool      `json:"failed,omitempty"`
+	// State is the new alarm state for alarm events
+	State string `json:"state,omitempty"`
+}
+
+// Draft is the raw material of a postmortem: the incident window, its
+// timeline and the sources that could not be read
+type Draft struct {
+	Title    string
+	Start    time.Time
+	End      time.Time
+	Timeline []Event
+	Gaps     []string
+}
+
+// Sort orders the timeline chronologically, keeping the source order for ties
+func (d *Draft) Sort() {
+	sort.SliceStable(d.Timeline, func(i, j int) bool {
+		return d.Timeline[i].Time.Before(d.Timeline[j].Time)
+	})
+}
+
+// Count returns how many timeline events came from a source
+func (d Draft) Count(source string) int {
+	count := 0
+	for _, event := range d.Timeline {
+		if event.Source == source {
+			count++
+		}
+	}
+	return count
+}
+
+// Markdown renders the draft as a postmortem document. Facts that can be
+// derived from the timeline are filled in; judgement calls are left as TODOs.
+func (d Draft) Markdown(formatTime func(time.Time) string) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "# Postmortem: %s\n\n", d.Title)
+	fmt.Fprintf(&b, "_Draft assembled from alarm history, CloudTrail changes and the AIOps audit log. Sections marked %s need a human._\n\n", todo)
+
+	b.WriteString("## Summary\n\n")
+	fmt.Fprintf(&b, "%s\n\n", todo)
+
+	b.WriteString("## Incident window\n\n")
+	fmt.Fprintf(&b, "- **Start:** %s\n", formatTime(d.Start))
+	fmt.Fprintf(&b, "- **End:** %s\n", formatTime(d.End))
+	fmt.Fprintf(&b, "- **Duration:** %s\n\n", d.End.Sub(d.Start).Round(time.Minute))
+
+	b.WriteString("## Impact\n\n")
+	fmt.Fprintf(&b, "%s\n\n", todo)
+
+	b.WriteString("## Detection\n\n")
+	d.writeDetection(&b, formatTime)
+
+	b.WriteString("## Timeline\n\n")
+	if len(d.Timeline) == 0 {
+		b.WriteString("No alarms, changes or actions were recorded in the window.\n\n")
+	} else {
+		b.WriteString("| Time | Source | Event |\n|---|---|---|\n")
+		for _, event := range d.Timeline {
+			summary := event.Summary
+			if event.Failed {
+				summary += " **(failed)**"
+			}
+			if event.Detail != "" {
+				summary += " — " + event.Detail
+			}
+			fmt.Fprintf(&b, "| %s | %s | %s |\n", formatTime(event.Time), event.Source, cell(summary))
+		}
+		b.WriteString("\n")
+	}
+
+	b.WriteString("## Changes during the window\n\n")
+	d.writeChanges(&b)
+
+	b.WriteString("## Actions taken through the AIOps server\n\n")
+	d.writeActions(&b, formatTime)
+
+	b.WriteString("## Root cause\n\n")
+	fmt.Fprintf(&b, "%s\n\n", todo)
+
+	b.WriteString("## Contributing factors\n\n")
+	fmt.Fprintf(&b, "%s\n\n", todo)
+
+	b.WriteString("## Action items\n\n")
+	b.WriteString("| Action | Owner | Due |\n|---|---|---|\n")
+	fmt.Fprintf(&b, "| %s | %s | %s |\n", todo, todo, todo)
+
+	if len(d.Gaps) > 0 {
+		b.WriteString("\n## Data gaps\n\n")
+		for _, gap := range d.Gaps {
+			fmt.Fprintf(&b, "- %s\n", gap)
+		}
+	}
+
+	return b.String()
+}
+
+// writeDetection names the first alarm that fired and the last recovery
+func (d Draft) writeDetection(b *strings.Builder, formatTime func(time.Time) string) {
+	var first, recovered *Event
+	for i := range d.Timeline {
+		event := &d.Timeline[i]
+		if event.Source != SourceAlarm {
+			continue
+		}
+		if event.State == "ALARM" && first == nil {
+			first = event
+		}
+		if event.State == "OK" {
+			recovered = event
+		}
+	}
+
+	if first == nil {
+		fmt.Fprintf(b, "No CloudWatch alarm fired in the window. How was the incident detected? %s\n\n", todo)
+		return
+	}
+
+	fmt.Fprintf(b, "- **First alarm:** %s at %s (%s after the window start)\n",
+		first.Summary, formatTime(first.Time), first.Time.Sub(d.Start).Round(time.Minute))
+	if recovered != nil && recovered.Time.After(first.Time) {
+		fmt.Fprintf(b, "- **Last recovery:** %s at %s\n", recovered.Summary, formatTime(recovered.Time))
+	}
+	b.WriteString("\n")
+}
+
+// writeChanges counts CloudTrail write calls by API and lists failed calls
+func (d Draft) writeChanges(b *strings.Builder) {
+	counts := make(map[string]int)
+	var failed []string
+	for _, event := range d.Timeline {
+		if event.Source != SourceChange {
+			continue
+		}
+		counts[event.Summary]++
+		if event.Failed {
+			failed = append(failed, event.Summary)
+		}
+	}
+
+	if len(counts) == 0 {
+		b.WriteString("No write API calls were recorded by CloudTrail.\n\n")
+		return
+	}
+
+	names := make([]string, 0, len(counts))
+	for name := range counts {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if counts[names[i]] != counts[names[j]] {
+			return counts[names[i]] > counts[names[j]]
+		}
+		return names[i] < names[j]
+	})
+
+	for _, name := range names {
+		fmt.Fprintf(b, "- %s × %d\n", name, counts[name])
+	}
+	if len(failed) > 0 {
+		fmt.Fprintf(b, "\n%d of these calls failed.\n", len(failed))
+	}
+	fmt.Fprintf(b, "\nWhich of these changes could have caused or worsened the incident? %s\n\n", todo)
+}
+
+// writeActions lists the tool calls recorded in the audit log
+func (d Draft) writeActions(b *strings.Builder, formatTime func(time.Time) string) {
+	found := false
+	for _, event := range d.Timeline {
+		if event.Source != SourceAction {
+			continue
+		}
+		found = true
+		status := "succeeded"
+		if event.Failed {
+			status = "failed"
+		}
+		fmt.Fprintf(b, "- %s: %s (%s)", formatTime(event.Time), event.Summary, status)
+		if event.Detail != "" {
+			fmt.Fprintf(b, " — %s", event.Detail)
+		}
+		b.WriteString("\n")
+	}
+
+	if !found {
+		b.WriteString("No actions were taken through the server in the window.\n")
+	}
+	b.WriteString("\n")
+}
+
+// cell makes text safe for a markdown table cell
+func cell(text string) string {
+	text = strings.ReplaceAll(text, "|", `\|`)
+	return strings.Join(strings.Fields(text), " ")
+}
@@ -0,0 +1,52 @@
+package postmortem
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMarkdown(t *testing.T) {
+	start := time.Date(2025, 6, 1, 9, 0, 0, 0, time.UTC)
+	draft := Draft{
+		Title: "Checkout latency",
+		Start: start,
+		End:   start.Add(2 * time.Hour),
+		Timeline: []Event{
+			{Time: start.Add(40 * time.Minute), Source: SourceAction, Summary: "stop-ec2-instance by operator", Detail: "Instance stop initiated"},
+			{Time: start.Add(5 * time.Minute), Source: SourceChange, Summary: "ec2:ModifyInstanceAttribute", Detail: "by deploy-bot; i-abc"},
+			{Time: start.Add(15 * time.Minute), Source: SourceAlarm, Summary: "api-p99 OK → ALARM", State: "ALARM", Detail: "Threshold | crossed"},
+			{Time: start.Add(6 * time.Minute), Source: SourceChange, Summary: "ec2:ModifyInstanceAttribute", Failed: true},
+			{Time: start.Add(90 * time.Minute), Source: SourceAlarm, Summary: "api-p99 ALARM → OK", State: "OK"},
+		},
+		Gaps: []string{"CloudTrail changes could not be read: throttled"},
+	}
+	draft.Sort()
+
+	assert.Equal(t, SourceChange, draft.Timeline[0].Source)
+	assert.Equal(t, 2, draft.Count(SourceChange))
+
+	markdown := draft.Markdown(func(t time.Time) string { return t.Format("15:04") })
+
+	assert.Contains(t, markdown, "# Postmortem: Checkout latency")
+	assert.Contains(t, markdown, "- **Duration:** 2h0m0s")
+	assert.Contains(t, markdown, "- **First alarm:** api-p99 OK → ALARM at 09:15 (15m0s after the window start)")
+	assert.Contains(t, markdown, "- **Last recovery:** api-p99 ALARM → OK at 10:30")
+	assert.Contains(t, markdown, `| 09:15 | alarm | api-p99 OK → ALARM — Threshold \| crossed |`)
+	assert.Contains(t, markdown, "| 09:06 | change | ec2:ModifyInstanceAttribute **(failed)** |")
+	assert.Contains(t, markdown, "- ec2:ModifyInstanceAttribute × 2")
+	assert.Contains(t, markdown, "1 of these calls failed.")
+	assert.Contains(t, markdown, "- 09:40: stop-ec2-instance by operator (succeeded) — Instance stop initiated")
+	assert.Contains(t, markdown, "## Data gaps\n\n- CloudTrail changes could not be read: throttled")
+}
+
+func TestMarkdownWithoutAlarms(t *testing.T) {
+	start := time.Date(2025, 6, 1, 9, 0, 0, 0, time.UTC)
+	markdown := Draft{Title: "Quiet", Start: start, End: start.Add(time.Hour)}.Markdown(func(t time.Time) string { return t.Format(time.RFC3339) })
+
+	assert.Contains(t, markdown, "No CloudWatch alarm fired in the window")
+	assert.Contains(t, markdown, "No alarms, changes or actions were recorded in the window.")
+	assert.Contains(t, markdown, "No actions were taken through the server in the window.")
+	assert.NotContains(t, markdown, "## Data gaps")
+}
@@ -0,0 +1,144 @@
+package prometheus
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"aws-mcp-server/internal/config"
+	"aws-mcp-server/internal/logging"
+
+	"github.com/sirupsen/logrus"
+)
+
+// maxResponseSize bounds how much of a query response is read
+const maxResponseSize = 32 << 20
+
+// Client runs PromQL queries against the HTTP API of Prometheus or any
+// compatible endpoint such as Thanos Query, Cortex or Mimir
+type Client struct {
+	baseURL  *url.URL
+	headers  map[string]string
+	token    string
+	username string
+	password string
+	http     *http.Client
+	logger   *logging.Logger
+}
+
+// NewClient creates a client for the configured endpoint
+func NewClient(cfg config.PrometheusConfig, logger *logging.Logger) (*Client, error) {
+	if cfg.URL == "" {
+		return nil, errors.New("prometheus url is required")
+	}
+
+	baseURL, err := url.Parse(strings.TrimSuffix(cfg.URL, "/"))
+	if err != nil || baseURL.Scheme == "" || baseURL.Host == "" {
+		return nil, fmt.Errorf("invalid prometheus url %q", cfg.URL)
+	}
+
+	return &Client{
+		baseURL:  baseURL,
+		headers:  cfg.Headers,
+		token:    cfg.BearerToken,
+		username: cfg.Username,
+		password: cfg.Password,
+		http:     &http.Client{Timeout: cfg.Timeout},
+		logger:   logger,
+	}, nil
+}
+
+// Query evaluates an instant query at the given time
+func (c *Client) Query(ctx context.Context, expr string, at time.Time) (*Result, error) {
+	params := url.Values{
+		"query": {expr},
+		"time":  {formatTime(at)},
+	}
+	return c.do(ctx, "/api/v1/query", expr, params)
+}
+
+// QueryRange evaluates a query over a time range at the given resolution
+func (c *Client) QueryRange(ctx context.Context, expr string, start, end time.Time, step time.Duration) (*Result, error) {
+	if step <= 0 {
+		return nil, errors.New("step must be positive")
+	}
+
+	params := url.Values{
+		"query": {expr},
+		"start": {formatTime(start)},
+		"end":   {formatTime(end)},
+		"step":  {strconv.FormatFloat(step.Seconds(), 'f', -1, 64)},
+	}
+	return c.do(ctx, "/api/v1/query_range", expr, params)
+}
+
+// do posts a query to an API endpoint and decodes the result
+func (c *Client) do(ctx context.Context, path, expr string, params url.Values) (*Result, error) {
+	start := time.Now()
+
+	endpoint := c.baseURL.JoinPath(path)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint.String(), strings.NewReader(params.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create prometheus request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	for key, value := range c.headers {
+		req.Header.Set(key, value)
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	} else if c.username != "" {
+		req.SetBasicAuth(c.username, c.password)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query prometheus: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read prometheus response: %w", err)
+	}
+
+	// Prometheus reports query errors as JSON with a 4xx/5xx status
+	var apiResp apiResponse
+	if err := json.Unmarshal(body, &apiResp); err != nil {
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("prometheus returned %s", resp.Status)
+		}
+		return nil, fmt.Errorf("failed to decode prometheus response: %w", err)
+	}
+	if apiResp.Status != "success" {
+		return nil, fmt.Errorf("prometheus query failed (%s): %s", apiResp.ErrorType, apiResp.Error)
+	}
+
+	result, err := apiResp.Data.result()
+	if err != nil {
+		return nil, err
+	}
+	result.Warnings = apiResp.Warnings
+
+	c.logger.WithFields(logrus.Fields{
+		"expr":        expr,
+		"result_type": result.Type,
+		"series":      len(result.Series),
+		"duration":    time.Since(start),
+	}).Debug("Executed PromQL query")
+
+	return result, nil
+}
+
+// formatTime formats a time as Unix seconds with sub-second precision
+func formatTime(t time.Time) string {
+	return strconv.FormatFloat(float64(t.UnixNano())/1e9, 'f', 3, 64)
+}
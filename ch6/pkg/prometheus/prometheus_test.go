@@ -0,0 +1,90 @@
+package prometheus
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"aws-mcp-server/internal/config"
+	"aws-mcp-server/internal/logging"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestClient(t *testing.T, handler http.HandlerFunc) *Client {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	client, err := NewClient(config.PrometheusConfig{URL: server.URL + "/thanos/", BearerToken: "tok"}, logging.NewLogger("error", "text"))
+	require.NoError(t, err)
+	return client
+}
+
+func TestQueryVector(t *testing.T) {
+	var path, query, auth string
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		auth = r.Header.Get("Authorization")
+		r.ParseForm()
+		query = r.PostForm.Get("query")
+		w.Write([]byte(`{"status":"success","warnings":["partial response"],"data":{"resultType":"vector","result":[
+			{"metric":{"job":"api"},"value":[1700000000.5,"42"]},
+			{"metric":{"job":"db"},"value":[1700000000.5,"NaN"]}]}}`))
+	})
+
+	result, err := client.Query(context.Background(), `sum by (job) (up)`, time.Unix(1700000000, 0))
+	require.NoError(t, err)
+
+	assert.Equal(t, "/thanos/api/v1/query", path)
+	assert.Equal(t, "Bearer tok", auth)
+	assert.Equal(t, `sum by (job) (up)`, query)
+	assert.Equal(t, ResultVector, result.Type)
+	assert.Equal(t, []string{"partial response"}, result.Warnings)
+	require.Len(t, result.Series, 2)
+	assert.Equal(t, "api", result.Series[0].Metric["job"])
+	assert.Equal(t, 42.0, result.Series[0].Points[0].Value)
+	assert.Equal(t, time.UnixMilli(1700000000500).UTC(), result.Series[0].Points[0].Time)
+	assert.True(t, math.IsNaN(result.Series[1].Points[0].Value))
+}
+
+func TestQueryRangeMatrix(t *testing.T) {
+	var step string
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		step = r.PostForm.Get("step")
+		w.Write([]byte(`{"status":"success","data":{"resultType":"matrix","result":[
+			{"metric":{"pod":"web-1"},"values":[[1700000000,"1"],[1700000060,"2"],[1700000120,"3"]]}]}}`))
+	})
+
+	end := time.Unix(1700000120, 0)
+	result, err := client.QueryRange(context.Background(), "rate(x[5m])", end.Add(-2*time.Minute), end, time.Minute)
+	require.NoError(t, err)
+
+	assert.Equal(t, "60", step)
+	require.Len(t, result.Series, 1)
+	assert.Len(t, result.Series[0].Points, 3)
+	assert.Equal(t, 3.0, result.Series[0].Points[2].Value)
+}
+
+func TestQueryErrors(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"status":"error","errorType":"bad_data","error":"parse error at char 5"}`))
+	})
+	_, err := client.Query(context.Background(), "sum(", time.Now())
+	assert.ErrorContains(t, err, "bad_data")
+	assert.ErrorContains(t, err, "parse error")
+
+	broken := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "upstream down", http.StatusBadGateway)
+	})
+	_, err = broken.Query(context.Background(), "up", time.Now())
+	assert.ErrorContains(t, err, "502")
+
+	_, err = NewClient(config.PrometheusConfig{URL: "localhost:9090"}, logging.NewLogger("error", "text"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,135 @@
+package prometheus
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+)
+
+// Result types returned by the Prometheus query API
+const (
+	ResultVector = "vector"
+	ResultMatrix = "matrix"
+	ResultScalar = "scalar"
+	ResultString = "string"
+)
+
+// Point is one sample. Value may be NaN or ±Inf, which JSON cannot represent,
+// so callers formatting points for output should check it.
+type Point struct {
+	Time  time.Time
+	Value float64
+}
+
+// Series is one labelled time series. Instant vectors and scalars have a
+// single point; range queries have one point per step.
+type Series struct {
+	Metric map[string]string
+	Points []Point
+}
+
+// Result is a decoded query result
+type Result struct {
+	Type     string
+	Series   []Series
+	Text     string // set for string results
+	Warnings []string
+}
+
+// apiResponse is the envelope of every Prometheus API response
+type apiResponse struct {
+	Status    string   `json:"status"`
+	Data      apiData  `json:"data"`
+	ErrorType string   `json:"errorType"`
+	Error     string   `json:"error"`
+	Warnings  []string `json:"warnings"`
+}
+
+// apiData holds a result whose shape depends on its type
+type apiData struct {
+	ResultType string          `json:"resultType"`
+	Result     json.RawMessage `json:"result"`
+}
+
+// apiSeries is a vector or matrix entry
+type apiSeries struct {
+	Metric map[string]string `json:"metric"`
+	Value  []interface{}     `json:"value"`
+	Values [][]interface{}   `json:"values"`
+}
+
+// result decodes the data into a Result
+func (d apiData) result() (*Result, error) {
+	result := &Result{Type: d.ResultType}
+
+	switch d.ResultType {
+	case ResultVector, ResultMatrix:
+		var entries []apiSeries
+		if err := json.Unmarshal(d.Result, &entries); err != nil {
+			return nil, fmt.Errorf("failed to decode %s result: %w", d.ResultType, err)
+		}
+
+		for _, entry := range entries {
+			series := Series{Metric: entry.Metric}
+			if d.ResultType == ResultVector {
+				entry.Values = [][]interface{}{entry.Value}
+			}
+			for _, raw := range entry.Values {
+				point, err := parsePoint(raw)
+				if err != nil {
+					return nil, err
+				}
+				series.Points = append(series.Points, point)
+			}
+			result.Series = append(result.Series, series)
+		}
+	case ResultScalar:
+		var raw []interface{}
+		if err := json.Unmarshal(d.Result, &raw); err != nil {
+			return nil, fmt.Errorf("failed to decode scalar result: %w", err)
+		}
+		point, err := parsePoint(raw)
+		if err != nil {
+			return nil, err
+		}
+		result.Series = []Series{{Metric: map[string]string{}, Points: []Point{point}}}
+	case ResultString:
+		var raw []interface{}
+		if err := json.Unmarshal(d.Result, &raw); err != nil || len(raw) != 2 {
+			return nil, fmt.Errorf("failed to decode string result")
+		}
+		result.Text, _ = raw[1].(string)
+	default:
+		return nil, fmt.Errorf("unsupported result type %q", d.ResultType)
+	}
+
+	return result, nil
+}
+
+// parsePoint decodes a [<unix seconds>, "<value>"] pair
+func parsePoint(raw []interface{}) (Point, error) {
+	if len(raw) != 2 {
+		return Point{}, fmt.Errorf("malformed sample %v", raw)
+	}
+
+	seconds, ok := raw[0].(float64)
+	if !ok {
+		return Point{}, fmt.Errorf("malformed sample timestamp %v", raw[0])
+	}
+
+	text, ok := raw[1].(string)
+	if !ok {
+		return Point{}, fmt.Errorf("malformed sample value %v", raw[1])
+	}
+	value, err := strconv.ParseFloat(text, 64)
+	if err != nil {
+		return Point{}, fmt.Errorf("malformed sample value %q: %w", text, err)
+	}
+
+	return Point{
+		Time:  time.UnixMilli(int64(math.Round(seconds * 1000))).UTC(),
+		Value: value,
+	}, nil
+}
@@ -0,0 +1,343 @@
+package pseudonym
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+var (
+	// accountID matches 12-digit AWS account IDs, alone or inside ARNs
+	accountID = regexp.MustCompile(`\b\d{12}\b`)
+	// ipv4 matches IPv4 addresses, including those of CIDR blocks
+	ipv4 = regexp.MustCompile(`\b(\d{1,3})\.(\d{1,3})\.(\d{1,3})\.(\d{1,3})\b`)
+	// privateDNS matches the IP address in EC2 private DNS names such as ip-10-0-1-23
+	privateDNS = regexp.MustCompile(`\bip-(\d{1,3})-(\d{1,3})-(\d{1,3})-(\d{1,3})\b`)
+	// resourceID matches the IDs of EC2 and VPC resources
+	resourceID = regexp.MustCompile(`\b(i|vol|snap|ami|sg|subnet|vpc|eni|igw|eigw|nat|rtb|acl|eipalloc|eipassoc|lt|tgw|tgw-attach|vpce|pcx|dopt|cgw|vgw|vpn|fs|fsmap|r)-([0-9a-f]{8,17})\b`)
+	// email matches email addresses, e.g. of on-call responders
+	email = regexp.MustCompile(`\b[A-Za-z0-9._%+-]+@[A-Za-z0-9-]+(\.[A-Za-z0-9-]+)+\b`)
+)
+
+// nameKeys are the JSON keys, in lowercase, whose string values name a
+// resource or person, mapped to the prefix of their pseudonyms
+var nameKeys = map[string]string{
+	"name":                 "name",
+	"username":             "user",
+	"user_name":            "user",
+	"rolename":             "role",
+	"groupname":            "group",
+	"keyname":              "key",
+	"dnsname":              "host",
+	"computername":         "host",
+	"hostname":             "host",
+	"displayname":          "name",
+	"resourcename":         "resource",
+	"alarmname":            "alarm",
+	"vaultname":            "vault",
+	"bucket":               "bucket",
+	"bucketname":           "bucket",
+	"queue":                "queue",
+	"queuename":            "queue",
+	"topic":                "topic",
+	"cluster":              "cluster",
+	"clustername":          "cluster",
+	"dbinstanceid":         "db",
+	"dbinstanceidentifier": "db",
+	"functionname":         "function",
+	"loggroup":             "log-group",
+	"loggroupname":         "log-group",
+	"tablename":            "table",
+}
+
+// Pseudonymizer replaces account IDs, IP addresses, resource IDs, emails and
+// resource names with pseudonyms derived from a secret seed, so the same
+// identifier always gets the same pseudonym. It remembers what it replaced so
+// pseudonyms passed back, e.g. as tool arguments, can be revealed again. A
+// nil Pseudonymizer leaves everything as is.
+type Pseudonymizer struct {
+	key []byte
+
+	mu        sync.Mutex
+	originals map[string]string
+	names     map[string]string
+}
+
+// New creates a pseudonymizer for the seed. With an empty seed a random one
+// is used, so pseudonyms are stable within a run but differ between runs.
+func New(seed string) (*Pseudonymizer, error) {
+	key := []byte(seed)
+	if seed == "" {
+		key = make([]byte, 32)
+		if _, err := rand.Read(key); err != nil {
+			return nil, fmt.Errorf("failed to generate pseudonym seed: %w", err)
+		}
+	}
+
+	return &Pseudonymizer{
+		key:       key,
+		originals: make(map[string]string),
+		names:     make(map[string]string),
+	}, nil
+}
+
+// JSON pseudonymizes a JSON document without changing its layout. Values of
+// name keys are replaced wherever they appear in the document; other text is
+// pseudonymized as with Text. Text that is not JSON is handled by Text alone.
+func (p *Pseudonymizer) JSON(text string) string {
+	p.Collect(text)
+	return p.Text(text)
+}
+
+// Collect gives the values of name keys in a JSON document pseudonyms, so Text
+// replaces them from then on, e.g. when a result has several documents
+func (p *Pseudonymizer) Collect(text string) {
+	if p == nil {
+		return
+	}
+
+	var value interface{}
+	if err := json.Unmarshal([]byte(text), &value); err == nil {
+		p.collectNames("", value)
+	}
+}
+
+// Text replaces the identifiers in free text, including the names collected
+// from earlier JSON documents
+func (p *Pseudonymizer) Text(text string) string {
+	if p == nil || text == "" {
+		return text
+	}
+
+	text = p.replaceNames(text)
+	text = accountID.ReplaceAllStringFunc(text, func(id string) string {
+		return p.remember(id, fmt.Sprintf("%012d", p.number("account", id)%1_000_000_000_000))
+	})
+	text = privateDNS.ReplaceAllStringFunc(text, func(name string) string {
+		address := strings.ReplaceAll(strings.TrimPrefix(name, "ip-"), "-", ".")
+		return p.remember(name, "ip-"+strings.ReplaceAll(p.address(address), ".", "-"))
+	})
+	text = ipv4.ReplaceAllStringFunc(text, p.address)
+	text = resourceID.ReplaceAllStringFunc(text, func(id string) string {
+		prefix, suffix, _ := strings.Cut(id, "-")
+		if strings.HasPrefix(id, "tgw-attach-") {
+			prefix, suffix = "tgw-attach", strings.TrimPrefix(id, "tgw-attach-")
+		}
+		return p.remember(id, prefix+"-"+p.hex("resource", id, len(suffix)))
+	})
+	text = email.ReplaceAllStringFunc(text, func(address string) string {
+		return p.remember(address, "user-"+p.hex("email", strings.ToLower(address), 6)+"@example.com")
+	})
+	return text
+}
+
+// Reveal replaces the pseudonyms handed out so far with the identifiers they
+// stand for
+func (p *Pseudonymizer) Reveal(text string) string {
+	if p == nil || text == "" {
+		return text
+	}
+
+	p.mu.Lock()
+	pairs := make([]string, 0, 2*len(p.originals))
+	for pseudonym, original := range p.originals {
+		pairs = append(pairs, pseudonym, original)
+	}
+	p.mu.Unlock()
+	if len(pairs) == 0 {
+		return text
+	}
+	return newReplacer(pairs).Replace(text)
+}
+
+// RevealArguments reveals the pseudonyms in tool arguments, including those
+// nested in lists and objects
+func (p *Pseudonymizer) RevealArguments(arguments map[string]interface{}) map[string]interface{} {
+	if p == nil {
+		return arguments
+	}
+	revealed, _ := p.revealValue(arguments).(map[string]interface{})
+	return revealed
+}
+
+// revealValue reveals the pseudonyms in a decoded JSON value
+func (p *Pseudonymizer) revealValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case string:
+		return p.Reveal(v)
+	case []interface{}:
+		revealed := make([]interface{}, len(v))
+		for i, item := range v {
+			revealed[i] = p.revealValue(item)
+		}
+		return revealed
+	case map[string]interface{}:
+		revealed := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			revealed[key] = p.revealValue(item)
+		}
+		return revealed
+	default:
+		return value
+	}
+}
+
+// collectNames gives every string under a name key a pseudonym. Tags listed
+// as Key/Value pairs count as name keys through their Key.
+func (p *Pseudonymizer) collectNames(key string, value interface{}) {
+	switch v := value.(type) {
+	case string:
+		if prefix, ok := nameKeys[strings.ToLower(key)]; ok {
+			p.name(prefix, v)
+		}
+	case []interface{}:
+		for _, item := range v {
+			p.collectNames(key, item)
+		}
+	case map[string]interface{}:
+		if tagKey, ok := v["Key"].(string); ok {
+			if tagValue, ok := v["Value"].(string); ok {
+				p.collectNames(tagKey, tagValue)
+			}
+		}
+		for childKey, item := range v {
+			p.collectNames(childKey, item)
+		}
+	}
+}
+
+// name gives a resource or person name a pseudonym
+func (p *Pseudonymizer) name(prefix, value string) {
+	if strings.TrimSpace(value) == "" {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if _, known := p.names[value]; known {
+		return
+	}
+	if _, isPseudonym := p.originals[value]; isPseudonym {
+		return
+	}
+
+	pseudonym := prefix + "-" + p.hex("name", value, 6)
+	p.names[value] = pseudonym
+	p.originals[pseudonym] = value
+}
+
+// replaceNames replaces the known names. Quoted JSON strings holding a name
+// are always replaced; in free text only names that look like identifiers
+// are, so a resource called "default" does not replace the word everywhere.
+func (p *Pseudonymizer) replaceNames(text string) string {
+	p.mu.Lock()
+	pairs := make([]string, 0, 4*len(p.names))
+	for name, pseudonym := range p.names {
+		quoted, err := json.Marshal(name)
+		if err != nil {
+			continue
+		}
+		pairs = append(pairs, string(quoted), `"`+pseudonym+`"`)
+		if looksLikeIdentifier(name) {
+			pairs = append(pairs, name, pseudonym)
+		}
+	}
+	p.mu.Unlock()
+	if len(pairs) == 0 {
+		return text
+	}
+	return newReplacer(pairs).Replace(text)
+}
+
+// address pseudonymizes an IPv4 address. Private addresses stay in 10.0.0.0/8
+// and public ones move to 198.18.0.0/15, which is reserved for testing, so
+// readers can still tell them apart. Well-known addresses are kept.
+func (p *Pseudonymizer) address(address string) string {
+	octets := strings.Split(address, ".")
+	values := make([]int, 4)
+	for i, octet := range octets {
+		value, err := strconv.Atoi(octet)
+		if err != nil || value > 255 {
+			return address
+		}
+		values[i] = value
+	}
+
+	switch {
+	case address == "0.0.0.0", address == "255.255.255.255", values[0] == 127, values[0] == 169 && values[1] == 254:
+		return address
+	case values[0] == 10, values[0] == 172 && values[1]&0xf0 == 16, values[0] == 192 && values[1] == 168:
+		n := p.number("ip", address)
+		return p.remember(address, fmt.Sprintf("10.%d.%d.%d", n>>16&0xff, n>>8&0xff, n&0xff))
+	default:
+		n := p.number("ip", address)
+		return p.remember(address, fmt.Sprintf("198.%d.%d.%d", 18+n>>16&1, n>>8&0xff, n&0xff))
+	}
+}
+
+// remember records which identifier a pseudonym stands for and returns the pseudonym
+func (p *Pseudonymizer) remember(original, pseudonym string) string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.originals[pseudonym] = original
+	return pseudonym
+}
+
+// hex returns length hex digits derived from an identifier
+func (p *Pseudonymizer) hex(kind, value string, length int) string {
+	sum := p.sum(kind, value)
+	digits := hex.EncodeToString(sum)
+	for len(digits) < length {
+		digits += digits
+	}
+	return digits[:length]
+}
+
+// number returns a number derived from an identifier
+func (p *Pseudonymizer) number(kind, value string) uint64 {
+	return binary.BigEndian.Uint64(p.sum(kind, value))
+}
+
+// sum is the keyed hash pseudonyms are derived from
+func (p *Pseudonymizer) sum(kind, value string) []byte {
+	mac := hmac.New(sha256.New, p.key)
+	mac.Write([]byte(kind + ":" + value))
+	return mac.Sum(nil)
+}
+
+// looksLikeIdentifier reports whether a name is unlikely to be an ordinary
+// word, e.g. web-prod-01 or orders_db
+func looksLikeIdentifier(name string) bool {
+	return len(name) >= 6 && strings.ContainsAny(name, "-_.0123456789")
+}
+
+// newReplacer replaces the longest matches first, so a name is not partly
+// replaced by a shorter name it contains
+func newReplacer(pairs []string) *strings.Replacer {
+	type pair struct{ old, new string }
+	sorted := make([]pair, 0, len(pairs)/2)
+	for i := 0; i+1 < len(pairs); i += 2 {
+		sorted = append(sorted, pair{pairs[i], pairs[i+1]})
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if len(sorted[i].old) != len(sorted[j].old) {
+			return len(sorted[i].old) > len(sorted[j].old)
+		}
+		return sorted[i].old < sorted[j].old
+	})
+
+	args := make([]string, 0, len(pairs))
+	for _, pair := range sorted {
+		args = append(args, pair.old, pair.new)
+	}
+	return strings.NewReplacer(args...)
+}
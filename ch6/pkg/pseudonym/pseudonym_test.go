@@ -0,0 +1,66 @@
+package pseudonym
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPseudonymizerIsDeterministic(t *testing.T) {
+	first, err := New("book-exercises")
+	require.NoError(t, err)
+	second, err := New("book-exercises")
+	require.NoError(t, err)
+	other, err := New("another-seed")
+	require.NoError(t, err)
+
+	text := "arn:aws:iam::123456789012:role/admin on i-0abc1234def567890 at 54.12.9.1"
+	assert.Equal(t, first.Text(text), second.Text(text), "the same seed gives the same pseudonyms")
+	assert.NotEqual(t, first.Text(text), other.Text(text))
+}
+
+func TestPseudonymizerReplacesIdentifiers(t *testing.T) {
+	p, err := New("book-exercises")
+	require.NoError(t, err)
+
+	document := `{
+  "instanceId": "i-0abc1234def567890",
+  "name": "orders-api-01",
+  "privateIp": "10.0.1.23",
+  "privateDns": "ip-10-0-1-23.ec2.internal",
+  "publicIp": "54.12.9.1",
+  "cidr": "0.0.0.0/0",
+  "arn": "arn:aws:ec2:us-east-1:123456789012:instance/i-0abc1234def567890",
+  "tags": [{"Key": "Name", "Value": "payments"}],
+  "oncall": "dana@example.org",
+  "message": "Stopped orders-api-01"
+}`
+	out := p.JSON(document)
+
+	for _, identifier := range []string{"i-0abc1234def567890", "orders-api-01", "10.0.1.23", "ip-10-0-1-23", "54.12.9.1", "123456789012", `"payments"`, "dana@example.org"} {
+		assert.NotContains(t, out, identifier)
+	}
+	assert.Contains(t, out, `"cidr": "0.0.0.0/0"`, "well-known addresses are kept")
+	assert.Contains(t, out, `"publicIp": "198.1`)
+	assert.Contains(t, out, `"privateIp": "10.`)
+	assert.Regexp(t, `"instanceId": "i-[0-9a-f]{17}"`, out)
+	assert.Equal(t, strings.Count(document, "\n"), strings.Count(out, "\n"), "the layout is kept")
+
+	assert.Equal(t, document, p.Reveal(out), "pseudonyms can be revealed")
+	revealed := p.RevealArguments(map[string]interface{}{"instanceIds": []interface{}{p.Text("i-0abc1234def567890")}})
+	assert.Equal(t, []interface{}{"i-0abc1234def567890"}, revealed["instanceIds"])
+}
+
+func TestPseudonymizerLeavesWordsAlone(t *testing.T) {
+	p, err := New("book-exercises")
+	require.NoError(t, err)
+
+	out := p.JSON(`{"groupName": "default", "note": "uses the default VPC"}`)
+	assert.NotContains(t, out, `"default"`)
+	assert.Contains(t, out, "uses the default VPC", "names that are ordinary words are only replaced as whole values")
+
+	var none *Pseudonymizer
+	assert.Equal(t, "i-0abc1234def567890", none.Text("i-0abc1234def567890"))
+}
@@ -0,0 +1,123 @@
+package render
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+// labelsTemplate is shared by all summaries and renders " (name, environment)"
+// when the payload carries those fields
+const labelsTemplate = `{{define "labels"}}{{if .name}} ({{.name}}{{with .environment}}, {{.}}{{end}}){{end}}{{end}}`
+
+// Renderer turns tool and resource payloads into one-line human-readable summaries
+type Renderer struct {
+	templates map[string]*template.Template
+}
+
+// New parses the built-in summary templates and applies overrides keyed by tool
+// name or resource URI. An empty override disables the summary for that key.
+// Invalid overrides are skipped and reported in the returned error, while the
+// renderer stays usable with the remaining templates.
+func New(overrides map[string]string) (*Renderer, error) {
+	sources := make(map[string]string, len(defaultTemplates)+len(overrides))
+	for name, text := range defaultTemplates {
+		sources[strings.ToLower(name)] = text
+	}
+	// Keys are matched case-insensitively because viper lowercases map keys
+	for name, text := range overrides {
+		sources[strings.ToLower(name)] = text
+	}
+
+	r := &Renderer{templates: make(map[string]*template.Template, len(sources))}
+
+	var errs []error
+	for name, text := range sources {
+		if strings.TrimSpace(text) == "" {
+			continue
+		}
+
+		tmpl, err := template.New(name).Funcs(funcs).Parse(labelsTemplate + text)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("invalid summary template %q: %w", name, err))
+			continue
+		}
+		r.templates[name] = tmpl
+	}
+
+	return r, errors.Join(errs...)
+}
+
+// Render executes the template registered for name against data. It returns false
+// when there is no template or the payload does not have the fields it needs.
+func (r *Renderer) Render(name string, data map[string]interface{}) (string, bool) {
+	if r == nil {
+		return "", false
+	}
+
+	tmpl, exists := r.templates[strings.ToLower(name)]
+	if !exists {
+		return "", false
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", false
+	}
+
+	summary := strings.Join(strings.Fields(buf.String()), " ")
+	if summary == "" || strings.Contains(summary, "<no value>") {
+		return "", false
+	}
+
+	return summary, true
+}
+
+// RenderJSON renders a summary for a JSON object payload, so templates see exactly
+// the fields the client receives
+func (r *Renderer) RenderJSON(name string, payload []byte, extra map[string]interface{}) (string, bool) {
+	if r == nil {
+		return "", false
+	}
+
+	var data map[string]interface{}
+	if err := json.Unmarshal(payload, &data); err != nil {
+		return "", false
+	}
+
+	for key, value := range extra {
+		if _, exists := data[key]; !exists {
+			data[key] = value
+		}
+	}
+
+	return r.Render(name, data)
+}
+
+// funcs are the helpers available to summary templates
+var funcs = template.FuncMap{
+	// count returns the length of a list or map payload field
+	"count": func(value interface{}) int {
+		switch v := value.(type) {
+		case []interface{}:
+			return len(v)
+		case map[string]interface{}:
+			return len(v)
+		default:
+			return 0
+		}
+	},
+	// plural picks the singular or plural word for a count
+	"plural": func(count interface{}, singular, plural string) string {
+		if n, ok := count.(float64); ok && n == 1 {
+			return singular
+		}
+		if n, ok := count.(int); ok && n == 1 {
+			return singular
+		}
+		return plural
+	},
+}
@@ -0,0 +1,93 @@
+package render
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderDefaultTemplates(t *testing.T) {
+	r, err := New(nil)
+	require.NoError(t, err)
+
+	summary, ok := r.RenderJSON("stop-ec2-instance",
+		[]byte(`{"success":true,"instanceId":"i-abc","name":"web-01","environment":"prod"}`),
+		map[string]interface{}{"region": "eu-west-1"})
+	require.True(t, ok)
+	assert.Equal(t, "Stopped i-abc (web-01, prod) in eu-west-1", summary)
+
+	summary, ok = r.RenderJSON("start-ec2-instance", []byte(`{"success":true,"instanceId":"i-abc"}`), nil)
+	require.True(t, ok)
+	assert.Equal(t, "Started i-abc", summary)
+
+	summary, ok = r.RenderJSON("aws://ec2/instances",
+		[]byte(`{"total_instances":1,"summary_by_state":{"running":1}}`),
+		map[string]interface{}{"region": "us-west-2"})
+	require.True(t, ok)
+	assert.Equal(t, "1 EC2 instance in us-west-2: 1 running", summary)
+
+	summary, ok = r.RenderJSON("aws://cloudwatch/alarms",
+		[]byte(`{"total_alarms":3,"summary_by_state":{"ALARM":1,"INSUFFICIENT_DATA":0,"OK":2}}`), nil)
+	require.True(t, ok)
+	assert.Equal(t, "3 CloudWatch alarms: 1 in ALARM, 0 INSUFFICIENT_DATA, 2 OK", summary)
+
+	summary, ok = r.RenderJSON("aws://ecs/clusters/{cluster}/services",
+		[]byte(`{"cluster":"prod","total_services":3,"unhealthy_services":2,"unhealthy":["api","worker"]}`), nil)
+	require.True(t, ok)
+	assert.Equal(t, "3 services in prod, 2 with issues: api, worker", summary)
+}
+
+func TestRenderOverrides(t *testing.T) {
+	r, err := New(map[string]string{
+		"stop-ec2-instance":      "{{.instanceId}} is going down",
+		"start-ec2-instance":     "",
+		"terminate-ec2-instance": "{{.instanceId",
+	})
+	assert.Error(t, err)
+
+	summary, ok := r.RenderJSON("STOP-EC2-INSTANCE", []byte(`{"instanceId":"i-abc"}`), nil)
+	require.True(t, ok)
+	assert.Equal(t, "i-abc is going down", summary)
+
+	_, ok = r.RenderJSON("start-ec2-instance", []byte(`{"instanceId":"i-abc"}`), nil)
+	assert.False(t, ok)
+
+	_, ok = r.RenderJSON("terminate-ec2-instance", []byte(`{"instanceId":"i-abc"}`), nil)
+	assert.False(t, ok)
+}
+
+func TestRenderMissingFields(t *testing.T) {
+	r, err := New(nil)
+	require.NoError(t, err)
+
+	_, ok := r.RenderJSON("deactivate-access-key", []byte(`{"success":true}`), nil)
+	assert.False(t, ok)
+
+	_, ok = r.RenderJSON("unknown-tool", []byte(`{"success":true}`), nil)
+	assert.False(t, ok)
+}
+
+func TestSince(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, "just now", Since(now.Add(-30*time.Second), now))
+	assert.Equal(t, "1 minute ago", Since(now.Add(-90*time.Second), now))
+	assert.Equal(t, "5 hours ago", Since(now.Add(-5*time.Hour), now))
+	assert.Equal(t, "3 days ago", Since(now.AddDate(0, 0, -3), now))
+	assert.Equal(t, "4 months ago", Since(now.AddDate(0, 0, -125), now))
+	assert.Equal(t, "2 years ago", Since(now.AddDate(-2, 0, -1), now))
+	assert.Equal(t, "in 2 hours", Since(now.Add(2*time.Hour), now))
+}
+
+func TestTimeFormatter(t *testing.T) {
+	launched := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	f := NewTimeFormatter("Asia/Ho_Chi_Minh", true)
+	assert.Equal(t, "2025-06-01T19:00:00+07:00", f.Format(launched))
+
+	f = NewTimeFormatter("", false)
+	assert.Equal(t, "2025-06-01T12:00:00Z", f.Format(launched))
+	assert.Empty(t, f.Relative(launched))
+}
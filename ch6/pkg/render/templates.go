@@ -0,0 +1,264 @@
+package render
+
+// defaultTemplates are the built-in summaries, keyed by tool name or resource URI.
+// Templates receive the JSON payload as a map plus the configured region.
+var defaultTemplates = map[string]string{
+	// Tools
+	"create-ec2-instance":    `Created {{.instanceId}}{{template "labels" .}} as {{with .market}}{{.}} {{end}}{{.instanceType}}{{with .region}} in {{.}}{{end}}`,
+	"start-ec2-instance":     `Started {{.instanceId}}{{template "labels" .}}{{with .region}} in {{.}}{{end}}`,
+	"stop-ec2-instance":      `Stopped {{.instanceId}}{{template "labels" .}}{{with .region}} in {{.}}{{end}}`,
+	"terminate-ec2-instance": `Terminated {{.instanceId}}{{template "labels" .}}{{with .region}} in {{.}}{{end}}`,
+	"bulk-stop-ec2-instances": `{{if eq .action "terminate"}}Terminated{{else}}Stopped{{end}} {{.completed}} of {{.requested}} instances in {{len .waves}} {{plural (len .waves) "wave" "waves"}}
+		{{- with .aborted}}; aborted: {{.}}{{end}}`,
+	"verify-remediation":   `{{.summary}} ({{.verdict}} after {{.sample_count}} {{plural .sample_count "check" "checks"}})`,
+	"start-gcp-instance":   `Started Compute Engine instance {{.instanceId}}{{template "labels" .}}`,
+	"stop-gcp-instance":    `Stopped Compute Engine instance {{.instanceId}}{{template "labels" .}}`,
+	"start-azure-vm":       `Started Azure VM {{.instanceId}}{{template "labels" .}}`,
+	"stop-azure-vm":        `Stopped and deallocated Azure VM {{.instanceId}}{{template "labels" .}}`,
+	"publish-sns-message":  `Published message {{.messageId}} to SNS topic {{.topic}} with {{.subscribers}} confirmed {{plural .subscribers "subscriber" "subscribers"}}`,
+	"purge-sqs-queue":      `Purged {{.purged}} {{plural .purged "message" "messages"}} from SQS queue {{.queue}}`,
+	"redrive-sqs-dlq":      `Redriving {{.messages}} {{plural .messages "message" "messages"}} from {{.queue}} to {{.destination}}{{with .maxMessagesPerSecond}} at up to {{.}} per second{{end}}`,
+	"update-ecs-service":   `Scaled ECS service {{.service}} in {{.cluster}} from {{.previousDesiredCount}} to {{.desiredCount}} {{plural .desiredCount "task" "tasks"}}`,
+	"force-ecs-deployment": `Started a new deployment of ECS service {{.service}} in {{.cluster}}{{with .taskDefinition}} with {{.}}{{end}}`,
+	"start-rds-instance":   `Started RDS instance {{.dbInstanceId}}{{with .region}} in {{.}}{{end}}`,
+	"stop-rds-instance":    `Stopped RDS instance {{.dbInstanceId}}{{with .snapshotId}} after snapshot {{.}}{{end}}{{with .region}} in {{.}}{{end}}`,
+	"reboot-cache-node":    `Rebooting {{len .nodeIds}} {{plural (len .nodeIds) "node" "nodes"}} of ElastiCache cluster {{.clusterId}}`,
+	"reboot-rds-instance":  `Rebooted RDS instance {{.dbInstanceId}}{{if .forceFailover}} with failover{{end}}{{with .region}} in {{.}}{{end}}`,
+	"create-rds-snapshot":  `Creating snapshot {{.snapshotId}} of RDS instance {{.dbInstanceId}}`,
+	"audit-tags": `{{.non_compliant_resources}} of {{.total_resources}} resources are missing required tags
+		{{- with .remediated}}; remediated {{count .}}{{end}}`,
+	"tag-resource":   `Tagged {{count .resourceIds}} {{plural (count .resourceIds) "resource" "resources"}} with{{range $key, $value := .tags}} {{$key}}={{$value}}{{end}}`,
+	"untag-resource": `Removed {{range $i, $key := .tagKeys}}{{if $i}}, {{end}}{{$key}}{{end}} from {{count .resourceIds}} {{plural (count .resourceIds) "resource" "resources"}}`,
+	"find-public-exposure": `{{.summary.exposed_instances}} {{plural .summary.exposed_instances "instance" "instances"}} and
+		{{.summary.exposed_load_balancers}} {{plural .summary.exposed_load_balancers "load balancer" "load balancers"}} reachable from the internet`,
+	"find-orphans": `{{.total}} unused {{plural .total "resource" "resources"}} costing about ${{printf "%.2f" .estimated_monthly_usd}} a month
+		{{- with .unavailable}}, {{len .}} {{plural (len .) "kind" "kinds"}} could not be scanned{{end}}`,
+	"aws://ssm/instances": `{{.total}} managed {{plural .total "instance" "instances"}}
+		{{- with .by_patch_status.failed}}, {{.}} with failed patches{{end}}
+		{{- with index .by_patch_status "non-compliant"}}, {{.}} missing patches{{end}}
+		{{- with .offline_agents}}, {{.}} offline{{end}}`,
+	"aws://ssm/instances/{instanceId}/inventory{?name}": `{{.matched}} of {{.total}} {{plural .total "application" "applications"}} on {{.instance_id}}{{with .filter}} matching {{.}}{{end}}`,
+	"aws://ssm/instances/{instanceId}/patches":          `{{.instance_id}} is {{.patch_status}}{{with .detail}}: {{.}}{{end}}`,
+	"aws://kinesis/streams": `{{.total}} Kinesis {{plural .total "stream" "streams"}} with {{.open_shards}} open {{plural .open_shards "shard" "shards"}}
+		{{- with .at_risk}}, at risk: {{range $i, $name := .}}{{if $i}}, {{end}}{{$name}}{{end}}{{end}}`,
+	"aws://kinesis/streams/{name}": `{{.name}} is {{.status}} with {{.open_shards}} {{plural .open_shards "shard" "shards"}}
+		{{- with .issues}}: {{index . 0}}{{end}}`,
+	"aws://apigateway/apis": `{{.total}} {{plural .total "API" "APIs"}}{{with .by_protocol}}:{{range $protocol, $count := .}} {{$count}} {{$protocol}}{{end}}{{end}}
+		{{- with .with_issues}}; needing attention: {{range $i, $name := .}}{{if $i}}, {{end}}{{$name}}{{end}}{{end}}`,
+	"aws://apigateway/apis/{apiId}": `{{.name}} ({{.protocol}}) has {{len .stages}} {{plural (len .stages) "stage" "stages"}}`,
+	"deploy-api-stage":              `Deployed {{.api}} to stage {{.stage}} as deployment {{.deploymentId}}`,
+	"update-api-throttling":         `Throttled stage {{.stage}} of {{.api}} to {{.throttle.rate_limit}} requests per second with bursts of {{.throttle.burst_limit}}`,
+	"aws://servicequotas/quotas": `{{.total}} {{plural .total "quota" "quotas"}} in use, {{.quotas_at_risk}} at 80% or more
+		{{- with .at_risk}}: {{range $i, $name := .}}{{if $i}}, {{end}}{{$name}}{{end}}{{end}}
+		{{- with .open_requests}}; {{len .}} increase {{plural (len .) "request" "requests"}} open{{end}}`,
+	"aws://servicequotas/quotas/{serviceCode}": `{{.total}} {{.service}} {{plural .total "quota" "quotas"}}, {{.quotas_at_risk}} at 80% or more
+		{{- with .at_risk}}: {{range $i, $name := .}}{{if $i}}, {{end}}{{$name}}{{end}}{{end}}`,
+	"request-quota-increase": `Requested raising {{.quotaName}} of {{.service}} from {{.previousValue}} to {{.desiredValue}}, {{.status}}`,
+	"aws://efs/file-systems": `{{.total}} EFS file {{plural .total "system" "systems"}}{{with .summary_by_mode}}:{{range $mode, $count := .}} {{$count}} {{$mode}}{{end}}{{end}}
+		{{- with .constrained}}; constrained: {{range $i, $name := .}}{{if $i}}, {{end}}{{$name}}{{end}}{{end}}`,
+	"aws://efs/file-systems/{fileSystemId}": `{{.name}} is {{.state}} with {{.throughput_mode}} throughput and {{len .mount_targets}} mount {{plural (len .mount_targets) "target" "targets"}}
+		{{- with .constraints}}: {{index . 0}}{{end}}`,
+	"aws://config/rules": `{{.total}} Config {{plural .total "rule" "rules"}}{{with .summary_by_compliance}}:{{range $compliance, $count := .}} {{$count}} {{$compliance}}{{end}}{{end}}
+		{{- with .noncompliant_rules}}; failing: {{range $i, $name := .}}{{if $i}}, {{end}}{{$name}}{{end}}{{end}}`,
+	"aws://config/rules/{ruleName}":       `{{.rule.name}} is {{.rule.compliance}}{{with .noncompliant_resources}} for {{len .}} {{plural (len .) "resource" "resources"}}{{end}}`,
+	"aws://config/noncompliant-resources": `{{.total}} non-compliant {{plural .total "resource" "resources"}}{{with .summary_by_type}}:{{range $type, $count := .}} {{$count}} {{$type}}{{end}}{{end}}`,
+	"aws://backup/jobs": `{{.total}} backup {{plural .total "job" "jobs"}} in the last {{.lookback_hours}}h{{with .summary_by_state}}:{{range $state, $count := .}} {{$count}} {{$state}}{{end}}{{end}}
+		{{- with .failed_resources}}; failed: {{range $i, $name := .}}{{if $i}}, {{end}}{{$name}}{{end}}{{end}}`,
+	"aws://backup/resources/{+resourceArn}": `{{len .recovery_points}} recovery {{plural (len .recovery_points) "point" "points"}} of {{.resource_arn}}
+		{{- with .latest_recovery_point_age_hours}}, the latest {{printf "%.0f" .}}h old{{else}}, none usable{{end}}{{with .running_job}}; backup job {{.id}} {{.state}}{{end}}`,
+	"start-on-demand-backup": `Started backup job {{.backupJobId}} of {{.resourceArn}} into vault {{.backupVaultName}}`,
+	"update-efs-throughput":  `Switched {{.name}} from {{.previousMode}} to {{.throughputMode}} throughput{{with .provisionedThroughputMibps}} at {{.}} MiB/s{{end}}`,
+	"get-windows-password":   `Decrypted the Administrator password of {{.instanceId}} with key pair {{.keyName}}`,
+	"get-instance-console-output": `{{len .lines}} console output {{plural (len .lines) "line" "lines"}} of {{.instanceId}}
+		{{- with .bootProblems}}; boot problem: {{(index . 0).hint}}{{end}}`,
+	"update-shard-count": `Scaling {{.stream}} from {{.previousShards}} to {{.targetShardCount}} shards`,
+	"aws://stepfunctions/state-machines": `{{.total}} state {{plural .total "machine" "machines"}}
+		{{- with .failing}}, last execution failed for {{range $i, $name := .}}{{if $i}}, {{end}}{{$name}}{{end}}{{end}}`,
+	"aws://stepfunctions/state-machines/{name}/executions": `{{.total}} recent {{plural .total "execution" "executions"}} of {{.state_machine}}
+		{{- with .by_status}}:{{range $status, $count := .}} {{$count}} {{$status}}{{end}}{{end}}`,
+	"start-execution":   `Started execution {{.executionArn}} of {{.stateMachine}}`,
+	"redrive-execution": `Redrove {{.name}}{{with .resumesAt}} from {{.}}{{end}}`,
+	"capture-traffic": `{{if eq .platform "windows"}}Captured {{.sizeBytes}} bytes on {{.instanceId}} in {{.durationSeconds}}s{{else}}Captured {{.packets}} {{plural .packets "packet" "packets"}} on {{.instanceId}} in {{.durationSeconds}}s{{if .truncated}} (size limit reached){{end}}
+		{{- with .topFlows}}; busiest flow {{(index . 0).source}} > {{(index . 0).destination}}{{end}}{{end}}`,
+	"run-patch-baseline": `Started patch {{.operation}} of {{len .instanceIds}} {{plural (len .instanceIds) "instance" "instances"}} as command {{.commandId}}`,
+	"aws://ecr/repositories": `{{.total}} ECR {{plural .total "repository" "repositories"}}
+		{{- with .without_scan_on_push}}, {{.}} without scan on push{{end}}`,
+	"aws://ecr/repositories/{+name}": `{{.total}} {{plural .total "image" "images"}} in {{.repository}}
+		{{- with .with_critical_or_high}}, {{.}} with critical or high findings{{end}}`,
+	"aws://ecr/repositories/{+name}/images/{reference}": `{{.total}} {{plural .total "finding" "findings"}} in {{.repository}}:{{.reference}}
+		{{- with .by_severity}}{{with .CRITICAL}}, {{.}} critical{{end}}{{with .HIGH}}, {{.}} high{{end}}{{end}}
+		{{- with .fixable}}, {{.}} fixable{{end}}`,
+	"rotate-secret": `Started rotation of {{.secretId}} to version {{.versionId}}`,
+	"aws://secretsmanager/secrets": `{{.total}} {{plural .total "secret" "secrets"}}
+		{{- with .by_status.failing}}, {{.}} failing rotation{{end}}
+		{{- with .by_status.overdue}}, {{.}} overdue{{end}}
+		{{- with .by_status.stale}}, {{.}} stale{{end}}`,
+	"aws://secretsmanager/secrets/{+name}": `{{.name}}: rotation {{.rotation_status}}{{with .last_rotated}}, last rotated {{.}}{{end}}`,
+	"put-parameter":                        `{{if eq .action "unchanged"}}{{.name}} already has this value{{else}}{{if eq .action "created"}}Created{{else}}Updated{{end}} {{.name}} as version {{.version}}{{end}}`,
+	"aws://ssm/parameters": `{{.total}} {{plural .total "parameter" "parameters"}} under {{.path}}
+		{{- with .by_type.SecureString}}, {{.}} SecureString{{end}}`,
+	"aws://ssm/parameters/{+name}":     `{{.name}} ({{.type}}) version {{.version}}, modified {{.last_modified}}`,
+	"diagnose-connectivity":            `{{.protocol}}/{{.port}} on {{.instanceId}} is {{if .reachable}}reachable from {{.source}}{{else}}blocked at {{.blocked_at}}: {{.reason}}{{end}}`,
+	"encrypt-volume":                   `Replaced {{.originalVolumeId}} with encrypted volume {{.newVolumeId}}{{with .instanceId}} on {{.}}{{end}}`,
+	"authorize-security-group-ingress": `Allowed {{.ports}} from {{.source}} on {{.groupId}}`,
+	"revoke-security-group-ingress":    `Removed {{.ports}} from {{.source}} on {{.groupId}}`,
+	"create-ebs-snapshot":              `Creating snapshot {{.snapshotId}} of EBS volume {{.volumeId}}`,
+	"delete-ebs-snapshot":              `Deleted EBS snapshot {{.snapshotId}}`,
+	"modify-ebs-volume": `Modifying {{.volumeId}} to {{.targetSizeGiB}} GiB {{.targetVolumeType}}
+		{{- if ne .originalSizeGiB .targetSizeGiB}} (from {{.originalSizeGiB}} GiB){{end}}`,
+	"deactivate-access-key": `Deactivated access key {{.accessKeyId}} of {{.userName}}`,
+	"get-cloudwatch-metrics": `{{.datapoint_count}} {{plural .datapoint_count "datapoint" "datapoints"}} of {{.namespace}}/{{.metric_name}} {{.statistic}}
+		{{- if .datapoint_count}}, last {{printf "%g" .last}} (min {{printf "%g" .min}}, max {{printf "%g" .max}}){{end}}
+		{{- with .baseline}}{{if .trained}}, {{.anomaly_count}} {{plural .anomaly_count "anomaly" "anomalies"}}{{else}}, baseline learning{{end}}{{end}}`,
+	"get-metric-baseline":   `{{.count}} learned {{plural .count "baseline" "baselines"}} for {{.resource}}`,
+	"reset-metric-baseline": `Reset {{.count}} {{plural .count "baseline" "baselines"}} for {{.resource}}`,
+	"aws://digest/daily": `Daily digest with {{.counts.alarms_firing}} {{plural .counts.alarms_firing "alarm" "alarms"}} firing, {{.counts.alarm_changes}} alarm
+		{{- plural .counts.alarm_changes " change" " changes"}} and {{.counts.pending_approvals}} pending {{plural .counts.pending_approvals "approval" "approvals"}}{{if .cached}} (cached){{end}}`,
+	"aws://cost/daily": `${{printf "%.2f" .total_usd}} over {{len .periods}} {{plural (len .periods) "period" "periods"}}
+		{{- with .trend}}{{with .change_percent}}, {{printf "%+.1f" .}}% between the halves of the window{{end}}{{end}}
+		{{- with .groups}}, led by {{(index . 0).key}}{{end}}`,
+	"aws://cost/by-service": `${{printf "%.2f" .total_usd}} across {{.total_services}} {{plural .total_services "service" "services"}}
+		{{- with .change_percent}} ({{printf "%+.1f" .}}% on the previous window){{end}}
+		{{- with .largest_increases}}, {{(index . 0).service}} grew most{{end}}`,
+	"aws://ec2/instances/by-{grouping}": `{{.total_instances}} {{plural .total_instances "instance" "instances"}} in {{.total_groups}} {{.grouping}} {{plural .total_groups "group" "groups"}}
+		{{- with .ungrouped}}, {{.}} without one{{end}}`,
+	"search": `{{len .results}} {{plural (len .results) "result" "results"}} for "{{.query}}"{{with .results}}, best match {{(index . 0).kind}} {{(index . 0).id}}{{end}}
+		{{- with .unavailable_sources}} ({{len .}} {{plural (len .) "source" "sources"}} unavailable){{end}}`,
+	"query-logs": `{{.count}} log {{plural .count "entry" "entries"}} from {{.source}}{{if .truncated}} (limit reached){{end}}`,
+	"summarize-logs": `{{.total_entries}} log {{plural .total_entries "entry" "entries"}} from {{.source}} in {{.distinct_patterns}}
+		{{- plural .distinct_patterns " pattern" " patterns"}}{{with .levels.error}}, {{.}} errors{{end}}`,
+	"query-cloudwatch-logs": `{{.count}} {{plural .count "row" "rows"}} from {{len .log_groups}} log {{plural (len .log_groups) "group" "groups"}}
+		{{- with .statistics.records_scanned}} ({{printf "%.0f" .}} records scanned){{end}}{{if .truncated}} (limit reached){{end}}`,
+	"run-athena-query": `{{.count}} {{plural .count "row" "rows"}} from Athena{{with .database}} database {{.}}{{end}}, {{printf "%.2f" .data_scanned_mb}} MB scanned
+		{{- with .estimated_cost_usd}} (about ${{printf "%.4f" .}}){{end}}{{if .truncated}} (row cap reached){{end}}`,
+	"start-athena-query":         `Started Athena query {{.queryExecutionId}} in workgroup {{.workgroup}}`,
+	"get-athena-query":           `Athena query {{.queryExecutionId}} is {{.state}}, {{printf "%.2f" .data_scanned_mb}} MB scanned{{with .reason}}: {{.}}{{end}}`,
+	"get-athena-query-results":   `{{.count}} {{plural .count "row" "rows"}} from Athena query {{.queryExecutionId}}{{if .truncated}} (truncated){{end}}`,
+	"start-redshift-query":       `Started Redshift query {{.statementId}} on {{or .cluster .workgroup}}`,
+	"get-redshift-query":         `Redshift query {{.statementId}} is {{.state}}{{with .reason}}: {{.}}{{end}}`,
+	"get-redshift-query-results": `{{.count}} of {{.total_rows}} {{plural .total_rows "row" "rows"}} from Redshift query {{.statementId}}`,
+	"draft-postmortem": `Postmortem draft "{{.title}}" with {{.counts.alarms}} {{plural .counts.alarms "alarm" "alarms"}}, {{.counts.changes}} {{plural .counts.changes "change" "changes"}} and {{.counts.actions}}
+		{{- plural .counts.actions " action" " actions"}}{{with .gaps}} ({{len .}} {{plural (len .) "source" "sources"}} unavailable){{end}}`,
+	"simulate-action": `{{.action}} on {{.target}} is {{.risk}} risk with {{len .effects}} predicted {{plural (len .effects) "effect" "effects"}}
+		{{- with .monthlyCostDeltaUsd}} ({{printf "%+.2f" .}} USD/month){{end}}`,
+	"approve-action":    `Approved {{.request.id}} and ran {{.request.tool}}`,
+	"reject-action":     `Rejected {{.request.id}} ({{.request.tool}})`,
+	"request-elevation": `Role {{.role}} has write access until {{.expires_at}} ({{.id}}), approved by {{.approved_by}}`,
+	"end-elevation":     `Role {{.role}} is read-only again; {{.id}} ended`,
+	"acknowledge-alert": `Acknowledged alarm {{.alert}} in {{.alarmState}}: {{.reason}}`,
+	"snooze-alert":      `Snoozed alarm {{.alert}} until {{.expires_at}}: {{.reason}}`,
+	"suppress-alert":    `Suppressed alarm {{.alert}}{{with .expires_at}} until {{.}}{{end}}: {{.reason}}`,
+	"unsuppress-alert":  `Alarm {{.alert}} is no longer silenced`,
+	"add-note":          `Added note {{.id}} to {{.resource}}, which has {{.resourceNotes}} {{plural .resourceNotes "note" "notes"}}`,
+	"remove-note":       `Removed note {{.removed.id}} from {{.removed.resource}}`,
+	"ignore-resource":   `Ignoring {{.resource}}{{with .expires_at}} until {{.}}{{end}}: {{.reason}}`,
+	"unignore-resource": `{{.resource}} is no longer ignored`,
+
+	// Resources
+	"aws://ec2/instances": `{{.total_instances}} EC2 {{plural .total_instances "instance" "instances"}}{{with .region}} in {{.}}{{end}}
+		{{- with .summary_by_state}}:{{range $state, $count := .}} {{$count}} {{$state}}{{end}}{{end}}
+		{{- if .stale}} (as of {{.last_refresh}}, refreshing){{end}}
+		{{- if .partial}} on this page{{end}}{{with .next_cursor}}; more on the next page{{end}}`,
+	"aws://organization/accounts": `{{.total}} organization {{plural .total "account" "accounts"}}{{with .summary_by_state}}:{{range $state, $count := .}} {{$count}} {{$state}}{{end}}{{end}}
+		{{- with .note}} (without tags){{end}}`,
+	"aws://org/inventory": `{{.total_instances}} EC2 {{plural .total_instances "instance" "instances"}} across {{.total_accounts}} {{plural .total_accounts "account" "accounts"}}
+		{{- with .summary_by_state}}:{{range $state, $count := .}} {{$count}} {{$state}}{{end}}{{end}}
+		{{- with .failed_accounts}}; {{len .}} failed: {{range $i, $failure := .}}{{if $i}}, {{end}}{{$failure.alias}}{{end}}{{end}}`,
+	"aws://org/cost/daily": `${{printf "%.2f" .total_usd}} across {{.total_accounts}} {{plural .total_accounts "account" "accounts"}} over {{.days}} {{plural .days "day" "days"}}
+		{{- with .accounts}}, led by {{(index . 0).alias}}{{end}}
+		{{- with .failed_accounts}}; {{len .}} failed: {{range $i, $failure := .}}{{if $i}}, {{end}}{{$failure.alias}}{{end}}{{end}}`,
+	"aws://{account}/ec2/instances": `{{.total_instances}} EC2 {{plural .total_instances "instance" "instances"}} in account {{.account}}
+		{{- with .summary_by_state}}:{{range $state, $count := .}} {{$count}} {{$state}}{{end}}{{end}}
+		{{- if .partial}} on this page{{end}}{{with .next_cursor}}; more on the next page{{end}}`,
+	"aws://ec2/instances/{instanceId}": `{{.id}}{{if ne .name .id}}{{template "labels" .}}{{end}} is {{.state}}{{with .details.instanceType}}, {{.}}{{end}} in {{.region}}`,
+	"gcp://compute/instances": `{{.total_instances}} Compute Engine {{plural .total_instances "instance" "instances"}}
+		{{- with .summary_by_state}}:{{range $state, $count := .}} {{$count}} {{$state}}{{end}}{{end}}
+		{{- if .stale}} (as of {{.last_refresh}}, refreshing){{end}}
+		{{- if .partial}} on this page{{end}}{{with .next_cursor}}; more on the next page{{end}}`,
+	"gcp://compute/instances/{instanceId}": `{{.id}}{{with .environment}} ({{.}}){{end}} is {{.state}}{{with .details.instanceType}}, {{.}}{{end}} in {{.region}}`,
+	"azure://vm/instances": `{{.total_instances}} Azure {{plural .total_instances "VM" "VMs"}}
+		{{- with .summary_by_state}}:{{range $state, $count := .}} {{$count}} {{$state}}{{end}}{{end}}
+		{{- if .stale}} (as of {{.last_refresh}}, refreshing){{end}}
+		{{- if .partial}} on this page{{end}}{{with .next_cursor}}; more on the next page{{end}}`,
+	"azure://vm/instances/{instanceId}": `{{.id}}{{with .environment}} ({{.}}){{end}} is {{.state}}{{with .details.instanceType}}, {{.}}{{end}} in {{.region}}`,
+	"azure://vm/resource-groups":        `{{.total_vms}} Azure {{plural .total_vms "VM" "VMs"}} in {{.total_resource_groups}} resource {{plural .total_resource_groups "group" "groups"}}`,
+	"aws://ecs/clusters":                `{{.total_clusters}} ECS {{plural .total_clusters "cluster" "clusters"}} running {{.running_tasks}} {{plural .running_tasks "task" "tasks"}}{{with .region}} in {{.}}{{end}}`,
+	"aws://ecs/clusters/{cluster}/services": `{{.total_services}} {{plural .total_services "service" "services"}} in {{.cluster}}
+		{{- if .unhealthy_services}}, {{.unhealthy_services}} with issues: {{range $i, $name := .unhealthy}}{{if $i}}, {{end}}{{$name}}{{end}}{{end}}`,
+	"aws://ecs/clusters/{cluster}/services/{service}/tasks": `{{.total_tasks}} {{plural .total_tasks "task" "tasks"}} of {{.service}} in {{.cluster}}
+		{{- with .summary_by_status}}:{{range $status, $count := .}} {{$count}} {{$status}}{{end}}{{end}}`,
+	"aws://knowledge/remediations": `{{.total_outcomes}} verified {{plural .total_outcomes "remediation" "remediations"}}{{with .signal}} of {{.}}{{end}}
+		{{- with .recommendation}}; {{.}}{{end}}`,
+	"aws://sqs/queues": `{{.visible_messages}} {{plural .visible_messages "message" "messages"}} waiting in {{.total_queues}} SQS {{plural .total_queues "queue" "queues"}}
+		{{- if .queues_at_risk}}, {{.queues_at_risk}} need attention: {{range $i, $name := .at_risk}}{{if $i}}, {{end}}{{$name}}{{end}}{{end}}`,
+	"aws://sqs/queues/{queue}": `{{.name}} has {{.visible}} visible and {{.in_flight}} in-flight {{plural .visible "message" "messages"}}
+		{{- with .oldest_message_age_seconds}}, oldest {{.}}s old{{end}}{{with .issues}}; {{index . 0}}{{end}}`,
+	"aws://sns/topics": `{{.total_topics}} SNS {{plural .total_topics "topic" "topics"}}
+		{{- if .topics_without_subscribers}}, {{.topics_without_subscribers}} without confirmed subscribers: {{range $i, $name := .without_subscribers}}{{if $i}}, {{end}}{{$name}}{{end}}{{end}}`,
+	"aws://sns/topics/{topic}": `{{.name}} has {{.confirmed_subscriptions}} confirmed and {{.pending_subscriptions}} pending {{plural .confirmed_subscriptions "subscription" "subscriptions"}}
+		{{- with .issues}}; {{index . 0}}{{end}}`,
+	"kb://docs": `{{if .query}}{{len .results}} of {{.total_documents}} {{plural .total_documents "document" "documents"}} match "{{.query}}"
+		{{- with .results}}{{with index . 0}}, best {{.title}} ({{.path}}){{end}}{{end}}
+		{{- else}}{{.total_documents}} {{plural .total_documents "document" "documents"}} in the knowledge base{{end}}
+		{{- with .unavailable_sources}}; {{len .}} {{plural (len .) "source" "sources"}} unavailable{{end}}`,
+	"kb://docs/{+path}": `{{.title}} ({{.path}}){{with .headings}}: {{range $i, $h := .}}{{if $i}}, {{end}}{{$h}}{{end}}{{end}}`,
+	"aws://elasticache/clusters": `{{.total_clusters}} ElastiCache {{plural .total_clusters "cluster" "clusters"}} with {{.total_nodes}} {{plural .total_nodes "node" "nodes"}}
+		{{- with .needs_attention}}, {{len .}} {{plural (len .) "needs" "need"}} attention: {{range $i, $id := .}}{{if $i}}, {{end}}{{$id}}{{end}}{{end}}`,
+	"aws://elasticache/clusters/{clusterId}": `{{.id}} ({{.engine}} {{.engine_version}}) is {{.status}} with {{.nodes_available}} of {{len .nodes}} {{plural (len .nodes) "node" "nodes"}} available
+		{{- with .next_maintenance}}, next maintenance {{.}}{{end}}{{with .issues}}; {{index . 0}}{{end}}`,
+	"aws://elb/load-balancers": `{{.total_load_balancers}} load {{plural .total_load_balancers "balancer" "balancers"}}{{with .region}} in {{.}}{{end}}
+		{{- with .needs_attention}}, {{len .}} without all targets healthy{{end}}`,
+	"aws://elb/target-groups": `{{.total_targets}} {{plural .total_targets "target" "targets"}} in {{.total_target_groups}} target {{plural .total_target_groups "group" "groups"}}
+		{{- with .unhealthy_targets}}, {{len .}} unhealthy{{end}}`,
+	"aws://cost/cur/daily": `{{len .resources}} top resources cost ${{printf "%.2f" .total_usd}} over {{.days}} days
+		{{- with .resources}}{{with index . 0}}, most {{.resource_id}} at ${{printf "%.2f" .total_usd}}{{end}}{{end}}`,
+	"aws://cost/cur/resources/{+resourceId}": `{{.resource_id}} cost ${{printf "%.2f" .total_usd}} over {{.days}} days`,
+	"aws://rds/instances": `{{.total_instances}} RDS {{plural .total_instances "instance" "instances"}}{{with .region}} in {{.}}{{end}}
+		{{- with .summary_by_state}}:{{range $state, $count := .}} {{$count}} {{$state}}{{end}}{{end}}`,
+	"aws://cloudwatch/alarms": `{{.total_alarms}} CloudWatch {{plural .total_alarms "alarm" "alarms"}}{{with .region}} in {{.}}{{end}}:
+		{{- with .summary_by_state}} {{.ALARM}} in ALARM, {{.INSUFFICIENT_DATA}} INSUFFICIENT_DATA, {{.OK}} OK{{end}}
+		{{- with .grouped_alarms}} ({{.}} grouped or duplicate){{end}}`,
+	"aws://cloudwatch/alarms/{+alarmName}/history": `{{.alarm_name}} is {{.current_state}}, fired {{.times_fired}} of {{.count}} state {{plural .count "change" "changes"}}`,
+	"aws://iam/roles": `{{.count}} IAM {{plural .count "role" "roles"}}{{with .full_access}}, {{len .}} with full access{{end}}
+		{{- with .service_linked_roles}} ({{.}} service-linked not shown){{end}}`,
+	"aws://iam/users":           `{{.count}} IAM {{plural .count "user" "users"}}{{with .full_access}}, {{len .}} with full access{{end}}`,
+	"aws://iam/policies/{+arn}": `{{.name}} with {{len .statements}} {{plural (len .statements) "statement" "statements"}}, attached {{.attachment_count}} {{plural .attachment_count "time" "times"}}`,
+	"aws://vpc/topology": `{{.vpc_count}} {{plural .vpc_count "VPC" "VPCs"}} with {{.subnet_count}} {{plural .subnet_count "subnet" "subnets"}}
+		{{- with .subnets_by_tier}}: {{.public}} public, {{.private}} private, {{.isolated}} isolated{{end}}
+		{{- with .issue_count}}; {{.}} {{plural . "issue" "issues"}}{{end}}`,
+	"aws://vpc/nat-gateways": `{{.total}} NAT {{plural .total "gateway" "gateways"}} processed {{printf "%.1f" .total_data_processed_gb}} GB in the last {{.window_minutes}} minutes
+		{{- with .troubled}}; troubled: {{range $i, $name := .}}{{if $i}}, {{end}}{{$name}}{{end}}{{end}}`,
+	"aws://vpc/endpoints": `{{.total}} VPC {{plural .total "endpoint" "endpoints"}}{{with .summary_by_type}}:{{range $type, $count := .}} {{$count}} {{$type}}{{end}}{{end}}
+		{{- with .unavailable}}; {{len .}} not available{{end}}
+		{{- with .nat_without_gateway_endpoints}}; {{len .}} {{plural (len .) "VPC sends" "VPCs send"}} S3 or DynamoDB traffic through NAT{{end}}`,
+	"aws://ec2/security-groups": `{{.total_groups}} security {{plural .total_groups "group" "groups"}}
+		{{- if .world_open_groups}}, {{.world_open_groups}} open to the internet{{end}}`,
+	"aws://ec2/security-groups/{groupId}": `{{.id}} ({{.name}}) with {{len .ingress}} inbound {{plural (len .ingress) "rule" "rules"}} used by {{len .instances}}
+		{{- plural (len .instances) " instance" " instances"}}{{with .world_open_ports}}, {{len .}} open to the internet{{end}}`,
+	"aws://ebs/volumes": `{{.total_volumes}} EBS {{plural .total_volumes "volume" "volumes"}} ({{.total_gib}} GiB)
+		{{- if .unattached_volumes}}, {{.unattached_volumes}} unattached ({{.unattached_gib}} GiB){{end}}`,
+	"aws://ec2/spot-requests": `{{.total}} Spot {{plural .total "request" "requests"}}
+		{{- with .summary_by_state}}:{{range $state, $count := .}} {{$count}} {{$state}}{{end}}{{end}}`,
+	"aws://ec2/spot-interruptions": `{{len .notices}} Spot {{plural (len .notices) "instance" "instances"}} with an interruption notice
+		{{- with .notices}}: {{range $i, $notice := .}}{{if $i}}, {{end}}{{$notice.instanceId}} ({{$notice.statusCode}}){{end}}{{end}};
+		{{- " "}}{{len .interruptions}} {{plural (len .interruptions) "interruption" "interruptions"}} in the last {{.days}} days`,
+	"aws://ebs/snapshots": `{{.total_snapshots}} EBS {{plural .total_snapshots "snapshot" "snapshots"}} of {{.total_gib}} GiB of volumes`,
+	"aws://security/unencrypted": `{{.summary.unencrypted_volumes}} volumes, {{.summary.unencrypted_snapshots}} snapshots and
+		{{.summary.unencrypted_db_instances}} RDS instances are unencrypted`,
+	"aws://resources/ignored": `{{.count}} ignored {{plural .count "resource" "resources"}}{{if .expiring}}, {{.expiring}} with an expiry{{end}}`,
+	"aws://notes":             `{{.total}} {{plural .total "note" "notes"}} on {{.total_resources}} {{plural .total_resources "resource" "resources"}}`,
+	"aws://notes/{+resource}": `{{.total}} {{plural .total "note" "notes"}} on {{.resource}}`,
+	"aws://alerts/suppressions": `{{.count}} silenced {{plural .count "alarm" "alarms"}}: {{.summary.acknowledge}} acknowledged,
+		{{.summary.snooze}} snoozed, {{.summary.suppress}} suppressed`,
+	"aws://approvals/pending": `{{.count}} {{plural .count "action" "actions"}} waiting for approval`,
+	"prom://query{+params}": `{{.series_count}} {{plural .series_count "series" "series"}} for {{.expr}}
+		{{- if .truncated}} (showing {{len .series}}){{end}}`,
+	"metrics://query{+params}": `{{.series_count}} {{plural .series_count "series" "series"}} from {{.provider}} for {{.query}}
+		{{- if .truncated}} (showing {{len .series}}){{end}}`,
+	"aws://oncall/current": `{{if .primary}}On call: {{range $i, $s := .primary}}{{if $i}}, {{end}}{{$s.name}}{{end}}
+		{{- else}}Nobody is on call{{end}} ({{.provider}})`,
+	"aws://ownership/{+resourceId}": `{{if .owned}}{{.resource}} is owned by {{.team}} ({{.source}}: {{.rule}})
+		{{- else}}No owner found for {{.resource}}{{end}}`,
+	"aws://iam/credential-hygiene": `{{.summary.users_checked}} IAM users checked: {{.summary.old_access_keys}} old access keys,
+		{{.summary.unused_credentials}} unused credentials, {{.summary.console_users_without_mfa}} console users without MFA`,
+}
@@ -0,0 +1,90 @@
+package render
+
+import (
+	"fmt"
+	"time"
+)
+
+// TimeFormatter renders timestamps in the configured timezone as RFC3339 and,
+// when enabled, as relative times such as "3 days ago" for AI-formatted views
+type TimeFormatter struct {
+	location *time.Location
+	relative bool
+	now      func() time.Time
+}
+
+// NewTimeFormatter creates a formatter for an IANA timezone name. An empty or
+// unknown timezone falls back to UTC; config.Load rejects unknown names upfront.
+func NewTimeFormatter(timezone string, relative bool) *TimeFormatter {
+	location := time.UTC
+	if timezone != "" {
+		if loaded, err := time.LoadLocation(timezone); err == nil {
+			location = loaded
+		}
+	}
+
+	return &TimeFormatter{
+		location: location,
+		relative: relative,
+		now:      time.Now,
+	}
+}
+
+// Format returns t as RFC3339 in the configured timezone
+func (f *TimeFormatter) Format(t time.Time) string {
+	return t.In(f.location).Format(time.RFC3339)
+}
+
+// Location returns the configured timezone
+func (f *TimeFormatter) Location() *time.Location {
+	return f.location
+}
+
+// Now returns the current time as RFC3339 in the configured timezone
+func (f *TimeFormatter) Now() string {
+	return f.Format(f.now())
+}
+
+// Relative describes t relative to now, or returns "" when relative times are disabled
+func (f *TimeFormatter) Relative(t time.Time) string {
+	if !f.relative || t.IsZero() {
+		return ""
+	}
+	return Since(t, f.now())
+}
+
+// Since describes the distance between t and now in the largest sensible unit,
+// e.g. "just now", "5 minutes ago", "3 days ago" or "in 2 hours"
+func Since(t, now time.Time) string {
+	d := now.Sub(t)
+	future := d < 0
+	if future {
+		d = -d
+	}
+
+	var amount int
+	var unit string
+	switch {
+	case d < time.Minute:
+		return "just now"
+	case d < time.Hour:
+		amount, unit = int(d/time.Minute), "minute"
+	case d < 24*time.Hour:
+		amount, unit = int(d/time.Hour), "hour"
+	case d < 60*24*time.Hour:
+		amount, unit = int(d/(24*time.Hour)), "day"
+	case d < 730*24*time.Hour:
+		amount, unit = int(d/(30*24*time.Hour)), "month"
+	default:
+		amount, unit = int(d/(365*24*time.Hour)), "year"
+	}
+
+	if amount != 1 {
+		unit += "s"
+	}
+
+	if future {
+		return fmt.Sprintf("in %d %s", amount, unit)
+	}
+	return fmt.Sprintf("%d %s ago", amount, unit)
+}
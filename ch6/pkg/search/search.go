@@ -0,0 +1,364 @@
+package search
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+)
+
+// Embedders
+const (
+	EmbedderLocal   = "local"
+	EmbedderBedrock = "bedrock"
+)
+
+const (
+	// defaultDimensions applies when search.dimensions is not configured
+	defaultDimensions = 512
+	// defaultRefreshInterval applies when search.refresh_interval is not configured
+	defaultRefreshInterval = 15 * time.Minute
+	// trigramWeight is how much the letter trigrams of a word count next to
+	// the word itself, so misspellings and word forms still match
+	trigramWeight = 0.5
+)
+
+// Item is something search can find: a resource or a document. Text is what
+// is embedded, e.g. the kind, name and tags of a resource.
+type Item struct {
+	Kind string `json:"kind"`
+	ID   string `json:"id"`
+	Name string `json:"name,omitempty"`
+	URI  string `json:"uri"`
+	Text string `json:"-"`
+}
+
+// Source lists items to index, e.g. RDS instances
+type Source struct {
+	Name string
+	List func(ctx context.Context) ([]Item, error)
+}
+
+// Embedder turns texts into vectors whose cosine similarity reflects how
+// related the texts are
+type Embedder interface {
+	Name() string
+	Embed(ctx context.Context, texts []string) ([][]float32, error)
+}
+
+// Result is an item matching a search and how similar it is to the query,
+// between 0 and 1
+type Result struct {
+	Item
+	Score float64
+}
+
+// entry is an indexed item with its normalized vector and the source that
+// listed it
+type entry struct {
+	item   Item
+	source string
+	vector []float32
+}
+
+// Index embeds the items of its sources for search. It is rebuilt on the
+// first search after the refresh interval; items whose text did not change
+// keep their embedding, so remote embedders are only asked about new ones.
+type Index struct {
+	embedder Embedder
+	sources  []Source
+	refresh  time.Duration
+	now      func() time.Time
+
+	mu       sync.Mutex
+	entries  []entry
+	vectors  map[[sha256.Size]byte][]float32
+	failures map[string]string
+	loaded   time.Time
+}
+
+// New creates an index of the sources
+func New(embedder Embedder, sources []Source, refresh time.Duration) *Index {
+	if refresh <= 0 {
+		refresh = defaultRefreshInterval
+	}
+	return &Index{
+		embedder: embedder,
+		sources:  sources,
+		refresh:  refresh,
+		now:      time.Now,
+		vectors:  make(map[[sha256.Size]byte][]float32),
+	}
+}
+
+// Embedder returns the name of the embedder
+func (idx *Index) Embedder() string {
+	return idx.embedder.Name()
+}
+
+// Search returns up to limit items most similar to query, best first. With
+// kinds only items of those kinds are returned. Items of sources that could
+// not be listed are missing; their errors are returned by source name.
+func (idx *Index) Search(ctx context.Context, query string, kinds []string, limit int) ([]Result, map[string]string, error) {
+	if strings.TrimSpace(query) == "" {
+		return nil, nil, errors.New("query is empty")
+	}
+
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	if err := idx.load(ctx); err != nil {
+		return nil, nil, err
+	}
+
+	vectors, err := idx.embedder.Embed(ctx, []string{query})
+	if err != nil {
+		return nil, idx.failures, fmt.Errorf("failed to embed the query: %w", err)
+	}
+	queryVector := normalize(vectors[0])
+
+	wanted := make(map[string]bool, len(kinds))
+	for _, kind := range kinds {
+		wanted[kind] = true
+	}
+
+	var results []Result
+	for _, e := range idx.entries {
+		if len(wanted) > 0 && !wanted[e.item.Kind] {
+			continue
+		}
+		if score := dot(queryVector, e.vector); score > 0 {
+			results = append(results, Result{Item: e.item, Score: score})
+		}
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Score > results[j].Score
+	})
+	if limit > 0 && len(results) > limit {
+		results = results[:limit]
+	}
+	return results, idx.failures, nil
+}
+
+// load rebuilds the index when it is stale. Sources that fail keep their
+// items from the previous load. Callers hold mu.
+func (idx *Index) load(ctx context.Context) error {
+	if !idx.loaded.IsZero() && idx.now().Sub(idx.loaded) < idx.refresh {
+		return nil
+	}
+
+	var listed []entry
+	var kept []entry
+	idx.failures = nil
+	for _, source := range idx.sources {
+		items, err := source.List(ctx)
+		if err != nil {
+			if idx.failures == nil {
+				idx.failures = make(map[string]string)
+			}
+			idx.failures[source.Name] = err.Error()
+			for _, e := range idx.entries {
+				if e.source == source.Name {
+					kept = append(kept, e)
+				}
+			}
+			continue
+		}
+		for _, item := range items {
+			listed = append(listed, entry{item: item, source: source.Name})
+		}
+	}
+
+	// Only texts not embedded before are sent to the embedder
+	var missing []string
+	seen := make(map[[sha256.Size]byte]bool)
+	for _, e := range listed {
+		key := sha256.Sum256([]byte(e.item.Text))
+		if _, ok := idx.vectors[key]; !ok && !seen[key] {
+			seen[key] = true
+			missing = append(missing, e.item.Text)
+		}
+	}
+	if len(missing) > 0 {
+		vectors, err := idx.embedder.Embed(ctx, missing)
+		if err != nil {
+			if len(idx.entries) == 0 {
+				return fmt.Errorf("failed to embed %d items: %w", len(missing), err)
+			}
+			// Searching the previous index beats failing every search until
+			// the embedder recovers
+			if idx.failures == nil {
+				idx.failures = make(map[string]string)
+			}
+			idx.failures["embedder"] = err.Error()
+			idx.loaded = idx.now()
+			return nil
+		}
+		for i, text := range missing {
+			idx.vectors[sha256.Sum256([]byte(text))] = normalize(vectors[i])
+		}
+	}
+
+	used := make(map[[sha256.Size]byte][]float32, len(listed))
+	entries := kept
+	for _, e := range listed {
+		key := sha256.Sum256([]byte(e.item.Text))
+		used[key] = idx.vectors[key]
+		e.vector = used[key]
+		entries = append(entries, e)
+	}
+	for _, e := range kept {
+		used[sha256.Sum256([]byte(e.item.Text))] = e.vector
+	}
+
+	idx.entries = entries
+	idx.vectors = used
+	idx.loaded = idx.now()
+	return nil
+}
+
+// HashEmbedder embeds texts locally by hashing their words and the letter
+// trigrams of the words into a fixed number of dimensions. It needs no model
+// and matches words rather than meaning: "payments database" finds an RDS
+// instance tagged Service=payments, and "paymnt" still finds "payments".
+type HashEmbedder struct {
+	dimensions int
+}
+
+// NewHashEmbedder creates a local embedder
+func NewHashEmbedder(dimensions int) *HashEmbedder {
+	if dimensions <= 0 {
+		dimensions = defaultDimensions
+	}
+	return &HashEmbedder{dimensions: dimensions}
+}
+
+// Name identifies the embedder
+func (e *HashEmbedder) Name() string {
+	return EmbedderLocal
+}
+
+// Embed hashes each text into a vector
+func (e *HashEmbedder) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	vectors := make([][]float32, len(texts))
+	for i, text := range texts {
+		vector := make([]float32, e.dimensions)
+		for _, word := range Words(text) {
+			e.add(vector, word, 1)
+			padded := "^" + word + "$"
+			for j := 0; j+3 <= len(padded); j++ {
+				e.add(vector, padded[j:j+3], trigramWeight)
+			}
+		}
+		vectors[i] = vector
+	}
+	return vectors, nil
+}
+
+// add hashes a feature into the vector. A second hash bit picks the sign so
+// collisions cancel out rather than add up.
+func (e *HashEmbedder) add(vector []float32, feature string, weight float32) {
+	h := fnv.New64a()
+	h.Write([]byte(feature))
+	sum := h.Sum64()
+	if sum&(1<<63) != 0 {
+		weight = -weight
+	}
+	vector[sum%uint64(e.dimensions)] += weight
+}
+
+// TextEmbedder embeds one text at a time with a remote model, e.g. on Bedrock
+type TextEmbedder struct {
+	name  string
+	embed func(ctx context.Context, text string) ([]float32, error)
+}
+
+// NewTextEmbedder creates an embedder calling embed for every text
+func NewTextEmbedder(name string, embed func(ctx context.Context, text string) ([]float32, error)) *TextEmbedder {
+	return &TextEmbedder{name: name, embed: embed}
+}
+
+// Name identifies the embedder
+func (e *TextEmbedder) Name() string {
+	return e.name
+}
+
+// Embed embeds the texts in order, stopping at the first failure
+func (e *TextEmbedder) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	vectors := make([][]float32, 0, len(texts))
+	for _, text := range texts {
+		vector, err := e.embed(ctx, text)
+		if err != nil {
+			return nil, err
+		}
+		vectors = append(vectors, vector)
+	}
+	return vectors, nil
+}
+
+// Words splits text into lower-case words, also at the case changes and
+// digits of identifiers, so "paymentsDB-2" is payments, db and 2
+func Words(text string) []string {
+	var words []string
+	var word []rune
+	flush := func() {
+		if len(word) > 0 {
+			words = append(words, strings.ToLower(string(word)))
+			word = word[:0]
+		}
+	}
+
+	runes := []rune(text)
+	for i, r := range runes {
+		switch {
+		case !unicode.IsLetter(r) && !unicode.IsDigit(r):
+			flush()
+			continue
+		case i > 0 && unicode.IsUpper(r) && unicode.IsLower(runes[i-1]):
+			flush()
+		case i > 0 && unicode.IsDigit(r) != unicode.IsDigit(runes[i-1]) && (unicode.IsLetter(runes[i-1]) || unicode.IsDigit(runes[i-1])):
+			flush()
+		}
+		word = append(word, r)
+	}
+	flush()
+	return words
+}
+
+// normalize scales a vector to unit length, so the dot product of two
+// vectors is their cosine similarity
+func normalize(vector []float32) []float32 {
+	var sum float64
+	for _, v := range vector {
+		sum += float64(v) * float64(v)
+	}
+	if sum == 0 {
+		return vector
+	}
+	norm := float32(math.Sqrt(sum))
+	normalized := make([]float32, len(vector))
+	for i, v := range vector {
+		normalized[i] = v / norm
+	}
+	return normalized
+}
+
+// dot returns the dot product of two vectors of the same length
+func dot(a, b []float32) float64 {
+	if len(a) != len(b) {
+		return 0
+	}
+	var sum float64
+	for i := range a {
+		sum += float64(a[i]) * float64(b[i])
+	}
+	return sum
+}
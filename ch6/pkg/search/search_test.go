@@ -0,0 +1,115 @@
+package search
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingEmbedder records how many texts it embedded
+type countingEmbedder struct {
+	*HashEmbedder
+	embedded int
+	err      error
+}
+
+func (e *countingEmbedder) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	if e.err != nil {
+		return nil, e.err
+	}
+	e.embedded += len(texts)
+	return e.HashEmbedder.Embed(ctx, texts)
+}
+
+func TestWords(t *testing.T) {
+	assert.Equal(t, []string{"payments", "db", "2"}, Words("paymentsDB-2"))
+	assert.Equal(t, []string{"service", "checkout", "api"}, Words("Service=checkout_api"))
+	assert.Empty(t, Words(" -- "))
+}
+
+func TestSearch(t *testing.T) {
+	fail := false
+	resources := []Item{
+		{Kind: "database", ID: "db-7f3a", URI: "aws://rds/instances", Text: "RDS database db postgres db-7f3a Service payments"},
+		{Kind: "database", ID: "orders-db", URI: "aws://rds/instances", Text: "RDS database db mysql orders-db Service orders"},
+		{Kind: "queue", ID: "payments-events", URI: "aws://sqs/queues/payments-events", Text: "SQS queue payments-events"},
+	}
+	docs := []Item{
+		{Kind: "doc", ID: "runbooks/failover.md", URI: "kb://docs/runbooks/failover.md", Text: "doc runbook RDS failover promote the replica"},
+	}
+	sources := []Source{
+		{Name: "resources", List: func(context.Context) ([]Item, error) { return resources, nil }},
+		{Name: "docs", List: func(context.Context) ([]Item, error) {
+			if fail {
+				return nil, errors.New("access denied")
+			}
+			return docs, nil
+		}},
+	}
+
+	embedder := &countingEmbedder{HashEmbedder: NewHashEmbedder(256)}
+	idx := New(embedder, sources, time.Minute)
+	now := time.Now()
+	idx.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	results, failures, err := idx.Search(ctx, "the payments database", nil, 10)
+	require.NoError(t, err)
+	assert.Empty(t, failures)
+	require.NotEmpty(t, results)
+	assert.Equal(t, "db-7f3a", results[0].ID, "the tag and kind words outrank a name match of another kind")
+	assert.Equal(t, 5, embedder.embedded, "four items and the query")
+
+	results, _, err = idx.Search(ctx, "paymnt", []string{"queue"}, 10)
+	require.NoError(t, err)
+	require.Len(t, results, 1, "trigrams match misspellings, kinds filter")
+	assert.Equal(t, "payments-events", results[0].ID)
+
+	results, _, err = idx.Search(ctx, "database", nil, 1)
+	require.NoError(t, err)
+	assert.Len(t, results, 1)
+
+	// After the refresh interval items are listed again, but only new texts
+	// are embedded and failed sources keep their items
+	fail = true
+	resources = append(resources, Item{Kind: "cache", ID: "sessions", Text: "ElastiCache cache redis sessions"})
+	now = now.Add(2 * time.Minute)
+	embedded := embedder.embedded
+	results, failures, err = idx.Search(ctx, "failover runbook", nil, 10)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"docs": "access denied"}, failures)
+	require.NotEmpty(t, results)
+	assert.Equal(t, "runbooks/failover.md", results[0].ID)
+	assert.Equal(t, embedded+2, embedder.embedded, "the new item and the query")
+
+	_, _, err = idx.Search(ctx, "  ", nil, 10)
+	assert.Error(t, err)
+}
+
+func TestSearchEmbedderFailure(t *testing.T) {
+	embedder := &countingEmbedder{HashEmbedder: NewHashEmbedder(0), err: errors.New("throttled")}
+	idx := New(embedder, []Source{{Name: "resources", List: func(context.Context) ([]Item, error) {
+		return []Item{{Kind: "queue", ID: "jobs", Text: "SQS queue jobs"}}, nil
+	}}}, 0)
+
+	_, _, err := idx.Search(context.Background(), "jobs", nil, 10)
+	assert.ErrorContains(t, err, "throttled")
+}
+
+func TestTextEmbedder(t *testing.T) {
+	var texts []string
+	embedder := NewTextEmbedder("bedrock:titan", func(_ context.Context, text string) ([]float32, error) {
+		texts = append(texts, text)
+		return []float32{float32(len(text)), 1}, nil
+	})
+
+	vectors, err := embedder.Embed(context.Background(), []string{"a", "bcd"})
+	require.NoError(t, err)
+	assert.Equal(t, [][]float32{{1, 1}, {3, 1}}, vectors)
+	assert.Equal(t, []string{"a", "bcd"}, texts)
+	assert.Equal(t, "bedrock:titan", embedder.Name())
+}
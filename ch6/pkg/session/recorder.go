@@ -0,0 +1,114 @@
+package session
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Entry is a single recorded JSON-RPC exchange. Notifications have no response.
+type Entry struct {
+	Seq        int             `json:"seq"`
+	Timestamp  time.Time       `json:"timestamp"`
+	DurationMs int64           `json:"duration_ms"`
+	Request    json.RawMessage `json:"request"`
+	Response   json.RawMessage `json:"response,omitempty"`
+}
+
+// Recorder appends MCP exchanges to a JSON Lines session file
+type Recorder struct {
+	mu   sync.Mutex
+	file *os.File
+	seq  int
+}
+
+// NewRecorder creates a new session file in dir, named after the current time.
+// Only the owner can read it, since calls and their results are recorded.
+func NewRecorder(dir string) (*Recorder, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create session directory: %w", err)
+	}
+
+	name := fmt.Sprintf("session-%s.jsonl", time.Now().UTC().Format("20060102T150405Z"))
+	file, err := os.OpenFile(filepath.Join(dir, name), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create session file: %w", err)
+	}
+
+	return &Recorder{file: file}, nil
+}
+
+// Path returns the location of the session file
+func (r *Recorder) Path() string {
+	return r.file.Name()
+}
+
+// Record writes one exchange to the session file. response is nil for notifications.
+func (r *Recorder) Record(request, response []byte, started time.Time, duration time.Duration) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.seq++
+	entry := Entry{
+		Seq:        r.seq,
+		Timestamp:  started.UTC(),
+		DurationMs: duration.Milliseconds(),
+		Request:    json.RawMessage(request),
+	}
+	if response != nil {
+		entry.Response = json.RawMessage(response)
+	}
+
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to marshal session entry: %w", err)
+	}
+
+	if _, err := r.file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to write session entry: %w", err)
+	}
+
+	return nil
+}
+
+// Close flushes and closes the session file
+func (r *Recorder) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.file.Close()
+}
+
+// Load reads all entries of a recorded session file
+func Load(path string) ([]Entry, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open session file: %w", err)
+	}
+	defer file.Close()
+
+	var entries []Entry
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+
+		var entry Entry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return nil, fmt.Errorf("failed to parse session entry %d: %w", len(entries)+1, err)
+		}
+		entries = append(entries, entry)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read session file: %w", err)
+	}
+
+	return entries, nil
+}
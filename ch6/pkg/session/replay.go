@@ -0,0 +1,156 @@
+package session
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// DefaultIgnoreFields are response fields whose values change on every call
+var DefaultIgnoreFields = []string{"timestamp"}
+
+// Transport sends requests to a server under test and reads its responses in order
+type Transport interface {
+	Send(request []byte) error
+	Receive() ([]byte, error)
+}
+
+// Result is the outcome of replaying a single recorded exchange
+type Result struct {
+	Seq        int             `json:"seq"`
+	Method     string          `json:"method"`
+	Match      bool            `json:"match"`
+	DurationMs int64           `json:"duration_ms"`
+	RecordedMs int64           `json:"recorded_ms"`
+	Expected   json.RawMessage `json:"expected,omitempty"`
+	Actual     json.RawMessage `json:"actual,omitempty"`
+}
+
+// Report summarizes a replay run
+type Report struct {
+	Total      int      `json:"total"`
+	Matched    int      `json:"matched"`
+	Mismatched int      `json:"mismatched"`
+	Results    []Result `json:"results"`
+}
+
+// Replay re-drives recorded requests through transport and compares each response
+// with the recorded one, ignoring the given fields at any depth
+func Replay(entries []Entry, transport Transport, ignoreFields []string) (*Report, error) {
+	ignore := make(map[string]bool, len(ignoreFields))
+	for _, field := range ignoreFields {
+		ignore[field] = true
+	}
+
+	report := &Report{Results: make([]Result, 0, len(entries))}
+	for _, entry := range entries {
+		start := time.Now()
+		if err := transport.Send(entry.Request); err != nil {
+			return report, fmt.Errorf("failed to send request %d: %w", entry.Seq, err)
+		}
+
+		// Notifications were recorded without a response, so there is nothing to read
+		if entry.Response == nil {
+			continue
+		}
+
+		actual, err := transport.Receive()
+		if err != nil {
+			return report, fmt.Errorf("failed to receive response %d: %w", entry.Seq, err)
+		}
+
+		result := Result{
+			Seq:        entry.Seq,
+			Method:     requestMethod(entry.Request),
+			DurationMs: time.Since(start).Milliseconds(),
+			RecordedMs: entry.DurationMs,
+			Match:      Equivalent(entry.Response, actual, ignore),
+		}
+		if !result.Match {
+			result.Expected = entry.Response
+			result.Actual = json.RawMessage(actual)
+		}
+
+		report.Total++
+		if result.Match {
+			report.Matched++
+		} else {
+			report.Mismatched++
+		}
+		report.Results = append(report.Results, result)
+	}
+
+	return report, nil
+}
+
+// Equivalent reports whether two JSON documents are equal once the ignored fields
+// are removed. Tool results carry JSON inside text content, so string values that
+// hold JSON objects are compared structurally as well.
+func Equivalent(expected, actual []byte, ignore map[string]bool) bool {
+	var expectedValue, actualValue interface{}
+	if err := json.Unmarshal(expected, &expectedValue); err != nil {
+		return false
+	}
+	if err := json.Unmarshal(actual, &actualValue); err != nil {
+		return false
+	}
+
+	return reflect.DeepEqual(normalize(expectedValue, ignore), normalize(actualValue, ignore))
+}
+
+// normalize drops ignored fields and expands embedded JSON documents
+func normalize(value interface{}, ignore map[string]bool) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		normalized := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			if ignore[key] {
+				continue
+			}
+			normalized[key] = normalize(item, ignore)
+		}
+		return normalized
+	case []interface{}:
+		normalized := make([]interface{}, len(v))
+		for i, item := range v {
+			normalized[i] = normalize(item, ignore)
+		}
+		return normalized
+	case string:
+		trimmed := strings.TrimSpace(v)
+		if strings.HasPrefix(trimmed, "{") || strings.HasPrefix(trimmed, "[") {
+			var embedded interface{}
+			if err := json.Unmarshal([]byte(trimmed), &embedded); err == nil {
+				return normalize(embedded, ignore)
+			}
+		}
+		return v
+	default:
+		return v
+	}
+}
+
+// requestMethod extracts the JSON-RPC method name for reporting
+func requestMethod(request []byte) string {
+	var message struct {
+		Method string `json:"method"`
+		Params struct {
+			Name string `json:"name"`
+			URI  string `json:"uri"`
+		} `json:"params"`
+	}
+	if err := json.Unmarshal(request, &message); err != nil {
+		return ""
+	}
+
+	switch {
+	case message.Params.Name != "":
+		return message.Method + " " + message.Params.Name
+	case message.Params.URI != "":
+		return message.Method + " " + message.Params.URI
+	default:
+		return message.Method
+	}
+}
@@ -0,0 +1,85 @@
+package session
+
+import (
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// scriptedTransport answers each request with the next canned response
+type scriptedTransport struct {
+	sent      [][]byte
+	responses [][]byte
+}
+
+func (t *scriptedTransport) Send(request []byte) error {
+	t.sent = append(t.sent, request)
+	return nil
+}
+
+func (t *scriptedTransport) Receive() ([]byte, error) {
+	if len(t.responses) == 0 {
+		return nil, errors.New("no more responses")
+	}
+	response := t.responses[0]
+	t.responses = t.responses[1:]
+	return response, nil
+}
+
+func TestRecorderRoundTrip(t *testing.T) {
+	recorder, err := NewRecorder(t.TempDir())
+	require.NoError(t, err)
+	info, err := os.Stat(recorder.Path())
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm(), "recordings are private to the server's user")
+
+	started := time.Now()
+	require.NoError(t, recorder.Record([]byte(`{"jsonrpc":"2.0","id":1,"method":"initialize"}`), []byte(`{"jsonrpc":"2.0","id":1,"result":{}}`), started, 5*time.Millisecond))
+	require.NoError(t, recorder.Record([]byte(`{"jsonrpc":"2.0","method":"notifications/initialized"}`), nil, started, 0))
+	require.NoError(t, recorder.Close())
+
+	entries, err := Load(recorder.Path())
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+
+	assert.Equal(t, 1, entries[0].Seq)
+	assert.Equal(t, int64(5), entries[0].DurationMs)
+	assert.JSONEq(t, `{"jsonrpc":"2.0","id":1,"result":{}}`, string(entries[0].Response))
+	assert.Nil(t, entries[1].Response)
+}
+
+func TestReplayIgnoresVolatileFields(t *testing.T) {
+	entries := []Entry{
+		{Seq: 1, Request: []byte(`{"jsonrpc":"2.0","method":"notifications/initialized"}`)},
+		{
+			Seq:      2,
+			Request:  []byte(`{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"stop-ec2-instance"}}`),
+			Response: []byte(`{"id":2,"result":{"content":[{"type":"text","text":"{\"success\":true,\"timestamp\":\"2025-01-01T00:00:00Z\"}"}]}}`),
+		},
+		{
+			Seq:      3,
+			Request:  []byte(`{"jsonrpc":"2.0","id":3,"method":"resources/list"}`),
+			Response: []byte(`{"id":3,"result":{"resources":[]}}`),
+		},
+	}
+
+	transport := &scriptedTransport{responses: [][]byte{
+		[]byte(`{"id":2,"result":{"content":[{"type":"text","text":"{\n  \"timestamp\": \"2025-06-01T12:00:00Z\",\n  \"success\": true\n}"}]}}`),
+		[]byte(`{"id":3,"result":{"resources":[{"uri":"aws://ec2/instances"}]}}`),
+	}}
+
+	report, err := Replay(entries, transport, DefaultIgnoreFields)
+	require.NoError(t, err)
+
+	assert.Len(t, transport.sent, 3)
+	assert.Equal(t, 2, report.Total)
+	assert.Equal(t, 1, report.Matched)
+	assert.Equal(t, 1, report.Mismatched)
+	assert.Equal(t, "tools/call stop-ec2-instance", report.Results[0].Method)
+	assert.True(t, report.Results[0].Match)
+	assert.False(t, report.Results[1].Match)
+}
@@ -0,0 +1,272 @@
+package shutdown
+
+import (
+	"fmt"
+	"strings"
+
+	"aws-mcp-server/pkg/types"
+)
+
+// managementPorts are SSH and RDP; an instance allowed in only on these ports
+// is how operators reach the target, like a bastion
+var managementPorts = map[int32]bool{22: true, 3389: true}
+
+// Instance is what the planner needs to know about an instance to be stopped
+type Instance struct {
+	ID             string
+	Name           string
+	Role           string
+	SecurityGroups []string
+}
+
+// Bastion reports whether the instance's name or Role tag marks it as a
+// bastion or jump host
+func (i Instance) Bastion() bool {
+	for _, label := range []string{i.Name, i.Role} {
+		label = strings.ToLower(label)
+		if strings.Contains(label, "bastion") || strings.Contains(label, "jump") {
+			return true
+		}
+	}
+	return false
+}
+
+// Dependency orders two instances: Before is stopped in an earlier wave than After
+type Dependency struct {
+	Before string `json:"before"`
+	After  string `json:"after"`
+	Reason string `json:"reason"`
+}
+
+// Plan is the order in which instances are stopped. Instances in a wave are
+// stopped together; the caller pauses and checks health between waves.
+type Plan struct {
+	Waves        [][]string
+	Dependencies []Dependency
+	// TargetGroups maps target group names to the planned instances registered in them
+	TargetGroups map[string][]string
+	Warnings     []string
+}
+
+// Build orders instances so that nothing is stopped while something still
+// depends on it:
+//
+//   - an instance that security groups allow to connect to another is a
+//     client of it and stops first, e.g. web servers before their database
+//   - bastions, and instances allowed in only on SSH or RDP, stop last so
+//     operators can reach the others until they are down
+//   - instances behind the same target group never stop in the same wave, so
+//     its remaining targets can be checked before the next one goes
+func Build(instances []Instance, groups []types.SecurityGroup, targetGroups []types.TargetGroup) Plan {
+	plan := Plan{TargetGroups: make(map[string][]string)}
+
+	planned := make(map[string]bool, len(instances))
+	byGroup := make(map[string][]string)
+	bastions := make(map[string]bool)
+	for _, instance := range instances {
+		planned[instance.ID] = true
+		for _, group := range instance.SecurityGroups {
+			byGroup[group] = append(byGroup[group], instance.ID)
+		}
+		if instance.Bastion() {
+			bastions[instance.ID] = true
+		}
+	}
+
+	// Management rules mark their sources as bastions before ordering, so a
+	// bastion that also reaches an instance on other ports still stops last
+	for _, group := range groups {
+		for _, rule := range group.Ingress {
+			if !isManagementRule(rule) {
+				continue
+			}
+			for _, source := range rule.SourceGroups {
+				for _, client := range byGroup[source] {
+					if len(byGroup[group.ID]) > 0 {
+						bastions[client] = true
+					}
+				}
+			}
+		}
+	}
+
+	seen := make(map[[2]string]bool)
+	addDependency := func(before, after, reason string) {
+		key := [2]string{before, after}
+		if before == after || seen[key] {
+			return
+		}
+		seen[key] = true
+		plan.Dependencies = append(plan.Dependencies, Dependency{Before: before, After: after, Reason: reason})
+	}
+
+	for _, group := range groups {
+		servers := byGroup[group.ID]
+		if len(servers) == 0 {
+			continue
+		}
+		for _, rule := range group.Ingress {
+			for _, source := range rule.SourceGroups {
+				// Instances sharing a self-referencing group are peers, e.g. a cluster
+				if source == group.ID {
+					continue
+				}
+				for _, client := range byGroup[source] {
+					for _, server := range servers {
+						if bastions[client] {
+							addDependency(server, client, fmt.Sprintf("%s is reached through bastion %s, which stops last", server, client))
+						} else {
+							addDependency(client, server, fmt.Sprintf("%s connects to %s on %s, so it stops first", client, server, portRange(rule)))
+						}
+					}
+				}
+			}
+		}
+	}
+
+	// Bastions without a rule tying them to the others still stop last
+	for _, bastion := range instances {
+		if !bastions[bastion.ID] {
+			continue
+		}
+		for _, instance := range instances {
+			if !bastions[instance.ID] {
+				addDependency(instance.ID, bastion.ID, fmt.Sprintf("%s is a bastion, which stops last", bastion.ID))
+			}
+		}
+	}
+
+	members := make(map[string][]string)
+	for _, group := range targetGroups {
+		healthy := 0
+		healthyPlanned := 0
+		for _, target := range group.Targets {
+			if target.State == "healthy" {
+				healthy++
+			}
+			if !planned[target.ID] {
+				continue
+			}
+			plan.TargetGroups[group.Name] = append(plan.TargetGroups[group.Name], target.ID)
+			members[target.ID] = append(members[target.ID], group.Name)
+			if target.State == "healthy" {
+				healthyPlanned++
+			}
+		}
+		if healthy > 0 && healthyPlanned == healthy {
+			plan.Warnings = append(plan.Warnings, fmt.Sprintf("Target group %s loses all %d healthy targets; its load balancer returns errors until they are replaced", group.Name, healthy))
+		}
+	}
+
+	for _, level := range levels(instances, plan.Dependencies, &plan.Warnings) {
+		plan.Waves = append(plan.Waves, stagger(level, members)...)
+	}
+	return plan
+}
+
+// levels groups instances so each group depends only on earlier ones,
+// keeping the input order within a group. When instances depend on each other
+// the ones waiting on the fewest others are stopped together, with a warning,
+// and ordering continues from there.
+func levels(instances []Instance, dependencies []Dependency, warnings *[]string) [][]string {
+	waiting := make(map[string]int, len(instances))
+	next := make(map[string][]string)
+	for _, dependency := range dependencies {
+		waiting[dependency.After]++
+		next[dependency.Before] = append(next[dependency.Before], dependency.After)
+	}
+
+	done := make(map[string]bool, len(instances))
+	var result [][]string
+	for len(done) < len(instances) {
+		var level []string
+		for _, instance := range instances {
+			if !done[instance.ID] && waiting[instance.ID] == 0 {
+				level = append(level, instance.ID)
+			}
+		}
+
+		if len(level) == 0 {
+			fewest := -1
+			for _, instance := range instances {
+				if !done[instance.ID] && (fewest < 0 || waiting[instance.ID] < fewest) {
+					fewest = waiting[instance.ID]
+				}
+			}
+			for _, instance := range instances {
+				if !done[instance.ID] && waiting[instance.ID] == fewest {
+					level = append(level, instance.ID)
+				}
+			}
+			*warnings = append(*warnings, fmt.Sprintf("Could not order %s because they depend on each other; they stop together", strings.Join(level, ", ")))
+		}
+
+		for _, id := range level {
+			done[id] = true
+			for _, after := range next[id] {
+				waiting[after]--
+			}
+		}
+		result = append(result, level)
+	}
+	return result
+}
+
+// stagger splits a level into waves so that no two instances of the same
+// target group stop together
+func stagger(level []string, members map[string][]string) [][]string {
+	var waves [][]string
+	var used []map[string]bool
+	for _, id := range level {
+		placed := false
+		for i := range waves {
+			if !sharesGroup(used[i], members[id]) {
+				waves[i] = append(waves[i], id)
+				markGroups(used[i], members[id])
+				placed = true
+				break
+			}
+		}
+		if !placed {
+			groups := make(map[string]bool)
+			markGroups(groups, members[id])
+			waves = append(waves, []string{id})
+			used = append(used, groups)
+		}
+	}
+	return waves
+}
+
+// sharesGroup reports whether any of the target groups is already used
+func sharesGroup(used map[string]bool, groups []string) bool {
+	for _, group := range groups {
+		if used[group] {
+			return true
+		}
+	}
+	return false
+}
+
+// markGroups records target groups as used by a wave
+func markGroups(used map[string]bool, groups []string) {
+	for _, group := range groups {
+		used[group] = true
+	}
+}
+
+// isManagementRule reports whether a rule only opens SSH or RDP
+func isManagementRule(rule types.SecurityGroupRule) bool {
+	return rule.FromPort == rule.ToPort && managementPorts[rule.FromPort] && (rule.Protocol == "tcp" || rule.Protocol == "6")
+}
+
+// portRange describes the ports a rule opens
+func portRange(rule types.SecurityGroupRule) string {
+	switch {
+	case rule.Protocol == "-1":
+		return "all ports"
+	case rule.FromPort == rule.ToPort:
+		return fmt.Sprintf("port %d", rule.FromPort)
+	default:
+		return fmt.Sprintf("ports %d-%d", rule.FromPort, rule.ToPort)
+	}
+}
@@ -0,0 +1,65 @@
+package shutdown
+
+import (
+	"testing"
+
+	"aws-mcp-server/pkg/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildOrdersClientsBastionsAndTargets(t *testing.T) {
+	instances := []Instance{
+		{ID: "i-db", Name: "orders-db", SecurityGroups: []string{"sg-db"}},
+		{ID: "i-jump", Name: "ops", SecurityGroups: []string{"sg-ops"}},
+		{ID: "i-web1", Name: "web-1", SecurityGroups: []string{"sg-web"}},
+		{ID: "i-web2", Name: "web-2", SecurityGroups: []string{"sg-web"}},
+	}
+	groups := []types.SecurityGroup{
+		{ID: "sg-db", Ingress: []types.SecurityGroupRule{
+			{Protocol: "tcp", FromPort: 5432, ToPort: 5432, SourceGroups: []string{"sg-web"}},
+		}},
+		{ID: "sg-web", Ingress: []types.SecurityGroupRule{
+			{Protocol: "tcp", FromPort: 22, ToPort: 22, SourceGroups: []string{"sg-ops"}},
+			// Web servers talk to each other; peers are not ordered
+			{Protocol: "-1", FromPort: -1, ToPort: -1, SourceGroups: []string{"sg-web"}},
+		}},
+	}
+	targetGroups := []types.TargetGroup{{Name: "web", Targets: []types.Target{
+		{ID: "i-web1", State: "healthy"},
+		{ID: "i-web2", State: "healthy"},
+		{ID: "i-web3", State: "healthy"},
+	}}}
+
+	plan := Build(instances, groups, targetGroups)
+
+	assert.Equal(t, [][]string{{"i-web1"}, {"i-web2"}, {"i-db"}, {"i-jump"}}, plan.Waves)
+	assert.Equal(t, map[string][]string{"web": {"i-web1", "i-web2"}}, plan.TargetGroups)
+	assert.Empty(t, plan.Warnings, "i-web3 keeps serving the target group")
+	assert.Contains(t, plan.Dependencies, Dependency{Before: "i-web1", After: "i-db", Reason: "i-web1 connects to i-db on port 5432, so it stops first"})
+	assert.Contains(t, plan.Dependencies, Dependency{Before: "i-web2", After: "i-jump", Reason: "i-web2 is reached through bastion i-jump, which stops last"})
+}
+
+func TestBuildWarnings(t *testing.T) {
+	instances := []Instance{
+		{ID: "i-a", SecurityGroups: []string{"sg-a"}},
+		{ID: "i-b", SecurityGroups: []string{"sg-b"}},
+		{ID: "i-c", Role: "Bastion"},
+	}
+	groups := []types.SecurityGroup{
+		{ID: "sg-a", Ingress: []types.SecurityGroupRule{{Protocol: "tcp", FromPort: 8080, ToPort: 8080, SourceGroups: []string{"sg-b"}}}},
+		{ID: "sg-b", Ingress: []types.SecurityGroupRule{{Protocol: "tcp", FromPort: 9000, ToPort: 9100, SourceGroups: []string{"sg-a"}}}},
+	}
+	targetGroups := []types.TargetGroup{{Name: "api", Targets: []types.Target{
+		{ID: "i-a", State: "healthy"},
+		{ID: "i-x", State: "unhealthy"},
+	}}}
+
+	plan := Build(instances, groups, targetGroups)
+
+	assert.Equal(t, [][]string{{"i-a", "i-b"}, {"i-c"}}, plan.Waves, "the bastion still stops last")
+	require.Len(t, plan.Warnings, 2)
+	assert.Equal(t, "Target group api loses all 1 healthy targets; its load balancer returns errors until they are replaced", plan.Warnings[0])
+	assert.Equal(t, "Could not order i-a, i-b because they depend on each other; they stop together", plan.Warnings[1])
+}
@@ -0,0 +1,296 @@
+package simulate
+
+import (
+	"fmt"
+	"math"
+
+	"aws-mcp-server/pkg/cost"
+	"aws-mcp-server/pkg/types"
+)
+
+// Actions that can be simulated. resize-ec2-instance has no tool yet; simulating
+// it shows what a stop, type change and start would cost in downtime and money.
+const (
+	ActionCreate    = "create-ec2-instance"
+	ActionStart     = "start-ec2-instance"
+	ActionStop      = "stop-ec2-instance"
+	ActionTerminate = "terminate-ec2-instance"
+	ActionResize    = "resize-ec2-instance"
+)
+
+// Actions lists the supported actions in the order they are documented
+var Actions = []string{ActionCreate, ActionStart, ActionStop, ActionTerminate, ActionResize}
+
+// Effect severities, from least to most disruptive
+const (
+	SeverityInfo     = "info"
+	SeverityWarning  = "warning"
+	SeverityCritical = "critical"
+)
+
+// Effect is one predicted consequence of an action
+type Effect struct {
+	Severity    string `json:"severity"`
+	Resource    string `json:"resource"`
+	Description string `json:"description"`
+}
+
+// Instance is what a simulation needs to know about the instance an action targets
+type Instance struct {
+	ID           string
+	Name         string
+	State        string
+	InstanceType string
+	PublicIP     string
+}
+
+// Prediction is the predicted outcome of an action that was not executed
+type Prediction struct {
+	Action              string   `json:"action"`
+	Target              string   `json:"target"`
+	Risk                string   `json:"risk"`
+	Effects             []Effect `json:"effects"`
+	MonthlyCostDeltaUSD float64  `json:"monthlyCostDeltaUsd"`
+	CostKnown           bool     `json:"costKnown"`
+}
+
+// Add records an effect and raises the overall risk when it is more severe
+func (p *Prediction) Add(severity, resource, format string, args ...interface{}) {
+	p.Effects = append(p.Effects, Effect{
+		Severity:    severity,
+		Resource:    resource,
+		Description: fmt.Sprintf(format, args...),
+	})
+	if severityRank(severity) > severityRank(p.Risk) {
+		p.Risk = severity
+	}
+}
+
+// Supported reports whether an action can be simulated
+func Supported(action string) bool {
+	for _, supported := range Actions {
+		if action == supported {
+			return true
+		}
+	}
+	return false
+}
+
+// CreateInstance predicts launching a new instance of the given type
+func CreateInstance(instanceType string, prices map[string]float64) Prediction {
+	p := newPrediction(ActionCreate, instanceType)
+	p.addCost("", instanceType, prices)
+	if p.CostKnown {
+		p.Add(SeverityInfo, "ec2:"+instanceType, "A new %s instance adds %s to the monthly bill", instanceType, formatAmount(p.MonthlyCostDeltaUSD))
+	}
+	return p
+}
+
+// StartInstance predicts starting a stopped instance
+func StartInstance(instance Instance, groups []types.TargetGroup, prices map[string]float64) Prediction {
+	p := newPrediction(ActionStart, instance.ID)
+	if instance.State != "stopped" {
+		p.Add(SeverityInfo, instance.ID, "%s is %s, so starting it has no effect", instance.label(), instance.State)
+		return p
+	}
+
+	p.addCost("", instance.InstanceType, prices)
+	if p.CostKnown {
+		p.Add(SeverityInfo, instance.ID, "Running %s adds %s to the monthly bill", instance.label(), formatAmount(p.MonthlyCostDeltaUSD))
+	}
+
+	for _, group := range groups {
+		if registered := registrations(group, instance.ID); registered > 0 {
+			healthy := group.HealthyCount()
+			p.Add(SeverityInfo, group.Name, "Target group %s goes from %d to %d healthy %s once health checks pass",
+				group.Name, healthy, healthy+registered, plural(healthy+registered, "target", "targets"))
+		}
+	}
+	return p
+}
+
+// StopInstance predicts stopping a running instance
+func StopInstance(instance Instance, groups []types.TargetGroup, prices map[string]float64) Prediction {
+	p := newPrediction(ActionStop, instance.ID)
+	if instance.State != "running" {
+		p.Add(SeverityInfo, instance.ID, "%s is %s, so stopping it has no effect", instance.label(), instance.State)
+		return p
+	}
+
+	p.addTargetGroupLoss(instance, groups, "Stopping")
+	p.addCost(instance.InstanceType, "", prices)
+	if p.CostKnown {
+		p.Add(SeverityInfo, instance.ID, "Compute charges stop, saving %s; attached EBS volumes are still billed", formatAmount(-p.MonthlyCostDeltaUSD))
+	}
+	if instance.PublicIP != "" {
+		p.Add(SeverityWarning, instance.ID, "The public IP %s is released unless it is an Elastic IP", instance.PublicIP)
+	}
+	p.Add(SeverityWarning, instance.ID, "Data on instance store volumes is lost")
+	return p
+}
+
+// TerminateInstance predicts terminating an instance
+func TerminateInstance(instance Instance, groups []types.TargetGroup, prices map[string]float64) Prediction {
+	p := newPrediction(ActionTerminate, instance.ID)
+	if instance.State == "terminated" || instance.State == "shutting-down" {
+		p.Add(SeverityInfo, instance.ID, "%s is already %s", instance.label(), instance.State)
+		return p
+	}
+
+	p.Add(SeverityCritical, instance.ID, "%s is deleted permanently and cannot be started again", instance.label())
+	if instance.State == "running" {
+		p.addTargetGroupLoss(instance, groups, "Terminating")
+		p.addCost(instance.InstanceType, "", prices)
+		if p.CostKnown {
+			p.Add(SeverityInfo, instance.ID, "Compute charges stop, saving %s", formatAmount(-p.MonthlyCostDeltaUSD))
+		}
+	} else {
+		p.CostKnown = true
+	}
+	p.Add(SeverityWarning, instance.ID, "Volumes with DeleteOnTermination set are deleted with the instance")
+	return p
+}
+
+// ResizeInstance predicts changing the type of an instance, which requires a
+// stop and start when it is running
+func ResizeInstance(instance Instance, instanceType string, groups []types.TargetGroup, prices map[string]float64) Prediction {
+	p := newPrediction(ActionResize, instance.ID)
+	if instance.InstanceType == instanceType {
+		p.Add(SeverityInfo, instance.ID, "%s is already %s", instance.label(), instanceType)
+		p.CostKnown = true
+		return p
+	}
+
+	if instance.State == "running" {
+		p.Add(SeverityWarning, instance.ID, "%s must be stopped to change its type, causing downtime until it is started again", instance.label())
+		p.addTargetGroupLoss(instance, groups, "Resizing")
+		if instance.PublicIP != "" {
+			p.Add(SeverityWarning, instance.ID, "The public IP %s is released during the stop unless it is an Elastic IP", instance.PublicIP)
+		}
+	}
+
+	p.addCost(instance.InstanceType, instanceType, prices)
+	if p.CostKnown {
+		p.Add(SeverityInfo, instance.ID, "Moving from %s to %s changes the monthly bill by %s while running",
+			instance.InstanceType, instanceType, formatUSD(p.MonthlyCostDeltaUSD))
+	}
+	return p
+}
+
+// newPrediction starts a prediction with no effects
+func newPrediction(action, target string) Prediction {
+	return Prediction{
+		Action:  action,
+		Target:  target,
+		Risk:    SeverityInfo,
+		Effects: make([]Effect, 0),
+	}
+}
+
+// addTargetGroupLoss records how many healthy targets each target group loses
+// when the instance goes away
+func (p *Prediction) addTargetGroupLoss(instance Instance, groups []types.TargetGroup, verb string) {
+	for _, group := range groups {
+		removed := 0
+		for _, target := range group.Targets {
+			if target.ID == instance.ID && target.State == "healthy" {
+				removed++
+			}
+		}
+		if removed == 0 {
+			continue
+		}
+
+		healthy := group.HealthyCount()
+		remaining := healthy - removed
+		severity := SeverityInfo
+		switch {
+		case remaining == 0:
+			severity = SeverityCritical
+		case remaining == 1:
+			severity = SeverityWarning
+		}
+
+		p.Add(severity, group.Name, "%s %s removes %d healthy %s from target group %s, leaving %d of %d",
+			verb, instance.label(), removed, plural(removed, "target", "targets"), group.Name, remaining, healthy)
+	}
+}
+
+// addCost sets the monthly cost difference between running fromType and
+// toType. An empty type means no instance is running.
+func (p *Prediction) addCost(fromType, toType string, prices map[string]float64) {
+	var delta float64
+	for _, change := range []struct {
+		instanceType string
+		sign         float64
+	}{{fromType, -1}, {toType, 1}} {
+		if change.instanceType == "" {
+			continue
+		}
+		estimate := cost.EstimateEC2(change.instanceType, prices)
+		if !estimate.Known {
+			p.CostKnown = false
+			p.Add(SeverityInfo, estimate.Resource, "No price is known for %s, so the cost change cannot be estimated", change.instanceType)
+			return
+		}
+		delta += change.sign * estimate.MonthlyUSD()
+	}
+
+	p.MonthlyCostDeltaUSD = math.Round(delta*100) / 100
+	p.CostKnown = true
+}
+
+// label returns the instance ID with its Name tag when it has one
+func (i Instance) label() string {
+	if i.Name != "" {
+		return fmt.Sprintf("%s (%s)", i.ID, i.Name)
+	}
+	return i.ID
+}
+
+// registrations counts how many times an instance is registered with a target group
+func registrations(group types.TargetGroup, instanceID string) int {
+	count := 0
+	for _, target := range group.Targets {
+		if target.ID == instanceID {
+			count++
+		}
+	}
+	return count
+}
+
+// severityRank orders severities from least to most disruptive
+func severityRank(severity string) int {
+	switch severity {
+	case SeverityCritical:
+		return 3
+	case SeverityWarning:
+		return 2
+	case SeverityInfo:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// formatUSD renders a signed monthly amount such as +$42.05/month
+func formatUSD(amount float64) string {
+	sign := "+"
+	if amount < 0 {
+		sign, amount = "-", -amount
+	}
+	return fmt.Sprintf("%s$%.2f/month", sign, amount)
+}
+
+// formatAmount renders an unsigned monthly amount such as $42.05/month
+func formatAmount(amount float64) string {
+	return fmt.Sprintf("$%.2f/month", amount)
+}
+
+// plural picks the singular or plural form for a count
+func plural(count int, singular, pluralForm string) string {
+	if count == 1 {
+		return singular
+	}
+	return pluralForm
+}
@@ -0,0 +1,105 @@
+package simulate
+
+import (
+	"testing"
+
+	"aws-mcp-server/pkg/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func webGroups() []types.TargetGroup {
+	return []types.TargetGroup{
+		{
+			Name: "web",
+			Targets: []types.Target{
+				{ID: "i-1", Port: 80, State: "healthy"},
+				{ID: "i-1", Port: 8080, State: "healthy"},
+				{ID: "i-2", Port: 80, State: "healthy"},
+			},
+		},
+		{
+			Name: "admin",
+			Targets: []types.Target{
+				{ID: "i-1", Port: 80, State: "healthy"},
+				{ID: "i-3", Port: 80, State: "unhealthy"},
+			},
+		},
+		{
+			Name:    "other",
+			Targets: []types.Target{{ID: "i-9", State: "healthy"}},
+		},
+	}
+}
+
+func TestStopInstance(t *testing.T) {
+	instance := Instance{ID: "i-1", Name: "web-1", State: "running", InstanceType: "t3.large", PublicIP: "203.0.113.10"}
+
+	p := StopInstance(instance, webGroups(), nil)
+
+	assert.Equal(t, SeverityCritical, p.Risk)
+	assert.True(t, p.CostKnown)
+	assert.Equal(t, -60.74, p.MonthlyCostDeltaUSD)
+
+	require.GreaterOrEqual(t, len(p.Effects), 2)
+	assert.Equal(t, "web", p.Effects[0].Resource)
+	assert.Equal(t, SeverityWarning, p.Effects[0].Severity)
+	assert.Contains(t, p.Effects[0].Description, "removes 2 healthy targets from target group web, leaving 1 of 3")
+	assert.Equal(t, "admin", p.Effects[1].Resource)
+	assert.Equal(t, SeverityCritical, p.Effects[1].Severity)
+	assert.Contains(t, p.Effects[1].Description, "leaving 0 of 1")
+
+	for _, effect := range p.Effects {
+		assert.NotEqual(t, "other", effect.Resource)
+	}
+}
+
+func TestStopInstanceAlreadyStopped(t *testing.T) {
+	p := StopInstance(Instance{ID: "i-1", State: "stopped", InstanceType: "t3.large"}, webGroups(), nil)
+
+	assert.Equal(t, SeverityInfo, p.Risk)
+	assert.False(t, p.CostKnown)
+	require.Len(t, p.Effects, 1)
+	assert.Contains(t, p.Effects[0].Description, "has no effect")
+}
+
+func TestStartInstance(t *testing.T) {
+	p := StartInstance(Instance{ID: "i-3", State: "stopped", InstanceType: "t3.micro"}, webGroups(), nil)
+
+	assert.Equal(t, 7.59, p.MonthlyCostDeltaUSD)
+	require.Len(t, p.Effects, 2)
+	assert.Contains(t, p.Effects[1].Description, "goes from 1 to 2 healthy targets")
+}
+
+func TestResizeInstance(t *testing.T) {
+	instance := Instance{ID: "i-2", State: "running", InstanceType: "t3.medium"}
+
+	p := ResizeInstance(instance, "m5.large", webGroups(), nil)
+
+	// (0.096 - 0.0416) * 730
+	assert.Equal(t, 39.71, p.MonthlyCostDeltaUSD)
+	assert.Equal(t, SeverityWarning, p.Risk)
+	assert.Contains(t, p.Effects[len(p.Effects)-1].Description, "+$39.71/month")
+
+	p = ResizeInstance(instance, "x99.mega", nil, nil)
+	assert.False(t, p.CostKnown)
+	assert.Contains(t, p.Effects[len(p.Effects)-1].Description, "No price is known for x99.mega")
+
+	p = ResizeInstance(instance, "t3.medium", nil, nil)
+	assert.Equal(t, 0.0, p.MonthlyCostDeltaUSD)
+	assert.Len(t, p.Effects, 1)
+}
+
+func TestTerminateAndCreate(t *testing.T) {
+	p := TerminateInstance(Instance{ID: "i-9", State: "running", InstanceType: "t3.micro"}, webGroups(), nil)
+	assert.Equal(t, SeverityCritical, p.Risk)
+	assert.Equal(t, -7.59, p.MonthlyCostDeltaUSD)
+
+	p = CreateInstance("m5.large", map[string]float64{"m5.large": 0.1})
+	assert.Equal(t, 73.0, p.MonthlyCostDeltaUSD)
+	assert.Equal(t, SeverityInfo, p.Risk)
+
+	assert.True(t, Supported("resize-ec2-instance"))
+	assert.False(t, Supported("reboot-ec2-instance"))
+}
@@ -0,0 +1,178 @@
+package slo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+	"time"
+
+	"aws-mcp-server/internal/config"
+)
+
+// Sources an error ratio can be read from
+const (
+	SourcePrometheus = "prometheus"
+	SourceMetrics    = "metrics"
+)
+
+const (
+	// defaultThreshold is the remaining budget fraction that gates tools when slo.budget_threshold is not set
+	defaultThreshold = 0.1
+	// defaultCacheTTL is how long a budget is reused when slo.cache_ttl is not set
+	defaultCacheTTL = time.Minute
+)
+
+// Evaluator runs a query and returns its current value, the error ratio (0-1)
+// of a service over its SLO window
+type Evaluator func(ctx context.Context, query string) (float64, error)
+
+// Objective is the availability target of one service
+type Objective struct {
+	Service string
+	Target  float64 // percent, e.g. 99.9
+	Source  string
+	Query   string
+}
+
+// Status is the error budget of a service at a point in time. BudgetRemaining
+// is the fraction of the budget left and goes negative once it is overspent.
+type Status struct {
+	Service         string    `json:"service"`
+	Objective       float64   `json:"objective"`
+	ErrorRatio      float64   `json:"errorRatio"`
+	BudgetRemaining float64   `json:"budgetRemaining"`
+	Threshold       float64   `json:"threshold"`
+	Exhausted       bool      `json:"exhausted"`
+	CheckedAt       time.Time `json:"checkedAt"`
+}
+
+// Policy decides whether a service has enough error budget left for risky changes
+type Policy struct {
+	objectives map[string]Objective
+	evaluators map[string]Evaluator
+	threshold  float64
+	ttl        time.Duration
+
+	mu    sync.Mutex
+	cache map[string]Status
+	now   func() time.Time
+}
+
+// NewPolicy creates a policy for the configured services. No services means
+// budgets are not enforced and returns a nil Policy. Invalid services are
+// skipped and reported in the returned error.
+func NewPolicy(cfg config.SLOConfig, evaluators map[string]Evaluator) (*Policy, error) {
+	if len(cfg.Services) == 0 {
+		return nil, nil
+	}
+
+	p := &Policy{
+		objectives: make(map[string]Objective, len(cfg.Services)),
+		evaluators: evaluators,
+		threshold:  cfg.BudgetThreshold,
+		ttl:        cfg.CacheTTL,
+		cache:      make(map[string]Status),
+		now:        time.Now,
+	}
+	if p.threshold <= 0 || p.threshold >= 1 {
+		p.threshold = defaultThreshold
+	}
+	if p.ttl <= 0 {
+		p.ttl = defaultCacheTTL
+	}
+
+	var errs []error
+	for _, service := range cfg.Services {
+		objective := Objective{
+			Service: service.Name,
+			Target:  service.Objective,
+			Source:  strings.ToLower(service.Source),
+			Query:   strings.TrimSpace(service.ErrorRatioQuery),
+		}
+		if objective.Source == "" {
+			objective.Source = SourcePrometheus
+		}
+
+		switch {
+		case objective.Service == "":
+			errs = append(errs, errors.New("slo service without a name"))
+		case objective.Target <= 0 || objective.Target >= 100:
+			errs = append(errs, fmt.Errorf("slo service %s: objective must be a percentage between 0 and 100, got %v", objective.Service, objective.Target))
+		case objective.Query == "":
+			errs = append(errs, fmt.Errorf("slo service %s: error_ratio_query is required", objective.Service))
+		case evaluators[objective.Source] == nil:
+			errs = append(errs, fmt.Errorf("slo service %s: source %q is not configured", objective.Service, objective.Source))
+		default:
+			p.objectives[strings.ToLower(objective.Service)] = objective
+		}
+	}
+
+	if len(p.objectives) == 0 {
+		return nil, errors.Join(errs...)
+	}
+	return p, errors.Join(errs...)
+}
+
+// Flush drops the cached budgets, so the next checks evaluate them again
+func (p *Policy) Flush() {
+	if p == nil {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.cache = make(map[string]Status)
+}
+
+// Check returns the error budget of a service, or nil when the service has no
+// objective. Budgets are cached for the policy's TTL.
+func (p *Policy) Check(ctx context.Context, service string) (*Status, error) {
+	if p == nil {
+		return nil, nil
+	}
+	objective, ok := p.objectives[strings.ToLower(service)]
+	if !ok {
+		return nil, nil
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	key := strings.ToLower(service)
+	if cached, ok := p.cache[key]; ok && p.now().Sub(cached.CheckedAt) < p.ttl {
+		return &cached, nil
+	}
+
+	ratio, err := p.evaluators[objective.Source](ctx, objective.Query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to evaluate the error ratio of %s: %w", objective.Service, err)
+	}
+	if math.IsNaN(ratio) || math.IsInf(ratio, 0) {
+		// No traffic in the window gives 0/0; nothing was spent
+		ratio = 0
+	}
+
+	remaining := BudgetRemaining(objective.Target, ratio)
+	status := Status{
+		Service:         objective.Service,
+		Objective:       objective.Target,
+		ErrorRatio:      ratio,
+		BudgetRemaining: math.Round(remaining*10000) / 10000,
+		Threshold:       p.threshold,
+		Exhausted:       remaining <= p.threshold,
+		CheckedAt:       p.now(),
+	}
+	p.cache[key] = status
+	return &status, nil
+}
+
+// BudgetRemaining returns the fraction of the error budget left for an
+// objective in percent and an observed error ratio. A 99.9% objective allows a
+// 0.001 error ratio; an observed 0.00075 leaves a quarter of the budget.
+func BudgetRemaining(objective, errorRatio float64) float64 {
+	allowed := 1 - objective/100
+	return 1 - errorRatio/allowed
+}
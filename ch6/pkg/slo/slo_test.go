@@ -0,0 +1,104 @@
+package slo
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"aws-mcp-server/internal/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBudgetRemaining(t *testing.T) {
+	assert.InDelta(t, 0.25, BudgetRemaining(99.9, 0.00075), 1e-9)
+	assert.InDelta(t, 1.0, BudgetRemaining(99.9, 0), 1e-9)
+	assert.InDelta(t, -1.0, BudgetRemaining(99, 0.02), 1e-9)
+}
+
+func TestNewPolicy(t *testing.T) {
+	policy, err := NewPolicy(config.SLOConfig{}, nil)
+	assert.NoError(t, err)
+	assert.Nil(t, policy)
+
+	evaluators := map[string]Evaluator{
+		SourcePrometheus: func(ctx context.Context, query string) (float64, error) { return 0, nil },
+	}
+	policy, err = NewPolicy(config.SLOConfig{Services: []config.SLOServiceConfig{
+		{Name: "checkout", Objective: 99.9, ErrorRatioQuery: "checkout:error_ratio:30d"},
+		{Name: "search", Objective: 100, ErrorRatioQuery: "search:error_ratio:30d"},
+		{Name: "billing", Objective: 99.5, Source: "metrics", ErrorRatioQuery: "SELECT ..."},
+	}}, evaluators)
+	require.NotNil(t, policy)
+	assert.ErrorContains(t, err, "search: objective must be a percentage")
+	assert.ErrorContains(t, err, `billing: source "metrics" is not configured`)
+	assert.Equal(t, defaultThreshold, policy.threshold)
+	assert.Len(t, policy.objectives, 1)
+}
+
+func TestPolicyCheck(t *testing.T) {
+	calls := 0
+	ratio := 0.0009
+	evaluators := map[string]Evaluator{
+		SourcePrometheus: func(ctx context.Context, query string) (float64, error) {
+			calls++
+			assert.Equal(t, "checkout:error_ratio:30d", query)
+			return ratio, nil
+		},
+	}
+
+	policy, err := NewPolicy(config.SLOConfig{
+		BudgetThreshold: 0.2,
+		Services: []config.SLOServiceConfig{
+			{Name: "checkout", Objective: 99.9, ErrorRatioQuery: "checkout:error_ratio:30d"},
+		},
+	}, evaluators)
+	require.NoError(t, err)
+
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	policy.now = func() time.Time { return now }
+
+	status, err := policy.Check(context.Background(), "Checkout")
+	require.NoError(t, err)
+	require.NotNil(t, status)
+	assert.Equal(t, "checkout", status.Service)
+	assert.InDelta(t, 0.1, status.BudgetRemaining, 1e-9)
+	assert.True(t, status.Exhausted)
+
+	// Answers are cached until the TTL passes
+	ratio = 0
+	status, _ = policy.Check(context.Background(), "checkout")
+	assert.True(t, status.Exhausted)
+	assert.Equal(t, 1, calls)
+
+	now = now.Add(2 * time.Minute)
+	status, _ = policy.Check(context.Background(), "checkout")
+	assert.False(t, status.Exhausted)
+	assert.Equal(t, 1.0, status.BudgetRemaining)
+
+	status, err = policy.Check(context.Background(), "unknown")
+	assert.NoError(t, err)
+	assert.Nil(t, status)
+
+	var nilPolicy *Policy
+	status, err = nilPolicy.Check(context.Background(), "checkout")
+	assert.NoError(t, err)
+	assert.Nil(t, status)
+}
+
+func TestPolicyCheckError(t *testing.T) {
+	evaluators := map[string]Evaluator{
+		SourcePrometheus: func(ctx context.Context, query string) (float64, error) {
+			return 0, errors.New("connection refused")
+		},
+	}
+	policy, err := NewPolicy(config.SLOConfig{Services: []config.SLOServiceConfig{
+		{Name: "checkout", Objective: 99.9, ErrorRatioQuery: "checkout:error_ratio:30d"},
+	}}, evaluators)
+	require.NoError(t, err)
+
+	_, err = policy.Check(context.Background(), "checkout")
+	assert.ErrorContains(t, err, "connection refused")
+}
@@ -0,0 +1,145 @@
+package summarize
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"sync"
+	"time"
+
+	"aws-mcp-server/internal/config"
+)
+
+const (
+	// defaultCacheTTL applies when summaries.cache_ttl is not configured
+	defaultCacheTTL = time.Hour
+	// defaultMaxInputChars applies when summaries.max_input_chars is not configured
+	defaultMaxInputChars = 50000
+	// truncatedMarker ends inputs cut at the configured size
+	truncatedMarker = "\n[input truncated]"
+)
+
+// Kinds of summaries, each with its own instructions
+const (
+	KindLogs   = "logs"
+	KindDigest = "digest"
+)
+
+// prompts are the system prompts of each kind of summary
+var prompts = map[string]string{
+	KindLogs: "You are an SRE reading log patterns clustered from an application's logs. " +
+		"In at most five short sentences, explain what the application was doing, which errors matter and when they started, " +
+		"and what to check next. Quote pattern text exactly and do not invent causes the data does not show.",
+	KindDigest: "You are an SRE writing the daily operations digest for a team. " +
+		"In a few short bullet points, state what needs attention first, then what changed and what recovered. " +
+		"Use the names and numbers from the data and do not invent events.",
+}
+
+// GenerateFunc asks a model to answer a prompt under a system prompt
+type GenerateFunc func(ctx context.Context, system, prompt string) (string, error)
+
+// Summary is a narrative written by the model
+type Summary struct {
+	Text        string
+	Model       string
+	GeneratedAt time.Time
+	Cached      bool
+	Truncated   bool
+}
+
+// cached is a summary and when it expires
+type cached struct {
+	summary Summary
+	expires time.Time
+}
+
+// Summarizer writes narratives of data with a model and caches them by kind
+// and input, so asking again about the same data costs nothing
+type Summarizer struct {
+	model    string
+	generate GenerateFunc
+	ttl      time.Duration
+	maxInput int
+	now      func() time.Time
+
+	mu    sync.Mutex
+	cache map[[sha256.Size]byte]cached
+}
+
+// New creates a summarizer for the model behind generate
+func New(cfg config.SummariesConfig, generate GenerateFunc) *Summarizer {
+	ttl := cfg.CacheTTL
+	if ttl <= 0 {
+		ttl = defaultCacheTTL
+	}
+	maxInput := cfg.MaxInputChars
+	if maxInput <= 0 {
+		maxInput = defaultMaxInputChars
+	}
+	return &Summarizer{
+		model:    cfg.Model,
+		generate: generate,
+		ttl:      ttl,
+		maxInput: maxInput,
+		now:      time.Now,
+		cache:    make(map[[sha256.Size]byte]cached),
+	}
+}
+
+// Summarize returns the narrative of input, from the cache while it is fresh
+func (s *Summarizer) Summarize(ctx context.Context, kind, input string) (*Summary, error) {
+	system, ok := prompts[kind]
+	if !ok {
+		return nil, errors.New("unknown summary kind " + kind)
+	}
+
+	truncated := len(input) > s.maxInput
+	if truncated {
+		input = input[:s.maxInput] + truncatedMarker
+	}
+	key := sha256.Sum256([]byte(kind + "\x00" + input))
+
+	s.mu.Lock()
+	entry, ok := s.cache[key]
+	s.mu.Unlock()
+	if ok && s.now().Before(entry.expires) {
+		summary := entry.summary
+		summary.Cached = true
+		return &summary, nil
+	}
+
+	// The model is called without the lock, so a slow summary does not hold
+	// up cached ones; concurrent misses for the same input both call it
+	text, err := s.generate(ctx, system, input)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := Summary{
+		Text:        text,
+		Model:       s.model,
+		GeneratedAt: s.now(),
+		Truncated:   truncated,
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for k, e := range s.cache {
+		if !s.now().Before(e.expires) {
+			delete(s.cache, k)
+		}
+	}
+	s.cache[key] = cached{summary: summary, expires: summary.GeneratedAt.Add(s.ttl)}
+	return &summary, nil
+}
+
+// Flush drops the cached summaries, so the next ones are written anew
+func (s *Summarizer) Flush() {
+	if s == nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.cache = make(map[[sha256.Size]byte]cached)
+}
@@ -0,0 +1,61 @@
+package summarize
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"aws-mcp-server/internal/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSummarize(t *testing.T) {
+	var prompts []string
+	fail := false
+	summarizer := New(config.SummariesConfig{Model: "haiku", CacheTTL: time.Hour, MaxInputChars: 20}, func(_ context.Context, system, prompt string) (string, error) {
+		if fail {
+			return "", errors.New("throttled")
+		}
+		prompts = append(prompts, prompt)
+		return "Timeouts started at 10:02.", nil
+	})
+	now := time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC)
+	summarizer.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	summary, err := summarizer.Summarize(ctx, KindLogs, "3x ERROR db timeout")
+	require.NoError(t, err)
+	assert.Equal(t, "Timeouts started at 10:02.", summary.Text)
+	assert.Equal(t, "haiku", summary.Model)
+	assert.False(t, summary.Cached)
+
+	summary, err = summarizer.Summarize(ctx, KindLogs, "3x ERROR db timeout")
+	require.NoError(t, err)
+	assert.True(t, summary.Cached)
+	assert.Len(t, prompts, 1, "the cached narrative is reused")
+
+	_, err = summarizer.Summarize(ctx, KindDigest, "3x ERROR db timeout")
+	require.NoError(t, err)
+	assert.Len(t, prompts, 2, "each kind is cached separately")
+
+	now = now.Add(2 * time.Hour)
+	_, err = summarizer.Summarize(ctx, KindLogs, "3x ERROR db timeout")
+	require.NoError(t, err)
+	assert.Len(t, prompts, 3, "expired narratives are written again")
+
+	summary, err = summarizer.Summarize(ctx, KindLogs, strings.Repeat("x", 50))
+	require.NoError(t, err)
+	assert.True(t, summary.Truncated)
+	assert.Equal(t, strings.Repeat("x", 20)+truncatedMarker, prompts[3])
+
+	fail = true
+	_, err = summarizer.Summarize(ctx, KindLogs, "new input")
+	assert.ErrorContains(t, err, "throttled")
+
+	_, err = summarizer.Summarize(ctx, "poem", "input")
+	assert.ErrorContains(t, err, "unknown summary kind")
+}
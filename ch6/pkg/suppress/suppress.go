@@ -0,0 +1,166 @@
+package suppress
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"aws-mcp-server/pkg/jsonfile"
+)
+
+// Kind is how an alert is silenced
+type Kind string
+
+const (
+	// KindAcknowledge marks an alert as known while it stays in the same state;
+	// it is still listed, and the acknowledgment lapses when the state changes
+	KindAcknowledge Kind = "acknowledge"
+	// KindSnooze hides an alert until the snooze expires
+	KindSnooze Kind = "snooze"
+	// KindSuppress hides an alert until it is unsuppressed or, with an expiry, until then
+	KindSuppress Kind = "suppress"
+)
+
+// Entry silences one alert, identified by its name
+type Entry struct {
+	Alert     string     `json:"alert"`
+	Kind      Kind       `json:"kind"`
+	Reason    string     `json:"reason"`
+	CreatedBy string     `json:"createdBy"`
+	CreatedAt time.Time  `json:"createdAt"`
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+}
+
+// Expired reports whether the entry no longer applies at now
+func (e Entry) Expired(now time.Time) bool {
+	return e.ExpiresAt != nil && !now.Before(*e.ExpiresAt)
+}
+
+// Hides reports whether the entry keeps its alert out of listings at now;
+// acknowledged alerts stay listed
+func (e Entry) Hides(now time.Time) bool {
+	return e.Kind != KindAcknowledge && !e.Expired(now)
+}
+
+// List holds the silenced alerts. With a path, the list is saved as JSON after
+// every change and reloaded on startup, so permanent suppressions survive restarts.
+type List struct {
+	mu      sync.Mutex
+	path    string
+	entries map[string]Entry
+}
+
+// NewList creates an empty in-memory list
+func NewList() *List {
+	return &List{entries: make(map[string]Entry)}
+}
+
+// Open creates a list saved to path. With an empty path entries are only kept in memory.
+func Open(path string) (*List, error) {
+	l := NewList()
+	l.path = path
+	if path == "" {
+		return l, nil
+	}
+
+	var entries []Entry
+	if err := jsonfile.Load(path, "suppressions", &entries); err != nil {
+		return l, err
+	}
+	for _, entry := range entries {
+		l.entries[entry.Alert] = entry
+	}
+	return l, nil
+}
+
+// Add silences an alert, replacing any earlier entry for it
+func (l *List) Add(entry Entry) error {
+	if entry.CreatedAt.IsZero() {
+		entry.CreatedAt = time.Now()
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.entries[entry.Alert] = entry
+	return l.save()
+}
+
+// Remove lifts the silence on an alert and returns the removed entry
+func (l *List) Remove(alert string) (Entry, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	entry, exists := l.entries[alert]
+	if !exists {
+		return Entry{}, fmt.Errorf("alert %s is not acknowledged, snoozed or suppressed", alert)
+	}
+
+	delete(l.entries, alert)
+	return entry, l.save()
+}
+
+// Get returns the entry for an alert if it is active at now
+func (l *List) Get(alert string, now time.Time) (Entry, bool) {
+	if l == nil {
+		return Entry{}, false
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	entry, exists := l.entries[alert]
+	if !exists || entry.Expired(now) {
+		return Entry{}, false
+	}
+	return entry, true
+}
+
+// Active returns the entries that apply at now, newest first. Expired entries
+// are dropped.
+func (l *List) Active(now time.Time) []Entry {
+	if l == nil {
+		return nil
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	active := make([]Entry, 0, len(l.entries))
+	expired := false
+	for alert, entry := range l.entries {
+		if entry.Expired(now) {
+			delete(l.entries, alert)
+			expired = true
+			continue
+		}
+		active = append(active, entry)
+	}
+	if expired {
+		// Failing to save only means expired entries are dropped again next time
+		_ = l.save()
+	}
+
+	sort.Slice(active, func(i, j int) bool {
+		return active[i].CreatedAt.After(active[j].CreatedAt)
+	})
+	return active
+}
+
+// save writes all entries to the list file, replacing it atomically
+func (l *List) save() error {
+	if l.path == "" {
+		return nil
+	}
+
+	entries := make([]Entry, 0, len(l.entries))
+	for _, entry := range l.entries {
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Alert < entries[j].Alert
+	})
+
+	return jsonfile.SaveIndent(l.path, "suppressions", entries)
+}
@@ -0,0 +1,54 @@
+package suppress
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListExpiry(t *testing.T) {
+	l := NewList()
+	now := time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC)
+	until := now.Add(time.Hour)
+	require.NoError(t, l.Add(Entry{Alert: "cpu-high", Kind: KindSnooze, Reason: "load test", CreatedAt: now, ExpiresAt: &until}))
+	require.NoError(t, l.Add(Entry{Alert: "disk-full", Kind: KindAcknowledge, Reason: "cleanup running", CreatedAt: now.Add(time.Minute)}))
+
+	entry, ok := l.Get("cpu-high", now.Add(30*time.Minute))
+	require.True(t, ok)
+	assert.True(t, entry.Hides(now.Add(30*time.Minute)))
+
+	acknowledged, ok := l.Get("disk-full", now)
+	require.True(t, ok)
+	assert.False(t, acknowledged.Hides(now))
+
+	active := l.Active(now.Add(2 * time.Hour))
+	require.Len(t, active, 1)
+	assert.Equal(t, "disk-full", active[0].Alert)
+
+	_, ok = l.Get("cpu-high", now)
+	assert.False(t, ok, "expired entries are dropped")
+
+	_, err := l.Remove("cpu-high")
+	assert.ErrorContains(t, err, "not acknowledged, snoozed or suppressed")
+}
+
+func TestListPersists(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state", "suppressions.json")
+
+	l, err := Open(path)
+	require.NoError(t, err)
+	require.NoError(t, l.Add(Entry{Alert: "nightly-batch-errors", Kind: KindSuppress, Reason: "expected during backfill", CreatedBy: "sre"}))
+	require.NoError(t, l.Add(Entry{Alert: "cpu-high", Kind: KindAcknowledge, Reason: "investigating"}))
+	_, err = l.Remove("cpu-high")
+	require.NoError(t, err)
+
+	reopened, err := Open(path)
+	require.NoError(t, err)
+	active := reopened.Active(time.Now())
+	require.Len(t, active, 1)
+	assert.Equal(t, KindSuppress, active[0].Kind)
+	assert.Equal(t, "sre", active[0].CreatedBy)
+}
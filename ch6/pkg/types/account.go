@@ -0,0 +1,18 @@
+package types
+
+import "time"
+
+// Account is a member account of an AWS organization. State is
+// PENDING_ACTIVATION, ACTIVE, SUSPENDED, PENDING_CLOSURE or CLOSED, and
+// JoinedMethod INVITED or CREATED. Tags are only filled in by
+// ListAccountTags.
+type Account struct {
+	ID           string            `json:"id"`
+	ARN          string            `json:"arn"`
+	Name         string            `json:"name"`
+	Email        string            `json:"email"`
+	State        string            `json:"state"`
+	JoinedMethod string            `json:"joinedMethod,omitempty"`
+	JoinedAt     time.Time         `json:"joinedAt"`
+	Tags         map[string]string `json:"tags,omitempty"`
+}
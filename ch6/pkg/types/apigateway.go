@@ -0,0 +1,37 @@
+package types
+
+import "time"
+
+// API is an API Gateway API. Protocol is REST for the REST APIs of API
+// Gateway v1 and HTTP or WEBSOCKET for v2 APIs.
+type API struct {
+	ID           string     `json:"id"`
+	Name         string     `json:"name"`
+	Protocol     string     `json:"protocol"`
+	Endpoint     string     `json:"endpoint,omitempty"`
+	EndpointType string     `json:"endpointType,omitempty"`
+	CreatedAt    time.Time  `json:"createdAt"`
+	Stages       []APIStage `json:"stages"`
+}
+
+// APIStage is a stage of an API. Throttle is the stage's default limit, nil
+// when the account limit applies; RouteThrottles override it for single
+// methods of REST APIs or routes of v2 APIs.
+type APIStage struct {
+	Name             string                 `json:"name"`
+	DeploymentID     string                 `json:"deploymentId,omitempty"`
+	AutoDeploy       bool                   `json:"autoDeploy"`
+	Throttle         *APIThrottle           `json:"throttle,omitempty"`
+	RouteThrottles   map[string]APIThrottle `json:"routeThrottles,omitempty"`
+	TracingEnabled   bool                   `json:"tracingEnabled"`
+	CacheEnabled     bool                   `json:"cacheEnabled"`
+	LastUpdatedAt    *time.Time             `json:"lastUpdatedAt,omitempty"`
+	DeploymentStatus string                 `json:"deploymentStatus,omitempty"`
+}
+
+// APIThrottle is a steady-state request rate per second with the burst of
+// requests allowed above it
+type APIThrottle struct {
+	RateLimit  float64 `json:"rateLimit"`
+	BurstLimit int32   `json:"burstLimit"`
+}
@@ -0,0 +1,22 @@
+package types
+
+// AthenaResult is the outcome of an Athena query. Rows map column names to
+// values; Columns keeps the order of the SELECT list.
+type AthenaResult struct {
+	QueryExecutionID string              `json:"queryExecutionId"`
+	State            string              `json:"state"`
+	StateReason      string              `json:"stateReason,omitempty"`
+	StatementType    string              `json:"statementType,omitempty"`
+	Columns          []string            `json:"columns"`
+	Rows             []map[string]string `json:"rows"`
+	Truncated        bool                `json:"truncated,omitempty"`
+	DataScannedBytes int64               `json:"dataScannedBytes"`
+	ExecutionTimeMs  int64               `json:"executionTimeMs"`
+	OutputLocation   string              `json:"outputLocation,omitempty"`
+}
+
+// Done reports whether the query stopped running, whether it succeeded,
+// failed or was cancelled
+func (r *AthenaResult) Done() bool {
+	return r.State == "SUCCEEDED" || r.State == "FAILED" || r.State == "CANCELLED"
+}
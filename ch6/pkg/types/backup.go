@@ -0,0 +1,33 @@
+package types
+
+import "time"
+
+// BackupJob is an AWS Backup job. State is CREATED, PENDING, RUNNING,
+// ABORTING, ABORTED, COMPLETED, FAILED, EXPIRED or PARTIAL; BackupPlan is
+// empty for on-demand backups.
+type BackupJob struct {
+	ID               string     `json:"id"`
+	ResourceARN      string     `json:"resourceArn"`
+	ResourceType     string     `json:"resourceType"`
+	ResourceName     string     `json:"resourceName,omitempty"`
+	VaultName        string     `json:"vaultName"`
+	State            string     `json:"state"`
+	StatusMessage    string     `json:"statusMessage,omitempty"`
+	PercentDone      string     `json:"percentDone,omitempty"`
+	SizeBytes        int64      `json:"sizeBytes"`
+	RecoveryPointARN string     `json:"recoveryPointArn,omitempty"`
+	BackupPlan       string     `json:"backupPlan,omitempty"`
+	Created          time.Time  `json:"created"`
+	Completed        *time.Time `json:"completed,omitempty"`
+}
+
+// RecoveryPoint is a backup of a resource that can be restored. Status is
+// COMPLETED, PARTIAL, DELETING, EXPIRED, AVAILABLE, STOPPED or CREATING.
+type RecoveryPoint struct {
+	ARN       string    `json:"arn"`
+	VaultName string    `json:"vaultName"`
+	Status    string    `json:"status"`
+	SizeBytes int64     `json:"sizeBytes"`
+	Encrypted bool      `json:"encrypted"`
+	Created   time.Time `json:"created"`
+}
@@ -0,0 +1,12 @@
+package types
+
+// CommandResult is the outcome of a shell command run on an instance through
+// SSM Run Command. Output is truncated by SSM to the first 24,000 characters.
+type CommandResult struct {
+	CommandID    string `json:"commandId"`
+	InstanceID   string `json:"instanceId"`
+	Status       string `json:"status"`
+	ResponseCode int32  `json:"responseCode"`
+	Output       string `json:"output"`
+	Error        string `json:"error,omitempty"`
+}
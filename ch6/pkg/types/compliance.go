@@ -0,0 +1,40 @@
+package types
+
+import "time"
+
+// ConfigRule is an AWS Config rule with its compliance. Source is the
+// identifier of an AWS managed rule, e.g. S3_BUCKET_PUBLIC_READ_PROHIBITED,
+// or the Lambda function ARN of a custom rule. Compliance is COMPLIANT,
+// NON_COMPLIANT, NOT_APPLICABLE or INSUFFICIENT_DATA; AWS stops counting
+// non-compliant resources at 100, which CountCapped reports.
+type ConfigRule struct {
+	Name              string             `json:"name"`
+	Description       string             `json:"description,omitempty"`
+	Owner             string             `json:"owner"`
+	Source            string             `json:"source"`
+	State             string             `json:"state"`
+	ResourceTypes     []string           `json:"resourceTypes,omitempty"`
+	Compliance        string             `json:"compliance"`
+	NonCompliantCount int                `json:"nonCompliantCount"`
+	CountCapped       bool               `json:"countCapped,omitempty"`
+	Remediation       *ConfigRemediation `json:"remediation,omitempty"`
+}
+
+// ConfigRemediation is the remediation action set up for a rule, an SSM
+// Automation document run on non-compliant resources
+type ConfigRemediation struct {
+	TargetType string `json:"targetType"`
+	TargetID   string `json:"targetId"`
+	Automatic  bool   `json:"automatic"`
+}
+
+// ConfigEvaluation is the result of a rule for one resource, with the rule's
+// explanation in Annotation when it gave one
+type ConfigEvaluation struct {
+	RuleName     string    `json:"ruleName"`
+	ResourceType string    `json:"resourceType"`
+	ResourceID   string    `json:"resourceId"`
+	Compliance   string    `json:"compliance"`
+	Annotation   string    `json:"annotation,omitempty"`
+	RecordedAt   time.Time `json:"recordedAt"`
+}
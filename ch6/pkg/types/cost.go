@@ -0,0 +1,20 @@
+package types
+
+import "time"
+
+// CostPeriod is the cost of one Cost Explorer period, broken down by group
+// when the query is grouped. Estimated periods are not final yet.
+type CostPeriod struct {
+	Start     time.Time   `json:"start"`
+	End       time.Time   `json:"end"`
+	Total     float64     `json:"total"`
+	Unit      string      `json:"unit"`
+	Estimated bool        `json:"estimated"`
+	Groups    []CostGroup `json:"groups,omitempty"`
+}
+
+// CostGroup is the cost of one group, such as a service or a tag value
+type CostGroup struct {
+	Key    string  `json:"key"`
+	Amount float64 `json:"amount"`
+}
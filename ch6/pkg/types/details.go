@@ -0,0 +1,356 @@
+package types
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"time"
+
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+)
+
+// ResourceDetails are the provider- and type-specific attributes of a
+// CloudResource. Implementations are pointers to the structs below and
+// marshal to a JSON object keyed by the attribute names of the provider's API.
+type ResourceDetails interface {
+	// Fields returns the attributes keyed by their JSON names, leaving out
+	// unset optional ones. Times stay time values so handlers can format them.
+	Fields() map[string]interface{}
+}
+
+// EC2InstanceDetails are the attributes of an EC2 instance. Platform is
+// windows or linux. Lifecycle is spot for Spot Instances, empty for
+// On-Demand ones.
+type EC2InstanceDetails struct {
+	InstanceType     string              `json:"instanceType"`
+	Placement        *ec2types.Placement `json:"placement"`
+	LaunchTime       *time.Time          `json:"launchTime"`
+	ImageID          string              `json:"imageId,omitempty"`
+	Platform         string              `json:"platform"`
+	PlatformDetails  string              `json:"platformDetails,omitempty"`
+	KeyName          string              `json:"keyName,omitempty"`
+	PublicIPAddress  string              `json:"publicIpAddress,omitempty"`
+	PrivateIPAddress string              `json:"privateIpAddress,omitempty"`
+	VpcID            string              `json:"vpcId,omitempty"`
+	SubnetID         string              `json:"subnetId,omitempty"`
+	SecurityGroups   []string            `json:"securityGroups,omitempty"`
+	Lifecycle        string              `json:"lifecycle,omitempty"`
+	SpotRequestID    string              `json:"spotInstanceRequestId,omitempty"`
+}
+
+// Fields returns the instance's attributes
+func (d *EC2InstanceDetails) Fields() map[string]interface{} {
+	return fieldsOf(d)
+}
+
+// EBSVolumeDetails are the attributes of an EBS volume. IOPS and throughput
+// are only set for volume types that have them.
+type EBSVolumeDetails struct {
+	VolumeType       string          `json:"volumeType"`
+	SizeGiB          int32           `json:"sizeGiB"`
+	Encrypted        bool            `json:"encrypted"`
+	AvailabilityZone string          `json:"availabilityZone"`
+	CreateTime       *time.Time      `json:"createTime"`
+	MultiAttach      bool            `json:"multiAttach"`
+	IOPS             int32           `json:"iops,omitempty"`
+	Throughput       int32           `json:"throughput,omitempty"`
+	KmsKeyID         string          `json:"kmsKeyId,omitempty"`
+	SnapshotID       string          `json:"snapshotId,omitempty"`
+	Attachments      []EBSAttachment `json:"attachments"`
+}
+
+// Fields returns the volume's attributes
+func (d *EBSVolumeDetails) Fields() map[string]interface{} {
+	return fieldsOf(d)
+}
+
+// EBSAttachment is the attachment of an EBS volume to an instance
+type EBSAttachment struct {
+	InstanceID          string     `json:"instanceId"`
+	Device              string     `json:"device"`
+	State               string     `json:"state"`
+	DeleteOnTermination bool       `json:"deleteOnTermination"`
+	AttachTime          *time.Time `json:"attachTime"`
+}
+
+// EBSSnapshotDetails are the attributes of an EBS snapshot. Progress is a
+// percentage such as "100%".
+type EBSSnapshotDetails struct {
+	VolumeID       string     `json:"volumeId"`
+	SizeGiB        int32      `json:"sizeGiB"`
+	Encrypted      bool       `json:"encrypted"`
+	StartTime      *time.Time `json:"startTime"`
+	Progress       string     `json:"progress"`
+	StorageTier    string     `json:"storageTier"`
+	Description    string     `json:"description,omitempty"`
+	CompletionTime *time.Time `json:"completionTime,omitempty"`
+}
+
+// Fields returns the snapshot's attributes
+func (d *EBSSnapshotDetails) Fields() map[string]interface{} {
+	return fieldsOf(d)
+}
+
+// RDSInstanceDetails are the attributes of an RDS DB instance. Endpoint is
+// "address:port" once the instance is reachable.
+type RDSInstanceDetails struct {
+	Engine                    string `json:"engine"`
+	EngineVersion             string `json:"engineVersion"`
+	InstanceClass             string `json:"instanceClass"`
+	AllocatedStorage          int32  `json:"allocatedStorage"`
+	StorageEncrypted          bool   `json:"storageEncrypted"`
+	MultiAZ                   bool   `json:"multiAZ"`
+	PubliclyAccessible        bool   `json:"publiclyAccessible"`
+	ARN                       string `json:"arn"`
+	DeletionProtection        bool   `json:"deletionProtection"`
+	SecondaryAvailabilityZone string `json:"secondaryAvailabilityZone,omitempty"`
+	AvailabilityZone          string `json:"availabilityZone,omitempty"`
+	KmsKeyID                  string `json:"kmsKeyId,omitempty"`
+	Endpoint                  string `json:"endpoint,omitempty"`
+}
+
+// Fields returns the DB instance's attributes
+func (d *RDSInstanceDetails) Fields() map[string]interface{} {
+	return fieldsOf(d)
+}
+
+// RDSSnapshotDetails are the attributes of a manual RDS snapshot. Only the DB
+// instance is known when RDS did not describe the snapshot on creation.
+type RDSSnapshotDetails struct {
+	DBInstanceID     string `json:"dbInstanceId"`
+	ARN              string `json:"arn,omitempty"`
+	Engine           string `json:"engine,omitempty"`
+	AllocatedStorage int32  `json:"allocatedStorage,omitempty"`
+	Encrypted        bool   `json:"encrypted,omitempty"`
+}
+
+// Fields returns the snapshot's attributes
+func (d *RDSSnapshotDetails) Fields() map[string]interface{} {
+	return fieldsOf(d)
+}
+
+// ComputeEngineInstanceDetails are the attributes of a Compute Engine
+// instance. InstanceType is the machine type and the network fields are those
+// of the first network interface.
+type ComputeEngineInstanceDetails struct {
+	InstanceType       string     `json:"instanceType"`
+	Zone               string     `json:"zone"`
+	InstanceID         string     `json:"instanceId"`
+	StatusMessage      string     `json:"statusMessage,omitempty"`
+	CreationTime       *time.Time `json:"creationTime,omitempty"`
+	LaunchTime         *time.Time `json:"launchTime,omitempty"`
+	DeletionProtection bool       `json:"deletionProtection,omitempty"`
+	Spot               bool       `json:"spot,omitempty"`
+	Network            string     `json:"network,omitempty"`
+	Subnetwork         string     `json:"subnetwork,omitempty"`
+	PrivateIPAddress   string     `json:"privateIpAddress,omitempty"`
+	PublicIPAddress    string     `json:"publicIpAddress,omitempty"`
+}
+
+// Fields returns the instance's attributes
+func (d *ComputeEngineInstanceDetails) Fields() map[string]interface{} {
+	return fieldsOf(d)
+}
+
+// AzureVMDetails are the attributes of an Azure virtual machine. InstanceType
+// is the VM size.
+type AzureVMDetails struct {
+	InstanceType      string     `json:"instanceType"`
+	ResourceGroup     string     `json:"resourceGroup"`
+	Location          string     `json:"location"`
+	ResourceID        string     `json:"resourceId"`
+	VMID              string     `json:"vmId"`
+	ProvisioningState string     `json:"provisioningState"`
+	OSType            string     `json:"osType,omitempty"`
+	Zones             []string   `json:"zones,omitempty"`
+	Spot              bool       `json:"spot,omitempty"`
+	CreationTime      *time.Time `json:"creationTime,omitempty"`
+}
+
+// Fields returns the VM's attributes
+func (d *AzureVMDetails) Fields() map[string]interface{} {
+	return fieldsOf(d)
+}
+
+// InstanceType returns the size of a compute instance of any provider, empty
+// for other resources
+func (r CloudResource) InstanceType() string {
+	switch d := r.Details.(type) {
+	case *EC2InstanceDetails:
+		return d.InstanceType
+	case *ComputeEngineInstanceDetails:
+		return d.InstanceType
+	case *AzureVMDetails:
+		return d.InstanceType
+	}
+	return ""
+}
+
+// PublicIPAddress returns the public IP address of a compute instance of any
+// provider, empty when it has none
+func (r CloudResource) PublicIPAddress() string {
+	switch d := r.Details.(type) {
+	case *EC2InstanceDetails:
+		return d.PublicIPAddress
+	case *ComputeEngineInstanceDetails:
+		return d.PublicIPAddress
+	}
+	return ""
+}
+
+// PrivateIPAddress returns the private IP address of a compute instance of
+// any provider, empty when it is not known
+func (r CloudResource) PrivateIPAddress() string {
+	switch d := r.Details.(type) {
+	case *EC2InstanceDetails:
+		return d.PrivateIPAddress
+	case *ComputeEngineInstanceDetails:
+		return d.PrivateIPAddress
+	}
+	return ""
+}
+
+// EC2Instance returns the details of an EC2 instance, empty ones when the
+// resource is not one
+func (r CloudResource) EC2Instance() EC2InstanceDetails {
+	if d, ok := r.Details.(*EC2InstanceDetails); ok && d != nil {
+		return *d
+	}
+	return EC2InstanceDetails{}
+}
+
+// EBSVolume returns the details of an EBS volume, empty ones when the
+// resource is not one
+func (r CloudResource) EBSVolume() EBSVolumeDetails {
+	if d, ok := r.Details.(*EBSVolumeDetails); ok && d != nil {
+		return *d
+	}
+	return EBSVolumeDetails{}
+}
+
+// EBSSnapshot returns the details of an EBS snapshot, empty ones when the
+// resource is not one
+func (r CloudResource) EBSSnapshot() EBSSnapshotDetails {
+	if d, ok := r.Details.(*EBSSnapshotDetails); ok && d != nil {
+		return *d
+	}
+	return EBSSnapshotDetails{}
+}
+
+// RDSInstance returns the details of an RDS DB instance, empty ones when the
+// resource is not one
+func (r CloudResource) RDSInstance() RDSInstanceDetails {
+	if d, ok := r.Details.(*RDSInstanceDetails); ok && d != nil {
+		return *d
+	}
+	return RDSInstanceDetails{}
+}
+
+// RDSSnapshot returns the details of an RDS snapshot, empty ones when the
+// resource is not one
+func (r CloudResource) RDSSnapshot() RDSSnapshotDetails {
+	if d, ok := r.Details.(*RDSSnapshotDetails); ok && d != nil {
+		return *d
+	}
+	return RDSSnapshotDetails{}
+}
+
+// AzureVM returns the details of an Azure virtual machine, empty ones when
+// the resource is not one
+func (r CloudResource) AzureVM() AzureVMDetails {
+	if d, ok := r.Details.(*AzureVMDetails); ok && d != nil {
+		return *d
+	}
+	return AzureVMDetails{}
+}
+
+// detailsOf returns empty details of the implementation that resources of a
+// type hold, nil for types without details
+func detailsOf(resourceType string) ResourceDetails {
+	switch resourceType {
+	case "ec2-instance":
+		return &EC2InstanceDetails{}
+	case "ebs-volume":
+		return &EBSVolumeDetails{}
+	case "ebs-snapshot":
+		return &EBSSnapshotDetails{}
+	case "rds-instance":
+		return &RDSInstanceDetails{}
+	case "rds-snapshot":
+		return &RDSSnapshotDetails{}
+	case "compute-instance":
+		return &ComputeEngineInstanceDetails{}
+	case "virtual-machine":
+		return &AzureVMDetails{}
+	}
+	return nil
+}
+
+// UnmarshalJSON decodes a resource, decoding its details into the
+// implementation of its type so resources saved as JSON read back like
+// those listed from the provider
+func (r *CloudResource) UnmarshalJSON(data []byte) error {
+	// resource has the fields but not the methods of CloudResource
+	type resource CloudResource
+	var decoded struct {
+		resource
+		Details json.RawMessage `json:"details"`
+	}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+
+	*r = CloudResource(decoded.resource)
+	r.Details = detailsOf(r.Type)
+	if r.Details == nil || len(decoded.Details) == 0 || string(decoded.Details) == "null" {
+		r.Details = nil
+		return nil
+	}
+	return json.Unmarshal(decoded.Details, r.Details)
+}
+
+// fieldsOf returns the JSON fields of a details struct, leaving out empty
+// omitempty fields the way encoding/json does
+func fieldsOf(details interface{}) map[string]interface{} {
+	v := reflect.ValueOf(details)
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return map[string]interface{}{}
+		}
+		v = v.Elem()
+	}
+
+	fields := make(map[string]interface{}, v.NumField())
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		name, options, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if !field.IsExported() || name == "" || name == "-" {
+			continue
+		}
+		value := v.Field(i)
+		if options == "omitempty" && isEmptyValue(value) {
+			continue
+		}
+		fields[name] = value.Interface()
+	}
+	return fields
+}
+
+// isEmptyValue reports whether encoding/json treats a value as empty
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool:
+		return !v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return v.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return v.Float() == 0
+	case reflect.Interface, reflect.Pointer:
+		return v.IsNil()
+	}
+	return false
+}
@@ -0,0 +1,47 @@
+package types
+
+import "time"
+
+// ECRRepository is an ECR repository and how its images are scanned
+type ECRRepository struct {
+	Name           string    `json:"name"`
+	ARN            string    `json:"arn"`
+	URI            string    `json:"uri"`
+	CreatedAt      time.Time `json:"createdAt"`
+	TagMutability  string    `json:"tagMutability"`
+	ScanOnPush     bool      `json:"scanOnPush"`
+	EncryptionType string    `json:"encryptionType,omitempty"`
+}
+
+// ECRImage is an image in a repository with the summary of its latest scan.
+// FindingCounts is keyed by severity, e.g. CRITICAL or HIGH.
+type ECRImage struct {
+	Digest        string           `json:"digest"`
+	Tags          []string         `json:"tags,omitempty"`
+	PushedAt      time.Time        `json:"pushedAt"`
+	LastPulledAt  *time.Time       `json:"lastPulledAt,omitempty"`
+	SizeBytes     int64            `json:"sizeBytes"`
+	ScanStatus    string           `json:"scanStatus,omitempty"`
+	ScannedAt     *time.Time       `json:"scannedAt,omitempty"`
+	FindingCounts map[string]int32 `json:"findingCounts,omitempty"`
+}
+
+// ECRScanFinding is one vulnerability found in an image
+type ECRScanFinding struct {
+	Name           string `json:"name"`
+	Severity       string `json:"severity"`
+	Description    string `json:"description,omitempty"`
+	URI            string `json:"uri,omitempty"`
+	Package        string `json:"package,omitempty"`
+	PackageVersion string `json:"packageVersion,omitempty"`
+	FixedIn        string `json:"fixedIn,omitempty"`
+}
+
+// ECRScanFindings are the findings of the latest scan of an image
+type ECRScanFindings struct {
+	Digest        string           `json:"digest"`
+	ScanStatus    string           `json:"scanStatus"`
+	ScannedAt     *time.Time       `json:"scannedAt,omitempty"`
+	FindingCounts map[string]int32 `json:"findingCounts,omitempty"`
+	Findings      []ECRScanFinding `json:"findings"`
+}
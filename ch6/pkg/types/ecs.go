@@ -0,0 +1,81 @@
+package types
+
+import "time"
+
+// ECSCluster is an ECS cluster with its service and task counts
+type ECSCluster struct {
+	Name               string   `json:"name"`
+	ARN                string   `json:"arn"`
+	Status             string   `json:"status"`
+	ActiveServices     int32    `json:"activeServices"`
+	RunningTasks       int32    `json:"runningTasks"`
+	PendingTasks       int32    `json:"pendingTasks"`
+	ContainerInstances int32    `json:"containerInstances"`
+	CapacityProviders  []string `json:"capacityProviders,omitempty"`
+}
+
+// ECSService is an ECS service with its deployments and latest events
+type ECSService struct {
+	Name           string            `json:"name"`
+	ARN            string            `json:"arn"`
+	Cluster        string            `json:"cluster"`
+	Status         string            `json:"status"`
+	LaunchType     string            `json:"launchType,omitempty"`
+	TaskDefinition string            `json:"taskDefinition"`
+	DesiredCount   int32             `json:"desiredCount"`
+	RunningCount   int32             `json:"runningCount"`
+	PendingCount   int32             `json:"pendingCount"`
+	CreatedAt      time.Time         `json:"createdAt"`
+	Deployments    []ECSDeployment   `json:"deployments"`
+	Events         []ECSServiceEvent `json:"events,omitempty"`
+}
+
+// ECSDeployment is one deployment of a service. The PRIMARY deployment is the
+// one the service is rolling out or running.
+type ECSDeployment struct {
+	ID                 string    `json:"id"`
+	Status             string    `json:"status"`
+	RolloutState       string    `json:"rolloutState,omitempty"`
+	RolloutStateReason string    `json:"rolloutStateReason,omitempty"`
+	TaskDefinition     string    `json:"taskDefinition"`
+	DesiredCount       int32     `json:"desiredCount"`
+	RunningCount       int32     `json:"runningCount"`
+	PendingCount       int32     `json:"pendingCount"`
+	FailedTasks        int32     `json:"failedTasks"`
+	CreatedAt          time.Time `json:"createdAt"`
+	UpdatedAt          time.Time `json:"updatedAt"`
+}
+
+// ECSServiceEvent is a message from the ECS service scheduler
+type ECSServiceEvent struct {
+	Time    time.Time `json:"time"`
+	Message string    `json:"message"`
+}
+
+// ECSTask is a running or recently stopped task of a service
+type ECSTask struct {
+	ID             string         `json:"id"`
+	ARN            string         `json:"arn"`
+	Group          string         `json:"group"`
+	TaskDefinition string         `json:"taskDefinition"`
+	LastStatus     string         `json:"lastStatus"`
+	DesiredStatus  string         `json:"desiredStatus"`
+	HealthStatus   string         `json:"healthStatus,omitempty"`
+	LaunchType     string         `json:"launchType,omitempty"`
+	StartedAt      *time.Time     `json:"startedAt,omitempty"`
+	StoppedAt      *time.Time     `json:"stoppedAt,omitempty"`
+	StopCode       string         `json:"stopCode,omitempty"`
+	StoppedReason  string         `json:"stoppedReason,omitempty"`
+	Containers     []ECSContainer `json:"containers"`
+}
+
+// ECSContainer is one container of a task
+type ECSContainer struct {
+	Name         string `json:"name"`
+	Image        string `json:"image,omitempty"`
+	ImageDigest  string `json:"imageDigest,omitempty"`
+	LastStatus   string `json:"lastStatus"`
+	HealthStatus string `json:"healthStatus,omitempty"`
+	ExitCode     *int32 `json:"exitCode,omitempty"`
+	Reason       string `json:"reason,omitempty"`
+}
@@ -0,0 +1,39 @@
+package types
+
+import "time"
+
+// FileSystem is an EFS file system. ThroughputMode is bursting, provisioned
+// or elastic; ProvisionedMiBps is only set in provisioned mode. Metrics is nil
+// when CloudWatch could not be read.
+type FileSystem struct {
+	ID               string             `json:"id"`
+	Name             string             `json:"name,omitempty"`
+	State            string             `json:"state"`
+	PerformanceMode  string             `json:"performanceMode"`
+	ThroughputMode   string             `json:"throughputMode"`
+	ProvisionedMiBps float64            `json:"provisionedMiBps,omitempty"`
+	SizeBytes        int64              `json:"sizeBytes"`
+	Encrypted        bool               `json:"encrypted"`
+	Created          time.Time          `json:"created"`
+	MountTargets     []MountTarget      `json:"mountTargets"`
+	Metrics          *FileSystemMetrics `json:"metrics,omitempty"`
+}
+
+// MountTarget is the network endpoint of a file system in one subnet
+type MountTarget struct {
+	ID               string `json:"id"`
+	SubnetID         string `json:"subnetId"`
+	AvailabilityZone string `json:"availabilityZone"`
+	IPAddress        string `json:"ipAddress"`
+	State            string `json:"state"`
+}
+
+// FileSystemMetrics are the latest CloudWatch metrics of a file system.
+// BurstCreditBytes is the burst credit balance, PermittedBytesPerSecond the
+// throughput the file system is allowed now and PercentIOLimit how close a
+// General Purpose file system is to its I/O limit.
+type FileSystemMetrics struct {
+	BurstCreditBytes        float64 `json:"burstCreditBytes"`
+	PermittedBytesPerSecond float64 `json:"permittedBytesPerSecond"`
+	PercentIOLimit          float64 `json:"percentIOLimit"`
+}
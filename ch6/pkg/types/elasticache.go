@@ -0,0 +1,57 @@
+package types
+
+import "time"
+
+// CacheCluster is an ElastiCache cluster (a Redis OSS, Valkey or Memcached
+// node group) with its nodes and pending maintenance
+type CacheCluster struct {
+	ID                 string      `json:"id"`
+	ARN                string      `json:"arn"`
+	Engine             string      `json:"engine"`
+	EngineVersion      string      `json:"engineVersion"`
+	NodeType           string      `json:"nodeType"`
+	Status             string      `json:"status"`
+	ReplicationGroupID string      `json:"replicationGroupId,omitempty"`
+	ClusterModeEnabled bool        `json:"clusterModeEnabled"`
+	AvailabilityZone   string      `json:"availabilityZone"`
+	Nodes              []CacheNode `json:"nodes"`
+	// MaintenanceWindow is the weekly window in UTC, e.g. sun:23:00-mon:01:30
+	MaintenanceWindow       string    `json:"maintenanceWindow"`
+	SnapshotWindow          string    `json:"snapshotWindow,omitempty"`
+	SnapshotRetention       int32     `json:"snapshotRetention"`
+	AutoMinorVersionUpgrade bool      `json:"autoMinorVersionUpgrade"`
+	TransitEncryption       bool      `json:"transitEncryption"`
+	AtRestEncryption        bool      `json:"atRestEncryption"`
+	CreatedAt               time.Time `json:"createdAt"`
+	// PendingChanges are modifications applied in the next maintenance window,
+	// e.g. nodeType or engineVersion with their new values
+	PendingChanges map[string]string    `json:"pendingChanges,omitempty"`
+	PendingUpdates []CacheServiceUpdate `json:"pendingUpdates,omitempty"`
+}
+
+// CacheNode is one node of an ElastiCache cluster
+type CacheNode struct {
+	ID                   string    `json:"id"`
+	Status               string    `json:"status"`
+	AvailabilityZone     string    `json:"availabilityZone"`
+	Endpoint             string    `json:"endpoint,omitempty"`
+	ParameterGroupStatus string    `json:"parameterGroupStatus"`
+	CreatedAt            time.Time `json:"createdAt"`
+}
+
+// CacheServiceUpdate is an ElastiCache service update, such as a security
+// patch, not yet applied to a cluster
+type CacheServiceUpdate struct {
+	Name     string    `json:"name"`
+	Type     string    `json:"type"`
+	Severity string    `json:"severity"`
+	Status   string    `json:"status"`
+	ApplyBy  time.Time `json:"applyBy,omitempty"`
+}
+
+// CacheEvent is an ElastiCache event such as a node restart or failover
+type CacheEvent struct {
+	Time     time.Time `json:"time"`
+	SourceID string    `json:"sourceId"`
+	Message  string    `json:"message"`
+}
@@ -0,0 +1,23 @@
+package types
+
+import "time"
+
+// AlarmStateChange is a CloudWatch alarm moving between states
+type AlarmStateChange struct {
+	Time      time.Time `json:"time"`
+	AlarmName string    `json:"alarmName"`
+	OldState  string    `json:"oldState,omitempty"`
+	NewState  string    `json:"newState,omitempty"`
+	Summary   string    `json:"summary"`
+	Reason    string    `json:"reason,omitempty"`
+}
+
+// ChangeEvent is a write API call recorded by CloudTrail
+type ChangeEvent struct {
+	Time        time.Time `json:"time"`
+	EventName   string    `json:"eventName"`
+	EventSource string    `json:"eventSource"`
+	Username    string    `json:"username,omitempty"`
+	Resources   []string  `json:"resources,omitempty"`
+	ErrorCode   string    `json:"errorCode,omitempty"`
+}
@@ -0,0 +1,99 @@
+package types
+
+import (
+	"time"
+)
+
+// AccessKey represents an IAM user access key and when it was last used
+type AccessKey struct {
+	ID              string     `json:"id"`
+	Status          string     `json:"status"`
+	CreateDate      time.Time  `json:"createDate"`
+	LastUsed        *time.Time `json:"lastUsed,omitempty"`
+	LastUsedService string     `json:"lastUsedService,omitempty"`
+}
+
+// IAMUser represents an IAM user with the credential details needed for hygiene checks
+type IAMUser struct {
+	UserName         string      `json:"userName"`
+	ARN              string      `json:"arn"`
+	CreateDate       time.Time   `json:"createDate"`
+	PasswordLastUsed *time.Time  `json:"passwordLastUsed,omitempty"`
+	ConsoleAccess    bool        `json:"consoleAccess"`
+	MFAEnabled       bool        `json:"mfaEnabled"`
+	AccessKeys       []AccessKey `json:"accessKeys"`
+}
+
+// IsActive reports whether the access key can still be used to sign requests
+func (k AccessKey) IsActive() bool {
+	return k.Status == "Active"
+}
+
+// IAMPolicyRef is a managed policy attached to a user, group or role
+type IAMPolicyRef struct {
+	Name string `json:"name"`
+	ARN  string `json:"arn"`
+}
+
+// IAMInlinePolicy is a policy embedded in a user, group or role with its JSON document
+type IAMInlinePolicy struct {
+	Name     string `json:"name"`
+	Document string `json:"document"`
+}
+
+// IAMRole is an IAM role with its trust policy, which says who may assume it,
+// and the policies granting its permissions
+type IAMRole struct {
+	RoleName            string            `json:"roleName"`
+	ARN                 string            `json:"arn"`
+	Path                string            `json:"path"`
+	CreateDate          time.Time         `json:"createDate"`
+	LastUsed            *time.Time        `json:"lastUsed,omitempty"`
+	LastUsedRegion      string            `json:"lastUsedRegion,omitempty"`
+	TrustPolicy         string            `json:"trustPolicy"`
+	PermissionsBoundary string            `json:"permissionsBoundary,omitempty"`
+	AttachedPolicies    []IAMPolicyRef    `json:"attachedPolicies"`
+	InlinePolicies      []IAMInlinePolicy `json:"inlinePolicies"`
+	InstanceProfiles    []string          `json:"instanceProfiles,omitempty"`
+	Tags                map[string]string `json:"tags,omitempty"`
+}
+
+// IAMGroup is an IAM group with the policies its members get
+type IAMGroup struct {
+	GroupName        string            `json:"groupName"`
+	ARN              string            `json:"arn"`
+	AttachedPolicies []IAMPolicyRef    `json:"attachedPolicies"`
+	InlinePolicies   []IAMInlinePolicy `json:"inlinePolicies"`
+}
+
+// IAMUserPermissions is an IAM user with the policies attached to it directly
+// and through its groups
+type IAMUserPermissions struct {
+	UserName            string            `json:"userName"`
+	ARN                 string            `json:"arn"`
+	Path                string            `json:"path"`
+	CreateDate          time.Time         `json:"createDate"`
+	PermissionsBoundary string            `json:"permissionsBoundary,omitempty"`
+	AttachedPolicies    []IAMPolicyRef    `json:"attachedPolicies"`
+	InlinePolicies      []IAMInlinePolicy `json:"inlinePolicies"`
+	Groups              []IAMGroup        `json:"groups"`
+	Tags                map[string]string `json:"tags,omitempty"`
+}
+
+// IAMPolicy is a managed policy with the document of its default version and
+// the users, groups and roles it is attached to
+type IAMPolicy struct {
+	ARN             string    `json:"arn"`
+	Name            string    `json:"name"`
+	Path            string    `json:"path"`
+	Description     string    `json:"description,omitempty"`
+	AWSManaged      bool      `json:"awsManaged"`
+	DefaultVersion  string    `json:"defaultVersion"`
+	AttachmentCount int32     `json:"attachmentCount"`
+	CreateDate      time.Time `json:"createDate"`
+	UpdateDate      time.Time `json:"updateDate"`
+	Document        string    `json:"document"`
+	Users           []string  `json:"users"`
+	Groups          []string  `json:"groups"`
+	Roles           []string  `json:"roles"`
+}
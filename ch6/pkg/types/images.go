@@ -0,0 +1,15 @@
+package types
+
+import "time"
+
+// MachineImage represents an AMI owned by the account
+type MachineImage struct {
+	ID          string            `json:"id"`
+	Name        string            `json:"name"`
+	State       string            `json:"state"`
+	CreatedAt   time.Time         `json:"createdAt"`
+	Public      bool              `json:"public"`
+	SnapshotIDs []string          `json:"snapshotIds,omitempty"`
+	SizeGiB     int32             `json:"sizeGiB"`
+	Tags        map[string]string `json:"tags,omitempty"`
+}
@@ -0,0 +1,33 @@
+package types
+
+import "time"
+
+// KinesisStream is a Kinesis data stream with its recent throughput metrics.
+// Mode is PROVISIONED or ON_DEMAND.
+type KinesisStream struct {
+	Name           string    `json:"name"`
+	ARN            string    `json:"arn"`
+	Status         string    `json:"status"`
+	Mode           string    `json:"mode"`
+	OpenShards     int32     `json:"openShards"`
+	Consumers      int32     `json:"consumers"`
+	RetentionHours int32     `json:"retentionHours"`
+	Encryption     string    `json:"encryption"`
+	CreatedAt      time.Time `json:"createdAt"`
+	// Metrics is nil when CloudWatch could not be read for the stream
+	Metrics *KinesisStreamMetrics `json:"metrics,omitempty"`
+}
+
+// KinesisStreamMetrics are the stream's CloudWatch metrics over the metrics
+// window. Throttled counts are the records rejected for exceeding the shard
+// limits; iterator age is how far the slowest consumer lags behind.
+type KinesisStreamMetrics struct {
+	WindowMinutes        int     `json:"windowMinutes"`
+	IteratorAgeMs        float64 `json:"iteratorAgeMs"`
+	IncomingBytes        float64 `json:"incomingBytes"`
+	IncomingRecords      float64 `json:"incomingRecords"`
+	WriteThrottled       float64 `json:"writeThrottled"`
+	ReadThrottled        float64 `json:"readThrottled"`
+	PeakBytesPerSecond   float64 `json:"peakBytesPerSecond"`
+	PeakRecordsPerSecond float64 `json:"peakRecordsPerSecond"`
+}
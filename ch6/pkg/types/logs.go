@@ -0,0 +1,23 @@
+package types
+
+import "time"
+
+// LogEvent represents a single CloudWatch Logs event
+type LogEvent struct {
+	Timestamp time.Time `json:"timestamp"`
+	LogGroup  string    `json:"logGroup"`
+	LogStream string    `json:"logStream"`
+	Message   string    `json:"message"`
+}
+
+// InsightsResult is the outcome of a CloudWatch Logs Insights query. Rows map
+// field names to values in the order the query returned them.
+type InsightsResult struct {
+	QueryID        string              `json:"queryId"`
+	Status         string              `json:"status"`
+	Fields         []string            `json:"fields"`
+	Rows           []map[string]string `json:"rows"`
+	RecordsMatched float64             `json:"recordsMatched"`
+	RecordsScanned float64             `json:"recordsScanned"`
+	BytesScanned   float64             `json:"bytesScanned"`
+}
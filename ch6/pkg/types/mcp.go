@@ -19,13 +19,27 @@ type ResourceInfo struct {
 	Metadata    map[string]string `json:"metadata,omitempty"`
 }
 
-// AWSResource represents AWS infrastructure resources
-type AWSResource struct {
-	ID       string                 `json:"id"`
-	Type     string                 `json:"type"`
-	Region   string                 `json:"region"`
-	State    string                 `json:"state"`
-	Tags     map[string]string      `json:"tags,omitempty"`
-	Details  map[string]interface{} `json:"details"`
-	LastSeen time.Time              `json:"lastSeen"`
+// CloudResource represents an infrastructure resource of any cloud provider.
+// Type is provider-specific (ec2-instance, rds-instance) and decides which
+// ResourceDetails implementation Details holds; compute instances of every
+// provider report their size through InstanceType so handlers can summarize
+// them without knowing the provider.
+type CloudResource struct {
+	ID       string            `json:"id"`
+	Provider string            `json:"provider"`
+	Type     string            `json:"type"`
+	Region   string            `json:"region"`
+	State    string            `json:"state"`
+	Tags     map[string]string `json:"tags,omitempty"`
+	Details  ResourceDetails   `json:"details"`
+	LastSeen time.Time         `json:"lastSeen"`
+}
+
+// InstanceStatus is an EC2 instance's state and the results of its status
+// checks: ok, impaired, initializing, insufficient-data or not-applicable
+type InstanceStatus struct {
+	InstanceID     string `json:"instanceId"`
+	State          string `json:"state"`
+	SystemStatus   string `json:"systemStatus"`
+	InstanceStatus string `json:"instanceStatus"`
 }
@@ -0,0 +1,39 @@
+package types
+
+import "time"
+
+// MetricDatapoint is a single value of a CloudWatch metric statistic
+type MetricDatapoint struct {
+	Timestamp time.Time `json:"timestamp"`
+	Value     float64   `json:"value"`
+}
+
+// MetricData is a CloudWatch metric time series, oldest datapoint first
+type MetricData struct {
+	Label      string            `json:"label"`
+	StatusCode string            `json:"statusCode"`
+	Datapoints []MetricDatapoint `json:"datapoints"`
+	Messages   []string          `json:"messages,omitempty"`
+}
+
+// Alarm is a CloudWatch metric or composite alarm and its current state.
+// Metric fields are empty for composite alarms, which have a Rule instead.
+type Alarm struct {
+	Name               string            `json:"name"`
+	ARN                string            `json:"arn"`
+	Type               string            `json:"type"`
+	Description        string            `json:"description,omitempty"`
+	State              string            `json:"state"`
+	StateReason        string            `json:"stateReason,omitempty"`
+	StateUpdated       time.Time         `json:"stateUpdated"`
+	Namespace          string            `json:"namespace,omitempty"`
+	MetricName         string            `json:"metricName,omitempty"`
+	Dimensions         map[string]string `json:"dimensions,omitempty"`
+	Statistic          string            `json:"statistic,omitempty"`
+	ComparisonOperator string            `json:"comparisonOperator,omitempty"`
+	Threshold          *float64          `json:"threshold,omitempty"`
+	Period             int32             `json:"period,omitempty"`
+	EvaluationPeriods  int32             `json:"evaluationPeriods,omitempty"`
+	Rule               string            `json:"rule,omitempty"`
+	ActionsEnabled     bool              `json:"actionsEnabled"`
+}
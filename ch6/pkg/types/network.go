@@ -0,0 +1,244 @@
+package types
+
+import "time"
+
+// SecurityGroupRule represents a single ingress permission of a security group
+type SecurityGroupRule struct {
+	Protocol     string   `json:"protocol"`
+	FromPort     int32    `json:"fromPort"`
+	ToPort       int32    `json:"toPort"`
+	CIDRs        []string `json:"cidrs,omitempty"`
+	SourceGroups []string `json:"sourceGroups,omitempty"`
+}
+
+// SecurityGroup represents an EC2 security group and its rules. For egress
+// rules, CIDRs and SourceGroups are the destinations.
+type SecurityGroup struct {
+	ID          string              `json:"id"`
+	Name        string              `json:"name"`
+	Description string              `json:"description,omitempty"`
+	VpcID       string              `json:"vpcId,omitempty"`
+	Ingress     []SecurityGroupRule `json:"ingress"`
+	Egress      []SecurityGroupRule `json:"egress,omitempty"`
+}
+
+// LoadBalancerListener represents a listener port on a load balancer
+type LoadBalancerListener struct {
+	Port     int32  `json:"port"`
+	Protocol string `json:"protocol"`
+}
+
+// LoadBalancer represents an Elastic Load Balancing v2 load balancer
+type LoadBalancer struct {
+	ARN            string                 `json:"arn"`
+	Name           string                 `json:"name"`
+	DNSName        string                 `json:"dnsName"`
+	Type           string                 `json:"type"`
+	Scheme         string                 `json:"scheme"`
+	State          string                 `json:"state"`
+	VpcID          string                 `json:"vpcId,omitempty"`
+	SecurityGroups []string               `json:"securityGroups,omitempty"`
+	Listeners      []LoadBalancerListener `json:"listeners,omitempty"`
+}
+
+// AllowsAllPorts reports whether the rule applies to every protocol and port
+func (r SecurityGroupRule) AllowsAllPorts() bool {
+	return r.Protocol == "-1"
+}
+
+// CoversPort reports whether the rule permits traffic to the given TCP port
+func (r SecurityGroupRule) CoversPort(port int32) bool {
+	if r.AllowsAllPorts() {
+		return true
+	}
+	return r.Protocol == "tcp" && r.FromPort <= port && port <= r.ToPort
+}
+
+// NetworkACLEntry is one numbered rule of a network ACL. Protocol is the IANA
+// number as a string, or -1 for all protocols.
+type NetworkACLEntry struct {
+	RuleNumber int32  `json:"ruleNumber"`
+	Egress     bool   `json:"egress"`
+	Protocol   string `json:"protocol"`
+	FromPort   int32  `json:"fromPort,omitempty"`
+	ToPort     int32  `json:"toPort,omitempty"`
+	CIDR       string `json:"cidr"`
+	Allow      bool   `json:"allow"`
+}
+
+// NetworkACL is the network ACL associated with a subnet
+type NetworkACL struct {
+	ID      string            `json:"id"`
+	Default bool              `json:"default"`
+	Entries []NetworkACLEntry `json:"entries"`
+}
+
+// Route is one route of a route table. Target is the gateway, NAT gateway,
+// peering connection or other resource traffic is sent to, or local.
+type Route struct {
+	Destination string `json:"destination"`
+	Target      string `json:"target"`
+	State       string `json:"state"`
+}
+
+// RouteTable is the route table that applies to a subnet. SubnetIDs are the
+// subnets explicitly associated with it; the main table also applies to every
+// subnet of the VPC without an association.
+type RouteTable struct {
+	ID        string   `json:"id"`
+	VpcID     string   `json:"vpcId,omitempty"`
+	Main      bool     `json:"main"`
+	SubnetIDs []string `json:"subnetIds,omitempty"`
+	Routes    []Route  `json:"routes"`
+}
+
+// InstanceNetwork is everything on the network path to an EC2 instance in its
+// VPC: its security groups, and the network ACL and route table of its subnet
+type InstanceNetwork struct {
+	InstanceID      string          `json:"instanceId"`
+	State           string          `json:"state"`
+	PrivateIP       string          `json:"privateIp,omitempty"`
+	PublicIP        string          `json:"publicIp,omitempty"`
+	VpcID           string          `json:"vpcId"`
+	SubnetID        string          `json:"subnetId"`
+	SecurityGroups  []SecurityGroup `json:"securityGroups"`
+	NetworkACL      NetworkACL      `json:"networkAcl"`
+	RouteTable      RouteTable      `json:"routeTable"`
+	InternetGateway string          `json:"internetGateway,omitempty"`
+}
+
+// ReachabilityAnalysis is the result of a VPC Reachability Analyzer run
+type ReachabilityAnalysis struct {
+	AnalysisID   string   `json:"analysisId"`
+	Status       string   `json:"status"`
+	Message      string   `json:"message,omitempty"`
+	PathFound    bool     `json:"pathFound"`
+	Explanations []string `json:"explanations,omitempty"`
+}
+
+// Target is a target registered with a target group and its health
+type Target struct {
+	ID          string `json:"id"`
+	Port        int32  `json:"port,omitempty"`
+	State       string `json:"state"`
+	Reason      string `json:"reason,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// TargetGroup represents an ELBv2 target group and the health of its targets
+type TargetGroup struct {
+	ARN              string   `json:"arn"`
+	Name             string   `json:"name"`
+	Protocol         string   `json:"protocol,omitempty"`
+	Port             int32    `json:"port,omitempty"`
+	TargetType       string   `json:"targetType"`
+	LoadBalancerARNs []string `json:"loadBalancerArns,omitempty"`
+	Targets          []Target `json:"targets"`
+}
+
+// HealthyCount returns the number of healthy targets in the group
+func (g TargetGroup) HealthyCount() int {
+	count := 0
+	for _, target := range g.Targets {
+		if target.State == "healthy" {
+			count++
+		}
+	}
+	return count
+}
+
+// VPC is a virtual private cloud with its IPv4 and IPv6 CIDR blocks
+type VPC struct {
+	ID      string   `json:"id"`
+	Name    string   `json:"name,omitempty"`
+	CIDRs   []string `json:"cidrs"`
+	Default bool     `json:"default"`
+	State   string   `json:"state"`
+}
+
+// Subnet is a range of a VPC in one availability zone
+type Subnet struct {
+	ID               string `json:"id"`
+	Name             string `json:"name,omitempty"`
+	VpcID            string `json:"vpcId"`
+	CIDR             string `json:"cidr"`
+	AvailabilityZone string `json:"availabilityZone"`
+	AvailableIPs     int32  `json:"availableIps"`
+	MapPublicIP      bool   `json:"mapPublicIp"`
+}
+
+// NATGateway is a managed NAT gateway. Public gateways have an elastic IP and
+// reach the internet through the internet gateway of their subnet's VPC.
+// Addresses counts the primary and secondary IPs, each of which allows about
+// 55,000 connections to one destination. Metrics is only read for the NAT
+// gateway resource and nil when CloudWatch could not be read.
+type NATGateway struct {
+	ID               string             `json:"id"`
+	Name             string             `json:"name,omitempty"`
+	VpcID            string             `json:"vpcId"`
+	SubnetID         string             `json:"subnetId"`
+	State            string             `json:"state"`
+	ConnectivityType string             `json:"connectivityType"`
+	PublicIP         string             `json:"publicIp,omitempty"`
+	Addresses        int                `json:"addresses"`
+	FailureMessage   string             `json:"failureMessage,omitempty"`
+	Metrics          *NATGatewayMetrics `json:"metrics,omitempty"`
+}
+
+// NATGatewayMetrics are the CloudWatch sums of a NAT gateway over the metric
+// window. BytesProcessed is the traffic the gateway received from sources and
+// destinations, which is what NAT data processing is billed on;
+// ErrorPortAllocation counts connections that failed because no source port
+// was free and PacketsDropped the packets the gateway dropped.
+// ActiveConnections is the highest number of concurrent connections.
+type NATGatewayMetrics struct {
+	BytesProcessed      float64 `json:"bytesProcessed"`
+	ErrorPortAllocation float64 `json:"errorPortAllocation"`
+	PacketsDropped      float64 `json:"packetsDropped"`
+	ActiveConnections   float64 `json:"activeConnections"`
+}
+
+// VPCEndpoint is an interface, gateway or Gateway Load Balancer endpoint that
+// reaches an AWS or PrivateLink service without leaving the VPC. Gateway
+// endpoints are reached through RouteTableIDs, the others through network
+// interfaces in SubnetIDs.
+type VPCEndpoint struct {
+	ID                string    `json:"id"`
+	Name              string    `json:"name,omitempty"`
+	VpcID             string    `json:"vpcId"`
+	ServiceName       string    `json:"serviceName"`
+	Type              string    `json:"type"`
+	State             string    `json:"state"`
+	PrivateDNSEnabled bool      `json:"privateDnsEnabled"`
+	SubnetIDs         []string  `json:"subnetIds,omitempty"`
+	RouteTableIDs     []string  `json:"routeTableIds,omitempty"`
+	Created           time.Time `json:"created"`
+}
+
+// InternetGateway is an internet gateway and the VPCs it is attached to
+type InternetGateway struct {
+	ID     string   `json:"id"`
+	VpcIDs []string `json:"vpcIds"`
+}
+
+// VPCTopology is every VPC of a region with the subnets, route tables and
+// gateways that decide where their traffic goes
+type VPCTopology struct {
+	VPCs             []VPC             `json:"vpcs"`
+	Subnets          []Subnet          `json:"subnets"`
+	RouteTables      []RouteTable      `json:"routeTables"`
+	NATGateways      []NATGateway      `json:"natGateways"`
+	InternetGateways []InternetGateway `json:"internetGateways"`
+}
+
+// ElasticIP represents an Elastic IP address allocated to the account. An
+// address without an AssociationID is billed while doing nothing.
+type ElasticIP struct {
+	AllocationID       string            `json:"allocationId"`
+	PublicIP           string            `json:"publicIp"`
+	Domain             string            `json:"domain"`
+	AssociationID      string            `json:"associationId,omitempty"`
+	InstanceID         string            `json:"instanceId,omitempty"`
+	NetworkInterfaceID string            `json:"networkInterfaceId,omitempty"`
+	Tags               map[string]string `json:"tags,omitempty"`
+}
@@ -0,0 +1,78 @@
+package types
+
+import "time"
+
+// ManagedInstance is an instance registered with Systems Manager and the
+// state of its SSM agent
+type ManagedInstance struct {
+	InstanceID      string     `json:"instanceId"`
+	ComputerName    string     `json:"computerName,omitempty"`
+	PingStatus      string     `json:"pingStatus"`
+	LastPingAt      *time.Time `json:"lastPingAt,omitempty"`
+	AgentVersion    string     `json:"agentVersion"`
+	LatestAgent     bool       `json:"latestAgent"`
+	PlatformType    string     `json:"platformType"`
+	PlatformName    string     `json:"platformName,omitempty"`
+	PlatformVersion string     `json:"platformVersion,omitempty"`
+	IPAddress       string     `json:"ipAddress,omitempty"`
+}
+
+// InstalledApplication is a package in the SSM Inventory of an instance
+type InstalledApplication struct {
+	Name          string `json:"name"`
+	Version       string `json:"version,omitempty"`
+	Publisher     string `json:"publisher,omitempty"`
+	Architecture  string `json:"architecture,omitempty"`
+	InstalledTime string `json:"installedTime,omitempty"`
+}
+
+// InstancePatchState is the Patch Manager compliance summary of an instance
+// from its last Scan or Install operation
+type InstancePatchState struct {
+	InstanceID            string     `json:"instanceId"`
+	PatchGroup            string     `json:"patchGroup,omitempty"`
+	BaselineID            string     `json:"baselineId"`
+	Operation             string     `json:"operation"`
+	OperationEndTime      time.Time  `json:"operationEndTime"`
+	InstalledCount        int32      `json:"installedCount"`
+	MissingCount          int32      `json:"missingCount"`
+	FailedCount           int32      `json:"failedCount"`
+	PendingRebootCount    int32      `json:"pendingRebootCount"`
+	CriticalNonCompliant  int32      `json:"criticalNonCompliant"`
+	SecurityNonCompliant  int32      `json:"securityNonCompliant"`
+	RebootOption          string     `json:"rebootOption,omitempty"`
+	LastNoRebootInstallAt *time.Time `json:"lastNoRebootInstallAt,omitempty"`
+}
+
+// PatchInfo is one patch and its state on an instance
+type PatchInfo struct {
+	Title          string     `json:"title"`
+	KBID           string     `json:"kbId"`
+	Classification string     `json:"classification,omitempty"`
+	Severity       string     `json:"severity,omitempty"`
+	State          string     `json:"state"`
+	InstalledTime  *time.Time `json:"installedTime,omitempty"`
+	CVEIDs         []string   `json:"cveIds,omitempty"`
+}
+
+// MaintenanceWindow is an SSM maintenance window with the targets it runs on.
+// PatchTasks is set when one of its tasks runs a patch baseline.
+type MaintenanceWindow struct {
+	ID            string                    `json:"id"`
+	Name          string                    `json:"name"`
+	Enabled       bool                      `json:"enabled"`
+	Schedule      string                    `json:"schedule"`
+	Timezone      string                    `json:"timezone,omitempty"`
+	DurationHours int32                     `json:"durationHours"`
+	CutoffHours   int32                     `json:"cutoffHours"`
+	NextExecution *time.Time                `json:"nextExecution,omitempty"`
+	Targets       []MaintenanceWindowTarget `json:"targets"`
+	PatchTasks    bool                      `json:"patchTasks"`
+}
+
+// MaintenanceWindowTarget selects instances by key, e.g. InstanceIds or
+// tag:Environment, and the values to match
+type MaintenanceWindowTarget struct {
+	Key    string   `json:"key"`
+	Values []string `json:"values"`
+}
@@ -0,0 +1,22 @@
+package types
+
+// RedshiftResult is the outcome of a statement run with the Redshift Data
+// API. Rows map column names to values, NULL being an empty string; Columns
+// keeps the order of the SELECT list. TotalRows is the size of the whole
+// result, of which Rows may be the first part.
+type RedshiftResult struct {
+	StatementID string              `json:"statementId"`
+	State       string              `json:"state"`
+	Error       string              `json:"error,omitempty"`
+	Columns     []string            `json:"columns"`
+	Rows        []map[string]string `json:"rows"`
+	Truncated   bool                `json:"truncated,omitempty"`
+	TotalRows   int64               `json:"totalRows"`
+	DurationMs  int64               `json:"durationMs"`
+}
+
+// Done reports whether the statement stopped running, whether it finished,
+// failed or was aborted
+func (r *RedshiftResult) Done() bool {
+	return r.State == "FINISHED" || r.State == "FAILED" || r.State == "ABORTED"
+}
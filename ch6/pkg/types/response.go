@@ -0,0 +1,60 @@
+package types
+
+import "encoding/json"
+
+// ResponseSchemaVersion is the version of the envelope of tool responses.
+// Bump it whenever an envelope field is renamed, removed or changes meaning,
+// so prompts and clients reading the envelope can tell; adding an optional
+// field does not need a new version.
+//
+// Version 1: success, message or error, warnings, timestamp
+const ResponseSchemaVersion = 1
+
+// ToolResponse is the envelope of every tool response. Success responses
+// carry a Message, failed and blocked ones an Error; Warnings are non-fatal
+// caveats and always present, empty when there are none. Fields holds the
+// tool's own data, written next to the envelope fields, which win when a
+// name is taken by both.
+type ToolResponse struct {
+	SchemaVersion int
+	Success       bool
+	Message       string
+	Error         string
+	Warnings      []string
+	Timestamp     string
+	Fields        map[string]interface{}
+}
+
+// NewToolResponse creates an envelope of the current schema version
+func NewToolResponse(success bool, timestamp string, fields map[string]interface{}) ToolResponse {
+	return ToolResponse{
+		SchemaVersion: ResponseSchemaVersion,
+		Success:       success,
+		Timestamp:     timestamp,
+		Fields:        fields,
+	}
+}
+
+// MarshalJSON writes the tool's fields and the envelope as one object
+func (r ToolResponse) MarshalJSON() ([]byte, error) {
+	data := make(map[string]interface{}, len(r.Fields)+6)
+	for key, value := range r.Fields {
+		data[key] = value
+	}
+
+	warnings := r.Warnings
+	if warnings == nil {
+		warnings = []string{}
+	}
+	data["schemaVersion"] = r.SchemaVersion
+	data["success"] = r.Success
+	data["warnings"] = warnings
+	data["timestamp"] = r.Timestamp
+	if r.Message != "" {
+		data["message"] = r.Message
+	}
+	if r.Error != "" {
+		data["error"] = r.Error
+	}
+	return json.Marshal(data)
+}
@@ -0,0 +1,11 @@
+package types
+
+import "time"
+
+// S3Object is an object listed under an S3 prefix
+type S3Object struct {
+	Bucket       string    `json:"bucket"`
+	Key          string    `json:"key"`
+	Size         int64     `json:"size"`
+	LastModified time.Time `json:"lastModified"`
+}
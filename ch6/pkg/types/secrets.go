@@ -0,0 +1,47 @@
+package types
+
+import "time"
+
+// Secret is the metadata of a Secrets Manager secret; its value is never read
+type Secret struct {
+	Name              string              `json:"name"`
+	ARN               string              `json:"arn"`
+	Description       string              `json:"description,omitempty"`
+	KMSKeyID          string              `json:"kmsKeyId,omitempty"`
+	OwningService     string              `json:"owningService,omitempty"`
+	PrimaryRegion     string              `json:"primaryRegion,omitempty"`
+	RotationEnabled   bool                `json:"rotationEnabled"`
+	RotationLambdaARN string              `json:"rotationLambdaArn,omitempty"`
+	RotationDays      int64               `json:"rotationDays,omitempty"`
+	RotationSchedule  string              `json:"rotationSchedule,omitempty"`
+	CreatedAt         time.Time           `json:"createdAt"`
+	LastRotated       *time.Time          `json:"lastRotated,omitempty"`
+	LastChanged       *time.Time          `json:"lastChanged,omitempty"`
+	LastAccessed      *time.Time          `json:"lastAccessed,omitempty"`
+	NextRotation      *time.Time          `json:"nextRotation,omitempty"`
+	DeletedAt         *time.Time          `json:"deletedAt,omitempty"`
+	VersionStages     map[string][]string `json:"versionStages,omitempty"`
+	Tags              map[string]string   `json:"tags,omitempty"`
+}
+
+// RotationConfigured reports whether the secret can be rotated, by its own
+// Lambda function or by the service that manages it
+func (s Secret) RotationConfigured() bool {
+	return s.RotationLambdaARN != "" || s.OwningService != ""
+}
+
+// PendingVersion returns the version labelled AWSPENDING but not AWSCURRENT,
+// which a rotation leaves behind when it did not finish
+func (s Secret) PendingVersion() string {
+	for version, stages := range s.VersionStages {
+		pending, current := false, false
+		for _, stage := range stages {
+			pending = pending || stage == "AWSPENDING"
+			current = current || stage == "AWSCURRENT"
+		}
+		if pending && !current {
+			return version
+		}
+	}
+	return ""
+}
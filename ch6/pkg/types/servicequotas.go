@@ -0,0 +1,37 @@
+package types
+
+import "time"
+
+// ServiceQuota is an applied quota of an AWS service, e.g. the running
+// On-Demand vCPUs of EC2, with its current usage. Usage is nil when the quota
+// has no usage metric or the metric could not be read.
+type ServiceQuota struct {
+	ServiceCode string   `json:"serviceCode"`
+	ServiceName string   `json:"serviceName"`
+	QuotaCode   string   `json:"quotaCode"`
+	QuotaName   string   `json:"quotaName"`
+	Value       float64  `json:"value"`
+	Unit        string   `json:"unit"`
+	Adjustable  bool     `json:"adjustable"`
+	GlobalQuota bool     `json:"globalQuota"`
+	Usage       *float64 `json:"usage,omitempty"`
+}
+
+// QuotaIncreaseRequest is a requested increase of a quota. Status is PENDING
+// or CASE_OPENED while AWS reviews it, then APPROVED, DENIED, NOT_APPROVED,
+// CASE_CLOSED or INVALID_REQUEST.
+type QuotaIncreaseRequest struct {
+	ID           string    `json:"id"`
+	ServiceCode  string    `json:"serviceCode"`
+	QuotaCode    string    `json:"quotaCode"`
+	QuotaName    string    `json:"quotaName"`
+	DesiredValue float64   `json:"desiredValue"`
+	Status       string    `json:"status"`
+	CaseID       string    `json:"caseId,omitempty"`
+	Created      time.Time `json:"created"`
+}
+
+// Open reports whether AWS is still reviewing the request
+func (r QuotaIncreaseRequest) Open() bool {
+	return r.Status == "PENDING" || r.Status == "CASE_OPENED"
+}
@@ -0,0 +1,22 @@
+package types
+
+// SNSTopic is an SNS topic with its subscription counts
+type SNSTopic struct {
+	Name        string `json:"name"`
+	ARN         string `json:"arn"`
+	DisplayName string `json:"displayName,omitempty"`
+	FIFO        bool   `json:"fifo"`
+	Encrypted   bool   `json:"encrypted"`
+	Confirmed   int    `json:"confirmed"`
+	Pending     int    `json:"pending"`
+	Deleted     int    `json:"deleted"`
+}
+
+// SNSSubscription is one endpoint subscribed to a topic. Pending subscriptions
+// have not been confirmed by their endpoint and receive nothing.
+type SNSSubscription struct {
+	ARN      string `json:"arn,omitempty"`
+	Protocol string `json:"protocol"`
+	Endpoint string `json:"endpoint"`
+	Pending  bool   `json:"pending"`
+}
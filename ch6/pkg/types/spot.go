@@ -0,0 +1,30 @@
+package types
+
+import "time"
+
+// SpotRequest is a Spot Instance request. State is open, active, closed,
+// cancelled or failed; StatusCode says why, e.g. fulfilled, or
+// marked-for-termination once AWS has given the instance its two-minute
+// interruption notice. Type is one-time or persistent, and MaxPrice is in USD
+// per hour, empty when it is the On-Demand price.
+type SpotRequest struct {
+	ID                   string     `json:"id"`
+	State                string     `json:"state"`
+	StatusCode           string     `json:"statusCode"`
+	StatusMessage        string     `json:"statusMessage,omitempty"`
+	StatusUpdated        *time.Time `json:"statusUpdated,omitempty"`
+	Type                 string     `json:"type"`
+	InstanceID           string     `json:"instanceId,omitempty"`
+	InstanceType         string     `json:"instanceType,omitempty"`
+	AvailabilityZone     string     `json:"availabilityZone,omitempty"`
+	MaxPrice             string     `json:"maxPrice,omitempty"`
+	InterruptionBehavior string     `json:"interruptionBehavior,omitempty"`
+	Created              time.Time  `json:"created"`
+}
+
+// SpotInterruption is an interruption of a Spot Instance recorded by
+// CloudTrail as a BidEvictedEvent
+type SpotInterruption struct {
+	Time       time.Time `json:"time"`
+	InstanceID string    `json:"instanceId"`
+}
@@ -0,0 +1,38 @@
+package types
+
+import "time"
+
+// SQSQueue is an SQS queue with its approximate depth and how old its oldest
+// message is
+type SQSQueue struct {
+	Name              string    `json:"name"`
+	URL               string    `json:"url"`
+	ARN               string    `json:"arn"`
+	FIFO              bool      `json:"fifo"`
+	Visible           int64     `json:"visible"`
+	InFlight          int64     `json:"inFlight"`
+	Delayed           int64     `json:"delayed"`
+	VisibilityTimeout int32     `json:"visibilityTimeout"`
+	RetentionPeriod   int32     `json:"retentionPeriod"`
+	CreatedAt         time.Time `json:"createdAt"`
+	// OldestMessageAge is the ApproximateAgeOfOldestMessage metric in seconds,
+	// nil when CloudWatch has no recent datapoint for the queue
+	OldestMessageAge *float64 `json:"oldestMessageAge,omitempty"`
+	// DeadLetterQueueARN and MaxReceiveCount come from the redrive policy
+	DeadLetterQueueARN string `json:"deadLetterQueueArn,omitempty"`
+	MaxReceiveCount    int    `json:"maxReceiveCount,omitempty"`
+}
+
+// SQSMoveTask is a message move task, which redrives messages from a dead
+// letter queue back to their source or to another queue
+type SQSMoveTask struct {
+	Handle         string    `json:"handle,omitempty"`
+	Status         string    `json:"status"`
+	SourceARN      string    `json:"sourceArn"`
+	DestinationARN string    `json:"destinationArn,omitempty"`
+	Moved          int64     `json:"moved"`
+	ToMove         *int64    `json:"toMove,omitempty"`
+	MaxPerSecond   *int32    `json:"maxPerSecond,omitempty"`
+	FailureReason  string    `json:"failureReason,omitempty"`
+	StartedAt      time.Time `json:"startedAt"`
+}
@@ -0,0 +1,15 @@
+package types
+
+import "time"
+
+// Parameter is an SSM Parameter Store parameter. Value is empty for
+// SecureString parameters that were not decrypted.
+type Parameter struct {
+	Name         string    `json:"name"`
+	Type         string    `json:"type"`
+	Value        string    `json:"value"`
+	Version      int64     `json:"version"`
+	DataType     string    `json:"dataType,omitempty"`
+	ARN          string    `json:"arn,omitempty"`
+	LastModified time.Time `json:"lastModified"`
+}
@@ -0,0 +1,28 @@
+package types
+
+import "time"
+
+// StateMachine is a Step Functions state machine. Type is STANDARD or EXPRESS.
+type StateMachine struct {
+	ARN       string    `json:"arn"`
+	Name      string    `json:"name"`
+	Type      string    `json:"type"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// Execution is a run of a state machine. Error, Cause and FailedState are
+// only filled in for executions that were described after they failed.
+type Execution struct {
+	ARN                 string     `json:"arn"`
+	Name                string     `json:"name"`
+	StateMachineARN     string     `json:"stateMachineArn"`
+	Status              string     `json:"status"`
+	StartedAt           time.Time  `json:"startedAt"`
+	StoppedAt           *time.Time `json:"stoppedAt,omitempty"`
+	RedriveCount        int32      `json:"redriveCount"`
+	RedriveStatus       string     `json:"redriveStatus,omitempty"`
+	RedriveStatusReason string     `json:"redriveStatusReason,omitempty"`
+	Error               string     `json:"error,omitempty"`
+	Cause               string     `json:"cause,omitempty"`
+	FailedState         string     `json:"failedState,omitempty"`
+}
@@ -0,0 +1,136 @@
+package verify
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Sample statuses
+const (
+	StatusHealthy   = "healthy"
+	StatusUnhealthy = "unhealthy"
+	// StatusUnknown is a signal without a verdict yet, e.g. an alarm with
+	// insufficient data or status checks still initializing
+	StatusUnknown = "unknown"
+)
+
+// Verdicts on whether a remediation worked
+const (
+	// VerdictResolved means the signal became healthy and stayed healthy
+	VerdictResolved = "resolved"
+	// VerdictFlapping means the signal became healthy and then regressed
+	VerdictFlapping = "flapping"
+	// VerdictNotResolved means the signal was still unhealthy at the end
+	VerdictNotResolved = "not_resolved"
+	// VerdictInconclusive means the signal never reported a status
+	VerdictInconclusive = "inconclusive"
+)
+
+// Sample is one reading of the signal a remediation was meant to fix
+type Sample struct {
+	Time   time.Time `json:"time"`
+	Status string    `json:"status"`
+	State  string    `json:"state"`
+	Detail string    `json:"detail,omitempty"`
+}
+
+// Check reads the signal once. Errors are recorded as unknown samples, so a
+// transient API failure does not end the verification.
+type Check func(ctx context.Context) (Sample, error)
+
+// Result is the verdict over all samples
+type Result struct {
+	Verdict string
+	// HealthySince is when the final healthy streak began, if the signal ended healthy
+	HealthySince time.Time
+	// Regressions counts how often the signal went from healthy back to unhealthy
+	Regressions int
+	Samples     []Sample
+}
+
+// Worked reports whether the remediation fixed the signal
+func (r Result) Worked() bool {
+	return r.Verdict == VerdictResolved
+}
+
+// Watch samples the signal immediately and then every interval until the
+// duration has passed, and returns the verdict. Cancelling ctx ends the watch
+// early with the samples taken so far.
+func Watch(ctx context.Context, check Check, duration, interval time.Duration) Result {
+	deadline := time.Now().Add(duration)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var samples []Sample
+	for {
+		sample, err := check(ctx)
+		if err != nil {
+			sample = Sample{Status: StatusUnknown, State: "error", Detail: err.Error()}
+		}
+		if sample.Time.IsZero() {
+			sample.Time = time.Now()
+		}
+		samples = append(samples, sample)
+
+		if !time.Now().Before(deadline) {
+			return Evaluate(samples)
+		}
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return Evaluate(samples)
+		}
+	}
+}
+
+// Evaluate decides whether the signal recovered. Unknown samples neither
+// confirm nor break a healthy streak.
+func Evaluate(samples []Sample) Result {
+	result := Result{Samples: samples}
+
+	last := ""
+	var streakStart time.Time
+	for _, sample := range samples {
+		switch sample.Status {
+		case StatusHealthy:
+			if last != StatusHealthy {
+				streakStart = sample.Time
+			}
+			last = StatusHealthy
+		case StatusUnhealthy:
+			if last == StatusHealthy {
+				result.Regressions++
+			}
+			last = StatusUnhealthy
+		}
+	}
+
+	switch {
+	case last == "":
+		result.Verdict = VerdictInconclusive
+	case last == StatusUnhealthy:
+		result.Verdict = VerdictNotResolved
+	case result.Regressions > 0:
+		result.Verdict = VerdictFlapping
+		result.HealthySince = streakStart
+	default:
+		result.Verdict = VerdictResolved
+		result.HealthySince = streakStart
+	}
+	return result
+}
+
+// Summary describes the verdict in one sentence
+func (r Result) Summary(signal string) string {
+	switch r.Verdict {
+	case VerdictResolved:
+		return fmt.Sprintf("%s recovered and stayed healthy", signal)
+	case VerdictFlapping:
+		return fmt.Sprintf("%s recovered but regressed %d times during verification; it is healthy now but not stable", signal, r.Regressions)
+	case VerdictNotResolved:
+		return fmt.Sprintf("%s is still unhealthy; the remediation did not work", signal)
+	default:
+		return fmt.Sprintf("%s reported no status during verification, so the remediation could not be verified", signal)
+	}
+}
@@ -0,0 +1,64 @@
+package verify
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func samples(statuses ...string) []Sample {
+	start := time.Date(2025, 6, 2, 10, 0, 0, 0, time.UTC)
+	result := make([]Sample, 0, len(statuses))
+	for i, status := range statuses {
+		result = append(result, Sample{Time: start.Add(time.Duration(i) * 30 * time.Second), Status: status})
+	}
+	return result
+}
+
+func TestEvaluate(t *testing.T) {
+	result := Evaluate(samples(StatusUnhealthy, StatusUnknown, StatusHealthy, StatusUnknown, StatusHealthy))
+	assert.Equal(t, VerdictResolved, result.Verdict)
+	assert.True(t, result.Worked())
+	assert.Equal(t, time.Date(2025, 6, 2, 10, 1, 0, 0, time.UTC), result.HealthySince, "unknown samples do not break the streak")
+
+	result = Evaluate(samples(StatusHealthy, StatusUnhealthy, StatusHealthy))
+	assert.Equal(t, VerdictFlapping, result.Verdict)
+	assert.Equal(t, 1, result.Regressions)
+	assert.False(t, result.Worked())
+
+	result = Evaluate(samples(StatusUnhealthy, StatusHealthy, StatusUnhealthy))
+	assert.Equal(t, VerdictNotResolved, result.Verdict)
+	assert.True(t, result.HealthySince.IsZero())
+	assert.Contains(t, result.Summary("alarm cpu-high"), "did not work")
+
+	assert.Equal(t, VerdictInconclusive, Evaluate(samples(StatusUnknown, StatusUnknown)).Verdict)
+}
+
+func TestWatch(t *testing.T) {
+	calls := 0
+	check := func(ctx context.Context) (Sample, error) {
+		calls++
+		switch calls {
+		case 1:
+			return Sample{Status: StatusUnhealthy, State: "ALARM"}, nil
+		case 2:
+			return Sample{}, errors.New("throttled")
+		default:
+			return Sample{Status: StatusHealthy, State: "OK"}, nil
+		}
+	}
+
+	result := Watch(context.Background(), check, 50*time.Millisecond, 10*time.Millisecond)
+	assert.Equal(t, VerdictResolved, result.Verdict)
+	require.GreaterOrEqual(t, len(result.Samples), 4)
+	assert.Equal(t, Sample{Time: result.Samples[1].Time, Status: StatusUnknown, State: "error", Detail: "throttled"}, result.Samples[1])
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	result = Watch(ctx, check, time.Hour, time.Minute)
+	assert.Len(t, result.Samples, 1, "a cancelled watch returns what it has")
+}
@@ -0,0 +1,164 @@
+// Package websocket serves and dials the WebSocket connections of the MCP
+// server on top of gorilla/websocket, exchanging whole messages. It adds
+// what every connection of the server needs: a bound on message size,
+// deadlines on reads and writes, and pings that drop peers that went away
+// without closing the connection.
+package websocket
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// MaxMessageSize bounds the messages a peer may send
+const MaxMessageSize = 16 * 1024 * 1024
+
+// Keepalive timing. A connection is dropped when nothing, pongs included,
+// arrived for pongWait; peers are pinged every pingInterval, well within it.
+// Variables so tests can shorten them.
+var (
+	pongWait     = 60 * time.Second
+	pingInterval = 25 * time.Second
+	writeWait    = 10 * time.Second
+)
+
+// Conn is an open WebSocket connection. Reads must come from one goroutine;
+// writes may come from several.
+type Conn struct {
+	conn    *websocket.Conn
+	writeMu sync.Mutex
+	// pongWait and pingInterval are fixed when the connection opens
+	pongWait     time.Duration
+	pingInterval time.Duration
+
+	closeOnce sync.Once
+	done      chan struct{}
+}
+
+// IsUpgrade reports whether the request asks to switch to WebSocket
+func IsUpgrade(r *http.Request) bool {
+	return websocket.IsWebSocketUpgrade(r)
+}
+
+// Upgrade completes the opening handshake and takes over the connection.
+// When the client offers protocol as a subprotocol, it is selected. On
+// failure the client has been answered with an HTTP error. Origins are not
+// checked; callers serve only the origins they allow.
+func Upgrade(w http.ResponseWriter, r *http.Request, protocol string) (*Conn, error) {
+	upgrader := websocket.Upgrader{
+		HandshakeTimeout: writeWait,
+		CheckOrigin:      func(*http.Request) bool { return true },
+	}
+	if protocol != "" {
+		upgrader.Subprotocols = []string{protocol}
+	}
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return nil, err
+	}
+	return newConn(conn), nil
+}
+
+// Dial opens a WebSocket connection to a ws:// or wss:// URL, offering
+// protocol as the subprotocol when set. The header is sent with the opening
+// handshake, e.g. an Origin.
+func Dial(rawURL, protocol string, header http.Header) (*Conn, error) {
+	dialer := *websocket.DefaultDialer
+	if protocol != "" {
+		dialer.Subprotocols = []string{protocol}
+	}
+	conn, response, err := dialer.Dial(rawURL, header)
+	if err != nil {
+		if errors.Is(err, websocket.ErrBadHandshake) && response != nil {
+			return nil, fmt.Errorf("server refused the WebSocket upgrade: %s", response.Status)
+		}
+		return nil, fmt.Errorf("failed to connect to %s: %w", rawURL, err)
+	}
+	return newConn(conn), nil
+}
+
+// newConn bounds the messages and starts the keepalive of a connection
+func newConn(conn *websocket.Conn) *Conn {
+	c := &Conn{conn: conn, pongWait: pongWait, pingInterval: pingInterval, done: make(chan struct{})}
+	conn.SetReadLimit(MaxMessageSize)
+	conn.SetReadDeadline(time.Now().Add(c.pongWait))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(c.pongWait))
+	})
+	conn.SetPingHandler(func(data string) error {
+		conn.SetReadDeadline(time.Now().Add(c.pongWait))
+		err := conn.WriteControl(websocket.PongMessage, []byte(data), time.Now().Add(writeWait))
+		if errors.Is(err, websocket.ErrCloseSent) {
+			return nil
+		}
+		return err
+	})
+	go c.keepalive()
+	return c
+}
+
+// keepalive pings the peer until the connection is closed. A peer that
+// stops answering is caught by the read deadline.
+func (c *Conn) keepalive() {
+	ticker := time.NewTicker(c.pingInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-c.done:
+			return
+		case <-ticker.C:
+			if err := c.conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(writeWait)); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// Protocol returns the subprotocol selected during the handshake, if any
+func (c *Conn) Protocol() string {
+	return c.conn.Subprotocol()
+}
+
+// ReadMessage returns the next text or binary message, answering pings on
+// the way. It returns io.EOF once the peer closes the connection, and an
+// error when the peer stayed silent for too long. The silence is counted
+// from the start of the read, so handling a message for longer than
+// pongWait between reads does not drop the connection.
+func (c *Conn) ReadMessage() ([]byte, error) {
+	c.conn.SetReadDeadline(time.Now().Add(c.pongWait))
+	_, message, err := c.conn.ReadMessage()
+	if websocket.IsCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway, websocket.CloseNoStatusReceived) {
+		return nil, io.EOF
+	}
+	if err != nil {
+		return nil, err
+	}
+	return message, nil
+}
+
+// WriteMessage sends a text message
+func (c *Conn) WriteMessage(message []byte) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+	return c.conn.WriteMessage(websocket.TextMessage, message)
+}
+
+// Close sends a close frame and closes the connection. Closing twice is
+// harmless.
+func (c *Conn) Close() error {
+	err := net.ErrClosed
+	c.closeOnce.Do(func() {
+		close(c.done)
+		c.conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""), time.Now().Add(writeWait))
+		err = c.conn.Close()
+	})
+	return err
+}
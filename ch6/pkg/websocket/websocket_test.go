@@ -0,0 +1,212 @@
+package websocket
+
+import (
+	"bytes"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	gorilla "github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newEchoServer serves a WebSocket endpoint sending every message back
+func newEchoServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := Upgrade(w, r, "mcp")
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		for {
+			message, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			if err := conn.WriteMessage(message); err != nil {
+				return
+			}
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// wsURL returns the WebSocket URL of a test server
+func wsURL(server *httptest.Server) string {
+	return "ws" + strings.TrimPrefix(server.URL, "http")
+}
+
+// shortKeepalive shortens the keepalive timing for a test
+func shortKeepalive(t *testing.T) {
+	t.Helper()
+	wait, interval := pongWait, pingInterval
+	pongWait, pingInterval = 200*time.Millisecond, 50*time.Millisecond
+	t.Cleanup(func() { pongWait, pingInterval = wait, interval })
+}
+
+func TestEcho(t *testing.T) {
+	server := newEchoServer(t)
+	conn, err := Dial(wsURL(server), "mcp", nil)
+	require.NoError(t, err)
+	defer conn.Close()
+	assert.Equal(t, "mcp", conn.Protocol())
+
+	for _, size := range []int{0, 5, 125, 126, 70000} {
+		message := bytes.Repeat([]byte("x"), size)
+		require.NoError(t, conn.WriteMessage(message))
+		echoed, err := conn.ReadMessage()
+		require.NoError(t, err)
+		assert.Equal(t, message, echoed, "message of %d bytes", size)
+	}
+}
+
+func TestFragmentsAndPing(t *testing.T) {
+	server := newEchoServer(t)
+	conn, err := Dial(wsURL(server), "", nil)
+	require.NoError(t, err)
+	defer conn.Close()
+	assert.Empty(t, conn.Protocol())
+
+	// "he", a ping between the fragments and "llo", all with a zero mask
+	_, err = conn.conn.UnderlyingConn().Write([]byte{
+		0x01, 0x82, 0, 0, 0, 0, 'h', 'e',
+		0x89, 0x80, 0, 0, 0, 0,
+		0x80, 0x83, 0, 0, 0, 0, 'l', 'l', 'o',
+	})
+	require.NoError(t, err)
+
+	echoed, err := conn.ReadMessage()
+	require.NoError(t, err, "the pong is skipped")
+	assert.Equal(t, "hello", string(echoed))
+}
+
+func TestUnmaskedClientFrame(t *testing.T) {
+	server := newEchoServer(t)
+	conn, err := Dial(wsURL(server), "", nil)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	_, err = conn.conn.UnderlyingConn().Write([]byte{0x81, 0x02, 'h', 'i'})
+	require.NoError(t, err)
+	_, err = conn.ReadMessage()
+	var closed *gorilla.CloseError
+	require.ErrorAs(t, err, &closed, "the server closes the connection")
+	assert.Equal(t, gorilla.CloseProtocolError, closed.Code)
+}
+
+func TestUpgradeRejectsPlainRequests(t *testing.T) {
+	server := newEchoServer(t)
+	response, err := http.Get(server.URL)
+	require.NoError(t, err)
+	response.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+
+	_, err = Dial(server.URL, "", nil)
+	assert.ErrorContains(t, err, "malformed ws or wss URL")
+}
+
+func TestCloseEndsReadsWithEOF(t *testing.T) {
+	server := newEchoServer(t)
+	conn, err := Dial(wsURL(server), "", nil)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	// The echo server closes its side once its read fails
+	conn.conn.UnderlyingConn().Write([]byte{0x88, 0x82, 0, 0, 0, 0, 0x03, 0xe8})
+	_, err = conn.ReadMessage()
+	assert.ErrorIs(t, err, io.EOF)
+}
+
+func TestKeepaliveKeepsIdleConnections(t *testing.T) {
+	shortKeepalive(t)
+	server := newEchoServer(t)
+	conn, err := Dial(wsURL(server), "", nil)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	// Both sides wait in a read, exchanging only pings for several read
+	// deadlines
+	echoed := make(chan string, 1)
+	go func() {
+		message, err := conn.ReadMessage()
+		if err != nil {
+			message = []byte(err.Error())
+		}
+		echoed <- string(message)
+	}()
+	time.Sleep(3 * pongWait)
+	require.NoError(t, conn.WriteMessage([]byte("still there")))
+	assert.Equal(t, "still there", <-echoed)
+}
+
+func TestSlowHandlingKeepsConnections(t *testing.T) {
+	shortKeepalive(t)
+	// The server handles each message for longer than the read deadline
+	// before it reads the next one
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := Upgrade(w, r, "")
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		for {
+			message, err := conn.ReadMessage()
+			if err != nil {
+				conn.WriteMessage([]byte(err.Error()))
+				return
+			}
+			time.Sleep(2 * pongWait)
+			if err := conn.WriteMessage(message); err != nil {
+				return
+			}
+		}
+	}))
+	defer server.Close()
+
+	conn, err := Dial(wsURL(server), "", nil)
+	require.NoError(t, err)
+	defer conn.Close()
+	for _, message := range []string{"first", "second"} {
+		require.NoError(t, conn.WriteMessage([]byte(message)))
+		echoed, err := conn.ReadMessage()
+		require.NoError(t, err)
+		assert.Equal(t, message, string(echoed))
+	}
+}
+
+func TestKeepaliveDropsSilentPeers(t *testing.T) {
+	shortKeepalive(t)
+	failed := make(chan error, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := Upgrade(w, r, "")
+		if err != nil {
+			failed <- err
+			return
+		}
+		defer conn.Close()
+		_, err = conn.ReadMessage()
+		failed <- err
+	}))
+	defer server.Close()
+
+	// A peer that never reads never answers the pings
+	peer, _, err := gorilla.DefaultDialer.Dial(wsURL(server), nil)
+	require.NoError(t, err)
+	defer peer.Close()
+
+	select {
+	case err := <-failed:
+		var netErr net.Error
+		require.ErrorAs(t, err, &netErr)
+		assert.True(t, netErr.Timeout())
+	case <-time.After(5 * time.Second):
+		t.Fatal("the silent peer was not dropped")
+	}
+}
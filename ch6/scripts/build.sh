@@ -4,7 +4,7 @@ set -e
 echo "Building AWS MCP Server..."
 
 # Clean previous builds
-rm -f bin/aws-mcp-server
+rm -f bin/aws-mcp-server bin/replay bin/bench bin/eval
 
 # Create bin directory
 mkdir -p bin
@@ -12,4 +12,13 @@ mkdir -p bin
 # Build the server
 go build -o bin/aws-mcp-server ./cmd/server
 
-echo "✓ Build completed: bin/aws-mcp-server"
\ No newline at end of file
+# Build the session replay utility
+go build -o bin/replay ./cmd/replay
+
+# Build the load test driver
+go build -o bin/bench ./cmd/bench
+
+# Build the agent evaluation harness
+go build -o bin/eval ./cmd/eval
+
+echo "✓ Build completed: bin/aws-mcp-server, bin/replay, bin/bench, bin/eval"
\ No newline at end of file